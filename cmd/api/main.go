@@ -1,25 +1,31 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
-	"strings"
 	"syscall"
 	"time"
 
 	"github.com/payperplay/hosting/internal/api"
+	"github.com/payperplay/hosting/internal/cache"
 	"github.com/payperplay/hosting/internal/cloud"
 	"github.com/payperplay/hosting/internal/conductor"
 	"github.com/payperplay/hosting/internal/docker"
 	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/external"
 	"github.com/payperplay/hosting/internal/middleware"
-	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/monitoring"
+	"github.com/payperplay/hosting/internal/ratelimit"
 	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/secrets"
 	"github.com/payperplay/hosting/internal/service"
 	"github.com/payperplay/hosting/internal/storage"
+	"github.com/payperplay/hosting/internal/tracing"
 	"github.com/payperplay/hosting/internal/velocity"
 	"github.com/payperplay/hosting/internal/websocket"
 	"github.com/payperplay/hosting/pkg/config"
@@ -41,7 +47,7 @@ func main() {
 	cfg := config.Load()
 
 	// Initialize logger
-	logLevel := parseLogLevel(cfg.LogLevel)
+	logLevel := logger.ParseLevel(cfg.LogLevel)
 	appLogger := logger.NewLogger(logLevel, os.Stdout, cfg.LogJSON)
 	logger.SetDefault(appLogger)
 
@@ -51,24 +57,77 @@ func main() {
 		"port":  cfg.Port,
 	})
 
+	// OpenTelemetry tracing (OTLP export if OTEL_EXPORTER_OTLP_ENDPOINT is set)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		logger.Warn("Failed to initialize tracing, continuing without", map[string]interface{}{
+			"error": err.Error(),
+		})
+		shutdownTracing = func(context.Context) error { return nil }
+	}
+	// Deferred calls run in reverse order: workers stop before the spans of their last work are exported
+	defer logger.Info("Shutdown complete", nil)
+	defer func() {
+		// Export spans that are still buffered
+		tracingCtx, cancelTracing := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelTracing()
+		shutdownTracing(tracingCtx)
+	}()
+
+	// Secrets store (Vault or SOPS) for the SSH key and API tokens; without one the env vars are used
+	secretsProvider, err := secrets.New(cfg)
+	if err != nil {
+		logger.Fatal("Invalid secrets store configuration", err, nil)
+	}
+	var secretsService *service.SecretsService
+	if secretsProvider != nil {
+		secretsService = service.NewSecretsService(secretsProvider, cfg)
+		if err := secretsService.Load(cfg); err != nil {
+			logger.Fatal("Failed to load secrets", err, map[string]interface{}{
+				"provider": secretsProvider.Name(),
+			})
+		}
+		secretsService.Start()
+		defer secretsService.Stop()
+	}
+
 	// Initialize database
 	if err := repository.InitDB(cfg); err != nil {
 		logger.Fatal("Failed to initialize database", err, nil)
 	}
+	defer repository.CloseReplicas()
 	logger.Info("Database initialized", nil)
 
+	// Cache for hot server lookups (Redis shares it between API replicas)
+	if cfg.CacheStore != "" {
+		serverCache, err := newServerCache(cfg)
+		if err != nil {
+			logger.Fatal("Failed to initialize cache", err, map[string]interface{}{"store": cfg.CacheStore})
+		}
+		defer serverCache.Close()
+		repository.SetServerCache(serverCache)
+		logger.Info("Server lookups cached", map[string]interface{}{"store": cfg.CacheStore, "ttl": cfg.CacheTTL})
+	}
+
 	// Initialize Event-Bus with multi-storage (PostgreSQL + InfluxDB)
 	db := repository.GetDB()
 	dbStorage := events.NewDatabaseEventStorage(db)
 
+	// Admin replay of stored events (sources: PostgreSQL, InfluxDB if configured)
+	eventReplayService := service.NewEventReplayService()
+	eventReplayService.AddSource("postgres", dbStorage)
+
 	// Try to initialize InfluxDB if configured
 	var eventStorage events.EventStorage = dbStorage
+	var containerMetricsStore service.ContainerMetricsStore // Performance graphs (nil without InfluxDB)
+	var tickMetricsStore service.TickMetricsStore
 	if cfg.InfluxDBURL != "" && cfg.InfluxDBToken != "" {
 		influxConfig := storage.InfluxDBConfig{
-			URL:    cfg.InfluxDBURL,
-			Token:  cfg.InfluxDBToken,
-			Org:    cfg.InfluxDBOrg,
-			Bucket: cfg.InfluxDBBucket,
+			URL:           cfg.InfluxDBURL,
+			Token:         cfg.InfluxDBToken,
+			Org:           cfg.InfluxDBOrg,
+			Bucket:        cfg.InfluxDBBucket,
+			MetricsBucket: cfg.InfluxDBMetricsBucket,
 		}
 
 		influxClient, err := storage.NewInfluxDBClient(influxConfig)
@@ -78,8 +137,12 @@ func main() {
 			})
 		} else {
 			defer influxClient.Close()
+			secretsService.OnRotate(secrets.InfluxDBToken, influxClient.SetToken)
 			influxStorage := events.NewInfluxDBEventStorage(influxClient)
+			eventReplayService.AddSource("influxdb", influxStorage)
 			eventStorage = events.NewMultiEventStorage(dbStorage, influxStorage)
+			containerMetricsStore = influxClient
+			tickMetricsStore = influxClient
 			logger.Info("Event-Bus initialized with dual storage (PostgreSQL + InfluxDB)", map[string]interface{}{
 				"influxdb_url": cfg.InfluxDBURL,
 				"org":          cfg.InfluxDBOrg,
@@ -92,6 +155,49 @@ func main() {
 
 	events.SetEventStorage(eventStorage)
 
+	// Event transport: share events and WebSocket broadcasts between API replicas
+	var eventTransport events.Transport
+	if cfg.EventTransport != "" {
+		transport, err := events.NewTransport(cfg.EventTransport, cfg.EventTransportURL)
+		if err != nil {
+			logger.Fatal("Failed to initialize event transport", err, map[string]interface{}{
+				"transport": cfg.EventTransport,
+			})
+		}
+		defer transport.Close()
+		eventTransport = transport
+
+		if err := events.SetEventTransport(eventTransport); err != nil {
+			logger.Fatal("Failed to subscribe Event-Bus to transport", err, nil)
+		}
+		logger.Info("Event-Bus shared across API instances", map[string]interface{}{
+			"transport":   cfg.EventTransport,
+			"instance_id": events.InstanceID,
+		})
+	}
+
+	// API rate limits (Redis shares the counters between API replicas)
+	applyRateLimits(cfg)
+	var rateLimitStore ratelimit.Store
+	if cfg.RateLimitStore == "redis" {
+		store, err := ratelimit.NewRedisStore(cfg.RateLimitRedisURL)
+		if err != nil {
+			logger.Fatal("Failed to initialize Redis rate limit store", err, nil)
+		}
+		rateLimitStore = store
+		middleware.SetRateLimitStore(store)
+		logger.Info("Rate limit counters shared via Redis", nil)
+	}
+
+	// Live reload of log level, idle timeout, rate limits and node sizing (CONFIG_FILE, SIGHUP, admin API)
+	runtimeConfig := service.NewRuntimeConfigService(cfg)
+	runtimeConfig.OnChange(func(changes []config.Change) {
+		logger.SetLevel(logger.ParseLevel(cfg.LogLevel))
+		applyRateLimits(cfg)
+	})
+	runtimeConfig.Start()
+	defer runtimeConfig.Stop()
+
 	// Initialize Docker service
 	dockerService, err := docker.NewDockerService(cfg)
 	if err != nil {
@@ -111,11 +217,16 @@ func main() {
 	backupRestoreTrackingRepo := repository.NewBackupRestoreTrackingRepository(db)
 	nodeRepo := repository.NewNodeRepository(db)
 
-	// Initialize Email Service (using mock sender for now)
-	// 🚧 TODO: Replace MockEmailSender with ResendEmailSender when ready for production
-	mockEmailSender := service.NewMockEmailSender(db)
-	emailService := service.NewEmailService(mockEmailSender, db)
-	logger.Info("Email service initialized (🚧 MOCK MODE)", nil)
+	// Initialize Email Service (Resend with an API key, otherwise emails are only logged and stored)
+	var emailSender service.EmailSender
+	if cfg.ResendAPIKey != "" {
+		emailSender = service.NewResendEmailSender(cfg.ResendAPIKey, cfg.EmailFrom, cfg.FrontendURL)
+		logger.Info("Email service initialized (Resend)", map[string]interface{}{"from": cfg.EmailFrom})
+	} else {
+		emailSender = service.NewMockEmailSender(db, cfg.FrontendURL)
+		logger.Info("Email service initialized (MOCK MODE, set RESEND_API_KEY to send emails)", nil)
+	}
+	emailService := service.NewEmailService(emailSender, db)
 
 	// Initialize Security Service for device trust and security events
 	securityService := service.NewSecurityService(db, emailService)
@@ -124,11 +235,24 @@ func main() {
 	// Initialize services
 	authService := service.NewAuthService(userRepo, cfg, emailService, securityService)
 	oauthService := service.NewOAuthService(db, userRepo, cfg, securityService, emailService)
+	oauthService.StartTokenRefresh() // Refresh stale provider tokens in the background
+	defer oauthService.StopTokenRefresh()
 	logger.Info("OAuth service initialized", nil)
 
+	// Two-factor authentication (TOTP): second login step and checks for sensitive operations
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	twoFactorService := service.NewTwoFactorService(userRepo, recoveryCodeRepo, securityService, cfg)
+	authService.SetTwoFactorService(twoFactorService)
+	securityService.SetTwoFactorVerifier(twoFactorService)
+	middleware.SetSensitiveOperationGuard(securityService)
+
 	mcService := service.NewMinecraftService(serverRepo, dockerService, cfg)
 	monitoringService := service.NewMonitoringService(mcService, serverRepo, cfg)
 
+	// Initialize Idle Policy Service for configurable idle-shutdown rules
+	idlePolicyService := service.NewIdlePolicyService(db)
+	monitoringService.SetIdlePolicyService(idlePolicyService)
+
 	// Initialize Recovery Service for automatic crash handling
 	recoveryService := service.NewRecoveryService(serverRepo, dockerService, cfg)
 	recoveryService.Start()
@@ -141,6 +265,33 @@ func main() {
 	// Link auth service to middleware
 	middleware.SetAuthService(authService)
 
+	// Organizations and per-server shares: every server route checks PermissionService
+	// (via middleware.RequireServerPermission)
+	orgRepo := repository.NewOrganizationRepository(db)
+	orgService := service.NewOrganizationService(orgRepo, userRepo, serverRepo, emailService)
+	serverShareRepo := repository.NewServerShareRepository(db)
+	permissionService := service.NewPermissionService(serverRepo, serverShareRepo, orgService)
+	middleware.SetServerAccessService(permissionService)
+
+	// API keys for programmatic access (authenticated by AuthMiddleware alongside JWTs)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, orgService, cfg)
+	if rateLimitStore != nil {
+		apiKeyService.SetRateLimitStore(rateLimitStore)
+	}
+	middleware.SetAPIKeyService(apiKeyService)
+
+	// Idempotency keys: retried mutating requests return the stored response
+	idempotencyService := service.NewIdempotencyService(db, cfg)
+	middleware.SetIdempotencyService(idempotencyService)
+	idempotencyService.Start()
+	defer idempotencyService.Stop()
+
+	// Organization SSO (OIDC) with just-in-time provisioning; AuthMiddleware enforces its session policies
+	ssoRepo := repository.NewSSORepository(db)
+	ssoService := service.NewSSOService(ssoRepo, orgRepo, userRepo, orgService, authService, securityService, emailService, cfg)
+	middleware.SetSSOSessionPolicy(ssoService)
+
 	// Initialize Backup Quota Service for user quota management
 	backupQuotaService := service.NewBackupQuotaService(backupRepo, backupRestoreTrackingRepo, userRepo)
 	logger.Info("Backup quota service initialized", nil)
@@ -150,6 +301,37 @@ func main() {
 	logger.Info("Backup service initialized with SFTP support and quota enforcement", map[string]interface{}{
 		"storage_box_enabled": cfg.StorageBoxEnabled,
 	})
+	backupService.SetSecurityService(securityService) // Legal hold changes go to the security audit log
+
+	// Persisted history of background jobs (operations and bulk jobs)
+	jobService := service.NewJobService(repository.NewJobRepository(db), cfg)
+	jobService.Start()
+	defer jobService.Stop()
+
+	// Per-owner concurrency limits for starts, manual backups and restores (queued beyond the plan's limit)
+	opLimiter := service.NewOperationLimiter(userRepo, cfg)
+	opLimiter.SetJobService(jobService)
+	backupService.SetOperationLimiter(opLimiter)
+	mcService.SetOperationLimiter(opLimiter)
+	logger.Info("Operation limiter initialized", map[string]interface{}{
+		"basic":      cfg.OperationLimitBasic,
+		"premium":    cfg.OperationLimitPremium,
+		"enterprise": cfg.OperationLimitEnterprise,
+		"queue_max":  cfg.OperationQueueMax,
+	})
+
+	// Organization data residency: restricts placement, backups, archives and migrations to a country
+	residencyService := service.NewResidencyService(orgRepo)
+	backupService.SetResidencyService(residencyService)
+	mcService.SetResidencyService(residencyService)
+	logger.Info("Data residency service initialized", map[string]interface{}{
+		"storage_box_country":   cfg.StorageBoxCountry,
+		"local_storage_country": cfg.LocalStorageCountry,
+	})
+
+	// Control plane resource monitor: alerts admins and defers compression/restores under pressure
+	controlPlaneMonitor := service.NewControlPlaneMonitor(cfg, userRepo, emailService)
+	backupService.SetControlPlaneMonitor(controlPlaneMonitor)
 
 	// Initialize Backup Scheduler for automated backups
 	backupScheduler := service.NewBackupScheduler(db, backupService, backupRepo, serverRepo)
@@ -157,8 +339,24 @@ func main() {
 	defer backupScheduler.Stop()
 	logger.Info("Backup scheduler started", nil)
 
+	// Lifecycle thresholds per plan/server (defaults from the configuration) and transition notices
+	lifecyclePolicyService := service.NewLifecyclePolicyService(db, userRepo, emailService)
+	lifecycleSleepAfter, err := time.ParseDuration(cfg.LifecycleSleepAfter)
+	if err != nil {
+		lifecycleSleepAfter = 5 * time.Minute
+	}
+	lifecycleColdAfter, err := time.ParseDuration(cfg.ArchiveColdAfter)
+	if err != nil || lifecycleColdAfter <= 0 {
+		lifecycleColdAfter = 30 * 24 * time.Hour
+	}
+	lifecyclePolicyService.SetDefaults(lifecycleSleepAfter, time.Duration(cfg.ArchiveAfterHours)*time.Hour, lifecycleColdAfter)
+	if notifyBefore, err := time.ParseDuration(cfg.LifecycleNotifyBefore); err == nil {
+		lifecyclePolicyService.SetNotifyBefore(notifyBefore)
+	}
+
 	// Initialize Lifecycle Service for 3-phase lifecycle management
 	lifecycleService := service.NewLifecycleService(db, serverRepo)
+	lifecycleService.SetLifecyclePolicies(lifecyclePolicyService)
 	lifecycleService.Start()
 	defer lifecycleService.Stop()
 	logger.Info("Lifecycle service started", nil)
@@ -166,6 +364,9 @@ func main() {
 	// Initialize Archive Service for Phase 3 (Sleeping > 48h → Archived)
 	// NOTE: Conductor is not available yet, will be set later via SetConductor()
 	archiveService := service.NewArchiveService(serverRepo, nil)
+	archiveService.SetControlPlaneMonitor(controlPlaneMonitor)
+	archiveService.SetOperationLimiter(opLimiter)
+	archiveService.SetResidencyService(residencyService)
 	logger.Info("Archive service initialized", nil)
 
 	// Initialize Archive Worker for automatic archiving (sleeping > 48h servers)
@@ -173,12 +374,21 @@ func main() {
 
 	// Configure archive settings from environment
 	archiveWorker.SetArchiveAfter(time.Duration(cfg.ArchiveAfterHours) * time.Hour)
+	if coldAfter, err := time.ParseDuration(cfg.ArchiveColdAfter); err == nil && coldAfter > 0 {
+		archiveWorker.SetColdAfter(coldAfter)
+	}
 	if scanInterval, err := time.ParseDuration(cfg.ArchiveScanInterval); err == nil {
 		archiveWorker.SetScanInterval(scanInterval)
 	}
 
+	archiveWorker.SetLifecyclePolicies(lifecyclePolicyService)
+
 	archiveWorker.Start()
 	defer archiveWorker.Stop()
+
+	lifecyclePolicyService.SetColdStorage(archiveService.ColdStorageEnabled())
+	lifecyclePolicyService.Start()
+	defer lifecyclePolicyService.Stop()
 	logger.Info("Archive worker started", map[string]interface{}{
 		"archive_after": fmt.Sprintf("%dh", cfg.ArchiveAfterHours),
 		"scan_interval": cfg.ArchiveScanInterval,
@@ -190,16 +400,84 @@ func main() {
 	defer backupRetentionWorker.Stop()
 	logger.Info("Backup retention worker started (cleans up expired backups daily)", nil)
 
+	// Heap dumps: opt-in OOM diagnostics for modded servers (quota-limited, pruned hourly)
+	heapDumpService := service.NewHeapDumpService(serverRepo, cfg)
+	recoveryService.SetHeapDumpCollector(heapDumpService)
+	heapDumpPruneWorker := service.NewHeapDumpPruneWorker(heapDumpService)
+	heapDumpPruneWorker.Start()
+	defer heapDumpPruneWorker.Stop()
+
+	// SFTP gateway and WebDAV: file protocol access to server directories (the gateway starts once remote access is wired)
+	serverFileAccess := service.NewServerFileAccess(cfg)
+	sftpGatewayService := service.NewSFTPGatewayService(repository.NewSFTPRepository(db), serverRepo, permissionService, serverFileAccess, cfg)
+	webdavService := service.NewWebDAVService(serverRepo, serverFileAccess)
+
+	// Webhook service (Discord/Slack notifications of server events, signed JSON deliveries to webhook endpoints)
+	webhookService := service.NewWebhookService(db)
+	webhookService.Start()
+	defer webhookService.Stop()
+	recoveryService.SetWebhookService(webhookService)
+	backupService.SetWebhookService(webhookService)
+
+	// Disk quotas: size of server directories, threshold warnings and refused uploads over quota
+	diskQuotaService := service.NewDiskQuotaService(serverRepo, serverFileAccess, cfg)
+	diskQuotaService.SetWebhookService(webhookService)
+	sftpGatewayService.SetDiskQuota(diskQuotaService)
+	webdavService.SetDiskQuota(diskQuotaService)
+
 	// Initialize Billing Service for cost analytics
 	billingService := service.NewBillingService(db, serverRepo)
-	billingService.Start() // Subscribe to Event-Bus for automatic billing tracking
+
+	// Transactional event outbox: server start/stop and phase changes are stored with the status
+	// change and delivered to billing at least once (survives crashes between stop and billing)
+	outboxWorker := service.NewOutboxWorker(repository.NewOutboxRepository(db))
+	billingService.Start(outboxWorker) // Register billing handlers on the outbox
+	outboxWorker.Start()
+	defer outboxWorker.Stop()
 	defer billingService.Stop()
-	logger.Info("Billing service initialized and subscribed to Event-Bus", nil)
+	logger.Info("Billing service initialized with durable event outbox", nil)
+
+	// Replay targets: rebuild billing from stored events, re-send node events to the dashboard
+	eventReplayService.AddTarget(service.BillingReplayTarget(billingService))
+	eventReplayService.AddTarget(service.DashboardReplayTarget())
 
 	// GAP-3: Start zombie session cleanup worker (runs every 10min)
 	billingService.StartZombieCleanupWorker(10 * time.Minute)
 	logger.Info("Billing zombie session cleanup worker started (every 10min)", nil)
 
+	// Budget caps: track accrued cost against monthly limits (alerts at 50/80/100%, optional auto-stop)
+	billingService.SetServerStopper(mcService)
+	billingService.SetWebhookService(webhookService)
+	mcService.AddBillingGuard(billingService) // Blocking caps reject starts when exceeded
+	billingService.StartBudgetWorker(time.Minute)
+
+	// Initialize Stripe Service for metered usage invoicing
+	stripeService := service.NewStripeService(db, cfg, userRepo, serverRepo, mcService)
+	if stripeService.IsEnabled() {
+		mcService.AddBillingGuard(stripeService) // Suspended owners can't start servers
+		stripeService.Start()
+		defer stripeService.Stop()
+		logger.Info("Stripe billing enabled", nil)
+	}
+
+	// Initialize Wallet Service for prepaid credit
+	walletService := service.NewWalletService(db, cfg)
+	if walletService.IsEnabled() {
+		mcService.AddBillingGuard(walletService) // Prepaid owners need enough credit to start
+		monitoringService.SetWalletService(walletService)
+		stripeService.SetWalletService(walletService)
+		walletService.Start()
+		defer walletService.Stop()
+		logger.Info("Prepaid wallet enabled", nil)
+	}
+
+	// Initialize Invoice Service (monthly documents for customers not invoiced by Stripe)
+	invoiceService := service.NewInvoiceService(db, cfg, billingService, userRepo, serverRepo)
+	if cfg.InvoiceEnabled {
+		invoiceService.Start()
+		defer invoiceService.Stop()
+	}
+
 	// Initialize Plugin Marketplace Services
 	pluginSyncService := service.NewPluginSyncService(pluginRepo)
 	pluginSyncService.Start() // Start background sync worker (every 6 hours)
@@ -212,15 +490,27 @@ func main() {
 	pluginService := service.NewPluginService(serverRepo, cfg)
 	fileManagerService := service.NewFileManagerService(serverRepo, cfg)
 	fileService := service.NewFileService(fileRepo, serverRepo, cfg.ServersBasePath)
+	fileManagerService.SetDiskQuota(diskQuotaService)
+	fileService.SetDiskQuota(diskQuotaService)
 
 	// Initialize WebSocket Hub
 	wsHub := websocket.NewHub()
 	go wsHub.Run()
+	if eventTransport != nil {
+		if err := wsHub.SetTransport(eventTransport); err != nil {
+			logger.Fatal("Failed to subscribe WebSocket hub to event transport", err, nil)
+		}
+	}
 	logger.Info("WebSocket hub started", nil)
 
 	// Link WebSocket Hub to services for real-time updates
 	mcService.SetWebSocketHub(wsHub)
 	recoveryService.SetWebSocketHub(wsHub)
+	backupService.SetWebSocketHub(wsHub)
+	opLimiter.SetWebSocketHub(wsHub)
+	controlPlaneMonitor.SetWebSocketHub(wsHub)
+	controlPlaneMonitor.Start() // Started once the hub is set, alerts go out on the first sample
+	defer controlPlaneMonitor.Stop()
 
 	// Note: BillingService now automatically tracks events via Event-Bus subscription
 	// No need to manually link it to services
@@ -258,6 +548,9 @@ func main() {
 	var velocityMonitor *velocity.VelocityMonitor
 	if cfg.VelocityAPIURL != "" {
 		remoteVelocityClient = velocity.NewRemoteVelocityClient(cfg.VelocityAPIURL)
+		if cfg.VelocityAPISecret != "" {
+			remoteVelocityClient.SetSigningSecret(cfg.VelocityAPISecret)
+		}
 
 		// Link Remote Velocity client to MinecraftService for automatic server registration
 		mcService.SetRemoteVelocityClient(remoteVelocityClient)
@@ -269,6 +562,14 @@ func main() {
 		velocityMonitor = velocity.NewVelocityMonitor(remoteVelocityClient, serverRepo, cfg)
 		logger.Info("Velocity monitor initialized", nil)
 
+		// Move players of crashed servers to the fallback lobby and back once they recover
+		if cfg.VelocityFallbackLobby != "" {
+			recoveryService.SetVelocityFallback(remoteVelocityClient)
+			logger.Info("Velocity fallback lobby enabled for crash recovery", map[string]interface{}{
+				"lobby": cfg.VelocityFallbackLobby,
+			})
+		}
+
 		// Initialize Player Count tracking service for accurate auto-shutdown
 		playerCountService := service.NewPlayerCountService(remoteVelocityClient, serverRepo)
 		playerCountService.Start()
@@ -276,6 +577,10 @@ func main() {
 		logger.Info("Player count tracking service started (Velocity-based)", map[string]interface{}{
 			"check_interval": "15s",
 		})
+
+		// Tell players of crashed servers in-game when their server is back
+		recoveryService.SetPlayerTracker(playerCountService)
+		recoveryService.SetPlayerMessenger(remoteVelocityClient)
 	} else {
 		logger.Warn("VELOCITY_API_URL not configured, remote Velocity integration disabled", nil)
 	}
@@ -291,12 +596,83 @@ func main() {
 	logger.Info("Prometheus metrics exporter started", nil)
 
 	// Initialize Conductor Core for fleet orchestration
-	cond := conductor.NewConductor(10*time.Second, cfg.SSHPrivateKeyPath, nodeRepo) // Health check every 10 seconds for real-time dashboard updates
+	sshKeyPath := cfg.SSHPrivateKeyPath
+	sshKey, sshKeyInStore := secretsService.Get(secrets.SSHPrivateKey)
+	if sshKeyInStore && sshKeyPath == "" {
+		sshKeyPath = secrets.SSHKeyPathFromStore
+	}
+	cond := conductor.NewConductor(10*time.Second, sshKeyPath, nodeRepo) // Health check every 10 seconds for real-time dashboard updates
+	if sshKeyInStore && cond.RemoteClient != nil {
+		// The key from the store replaces the key file; rotations apply to new node connections
+		if err := cond.RemoteClient.SetPrivateKey([]byte(sshKey)); err != nil {
+			logger.Fatal("Invalid SSH_PRIVATE_KEY in secrets store", err, nil)
+		}
+		secretsService.OnRotate(secrets.SSHPrivateKey, func(key string) {
+			if err := cond.RemoteClient.SetPrivateKey([]byte(key)); err != nil {
+				logger.Error("Rotated SSH_PRIVATE_KEY is invalid, keeping the previous key", err, nil)
+			}
+		})
+	}
+	cond.NodeRegistry.SetMinFreeDiskMB(cfg.NodeMinFreeDiskMB) // Disk-aware placement (health checks measure free disk)
+	cond.NodeRegistry.SetCPUBusyPercent(cfg.NodeCPUBusyPercent) // CPU-aware placement (CPU metrics worker measures load)
+
+	// Audit log in PostgreSQL (conductor decisions + admin changes), pruned after AUDIT_RETENTION_DAYS
+	auditService := service.NewAuditService(repository.NewAuditRepository(db), cond.AuditLog, cfg)
+	auditService.Start()
+	defer auditService.Stop()
+
+	// Node SSH key rotation (managed keypair per environment, old keys retired after the grace period)
+	sshKeyService := service.NewSSHKeyService(repository.NewSSHKeyRepository(db), cond, auditService, cfg)
+	if err := sshKeyService.Init(); err != nil {
+		logger.Warn("Failed to load the managed SSH key, using SSH_PRIVATE_KEY_PATH", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	sshKeyService.Start()
+	defer sshKeyService.Stop()
+
+	// Usage logs and events older than USAGE_ARCHIVE_RETENTION_DAYS -> monthly CSV on the Storage Box, then deleted
+	var usageArchiveStore service.UsageArchiveStore
+	if cfg.StorageBoxEnabled {
+		client, err := storage.NewSFTPClient(cfg)
+		if err != nil {
+			logger.Warn("USAGE-ARCHIVE: Failed to initialize SFTP client, keeping archives locally", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			usageArchiveStore = client
+		}
+	}
+	usageArchiveService := service.NewUsageArchiveService(db, usageArchiveStore, cfg)
+	usageArchiveService.Start()
+	defer usageArchiveService.Stop()
+
+	// Per-container CPU, memory, network and disk IO samples of the metrics worker -> InfluxDB
+	containerMetricsService := service.NewContainerMetricsService(containerMetricsStore)
+	cond.SetContainerMetricsSink(containerMetricsService)
+
+	// Every evaluation of the scaling engine -> PostgreSQL, pruned after SCALING_DECISION_RETENTION_DAYS
+	scalingDecisionService := service.NewScalingDecisionService(repository.NewScalingDecisionRepository(db), cfg)
+	scalingDecisionService.Start()
+	defer scalingDecisionService.Stop()
+
+	// Fleet cost: estimated cloud node costs reconciled with the provider's billing, per-node cost timelines
+	fleetCostService := service.NewFleetCostService(cond, repository.NewNodeCostSampleRepository(db), cfg)
+	fleetCostService.Start()
+	defer fleetCostService.Stop()
+
+	// Admin settings of the scaling policies on top of the SCALING_* defaults
+	scalingPolicyService := service.NewScalingPolicyService(repository.NewScalingPolicyConfigRepository(db), cond, cfg)
 
 	// Initialize Scaling Engine (B5 + B8) if Hetzner Cloud token is configured
 	if cfg.HetznerCloudToken != "" {
 		hetznerProvider := cloud.NewHetznerProvider(cfg.HetznerCloudToken)
+		secretsService.OnRotate(secrets.HetznerCloudToken, hetznerProvider.SetToken)
 		cond.InitializeScaling(hetznerProvider, cfg.HetznerSSHKeyName, cfg.ScalingEnabled, remoteVelocityClient)
+		cond.ScalingEngine.SetDecisionSink(scalingDecisionService)
+		if err := scalingPolicyService.Apply(); err != nil {
+			logger.Error("Failed to apply scaling policy settings, using defaults", err, nil)
+		}
 		logger.Info("Scaling engine initialized", map[string]interface{}{
 			"ssh_key": cfg.HetznerSSHKeyName,
 			"enabled": cfg.ScalingEnabled,
@@ -318,6 +694,21 @@ func main() {
 	mcService.SetBackupService(backupService)
 	logger.Info("Backup service linked to MinecraftService for pre-operation backups", nil)
 
+	// Share the Conductor's pooled SSH connections with remote backup restores
+	if cond.RemoteClient != nil {
+		backupService.SetSSHPool(cond.RemoteClient.Pool())
+		logger.Info("SSH connection pool linked to BackupService for remote transfers", nil)
+
+		// Heap dumps of servers on worker nodes are downloaded over the same pool
+		heapDumpService.SetRemoteAccess(cond, cond.RemoteClient.Pool())
+
+		// SFTP and WebDAV sessions for servers on worker nodes share one pooled session per node
+		serverFileAccess.SetRemoteAccess(cond, cond.RemoteClient.Pool())
+	}
+
+	// Migration targets are checked against the country of the Conductor's nodes
+	residencyService.SetNodeResolver(cond)
+
 	// Link MinecraftService to Conductor as ServerStarter for queue processing
 	cond.SetServerStarter(mcService)
 	logger.Info("MinecraftService linked to Conductor as ServerStarter for queue processing", nil)
@@ -331,6 +722,7 @@ func main() {
 	logger.Info("ServerRepo linked to Conductor for ghost container cleanup (1-minute intervals)", nil)
 
 	cond.Start()
+	defer saveConductorState(cond) // Runs after cond.Stop, once nothing changes the registries anymore
 	defer cond.Stop()
 	logger.Info("Conductor Core started", nil)
 
@@ -346,6 +738,9 @@ func main() {
 	// Initialize Cost-Optimization Service for automatic server placement optimization
 	costOptimizationService := service.NewCostOptimizationService(serverRepo, migrationRepo)
 	costOptimizationService.SetConductor(cond)
+	costOptimizationService.SetResidencyService(residencyService)
+	migrationAnalyticsService := service.NewMigrationAnalyticsService(migrationRepo, backupRepo, cfg)
+	costOptimizationService.SetMigrationAnalytics(migrationAnalyticsService)
 	costOptimizationService.Start()
 	defer costOptimizationService.Stop()
 	logger.Info("Cost optimization service started", map[string]interface{}{
@@ -354,10 +749,18 @@ func main() {
 		"scaling_cooldown":  "2h",
 	})
 
+	// Customer-dedicated nodes: whole worker nodes rented by one customer (billed hourly, excluded from scaling)
+	dedicatedNodeService := service.NewDedicatedNodeService(db, cond, userRepo, serverRepo)
+	dedicatedNodeService.Start()
+	defer dedicatedNodeService.Stop()
+
 	// Initialize Migration Service for live server migrations
 	migrationService := service.NewMigrationService(migrationRepo, serverRepo, dockerService, backupService)
 	migrationService.SetConductor(cond)
 	migrationService.SetWebSocketHub(wsHub)
+	migrationService.SetOperationLimiter(opLimiter)
+	migrationService.SetWebhookService(webhookService)
+	migrationService.SetResidencyService(residencyService)
 	if remoteVelocityClient != nil {
 		migrationService.SetRemoteVelocityClient(remoteVelocityClient)
 	}
@@ -367,116 +770,40 @@ func main() {
 		"enabled":        true,
 	})
 
-	// CRITICAL: Sync running containers with Conductor state (prevents OOM after restarts)
-	logger.Info("Syncing running containers with Conductor state...", nil)
-	cond.SyncRunningContainers(dockerService, serverRepo)
-	logger.Info("Container state sync completed", nil)
+	// Failover: servers of a node that stays unhealthy are restored from their latest backup onto healthy nodes
+	if cfg.FailoverEnabled {
+		failoverService := service.NewFailoverService(cond, serverRepo, backupRepo, userRepo, mcService, migrationService, recoveryService, emailService, auditService, cfg)
+		failoverService.SetWebhookService(webhookService)
+		cond.HealthChecker.SetFailoverHandler(failoverService)
+		defer failoverService.Stop()
+		logger.Info("Node failover enabled", map[string]interface{}{
+			"confirmation_window": cfg.FailoverConfirmationWindow,
+		})
+	}
 
-	// CRITICAL: Sync queued servers from database into StartQueue (prevents queue loss after restarts)
-	logger.Info("Syncing queued servers into StartQueue...", nil)
-	cond.SyncQueuedServers(serverRepo, false) // Don't trigger scaling yet
-	logger.Info("Queue sync completed", nil)
+	// Eviction: servers of interruptible nodes with a termination notice are moved off before the provider reclaims the node
+	evictionService := service.NewEvictionService(cond, serverRepo, backupRepo, userRepo, mcService, migrationService, emailService, auditService)
+	cond.HealthChecker.SetEvictionHandler(evictionService)
 
-	// CRITICAL: Restore worker nodes from persisted state file FIRST
-	// This prevents data loss by restoring nodes that existed before restart
-	// These nodes get a recovery grace period to prevent immediate scale-down
-	if cond.CloudProvider != nil {
-		nodeStateFile := filepath.Join("./data", "node_state.json")
-		logger.Info("Restoring nodes from persisted state...", map[string]interface{}{
-			"state_file": nodeStateFile,
-		})
-		if err := cond.RestoreNodesFromState(nodeStateFile); err != nil {
-			logger.Error("Failed to restore nodes from state", err, nil)
-			// Continue anyway - will fall back to Hetzner sync
-		}
+	// Maintenance mode: rejects starts and destructive operations, pauses scaling and migrations
+	maintenanceService := service.NewMaintenanceService(opLimiter, cond.ScalingEngine, migrationService)
+	mcService.SetMaintenanceService(maintenanceService)
 
-		// CRITICAL: Sync with Hetzner Cloud API to discover any new nodes
-		// This is secondary to state file restoration
-		logger.Info("Syncing with Hetzner Cloud API...", nil)
-		cond.SyncExistingWorkerNodes(false) // Don't trigger scaling yet
-		logger.Info("Worker node sync completed", nil)
-
-		// CRITICAL: Restore containers from persisted state FIRST
-		// This preserves timing information (sleeping timers, etc) and prevents data loss
-		containerStateFile := filepath.Join("./data", "container_state.json")
-		logger.Info("Restoring containers from persisted state...", map[string]interface{}{
-			"state_file": containerStateFile,
-		})
-		syncedCount, err := cond.RestoreContainersFromState(containerStateFile, serverRepo)
-		if err != nil {
-			logger.Error("Failed to restore containers from state", err, nil)
-		} else {
-			logger.Info("Container state restoration completed", map[string]interface{}{
-				"synced_containers": syncedCount,
-			})
-		}
+	// CRITICAL: Reconcile the conductor state (containers, queue, nodes) and Velocity after the restart
+	// (prevents OOM, queue loss and "server not found" on the proxy; rerun via POST /api/admin/reconcile)
+	stateReconciler := service.NewStateReconciler(cond, dockerService, serverRepo, remoteVelocityClient, "./data", cfg)
+	stateReconciler.Run(context.Background(), service.ReconcileTriggerStartup)
 
-		// CRITICAL: Sync actual Docker containers from remote worker nodes
-		// This verifies containers still exist and reconciles with state file
-		logger.Info("Verifying actual Docker containers on remote worker nodes...", nil)
-		cond.SyncRemoteNodeContainers(serverRepo)
-		logger.Info("Remote container verification completed", nil)
-	}
+	// Readiness probe: database, Docker, Velocity, cloud provider and the startup reconciliation
+	readinessService := service.NewReadinessService(repository.GetDBProvider(), dockerService, remoteVelocityClient, cond.CloudProvider, stateReconciler, cfg)
 
-	// CRITICAL: Re-register all running servers with Velocity after restart
-	// This prevents "server not found" errors when backend restarts while servers are running
-	if remoteVelocityClient != nil {
-		logger.Info("Re-registering running servers with Velocity...", nil)
-		runningServers, err := serverRepo.FindByStatus(string(models.StatusRunning))
-		if err != nil {
-			logger.Error("Failed to find running servers for Velocity sync", err, nil)
-		} else {
-			registered := 0
-			for _, server := range runningServers {
-				if server.NodeID == "" {
-					logger.Warn("Skipping Velocity registration for server without node assignment", map[string]interface{}{
-						"server_id": server.ID,
-						"name":      server.Name,
-					})
-					continue
-				}
-
-				velocityServerName := fmt.Sprintf("mc-%s", server.ID)
-
-				// Get node IP
-				var serverIP string
-				if server.NodeID == "local-node" {
-					serverIP = cfg.ControlPlaneIP
-				} else {
-					remoteNode, err := cond.GetRemoteNode(server.NodeID)
-					if err != nil {
-						logger.Warn("Failed to get node IP for Velocity registration", map[string]interface{}{
-							"server_id": server.ID,
-							"node_id":   server.NodeID,
-							"error":     err.Error(),
-						})
-						continue
-					}
-					serverIP = remoteNode.IPAddress
-				}
-
-				serverAddress := fmt.Sprintf("%s:%d", serverIP, server.Port)
-
-				if err := remoteVelocityClient.RegisterServer(velocityServerName, serverAddress); err != nil {
-					logger.Warn("Failed to re-register server with Velocity", map[string]interface{}{
-						"server_id": server.ID,
-						"error":     err.Error(),
-					})
-				} else {
-					registered++
-					logger.Debug("Server re-registered with Velocity", map[string]interface{}{
-						"server_id": server.ID,
-						"name":      velocityServerName,
-						"address":   serverAddress,
-					})
-				}
-			}
-			logger.Info("Velocity state sync completed", map[string]interface{}{
-				"total_running": len(runningServers),
-				"registered":    registered,
-			})
-		}
-	}
+	// Self-diagnostics: actively probes every dependency for incident triage (GET /api/admin/diagnostics)
+	diagnosticsService := service.NewDiagnosticsService(repository.GetDBProvider(), dockerService, remoteVelocityClient, cond, cfg)
+
+	// Drift detection: compares database, Docker and Velocity periodically and heals confirmed drift
+	driftService := service.NewDriftService(cond, dockerService, serverRepo, mcService, remoteVelocityClient, cfg)
+	driftService.Start()
+	defer driftService.Stop()
 
 	// NOTE: No immediate scaling check after startup to prevent race conditions
 	// The Scaling Engine will run normally (every 2 minutes)
@@ -485,8 +812,26 @@ func main() {
 	authHandler := api.NewAuthHandler(authService)
 	oauthHandler := api.NewOAuthHandler(oauthService)
 	handler := api.NewHandler(mcService)
+	handler.SetAuditService(auditService)
+	auditHandler := api.NewAuditHandler(auditService)
+	maintenanceHandler := api.NewMaintenanceHandler(maintenanceService, auditService)
+	runtimeConfigHandler := api.NewRuntimeConfigHandler(runtimeConfig, auditService)
+	sshKeyHandler := api.NewSSHKeyHandler(sshKeyService)
+	schemaMigrator, err := repository.NewMigrator(db)
+	if err != nil {
+		logger.Fatal("Failed to load schema migrations", err, nil)
+	}
+	schemaHandler := api.NewSchemaHandler(schemaMigrator)
+	usageArchiveHandler := api.NewUsageArchiveHandler(usageArchiveService, auditService)
+	reconcileHandler := api.NewReconcileHandler(stateReconciler, auditService)
+	healthHandler := api.NewHealthHandler(readinessService)
+	diagnosticsHandler := api.NewDiagnosticsHandler(diagnosticsService)
+	driftHandler := api.NewDriftHandler(driftService)
+	archiveHandler := api.NewArchiveHandler(archiveService)
+	lifecycleHandler := api.NewLifecycleHandler(lifecyclePolicyService, serverRepo, auditService)
 	monitoringHandler := api.NewMonitoringHandler(monitoringService)
-	backupHandler := api.NewBackupHandler(backupService, backupRepo, backupQuotaService, serverRepo)
+	monitoringHandler.SetControlPlaneMonitor(controlPlaneMonitor)
+	backupHandler := api.NewBackupHandler(backupService, backupRepo, backupQuotaService, serverRepo, permissionService)
 	pluginHandler := api.NewPluginHandler(pluginService)
 	velocityHandler := api.NewVelocityHandler(velocityService, mcService)
 	wsHandler := api.NewWebSocketHandler(wsHub)
@@ -496,6 +841,12 @@ func main() {
 	consoleService := service.NewConsoleService(serverRepo, dockerService)
 	consoleHandler := api.NewConsoleHandler(consoleService)
 
+	// Chat announcements to running servers (e.g. before maintenance)
+	announcementService := service.NewAnnouncementService(db, serverRepo, consoleService)
+	announcementService.Start()
+	defer announcementService.Stop()
+	announcementHandler := api.NewAnnouncementHandler(announcementService, auditService)
+
 	// MOTD (Message of the Day) service
 	motdService := service.NewMOTDService(serverRepo, cfg)
 	motdHandler := api.NewMOTDHandler(motdService)
@@ -517,23 +868,102 @@ func main() {
 	metricsHandler := api.NewMetricsHandler()
 
 	// Player list service for whitelist, ops, banned players
-	playerListService := service.NewPlayerListService(serverRepo, consoleService, cfg)
+	playerListService := service.NewPlayerListService(serverRepo, repository.NewPlayerListRepository(db), consoleService, fileManagerService, cfg)
+	playerListService.StartPendingSync()
+	uuidService, err := newPlayerUUIDService(cfg, rateLimitStore)
+	if err != nil {
+		logger.Fatal("Failed to initialize player UUID resolution", err, nil)
+	}
+	playerListService.SetUUIDResolver(uuidService)
+	minecraftLinkService := service.NewMinecraftLinkService(repository.NewMinecraftLinkRepository(db), serverRepo, playerListService, consoleService, uuidService)
+	minecraftLinkService.StartAutoOp()
+	minecraftLinkHandler := api.NewMinecraftLinkHandler(minecraftLinkService)
+
+	// Global and organization bans, enforced at the Velocity proxy
+	playerBanService := service.NewPlayerBanService(repository.NewPlayerBanRepository(db), orgRepo, orgService, uuidService)
+	if remoteVelocityClient != nil {
+		playerBanService.SetEnforcer(remoteVelocityClient)
+		playerBanService.Start()
+		defer playerBanService.Stop()
+	}
+	playerBanHandler := api.NewPlayerBanHandler(playerBanService, auditService)
 	playerHandler := api.NewPlayerHandler(playerListService)
 
+	idlePolicyHandler := api.NewIdlePolicyHandler(idlePolicyService, serverRepo)
+
 	// World management service
 	worldService := service.NewWorldService(serverRepo, backupService, cfg)
+	worldService.SetMinecraftService(mcService)
+	worldService.SetConfigService(configService)
+	worldService.SetOperationLimiter(opLimiter)
+	worldService.SetConsoleService(consoleService)
+	worldService.SetDiskQuota(diskQuotaService)
+
+	// World exports are kept on the Storage Box if it is enabled, locally otherwise
+	var worldExportStorage *storage.SFTPClient
+	if cfg.StorageBoxEnabled {
+		client, err := storage.NewSFTPClient(cfg)
+		if err != nil {
+			logger.Warn("WORLD: Failed to initialize SFTP client, keeping world exports locally", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			worldExportStorage = client
+		}
+	}
+	worldService.SetExportStorage(repository.NewWorldExportRepository(db), worldExportStorage)
+	worldExportPruneWorker := service.NewWorldExportPruneWorker(worldService)
+	worldExportPruneWorker.Start()
+	defer worldExportPruneWorker.Stop()
 	worldHandler := api.NewWorldHandler(worldService)
+	heapDumpHandler := api.NewHeapDumpHandler(heapDumpService)
+
+	if cfg.SFTPGatewayEnabled {
+		if err := sftpGatewayService.Start(); err != nil {
+			logger.Error("Failed to start SFTP gateway", err, nil)
+		} else {
+			defer sftpGatewayService.Stop()
+		}
+	}
+	sftpHandler := api.NewSFTPHandler(sftpGatewayService)
+	webdavHandler := api.NewWebDAVHandler(webdavService)
+	diskQuotaWorker := service.NewDiskQuotaWorker(diskQuotaService, cfg.DiskQuotaScanInterval)
+	diskQuotaWorker.Start()
+	defer diskQuotaWorker.Stop()
+	diskHandler := api.NewDiskHandler(diskQuotaService)
+	diskHandler.SetAuditService(auditService)
+
+	// TPS/MSPT sampling (spark or tps/mspt commands), low-TPS alerts
+	performanceService := service.NewPerformanceService(serverRepo, pluginRepo, consoleService, containerMetricsService, tickMetricsStore, cfg)
+	performanceService.SetWebhookService(webhookService)
+	performanceService.Start()
+	defer performanceService.Stop()
+	performanceHandler := api.NewPerformanceHandler(performanceService)
+
+	// Vote site integration (managed NuVotifier + vote relay)
+	votifierService := service.NewVotifierService(db, serverRepo, dockerService, cfg)
+	votifierService.SetPluginManager(pluginManagerService)
+	votifierService.SetNodeResolver(cond)
+	votifierHandler := api.NewVotifierHandler(votifierService, cfg.BaseURL)
 
 	// Template service
 	templateService, err := service.NewTemplateService("templates/server-templates.json")
 	if err != nil {
 		logger.Fatal("Failed to initialize template service", err, nil)
 	}
+	templateService.SetRepository(repository.NewTemplateRepository(db))
+	templateService.SetBackupService(backupService)
 	templateHandler := api.NewTemplateHandler(templateService)
 
-	// Webhook service
-	webhookService := service.NewWebhookService(db)
+	// Clone service (copies servers, optionally saving them as user templates)
+	cloneService := service.NewCloneService(serverRepo, pluginRepo, mcService, backupService, pluginManagerService, templateService)
+	cloneService.SetOperationLimiter(opLimiter)
+	cloneHandler := api.NewCloneHandler(cloneService)
+	handler.SetCloneService(cloneService) // POST /api/servers with template_id
+
+	// Webhook handler
 	webhookHandler := api.NewWebhookHandler(webhookService, serverRepo)
+	webhookEndpointHandler := api.NewWebhookEndpointHandler(webhookService)
 
 	// Backup schedule handler
 	backupScheduleHandler := api.NewBackupScheduleHandler(backupScheduler, serverRepo)
@@ -542,29 +972,75 @@ func main() {
 	prometheusHandler := api.NewPrometheusHandler()
 
 	// Conductor handler for fleet orchestration
-	conductorHandler := api.NewConductorHandler(cond)
+	conductorHandler := api.NewConductorHandler(cond, migrationRepo)
+	conductorHandler.SetAuditService(auditService)
 
 	// Billing handler for cost analytics
 	billingHandler := api.NewBillingHandler(billingService)
+	stripeHandler := api.NewStripeHandler(stripeService)
+	walletHandler := api.NewWalletHandler(walletService, stripeService)
+	budgetHandler := api.NewBudgetHandler(billingService, serverRepo)
+	invoiceHandler := api.NewInvoiceHandler(invoiceService, userRepo)
+	orgHandler := api.NewOrganizationHandler(orgService)
+	shareHandler := api.NewShareHandler(permissionService, serverRepo)
+	operationHandler := api.NewOperationHandler(opLimiter)
+	apiKeyHandler := api.NewAPIKeyHandler(apiKeyService)
+	twoFactorHandler := api.NewTwoFactorHandler(twoFactorService, authService)
+	dedicatedNodeHandler := api.NewDedicatedNodeHandler(dedicatedNodeService)
+	ssoHandler := api.NewSSOHandler(ssoService)
+	eventReplayHandler := api.NewEventReplayHandler(eventReplayService)
+
+	// Alertmanager receiver: firing alerts → dashboard, admin emails and status page incidents
+	alertService := service.NewAlertService(repository.NewIncidentRepository(db), userRepo, emailService, cfg)
+	alertHandler := api.NewAlertHandler(alertService)
+	graphQLHandler := api.NewGraphQLHandler(mcService, backupRepo, pluginService, playerListService)
+	diagnosisService := service.NewDiagnosisService(serverRepo, dockerService, cfg)
+	diagnosisService.SetConductor(cond)
+	if remoteVelocityClient != nil {
+		diagnosisService.SetVelocityClient(remoteVelocityClient)
+	}
+	diagnosisHandler := api.NewDiagnosisHandler(diagnosisService)
 
 	// Marketplace handler for plugin marketplace
 	marketplaceHandler := api.NewMarketplaceHandler(pluginManagerService, pluginSyncService)
 
 	// Bulk operations handler for multi-server management
-	bulkHandler := api.NewBulkHandler(mcService, backupService)
+	bulkJobService := service.NewBulkJobService(mcService, pluginManagerService, configService)
+	bulkJobService.SetWebSocketHub(wsHub)
+	bulkJobService.SetJobService(jobService)
+	bulkHandler := api.NewBulkHandler(mcService, backupService, bulkJobService)
+	// Chunk pre-generation jobs
+	pregenService := service.NewPregenerationService(serverRepo, pluginRepo, consoleService)
+	pregenService.SetWebSocketHub(wsHub)
+	pregenService.SetJobService(jobService)
+	pregenService.SetWebhookService(webhookService)
+	pregenService.SetDiskQuota(diskQuotaService)
+	pregenHandler := api.NewPregenerationHandler(pregenService)
+
+	jobHandler := api.NewJobHandler(jobService, opLimiter, bulkJobService, pregenService)
 
 	// Scaling handler for auto-scaling (B5)
-	scalingHandler := api.NewScalingHandler(cond)
+	scalingHandler := api.NewScalingHandler(cond, scalingPolicyService, auditService)
+	scalingDecisionHandler := api.NewScalingDecisionHandler(scalingDecisionService)
+	fleetCostHandler := api.NewFleetCostHandler(fleetCostService)
+	remoteActionHandler := api.NewRemoteActionHandler(service.NewRemoteActionService(mcService, backupService, consoleService, maintenanceService), auditService)
+	applyService := service.NewApplyService(repository.NewServerDeclarationRepository(db), serverRepo, mcService, cloneService, configService, pluginManagerService)
+	applyHandler := api.NewApplyHandler(applyService, auditService)
 
 	// Cost optimization handler for cost analysis and suggestions (B8)
 	costOptHandler := api.NewCostOptimizationHandler(costOptimizationService)
 
 	// Migration handler for server migration management
-	migrationHandler := api.NewMigrationHandler(migrationRepo, serverRepo, cond)
+	migrationHandler := api.NewMigrationHandler(migrationRepo, serverRepo, cond, migrationService, migrationAnalyticsService)
 
 	// Dashboard WebSocket for real-time visualization
 	dashboardWs := api.NewDashboardWebSocket(cond)
 	dashboardWs.SetRepositories(migrationRepo, serverRepo) // Enable loading active migrations on reconnect
+	if eventTransport != nil {
+		if err := dashboardWs.SetTransport(eventTransport); err != nil {
+			logger.Fatal("Failed to subscribe dashboard WebSocket to event transport", err, nil)
+		}
+	}
 	go dashboardWs.Run()
 	defer dashboardWs.Shutdown()
 	logger.Info("Dashboard WebSocket started", nil)
@@ -580,48 +1056,7 @@ func main() {
 	containerSyncHandler := api.NewContainerSyncHandler(cond, serverRepo)
 
 	// Setup router
-	router := api.SetupRouter(authHandler, oauthHandler, handler, monitoringHandler, backupHandler, pluginHandler, velocityHandler, wsHandler, fileManagerHandler, consoleHandler, configHandler, fileHandler, motdHandler, metricsHandler, playerHandler, worldHandler, templateHandler, webhookHandler, backupScheduleHandler, prometheusHandler, conductorHandler, billingHandler, bulkHandler, marketplaceHandler, scalingHandler, costOptHandler, migrationHandler, dashboardWs, containerSyncHandler, cfg)
-
-	// Graceful shutdown
-	go func() {
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-		<-sigChan
-
-		logger.Info("Shutting down gracefully...", nil)
-
-		// CRITICAL: Save state before shutdown to prevent data loss on restart
-		if cond != nil {
-			// Save node state
-			if cond.CloudProvider != nil {
-				nodeStateFile := filepath.Join("./data", "node_state.json")
-				logger.Info("Saving node state before shutdown...", map[string]interface{}{
-					"state_file": nodeStateFile,
-				})
-				if err := cond.SaveNodeState(nodeStateFile); err != nil {
-					logger.Error("Failed to save node state", err, nil)
-				} else {
-					logger.Info("Node state saved successfully", nil)
-				}
-			}
-
-			// Save container state (preserves timing information)
-			containerStateFile := filepath.Join("./data", "container_state.json")
-			logger.Info("Saving container state before shutdown...", map[string]interface{}{
-				"state_file": containerStateFile,
-			})
-			if err := cond.SaveContainerState(containerStateFile); err != nil {
-				logger.Error("Failed to save container state", err, nil)
-			} else {
-				logger.Info("Container state saved successfully", nil)
-			}
-		}
-
-		// Leave servers running - they will be managed by auto-shutdown
-		// This allows maintenance without disrupting active servers
-		logger.Info("Shutdown complete", nil)
-		os.Exit(0)
-	}()
+	router := api.SetupRouter(authHandler, oauthHandler, handler, monitoringHandler, backupHandler, pluginHandler, velocityHandler, wsHandler, fileManagerHandler, consoleHandler, configHandler, fileHandler, motdHandler, metricsHandler, playerHandler, worldHandler, heapDumpHandler, votifierHandler, templateHandler, webhookHandler, backupScheduleHandler, prometheusHandler, conductorHandler, billingHandler, bulkHandler, marketplaceHandler, scalingHandler, costOptHandler, migrationHandler, dashboardWs, containerSyncHandler, idlePolicyHandler, stripeHandler, walletHandler, budgetHandler, invoiceHandler, orgHandler, shareHandler, operationHandler, apiKeyHandler, twoFactorHandler, dedicatedNodeHandler, ssoHandler, eventReplayHandler, alertHandler, graphQLHandler, diagnosisHandler, jobHandler, cloneHandler, pregenHandler, sftpHandler, webdavHandler, diskHandler, performanceHandler, auditHandler, maintenanceHandler, runtimeConfigHandler, sshKeyHandler, schemaHandler, usageArchiveHandler, reconcileHandler, driftHandler, archiveHandler, lifecycleHandler, minecraftLinkHandler, announcementHandler, playerBanHandler, scalingDecisionHandler, fleetCostHandler, webhookEndpointHandler, remoteActionHandler, applyHandler, healthHandler, diagnosticsHandler, cfg)
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.Port)
@@ -631,25 +1066,128 @@ func main() {
 		"health_check": fmt.Sprintf("http://localhost%s/health", addr),
 	})
 
-	if err := router.Run(addr); err != nil {
-		logger.Fatal("Failed to start server", err, nil)
+	srv := &http.Server{Addr: addr, Handler: router}
+	srv.RegisterOnShutdown(api.CloseWebSockets) // Shutdown doesn't wait for hijacked connections
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("Failed to start server", err, nil)
+		}
+	}()
+
+	// Graceful shutdown: stop accepting connections and let running requests finish, then return so
+	// the deferred Stop calls shut the workers down in reverse start order (dependents first).
+	// Servers are left running - they are managed by auto-shutdown, so a deploy doesn't disrupt players.
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	<-sigChan
+
+	shutdownTimeout, err := time.ParseDuration(cfg.ShutdownTimeout)
+	if err != nil || shutdownTimeout <= 0 {
+		shutdownTimeout = 30 * time.Second
+	}
+	logger.Info("Shutting down gracefully...", map[string]interface{}{
+		"timeout": shutdownTimeout.String(),
+	})
+	go func() {
+		<-sigChan
+		logger.Warn("Second signal received, exiting without cleanup", nil)
+		os.Exit(1)
+	}()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("Requests still running after the shutdown timeout, closing them", map[string]interface{}{
+			"error": err.Error(),
+		})
+		srv.Close()
+	}
+	logger.Info("HTTP server stopped, stopping background workers", nil)
+}
+
+// applyRateLimits sets the API rate limiters from the configuration (at startup and on config reload)
+func applyRateLimits(cfg *config.Config) {
+	middleware.GlobalRateLimiter.SetLimit(cfg.RateLimitGlobalPerMinute, cfg.RateLimitGlobalBurst)
+	middleware.APIRateLimiter.SetLimit(cfg.RateLimitAPIPerMinute, cfg.RateLimitAPIBurst)
+	middleware.AuthRateLimiter.SetLimit(cfg.RateLimitAuthPerMinute, cfg.RateLimitAuthBurst)
+	middleware.FileUploadRateLimiter.SetLimit(cfg.RateLimitUploadPerMinute, cfg.RateLimitUploadBurst)
+	middleware.ExpensiveRateLimiter.SetLimit(cfg.RateLimitExpensivePerMinute, cfg.RateLimitExpensiveBurst)
+	middleware.RemoteActionRateLimiter.SetLimit(cfg.RateLimitActionPerMinute, cfg.RateLimitActionBurst)
+}
+
+// newServerCache creates the server lookup cache of CACHE_STORE ("memory" or "redis")
+func newServerCache(cfg *config.Config) (*cache.Cache, error) {
+	ttl, err := time.ParseDuration(cfg.CacheTTL)
+	if err != nil || ttl <= 0 {
+		return nil, fmt.Errorf("invalid CACHE_TTL %q", cfg.CacheTTL)
+	}
+	store, err := newCacheStore(cfg)
+	if err != nil {
+		return nil, err
 	}
+	return cache.New("servers", store, ttl), nil
 }
 
-// parseLogLevel converts a string to a logger.LogLevel
-func parseLogLevel(level string) logger.LogLevel {
-	switch strings.ToUpper(level) {
-	case "DEBUG":
-		return logger.DEBUG
-	case "INFO":
-		return logger.INFO
-	case "WARN":
-		return logger.WARN
-	case "ERROR":
-		return logger.ERROR
-	case "FATAL":
-		return logger.FATAL
+// newCacheStore creates the store of CACHE_STORE ("memory" or "redis")
+func newCacheStore(cfg *config.Config) (cache.Store, error) {
+	switch cfg.CacheStore {
+	case "memory":
+		return cache.NewMemoryStore(), nil
+	case "redis":
+		return cache.NewRedisStore(cfg.CacheRedisURL)
 	default:
-		return logger.INFO
+		return nil, fmt.Errorf("unknown CACHE_STORE %q (memory or redis)", cfg.CacheStore)
+	}
+}
+
+// newPlayerUUIDService creates the Mojang UUID resolver of the player lists
+// Profiles are cached in CACHE_STORE (in memory if caching is off) and lookups share the
+// rate limit store, so all API replicas stay below MOJANG_REQUESTS_PER_MINUTE together.
+func newPlayerUUIDService(cfg *config.Config, limiter ratelimit.Store) (*service.PlayerUUIDService, error) {
+	ttl, err := time.ParseDuration(cfg.PlayerProfileCacheTTL)
+	if err != nil || ttl <= 0 {
+		return nil, fmt.Errorf("invalid PLAYER_PROFILE_CACHE_TTL %q", cfg.PlayerProfileCacheTTL)
+	}
+	if cfg.MojangRequestsPerMinute <= 0 {
+		return nil, fmt.Errorf("invalid MOJANG_REQUESTS_PER_MINUTE %d", cfg.MojangRequestsPerMinute)
+	}
+	var store cache.Store = cache.NewMemoryStore()
+	if cfg.CacheStore != "" {
+		if store, err = newCacheStore(cfg); err != nil {
+			return nil, err
+		}
+	}
+	if limiter == nil {
+		limiter = ratelimit.NewMemoryStore()
+	}
+	return service.NewPlayerUUIDService(external.NewMojangClient(), store, ttl, limiter, cfg.MojangRequestsPerMinute), nil
+}
+
+// saveConductorState writes the node and container registries for the next start
+// (the start queue needs no file: queued servers keep their status in the database and
+// SyncQueuedServers rebuilds the queue on startup)
+func saveConductorState(cond *conductor.Conductor) {
+	// Save node state
+	if cond.CloudProvider != nil {
+		nodeStateFile := filepath.Join("./data", "node_state.json")
+		logger.Info("Saving node state before shutdown...", map[string]interface{}{
+			"state_file": nodeStateFile,
+		})
+		if err := cond.SaveNodeState(nodeStateFile); err != nil {
+			logger.Error("Failed to save node state", err, nil)
+		} else {
+			logger.Info("Node state saved successfully", nil)
+		}
+	}
+
+	// Save container state (preserves timing information)
+	containerStateFile := filepath.Join("./data", "container_state.json")
+	logger.Info("Saving container state before shutdown...", map[string]interface{}{
+		"state_file": containerStateFile,
+	})
+	if err := cond.SaveContainerState(containerStateFile); err != nil {
+		logger.Error("Failed to save container state", err, nil)
+	} else {
+		logger.Info("Container state saved successfully", nil)
 	}
 }
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/payperplay/hosting/pkg/sdk"
+)
+
+func showLogs(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	tail := fs.Int("tail", 100, "number of lines")
+	args, err := a.parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Logs string `json:"logs"`
+	}
+	var raw json.RawMessage
+	if err := a.client.GetServerLogs(ctx, args[0], url.Values{"tail": {strconv.Itoa(*tail)}}, &raw); err != nil {
+		return err
+	}
+	if a.json {
+		return printJSON(raw, false)
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return fmt.Errorf("unexpected response: %w", err)
+	}
+	fmt.Print(resp.Logs)
+	return nil
+}
+
+// streamConsole prints the live console and sends every line typed on stdin as a command
+func streamConsole(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("console", flag.ExitOnError)
+	args, err := a.parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	stream, err := a.client.StreamConsole(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	go func() {
+		<-ctx.Done()
+		stream.Close() // Unblocks Receive
+	}()
+
+	// Commands from stdin (ends with EOF, e.g. when piped)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if command := strings.TrimSpace(scanner.Text()); command != "" {
+				if err := stream.SendCommand(command); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		msg, err := stream.Receive()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("console closed: %w", err)
+		}
+		if err := printConsoleMessage(a, msg); err != nil {
+			return err
+		}
+	}
+}
+
+// printConsoleMessage prints a console message: as a JSON line in -json mode, log lines as they
+// are, command output and errors marked
+func printConsoleMessage(a *app, msg *sdk.ConsoleMessage) error {
+	if a.json {
+		return printJSON(msg, true)
+	}
+	switch msg.Type {
+	case "response":
+		fmt.Println("> " + strings.TrimRight(msg.Content, "\n"))
+	case "error":
+		fmt.Fprintln(os.Stderr, "error: "+msg.Content)
+	default:
+		fmt.Println(strings.TrimRight(msg.Content, "\n"))
+	}
+	return nil
+}
+
+func execCommand(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("exec", flag.ExitOnError)
+	args, err := a.parseTextArgs(fs, args, 2, 1)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Response string `json:"response"`
+	}
+	var raw json.RawMessage
+	body := &sdk.ExecuteConsoleCommandRequest{Command: strings.Join(args[1:], " ")}
+	if err := a.client.ExecuteConsoleCommand(ctx, args[0], body, &raw); err != nil {
+		return err
+	}
+	return a.decodeAndReport(raw, &resp, func() string { return strings.TrimRight(resp.Response, "\n") })
+}
@@ -0,0 +1,184 @@
+// Command payperplay-cli manages servers through the REST API (pkg/sdk): list, start and stop
+// servers, tail the console, upload files, trigger backups and follow migrations.
+//
+// Authenticate with an API key (recommended for scripts and CI) or a JWT access token, given as
+// flags or environment variables:
+//
+//	export PAYPERPLAY_URL=https://panel.example.com
+//	export PAYPERPLAY_API_KEY=ppk_...
+//
+// Usage:
+//
+//	payperplay-cli servers [-status running]
+//	payperplay-cli server <id>
+//	payperplay-cli start <id> [-wait]
+//	payperplay-cli stop <id>
+//	payperplay-cli logs <id> [-tail 100]
+//	payperplay-cli console <id>                       (live console; type commands, Ctrl-C to quit)
+//	payperplay-cli exec <id> [--] <command...>        (flags go before the command; use -- if it starts with "-")
+//	payperplay-cli upload <id> <local-file> <path> [-overwrite]
+//	payperplay-cli backup <id> [-description text] [-wait]
+//	payperplay-cli migration <id> [-follow]
+//	payperplay-cli operation <operation-id> [-wait]
+//
+// Every command accepts -json to print the API responses as JSON (one object per line while
+// following or streaming), for use in scripts.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/payperplay/hosting/pkg/sdk"
+)
+
+// command is a subcommand of the CLI
+type command struct {
+	usage string
+	run   func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = map[string]command{
+	"servers":   {"servers [-status running]", listServers},
+	"server":    {"server <id>", showServer},
+	"start":     {"start <id> [-wait]", startServer},
+	"stop":      {"stop <id>", stopServer},
+	"logs":      {"logs <id> [-tail 100]", showLogs},
+	"console":   {"console <id>", streamConsole},
+	"exec":      {"exec <id> [--] <command...>", execCommand},
+	"upload":    {"upload <id> <local-file> <path> [-overwrite]", uploadFile},
+	"backup":    {"backup <id> [-description text] [-wait]", createBackup},
+	"migration": {"migration <id> [-follow]", showMigration},
+	"operation": {"operation <operation-id> [-wait]", showOperation},
+}
+
+// commandOrder is the order of the commands in the usage text
+var commandOrder = []string{"servers", "server", "start", "stop", "logs", "console", "exec", "upload", "backup", "migration", "operation"}
+
+// app holds the API client and the output mode shared by all commands
+type app struct {
+	client *sdk.Client
+	json   bool
+	usage  string // Of the running command
+}
+
+func main() {
+	global := flag.NewFlagSet("payperplay-cli", flag.ExitOnError)
+	baseURL := global.String("url", os.Getenv("PAYPERPLAY_URL"), "API base URL (PAYPERPLAY_URL)")
+	apiKey := global.String("api-key", os.Getenv("PAYPERPLAY_API_KEY"), "API key (PAYPERPLAY_API_KEY)")
+	token := global.String("token", os.Getenv("PAYPERPLAY_TOKEN"), "JWT access token, alternative to -api-key (PAYPERPLAY_TOKEN)")
+	jsonOutput := global.Bool("json", false, "print JSON instead of tables")
+	global.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: payperplay-cli [-url URL] [-api-key KEY] [-json] <command> [arguments]")
+		fmt.Fprintln(os.Stderr, "\ncommands:")
+		for _, name := range commandOrder {
+			fmt.Fprintln(os.Stderr, "  "+commands[name].usage)
+		}
+		fmt.Fprintln(os.Stderr, "\nflags:")
+		global.PrintDefaults()
+	}
+	global.Parse(os.Args[1:])
+
+	if global.NArg() == 0 {
+		global.Usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[global.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", global.Arg(0))
+		global.Usage()
+		os.Exit(2)
+	}
+	if *baseURL == "" || (*apiKey == "" && *token == "") {
+		fmt.Fprintln(os.Stderr, "set the API URL and an API key (-url and -api-key, or PAYPERPLAY_URL and PAYPERPLAY_API_KEY)")
+		os.Exit(2)
+	}
+
+	client := sdk.NewClient(*baseURL)
+	client.APIKey, client.Token = *apiKey, *token
+	// Streams and uploads run longer than single requests; commands set their own deadlines
+	client.HTTPClient.Timeout = 0
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	a := &app{client: client, json: *jsonOutput, usage: cmd.usage}
+	if err := cmd.run(ctx, a, global.Args()[1:]); err != nil {
+		if errors.Is(err, context.Canceled) {
+			os.Exit(130)
+		}
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// parseArgs parses the flags of a command (-json is accepted after the command too) and checks
+// that at least minArgs arguments are left
+func (a *app) parseArgs(fs *flag.FlagSet, args []string, minArgs int) ([]string, error) {
+	return a.parseFlags(fs, interleaveFlags(args, -1), minArgs)
+}
+
+// parseTextArgs is parseArgs for commands that end in free text ("exec <id> <command...>"): flags
+// are only picked from before the text, which starts after lead arguments, so "tp @p 0 -60 0" stays intact
+func (a *app) parseTextArgs(fs *flag.FlagSet, args []string, minArgs, lead int) ([]string, error) {
+	return a.parseFlags(fs, interleaveFlags(args, lead), minArgs)
+}
+
+func (a *app) parseFlags(fs *flag.FlagSet, args []string, minArgs int) ([]string, error) {
+	fs.BoolVar(&a.json, "json", a.json, "print JSON instead of tables")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: payperplay-cli "+a.usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() < minArgs {
+		fs.Usage()
+		os.Exit(2)
+	}
+	return fs.Args(), nil
+}
+
+// interleaveFlags moves flags in front of the positional arguments, so "start <id> -wait" works
+// like "start -wait <id>" (the flag package stops at the first argument). Everything after "--"
+// stays positional, and so does everything from positional argument lead+1 on (lead < 0 = no limit).
+func interleaveFlags(args []string, lead int) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || (lead >= 0 && len(positional) > lead) {
+			if arg == "--" {
+				i++
+			}
+			positional = append(positional, args[i:]...)
+			break
+		}
+		if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+			flags = append(flags, arg)
+			// A separate value of a non-boolean flag ("-tail 100")
+			if !strings.Contains(arg, "=") && i+1 < len(args) && valueFlags[strings.TrimLeft(arg, "-")] {
+				flags = append(flags, args[i+1])
+				i++
+			}
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	return append(append(flags, "--"), positional...)
+}
+
+// valueFlags are the command flags that take a value
+var valueFlags = map[string]bool{
+	"status":      true,
+	"tail":        true,
+	"description": true,
+	"chunk-size":  true,
+	"interval":    true,
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterleaveFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		lead int
+		want []string
+	}{
+		{
+			name: "flag after argument",
+			args: []string{"abc", "-wait"},
+			lead: -1,
+			want: []string{"-wait", "--", "abc"},
+		},
+		{
+			name: "separate flag value",
+			args: []string{"abc", "-tail", "100", "-json"},
+			lead: -1,
+			want: []string{"-tail", "100", "-json", "--", "abc"},
+		},
+		{
+			name: "double dash",
+			args: []string{"abc", "--", "-x"},
+			lead: -1,
+			want: []string{"--", "abc", "-x"},
+		},
+		{
+			name: "command text keeps negative numbers",
+			args: []string{"abc", "tp", "@p", "0", "-60", "0"},
+			lead: 1,
+			want: []string{"--", "abc", "tp", "@p", "0", "-60", "0"},
+		},
+		{
+			name: "flags before command text",
+			args: []string{"-json", "abc", "-json", "say", "-s", "hi"},
+			lead: 1,
+			want: []string{"-json", "-json", "--", "abc", "say", "-s", "hi"},
+		},
+		{
+			name: "command text starting with a dash",
+			args: []string{"abc", "--", "-60"},
+			lead: 1,
+			want: []string{"--", "abc", "-60"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := interleaveFlags(tt.args, tt.lead); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("interleaveFlags(%q, %d) = %q, want %q", tt.args, tt.lead, got, tt.want)
+			}
+		})
+	}
+}
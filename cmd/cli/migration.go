@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// migration is a move of a server between nodes (GET /api/servers/:id/migrations)
+type migration struct {
+	ID               string `json:"id"`
+	FromNodeName     string `json:"from_node_name"`
+	ToNodeName       string `json:"to_node_name"`
+	Status           string `json:"status"`
+	Reason           string `json:"reason"`
+	DataSyncProgress int    `json:"data_sync_progress"`
+	TransferBytes    int64  `json:"transfer_bytes"`
+	DowntimeMs       int64  `json:"downtime_ms"`
+	ErrorMessage     string `json:"error_message"`
+}
+
+// showMigration prints the active (or last) migration of a server; -follow polls it until it is finished
+func showMigration(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("migration", flag.ExitOnError)
+	follow := fs.Bool("follow", false, "print progress until the migration is finished")
+	interval := fs.Duration("interval", pollInterval, "poll interval with -follow")
+	args, err := a.parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	serverID := args[0]
+
+	active, err := a.activeMigration(ctx, serverID)
+	if err != nil {
+		return err
+	}
+	if active == nil {
+		last, err := a.lastMigration(ctx, serverID, "")
+		if err != nil {
+			return err
+		}
+		if last == nil {
+			return a.report(nil, "no migrations")
+		}
+		return a.report(last, describeMigration(last))
+	}
+	if !*follow {
+		return a.report(active, describeMigration(active))
+	}
+
+	line := describeMigration(active)
+	if err := a.report(active, line); err != nil {
+		return err
+	}
+	for {
+		if err := sleep(ctx, *interval); err != nil {
+			return err
+		}
+		current, err := a.activeMigration(ctx, serverID)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.ID != active.ID {
+			// Finished: the final state is in the migration history
+			final, err := a.lastMigration(ctx, serverID, active.ID)
+			if err != nil {
+				return err
+			}
+			if final == nil {
+				return fmt.Errorf("migration %s not found", active.ID)
+			}
+			if err := a.report(final, describeMigration(final)); err != nil {
+				return err
+			}
+			if final.Status != "completed" {
+				return fmt.Errorf("migration %s", final.Status)
+			}
+			return nil
+		}
+		if next := describeMigration(current); next != line {
+			line = next
+			if err := a.report(current, line); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// activeMigration returns the migration in progress, nil if there is none
+func (a *app) activeMigration(ctx context.Context, serverID string) (*migration, error) {
+	var resp struct {
+		Migration *migration `json:"migration"`
+	}
+	if err := a.client.GetActiveMigration(ctx, serverID, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Migration, nil
+}
+
+// lastMigration returns the migration with the ID, or the newest one if id is empty
+func (a *app) lastMigration(ctx context.Context, serverID, id string) (*migration, error) {
+	var resp struct {
+		Migrations []migration `json:"migrations"` // Newest first
+	}
+	if err := a.client.GetServerMigrations(ctx, serverID, &resp); err != nil {
+		return nil, err
+	}
+	for i := range resp.Migrations {
+		if id == "" || resp.Migrations[i].ID == id {
+			return &resp.Migrations[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// describeMigration returns a one-line status of a migration
+func describeMigration(m *migration) string {
+	line := fmt.Sprintf("migration %s %s -> %s (%s): %s", m.ID, m.FromNodeName, m.ToNodeName, m.Reason, m.Status)
+	switch m.Status {
+	case "preparing", "transferring", "completing":
+		line += fmt.Sprintf(" %d%%", m.DataSyncProgress)
+		if m.TransferBytes > 0 {
+			line += ", " + formatBytes(m.TransferBytes)
+		}
+	case "completed":
+		line += fmt.Sprintf(", %s transferred, %s downtime", formatBytes(m.TransferBytes), time.Duration(m.DowntimeMs)*time.Millisecond)
+	}
+	if m.ErrorMessage != "" {
+		line += ": " + m.ErrorMessage
+	}
+	return line
+}
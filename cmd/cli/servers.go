@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/sdk"
+)
+
+// pollInterval is how often -wait and -follow poll the API
+const pollInterval = 2 * time.Second
+
+// server is the part of a server the tables show
+type server struct {
+	ID               string
+	Name             string
+	Status           string
+	ServerType       string
+	MinecraftVersion string
+	RAMMb            int
+	Port             int
+}
+
+// operation is a queued or running background operation (GET /api/operations/:operation_id)
+type operation struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	ServerID string `json:"server_id"`
+	Status   string `json:"status"`
+	Position int    `json:"position"`
+	Phase    string `json:"phase"`
+	Progress int    `json:"progress"`
+	Error    string `json:"error"`
+}
+
+// finished reports whether the operation is done (successfully or not)
+func (o *operation) finished() bool {
+	return o.Status == "completed" || o.Status == "failed" || o.Status == "cancelled"
+}
+
+func listServers(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("servers", flag.ExitOnError)
+	status := fs.String("status", "", "only servers with this status (running, stopped, ...)")
+	if _, err := a.parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if *status != "" {
+		query.Set("status", *status)
+	}
+	var raw json.RawMessage
+	if err := a.client.ServerListServers(ctx, query, &raw); err != nil {
+		return err
+	}
+	if a.json {
+		return printJSON(raw, false)
+	}
+
+	var servers []server
+	if err := json.Unmarshal(raw, &servers); err != nil {
+		return fmt.Errorf("unexpected response: %w", err)
+	}
+	rows := make([][]string, 0, len(servers))
+	for _, s := range servers {
+		rows = append(rows, []string{s.ID, s.Name, s.Status, s.ServerType, s.MinecraftVersion, strconv.Itoa(s.RAMMb) + " MB", strconv.Itoa(s.Port)})
+	}
+	return printTable([]string{"ID", "NAME", "STATUS", "TYPE", "VERSION", "RAM", "PORT"}, rows)
+}
+
+func showServer(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("server", flag.ExitOnError)
+	args, err := a.parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var raw json.RawMessage
+	if err := a.client.GetServer(ctx, args[0], &raw); err != nil {
+		return err
+	}
+	if a.json {
+		return printJSON(raw, false)
+	}
+
+	var s server
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return fmt.Errorf("unexpected response: %w", err)
+	}
+	return printTable([]string{"FIELD", "VALUE"}, [][]string{
+		{"ID", s.ID},
+		{"Name", s.Name},
+		{"Status", s.Status},
+		{"Type", s.ServerType},
+		{"Version", s.MinecraftVersion},
+		{"RAM", strconv.Itoa(s.RAMMb) + " MB"},
+		{"Port", strconv.Itoa(s.Port)},
+	})
+}
+
+func startServer(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("start", flag.ExitOnError)
+	wait := fs.Bool("wait", false, "wait until the server is running")
+	args, err := a.parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Message   string     `json:"message"`
+		Operation *operation `json:"operation"`
+	}
+	var raw json.RawMessage
+	if err := a.client.StartServer(ctx, args[0], &raw); err != nil {
+		return err
+	}
+	if err := a.decodeAndReport(raw, &resp, func() string { return resp.Message }); err != nil {
+		return err
+	}
+	if !*wait {
+		return nil
+	}
+
+	// A queued start runs once the plan has a free slot
+	if resp.Operation != nil {
+		op, err := a.waitOperation(ctx, resp.Operation.ID)
+		if err != nil {
+			return err
+		}
+		if op.Status != "completed" {
+			return fmt.Errorf("start %s: %s", op.Status, op.Error)
+		}
+	}
+	return a.waitServerStatus(ctx, args[0], "running")
+}
+
+func stopServer(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("stop", flag.ExitOnError)
+	args, err := a.parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Message string `json:"message"`
+	}
+	var raw json.RawMessage
+	if err := a.client.StopServer(ctx, args[0], &raw); err != nil {
+		return err
+	}
+	return a.decodeAndReport(raw, &resp, func() string { return resp.Message })
+}
+
+func createBackup(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	description := fs.String("description", "", "shown in the backup list")
+	wait := fs.Bool("wait", false, "wait until the backup is completed")
+	args, err := a.parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Message string `json:"message"`
+		Backup  struct {
+			ID     string
+			Status string
+		} `json:"backup"`
+		Operation *operation `json:"operation"`
+	}
+	body := &sdk.CreateBackupRequest{Type: "manual", Description: *description}
+	var raw json.RawMessage
+	if err := a.client.CreateBackup(ctx, args[0], body, &raw); err != nil {
+		return err
+	}
+	if err := a.decodeAndReport(raw, &resp, func() string {
+		return fmt.Sprintf("%s (backup %s)", resp.Message, resp.Backup.ID)
+	}); err != nil {
+		return err
+	}
+	if !*wait {
+		return nil
+	}
+
+	last := ""
+	for {
+		var backup struct {
+			ID             string
+			Status         string
+			CompressedSize int64
+			ErrorMessage   string
+		}
+		if err := a.client.GetBackup(ctx, resp.Backup.ID, &backup); err != nil {
+			return err
+		}
+		if backup.Status != last {
+			last = backup.Status
+			if err := a.report(backup, "backup "+backup.Status); err != nil {
+				return err
+			}
+		}
+		switch backup.Status {
+		case "completed":
+			if !a.json {
+				fmt.Printf("size: %s\n", formatBytes(backup.CompressedSize))
+			}
+			return nil
+		case "failed", "cancelled":
+			return fmt.Errorf("backup %s: %s", backup.Status, backup.ErrorMessage)
+		}
+		if err := sleep(ctx, pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+func showOperation(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("operation", flag.ExitOnError)
+	wait := fs.Bool("wait", false, "wait until the operation is finished")
+	args, err := a.parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	if *wait {
+		op, err := a.waitOperation(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if op.Status != "completed" {
+			return fmt.Errorf("operation %s: %s", op.Status, op.Error)
+		}
+		return nil
+	}
+
+	var op operation
+	if err := a.client.GetOperation(ctx, args[0], &op); err != nil {
+		return err
+	}
+	return a.report(op, describeOperation(&op))
+}
+
+// waitOperation polls an operation until it is finished, printing every change
+func (a *app) waitOperation(ctx context.Context, operationID string) (*operation, error) {
+	last := ""
+	for {
+		var op operation
+		if err := a.client.GetOperation(ctx, operationID, &op); err != nil {
+			return nil, err
+		}
+		if line := describeOperation(&op); line != last {
+			last = line
+			if err := a.report(op, line); err != nil {
+				return nil, err
+			}
+		}
+		if op.finished() {
+			return &op, nil
+		}
+		if err := sleep(ctx, pollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitServerStatus polls a server until it has the status, failing on error states
+func (a *app) waitServerStatus(ctx context.Context, serverID, status string) error {
+	last := ""
+	for {
+		var s server
+		if err := a.client.GetServer(ctx, serverID, &s); err != nil {
+			return err
+		}
+		if s.Status != last {
+			last = s.Status
+			if err := a.report(s, "server "+s.Status); err != nil {
+				return err
+			}
+		}
+		switch s.Status {
+		case status:
+			return nil
+		case "error", "suspended", "archived":
+			return fmt.Errorf("server is %s", s.Status)
+		}
+		if err := sleep(ctx, pollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// describeOperation returns a one-line status of an operation
+func describeOperation(op *operation) string {
+	line := fmt.Sprintf("%s %s: %s", op.Kind, op.ID, op.Status)
+	switch {
+	case op.Status == "queued" && op.Position > 0:
+		line += fmt.Sprintf(" (position %d)", op.Position)
+	case op.Phase != "":
+		line += fmt.Sprintf(" (%s %d%%)", op.Phase, op.Progress)
+	}
+	if op.Error != "" {
+		line += ": " + op.Error
+	}
+	return line
+}
+
+// report prints v as one JSON line in -json mode, otherwise the message
+func (a *app) report(v interface{}, message string) error {
+	if a.json {
+		return printJSON(v, true)
+	}
+	fmt.Println(message)
+	return nil
+}
+
+// decodeAndReport decodes an API response into v and reports it: the response as is in -json mode,
+// otherwise the message built from v
+func (a *app) decodeAndReport(raw json.RawMessage, v interface{}, message func() string) error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("unexpected response: %w", err)
+	}
+	return a.report(raw, message())
+}
+
+// sleep waits for d or until ctx is cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/payperplay/hosting/pkg/sdk"
+)
+
+// maxChunkRetries is how often a chunk is retried after a network error or offset mismatch
+const maxChunkRetries = 5
+
+// fileUpload is a resumable upload (POST /api/servers/:id/files/uploads)
+type fileUpload struct {
+	ID        string `json:"id"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Offset    int64  `json:"offset"`
+	Completed bool   `json:"completed"`
+}
+
+// uploadFile uploads a local file in chunks; interrupted chunks continue at the offset the server has
+func uploadFile(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("upload", flag.ExitOnError)
+	overwrite := fs.Bool("overwrite", false, "replace an existing file")
+	chunkSize := fs.Int64("chunk-size", 8<<20, "bytes per request")
+	args, err := a.parseArgs(fs, args, 3)
+	if err != nil {
+		return err
+	}
+	if *chunkSize <= 0 {
+		return fmt.Errorf("-chunk-size must be positive")
+	}
+
+	file, err := os.Open(args[1])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	var upload fileUpload
+	body := &sdk.CreateUploadRequest{Path: args[2], Size: info.Size(), Overwrite: *overwrite}
+	if err := a.client.CreateUpload(ctx, args[0], body, &upload); err != nil {
+		return err
+	}
+
+	retries := 0
+	for !upload.Completed {
+		n := min(*chunkSize, upload.Size-upload.Offset)
+		chunk := &sdk.Chunk{Offset: upload.Offset, Size: n, Content: io.NewSectionReader(file, upload.Offset, n)}
+		var next fileUpload
+		err := a.client.AppendUploadChunk(ctx, args[0], upload.ID, chunk, &next)
+		if err != nil {
+			if ctx.Err() != nil || !resumable(err) || retries >= maxChunkRetries {
+				return fmt.Errorf("upload %s at %s: %w", upload.ID, formatBytes(upload.Offset), err)
+			}
+			retries++
+			if err := sleep(ctx, pollInterval); err != nil {
+				return err
+			}
+			// Continue at the offset the server has
+			if err := a.client.FileManagerGetUploadGet(ctx, args[0], upload.ID, &next); err != nil {
+				return err
+			}
+		} else {
+			retries = 0
+		}
+		upload = next
+
+		if a.json {
+			if err := printJSON(upload, true); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("\r%s / %s", formatBytes(upload.Offset), formatBytes(upload.Size))
+		}
+	}
+	if !a.json {
+		fmt.Printf("\nuploaded %s\n", upload.Path)
+	}
+	return nil
+}
+
+// resumable reports whether an upload can continue after err: network errors and offset
+// mismatches (409) can, other API errors can't
+func resumable(err error) bool {
+	var apiErr *sdk.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusConflict
+	}
+	return true
+}
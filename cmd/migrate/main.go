@@ -0,0 +1,91 @@
+// Command migrate applies, rolls back and shows the versioned database schema
+// migrations of internal/repository. It reads the same configuration as the API
+// (DATABASE_URL, CONFIG_FILE).
+//
+// Usage (from the repository root):
+//
+//	go run ./cmd/migrate status
+//	go run ./cmd/migrate up
+//	go run ./cmd/migrate down [steps]     (default 1)
+//	go run ./cmd/migrate force <version>  (after repairing a dirty migration by hand)
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: migrate status | up | down [steps] | force <version>")
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	if err := repository.OpenDB(cfg); err != nil {
+		log.Fatalf("failed to connect: %v", err)
+	}
+	migrator, err := repository.NewMigrator(repository.GetDB())
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+
+	switch flag.Arg(0) {
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("failed to read schema status: %v", err)
+		}
+		out, _ := json.MarshalIndent(status, "", "  ")
+		fmt.Println(string(out))
+
+	case "up":
+		applied, err := migrator.Up(cfg.DatabaseAllowNewerSchema)
+		if err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		fmt.Printf("applied %d migration(s)\n", applied)
+
+	case "down":
+		steps := 1
+		if flag.NArg() > 1 {
+			if steps, err = strconv.Atoi(flag.Arg(1)); err != nil || steps < 1 {
+				log.Fatalf("invalid number of steps %q", flag.Arg(1))
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		if err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+		fmt.Printf("rolled back %d migration(s)\n", reverted)
+
+	case "force":
+		if flag.NArg() < 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		version, err := strconv.ParseInt(flag.Arg(1), 10, 64)
+		if err != nil || version < 0 {
+			log.Fatalf("invalid version %q", flag.Arg(1))
+		}
+		if err := migrator.Force(version); err != nil {
+			log.Fatalf("migrate force: %v", err)
+		}
+		fmt.Printf("schema marked as version %d\n", version)
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,67 @@
+// Command openapi-gen generates the OpenAPI 3 document of the HTTP API and the
+// Go and TypeScript client SDKs from the routes registered in SetupRouter.
+//
+// Routes, auth, server permissions and 2FA requirements come from
+// internal/api/router.go; summaries, query parameters and request bodies come
+// from the handler methods (doc comments, c.Query calls, ShouldBindJSON targets).
+//
+// Usage (from the repository root):
+//
+//	go run ./cmd/openapi-gen
+//	go generate ./internal/api
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	root := flag.String("root", ".", "Repository root")
+	flag.Parse()
+
+	index, err := loadPackages(filepath.Join(*root, "internal"))
+	if err != nil {
+		log.Fatalf("failed to parse packages: %v", err)
+	}
+
+	routes, err := parseRouter(index, filepath.Join(*root, "internal", "api", "router.go"))
+	if err != nil {
+		log.Fatalf("failed to parse router: %v", err)
+	}
+
+	spec := buildSpec(index, routes)
+
+	data, err := json.MarshalIndent(spec.document(), "", "  ")
+	if err != nil {
+		log.Fatalf("failed to encode spec: %v", err)
+	}
+	writeFile(filepath.Join(*root, "internal", "api", "openapi.json"), append(data, '\n'))
+
+	goSource, err := format.Source(generateGo(spec))
+	if err != nil {
+		log.Fatalf("generated Go SDK does not compile: %v", err)
+	}
+	writeFile(filepath.Join(*root, "pkg", "sdk", "operations_gen.go"), goSource)
+	writeFile(filepath.Join(*root, "sdk", "typescript", "src", "index.ts"), generateTypeScript(spec))
+
+	fmt.Printf("openapi-gen: %d operations, %d schemas\n", len(spec.operations), len(spec.schemas))
+}
+
+func writeFile(path string, data []byte) {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Fatalf("failed to write %s: %v", path, err)
+	}
+}
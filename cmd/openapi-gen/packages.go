@@ -0,0 +1,97 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// typeDecl is a named type declaration and the package it was declared in
+type typeDecl struct {
+	pkg  string
+	spec *ast.TypeSpec
+}
+
+// packageIndex holds the declarations the generator resolves by name
+type packageIndex struct {
+	fset    *token.FileSet
+	types   map[string]*typeDecl     // "models.Server"
+	consts  map[string]string        // "models.PermServerView" -> "view"
+	methods map[string]*ast.FuncDecl // "Handler.CreateServer" (package api only)
+}
+
+// loadPackages parses every non-test Go file below dir
+func loadPackages(dir string) (*packageIndex, error) {
+	index := &packageIndex{
+		fset:    token.NewFileSet(),
+		types:   make(map[string]*typeDecl),
+		consts:  make(map[string]string),
+		methods: make(map[string]*ast.FuncDecl),
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(index.fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		index.add(file)
+		return nil
+	})
+	return index, err
+}
+
+func (idx *packageIndex) add(file *ast.File) {
+	pkg := file.Name.Name
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					idx.types[pkg+"."+spec.Name.Name] = &typeDecl{pkg: pkg, spec: spec}
+				case *ast.ValueSpec:
+					if decl.Tok != token.CONST {
+						continue
+					}
+					for i, name := range spec.Names {
+						if i >= len(spec.Values) {
+							break
+						}
+						if lit, ok := spec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+							if value, err := strconv.Unquote(lit.Value); err == nil {
+								idx.consts[pkg+"."+name.Name] = value
+							}
+						}
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if pkg != "api" || decl.Recv == nil || len(decl.Recv.List) == 0 {
+				continue
+			}
+			if recv := receiverName(decl.Recv.List[0].Type); recv != "" {
+				idx.methods[recv+"."+decl.Name.Name] = decl
+			}
+		}
+	}
+}
+
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
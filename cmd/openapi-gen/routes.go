@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// route is one handler registration in SetupRouter
+type route struct {
+	Method     string
+	Path       string // gin syntax (/servers/:id)
+	Handler    string // Handler type, e.g. BackupHandler
+	Func       string // Method name, e.g. CreateBackup
+	Auth       bool
+	Permission string // Server permission required by RequireServerPermission
+	TwoFactor  string // Sensitive operation guarded by RequireTwoFactor
+	Comment    string // Trailing line comment in router.go
+}
+
+// routeGroup is a gin router or router group variable in SetupRouter
+type routeGroup struct {
+	prefix string
+	auth   bool
+}
+
+var httpMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true,
+}
+
+// parseRouter walks SetupRouter in source order and collects every route
+func parseRouter(index *packageIndex, path string) ([]route, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var setup *ast.FuncDecl
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "SetupRouter" {
+			setup = fn
+		}
+	}
+	if setup == nil {
+		return nil, fmt.Errorf("SetupRouter not found in %s", path)
+	}
+
+	// Handler variables: SetupRouter parameters and locally constructed handlers
+	handlers := make(map[string]string)
+	for _, param := range setup.Type.Params.List {
+		if name := receiverName(param.Type); strings.HasSuffix(name, "Handler") || strings.HasSuffix(name, "WebSocket") {
+			for _, ident := range param.Names {
+				handlers[ident.Name] = name
+			}
+		}
+	}
+
+	lineComments := make(map[int]string)
+	for _, group := range file.Comments {
+		lineComments[fset.Position(group.Pos()).Line] = strings.TrimSpace(group.Text())
+	}
+
+	groups := map[string]*routeGroup{"router": {}}
+	aliases := make(map[string]string) // perm -> RequireServerPermission
+	var routes []route
+
+	ast.Inspect(setup.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) != 1 || len(stmt.Rhs) != 1 {
+				return true
+			}
+			name := identName(stmt.Lhs[0])
+			switch rhs := stmt.Rhs[0].(type) {
+			case *ast.SelectorExpr:
+				if identName(rhs.X) == "middleware" {
+					aliases[name] = rhs.Sel.Name
+				}
+			case *ast.CallExpr:
+				sel, ok := rhs.Fun.(*ast.SelectorExpr)
+				if ok && sel.Sel.Name == "Group" {
+					parent := groups[identName(sel.X)]
+					if parent == nil || len(rhs.Args) == 0 {
+						return true
+					}
+					group := &routeGroup{prefix: joinPaths(parent.prefix, stringLit(rhs.Args[0])), auth: parent.auth}
+					for _, arg := range rhs.Args[1:] {
+						if middlewareName(arg, aliases) == "AuthMiddleware" {
+							group.auth = true
+						}
+					}
+					groups[name] = group
+				} else if ident, ok := rhs.Fun.(*ast.Ident); ok && strings.HasPrefix(ident.Name, "New") && strings.HasSuffix(ident.Name, "Handler") {
+					handlers[name] = strings.TrimPrefix(ident.Name, "New")
+				}
+			}
+		case *ast.ExprStmt:
+			call, ok := stmt.X.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			group := groups[identName(sel.X)]
+			if group == nil {
+				return true
+			}
+			if sel.Sel.Name == "Use" {
+				for _, arg := range call.Args {
+					if middlewareName(arg, aliases) == "AuthMiddleware" {
+						group.auth = true
+					}
+				}
+				return true
+			}
+			if !httpMethods[sel.Sel.Name] || len(call.Args) < 2 {
+				return true
+			}
+
+			target, ok := call.Args[len(call.Args)-1].(*ast.SelectorExpr)
+			if !ok || handlers[identName(target.X)] == "" {
+				return true // Inline func literals (the HTML index) are not API operations
+			}
+			r := route{
+				Method:  sel.Sel.Name,
+				Path:    joinPaths(group.prefix, stringLit(call.Args[0])),
+				Handler: handlers[identName(target.X)],
+				Func:    target.Sel.Name,
+				Auth:    group.auth,
+				Comment: lineComments[fset.Position(call.End()).Line],
+			}
+			for _, arg := range call.Args[1 : len(call.Args)-1] {
+				mw, ok := arg.(*ast.CallExpr)
+				if !ok {
+					continue
+				}
+				switch middlewareName(mw, aliases) {
+				case "AuthMiddleware":
+					r.Auth = true
+				case "RequireServerPermission":
+					r.Permission = constValue(index, mw.Args)
+				case "RequireTwoFactor":
+					r.TwoFactor = constValue(index, mw.Args)
+				}
+			}
+			routes = append(routes, r)
+		}
+		return true
+	})
+
+	return routes, nil
+}
+
+// middlewareName resolves middleware.X(...) and aliased calls like perm(...) to X
+func middlewareName(expr ast.Expr, aliases map[string]string) string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return aliases[fn.Name]
+	case *ast.SelectorExpr:
+		if identName(fn.X) == "middleware" {
+			return fn.Sel.Name
+		}
+	}
+	return ""
+}
+
+func constValue(index *packageIndex, args []ast.Expr) string {
+	if len(args) == 0 {
+		return ""
+	}
+	sel, ok := args[0].(*ast.SelectorExpr)
+	if !ok {
+		return stringLit(args[0])
+	}
+	if value, ok := index.consts[identName(sel.X)+"."+sel.Sel.Name]; ok {
+		return value
+	}
+	return sel.Sel.Name
+}
+
+func identName(expr ast.Expr) string {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+func stringLit(expr ast.Expr) string {
+	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		if value, err := strconv.Unquote(lit.Value); err == nil {
+			return value
+		}
+	}
+	return ""
+}
+
+func joinPaths(base, rel string) string {
+	if rel == "" {
+		return base
+	}
+	return strings.TrimSuffix(base, "/") + rel
+}
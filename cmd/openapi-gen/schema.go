@@ -0,0 +1,237 @@
+package main
+
+import (
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type schema = map[string]interface{}
+
+// schemaBuilder turns Go type expressions into OpenAPI schemas, registering
+// named struct types as components
+type schemaBuilder struct {
+	index   *packageIndex
+	schemas map[string]schema
+	names   map[string]string // "models.Server" -> component name
+}
+
+func newSchemaBuilder(index *packageIndex) *schemaBuilder {
+	return &schemaBuilder{
+		index:   index,
+		schemas: make(map[string]schema),
+		names:   make(map[string]string),
+	}
+}
+
+// genericTypeNames are qualified with their package to stay meaningful as components
+var genericTypeNames = map[string]bool{"Request": true, "Response": true, "Input": true, "Config": true, "Options": true}
+
+func ref(name string) schema {
+	return schema{"$ref": "#/components/schemas/" + name}
+}
+
+// componentName reserves a unique component name for a qualified Go type
+func (b *schemaBuilder) componentName(qualified, preferred string) string {
+	if name, ok := b.names[qualified]; ok {
+		return name
+	}
+	name := preferred
+	for i := 2; ; i++ {
+		if _, taken := b.schemas[name]; !taken {
+			break
+		}
+		name = preferred + strconv.Itoa(i)
+	}
+	b.names[qualified] = name
+	b.schemas[name] = schema{} // Placeholder so recursive types terminate
+	return name
+}
+
+// named registers a struct component for an inline request type and returns its ref
+func (b *schemaBuilder) named(name string, pkg string, expr ast.Expr) schema {
+	s := b.build(pkg, expr)
+	if _, isRef := s["$ref"]; isRef || s["type"] != "object" || s["properties"] == nil {
+		return s
+	}
+	name = b.componentName("inline."+name, name)
+	b.schemas[name] = s
+	return ref(name)
+}
+
+func (b *schemaBuilder) build(pkg string, expr ast.Expr) schema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if s := basicSchema(t.Name); s != nil {
+			return s
+		}
+		return b.namedType(pkg, t.Name)
+	case *ast.SelectorExpr:
+		qualifier := identName(t.X)
+		switch qualifier + "." + t.Sel.Name {
+		case "time.Time":
+			return schema{"type": "string", "format": "date-time"}
+		case "time.Duration":
+			return schema{"type": "integer", "format": "int64"}
+		case "json.RawMessage":
+			return schema{}
+		case "gin.H":
+			return schema{"type": "object", "additionalProperties": true}
+		}
+		return b.namedType(qualifier, t.Sel.Name)
+	case *ast.StarExpr:
+		s := b.build(pkg, t.X)
+		if _, isRef := s["$ref"]; isRef {
+			return schema{"allOf": []interface{}{s}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	case *ast.ArrayType:
+		if identName(t.Elt) == "byte" {
+			return schema{"type": "string", "format": "byte"}
+		}
+		return schema{"type": "array", "items": b.build(pkg, t.Elt)}
+	case *ast.MapType:
+		return schema{"type": "object", "additionalProperties": b.build(pkg, t.Value)}
+	case *ast.StructType:
+		return b.structSchema(pkg, t)
+	}
+	return schema{}
+}
+
+func (b *schemaBuilder) namedType(pkg, name string) schema {
+	qualified := pkg + "." + name
+	decl, ok := b.index.types[qualified]
+	if !ok {
+		return schema{"type": "object"}
+	}
+	if _, isStruct := decl.spec.Type.(*ast.StructType); !isStruct {
+		return b.build(decl.pkg, decl.spec.Type)
+	}
+	if component, ok := b.names[qualified]; ok {
+		return ref(component)
+	}
+	preferred := upperFirst(name)
+	if genericTypeNames[preferred] {
+		preferred = upperFirst(decl.pkg) + preferred // graphql.Request -> GraphqlRequest
+	}
+	component := b.componentName(qualified, preferred)
+	s := b.build(decl.pkg, decl.spec.Type)
+	if decl.spec.Doc != nil {
+		s["description"] = strings.TrimSpace(decl.spec.Doc.Text())
+	}
+	b.schemas[component] = s
+	return ref(component)
+}
+
+func (b *schemaBuilder) structSchema(pkg string, st *ast.StructType) schema {
+	properties := schema{}
+	var required []string
+
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			if value, err := strconv.Unquote(field.Tag.Value); err == nil {
+				tag = reflect.StructTag(value)
+			}
+		}
+		jsonName, jsonOpts, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" && jsonOpts == "" {
+			continue
+		}
+
+		// Embedded structs without a json name are flattened (encoding/json semantics)
+		if len(field.Names) == 0 && jsonName == "" {
+			embedded := b.build(pkg, field.Type)
+			if r, ok := embedded["$ref"].(string); ok {
+				embedded = b.schemas[strings.TrimPrefix(r, "#/components/schemas/")]
+			}
+			if props, ok := embedded["properties"].(schema); ok {
+				for name, prop := range props {
+					if _, exists := properties[name]; !exists {
+						properties[name] = prop
+					}
+				}
+			}
+			continue
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(receiverName(field.Type))}
+		}
+		for _, ident := range names {
+			if !ast.IsExported(ident.Name) {
+				continue
+			}
+			name := jsonName
+			if name == "" {
+				name = ident.Name
+			}
+			prop := b.build(pkg, field.Type)
+			if field.Comment != nil {
+				prop = withDescription(prop, strings.TrimSpace(field.Comment.Text()))
+			} else if field.Doc != nil {
+				prop = withDescription(prop, strings.TrimSpace(field.Doc.Text()))
+			}
+			if enum := bindingEnum(tag.Get("binding")); enum != nil {
+				prop["enum"] = enum
+			}
+			properties[name] = prop
+			if strings.Contains(","+tag.Get("binding")+",", ",required,") {
+				required = append(required, name)
+			}
+		}
+	}
+
+	s := schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// withDescription attaches a description without mutating a shared $ref
+func withDescription(s schema, description string) schema {
+	if description == "" {
+		return s
+	}
+	if _, isRef := s["$ref"]; isRef {
+		return schema{"allOf": []interface{}{s}, "description": description}
+	}
+	s["description"] = description
+	return s
+}
+
+// bindingEnum extracts the values of a gin binding:"oneof=a b" rule
+func bindingEnum(binding string) []interface{} {
+	for _, rule := range strings.Split(binding, ",") {
+		if values, ok := strings.CutPrefix(rule, "oneof="); ok {
+			var enum []interface{}
+			for _, value := range strings.Fields(values) {
+				enum = append(enum, value)
+			}
+			return enum
+		}
+	}
+	return nil
+}
+
+func basicSchema(name string) schema {
+	switch name {
+	case "string":
+		return schema{"type": "string"}
+	case "bool":
+		return schema{"type": "boolean"}
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32":
+		return schema{"type": "integer"}
+	case "int64", "uint64":
+		return schema{"type": "integer", "format": "int64"}
+	case "float32", "float64":
+		return schema{"type": "number"}
+	case "interface", "any":
+		return schema{}
+	}
+	return nil
+}
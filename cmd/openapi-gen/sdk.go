@@ -0,0 +1,468 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const generatedHeader = "Code generated by openapi-gen from internal/api/router.go. DO NOT EDIT."
+
+var goInitialisms = map[string]bool{
+	"API": true, "CPU": true, "DNS": true, "HTTP": true, "ID": true, "IP": true, "JSON": true, "MB": true,
+	"GB": true, "MOTD": true, "RAM": true, "SSH": true, "SSO": true, "TOTP": true, "TTL": true, "URL": true, "UUID": true,
+}
+
+var goReserved = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true, "defer": true,
+	"else": true, "fallthrough": true, "for": true, "func": true, "go": true, "goto": true, "if": true,
+	"import": true, "interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+	"ctx": true, "query": true, "body": true, "out": true, "c": true, "url": true,
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// goName converts a JSON or path parameter name to an exported Go identifier
+func goName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' || r == ' ' })
+	var b strings.Builder
+	for _, part := range parts {
+		for _, word := range splitWords(upperFirst(part)) {
+			if goInitialisms[strings.ToUpper(word)] {
+				b.WriteString(strings.ToUpper(word))
+			} else {
+				b.WriteString(upperFirst(word))
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return "Field"
+	}
+	return b.String()
+}
+
+func goParamName(name string) string {
+	param := lowerFirst(goName(name))
+	if goReserved[param] {
+		param += "Param"
+	}
+	return param
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func refName(s schema) (string, bool) {
+	if r, ok := s["$ref"].(string); ok {
+		return strings.TrimPrefix(r, "#/components/schemas/"), true
+	}
+	if all, ok := s["allOf"].([]interface{}); ok && len(all) == 1 {
+		if inner, ok := all[0].(schema); ok {
+			return refName(inner)
+		}
+	}
+	return "", false
+}
+
+func isRequired(s schema, name string) bool {
+	required, _ := s["required"].([]string)
+	for _, r := range required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// goType renders a schema as a Go type expression
+func goType(s schema, indent string) string {
+	if name, ok := refName(s); ok {
+		if s["nullable"] == true {
+			return "*" + name
+		}
+		return name
+	}
+	var t string
+	switch s["type"] {
+	case "string":
+		switch s["format"] {
+		case "date-time":
+			t = "time.Time"
+		case "byte":
+			return "[]byte"
+		default:
+			t = "string"
+		}
+	case "integer":
+		t = "int"
+		if s["format"] == "int64" {
+			t = "int64"
+		}
+	case "number":
+		t = "float64"
+	case "boolean":
+		t = "bool"
+	case "array":
+		items, _ := s["items"].(schema)
+		return "[]" + goType(items, indent)
+	case "object":
+		if props, ok := s["properties"].(schema); ok {
+			t = goStruct(s, props, indent)
+		} else if additional, ok := s["additionalProperties"].(schema); ok {
+			return "map[string]" + goType(additional, indent)
+		} else {
+			return "map[string]interface{}"
+		}
+	default:
+		return "interface{}"
+	}
+	if s["nullable"] == true {
+		return "*" + t
+	}
+	return t
+}
+
+func goStruct(s schema, props schema, indent string) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	used := make(map[string]bool)
+	for _, name := range sortedKeys(props) {
+		prop, _ := props[name].(schema)
+		field := goName(name)
+		for i := 2; used[field]; i++ {
+			field = fmt.Sprintf("%s%d", goName(name), i)
+		}
+		used[field] = true
+		tag := name
+		if !isRequired(s, name) {
+			tag += ",omitempty"
+		}
+		if description, ok := prop["description"].(string); ok && description != "" {
+			for _, line := range strings.Split(description, "\n") {
+				fmt.Fprintf(&b, "%s\t// %s\n", indent, line)
+			}
+		}
+		typ := goType(prop, indent+"\t")
+		if typ == "time.Time" && !isRequired(s, name) {
+			typ = "*time.Time" // omitempty never omits a zero time.Time
+		}
+		fmt.Fprintf(&b, "%s\t%s %s `json:\"%s\"`\n", indent, field, typ, tag)
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// goPath renders the request path as a Go string expression with escaped parameters
+func goPath(op *operation) string {
+	var parts []string
+	rest := op.OpenAPIPath
+	for {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest, "}")
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", rest[:start]))
+		}
+		parts = append(parts, "url.PathEscape("+goParamName(rest[start+1:end])+")")
+		rest = rest[end+1:]
+	}
+	if rest != "" {
+		parts = append(parts, fmt.Sprintf("%q", rest))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goBodyType is the Go type of the operation's request body ("" if it takes none)
+func goBodyType(op *operation) string {
+	switch {
+	case len(op.FormFields) > 0:
+		return "*Form"
+	case op.Body != nil:
+		if name, ok := refName(op.Body); ok {
+			return "*" + name
+		}
+		return goType(op.Body, "")
+	case op.BodyExample != nil:
+		return "interface{}"
+	}
+	return ""
+}
+
+func generateGo(spec *apiSpec) []byte {
+	var types, methods bytes.Buffer
+
+	for _, name := range sortedKeys(spec.schemas) {
+		s := spec.schemas[name]
+		if description, ok := s["description"].(string); ok && description != "" {
+			for _, line := range strings.Split(description, "\n") {
+				fmt.Fprintf(&types, "// %s\n", line)
+			}
+		} else {
+			fmt.Fprintf(&types, "// %s is a request type of the API\n", name)
+		}
+		fmt.Fprintf(&types, "type %s %s\n\n", name, goType(s, ""))
+	}
+
+	for _, op := range spec.operations {
+		if op.WebSocket {
+			continue
+		}
+		name := op.Name
+		fmt.Fprintf(&methods, "// %s calls %s %s\n", name, op.Method, op.OpenAPIPath)
+		fmt.Fprintf(&methods, "// %s\n", strings.TrimSuffix(op.Summary, "."))
+		if len(op.QueryParams) > 0 {
+			var names []string
+			for _, q := range op.QueryParams {
+				names = append(names, q.Name)
+			}
+			fmt.Fprintf(&methods, "//\n// Query parameters: %s\n", strings.Join(names, ", "))
+		}
+		if op.Permission != "" {
+			fmt.Fprintf(&methods, "//\n// Requires the %q permission on the server.\n", op.Permission)
+		}
+		if op.TwoFactor != "" {
+			fmt.Fprintf(&methods, "//\n// Sensitive operation %q: pass the second factor with WithTwoFactorCode.\n", op.TwoFactor)
+		}
+
+		args := []string{"ctx context.Context"}
+		for _, param := range op.PathParams {
+			args = append(args, goParamName(param)+" string")
+		}
+		query, body := "nil", "nil"
+		if len(op.QueryParams) > 0 {
+			args = append(args, "query url.Values")
+			query = "query"
+		}
+		if bodyType := goBodyType(op); bodyType != "" {
+			args = append(args, "body "+bodyType)
+			body = "body"
+		}
+		args = append(args, "out interface{}")
+
+		fmt.Fprintf(&methods, "func (c *Client) %s(%s) error {\n", name, strings.Join(args, ", "))
+		fmt.Fprintf(&methods, "\treturn c.do(ctx, %q, %s, %s, %s, out)\n}\n\n", op.Method, goPath(op), query, body)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// %s\n\npackage sdk\n\nimport (\n\t\"context\"\n\t\"net/url\"\n", generatedHeader)
+	if bytes.Contains(types.Bytes(), []byte("time.Time")) {
+		out.WriteString("\t\"time\"\n")
+	}
+	out.WriteString(")\n\n")
+	out.Write(types.Bytes())
+	out.Write(methods.Bytes())
+	return out.Bytes()
+}
+
+// tsType renders a schema as a TypeScript type expression
+func tsType(s schema, indent string) string {
+	var t string
+	if name, ok := refName(s); ok {
+		t = name
+	} else {
+		switch s["type"] {
+		case "string":
+			t = "string"
+		case "integer", "number":
+			t = "number"
+		case "boolean":
+			t = "boolean"
+		case "array":
+			items, _ := s["items"].(schema)
+			t = tsType(items, indent)
+			if strings.ContainsAny(t, " |") {
+				t = "(" + t + ")"
+			}
+			t += "[]"
+		case "object":
+			if props, ok := s["properties"].(schema); ok {
+				t = tsObject(s, props, indent)
+			} else if additional, ok := s["additionalProperties"].(schema); ok {
+				t = "Record<string, " + tsType(additional, indent) + ">"
+			} else {
+				t = "Record<string, unknown>"
+			}
+		default:
+			t = "unknown"
+		}
+	}
+	if s["nullable"] == true {
+		t += " | null"
+	}
+	return t
+}
+
+func tsObject(s schema, props schema, indent string) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range sortedKeys(props) {
+		prop, _ := props[name].(schema)
+		key := name
+		if !tsIdentifier.MatchString(key) {
+			key = fmt.Sprintf("%q", key)
+		}
+		optional := "?"
+		if isRequired(s, name) {
+			optional = ""
+		}
+		if description, ok := prop["description"].(string); ok && description != "" {
+			fmt.Fprintf(&b, "%s  /** %s */\n", indent, strings.ReplaceAll(description, "\n", " "))
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, key, optional, tsType(prop, indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func tsPath(op *operation) string {
+	path := op.OpenAPIPath
+	for _, param := range op.PathParams {
+		path = strings.Replace(path, "{"+param+"}", "${encodeURIComponent("+lowerFirst(goName(param))+")}", 1)
+	}
+	return "`" + path + "`"
+}
+
+func generateTypeScript(spec *apiSpec) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// %s\n\n", generatedHeader)
+	b.WriteString(tsRuntime)
+
+	for _, name := range sortedKeys(spec.schemas) {
+		s := spec.schemas[name]
+		if description, ok := s["description"].(string); ok && description != "" {
+			fmt.Fprintf(&b, "/** %s */\n", strings.ReplaceAll(description, "\n", " "))
+		}
+		fmt.Fprintf(&b, "export type %s = %s;\n\n", name, tsType(s, ""))
+	}
+
+	b.WriteString("export class PayPerPlayClient extends BaseClient {\n")
+	for i, op := range spec.operations {
+		if op.WebSocket {
+			continue
+		}
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "  /**\n   * %s\n   *\n   * %s %s\n", op.Summary, op.Method, op.OpenAPIPath)
+		if op.Permission != "" {
+			fmt.Fprintf(&b, "   * Requires the `%s` permission on the server.\n", op.Permission)
+		}
+		if op.TwoFactor != "" {
+			fmt.Fprintf(&b, "   * Sensitive operation `%s`: pass `twoFactorCode` in the options.\n", op.TwoFactor)
+		}
+		b.WriteString("   */\n")
+
+		var args []string
+		for _, param := range op.PathParams {
+			args = append(args, lowerFirst(goName(param))+": string")
+		}
+		query, body := "undefined", "undefined"
+		if len(op.QueryParams) > 0 {
+			var fields []string
+			for _, q := range op.QueryParams {
+				key := q.Name
+				if !tsIdentifier.MatchString(key) {
+					key = fmt.Sprintf("%q", key)
+				}
+				fields = append(fields, key+"?: QueryValue")
+			}
+			args = append(args, "query?: { "+strings.Join(fields, "; ")+" }")
+			query = "query"
+		}
+		switch {
+		case len(op.FormFields) > 0:
+			args = append(args, "body: FormData")
+			body = "body"
+		case op.Body != nil:
+			args = append(args, "body: "+tsType(op.Body, "  "))
+			body = "body"
+		case op.BodyExample != nil:
+			args = append(args, "body: unknown")
+			body = "body"
+		}
+		args = append(args, "options?: RequestOptions")
+
+		fmt.Fprintf(&b, "  %s<T = unknown>(%s): Promise<T> {\n", op.ID, strings.Join(args, ", "))
+		fmt.Fprintf(&b, "    return this.request<T>(%q, %s, %s, %s, options);\n  }\n", op.Method, tsPath(op), query, body)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+const tsRuntime = `export interface ClientOptions {
+  /** API origin, e.g. https://panel.example.com */
+  baseUrl: string;
+  /** JWT access token (Authorization: Bearer) */
+  token?: string;
+  /** API key (X-API-Key), alternative to token */
+  apiKey?: string;
+  fetch?: typeof fetch;
+}
+
+export interface RequestOptions {
+  /** Second factor for sensitive operations (X-2FA-Code) */
+  twoFactorCode?: string;
+  signal?: AbortSignal;
+}
+
+export type QueryValue = string | number | boolean;
+
+export class APIError extends Error {
+  constructor(public readonly status: number, message: string, public readonly body?: unknown) {
+    super(message);
+    this.name = "APIError";
+  }
+}
+
+export class BaseClient {
+  constructor(protected readonly options: ClientOptions) {}
+
+  protected async request<T>(
+    method: string,
+    path: string,
+    query?: Record<string, QueryValue | undefined>,
+    body?: unknown,
+    options?: RequestOptions,
+  ): Promise<T> {
+    const url = new URL(path, this.options.baseUrl);
+    for (const [key, value] of Object.entries(query ?? {})) {
+      if (value !== undefined) url.searchParams.set(key, String(value));
+    }
+
+    const headers: Record<string, string> = { Accept: "application/json" };
+    if (this.options.token) headers["Authorization"] = "Bearer " + this.options.token;
+    if (this.options.apiKey) headers["X-API-Key"] = this.options.apiKey;
+    if (options?.twoFactorCode) headers["X-2FA-Code"] = options.twoFactorCode;
+
+    let payload: BodyInit | undefined;
+    if (body instanceof FormData) {
+      payload = body;
+    } else if (body !== undefined) {
+      headers["Content-Type"] = "application/json";
+      payload = JSON.stringify(body);
+    }
+
+    const doFetch = this.options.fetch ?? fetch;
+    const res = await doFetch(url.toString(), { method, headers, body: payload, signal: options?.signal });
+    const contentType = res.headers.get("Content-Type") ?? "";
+    const data = contentType.includes("application/json") ? await res.json() : await res.blob();
+    if (!res.ok) {
+      const message = (data as { error?: string })?.error ?? res.statusText;
+      throw new APIError(res.status, message, data);
+    }
+    return data as T;
+  }
+}
+
+`
@@ -0,0 +1,487 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// operation is a route enriched with what the handler reveals about its inputs
+type operation struct {
+	route
+	Name        string // Exported name (SDK methods, request types)
+	ID          string // operationId
+	Tag         string
+	Summary     string
+	Description string
+	OpenAPIPath string
+	PathParams  []string
+	QueryParams []queryParam
+	FormFields  []formField
+	Body        schema // JSON request body, nil if the handler reads none
+	BodyExample interface{}
+	WebSocket   bool
+}
+
+type queryParam struct {
+	Name    string
+	Default string
+}
+
+type formField struct {
+	Name string
+	File bool
+}
+
+type apiSpec struct {
+	operations []*operation
+	schemas    map[string]schema
+}
+
+// routeLine matches doc comment lines that restate the route ("X handles GET /api/...")
+var routeLine = regexp.MustCompile(`^(?:\w+ handles )?(?:GET|POST|PUT|PATCH|DELETE|HEAD)\s+/\S*\s*(.*)$`)
+
+var pathParam = regexp.MustCompile(`[:*]([A-Za-z_]+)`)
+
+func buildSpec(index *packageIndex, routes []route) *apiSpec {
+	builder := newSchemaBuilder(index)
+	spec := &apiSpec{}
+
+	funcCount := make(map[string]int)
+	routeCount := make(map[string]int)
+	for _, r := range routes {
+		funcCount[r.Func]++
+		routeCount[r.Handler+"."+r.Func]++
+	}
+
+	for _, r := range routes {
+		op := &operation{
+			route:       r,
+			Tag:         tagName(r.Handler),
+			OpenAPIPath: pathParam.ReplaceAllString(r.Path, "{$1}"),
+		}
+		for _, match := range pathParam.FindAllStringSubmatch(r.Path, -1) {
+			op.PathParams = append(op.PathParams, match[1])
+		}
+
+		op.Name = r.Func
+		if funcCount[r.Func] > 1 {
+			op.Name = strings.ReplaceAll(op.Tag, " ", "") + r.Func
+		}
+		if routeCount[r.Handler+"."+r.Func] > 1 {
+			op.Name += upperFirst(strings.ToLower(r.Method))
+		}
+		op.ID = lowerFirst(op.Name)
+
+		if decl := index.methods[r.Handler+"."+r.Func]; decl != nil {
+			analyzeHandler(builder, op, decl)
+		}
+		if op.Summary == "" {
+			op.Summary = humanize(r.Func)
+		}
+		spec.operations = append(spec.operations, op)
+	}
+
+	spec.schemas = builder.schemas
+	return spec
+}
+
+// analyzeHandler reads the handler's doc comment and the gin.Context calls in its body
+func analyzeHandler(builder *schemaBuilder, op *operation, decl *ast.FuncDecl) {
+	var description []string
+	var summary, routeNote string
+	if decl.Doc != nil {
+		for _, line := range strings.Split(strings.TrimSpace(decl.Doc.Text()), "\n") {
+			line = strings.TrimSpace(line)
+			if body, ok := strings.CutPrefix(line, "Body:"); ok {
+				var note string
+				op.BodyExample, note = parseExample(strings.TrimSpace(body))
+				if note != "" {
+					description = append(description, note)
+				}
+				continue
+			} else if m := routeLine.FindStringSubmatch(line); m != nil {
+				if routeNote == "" {
+					routeNote = strings.Trim(m[1], "() ")
+				}
+				continue
+			}
+			if summary == "" {
+				line = strings.TrimPrefix(line, decl.Name.Name+" ")
+				summary = upperFirst(strings.TrimPrefix(line, "handles "))
+				continue
+			}
+			description = append(description, line)
+		}
+	}
+
+	// Prefer the handler's own description; the router line comment fills in for bare "X handles GET ..." docs
+	switch {
+	case summary != "":
+		op.Summary = summary
+		if op.Comment != "" {
+			description = append([]string{upperFirst(op.Comment)}, description...)
+		}
+	case op.Comment != "":
+		op.Summary = upperFirst(op.Comment)
+	case routeNote != "":
+		op.Summary = upperFirst(routeNote)
+	}
+	op.Description = strings.Join(description, "\n")
+
+	ctx := contextParam(decl)
+	if ctx == "" || decl.Body == nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if fn, ok := call.Fun.(*ast.Ident); ok && fn.Name == "parsePageRequest" {
+			// List helper: cursor/limit/sort plus the filters named in its trailing arguments
+			names := []string{"cursor", "limit", "sort"}
+			for _, arg := range call.Args[min(2, len(call.Args)):] {
+				names = append(names, stringLit(arg))
+			}
+			for _, name := range names {
+				if name != "" && !seen["q:"+name] {
+					seen["q:"+name] = true
+					op.QueryParams = append(op.QueryParams, queryParam{Name: name})
+				}
+			}
+			return true
+		}
+		if fn, ok := call.Fun.(*ast.Ident); ok && fn.Name == "parseAuditTime" && len(call.Args) > 1 {
+			// Time helper: the time range parameter named in its second argument
+			if name := stringLit(call.Args[1]); name != "" && !seen["q:"+name] {
+				seen["q:"+name] = true
+				op.QueryParams = append(op.QueryParams, queryParam{Name: name})
+			}
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if sel.Sel.Name == "Upgrade" && len(call.Args) > 0 {
+			if w, ok := call.Args[0].(*ast.SelectorExpr); ok && identName(w.X) == ctx && w.Sel.Name == "Writer" {
+				op.WebSocket = true
+			}
+			return true
+		}
+		receiver := sel.X
+		if req, ok := receiver.(*ast.SelectorExpr); ok && req.Sel.Name == "Request" {
+			receiver = req.X // c.Request.FormFile
+		}
+		if identName(receiver) != ctx {
+			return true
+		}
+
+		switch sel.Sel.Name {
+		case "Query", "DefaultQuery", "GetQuery", "QueryArray":
+			name := stringLit(firstArg(call))
+			if name == "" || seen["q:"+name] {
+				return true
+			}
+			seen["q:"+name] = true
+			param := queryParam{Name: name}
+			if sel.Sel.Name == "DefaultQuery" && len(call.Args) > 1 {
+				param.Default = stringLit(call.Args[1])
+			}
+			op.QueryParams = append(op.QueryParams, param)
+		case "PostForm", "DefaultPostForm", "FormFile":
+			name := stringLit(firstArg(call))
+			if name == "" || seen["f:"+name] {
+				return true
+			}
+			seen["f:"+name] = true
+			op.FormFields = append(op.FormFields, formField{Name: name, File: sel.Sel.Name == "FormFile"})
+		case "ShouldBindJSON", "BindJSON", "ShouldBind":
+			unary, ok := firstArg(call).(*ast.UnaryExpr)
+			if !ok {
+				return true
+			}
+			if typ := localVarType(decl.Body, identName(unary.X)); typ != nil {
+				op.Body = builder.named(op.Name+"Request", "api", typ)
+			}
+		}
+		return true
+	})
+
+	// Token-authenticated WebSockets pass the JWT as a query parameter (browsers cannot set headers)
+	if op.WebSocket && op.Auth && !seen["q:token"] {
+		op.QueryParams = append(op.QueryParams, queryParam{Name: "token"})
+	}
+}
+
+// parseExample splits a "Body:" comment into its JSON example and trailing note
+func parseExample(text string) (interface{}, string) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	var example interface{}
+	if err := decoder.Decode(&example); err != nil {
+		return nil, text
+	}
+	rest := strings.TrimSpace(text[decoder.InputOffset():])
+	return example, upperFirst(strings.Trim(rest, "() "))
+}
+
+// contextParam returns the name of the handler's *gin.Context parameter
+func contextParam(decl *ast.FuncDecl) string {
+	for _, param := range decl.Type.Params.List {
+		star, ok := param.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		if sel, ok := star.X.(*ast.SelectorExpr); ok && identName(sel.X) == "gin" && sel.Sel.Name == "Context" && len(param.Names) > 0 {
+			return param.Names[0].Name
+		}
+	}
+	return ""
+}
+
+// localVarType finds the declared type of a local variable (var x T or x := T{})
+func localVarType(body *ast.BlockStmt, name string) ast.Expr {
+	var found ast.Expr
+	ast.Inspect(body, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		switch stmt := n.(type) {
+		case *ast.ValueSpec:
+			for _, ident := range stmt.Names {
+				if ident.Name == name && stmt.Type != nil {
+					found = stmt.Type
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range stmt.Lhs {
+				if identName(lhs) != name || i >= len(stmt.Rhs) {
+					continue
+				}
+				if lit, ok := stmt.Rhs[i].(*ast.CompositeLit); ok && lit.Type != nil {
+					found = lit.Type
+				}
+			}
+		}
+		return true
+	})
+	return found
+}
+
+func firstArg(call *ast.CallExpr) ast.Expr {
+	if len(call.Args) == 0 {
+		return nil
+	}
+	return call.Args[0]
+}
+
+// document renders the OpenAPI 3 document
+func (s *apiSpec) document() schema {
+	paths := schema{}
+	tagSet := make(map[string]bool)
+
+	for _, op := range s.operations {
+		tagSet[op.Tag] = true
+		item, ok := paths[op.OpenAPIPath].(schema)
+		if !ok {
+			item = schema{}
+			paths[op.OpenAPIPath] = item
+		}
+
+		var params []interface{}
+		for _, name := range op.PathParams {
+			params = append(params, schema{"name": name, "in": "path", "required": true, "schema": schema{"type": "string"}})
+		}
+		for _, q := range op.QueryParams {
+			p := schema{"type": "string"}
+			if q.Default != "" {
+				p["default"] = q.Default
+			}
+			params = append(params, schema{"name": q.Name, "in": "query", "schema": p})
+		}
+		if op.TwoFactor != "" {
+			params = append(params, schema{
+				"name": "X-2FA-Code", "in": "header",
+				"description": "TOTP or recovery code, required when the user has two-factor authentication enabled",
+				"schema":      schema{"type": "string"},
+			})
+		}
+
+		entry := schema{
+			"operationId": op.ID,
+			"summary":     op.Summary,
+			"tags":        []string{op.Tag},
+			"responses": schema{
+				"200":     schema{"description": "Success", "content": schema{"application/json": schema{"schema": schema{}}}},
+				"default": schema{"description": "Error", "content": schema{"application/json": schema{"schema": ref("Error")}}},
+			},
+		}
+		description := op.Description
+		if op.Permission != "" {
+			description = joinLines(description, fmt.Sprintf("Requires the `%s` permission on the server.", op.Permission))
+			entry["x-server-permission"] = op.Permission
+		}
+		if op.TwoFactor != "" {
+			description = joinLines(description, fmt.Sprintf("Sensitive operation `%s`: send the second factor in X-2FA-Code.", op.TwoFactor))
+			entry["x-two-factor"] = op.TwoFactor
+		}
+		if op.WebSocket {
+			description = joinLines(description, "WebSocket endpoint: connect with an Upgrade request.")
+			entry["x-websocket"] = true
+		}
+		if description != "" {
+			entry["description"] = description
+		}
+		if len(params) > 0 {
+			entry["parameters"] = params
+		}
+		if op.Auth {
+			entry["security"] = []interface{}{schema{"bearerAuth": []string{}}, schema{"apiKeyAuth": []string{}}}
+		}
+		if body := op.requestBody(); body != nil {
+			entry["requestBody"] = body
+		}
+		item[strings.ToLower(op.Method)] = entry
+	}
+
+	tagNames := make([]string, 0, len(tagSet))
+	for tag := range tagSet {
+		tagNames = append(tagNames, tag)
+	}
+	sort.Strings(tagNames)
+	tags := make([]interface{}, 0, len(tagNames))
+	for _, tag := range tagNames {
+		tags = append(tags, schema{"name": tag})
+	}
+
+	schemas := schema{
+		"Error": schema{
+			"type":       "object",
+			"properties": schema{"error": schema{"type": "string"}},
+		},
+	}
+	for name, sc := range s.schemas {
+		schemas[name] = sc
+	}
+
+	return schema{
+		"openapi": "3.0.3",
+		"info": schema{
+			"title":       "PayPerPlay Hosting API",
+			"version":     "1.0.0",
+			"description": "Generated from internal/api/router.go by cmd/openapi-gen. Authenticate with a JWT (Authorization: Bearer) or an API key (X-API-Key).",
+		},
+		"servers": []interface{}{schema{"url": "/"}},
+		"tags":    tags,
+		"paths":   paths,
+		"components": schema{
+			"schemas": schemas,
+			"securitySchemes": schema{
+				"bearerAuth": schema{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKeyAuth": schema{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+	}
+}
+
+func (op *operation) requestBody() schema {
+	if len(op.FormFields) > 0 {
+		properties := schema{}
+		for _, field := range op.FormFields {
+			if field.File {
+				properties[field.Name] = schema{"type": "string", "format": "binary"}
+			} else {
+				properties[field.Name] = schema{"type": "string"}
+			}
+		}
+		return schema{"content": schema{"multipart/form-data": schema{"schema": schema{"type": "object", "properties": properties}}}}
+	}
+	if op.Body == nil && op.BodyExample == nil {
+		return nil
+	}
+	media := schema{"schema": op.Body}
+	if op.Body == nil {
+		media["schema"] = schema{"type": "object"}
+	}
+	if op.BodyExample != nil {
+		media["example"] = op.BodyExample
+	}
+	return schema{"required": op.Body != nil, "content": schema{"application/json": media}}
+}
+
+// tagName groups operations by handler: BackupScheduleHandler -> "Backup Schedule"
+func tagName(handler string) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(handler, "Handler"), "WebSocket")
+	if name == "" {
+		name = "Server"
+	}
+	return strings.Join(splitWords(name), " ")
+}
+
+// humanize turns a method name into a summary: GetServerUsage -> "Get server usage"
+func humanize(name string) string {
+	words := splitWords(name)
+	for i := 1; i < len(words); i++ {
+		if strings.ToUpper(words[i]) != words[i] {
+			words[i] = strings.ToLower(words[i])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// splitWords splits a Go identifier at case changes, keeping initialisms together (GetMOTD -> Get, MOTD)
+func splitWords(name string) []string {
+	var words []string
+	start := 0
+	runes := []rune(name)
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+func joinLines(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "\n\n" + b
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	i := 0
+	for i < len(r) && unicode.IsUpper(r[i]) {
+		i++
+	}
+	switch {
+	case i == 0:
+	case i == 1 || i == len(r):
+		for j := 0; j < i; j++ {
+			r[j] = unicode.ToLower(r[j])
+		}
+	default: // Keep the start of the next word: MOTDHandler -> motdHandler
+		for j := 0; j < i-1; j++ {
+			r[j] = unicode.ToLower(r[j])
+		}
+	}
+	return string(r)
+}
@@ -1,5 +1,5 @@
 import { useEffect, useRef, useCallback } from 'react';
-import type { DashboardEvent } from '../types/events';
+import type { DashboardEvent, StreamPositionEvent } from '../types/events';
 
 interface UseWebSocketOptions {
   url: string;
@@ -25,6 +25,9 @@ export const useWebSocket = ({
   const reconnectTimeout = useRef<number | undefined>(undefined);
   const shouldReconnect = useRef(true);
   const mounted = useRef(true);
+  // Stream position for resuming after a reconnect (the server replays the events we missed)
+  const streamId = useRef<string | null>(null);
+  const lastSeq = useRef(0);
 
   const connect = useCallback(() => {
     if (!mounted.current || !shouldReconnect.current) {
@@ -32,8 +35,14 @@ export const useWebSocket = ({
     }
 
     try {
-      console.log('[WebSocket] Connecting to', url);
-      ws.current = new WebSocket(url);
+      let connectUrl = url;
+      if (streamId.current) {
+        const separator = url.includes('?') ? '&' : '?';
+        connectUrl = `${url}${separator}stream=${encodeURIComponent(streamId.current)}&since=${lastSeq.current}`;
+      }
+
+      console.log('[WebSocket] Connecting to', connectUrl);
+      ws.current = new WebSocket(connectUrl);
 
       ws.current.onopen = () => {
         console.log('[WebSocket] Connected');
@@ -45,6 +54,24 @@ export const useWebSocket = ({
         try {
           const data: DashboardEvent = JSON.parse(event.data);
           console.log('[WebSocket] Message received:', data.type);
+
+          if (data.type === 'stream.hello' || data.type === 'stream.resumed') {
+            const position = data.data as StreamPositionEvent;
+            streamId.current = position.stream_id;
+            if (data.type === 'stream.hello') {
+              lastSeq.current = position.seq; // Fresh state follows, live events continue after seq
+            } else {
+              console.log(`[WebSocket] Resumed stream, replaying ${position.replayed} missed events`);
+            }
+            return;
+          }
+
+          if (data.seq !== undefined) {
+            if (data.seq <= lastSeq.current) {
+              return; // Already applied
+            }
+            lastSeq.current = data.seq;
+          }
           onMessage(data);
         } catch (error) {
           console.error('[WebSocket] Failed to parse message:', error);
@@ -1,11 +1,20 @@
 // Dashboard Event Types matching backend events
 
 export interface DashboardEvent {
+  seq?: number; // Stream sequence number (absent for snapshots like stats.fleet)
   type: string;
   timestamp: string;
   data: any;
 }
 
+// Stream control messages (stream.hello on a fresh connection, stream.resumed after a replay)
+export interface StreamPositionEvent {
+  stream_id: string;
+  seq: number;
+  from?: number;
+  replayed?: number;
+}
+
 // Node Events
 export interface NodeCreatedEvent {
   node_id: string;
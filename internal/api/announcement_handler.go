@@ -0,0 +1,188 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// AnnouncementHandler broadcasts chat announcements to running servers (admin only)
+type AnnouncementHandler struct {
+	announcements *service.AnnouncementService
+	audit         *service.AuditService
+}
+
+// NewAnnouncementHandler creates a new announcement handler
+func NewAnnouncementHandler(announcements *service.AnnouncementService, auditService *service.AuditService) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		announcements: announcements,
+		audit:         auditService,
+	}
+}
+
+// announcementRequest is the body of a new announcement or a preview
+type announcementRequest struct {
+	Message       string                    `json:"message" binding:"required"`
+	Filter        models.AnnouncementFilter `json:"filter"`
+	MaintenanceAt *time.Time                `json:"maintenance_at"`
+	SendAt        *time.Time                `json:"send_at"`
+}
+
+func (r announcementRequest) announcement(userID string) *models.Announcement {
+	announcement := &models.Announcement{
+		Message:       r.Message,
+		Filter:        r.Filter,
+		MaintenanceAt: r.MaintenanceAt,
+		CreatedBy:     userID,
+	}
+	if r.SendAt != nil {
+		announcement.SendAt = *r.SendAt
+	}
+	return announcement
+}
+
+// CreateAnnouncement sends an announcement now or schedules it (admin only)
+// The message is a template with {{.ServerName}}, {{.ServerID}}, {{.MaintenanceAt}} and {{.MinutesLeft}}.
+// POST /api/admin/announcements
+// Body: {"message": "Maintenance in {{.MinutesLeft}} minutes", "maintenance_at": "2025-01-31T22:00:00Z",
+// "send_at": "2025-01-31T21:45:00Z", "filter": {"node_ids": ["node-1"], "plans": ["payperplay"]}}
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request announcementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	announcement := request.announcement(c.GetString("user_id"))
+	if err := h.announcements.Create(announcement); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionAnnouncement, "announcement", strconv.FormatUint(uint64(announcement.ID), 10), nil, announcement))
+
+	c.JSON(http.StatusCreated, gin.H{"announcement": announcement})
+}
+
+// PreviewAnnouncement renders an announcement for the first targeted server and counts the targets (admin only)
+// POST /api/admin/announcements/preview
+func (h *AnnouncementHandler) PreviewAnnouncement(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request announcementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	targets, err := h.announcements.Targets(request.Filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load running servers"})
+		return
+	}
+	sample := &models.MinecraftServer{ID: "example", Name: "Example"}
+	if len(targets) > 0 {
+		sample = &targets[0]
+	}
+	message, err := h.announcements.Preview(request.announcement(c.GetString("user_id")), sample)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   message,
+		"server_id": sample.ID,
+		"targets":   len(targets), // Running now; the filter is applied again when sending
+	})
+}
+
+// ListAnnouncements returns the recent announcements (admin only)
+// GET /api/admin/announcements?limit=100
+func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	announcements, err := h.announcements.List(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list announcements"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"announcements": announcements, "count": len(announcements)})
+}
+
+// GetAnnouncement returns an announcement with its per-server delivery report (admin only)
+// GET /api/admin/announcements/:announcement_id
+func (h *AnnouncementHandler) GetAnnouncement(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("announcement_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid announcement ID"})
+		return
+	}
+	announcement, err := h.announcements.Get(uint(id))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"announcement": announcement})
+}
+
+// CancelAnnouncement cancels a scheduled announcement (admin only)
+// DELETE /api/admin/announcements/:announcement_id
+func (h *AnnouncementHandler) CancelAnnouncement(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("announcement_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid announcement ID"})
+		return
+	}
+	announcement, err := h.announcements.Cancel(uint(id))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionAnnouncement, "announcement", c.Param("announcement_id"),
+		gin.H{"status": models.AnnouncementScheduled}, gin.H{"status": announcement.Status}))
+
+	c.JSON(http.StatusOK, gin.H{"announcement": announcement})
+}
+
+func (h *AnnouncementHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAnnouncementInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAnnouncementNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAnnouncementNotScheduled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Announcement request failed", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Announcement request failed"})
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// APIKeyHandler handles personal and organization API keys
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// createAPIKeyRequest is the body for creating personal and organization keys
+type createAPIKeyRequest struct {
+	Name           string               `json:"name" binding:"required,max=100"`
+	Scopes         []models.APIKeyScope `json:"scopes" binding:"required,min=1"`
+	RateLimit      int                  `json:"rate_limit" binding:"min=0"`       // Requests per minute, 0 = default
+	ExpiresInHours int                  `json:"expires_in_hours" binding:"min=0"` // 0 = key doesn't expire
+}
+
+// ListKeys returns the personal API keys of the current user
+// GET /api/api-keys
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	h.listKeys(c, "")
+}
+
+// CreateKey creates a personal API key
+// POST /api/api-keys
+// Body: {"name": "CI deploy", "scopes": ["servers:read", "servers:start"], "rate_limit": 60, "expires_in_hours": 720}
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	h.createKey(c, "")
+}
+
+// ListOrganizationKeys returns the API keys of an organization (admins only)
+// GET /api/organizations/:org_id/api-keys
+func (h *APIKeyHandler) ListOrganizationKeys(c *gin.Context) {
+	h.listKeys(c, c.Param("org_id"))
+}
+
+// CreateOrganizationKey creates an API key that only reaches the organization's servers (admins only)
+// POST /api/organizations/:org_id/api-keys
+// Body: same as POST /api/api-keys
+func (h *APIKeyHandler) CreateOrganizationKey(c *gin.Context) {
+	h.createKey(c, c.Param("org_id"))
+}
+
+// RevokeKey revokes a personal key or, for organization admins, an organization key
+// DELETE /api/api-keys/:key_id
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	if err := h.apiKeyService.RevokeKey(c.GetString("user_id"), c.Param("key_id")); err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+func (h *APIKeyHandler) listKeys(c *gin.Context, orgID string) {
+	keys, err := h.apiKeyService.ListKeys(c.GetString("user_id"), orgID)
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys": keys,
+		"count":    len(keys),
+	})
+}
+
+func (h *APIKeyHandler) createKey(c *gin.Context, orgID string) {
+	var request createAPIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	key, rawKey, err := h.apiKeyService.CreateKey(c.GetString("user_id"), orgID, request.Name, request.Scopes,
+		request.RateLimit, time.Duration(request.ExpiresInHours)*time.Hour)
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"api_key": key,
+		"key":     rawKey, // Only returned once
+	})
+}
+
+// respondAPIKeyError maps API key and organization errors to HTTP status codes
+func respondAPIKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrAPIKeyNotFound), errors.Is(err, models.ErrOrgNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrOrgForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrAPIKeyInvalidScope), errors.Is(err, models.ErrAPIKeyInvalidRateLimit):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrAPIKeyLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/middleware"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// maxManifestBytes limits the size of a manifest body
+const maxManifestBytes = 1 << 20
+
+// ApplyHandler handles declarative server management: the desired servers of the account are sent
+// as one manifest and reconciled, so they can be managed from Git (CI pipelines, a Terraform provider)
+type ApplyHandler struct {
+	apply *service.ApplyService
+	audit *service.AuditService
+}
+
+// NewApplyHandler creates a new apply handler
+func NewApplyHandler(apply *service.ApplyService, auditService *service.AuditService) *ApplyHandler {
+	return &ApplyHandler{
+		apply: apply,
+		audit: auditService,
+	}
+}
+
+// Apply reconciles the user's servers with a manifest (JSON, or YAML with a YAML Content-Type)
+// Servers are matched by name; ?dry_run=true only returns the plan. Pruning deletes managed servers
+// missing from the manifest and needs the 2FA code like DELETE /api/servers/:id.
+// POST /api/apply
+// Body: {"servers": [{"name": "lobby", "server_type": "paper", "minecraft_version": "1.21", "ram_mb": 4096, "config": {"max_players": 50}, "plugins": ["luckperms"]}], "prune": false}
+func (h *ApplyHandler) Apply(c *gin.Context) {
+	manifest, err := decodeManifest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, server := range manifest.Servers {
+		if !serverNameRegex.MatchString(server.Name) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s: %s", server.Name, errInvalidServerName)})
+			return
+		}
+	}
+
+	userID := c.GetString("user_id")
+	plan, err := h.apply.Plan(userID, manifest)
+	if err != nil {
+		respondApplyError(c, err)
+		return
+	}
+	if c.Query("dry_run") == "true" {
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+	if plan.HasDeletes() && !middleware.CheckTwoFactor(c, models.SensitiveServerDelete) {
+		return
+	}
+	if !middleware.AllowRequest(c, middleware.ExpensiveRateLimiter) {
+		return
+	}
+
+	result, err := h.apply.Apply(userID, manifest)
+	if err != nil {
+		respondApplyError(c, err)
+		return
+	}
+
+	actions := map[service.ApplyAction]int{}
+	for _, change := range result.Changes {
+		actions[change.Action]++
+	}
+	entry := auditEntry(c, audit.ActionManifestApply, "user", userID, nil, gin.H{
+		"servers": len(manifest.Servers),
+		"prune":   manifest.Prune,
+		"changes": actions,
+		"failed":  result.Failed,
+	})
+	entry.Result = "success"
+	if result.Failed > 0 {
+		entry.Result, entry.Error = "failed", fmt.Sprintf("%d of %d changes failed", result.Failed, len(result.Changes))
+	}
+	h.audit.Record(entry)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListManagedServers returns the servers managed by the user's manifest (name and server ID)
+// GET /api/apply/servers
+func (h *ApplyHandler) ListManagedServers(c *gin.Context) {
+	declarations, err := h.apply.ListDeclarations(c.GetString("user_id"))
+	if err != nil {
+		respondApplyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"servers": declarations,
+		"count":   len(declarations),
+	})
+}
+
+// decodeManifest parses the manifest body as YAML or JSON (by Content-Type); unknown fields are rejected
+func decodeManifest(c *gin.Context) (*service.ServerManifest, error) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxManifestBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if len(body) > maxManifestBytes {
+		return nil, fmt.Errorf("manifest is larger than %d bytes", maxManifestBytes)
+	}
+
+	switch c.ContentType() {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		// Through JSON, so both formats share the field names and checks
+		var document interface{}
+		if err := yaml.Unmarshal(body, &document); err != nil {
+			return nil, fmt.Errorf("invalid YAML manifest: %w", err)
+		}
+		if body, err = json.Marshal(document); err != nil {
+			return nil, fmt.Errorf("invalid YAML manifest: %w", err)
+		}
+	}
+
+	var manifest service.ServerManifest
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// respondApplyError maps apply errors to HTTP status codes
+func respondApplyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrManifestInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrApplyInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logger.Error("APPLY-API: Request failed", err, map[string]interface{}{
+			"user_id": c.GetString("user_id"),
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply manifest"})
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+func newManifestTestContext(contentType, body string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/api/apply", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", contentType)
+	return c
+}
+
+func TestDecodeManifest(t *testing.T) {
+	want := &service.ServerManifest{
+		Servers: []service.DeclaredServer{{
+			Name:             "lobby",
+			ServerType:       "paper",
+			MinecraftVersion: "1.21",
+			RAMMb:            4096,
+			Config:           map[string]interface{}{"max_players": float64(50), "pvp": false},
+			Plugins:          []string{"luckperms"},
+		}},
+		Prune: true,
+	}
+
+	yamlBody := `
+prune: true
+servers:
+  - name: lobby
+    server_type: paper
+    minecraft_version: "1.21"
+    ram_mb: 4096
+    config:
+      max_players: 50
+      pvp: false
+    plugins: [luckperms]
+`
+	jsonBody := `{"prune": true, "servers": [{"name": "lobby", "server_type": "paper", "minecraft_version": "1.21",
+		"ram_mb": 4096, "config": {"max_players": 50, "pvp": false}, "plugins": ["luckperms"]}]}`
+
+	for contentType, body := range map[string]string{"application/yaml": yamlBody, "application/json; charset=utf-8": jsonBody} {
+		got, err := decodeManifest(newManifestTestContext(contentType, body))
+		if err != nil {
+			t.Fatalf("%s: decodeManifest() error = %v", contentType, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: decodeManifest() = %+v, want %+v", contentType, got, want)
+		}
+	}
+
+	// Typos are rejected instead of silently ignored
+	for contentType, body := range map[string]string{
+		"text/yaml":        "servers:\n  - name: lobby\n    ram: 4096\n",
+		"application/json": `{"server": []}`,
+	} {
+		if _, err := decodeManifest(newManifestTestContext(contentType, body)); err == nil {
+			t.Errorf("%s: decodeManifest(%q) accepted an unknown field", contentType, body)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// ArchiveHandler shows the archive of an archived server and restores parts of it
+type ArchiveHandler struct {
+	archiveService *service.ArchiveService
+}
+
+// NewArchiveHandler creates a new archive handler
+func NewArchiveHandler(archiveService *service.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{archiveService: archiveService}
+}
+
+// GetArchiveManifest returns the manifest of an archived server with the size of each part
+// Query: files=true adds the file list (path, size, SHA-256)
+// GET /api/servers/:id/archive
+func (h *ArchiveHandler) GetArchiveManifest(c *gin.Context) {
+	summary, err := h.archiveService.GetArchiveManifest(c.Param("id"), c.Query("files") == "true")
+	if err != nil {
+		respondArchiveError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// restoreArchivePartsRequest selects the parts of a selective restore
+type restoreArchivePartsRequest struct {
+	Parts []string `json:"parts" binding:"required"` // world, plugins, config
+}
+
+// RestoreArchiveParts restores only the selected parts of an archived server. Answers 202 with
+// the operation; the server is stopped once it completed and the rest of the archive is dropped.
+// POST /api/servers/:id/archive/restore
+func (h *ArchiveHandler) RestoreArchiveParts(c *gin.Context) {
+	var req restoreArchivePartsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	op, err := h.archiveService.RestoreArchiveParts(c.Param("id"), c.GetString("user_id"), req.Parts)
+	if err != nil {
+		respondArchiveError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"operation": op,
+	})
+}
+
+// respondArchiveError maps archive manifest and selective restore errors to HTTP status codes
+func respondArchiveError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrArchivePartsMissing), errors.Is(err, service.ErrArchivePartInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrArchiveManifestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrServerNotArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		respondOperationError(c, err)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// AuditHandler serves the persisted audit log to admins
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAudit lists audit events, newest first (admin only)
+// GET /api/admin/audit
+// Supports ?cursor, ?limit, ?sort, the user/action/resource_type/resource_id/result filters and
+// ?from / ?to (RFC 3339 or YYYY-MM-DD, "to" exclusive).
+func (h *AuditHandler) ListAudit(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	page, err := parsePageRequest(c, 50, "user", "action", "resource_type", "resource_id", "result")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, ok := parseAuditTime(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseAuditTime(c, "to")
+	if !ok {
+		return
+	}
+
+	events, err := h.auditService.List(from, to, page)
+	if err != nil {
+		logger.Error("AUDIT-API: Failed to list audit events", err, nil)
+		respondPageError(c, err)
+		return
+	}
+
+	setPageHeaders(c, events.Total, events.NextCursor)
+	c.JSON(http.StatusOK, gin.H{
+		"events":      events.Items,
+		"count":       len(events.Items),
+		"total":       events.Total,
+		"next_cursor": events.NextCursor,
+	})
+}
+
+// parseAuditTime reads a time range bound (zero if absent); responds 400 and returns false if it is invalid
+func parseAuditTime(c *gin.Context, param string) (time.Time, bool) {
+	value := c.Query(param)
+	if value == "" {
+		return time.Time{}, true
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, true
+	}
+	if parsed, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return parsed, true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid '" + param + "', expected RFC 3339 time or YYYY-MM-DD"})
+	return time.Time{}, false
+}
+
+// auditEntry starts the audit entry of an action of the requesting user
+func auditEntry(c *gin.Context, action audit.ActionType, resourceType, resourceID string, before, after interface{}) audit.AuditEntry {
+	return audit.AuditEntry{
+		Action:       action,
+		ActorID:      c.GetString("user_id"),
+		ActorIP:      c.ClientIP(),
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Before:       before,
+		After:        after,
+		DecisionBy:   "manual",
+	}
+}
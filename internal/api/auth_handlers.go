@@ -88,6 +88,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	token, user, isNewDevice, err := h.authService.Login(req.Email, req.Password, userAgent, ipAddress)
 	if err != nil {
+		if errors.Is(err, models.ErrTwoFactorRequired) {
+			respondTwoFactorChallenge(c, token)
+			return
+		}
 		if errors.Is(err, models.ErrInvalidCredentials) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 			return
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/compression"
 	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/repository"
 	"github.com/payperplay/hosting/internal/service"
@@ -15,6 +17,7 @@ type BackupHandler struct {
 	backupRepo         *repository.BackupRepository
 	backupQuotaService *service.BackupQuotaService
 	serverRepo         *repository.ServerRepository
+	permissionService  *service.PermissionService
 }
 
 func NewBackupHandler(
@@ -22,12 +25,14 @@ func NewBackupHandler(
 	backupRepo *repository.BackupRepository,
 	backupQuotaService *service.BackupQuotaService,
 	serverRepo *repository.ServerRepository,
+	permissionService *service.PermissionService,
 ) *BackupHandler {
 	return &BackupHandler{
 		backupService:      backupService,
 		backupRepo:         backupRepo,
 		backupQuotaService: backupQuotaService,
 		serverRepo:         serverRepo,
+		permissionService:  permissionService,
 	}
 }
 
@@ -36,6 +41,10 @@ type CreateBackupRequest struct {
 	Type          models.BackupType `json:"type" binding:"required"`
 	Description   string            `json:"description"`
 	RetentionDays int               `json:"retention_days"` // 0 = use default based on type
+
+	// Optional codec override (defaults to BACKUP_COMPRESSION / BACKUP_COMPRESSION_LEVEL)
+	Compression      string `json:"compression"`       // gzip, zstd
+	CompressionLevel int    `json:"compression_level"` // 0 = codec default
 }
 
 // RestoreBackupRequest represents the request body for restoring a backup
@@ -80,13 +89,40 @@ func (h *BackupHandler) CreateBackup(c *gin.Context) {
 		userID = &uidStr
 	}
 
-	backup, err := h.backupService.CreateBackup(serverID, req.Type, req.Description, userID, req.RetentionDays)
+	opts := h.backupService.DefaultCompression()
+	if req.Compression != "" {
+		codec, err := compression.ParseCodec(req.Compression)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		opts.Codec = codec
+		opts.Level = req.CompressionLevel
+	} else if req.CompressionLevel != 0 {
+		opts.Level = req.CompressionLevel
+	}
+	if err := opts.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	backup, op, err := h.backupService.CreateBackupWithCompression(c.Request.Context(), serverID, req.Type, req.Description, userID, req.RetentionDays, opts)
 	if err != nil {
 		logger.Error("BACKUP-API: Failed to create backup", err, map[string]interface{}{
 			"server_id": serverID,
 			"type":      req.Type,
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondOperationError(c, err)
+		return
+	}
+
+	// Owner is at the concurrency limit of their plan: backup stays pending until a slot is free
+	if op.IsQueued() {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":   "backup queued",
+			"backup":    backup,
+			"operation": op,
+		})
 		return
 	}
 
@@ -96,22 +132,33 @@ func (h *BackupHandler) CreateBackup(c *gin.Context) {
 	})
 }
 
-// ListBackups handles GET /api/servers/:id/backups
+// ListBackups lists the backups of a server
+// GET /api/servers/:id/backups
+// Supports ?cursor, ?limit, ?sort and the status/type filters.
 func (h *BackupHandler) ListBackups(c *gin.Context) {
 	serverID := c.Param("id")
 
-	backups, err := h.backupRepo.FindByServerID(serverID)
+	page, err := parsePageRequest(c, 0, "status", "type")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	backups, err := h.backupRepo.FindByServerIDPage(serverID, page)
 	if err != nil {
 		logger.Error("BACKUP-API: Failed to list backups", err, map[string]interface{}{
 			"server_id": serverID,
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondPageError(c, err)
 		return
 	}
 
+	setPageHeaders(c, backups.Total, backups.NextCursor)
 	c.JSON(http.StatusOK, gin.H{
-		"backups": backups,
-		"count":   len(backups),
+		"backups":     backups.Items,
+		"count":       len(backups.Items),
+		"total":       backups.Total,
+		"next_cursor": backups.NextCursor,
 	})
 }
 
@@ -128,6 +175,10 @@ func (h *BackupHandler) GetBackup(c *gin.Context) {
 		return
 	}
 
+	if !h.authorizeBackup(c, backup, models.PermServerView) {
+		return
+	}
+
 	c.JSON(http.StatusOK, backup)
 }
 
@@ -155,12 +206,22 @@ func (h *BackupHandler) RestoreBackup(c *gin.Context) {
 
 	// Restore backup without quota enforcement (server-level restore)
 	// If user quota tracking is needed, use RestoreUserBackup endpoint instead
-	if err := h.backupService.RestoreBackup(req.BackupID, serverID, nil); err != nil {
+	op, err := h.backupService.RequestRestore(c.Request.Context(), req.BackupID, serverID, nil, c.GetString("user_id"))
+	if err != nil {
 		logger.Error("BACKUP-API: Failed to restore backup", err, map[string]interface{}{
 			"server_id": serverID,
 			"backup_id": req.BackupID,
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondOperationError(c, err)
+		return
+	}
+	if op.IsQueued() {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":   "backup restore queued",
+			"server_id": serverID,
+			"backup_id": req.BackupID,
+			"operation": op,
+		})
 		return
 	}
 
@@ -183,23 +244,16 @@ func (h *BackupHandler) DeleteBackup(c *gin.Context) {
 		return
 	}
 
-	// FIX BACKUP-2: Authorization - verify user owns the server that this backup belongs to
-	userID, exists := c.Get("user_id")
-	if exists {
-		server, err := h.serverRepo.FindByID(backup.ServerID)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "backup not found"})
-			return
-		}
-		// Only allow the server owner (or admin) to delete backups
-		isAdmin, _ := c.Get("is_admin")
-		if server.OwnerID != userID.(string) && !isAdmin.(bool) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "you don't have permission to delete this backup"})
-			return
-		}
+	// FIX BACKUP-2: Authorization - deleting requires the manage permission on the backup's server
+	if !h.authorizeBackup(c, backup, models.PermServerManage) {
+		return
 	}
 
 	if err := h.backupService.DeleteBackup(backupID); err != nil {
+		if errors.Is(err, service.ErrBackupLegalHold) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		logger.Error("BACKUP-API: Failed to delete backup", err, map[string]interface{}{
 			"backup_id": backupID,
 		})
@@ -351,9 +405,13 @@ func (h *BackupHandler) RestoreUserBackup(c *gin.Context) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
+	if !h.authorizeBackup(c, backup, models.PermServerManage) {
+		return
+	}
 
 	// Restore backup (quota check happens inside)
-	if err := h.backupService.RestoreBackup(backupID, backup.ServerID, &userID); err != nil {
+	op, err := h.backupService.RequestRestore(c.Request.Context(), backupID, backup.ServerID, &userID, c.GetString("user_id"))
+	if err != nil {
 		logger.Error("Failed to restore backup", err, map[string]interface{}{
 			"backup_id": backupID,
 			"user_id":   userID,
@@ -366,7 +424,16 @@ func (h *BackupHandler) RestoreUserBackup(c *gin.Context) {
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondOperationError(c, err)
+		return
+	}
+	if op.IsQueued() {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":   "Backup restore queued",
+			"backup_id": backupID,
+			"server_id": backup.ServerID,
+			"operation": op,
+		})
 		return
 	}
 
@@ -376,3 +443,96 @@ func (h *BackupHandler) RestoreUserBackup(c *gin.Context) {
 		"server_id": backup.ServerID,
 	})
 }
+
+// authorizeBackup checks that the caller has perm on the backup's server (admins always pass)
+// and writes the error response otherwise
+func (h *BackupHandler) authorizeBackup(c *gin.Context, backup *models.Backup, perm models.ServerPermission) bool {
+	if c.GetBool("is_admin") || h.permissionService == nil {
+		return true
+	}
+
+	_, err := h.permissionService.Authorize(c.GetString("user_id"), backup.ServerID, perm)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, models.ErrServerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "backup not found"})
+	default:
+		c.JSON(http.StatusForbidden, gin.H{"error": "you don't have permission for this backup"})
+	}
+	return false
+}
+
+// PlaceLegalHoldRequest represents the request body for placing a legal hold
+type PlaceLegalHoldRequest struct {
+	ServerID string `json:"server_id"` // Hold all backups of the server (if backup_id is empty)
+	BackupID string `json:"backup_id"`
+	Reason   string `json:"reason" binding:"required"`
+}
+
+// ListLegalHolds handles GET /api/admin/legal-holds?server_id=...&include_released=true (admin only)
+func (h *BackupHandler) ListLegalHolds(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	holds, err := h.backupService.ListLegalHolds(c.Query("server_id"), c.Query("include_released") == "true")
+	if err != nil {
+		logger.Error("BACKUP-API: Failed to list legal holds", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"holds": holds,
+		"count": len(holds),
+	})
+}
+
+// PlaceLegalHold handles POST /api/admin/legal-holds (admin only)
+func (h *BackupHandler) PlaceLegalHold(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var req PlaceLegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hold, err := h.backupService.PlaceLegalHold(req.ServerID, req.BackupID, req.Reason,
+		c.GetString("user_id"), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, hold)
+}
+
+// ReleaseLegalHold handles DELETE /api/admin/legal-holds/:hold_id (admin only)
+func (h *BackupHandler) ReleaseLegalHold(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	hold, err := h.backupService.ReleaseLegalHold(c.Param("hold_id"),
+		c.GetString("user_id"), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		if errors.Is(err, service.ErrLegalHoldNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("BACKUP-API: Failed to release legal hold", err, map[string]interface{}{
+			"hold_id": c.Param("hold_id"),
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
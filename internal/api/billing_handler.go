@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/payperplay/hosting/internal/service"
@@ -108,3 +109,43 @@ func (h *BillingHandler) GetUsageSessions(c *gin.Context) {
 		"sessions":  sessions,
 	})
 }
+
+// GetCostBreakdown returns a server's costs split by compute, backup, archive and migration per day
+// GET /api/billing/servers/:id/breakdown?from=2025-11-01&to=2025-12-01 (default: current month, "to" exclusive)
+func (h *BillingHandler) GetCostBreakdown(c *gin.Context) {
+	serverID := c.Param("id")
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	to := from.AddDate(0, 1, 0)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' date, expected YYYY-MM-DD"})
+			return
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' date, expected YYYY-MM-DD"})
+			return
+		}
+		to = parsed
+	}
+
+	breakdown, err := h.billingService.GetCostBreakdown(serverID, from, to)
+	if err != nil {
+		logger.Error("Failed to get cost breakdown", err, map[string]interface{}{
+			"server_id": serverID,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
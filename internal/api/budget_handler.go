@@ -0,0 +1,267 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// BudgetHandler handles monthly budget cap and spending alert endpoints
+type BudgetHandler struct {
+	billingService *service.BillingService
+	serverRepo     *repository.ServerRepository
+}
+
+// NewBudgetHandler creates a new budget handler
+func NewBudgetHandler(billingService *service.BillingService, serverRepo *repository.ServerRepository) *BudgetHandler {
+	return &BudgetHandler{
+		billingService: billingService,
+		serverRepo:     serverRepo,
+	}
+}
+
+// budgetRequest is the body for creating or updating a budget cap
+type budgetRequest struct {
+	MonthlyLimitEUR float64 `json:"monthly_limit_eur" binding:"required,gt=0"`
+	AutoStop        bool    `json:"auto_stop"`
+	BlockStarts     bool    `json:"block_starts"`
+}
+
+// ListBudgets returns all budget caps of the current user with their current spend
+// GET /api/billing/budgets
+func (h *BudgetHandler) ListBudgets(c *gin.Context) {
+	caps, err := h.billingService.ListBudgetCaps(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list budgets"})
+		return
+	}
+
+	statuses := make([]*service.BudgetStatus, 0, len(caps))
+	for i := range caps {
+		status, err := h.billingService.GetBudgetStatus(&caps[i])
+		if err != nil {
+			logger.Warn("Failed to get budget status", map[string]interface{}{
+				"budget_cap_id": caps[i].ID,
+				"error":         err.Error(),
+			})
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"budgets": statuses,
+		"count":   len(statuses),
+	})
+}
+
+// GetUserBudget returns the account-wide budget cap of the current user
+// GET /api/billing/budget
+func (h *BudgetHandler) GetUserBudget(c *gin.Context) {
+	h.getBudget(c, models.BudgetScopeUser, c.GetString("user_id"))
+}
+
+// UpdateUserBudget creates or updates the account-wide budget cap of the current user
+// PUT /api/billing/budget
+// Body: {"monthly_limit_eur": 25.0, "auto_stop": true, "block_starts": true}
+func (h *BudgetHandler) UpdateUserBudget(c *gin.Context) {
+	userID := c.GetString("user_id")
+	h.updateBudget(c, &models.BudgetCap{Scope: models.BudgetScopeUser, UserID: userID})
+}
+
+// DeleteUserBudget removes the account-wide budget cap of the current user
+// DELETE /api/billing/budget
+func (h *BudgetHandler) DeleteUserBudget(c *gin.Context) {
+	h.deleteBudget(c, models.BudgetScopeUser, c.GetString("user_id"))
+}
+
+// GetServerBudget returns the budget cap of a server
+// GET /api/servers/:id/budget
+func (h *BudgetHandler) GetServerBudget(c *gin.Context) {
+	server, ok := h.loadServer(c)
+	if !ok {
+		return
+	}
+	h.getBudget(c, models.BudgetScopeServer, server.ID)
+}
+
+// UpdateServerBudget creates or updates the budget cap of a server
+// PUT /api/servers/:id/budget
+// Body: {"monthly_limit_eur": 10.0, "auto_stop": true, "block_starts": false}
+func (h *BudgetHandler) UpdateServerBudget(c *gin.Context) {
+	server, ok := h.loadServer(c)
+	if !ok {
+		return
+	}
+	h.updateBudget(c, &models.BudgetCap{Scope: models.BudgetScopeServer, UserID: server.OwnerID, ServerID: server.ID})
+}
+
+// DeleteServerBudget removes the budget cap of a server
+// DELETE /api/servers/:id/budget
+func (h *BudgetHandler) DeleteServerBudget(c *gin.Context) {
+	server, ok := h.loadServer(c)
+	if !ok {
+		return
+	}
+	h.deleteBudget(c, models.BudgetScopeServer, server.ID)
+}
+
+// GetAlerts returns the spending alerts of the current user
+// GET /api/billing/budget/alerts?limit=100
+func (h *BudgetHandler) GetAlerts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	alerts, err := h.billingService.GetBudgetAlerts(c.GetString("user_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get budget alerts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": alerts,
+		"count":  len(alerts),
+	})
+}
+
+// SetOverride suspends enforcement of a budget cap (admin only)
+// POST /api/admin/budgets/:cap_id/override
+// Body: {"duration_hours": 24, "reason": "Tournament weekend"}
+func (h *BudgetHandler) SetOverride(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	capID, err := strconv.ParseUint(c.Param("cap_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid budget cap ID"})
+		return
+	}
+
+	var request struct {
+		DurationHours int    `json:"duration_hours" binding:"required,gt=0"`
+		Reason        string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	until := time.Now().Add(time.Duration(request.DurationHours) * time.Hour)
+	budget, err := h.billingService.SetBudgetOverride(uint(capID), c.GetString("user_id"), until, request.Reason)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"budget": budget,
+	})
+}
+
+// ClearOverride re-enables enforcement of a budget cap (admin only)
+// DELETE /api/admin/budgets/:cap_id/override
+func (h *BudgetHandler) ClearOverride(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	capID, err := strconv.ParseUint(c.Param("cap_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid budget cap ID"})
+		return
+	}
+
+	if err := h.billingService.ClearBudgetOverride(uint(capID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear override"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Budget override cleared",
+	})
+}
+
+// loadServer loads the server from :id (access is checked by middleware.RequireServerPermission)
+func (h *BudgetHandler) loadServer(c *gin.Context) (*models.MinecraftServer, bool) {
+	server, err := h.serverRepo.FindByID(c.Param("id"))
+	if err != nil || server == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return nil, false
+	}
+	return server, true
+}
+
+func (h *BudgetHandler) getBudget(c *gin.Context, scope models.BudgetScope, targetID string) {
+	budget, err := h.billingService.GetBudgetCap(scope, targetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get budget"})
+		return
+	}
+	if budget == nil {
+		c.JSON(http.StatusOK, gin.H{"configured": false})
+		return
+	}
+
+	status, err := h.billingService.GetBudgetStatus(budget)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate budget status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"configured": true,
+		"budget":     status,
+	})
+}
+
+func (h *BudgetHandler) updateBudget(c *gin.Context, budget *models.BudgetCap) {
+	var request budgetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	budget.MonthlyLimitEUR = request.MonthlyLimitEUR
+	budget.AutoStop = request.AutoStop
+	budget.BlockStarts = request.BlockStarts
+
+	saved, err := h.billingService.SetBudgetCap(budget)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("Budget cap updated", map[string]interface{}{
+		"scope":             saved.Scope,
+		"user_id":           saved.UserID,
+		"server_id":         saved.ServerID,
+		"monthly_limit_eur": saved.MonthlyLimitEUR,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"budget": saved,
+	})
+}
+
+func (h *BudgetHandler) deleteBudget(c *gin.Context, scope models.BudgetScope, targetID string) {
+	if err := h.billingService.DeleteBudgetCap(scope, targetID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete budget"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Budget cap removed",
+	})
+}
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"sync"
 
 	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/middleware"
 	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/service"
 	"github.com/payperplay/hosting/pkg/logger"
@@ -14,13 +16,15 @@ import (
 type BulkHandler struct {
 	mcService     *service.MinecraftService
 	backupService *service.BackupService
+	bulkJobs      *service.BulkJobService
 }
 
 // NewBulkHandler creates a new bulk handler
-func NewBulkHandler(mcService *service.MinecraftService, backupService *service.BackupService) *BulkHandler {
+func NewBulkHandler(mcService *service.MinecraftService, backupService *service.BackupService, bulkJobs *service.BulkJobService) *BulkHandler {
 	return &BulkHandler{
 		mcService:     mcService,
 		backupService: backupService,
+		bulkJobs:      bulkJobs,
 	}
 }
 
@@ -41,6 +45,37 @@ type BulkItem struct {
 	Message  string `json:"message,omitempty"`
 }
 
+// BulkRestartRequest is a rolling restart of multiple servers
+type BulkRestartRequest struct {
+	BulkRequest
+	service.BulkJobOptions
+}
+
+// BulkPluginInstallRequest installs a marketplace plugin on multiple servers
+type BulkPluginInstallRequest struct {
+	BulkRequest
+	service.BulkJobOptions
+	PluginSlug string `json:"plugin_slug" binding:"required"`
+	VersionID  string `json:"version_id"` // Latest compatible version if empty
+	AutoUpdate bool   `json:"auto_update"`
+	Restart    bool   `json:"restart"` // Restart running servers after the install
+}
+
+// BulkPluginRemoveRequest removes a marketplace plugin from multiple servers
+type BulkPluginRemoveRequest struct {
+	BulkRequest
+	service.BulkJobOptions
+	PluginID string `json:"plugin_id" binding:"required"`
+	Restart  bool   `json:"restart"` // Restart running servers after the removal
+}
+
+// BulkConfigRequest applies the same configuration changes to multiple servers
+type BulkConfigRequest struct {
+	BulkRequest
+	service.BulkJobOptions
+	Changes map[string]interface{} `json:"changes" binding:"required"`
+}
+
 // BulkStartServers starts multiple servers
 // POST /api/servers/bulk/start
 func (h *BulkHandler) BulkStartServers(c *gin.Context) {
@@ -53,7 +88,8 @@ func (h *BulkHandler) BulkStartServers(c *gin.Context) {
 	}
 
 	result := h.executeBulkOperation(req.ServerIDs, userID, func(serverID string) error {
-		return h.mcService.StartServer(serverID)
+		_, err := h.mcService.RequestStart(c.Request.Context(), serverID, userID) // Queued starts count as success
+		return err
 	})
 
 	logger.Info("Bulk start operation completed", map[string]interface{}{
@@ -81,7 +117,7 @@ func (h *BulkHandler) BulkStopServers(c *gin.Context) {
 	}
 
 	result := h.executeBulkOperation(req.ServerIDs, userID, func(serverID string) error {
-		return h.mcService.StopServer(serverID, "Bulk stop operation")
+		return h.mcService.StopServerContext(c.Request.Context(), serverID, "Bulk stop operation")
 	})
 
 	logger.Info("Bulk stop operation completed", map[string]interface{}{
@@ -161,6 +197,125 @@ func (h *BulkHandler) BulkBackupServers(c *gin.Context) {
 	})
 }
 
+// BulkRestartServers restarts running servers in batches, waiting until each batch is running again
+// The job runs in the background; progress is sent as "bulk_job.progress" WebSocket events.
+// POST /api/servers/bulk/restart
+// Body: {"server_ids": ["a1b2c3d4", "e5f6a7b8"], "batch_size": 1, "stop_on_failure": true}
+func (h *BulkHandler) BulkRestartServers(c *gin.Context) {
+	var req BulkRestartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	rejected := h.authorizeServers(c, req.ServerIDs, models.PermServerPower)
+	job := h.bulkJobs.RollingRestart(c.GetString("user_id"), req.ServerIDs, req.BulkJobOptions, rejected)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// BulkInstallPlugin installs a marketplace plugin on multiple servers, optionally restarting them
+// Small batches with stop_on_failure stage the rollout: a plugin that breaks a server stops it.
+// POST /api/servers/bulk/plugins/install
+// Body: {"server_ids": ["a1b2c3d4", "e5f6a7b8"], "plugin_slug": "luckperms", "restart": true, "batch_size": 1, "stop_on_failure": true}
+func (h *BulkHandler) BulkInstallPlugin(c *gin.Context) {
+	var req BulkPluginInstallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	rejected := h.authorizeServers(c, req.ServerIDs, models.PermServerFilesWrite)
+	if req.Restart {
+		h.authorizeMore(c, req.ServerIDs, models.PermServerPower, rejected)
+	}
+	job := h.bulkJobs.InstallPlugin(c.GetString("user_id"), req.ServerIDs, req.PluginSlug, req.VersionID, req.AutoUpdate, req.Restart, req.BulkJobOptions, rejected)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// BulkRemovePlugin removes a marketplace plugin from multiple servers, optionally restarting them
+// POST /api/servers/bulk/plugins/remove
+// Body: {"server_ids": ["a1b2c3d4", "e5f6a7b8"], "plugin_id": "7d9e1f20", "restart": true}
+func (h *BulkHandler) BulkRemovePlugin(c *gin.Context) {
+	var req BulkPluginRemoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	rejected := h.authorizeServers(c, req.ServerIDs, models.PermServerFilesWrite)
+	if req.Restart {
+		h.authorizeMore(c, req.ServerIDs, models.PermServerPower, rejected)
+	}
+	job := h.bulkJobs.RemovePlugin(c.GetString("user_id"), req.ServerIDs, req.PluginID, req.Restart, req.BulkJobOptions, rejected)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// BulkApplyConfig applies the same configuration changes to multiple servers (one at a time by default)
+// POST /api/servers/bulk/config
+// Body: {"server_ids": ["a1b2c3d4", "e5f6a7b8"], "changes": {"max_players": 50, "difficulty": "hard"}, "batch_size": 1}
+func (h *BulkHandler) BulkApplyConfig(c *gin.Context) {
+	var req BulkConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if len(req.Changes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No changes given"})
+		return
+	}
+
+	rejected := h.authorizeServers(c, req.ServerIDs, models.PermServerFilesWrite)
+	job := h.bulkJobs.ApplyConfig(c.GetString("user_id"), req.ServerIDs, req.Changes, req.BulkJobOptions, rejected)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListBulkJobs returns the running and recently finished bulk jobs of the current user
+// GET /api/servers/bulk/jobs
+func (h *BulkHandler) ListBulkJobs(c *gin.Context) {
+	jobs := h.bulkJobs.List(c.GetString("user_id"))
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+// GetBulkJob returns the progress and per-server results of a bulk job
+// GET /api/servers/bulk/jobs/:job_id
+func (h *BulkHandler) GetBulkJob(c *gin.Context) {
+	job, err := h.bulkJobs.Get(c.GetString("user_id"), c.Param("job_id"))
+	if errors.Is(err, service.ErrBulkJobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// authorizeServers checks perm on every server and returns the rejected ones (server ID -> reason)
+func (h *BulkHandler) authorizeServers(c *gin.Context, serverIDs []string, perm models.ServerPermission) map[string]string {
+	rejected := make(map[string]string)
+	h.authorizeMore(c, serverIDs, perm, rejected)
+	return rejected
+}
+
+// authorizeMore adds the servers missing perm to rejected
+func (h *BulkHandler) authorizeMore(c *gin.Context, serverIDs []string, perm models.ServerPermission, rejected map[string]string) {
+	for _, serverID := range serverIDs {
+		if _, ok := rejected[serverID]; ok {
+			continue
+		}
+		err := middleware.AuthorizeServerAccess(c, serverID, perm)
+		switch {
+		case err == nil:
+		case errors.Is(err, models.ErrServerNotFound):
+			rejected[serverID] = "server not found"
+		case errors.Is(err, models.ErrServerForbidden):
+			rejected[serverID] = "requires the '" + string(perm) + "' permission on this server"
+		default:
+			rejected[serverID] = "failed to check server permissions"
+		}
+	}
+}
+
 // executeBulkOperation executes a bulk operation in parallel
 func (h *BulkHandler) executeBulkOperation(serverIDs []string, userID string, operation func(string) error) BulkResult {
 	var wg sync.WaitGroup
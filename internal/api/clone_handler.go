@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// CloneHandler handles copying servers and saving them as templates
+type CloneHandler struct {
+	cloneService *service.CloneService
+}
+
+// NewCloneHandler creates a new clone handler
+func NewCloneHandler(cloneService *service.CloneService) *CloneHandler {
+	return &CloneHandler{cloneService: cloneService}
+}
+
+// CloneServer creates a new server from a snapshot of the world, config and plugins of a server
+// The clone is owned by the current user and answers 202: it stays stopped until the copy (the
+// returned operation) finishes and is then queued like a new server. With save_as_template the
+// snapshot is also kept as a template of the user, listed in GET /api/templates.
+// POST /api/servers/:id/clone
+// Body: {"name": "survival-copy", "ram_mb": 0, "save_as_template": true, "template_name": "Survival Base", "template_description": ""}
+func (h *CloneHandler) CloneServer(c *gin.Context) {
+	var req service.CloneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !serverNameRegex.MatchString(req.Name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidServerName})
+		return
+	}
+
+	server, op, err := h.cloneService.Clone(c.Param("id"), c.GetString("user_id"), req)
+	switch {
+	case errors.Is(err, service.ErrCloneTemplateNameMissing):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrCloneSourceArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		respondOperationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"server":    server,
+		"operation": op,
+	})
+}
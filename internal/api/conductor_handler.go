@@ -1,24 +1,37 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
 	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/service"
 )
 
 // ConductorHandler handles Conductor API endpoints
 type ConductorHandler struct {
-	conductor *conductor.Conductor
+	conductor     *conductor.Conductor
+	migrationRepo *repository.MigrationRepository
+	audit         *service.AuditService // Optional: records placement changes
 }
 
 // NewConductorHandler creates a new Conductor handler
-func NewConductorHandler(cond *conductor.Conductor) *ConductorHandler {
+func NewConductorHandler(cond *conductor.Conductor, migrationRepo *repository.MigrationRepository) *ConductorHandler {
 	return &ConductorHandler{
-		conductor: cond,
+		conductor:     cond,
+		migrationRepo: migrationRepo,
 	}
 }
 
+// SetAuditService sets the service that records placement changes in the audit log
+func (h *ConductorHandler) SetAuditService(auditService *service.AuditService) {
+	h.audit = auditService
+}
+
 // GetStatus returns the current conductor status
 // GET /conductor/status
 func (h *ConductorHandler) GetStatus(c *gin.Context) {
@@ -52,6 +65,55 @@ func (h *ConductorHandler) GetNodes(c *gin.Context) {
 	})
 }
 
+// SetNodePlacement replaces the scheduling labels and taints of a node (admin only)
+// PUT /api/admin/nodes/:node_id/placement
+// Body: {"labels": "high-memory,dedicated-customer=acme", "taints": "dedicated-customer=acme:NoSchedule"}
+func (h *ConductorHandler) SetNodePlacement(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request struct {
+		Labels string `json:"labels"`
+		Taints string `json:"taints"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	labels, err := models.ParseNodeLabels(request.Labels)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	taints, err := models.ParseNodeTaints(request.Taints)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	nodeID := c.Param("node_id")
+	node, exists := h.conductor.NodeRegistry.GetNode(nodeID)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "node not found"})
+		return
+	}
+	before := gin.H{"labels": node.Labels, "taints": node.Taints}
+	if err := h.conductor.NodeRegistry.SetNodePlacement(nodeID, labels, taints); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionNodePlacement, "node", nodeID, before, gin.H{"labels": labels, "taints": taints}))
+
+	c.JSON(http.StatusOK, gin.H{
+		"node_id": nodeID,
+		"labels":  labels,
+		"taints":  taints,
+	})
+}
+
 // GetContainers returns all registered containers
 // GET /conductor/containers
 func (h *ConductorHandler) GetContainers(c *gin.Context) {
@@ -63,6 +125,58 @@ func (h *ConductorHandler) GetContainers(c *gin.Context) {
 	})
 }
 
+// GetTopology exports the fleet (nodes, containers, proxy links, pending migrations, start queue)
+// as a graph document from one consistent registry snapshot
+// GET /conductor/topology?format=json|dot
+func (h *ConductorHandler) GetTopology(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "dot" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or dot"})
+		return
+	}
+
+	snapshot := h.conductor.Snapshot()
+
+	var migrations []models.Migration
+	if h.migrationRepo != nil {
+		var err error
+		if migrations, err = h.migrationRepo.FindUnfinished(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load pending migrations"})
+			return
+		}
+	}
+
+	graph := conductor.BuildTopologyGraph(snapshot, migrations)
+	filename := fmt.Sprintf("fleet-topology-%s.%s", snapshot.TakenAt.UTC().Format("20060102-150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+
+	if format == "dot" {
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(graph.DOT()))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"data":   graph,
+	})
+}
+
+// GetSSHPoolStats returns per-node SSH connection pool statistics
+// GET /conductor/ssh-pool
+func (h *ConductorHandler) GetSSHPoolStats(c *gin.Context) {
+	if h.conductor.RemoteClient == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"status": "ok",
+			"data":   []interface{}{},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"data":   h.conductor.RemoteClient.Pool().Stats(),
+	})
+}
+
 // GetDebugLogs returns recent debug log entries for the dashboard console
 // GET /conductor/debug-logs
 func (h *ConductorHandler) GetDebugLogs(c *gin.Context) {
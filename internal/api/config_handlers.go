@@ -49,9 +49,8 @@ func (h *ConfigHandler) ApplyConfigChanges(c *gin.Context) {
 		return
 	}
 
-	// Verify server exists and user has access
-	server, err := h.serverService.GetServer(serverID)
-	if err != nil {
+	// Verify server exists (access is checked by middleware.RequireServerPermission)
+	if _, err := h.serverService.GetServer(serverID); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Server not found",
 			"code":  "SERVER_NOT_FOUND",
@@ -59,18 +58,6 @@ func (h *ConfigHandler) ApplyConfigChanges(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	if server.OwnerID != userID {
-		isAdmin, _ := c.Get("is_admin")
-		if !isAdmin.(bool) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "You don't have permission to modify this server",
-				"code":  "FORBIDDEN",
-			})
-			return
-		}
-	}
-
 	// Apply configuration changes
 	changeRequest := service.ConfigChangeRequest{
 		ServerID: serverID,
@@ -115,11 +102,9 @@ func (h *ConfigHandler) ApplyConfigChanges(c *gin.Context) {
 // GetConfigHistory handles GET /api/servers/:id/config/history
 func (h *ConfigHandler) GetConfigHistory(c *gin.Context) {
 	serverID := c.Param("id")
-	userID := middleware.GetUserID(c)
 
-	// Verify server exists and user has access
-	server, err := h.serverService.GetServer(serverID)
-	if err != nil {
+	// Verify server exists (access is checked by middleware.RequireServerPermission)
+	if _, err := h.serverService.GetServer(serverID); err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Server not found",
 			"code":  "SERVER_NOT_FOUND",
@@ -127,18 +112,6 @@ func (h *ConfigHandler) GetConfigHistory(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	if server.OwnerID != userID {
-		isAdmin, _ := c.Get("is_admin")
-		if !isAdmin.(bool) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "You don't have permission to view this server's configuration history",
-				"code":  "FORBIDDEN",
-			})
-			return
-		}
-	}
-
 	// Get configuration history
 	history, err := h.configService.GetConfigHistory(serverID)
 	if err != nil {
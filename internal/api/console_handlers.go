@@ -40,13 +40,14 @@ func (h *ConsoleHandler) HandleConsoleWebSocket(c *gin.Context) {
 		return
 	}
 	defer conn.Close()
+	defer trackWebSocket(conn)()
 
 	logger.Info("Console WebSocket connected", map[string]interface{}{
 		"server_id": serverID,
 	})
 
 	// Start streaming logs
-	logChan, cancel, err := h.consoleService.StreamLogs(serverID)
+	logChan, cancel, err := h.consoleService.StreamLogs(c.Request.Context(), serverID)
 	if err != nil {
 		logger.Error("Failed to start log stream", err, map[string]interface{}{
 			"server_id": serverID,
@@ -79,7 +80,7 @@ func (h *ConsoleHandler) HandleConsoleWebSocket(c *gin.Context) {
 
 			if msg.Type == "command" {
 				// Execute command via RCON
-				response, err := h.consoleService.ExecuteCommand(serverID, msg.Content)
+				response, err := h.consoleService.ExecuteCommand(c.Request.Context(), serverID, msg.Content)
 				if err != nil {
 					logger.Error("Failed to execute command", err, map[string]interface{}{
 						"server_id": serverID,
@@ -161,7 +162,7 @@ func (h *ConsoleHandler) ExecuteConsoleCommand(c *gin.Context) {
 	}
 
 	// Execute command via RCON
-	response, err := h.consoleService.ExecuteCommand(serverID, req.Command)
+	response, err := h.consoleService.ExecuteCommand(c.Request.Context(), serverID, req.Command)
 	if err != nil {
 		logger.Error("Failed to execute console command", err, map[string]interface{}{
 			"server_id": serverID,
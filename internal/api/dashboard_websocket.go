@@ -1,14 +1,18 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/events"
 	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/repository"
 	"github.com/payperplay/hosting/pkg/logger"
@@ -24,38 +28,69 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+const (
+	dashboardHistorySize  = 1024                     // Sequenced events kept for clients resuming after a reconnect
+	dashboardClientBuffer = dashboardHistorySize + 64 // Per-client send queue (fits a full replay)
+)
+
 // DashboardWebSocket manages WebSocket connections for the admin dashboard
+// Published events get a monotonically increasing sequence number and the most recent ones are kept
+// in a ring buffer, so a client reconnecting with ?stream=<id>&since=<seq> receives the events it
+// missed instead of a full state reload.
 type DashboardWebSocket struct {
 	conductor       *conductor.Conductor
 	migrationRepo   *repository.MigrationRepository
 	serverRepo      *repository.ServerRepository
-	clients         map[*websocket.Conn]bool
+	clients         map[*websocket.Conn]*dashboardClient
 	clientsMutex    sync.RWMutex
 	clientWriters   map[*websocket.Conn]*sync.Mutex // Mutex per client to prevent concurrent writes
 	writersMutex    sync.Mutex
 	broadcast       chan DashboardEvent
-	register        chan *websocket.Conn
+	register        chan dashboardRegistration
 	unregister      chan *websocket.Conn
 	shutdownChan    chan struct{}
+	relay           *events.Relay // Optional: shares published events with other API instances
+
+	// Owned by the Run goroutine
+	streamID string           // Changes on restart: sequence numbers of another stream can't be resumed
+	seq      uint64           // Sequence number of the last published event
+	history  []DashboardEvent // Last dashboardHistorySize sequenced events, oldest first
 }
 
 // DashboardEvent represents a WebSocket message sent to dashboard clients
+// Seq is 0 for snapshots (initial state, periodic fleet stats) that are not replayed on resume.
 type DashboardEvent struct {
+	Seq       uint64      `json:"seq,omitempty"`
 	Type      string      `json:"type"`
 	Timestamp time.Time   `json:"timestamp"`
 	Data      interface{} `json:"data"`
 }
 
+// dashboardClient is a connected dashboard with its ordered send queue
+type dashboardClient struct {
+	conn *websocket.Conn
+	send chan DashboardEvent
+}
+
+// dashboardRegistration is a new connection and the stream position it wants to resume from
+type dashboardRegistration struct {
+	conn     *websocket.Conn
+	streamID string
+	since    uint64
+	resume   bool
+}
+
 // NewDashboardWebSocket creates a new dashboard WebSocket manager
 func NewDashboardWebSocket(conductor *conductor.Conductor) *DashboardWebSocket {
 	return &DashboardWebSocket{
 		conductor:     conductor,
-		clients:       make(map[*websocket.Conn]bool),
+		clients:       make(map[*websocket.Conn]*dashboardClient),
 		clientWriters: make(map[*websocket.Conn]*sync.Mutex),
 		broadcast:     make(chan DashboardEvent, 256),
-		register:      make(chan *websocket.Conn),
+		register:      make(chan dashboardRegistration),
 		unregister:    make(chan *websocket.Conn),
 		shutdownChan:  make(chan struct{}),
+		streamID:      uuid.New().String(),
 	}
 }
 
@@ -65,6 +100,24 @@ func (ws *DashboardWebSocket) SetRepositories(migrationRepo *repository.Migratio
 	ws.serverRepo = serverRepo
 }
 
+// SetTransport shares published events with the dashboards connected to other API instances
+// Fleet stats are not relayed: every instance broadcasts its own view of the conductor.
+func (ws *DashboardWebSocket) SetTransport(transport events.Transport) error {
+	relay, err := events.NewRelay(transport, events.SubjectDashboard, func(payload []byte) {
+		var event DashboardEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return
+		}
+		ws.enqueue(event)
+	})
+	if err != nil {
+		return err
+	}
+
+	ws.relay = relay
+	return nil
+}
+
 // Run starts the WebSocket manager (run in goroutine)
 func (ws *DashboardWebSocket) Run() {
 	logger.Info("DashboardWebSocket: Starting WebSocket manager", nil)
@@ -75,34 +128,63 @@ func (ws *DashboardWebSocket) Run() {
 
 	for {
 		select {
-		case client := <-ws.register:
+		case registration := <-ws.register:
+			client := &dashboardClient{
+				conn: registration.conn,
+				send: make(chan DashboardEvent, dashboardClientBuffer),
+			}
+
 			ws.clientsMutex.Lock()
-			ws.clients[client] = true
+			ws.clients[client.conn] = client
 			ws.clientsMutex.Unlock()
 
 			// Create write mutex for this client
 			ws.writersMutex.Lock()
-			ws.clientWriters[client] = &sync.Mutex{}
+			ws.clientWriters[client.conn] = &sync.Mutex{}
 			ws.writersMutex.Unlock()
 
-			logger.Info("DashboardWebSocket: Client connected", map[string]interface{}{
-				"total_clients": len(ws.clients),
-			})
+			go ws.writePump(client)
+
+			// Replay the missed events, or send the full state if they are no longer buffered.
+			// Done here so no event published in between is lost or sent twice.
+			if missed, ok := ws.missedEvents(registration); ok {
+				client.send <- ws.streamEvent("stream.resumed", map[string]interface{}{
+					"from":     registration.since,
+					"replayed": len(missed),
+				})
+				for _, event := range missed {
+					client.send <- event
+				}
+
+				logger.Info("DashboardWebSocket: Client resumed stream", map[string]interface{}{
+					"total_clients": len(ws.clients),
+					"since":         registration.since,
+					"replayed":      len(missed),
+				})
+			} else {
+				client.send <- ws.streamEvent("stream.hello", nil)
 
-			// Send initial state to new client
-			go ws.sendInitialState(client)
+				logger.Info("DashboardWebSocket: Client connected", map[string]interface{}{
+					"total_clients": len(ws.clients),
+					"resume_failed": registration.resume,
+				})
+
+				// Send initial state to new client
+				go ws.sendInitialState(client.conn)
+			}
 
-		case client := <-ws.unregister:
+		case conn := <-ws.unregister:
 			ws.clientsMutex.Lock()
-			if _, ok := ws.clients[client]; ok {
-				delete(ws.clients, client)
-				client.Close()
+			if client, ok := ws.clients[conn]; ok {
+				delete(ws.clients, conn)
+				close(client.send)
+				conn.Close()
 			}
 			ws.clientsMutex.Unlock()
 
 			// Remove write mutex for this client
 			ws.writersMutex.Lock()
-			delete(ws.clientWriters, client)
+			delete(ws.clientWriters, conn)
 			ws.writersMutex.Unlock()
 
 			logger.Info("DashboardWebSocket: Client disconnected", map[string]interface{}{
@@ -110,11 +192,13 @@ func (ws *DashboardWebSocket) Run() {
 			})
 
 		case event := <-ws.broadcast:
-			ws.clientsMutex.RLock()
-			for client := range ws.clients {
-				go ws.sendToClient(client, event)
+			ws.seq++
+			event.Seq = ws.seq
+			ws.history = append(ws.history, event)
+			if len(ws.history) > dashboardHistorySize {
+				ws.history = ws.history[len(ws.history)-dashboardHistorySize:]
 			}
-			ws.clientsMutex.RUnlock()
+			ws.deliver(event)
 
 		case <-statsTicker.C:
 			// Broadcast periodic stats updates
@@ -128,8 +212,15 @@ func (ws *DashboardWebSocket) Run() {
 }
 
 // HandleConnection handles WebSocket upgrade and client connection
-// GET /api/admin/dashboard/stream
+// GET /api/admin/dashboard/stream?stream=<stream_id>&since=<seq>
+// stream and since resume after a reconnect (from the last stream.hello/stream.resumed and event seq).
 func (ws *DashboardWebSocket) HandleConnection(c *gin.Context) {
+	registration := dashboardRegistration{streamID: c.Query("stream")}
+	if since, err := strconv.ParseUint(c.Query("since"), 10, 64); err == nil && registration.streamID != "" {
+		registration.since = since
+		registration.resume = true
+	}
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		logger.Info("DashboardWebSocket: Failed to upgrade connection", map[string]interface{}{
@@ -139,10 +230,15 @@ func (ws *DashboardWebSocket) HandleConnection(c *gin.Context) {
 	}
 
 	// Register client
-	ws.register <- conn
+	untrack := trackWebSocket(conn)
+	registration.conn = conn
+	ws.register <- registration
 
 	// Handle client messages (ping/pong)
-	go ws.handleClientMessages(conn)
+	go func() {
+		ws.handleClientMessages(conn)
+		untrack()
+	}()
 }
 
 // handleClientMessages handles incoming messages from clients (mostly ping/pong)
@@ -201,6 +297,67 @@ func (ws *DashboardWebSocket) handleClientMessages(conn *websocket.Conn) {
 	}
 }
 
+// writePump sends queued events to a client in sequence order until it is unregistered
+func (ws *DashboardWebSocket) writePump(client *dashboardClient) {
+	for event := range client.send {
+		ws.sendToClient(client.conn, event)
+	}
+}
+
+// deliver queues an event for every client (called by the Run goroutine)
+// A client whose queue is full is disconnected; it reconnects and resumes from its last sequence.
+func (ws *DashboardWebSocket) deliver(event DashboardEvent) {
+	ws.clientsMutex.RLock()
+	defer ws.clientsMutex.RUnlock()
+
+	for conn, client := range ws.clients {
+		select {
+		case client.send <- event:
+		default:
+			logger.Warn("DashboardWebSocket: Client too slow, disconnecting", map[string]interface{}{
+				"event_type": event.Type,
+			})
+			go func(conn *websocket.Conn) { ws.unregister <- conn }(conn)
+		}
+	}
+}
+
+// missedEvents returns the buffered events after the client's last sequence number
+// ok is false if the client starts fresh or the events it missed are no longer buffered.
+func (ws *DashboardWebSocket) missedEvents(registration dashboardRegistration) (missed []DashboardEvent, ok bool) {
+	if !registration.resume || registration.streamID != ws.streamID || registration.since > ws.seq {
+		return nil, false
+	}
+	if registration.since == ws.seq {
+		return nil, true
+	}
+	if len(ws.history) == 0 || ws.history[0].Seq > registration.since+1 {
+		return nil, false // Gap: the oldest missed event was dropped from the ring buffer
+	}
+
+	for _, event := range ws.history {
+		if event.Seq > registration.since {
+			missed = append(missed, event)
+		}
+	}
+	return missed, true
+}
+
+// streamEvent builds an unsequenced stream control message with the current stream position
+func (ws *DashboardWebSocket) streamEvent(eventType string, data map[string]interface{}) DashboardEvent {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["stream_id"] = ws.streamID
+	data["seq"] = ws.seq
+
+	return DashboardEvent{
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+}
+
 // sendToClient sends an event to a specific client (thread-safe)
 func (ws *DashboardWebSocket) sendToClient(client *websocket.Conn, event DashboardEvent) {
 	// Get the write mutex for this client
@@ -272,6 +429,8 @@ func (ws *DashboardWebSocket) sendInitialState(client *websocket.Conn) {
 				"container_count":   containerCount,
 				"capacity_percent":  capacityPercent,
 				"cpu_usage_percent": node.CPUUsagePercent,
+				"cpu_load_percent":  node.CPULoadPercent,
+				"disk_free_mb":      node.DiskFreeMB,
 			},
 		}
 		ws.sendToClient(client, statsEvent)
@@ -301,6 +460,7 @@ func (ws *DashboardWebSocket) sendInitialState(client *websocket.Conn) {
 					"join_address":      joinAddress,
 					"minecraft_version": container.MinecraftVersion,
 					"server_type":       container.ServerType,
+					"cpu_percent":       container.CPUPercent,
 				},
 			}
 
@@ -432,7 +592,9 @@ func (ws *DashboardWebSocket) broadcastFleetStats() {
 			"queue_size":       ws.conductor.StartQueue.Size(),
 		},
 	}
-	ws.broadcast <- event
+
+	// Snapshot: not sequenced, a resuming client gets the next one within 5 seconds
+	ws.deliver(event)
 }
 
 // PublishEvent publishes an event to all connected clients
@@ -443,12 +605,22 @@ func (ws *DashboardWebSocket) PublishEvent(eventType string, data interface{}) {
 		Data:      data,
 	}
 
-	// Non-blocking send
+	ws.enqueue(event)
+
+	if ws.relay != nil {
+		if payload, err := json.Marshal(event); err == nil {
+			ws.relay.Publish(payload)
+		}
+	}
+}
+
+// enqueue hands an event to the broadcaster without blocking the publisher
+func (ws *DashboardWebSocket) enqueue(event DashboardEvent) {
 	select {
 	case ws.broadcast <- event:
 	default:
 		logger.Warn("DashboardWebSocket: Broadcast channel full, dropping event", map[string]interface{}{
-			"event_type": eventType,
+			"event_type": event.Type,
 		})
 	}
 }
@@ -459,8 +631,8 @@ func (ws *DashboardWebSocket) Shutdown() {
 
 	// Close all client connections
 	ws.clientsMutex.Lock()
-	for client := range ws.clients {
-		client.Close()
+	for conn := range ws.clients {
+		conn.Close()
 	}
 	ws.clientsMutex.Unlock()
 }
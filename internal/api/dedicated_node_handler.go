@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// DedicatedNodeHandler handles customer-dedicated worker nodes
+type DedicatedNodeHandler struct {
+	dedicatedNodeService *service.DedicatedNodeService
+}
+
+// NewDedicatedNodeHandler creates a new dedicated node handler
+func NewDedicatedNodeHandler(dedicatedNodeService *service.DedicatedNodeService) *DedicatedNodeHandler {
+	return &DedicatedNodeHandler{dedicatedNodeService: dedicatedNodeService}
+}
+
+// ListMyNodes returns the nodes dedicated to the current user
+// GET /api/billing/dedicated-nodes
+func (h *DedicatedNodeHandler) ListMyNodes(c *gin.Context) {
+	nodes := h.dedicatedNodeService.ListNodes(c.GetString("user_id"))
+	c.JSON(http.StatusOK, gin.H{
+		"nodes": nodes,
+		"count": len(nodes),
+	})
+}
+
+// ListNodes returns all customer-dedicated nodes (admin only)
+// GET /api/admin/dedicated-nodes
+func (h *DedicatedNodeHandler) ListNodes(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	nodes := h.dedicatedNodeService.ListNodes("")
+	c.JSON(http.StatusOK, gin.H{
+		"nodes": nodes,
+		"count": len(nodes),
+	})
+}
+
+// AssignNode reserves a node for a customer and starts billing it (admin only)
+// POST /api/admin/nodes/:node_id/dedicated
+// Body: {"owner_id": "...", "hourly_price_eur": 0.05} (price defaults to the node's hourly cost)
+func (h *DedicatedNodeHandler) AssignNode(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request struct {
+		OwnerID        string  `json:"owner_id" binding:"required"`
+		HourlyPriceEUR float64 `json:"hourly_price_eur" binding:"min=0"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	info, err := h.dedicatedNodeService.AssignNode(c.Param("node_id"), request.OwnerID, request.HourlyPriceEUR)
+	if err != nil {
+		respondDedicatedNodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Node dedicated to customer",
+		"node":    info,
+	})
+}
+
+// ReleaseNode ends a customer's node reservation and billing (admin only)
+// DELETE /api/admin/nodes/:node_id/dedicated
+func (h *DedicatedNodeHandler) ReleaseNode(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	if err := h.dedicatedNodeService.ReleaseNode(c.Param("node_id")); err != nil {
+		respondDedicatedNodeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Node released"})
+}
+
+// respondDedicatedNodeError maps dedicated node errors to HTTP responses
+func respondDedicatedNodeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrNodeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrNodeAlreadyDedicated), errors.Is(err, models.ErrNodeNotDedicated),
+		errors.Is(err, models.ErrNodeHostsOtherCustomers):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrNodeNotDedicatable), errors.Is(err, models.ErrInvalidDedicatedNodePrice):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
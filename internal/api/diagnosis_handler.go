@@ -0,0 +1,43 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// DiagnosisHandler handles the self-service server diagnosis
+type DiagnosisHandler struct {
+	diagnosisService *service.DiagnosisService
+}
+
+// NewDiagnosisHandler creates a new diagnosis handler
+func NewDiagnosisHandler(diagnosisService *service.DiagnosisService) *DiagnosisHandler {
+	return &DiagnosisHandler{diagnosisService: diagnosisService}
+}
+
+// Diagnose runs the server checks (container, memory, disk, config, status ping, proxy)
+// and returns the findings ranked by severity with suggested fixes
+// POST /api/servers/:id/diagnose
+func (h *DiagnosisHandler) Diagnose(c *gin.Context) {
+	serverID := c.Param("id")
+
+	report, err := h.diagnosisService.Diagnose(c.Request.Context(), serverID)
+	if err != nil {
+		if errors.Is(err, models.ErrServerNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "server not found"})
+			return
+		}
+		logger.Error("DIAGNOSIS: Failed to diagnose server", err, map[string]interface{}{
+			"server_id": serverID,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to diagnose server"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// DiagnosticsHandler runs the self-diagnostics of the control plane's dependencies (admin only)
+type DiagnosticsHandler struct {
+	diagnostics *service.DiagnosticsService
+}
+
+// NewDiagnosticsHandler creates a new diagnostics handler
+func NewDiagnosticsHandler(diagnostics *service.DiagnosticsService) *DiagnosticsHandler {
+	return &DiagnosticsHandler{diagnostics: diagnostics}
+}
+
+// RunDiagnostics probes every dependency now and returns its status, latency and last error
+// Probes the database, InfluxDB, the Docker socket, the Storage Box, the Velocity API, the cloud
+// provider API and SSH to a healthy worker node (or the one in ?node_id=).
+// GET /api/admin/diagnostics
+func (h *DiagnosticsHandler) RunDiagnostics(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	report, err := h.diagnostics.Run(c.Request.Context(), c.Query("node_id"))
+	switch {
+	case err == nil:
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, report)
+	case errors.Is(err, service.ErrDiagnosticsRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDiagnosticsNode):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		logger.Error("DIAGNOSTICS: Diagnostics failed", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Diagnostics failed: " + err.Error()})
+	}
+}
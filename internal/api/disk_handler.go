@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// DiskHandler handles disk usage and disk quotas of servers
+type DiskHandler struct {
+	quotaService *service.DiskQuotaService
+	audit        *service.AuditService // Optional: records quota changes
+}
+
+// NewDiskHandler creates a new disk handler
+func NewDiskHandler(quotaService *service.DiskQuotaService) *DiskHandler {
+	return &DiskHandler{quotaService: quotaService}
+}
+
+// SetAuditService sets the service that records quota changes in the audit log
+func (h *DiskHandler) SetAuditService(auditService *service.AuditService) {
+	h.audit = auditService
+}
+
+// GetDiskUsage returns the size of a server directory and its disk quota
+// GET /api/servers/:id/disk?refresh=true
+func (h *DiskHandler) GetDiskUsage(c *gin.Context) {
+	usage, err := h.quotaService.GetUsage(c.Param("id"), c.Query("refresh") == "true")
+	if err != nil {
+		respondDiskError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
+// SetDiskQuota sets the disk quota of a server (admin only, 0 = DISK_QUOTA_DEFAULT_MB)
+// PUT /api/admin/servers/:id/disk-quota
+// Body: {"quota_mb": 51200}
+func (h *DiskHandler) SetDiskQuota(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request struct {
+		QuotaMB *int `json:"quota_mb" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	previousMB, err := h.quotaService.QuotaMB(c.Param("id"))
+	if err != nil {
+		respondDiskError(c, err)
+		return
+	}
+	usage, err := h.quotaService.SetQuota(c.Param("id"), *request.QuotaMB)
+	if err != nil {
+		respondDiskError(c, err)
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionServerDiskQuota, "server", usage.ServerID,
+		gin.H{"quota_mb": previousMB}, gin.H{"quota_mb": *request.QuotaMB}))
+	c.JSON(http.StatusOK, usage)
+}
+
+// respondDiskError maps disk quota errors to HTTP status codes
+func respondDiskError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrServerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDiskQuotaInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDiskQuotaExceeded):
+		c.JSON(http.StatusInsufficientStorage, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
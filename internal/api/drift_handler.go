@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// DriftHandler shows and runs the drift detection between database, Docker and Velocity (admin only)
+type DriftHandler struct {
+	drift *service.DriftService
+}
+
+// NewDriftHandler creates a new drift handler
+func NewDriftHandler(drift *service.DriftService) *DriftHandler {
+	return &DriftHandler{drift: drift}
+}
+
+// GetDriftReport returns the drift found by the last check
+// GET /api/admin/drift
+func (h *DriftHandler) GetDriftReport(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	report := h.drift.LastReport()
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No drift check has run yet"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// CheckDrift compares the state now. Drift also found by the previous check is healed if
+// DRIFT_AUTO_HEAL is on, so a second call confirms and heals what the first one found.
+// POST /api/admin/drift/check
+func (h *DriftHandler) CheckDrift(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	report, err := h.drift.Check(c.Request.Context())
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, report)
+	case errors.Is(err, service.ErrDriftCheckRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logger.Error("DRIFT: Drift check failed", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Drift check failed: " + err.Error()})
+	}
+}
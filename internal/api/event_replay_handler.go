@@ -0,0 +1,67 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// EventReplayHandler handles admin replay of stored events
+type EventReplayHandler struct {
+	replayService *service.EventReplayService
+}
+
+// NewEventReplayHandler creates a new event replay handler
+func NewEventReplayHandler(replayService *service.EventReplayService) *EventReplayHandler {
+	return &EventReplayHandler{replayService: replayService}
+}
+
+// ListReplayTargets returns the event sources and replay targets (admin only)
+// GET /api/admin/events/replay
+func (h *EventReplayHandler) ListReplayTargets(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sources": h.replayService.Sources(),
+		"targets": h.replayService.Targets(),
+	})
+}
+
+// ReplayEvents replays stored events into a target (admin only)
+// POST /api/admin/events/replay
+// Body: {"source": "postgres", "target": "billing", "types": ["server.stopped"], "server_id": "...",
+//
+//	"start": "2026-01-01T00:00:00Z", "end": "2026-01-02T00:00:00Z", "limit": 10000, "dry_run": true}
+func (h *EventReplayHandler) ReplayEvents(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request service.ReplayRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	result, err := h.replayService.Replay(request)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReplayUnknownSource), errors.Is(err, service.ErrReplayUnknownTarget),
+			errors.Is(err, service.ErrReplayInvalidRange), errors.Is(err, service.ErrReplayInvalidType):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrReplayInProgress):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -94,7 +95,11 @@ func (h *FileHandler) UploadFile(c *gin.Context) {
 			"file_type": fileType,
 			"user_id":   userID,
 		})
-		c.JSON(http.StatusBadRequest, gin.H{
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrDiskQuotaExceeded) {
+			status = http.StatusInsufficientStorage
+		}
+		c.JSON(status, gin.H{
 			"error": fmt.Sprintf("Upload failed: %v", err),
 		})
 		return
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/payperplay/hosting/internal/service"
@@ -103,3 +105,257 @@ func (h *FileManagerHandler) ListFiles(c *gin.Context) {
 		"count": len(files),
 	})
 }
+
+// DeleteFilesRequest lists the paths to delete
+type DeleteFilesRequest struct {
+	Paths []string `json:"paths" binding:"required,min=1"`
+}
+
+// DeleteFiles deletes files and folders (recursively); each path succeeds or fails on its own
+// POST /api/servers/:id/files/delete
+// Body: {"paths": ["plugins/OldPlugin.jar", "logs"]}
+func (h *FileManagerHandler) DeleteFiles(c *gin.Context) {
+	var req DeleteFilesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.service.DeletePaths(c.Param("id"), req.Paths)
+	if err != nil {
+		respondFileManagerError(c, err)
+		return
+	}
+	respondFileOpResults(c, results)
+}
+
+// MoveFilesRequest lists the paths to move and the folder to move them into
+type MoveFilesRequest struct {
+	Paths       []string `json:"paths" binding:"required,min=1"`
+	Destination string   `json:"destination"` // Folder, "" = server directory
+}
+
+// MoveFiles moves files and folders into a folder without overwriting existing files
+// POST /api/servers/:id/files/move
+// Body: {"paths": ["MyPlugin.jar"], "destination": "plugins"}
+func (h *FileManagerHandler) MoveFiles(c *gin.Context) {
+	var req MoveFilesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.service.MovePaths(c.Param("id"), req.Paths, req.Destination)
+	if err != nil {
+		respondFileManagerError(c, err)
+		return
+	}
+	respondFileOpResults(c, results)
+}
+
+// ChmodRequest sets the permissions of a path
+type ChmodRequest struct {
+	Path string `json:"path" binding:"required"`
+	Mode string `json:"mode" binding:"required"` // Octal, e.g. "644"
+}
+
+// ChmodFile sets the permission bits of a file or folder
+// POST /api/servers/:id/files/chmod
+// Body: {"path": "start.sh", "mode": "755"}
+func (h *FileManagerHandler) ChmodFile(c *gin.Context) {
+	var req ChmodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ChangeMode(c.Param("id"), req.Path, req.Mode); err != nil {
+		respondFileManagerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"path": req.Path,
+		"mode": req.Mode,
+	})
+}
+
+// CompressRequest lists the paths to put into a new ZIP archive
+type CompressRequest struct {
+	Paths  []string `json:"paths" binding:"required,min=1"`
+	Target string   `json:"target" binding:"required"` // Path of the new archive, must end in .zip
+}
+
+// CompressFiles creates a ZIP archive of files and folders
+// POST /api/servers/:id/files/compress
+// Body: {"paths": ["plugins/Essentials", "plugins/LuckPerms"], "target": "plugin-configs.zip"}
+func (h *FileManagerHandler) CompressFiles(c *gin.Context) {
+	var req CompressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	archive, err := h.service.Compress(c.Param("id"), req.Paths, req.Target)
+	if err != nil {
+		respondFileManagerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"path":    req.Target,
+		"archive": archive,
+	})
+}
+
+// ExtractRequest names a ZIP archive and the folder to extract it into
+type ExtractRequest struct {
+	Path        string `json:"path" binding:"required"` // The ZIP archive
+	Destination string `json:"destination"`             // Folder, "" = server directory
+	Overwrite   bool   `json:"overwrite"`               // Replace existing files instead of refusing
+}
+
+// ExtractArchive extracts a ZIP archive
+// Nothing is written if an entry would leave the destination, touch a protected path (server.jar,
+// eula.txt, ...) or replace an existing file without overwrite.
+// POST /api/servers/:id/files/extract
+// Body: {"path": "plugin-configs.zip", "destination": "plugins", "overwrite": false}
+func (h *FileManagerHandler) ExtractArchive(c *gin.Context) {
+	var req ExtractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	extracted, err := h.service.Extract(c.Param("id"), req.Path, req.Destination, req.Overwrite)
+	if err != nil {
+		respondFileManagerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"path":        req.Path,
+		"destination": req.Destination,
+		"files":       extracted,
+	})
+}
+
+// CreateUploadRequest declares a resumable upload
+type CreateUploadRequest struct {
+	Path      string `json:"path" binding:"required"`
+	Size      int64  `json:"size" binding:"required,min=1"`
+	Overwrite bool   `json:"overwrite"`
+}
+
+// CreateUpload starts a resumable upload for a large file (mods, worlds)
+// Send the file in chunks with PATCH .../files/uploads/:upload_id and an Upload-Offset header.
+// After an interruption, HEAD or GET the upload for the current Upload-Offset and continue there.
+// POST /api/servers/:id/files/uploads
+// Body: {"path": "mods/create-0.5.1.jar", "size": 15728640, "overwrite": false}
+func (h *FileManagerHandler) CreateUpload(c *gin.Context) {
+	var req CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	upload, err := h.service.CreateUpload(c.Param("id"), req.Path, req.Size, req.Overwrite)
+	if err != nil {
+		respondFileManagerError(c, err)
+		return
+	}
+
+	c.Header("Location", c.Request.URL.Path+"/"+upload.ID)
+	setUploadHeaders(c, upload)
+	c.JSON(http.StatusCreated, upload)
+}
+
+// GetUpload returns the offset of an unfinished upload (also in the Upload-Offset header)
+// GET /api/servers/:id/files/uploads/:upload_id
+func (h *FileManagerHandler) GetUpload(c *gin.Context) {
+	upload, err := h.service.GetUpload(c.Param("id"), c.Param("upload_id"))
+	if err != nil {
+		respondFileManagerError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	setUploadHeaders(c, upload)
+	c.JSON(http.StatusOK, upload)
+}
+
+// AppendUpload writes the request body as the next chunk of an upload
+// Upload-Offset must equal the bytes received so far (409 with the current offset otherwise).
+// The file is moved to its path with the last chunk; the response then has completed set.
+// PATCH /api/servers/:id/files/uploads/:upload_id
+func (h *FileManagerHandler) AppendUpload(c *gin.Context) {
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Offset header is required"})
+		return
+	}
+
+	upload, err := h.service.AppendUpload(c.Param("id"), c.Param("upload_id"), offset, c.Request.Body)
+	if upload != nil {
+		setUploadHeaders(c, upload)
+	}
+	if err != nil {
+		respondFileManagerError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, upload)
+}
+
+// CancelUpload discards an unfinished upload
+// DELETE /api/servers/:id/files/uploads/:upload_id
+func (h *FileManagerHandler) CancelUpload(c *gin.Context) {
+	if err := h.service.CancelUpload(c.Param("id"), c.Param("upload_id")); err != nil {
+		respondFileManagerError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// setUploadHeaders sets the tus-style offset and length headers of an upload
+func setUploadHeaders(c *gin.Context, upload *service.FileUpload) {
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(upload.Size, 10))
+}
+
+// respondFileOpResults answers a bulk operation with the result of every path
+func respondFileOpResults(c *gin.Context, results []service.FileOpResult) {
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+		"failed":  failed,
+	})
+}
+
+// respondFileManagerError maps file manager errors to HTTP status codes
+func respondFileManagerError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrFileNotFound), errors.Is(err, service.ErrUploadNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFileProtected):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFileExists),
+		errors.Is(err, service.ErrUploadOffsetMismatch),
+		errors.Is(err, service.ErrUploadBusy):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFileArchiveTooBig), errors.Is(err, service.ErrUploadTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDiskQuotaExceeded):
+		c.JSON(http.StatusInsufficientStorage, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFilePathInvalid),
+		errors.Is(err, service.ErrFileModeInvalid),
+		errors.Is(err, service.ErrFileNotArchive):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
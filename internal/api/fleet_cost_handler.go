@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// fleetCostTimelineDays is the default range of a node cost timeline
+const fleetCostTimelineDays = 30
+
+// FleetCostHandler serves the fleet cost dashboard data to admins: the estimated cost of the cloud
+// nodes reconciled with the provider's billing and the per-node cost timelines
+type FleetCostHandler struct {
+	fleetCost *service.FleetCostService
+}
+
+// NewFleetCostHandler creates a new fleet cost handler
+func NewFleetCostHandler(fleetCost *service.FleetCostService) *FleetCostHandler {
+	return &FleetCostHandler{fleetCost: fleetCost}
+}
+
+// GetFleetCosts returns the last reconciliation, running one if there is none yet (admin only)
+// GET /api/billing/fleet
+func (h *FleetCostHandler) GetFleetCosts(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	if report := h.fleetCost.LastReport(); report != nil {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	h.reconcile(c)
+}
+
+// ReconcileFleetCosts reconciles the fleet's cost estimate with the provider's billing now (admin only)
+// POST /api/billing/fleet/reconcile
+func (h *FleetCostHandler) ReconcileFleetCosts(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+	h.reconcile(c)
+}
+
+func (h *FleetCostHandler) reconcile(c *gin.Context) {
+	report, err := h.fleetCost.Reconcile()
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, report)
+	case errors.Is(err, service.ErrFleetCostRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrFleetCostUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		logger.Error("FLEET-COST: Reconciliation failed", err, nil)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Fleet cost reconciliation failed: " + err.Error()})
+	}
+}
+
+// GetNodeCostTimeline returns the hourly cost samples of one node, estimated and billed (admin only)
+// GET /api/billing/fleet/nodes/:node_id/timeline
+// Supports ?from / ?to (RFC 3339 or YYYY-MM-DD, "to" exclusive); defaults to the last 30 days.
+func (h *FleetCostHandler) GetNodeCostTimeline(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	from, ok := parseAuditTime(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseAuditTime(c, "to")
+	if !ok {
+		return
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -fleetCostTimelineDays)
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'from' must be before 'to'"})
+		return
+	}
+
+	nodeID := c.Param("node_id")
+	samples, err := h.fleetCost.Timeline(nodeID, from, to)
+	if err != nil {
+		logger.Error("FLEET-COST: Failed to load node cost timeline", err, map[string]interface{}{
+			"node_id": nodeID,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load node cost timeline"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"node_id": nodeID,
+		"from":    from,
+		"to":      to,
+		"samples": samples,
+		"count":   len(samples),
+	})
+}
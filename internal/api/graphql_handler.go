@@ -0,0 +1,407 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/graphql"
+	"github.com/payperplay/hosting/internal/middleware"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	graphQLProtocol        = "graphql-transport-ws"
+	graphQLInitTimeout     = 10 * time.Second
+	graphQLEventBuffer     = 64 // Per-subscription event queue; events are dropped for slow subscribers
+	graphQLMaxSubscription = 32 // Per connection
+)
+
+// graphQLEventTypes are the events streamed by the serverEvents subscription
+var graphQLEventTypes = []events.EventType{
+	events.EventServerStarted, events.EventServerStartFailed, events.EventServerStopped,
+	events.EventServerCrashed, events.EventServerRestarted, events.EventServerStateChanged, events.EventServerDeleted,
+	events.EventPlayerJoined, events.EventPlayerLeft, events.EventPlayerCountChanged,
+	events.EventBackupCreated, events.EventBackupRestored, events.EventBackupDeleted, events.EventBackupFailed,
+	events.EventWorldPregenerated,
+	events.EventBillingStarted, events.EventBillingStopped, events.EventBillingPhaseChanged, events.EventBillingBudgetAlert,
+}
+
+// GraphQLHandler serves the GraphQL API: composite read views over the REST services in one round trip
+// (POST /api/graphql) and event subscriptions over WebSocket (GET /api/graphql, graphql-transport-ws).
+// Objects have the same field names as the REST responses plus relation fields (backups, usage, ...).
+type GraphQLHandler struct {
+	mcService         *service.MinecraftService
+	backupRepo        *repository.BackupRepository
+	pluginService     *service.PluginService
+	playerListService *service.PlayerListService
+	schema            *graphql.Schema
+	upgrader          websocket.Upgrader
+
+	subsMu sync.Mutex
+	subs   map[*graphQLSubscription]struct{}
+}
+
+// graphQLSubscription is an active serverEvents subscription
+type graphQLSubscription struct {
+	serverID string
+	types    map[events.EventType]bool // Empty = all
+	events   chan interface{}
+}
+
+// graphQLContextKey carries the request's gin context to resolvers (user, admin flag, API key)
+type graphQLContextKey struct{}
+
+// NewGraphQLHandler creates a new GraphQL handler
+func NewGraphQLHandler(
+	mcService *service.MinecraftService,
+	backupRepo *repository.BackupRepository,
+	pluginService *service.PluginService,
+	playerListService *service.PlayerListService,
+) *GraphQLHandler {
+	h := &GraphQLHandler{
+		mcService:         mcService,
+		backupRepo:        backupRepo,
+		pluginService:     pluginService,
+		playerListService: playerListService,
+		upgrader:          createUpgrader(true),
+		subs:              make(map[*graphQLSubscription]struct{}),
+	}
+	h.upgrader.Subprotocols = []string{graphQLProtocol}
+	h.schema = h.buildSchema()
+
+	bus := events.GetEventBus()
+	for _, eventType := range graphQLEventTypes {
+		bus.SubscribeAll(eventType, h.publish)
+	}
+	return h
+}
+
+// buildSchema defines the query and subscription types
+func (h *GraphQLHandler) buildSchema() *graphql.Schema {
+	server := &graphql.Object{
+		Name: "Server",
+		Fields: map[string]*graphql.Field{
+			"backups": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return h.backupRepo.FindByServerID(p.Source.(*models.MinecraftServer).ID)
+			}},
+			"usage": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return h.mcService.GetServerUsage(p.Source.(*models.MinecraftServer).ID)
+			}},
+			"plugins": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return h.pluginService.ListInstalledPlugins(p.Source.(*models.MinecraftServer).ID)
+			}},
+			"onlinePlayers": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return h.playerListService.GetOnlinePlayers(p.Source.(*models.MinecraftServer).ID)
+			}},
+			"playerHistory": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return h.playerListService.GetHistoricPlayers(p.Source.(*models.MinecraftServer).ID)
+			}},
+		},
+	}
+
+	query := &graphql.Object{
+		Name: "Query",
+		Fields: map[string]*graphql.Field{
+			// server(id: ID!): the server with its relations
+			"server": {Type: server, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				serverID := p.String("id")
+				if err := authorizeGraphQLServer(p.Context, serverID, models.PermServerView); err != nil {
+					return nil, err
+				}
+				return h.mcService.GetServer(serverID)
+			}},
+			// servers: the servers of the current user (organization API keys: the organization's servers)
+			"servers": {Type: server, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				c := graphQLGinContext(p.Context)
+				servers, err := h.mcService.ListServers(c.GetString("user_id"))
+				if err != nil {
+					return nil, err
+				}
+				key := middleware.CurrentAPIKey(c)
+				result := make([]*models.MinecraftServer, 0, len(servers))
+				for i := range servers {
+					if key != nil && key.OrganizationID != "" && servers[i].OrganizationID != key.OrganizationID {
+						continue
+					}
+					result = append(result, &servers[i])
+				}
+				return result, nil
+			}},
+		},
+	}
+
+	subscription := &graphql.Object{
+		Name: "Subscription",
+		Fields: map[string]*graphql.Field{
+			// serverEvents(serverId: ID!, types: [String]): lifecycle, player, backup and billing events of a server
+			"serverEvents": {Subscribe: h.subscribeServerEvents},
+		},
+	}
+
+	return &graphql.Schema{Query: query, Subscription: subscription}
+}
+
+// Query executes a GraphQL query
+// POST /api/graphql
+// Body: {"query": "...", "operationName": "...", "variables": {...}}
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphql.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), graphQLContextKey{}, c)
+	c.JSON(http.StatusOK, h.schema.Execute(ctx, &req))
+}
+
+// graphQLMessage is a graphql-transport-ws protocol message
+type graphQLMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// HandleWebSocket serves subscriptions (and queries) over the graphql-transport-ws protocol
+// GET /api/graphql (WebSocket, token query parameter)
+func (h *GraphQLHandler) HandleWebSocket(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Error("GRAPHQL: WebSocket upgrade failed", err, nil)
+		return
+	}
+	defer conn.Close()
+	defer trackWebSocket(conn)()
+
+	if conn.Subprotocol() != graphQLProtocol {
+		closeGraphQLSocket(conn, 4406, "Subprotocol not acceptable")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(c.Request.Context(), graphQLContextKey{}, c))
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait() // Resolvers use the gin context, which is reused once the handler returns
+	}()
+
+	var writeMu sync.Mutex
+	send := func(msg graphQLMessage) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := conn.WriteJSON(msg); err != nil {
+			cancel()
+		}
+	}
+
+	var opsMu sync.Mutex
+	operations := map[string]context.CancelFunc{}
+	initialized := false
+	conn.SetReadDeadline(time.Now().Add(graphQLInitTimeout))
+
+	for {
+		var msg graphQLMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if !initialized {
+				closeGraphQLSocket(conn, 4408, "Connection initialisation timeout")
+			}
+			return
+		}
+
+		switch msg.Type {
+		case "connection_init":
+			if initialized {
+				closeGraphQLSocket(conn, 4429, "Too many initialisation requests")
+				return
+			}
+			initialized = true
+			conn.SetReadDeadline(time.Time{})
+			send(graphQLMessage{Type: "connection_ack"})
+
+		case "ping":
+			send(graphQLMessage{Type: "pong"})
+
+		case "pong":
+
+		case "subscribe":
+			if !initialized {
+				closeGraphQLSocket(conn, 4401, "Unauthorized")
+				return
+			}
+			var req graphql.Request
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil {
+				closeGraphQLSocket(conn, 4400, "Invalid subscribe message")
+				return
+			}
+
+			opsMu.Lock()
+			_, exists := operations[msg.ID]
+			tooMany := len(operations) >= graphQLMaxSubscription
+			opCtx, opCancel := context.WithCancel(ctx)
+			if !exists && !tooMany {
+				operations[msg.ID] = opCancel
+			}
+			opsMu.Unlock()
+			if exists {
+				opCancel()
+				closeGraphQLSocket(conn, 4409, "Subscriber for "+msg.ID+" already exists")
+				return
+			}
+			if tooMany {
+				opCancel()
+				sendGraphQLErrors(send, msg.ID, errors.New("too many active subscriptions"))
+				continue
+			}
+
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				defer func() {
+					opsMu.Lock()
+					delete(operations, id)
+					opsMu.Unlock()
+					opCancel()
+				}()
+				h.runOperation(opCtx, id, &req, send)
+			}(msg.ID)
+
+		case "complete":
+			opsMu.Lock()
+			if opCancel, ok := operations[msg.ID]; ok {
+				opCancel()
+				delete(operations, msg.ID)
+			}
+			opsMu.Unlock()
+
+		default:
+			closeGraphQLSocket(conn, 4400, "Unknown message type "+msg.Type)
+			return
+		}
+	}
+}
+
+// runOperation executes a query or streams a subscription until it ends or the client completes it
+func (h *GraphQLHandler) runOperation(ctx context.Context, id string, req *graphql.Request, send func(graphQLMessage)) {
+	opType, err := h.schema.OperationType(req)
+	if err != nil {
+		sendGraphQLErrors(send, id, err)
+		return
+	}
+
+	// Queries over the socket yield a single result
+	if opType == "query" {
+		sendGraphQLResult(send, id, h.schema.Execute(ctx, req))
+		send(graphQLMessage{ID: id, Type: "complete"})
+		return
+	}
+
+	responses, err := h.schema.Subscribe(ctx, req)
+	if err != nil {
+		sendGraphQLErrors(send, id, err)
+		return
+	}
+	for response := range responses {
+		sendGraphQLResult(send, id, response)
+	}
+	if ctx.Err() == nil {
+		send(graphQLMessage{ID: id, Type: "complete"})
+	}
+}
+
+// subscribeServerEvents streams the events of a server the user may view
+func (h *GraphQLHandler) subscribeServerEvents(p graphql.ResolveParams) (<-chan interface{}, error) {
+	serverID := p.String("serverId")
+	if err := authorizeGraphQLServer(p.Context, serverID, models.PermServerView); err != nil {
+		return nil, err
+	}
+
+	sub := &graphQLSubscription{
+		serverID: serverID,
+		types:    map[events.EventType]bool{},
+		events:   make(chan interface{}, graphQLEventBuffer),
+	}
+	if types, ok := p.Args["types"].([]interface{}); ok {
+		for _, t := range types {
+			if name, ok := t.(string); ok {
+				sub.types[events.EventType(name)] = true
+			}
+		}
+	}
+
+	h.subsMu.Lock()
+	h.subs[sub] = struct{}{}
+	h.subsMu.Unlock()
+
+	go func() {
+		<-p.Context.Done()
+		h.subsMu.Lock()
+		delete(h.subs, sub)
+		close(sub.events)
+		h.subsMu.Unlock()
+	}()
+
+	return sub.events, nil
+}
+
+// publish delivers an event bus event to the matching subscriptions
+func (h *GraphQLHandler) publish(event events.Event) {
+	h.subsMu.Lock()
+	defer h.subsMu.Unlock()
+
+	for sub := range h.subs {
+		if sub.serverID != event.ServerID || (len(sub.types) > 0 && !sub.types[event.Type]) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			logger.Warn("GRAPHQL: Subscriber too slow, dropping event", map[string]interface{}{
+				"server_id":  event.ServerID,
+				"event_type": event.Type,
+			})
+		}
+	}
+}
+
+// authorizeGraphQLServer checks a server permission of the request's user
+func authorizeGraphQLServer(ctx context.Context, serverID string, perm models.ServerPermission) error {
+	if serverID == "" {
+		return errors.New("server id is required")
+	}
+	return middleware.AuthorizeServerAccess(graphQLGinContext(ctx), serverID, perm)
+}
+
+// graphQLGinContext returns the gin context of the GraphQL request
+func graphQLGinContext(ctx context.Context) *gin.Context {
+	c, _ := ctx.Value(graphQLContextKey{}).(*gin.Context)
+	return c
+}
+
+func sendGraphQLResult(send func(graphQLMessage), id string, response *graphql.Response) {
+	payload, err := json.Marshal(response)
+	if err != nil {
+		sendGraphQLErrors(send, id, err)
+		return
+	}
+	send(graphQLMessage{ID: id, Type: "next", Payload: payload})
+}
+
+func sendGraphQLErrors(send func(graphQLMessage), id string, err error) {
+	payload, _ := json.Marshal([]*graphql.Error{{Message: err.Error()}})
+	send(graphQLMessage{ID: id, Type: "error", Payload: payload})
+}
+
+func closeGraphQLSocket(conn *websocket.Conn, code int, reason string) {
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+}
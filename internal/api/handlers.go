@@ -1,30 +1,53 @@
 package api
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"regexp"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/middleware"
 	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/service"
 )
 
 type Handler struct {
-	mcService *service.MinecraftService
+	mcService    *service.MinecraftService
+	cloneService *service.CloneService // Optional: creates servers from templates
+	audit        *service.AuditService // Optional: records deletions and admin changes
 }
 
 func NewHandler(mcService *service.MinecraftService) *Handler {
 	return &Handler{mcService: mcService}
 }
 
+// SetCloneService sets the service that creates servers from templates (template_id)
+func (h *Handler) SetCloneService(cloneService *service.CloneService) {
+	h.cloneService = cloneService
+}
+
+// SetAuditService sets the service that records deletions and admin changes in the audit log
+func (h *Handler) SetAuditService(auditService *service.AuditService) {
+	h.audit = auditService
+}
+
+// serverNameRegex is the allowed format of server names (letters, numbers, dashes, underscores)
+var serverNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{3,32}$`)
+
+const errInvalidServerName = "Server name must be 3-32 characters and contain only letters, numbers, dashes, and underscores"
+
 // CreateServerRequest represents the request body for creating a server
+// With template_id, type, version and RAM come from the template (ram_mb may still override the RAM).
 type CreateServerRequest struct {
 	Name             string `json:"name" binding:"required"`
-	ServerType       string `json:"server_type" binding:"required"`
-	MinecraftVersion string `json:"minecraft_version" binding:"required"`
-	RAMMb            int    `json:"ram_mb" binding:"required,min=1024"`
+	ServerType       string `json:"server_type" binding:"required_without=TemplateID"`
+	MinecraftVersion string `json:"minecraft_version" binding:"required_without=TemplateID"`
+	RAMMb            int    `json:"ram_mb" binding:"required_without=TemplateID,omitempty,min=1024"`
+	TemplateID       string `json:"template_id"`       // Built-in, own or published user template
+	TemplateRevision int    `json:"template_revision"` // Revision of a user template (0 = current)
 }
 
 // CreateServer handles POST /api/servers
@@ -36,11 +59,13 @@ func (h *Handler) CreateServer(c *gin.Context) {
 	}
 
 	// FIX SERVER-5: Validate server name (alphanumeric, dash, underscore only)
-	serverNameRegex := regexp.MustCompile(`^[a-zA-Z0-9_-]{3,32}$`)
 	if !serverNameRegex.MatchString(req.Name) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Server name must be 3-32 characters and contain only letters, numbers, dashes, and underscores",
-		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidServerName})
+		return
+	}
+
+	if req.TemplateID != "" {
+		h.createServerFromTemplate(c, req)
 		return
 	}
 
@@ -106,7 +131,41 @@ func (h *Handler) CreateServer(c *gin.Context) {
 	})
 }
 
-// ListServers handles GET /api/servers
+// createServerFromTemplate creates a server from a built-in or user template
+// Templates with a world or plugins answer 202 with the operation that sets them up.
+func (h *Handler) createServerFromTemplate(c *gin.Context, req CreateServerRequest) {
+	if h.cloneService == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": service.ErrUserTemplatesDisabled.Error()})
+		return
+	}
+
+	server, op, err := h.cloneService.CreateFromTemplate(c.GetString("user_id"), req.Name, req.TemplateID, req.TemplateRevision, req.RAMMb)
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		respondOperationError(c, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if op != nil {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, gin.H{
+		"server":                 server,
+		"operation":              op,
+		"estimated_hourly_cost":  server.GetHourlyRate(),
+		"estimated_monthly_cost": server.GetMonthlyRate(),
+		"billing_plan":           server.Plan,
+		"tier":                   server.RAMTier,
+	})
+}
+
+// ListServers lists the servers the user can access
+// GET /api/servers
+// Supports ?cursor, ?limit, ?sort and the status/node/type filters; totals are in X-Total-Count.
 func (h *Handler) ListServers(c *gin.Context) {
 	// Get owner ID from auth context
 	ownerID, exists := c.Get("user_id")
@@ -115,13 +174,25 @@ func (h *Handler) ListServers(c *gin.Context) {
 		return
 	}
 
-	servers, err := h.mcService.ListServers(ownerID.(string))
+	page, err := parsePageRequest(c, 0, "status", "node", "type")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Organization API keys only see the organization's servers
+	if key := middleware.CurrentAPIKey(c); key != nil && key.OrganizationID != "" {
+		page.Filters["organization_id"] = []string{key.OrganizationID}
+	}
+
+	servers, err := h.mcService.ListServersPage(ownerID.(string), page)
+	if err != nil {
+		respondPageError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, servers)
+	setPageHeaders(c, servers.Total, servers.NextCursor)
+	c.JSON(http.StatusOK, servers.Items)
 }
 
 // GetServer handles GET /api/servers/:id
@@ -155,10 +226,19 @@ func (h *Handler) GetServerConnectionInfo(c *gin.Context) {
 func (h *Handler) StartServer(c *gin.Context) {
 	serverID := c.Param("id")
 
-	err := h.mcService.StartServer(serverID)
+	op, err := h.mcService.RequestStart(c.Request.Context(), serverID, c.GetString("user_id"))
 	if err != nil {
 		log.Printf("ERROR starting server %s: %v", serverID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondOperationError(c, err)
+		return
+	}
+
+	// Owner is at the concurrency limit of their plan: start runs once a slot is free
+	if op.IsQueued() {
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":   "server start queued",
+			"operation": op,
+		})
 		return
 	}
 
@@ -169,7 +249,7 @@ func (h *Handler) StartServer(c *gin.Context) {
 func (h *Handler) StopServer(c *gin.Context) {
 	serverID := c.Param("id")
 
-	err := h.mcService.StopServer(serverID, "manual")
+	err := h.mcService.StopServerContext(c.Request.Context(), serverID, "manual")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -182,15 +262,51 @@ func (h *Handler) StopServer(c *gin.Context) {
 func (h *Handler) DeleteServer(c *gin.Context) {
 	serverID := c.Param("id")
 
+	var before interface{}
+	if server, err := h.mcService.GetServer(serverID); err == nil {
+		before = gin.H{"name": server.Name, "owner_id": server.OwnerID, "status": server.Status, "node_id": server.NodeID}
+	}
+
 	err := h.mcService.DeleteServer(serverID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.audit.Record(auditEntry(c, audit.ActionServerDelete, "server", serverID, before, nil))
 
 	c.JSON(http.StatusOK, gin.H{"message": "server deleted"})
 }
 
+// UpgradeServerRAM handles POST /api/servers/:id/ram
+// Body: {"ram_mb": 4096} (a running server is restarted)
+func (h *Handler) UpgradeServerRAM(c *gin.Context) {
+	serverID := c.Param("id")
+
+	var req struct {
+		RAMMb int `json:"ram_mb" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var before interface{}
+	if server, err := h.mcService.GetServer(serverID); err == nil {
+		before = gin.H{"ram_mb": server.RAMMb}
+	}
+
+	if err := h.mcService.UpgradeServerRAM(serverID, req.RAMMb); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionServerRAM, "server", serverID, before, gin.H{"ram_mb": req.RAMMb}))
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "server RAM updated",
+		"ram_mb":  req.RAMMb,
+	})
+}
+
 // GetServerUsage handles GET /api/servers/:id/usage
 func (h *Handler) GetServerUsage(c *gin.Context) {
 	serverID := c.Param("id")
@@ -214,7 +330,7 @@ func (h *Handler) GetServerLogs(c *gin.Context) {
 		tail = 100
 	}
 
-	logs, err := h.mcService.GetServerLogs(serverID, tail)
+	logs, err := h.mcService.GetServerLogs(c.Request.Context(), serverID, tail)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -223,15 +339,272 @@ func (h *Handler) GetServerLogs(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"logs": logs})
 }
 
-// ListAllServers handles GET /api/admin/servers (shows ALL servers, not filtered by owner)
+// ListAllServers lists the servers of all owners, including deleted ones (admin only)
+// GET /api/admin/servers
+// Supports the same paging, sorting and filters as ListServers.
 func (h *Handler) ListAllServers(c *gin.Context) {
-	servers, err := h.mcService.ListAllServers()
+	page, err := parsePageRequest(c, 0, "status", "node", "type")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	servers, err := h.mcService.ListAllServersPage(page)
+	if err != nil {
+		respondPageError(c, err)
+		return
+	}
+
+	setPageHeaders(c, servers.Total, servers.NextCursor)
+	c.JSON(http.StatusOK, servers.Items)
+}
+
+// SetServerPlacement sets which nodes a server may run on (admin only)
+// PUT /api/admin/servers/:id/placement
+// Body: {"node_selector": "dedicated-customer=acme", "tolerations": "dedicated-customer=acme", "pinned_node_id": ""}
+func (h *Handler) SetServerPlacement(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request struct {
+		NodeSelector string `json:"node_selector"`
+		Tolerations  string `json:"tolerations"`
+		PinnedNodeID string `json:"pinned_node_id"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	previous, err := h.mcService.GetServer(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "server not found"})
+		return
+	}
+	before := previous.Placement()
+
+	server, err := h.mcService.SetServerPlacement(c.Param("id"), request.NodeSelector, request.Tolerations, request.PinnedNodeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidNodeLabel), errors.Is(err, models.ErrInvalidNodeToleration), errors.Is(err, models.ErrInvalidPinnedNode):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	h.audit.Record(auditEntry(c, audit.ActionServerPlacement, "server", server.ID, before, server.Placement()))
+
+	c.JSON(http.StatusOK, gin.H{
+		"server_id": server.ID,
+		"placement": server.Placement(),
+	})
+}
+
+// SetServerCPULimits sets the CPU weight and cap of a server container (admin only, 0 = configured default)
+// PUT /api/admin/servers/:id/cpu
+// Body: {"cpu_shares": 2048, "cpu_limit_cores": 2}
+func (h *Handler) SetServerCPULimits(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request struct {
+		CPUShares     int     `json:"cpu_shares"`
+		CPULimitCores float64 `json:"cpu_limit_cores"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	previous, err := h.mcService.GetServer(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "server not found"})
+		return
+	}
+	before := gin.H{"cpu_shares": previous.CPUShares, "cpu_limit_cores": previous.CPULimitCores}
+
+	server, err := h.mcService.SetServerCPULimits(c.Param("id"), request.CPUShares, request.CPULimitCores)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidCPUShares), errors.Is(err, models.ErrInvalidCPULimit):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	h.audit.Record(auditEntry(c, audit.ActionServerCPULimits, "server", server.ID, before,
+		gin.H{"cpu_shares": server.CPUShares, "cpu_limit_cores": server.CPULimitCores}))
+
+	c.JSON(http.StatusOK, gin.H{
+		"server_id":       server.ID,
+		"cpu_shares":      server.CPUShares,
+		"cpu_limit_cores": server.CPULimitCores,
+	})
+}
+
+// SuspendServer puts a server on hold for abuse or billing (admin only)
+// The server is stopped and unregistered from Velocity, starts are rejected with the reason.
+// POST /api/admin/servers/:id/suspend
+// Body: {"reason": "Reported for DDoS attacks from the server"}
+func (h *Handler) SuspendServer(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+		return
+	}
+
+	previous, err := h.mcService.GetServer(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "server not found"})
+		return
+	}
+
+	server, err := h.mcService.SuspendServer(c.Request.Context(), previous.ID, request.Reason, service.SuspensionSourceAdmin, c.GetString("user_id"))
 	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSuspensionReasonMissing):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrServerAlreadySuspended), errors.Is(err, service.ErrSuspensionInTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	h.audit.Record(auditEntry(c, audit.ActionServerSuspend, "server", server.ID,
+		gin.H{"status": previous.Status},
+		gin.H{"status": server.Status, "reason": server.SuspensionReason}))
+
+	c.JSON(http.StatusOK, suspensionResponse(server))
+}
+
+// UnsuspendServer lifts the suspension of a server (admin only), it returns to stopped
+// POST /api/admin/servers/:id/unsuspend
+func (h *Handler) UnsuspendServer(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	previous, err := h.mcService.GetServer(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "server not found"})
+		return
+	}
+
+	server, err := h.mcService.UnsuspendServer(previous.ID, "")
+	if err != nil {
+		if errors.Is(err, service.ErrServerNotSuspended) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, servers)
+	h.audit.Record(auditEntry(c, audit.ActionServerUnsuspend, "server", server.ID,
+		gin.H{"status": previous.Status, "reason": previous.SuspensionReason, "source": previous.SuspensionSource},
+		gin.H{"status": server.Status}))
+
+	c.JSON(http.StatusOK, suspensionResponse(server))
+}
+
+// suspensionResponse is the suspension state of a server returned by the admin endpoints
+func suspensionResponse(server *models.MinecraftServer) gin.H {
+	return gin.H{
+		"server_id":    server.ID,
+		"status":       server.Status,
+		"suspended_at": server.SuspendedAt,
+		"reason":       server.SuspensionReason,
+		"source":       server.SuspensionSource,
+		"suspended_by": server.SuspendedBy,
+	}
+}
+
+// GetReleaseChannel handles GET /api/servers/:id/release-channel
+func (h *Handler) GetReleaseChannel(c *gin.Context) {
+	server, err := h.mcService.GetServer(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "server not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.mcService.GetReleaseChannel(server))
+}
+
+// SetServerAffinity keeps a server off the nodes of the owner's other servers
+// (e.g. a network's lobby and game servers in one anti-affinity group)
+// PUT /api/servers/:id/affinity
+// Body: {"anti_affinity_group": "network", "spread": false}
+func (h *Handler) SetServerAffinity(c *gin.Context) {
+	var request struct {
+		AntiAffinityGroup string `json:"anti_affinity_group"`
+		Spread            bool   `json:"spread"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	server, err := h.mcService.SetServerAffinity(c.Param("id"), request.AntiAffinityGroup, request.Spread)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidAffinityGroup) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"server_id":           server.ID,
+		"anti_affinity_group": server.AntiAffinityGroup,
+		"spread":              server.SpreadAcrossNodes,
+	})
+}
+
+// SetReleaseChannel opts a server into the stable or beta channel for managed changes
+// (image updates, plugin auto-updates, readiness probes)
+// PUT /api/servers/:id/release-channel
+// Body: {"channel": "beta"}
+func (h *Handler) SetReleaseChannel(c *gin.Context) {
+	var request struct {
+		Channel string `json:"channel" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	channel, err := models.ParseReleaseChannel(request.Channel)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	server, err := h.mcService.SetReleaseChannel(c.Param("id"), channel)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.mcService.GetReleaseChannel(server))
 }
 
 // CleanOrphanedServers handles POST /api/admin/cleanup
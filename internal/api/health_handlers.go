@@ -6,58 +6,90 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/service"
 )
 
 type HealthHandler struct {
-	startTime time.Time
-	dbProvider repository.DatabaseProvider
+	startTime   time.Time
+	readiness   *service.ReadinessService
+	maintenance *service.MaintenanceService // Optional: reported for deploy automation
 }
 
-func NewHealthHandler(dbProvider repository.DatabaseProvider) *HealthHandler {
+func NewHealthHandler(readiness *service.ReadinessService) *HealthHandler {
 	return &HealthHandler{
 		startTime: time.Now(),
-		dbProvider: dbProvider,
+		readiness: readiness,
 	}
 }
 
-// HealthCheck handles GET /health
-func (h *HealthHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "healthy",
-		"service": "payperplay-hosting",
-		"version": "2.0",
-		"uptime":  time.Since(h.startTime).String(),
-	})
+// SetMaintenanceService sets the maintenance switch whose state /health reports
+func (h *HealthHandler) SetMaintenanceService(maintenance *service.MaintenanceService) {
+	h.maintenance = maintenance
 }
 
-// ReadinessCheck handles GET /ready
-func (h *HealthHandler) ReadinessCheck(c *gin.Context) {
-	// Check database connection
-	if err := h.dbProvider.Ping(); err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status": "not_ready",
-			"reason": "database_unavailable",
-			"error":  err.Error(),
-		})
-		return
+// HealthCheck handles GET /health
+// During maintenance the status is "maintenance" (still 200) and maintenance.drained tells deploy
+// automation when no operation is running anymore.
+func (h *HealthHandler) HealthCheck(c *gin.Context) {
+	status := "healthy"
+	maintenance := h.maintenance.Status()
+	if maintenance.Enabled {
+		status = "maintenance"
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status":   "ready",
-		"database": "connected",
-		"uptime":   time.Since(h.startTime).String(),
+		"status":      status,
+		"service":     "payperplay-hosting",
+		"version":     "2.0",
+		"uptime":      time.Since(h.startTime).String(),
+		"maintenance": maintenance,
 	})
 }
 
-// LivenessCheck handles GET /live
-func (h *HealthHandler) LivenessCheck(c *gin.Context) {
+// Live reports that the process is up and serving requests (liveness probe)
+// Dependencies aren't checked: restart the instance only if this fails, take it out of the load
+// balancer with /health/ready.
+// GET /health/live
+func (h *HealthHandler) Live(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status": "alive",
 		"uptime": time.Since(h.startTime).String(),
 	})
 }
 
+// Ready reports whether the instance can take traffic (readiness probe)
+// 503 while a required dependency (database, Docker, Velocity, cloud provider, startup
+// reconciliation) is down. Every check reports its status (up, down, degraded, disabled), latency
+// and details; results are reused for HEALTH_CHECK_CACHE_TTL.
+// GET /health/ready
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.readiness.Check(c.Request.Context())
+
+	status, code := "ready", http.StatusOK
+	if !report.Ready {
+		status, code = "not_ready", http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(code, gin.H{
+		"status":     status,
+		"uptime":     time.Since(h.startTime).String(),
+		"checks":     report.Checks,
+		"checked_at": report.CheckedAt,
+	})
+}
+
+// ReadinessCheck handles GET /ready
+// Alias of GET /health/ready for existing probes.
+func (h *HealthHandler) ReadinessCheck(c *gin.Context) {
+	h.Ready(c)
+}
+
+// LivenessCheck handles GET /live
+// Alias of GET /health/live for existing probes.
+func (h *HealthHandler) LivenessCheck(c *gin.Context) {
+	h.Live(c)
+}
+
 // MetricsCheck handles GET /metrics (basic version)
 func (h *HealthHandler) MetricsCheck(c *gin.Context) {
 	var m runtime.MemStats
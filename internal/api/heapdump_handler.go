@@ -0,0 +1,121 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// HeapDumpHandler handles JVM heap dump endpoints of modded servers
+type HeapDumpHandler struct {
+	heapDumpService *service.HeapDumpService
+}
+
+// NewHeapDumpHandler creates a new heap dump handler
+func NewHeapDumpHandler(heapDumpService *service.HeapDumpService) *HeapDumpHandler {
+	return &HeapDumpHandler{
+		heapDumpService: heapDumpService,
+	}
+}
+
+// UpdateHeapDumpSettings enables or disables heap dumps on Java OOM
+// PUT /api/servers/:id/heapdumps/settings
+// Body: {"enabled": true}
+func (h *HeapDumpHandler) UpdateHeapDumpSettings(c *gin.Context) {
+	serverID := c.Param("id")
+
+	var req struct {
+		Enabled *bool `json:"enabled" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "enabled is required"})
+		return
+	}
+
+	server, err := h.heapDumpService.SetHeapDumpsEnabled(serverID, *req.Enabled)
+	if err != nil {
+		if errors.Is(err, service.ErrHeapDumpsNotModded) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled": server.HeapDumpsEnabled,
+		"message": "Takes effect on the next server start",
+	})
+}
+
+// ListHeapDumps returns the stored heap dumps of a server
+// GET /api/servers/:id/heapdumps
+func (h *HeapDumpHandler) ListHeapDumps(c *gin.Context) {
+	serverID := c.Param("id")
+
+	dumps, err := h.heapDumpService.ListHeapDumps(serverID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list heap dumps: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"heap_dumps": dumps,
+	})
+}
+
+// DownloadHeapDump serves a stored heap dump
+// GET /api/servers/:id/heapdumps/:name/download
+func (h *HeapDumpHandler) DownloadHeapDump(c *gin.Context) {
+	serverID := c.Param("id")
+	name := c.Param("name")
+
+	path, err := h.heapDumpService.GetHeapDumpPath(serverID, name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": service.ErrHeapDumpNotFound.Error()})
+		return
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Transfer-Encoding", "binary")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Length", fmt.Sprintf("%d", fileInfo.Size()))
+
+	c.File(path)
+
+	logger.Info("Heap dump download served", map[string]interface{}{
+		"server_id": serverID,
+		"name":      name,
+		"size":      fileInfo.Size(),
+	})
+}
+
+// DeleteHeapDump removes a stored heap dump
+// DELETE /api/servers/:id/heapdumps/:name
+func (h *HeapDumpHandler) DeleteHeapDump(c *gin.Context) {
+	serverID := c.Param("id")
+	name := c.Param("name")
+
+	if err := h.heapDumpService.DeleteHeapDump(serverID, name); err != nil {
+		if errors.Is(err, service.ErrHeapDumpNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Heap dump deleted"})
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// IdlePolicyHandler handles idle-shutdown policy endpoints
+type IdlePolicyHandler struct {
+	idlePolicyService *service.IdlePolicyService
+	serverRepo        *repository.ServerRepository
+}
+
+// NewIdlePolicyHandler creates a new idle policy handler
+func NewIdlePolicyHandler(idlePolicyService *service.IdlePolicyService, serverRepo *repository.ServerRepository) *IdlePolicyHandler {
+	return &IdlePolicyHandler{
+		idlePolicyService: idlePolicyService,
+		serverRepo:        serverRepo,
+	}
+}
+
+// GetPolicy returns the idle-shutdown policy for a server
+// GET /api/servers/:id/idle-policy
+func (h *IdlePolicyHandler) GetPolicy(c *gin.Context) {
+	server, err := h.serverRepo.FindByID(c.Param("id"))
+	if err != nil || server == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	policy, err := h.idlePolicyService.GetPolicy(server.ID)
+	if err != nil {
+		logger.Error("Failed to get idle policy", err, map[string]interface{}{
+			"server_id": server.ID,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get idle policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"configured":           policy != nil,
+		"idle_timeout_seconds": server.IdleTimeoutSeconds,
+		"policy":               policy,
+	})
+}
+
+// updatePolicyRequest is the idle-shutdown policy of a server. protected_players was removed: a server
+// never shuts down while any player is online, so it is rejected like every unknown field.
+type updatePolicyRequest struct {
+	Timezone              string `json:"timezone"`
+	NightTimeoutSeconds   int    `json:"night_timeout_seconds"`
+	NightStartHour        *int   `json:"night_start_hour"`
+	NightEndHour          *int   `json:"night_end_hour"`
+	RestartGraceSeconds   int    `json:"restart_grace_seconds"`
+	MinDailyUptimeMinutes int    `json:"min_daily_uptime_minutes"`
+}
+
+// UnmarshalJSON rejects unknown fields, so options that don't exist (anymore) aren't dropped silently
+func (r *updatePolicyRequest) UnmarshalJSON(data []byte) error {
+	type plain updatePolicyRequest
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode((*plain)(r))
+}
+
+// UpdatePolicy creates or replaces the idle-shutdown policy for a server
+// PUT /api/servers/:id/idle-policy
+// Body: {"timezone": "Europe/Berlin", "night_timeout_seconds": 120, "night_start_hour": 23, "night_end_hour": 7, "restart_grace_seconds": 600, "min_daily_uptime_minutes": 60}
+func (h *IdlePolicyHandler) UpdatePolicy(c *gin.Context) {
+	server, err := h.serverRepo.FindByID(c.Param("id"))
+	if err != nil || server == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	var request updatePolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	policy := &models.IdleShutdownPolicy{
+		ServerID:              server.ID,
+		Timezone:              request.Timezone,
+		NightTimeoutSeconds:   request.NightTimeoutSeconds,
+		NightStartHour:        22,
+		NightEndHour:          8,
+		RestartGraceSeconds:   request.RestartGraceSeconds,
+		MinDailyUptimeMinutes: request.MinDailyUptimeMinutes,
+	}
+	if request.NightStartHour != nil {
+		policy.NightStartHour = *request.NightStartHour
+	}
+	if request.NightEndHour != nil {
+		policy.NightEndHour = *request.NightEndHour
+	}
+
+	if err := h.idlePolicyService.SavePolicy(policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("Idle policy updated", map[string]interface{}{
+		"server_id": server.ID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"policy": policy,
+	})
+}
+
+// DeletePolicy removes the idle-shutdown policy (plain idle timeout applies again)
+// DELETE /api/servers/:id/idle-policy
+func (h *IdlePolicyHandler) DeletePolicy(c *gin.Context) {
+	server, err := h.serverRepo.FindByID(c.Param("id"))
+	if err != nil || server == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	if err := h.idlePolicyService.DeletePolicy(server.ID); err != nil {
+		logger.Error("Failed to delete idle policy", err, map[string]interface{}{
+			"server_id": server.ID,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete idle policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Idle policy deleted",
+	})
+}
+
+// GetShutdownAudit returns the audit trail of idle-shutdown decisions
+// GET /api/servers/:id/idle-policy/audit?limit=100
+func (h *IdlePolicyHandler) GetShutdownAudit(c *gin.Context) {
+	serverID := c.Param("id")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	entries, err := h.idlePolicyService.GetAuditLog(serverID, limit)
+	if err != nil {
+		logger.Error("Failed to get shutdown audit log", err, map[string]interface{}{
+			"server_id": serverID,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get shutdown audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"server_id": serverID,
+		"entries":   entries,
+	})
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// InvoiceHandler handles invoice documents (PDF/CSV), billing export and billing profile endpoints
+type InvoiceHandler struct {
+	invoiceService *service.InvoiceService
+	userRepo       *repository.UserRepository
+}
+
+// NewInvoiceHandler creates a new invoice handler
+func NewInvoiceHandler(invoiceService *service.InvoiceService, userRepo *repository.UserRepository) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceService: invoiceService,
+		userRepo:       userRepo,
+	}
+}
+
+// ListDocuments returns the issued invoices of the current user
+// GET /api/billing/invoices/documents
+func (h *InvoiceHandler) ListDocuments(c *gin.Context) {
+	invoices, err := h.invoiceService.ListInvoices(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list invoices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invoices": invoices,
+		"count":    len(invoices),
+	})
+}
+
+// GenerateDocument issues the invoice of the current user for a closed month (idempotent)
+// POST /api/billing/invoices/documents
+// Body: {"period": "2025-01"}
+func (h *InvoiceHandler) GenerateDocument(c *gin.Context) {
+	var request struct {
+		Period string `json:"period" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	invoice, err := h.invoiceService.GenerateInvoice(userID, request.Period)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvoicePeriodOpen):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvoicedByStripe):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			logger.Error("Failed to generate invoice", err, map[string]interface{}{
+				"user_id": userID,
+				"period":  request.Period,
+			})
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	if invoice == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "empty",
+			"message": "No billable usage in this period",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"invoice": invoice,
+	})
+}
+
+// GetDocument returns an invoice with its line items
+// GET /api/billing/invoices/documents/:invoice_id
+func (h *InvoiceHandler) GetDocument(c *gin.Context) {
+	invoice, ok := h.loadInvoice(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// DownloadPDF returns an invoice as PDF
+// GET /api/billing/invoices/documents/:invoice_id/pdf
+func (h *InvoiceHandler) DownloadPDF(c *gin.Context) {
+	invoice, ok := h.loadInvoice(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", invoice.Number+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", h.invoiceService.RenderPDF(invoice))
+}
+
+// DownloadCSV returns the line items of an invoice as CSV
+// GET /api/billing/invoices/documents/:invoice_id/csv
+func (h *InvoiceHandler) DownloadCSV(c *gin.Context) {
+	invoice, ok := h.loadInvoice(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.invoiceService.RenderCSV(invoice)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render CSV"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", invoice.Number+".csv"))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// Export returns all invoice line items of the current user in a range of months as CSV
+// GET /api/billing/export?from=2025-01&to=2025-12 (defaults to the current year)
+func (h *InvoiceHandler) Export(c *gin.Context) {
+	year := time.Now().Year()
+	from := c.DefaultQuery("from", fmt.Sprintf("%d-01", year))
+	to := c.DefaultQuery("to", fmt.Sprintf("%d-12", year))
+
+	data, err := h.invoiceService.ExportCSV(c.GetString("user_id"), from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("billing-export_%s_%s.csv", from, to)))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// GetBillingProfile returns the invoice details of the current user and the resulting VAT treatment
+// GET /api/billing/profile
+func (h *InvoiceHandler) GetBillingProfile(c *gin.Context) {
+	user, err := h.userRepo.FindByID(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusOK, h.billingProfileResponse(user))
+}
+
+// UpdateBillingProfile sets the invoice details of the current user
+// PUT /api/billing/profile
+// Body: {"billing_name": "ACME GmbH", "billing_address": "...", "billing_country": "AT", "vat_id": "ATU12345678"}
+func (h *InvoiceHandler) UpdateBillingProfile(c *gin.Context) {
+	var request struct {
+		BillingName    string `json:"billing_name" binding:"max=255"`
+		BillingAddress string `json:"billing_address" binding:"max=512"`
+		BillingCountry string `json:"billing_country"`
+		VATID          string `json:"vat_id"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user, err := h.invoiceService.UpdateBillingProfile(c.GetString("user_id"), request.BillingName, request.BillingAddress, request.BillingCountry, request.VATID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, h.billingProfileResponse(user))
+}
+
+func (h *InvoiceHandler) billingProfileResponse(user *models.User) gin.H {
+	rate, reverseCharge, note := h.invoiceService.VATTreatment(user)
+	return gin.H{
+		"billing_name":       user.BillingName,
+		"billing_address":    user.BillingAddress,
+		"billing_country":    user.BillingCountry,
+		"vat_id":             user.VATID,
+		"vat_rate":           rate,
+		"vat_reverse_charge": reverseCharge,
+		"vat_note":           note,
+	}
+}
+
+// loadInvoice loads :invoice_id for the current user (admins may load any invoice)
+func (h *InvoiceHandler) loadInvoice(c *gin.Context) (*models.Invoice, bool) {
+	userID := c.GetString("user_id")
+	if c.GetBool("is_admin") {
+		userID = ""
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(c.Param("invoice_id"), userID)
+	if errors.Is(err, service.ErrInvoiceNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get invoice"})
+		return nil, false
+	}
+	return invoice, true
+}
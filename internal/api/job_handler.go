@@ -0,0 +1,124 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// JobHandler exposes the persisted history of background jobs (backups, restores, migrations,
+// archives, bulk jobs, chunk pre-generations) with their status and progress, for polling and cancellation
+type JobHandler struct {
+	jobService *service.JobService
+	opLimiter  *service.OperationLimiter
+	bulkJobs   *service.BulkJobService
+	pregen     *service.PregenerationService
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(jobService *service.JobService, opLimiter *service.OperationLimiter, bulkJobs *service.BulkJobService, pregen *service.PregenerationService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+		opLimiter:  opLimiter,
+		bulkJobs:   bulkJobs,
+		pregen:     pregen,
+	}
+}
+
+// ListJobs lists the jobs of the current user (owned servers and jobs the user started)
+// GET /api/jobs
+// Supports ?cursor, ?limit, ?sort and the kind/status/server_id filters.
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	page, err := parsePageRequest(c, 50, "kind", "status", "server_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	jobs, err := h.jobService.List(userID, page)
+	if err != nil {
+		logger.Error("JOBS-API: Failed to list jobs", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		respondPageError(c, err)
+		return
+	}
+
+	setPageHeaders(c, jobs.Total, jobs.NextCursor)
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":        jobs.Items,
+		"count":       len(jobs.Items),
+		"total":       jobs.Total,
+		"next_cursor": jobs.NextCursor,
+	})
+}
+
+// GetJob returns the status, phase, progress and error of a job
+// GET /api/jobs/:job_id
+func (h *JobHandler) GetJob(c *gin.Context) {
+	job, err := h.jobService.Get(c.GetString("user_id"), c.Param("job_id"))
+	if errors.Is(err, service.ErrJobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load job"})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// CancelJob cancels a queued or running operation, bulk job or chunk pre-generation
+// Running backups, restores, migrations and archives answer 202 with status "cancelling" and end as
+// "cancelled" once their partial files are removed; bulk jobs stop after the current batch and
+// pre-generations at their next check.
+// DELETE /api/jobs/:job_id
+func (h *JobHandler) CancelJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	jobID := c.Param("job_id")
+
+	op, err := h.opLimiter.Cancel(userID, jobID)
+	switch {
+	case err == nil && op.Status == service.OperationCancelling:
+		c.JSON(http.StatusAccepted, op)
+		return
+	case err == nil:
+		c.JSON(http.StatusOK, op)
+		return
+	case !errors.Is(err, service.ErrOperationNotFound) && !errors.Is(err, service.ErrOperationLimiterUnset):
+		respondOperationError(c, err)
+		return
+	}
+
+	bulkJob, err := h.bulkJobs.Cancel(userID, jobID)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusAccepted, bulkJob)
+		return
+	case errors.Is(err, service.ErrBulkJobNotCancellable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	pregenJob, err := h.pregen.CancelForUser(userID, jobID)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusAccepted, pregenJob)
+		return
+	case errors.Is(err, service.ErrPregenFinished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Neither running nor queued: finished, or interrupted by an API restart
+	if _, err := h.jobService.Get(userID, jobID); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": service.ErrOperationNotCancellable.Error()})
+		return
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": service.ErrJobNotFound.Error()})
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// LifecycleHandler handles the lifecycle thresholds (sleep, archive, cold storage) of servers and plans
+type LifecycleHandler struct {
+	policyService *service.LifecyclePolicyService
+	serverRepo    *repository.ServerRepository
+	audit         *service.AuditService
+}
+
+// NewLifecycleHandler creates a new lifecycle handler
+func NewLifecycleHandler(policyService *service.LifecyclePolicyService, serverRepo *repository.ServerRepository, auditService *service.AuditService) *LifecycleHandler {
+	return &LifecycleHandler{
+		policyService: policyService,
+		serverRepo:    serverRepo,
+		audit:         auditService,
+	}
+}
+
+// lifecyclePolicyRequest is the body of a lifecycle policy update (omitted fields inherit)
+type lifecyclePolicyRequest struct {
+	SleepAfterMinutes *int `json:"sleep_after_minutes"`
+	ArchiveAfterHours *int `json:"archive_after_hours"`
+	ColdAfterDays     *int `json:"cold_after_days"`
+}
+
+func (r lifecyclePolicyRequest) policy() *models.LifecyclePolicy {
+	return &models.LifecyclePolicy{
+		SleepAfterMinutes: r.SleepAfterMinutes,
+		ArchiveAfterHours: r.ArchiveAfterHours,
+		ColdAfterDays:     r.ColdAfterDays,
+	}
+}
+
+// GetLifecycle returns the effective thresholds of a server and when it will be archived
+// GET /api/servers/:id/lifecycle
+func (h *LifecycleHandler) GetLifecycle(c *gin.Context) {
+	server, err := h.serverRepo.FindByID(c.Param("id"))
+	if err != nil || server == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	preview, err := h.policyService.Preview(server)
+	if err != nil {
+		logger.Error("Failed to get lifecycle preview", err, map[string]interface{}{
+			"server_id": server.ID,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get lifecycle"})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// UpdateLifecycle creates or replaces the lifecycle override of a server
+// PUT /api/servers/:id/lifecycle
+// Body: {"sleep_after_minutes": 30, "archive_after_hours": 168, "cold_after_days": 90}
+func (h *LifecycleHandler) UpdateLifecycle(c *gin.Context) {
+	server, err := h.serverRepo.FindByID(c.Param("id"))
+	if err != nil || server == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	var request lifecyclePolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.policyService.SaveServerPolicy(server.ID, c.GetString("user_id"), request.policy()); err != nil {
+		h.respondPolicyError(c, err)
+		return
+	}
+
+	logger.Info("Lifecycle policy updated", map[string]interface{}{
+		"server_id": server.ID,
+	})
+	h.GetLifecycle(c)
+}
+
+// DeleteLifecycle removes the lifecycle override of a server (the plan thresholds apply again)
+// DELETE /api/servers/:id/lifecycle
+func (h *LifecycleHandler) DeleteLifecycle(c *gin.Context) {
+	server, err := h.serverRepo.FindByID(c.Param("id"))
+	if err != nil || server == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server not found"})
+		return
+	}
+
+	if err := h.policyService.DeleteServerPolicy(server.ID); err != nil {
+		logger.Error("Failed to delete lifecycle policy", err, map[string]interface{}{
+			"server_id": server.ID,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete lifecycle policy"})
+		return
+	}
+	h.GetLifecycle(c)
+}
+
+// ListPlanPolicies returns the effective lifecycle thresholds of every plan (admin only)
+// GET /api/admin/lifecycle-policies
+func (h *LifecycleHandler) ListPlanPolicies(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	plans, err := h.policyService.ListPlanPolicies()
+	if err != nil {
+		logger.Error("Failed to list lifecycle policies", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list lifecycle policies"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"plans": plans})
+}
+
+// UpdatePlanPolicy creates or replaces the lifecycle policy of a plan (admin only)
+// archive_after_hours and cold_after_days of 0 disable the transition for the plan.
+// PUT /api/admin/lifecycle-policies/:plan
+func (h *LifecycleHandler) UpdatePlanPolicy(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request lifecyclePolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	plan := c.Param("plan")
+	before, err := h.policyService.GetPlanPolicy(plan)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get lifecycle policy"})
+		return
+	}
+	policy := request.policy()
+	if err := h.policyService.SavePlanPolicy(plan, c.GetString("user_id"), policy); err != nil {
+		h.respondPolicyError(c, err)
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionLifecyclePolicy, "plan", plan, before, policy))
+
+	c.JSON(http.StatusOK, gin.H{"policy": policy})
+}
+
+// DeletePlanPolicy removes the lifecycle policy of a plan (the defaults apply again, admin only)
+// DELETE /api/admin/lifecycle-policies/:plan
+func (h *LifecycleHandler) DeletePlanPolicy(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	plan := c.Param("plan")
+	before, err := h.policyService.GetPlanPolicy(plan)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get lifecycle policy"})
+		return
+	}
+	if before == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plan has no lifecycle policy"})
+		return
+	}
+	if err := h.policyService.DeletePlanPolicy(plan); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete lifecycle policy"})
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionLifecyclePolicy, "plan", plan, before, nil))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lifecycle policy deleted"})
+}
+
+func (h *LifecycleHandler) respondPolicyError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrLifecyclePolicyInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	logger.Error("Failed to save lifecycle policy", err, nil)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save lifecycle policy"})
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// MaintenanceHandler switches the global maintenance mode (admin only)
+type MaintenanceHandler struct {
+	maintenance *service.MaintenanceService
+	audit       *service.AuditService
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(maintenance *service.MaintenanceService, auditService *service.AuditService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenance: maintenance,
+		audit:       auditService,
+	}
+}
+
+// GetMaintenance returns whether maintenance mode is on and how many operations are still running
+// GET /api/admin/maintenance
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+	c.JSON(http.StatusOK, h.maintenance.Status())
+}
+
+// SetMaintenance turns maintenance mode on or off (admin only)
+// While it is on, server starts and destructive operations answer 503 with the message; running
+// operations finish, and scaling and scheduled migrations are paused.
+// PUT /api/admin/maintenance
+// Body: {"enabled": true, "message": "Database upgrade, back at 22:15 UTC"}
+func (h *MaintenanceHandler) SetMaintenance(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request struct {
+		Enabled *bool  `json:"enabled" binding:"required"`
+		Message string `json:"message"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	before := h.maintenance.Status()
+	var status service.MaintenanceStatus
+	if *request.Enabled {
+		status = h.maintenance.Enable(request.Message, c.GetString("user_id"))
+	} else {
+		status = h.maintenance.Disable(c.GetString("user_id"))
+	}
+	h.audit.Record(auditEntry(c, audit.ActionMaintenanceMode, "platform", "maintenance",
+		gin.H{"enabled": before.Enabled, "message": before.Message},
+		gin.H{"enabled": status.Enabled, "message": status.Message}))
+
+	c.JSON(http.StatusOK, status)
+}
@@ -1,9 +1,9 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,73 +11,61 @@ import (
 	"github.com/payperplay/hosting/internal/conductor"
 	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/service"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
 // MigrationHandler handles migration-related HTTP requests
 type MigrationHandler struct {
-	migrationRepo *repository.MigrationRepository
-	serverRepo    *repository.ServerRepository
-	conductor     *conductor.Conductor
+	migrationRepo    *repository.MigrationRepository
+	serverRepo       *repository.ServerRepository
+	conductor        *conductor.Conductor
+	migrationService *service.MigrationService
+	analytics        *service.MigrationAnalyticsService
 }
 
 // NewMigrationHandler creates a new migration handler
-func NewMigrationHandler(migrationRepo *repository.MigrationRepository, serverRepo *repository.ServerRepository, cond *conductor.Conductor) *MigrationHandler {
+func NewMigrationHandler(migrationRepo *repository.MigrationRepository, serverRepo *repository.ServerRepository, cond *conductor.Conductor, migrationService *service.MigrationService, analytics *service.MigrationAnalyticsService) *MigrationHandler {
 	return &MigrationHandler{
-		migrationRepo: migrationRepo,
-		serverRepo:    serverRepo,
-		conductor:     cond,
+		migrationRepo:    migrationRepo,
+		serverRepo:       serverRepo,
+		conductor:        cond,
+		migrationService: migrationService,
+		analytics:        analytics,
 	}
 }
 
-// ListMigrations returns all migrations with optional filters
-// GET /api/migrations
+// ListMigrations returns migrations with optional filters (status, server_id, reason, node; comma-separated)
+// GET /admin/migrations
+// Supports ?cursor, ?limit (default 50, max 200), ?sort and the legacy ?offset.
 func (h *MigrationHandler) ListMigrations(c *gin.Context) {
-	// Parse query parameters
-	filters := make(map[string]interface{})
-
-	if status := c.Query("status"); status != "" {
-		// Support comma-separated statuses
-		statuses := strings.Split(status, ",")
-		if len(statuses) == 1 {
-			filters["status"] = models.MigrationStatus(statuses[0])
-		}
-		// TODO: Support multiple statuses with IN query
-	}
-
-	if serverID := c.Query("server_id"); serverID != "" {
-		filters["server_id"] = serverID
-	}
-
-	if reason := c.Query("reason"); reason != "" {
-		filters["reason"] = models.MigrationReason(reason)
-	}
-
-	// Pagination
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-	if limit > 200 {
-		limit = 200 // Max 200 per request
+	page, err := parsePageRequest(c, 50, "status", "server_id", "reason", "node")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
+	page.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	migrations, err := h.migrationRepo.FindAll(filters, limit, offset)
+	migrations, err := h.migrationRepo.FindPage(page)
 	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) || errors.Is(err, repository.ErrInvalidSort) {
+			respondPageError(c, err)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch migrations",
 		})
 		return
 	}
 
-	// Get total count
-	total, _ := h.migrationRepo.Count(filters)
-
+	setPageHeaders(c, migrations.Total, migrations.NextCursor)
 	c.JSON(http.StatusOK, gin.H{
-		"status":     "ok",
-		"migrations": migrations,
-		"total":      total,
-		"limit":      limit,
-		"offset":     offset,
+		"status":      "ok",
+		"migrations":  migrations.Items,
+		"total":       migrations.Total,
+		"limit":       page.Limit,
+		"offset":      page.Offset,
+		"next_cursor": migrations.NextCursor,
 	})
 }
 
@@ -197,6 +185,22 @@ func (h *MigrationHandler) CancelMigration(c *gin.Context) {
 		return
 	}
 
+	// In-flight migration: abort the running transfer (rsync is killed, migration is cancelled and rolls back)
+	if migration.Status == models.MigrationStatusPreparing && h.migrationService != nil {
+		if err := h.migrationService.CancelTransfer(migrationID); err != nil {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Migration transfer is not cancellable anymore",
+			})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"status":  "ok",
+			"message": "Migration transfer cancellation requested",
+		})
+		return
+	}
+
 	// Check if migration can be cancelled
 	if !migration.CanBeCancelled() {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -332,6 +336,29 @@ func (h *MigrationHandler) GetMigrationStats(c *gin.Context) {
 	})
 }
 
+// GetMigrationAnalytics returns historical success rates, durations and downtime per node pair and world size
+// GET /admin/migrations/analytics?days=30
+func (h *MigrationHandler) GetMigrationAnalytics(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "days must be between 1 and 365",
+		})
+		return
+	}
+
+	analytics, err := h.analytics.GetAnalytics(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		logger.Error("Failed to compute migration analytics", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to compute analytics",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
 // CreateManualMigration creates a manual migration request
 // POST /api/migrations
 func (h *MigrationHandler) CreateManualMigration(c *gin.Context) {
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// MinecraftLinkHandler handles linking the current user to a Minecraft account
+type MinecraftLinkHandler struct {
+	linkService *service.MinecraftLinkService
+}
+
+// NewMinecraftLinkHandler creates a new Minecraft link handler
+func NewMinecraftLinkHandler(linkService *service.MinecraftLinkService) *MinecraftLinkHandler {
+	return &MinecraftLinkHandler{linkService: linkService}
+}
+
+// GetLink returns the linked Minecraft account and a pending link of the current user
+// GET /api/auth/minecraft
+func (h *MinecraftLinkHandler) GetLink(c *gin.Context) {
+	link, err := h.linkService.Get(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Minecraft link"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"linked": link.Verified(), "link": link})
+}
+
+// StartLink sends a link code to the player in-game
+// The player must be online on one of the user's running servers.
+// POST /api/auth/minecraft/link
+// Body: {"name": "Steve"}
+func (h *MinecraftLinkHandler) StartLink(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, serverID, err := h.linkService.StartLink(c.Request.Context(), c.GetString("user_id"), req.Name)
+	if err != nil {
+		respondMinecraftLinkError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "A link code was sent to " + link.PendingName + " in-game, confirm it to link the account",
+		"server_id": serverID,
+		"link":      link,
+	})
+}
+
+// ConfirmLink links the account with the code the player received
+// POST /api/auth/minecraft/verify
+// Body: {"code": "123456"}
+func (h *MinecraftLinkHandler) ConfirmLink(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := h.linkService.ConfirmLink(c.GetString("user_id"), req.Code)
+	if err != nil {
+		respondMinecraftLinkError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"linked": true, "link": link})
+}
+
+// UpdateLink changes the settings of the linked account
+// PUT /api/auth/minecraft
+// Body: {"auto_op": true}
+func (h *MinecraftLinkHandler) UpdateLink(c *gin.Context) {
+	var req struct {
+		AutoOp *bool `json:"auto_op" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := h.linkService.SetAutoOp(c.GetString("user_id"), *req.AutoOp)
+	if err != nil {
+		respondMinecraftLinkError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"linked": true, "link": link})
+}
+
+// Unlink removes the linked account of the current user
+// DELETE /api/auth/minecraft
+func (h *MinecraftLinkHandler) Unlink(c *gin.Context) {
+	if err := h.linkService.Unlink(c.GetString("user_id")); err != nil {
+		respondMinecraftLinkError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Minecraft account unlinked"})
+}
+
+func respondMinecraftLinkError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPlayerNameInvalid), errors.Is(err, service.ErrPlayerNotFound),
+		errors.Is(err, models.ErrMinecraftLinkNotStarted):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrMinecraftLinkCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrMinecraftLinkNotLinked):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrMinecraftPlayerOffline):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrMinecraftLinkTooSoon):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPlayerLookupUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Minecraft account linking failed", err, map[string]interface{}{
+			"user_id": c.GetString("user_id"),
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Minecraft account linking failed"})
+	}
+}
@@ -10,6 +10,7 @@ import (
 type MonitoringHandler struct {
 	monitoringService *service.MonitoringService
 	mcService         *service.MinecraftService
+	controlPlane      *service.ControlPlaneMonitor
 }
 
 func NewMonitoringHandler(monitoringService *service.MonitoringService) *MonitoringHandler {
@@ -23,6 +24,26 @@ func (h *MonitoringHandler) SetMinecraftService(mcService *service.MinecraftServ
 	h.mcService = mcService
 }
 
+// SetControlPlaneMonitor sets the monitor of the control plane host's own resources
+func (h *MonitoringHandler) SetControlPlaneMonitor(controlPlane *service.ControlPlaneMonitor) {
+	h.controlPlane = controlPlane
+}
+
+// GetControlPlaneStatus handles GET /api/admin/control-plane (admin only)
+// Returns CPU, memory and disk pressure of the control plane host
+func (h *MonitoringHandler) GetControlPlaneStatus(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+	if h.controlPlane == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "control plane monitoring is not enabled"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.controlPlane.Status())
+}
+
 // GetServerStatus handles GET /api/servers/:id/status
 func (h *MonitoringHandler) GetServerStatus(c *gin.Context) {
 	serverID := c.Param("id")
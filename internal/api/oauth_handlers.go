@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -65,6 +66,10 @@ func (h *OAuthHandler) DiscordCallback(c *gin.Context) {
 		ipAddress,
 	)
 
+	if errors.Is(err, models.ErrTwoFactorRequired) {
+		respondTwoFactorChallenge(c, token)
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "OAuth authentication failed",
@@ -132,6 +137,10 @@ func (h *OAuthHandler) GoogleCallback(c *gin.Context) {
 		ipAddress,
 	)
 
+	if errors.Is(err, models.ErrTwoFactorRequired) {
+		respondTwoFactorChallenge(c, token)
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "OAuth authentication failed",
@@ -199,6 +208,10 @@ func (h *OAuthHandler) GitHubCallback(c *gin.Context) {
 		ipAddress,
 	)
 
+	if errors.Is(err, models.ErrTwoFactorRequired) {
+		respondTwoFactorChallenge(c, token)
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "OAuth authentication failed",
@@ -221,3 +234,55 @@ func (h *OAuthHandler) GitHubCallback(c *gin.Context) {
 		"provider":      "github",
 	})
 }
+
+// ListProviders returns the OAuth providers linked to the current user
+// GET /api/auth/oauth/providers
+func (h *OAuthHandler) ListProviders(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	providers, hasPassword, err := h.oauthService.ListLinkedProviders(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list linked providers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"providers":    providers,
+		"has_password": hasPassword,
+	})
+}
+
+// UnlinkProvider removes a linked OAuth provider from the current user
+// DELETE /api/auth/oauth/providers/:provider
+func (h *OAuthHandler) UnlinkProvider(c *gin.Context) {
+	userID := c.GetString("user_id")
+	provider := models.OAuthProviderType(c.Param("provider"))
+
+	if _, err := h.oauthService.GetProviderConfig(provider); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unsupported OAuth provider",
+		})
+		return
+	}
+
+	err := h.oauthService.UnlinkProvider(userID, provider, c.ClientIP(), c.GetHeader("User-Agent"))
+	if errors.Is(err, models.ErrLastLoginMethod) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Provider unlinked",
+		"provider": provider,
+	})
+}
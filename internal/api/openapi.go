@@ -0,0 +1,51 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate go run ../../cmd/openapi-gen -root ../..
+
+// openAPISpec is generated from SetupRouter by cmd/openapi-gen
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// DocsHandler serves the generated OpenAPI document and an interactive viewer
+type DocsHandler struct{}
+
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// GetSpec returns the OpenAPI 3 document
+// GET /api/docs/openapi.json
+func (h *DocsHandler) GetSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openAPISpec)
+}
+
+// GetDocs renders the API reference (Swagger UI)
+// GET /api/docs
+func (h *DocsHandler) GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
+
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>PayPerPlay API Reference</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/docs/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// OperationHandler exposes the per-owner operation queue (running and queued starts, backups, restores)
+// plus the tracked migrations and archives of the user's servers
+type OperationHandler struct {
+	opLimiter *service.OperationLimiter
+}
+
+// NewOperationHandler creates a new operation handler
+func NewOperationHandler(opLimiter *service.OperationLimiter) *OperationHandler {
+	return &OperationHandler{opLimiter: opLimiter}
+}
+
+// ListOperations returns the running, queued and recently finished operations of the current user
+// GET /api/operations
+func (h *OperationHandler) ListOperations(c *gin.Context) {
+	userID := c.GetString("user_id")
+	ops := h.opLimiter.List(userID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"operations": ops,
+		"count":      len(ops),
+		"limit":      h.opLimiter.Limit(userID), // Concurrent operations of the user's plan (0 = unlimited)
+	})
+}
+
+// GetOperation returns the status and queue position of an operation
+// GET /api/operations/:operation_id
+func (h *OperationHandler) GetOperation(c *gin.Context) {
+	op, err := h.opLimiter.Get(c.GetString("user_id"), c.Param("operation_id"))
+	if err != nil {
+		respondOperationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, op)
+}
+
+// CancelOperation cancels a queued operation or asks a running backup, restore, migration or archive to stop
+// Running operations answer 202 with status "cancelling" and end as "cancelled" once cleaned up.
+// POST /api/operations/:operation_id/cancel
+// DELETE /api/operations/:operation_id
+func (h *OperationHandler) CancelOperation(c *gin.Context) {
+	op, err := h.opLimiter.Cancel(c.GetString("user_id"), c.Param("operation_id"))
+	if err != nil {
+		respondOperationError(c, err)
+		return
+	}
+	if op.Status == service.OperationCancelling {
+		c.JSON(http.StatusAccepted, op)
+		return
+	}
+	c.JSON(http.StatusOK, op)
+}
+
+// respondOperationError maps operation limiter errors to HTTP status codes (anything else is a 500)
+func respondOperationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOperationQueueFull):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOperationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOperationNotCancellable), errors.Is(err, context.Canceled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrControlPlaneBusy):
+		c.Header("Retry-After", "300")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMaintenanceMode):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error(), "maintenance": true})
+	case errors.Is(err, service.ErrServerSuspended):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "suspended": true})
+	case errors.Is(err, service.ErrArchiveRestoring):
+		// Cold storage restores take minutes to hours, the client starts again later
+		c.Header("Retry-After", "600")
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error(), "restoring": true})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// OrganizationHandler handles organizations (teams), members, invitations and shared servers
+type OrganizationHandler struct {
+	orgService *service.OrganizationService
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(orgService *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgService: orgService}
+}
+
+// ListOrganizations returns the organizations of the current user
+// GET /api/organizations
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	orgs, err := h.orgService.ListOrganizations(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list organizations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"organizations": orgs,
+		"count":         len(orgs),
+	})
+}
+
+// CreateOrganization creates an organization owned by the current user
+// POST /api/organizations
+// Body: {"name": "My Team"}
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var request struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	org, err := h.orgService.CreateOrganization(c.GetString("user_id"), request.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, org)
+}
+
+// GetOrganization returns an organization with its members
+// GET /api/organizations/:org_id
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	org, err := h.orgService.GetOrganization(c.Param("org_id"), c.GetString("user_id"))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, org)
+}
+
+// UpdateOrganization renames an organization (admin)
+// PUT /api/organizations/:org_id
+// Body: {"name": "New Name"}
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	var request struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	org, err := h.orgService.RenameOrganization(c.Param("org_id"), c.GetString("user_id"), request.Name)
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, org)
+}
+
+// SetDataResidency restricts where the organization's data is stored (owner)
+// PUT /api/organizations/:org_id/residency
+// Body: {"country": "DE"} ("" removes the restriction)
+func (h *OrganizationHandler) SetDataResidency(c *gin.Context) {
+	var request struct {
+		Country string `json:"country"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	org, err := h.orgService.SetDataResidency(c.Param("org_id"), c.GetString("user_id"), request.Country)
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, org)
+}
+
+// DeleteOrganization deletes an organization (owner)
+// DELETE /api/organizations/:org_id
+func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	if err := h.orgService.DeleteOrganization(c.Param("org_id"), c.GetString("user_id")); err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "organization deleted"})
+}
+
+// UpdateMember changes the role of a member (admin)
+// PUT /api/organizations/:org_id/members/:user_id
+// Body: {"role": "operator"}
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	var request struct {
+		Role models.OrgRole `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	member, err := h.orgService.UpdateMemberRole(c.Param("org_id"), c.GetString("user_id"), c.Param("user_id"), request.Role)
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember removes a member (admin) or leaves the organization (own user ID)
+// DELETE /api/organizations/:org_id/members/:user_id
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	if err := h.orgService.RemoveMember(c.Param("org_id"), c.GetString("user_id"), c.Param("user_id")); err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+}
+
+// ListInvitations returns the pending invitations of an organization (admin)
+// GET /api/organizations/:org_id/invitations
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.orgService.ListInvitations(c.Param("org_id"), c.GetString("user_id"))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitations": invitations,
+		"count":       len(invitations),
+	})
+}
+
+// InviteMember invites a user by email (admin)
+// POST /api/organizations/:org_id/invitations
+// Body: {"email": "friend@example.com", "role": "operator"}
+func (h *OrganizationHandler) InviteMember(c *gin.Context) {
+	var request struct {
+		Email string         `json:"email" binding:"required,email"`
+		Role  models.OrgRole `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	invitation, err := h.orgService.InviteMember(c.Param("org_id"), c.GetString("user_id"), request.Email, request.Role)
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// RevokeInvitation deletes a pending invitation (admin)
+// DELETE /api/organizations/:org_id/invitations/:invitation_id
+func (h *OrganizationHandler) RevokeInvitation(c *gin.Context) {
+	invitationID, err := strconv.ParseUint(c.Param("invitation_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+
+	if err := h.orgService.RevokeInvitation(c.Param("org_id"), c.GetString("user_id"), uint(invitationID)); err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "invitation revoked"})
+}
+
+// AcceptInvitation joins the organization of an invitation sent to the current user's email
+// POST /api/organizations/invitations/accept
+// Body: {"token": "..."}
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	member, err := h.orgService.AcceptInvitation(request.Token, c.GetString("user_id"))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// ListServers returns the servers shared with an organization
+// GET /api/organizations/:org_id/servers
+func (h *OrganizationHandler) ListServers(c *gin.Context) {
+	servers, err := h.orgService.ListServers(c.Param("org_id"), c.GetString("user_id"))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, servers)
+}
+
+// ShareServer shares one of the current user's servers with an organization
+// PUT /api/organizations/:org_id/servers/:server_id
+func (h *OrganizationHandler) ShareServer(c *gin.Context) {
+	if err := h.orgService.ShareServer(c.Param("org_id"), c.GetString("user_id"), c.Param("server_id")); err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "server shared with organization"})
+}
+
+// UnshareServer removes a server from an organization
+// DELETE /api/organizations/:org_id/servers/:server_id
+func (h *OrganizationHandler) UnshareServer(c *gin.Context) {
+	if err := h.orgService.UnshareServer(c.Param("org_id"), c.GetString("user_id"), c.Param("server_id")); err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "server removed from organization"})
+}
+
+// respondOrganizationError maps organization errors to HTTP status codes
+func respondOrganizationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrOrgNotFound), errors.Is(err, models.ErrServerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrOrgForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrOrgLastOwner), errors.Is(err, models.ErrOrgAlreadyMember):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrOrgInvalidRole), errors.Is(err, models.ErrOrgInvalidInvitation),
+		errors.Is(err, models.ErrInvalidResidencyCountry):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/repository"
+)
+
+// parsePageRequest reads the standard list parameters:
+//
+//	?cursor=<next cursor>&limit=50&sort=-created_at&<filter>=a,b
+//
+// A leading "-" sorts descending. defaultLimit applies when no limit is given (0 = all rows).
+// Only the named filters are read; each accepts a comma-separated list of values.
+func parsePageRequest(c *gin.Context, defaultLimit int, filters ...string) (repository.PageRequest, error) {
+	page := repository.PageRequest{
+		Cursor:  c.Query("cursor"),
+		Limit:   defaultLimit,
+		Filters: make(map[string][]string),
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return page, fmt.Errorf("limit must be a positive number")
+		}
+		page.Limit = n
+	}
+	if page.Limit > repository.MaxPageLimit || (page.Limit == 0 && page.Cursor != "") {
+		page.Limit = repository.MaxPageLimit
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		page.Sort = strings.TrimPrefix(sort, "-")
+		page.Desc = strings.HasPrefix(sort, "-")
+	} else {
+		page.Desc = true // Newest first
+	}
+
+	for _, filter := range filters {
+		for _, value := range strings.Split(c.Query(filter), ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				page.Filters[filter] = append(page.Filters[filter], value)
+			}
+		}
+	}
+	return page, nil
+}
+
+// setPageHeaders exposes the total count and the next cursor (plus an RFC 8288 Link) for dashboard tables
+func setPageHeaders(c *gin.Context, total int64, nextCursor string) {
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	if nextCursor == "" {
+		return
+	}
+	c.Header("X-Next-Cursor", nextCursor)
+
+	next := *c.Request.URL
+	query := next.Query()
+	query.Set("cursor", nextCursor)
+	next.RawQuery = query.Encode()
+	c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+}
+
+// respondPageError maps list query errors to a response
+func respondPageError(c *gin.Context, err error) {
+	if errors.Is(err, repository.ErrInvalidCursor) || errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/repository"
+)
+
+func newPageTestContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest("GET", target, nil)
+	return c, recorder
+}
+
+func TestParsePageRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    repository.PageRequest
+		wantErr bool
+	}{
+		{
+			name:  "defaults to newest first",
+			query: "",
+			want:  repository.PageRequest{Limit: 50, Desc: true, Filters: map[string][]string{}},
+		},
+		{
+			name:  "ascending sort",
+			query: "sort=name&limit=10",
+			want:  repository.PageRequest{Limit: 10, Sort: "name", Filters: map[string][]string{}},
+		},
+		{
+			name:  "descending sort",
+			query: "sort=-ram_mb",
+			want:  repository.PageRequest{Limit: 50, Sort: "ram_mb", Desc: true, Filters: map[string][]string{}},
+		},
+		{
+			name:  "limit is capped",
+			query: "limit=100000",
+			want:  repository.PageRequest{Limit: repository.MaxPageLimit, Desc: true, Filters: map[string][]string{}},
+		},
+		{
+			name:  "filters split on commas, unknown filters ignored",
+			query: "status=running,%20stopped,&node=n1&owner_id=x",
+			want: repository.PageRequest{Limit: 50, Desc: true, Filters: map[string][]string{
+				"status": {"running", "stopped"},
+				"node":   {"n1"},
+			}},
+		},
+		{
+			name:  "cursor is passed through",
+			query: "cursor=abc&limit=5",
+			want:  repository.PageRequest{Cursor: "abc", Limit: 5, Desc: true, Filters: map[string][]string{}},
+		},
+		{name: "zero limit", query: "limit=0", wantErr: true},
+		{name: "non-numeric limit", query: "limit=ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newPageTestContext("/api/servers?" + tt.query)
+			page, err := parsePageRequest(c, 50, "status", "node", "type")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePageRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(page, tt.want) {
+				t.Errorf("parsePageRequest() = %+v, want %+v", page, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePageRequestCursorWithoutLimit(t *testing.T) {
+	// Endpoints without a default limit must not return every remaining row for a cursor
+	c, _ := newPageTestContext("/api/servers?cursor=abc")
+	page, err := parsePageRequest(c, 0)
+	if err != nil {
+		t.Fatalf("parsePageRequest() error = %v", err)
+	}
+	if page.Limit != repository.MaxPageLimit {
+		t.Errorf("Limit = %d, want %d", page.Limit, repository.MaxPageLimit)
+	}
+}
+
+func TestSetPageHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		nextCursor string
+		wantNext   string
+		wantLink   string
+	}{
+		{name: "last page", target: "/api/servers?limit=2", wantLink: ""},
+		{
+			name:       "next page keeps the query",
+			target:     "/api/servers?limit=2&status=running",
+			nextCursor: "eyJpZCI6IjEifQ",
+			wantNext:   "eyJpZCI6IjEifQ",
+			wantLink:   `</api/servers?cursor=eyJpZCI6IjEifQ&limit=2&status=running>; rel="next"`,
+		},
+		{
+			name:       "replaces the previous cursor",
+			target:     "/api/servers?cursor=old&limit=2",
+			nextCursor: "new",
+			wantNext:   "new",
+			wantLink:   `</api/servers?cursor=new&limit=2>; rel="next"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, recorder := newPageTestContext(tt.target)
+			setPageHeaders(c, 42, tt.nextCursor)
+
+			header := recorder.Header()
+			if got := header.Get("X-Total-Count"); got != "42" {
+				t.Errorf("X-Total-Count = %q, want 42", got)
+			}
+			if got := header.Get("X-Next-Cursor"); got != tt.wantNext {
+				t.Errorf("X-Next-Cursor = %q, want %q", got, tt.wantNext)
+			}
+			if got := header.Get("Link"); got != tt.wantLink {
+				t.Errorf("Link = %q, want %q", got, tt.wantLink)
+			}
+		})
+	}
+}
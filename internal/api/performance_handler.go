@@ -0,0 +1,42 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// PerformanceHandler serves the performance graphs of servers
+type PerformanceHandler struct {
+	performanceService *service.PerformanceService
+}
+
+// NewPerformanceHandler creates a new performance handler
+func NewPerformanceHandler(performanceService *service.PerformanceService) *PerformanceHandler {
+	return &PerformanceHandler{performanceService: performanceService}
+}
+
+// GetPerformance returns the container resources and TPS/MSPT of a server over a range (1h, 6h, 24h, 7d)
+// GET /api/servers/:id/performance?range=1h
+func (h *PerformanceHandler) GetPerformance(c *gin.Context) {
+	history, err := h.performanceService.History(c.Request.Context(), c.Param("id"), c.DefaultQuery("range", "1h"))
+	if err != nil {
+		respondPerformanceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// respondPerformanceError maps container metrics errors to HTTP status codes
+func respondPerformanceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrContainerMetricsRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrContainerMetricsDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// PlayerBanHandler handles bans enforced at the Velocity proxy: global bans (admin only) and
+// bans from the servers of an organization (organization admins)
+type PlayerBanHandler struct {
+	bans  *service.PlayerBanService
+	audit *service.AuditService
+}
+
+// NewPlayerBanHandler creates a new player ban handler
+func NewPlayerBanHandler(bans *service.PlayerBanService, auditService *service.AuditService) *PlayerBanHandler {
+	return &PlayerBanHandler{
+		bans:  bans,
+		audit: auditService,
+	}
+}
+
+// ListGlobalBans returns the network-wide bans, including lifted ones (admin only)
+// GET /api/admin/bans?limit=100
+func (h *PlayerBanHandler) ListGlobalBans(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+	h.list(c, "")
+}
+
+// CreateGlobalBan bans a player from the whole network (admin only)
+// POST /api/admin/bans
+// Body: {"name": "Griefer123", "reason": "Charge-back", "expires_at": "2025-03-01T00:00:00Z"}
+func (h *PlayerBanHandler) CreateGlobalBan(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+	h.create(c, "")
+}
+
+// RevokeGlobalBan lifts a network-wide ban (admin only)
+// DELETE /api/admin/bans/:ban_id
+func (h *PlayerBanHandler) RevokeGlobalBan(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+	h.revoke(c, "")
+}
+
+// ListOrganizationBans returns the bans of an organization, including lifted ones
+// GET /api/organizations/:org_id/bans?limit=100
+func (h *PlayerBanHandler) ListOrganizationBans(c *gin.Context) {
+	h.list(c, c.Param("org_id"))
+}
+
+// CreateOrganizationBan bans a player from all servers of an organization
+// POST /api/organizations/:org_id/bans
+// Body: {"name": "Griefer123", "reason": "Griefing", "expires_at": "2025-03-01T00:00:00Z"}
+func (h *PlayerBanHandler) CreateOrganizationBan(c *gin.Context) {
+	h.create(c, c.Param("org_id"))
+}
+
+// RevokeOrganizationBan lifts a ban of an organization
+// DELETE /api/organizations/:org_id/bans/:ban_id
+func (h *PlayerBanHandler) RevokeOrganizationBan(c *gin.Context) {
+	h.revoke(c, c.Param("org_id"))
+}
+
+func (h *PlayerBanHandler) list(c *gin.Context, orgID string) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	bans, err := h.bans.List(c.GetString("user_id"), orgID, limit)
+	if err != nil {
+		respondPlayerBanError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bans": bans, "count": len(bans)})
+}
+
+func (h *PlayerBanHandler) create(c *gin.Context, orgID string) {
+	var req struct {
+		Name      string     `json:"name" binding:"required"`
+		Reason    string     `json:"reason" binding:"required"`
+		ExpiresAt *time.Time `json:"expires_at"` // Omitted = permanent
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ban, err := h.bans.Ban(c.Request.Context(), c.GetString("user_id"), orgID, req.Name, req.Reason, req.ExpiresAt)
+	if err != nil {
+		respondPlayerBanError(c, err)
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionPlayerBan, "player_ban", strconv.FormatUint(uint64(ban.ID), 10), nil, ban))
+
+	c.JSON(http.StatusCreated, gin.H{"ban": ban})
+}
+
+func (h *PlayerBanHandler) revoke(c *gin.Context, orgID string) {
+	id, err := strconv.ParseUint(c.Param("ban_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ban ID"})
+		return
+	}
+
+	ban, err := h.bans.Revoke(c.GetString("user_id"), orgID, uint(id))
+	if err != nil {
+		respondPlayerBanError(c, err)
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionPlayerBan, "player_ban", c.Param("ban_id"),
+		gin.H{"revoked_at": nil}, gin.H{"revoked_at": ban.RevokedAt}))
+
+	c.JSON(http.StatusOK, gin.H{"ban": ban})
+}
+
+func respondPlayerBanError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrPlayerBanInvalid), errors.Is(err, service.ErrPlayerNameInvalid),
+		errors.Is(err, service.ErrPlayerNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrOrgNotFound), errors.Is(err, models.ErrPlayerBanNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrOrgForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrPlayerBanRevoked):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPlayerLookupUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Player ban request failed", err, map[string]interface{}{
+			"user_id": c.GetString("user_id"),
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Player ban request failed"})
+	}
+}
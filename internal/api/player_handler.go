@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -62,12 +64,74 @@ func (h *PlayerHandler) GetPlayerList(c *gin.Context) {
 		return
 	}
 
+	// Edits made while the server was offline that wait for the next start
+	pending, err := h.playerListService.GetPendingChanges(serverID, listType)
+	if err != nil {
+		logger.Error("Failed to get pending player list changes", err, map[string]interface{}{
+			"server_id": serverID,
+			"list_type": listType,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get player list",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"list_type": listType,
 		"players":   list,
+		"pending":   pending,
 	})
 }
 
+// ReplacePlayerList replaces a whole list file while the server is offline
+// The body is the file content in the Minecraft format; it is validated before it is written.
+// PUT /api/servers/:id/players/:listType
+func (h *PlayerHandler) ReplacePlayerList(c *gin.Context) {
+	serverID := c.Param("id")
+	listType := service.PlayerListType(c.Param("listType"))
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read request body",
+		})
+		return
+	}
+
+	if err := h.playerListService.ReplaceList(serverID, listType, body); err != nil {
+		h.respondListError(c, err, "Failed to replace player list")
+		return
+	}
+
+	logger.Info("Player list replaced", map[string]interface{}{
+		"server_id": serverID,
+		"list_type": listType,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"list_type": listType,
+	})
+}
+
+// respondListError maps player list errors to status codes
+func (h *PlayerHandler) respondListError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPlayerListInvalid), errors.Is(err, service.ErrPlayerNameInvalid), errors.Is(err, service.ErrPlayerListType),
+		errors.Is(err, service.ErrPlayerNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPlayerListServerLive):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logger.Error(message, err, map[string]interface{}{
+			"server_id": c.Param("id"),
+			"list_type": c.Param("listType"),
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 // AddToPlayerList adds a player to a specific list
 // POST /api/servers/:id/players/:listType/add
 // Body: { "username": "PlayerName" }
@@ -88,16 +152,9 @@ func (h *PlayerHandler) AddToPlayerList(c *gin.Context) {
 	}
 
 	// Add player to list
-	err := h.playerListService.AddToList(serverID, req.Username, listType)
+	applied, err := h.playerListService.AddToList(serverID, req.Username, listType, c.GetString("user_id"))
 	if err != nil {
-		logger.Error("Failed to add player to list", err, map[string]interface{}{
-			"server_id": serverID,
-			"username":  req.Username,
-			"list_type": listType,
-		})
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		h.respondListError(c, err, "Failed to add player to list")
 		return
 	}
 
@@ -112,6 +169,7 @@ func (h *PlayerHandler) AddToPlayerList(c *gin.Context) {
 		"message":   "Player added successfully",
 		"username":  req.Username,
 		"list_type": listType,
+		"applied":   applied, // rcon, file or pending (applied on the next start)
 	})
 }
 
@@ -124,16 +182,9 @@ func (h *PlayerHandler) RemoveFromPlayerList(c *gin.Context) {
 	listType := service.PlayerListType(listTypeStr)
 
 	// Remove player from list
-	err := h.playerListService.RemoveFromList(serverID, username, listType)
+	applied, err := h.playerListService.RemoveFromList(serverID, username, listType, c.GetString("user_id"))
 	if err != nil {
-		logger.Error("Failed to remove player from list", err, map[string]interface{}{
-			"server_id": serverID,
-			"username":  username,
-			"list_type": listType,
-		})
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		h.respondListError(c, err, "Failed to remove player from list")
 		return
 	}
 
@@ -148,6 +199,7 @@ func (h *PlayerHandler) RemoveFromPlayerList(c *gin.Context) {
 		"message":   "Player removed successfully",
 		"username":  username,
 		"list_type": listType,
+		"applied":   applied,
 	})
 }
 
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// PregenerationHandler handles chunk pre-generation jobs
+type PregenerationHandler struct {
+	pregenService *service.PregenerationService
+}
+
+// NewPregenerationHandler creates a new pre-generation handler
+func NewPregenerationHandler(pregenService *service.PregenerationService) *PregenerationHandler {
+	return &PregenerationHandler{pregenService: pregenService}
+}
+
+// StartPregeneration starts pre-generating the chunks around a point of a running server
+// Uses the Chunky plugin if it is installed, force-loading otherwise (the job's method). The job
+// pauses itself while TPS is below min_tps; poll it here or in GET /api/jobs/:job_id.
+// POST /api/servers/:id/pregeneration
+// Body: {"dimension": "overworld", "radius": 2000, "shape": "circle", "center_x": 0, "center_z": 0, "min_tps": 18}
+func (h *PregenerationHandler) StartPregeneration(c *gin.Context) {
+	var req service.PregenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.pregenService.Start(c.Param("id"), c.GetString("user_id"), req)
+	if err != nil {
+		respondPregenError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListPregenerations lists the pre-generation jobs of a server (finished jobs for an hour)
+// GET /api/servers/:id/pregeneration
+func (h *PregenerationHandler) ListPregenerations(c *gin.Context) {
+	jobs := h.pregenService.List(c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+// GetPregeneration returns the status, progress and last TPS of a pre-generation job
+// GET /api/servers/:id/pregeneration/:job_id
+func (h *PregenerationHandler) GetPregeneration(c *gin.Context) {
+	job, err := h.pregenService.Get(c.Param("id"), c.Param("job_id"))
+	if err != nil {
+		respondPregenError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// PausePregeneration pauses a pre-generation job until it is resumed
+// POST /api/servers/:id/pregeneration/:job_id/pause
+func (h *PregenerationHandler) PausePregeneration(c *gin.Context) {
+	job, err := h.pregenService.Pause(c.Param("id"), c.Param("job_id"))
+	if err != nil {
+		respondPregenError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// ResumePregeneration continues a paused pre-generation job
+// POST /api/servers/:id/pregeneration/:job_id/resume
+func (h *PregenerationHandler) ResumePregeneration(c *gin.Context) {
+	job, err := h.pregenService.Resume(c.Param("id"), c.Param("job_id"))
+	if err != nil {
+		respondPregenError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// CancelPregeneration cancels a pre-generation job, keeping the chunks generated so far
+// Answers 202 with status "cancelling"; the job ends as "cancelled" at its next check.
+// DELETE /api/servers/:id/pregeneration/:job_id
+func (h *PregenerationHandler) CancelPregeneration(c *gin.Context) {
+	job, err := h.pregenService.Cancel(c.Param("id"), c.Param("job_id"))
+	if err != nil {
+		respondPregenError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// respondPregenError maps pre-generation errors to HTTP status codes
+func respondPregenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPregenNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPregenInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrPregenAlreadyRunning),
+		errors.Is(err, service.ErrPregenServerNotRunning),
+		errors.Is(err, service.ErrPregenNotRunning),
+		errors.Is(err, service.ErrPregenNotPaused),
+		errors.Is(err, service.ErrPregenFinished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDiskQuotaExceeded):
+		c.JSON(http.StatusInsufficientStorage, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/monitoring"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -19,3 +22,9 @@ func (h *PrometheusHandler) MetricsEndpoint(c *gin.Context) {
 	handler := promhttp.Handler()
 	handler.ServeHTTP(c.Writer, c.Request)
 }
+
+// AlertRules serves the bundled Prometheus alert rules (node down, queue stuck, backups, billing)
+// GET /prometheus/rules
+func (h *PrometheusHandler) AlertRules(c *gin.Context) {
+	c.Data(http.StatusOK, "application/yaml; charset=utf-8", monitoring.AlertRules)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// ReconcileHandler shows and reruns the state reconciliation of the conductor (admin only)
+type ReconcileHandler struct {
+	reconciler *service.StateReconciler
+	audit      *service.AuditService
+}
+
+// NewReconcileHandler creates a new reconcile handler
+func NewReconcileHandler(reconciler *service.StateReconciler, auditService *service.AuditService) *ReconcileHandler {
+	return &ReconcileHandler{
+		reconciler: reconciler,
+		audit:      auditService,
+	}
+}
+
+// GetReconcileReport returns the report of the last reconciliation (the startup run until rerun)
+// GET /api/admin/reconcile
+func (h *ReconcileHandler) GetReconcileReport(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	report := h.reconciler.LastReport()
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No reconciliation has finished yet"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// RunReconcile reruns the state reconciliation (without the startup-only state file restores)
+// Failed phases are listed in the report, the response is 200 as long as the run finished.
+// POST /api/admin/reconcile
+func (h *ReconcileHandler) RunReconcile(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	report, err := h.reconciler.Run(c.Request.Context(), service.ReconcileTriggerManual)
+	if errors.Is(err, service.ErrReconcileRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionStateReconcile, "platform", "conductor", nil, gin.H{
+		"failed": report.Failed,
+	}))
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Reconciliation aborted: " + err.Error(), "report": report})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/middleware"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// RemoteActionHandler exposes the remote action catalog: one endpoint per action that Discord bots,
+// CI pipelines and other automation call with an API key to restart a server, back it up or run commands
+type RemoteActionHandler struct {
+	actions *service.RemoteActionService
+	audit   *service.AuditService
+}
+
+// NewRemoteActionHandler creates a new remote action handler
+func NewRemoteActionHandler(actions *service.RemoteActionService, auditService *service.AuditService) *RemoteActionHandler {
+	return &RemoteActionHandler{
+		actions: actions,
+		audit:   auditService,
+	}
+}
+
+// remoteActionEntry is a catalog action with whether the caller may run it on the server
+type remoteActionEntry struct {
+	service.RemoteAction
+	Allowed bool `json:"allowed"`
+}
+
+// ListActions returns the action catalog and which actions the caller (user or API key) may run on the server
+// GET /api/servers/:id/actions
+func (h *RemoteActionHandler) ListActions(c *gin.Context) {
+	serverID := c.Param("id")
+
+	entries := make([]remoteActionEntry, 0, len(service.RemoteActions))
+	found := false
+	for _, action := range service.RemoteActions {
+		err := middleware.AuthorizeServerAccess(c, serverID, action.Permission)
+		found = found || !errors.Is(err, models.ErrServerNotFound)
+		entries = append(entries, remoteActionEntry{RemoteAction: action, Allowed: err == nil})
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "server not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"actions": entries,
+		"count":   len(entries),
+	})
+}
+
+// RunAction runs an action of the catalog on the server
+// The permission (and API key scope) depends on the action; start, restart and backup also count
+// against the expensive-operation rate limit.
+// POST /api/servers/:id/actions/:action
+// Body (optional): {"params": {"command": "whitelist add Steve"}}
+func (h *RemoteActionHandler) RunAction(c *gin.Context) {
+	serverID := c.Param("id")
+
+	action, err := service.FindRemoteAction(c.Param("action"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if !middleware.AuthorizeServerRequest(c, action.Permission) {
+		return
+	}
+	if action.Expensive && !middleware.AllowRequest(c, middleware.ExpensiveRateLimiter) {
+		return
+	}
+
+	var request struct {
+		Params map[string]string `json:"params"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body, expected {\"params\": {...}}"})
+			return
+		}
+	}
+
+	result, err := h.actions.Run(c.Request.Context(), serverID, c.GetString("user_id"), action, request.Params)
+	after := gin.H{"action": action.Name, "params": request.Params}
+	if key := middleware.CurrentAPIKey(c); key != nil {
+		after["api_key_id"] = key.ID
+	}
+	entry := auditEntry(c, audit.ActionRemoteAction, "server", serverID, nil, after)
+	if err != nil {
+		entry.Result, entry.Error = "failed", err.Error()
+		h.audit.Record(entry)
+		logger.Warn("ACTIONS: Remote action failed", map[string]interface{}{
+			"server_id": serverID,
+			"action":    action.Name,
+			"error":     err.Error(),
+		})
+		respondRemoteActionError(c, err)
+		return
+	}
+	entry.Result = "success"
+	after["status"] = result.Status
+	h.audit.Record(entry)
+
+	status := http.StatusOK
+	if result.Status != "done" {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, result)
+}
+
+// respondRemoteActionError maps remote action errors to HTTP status codes
+func respondRemoteActionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrRemoteActionUnknown):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRemoteActionInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrRemoteActionConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		respondOperationError(c, err)
+	}
+}
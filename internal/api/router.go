@@ -1,9 +1,11 @@
 package api
 
 import (
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"github.com/payperplay/hosting/internal/middleware"
-	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/pkg/config"
 )
 
@@ -24,6 +26,8 @@ func SetupRouter(
 	metricsHandler *MetricsHandler,
 	playerHandler *PlayerHandler,
 	worldHandler *WorldHandler,
+	heapDumpHandler *HeapDumpHandler,
+	votifierHandler *VotifierHandler,
 	templateHandler *TemplateHandler,
 	webhookHandler *WebhookHandler,
 	backupScheduleHandler *BackupScheduleHandler,
@@ -37,6 +41,49 @@ func SetupRouter(
 	migrationHandler *MigrationHandler,
 	dashboardWsHandler *DashboardWebSocket,
 	containerSyncHandler *ContainerSyncHandler,
+	idlePolicyHandler *IdlePolicyHandler,
+	stripeHandler *StripeHandler,
+	walletHandler *WalletHandler,
+	budgetHandler *BudgetHandler,
+	invoiceHandler *InvoiceHandler,
+	orgHandler *OrganizationHandler,
+	shareHandler *ShareHandler,
+	operationHandler *OperationHandler,
+	apiKeyHandler *APIKeyHandler,
+	twoFactorHandler *TwoFactorHandler,
+	dedicatedNodeHandler *DedicatedNodeHandler,
+	ssoHandler *SSOHandler,
+	eventReplayHandler *EventReplayHandler,
+	alertHandler *AlertHandler,
+	graphQLHandler *GraphQLHandler,
+	diagnosisHandler *DiagnosisHandler,
+	jobHandler *JobHandler,
+	cloneHandler *CloneHandler,
+	pregenHandler *PregenerationHandler,
+	sftpHandler *SFTPHandler,
+	webdavHandler *WebDAVHandler,
+	diskHandler *DiskHandler,
+	performanceHandler *PerformanceHandler,
+	auditHandler *AuditHandler,
+	maintenanceHandler *MaintenanceHandler,
+	runtimeConfigHandler *RuntimeConfigHandler,
+	sshKeyHandler *SSHKeyHandler,
+	schemaHandler *SchemaHandler,
+	usageArchiveHandler *UsageArchiveHandler,
+	reconcileHandler *ReconcileHandler,
+	driftHandler *DriftHandler,
+	archiveHandler *ArchiveHandler,
+	lifecycleHandler *LifecycleHandler,
+	minecraftLinkHandler *MinecraftLinkHandler,
+	announcementHandler *AnnouncementHandler,
+	playerBanHandler *PlayerBanHandler,
+	scalingDecisionHandler *ScalingDecisionHandler,
+	fleetCostHandler *FleetCostHandler,
+	webhookEndpointHandler *WebhookEndpointHandler,
+	remoteActionHandler *RemoteActionHandler,
+	applyHandler *ApplyHandler,
+	healthHandler *HealthHandler,
+	diagnosticsHandler *DiagnosticsHandler,
 	cfg *config.Config,
 ) *gin.Engine {
 	// Set Gin mode
@@ -50,6 +97,7 @@ func SetupRouter(
 	// Global middleware (in order)
 	router.Use(gin.Recovery())                     // Panic recovery
 	router.Use(middleware.ErrorHandler())          // Error handling
+	router.Use(middleware.Tracing())               // Request spans (OpenTelemetry, X-Trace-ID)
 	router.Use(middleware.RequestLogger())         // Request logging
 	router.Use(middleware.RateLimitMiddleware(middleware.GlobalRateLimiter)) // Global rate limiting
 
@@ -57,9 +105,10 @@ func SetupRouter(
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-2FA-Code, Idempotency-Key, traceparent, tracestate")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, X-Next-Cursor, Link, Retry-After, Idempotent-Replayed, X-Trace-ID")
 
-		if c.Request.Method == "OPTIONS" {
+		if c.Request.Method == "OPTIONS" && !strings.HasPrefix(c.Request.URL.Path, "/dav/") { // WebDAV answers OPTIONS itself
 			c.AbortWithStatus(204)
 			return
 		}
@@ -68,16 +117,25 @@ func SetupRouter(
 	})
 
 	// Health check endpoints (no auth required)
-	dbProvider := repository.GetDBProvider()
-	healthHandler := NewHealthHandler(dbProvider)
+	healthHandler.SetMaintenanceService(maintenanceHandler.maintenance)
 	router.GET("/health", healthHandler.HealthCheck)
 	router.HEAD("/health", healthHandler.HealthCheck)  // Docker healthcheck uses HEAD
+	router.GET("/health/live", healthHandler.Live)     // Liveness: the process is up
+	router.HEAD("/health/live", healthHandler.Live)
+	router.GET("/health/ready", healthHandler.Ready)   // Readiness: dependencies reachable, startup reconciliation finished
+	router.HEAD("/health/ready", healthHandler.Ready)
 	router.GET("/ready", healthHandler.ReadinessCheck)
 	router.GET("/live", healthHandler.LivenessCheck)
 	router.GET("/metrics", healthHandler.MetricsCheck)
 
+	// API reference (generated OpenAPI document + Swagger UI, no auth required)
+	docsHandler := NewDocsHandler()
+	router.GET("/api/docs", docsHandler.GetDocs)
+	router.GET("/api/docs/openapi.json", docsHandler.GetSpec)
+
 	// Prometheus metrics endpoint (no auth required for scraping)
 	router.GET("/prometheus", prometheusHandler.MetricsEndpoint)
+	router.GET("/prometheus/rules", prometheusHandler.AlertRules) // Bundled alert rules for Prometheus
 
 	// Conductor API endpoints (no auth required for internal monitoring)
 	conductor := router.Group("/conductor")
@@ -86,6 +144,8 @@ func SetupRouter(
 		conductor.GET("/fleet", conductorHandler.GetFleetStats)
 		conductor.GET("/nodes", conductorHandler.GetNodes)
 		conductor.GET("/containers", conductorHandler.GetContainers)
+		conductor.GET("/topology", conductorHandler.GetTopology) // Fleet graph export (format=json|dot)
+		conductor.GET("/ssh-pool", conductorHandler.GetSSHPoolStats)
 		conductor.GET("/debug-logs", conductorHandler.GetDebugLogs)
 		conductor.DELETE("/debug-logs", conductorHandler.ClearDebugLogs)
 		conductor.POST("/sync-container-metadata", containerSyncHandler.SyncContainerMetadata)
@@ -97,6 +157,7 @@ func SetupRouter(
 		adminMigrations.GET("", migrationHandler.ListMigrations)
 		adminMigrations.POST("", migrationHandler.CreateManualMigration)
 		adminMigrations.GET("/stats", migrationHandler.GetMigrationStats)
+		adminMigrations.GET("/analytics", migrationHandler.GetMigrationAnalytics)
 		adminMigrations.GET("/:id", migrationHandler.GetMigration)
 		adminMigrations.POST("/:id/approve", migrationHandler.ApproveMigration)
 		adminMigrations.POST("/:id/schedule", migrationHandler.ScheduleMigration)
@@ -114,6 +175,19 @@ func SetupRouter(
 	router.GET("/ws", wsHandler.HandleWebSocket)
 	router.GET("/api/ws/stats", wsHandler.GetStats)
 
+	// Stripe webhooks (no JWT - verified via Stripe-Signature)
+	router.POST("/webhooks/stripe", stripeHandler.HandleWebhook)
+
+	// Alertmanager webhook receiver (no JWT - verified via bearer token) and public status page
+	router.POST("/webhooks/alertmanager", alertHandler.AlertmanagerWebhook)
+	router.GET("/status", alertHandler.GetStatus)
+
+	// World export downloads (public, authenticated by the signed URL from GET /api/servers/:id/worlds/exports)
+	router.GET("/api/world-exports/:export_id/download", worldHandler.DownloadExport)
+
+	// Vote relay for server list sites (public, authenticated by the per-server relay token)
+	router.POST("/votes/:server_id", middleware.RateLimitMiddleware(middleware.APIRateLimiter), votifierHandler.RelayVote)
+
 	// Auth endpoints (no auth required, but with strict rate limiting)
 	auth := router.Group("/api/auth")
 	auth.Use(middleware.RateLimitMiddleware(middleware.AuthRateLimiter))  // Strict auth rate limiting
@@ -139,17 +213,66 @@ func SetupRouter(
 		auth.GET("/oauth/github", oauthHandler.GitHubLogin)
 		auth.GET("/oauth/github/callback", oauthHandler.GitHubCallback)
 
+		// Linked OAuth providers (requires auth)
+		auth.GET("/oauth/providers", middleware.AuthMiddleware(), oauthHandler.ListProviders)
+		auth.DELETE("/oauth/providers/:provider", middleware.AuthMiddleware(), oauthHandler.UnlinkProvider)
+
 		// Protected auth routes (require authentication)
 		auth.GET("/profile", middleware.AuthMiddleware(), authHandler.GetProfile)
 		auth.PUT("/profile", middleware.AuthMiddleware(), authHandler.UpdateProfile)
 		auth.POST("/change-password", middleware.AuthMiddleware(), authHandler.ChangePassword)
-		auth.DELETE("/account", middleware.AuthMiddleware(), authHandler.DeleteAccount)
+		auth.DELETE("/account", middleware.AuthMiddleware(), middleware.RequireTwoFactor(models.SensitiveAccountDelete), authHandler.DeleteAccount)
+
+		// Two-factor authentication (TOTP + recovery codes)
+		auth.POST("/2fa/login", twoFactorHandler.CompleteLogin) // Second login step with the challenge token
+		auth.GET("/2fa", middleware.AuthMiddleware(), twoFactorHandler.GetStatus)
+		auth.POST("/2fa/enroll", middleware.AuthMiddleware(), twoFactorHandler.BeginEnrollment)
+		auth.POST("/2fa/confirm", middleware.AuthMiddleware(), twoFactorHandler.ConfirmEnrollment)
+		auth.POST("/2fa/disable", middleware.AuthMiddleware(), twoFactorHandler.Disable)
+		auth.POST("/2fa/recovery-codes", middleware.AuthMiddleware(), twoFactorHandler.RegenerateRecoveryCodes)
+
+		// Linked Minecraft account (code whispered in-game)
+		auth.GET("/minecraft", middleware.AuthMiddleware(), minecraftLinkHandler.GetLink)
+		auth.PUT("/minecraft", middleware.AuthMiddleware(), minecraftLinkHandler.UpdateLink)
+		auth.DELETE("/minecraft", middleware.AuthMiddleware(), minecraftLinkHandler.Unlink)
+		auth.POST("/minecraft/link", middleware.AuthMiddleware(), minecraftLinkHandler.StartLink)
+		auth.POST("/minecraft/verify", middleware.AuthMiddleware(), minecraftLinkHandler.ConfirmLink)
+
+		// Organization single sign-on (no auth required)
+		auth.POST("/sso/discover", ssoHandler.Discover)
+		auth.POST("/sso/login", ssoHandler.Login)
+		auth.GET("/sso/callback", ssoHandler.Callback)
+		auth.POST("/sso/link", ssoHandler.ConfirmLink) // Confirm linking an existing account (password or emailed token)
+	}
+
+	// Per-server permission check (owner, organization role or share) for routes with :id
+	perm := middleware.RequireServerPermission
+
+	// Second factor for sensitive operations (if the user has 2FA enabled, code in the X-2FA-Code header)
+	twoFA := middleware.RequireTwoFactor
+
+	// Stricter per-user limit for operations that provision or start containers
+	expensive := middleware.RateLimitMiddleware(middleware.ExpensiveRateLimiter)
+
+	// Rejected while maintenance mode is on: server starts and destructive operations
+	maintenance := middleware.Maintenance(maintenanceHandler.maintenance)
+
+	// WebDAV network drives of server directories (Basic auth with an API key as password)
+	dav := router.Group("/dav", middleware.WebDAVAuthMiddleware())
+	for _, method := range webdavReadMethods {
+		dav.Handle(method, "/:id", perm(models.PermServerFilesRead), webdavHandler.ServeDAV)
+		dav.Handle(method, "/:id/*path", perm(models.PermServerFilesRead), webdavHandler.ServeDAV)
+	}
+	for _, method := range webdavWriteMethods {
+		dav.Handle(method, "/:id", perm(models.PermServerFilesWrite), webdavHandler.ServeDAV)
+		dav.Handle(method, "/:id/*path", perm(models.PermServerFilesWrite), webdavHandler.ServeDAV)
 	}
 
 	// API routes (with auth and API-specific rate limiting)
 	api := router.Group("/api")
-	api.Use(middleware.AuthMiddleware())                                // Auth with JWT
+	api.Use(middleware.AuthMiddleware())                                // Auth with JWT or API key
 	api.Use(middleware.RateLimitMiddleware(middleware.APIRateLimiter))  // API rate limiting
+	api.Use(middleware.Idempotency())                                   // Replay responses of retried requests with an Idempotency-Key
 	{
 		// Server Templates (public within auth)
 		templates := api.Group("/templates")
@@ -160,139 +283,288 @@ func SetupRouter(
 			templates.GET("/category/:category", templateHandler.GetTemplatesByCategory)
 			templates.GET("/search", templateHandler.SearchTemplates)
 			templates.GET("/recommendations", templateHandler.GetRecommendations)
+			templates.GET("/mine", templateHandler.ListMyTemplates)
+			templates.GET("/marketplace", templateHandler.ListMarketplace)
+			templates.POST("", templateHandler.CreateTemplate)
 			templates.GET("/:id", templateHandler.GetTemplate)
+			templates.PUT("/:id", templateHandler.UpdateTemplate)
+			templates.DELETE("/:id", templateHandler.DeleteTemplate)
+			templates.GET("/:id/revisions", templateHandler.ListTemplateRevisions)
+			templates.POST("/:id/publish", templateHandler.PublishTemplate)
+			templates.DELETE("/:id/publish", templateHandler.UnpublishTemplate)
 		}
 
+		// GraphQL: composite read views in one round trip, subscriptions over WebSocket (graphql-transport-ws)
+		api.POST("/graphql", graphQLHandler.Query)
+		api.GET("/graphql", graphQLHandler.HandleWebSocket)
+
 		// Server management
 		servers := api.Group("/servers")
 		{
-			servers.POST("", handler.CreateServer)
+			servers.POST("", maintenance, expensive, handler.CreateServer)
 			servers.GET("", handler.ListServers)
-			servers.GET("/:id", handler.GetServer)
-			servers.GET("/:id/connection", handler.GetServerConnectionInfo) // Connection info (IP + Port)
-			servers.POST("/:id/start", handler.StartServer)
-			servers.POST("/:id/stop", handler.StopServer)
-			servers.DELETE("/:id", handler.DeleteServer)
-			servers.GET("/:id/usage", handler.GetServerUsage)
-			servers.GET("/:id/logs", handler.GetServerLogs)
-			servers.POST("/:id/apply-template", templateHandler.ApplyTemplate)
+			servers.GET("/:id", perm(models.PermServerView), handler.GetServer)
+			servers.GET("/:id/connection", perm(models.PermServerView), handler.GetServerConnectionInfo) // Connection info (IP + Port)
+			servers.POST("/:id/start", maintenance, expensive, perm(models.PermServerPower), handler.StartServer)
+			servers.POST("/:id/stop", perm(models.PermServerPower), handler.StopServer)
+			servers.DELETE("/:id", maintenance, perm(models.PermServerManage), twoFA(models.SensitiveServerDelete), handler.DeleteServer)
+			servers.POST("/:id/ram", maintenance, expensive, perm(models.PermServerManage), handler.UpgradeServerRAM) // Restarts a running server
+			servers.GET("/:id/release-channel", perm(models.PermServerView), handler.GetReleaseChannel)
+			servers.PUT("/:id/release-channel", perm(models.PermServerManage), handler.SetReleaseChannel) // stable/beta for managed changes
+			servers.PUT("/:id/affinity", perm(models.PermServerManage), handler.SetServerAffinity) // Anti-affinity group / spread across nodes
+			servers.GET("/:id/usage", perm(models.PermServerView), handler.GetServerUsage)
+			servers.GET("/:id/logs", perm(models.PermServerConsole), handler.GetServerLogs)
+			servers.POST("/:id/diagnose", expensive, perm(models.PermServerConsole), diagnosisHandler.Diagnose) // "My server won't start" checks
+			// Permissions & per-server shares (every /:id route needs perm: API key scopes are checked there)
+			servers.GET("/:id/permissions", perm(models.PermServerView), shareHandler.GetPermissions)
+			servers.GET("/:id/shares", perm(models.PermServerShare), shareHandler.ListShares)
+			servers.POST("/:id/shares", perm(models.PermServerShare), shareHandler.CreateShare)
+			servers.PUT("/:id/shares/:share_id", perm(models.PermServerShare), shareHandler.UpdateShare)
+			servers.DELETE("/:id/shares/:share_id", perm(models.PermServerShare), shareHandler.RevokeShare)
+
+			servers.POST("/:id/apply-template", perm(models.PermServerFilesWrite), templateHandler.ApplyTemplate)
+			servers.POST("/:id/clone", maintenance, expensive, perm(models.PermServerManage), cloneHandler.CloneServer) // Copy world, config and plugins into a new server
+
+			// Remote actions for automation (bots, CI): the permission and API key scope depend on the action,
+			// checked by the handler like perm
+			servers.GET("/:id/actions", remoteActionHandler.ListActions)
+			servers.POST("/:id/actions/:action", middleware.RateLimitMiddleware(middleware.RemoteActionRateLimiter), remoteActionHandler.RunAction)
 
 			// Monitoring
-			servers.GET("/:id/status", monitoringHandler.GetServerStatus)
-			servers.POST("/:id/auto-shutdown/enable", monitoringHandler.EnableAutoShutdown)
-			servers.POST("/:id/auto-shutdown/disable", monitoringHandler.DisableAutoShutdown)
+			servers.GET("/:id/status", perm(models.PermServerView), monitoringHandler.GetServerStatus)
+			servers.POST("/:id/auto-shutdown/enable", perm(models.PermServerPower), monitoringHandler.EnableAutoShutdown)
+			servers.POST("/:id/auto-shutdown/disable", perm(models.PermServerPower), monitoringHandler.DisableAutoShutdown)
+
+			// Idle-Shutdown Policies
+			servers.GET("/:id/idle-policy", perm(models.PermServerView), idlePolicyHandler.GetPolicy)
+			servers.PUT("/:id/idle-policy", perm(models.PermServerPower), idlePolicyHandler.UpdatePolicy)
+			servers.DELETE("/:id/idle-policy", perm(models.PermServerPower), idlePolicyHandler.DeletePolicy)
+			servers.GET("/:id/idle-policy/audit", perm(models.PermServerView), idlePolicyHandler.GetShutdownAudit)
+
+			// Lifecycle thresholds (sleep, archive, cold storage) with the upcoming transitions
+			servers.GET("/:id/lifecycle", perm(models.PermServerView), lifecycleHandler.GetLifecycle)
+			servers.PUT("/:id/lifecycle", perm(models.PermServerPower), lifecycleHandler.UpdateLifecycle)
+			servers.DELETE("/:id/lifecycle", perm(models.PermServerPower), lifecycleHandler.DeleteLifecycle)
 
 			// Backups (with stricter rate limiting for expensive operations)
 			backups := servers.Group("/:id/backups")
-			backups.Use(middleware.RateLimitMiddleware(middleware.ExpensiveRateLimiter))
+			backups.Use(expensive)
 			{
-				backups.POST("", backupHandler.CreateBackup)           // Create backup
-				backups.GET("", backupHandler.ListBackups)             // List server backups
-				backups.POST("/restore", backupHandler.RestoreBackup)  // Restore backup
-				backups.GET("/stats", backupHandler.GetServerBackupStats) // Get server backup stats
+				backups.POST("", perm(models.PermServerBackup), backupHandler.CreateBackup)           // Create backup
+				backups.GET("", perm(models.PermServerView), backupHandler.ListBackups)
+				backups.POST("/restore", maintenance, perm(models.PermServerManage), backupHandler.RestoreBackup) // Restore backup
+				backups.GET("/stats", perm(models.PermServerView), backupHandler.GetServerBackupStats) // Get server backup stats
 			}
 
 			// Plugins
-			servers.POST("/:id/plugins", pluginHandler.InstallPlugin)
-			servers.GET("/:id/plugins", pluginHandler.ListPlugins)
-			servers.DELETE("/:id/plugins/:filename", pluginHandler.RemovePlugin)
+			servers.POST("/:id/plugins", perm(models.PermServerFilesWrite), pluginHandler.InstallPlugin)
+			servers.GET("/:id/plugins", perm(models.PermServerView), pluginHandler.ListPlugins)
+			servers.DELETE("/:id/plugins/:filename", perm(models.PermServerFilesWrite), pluginHandler.RemovePlugin)
 
 			// Mod packs
-			servers.POST("/:id/modpack", pluginHandler.InstallModPack)
+			servers.POST("/:id/modpack", perm(models.PermServerFilesWrite), pluginHandler.InstallModPack)
 
 			// File Manager (server.properties, configs, etc.)
-			servers.GET("/:id/files", fileManagerHandler.GetAllowedFiles)
-			servers.GET("/:id/files/read", fileManagerHandler.ReadFile)
-			servers.POST("/:id/files/write", fileManagerHandler.WriteFile)
-			servers.GET("/:id/files/list", fileManagerHandler.ListFiles)
+			servers.GET("/:id/files", perm(models.PermServerFilesRead), fileManagerHandler.GetAllowedFiles)
+			servers.GET("/:id/files/read", perm(models.PermServerFilesRead), fileManagerHandler.ReadFile)
+			servers.POST("/:id/files/write", perm(models.PermServerFilesWrite), fileManagerHandler.WriteFile)
+			servers.GET("/:id/files/list", perm(models.PermServerFilesRead), fileManagerHandler.ListFiles)
+			servers.POST("/:id/files/delete", perm(models.PermServerFilesWrite), fileManagerHandler.DeleteFiles)
+			servers.POST("/:id/files/move", perm(models.PermServerFilesWrite), fileManagerHandler.MoveFiles)
+			servers.POST("/:id/files/chmod", perm(models.PermServerFilesWrite), fileManagerHandler.ChmodFile)
+			servers.POST("/:id/files/compress", perm(models.PermServerFilesWrite), fileManagerHandler.CompressFiles)
+			servers.POST("/:id/files/extract", perm(models.PermServerFilesWrite), fileManagerHandler.ExtractArchive)
+
+			// Resumable chunked uploads (tus-style, Upload-Offset header)
+			servers.POST("/:id/files/uploads", perm(models.PermServerFilesWrite), fileManagerHandler.CreateUpload)
+			servers.GET("/:id/files/uploads/:upload_id", perm(models.PermServerFilesWrite), fileManagerHandler.GetUpload)
+			servers.HEAD("/:id/files/uploads/:upload_id", perm(models.PermServerFilesWrite), fileManagerHandler.GetUpload)
+			servers.PATCH("/:id/files/uploads/:upload_id", perm(models.PermServerFilesWrite), fileManagerHandler.AppendUpload)
+			servers.DELETE("/:id/files/uploads/:upload_id", perm(models.PermServerFilesWrite), fileManagerHandler.CancelUpload)
 
 			// Uploaded Files (resource packs, data packs, icons, world gen)
 			uploads := servers.Group("/:id/uploads")
 			uploads.Use(middleware.RateLimitMiddleware(middleware.FileUploadRateLimiter))
 			{
-				uploads.POST("", fileHandler.UploadFile)
-				uploads.GET("", fileHandler.ListFiles)
-				uploads.GET("/:fileId", fileHandler.GetFile)
-				uploads.PUT("/:fileId/activate", fileHandler.ActivateFile)
-				uploads.PUT("/:fileId/deactivate", fileHandler.DeactivateFile)
-				uploads.DELETE("/:fileId", fileHandler.DeleteFile)
+				uploads.POST("", perm(models.PermServerFilesWrite), fileHandler.UploadFile)
+				uploads.GET("", perm(models.PermServerFilesRead), fileHandler.ListFiles)
+				uploads.GET("/:fileId", perm(models.PermServerFilesRead), fileHandler.GetFile)
+				uploads.PUT("/:fileId/activate", perm(models.PermServerFilesWrite), fileHandler.ActivateFile)
+				uploads.PUT("/:fileId/deactivate", perm(models.PermServerFilesWrite), fileHandler.DeactivateFile)
+				uploads.DELETE("/:fileId", perm(models.PermServerFilesWrite), fileHandler.DeleteFile)
 			}
 
 			// Console Access (WebSocket for real-time logs and command execution)
-			servers.GET("/:id/console/stream", consoleHandler.HandleConsoleWebSocket)
-			servers.GET("/:id/console/logs", consoleHandler.GetConsoleLogs)
-			servers.POST("/:id/console/command", consoleHandler.ExecuteConsoleCommand)
+			servers.GET("/:id/console/stream", perm(models.PermServerConsole), consoleHandler.HandleConsoleWebSocket)
+			servers.GET("/:id/console/logs", perm(models.PermServerConsole), consoleHandler.GetConsoleLogs)
+			servers.POST("/:id/console/command", perm(models.PermServerConsole), consoleHandler.ExecuteConsoleCommand)
+
+			// SFTP access (login details and audit log of the SFTP gateway)
+			servers.GET("/:id/sftp", perm(models.PermServerFilesRead), sftpHandler.GetConnectionInfo)
+			servers.GET("/:id/sftp/audit", perm(models.PermServerManage), sftpHandler.ListAudit)
+
+			// Disk usage (size of the server directory and its quota)
+			servers.GET("/:id/disk", perm(models.PermServerView), diskHandler.GetDiskUsage)
+
+			// Performance graphs (container CPU, memory, network and disk IO from InfluxDB)
+			servers.GET("/:id/performance", perm(models.PermServerView), performanceHandler.GetPerformance)
 
 			// Configuration Management
-			servers.POST("/:id/config", configHandler.ApplyConfigChanges)
-			servers.GET("/:id/config/history", configHandler.GetConfigHistory)
+			servers.POST("/:id/config", perm(models.PermServerFilesWrite), configHandler.ApplyConfigChanges)
+			servers.GET("/:id/config/history", perm(models.PermServerFilesRead), configHandler.GetConfigHistory)
 
 			// MOTD (Message of the Day)
-			servers.GET("/:id/motd", motdHandler.GetMOTD)
-			servers.PUT("/:id/motd", motdHandler.UpdateMOTD)
+			servers.GET("/:id/motd", perm(models.PermServerView), motdHandler.GetMOTD)
+			servers.PUT("/:id/motd", perm(models.PermServerFilesWrite), motdHandler.UpdateMOTD)
 
 			// Server Icon (publicly accessible for display)
-			servers.GET("/:id/icon", fileHandler.GetServerIcon)
+			servers.GET("/:id/icon", perm(models.PermServerView), fileHandler.GetServerIcon)
 
 			// Player Management (Whitelist, Ops, Banned)
-			servers.GET("/:id/players/:listType", playerHandler.GetPlayerList)
-			servers.POST("/:id/players/:listType/add", playerHandler.AddToPlayerList)
-			servers.DELETE("/:id/players/:listType/:username", playerHandler.RemoveFromPlayerList)
+			servers.GET("/:id/players/:listType", perm(models.PermServerView), playerHandler.GetPlayerList)
+			servers.PUT("/:id/players/:listType", perm(models.PermServerConsole), playerHandler.ReplacePlayerList)
+			servers.POST("/:id/players/:listType/add", perm(models.PermServerConsole), playerHandler.AddToPlayerList)
+			servers.DELETE("/:id/players/:listType/:username", perm(models.PermServerConsole), playerHandler.RemoveFromPlayerList)
 
 			// Online & Historic Players
-			servers.GET("/:id/players-online", playerHandler.GetOnlinePlayers)
-			servers.GET("/:id/players-history", playerHandler.GetHistoricPlayers)
+			servers.GET("/:id/players-online", perm(models.PermServerView), playerHandler.GetOnlinePlayers)
+			servers.GET("/:id/players-history", perm(models.PermServerView), playerHandler.GetHistoricPlayers)
 
 			// World Management
-			servers.GET("/:id/worlds", worldHandler.ListWorlds)
-			servers.GET("/:id/worlds/:name/download", worldHandler.DownloadWorld)
-			servers.POST("/:id/worlds/upload", worldHandler.UploadWorld)
-			servers.POST("/:id/worlds/:name/reset", worldHandler.ResetWorld)
-			servers.DELETE("/:id/worlds/:name", worldHandler.DeleteWorld)
+			servers.GET("/:id/worlds", perm(models.PermServerView), worldHandler.ListWorlds)
+			servers.GET("/:id/worlds/:name/download", perm(models.PermServerFilesRead), worldHandler.DownloadWorld)
+			servers.POST("/:id/worlds/upload", maintenance, perm(models.PermServerManage), worldHandler.UploadWorld)
+			servers.POST("/:id/worlds/reset", maintenance, expensive, perm(models.PermServerManage), twoFA(models.SensitiveWorldReset), worldHandler.ResetWorlds)
+			servers.POST("/:id/worlds/export", expensive, perm(models.PermServerFilesRead), worldHandler.ExportWorlds)
+			servers.GET("/:id/worlds/exports", perm(models.PermServerFilesRead), worldHandler.ListExports)
+			servers.GET("/:id/worlds/exports/:export_id", perm(models.PermServerFilesRead), worldHandler.GetExport)
+			servers.POST("/:id/worlds/import", maintenance, expensive, perm(models.PermServerManage), worldHandler.ImportWorlds)
+
+			// Archive of an archived server: manifest and selective restore
+			servers.GET("/:id/archive", perm(models.PermServerView), archiveHandler.GetArchiveManifest)
+			servers.POST("/:id/archive/restore", maintenance, expensive, perm(models.PermServerManage), archiveHandler.RestoreArchiveParts)
+
+			// Chunk pre-generation (Chunky or force-loading, TPS-aware)
+			servers.GET("/:id/pregeneration", perm(models.PermServerView), pregenHandler.ListPregenerations)
+			servers.POST("/:id/pregeneration", expensive, perm(models.PermServerManage), pregenHandler.StartPregeneration)
+			servers.GET("/:id/pregeneration/:job_id", perm(models.PermServerView), pregenHandler.GetPregeneration)
+			servers.POST("/:id/pregeneration/:job_id/pause", perm(models.PermServerManage), pregenHandler.PausePregeneration)
+			servers.POST("/:id/pregeneration/:job_id/resume", perm(models.PermServerManage), pregenHandler.ResumePregeneration)
+			servers.DELETE("/:id/pregeneration/:job_id", perm(models.PermServerManage), pregenHandler.CancelPregeneration)
+			servers.POST("/:id/worlds/:name/reset", maintenance, perm(models.PermServerManage), worldHandler.ResetWorld)
+			servers.DELETE("/:id/worlds/:name", maintenance, perm(models.PermServerManage), worldHandler.DeleteWorld)
+
+			// JVM Heap Dumps (modded servers; dumps may contain player data, so owner/admin only)
+			servers.GET("/:id/heapdumps", perm(models.PermServerManage), heapDumpHandler.ListHeapDumps)
+			servers.PUT("/:id/heapdumps/settings", perm(models.PermServerManage), heapDumpHandler.UpdateHeapDumpSettings)
+			servers.GET("/:id/heapdumps/:name/download", perm(models.PermServerManage), heapDumpHandler.DownloadHeapDump)
+			servers.DELETE("/:id/heapdumps/:name", perm(models.PermServerManage), heapDumpHandler.DeleteHeapDump)
+
+			// Vote Site Integration (managed NuVotifier)
+			servers.GET("/:id/votifier", perm(models.PermServerManage), votifierHandler.GetVotifier)
+			servers.POST("/:id/votifier", perm(models.PermServerManage), votifierHandler.EnableVotifier)
+			servers.DELETE("/:id/votifier", perm(models.PermServerManage), votifierHandler.DisableVotifier)
 
 			// Cost Analytics & Billing
-			servers.GET("/:id/costs", billingHandler.GetServerCosts)
-			servers.GET("/:id/billing/events", billingHandler.GetBillingEvents)
-			servers.GET("/:id/billing/sessions", billingHandler.GetUsageSessions)
-
-			// Discord Webhooks
-			servers.GET("/:id/webhook", webhookHandler.GetWebhook)
-			servers.POST("/:id/webhook", webhookHandler.CreateWebhook)
-			servers.PUT("/:id/webhook", webhookHandler.UpdateWebhook)
-			servers.DELETE("/:id/webhook", webhookHandler.DeleteWebhook)
-			servers.POST("/:id/webhook/test", webhookHandler.TestWebhook)
+			servers.GET("/:id/costs", perm(models.PermServerManage), billingHandler.GetServerCosts)
+			servers.GET("/:id/billing/events", perm(models.PermServerManage), billingHandler.GetBillingEvents)
+			servers.GET("/:id/billing/sessions", perm(models.PermServerManage), billingHandler.GetUsageSessions)
+			servers.GET("/:id/budget", perm(models.PermServerManage), budgetHandler.GetServerBudget)
+			servers.PUT("/:id/budget", perm(models.PermServerManage), budgetHandler.UpdateServerBudget)
+			servers.DELETE("/:id/budget", perm(models.PermServerManage), budgetHandler.DeleteServerBudget)
+
+			// Discord/Slack Webhooks
+			servers.GET("/:id/webhook", perm(models.PermServerManage), webhookHandler.GetWebhook)
+			servers.POST("/:id/webhook", perm(models.PermServerManage), webhookHandler.CreateWebhook)
+			servers.PUT("/:id/webhook", perm(models.PermServerManage), webhookHandler.UpdateWebhook)
+			servers.DELETE("/:id/webhook", perm(models.PermServerManage), webhookHandler.DeleteWebhook)
+			servers.POST("/:id/webhook/test", perm(models.PermServerManage), webhookHandler.TestWebhook)
 
 			// Backup Schedules
-			servers.GET("/:id/backup-schedule", backupScheduleHandler.GetSchedule)
-			servers.POST("/:id/backup-schedule", backupScheduleHandler.CreateSchedule)
-			servers.PUT("/:id/backup-schedule", backupScheduleHandler.UpdateSchedule)
-			servers.DELETE("/:id/backup-schedule", backupScheduleHandler.DeleteSchedule)
+			servers.GET("/:id/backup-schedule", perm(models.PermServerView), backupScheduleHandler.GetSchedule)
+			servers.POST("/:id/backup-schedule", perm(models.PermServerBackup), backupScheduleHandler.CreateSchedule)
+			servers.PUT("/:id/backup-schedule", perm(models.PermServerBackup), backupScheduleHandler.UpdateSchedule)
+			servers.DELETE("/:id/backup-schedule", perm(models.PermServerBackup), backupScheduleHandler.DeleteSchedule)
 
 			// Plugin Marketplace (new marketplace system)
-			servers.GET("/:id/marketplace/plugins", marketplaceHandler.ListInstalledPlugins)
-			servers.POST("/:id/marketplace/plugins", marketplaceHandler.InstallPlugin)
-			servers.DELETE("/:id/marketplace/plugins/:plugin_id", marketplaceHandler.UninstallPlugin)
-			servers.GET("/:id/marketplace/updates", marketplaceHandler.CheckForUpdates)
-			servers.PUT("/:id/marketplace/plugins/:plugin_id", marketplaceHandler.UpdatePlugin)
-			servers.POST("/:id/marketplace/auto-update", marketplaceHandler.AutoUpdatePlugins)
-			servers.POST("/:id/marketplace/plugins/:plugin_id/toggle", marketplaceHandler.TogglePlugin)
-			servers.POST("/:id/marketplace/plugins/:plugin_id/auto-update", marketplaceHandler.ToggleAutoUpdate)
+			servers.GET("/:id/marketplace/plugins", perm(models.PermServerView), marketplaceHandler.ListInstalledPlugins)
+			servers.POST("/:id/marketplace/plugins", perm(models.PermServerFilesWrite), marketplaceHandler.InstallPlugin)
+			servers.DELETE("/:id/marketplace/plugins/:plugin_id", perm(models.PermServerFilesWrite), marketplaceHandler.UninstallPlugin)
+			servers.GET("/:id/marketplace/updates", perm(models.PermServerView), marketplaceHandler.CheckForUpdates)
+			servers.PUT("/:id/marketplace/plugins/:plugin_id", perm(models.PermServerFilesWrite), marketplaceHandler.UpdatePlugin)
+			servers.POST("/:id/marketplace/auto-update", perm(models.PermServerFilesWrite), marketplaceHandler.AutoUpdatePlugins)
+			servers.POST("/:id/marketplace/plugins/:plugin_id/toggle", perm(models.PermServerFilesWrite), marketplaceHandler.TogglePlugin)
+			servers.POST("/:id/marketplace/plugins/:plugin_id/auto-update", perm(models.PermServerFilesWrite), marketplaceHandler.ToggleAutoUpdate)
 
 			// Bulk Operations (multi-server management)
 			bulk := servers.Group("/bulk")
 			{
-				bulk.POST("/start", bulkHandler.BulkStartServers)
+				bulk.POST("/start", maintenance, bulkHandler.BulkStartServers)
 				bulk.POST("/stop", bulkHandler.BulkStopServers)
-				bulk.POST("/delete", bulkHandler.BulkDeleteServers)
+				bulk.POST("/delete", maintenance, twoFA(models.SensitiveServerDelete), bulkHandler.BulkDeleteServers)
 				bulk.POST("/backup", bulkHandler.BulkBackupServers)
+				bulk.POST("/restart", maintenance, expensive, bulkHandler.BulkRestartServers) // Rolling restart, N servers at a time
+				bulk.POST("/plugins/install", bulkHandler.BulkInstallPlugin)
+				bulk.POST("/plugins/remove", bulkHandler.BulkRemovePlugin)
+				bulk.POST("/config", bulkHandler.BulkApplyConfig)
+				bulk.GET("/jobs", bulkHandler.ListBulkJobs)
+				bulk.GET("/jobs/:job_id", bulkHandler.GetBulkJob)
 			}
 		}
 
 		// Admin endpoints
 		admin := api.Group("/admin")
 		{
-			admin.GET("/servers", handler.ListAllServers)             // List ALL servers
+			admin.GET("/servers", handler.ListAllServers)
 			admin.POST("/cleanup", handler.CleanOrphanedServers)      // Clean orphaned servers
+			admin.POST("/budgets/:cap_id/override", budgetHandler.SetOverride)
+			admin.DELETE("/budgets/:cap_id/override", budgetHandler.ClearOverride)
+			admin.GET("/control-plane", monitoringHandler.GetControlPlaneStatus) // Control plane CPU/memory/disk pressure
+			admin.PUT("/nodes/:node_id/placement", conductorHandler.SetNodePlacement) // Node labels/taints
+			admin.GET("/dedicated-nodes", dedicatedNodeHandler.ListNodes)                // Nodes reserved for single customers
+			admin.POST("/nodes/:node_id/dedicated", dedicatedNodeHandler.AssignNode)
+			admin.DELETE("/nodes/:node_id/dedicated", dedicatedNodeHandler.ReleaseNode)
+			admin.PUT("/servers/:id/placement", handler.SetServerPlacement)          // Server node selector/tolerations/pinned node
+			admin.PUT("/servers/:id/disk-quota", diskHandler.SetDiskQuota)               // Disk quota in MB (0 = default)
+			admin.PUT("/servers/:id/cpu", handler.SetServerCPULimits)                    // CPU shares/cap (0 = default)
+			admin.POST("/servers/:id/suspend", handler.SuspendServer)                    // Abuse/billing hold: stop, block starts, keep files
+			admin.POST("/servers/:id/unsuspend", handler.UnsuspendServer)                // Lift a suspension (server returns to stopped)
+			admin.GET("/events/replay", eventReplayHandler.ListReplayTargets)            // Event sources and replay targets
+			admin.POST("/events/replay", eventReplayHandler.ReplayEvents)                // Replay stored events (dry-run supported)
+			admin.GET("/legal-holds", backupHandler.ListLegalHolds)                      // Backup legal holds (include_released=true for history)
+			admin.POST("/legal-holds", backupHandler.PlaceLegalHold)                     // Hold a backup or all backups of a server
+			admin.DELETE("/legal-holds/:hold_id", backupHandler.ReleaseLegalHold)        // Release a hold
+			admin.POST("/sso/organizations/:org_id/domains/:domain/approve", ssoHandler.ApproveDomain) // Verify an SSO domain without DNS proof
+			admin.GET("/audit", auditHandler.ListAudit)                                  // Audit log (user/action/resource filters, from/to)
+			admin.GET("/maintenance", maintenanceHandler.GetMaintenance)                 // Maintenance mode and running operations
+			admin.PUT("/maintenance", maintenanceHandler.SetMaintenance)                 // Reject starts/destructive operations, pause scaling and migrations
+			admin.GET("/announcements", announcementHandler.ListAnnouncements)
+			admin.POST("/announcements", announcementHandler.CreateAnnouncement)          // Chat broadcast to running servers, now or scheduled
+			admin.POST("/announcements/preview", announcementHandler.PreviewAnnouncement) // Rendered message and target count
+			admin.GET("/announcements/:announcement_id", announcementHandler.GetAnnouncement)
+			admin.DELETE("/announcements/:announcement_id", announcementHandler.CancelAnnouncement)
+			admin.GET("/bans", playerBanHandler.ListGlobalBans)
+			admin.POST("/bans", playerBanHandler.CreateGlobalBan) // Enforced at the Velocity proxy for the whole network
+			admin.DELETE("/bans/:ban_id", playerBanHandler.RevokeGlobalBan)
+			admin.GET("/scaling/simulate", scalingHandler.SimulateScaling) // What the scaling policies would do now (threshold overrides), nothing executed
+			admin.GET("/scaling/policies", scalingHandler.ListScalingPolicies)
+			admin.PUT("/scaling/policies/:policy", scalingHandler.UpdateScalingPolicy) // engine, reactive or consolidation settings, applied before the next evaluation
+			admin.DELETE("/scaling/policies/:policy", scalingHandler.DeleteScalingPolicy)
+			admin.GET("/config", runtimeConfigHandler.GetConfig)                         // Effective configuration, secrets redacted
+			admin.POST("/config/reload", runtimeConfigHandler.ReloadConfig)              // Apply changed reloadable settings now
+			admin.GET("/ssh-keys", sshKeyHandler.ListSSHKeys)                            // Node SSH keys of this environment
+			admin.POST("/ssh-keys/rotate", sshKeyHandler.RotateSSHKey)                   // New node key on all nodes, old one retired after the grace period
+			admin.GET("/schema", schemaHandler.GetSchemaStatus)                          // Applied and pending database schema migrations
+			admin.GET("/usage-archives", usageArchiveHandler.ListUsageArchives)          // Archived months of usage logs and events
+			admin.POST("/usage-archives/run", usageArchiveHandler.RunUsageArchive)       // Export and delete months past the retention now
+			admin.GET("/reconcile", reconcileHandler.GetReconcileReport)                 // Phases of the last state reconciliation
+			admin.POST("/reconcile", reconcileHandler.RunReconcile)                      // Rerun the container, queue, node and Velocity sync
+			admin.GET("/drift", driftHandler.GetDriftReport)                             // Drift found by the last check, with the action per server
+			admin.POST("/drift/check", driftHandler.CheckDrift)                          // Compare database, Docker and Velocity now
+			admin.GET("/diagnostics", diagnosticsHandler.RunDiagnostics)                 // Probe every dependency now: status, latency, last error
+			admin.GET("/lifecycle-policies", lifecycleHandler.ListPlanPolicies)          // Effective lifecycle thresholds per plan
+			admin.PUT("/lifecycle-policies/:plan", lifecycleHandler.UpdatePlanPolicy)    // Sleep/archive/cold thresholds of a plan
+			admin.DELETE("/lifecycle-policies/:plan", lifecycleHandler.DeletePlanPolicy) // Back to the defaults
 		}
 
 		// Global monitoring
@@ -319,6 +591,137 @@ func SetupRouter(
 		billing := api.Group("/billing")
 		{
 			billing.GET("/costs", billingHandler.GetOwnerCosts)
+			billing.GET("/dedicated-nodes", dedicatedNodeHandler.ListMyNodes)
+			billing.GET("/servers/:id/breakdown", perm(models.PermServerManage), billingHandler.GetCostBreakdown) // Daily cost explorer
+
+			// Fleet cost dashboard (admin): estimated node costs reconciled with the provider's billing
+			billing.GET("/fleet", fleetCostHandler.GetFleetCosts)
+			billing.POST("/fleet/reconcile", fleetCostHandler.ReconcileFleetCosts)
+			billing.GET("/fleet/nodes/:node_id/timeline", fleetCostHandler.GetNodeCostTimeline)
+
+			// Budget caps & spending alerts
+			billing.GET("/budgets", budgetHandler.ListBudgets)
+			billing.GET("/budget", budgetHandler.GetUserBudget)
+			billing.PUT("/budget", budgetHandler.UpdateUserBudget)
+			billing.DELETE("/budget", budgetHandler.DeleteUserBudget)
+			billing.GET("/budget/alerts", budgetHandler.GetAlerts)
+
+			// Invoice documents & export
+			billing.GET("/invoices/documents", invoiceHandler.ListDocuments)
+			billing.POST("/invoices/documents", invoiceHandler.GenerateDocument)
+			billing.GET("/invoices/documents/:invoice_id", invoiceHandler.GetDocument)
+			billing.GET("/invoices/documents/:invoice_id/pdf", invoiceHandler.DownloadPDF)
+			billing.GET("/invoices/documents/:invoice_id/csv", invoiceHandler.DownloadCSV)
+			billing.GET("/export", invoiceHandler.Export)
+			billing.GET("/profile", invoiceHandler.GetBillingProfile)
+			billing.PUT("/profile", twoFA(models.SensitivePaymentSettings), invoiceHandler.UpdateBillingProfile)
+
+			// Stripe metered billing
+			billing.GET("/subscription", stripeHandler.GetSubscription)
+			billing.POST("/subscription", stripeHandler.CreateSubscription)
+			billing.GET("/invoices", stripeHandler.ListInvoices)
+			billing.GET("/payment-methods", stripeHandler.ListPaymentMethods)
+			billing.POST("/payment-methods/setup-intent", twoFA(models.SensitivePaymentSettings), stripeHandler.CreateSetupIntent)
+			billing.PUT("/payment-methods/:pm_id/default", twoFA(models.SensitivePaymentSettings), stripeHandler.SetDefaultPaymentMethod)
+			billing.DELETE("/payment-methods/:pm_id", twoFA(models.SensitivePaymentSettings), stripeHandler.DeletePaymentMethod)
+		}
+
+		// Servers shared with the current user
+		shares := api.Group("/shares")
+		{
+			shares.GET("", shareHandler.ListGrants)
+			shares.POST("/redeem", shareHandler.RedeemShare)
+		}
+
+		// API keys (programmatic access; managing keys requires a JWT session)
+		apiKeys := api.Group("/api-keys")
+		{
+			apiKeys.GET("", apiKeyHandler.ListKeys)
+			apiKeys.POST("", twoFA(models.SensitiveAPIKeyCreate), apiKeyHandler.CreateKey)
+			apiKeys.DELETE("/:key_id", apiKeyHandler.RevokeKey)
+		}
+
+		// SFTP keys (SSH public keys for logging into the SFTP gateway)
+		sftpKeys := api.Group("/sftp/keys")
+		{
+			sftpKeys.GET("", sftpHandler.ListKeys)
+			sftpKeys.POST("", twoFA(models.SensitiveSFTPKeyCreate), sftpHandler.AddKey)
+			sftpKeys.DELETE("/:key_id", sftpHandler.DeleteKey)
+		}
+
+		// Per-owner operations (queued starts, backups, restores; running migrations and archives) and their cancellation
+		operations := api.Group("/operations")
+		{
+			operations.GET("", operationHandler.ListOperations)
+			operations.GET("/:operation_id", operationHandler.GetOperation)
+			operations.POST("/:operation_id/cancel", operationHandler.CancelOperation)
+			operations.DELETE("/:operation_id", operationHandler.CancelOperation)
+		}
+
+		// Background job history with status and progress (persisted, survives API restarts) and cancellation
+		jobs := api.Group("/jobs")
+		{
+			jobs.GET("", jobHandler.ListJobs)
+			jobs.GET("/:job_id", jobHandler.GetJob)
+			jobs.DELETE("/:job_id", jobHandler.CancelJob)
+		}
+
+		// Organizations (teams sharing servers)
+		orgs := api.Group("/organizations")
+		{
+			orgs.GET("", orgHandler.ListOrganizations)
+			orgs.POST("", orgHandler.CreateOrganization)
+			orgs.POST("/invitations/accept", orgHandler.AcceptInvitation)
+			orgs.GET("/:org_id", orgHandler.GetOrganization)
+			orgs.PUT("/:org_id", orgHandler.UpdateOrganization)
+			orgs.DELETE("/:org_id", orgHandler.DeleteOrganization)
+			orgs.PUT("/:org_id/residency", orgHandler.SetDataResidency)
+			orgs.PUT("/:org_id/members/:user_id", orgHandler.UpdateMember)
+			orgs.DELETE("/:org_id/members/:user_id", orgHandler.RemoveMember)
+			orgs.GET("/:org_id/invitations", orgHandler.ListInvitations)
+			orgs.POST("/:org_id/invitations", orgHandler.InviteMember)
+			orgs.DELETE("/:org_id/invitations/:invitation_id", orgHandler.RevokeInvitation)
+			orgs.GET("/:org_id/servers", orgHandler.ListServers)
+			orgs.PUT("/:org_id/servers/:server_id", orgHandler.ShareServer)
+			orgs.DELETE("/:org_id/servers/:server_id", orgHandler.UnshareServer)
+			orgs.GET("/:org_id/bans", playerBanHandler.ListOrganizationBans)
+			orgs.POST("/:org_id/bans", playerBanHandler.CreateOrganizationBan) // Enforced at the Velocity proxy for the organization's servers
+			orgs.DELETE("/:org_id/bans/:ban_id", playerBanHandler.RevokeOrganizationBan)
+			orgs.GET("/:org_id/api-keys", apiKeyHandler.ListOrganizationKeys)
+			orgs.POST("/:org_id/api-keys", twoFA(models.SensitiveAPIKeyCreate), apiKeyHandler.CreateOrganizationKey)
+			orgs.GET("/:org_id/sso", ssoHandler.GetConnection)
+			orgs.PUT("/:org_id/sso", twoFA(models.SensitiveSSOConfigure), ssoHandler.ConfigureConnection)
+			orgs.DELETE("/:org_id/sso", twoFA(models.SensitiveSSOConfigure), ssoHandler.DeleteConnection)
+			orgs.GET("/:org_id/sso/domains", ssoHandler.ListDomains)
+			orgs.POST("/:org_id/sso/domains/:domain/verify", ssoHandler.VerifyDomain) // DNS TXT ownership check
+		}
+
+		// Webhook endpoints (signed JSON deliveries of the event catalog, with delivery log and redelivery)
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.GET("/events", webhookEndpointHandler.ListEvents)
+			webhooks.GET("", webhookEndpointHandler.ListEndpoints)
+			webhooks.POST("", webhookEndpointHandler.CreateEndpoint)
+			webhooks.GET("/:endpoint_id", webhookEndpointHandler.GetEndpoint)
+			webhooks.PUT("/:endpoint_id", webhookEndpointHandler.UpdateEndpoint)
+			webhooks.DELETE("/:endpoint_id", webhookEndpointHandler.DeleteEndpoint)
+			webhooks.POST("/:endpoint_id/secret", webhookEndpointHandler.RotateSecret)
+			webhooks.POST("/:endpoint_id/ping", webhookEndpointHandler.PingEndpoint)
+			webhooks.GET("/:endpoint_id/deliveries", webhookEndpointHandler.ListDeliveries)
+			webhooks.GET("/:endpoint_id/deliveries/:delivery_id", webhookEndpointHandler.GetDelivery)
+			webhooks.POST("/:endpoint_id/deliveries/:delivery_id/redeliver", webhookEndpointHandler.Redeliver)
+		}
+
+		// Declarative server management: reconcile the account's servers with a manifest (GitOps, Terraform)
+		api.POST("/apply", maintenance, applyHandler.Apply)
+		api.GET("/apply/servers", applyHandler.ListManagedServers)
+
+		// Prepaid credit wallet
+		wallet := api.Group("/wallet")
+		{
+			wallet.GET("", walletHandler.GetWallet)
+			wallet.GET("/transactions", walletHandler.GetTransactions)
+			wallet.POST("/top-up", walletHandler.TopUp)
 		}
 
 		// User Backup Management (with quota enforcement)
@@ -348,6 +751,8 @@ func SetupRouter(
 			scaling.POST("/enable", scalingHandler.EnableScaling)
 			scaling.POST("/disable", scalingHandler.DisableScaling)
 			scaling.GET("/history", scalingHandler.GetScalingHistory)
+			scaling.GET("/decisions", scalingDecisionHandler.ListDecisions)                // Every evaluation: fleet snapshot, policy verdicts, action, outcome
+			scaling.GET("/decisions/:decision_id", scalingDecisionHandler.GetDecision)
 			scaling.POST("/optimize-costs", scalingHandler.OptimizeCosts) // B8: Manual cost optimization trigger
 		}
 
@@ -360,8 +765,8 @@ func SetupRouter(
 		}
 
 		// Server-specific migration endpoints (require auth)
-		api.GET("/servers/:id/migrations", migrationHandler.GetServerMigrations)
-		api.GET("/servers/:id/migrations/active", migrationHandler.GetActiveMigration)
+		api.GET("/servers/:id/migrations", perm(models.PermServerView), migrationHandler.GetServerMigrations)
+		api.GET("/servers/:id/migrations/active", perm(models.PermServerView), migrationHandler.GetActiveMigration)
 	}
 
 	// Internal API (for Velocity plugin - NO AUTH required, network isolation)
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+// RuntimeConfigHandler shows the effective configuration and reloads it (admin only)
+type RuntimeConfigHandler struct {
+	runtimeConfig *service.RuntimeConfigService
+	audit         *service.AuditService
+}
+
+// NewRuntimeConfigHandler creates a new runtime config handler
+func NewRuntimeConfigHandler(runtimeConfig *service.RuntimeConfigService, auditService *service.AuditService) *RuntimeConfigHandler {
+	return &RuntimeConfigHandler{
+		runtimeConfig: runtimeConfig,
+		audit:         auditService,
+	}
+}
+
+// GetConfig returns the effective configuration with secrets redacted and the settings that can be reloaded
+// GET /api/admin/config
+func (h *RuntimeConfigHandler) GetConfig(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+	c.JSON(http.StatusOK, h.runtimeConfig.Status())
+}
+
+// ReloadConfig re-reads the config file now and applies the changed reloadable settings
+// POST /api/admin/config/reload
+func (h *RuntimeConfigHandler) ReloadConfig(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	changes, err := h.runtimeConfig.Reload()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload configuration: " + err.Error()})
+		return
+	}
+	if changes == nil {
+		changes = []config.Change{}
+	}
+	if len(changes) > 0 {
+		before, after := gin.H{}, gin.H{}
+		for _, change := range changes {
+			before[change.Key], after[change.Key] = change.Old, change.New
+		}
+		h.audit.Record(auditEntry(c, audit.ActionConfigReload, "platform", "config", before, after))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"changes": changes,
+		"count":   len(changes),
+	})
+}
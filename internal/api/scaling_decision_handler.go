@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// ScalingDecisionHandler serves the history of the scaling engine's evaluations to admins
+type ScalingDecisionHandler struct {
+	decisions *service.ScalingDecisionService
+}
+
+// NewScalingDecisionHandler creates a new scaling decision handler
+func NewScalingDecisionHandler(decisions *service.ScalingDecisionService) *ScalingDecisionHandler {
+	return &ScalingDecisionHandler{decisions: decisions}
+}
+
+// ListDecisions lists the evaluations of the scaling engine, newest first (admin only)
+// GET /api/scaling/decisions
+// Supports ?cursor, ?limit, ?sort, the action/outcome/policy/server_type filters and
+// ?from / ?to (RFC 3339 or YYYY-MM-DD, "to" exclusive).
+func (h *ScalingDecisionHandler) ListDecisions(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	page, err := parsePageRequest(c, 50, "action", "outcome", "policy", "server_type")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	from, ok := parseAuditTime(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseAuditTime(c, "to")
+	if !ok {
+		return
+	}
+
+	decisions, err := h.decisions.List(from, to, page)
+	if err != nil {
+		logger.Error("SCALING-HISTORY-API: Failed to list scaling decisions", err, nil)
+		respondPageError(c, err)
+		return
+	}
+
+	setPageHeaders(c, decisions.Total, decisions.NextCursor)
+	c.JSON(http.StatusOK, gin.H{
+		"decisions":   decisions.Items,
+		"count":       len(decisions.Items),
+		"total":       decisions.Total,
+		"next_cursor": decisions.NextCursor,
+	})
+}
+
+// GetDecision returns one evaluation of the scaling engine with its fleet snapshot (admin only)
+// GET /api/scaling/decisions/:decision_id
+func (h *ScalingDecisionHandler) GetDecision(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	decision, err := h.decisions.Get(c.Param("decision_id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scaling decision not found"})
+			return
+		}
+		logger.Error("SCALING-HISTORY-API: Failed to load scaling decision", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load scaling decision"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"decision": decision})
+}
@@ -1,22 +1,33 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
 	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
 // ScalingHandler handles scaling-related API requests
 type ScalingHandler struct {
-	conductor *conductor.Conductor
+	conductor     *conductor.Conductor
+	policyService *service.ScalingPolicyService
+	audit         *service.AuditService
 }
 
 // NewScalingHandler creates a new scaling handler
-func NewScalingHandler(conductor *conductor.Conductor) *ScalingHandler {
+func NewScalingHandler(conductor *conductor.Conductor, policyService *service.ScalingPolicyService, auditService *service.AuditService) *ScalingHandler {
 	return &ScalingHandler{
-		conductor: conductor,
+		conductor:     conductor,
+		policyService: policyService,
+		audit:         auditService,
 	}
 }
 
@@ -226,6 +237,197 @@ func (h *ScalingHandler) OptimizeCosts(c *gin.Context) {
 	})
 }
 
+// SimulateScaling runs the scaling policies against the current fleet without executing anything (admin only)
+// Thresholds can be overridden to see what the engine would do with them before changing them.
+// GET /api/admin/scaling/simulate?scale_up_threshold=80&scale_down_threshold=25&consolidation_max_capacity=60&consolidation_min_node_savings=1
+func (h *ScalingHandler) SimulateScaling(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+	if h.conductor.ScalingEngine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Scaling engine not initialized",
+		})
+		return
+	}
+
+	opts, err := parseSimulationOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"simulation": h.conductor.ScalingEngine.Simulate(opts),
+	})
+}
+
+// scalingPolicyRequest is the body of a scaling policy update (omitted fields keep the defaults)
+// Which fields apply depends on the policy: engine has check_interval_seconds only, reactive and
+// consolidation have enabled and cooldown_seconds plus their own thresholds.
+type scalingPolicyRequest struct {
+	CheckIntervalSeconds      *int     `json:"check_interval_seconds"`
+	Enabled                   *bool    `json:"enabled"`
+	CooldownSeconds           *int     `json:"cooldown_seconds"`
+	ScaleUpThreshold          *float64 `json:"scale_up_threshold"`
+	ScaleDownThreshold        *float64 `json:"scale_down_threshold"`
+	MinCloudNodes             *int     `json:"min_cloud_nodes"`
+	MaxCloudNodes             *int     `json:"max_cloud_nodes"`
+	PreferredServerTypes      []string `json:"preferred_server_types"`
+	MaxCapacityPercent        *float64 `json:"max_capacity_percent"`
+	MinNodeSavings            *int     `json:"min_node_savings"`
+	AllowMigrationWithPlayers *bool    `json:"allow_migration_with_players"`
+}
+
+func (r scalingPolicyRequest) config() *models.ScalingPolicyConfig {
+	config := &models.ScalingPolicyConfig{
+		CheckIntervalSeconds:      r.CheckIntervalSeconds,
+		Enabled:                   r.Enabled,
+		CooldownSeconds:           r.CooldownSeconds,
+		ScaleUpThreshold:          r.ScaleUpThreshold,
+		ScaleDownThreshold:        r.ScaleDownThreshold,
+		MinCloudNodes:             r.MinCloudNodes,
+		MaxCloudNodes:             r.MaxCloudNodes,
+		MaxCapacityPercent:        r.MaxCapacityPercent,
+		MinNodeSavings:            r.MinNodeSavings,
+		AllowMigrationWithPlayers: r.AllowMigrationWithPlayers,
+	}
+	if r.PreferredServerTypes != nil {
+		preferred := strings.Join(r.PreferredServerTypes, ",")
+		config.PreferredServerTypes = &preferred
+	}
+	return config
+}
+
+// ListScalingPolicies returns the configured and effective settings of the scaling policies (admin only)
+// GET /api/admin/scaling/policies
+func (h *ScalingHandler) ListScalingPolicies(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	policies, err := h.policyService.List()
+	if err != nil {
+		logger.Error("Failed to list scaling policies", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list scaling policies"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"policies":       policies,
+		"engine_running": h.conductor.ScalingEngine != nil,
+	})
+}
+
+// UpdateScalingPolicy creates or replaces the settings of a scaling policy and applies them to the
+// running engine before its next evaluation (admin only)
+// :policy is engine, reactive or consolidation.
+// PUT /api/admin/scaling/policies/:policy
+func (h *ScalingHandler) UpdateScalingPolicy(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var request scalingPolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	policy := c.Param("policy")
+	before, err := h.policyService.Get(policy)
+	if err != nil {
+		h.respondScalingPolicyError(c, err)
+		return
+	}
+	view, err := h.policyService.Save(policy, c.GetString("user_id"), request.config())
+	if err != nil {
+		h.respondScalingPolicyError(c, err)
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionScalingPolicy, "scaling_policy", policy, before, view.Override))
+
+	logger.Info("Scaling policy updated via API", map[string]interface{}{
+		"policy":  policy,
+		"user_id": c.GetString("user_id"),
+	})
+	c.JSON(http.StatusOK, gin.H{"policy": view})
+}
+
+// DeleteScalingPolicy removes the settings of a scaling policy (the defaults apply again, admin only)
+// DELETE /api/admin/scaling/policies/:policy
+func (h *ScalingHandler) DeleteScalingPolicy(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	policy := c.Param("policy")
+	before, err := h.policyService.Get(policy)
+	if err != nil {
+		h.respondScalingPolicyError(c, err)
+		return
+	}
+	if before == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scaling policy has no settings"})
+		return
+	}
+	if err := h.policyService.Delete(policy); err != nil {
+		logger.Error("Failed to delete scaling policy", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete scaling policy"})
+		return
+	}
+	h.audit.Record(auditEntry(c, audit.ActionScalingPolicy, "scaling_policy", policy, before, nil))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Scaling policy reset to defaults"})
+}
+
+func (h *ScalingHandler) respondScalingPolicyError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrScalingPolicyInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	logger.Error("Failed to save scaling policy", err, nil)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save scaling policy"})
+}
+
+// parseSimulationOptions reads the threshold overrides of a scaling simulation from the query
+func parseSimulationOptions(c *gin.Context) (conductor.ScalingSimulationOptions, error) {
+	var opts conductor.ScalingSimulationOptions
+
+	percents := map[string]**float64{
+		"scale_up_threshold":         &opts.ScaleUpThreshold,
+		"scale_down_threshold":       &opts.ScaleDownThreshold,
+		"consolidation_max_capacity": &opts.ConsolidationMaxCapacity,
+	}
+	for name, target := range percents {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return opts, fmt.Errorf("%s must be a percentage between 0 and 100", name)
+		}
+		*target = &percent
+	}
+
+	if value := c.Query("consolidation_min_node_savings"); value != "" {
+		savings, err := strconv.Atoi(value)
+		if err != nil || savings < 1 {
+			return opts, fmt.Errorf("consolidation_min_node_savings must be a positive number")
+		}
+		opts.ConsolidationThresholdSaving = &savings
+	}
+
+	if opts.ScaleUpThreshold != nil && opts.ScaleDownThreshold != nil && *opts.ScaleDownThreshold >= *opts.ScaleUpThreshold {
+		return opts, fmt.Errorf("scale_down_threshold must be below scale_up_threshold")
+	}
+	return opts, nil
+}
+
 // buildScalingContext is a helper to build context for manual operations
 func (h *ScalingHandler) buildScalingContext() conductor.ScalingContext {
 	stats := h.conductor.NodeRegistry.GetFleetStats()
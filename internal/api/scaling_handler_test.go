@@ -0,0 +1,36 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestParseSimulationOptions(t *testing.T) {
+	c, _ := newPageTestContext("/api/admin/scaling/simulate?scale_up_threshold=80&consolidation_min_node_savings=2")
+	opts, err := parseSimulationOptions(c)
+	if err != nil {
+		t.Fatalf("parseSimulationOptions() error = %v", err)
+	}
+	if opts.ScaleUpThreshold == nil || *opts.ScaleUpThreshold != 80 {
+		t.Errorf("ScaleUpThreshold = %v, want 80", opts.ScaleUpThreshold)
+	}
+	if opts.ConsolidationThresholdSaving == nil || *opts.ConsolidationThresholdSaving != 2 {
+		t.Errorf("ConsolidationThresholdSaving = %v, want 2", opts.ConsolidationThresholdSaving)
+	}
+	if opts.ScaleDownThreshold != nil || opts.ConsolidationMaxCapacity != nil {
+		t.Errorf("unset thresholds overridden: %+v", opts)
+	}
+
+	invalid := []string{
+		"scale_up_threshold=high",
+		"scale_down_threshold=-5",
+		"consolidation_max_capacity=120",
+		"consolidation_min_node_savings=0",
+		"scale_up_threshold=50&scale_down_threshold=60",
+	}
+	for _, query := range invalid {
+		c, _ := newPageTestContext("/api/admin/scaling/simulate?" + query)
+		if _, err := parseSimulationOptions(c); err == nil {
+			t.Errorf("parseSimulationOptions(%q) accepted invalid overrides", query)
+		}
+	}
+}
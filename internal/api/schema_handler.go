@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/repository"
+)
+
+// SchemaHandler shows the database schema version (admin only)
+type SchemaHandler struct {
+	migrator *repository.Migrator
+}
+
+// NewSchemaHandler creates a new schema handler
+func NewSchemaHandler(migrator *repository.Migrator) *SchemaHandler {
+	return &SchemaHandler{migrator: migrator}
+}
+
+// GetSchemaStatus returns the applied and pending schema migrations
+// "newer" means the database was migrated by a newer build, "modified" lists applied
+// SQL migrations whose script changed since.
+// GET /api/admin/schema
+func (h *SchemaHandler) GetSchemaStatus(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	status, err := h.migrator.Status()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read schema migrations"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
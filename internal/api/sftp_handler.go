@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// SFTPHandler handles SFTP keys, connection details and the SFTP audit log
+type SFTPHandler struct {
+	sftpService *service.SFTPGatewayService
+}
+
+// NewSFTPHandler creates a new SFTP handler
+func NewSFTPHandler(sftpService *service.SFTPGatewayService) *SFTPHandler {
+	return &SFTPHandler{sftpService: sftpService}
+}
+
+// addSFTPKeyRequest is the body for registering an SFTP key
+type addSFTPKeyRequest struct {
+	Name      string `json:"name" binding:"max=100"`
+	PublicKey string `json:"public_key" binding:"required"` // authorized_keys line
+	ServerID  string `json:"server_id"`                     // Empty = every server the user can access
+	ReadOnly  bool   `json:"read_only"`
+}
+
+// ListKeys returns the SFTP keys of the current user
+// GET /api/sftp/keys
+func (h *SFTPHandler) ListKeys(c *gin.Context) {
+	keys, err := h.sftpService.ListKeys(c.GetString("user_id"))
+	if err != nil {
+		respondSFTPError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"keys":  keys,
+		"count": len(keys),
+	})
+}
+
+// AddKey registers an SSH public key for SFTP logins
+// POST /api/sftp/keys
+// Body: {"name": "Laptop", "public_key": "ssh-ed25519 AAAAC3Nza... me@laptop", "server_id": "", "read_only": false}
+func (h *SFTPHandler) AddKey(c *gin.Context) {
+	var request addSFTPKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	key, err := h.sftpService.AddKey(c.GetString("user_id"), request.Name, request.PublicKey, request.ServerID, request.ReadOnly)
+	if err != nil {
+		respondSFTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"key": key})
+}
+
+// DeleteKey removes an SFTP key of the current user
+// DELETE /api/sftp/keys/:key_id
+func (h *SFTPHandler) DeleteKey(c *gin.Context) {
+	if err := h.sftpService.DeleteKey(c.GetString("user_id"), c.Param("key_id")); err != nil {
+		respondSFTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "SFTP key deleted"})
+}
+
+// GetConnectionInfo returns host, port and user name for opening a server over SFTP
+// GET /api/servers/:id/sftp
+func (h *SFTPHandler) GetConnectionInfo(c *gin.Context) {
+	info, err := h.sftpService.ConnectionInfo(c.Param("id"))
+	if err != nil {
+		respondSFTPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// ListAudit returns the latest SFTP logins and file operations on a server
+// GET /api/servers/:id/sftp/audit?limit=100
+func (h *SFTPHandler) ListAudit(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	entries, err := h.sftpService.ListAudit(c.Param("id"), limit)
+	if err != nil {
+		respondSFTPError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// respondSFTPError maps SFTP gateway errors to HTTP status codes
+func respondSFTPError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSFTPKeyNotFound), errors.Is(err, models.ErrServerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrServerForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSFTPKeyInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSFTPKeyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSFTPGatewayDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
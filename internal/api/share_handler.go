@@ -0,0 +1,189 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// ShareHandler handles per-server shares (scoped access for single users) and permission lookups
+type ShareHandler struct {
+	permissionService *service.PermissionService
+	serverRepo        *repository.ServerRepository
+}
+
+// NewShareHandler creates a new share handler
+func NewShareHandler(permissionService *service.PermissionService, serverRepo *repository.ServerRepository) *ShareHandler {
+	return &ShareHandler{
+		permissionService: permissionService,
+		serverRepo:        serverRepo,
+	}
+}
+
+// GetPermissions returns the permissions of the current user on a server
+// GET /api/servers/:id/permissions
+func (h *ShareHandler) GetPermissions(c *gin.Context) {
+	server, err := h.serverRepo.FindByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "server not found"})
+		return
+	}
+
+	perms := models.AllServerPermissions
+	if !c.GetBool("is_admin") {
+		perms = h.permissionService.Permissions(c.GetString("user_id"), server)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"server_id":   server.ID,
+		"permissions": perms,
+	})
+}
+
+// ListShares returns the shares of a server
+// GET /api/servers/:id/shares
+func (h *ShareHandler) ListShares(c *gin.Context) {
+	shares, err := h.permissionService.ListShares(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"shares": shares,
+		"count":  len(shares),
+	})
+}
+
+// CreateShare creates a share token for a server
+// POST /api/servers/:id/shares
+// Body: {"permissions": ["console", "files.read", "power"], "note": "Moderator", "expires_in_hours": 72}
+func (h *ShareHandler) CreateShare(c *gin.Context) {
+	var request struct {
+		Permissions    []models.ServerPermission `json:"permissions" binding:"required,min=1"`
+		Note           string                    `json:"note" binding:"max=255"`
+		ExpiresInHours int                       `json:"expires_in_hours" binding:"min=0"` // 0 = token doesn't expire
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	share, token, err := h.permissionService.CreateShare(c.GetString("user_id"), c.Param("id"),
+		request.Permissions, request.Note, time.Duration(request.ExpiresInHours)*time.Hour)
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"share": share,
+		"token": token, // Only returned once
+	})
+}
+
+// UpdateShare replaces the permissions of a share
+// PUT /api/servers/:id/shares/:share_id
+// Body: {"permissions": ["console"]}
+func (h *ShareHandler) UpdateShare(c *gin.Context) {
+	shareID, ok := parseShareID(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Permissions []models.ServerPermission `json:"permissions" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	share, err := h.permissionService.UpdateShare(c.GetString("user_id"), c.Param("id"), shareID, request.Permissions)
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, share)
+}
+
+// RevokeShare revokes a share (the grantee loses access immediately)
+// DELETE /api/servers/:id/shares/:share_id
+func (h *ShareHandler) RevokeShare(c *gin.Context) {
+	shareID, ok := parseShareID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.permissionService.RevokeShare(c.GetString("user_id"), c.Param("id"), shareID); err != nil {
+		respondShareError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "share revoked"})
+}
+
+// RedeemShare grants a share to the current user
+// POST /api/shares/redeem
+// Body: {"token": "..."}
+func (h *ShareHandler) RedeemShare(c *gin.Context) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	share, err := h.permissionService.RedeemShare(c.GetString("user_id"), request.Token)
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, share)
+}
+
+// ListGrants returns the servers shared with the current user and their permissions
+// GET /api/shares
+func (h *ShareHandler) ListGrants(c *gin.Context) {
+	shares, err := h.permissionService.ListGrants(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list shares"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"shares": shares,
+		"count":  len(shares),
+	})
+}
+
+func parseShareID(c *gin.Context) (uint, bool) {
+	shareID, err := strconv.ParseUint(c.Param("share_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid share ID"})
+		return 0, false
+	}
+	return uint(shareID), true
+}
+
+// respondShareError maps permission and share errors to HTTP status codes
+func respondShareError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrServerNotFound), errors.Is(err, models.ErrShareNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrServerForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrShareOwnServer), errors.Is(err, models.ErrShareAlreadyGranted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrInvalidPermission), errors.Is(err, models.ErrInvalidShareToken):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
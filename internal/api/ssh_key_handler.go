@@ -0,0 +1,65 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// SSHKeyHandler lists and rotates the conductor's node SSH keys (admin only)
+type SSHKeyHandler struct {
+	sshKeyService *service.SSHKeyService
+}
+
+// NewSSHKeyHandler creates a new SSH key handler
+func NewSSHKeyHandler(sshKeyService *service.SSHKeyService) *SSHKeyHandler {
+	return &SSHKeyHandler{sshKeyService: sshKeyService}
+}
+
+// ListSSHKeys lists the node keys of this environment, newest first (no private keys)
+// GET /api/admin/ssh-keys
+func (h *SSHKeyHandler) ListSSHKeys(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	keys, err := h.sshKeyService.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list SSH keys"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"keys":  keys,
+		"count": len(keys),
+	})
+}
+
+// RotateSSHKey generates a new node key, installs it on every node and switches to it
+// The previous key is removed from the nodes after SSH_KEY_GRACE_PERIOD. If a node rejects the
+// new key, nothing switches and the response is 502 with the failed key.
+// POST /api/admin/ssh-keys/rotate
+func (h *SSHKeyHandler) RotateSSHKey(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	key, err := h.sshKeyService.Rotate(c.Request.Context(), c.GetString("user_id"))
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, key)
+	case errors.Is(err, service.ErrSSHKeyRotationUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSSHKeyRotationRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSSHKeyPushFailed):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "key": key})
+	default:
+		logger.Error("SSH-KEYS: Rotation failed", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate SSH key"})
+	}
+}
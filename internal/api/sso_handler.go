@@ -0,0 +1,280 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// SSOHandler handles organization single sign-on (OIDC identity providers)
+type SSOHandler struct {
+	ssoService *service.SSOService
+}
+
+// NewSSOHandler creates a new SSO handler
+func NewSSOHandler(ssoService *service.SSOService) *SSOHandler {
+	return &SSOHandler{ssoService: ssoService}
+}
+
+// GetConnection returns the SSO connection of an organization (admin)
+// GET /api/organizations/:org_id/sso
+func (h *SSOHandler) GetConnection(c *gin.Context) {
+	conn, err := h.ssoService.GetConnection(c.Param("org_id"), c.GetString("user_id"))
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"connection": conn})
+}
+
+// ConfigureConnection creates or replaces the SSO connection of an organization (owner)
+// PUT /api/organizations/:org_id/sso
+// Body: {"issuer_url": "https://idp.acme.com", "client_id": "...", "client_secret": "...", "email_domains": ["acme.com"],
+// "role_claim": "groups", "role_mapping": {"minecraft-admins": "admin"}, "default_role": "viewer", "enforced": true, "session_max_minutes": 480}
+func (h *SSOHandler) ConfigureConnection(c *gin.Context) {
+	var input service.SSOConnectionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conn, err := h.ssoService.ConfigureConnection(c.Param("org_id"), c.GetString("user_id"), input, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+	domains, err := h.ssoService.ListDomains(c.Param("org_id"), c.GetString("user_id"))
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "SSO connection saved, verify its email domains to enable discovery and provisioning",
+		"connection": conn,
+		"domains":    domains,
+	})
+}
+
+// ListDomains returns the connection's email domains with their DNS verification records (admin)
+// GET /api/organizations/:org_id/sso/domains
+func (h *SSOHandler) ListDomains(c *gin.Context) {
+	domains, err := h.ssoService.ListDomains(c.Param("org_id"), c.GetString("user_id"))
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"domains": domains})
+}
+
+// VerifyDomain checks the TXT record of a claimed domain (owner)
+// POST /api/organizations/:org_id/sso/domains/:domain/verify
+func (h *SSOHandler) VerifyDomain(c *gin.Context) {
+	domain, err := h.ssoService.VerifyDomain(c.Param("org_id"), c.GetString("user_id"), c.Param("domain"), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Domain verified",
+		"domain":  domain,
+	})
+}
+
+// ApproveDomain verifies a claimed domain without DNS proof (platform admin)
+// POST /api/admin/sso/organizations/:org_id/domains/:domain/approve
+func (h *SSOHandler) ApproveDomain(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	domain, err := h.ssoService.ApproveDomain(c.Param("org_id"), c.Param("domain"), c.GetString("user_id"), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Domain approved",
+		"domain":  domain,
+	})
+}
+
+// DeleteConnection removes the SSO connection of an organization (owner)
+// DELETE /api/organizations/:org_id/sso
+func (h *SSOHandler) DeleteConnection(c *gin.Context) {
+	if err := h.ssoService.DeleteConnection(c.Param("org_id"), c.GetString("user_id"), c.ClientIP(), c.Request.UserAgent()); err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "SSO connection removed"})
+}
+
+// Discover tells the login page whether an email signs in via an organization's SSO
+// POST /api/auth/sso/discover
+// Body: {"email": "jane@acme.com"}
+func (h *SSOHandler) Discover(c *gin.Context) {
+	var request struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	discovery, err := h.ssoService.Discover(request.Email)
+	if errors.Is(err, models.ErrSSONotConfigured) {
+		c.JSON(http.StatusOK, gin.H{"sso": false})
+		return
+	}
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sso":          true,
+		"organization": discovery,
+	})
+}
+
+// Login returns the authorization URL of an organization's identity provider
+// POST /api/auth/sso/login
+// Body: {"organization_id": "..."} or {"email": "jane@acme.com"}
+func (h *SSOHandler) Login(c *gin.Context) {
+	var request struct {
+		OrganizationID string `json:"organization_id"`
+		Email          string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orgID := request.OrganizationID
+	if orgID == "" && request.Email != "" {
+		discovery, err := h.ssoService.Discover(request.Email)
+		if err != nil {
+			respondSSOError(c, err)
+			return
+		}
+		orgID = discovery.OrganizationID
+	}
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id or email is required"})
+		return
+	}
+
+	authURL, err := h.ssoService.BeginLogin(orgID)
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"auth_url":        authURL,
+		"organization_id": orgID,
+	})
+}
+
+// Callback completes an SSO login
+// GET /api/auth/sso/callback
+func (h *SSOHandler) Callback(c *gin.Context) {
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Missing code or state parameter",
+			"detail": c.Query("error_description"),
+		})
+		return
+	}
+
+	token, user, isNewDevice, err := h.ssoService.HandleCallback(code, state, c.Request.UserAgent(), c.ClientIP())
+	var linkErr *service.SSOLinkRequiredError
+	if errors.As(err, &linkErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      err.Error(),
+			"code":       "SSO_LINK_REQUIRED",
+			"link_token": linkErr.LinkToken, // POST /api/auth/sso/link with the account password, or use the emailed link
+		})
+		return
+	}
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	respondSSOLogin(c, token, user, isNewDevice)
+}
+
+// ConfirmLink links an SSO identity to the existing account it matched and signs in
+// POST /api/auth/sso/link
+// Body: {"token": "<link_token>", "password": "..."} or {"token": "<emailed token>"}
+func (h *SSOHandler) ConfirmLink(c *gin.Context) {
+	var request struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, user, isNewDevice, err := h.ssoService.ConfirmLink(request.Token, request.Password, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	respondSSOLogin(c, token, user, isNewDevice)
+}
+
+// respondSSOLogin writes the session of a completed SSO login
+func respondSSOLogin(c *gin.Context, token string, user *models.User, isNewDevice bool) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"user": gin.H{
+			"id":       user.ID,
+			"email":    user.Email,
+			"username": user.Username,
+			"balance":  user.Balance,
+			"is_admin": user.IsAdmin,
+		},
+		"token":         token,
+		"is_new_device": isNewDevice,
+		"provider":      "sso",
+	})
+}
+
+// respondSSOError maps SSO and organization errors to HTTP responses
+func respondSSOError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrOrgNotFound), errors.Is(err, models.ErrSSONotConfigured), errors.Is(err, models.ErrSSODomainNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrOrgForbidden), errors.Is(err, models.ErrSSODisabled),
+		errors.Is(err, models.ErrSSONotAuthorized), errors.Is(err, models.ErrSSOEmailNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrSSOInvalidState), errors.Is(err, models.ErrSSOInvalidIDToken),
+		errors.Is(err, models.ErrSSOLinkInvalid), errors.Is(err, models.ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrSSODomainTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrSSODomainUnverified):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrAccountLocked):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrSSOInvalidConfig), errors.Is(err, models.ErrSSOUnsupportedProtocol):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// StripeHandler handles Stripe subscription, payment method, invoice and webhook endpoints
+type StripeHandler struct {
+	stripeService *service.StripeService
+}
+
+// NewStripeHandler creates a new Stripe handler
+func NewStripeHandler(stripeService *service.StripeService) *StripeHandler {
+	return &StripeHandler{
+		stripeService: stripeService,
+	}
+}
+
+// stripeError maps service errors to HTTP responses
+func (h *StripeHandler) stripeError(c *gin.Context, err error, message string) {
+	if errors.Is(err, service.ErrStripeDisabled) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Error(message, err, map[string]interface{}{
+		"user_id": c.GetString("user_id"),
+	})
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// GetSubscription returns the billing account status of the current user
+// GET /api/billing/subscription
+func (h *StripeHandler) GetSubscription(c *gin.Context) {
+	customer, err := h.stripeService.GetCustomer(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get billing account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":  h.stripeService.IsEnabled(),
+		"enrolled": customer != nil && customer.SubscriptionID != "",
+		"account":  customer,
+	})
+}
+
+// CreateSubscription enrolls the current user in metered billing
+// POST /api/billing/subscription
+func (h *StripeHandler) CreateSubscription(c *gin.Context) {
+	customer, err := h.stripeService.EnsureCustomer(c.GetString("user_id"))
+	if err != nil {
+		h.stripeError(c, err, "Failed to create subscription")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"account": customer,
+	})
+}
+
+// ListPaymentMethods returns the stored cards of the current user
+// GET /api/billing/payment-methods
+func (h *StripeHandler) ListPaymentMethods(c *gin.Context) {
+	methods, err := h.stripeService.ListPaymentMethods(c.GetString("user_id"))
+	if err != nil {
+		h.stripeError(c, err, "Failed to list payment methods")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"payment_methods": methods,
+	})
+}
+
+// CreateSetupIntent starts adding a new card (frontend confirms it with Stripe.js)
+// POST /api/billing/payment-methods/setup-intent
+func (h *StripeHandler) CreateSetupIntent(c *gin.Context) {
+	clientSecret, err := h.stripeService.CreateSetupIntent(c.GetString("user_id"))
+	if err != nil {
+		h.stripeError(c, err, "Failed to create setup intent")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"client_secret": clientSecret,
+	})
+}
+
+// SetDefaultPaymentMethod makes a stored card the default for invoices
+// PUT /api/billing/payment-methods/:pm_id/default
+func (h *StripeHandler) SetDefaultPaymentMethod(c *gin.Context) {
+	if err := h.stripeService.SetDefaultPaymentMethod(c.GetString("user_id"), c.Param("pm_id")); err != nil {
+		h.stripeError(c, err, "Failed to set default payment method")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Default payment method updated",
+	})
+}
+
+// DeletePaymentMethod removes a stored card
+// DELETE /api/billing/payment-methods/:pm_id
+func (h *StripeHandler) DeletePaymentMethod(c *gin.Context) {
+	if err := h.stripeService.DetachPaymentMethod(c.GetString("user_id"), c.Param("pm_id")); err != nil {
+		h.stripeError(c, err, "Failed to delete payment method")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Payment method removed",
+	})
+}
+
+// ListInvoices returns the monthly invoices of the current user
+// GET /api/billing/invoices
+func (h *StripeHandler) ListInvoices(c *gin.Context) {
+	invoices, err := h.stripeService.ListInvoices(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list invoices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invoices": invoices,
+	})
+}
+
+// HandleWebhook receives Stripe webhook events (signature verified, no JWT)
+// POST /webhooks/stripe
+func (h *StripeHandler) HandleWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read body"})
+		return
+	}
+
+	if err := h.stripeService.HandleWebhook(payload, c.GetHeader("Stripe-Signature")); err != nil {
+		if errors.Is(err, service.ErrInvalidStripeSignature) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrStripeDisabled) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("STRIPE: Failed to process webhook", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
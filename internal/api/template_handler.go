@@ -1,10 +1,12 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/middleware"
 	"github.com/payperplay/hosting/internal/service"
 	"github.com/payperplay/hosting/pkg/logger"
 )
@@ -21,10 +23,14 @@ func NewTemplateHandler(templateService *service.TemplateService) *TemplateHandl
 	}
 }
 
-// GetAllTemplates returns all available templates
+// GetAllTemplates returns all available templates, including the user's own templates
 // GET /api/templates
 func (h *TemplateHandler) GetAllTemplates(c *gin.Context) {
-	templates := h.templateService.GetAllTemplates()
+	templates, err := h.templateService.GetTemplatesForUser(middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"templates": templates,
@@ -37,7 +43,7 @@ func (h *TemplateHandler) GetAllTemplates(c *gin.Context) {
 func (h *TemplateHandler) GetTemplate(c *gin.Context) {
 	templateID := c.Param("id")
 
-	template, err := h.templateService.GetTemplateByID(templateID)
+	template, err := h.templateService.GetTemplateForUser(templateID, middleware.GetUserID(c))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Template not found",
@@ -125,7 +131,7 @@ func (h *TemplateHandler) ApplyTemplate(c *gin.Context) {
 	}
 
 	// Check if template exists
-	template, err := h.templateService.GetTemplateByID(request.TemplateID)
+	template, err := h.templateService.GetTemplateForUser(request.TemplateID, middleware.GetUserID(c))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Template not found",
@@ -134,7 +140,7 @@ func (h *TemplateHandler) ApplyTemplate(c *gin.Context) {
 	}
 
 	// Apply template to server
-	if err := h.templateService.ApplyTemplateToServer(serverID, request.TemplateID); err != nil {
+	if err := h.templateService.ApplyTemplateToServer(serverID, request.TemplateID, middleware.GetUserID(c)); err != nil {
 		logger.Error("Failed to apply template", err, map[string]interface{}{
 			"server_id":   serverID,
 			"template_id": request.TemplateID,
@@ -185,3 +191,151 @@ func (h *TemplateHandler) GetRecommendations(c *gin.Context) {
 		"count": len(templates),
 	})
 }
+
+// CreateTemplateRequest is the body of POST /api/templates
+type CreateTemplateRequest struct {
+	service.UserTemplateInput
+	Published bool `json:"published"` // List the template in the marketplace right away
+}
+
+// ListMyTemplates returns the user's own templates with their current revision
+// GET /api/templates/mine
+func (h *TemplateHandler) ListMyTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListUserTemplates(c.GetString("user_id"))
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+		"count":     len(templates),
+	})
+}
+
+// ListMarketplace returns the templates published by all users
+// GET /api/templates/marketplace
+// Supports ?cursor, ?limit, ?sort (published_at, usage_count, name) and the type/version/owner filters.
+func (h *TemplateHandler) ListMarketplace(c *gin.Context) {
+	page, err := parsePageRequest(c, 50, "type", "version", "owner")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	templates, err := h.templateService.ListMarketplace(page)
+	if err != nil {
+		if errors.Is(err, service.ErrUserTemplatesDisabled) {
+			respondTemplateError(c, err)
+			return
+		}
+		respondPageError(c, err)
+		return
+	}
+
+	setPageHeaders(c, templates.Total, templates.NextCursor)
+	c.JSON(http.StatusOK, gin.H{
+		"templates":   templates.Items,
+		"count":       len(templates.Items),
+		"total":       templates.Total,
+		"next_cursor": templates.NextCursor,
+	})
+}
+
+// CreateTemplate creates a user template (revision 1), optionally published in the marketplace
+// POST /api/templates
+// Body: {"name": "Hardcore Survival", "server_type": "paper", "minecraft_version": "1.21", "ram_mb": 4096, "world_seed": "-4172144997902289642", "plugins": ["essentialsx"], "published": false}
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	var req CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.CreateUserTemplate(c.GetString("user_id"), req.UserTemplateInput, req.Published)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdateTemplate saves new content of an own template as its next revision
+// Servers can still be created from earlier revisions (template_revision in POST /api/servers).
+// PUT /api/templates/:id
+// Body: {"name": "Hardcore Survival", "server_type": "paper", "minecraft_version": "1.21.1", "ram_mb": 4096, "plugins": ["essentialsx"], "changelog": "Update to 1.21.1"}
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	var req service.UserTemplateInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.UpdateUserTemplate(c.GetString("user_id"), c.Param("id"), req)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate deletes an own template with its revisions and world snapshot
+// DELETE /api/templates/:id
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	if err := h.templateService.DeleteUserTemplate(c.GetString("user_id"), c.Param("id")); err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted"})
+}
+
+// ListTemplateRevisions returns the revisions of an own or published template, newest first
+// GET /api/templates/:id/revisions
+func (h *TemplateHandler) ListTemplateRevisions(c *gin.Context) {
+	revisions, err := h.templateService.ListRevisions(c.GetString("user_id"), c.Param("id"))
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+		"count":     len(revisions),
+	})
+}
+
+// PublishTemplate lists an own template in the marketplace
+// POST /api/templates/:id/publish
+func (h *TemplateHandler) PublishTemplate(c *gin.Context) {
+	template, err := h.templateService.SetPublished(c.GetString("user_id"), c.Param("id"), true)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// UnpublishTemplate removes an own template from the marketplace
+// Servers already created from it are not affected.
+// DELETE /api/templates/:id/publish
+func (h *TemplateHandler) UnpublishTemplate(c *gin.Context) {
+	template, err := h.templateService.SetPublished(c.GetString("user_id"), c.Param("id"), false)
+	if err != nil {
+		respondTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+func respondTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserTemplatesDisabled):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
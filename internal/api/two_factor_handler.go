@@ -0,0 +1,189 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// TwoFactorHandler handles TOTP enrollment, recovery codes and the 2FA login step
+type TwoFactorHandler struct {
+	twoFactorService *service.TwoFactorService
+	authService      *service.AuthService
+}
+
+// NewTwoFactorHandler creates a new two-factor handler
+func NewTwoFactorHandler(twoFactorService *service.TwoFactorService, authService *service.AuthService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+		authService:      authService,
+	}
+}
+
+// twoFactorCodeRequest is the body of endpoints that only need a code
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// GetStatus returns the 2FA state of the current user
+// GET /api/auth/2fa
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	status, err := h.twoFactorService.Status(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get two-factor status"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// BeginEnrollment issues a new TOTP secret (2FA stays disabled until confirmed)
+// POST /api/auth/2fa/enroll
+func (h *TwoFactorHandler) BeginEnrollment(c *gin.Context) {
+	secret, otpauthURL, err := h.twoFactorService.BeginEnrollment(c.GetString("user_id"))
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":      secret,
+		"otpauth_url": otpauthURL,
+		"message":     "Add the secret to your authenticator app, then confirm with a code",
+	})
+}
+
+// ConfirmEnrollment enables 2FA and returns the recovery codes (shown only once)
+// POST /api/auth/2fa/confirm
+// Body: {"code": "123456"}
+func (h *TwoFactorHandler) ConfirmEnrollment(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.twoFactorService.ConfirmEnrollment(c.GetString("user_id"), req.Code, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled. Store your recovery codes in a safe place.",
+		"recovery_codes": codes,
+	})
+}
+
+// Disable turns off 2FA
+// POST /api/auth/2fa/disable
+// Body: {"password": "...", "code": "123456"} (password not required for OAuth-only accounts)
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req struct {
+		Password string `json:"password"`
+		Code     string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.twoFactorService.Disable(c.GetString("user_id"), req.Password, req.Code, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes
+// POST /api/auth/2fa/recovery-codes
+// Body: {"code": "123456"}
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.GetString("user_id"), req.Code, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "New recovery codes generated. Previous codes no longer work.",
+		"recovery_codes": codes,
+	})
+}
+
+// CompleteLogin exchanges the challenge token from login (password or OAuth) and a 2FA or recovery code for a session token
+// POST /api/auth/2fa/login
+// Body: {"challenge_token": "...", "code": "123456"}
+func (h *TwoFactorHandler) CompleteLogin(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token, user, isNewDevice, err := h.authService.CompleteTwoFactorLogin(req.ChallengeToken, req.Code, c.GetHeader("User-Agent"), c.ClientIP())
+	if err != nil {
+		if errors.Is(err, models.ErrAccountLocked) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Your account has been temporarily locked due to multiple failed login attempts. Please check your email or try again later.",
+				"code":  "ACCOUNT_LOCKED",
+			})
+			return
+		}
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"user": gin.H{
+			"id":       user.ID,
+			"email":    user.Email,
+			"username": user.Username,
+			"balance":  user.Balance,
+			"is_admin": user.IsAdmin,
+		},
+		"token":         token,
+		"is_new_device": isNewDevice,
+	})
+}
+
+// respondTwoFactorChallenge tells the client to complete the login via /api/auth/2fa/login
+func respondTwoFactorChallenge(c *gin.Context, challengeToken string) {
+	c.JSON(http.StatusOK, gin.H{
+		"message":             "Two-factor authentication required",
+		"two_factor_required": true,
+		"challenge_token":     challengeToken,
+	})
+}
+
+// respondTwoFactorError maps 2FA errors to HTTP responses
+func respondTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": "INVALID_TWO_FACTOR_CODE"})
+	case errors.Is(err, models.ErrTwoFactorRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": "TWO_FACTOR_REQUIRED"})
+	case errors.Is(err, models.ErrInvalidChallengeToken):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid password"})
+	case errors.Is(err, models.ErrTwoFactorAlreadyEnabled), errors.Is(err, models.ErrTwoFactorNotEnabled),
+		errors.Is(err, models.ErrTwoFactorNotEnrolled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Two-factor authentication failed"})
+	}
+}
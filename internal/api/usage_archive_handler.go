@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// UsageArchiveHandler lists and runs the archival of old usage logs and events (admin only)
+type UsageArchiveHandler struct {
+	usageArchive *service.UsageArchiveService
+	audit        *service.AuditService
+}
+
+// NewUsageArchiveHandler creates a new usage archive handler
+func NewUsageArchiveHandler(usageArchive *service.UsageArchiveService, auditService *service.AuditService) *UsageArchiveHandler {
+	return &UsageArchiveHandler{
+		usageArchive: usageArchive,
+		audit:        auditService,
+	}
+}
+
+// ListUsageArchives lists the archived months of usage logs, system and billing events, newest first
+// GET /api/admin/usage-archives
+func (h *UsageArchiveHandler) ListUsageArchives(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	archives, err := h.usageArchive.List(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list usage archives"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"archives": archives,
+		"count":    len(archives),
+	})
+}
+
+// RunUsageArchive archives all months older than USAGE_ARCHIVE_RETENTION_DAYS now
+// The months archived before an error are kept and returned with the error.
+// POST /api/admin/usage-archives/run
+func (h *UsageArchiveHandler) RunUsageArchive(c *gin.Context) {
+	if !c.GetBool("is_admin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	run, err := h.usageArchive.Run(c.Request.Context())
+	if run != nil && len(run.Archives) > 0 {
+		h.audit.Record(auditEntry(c, audit.ActionUsageArchive, "platform", "usage_archive", nil, gin.H{
+			"months": len(run.Archives),
+			"rows":   run.Rows,
+			"cutoff": run.Cutoff,
+		}))
+	}
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, run)
+	case errors.Is(err, service.ErrUsageArchiveDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUsageArchiveRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logger.Error("USAGE-ARCHIVE: Archival run failed", err, nil)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Archival failed: " + err.Error(), "run": run})
+	}
+}
@@ -61,6 +61,17 @@ func (h *VelocityHandler) WakeupServer(c *gin.Context) {
 		return
 	}
 
+	// Suspended servers are held by an admin or billing, players can't wake them
+	if server.Status == models.StatusSuspended {
+		c.JSON(http.StatusForbidden, velocity.WakeupStatus{
+			ServerID: serverID,
+			Status:   string(server.Status),
+			Message:  "Server is suspended",
+			Ready:    false,
+		})
+		return
+	}
+
 	// Check if server is already starting
 	if server.Status == models.StatusStarting {
 		c.JSON(http.StatusAccepted, velocity.WakeupStatus{
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+	"gorm.io/gorm"
+)
+
+// VotifierHandler handles vote site integration endpoints
+type VotifierHandler struct {
+	votifierService *service.VotifierService
+	baseURL         string
+}
+
+// NewVotifierHandler creates a new Votifier handler
+// baseURL is the public API URL vote sites post to (relay endpoint)
+func NewVotifierHandler(votifierService *service.VotifierService, baseURL string) *VotifierHandler {
+	return &VotifierHandler{
+		votifierService: votifierService,
+		baseURL:         baseURL,
+	}
+}
+
+// GetVotifier returns the Votifier setup of a server
+// GET /api/servers/:id/votifier
+func (h *VotifierHandler) GetVotifier(c *gin.Context) {
+	serverID := c.Param("id")
+
+	votifierCfg, err := h.votifierService.GetConfig(serverID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusOK, gin.H{"enabled": false})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.votifierResponse(votifierCfg, nil))
+}
+
+// EnableVotifier sets up NuVotifier (keys, port, plugin); takes effect on the next start
+// POST /api/servers/:id/votifier
+func (h *VotifierHandler) EnableVotifier(c *gin.Context) {
+	serverID := c.Param("id")
+
+	votifierCfg, pluginInstalled, err := h.votifierService.Enable(serverID)
+	if err != nil {
+		if errors.Is(err, service.ErrVotifierUnsupported) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.votifierResponse(votifierCfg, &pluginInstalled))
+}
+
+// DisableVotifier releases the Votifier port; takes effect on the next start
+// DELETE /api/servers/:id/votifier
+func (h *VotifierHandler) DisableVotifier(c *gin.Context) {
+	serverID := c.Param("id")
+
+	if err := h.votifierService.Disable(serverID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Votifier disabled, takes effect on the next server start"})
+}
+
+// RelayVote receives a vote from a vote site and forwards it to the server
+// POST /votes/:server_id (public, authenticated by the relay token)
+// Header: X-Vote-Token (or ?token=)
+// Body: {"service_name": "...", "username": "...", "address": "...", "timestamp": 0}
+func (h *VotifierHandler) RelayVote(c *gin.Context) {
+	serverID := c.Param("server_id")
+
+	token := c.GetHeader("X-Vote-Token")
+	if token == "" {
+		token = c.Query("token")
+	}
+
+	var vote models.Vote
+	if err := c.ShouldBindJSON(&vote); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "service_name and username are required"})
+		return
+	}
+
+	if err := h.votifierService.RelayVote(serverID, token, vote); err != nil {
+		switch {
+		case errors.Is(err, service.ErrVoteUnauthorized):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrVotifierNotEnabled):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrVoteServerOffline):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// votifierResponse adds the values vote sites ask for to the stored setup
+func (h *VotifierHandler) votifierResponse(votifierCfg *models.VotifierConfig, pluginInstalled *bool) gin.H {
+	response := gin.H{
+		"votifier":  votifierCfg,
+		"relay_url": fmt.Sprintf("%s/votes/%s", h.baseURL, votifierCfg.ServerID),
+	}
+	if address, err := h.votifierService.VoteAddress(votifierCfg.ServerID); err == nil {
+		response["address"] = address // host:port for vote sites speaking the Votifier protocol
+	}
+	if pluginInstalled != nil {
+		response["plugin_installed"] = *pluginInstalled
+		response["message"] = "Votifier configured, takes effect on the next server start"
+		if !*pluginInstalled {
+			response["message"] = "Votifier configured, install the NuVotifier plugin to receive votes"
+		}
+	}
+	return response
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// WalletHandler handles prepaid credit endpoints
+type WalletHandler struct {
+	walletService *service.WalletService
+	stripeService *service.StripeService
+}
+
+// NewWalletHandler creates a new wallet handler
+func NewWalletHandler(walletService *service.WalletService, stripeService *service.StripeService) *WalletHandler {
+	return &WalletHandler{
+		walletService: walletService,
+		stripeService: stripeService,
+	}
+}
+
+// GetWallet returns the credit balance of the current user
+// GET /api/wallet
+func (h *WalletHandler) GetWallet(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	balance, err := h.walletService.GetBalance(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get balance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":               h.walletService.IsEnabled(),
+		"prepaid":               h.walletService.IsPrepaid(userID),
+		"balance_eur":           balance,
+		"low_balance_threshold": h.walletService.LowBalanceThreshold(),
+		"low_balance":           balance < h.walletService.LowBalanceThreshold(),
+	})
+}
+
+// GetTransactions returns the balance ledger of the current user
+// GET /api/wallet/transactions?limit=100
+func (h *WalletHandler) GetTransactions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	transactions, err := h.walletService.GetTransactions(c.GetString("user_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transactions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transactions": transactions,
+		"count":        len(transactions),
+	})
+}
+
+// TopUp starts a credit purchase (frontend confirms the payment with Stripe.js)
+// POST /api/wallet/top-up
+func (h *WalletHandler) TopUp(c *gin.Context) {
+	var req struct {
+		AmountEUR float64 `json:"amount_eur" binding:"required,gt=0"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	clientSecret, err := h.stripeService.CreateTopUpIntent(userID, req.AmountEUR)
+	if err != nil {
+		if errors.Is(err, service.ErrStripeDisabled) || errors.Is(err, service.ErrWalletDisabled) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Failed to create wallet top-up", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"client_secret": clientSecret,
+		"amount_eur":    req.AmountEUR,
+	})
+}
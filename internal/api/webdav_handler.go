@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// webdavReadMethods are the WebDAV methods that need files.read; all others need files.write
+var webdavReadMethods = []string{"OPTIONS", "GET", "HEAD", "PROPFIND"}
+
+// webdavWriteMethods change files or take locks for changing them
+var webdavWriteMethods = []string{"PUT", "DELETE", "MKCOL", "COPY", "MOVE", "PROPPATCH", "LOCK", "UNLOCK"}
+
+// WebDAVHandler mounts server directories as WebDAV network drives
+type WebDAVHandler struct {
+	webdavService *service.WebDAVService
+}
+
+// NewWebDAVHandler creates a new WebDAV handler
+func NewWebDAVHandler(webdavService *service.WebDAVService) *WebDAVHandler {
+	return &WebDAVHandler{webdavService: webdavService}
+}
+
+// ServeDAV serves the files of a server over WebDAV
+// Mount https://<host>/dav/<server id>/ with an API key as password.
+func (h *WebDAVHandler) ServeDAV(c *gin.Context) {
+	c.Writer.Header().Del("WWW-Authenticate") // Only for rejected credentials
+
+	serverID := c.Param("id")
+	if err := h.webdavService.ServeHTTP(c.Writer, c.Request, serverID, "/dav/"+serverID); err != nil {
+		switch {
+		case errors.Is(err, models.ErrServerNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrWebDAVArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrDiskQuotaExceeded):
+			c.JSON(http.StatusInsufficientStorage, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// WebhookEndpointHandler handles the account's signed JSON webhook endpoints and their deliveries
+type WebhookEndpointHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookEndpointHandler creates a new webhook endpoint handler
+func NewWebhookEndpointHandler(webhookService *service.WebhookService) *WebhookEndpointHandler {
+	return &WebhookEndpointHandler{webhookService: webhookService}
+}
+
+// webhookEndpointRequest is the body for creating and updating endpoints
+type webhookEndpointRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Description string   `json:"description"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	ServerID    string   `json:"server_id"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+// ListEvents returns the event catalog endpoints can subscribe to
+// GET /api/webhooks/events
+func (h *WebhookEndpointHandler) ListEvents(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"events": events.Catalog,
+		"count":  len(events.Catalog),
+	})
+}
+
+// ListEndpoints returns the webhook endpoints of the current user
+// GET /api/webhooks
+func (h *WebhookEndpointHandler) ListEndpoints(c *gin.Context) {
+	endpoints, err := h.webhookService.ListEndpoints(c.GetString("user_id"))
+	if err != nil {
+		respondWebhookEndpointError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"endpoints": endpoints,
+		"count":     len(endpoints),
+	})
+}
+
+// CreateEndpoint creates a webhook endpoint; the signing secret is only returned here and on rotation
+// POST /api/webhooks
+// Body: {"url": "https://example.com/hooks/payperplay", "events": ["server.*", "backup.failed"], "server_id": "", "description": "Status page"}
+func (h *WebhookEndpointHandler) CreateEndpoint(c *gin.Context) {
+	input, ok := bindWebhookEndpointRequest(c)
+	if !ok {
+		return
+	}
+
+	endpoint, secret, err := h.webhookService.CreateEndpoint(c.GetString("user_id"), input)
+	if err != nil {
+		respondWebhookEndpointError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"endpoint": endpoint,
+		"secret":   secret, // Only returned once
+	})
+}
+
+// GetEndpoint returns a webhook endpoint
+// GET /api/webhooks/:endpoint_id
+func (h *WebhookEndpointHandler) GetEndpoint(c *gin.Context) {
+	endpoint, err := h.webhookService.GetEndpoint(c.GetString("user_id"), c.Param("endpoint_id"))
+	if err != nil {
+		respondWebhookEndpointError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// UpdateEndpoint replaces the URL, subscriptions and server filter of an endpoint, or disables it
+// PUT /api/webhooks/:endpoint_id
+// Body: same as POST /api/webhooks, plus "enabled"
+func (h *WebhookEndpointHandler) UpdateEndpoint(c *gin.Context) {
+	input, ok := bindWebhookEndpointRequest(c)
+	if !ok {
+		return
+	}
+
+	endpoint, err := h.webhookService.UpdateEndpoint(c.GetString("user_id"), c.Param("endpoint_id"), input)
+	if err != nil {
+		respondWebhookEndpointError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// DeleteEndpoint deletes a webhook endpoint with its delivery log
+// DELETE /api/webhooks/:endpoint_id
+func (h *WebhookEndpointHandler) DeleteEndpoint(c *gin.Context) {
+	if err := h.webhookService.DeleteEndpoint(c.GetString("user_id"), c.Param("endpoint_id")); err != nil {
+		respondWebhookEndpointError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook endpoint deleted"})
+}
+
+// RotateSecret replaces the signing secret of an endpoint and returns the new one
+// POST /api/webhooks/:endpoint_id/secret
+func (h *WebhookEndpointHandler) RotateSecret(c *gin.Context) {
+	endpoint, secret, err := h.webhookService.RotateEndpointSecret(c.GetString("user_id"), c.Param("endpoint_id"))
+	if err != nil {
+		respondWebhookEndpointError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"endpoint": endpoint,
+		"secret":   secret, // Only returned once
+	})
+}
+
+// PingEndpoint sends a signed webhook.ping event to the endpoint and returns the delivery
+// POST /api/webhooks/:endpoint_id/ping
+func (h *WebhookEndpointHandler) PingEndpoint(c *gin.Context) {
+	delivery, err := h.webhookService.PingEndpoint(c.GetString("user_id"), c.Param("endpoint_id"))
+	if err != nil {
+		respondWebhookEndpointError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// ListDeliveries returns the delivery log of an endpoint with the attempts of each delivery
+// GET /api/webhooks/:endpoint_id/deliveries
+// Supports ?cursor, ?limit, ?sort and the status/event_type filters.
+func (h *WebhookEndpointHandler) ListDeliveries(c *gin.Context) {
+	page, err := parsePageRequest(c, 50, "status", "event_type")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(c.GetString("user_id"), c.Param("endpoint_id"), page)
+	if errors.Is(err, models.ErrWebhookEndpointNotFound) {
+		respondWebhookEndpointError(c, err)
+		return
+	}
+	if err != nil {
+		respondPageError(c, err)
+		return
+	}
+
+	setPageHeaders(c, deliveries.Total, deliveries.NextCursor)
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries":  deliveries.Items,
+		"count":       len(deliveries.Items),
+		"total":       deliveries.Total,
+		"next_cursor": deliveries.NextCursor,
+	})
+}
+
+// GetDelivery returns a delivery with its payload and attempts
+// GET /api/webhooks/:endpoint_id/deliveries/:delivery_id
+func (h *WebhookEndpointHandler) GetDelivery(c *gin.Context) {
+	delivery, err := h.webhookService.GetDelivery(c.GetString("user_id"), c.Param("endpoint_id"), c.Param("delivery_id"))
+	if err != nil {
+		respondWebhookEndpointError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// Redeliver sends the payload of a delivery again, as a new delivery signed with the current secret
+// Answers 202 with the pending delivery; poll it for the result.
+// POST /api/webhooks/:endpoint_id/deliveries/:delivery_id/redeliver
+func (h *WebhookEndpointHandler) Redeliver(c *gin.Context) {
+	delivery, err := h.webhookService.Redeliver(c.GetString("user_id"), c.Param("endpoint_id"), c.Param("delivery_id"))
+	if err != nil {
+		respondWebhookEndpointError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// bindWebhookEndpointRequest parses the endpoint body (answers 400 and returns false if it's invalid)
+func bindWebhookEndpointRequest(c *gin.Context) (service.WebhookEndpointInput, bool) {
+	var request webhookEndpointRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url and events are required"})
+		return service.WebhookEndpointInput{}, false
+	}
+	return service.WebhookEndpointInput{
+		URL:         request.URL,
+		Description: request.Description,
+		Events:      request.Events,
+		ServerID:    request.ServerID,
+		Enabled:     request.Enabled,
+	}, true
+}
+
+// respondWebhookEndpointError maps webhook endpoint errors to HTTP status codes
+func respondWebhookEndpointError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrWebhookEndpointNotFound), errors.Is(err, models.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrWebhookEndpointInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, models.ErrWebhookEndpointLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logger.Error("WEBHOOKS-API: Request failed", err, map[string]interface{}{
+			"user_id": c.GetString("user_id"),
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Webhook request failed"})
+	}
+}
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/repository"
 	"github.com/payperplay/hosting/internal/service"
 	"github.com/payperplay/hosting/pkg/logger"
@@ -59,7 +61,7 @@ func (h *WebhookHandler) GetWebhook(c *gin.Context) {
 
 // CreateWebhook creates a new webhook configuration
 // POST /api/servers/:id/webhook
-// Body: {"webhook_url": "https://discord.com/api/webhooks/..."}
+// Body: {"webhook_url": "https://discord.com/api/webhooks/...", "provider": "discord"} (provider optional: detected from the URL, "discord" or "slack")
 func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
 	serverID := c.Param("id")
 
@@ -71,7 +73,8 @@ func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
 	}
 
 	var request struct {
-		WebhookURL string `json:"webhook_url" binding:"required"`
+		WebhookURL string                 `json:"webhook_url" binding:"required"`
+		Provider   models.WebhookProvider `json:"provider"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -85,7 +88,12 @@ func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
 		return
 	}
 
-	webhook, err := h.webhookService.CreateWebhook(server.ID, request.WebhookURL)
+	if request.Provider != "" && !request.Provider.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "provider must be discord or slack"})
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(server.ID, request.WebhookURL, request.Provider)
 	if err != nil {
 		logger.Error("Failed to create webhook", err, map[string]interface{}{
 			"server_id": serverID,
@@ -107,7 +115,7 @@ func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
 
 // UpdateWebhook updates webhook configuration
 // PUT /api/servers/:id/webhook
-// Body: {"enabled": true, "on_server_start": true, ...}
+// Body: {"enabled": true, "provider": "slack", "on_server_start": true, "on_backup_failed": true, ...}
 func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
 	serverID := c.Param("id")
 
@@ -126,14 +134,29 @@ func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
 
 	// Only allow specific fields to be updated
 	allowedFields := map[string]bool{
-		"enabled":           true,
-		"webhook_url":       true,
-		"on_server_start":   true,
-		"on_server_stop":    true,
-		"on_server_crash":   true,
-		"on_player_join":    true,
-		"on_player_leave":   true,
-		"on_backup_created": true,
+		"enabled":                true,
+		"webhook_url":            true,
+		"provider":               true,
+		"on_server_start":        true,
+		"on_server_stop":         true,
+		"on_server_crash":        true,
+		"on_server_recovered":    true,
+		"on_player_join":         true,
+		"on_player_leave":        true,
+		"on_backup_created":      true,
+		"on_backup_failed":       true,
+		"on_migration_completed": true,
+		"on_budget_warning":      true,
+		"on_pregen_completed":    true,
+		"on_disk_quota_warning":  true,
+		"on_low_tps":             true,
+	}
+
+	if provider, ok := updates["provider"]; ok {
+		if name, isString := provider.(string); !isString || !models.WebhookProvider(name).Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "provider must be discord or slack"})
+			return
+		}
 	}
 
 	filteredUpdates := make(map[string]interface{})
@@ -216,7 +239,7 @@ func (h *WebhookHandler) TestWebhook(c *gin.Context) {
 		return
 	}
 
-	if err := h.webhookService.TestWebhook(webhook.WebhookURL, server.Name); err != nil {
+	if err := h.webhookService.TestWebhook(webhook, server.Name); err != nil {
 		logger.Error("Failed to send test webhook", err, map[string]interface{}{
 			"server_id": serverID,
 		})
@@ -230,6 +253,14 @@ func (h *WebhookHandler) TestWebhook(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
-		"message": "Test webhook sent successfully! Check your Discord channel.",
+		"message": fmt.Sprintf("Test webhook sent successfully! Check your %s channel.", providerName(webhook.Provider)),
 	})
 }
+
+// providerName returns the display name of a webhook provider
+func providerName(provider models.WebhookProvider) string {
+	if provider == models.WebhookProviderSlack {
+		return "Slack"
+	}
+	return "Discord"
+}
@@ -48,12 +48,16 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	untrack := trackWebSocket(conn)
 	client := ws.NewClient(h.hub, conn)
 	h.hub.Register(client)
 
 	// Start client goroutines
 	go client.WritePump()
-	go client.ReadPump()
+	go func() {
+		client.ReadPump()
+		untrack()
+	}()
 }
 
 // GetStats returns WebSocket statistics
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// webSocketCloseWait bounds writing the close frame to one client
+const webSocketCloseWait = time.Second
+
+// openWebSockets are the WebSocket connections of all handlers (events, dashboard, console, GraphQL).
+// http.Server.Shutdown does not touch hijacked connections, so they are closed by CloseWebSockets.
+var openWebSockets = struct {
+	sync.Mutex
+	conns   map[*websocket.Conn]struct{}
+	closing bool
+}{conns: make(map[*websocket.Conn]struct{})}
+
+// trackWebSocket registers an upgraded connection until the returned func is called
+func trackWebSocket(conn *websocket.Conn) (untrack func()) {
+	openWebSockets.Lock()
+	defer openWebSockets.Unlock()
+	if openWebSockets.closing {
+		closeWebSocket(conn) // Upgraded while the API is shutting down
+		return func() {}
+	}
+	openWebSockets.conns[conn] = struct{}{}
+
+	return func() {
+		openWebSockets.Lock()
+		delete(openWebSockets.conns, conn)
+		openWebSockets.Unlock()
+	}
+}
+
+// CloseWebSockets sends every open WebSocket a "going away" close frame and closes it, so clients
+// reconnect (to another instance or after the restart) right away instead of waiting for a timeout.
+// Registered as http.Server shutdown hook.
+func CloseWebSockets() {
+	openWebSockets.Lock()
+	openWebSockets.closing = true
+	conns := make([]*websocket.Conn, 0, len(openWebSockets.conns))
+	for conn := range openWebSockets.conns {
+		conns = append(conns, conn)
+	}
+	openWebSockets.conns = make(map[*websocket.Conn]struct{})
+	openWebSockets.Unlock()
+
+	for _, conn := range conns {
+		closeWebSocket(conn)
+	}
+	if len(conns) > 0 {
+		logger.Info("Closed WebSocket connections for shutdown", map[string]interface{}{
+			"connections": len(conns),
+		})
+	}
+}
+
+// closeWebSocket writes a close frame (WriteControl may run concurrently with the handler's writes) and closes conn
+func closeWebSocket(conn *websocket.Conn) {
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(webSocketCloseWait))
+	conn.Close()
+}
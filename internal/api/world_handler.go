@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"net/http"
@@ -217,6 +218,184 @@ func (h *WorldHandler) ResetWorld(c *gin.Context) {
 	})
 }
 
+// ResetWorlds regenerates the world of a server after taking a backup
+// Answers 202 with the operation that stops the server if needed, backs it up, deletes the chunks
+// of the selected dimensions (everything, including level.dat, with a new seed), applies the
+// seed and starts the server again if it was running. confirm must be the server name.
+// POST /api/servers/:id/worlds/reset
+// Body: {"confirm": "my-server", "dimensions": ["overworld", "nether", "end"], "seed": "", "random_seed": true}
+func (h *WorldHandler) ResetWorlds(c *gin.Context) {
+	var req service.WorldResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	server, op, err := h.worldService.StartWorldReset(c.Param("id"), c.GetString("user_id"), req)
+	switch {
+	case errors.Is(err, service.ErrWorldResetNotConfirmed),
+		errors.Is(err, service.ErrWorldResetDimension),
+		errors.Is(err, service.ErrWorldResetSeedConflict),
+		errors.Is(err, service.ErrWorldResetSeedPartial):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrWorldResetBusy):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrWorldResetUnavailable):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		respondOperationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"server":    server,
+		"operation": op,
+	})
+}
+
+// ExportWorlds starts creating a ZIP archive of the worlds of a server
+// Answers 202 with the export and its operation; once the export is ready, GET
+// /api/servers/:id/worlds/exports/:export_id returns a signed download_url that works without
+// authentication until expires_at. worlds empty exports all worlds.
+// POST /api/servers/:id/worlds/export
+// Body: {"worlds": ["world", "world_nether", "world_the_end"]}
+func (h *WorldHandler) ExportWorlds(c *gin.Context) {
+	var req struct {
+		Worlds []string `json:"worlds"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	export, op, err := h.worldService.StartExport(c.Param("id"), c.GetString("user_id"), req.Worlds)
+	if err != nil {
+		respondWorldTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"export":    export,
+		"operation": op,
+	})
+}
+
+// ListExports lists the world exports of a server, newest first
+// GET /api/servers/:id/worlds/exports
+func (h *WorldHandler) ListExports(c *gin.Context) {
+	exports, err := h.worldService.ListExports(c.Param("id"))
+	if err != nil {
+		respondWorldTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exports": exports,
+		"count":   len(exports),
+	})
+}
+
+// GetExport returns a world export, with its signed download_url once it is ready
+// GET /api/servers/:id/worlds/exports/:export_id
+func (h *WorldHandler) GetExport(c *gin.Context) {
+	export, err := h.worldService.GetExport(c.Param("id"), c.Param("export_id"))
+	if err != nil {
+		respondWorldTransferError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, export)
+}
+
+// DownloadExport streams the archive of a world export (no auth, the URL is signed)
+// GET /api/world-exports/:export_id/download
+func (h *WorldHandler) DownloadExport(c *gin.Context) {
+	exportID := c.Param("export_id")
+	reader, size, err := h.worldService.OpenExport(exportID, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		respondWorldTransferError(c, err)
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, size, "application/zip", reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=world-export-%s.zip", exportID),
+	})
+}
+
+// ImportWorlds replaces the worlds of a stopped server with an uploaded ZIP archive
+// The archive holds world, world_nether and/or world_the_end folders, or a single world (a folder
+// or the files at the root) that becomes the overworld. Size limits: WORLD_IMPORT_MAX_SIZE_MB for
+// the upload, WORLD_IMPORT_MAX_EXTRACTED_MB extracted. Answers 202 with the operation, which
+// backs the server up and checks every level.dat before replacing anything.
+// POST /api/servers/:id/worlds/import
+// Form data: file (ZIP)
+func (h *WorldHandler) ImportWorlds(c *gin.Context) {
+	// Some headroom for the multipart framing around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.worldService.ImportMaxBytes()+1<<20)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": service.ErrWorldImportTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+		return
+	}
+	if !isZipFile(file) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File must be a ZIP archive"})
+		return
+	}
+
+	upload, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer upload.Close()
+
+	op, err := h.worldService.StartImport(c.Param("id"), c.GetString("user_id"), upload)
+	if err != nil {
+		respondWorldTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"operation": op,
+	})
+}
+
+// respondWorldTransferError maps world export and import errors to HTTP status codes
+func respondWorldTransferError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrWorldExportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidDownloadLink):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWorldImportInvalid), errors.Is(err, service.ErrWorldExportInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWorldImportTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDiskQuotaExceeded):
+		c.JSON(http.StatusInsufficientStorage, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWorldExportNotReady),
+		errors.Is(err, service.ErrWorldExportNoWorlds),
+		errors.Is(err, service.ErrWorldExportArchived),
+		errors.Is(err, service.ErrWorldImportRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWorldTransferUnavailable):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+	default:
+		respondOperationError(c, err)
+	}
+}
+
 // DeleteWorld permanently deletes a world
 // DELETE /api/servers/:id/worlds/:name
 func (h *WorldHandler) DeleteWorld(c *gin.Context) {
@@ -18,6 +18,31 @@ const (
 	ActionContainerMigrate ActionType = "container_migrate"
 	ActionScaleUp          ActionType = "scale_up"
 	ActionScaleDown        ActionType = "scale_down"
+	ActionNodeHealth       ActionType = "node_health"
+	ActionServerFailover   ActionType = "server_failover"
+	ActionNodeEviction     ActionType = "node_eviction"
+
+	// User and admin actions
+	ActionServerDelete    ActionType = "server_delete"
+	ActionServerRAM       ActionType = "server_ram"
+	ActionServerPlacement ActionType = "server_placement"
+	ActionServerCPULimits ActionType = "server_cpu_limits"
+	ActionServerDiskQuota ActionType = "server_disk_quota"
+	ActionServerSuspend   ActionType = "server_suspend"
+	ActionServerUnsuspend ActionType = "server_unsuspend"
+	ActionNodePlacement   ActionType = "node_placement"
+	ActionMaintenanceMode ActionType = "maintenance_mode"
+	ActionConfigReload    ActionType = "config_reload"
+	ActionSSHKeyRotate    ActionType = "ssh_key_rotate"
+	ActionSSHKeyRetire    ActionType = "ssh_key_retire"
+	ActionUsageArchive    ActionType = "usage_archive"
+	ActionStateReconcile  ActionType = "state_reconcile"
+	ActionLifecyclePolicy ActionType = "lifecycle_policy"
+	ActionAnnouncement    ActionType = "announcement"
+	ActionPlayerBan       ActionType = "player_ban"
+	ActionScalingPolicy   ActionType = "scaling_policy"
+	ActionRemoteAction    ActionType = "remote_action"
+	ActionManifestApply   ActionType = "manifest_apply"
 )
 
 // AuditEntry represents a single audit log entry
@@ -31,13 +56,27 @@ type AuditEntry struct {
 	DecisionBy    string                 `json:"decision_by"` // "reactive_policy", "consolidation_policy", "manual"
 	Result        string                 `json:"result"`      // "success", "rejected", "failed"
 	Error         string                 `json:"error,omitempty"`
+
+	// Who changed what (user and admin actions)
+	ActorID      string      `json:"actor_id,omitempty"`      // User who acted, empty for automatic decisions
+	ActorIP      string      `json:"actor_ip,omitempty"`      // Client IP of the request
+	ResourceType string      `json:"resource_type,omitempty"` // "server", "node", ... (node for NodeID entries)
+	ResourceID   string      `json:"resource_id,omitempty"`
+	Before       interface{} `json:"before,omitempty"` // Snapshot before the change
+	After        interface{} `json:"after,omitempty"`  // Snapshot after the change
+}
+
+// Sink persists audit entries beyond the in-memory log
+type Sink interface {
+	Persist(entry AuditEntry)
 }
 
 // AuditLogger logs all destructive actions for accountability
 type AuditLogger struct {
 	entries []AuditEntry
 	mu      sync.RWMutex
-	maxSize int // Maximum entries to keep in memory
+	maxSize int  // Maximum entries to keep in memory
+	sink    Sink // Optional, stores every entry (database)
 }
 
 // NewAuditLogger creates a new audit logger
@@ -52,12 +91,25 @@ func NewAuditLogger(maxSize int) *AuditLogger {
 	}
 }
 
+// SetSink sets where entries are persisted
+func (a *AuditLogger) SetSink(sink Sink) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sink = sink
+}
+
 // Record adds an entry to the audit log
 func (a *AuditLogger) Record(entry AuditEntry) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	entry.Timestamp = time.Now()
+	if entry.ResourceType == "" && entry.NodeID != "" {
+		entry.ResourceType, entry.ResourceID = "node", entry.NodeID
+	}
+	if a.sink != nil {
+		a.sink.Persist(entry)
+	}
 
 	// Add to in-memory log
 	a.entries = append(a.entries, entry)
@@ -76,6 +128,12 @@ func (a *AuditLogger) Record(entry AuditEntry) {
 		"decision_by": entry.DecisionBy,
 		"result":      entry.Result,
 	}
+	if entry.ActorID != "" {
+		fields["actor_id"] = entry.ActorID
+	}
+	if entry.ResourceID != "" {
+		fields["resource"] = entry.ResourceType + "/" + entry.ResourceID
+	}
 
 	// Add state snapshot (but don't log entire snapshot, too verbose)
 	if len(entry.StateSnapshot) > 0 {
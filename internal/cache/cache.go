@@ -0,0 +1,120 @@
+// Package cache keeps JSON copies of hot database rows in Redis (shared by all API instances)
+// or in memory (single instance), so lookups on every WebSocket reconnect and monitoring tick
+// don't go to PostgreSQL. Entries expire after the TTL; writers invalidate them explicitly.
+package cache
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// errorLogEvery limits how often store failures are logged
+const errorLogEvery = time.Minute
+
+// Store holds the encoded entries
+type Store interface {
+	Get(key string) (string, bool, error)
+	Set(key, value string, ttl time.Duration) error
+	Delete(keys ...string) error
+	Close() error
+}
+
+// lookupsTotal counts cache lookups by result (hit, miss, error)
+var lookupsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "payperplay_cache_lookups_total",
+		Help: "Total number of cache lookups by cache and result (hit, miss, error)",
+	},
+	[]string{"cache", "result"},
+)
+
+// Cache stores JSON values with a TTL. Store errors are treated as misses, so an unreachable
+// Redis only costs speed. All methods are safe on a nil *Cache (caching disabled).
+type Cache struct {
+	name  string
+	store Store
+	ttl   time.Duration
+
+	mu         sync.Mutex
+	lastLogged time.Time
+}
+
+// New creates a cache on store whose entries expire after ttl; name labels its metrics and logs
+func New(name string, store Store, ttl time.Duration) *Cache {
+	return &Cache{name: name, store: store, ttl: ttl}
+}
+
+// Get decodes the entry of key into dest and reports whether there was one
+func (c *Cache) Get(key string, dest interface{}) bool {
+	if c == nil {
+		return false
+	}
+	value, ok, err := c.store.Get(key)
+	if err == nil && ok {
+		err = json.Unmarshal([]byte(value), dest)
+		if err == nil {
+			lookupsTotal.WithLabelValues(c.name, "hit").Inc()
+			return true
+		}
+	}
+	if err != nil {
+		lookupsTotal.WithLabelValues(c.name, "error").Inc()
+		c.logFailure("get", err)
+		return false
+	}
+	lookupsTotal.WithLabelValues(c.name, "miss").Inc()
+	return false
+}
+
+// Set stores value under key for the TTL
+func (c *Cache) Set(key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = c.store.Set(key, string(data), c.ttl)
+	}
+	if err != nil {
+		c.logFailure("set", err)
+	}
+}
+
+// Delete removes the entries of keys
+func (c *Cache) Delete(keys ...string) {
+	if c == nil || len(keys) == 0 {
+		return
+	}
+	if err := c.store.Delete(keys...); err != nil {
+		c.logFailure("delete", err)
+	}
+}
+
+// Close closes the store
+func (c *Cache) Close() error {
+	if c == nil {
+		return nil
+	}
+	return c.store.Close()
+}
+
+// logFailure logs a store error, at most once per errorLogEvery
+func (c *Cache) logFailure(operation string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.lastLogged) < errorLogEvery {
+		return
+	}
+	c.lastLogged = time.Now()
+
+	logger.Warn("CACHE: Store unavailable, reading from the database", map[string]interface{}{
+		"cache":     c.name,
+		"operation": operation,
+		"error":     err.Error(),
+	})
+}
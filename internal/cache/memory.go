@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryEntry is a value with its expiry
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// MemoryStore keeps entries in this process (single API instance)
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+// Get returns the value of key unless it expired
+func (s *MemoryStore) Get(key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok {
+		return "", false, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return "", false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set stores value under key for ttl; expired entries are dropped once a minute
+func (s *MemoryStore) Set(key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= time.Minute {
+		s.lastSweep = now
+		for k, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	s.entries[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Delete removes keys
+func (s *MemoryStore) Delete(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// Close does nothing
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/payperplay/hosting/internal/storage"
+)
+
+// redisKeyPrefix separates cache entries from rate limit counters and event streams
+const redisKeyPrefix = "payperplay:cache:"
+
+// RedisStore keeps entries in Redis, shared by all API instances
+type RedisStore struct {
+	client *storage.RedisClient
+}
+
+// NewRedisStore connects to Redis (redis://[user:password@]host:6379[/db])
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	client, err := storage.NewRedisClient(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisStore{client: client}, nil
+}
+
+// Get returns the value of key
+func (s *RedisStore) Get(key string) (string, bool, error) {
+	reply, err := s.client.Do("GET", redisKeyPrefix+key)
+	if err != nil || reply == nil {
+		return "", false, err
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return "", false, fmt.Errorf("unexpected cache reply: %v", reply)
+	}
+	return value, true, nil
+}
+
+// Set stores value under key for ttl
+func (s *RedisStore) Set(key, value string, ttl time.Duration) error {
+	_, err := s.client.Do("SET", redisKeyPrefix+key, value, "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	return err
+}
+
+// Delete removes keys
+func (s *RedisStore) Delete(keys ...string) error {
+	args := make([]string, 0, len(keys)+1)
+	args = append(args, "DEL")
+	for _, key := range keys {
+		args = append(args, redisKeyPrefix+key)
+	}
+	_, err := s.client.Do(args...)
+	return err
+}
+
+// Close closes the connection
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/payperplay/hosting/pkg/logger"
@@ -18,6 +19,7 @@ const (
 
 // HetznerProvider implements CloudProvider for Hetzner Cloud
 type HetznerProvider struct {
+	tokenMu    sync.RWMutex
 	token      string
 	httpClient *http.Client
 }
@@ -32,9 +34,17 @@ func NewHetznerProvider(token string) *HetznerProvider {
 	}
 }
 
+// SetToken replaces the API token (rotated in the secret store)
+func (p *HetznerProvider) SetToken(token string) {
+	p.tokenMu.Lock()
+	defer p.tokenMu.Unlock()
+	p.token = token
+}
+
 // ===== Server Management =====
 
 // CreateServer creates a new cloud server
+// Hetzner has no interruptible instances, spec.Interruptible is ignored (the server is a regular one).
 func (p *HetznerProvider) CreateServer(spec ServerSpec) (*Server, error) {
 	reqBody := map[string]interface{}{
 		"name":        spec.Name,
@@ -437,7 +447,9 @@ func (p *HetznerProvider) request(method, endpoint string, body interface{}) ([]
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	p.tokenMu.RLock()
 	req.Header.Set("Authorization", "Bearer "+p.token)
+	p.tokenMu.RUnlock()
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := p.httpClient.Do(req)
@@ -0,0 +1,86 @@
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// hetznerUsagePageSize is the number of servers fetched per page for the usage report
+const hetznerUsagePageSize = 50
+
+// GetServerUsage returns the usage of every server of the project in [from, to)
+// The Hetzner Cloud API has no invoices, so the usage is computed the way Hetzner bills: the hourly
+// price of the server's location for every started hour, at most the monthly price per month.
+// The period should therefore not span more than one calendar month.
+func (p *HetznerProvider) GetServerUsage(from, to time.Time) ([]*ServerUsage, error) {
+	usage := []*ServerUsage{}
+	for page := 1; page > 0; {
+		resp, err := p.request("GET", fmt.Sprintf("/servers?page=%d&per_page=%d", page, hetznerUsagePageSize), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list servers: %w", err)
+		}
+
+		var result struct {
+			Servers []hetznerServer `json:"servers"`
+			Meta    struct {
+				Pagination struct {
+					NextPage *int `json:"next_page"`
+				} `json:"pagination"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(resp, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		for i := range result.Servers {
+			usage = append(usage, hetznerServerUsage(&result.Servers[i], from, to))
+		}
+
+		page = 0
+		if result.Meta.Pagination.NextPage != nil {
+			page = *result.Meta.Pagination.NextPage
+		}
+	}
+	return usage, nil
+}
+
+// hetznerServerUsage computes the usage of a server from the prices of its location
+func hetznerServerUsage(hs *hetznerServer, from, to time.Time) *ServerUsage {
+	usage := &ServerUsage{
+		ServerID:  strconv.FormatInt(hs.ID, 10),
+		Name:      hs.Name,
+		Type:      hs.ServerType.Name,
+		Location:  hs.Datacenter.Location.Name,
+		Labels:    hs.Labels,
+		CreatedAt: hs.Created,
+	}
+	for _, price := range hs.ServerType.Prices {
+		if price.Location != usage.Location {
+			continue
+		}
+		usage.HourlyPriceEUR, _ = strconv.ParseFloat(price.Hourly.Gross, 64)
+		usage.MonthlyPriceEUR, _ = strconv.ParseFloat(price.Monthly.Gross, 64)
+		break
+	}
+	usage.BilledHours, usage.BilledEUR = billedUsage(usage.HourlyPriceEUR, usage.MonthlyPriceEUR, hs.Created, from, to)
+	return usage
+}
+
+// billedUsage returns the hours and the amount billed for a server created at created in [from, to)
+func billedUsage(hourly, monthly float64, created, from, to time.Time) (int, float64) {
+	if created.After(from) {
+		from = created
+	}
+	if !to.After(from) {
+		return 0, 0
+	}
+	hours := int(math.Ceil(to.Sub(from).Hours()))
+	billed := float64(hours) * hourly
+	if monthly > 0 && billed > monthly {
+		billed = monthly
+	}
+	return hours, billed
+}
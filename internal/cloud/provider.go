@@ -48,6 +48,10 @@ type ServerSpec struct {
 	CloudInit string            // Cloud-Init script
 	Labels    map[string]string // {"managed_by": "payperplay", "type": "cloud"}
 	SSHKeys   []string          // SSH key names/IDs
+
+	// Interruptible requests a spot/preemptible instance the provider may reclaim at short notice
+	// Providers without such instances ignore it and create a regular server (see Server.Interruptible).
+	Interruptible bool
 }
 
 // Server represents a cloud server instance
@@ -62,6 +66,7 @@ type Server struct {
 	CreatedAt     time.Time
 	Labels        map[string]string
 	HourlyCostEUR float64       // Cost per hour
+	Interruptible bool          // Spot/preemptible instance the provider may reclaim
 }
 
 // ServerStatus represents the current state of a server
@@ -106,3 +111,25 @@ type Pricing struct {
 	MonthlyCostEUR float64
 	Currency       string // "EUR"
 }
+
+// UsageProvider is implemented by cloud providers that can report what they bill for their servers
+// (used to reconcile the fleet's cost estimates with the provider's billing)
+type UsageProvider interface {
+	// GetServerUsage returns the usage of every server of the project in [from, to)
+	// Servers deleted before the call are not reported.
+	GetServerUsage(from, to time.Time) ([]*ServerUsage, error)
+}
+
+// ServerUsage is what the provider bills for one server in a period
+type ServerUsage struct {
+	ServerID        string
+	Name            string
+	Type            string
+	Location        string
+	Labels          map[string]string
+	CreatedAt       time.Time
+	HourlyPriceEUR  float64 // Price per hour at the server's location
+	MonthlyPriceEUR float64 // Most billed for the server per month
+	BilledHours     int     // Hours billed in the period (started hours count as full ones)
+	BilledEUR       float64 // Billed in the period
+}
@@ -0,0 +1,180 @@
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec identifies the compression format of a backup or archive
+type Codec string
+
+const (
+	CodecGzip Codec = "gzip" // tar.gz, readable everywhere (parallel: concatenated gzip members)
+	CodecZstd Codec = "zstd" // tar.zst, much faster at similar ratio
+)
+
+// DefaultCodec is used for records created before codecs were stored
+const DefaultCodec = CodecGzip
+
+// ErrUnknownFormat is returned when a stream matches none of the supported codecs
+var ErrUnknownFormat = errors.New("unknown compression format")
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// parallelGzipBlockSize is the uncompressed size of each independently compressed gzip member
+const parallelGzipBlockSize = 1 << 20 // 1 MiB
+
+// Options selects the codec, level and parallelism of a compression operation
+type Options struct {
+	Codec   Codec
+	Level   int // 0 = codec default (gzip 1-9, zstd 1-22)
+	Workers int // 0 = all CPUs, 1 = single-threaded
+}
+
+// ParseCodec validates a codec name ("" returns DefaultCodec)
+func ParseCodec(name string) (Codec, error) {
+	switch Codec(strings.ToLower(strings.TrimSpace(name))) {
+	case "":
+		return DefaultCodec, nil
+	case CodecGzip, "gz":
+		return CodecGzip, nil
+	case CodecZstd, "zst":
+		return CodecZstd, nil
+	}
+	return "", fmt.Errorf("unsupported compression codec %q (supported: gzip, zstd)", name)
+}
+
+// Validate checks codec and level and fills defaults
+func (o *Options) Validate() error {
+	codec, err := ParseCodec(string(o.Codec))
+	if err != nil {
+		return err
+	}
+	o.Codec = codec
+
+	maxLevel := 9
+	if o.Codec == CodecZstd {
+		maxLevel = 22
+	}
+	if o.Level < 0 || o.Level > maxLevel {
+		return fmt.Errorf("%s compression level must be between 1 and %d, got %d", o.Codec, maxLevel, o.Level)
+	}
+	if o.Workers <= 0 {
+		o.Workers = runtime.NumCPU()
+	}
+	return nil
+}
+
+// Extension returns the file extension of a tar stream compressed with codec
+func Extension(codec Codec) string {
+	if codec == CodecZstd {
+		return ".tar.zst"
+	}
+	return ".tar.gz"
+}
+
+// TarFlag returns the GNU tar flag that decompresses codec (zstd needs the zstd binary on the host)
+func TarFlag(codec Codec) string {
+	if codec == CodecZstd {
+		return "--zstd"
+	}
+	return "-z"
+}
+
+// NewWriter wraps w with the compressor selected by opts; Close flushes but doesn't close w
+func NewWriter(w io.Writer, opts Options) (io.WriteCloser, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch opts.Codec {
+	case CodecZstd:
+		level := zstd.SpeedDefault
+		if opts.Level > 0 {
+			level = zstd.EncoderLevelFromZstd(opts.Level)
+		}
+		return zstd.NewWriter(w,
+			zstd.WithEncoderLevel(level),
+			zstd.WithEncoderConcurrency(opts.Workers),
+			zstd.WithZeroFrames(true)) // Empty input still yields a detectable frame
+
+	default:
+		level := gzip.DefaultCompression
+		if opts.Level > 0 {
+			level = opts.Level
+		}
+		if opts.Workers > 1 {
+			return newParallelGzipWriter(w, level, opts.Workers), nil
+		}
+		return gzip.NewWriterLevel(w, level)
+	}
+}
+
+// Detect identifies the codec of a stream from its magic bytes
+func Detect(header []byte) (Codec, error) {
+	switch {
+	case bytes.HasPrefix(header, zstdMagic):
+		return CodecZstd, nil
+	case bytes.HasPrefix(header, gzipMagic):
+		return CodecGzip, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// DetectFile identifies the codec of a file from its magic bytes
+func DetectFile(path string) (Codec, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	header := make([]byte, len(zstdMagic))
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("failed to read header: %w", err)
+	}
+	return Detect(header[:n])
+}
+
+// NewReader auto-detects the codec of r and returns a decompressing reader
+func NewReader(r io.Reader) (io.ReadCloser, Codec, error) {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, "", fmt.Errorf("failed to read header: %w", err)
+	}
+
+	codec, err := Detect(header)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch codec {
+	case CodecZstd:
+		decoder, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		return decoder.IOReadCloser(), codec, nil
+
+	default:
+		reader, err := gzip.NewReader(buffered) // Multistream: reads parallel (multi-member) output
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return reader, codec, nil
+	}
+}
@@ -0,0 +1,138 @@
+package compression
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+)
+
+// parallelGzipWriter compresses fixed-size blocks on several goroutines and writes them in order
+// as concatenated gzip members. Concatenated members are a valid gzip stream (RFC 1952), so the
+// output is readable by gzip, tar -z and Go's multistream reader; the ratio loss at 1 MiB blocks
+// is negligible compared to the speedup on multi-GB worlds.
+type parallelGzipWriter struct {
+	w     io.Writer
+	level int
+
+	buf     []byte
+	pending chan chan gzipBlock // In-order results; capacity bounds blocks in flight
+	done    chan struct{}
+	blocks  int
+	closed  bool
+
+	mu  sync.Mutex
+	err error
+}
+
+type gzipBlock struct {
+	data []byte
+	err  error
+}
+
+func newParallelGzipWriter(w io.Writer, level, workers int) *parallelGzipWriter {
+	p := &parallelGzipWriter{
+		w:       w,
+		level:   level,
+		buf:     make([]byte, 0, parallelGzipBlockSize),
+		pending: make(chan chan gzipBlock, workers),
+		done:    make(chan struct{}),
+	}
+	go p.writeLoop()
+	return p
+}
+
+// writeLoop writes compressed blocks to w in submission order
+func (p *parallelGzipWriter) writeLoop() {
+	defer close(p.done)
+
+	for result := range p.pending {
+		block := <-result
+		if p.getErr() != nil {
+			continue // Drain remaining blocks
+		}
+		if block.err != nil {
+			p.setErr(block.err)
+			continue
+		}
+		if _, err := p.w.Write(block.data); err != nil {
+			p.setErr(err)
+		}
+	}
+}
+
+// Write implements io.Writer
+func (p *parallelGzipWriter) Write(data []byte) (int, error) {
+	if err := p.getErr(); err != nil {
+		return 0, err
+	}
+
+	n := len(data)
+	for len(data) > 0 {
+		room := parallelGzipBlockSize - len(p.buf)
+		if room > len(data) {
+			room = len(data)
+		}
+		p.buf = append(p.buf, data[:room]...)
+		data = data[room:]
+
+		if len(p.buf) == parallelGzipBlockSize {
+			p.submit()
+		}
+	}
+	return n, nil
+}
+
+// submit hands the current block to a compression goroutine (blocks while all workers are busy)
+func (p *parallelGzipWriter) submit() {
+	data := p.buf
+	p.buf = make([]byte, 0, parallelGzipBlockSize)
+	p.blocks++
+
+	result := make(chan gzipBlock, 1)
+	p.pending <- result
+
+	go func() {
+		var out bytes.Buffer
+		zw, err := gzip.NewWriterLevel(&out, p.level)
+		if err == nil {
+			_, err = zw.Write(data)
+		}
+		if err == nil {
+			err = zw.Close()
+		}
+		result <- gzipBlock{data: out.Bytes(), err: err}
+	}()
+}
+
+// Close compresses the last block and waits until everything is written (doesn't close w)
+func (p *parallelGzipWriter) Close() error {
+	if p.closed {
+		return p.getErr()
+	}
+	p.closed = true
+
+	// An empty input still produces one (empty) member so the output is valid gzip
+	if len(p.buf) > 0 || p.blocks == 0 {
+		p.submit()
+	}
+	close(p.pending)
+	<-p.done
+
+	return p.getErr()
+}
+
+func (p *parallelGzipWriter) getErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+func (p *parallelGzipWriter) setErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+}
@@ -2,7 +2,9 @@ package conductor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"sync"
 	"time"
@@ -13,6 +15,7 @@ import (
 	"github.com/payperplay/hosting/internal/docker"
 	"github.com/payperplay/hosting/internal/events"
 	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/monitoring"
 	"github.com/payperplay/hosting/internal/repository"
 	"github.com/payperplay/hosting/pkg/config"
 	"github.com/payperplay/hosting/pkg/logger"
@@ -43,6 +46,16 @@ type Conductor struct {
 	stopChan          chan struct{}              // For graceful shutdown of background workers
 	AuditLog          *audit.AuditLogger         // Audit log for tracking destructive actions
 	queueProcessMu    sync.Mutex                 // Prevents concurrent ProcessStartQueue() calls
+	metricsSink       ContainerMetricsSink       // Receives per-container resource samples (optional)
+
+	nodeKeysMu         sync.RWMutex
+	nodeAuthorizedKeys []string // Public keys put on new nodes by cloud-init (set by SSH key rotation)
+}
+
+// ContainerMetricsSink receives the resource usage samples of the containers on a node
+// Implemented by the container metrics pipeline (service.ContainerMetricsService).
+type ContainerMetricsSink interface {
+	RecordContainerStats(nodeID string, sampledAt time.Time, stats map[string]docker.ContainerStats)
 }
 
 // NodeRepositoryInterface defines the interface for node persistence
@@ -60,6 +73,15 @@ type ServerRepositoryInterface interface {
 	FindByID(id string) (*models.MinecraftServer, error)
 }
 
+// RunningContainerLister lists the running Minecraft containers of the local Docker daemon
+// (implemented by docker.DockerService, used by the startup container sync)
+type RunningContainerLister interface {
+	ListRunningMinecraftContainers(ctx context.Context) ([]struct {
+		ContainerID string
+		ServerID    string
+	}, error)
+}
+
 // NewConductor creates a new conductor instance
 // sshKeyPath is optional - if empty, remote node health checks will be skipped
 // nodeRepo is optional - if nil, nodes will not be persisted to database
@@ -91,6 +113,8 @@ func NewConductor(healthCheckInterval time.Duration, sshKeyPath string, nodeRepo
 	debugLogBuffer := NewDebugLogBuffer(200) // Keep last 200 debug events
 	healthChecker := NewHealthChecker(nodeRegistry, containerRegistry, remoteClient, debugLogBuffer, healthCheckInterval)
 	nodeSelector := NewNodeSelector(nodeRegistry)
+	auditLog := audit.NewAuditLogger(1000) // Keep last 1000 audit entries
+	healthChecker.SetAuditLog(auditLog)
 
 	return &Conductor{
 		NodeRegistry:      nodeRegistry,
@@ -103,7 +127,7 @@ func NewConductor(healthCheckInterval time.Duration, sshKeyPath string, nodeRepo
 		DebugLogBuffer:    debugLogBuffer,
 		StartedAt:         time.Now(), // Track startup time for delay
 		stopChan:          make(chan struct{}),
-		AuditLog:          audit.NewAuditLogger(1000), // Keep last 1000 audit entries
+		AuditLog:          auditLog,
 	}
 }
 
@@ -119,6 +143,7 @@ func (c *Conductor) InitializeScaling(cloudProvider cloud.CloudProvider, sshKeyN
 	c.CloudProvider = cloudProvider
 
 	vmProvisioner := NewVMProvisioner(cloudProvider, c.NodeRegistry, c.DebugLogBuffer, sshKeyName)
+	vmProvisioner.conductor = c // Authorized keys for cloud-init
 	c.ScalingEngine = NewScalingEngine(cloudProvider, vmProvisioner, c.NodeRegistry, c.StartQueue, c.DebugLogBuffer, enabled, velocityClient)
 	c.ScalingEngine.SetConductor(c) // Set back-reference for migrations (B8)
 
@@ -190,116 +215,52 @@ func (c *Conductor) Start() {
 // CRITICAL: This prevents OOM crashes after restarts by detecting existing containers
 // Called on startup to recover state after crashes/restarts/deployments
 //
-// This must be called from main.go after services are initialized
-func (c *Conductor) SyncRunningContainers(dockerSvc interface{}, serverRepo interface{}) {
+// Called by the StateReconciler on startup and on demand; running it again re-registers the
+// containers and recalculates the local node allocation from the registry.
+func (c *Conductor) SyncRunningContainers(dockerSvc RunningContainerLister, serverRepo ServerRepositoryInterface) error {
 	logger.Info("STATE_SYNC: Detecting running Minecraft containers...", nil)
 
-	// Use reflection to call ListRunningMinecraftContainers on dockerSvc
-	dockerVal := reflect.ValueOf(dockerSvc)
-	listMethod := dockerVal.MethodByName("ListRunningMinecraftContainers")
-	if !listMethod.IsValid() {
-		logger.Error("STATE_SYNC: Docker service missing ListRunningMinecraftContainers method", nil, nil)
-		return
-	}
-
-	// Call the method
-	results := listMethod.Call(nil)
-	if len(results) != 2 {
-		logger.Error("STATE_SYNC: Unexpected return from ListRunningMinecraftContainers", nil, nil)
-		return
-	}
-
-	// Check for error (second return value)
-	if !results[1].IsNil() {
-		err := results[1].Interface().(error)
+	containers, err := dockerSvc.ListRunningMinecraftContainers(context.Background())
+	if err != nil {
 		logger.Error("STATE_SYNC: Failed to list containers", err, nil)
-		return
+		return fmt.Errorf("failed to list containers: %w", err)
 	}
 
-	// Get containers slice
-	containersVal := results[0]
-	if containersVal.Len() == 0 {
+	if len(containers) == 0 {
 		logger.Info("STATE_SYNC: No running containers (clean state)", nil)
-		return
+		return nil
 	}
 
 	logger.Info("STATE_SYNC: Found containers, syncing RAM allocations...", map[string]interface{}{
-		"count": containersVal.Len(),
+		"count": len(containers),
 	})
 
 	syncedCount := 0
 	totalRAM := 0
 
-	// Iterate over containers
-	for i := 0; i < containersVal.Len(); i++ {
-		container := containersVal.Index(i)
-		containerID := container.FieldByName("ContainerID").String()
-		serverID := container.FieldByName("ServerID").String()
-
-		// Use reflection to call FindByID on serverRepo
-		repoVal := reflect.ValueOf(serverRepo)
-		findMethod := repoVal.MethodByName("FindByID")
-		if !findMethod.IsValid() {
-			logger.Error("STATE_SYNC: Repository missing FindByID method", nil, nil)
-			continue
-		}
-
-		// Call FindByID(serverID)
-		findResults := findMethod.Call([]reflect.Value{reflect.ValueOf(serverID)})
-		if len(findResults) != 2 {
-			logger.Warn("STATE_SYNC: Unexpected return from FindByID", map[string]interface{}{
-				"server_id": serverID[:8],
-			})
-			continue
-		}
+	for _, container := range containers {
+		containerID := container.ContainerID
+		serverID := container.ServerID
 
-		// Check for error
-		if !findResults[1].IsNil() {
+		server, err := serverRepo.FindByID(serverID)
+		if err != nil {
 			logger.Warn("STATE_SYNC: Container found but server not in DB", map[string]interface{}{
 				"container": containerID[:12],
 				"server_id": serverID[:8],
 			})
 			continue
 		}
-
-		// Get server object
-		serverVal := findResults[0]
-		if serverVal.IsNil() {
+		if server == nil {
 			logger.Warn("STATE_SYNC: Server is nil", map[string]interface{}{
 				"server_id": serverID[:8],
 			})
 			continue
 		}
 
-		// Call GetRAMMb() method on server
-		getRamMethod := serverVal.MethodByName("GetRAMMb")
-		if !getRamMethod.IsValid() {
-			logger.Warn("STATE_SYNC: Server missing GetRAMMb method", map[string]interface{}{
-				"server_id": serverID[:8],
-			})
-			continue
-		}
-
-		ramResults := getRamMethod.Call(nil)
-		if len(ramResults) != 1 {
-			logger.Warn("STATE_SYNC: Unexpected return from GetRAMMb", map[string]interface{}{
-				"server_id": serverID[:8],
-			})
-			continue
-		}
-
-		ramMB := int(ramResults[0].Int())
+		ramMB := server.GetRAMMb()
 
-		// Force allocate RAM (bypass checks - container IS running!)
-		c.NodeRegistry.mu.Lock()
-		if node, exists := c.NodeRegistry.nodes["local-node"]; exists {
-			node.AllocatedRAMMB += ramMB
-			node.ContainerCount++
-		}
-		c.NodeRegistry.mu.Unlock()
-
-		// CRITICAL: Also register in ContainerRegistry to prevent HealthChecker from resetting RAM!
-		// HealthChecker calls GetNodeAllocation() which reads from ContainerRegistry
+		// CRITICAL: Register in ContainerRegistry, the node allocation below is calculated from it
+		// (like the HealthChecker does, so it doesn't reset the RAM)
 		containerInfo := &ContainerInfo{
 			ContainerID: containerID,
 			ServerID:    serverID,
@@ -319,46 +280,49 @@ func (c *Conductor) SyncRunningContainers(dockerSvc interface{}, serverRepo inte
 		})
 	}
 
+	// Force allocate RAM (bypass checks - containers ARE running!)
+	containerCount, allocatedRAMMB := c.ContainerRegistry.GetNodeAllocation("local-node")
+	c.NodeRegistry.UpdateNodeResources("local-node", containerCount, allocatedRAMMB)
+
 	logger.Info("STATE_SYNC: Completed", map[string]interface{}{
 		"synced":       syncedCount,
 		"total_ram_mb": totalRAM,
 	})
+	return nil
 }
 
 // SyncQueuedServers synchronizes queued servers from database into StartQueue
 // CRITICAL: Prevents queue loss after container restart, ensures Worker-Nodes aren't decommissioned prematurely
-// This must be called from main.go after services are initialized
+// Called by the StateReconciler; servers that are already queued keep their place and retry state
 // If triggerScaling is false, no scaling check will be triggered (useful during startup sequence)
-func (c *Conductor) SyncQueuedServers(serverRepo interface{}, triggerScaling bool) {
+func (c *Conductor) SyncQueuedServers(serverRepo interface{}, triggerScaling bool) error {
 	logger.Info("QUEUE_SYNC: Detecting queued servers from database...", nil)
 
 	// Use reflection to call FindByStatus on serverRepo
 	repoVal := reflect.ValueOf(serverRepo)
 	findMethod := repoVal.MethodByName("FindByStatus")
 	if !findMethod.IsValid() {
-		logger.Error("QUEUE_SYNC: Repository missing FindByStatus method", nil, nil)
-		return
+		return fmt.Errorf("repository missing FindByStatus method")
 	}
 
 	// Call FindByStatus("queued")
 	results := findMethod.Call([]reflect.Value{reflect.ValueOf("queued")})
 	if len(results) != 2 {
-		logger.Error("QUEUE_SYNC: Unexpected return from FindByStatus", nil, nil)
-		return
+		return fmt.Errorf("unexpected return from FindByStatus")
 	}
 
 	// Check for error (second return value)
 	if !results[1].IsNil() {
 		err := results[1].Interface().(error)
 		logger.Error("QUEUE_SYNC: Failed to query queued servers", err, nil)
-		return
+		return fmt.Errorf("failed to query queued servers: %w", err)
 	}
 
 	// Get servers slice
 	serversVal := results[0]
 	if serversVal.Len() == 0 {
 		logger.Info("QUEUE_SYNC: No queued servers found (clean state)", nil)
-		return
+		return nil
 	}
 
 	logger.Info("QUEUE_SYNC: Found queued servers, re-enqueuing...", map[string]interface{}{
@@ -376,6 +340,11 @@ func (c *Conductor) SyncQueuedServers(serverRepo interface{}, triggerScaling boo
 		serverName := server.FieldByName("Name").String()
 		ownerID := server.FieldByName("OwnerID").String()
 
+		// Enqueuing again would count as a retry and delay the start
+		if c.StartQueue.GetPosition(serverID) > 0 {
+			continue
+		}
+
 		// Get RAM via GetRAMMb() method (need Addr() for pointer receiver)
 		getRamMethod := serversVal.Index(i).Addr().MethodByName("GetRAMMb")
 		if !getRamMethod.IsValid() {
@@ -423,6 +392,7 @@ func (c *Conductor) SyncQueuedServers(serverRepo interface{}, triggerScaling boo
 		logger.Info("QUEUE_SYNC: Triggering scaling check to provision capacity", nil)
 		c.TriggerScalingCheck()
 	}
+	return nil
 }
 
 // Stop stops the conductor and all its subsystems
@@ -440,6 +410,11 @@ func (c *Conductor) Stop() {
 	// Stop health checker
 	c.HealthChecker.Stop()
 
+	// Close pooled SSH connections to remote nodes
+	if c.RemoteClient != nil {
+		c.RemoteClient.Close()
+	}
+
 	logger.Info("Conductor Core stopped", nil)
 }
 
@@ -681,6 +656,7 @@ func (c *Conductor) ReleaseRAMOnNode(nodeID string, ramMB int) {
 // Returns (canStart bool, reason string)
 // STARTUP-DELAY: Prevents server starts for 2 minutes after API startup (allows CPU to settle)
 // CPU-GUARD: Prevents parallel server starts to avoid CPU overload
+// Reserved-plan servers skip it (ReserveGuaranteedStartSlot); the others can't use their headroom.
 func (c *Conductor) CanStartServer(ramMB int) (bool, string) {
 	// STARTUP-DELAY: Check if API has been running for at least 2 minutes
 	uptime := time.Since(c.StartedAt)
@@ -701,6 +677,11 @@ func (c *Conductor) CanStartServer(ramMB int) (bool, string) {
 		return false, "insufficient RAM capacity"
 	}
 
+	// RESERVED: The headroom of stopped reserved-plan servers is not for other servers
+	if fleetStats.AvailableRAMMB-c.ReservedStandbyRAMMB() < ramMB {
+		return false, "insufficient RAM capacity (rest reserved for guaranteed starts)"
+	}
+
 	return true, ""
 }
 
@@ -850,6 +831,11 @@ func (c *Conductor) SetServerStarter(starter ServerStarter) {
 	c.serverStarter = starter
 }
 
+// SetContainerMetricsSink sets the receiver of the per-container samples taken by the CPU metrics worker
+func (c *Conductor) SetContainerMetricsSink(sink ContainerMetricsSink) {
+	c.metricsSink = sink
+}
+
 // SetServerRepo sets the server repository for ghost container cleanup
 func (c *Conductor) SetServerRepo(repo ServerRepositoryInterface) {
 	c.ServerRepo = repo
@@ -1136,6 +1122,7 @@ func (c *Conductor) periodicQueueWorker() {
 				})
 			}
 			c.ProcessStartQueue()
+			c.recordQueueMetrics()
 		case <-c.stopChan:
 			logger.Info("Periodic queue worker stopped", nil)
 			return
@@ -1143,6 +1130,20 @@ func (c *Conductor) periodicQueueWorker() {
 	}
 }
 
+// recordQueueMetrics exports the start queue length and the longest wait (queue-stuck alerting)
+func (c *Conductor) recordQueueMetrics() {
+	queued := c.StartQueue.GetAll()
+	var oldestWait time.Duration
+	for _, server := range queued {
+		if wait := time.Since(server.FirstQueuedAt); wait > oldestWait {
+			oldestWait = wait
+		}
+	}
+
+	monitoring.StartQueueLength.Set(float64(len(queued)))
+	monitoring.StartQueueOldestWaitSeconds.Set(oldestWait.Seconds())
+}
+
 // reservationTimeoutWorker checks for stale "starting" reservations every 5 minutes
 // and automatically releases them after 30 minutes to prevent permanent deadlocks
 func (c *Conductor) reservationTimeoutWorker() {
@@ -1215,9 +1216,9 @@ func (c *Conductor) cleanStaleReservations(timeout time.Duration) {
 // SelectNodeForContainer selects the best node for a new container using the configured strategy
 // Returns (nodeID, error)
 // This is the Multi-Node equivalent of the old hardcoded "local-node" logic
-func (c *Conductor) SelectNodeForContainer(requiredRAMMB int, strategy SelectionStrategy) (string, error) {
+func (c *Conductor) SelectNodeForContainer(requiredRAMMB int, strategy SelectionStrategy, placement models.NodePlacement) (string, error) {
 	// Use NodeSelector to find the best node
-	nodeID, err := c.NodeSelector.SelectNode(requiredRAMMB, strategy)
+	nodeID, err := c.NodeSelector.SelectNode(requiredRAMMB, strategy, placement)
 	if err != nil {
 		return "", err
 	}
@@ -1234,7 +1235,8 @@ func (c *Conductor) SelectNodeForContainer(requiredRAMMB int, strategy Selection
 // SelectNodeForContainerAuto selects the best node using the recommended strategy
 // This is a convenience method that automatically chooses the best strategy based on fleet composition
 // Returns error if no worker nodes are available (caller should queue and provision)
-func (c *Conductor) SelectNodeForContainerAuto(requiredRAMMB int) (string, error) {
+// or an error wrapping models.ErrNoMatchingNode if no node satisfies the placement (provisioning won't help)
+func (c *Conductor) SelectNodeForContainerAuto(requiredRAMMB int, placement models.NodePlacement) (string, error) {
 	// First check if we have ANY worker nodes at all
 	// If no worker nodes exist, we need to provision one before deployment
 	if c.NodeSelector.GetWorkerNodeCount() == 0 {
@@ -1243,7 +1245,10 @@ func (c *Conductor) SelectNodeForContainerAuto(requiredRAMMB int) (string, error
 
 	// Try to select a node with the recommended strategy
 	recommendedStrategy := c.NodeSelector.GetRecommendedStrategy()
-	nodeID, err := c.SelectNodeForContainer(requiredRAMMB, recommendedStrategy)
+	nodeID, err := c.SelectNodeForContainer(requiredRAMMB, recommendedStrategy, placement)
+	if errors.Is(err, models.ErrNoMatchingNode) {
+		return "", err
+	}
 
 	// If selection failed due to capacity but we have worker nodes, return specific error
 	// This allows the caller to distinguish between "need more capacity" vs "need first worker node"
@@ -1262,6 +1267,20 @@ func (c *Conductor) GetNode(nodeID string) (interface{}, bool) {
 	return node, exists
 }
 
+// SetNodeAuthorizedKeys sets the public keys (authorized_keys lines) new nodes are provisioned with
+func (c *Conductor) SetNodeAuthorizedKeys(keys []string) {
+	c.nodeKeysMu.Lock()
+	defer c.nodeKeysMu.Unlock()
+	c.nodeAuthorizedKeys = append([]string(nil), keys...)
+}
+
+// NodeAuthorizedKeys returns the public keys new nodes are provisioned with (empty = built-in key)
+func (c *Conductor) NodeAuthorizedKeys() []string {
+	c.nodeKeysMu.RLock()
+	defer c.nodeKeysMu.RUnlock()
+	return append([]string(nil), c.nodeAuthorizedKeys...)
+}
+
 // GetRemoteNode builds a RemoteNode struct from a nodeID
 // Returns (RemoteNode, error) - error if node not found or is the local node
 func (c *Conductor) GetRemoteNode(nodeID string) (*docker.RemoteNode, error) {
@@ -1485,7 +1504,7 @@ func (c *Conductor) MigrateServer(serverID, fromNodeID, toNodeID string, velocit
 	return nil
 }
 
-// cpuMetricsWorker collects CPU metrics from cloud nodes every 60 seconds
+// cpuMetricsWorker collects node and container metrics every 60 seconds
 // and publishes NodeStatsEvents for dashboard visualization
 func (c *Conductor) cpuMetricsWorker() {
 	ticker := time.NewTicker(60 * time.Second)
@@ -1513,6 +1532,29 @@ func (c *Conductor) collectCPUMetrics() {
 	for _, node := range nodes {
 		var cpuUsage float64
 
+		// Per-container usage (docker stats): CPU is shown per server and summed into the load of
+		// non-cloud nodes, the full samples feed the container metrics pipeline
+		var containerCPU map[string]float64
+		if !node.IsSystemNode && node.IsHealthy() {
+			sampledAt := time.Now()
+			stats, err := c.collectContainerStats(node)
+			if err != nil {
+				logger.Debug("Failed to get container stats", map[string]interface{}{
+					"node_id": node.ID,
+					"error":   err.Error(),
+				})
+			} else {
+				containerCPU = make(map[string]float64, len(stats))
+				for serverID, sample := range stats {
+					containerCPU[serverID] = sample.CPUPercent
+				}
+				c.ContainerRegistry.UpdateNodeContainerCPU(node.ID, containerCPU)
+				if c.metricsSink != nil {
+					c.metricsSink.RecordContainerStats(node.ID, sampledAt, stats)
+				}
+			}
+		}
+
 		// Get CPU metrics based on node type
 		if node.CloudProviderID != "" && c.CloudProvider != nil {
 			// Cloud node - get metrics from Hetzner API
@@ -1526,9 +1568,11 @@ func (c *Conductor) collectCPUMetrics() {
 			}
 			cpuUsage = cpu
 		} else {
-			// Local/Dedicated node - use Docker stats (TODO: implement local CPU collection)
-			// For now, skip local nodes
-			continue
+			// Local/Dedicated node - sum of its containers' CPU usage, relative to all cores
+			if containerCPU == nil || node.TotalCPUCores == 0 {
+				continue
+			}
+			cpuUsage = nodeCPUPercent(containerCPU, node.TotalCPUCores)
 		}
 
 		// Update node CPU in registry
@@ -1557,6 +1601,39 @@ func (c *Conductor) collectCPUMetrics() {
 	}
 }
 
+// collectContainerStats samples the resource usage of the mc-* containers on a node (serverID -> sample)
+func (c *Conductor) collectContainerStats(node *Node) (map[string]docker.ContainerStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if node.Type == "local" || node.IPAddress == "" || node.IPAddress == "localhost" || node.IPAddress == "127.0.0.1" {
+		cli, err := dockerclient.NewClientWithOpts(dockerclient.FromEnv, dockerclient.WithAPIVersionNegotiation())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Docker client: %w", err)
+		}
+		defer cli.Close()
+		return docker.LocalContainerStats(ctx, cli)
+	}
+
+	if c.RemoteClient == nil {
+		return nil, fmt.Errorf("remote client not configured")
+	}
+	remoteNode, err := c.GetRemoteNode(node.ID)
+	if err != nil {
+		return nil, err
+	}
+	return c.RemoteClient.ContainerStats(ctx, remoteNode)
+}
+
+// nodeCPUPercent converts summed container CPU usage (100 = one core) into node usage (100 = all cores busy)
+func nodeCPUPercent(containerCPU map[string]float64, cores int) float64 {
+	total := 0.0
+	for _, percent := range containerCPU {
+		total += percent
+	}
+	return math.Min(total/float64(cores), 100)
+}
+
 // ghostContainerCleanupWorker periodically cleans up ghost containers from registry
 // Runs every minute to remove containers that no longer exist in database
 func (c *Conductor) ghostContainerCleanupWorker() {
@@ -1599,7 +1676,7 @@ func (c *Conductor) cleanupGhostContainers() {
 // SyncExistingWorkerNodes queries Hetzner API and registers all existing Worker-Nodes
 // CRITICAL: Prevents infinite provisioning loop by recovering nodes after container restart
 // If triggerScaling is false, no scaling check will be triggered (useful during startup sequence)
-func (c *Conductor) SyncExistingWorkerNodes(triggerScaling bool) {
+func (c *Conductor) SyncExistingWorkerNodes(triggerScaling bool) error {
 	logger.Info("WORKER-NODE-SYNC: Starting Worker-Node synchronization from Hetzner API", nil)
 
 	// Query Hetzner for all PayPerPlay-managed cloud nodes
@@ -1613,12 +1690,12 @@ func (c *Conductor) SyncExistingWorkerNodes(triggerScaling bool) {
 		logger.Error("WORKER-NODE-SYNC: Failed to list servers from Hetzner", err, map[string]interface{}{
 			"labels": labels,
 		})
-		return
+		return fmt.Errorf("failed to list servers from Hetzner: %w", err)
 	}
 
 	if len(servers) == 0 {
 		logger.Info("WORKER-NODE-SYNC: No existing Worker-Nodes found at Hetzner", nil)
-		return
+		return nil
 	}
 
 	logger.Info("WORKER-NODE-SYNC: Found existing Worker-Nodes", map[string]interface{}{
@@ -1734,17 +1811,19 @@ func (c *Conductor) SyncExistingWorkerNodes(triggerScaling bool) {
 		logger.Info("WORKER-NODE-SYNC: Triggering scaling check to assign queued servers", nil)
 		c.TriggerScalingCheck()
 	}
+	return nil
 }
 
 // SyncRemoteNodeContainers syncs running containers from all remote worker nodes
 // Called after worker node sync to immediately discover containers on remote nodes
-// Prevents capacity calculation errors after backend restarts
-func (c *Conductor) SyncRemoteNodeContainers(serverRepo interface{}) {
+// Prevents capacity calculation errors after backend restarts. Running it again re-registers the
+// containers and recalculates the node allocations; nodes that couldn't be listed are returned as error.
+func (c *Conductor) SyncRemoteNodeContainers(serverRepo interface{}) error {
 	logger.Info("CONTAINER-SYNC: Detecting running containers on remote worker nodes...", nil)
 
 	if c.RemoteClient == nil {
 		logger.Warn("CONTAINER-SYNC: RemoteClient not initialized, skipping remote sync", nil)
-		return
+		return nil
 	}
 
 	// Get all registered nodes
@@ -1757,6 +1836,7 @@ func (c *Conductor) SyncRemoteNodeContainers(serverRepo interface{}) {
 
 	syncedCount := 0
 	totalRAM := 0
+	var failedNodes []string
 
 	// Iterate through all nodes and sync containers
 	for _, node := range nodes {
@@ -1788,6 +1868,7 @@ func (c *Conductor) SyncRemoteNodeContainers(serverRepo interface{}) {
 				"node_id": node.ID,
 				"error":   err.Error(),
 			})
+			failedNodes = append(failedNodes, node.ID)
 			continue
 		}
 
@@ -1868,14 +1949,6 @@ func (c *Conductor) SyncRemoteNodeContainers(serverRepo interface{}) {
 			}
 			c.ContainerRegistry.RegisterContainer(containerInfo)
 
-			// Update node's RAM allocation
-			c.NodeRegistry.mu.Lock()
-			if n, exists := c.NodeRegistry.nodes[node.ID]; exists {
-				n.AllocatedRAMMB += ramMB
-				n.ContainerCount++
-			}
-			c.NodeRegistry.mu.Unlock()
-
 			totalRAM += ramMB
 			syncedCount++
 
@@ -1888,6 +1961,10 @@ func (c *Conductor) SyncRemoteNodeContainers(serverRepo interface{}) {
 				"port":        minecraftPort,
 			})
 		}
+
+		// Update node's RAM allocation from the registry
+		containerCount, allocatedRAMMB := c.ContainerRegistry.GetNodeAllocation(node.ID)
+		c.NodeRegistry.UpdateNodeResources(node.ID, containerCount, allocatedRAMMB)
 	}
 
 	if syncedCount > 0 {
@@ -1898,6 +1975,11 @@ func (c *Conductor) SyncRemoteNodeContainers(serverRepo interface{}) {
 	} else {
 		logger.Info("CONTAINER-SYNC: No containers found on remote nodes (clean state)", nil)
 	}
+
+	if len(failedNodes) > 0 {
+		return fmt.Errorf("failed to list containers on %d node(s): %v", len(failedNodes), failedNodes)
+	}
+	return nil
 }
 
 // VelocityRemoteClient interface for Velocity integration (dependency injection)
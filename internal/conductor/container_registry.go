@@ -25,6 +25,7 @@ type ContainerInfo struct {
 	MinecraftPort    int       `json:"minecraft_port"`
 	MinecraftVersion string    `json:"minecraft_version"`
 	ServerType       string    `json:"server_type"`
+	CPUPercent       float64   `json:"cpu_percent"` // Last measured CPU usage (100 = one core)
 }
 
 // ContainerRegistry tracks which containers are running on which nodes
@@ -136,6 +137,18 @@ func (r *ContainerRegistry) GetContainersByNode(nodeID string) []*ContainerInfo
 	return containers
 }
 
+// UpdateNodeContainerCPU records the measured CPU usage (serverID -> percent) of the containers on a node
+func (r *ContainerRegistry) UpdateNodeContainerCPU(nodeID string, cpuPercent map[string]float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, container := range r.containers {
+		if container.NodeID == nodeID {
+			container.CPUPercent = cpuPercent[container.ServerID] // 0 if not running
+		}
+	}
+}
+
 // RemoveContainer removes a container from the registry
 func (r *ContainerRegistry) RemoveContainer(serverID string) {
 	r.mu.Lock()
@@ -192,7 +205,7 @@ func (r *ContainerRegistry) RemoveContainersByNode(nodeID string) {
 // GetNodeAllocation returns the total RAM allocated on a specific node
 // Plan-based RAM reservation:
 // - Active containers (running/starting/provisioning): ALWAYS count RAM
-// - Sleeping containers with reserved plan: COUNT RAM (guaranteed quick wake)
+// - Sleeping containers with reserved plan: COUNT RAM (guaranteed quick wake, also while a start claims it)
 // - Sleeping containers with payperplay plan: DON'T count RAM (free capacity)
 func (r *ContainerRegistry) GetNodeAllocation(nodeID string) (containerCount int, allocatedRAMMB int) {
 	r.mu.RLock()
@@ -208,13 +221,11 @@ func (r *ContainerRegistry) GetNodeAllocation(nodeID string) (containerCount int
 			}
 
 			// Sleeping containers: plan-based decision
-			if container.Status == "sleeping" || container.Status == "stopped" {
-				// Reserved plan: keep RAM allocated for guaranteed quick wake
-				if container.PlanType == "reserved" {
-					shouldCountRAM = true
-				}
-				// PayPerPlay/Balanced: free RAM, accept best-effort wake
-				// (will archive quickly anyway - 10min window)
+			// Reserved plan: keep RAM allocated for guaranteed quick wake
+			// PayPerPlay/Balanced: free RAM, accept best-effort wake
+			// (will archive quickly anyway - 10min window)
+			if isHeldReservation(container) {
+				shouldCountRAM = true
 			}
 
 			if shouldCountRAM {
@@ -288,7 +299,7 @@ func (r *ContainerRegistry) GetNodeStats(nodeID string) NodeStats {
 			if container.Status == "running" || container.Status == "starting" || container.Status == "provisioning" {
 				shouldCountRAM = true
 			}
-			if (container.Status == "sleeping" || container.Status == "stopped") && container.PlanType == "reserved" {
+			if isHeldReservation(container) {
 				shouldCountRAM = true
 			}
 
@@ -332,7 +343,7 @@ func (r *ContainerRegistry) GetAllNodeStats() map[string]NodeStats {
 		if container.Status == "running" || container.Status == "starting" || container.Status == "provisioning" {
 			shouldCountRAM = true
 		}
-		if (container.Status == "sleeping" || container.Status == "stopped") && container.PlanType == "reserved" {
+		if isHeldReservation(container) {
 			shouldCountRAM = true
 		}
 
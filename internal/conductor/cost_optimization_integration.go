@@ -1,32 +1,45 @@
 package conductor
 
 import (
+	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
 // CostNodeInfo contains node information for cost optimization
 type CostNodeInfo struct {
-	ID          string
-	Type        string // "dedicated", "cloud", "proxy"
-	CostPerHour float64
-	TotalRAMMB  int
-	UsedRAMMB   int
-	IsHealthy   bool
+	ID           string
+	Type         string // "dedicated", "cloud", "proxy"
+	CostPerHour  float64
+	TotalRAMMB   int
+	UsedRAMMB    int
+	IsHealthy    bool
+	DiskFreeMB   int  // 0 if not measured yet
+	DiskPressure bool // Less free disk than NODE_MIN_FREE_DISK_MB
 }
 
 // GetAllNodesForCostAnalysis returns all nodes with cost information
 func (c *Conductor) GetAllNodesForCostAnalysis() []CostNodeInfo {
 	nodes := c.NodeRegistry.GetAllNodes()
 	costNodes := make([]CostNodeInfo, 0, len(nodes))
+	c.NodeRegistry.mu.RLock()
+	minFreeDiskMB := c.NodeRegistry.minFreeDiskMB
+	c.NodeRegistry.mu.RUnlock()
 
 	for _, node := range nodes {
+		// Customer-dedicated nodes are paid for by their customer: servers are neither moved off nor onto them
+		if node.IsCustomerDedicated() {
+			continue
+		}
+
 		costNode := CostNodeInfo{
-			ID:          node.ID,
-			Type:        node.Type,
-			CostPerHour: node.HourlyCostEUR,
-			TotalRAMMB:  node.TotalRAMMB,
-			UsedRAMMB:   node.AllocatedRAMMB,
-			IsHealthy:   node.HealthStatus == HealthStatusHealthy,
+			ID:           node.ID,
+			Type:         node.Type,
+			CostPerHour:  node.HourlyCostEUR,
+			TotalRAMMB:   node.TotalRAMMB,
+			UsedRAMMB:    node.AllocatedRAMMB,
+			IsHealthy:    node.HealthStatus == HealthStatusHealthy,
+			DiskFreeMB:   node.DiskFreeMB,
+			DiskPressure: node.HasDiskPressure(minFreeDiskMB),
 		}
 		costNodes = append(costNodes, costNode)
 	}
@@ -56,11 +69,67 @@ func (c *Conductor) CanFitServerOnNode(nodeID string, ramMB int) bool {
 		return false
 	}
 
+	// Nodes under disk pressure get no new servers
+	if c.NodeRegistry.HasDiskPressure(nodeID) {
+		return false
+	}
+
 	// Check if enough RAM available
 	availableRAM := node.TotalRAMMB - node.AllocatedRAMMB - node.SystemReservedRAMMB
 	return availableRAM >= ramMB
 }
 
+// NodeHasDiskRoom checks if a node keeps NODE_MIN_FREE_DISK_MB free after taking diskMB of server data
+// Nodes whose disk wasn't measured yet are assumed to have room.
+func (c *Conductor) NodeHasDiskRoom(nodeID string, diskMB int) bool {
+	c.NodeRegistry.mu.RLock()
+	defer c.NodeRegistry.mu.RUnlock()
+
+	node, exists := c.NodeRegistry.nodes[nodeID]
+	if !exists {
+		return false
+	}
+	if node.DiskTotalMB == 0 {
+		return true
+	}
+	return node.DiskFreeMB-diskMB >= c.NodeRegistry.minFreeDiskMB
+}
+
+// NodeAcceptsPlacement checks a server's placement constraints against a specific node
+// Nodes with untolerated PreferNoSchedule taints or peers of the server don't accept voluntary moves
+// (only fallback placement on start).
+func (c *Conductor) NodeAcceptsPlacement(nodeID string, placement models.NodePlacement) bool {
+	c.NodeRegistry.mu.RLock()
+	defer c.NodeRegistry.mu.RUnlock()
+
+	node, exists := c.NodeRegistry.nodes[nodeID]
+	if !exists {
+		return false
+	}
+	allowed, preferred := node.AllowsPlacement(placement)
+	return allowed && preferred && placement.PeersOn(nodeID) == 0
+}
+
+// NodeCountry returns the country a node is located in ("" if the node or its location is unknown)
+func (c *Conductor) NodeCountry(nodeID string) string {
+	c.NodeRegistry.mu.RLock()
+	defer c.NodeRegistry.mu.RUnlock()
+
+	node, exists := c.NodeRegistry.nodes[nodeID]
+	if !exists {
+		return ""
+	}
+	return models.NodeCountry(node.Labels)
+}
+
+// PlacementFor returns the placement of a server of ownerID, pinned to the owner's dedicated nodes if they have any
+func (c *Conductor) PlacementFor(ownerID string, placement models.NodePlacement) models.NodePlacement {
+	if !c.NodeRegistry.HasDedicatedNode(ownerID) {
+		return placement
+	}
+	return placement.Merge(models.DedicatedNodePlacement(ownerID))
+}
+
 // IsScalingSystemStable checks if the system is stable (no recent scaling events)
 func (c *Conductor) IsScalingSystemStable() bool {
 	// Check if queue is being processed
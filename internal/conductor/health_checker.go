@@ -10,16 +10,23 @@ import (
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
+	"github.com/payperplay/hosting/internal/audit"
 	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/monitoring"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
+// remoteDataPath holds server data on worker nodes (see docker.BuildVolumeBinds)
+const remoteDataPath = "/minecraft/servers"
+
 // HealthChecker performs periodic health checks on all nodes
 type HealthChecker struct {
 	nodeRegistry      *NodeRegistry
 	containerRegistry *ContainerRegistry
 	remoteClient      *docker.RemoteDockerClient
 	debugLogBuffer    *DebugLogBuffer
+	auditLog          *audit.AuditLogger // Node health transitions, optional
+	flaps             *flapSuppressor
 	interval          time.Duration
 	stopChan          chan struct{}
 
@@ -27,6 +34,8 @@ type HealthChecker struct {
 	crashCounters     map[string]int  // serverID -> consecutive failed checks
 	crashTimestamps   map[string]time.Time // serverID -> first failure time
 	minecraftService  MinecraftServiceInterface // For stopping crashed servers
+	failover          FailoverHandler           // Takes over the servers of failed nodes, optional
+	eviction          EvictionHandler           // Moves the servers off interruptible nodes about to be terminated, optional
 }
 
 // MinecraftServiceInterface defines methods needed from MinecraftService
@@ -37,6 +46,12 @@ type MinecraftServiceInterface interface {
 	HandleNodeFailure(serverID string) error
 }
 
+// FailoverHandler takes over the servers of a failed node (implemented by service.FailoverService)
+// Without one, the servers are marked as crashed right away.
+type FailoverHandler interface {
+	HandleNodeFailover(nodeID string, serverIDs []string)
+}
+
 // NewHealthChecker creates a new health checker
 func NewHealthChecker(nodeRegistry *NodeRegistry, containerRegistry *ContainerRegistry, remoteClient *docker.RemoteDockerClient, debugLogBuffer *DebugLogBuffer, interval time.Duration) *HealthChecker {
 	return &HealthChecker{
@@ -44,6 +59,7 @@ func NewHealthChecker(nodeRegistry *NodeRegistry, containerRegistry *ContainerRe
 		containerRegistry: containerRegistry,
 		remoteClient:      remoteClient,
 		debugLogBuffer:    debugLogBuffer,
+		flaps:             newFlapSuppressor(),
 		interval:          interval,
 		stopChan:          make(chan struct{}),
 		crashCounters:     make(map[string]int),
@@ -57,6 +73,16 @@ func (h *HealthChecker) SetMinecraftService(service MinecraftServiceInterface) {
 	h.minecraftService = service
 }
 
+// SetFailoverHandler hands the servers of failed nodes to handler instead of only marking them as crashed
+func (h *HealthChecker) SetFailoverHandler(handler FailoverHandler) {
+	h.failover = handler
+}
+
+// SetAuditLog records node health transitions in the audit log
+func (h *HealthChecker) SetAuditLog(auditLog *audit.AuditLogger) {
+	h.auditLog = auditLog
+}
+
 // Start begins the health check loop
 func (h *HealthChecker) Start() {
 	ticker := time.NewTicker(h.interval)
@@ -91,11 +117,31 @@ func (h *HealthChecker) performHealthCheck() {
 
 	for _, node := range nodes {
 		oldStatus := node.Status
-		status := h.checkNodeHealth(node)
+		probe := h.checkNodeHealth(node)
+		status := NodeStatusUnknown
+		if probe != nil {
+			failureThreshold, recoveryThreshold, _ := healthThresholds()
+			status = h.flaps.observe(node.ID, oldStatus, probe.Failed(), failureThreshold, recoveryThreshold)
+		}
 		h.nodeRegistry.UpdateNodeStatus(node.ID, status)
+		h.nodeRegistry.UpdateNodeHealthSignals(node.ID, probe)
+		if probe.Failed() && status != NodeStatusUnhealthy {
+			logger.Warn("Node failed a health check (not marked unhealthy yet)", map[string]interface{}{
+				"node_id": node.ID,
+				"reasons": probe.Reasons(),
+			})
+		}
+
+		nodeUp := 0.0
+		if status == NodeStatusHealthy {
+			nodeUp = 1
+		}
+		monitoring.NodeUp.WithLabelValues(node.ID, node.Hostname, node.Type).Set(nodeUp)
 
 		// LOG STATUS CHANGES (not just debug!)
 		if oldStatus != status {
+			h.recordHealthTransition(node, oldStatus, status, probe)
+
 			if status == NodeStatusUnhealthy {
 				fields := map[string]interface{}{
 					"node_id":     node.ID,
@@ -104,12 +150,13 @@ func (h *HealthChecker) performHealthCheck() {
 					"old_status":  oldStatus,
 					"new_status":  status,
 					"type":        node.Type,
+					"reasons":     probe.Reasons(),
 				}
 				logger.Warn("Node became UNHEALTHY", fields)
 
 				// Add to debug log buffer for dashboard
 				if h.debugLogBuffer != nil {
-					h.debugLogBuffer.Add("WARN", fmt.Sprintf("Node %s became UNHEALTHY (%s)", node.Hostname, node.IPAddress), fields)
+					h.debugLogBuffer.Add("WARN", fmt.Sprintf("Node %s became UNHEALTHY (%s): %s", node.Hostname, node.IPAddress, strings.Join(probe.Reasons(), "; ")), fields)
 				}
 
 				// GAP-1: Handle node failure - cleanup containers, close billing, update status
@@ -164,12 +211,9 @@ func (h *HealthChecker) performHealthCheck() {
 	})
 }
 
-// checkNodeHealth checks if a node is healthy by attempting to connect to Docker
-// Performs comprehensive health checks including:
-// - SSH connectivity (for remote nodes)
-// - Docker daemon health
-// - Resource availability (RAM, CPU, disk)
-func (h *HealthChecker) checkNodeHealth(node *Node) NodeStatus {
+// checkNodeHealth probes a node (see NodeProbe for the signals)
+// Returns nil if the node can't be probed (remote node without SSH client); its status is unknown then.
+func (h *HealthChecker) checkNodeHealth(node *Node) NodeProbe {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -177,130 +221,64 @@ func (h *HealthChecker) checkNodeHealth(node *Node) NodeStatus {
 	isLocal := node.Type == "local" || node.IPAddress == "" || node.IPAddress == "localhost" || node.IPAddress == "127.0.0.1"
 
 	if isLocal {
-		// Local node: Check using local Docker client
-		return h.checkLocalNodeHealth(ctx, node)
+		return h.probeLocalNode(ctx, node)
 	}
 
-	// Remote node: Check using SSH + RemoteDockerClient
-	return h.checkRemoteNodeHealth(ctx, node)
-}
-
-// checkLocalNodeHealth checks the health of the local Docker daemon
-func (h *HealthChecker) checkLocalNodeHealth(ctx context.Context, node *Node) NodeStatus {
-	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
-	if err != nil {
-		logger.Debug("Failed to create Docker client for health check", map[string]interface{}{
-			"node_id": node.ID,
-			"error":   err.Error(),
-		})
-		return NodeStatusUnhealthy
-	}
-	defer dockerClient.Close()
-
-	// Ping Docker daemon
-	_, err = dockerClient.Ping(ctx)
-	if err != nil {
-		logger.Debug("Docker ping failed for local node", map[string]interface{}{
-			"node_id": node.ID,
-			"error":   err.Error(),
-		})
-		return NodeStatusUnhealthy
-	}
-
-	logger.Debug("Local node health check passed", map[string]interface{}{
-		"node_id": node.ID,
-	})
-	return NodeStatusHealthy
-}
-
-// checkRemoteNodeHealth checks the health of a remote node via SSH
-func (h *HealthChecker) checkRemoteNodeHealth(ctx context.Context, node *Node) NodeStatus {
 	if h.remoteClient == nil {
 		logger.Warn("Remote client not configured, skipping remote node health check", map[string]interface{}{
 			"node_id": node.ID,
 		})
-		return NodeStatusUnknown
+		return nil
 	}
+	return h.probeRemoteNode(ctx, node)
+}
 
-	// Create remote node representation
-	remoteNode := &docker.RemoteNode{
-		ID:        node.ID,
-		IPAddress: node.IPAddress,
-		SSHUser:   node.SSHUser,
+// drainOnHighDisk marks a node as draining when its disk fills up, so it gets no new containers
+// GAP-6: Disk Full Monitoring - Mark node as draining if disk > 90%
+func (h *HealthChecker) drainOnHighDisk(node *Node, diskUsage int) {
+	if diskUsage <= 90 {
+		return
 	}
-
-	// 1. SSH Connectivity + Docker Daemon Check
-	err := h.remoteClient.HealthCheck(ctx, remoteNode)
-	if err != nil {
-		logger.Debug("Remote node health check failed", map[string]interface{}{
+	if diskUsage > 95 {
+		// CRITICAL: Disk almost full
+		logger.Error("GAP-6: CRITICAL - Node disk almost full, marking as draining", fmt.Errorf("disk usage critical"), map[string]interface{}{
 			"node_id":    node.ID,
-			"ip_address": node.IPAddress,
-			"error":      err.Error(),
-		})
-		return NodeStatusUnhealthy
-	}
-
-	// 2. Resource Availability Check (RAM, CPU, Disk)
-	diskUsage, err := h.checkRemoteNodeResources(ctx, remoteNode, node)
-	if err != nil {
-		logger.Warn("Failed to check remote node resources", map[string]interface{}{
-			"node_id": node.ID,
-			"error":   err.Error(),
+			"hostname":   node.Hostname,
+			"disk_usage": diskUsage,
 		})
-		// Don't mark as unhealthy if resource check fails, just log warning
-		// This prevents false-positives if the resource check command fails
-	}
-
-	// GAP-6: Disk Full Monitoring - Mark node as draining if disk > 90%
-	if diskUsage > 90 {
-		if diskUsage > 95 {
-			// CRITICAL: Disk almost full
-			logger.Error("GAP-6: CRITICAL - Node disk almost full, marking as draining", fmt.Errorf("disk usage critical"), map[string]interface{}{
-				"node_id":    node.ID,
-				"hostname":   node.Hostname,
-				"disk_usage": diskUsage,
-			})
-		} else {
-			// WARNING: Disk filling up
-			logger.Warn("GAP-6: Node disk filling up, marking as draining", map[string]interface{}{
-				"node_id":    node.ID,
-				"hostname":   node.Hostname,
-				"disk_usage": diskUsage,
-			})
-		}
-
-		// Mark node as draining to prevent new containers
-		node.LifecycleState = NodeStateDraining
-
-		logger.Info("GAP-6: Node marked as draining due to high disk usage", map[string]interface{}{
+	} else {
+		// WARNING: Disk filling up
+		logger.Warn("GAP-6: Node disk filling up, marking as draining", map[string]interface{}{
 			"node_id":    node.ID,
 			"hostname":   node.Hostname,
 			"disk_usage": diskUsage,
 		})
 	}
 
-	logger.Debug("Remote node health check passed", map[string]interface{}{
+	// Mark node as draining to prevent new containers
+	node.LifecycleState = NodeStateDraining
+
+	logger.Info("GAP-6: Node marked as draining due to high disk usage", map[string]interface{}{
 		"node_id":    node.ID,
-		"ip_address": node.IPAddress,
+		"hostname":   node.Hostname,
 		"disk_usage": diskUsage,
 	})
-	return NodeStatusHealthy
 }
 
 // checkRemoteNodeResources checks if a remote node has sufficient resources
-// Returns disk usage percentage (0-100) and error
-func (h *HealthChecker) checkRemoteNodeResources(ctx context.Context, remoteNode *docker.RemoteNode, node *Node) (int, error) {
+// Returns the total and free disk space in MB and error
+func (h *HealthChecker) checkRemoteNodeResources(ctx context.Context, remoteNode *docker.RemoteNode, node *Node) (int, int, error) {
 	// Execute 'free -m' to check available RAM
 	cmd := "free -m | awk 'NR==2{printf \"%d\", $7}'"
 	output, err := h.executeRemoteCommand(ctx, remoteNode, cmd)
 	if err != nil {
-		return 0, fmt.Errorf("failed to check RAM: %w", err)
+		return 0, 0, fmt.Errorf("failed to check RAM: %w", err)
 	}
 
 	// Parse available RAM
 	availableRAM, err := strconv.Atoi(strings.TrimSpace(output))
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse RAM value: %w", err)
+		return 0, 0, fmt.Errorf("failed to parse RAM value: %w", err)
 	}
 
 	// Check if available RAM is critically low (< 500MB)
@@ -311,18 +289,20 @@ func (h *HealthChecker) checkRemoteNodeResources(ctx context.Context, remoteNode
 		})
 	}
 
-	// Execute 'df -h /' to check disk usage
-	cmd = "df -h / | awk 'NR==2{print $5}' | sed 's/%//'"
+	// Execute 'df' on the server data filesystem (the root filesystem until the first server created it)
+	cmd = fmt.Sprintf("(df -P -k %s 2>/dev/null || df -P -k /) | awk 'NR==2{print $2, $4}'", remoteDataPath)
 	output, err = h.executeRemoteCommand(ctx, remoteNode, cmd)
 	if err != nil {
-		return 0, fmt.Errorf("failed to check disk usage: %w", err)
+		return 0, 0, fmt.Errorf("failed to check disk usage: %w", err)
 	}
 
-	// Parse disk usage percentage
-	diskUsage, err := strconv.Atoi(strings.TrimSpace(output))
+	// Parse total and available KB
+	totalMB, freeMB, err := parseDiskFree(output)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse disk usage: %w", err)
+		return 0, 0, fmt.Errorf("failed to parse disk usage: %w", err)
 	}
+	h.recordDisk(node, totalMB, freeMB)
+	diskUsage := (totalMB - freeMB) * 100 / totalMB
 
 	logger.Debug("Remote node resource check passed", map[string]interface{}{
 		"node_id":       node.ID,
@@ -330,7 +310,27 @@ func (h *HealthChecker) checkRemoteNodeResources(ctx context.Context, remoteNode
 		"disk_usage":    diskUsage,
 	})
 
-	return diskUsage, nil
+	return totalMB, freeMB, nil
+}
+
+// recordDisk stores the measured disk space of a node for placement decisions and metrics
+func (h *HealthChecker) recordDisk(node *Node, totalMB, freeMB int) {
+	h.nodeRegistry.UpdateNodeDisk(node.ID, totalMB, freeMB)
+	monitoring.NodeDiskFreeMB.WithLabelValues(node.ID, node.Hostname).Set(float64(freeMB))
+}
+
+// parseDiskFree parses "<total KB> <available KB>" into megabytes
+func parseDiskFree(output string) (int, int, error) {
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected df output %q", output)
+	}
+	totalKB, err1 := strconv.Atoi(fields[0])
+	freeKB, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || totalKB < 1<<10 {
+		return 0, 0, fmt.Errorf("unexpected df output %q", output)
+	}
+	return totalKB >> 10, freeKB >> 10, nil
 }
 
 // executeRemoteCommand executes a command on a remote node via SSH
@@ -574,6 +574,7 @@ func (h *HealthChecker) handleMinecraftCrash(container *ContainerInfo, address s
 // 2. Updating server status from "running" to "crashed"
 // 3. Removing containers from registry
 // 4. Logging for user notification
+// With a FailoverHandler, all of this is left to the handler.
 func (h *HealthChecker) handleNodeFailure(node *Node) {
 	if h.containerRegistry == nil {
 		return
@@ -603,6 +604,13 @@ func (h *HealthChecker) handleNodeFailure(node *Node) {
 		"affected_servers": len(affectedServers),
 	})
 
+	// Failover: the handler confirms the failure, then marks the servers as crashed and restores them
+	// elsewhere; their containers stay registered until then, in case the node comes back
+	if h.failover != nil {
+		h.failover.HandleNodeFailover(node.ID, affectedServers)
+		return
+	}
+
 	// Handle each affected server
 	for _, serverID := range affectedServers {
 		if h.minecraftService == nil {
@@ -1,6 +1,10 @@
 package conductor
 
-import "time"
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+)
 
 // NodeStatus represents the health status of a node
 type NodeStatus string
@@ -21,11 +25,15 @@ type Node struct {
 	TotalRAMMB          int               `json:"total_ram_mb"`
 	TotalCPUCores       int               `json:"total_cpu_cores"`
 	CPUUsagePercent     float64           `json:"cpu_usage_percent"`     // Current CPU usage (0-100%)
+	CPULoadPercent      float64           `json:"cpu_load_percent"`      // Sustained CPU usage (moving average of CPUUsagePercent)
+	DiskTotalMB         int               `json:"disk_total_mb"`         // Filesystem holding server data (0 = not measured yet)
+	DiskFreeMB          int               `json:"disk_free_mb"`          // Available on that filesystem
 	Status              NodeStatus        `json:"status"`                // DEPRECATED: Use HealthStatus instead
 	LifecycleState      NodeLifecycleState `json:"lifecycle_state"`      // Lifecycle stage (provisioning, ready, active, etc.)
 	HealthStatus        HealthStatus      `json:"health_status"`         // Health status (healthy, unhealthy, unknown)
 	Metrics             NodeLifecycleMetrics `json:"metrics"`            // Lifecycle metrics and tracking
 	LastHealthCheck     time.Time         `json:"last_health_check"`
+	HealthSignals       []HealthSignal    `json:"health_signals,omitempty"` // Signals of the last health check (SSH, Docker, disk, load)
 	ContainerCount      int               `json:"container_count"`
 	AllocatedRAMMB      int               `json:"allocated_ram_mb"`
 	SystemReservedRAMMB int               `json:"system_reserved_ram_mb"` // RAM reserved for system processes
@@ -36,9 +44,13 @@ type Node struct {
 	CreatedAt             time.Time         `json:"created_at"`
 	LastContainerAdded    time.Time         `json:"last_container_added"`    // When last container was added
 	LastContainerRemoved  time.Time         `json:"last_container_removed"`  // When last container was removed
-	Labels                map[string]string `json:"labels,omitempty"`  // Cloud provider labels
+	Labels                map[string]string `json:"labels,omitempty"`  // Cloud provider + scheduling labels (matched by server node selectors)
+	Taints                []models.NodeTaint `json:"taints,omitempty"` // Repel servers without a matching toleration
+	DedicatedOwnerID      string            `json:"dedicated_owner_id,omitempty"`  // Customer the node is reserved for (see IsCustomerDedicated)
+	DedicatedPriceEUR     float64           `json:"dedicated_price_eur,omitempty"` // Hourly price billed to that customer
 	HourlyCostEUR         float64           `json:"hourly_cost_eur"`   // For cost tracking
 	CloudProviderID       string            `json:"cloud_provider_id"` // External provider ID (e.g., Hetzner server ID)
+	TerminationNotice     *TerminationNotice `json:"termination_notice,omitempty"` // Interruptible node the provider is about to reclaim
 }
 
 // UsableRAMMB returns the maximum RAM available for BOOKING
@@ -69,6 +81,58 @@ func (n *Node) RAMUtilizationPercent() float64 {
 	return (float64(n.AllocatedRAMMB) / float64(usable)) * 100.0
 }
 
+// DiskUsedPercent returns the disk utilization percentage of the server data filesystem
+func (n *Node) DiskUsedPercent() float64 {
+	if n.DiskTotalMB == 0 {
+		return 0
+	}
+	return float64(n.DiskTotalMB-n.DiskFreeMB) / float64(n.DiskTotalMB) * 100.0
+}
+
+// HasDiskPressure returns true if the node has less than minFreeMB of free disk
+// Nodes whose disk wasn't measured yet (and minFreeMB 0) never have disk pressure.
+func (n *Node) HasDiskPressure(minFreeMB int) bool {
+	return minFreeMB > 0 && n.DiskTotalMB > 0 && n.DiskFreeMB < minFreeMB
+}
+
+// IsCPUBusy returns true if the node's sustained CPU load is at or above busyPercent (0 = never busy)
+func (n *Node) IsCPUBusy(busyPercent float64) bool {
+	return busyPercent > 0 && n.CPULoadPercent >= busyPercent
+}
+
+// IsCustomerDedicated returns true if the node is reserved for a single customer
+// (unrelated to Type "dedicated", which means bare-metal hardware)
+func (n *Node) IsCustomerDedicated() bool {
+	return n.DedicatedOwnerID != ""
+}
+
+// IsInterruptible returns true if the node runs on an interruptible (spot) instance
+func (n *Node) IsInterruptible() bool {
+	return n.Labels[models.NodeLabelInterruptible] == "true"
+}
+
+// AllowsPlacement checks a server's placement constraints against the node's ID, labels and taints
+// Returns (allowed, preferred): preferred is false if the node has an untolerated PreferNoSchedule taint.
+func (n *Node) AllowsPlacement(placement models.NodePlacement) (bool, bool) {
+	if placement.PinnedNodeID != "" && placement.PinnedNodeID != n.ID {
+		return false, false
+	}
+	if !placement.MatchesLabels(n.Labels) {
+		return false, false
+	}
+	preferred := true
+	for _, taint := range n.Taints {
+		if placement.Tolerates(taint) {
+			continue
+		}
+		if taint.Effect == models.TaintNoSchedule {
+			return false, false
+		}
+		preferred = false
+	}
+	return true, preferred
+}
+
 // UptimeDuration returns how long this node has been alive
 func (n *Node) UptimeDuration() time.Duration {
 	return time.Since(n.CreatedAt)
@@ -96,11 +160,14 @@ func (n *Node) IsEmpty() bool {
 // - Must be empty (0 containers)
 // - Must be alive for at least 30 minutes (prevent deleting fresh nodes)
 // - Must be idle for at least 15 minutes (prevent deleting recently emptied nodes)
-// - Must NOT be a System Node
+// - Must NOT be a System Node or dedicated to a customer
 func (n *Node) CanBeConsolidated(minUptime time.Duration, minIdleTime time.Duration) bool {
 	if n.IsSystemNode {
 		return false // Never consolidate system nodes
 	}
+	if n.IsCustomerDedicated() {
+		return false // Paid for by a customer, kept until released
+	}
 	if !n.IsEmpty() {
 		return false // Has containers
 	}
@@ -0,0 +1,143 @@
+package conductor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// terminationNoticePath is where the node agent of an interruptible node writes the provider's
+// termination notice: the time the instance will be terminated (RFC 3339), or nothing if the
+// provider didn't say. The file doesn't exist as long as there is no notice.
+const terminationNoticePath = "/run/payperplay/termination-notice"
+
+// terminationNoticeCommand prints "notice" and the content of the notice file if there is one
+const terminationNoticeCommand = "test -e " + terminationNoticePath + " && echo notice && cat " + terminationNoticePath + " || true"
+
+// TerminationNotice is the provider's announcement that it reclaims an interruptible node
+type TerminationNotice struct {
+	ReceivedAt   time.Time  `json:"received_at"`
+	TerminatesAt *time.Time `json:"terminates_at,omitempty"` // nil = the provider gave no time
+}
+
+// EvictionHandler moves the servers off an interruptible node before the provider terminates it
+// (implemented by service.EvictionService). Without one, the servers stay until the node is gone
+// and are then handled like those of a failed node.
+type EvictionHandler interface {
+	HandleNodeEviction(nodeID string, serverIDs []string, terminatesAt *time.Time)
+}
+
+// SetEvictionHandler hands the servers of interruptible nodes with a termination notice to handler
+func (h *HealthChecker) SetEvictionHandler(handler EvictionHandler) {
+	h.eviction = handler
+}
+
+// parseTerminationNotice parses the output of terminationNoticeCommand (nil = no notice)
+// An unreadable time still is a notice, just one without a known termination time.
+func parseTerminationNotice(output string, receivedAt time.Time) *TerminationNotice {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if strings.TrimSpace(lines[0]) != "notice" {
+		return nil
+	}
+	notice := &TerminationNotice{ReceivedAt: receivedAt}
+	if len(lines) > 1 {
+		if terminatesAt, err := time.Parse(time.RFC3339, strings.TrimSpace(lines[1])); err == nil {
+			notice.TerminatesAt = &terminatesAt
+		}
+	}
+	return notice
+}
+
+// terminationSignal warns (not critical) about a termination notice: the node still runs until then
+func terminationSignal(notice *TerminationNotice) HealthSignal {
+	signal := HealthSignal{Name: SignalTermination, OK: notice == nil, Critical: false}
+	if notice != nil {
+		signal.Detail = "termination notice received"
+		if notice.TerminatesAt != nil {
+			signal.Detail = "terminated by the provider at " + notice.TerminatesAt.UTC().Format(time.RFC3339)
+		}
+	}
+	return signal
+}
+
+// MarkTerminationNotice records the termination notice of a node and drains it (no new containers)
+// Returns false if the node is unknown or already has a notice.
+func (r *NodeRegistry) MarkTerminationNotice(nodeID string, notice *TerminationNotice) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	node, exists := r.nodes[nodeID]
+	if !exists || node.TerminationNotice != nil {
+		return false
+	}
+	node.TerminationNotice = notice
+	node.LifecycleState = NodeStateDraining
+	return true
+}
+
+// handleTerminationNotice drains an interruptible node with a new termination notice and hands its
+// servers to the eviction handler
+func (h *HealthChecker) handleTerminationNotice(node *Node, notice *TerminationNotice) {
+	if !h.nodeRegistry.MarkTerminationNotice(node.ID, notice) {
+		return
+	}
+
+	serverIDs := []string{}
+	if h.containerRegistry != nil {
+		for _, container := range h.containerRegistry.GetContainersByNode(node.ID) {
+			serverIDs = append(serverIDs, container.ServerID)
+		}
+	}
+
+	fields := map[string]interface{}{
+		"node_id":  node.ID,
+		"hostname": node.Hostname,
+		"servers":  len(serverIDs),
+	}
+	if notice.TerminatesAt != nil {
+		fields["terminates_at"] = notice.TerminatesAt.UTC().Format(time.RFC3339)
+	}
+	logger.Warn("EVICTION: Termination notice for interruptible node, draining it", fields)
+	if h.debugLogBuffer != nil {
+		h.debugLogBuffer.Add("WARN", fmt.Sprintf("Node %s will be terminated by the provider, moving %d servers off", node.Hostname, len(serverIDs)), fields)
+	}
+	if h.auditLog != nil {
+		h.auditLog.Record(audit.AuditEntry{
+			Action:     audit.ActionNodeEviction,
+			NodeID:     node.ID,
+			Reason:     terminationSignal(notice).Detail,
+			DecisionBy: "health_checker",
+			Result:     "success",
+			After:      map[string]interface{}{"lifecycle_state": NodeStateDraining, "servers": serverIDs, "termination_notice": notice},
+		})
+	}
+
+	if len(serverIDs) > 0 && h.eviction != nil {
+		h.eviction.HandleNodeEviction(node.ID, serverIDs, notice.TerminatesAt)
+	}
+}
+
+// interruptibleScaleUp returns true if a scale-up may use interruptible (spot) nodes
+// Only if enabled (CLOUD_INTERRUPTIBLE_NODES) and the headroom of the stopped reserved-plan servers
+// fits on the other nodes: reserved servers never start on interruptible ones.
+func (e *ScalingEngine) interruptibleScaleUp(ctx ScalingContext) bool {
+	if config.AppConfig == nil || !config.AppConfig.CloudInterruptibleNodes {
+		return false
+	}
+	return ctx.ReservedStandbyRAMMB <= regularFreeRAMMB(ctx.WorkerNodes)
+}
+
+// regularFreeRAMMB returns the free RAM of the healthy, not interruptible nodes
+func regularFreeRAMMB(nodes []*Node) int {
+	free := 0
+	for _, node := range nodes {
+		if node.IsHealthy() && !node.IsInterruptible() && node.TerminationNotice == nil {
+			free += node.AvailableRAMMB()
+		}
+	}
+	return free
+}
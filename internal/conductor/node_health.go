@@ -0,0 +1,289 @@
+package conductor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/monitoring"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// Health signals probed on every check
+const (
+	SignalSSH    = "ssh"    // The node answers SSH (remote nodes only)
+	SignalDocker = "docker" // docker info succeeds
+	SignalDisk   = "disk"   // The server data filesystem is not full
+	SignalLoad   = "load"   // The load average per core is not excessive
+
+	SignalTermination = "termination" // No termination notice from the provider (interruptible nodes only)
+)
+
+const (
+	// diskCriticalPercent is the disk usage at which containers can no longer write (failed check)
+	diskCriticalPercent = 98
+	// Flap suppression defaults: consecutive failed checks before a healthy node is marked
+	// unhealthy, and consecutive passed checks before an unhealthy node is healthy again
+	defaultHealthFailureThreshold  = 3
+	defaultHealthRecoveryThreshold = 2
+	defaultLoadPerCoreLimit        = 4.0
+)
+
+// HealthSignal is the outcome of one probe of a node
+type HealthSignal struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Critical bool   `json:"critical"` // A failed critical signal fails the check; others are warnings
+	Detail   string `json:"detail,omitempty"`
+}
+
+// NodeProbe is the outcome of all probes of a node in one check
+type NodeProbe []HealthSignal
+
+// Failed reports whether a critical signal failed
+func (p NodeProbe) Failed() bool {
+	for _, signal := range p {
+		if !signal.OK && signal.Critical {
+			return true
+		}
+	}
+	return false
+}
+
+// Reasons describes the failed signals, e.g. "docker: Cannot connect to the Docker daemon"
+func (p NodeProbe) Reasons() []string {
+	var reasons []string
+	for _, signal := range p {
+		if !signal.OK {
+			reasons = append(reasons, signal.Name+": "+signal.Detail)
+		}
+	}
+	return reasons
+}
+
+// flapSuppressor turns single probe results into node status changes that need several
+// consecutive results, so one lost SSH connection doesn't fail a node and its servers over
+type flapSuppressor struct {
+	mu        sync.Mutex
+	failures  map[string]int
+	successes map[string]int
+}
+
+func newFlapSuppressor() *flapSuppressor {
+	return &flapSuppressor{failures: make(map[string]int), successes: make(map[string]int)}
+}
+
+// observe records a probe result and returns the status the node should have now
+// A node without a known status takes the first passed check right away.
+func (f *flapSuppressor) observe(nodeID string, current NodeStatus, failed bool, failureThreshold, recoveryThreshold int) NodeStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if failed {
+		f.failures[nodeID]++
+		f.successes[nodeID] = 0
+		if f.failures[nodeID] >= failureThreshold {
+			return NodeStatusUnhealthy
+		}
+		return current
+	}
+
+	f.successes[nodeID]++
+	f.failures[nodeID] = 0
+	if current == NodeStatusUnhealthy && f.successes[nodeID] < recoveryThreshold {
+		return current
+	}
+	return NodeStatusHealthy
+}
+
+// healthThresholds returns the flap suppression thresholds and the load limit from the configuration
+func healthThresholds() (failures, recoveries int, loadPerCore float64) {
+	failures, recoveries, loadPerCore = defaultHealthFailureThreshold, defaultHealthRecoveryThreshold, defaultLoadPerCoreLimit
+	if cfg := config.AppConfig; cfg != nil {
+		if cfg.NodeHealthFailureThreshold > 0 {
+			failures = cfg.NodeHealthFailureThreshold
+		}
+		if cfg.NodeHealthRecoveryThreshold > 0 {
+			recoveries = cfg.NodeHealthRecoveryThreshold
+		}
+		if cfg.NodeLoadPerCoreLimit > 0 {
+			loadPerCore = cfg.NodeLoadPerCoreLimit
+		}
+	}
+	return failures, recoveries, loadPerCore
+}
+
+// probeLocalNode probes the local Docker daemon, disk and load
+func (h *HealthChecker) probeLocalNode(ctx context.Context, node *Node) NodeProbe {
+	_, _, loadLimit := healthThresholds()
+	probe := NodeProbe{}
+
+	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err == nil {
+		defer dockerClient.Close()
+		_, err = dockerClient.Info(ctx)
+	}
+	probe = append(probe, signalFromError(SignalDocker, true, err))
+
+	if cfg := config.AppConfig; cfg != nil {
+		if stats, err := monitoring.ReadDiskStats(cfg.ServersBasePath); err == nil {
+			totalMB, freeMB := int(stats.TotalBytes>>20), int(stats.AvailableBytes>>20)
+			h.recordDisk(node, totalMB, freeMB)
+			probe = append(probe, diskSignal(totalMB, freeMB))
+		}
+	}
+
+	if loadavg, err := os.ReadFile("/proc/loadavg"); err == nil {
+		if load, cores, err := parseLoad(string(loadavg) + "\n" + strconv.Itoa(runtime.NumCPU())); err == nil {
+			probe = append(probe, loadSignal(load, cores, loadLimit))
+		}
+	}
+	return probe
+}
+
+// probeRemoteNode probes SSH, Docker, disk and load of a remote node
+// Without SSH nothing else can be probed, so a failed SSH signal is the only one.
+func (h *HealthChecker) probeRemoteNode(ctx context.Context, node *Node) NodeProbe {
+	_, _, loadLimit := healthThresholds()
+	remoteNode := &docker.RemoteNode{
+		ID:        node.ID,
+		IPAddress: node.IPAddress,
+		SSHUser:   node.SSHUser,
+	}
+
+	if _, err := h.remoteClient.ExecuteSSHCommand(ctx, remoteNode, "true"); err != nil {
+		return NodeProbe{signalFromError(SignalSSH, true, err)}
+	}
+	probe := NodeProbe{{Name: SignalSSH, OK: true, Critical: true}}
+
+	_, err := h.remoteClient.ExecuteSSHCommand(ctx, remoteNode, "docker info --format '{{.ServerVersion}}'")
+	probe = append(probe, signalFromError(SignalDocker, true, err))
+
+	// Disk (and RAM, logged only); a failing command is not counted against the node
+	if totalMB, freeMB, err := h.checkRemoteNodeResources(ctx, remoteNode, node); err == nil {
+		probe = append(probe, diskSignal(totalMB, freeMB))
+		h.drainOnHighDisk(node, (totalMB-freeMB)*100/totalMB)
+	} else {
+		logger.Warn("Failed to check remote node resources", map[string]interface{}{
+			"node_id": node.ID,
+			"error":   err.Error(),
+		})
+	}
+
+	if output, err := h.remoteClient.ExecuteSSHCommand(ctx, remoteNode, "cat /proc/loadavg; nproc"); err == nil {
+		if load, cores, err := parseLoad(output); err == nil {
+			probe = append(probe, loadSignal(load, cores, loadLimit))
+		}
+	}
+
+	// Termination notice the node agent got from the provider (see terminationNoticePath)
+	if node.IsInterruptible() {
+		if output, err := h.remoteClient.ExecuteSSHCommand(ctx, remoteNode, terminationNoticeCommand); err == nil {
+			notice := parseTerminationNotice(output, time.Now())
+			probe = append(probe, terminationSignal(notice))
+			if notice != nil {
+				h.handleTerminationNotice(node, notice)
+			}
+		}
+	}
+	return probe
+}
+
+// recordHealthTransition reports a node status change with its reasons to the event bus, the
+// dashboard and the audit log
+func (h *HealthChecker) recordHealthTransition(node *Node, oldStatus, newStatus NodeStatus, probe NodeProbe) {
+	reasons := probe.Reasons()
+	events.PublishNodeHealthChanged(node.ID, string(oldStatus), string(newStatus), reasons)
+	events.PublishDashboardNodeHealth(node.ID, string(oldStatus), string(newStatus), reasons)
+
+	if h.auditLog != nil {
+		reason := "all health checks passed"
+		if len(reasons) > 0 {
+			reason = strings.Join(reasons, "; ")
+		}
+		h.auditLog.Record(audit.AuditEntry{
+			Action:     audit.ActionNodeHealth,
+			NodeID:     node.ID,
+			Reason:     reason,
+			DecisionBy: "health_checker",
+			Result:     "success",
+			Before:     map[string]interface{}{"status": oldStatus},
+			After:      map[string]interface{}{"status": newStatus, "signals": probe},
+		})
+	}
+}
+
+// signalFromError builds a signal that passed if err is nil
+func signalFromError(name string, critical bool, err error) HealthSignal {
+	signal := HealthSignal{Name: name, OK: err == nil, Critical: critical}
+	if err != nil {
+		signal.Detail = firstLine(err.Error())
+	}
+	return signal
+}
+
+// diskSignal fails (critically) when the server data filesystem is practically full
+func diskSignal(totalMB, freeMB int) HealthSignal {
+	signal := HealthSignal{Name: SignalDisk, OK: true, Critical: true}
+	if totalMB <= 0 {
+		return signal
+	}
+	usage := (totalMB - freeMB) * 100 / totalMB
+	signal.Detail = fmt.Sprintf("%d%% used, %d MB free", usage, freeMB)
+	signal.OK = usage < diskCriticalPercent
+	return signal
+}
+
+// loadSignal warns (not critical) when the 1-minute load average per core is above limit
+// A busy node still runs its servers; placement already avoids it by its CPU load.
+func loadSignal(load float64, cores int, limit float64) HealthSignal {
+	if cores < 1 {
+		cores = 1
+	}
+	perCore := load / float64(cores)
+	return HealthSignal{
+		Name:     SignalLoad,
+		OK:       perCore <= limit,
+		Critical: false,
+		Detail:   fmt.Sprintf("load %.2f on %d cores", load, cores),
+	}
+}
+
+// parseLoad parses the output of "cat /proc/loadavg; nproc" into the 1-minute load and the cores
+func parseLoad(output string) (float64, int, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 {
+		return 0, 0, fmt.Errorf("unexpected load output %q", output)
+	}
+	fields := strings.Fields(lines[0])
+	if len(fields) == 0 {
+		return 0, 0, fmt.Errorf("unexpected load output %q", output)
+	}
+	load, err1 := strconv.ParseFloat(fields[0], 64)
+	cores, err2 := strconv.Atoi(strings.TrimSpace(lines[1]))
+	if err1 != nil || err2 != nil || cores < 1 {
+		return 0, 0, fmt.Errorf("unexpected load output %q", output)
+	}
+	return load, cores, nil
+}
+
+// firstLine shortens multi-line command errors for reasons
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if len(s) > 200 {
+		s = s[:200]
+	}
+	return s
+}
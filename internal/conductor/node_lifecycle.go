@@ -59,6 +59,11 @@ type NodeLifecycleMetrics struct {
 // CanBeDecommissioned checks if a node can safely be decommissioned
 // Returns (canDecommission, reason)
 func (n *Node) CanBeDecommissioned() (bool, string) {
+	// RULE 0: Never while dedicated to a customer (release it first)
+	if n.IsCustomerDedicated() {
+		return false, "Node is dedicated to a customer"
+	}
+
 	// RULE 1: Never during Provisioning/Init
 	if n.LifecycleState == NodeStateProvisioning ||
 		n.LifecycleState == NodeStateInitializing {
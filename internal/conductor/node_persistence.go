@@ -7,23 +7,27 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
 // PersistedNodeState represents the minimal state needed to restore a cloud node after restart
 // This prevents data loss when the backend restarts while cloud VMs are still running
 type PersistedNodeState struct {
-	ID              string            `json:"id"`
-	Hostname        string            `json:"hostname"`
-	IPAddress       string            `json:"ip_address"`
-	Type            string            `json:"type"`
-	TotalRAMMB      int               `json:"total_ram_mb"`
-	TotalCPUCores   int               `json:"total_cpu_cores"`
-	CloudProviderID string            `json:"cloud_provider_id"`
-	HourlyCostEUR   float64           `json:"hourly_cost_eur"`
-	CreatedAt       time.Time         `json:"created_at"`
-	Labels          map[string]string `json:"labels"`
-	RecoveredAt     *time.Time        `json:"recovered_at,omitempty"` // When this node was last recovered from state file
+	ID                string             `json:"id"`
+	Hostname          string             `json:"hostname"`
+	IPAddress         string             `json:"ip_address"`
+	Type              string             `json:"type"`
+	TotalRAMMB        int                `json:"total_ram_mb"`
+	TotalCPUCores     int                `json:"total_cpu_cores"`
+	CloudProviderID   string             `json:"cloud_provider_id"`
+	HourlyCostEUR     float64            `json:"hourly_cost_eur"`
+	CreatedAt         time.Time          `json:"created_at"`
+	Labels            map[string]string  `json:"labels"`
+	Taints            []models.NodeTaint `json:"taints,omitempty"`
+	DedicatedOwnerID  string             `json:"dedicated_owner_id,omitempty"`
+	DedicatedPriceEUR float64            `json:"dedicated_price_eur,omitempty"`
+	RecoveredAt       *time.Time         `json:"recovered_at,omitempty"` // When this node was last recovered from state file
 }
 
 // SaveNodeState persists all cloud nodes to a JSON file
@@ -36,16 +40,19 @@ func (c *Conductor) SaveNodeState(filePath string) error {
 		// Only persist cloud nodes (dedicated nodes are always registered on startup)
 		if node.Type == "cloud" && !node.IsSystemNode {
 			state := PersistedNodeState{
-				ID:              node.ID,
-				Hostname:        node.Hostname,
-				IPAddress:       node.IPAddress,
-				Type:            node.Type,
-				TotalRAMMB:      node.TotalRAMMB,
-				TotalCPUCores:   node.TotalCPUCores,
-				CloudProviderID: node.CloudProviderID,
-				HourlyCostEUR:   node.HourlyCostEUR,
-				CreatedAt:       node.CreatedAt,
-				Labels:          node.Labels,
+				ID:                node.ID,
+				Hostname:          node.Hostname,
+				IPAddress:         node.IPAddress,
+				Type:              node.Type,
+				TotalRAMMB:        node.TotalRAMMB,
+				TotalCPUCores:     node.TotalCPUCores,
+				CloudProviderID:   node.CloudProviderID,
+				HourlyCostEUR:     node.HourlyCostEUR,
+				CreatedAt:         node.CreatedAt,
+				Labels:            node.Labels,
+				Taints:            node.Taints,
+				DedicatedOwnerID:  node.DedicatedOwnerID,
+				DedicatedPriceEUR: node.DedicatedPriceEUR,
 			}
 			cloudNodes = append(cloudNodes, state)
 		}
@@ -142,25 +149,28 @@ func (c *Conductor) RestoreNodesFromState(filePath string) error {
 
 		// Create node object with recovery timestamp
 		node := &Node{
-			ID:               state.ID,
-			Hostname:         state.Hostname,
-			IPAddress:        state.IPAddress,
-			Type:             state.Type,
-			TotalRAMMB:       state.TotalRAMMB,
-			TotalCPUCores:    state.TotalCPUCores,
-			Status:           NodeStatusHealthy, // Will be verified by health checker
-			LifecycleState:   NodeStateReady,
-			HealthStatus:     HealthStatusUnknown,
-			LastHealthCheck:  now,
-			ContainerCount:   0,
-			AllocatedRAMMB:   0,
-			DockerSocketPath: "/var/run/docker.sock",
-			SSHUser:          "root",
-			CreatedAt:        state.CreatedAt,
-			Labels:           state.Labels,
-			HourlyCostEUR:    state.HourlyCostEUR,
-			CloudProviderID:  state.CloudProviderID,
-			IsSystemNode:     false,
+			ID:                state.ID,
+			Hostname:          state.Hostname,
+			IPAddress:         state.IPAddress,
+			Type:              state.Type,
+			TotalRAMMB:        state.TotalRAMMB,
+			TotalCPUCores:     state.TotalCPUCores,
+			Status:            NodeStatusHealthy, // Will be verified by health checker
+			LifecycleState:    NodeStateReady,
+			HealthStatus:      HealthStatusUnknown,
+			LastHealthCheck:   now,
+			ContainerCount:    0,
+			AllocatedRAMMB:    0,
+			DockerSocketPath:  "/var/run/docker.sock",
+			SSHUser:           "root",
+			CreatedAt:         state.CreatedAt,
+			Labels:            state.Labels,
+			Taints:            state.Taints,
+			DedicatedOwnerID:  state.DedicatedOwnerID,
+			DedicatedPriceEUR: state.DedicatedPriceEUR,
+			HourlyCostEUR:     state.HourlyCostEUR,
+			CloudProviderID:   state.CloudProviderID,
+			IsSystemNode:      false,
 			Metrics: NodeLifecycleMetrics{
 				ProvisionedAt:             state.CreatedAt,
 				InitializedAt:             &now,
@@ -1,6 +1,7 @@
 package conductor
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -9,21 +10,61 @@ import (
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
+// nodeStatusPersistInterval is how often an unchanged node status is written to the database
+// (refreshes last_health_check there); the registry itself is updated on every health check
+const nodeStatusPersistInterval = 5 * time.Minute
+
+// persistedNodeState is what the database holds for a node, so unchanged health check
+// results aren't written again
+type persistedNodeState struct {
+	status         NodeStatus
+	statusAt       time.Time
+	containerCount int
+	allocatedRAMMB int
+	resources      bool // containerCount and allocatedRAMMB were written
+}
+
 // NodeRegistry manages the fleet of nodes
 type NodeRegistry struct {
-	nodes    map[string]*Node
-	mu       sync.RWMutex
-	nodeRepo *repository.NodeRepository
+	nodes          map[string]*Node
+	mu             sync.RWMutex
+	nodeRepo       *repository.NodeRepository
+	minFreeDiskMB  int     // Nodes with less free disk are under disk pressure (0 = disk is ignored)
+	cpuBusyPercent float64 // Nodes with a higher sustained CPU load are busy (0 = CPU load is ignored)
+	persisted      map[string]*persistedNodeState
 }
 
 // NewNodeRegistry creates a new node registry
 func NewNodeRegistry(nodeRepo *repository.NodeRepository) *NodeRegistry {
 	return &NodeRegistry{
-		nodes:    make(map[string]*Node),
-		nodeRepo: nodeRepo,
+		nodes:             make(map[string]*Node),
+		nodeRepo:          nodeRepo,
+		persisted:         make(map[string]*persistedNodeState),
 	}
 }
 
+// SetMinFreeDiskMB sets the free disk space below which nodes get no new servers (NODE_MIN_FREE_DISK_MB)
+func (r *NodeRegistry) SetMinFreeDiskMB(minFreeDiskMB int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.minFreeDiskMB = minFreeDiskMB
+}
+
+// SetCPUBusyPercent sets the sustained CPU load at which nodes only get new servers as a last resort (NODE_CPU_BUSY_PERCENT)
+func (r *NodeRegistry) SetCPUBusyPercent(cpuBusyPercent float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cpuBusyPercent = cpuBusyPercent
+}
+
+// HasDiskPressure returns true if a node has less free disk than NODE_MIN_FREE_DISK_MB
+func (r *NodeRegistry) HasDiskPressure(nodeID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	node, exists := r.nodes[nodeID]
+	return exists && node.HasDiskPressure(r.minFreeDiskMB)
+}
+
 // RegisterNode adds or updates a node in the registry
 func (r *NodeRegistry) RegisterNode(node *Node) {
 	r.mu.Lock()
@@ -36,7 +77,27 @@ func (r *NodeRegistry) RegisterNode(node *Node) {
 		node.CreatedAt = time.Now()
 	}
 
+	// Keep scheduling labels/taints set by admins when a node re-registers (provisioner/recovery only know cloud labels)
+	if existing, ok := r.nodes[node.ID]; ok && existing != node {
+		if node.Labels == nil {
+			node.Labels = make(map[string]string)
+		}
+		for key, value := range existing.Labels {
+			if _, set := node.Labels[key]; !set {
+				node.Labels[key] = value
+			}
+		}
+		if node.Taints == nil {
+			node.Taints = existing.Taints
+		}
+		if node.DedicatedOwnerID == "" {
+			node.DedicatedOwnerID = existing.DedicatedOwnerID
+			node.DedicatedPriceEUR = existing.DedicatedPriceEUR
+		}
+	}
+
 	r.nodes[node.ID] = node
+	delete(r.persisted, node.ID) // The whole node is written below
 
 	// Persist to database if repository is available
 	if r.nodeRepo != nil {
@@ -123,6 +184,129 @@ func (r *NodeRegistry) GetHealthyNodes() []*Node {
 	return nodes
 }
 
+// SetNodePlacement replaces the scheduling labels and taints of a node
+// Servers already running on the node are not moved; the new values apply to future placements.
+func (r *NodeRegistry) SetNodePlacement(nodeID string, labels map[string]string, taints []models.NodeTaint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	node, exists := r.nodes[nodeID]
+	if !exists {
+		return fmt.Errorf("node %s not found", nodeID)
+	}
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+
+	// The dedicated-customer label/taint is managed by SetNodeDedication only
+	delete(labels, models.DedicatedNodeKey)
+	kept := make([]models.NodeTaint, 0, len(taints)+1)
+	for _, taint := range taints {
+		if taint.Key != models.DedicatedNodeKey {
+			kept = append(kept, taint)
+		}
+	}
+	taints = kept
+	if node.IsCustomerDedicated() {
+		labels[models.DedicatedNodeKey] = node.DedicatedOwnerID
+		taints = append(taints, models.DedicatedNodeTaint(node.DedicatedOwnerID))
+	}
+
+	node.Labels = labels
+	node.Taints = taints
+
+	logger.Info("NODE-REGISTRY: Node placement updated", map[string]interface{}{
+		"node_id": nodeID,
+		"labels":  models.FormatNodeLabels(labels),
+		"taints":  models.FormatNodeTaints(taints),
+	})
+
+	// Persist to database if repository is available
+	if r.nodeRepo != nil {
+		if err := r.nodeRepo.UpdatePlacement(nodeID, models.FormatNodeLabels(labels), models.FormatNodeTaints(taints)); err != nil {
+			return fmt.Errorf("failed to persist node placement: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetNodeDedication reserves a node for a single customer (ownerID "" releases it)
+// The dedicated-customer label and NoSchedule taint keep other customers' servers off the node.
+func (r *NodeRegistry) SetNodeDedication(nodeID, ownerID string, priceEUR float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	node, exists := r.nodes[nodeID]
+	if !exists {
+		return models.ErrNodeNotFound
+	}
+
+	labels := make(map[string]string, len(node.Labels)+1)
+	for key, value := range node.Labels {
+		if key != models.DedicatedNodeKey {
+			labels[key] = value
+		}
+	}
+	taints := make([]models.NodeTaint, 0, len(node.Taints)+1)
+	for _, taint := range node.Taints {
+		if taint.Key != models.DedicatedNodeKey {
+			taints = append(taints, taint)
+		}
+	}
+	if ownerID != "" {
+		labels[models.DedicatedNodeKey] = ownerID
+		taints = append(taints, models.DedicatedNodeTaint(ownerID))
+	} else {
+		priceEUR = 0
+	}
+
+	// Persist first: a dedication that is lost on restart would stop billing but keep the node reserved
+	if r.nodeRepo != nil {
+		if err := r.nodeRepo.UpdateDedication(nodeID, ownerID, priceEUR, models.FormatNodeLabels(labels), models.FormatNodeTaints(taints)); err != nil {
+			return fmt.Errorf("failed to persist node dedication: %w", err)
+		}
+	}
+
+	node.Labels = labels
+	node.Taints = taints
+	node.DedicatedOwnerID = ownerID
+	node.DedicatedPriceEUR = priceEUR
+
+	logger.Info("NODE-REGISTRY: Node dedication updated", map[string]interface{}{
+		"node_id":   nodeID,
+		"owner_id":  ownerID,
+		"price_eur": priceEUR,
+	})
+	return nil
+}
+
+// GetDedicatedNodes returns all nodes reserved for a customer (ownerID "" = all customers)
+func (r *NodeRegistry) GetDedicatedNodes(ownerID string) []*Node {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	nodes := make([]*Node, 0)
+	for _, node := range r.nodes {
+		if node.IsCustomerDedicated() && (ownerID == "" || node.DedicatedOwnerID == ownerID) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// HasDedicatedNode returns true if at least one node is reserved for ownerID
+func (r *NodeRegistry) HasDedicatedNode(ownerID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, node := range r.nodes {
+		if node.DedicatedOwnerID == ownerID && ownerID != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // GetNodesByType returns all nodes of a specific type (dedicated, cloud, spare)
 func (r *NodeRegistry) GetNodesByType(nodeType string) []*Node {
 	r.mu.RLock()
@@ -146,9 +330,12 @@ func (r *NodeRegistry) UpdateNodeStatus(nodeID string, status NodeStatus) {
 		node.Status = status
 		node.LastHealthCheck = time.Now()
 
-		// Persist to database if repository is available
-		if r.nodeRepo != nil {
-			if err := r.nodeRepo.UpdateStatus(nodeID, string(status)); err != nil {
+		// Persist to database if repository is available; an unchanged status only now and then
+		state := r.persistedState(nodeID)
+		if r.nodeRepo != nil && (state.status != status || time.Since(state.statusAt) >= nodeStatusPersistInterval) {
+			if err := r.nodeRepo.UpdateStatus(nodeID, string(status)); err == nil {
+				state.status, state.statusAt = status, node.LastHealthCheck
+			} else {
 				logger.Warn("NODE-REGISTRY: Failed to update node status in database", map[string]interface{}{
 					"node_id": nodeID,
 					"error":   err.Error(),
@@ -158,6 +345,16 @@ func (r *NodeRegistry) UpdateNodeStatus(nodeID string, status NodeStatus) {
 	}
 }
 
+// persistedState returns the last database write of a node; callers hold r.mu
+func (r *NodeRegistry) persistedState(nodeID string) *persistedNodeState {
+	state, ok := r.persisted[nodeID]
+	if !ok {
+		state = &persistedNodeState{}
+		r.persisted[nodeID] = state
+	}
+	return state
+}
+
 // UpdateNodeResources updates the resource allocation for a node
 func (r *NodeRegistry) UpdateNodeResources(nodeID string, containerCount int, allocatedRAMMB int) {
 	r.mu.Lock()
@@ -167,9 +364,12 @@ func (r *NodeRegistry) UpdateNodeResources(nodeID string, containerCount int, al
 		node.ContainerCount = containerCount
 		node.AllocatedRAMMB = allocatedRAMMB
 
-		// Persist to database if repository is available
-		if r.nodeRepo != nil {
-			if err := r.nodeRepo.UpdateResources(nodeID, containerCount, allocatedRAMMB); err != nil {
+		// Persist to database if repository is available and the allocation changed
+		state := r.persistedState(nodeID)
+		if r.nodeRepo != nil && (!state.resources || state.containerCount != containerCount || state.allocatedRAMMB != allocatedRAMMB) {
+			if err := r.nodeRepo.UpdateResources(nodeID, containerCount, allocatedRAMMB); err == nil {
+				state.containerCount, state.allocatedRAMMB, state.resources = containerCount, allocatedRAMMB, true
+			} else {
 				logger.Warn("NODE-REGISTRY: Failed to update node resources in database", map[string]interface{}{
 					"node_id": nodeID,
 					"error":   err.Error(),
@@ -179,13 +379,44 @@ func (r *NodeRegistry) UpdateNodeResources(nodeID string, containerCount int, al
 	}
 }
 
-// UpdateNodeCPU updates the CPU usage for a node
+// cpuLoadSmoothing weights new CPU samples in a node's sustained load
+// Samples arrive every minute, so the load follows a change within about 5 minutes.
+const cpuLoadSmoothing = 0.2
+
+// UpdateNodeCPU updates the CPU usage and sustained CPU load of a node
 func (r *NodeRegistry) UpdateNodeCPU(nodeID string, cpuUsagePercent float64) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if node, exists := r.nodes[nodeID]; exists {
 		node.CPUUsagePercent = cpuUsagePercent
+		// Sustained load: exponential moving average, a single spike doesn't make a node busy
+		if node.CPULoadPercent == 0 {
+			node.CPULoadPercent = cpuUsagePercent
+		} else {
+			node.CPULoadPercent += cpuLoadSmoothing * (cpuUsagePercent - node.CPULoadPercent)
+		}
+	}
+}
+
+// UpdateNodeDisk updates the disk size and free disk space of a node
+func (r *NodeRegistry) UpdateNodeDisk(nodeID string, totalMB, freeMB int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if node, exists := r.nodes[nodeID]; exists {
+		node.DiskTotalMB = totalMB
+		node.DiskFreeMB = freeMB
+	}
+}
+
+// UpdateNodeHealthSignals stores the signals of the last health check of a node
+func (r *NodeRegistry) UpdateNodeHealthSignals(nodeID string, signals []HealthSignal) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if node, exists := r.nodes[nodeID]; exists {
+		node.HealthSignals = signals
 	}
 }
 
@@ -195,6 +426,7 @@ func (r *NodeRegistry) RemoveNode(nodeID string) {
 	defer r.mu.Unlock()
 
 	delete(r.nodes, nodeID)
+	delete(r.persisted, nodeID)
 
 	// Remove from database if repository is available
 	if r.nodeRepo != nil {
@@ -357,6 +589,18 @@ func (r *NodeRegistry) GetFleetStats() FleetStats {
 			continue // Skip capacity calculations for system nodes
 		}
 
+		// Customer-dedicated nodes aren't shared capacity: counting their free RAM would delay scale-ups
+		if node.IsCustomerDedicated() {
+			stats.TotalNodes++
+			stats.CustomerNodes++
+			if node.IsHealthy() {
+				stats.HealthyNodes++
+			} else {
+				stats.UnhealthyNodes++
+			}
+			continue
+		}
+
 		// Worker nodes (non-system): count everything for capacity planning
 		stats.TotalNodes++
 		stats.TotalRAMMB += node.TotalRAMMB
@@ -364,6 +608,14 @@ func (r *NodeRegistry) GetFleetStats() FleetStats {
 		stats.TotalCPUCores += node.TotalCPUCores
 		stats.AllocatedRAMMB += node.AllocatedRAMMB
 		stats.TotalContainers += node.ContainerCount
+		stats.TotalDiskMB += node.DiskTotalMB
+		stats.FreeDiskMB += node.DiskFreeMB
+		if node.HasDiskPressure(r.minFreeDiskMB) {
+			stats.DiskPressureNodes++
+		}
+		if node.IsCPUBusy(r.cpuBusyPercent) {
+			stats.CPUBusyNodes++
+		}
 
 		if node.IsHealthy() {
 			stats.HealthyNodes++
@@ -405,6 +657,7 @@ type FleetStats struct {
 	UnhealthyNodes        int     `json:"unhealthy_nodes"`
 	DedicatedNodes        int     `json:"dedicated_nodes"`
 	CloudNodes            int     `json:"cloud_nodes"`
+	CustomerNodes         int     `json:"customer_nodes"`           // Dedicated to a single customer (not in capacity figures)
 	TotalRAMMB            int     `json:"total_ram_mb"`             // Total physical RAM across all nodes
 	SystemReservedRAMMB   int     `json:"system_reserved_ram_mb"`   // RAM reserved for system processes
 	UsableRAMMB           int     `json:"usable_ram_mb"`            // Total - SystemReserved (capacity for containers)
@@ -413,6 +666,10 @@ type FleetStats struct {
 	RAMUtilizationPercent float64 `json:"ram_utilization_percent"`  // Allocated / Usable * 100
 	TotalCPUCores         int     `json:"total_cpu_cores"`
 	TotalContainers       int     `json:"total_containers"`
+	TotalDiskMB           int     `json:"total_disk_mb"`            // Server data filesystems of measured nodes
+	FreeDiskMB            int     `json:"free_disk_mb"`             // Free space on those filesystems
+	DiskPressureNodes     int     `json:"disk_pressure_nodes"`      // Below NODE_MIN_FREE_DISK_MB, get no new servers
+	CPUBusyNodes          int     `json:"cpu_busy_nodes"`           // Sustained CPU load above NODE_CPU_BUSY_PERCENT
 }
 
 // LoadNodesFromDB loads all nodes from the database into the in-memory registry
@@ -477,11 +734,27 @@ func (r *NodeRegistry) nodeToDBModel(node *Node) *models.Node {
 		HourlyCostEUR:        node.HourlyCostEUR,
 		CloudProviderID:      node.CloudProviderID,
 		CPUUsagePercent:      node.CPUUsagePercent,
+		Labels:               models.FormatNodeLabels(node.Labels),
+		Taints:               models.FormatNodeTaints(node.Taints),
+		DedicatedOwnerID:     node.DedicatedOwnerID,
+		DedicatedPriceEUR:    node.DedicatedPriceEUR,
 	}
 }
 
 // dbModelToNode converts a models.Node to a conductor.Node for in-memory use
 func (r *NodeRegistry) dbModelToNode(dbNode *models.Node) *Node {
+	labels, err := models.ParseNodeLabels(dbNode.Labels)
+	if err != nil {
+		labels = make(map[string]string)
+	}
+	taints, err := models.ParseNodeTaints(dbNode.Taints)
+	if err != nil {
+		logger.Warn("NODE-REGISTRY: Ignoring invalid node taints from database", map[string]interface{}{
+			"node_id": dbNode.ID,
+			"taints":  dbNode.Taints,
+		})
+	}
+
 	return &Node{
 		ID:                   dbNode.ID,
 		Hostname:             dbNode.Hostname,
@@ -506,7 +779,10 @@ func (r *NodeRegistry) dbModelToNode(dbNode *models.Node) *Node {
 		CreatedAt:            dbNode.CreatedAt,
 		LastContainerAdded:   dbNode.LastContainerAdded,
 		LastContainerRemoved: dbNode.LastContainerRemoved,
-		Labels:               make(map[string]string),
+		Labels:               labels,
+		Taints:               taints,
+		DedicatedOwnerID:     dbNode.DedicatedOwnerID,
+		DedicatedPriceEUR:    dbNode.DedicatedPriceEUR,
 		HourlyCostEUR:        dbNode.HourlyCostEUR,
 		CloudProviderID:      dbNode.CloudProviderID,
 	}
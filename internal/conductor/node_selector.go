@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
@@ -32,8 +33,12 @@ const (
 )
 
 // SelectNode selects the best node for a new container based on the strategy
-// Returns (nodeID, error)
-func (ns *NodeSelector) SelectNode(requiredRAMMB int, strategy SelectionStrategy) (string, error) {
+// Only nodes whose labels match placement.Selector and whose NoSchedule taints are tolerated are considered;
+// nodes with untolerated PreferNoSchedule taints or a sustained CPU load of NODE_CPU_BUSY_PERCENT
+// are only used if no other node fits. A pinned server only goes to its pinned node; a server with
+// an anti-affinity group or spread goes to the nodes running the fewest of its peers.
+// Returns (nodeID, error) - errors wrap models.ErrNoMatchingNode if capacity exists but not on a matching node
+func (ns *NodeSelector) SelectNode(requiredRAMMB int, strategy SelectionStrategy, placement models.NodePlacement) (string, error) {
 	ns.nodeRegistry.mu.RLock()
 	defer ns.nodeRegistry.mu.RUnlock()
 
@@ -45,6 +50,31 @@ func (ns *NodeSelector) SelectNode(requiredRAMMB int, strategy SelectionStrategy
 		return "", fmt.Errorf("no nodes available with sufficient capacity (%d MB required)", requiredRAMMB)
 	}
 
+	// Apply node labels/taints
+	candidates = ns.filterPlacement(candidates, placement)
+	if len(candidates) == 0 {
+		if placement.Country != "" {
+			logger.Warn("RESIDENCY: No node in the required country, placement blocked", map[string]interface{}{
+				"country":      placement.Country,
+				"required_ram": requiredRAMMB,
+			})
+			return "", fmt.Errorf("%w (%d MB required, country: %s, selector: %s)", models.ErrNoMatchingNode,
+				requiredRAMMB, placement.Country, models.FormatNodeLabels(placement.Selector))
+		}
+		if placement.PinnedNodeID != "" {
+			return "", fmt.Errorf("%w (%d MB required, pinned to node %s)", models.ErrNoMatchingNode,
+				requiredRAMMB, placement.PinnedNodeID)
+		}
+		return "", fmt.Errorf("%w (%d MB required, selector: %s, tolerations: %s)", models.ErrNoMatchingNode,
+			requiredRAMMB, models.FormatNodeLabels(placement.Selector), models.FormatNodeTolerations(placement.Tolerations))
+	}
+
+	// Keep servers of an anti-affinity group (or spread servers) apart
+	candidates = ns.filterPeers(candidates, placement)
+
+	// Keep servers off nodes with a sustained high CPU load (their servers would lose TPS)
+	candidates = ns.filterCPULoad(candidates)
+
 	// Apply strategy
 	var selectedNode *Node
 	switch strategy {
@@ -74,6 +104,7 @@ func (ns *NodeSelector) SelectNode(requiredRAMMB int, strategy SelectionStrategy
 		"required_ram":  requiredRAMMB,
 		"available_ram": selectedNode.AvailableRAMMB(),
 		"utilization":   fmt.Sprintf("%.1f%%", selectedNode.RAMUtilizationPercent()),
+		"cpu_load":      fmt.Sprintf("%.1f%%", selectedNode.CPULoadPercent),
 	})
 
 	return selectedNode.ID, nil
@@ -91,6 +122,8 @@ func (ns *NodeSelector) getCandidates(requiredRAMMB int) []*Node {
 		//    - Minecraft servers should only run on worker nodes
 		// 4. GAP-10: Node must NOT be draining (being decommissioned)
 		//    - Prevents starting containers on nodes that are about to be deleted
+		// 5. Node must have at least NODE_MIN_FREE_DISK_MB of free disk
+		//    - A new server (world, plugins, backups) would fill it up
 
 		// PROPORTIONAL OVERHEAD: Check against TotalRAM, not UsableRAM
 		// System overhead is now distributed proportionally across all containers
@@ -102,6 +135,11 @@ func (ns *NodeSelector) getCandidates(requiredRAMMB int) []*Node {
 			continue
 		}
 
+		// Skip nodes under disk pressure
+		if node.HasDiskPressure(ns.nodeRegistry.minFreeDiskMB) {
+			continue
+		}
+
 		if node.IsHealthy() && availableRAM >= requiredRAMMB && !node.IsSystemNode {
 			candidates = append(candidates, node)
 		}
@@ -110,6 +148,70 @@ func (ns *NodeSelector) getCandidates(requiredRAMMB int) []*Node {
 	return candidates
 }
 
+// filterPlacement returns the candidates the placement allows, preferring nodes without untolerated PreferNoSchedule taints
+func (ns *NodeSelector) filterPlacement(candidates []*Node, placement models.NodePlacement) []*Node {
+	var preferred, fallback []*Node
+	for _, node := range candidates {
+		allowed, isPreferred := node.AllowsPlacement(placement)
+		if !allowed {
+			continue
+		}
+		if isPreferred {
+			preferred = append(preferred, node)
+		} else {
+			fallback = append(fallback, node)
+		}
+	}
+
+	if len(preferred) > 0 {
+		return preferred
+	}
+	return fallback
+}
+
+// filterPeers returns the candidates running the fewest of the server's peers
+// Peers sharing a node beat not starting the server, so a node is only skipped if another one has fewer.
+func (ns *NodeSelector) filterPeers(candidates []*Node, placement models.NodePlacement) []*Node {
+	if !placement.SpreadsPeers() {
+		return candidates
+	}
+
+	var fewest []*Node
+	for _, node := range candidates {
+		switch {
+		case len(fewest) == 0 || placement.PeersOn(node.ID) < placement.PeersOn(fewest[0].ID):
+			fewest = []*Node{node}
+		case placement.PeersOn(node.ID) == placement.PeersOn(fewest[0].ID):
+			fewest = append(fewest, node)
+		}
+	}
+
+	if len(fewest) < len(candidates) {
+		logger.Debug("Placement: skipping nodes running peers of the server", map[string]interface{}{
+			"anti_affinity_group": placement.AntiAffinityGroup,
+			"spread":              placement.Spread,
+			"peers_on_selected":   placement.PeersOn(fewest[0].ID),
+		})
+	}
+	return fewest
+}
+
+// filterCPULoad returns the candidates below NODE_CPU_BUSY_PERCENT of sustained CPU load,
+// or all candidates if every one of them is busy (a busy node beats no node)
+func (ns *NodeSelector) filterCPULoad(candidates []*Node) []*Node {
+	var idle []*Node
+	for _, node := range candidates {
+		if !node.IsCPUBusy(ns.nodeRegistry.cpuBusyPercent) {
+			idle = append(idle, node)
+		}
+	}
+
+	if len(idle) > 0 {
+		return idle
+	}
+	return candidates
+}
+
 // selectBestFit selects the node with the smallest available RAM that still fits
 // This minimizes wasted capacity and keeps nodes efficiently packed
 func (ns *NodeSelector) selectBestFit(candidates []*Node, requiredRAMMB int) *Node {
@@ -117,9 +219,12 @@ func (ns *NodeSelector) selectBestFit(candidates []*Node, requiredRAMMB int) *No
 		return nil
 	}
 
-	// Sort by available RAM (ascending)
+	// Sort by available RAM (ascending), equally full nodes by CPU load
 	sort.Slice(candidates, func(i, j int) bool {
-		return candidates[i].AvailableRAMMB() < candidates[j].AvailableRAMMB()
+		if candidates[i].AvailableRAMMB() != candidates[j].AvailableRAMMB() {
+			return candidates[i].AvailableRAMMB() < candidates[j].AvailableRAMMB()
+		}
+		return candidates[i].CPULoadPercent < candidates[j].CPULoadPercent
 	})
 
 	// Return the node with the least available RAM (but still enough)
@@ -133,9 +238,12 @@ func (ns *NodeSelector) selectWorstFit(candidates []*Node, requiredRAMMB int) *N
 		return nil
 	}
 
-	// Sort by available RAM (descending)
+	// Sort by available RAM (descending), equally free nodes by CPU load
 	sort.Slice(candidates, func(i, j int) bool {
-		return candidates[i].AvailableRAMMB() > candidates[j].AvailableRAMMB()
+		if candidates[i].AvailableRAMMB() != candidates[j].AvailableRAMMB() {
+			return candidates[i].AvailableRAMMB() > candidates[j].AvailableRAMMB()
+		}
+		return candidates[i].CPULoadPercent < candidates[j].CPULoadPercent
 	})
 
 	// Return the node with the most available RAM
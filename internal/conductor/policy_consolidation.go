@@ -128,9 +128,10 @@ func (p *ConsolidationPolicy) ShouldConsolidate(ctx ScalingContext) (bool, Conso
 
 	// Check 6: Fleet capacity check (don't consolidate if too full)
 	// PROPORTIONAL OVERHEAD: Check against TotalRAM, not UsableRAM
+	// RESERVED: The headroom of stopped reserved-plan servers counts as used
 	capacityPercent := float64(0)
 	if ctx.FleetStats.TotalRAMMB > 0 {
-		capacityPercent = (float64(ctx.FleetStats.AllocatedRAMMB) / float64(ctx.FleetStats.TotalRAMMB)) * 100
+		capacityPercent = (float64(ctx.FleetStats.AllocatedRAMMB+ctx.ReservedStandbyRAMMB) / float64(ctx.FleetStats.TotalRAMMB)) * 100
 	}
 
 	if capacityPercent > p.MaxCapacityPercent {
@@ -462,6 +463,11 @@ func (p *ConsolidationPolicy) canMigrateServer(server *models.MinecraftServer, p
 		return false
 	}
 
+	// Pinned servers and servers kept apart from their peers stay put: bin packing would stack them
+	if placement := server.Placement(); placement.PinnedNodeID != "" || placement.SpreadsPeers() {
+		return false
+	}
+
 	// Tier-specific rules
 	switch server.RAMTier {
 	case models.TierMicro, models.TierSmall:
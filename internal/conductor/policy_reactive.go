@@ -13,11 +13,13 @@ import (
 // ReactivePolicy scales based on CURRENT capacity utilization (B5)
 // This is the foundation - it reacts to what's happening RIGHT NOW
 type ReactivePolicy struct {
+	Enabled            bool          // Disabled: never scales up or down (set via scaling policy config)
 	ScaleUpThreshold   float64       // Scale up when capacity > 85%
 	ScaleDownThreshold float64       // Scale down when capacity < 30%
 	CooldownPeriod     time.Duration // Wait 5 minutes between actions
 	MinCloudNodes      int           // Never scale below this (0 = can scale to zero)
 	MaxCloudNodes      int           // Never scale above this
+	PreferredServerTypes []string    // Only provision these types if any of them is available (empty = all)
 	lastScaleAction    time.Time
 	lastScaleType      ScaleAction
 
@@ -34,6 +36,7 @@ type ReactivePolicy struct {
 // NewReactivePolicy creates a new reactive scaling policy
 func NewReactivePolicy(cloudProvider cloud.CloudProvider, debugLogBuffer *DebugLogBuffer) *ReactivePolicy {
 	return &ReactivePolicy{
+		Enabled:            true,
 		ScaleUpThreshold:   85.0,              // Scale up at 85% capacity
 		ScaleDownThreshold: 30.0,              // Scale down below 30% capacity
 		CooldownPeriod:     5 * time.Minute,   // 5 minute cooldown
@@ -63,6 +66,10 @@ func (p *ReactivePolicy) ShouldConsolidate(ctx ScalingContext) (bool, Consolidat
 
 // ShouldScaleUp checks if we need more capacity
 func (p *ReactivePolicy) ShouldScaleUp(ctx ScalingContext) (bool, ScaleRecommendation) {
+	if !p.Enabled {
+		return false, ScaleRecommendation{Action: ScaleActionNone, Reason: "Reactive policy disabled"}
+	}
+
 	// CRITICAL: If servers are queued but NO worker nodes exist, provision immediately
 	// This handles the case where MC containers need worker nodes but none are available
 	// FIX: Only provision if ZERO worker nodes (including unhealthy ones being provisioned)
@@ -131,7 +138,8 @@ func (p *ReactivePolicy) ShouldScaleUp(ctx ScalingContext) (bool, ScaleRecommend
 
 	// CRITICAL FIX: Include queued server demand in capacity calculation
 	// This ensures we provision new nodes when queued servers are waiting
-	projectedRAMMB := ctx.FleetStats.AllocatedRAMMB + ctx.QueuedRAMMB
+	// RESERVED: Stopped reserved-plan servers count as running (headroom for their guaranteed start)
+	projectedRAMMB := ctx.FleetStats.AllocatedRAMMB + ctx.QueuedRAMMB + ctx.ReservedStandbyRAMMB
 	capacityPercent := (float64(projectedRAMMB) / float64(ctx.FleetStats.TotalRAMMB)) * 100
 
 	logger.Debug("ReactivePolicy: Capacity check", map[string]interface{}{
@@ -139,6 +147,7 @@ func (p *ReactivePolicy) ShouldScaleUp(ctx ScalingContext) (bool, ScaleRecommend
 		"scale_up_threshold":    p.ScaleUpThreshold,
 		"allocated_ram_mb":      ctx.FleetStats.AllocatedRAMMB,
 		"queued_ram_mb":         ctx.QueuedRAMMB,
+		"reserved_standby_mb":   ctx.ReservedStandbyRAMMB,
 		"projected_ram_mb":      projectedRAMMB,
 		"total_ram_mb":          ctx.FleetStats.TotalRAMMB,
 		"system_reserved_mb":    ctx.FleetStats.SystemReservedRAMMB,
@@ -181,6 +190,10 @@ func (p *ReactivePolicy) ShouldScaleUp(ctx ScalingContext) (bool, ScaleRecommend
 
 // ShouldScaleDown checks if we can remove capacity
 func (p *ReactivePolicy) ShouldScaleDown(ctx ScalingContext) (bool, ScaleRecommendation) {
+	if !p.Enabled {
+		return false, ScaleRecommendation{Action: ScaleActionNone, Reason: "Reactive policy disabled"}
+	}
+
 	// Don't scale down if we have no cloud nodes
 	if len(ctx.CloudNodes) <= p.MinCloudNodes {
 		return false, ScaleRecommendation{Action: ScaleActionNone}
@@ -213,10 +226,12 @@ func (p *ReactivePolicy) ShouldScaleDown(ctx ScalingContext) (bool, ScaleRecomme
 		return false, ScaleRecommendation{Action: ScaleActionNone}
 	}
 
-	capacityPercent := (float64(ctx.FleetStats.AllocatedRAMMB) / float64(ctx.FleetStats.TotalRAMMB)) * 100
+	// RESERVED: Never scale away the headroom of stopped reserved-plan servers
+	capacityPercent := (float64(ctx.FleetStats.AllocatedRAMMB+ctx.ReservedStandbyRAMMB) / float64(ctx.FleetStats.TotalRAMMB)) * 100
 
 	logger.Debug("ReactivePolicy: Scale down check", map[string]interface{}{
 		"capacity_percent":      capacityPercent,
+		"reserved_standby_mb":   ctx.ReservedStandbyRAMMB,
 		"scale_down_threshold":  p.ScaleDownThreshold,
 		"total_ram_mb":          ctx.FleetStats.TotalRAMMB,
 		"allocated_ram_mb":      ctx.FleetStats.AllocatedRAMMB,
@@ -360,6 +375,15 @@ func (p *ReactivePolicy) selectServerType(ctx ScalingContext, capacityPercent fl
 		filtered = serverTypes
 	}
 
+	// Preferred types (scaling policy config) narrow the choice while any of them is available
+	if preferred := p.filterPreferred(filtered); len(preferred) > 0 {
+		filtered = preferred
+	} else if len(p.PreferredServerTypes) > 0 {
+		logger.Warn("No preferred server type available, using all types", map[string]interface{}{
+			"preferred": p.PreferredServerTypes,
+		})
+	}
+
 	// Strategy selection based on config
 	var selectedType string
 	switch cfg.WorkerNodeStrategy {
@@ -397,6 +421,20 @@ func (p *ReactivePolicy) filterByRAMConstraints(serverTypes []*cloud.ServerType,
 	return filtered
 }
 
+// filterPreferred keeps the preferred server types (nil if none are preferred or available)
+func (p *ReactivePolicy) filterPreferred(serverTypes []*cloud.ServerType) []*cloud.ServerType {
+	var preferred []*cloud.ServerType
+	for _, st := range serverTypes {
+		for _, name := range p.PreferredServerTypes {
+			if st.Name == name {
+				preferred = append(preferred, st)
+				break
+			}
+		}
+	}
+	return preferred
+}
+
 // selectByQueue selects node type based on queued servers (multi-tenant packing)
 func (p *ReactivePolicy) selectByQueue(ctx ScalingContext, serverTypes []*cloud.ServerType) string {
 	cfg := config.AppConfig
@@ -0,0 +1,112 @@
+package conductor
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// ReservedServerRepository lists the stopped servers of the reserved plan (guaranteed start)
+// Implemented by repository.ServerRepository; set with SetServerRepo.
+type ReservedServerRepository interface {
+	FindReservedStandby() ([]models.MinecraftServer, error)
+}
+
+// isHeldReservation returns true if the registry entry keeps the RAM of a stopped reserved-plan server on its node
+// A reservation claimed by a start ("reserving" with a node) keeps it until the container is registered.
+func isHeldReservation(container *ContainerInfo) bool {
+	if container.PlanType != models.PlanReserved || container.NodeID == "" {
+		return false
+	}
+	return container.Status == "stopped" || container.Status == "sleeping" || container.Status == "reserving"
+}
+
+// ReservedStandbyRAMMB returns the RAM the fleet keeps free for stopped reserved-plan servers
+// Servers whose stopped container still holds its RAM on a node are part of that node's allocation
+// and not counted again; the others (e.g. after a restart of the control plane) need headroom.
+func (c *Conductor) ReservedStandbyRAMMB() int {
+	repo, ok := c.ServerRepo.(ReservedServerRepository)
+	if !ok {
+		return 0
+	}
+	servers, err := repo.FindReservedStandby()
+	if err != nil {
+		logger.Warn("RESERVED: Failed to list stopped reserved servers", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return 0
+	}
+
+	c.ContainerRegistry.mu.RLock()
+	defer c.ContainerRegistry.mu.RUnlock()
+
+	standbyRAMMB := 0
+	for _, server := range servers {
+		if container, exists := c.ContainerRegistry.containers[server.ID]; exists && isHeldReservation(container) {
+			continue
+		}
+		standbyRAMMB += server.RAMMb
+	}
+	return standbyRAMMB
+}
+
+// ReserveGuaranteedStartSlot takes the start slot of a reserved-plan server
+// Unlike AtomicReserveStartSlot it never waits for other starts: reserved servers don't queue.
+// If the server's stopped container still holds its RAM on a healthy node that fits the placement,
+// the reservation is claimed and its node returned (the RAM there stays allocated to the server).
+// Otherwise a reservation left on another node is released and "" is returned.
+func (c *Conductor) ReserveGuaranteedStartSlot(serverID, serverName string, ramMB int, placement models.NodePlacement) string {
+	c.ContainerRegistry.mu.Lock()
+	defer c.ContainerRegistry.mu.Unlock()
+	c.NodeRegistry.mu.Lock()
+	defer c.NodeRegistry.mu.Unlock()
+
+	if container, exists := c.ContainerRegistry.containers[serverID]; exists && isHeldReservation(container) && container.Status != "reserving" {
+		node, nodeExists := c.NodeRegistry.nodes[container.NodeID]
+		if nodeExists && node.IsHealthy() && node.LifecycleState != NodeStateDraining && container.RAMMb == ramMB {
+			if allowed, _ := node.AllowsPlacement(placement); allowed {
+				container.Status = "reserving"
+				container.LastSeenAt = time.Now()
+				logger.Info("RESERVED: Start claims the server's reserved RAM", map[string]interface{}{
+					"server_id": serverID,
+					"node_id":   node.ID,
+					"ram_mb":    container.RAMMb,
+				})
+				return node.ID
+			}
+		}
+
+		// Reservation unusable (node gone, unhealthy, draining or excluded now): give its RAM back
+		if nodeExists {
+			node.AllocatedRAMMB -= container.RAMMb
+			if node.AllocatedRAMMB < 0 {
+				node.AllocatedRAMMB = 0
+			}
+			if node.ContainerCount > 0 {
+				node.ContainerCount--
+			}
+		}
+		logger.Warn("RESERVED: Reserved RAM of server unusable, placing it anew", map[string]interface{}{
+			"server_id": serverID,
+			"node_id":   container.NodeID,
+		})
+	}
+
+	reservation := &ContainerInfo{
+		ServerID:   serverID,
+		ServerName: serverName,
+		RAMMb:      ramMB,
+		Status:     "reserving",
+		PlanType:   models.PlanReserved,
+		LastSeenAt: time.Now(),
+	}
+	c.ContainerRegistry.containers[serverID] = reservation
+
+	logger.Info("RESERVED: Guaranteed start slot reserved", map[string]interface{}{
+		"server_id":   serverID,
+		"server_name": serverName,
+		"ram_mb":      ramMB,
+	})
+	return ""
+}
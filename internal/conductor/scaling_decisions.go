@@ -0,0 +1,148 @@
+package conductor
+
+import (
+	"time"
+)
+
+// Outcomes of a scaling evaluation
+const (
+	ScalingOutcomeNone     = "none"     // No policy asked for an action
+	ScalingOutcomeExecuted = "executed" // The chosen action ran
+	ScalingOutcomeFailed   = "failed"   // The chosen action ran and failed
+)
+
+// ScalingDecisionSink receives the record of every evaluation of the scaling engine
+// Implemented by the scaling decision history (service.ScalingDecisionService).
+type ScalingDecisionSink interface {
+	RecordScalingDecision(decision ScalingDecision)
+}
+
+// ScalingDecision records one evaluation of the scaling engine: what it saw, what every policy
+// it asked answered, which action it chose and how that went
+type ScalingDecision struct {
+	EvaluatedAt time.Time              `json:"evaluated_at"`
+	Action      ScaleAction            `json:"action"`
+	Policy      string                 `json:"policy,omitempty"` // Policy whose recommendation was chosen
+	Reason      string                 `json:"reason,omitempty"`
+	ServerType  string                 `json:"server_type,omitempty"`
+	Count       int                    `json:"count,omitempty"`
+	Urgency     Urgency                `json:"urgency,omitempty"`
+	Outcome     string                 `json:"outcome"`
+	Error       string                 `json:"error,omitempty"`
+	NodeIDs     []string               `json:"node_ids,omitempty"` // Nodes provisioned or removed by the action
+	Migrations  []Migration            `json:"migrations,omitempty"`
+	Context     ScalingContextSnapshot `json:"context"`
+	Verdicts    []ScalingPolicyVerdict `json:"verdicts"`
+}
+
+// ScalingPolicyVerdict is the answer of a policy to one question of an evaluation
+// The engine stops asking at the first approval, so later questions have no verdicts then.
+type ScalingPolicyVerdict struct {
+	Policy   string      `json:"policy"`
+	Question ScaleAction `json:"question"`
+	Approved bool        `json:"approved"`
+	Reason   string      `json:"reason,omitempty"`
+}
+
+// ScalingContextSnapshot is the part of the scaling context a decision was based on
+type ScalingContextSnapshot struct {
+	CapacityPercent      float64        `json:"capacity_percent"`
+	Fleet                FleetStats     `json:"fleet"`
+	QueuedServerCount    int            `json:"queued_server_count"`
+	QueuedRAMMB          int            `json:"queued_ram_mb"`
+	ReservedStandbyRAMMB int            `json:"reserved_standby_ram_mb"`
+	Nodes                []NodeSnapshot `json:"nodes"`
+}
+
+// NodeSnapshot is the state of a node at a scaling evaluation
+type NodeSnapshot struct {
+	ID             string             `json:"id"`
+	Hostname       string             `json:"hostname"`
+	Type           string             `json:"type"`
+	LifecycleState NodeLifecycleState `json:"lifecycle_state"`
+	HealthStatus   HealthStatus       `json:"health_status"`
+	TotalRAMMB     int                `json:"total_ram_mb"`
+	AllocatedRAMMB int                `json:"allocated_ram_mb"`
+	ContainerCount int                `json:"container_count"`
+	HourlyCostEUR  float64            `json:"hourly_cost_eur"`
+}
+
+// SetDecisionSink sets the receiver of the scaling decision records (optional)
+func (e *ScalingEngine) SetDecisionSink(sink ScalingDecisionSink) {
+	e.decisionSink = sink
+}
+
+// newScalingDecision starts the record of an evaluation with a snapshot of its context
+func newScalingDecision(ctx ScalingContext) *ScalingDecision {
+	snapshot := ScalingContextSnapshot{
+		Fleet:                ctx.FleetStats,
+		QueuedServerCount:    ctx.QueuedServerCount,
+		QueuedRAMMB:          ctx.QueuedRAMMB,
+		ReservedStandbyRAMMB: ctx.ReservedStandbyRAMMB,
+		Nodes:                make([]NodeSnapshot, 0, len(ctx.DedicatedNodes)+len(ctx.CloudNodes)),
+	}
+	if ctx.FleetStats.TotalRAMMB > 0 {
+		snapshot.CapacityPercent = float64(ctx.FleetStats.AllocatedRAMMB) / float64(ctx.FleetStats.TotalRAMMB) * 100
+	}
+	for _, nodes := range [][]*Node{ctx.DedicatedNodes, ctx.CloudNodes} {
+		for _, node := range nodes {
+			snapshot.Nodes = append(snapshot.Nodes, NodeSnapshot{
+				ID:             node.ID,
+				Hostname:       node.Hostname,
+				Type:           node.Type,
+				LifecycleState: node.LifecycleState,
+				HealthStatus:   node.HealthStatus,
+				TotalRAMMB:     node.TotalRAMMB,
+				AllocatedRAMMB: node.AllocatedRAMMB,
+				ContainerCount: node.ContainerCount,
+				HourlyCostEUR:  node.HourlyCostEUR,
+			})
+		}
+	}
+
+	return &ScalingDecision{
+		EvaluatedAt: ctx.CurrentTime,
+		Action:      ScaleActionNone,
+		Outcome:     ScalingOutcomeNone,
+		Context:     snapshot,
+		Verdicts:    []ScalingPolicyVerdict{},
+	}
+}
+
+// verdict adds the answer of a policy to the record
+func (d *ScalingDecision) verdict(policy string, question ScaleAction, approved bool, reason string) {
+	d.Verdicts = append(d.Verdicts, ScalingPolicyVerdict{
+		Policy:   policy,
+		Question: question,
+		Approved: approved,
+		Reason:   reason,
+	})
+}
+
+// choose records the recommendation the engine executes
+func (d *ScalingDecision) choose(policy string, rec ScaleRecommendation) {
+	d.Action = rec.Action
+	d.Policy = policy
+	d.Reason = rec.Reason
+	d.ServerType = rec.ServerType
+	d.Count = rec.Count
+	d.Urgency = rec.Urgency
+}
+
+// finish records the outcome of the chosen action
+func (d *ScalingDecision) finish(nodeIDs []string, err error) {
+	d.NodeIDs = nodeIDs
+	d.Outcome = ScalingOutcomeExecuted
+	if err != nil {
+		d.Outcome = ScalingOutcomeFailed
+		d.Error = err.Error()
+	}
+}
+
+// recordDecision hands the record of an evaluation to the sink
+func (e *ScalingEngine) recordDecision(decision *ScalingDecision) {
+	if e.decisionSink == nil {
+		return
+	}
+	e.decisionSink.RecordScalingDecision(*decision)
+}
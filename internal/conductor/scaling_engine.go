@@ -3,6 +3,7 @@ package conductor
 import (
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/payperplay/hosting/internal/cloud"
@@ -21,9 +22,15 @@ type ScalingEngine struct {
 	conductor      *Conductor  // Back-reference for migrations (B8)
 	velocityClient interface{} // For Velocity-aware migrations (can be nil or *velocity.RemoteVelocityClient)
 	debugLogBuffer *DebugLogBuffer
+	decisionSink   ScalingDecisionSink // Decision history (optional)
 	enabled        bool
 	checkInterval  time.Duration
 	stopChan       chan struct{}
+
+	// Settings from the scaling policy config, taken over by the evaluation loop (see ApplySettings)
+	settingsMu      sync.Mutex
+	pendingSettings *ScalingSettings
+	settingsChanged chan struct{}
 }
 
 // NewScalingEngine creates a new scaling engine
@@ -48,6 +55,7 @@ func NewScalingEngine(
 		enabled:        enabled,
 		checkInterval:  2 * time.Minute, // Check every 2 minutes
 		stopChan:       make(chan struct{}),
+		settingsChanged: make(chan struct{}, 1),
 	}
 
 	// Register default policies
@@ -146,6 +154,7 @@ func (e *ScalingEngine) TriggerImmediateCheck() {
 
 // runLoop is the main evaluation loop
 func (e *ScalingEngine) runLoop() {
+	e.applyPendingSettings() // Settings saved before the start
 	ticker := time.NewTicker(e.checkInterval)
 	defer ticker.Stop()
 
@@ -157,6 +166,10 @@ func (e *ScalingEngine) runLoop() {
 		select {
 		case <-ticker.C:
 			e.evaluateScaling()
+		case <-e.settingsChanged:
+			if e.applyPendingSettings() {
+				ticker.Reset(e.checkInterval)
+			}
 		case <-e.stopChan:
 			logger.Info("ScalingEngine loop stopped", nil)
 			return
@@ -187,9 +200,16 @@ func (e *ScalingEngine) evaluateScaling() {
 		"cloud_nodes":     len(ctx.CloudNodes),
 	})
 
+	// Every evaluation is recorded, including the ones without an action
+	decision := newScalingDecision(ctx)
+	defer e.recordDecision(decision)
+
 	// Ask all policies (in priority order) if we should scale UP
 	for _, policy := range e.policies {
-		if shouldScale, recommendation := policy.ShouldScaleUp(ctx); shouldScale {
+		shouldScale, recommendation := policy.ShouldScaleUp(ctx)
+		decision.verdict(policy.Name(), ScaleActionScaleUp, shouldScale, recommendation.Reason)
+		if shouldScale {
+			decision.choose(policy.Name(), recommendation)
 			fields := map[string]interface{}{
 				"policy":      policy.Name(),
 				"action":      recommendation.Action,
@@ -212,7 +232,10 @@ func (e *ScalingEngine) evaluateScaling() {
 			}
 			events.PublishScalingDecision(policy.Name(), string(recommendation.Action), recommendation.ServerType, recommendation.Reason, string(recommendation.Urgency), recommendation.Count, capacityPercent, nil)
 
-			if err := e.executeScaling(recommendation); err != nil {
+			recommendation.Interruptible = e.interruptibleScaleUp(ctx)
+			nodeIDs, err := e.executeScaling(recommendation)
+			decision.finish(nodeIDs, err)
+			if err != nil {
 				logger.Error("Failed to execute scaling", err, map[string]interface{}{
 					"policy":     policy.Name(),
 					"action":     recommendation.Action,
@@ -226,7 +249,10 @@ func (e *ScalingEngine) evaluateScaling() {
 
 	// Ask all policies if we should scale DOWN
 	for _, policy := range e.policies {
-		if shouldScale, recommendation := policy.ShouldScaleDown(ctx); shouldScale {
+		shouldScale, recommendation := policy.ShouldScaleDown(ctx)
+		decision.verdict(policy.Name(), ScaleActionScaleDown, shouldScale, recommendation.Reason)
+		if shouldScale {
+			decision.choose(policy.Name(), recommendation)
 			fields := map[string]interface{}{
 				"policy": policy.Name(),
 				"action": recommendation.Action,
@@ -247,7 +273,9 @@ func (e *ScalingEngine) evaluateScaling() {
 			}
 			events.PublishScalingDecision(policy.Name(), string(recommendation.Action), recommendation.ServerType, recommendation.Reason, string(recommendation.Urgency), recommendation.Count, capacityPercent, nil)
 
-			if err := e.executeScaling(recommendation); err != nil {
+			nodeIDs, err := e.executeScaling(recommendation)
+			decision.finish(nodeIDs, err)
+			if err != nil {
 				logger.Error("Failed to execute scaling", err, map[string]interface{}{
 					"policy": policy.Name(),
 					"action": recommendation.Action,
@@ -260,7 +288,11 @@ func (e *ScalingEngine) evaluateScaling() {
 
 	// Ask all policies if we should CONSOLIDATE (B8 - lowest priority, only if no other action)
 	for _, policy := range e.policies {
-		if shouldConsolidate, plan := policy.ShouldConsolidate(ctx); shouldConsolidate {
+		shouldConsolidate, plan := policy.ShouldConsolidate(ctx)
+		decision.verdict(policy.Name(), ScaleActionConsolidate, shouldConsolidate, plan.Reason)
+		if shouldConsolidate {
+			decision.choose(policy.Name(), ScaleRecommendation{Action: ScaleActionConsolidate, Reason: plan.Reason})
+			decision.Migrations = plan.Migrations
 			logger.Info("CONSOLIDATION decision", map[string]interface{}{
 				"policy":                 policy.Name(),
 				"migrations":             len(plan.Migrations),
@@ -275,9 +307,12 @@ func (e *ScalingEngine) evaluateScaling() {
 			events.PublishConsolidationStarted(len(plan.Migrations), len(ctx.CloudNodes), len(plan.NodesToKeep), plan.NodeSavings, plan.EstimatedCostSavings, plan.Reason, plan.NodesToRemove)
 
 			if err := e.executeConsolidation(plan); err != nil {
+				decision.finish(nil, err) // Failed migrations abort the decommissioning
 				logger.Error("Failed to execute consolidation", err, map[string]interface{}{
 					"policy": policy.Name(),
 				})
+			} else {
+				decision.finish(plan.NodesToRemove, nil)
 			}
 
 			return // Only execute ONE action per cycle
@@ -294,6 +329,11 @@ func (e *ScalingEngine) buildScalingContext() ScalingContext {
 
 	var dedicatedNodes, cloudNodes, workerNodes []*Node
 	for _, node := range nodes {
+		// Customer-dedicated nodes are outside of scaling: not shared capacity, never scaled down or consolidated
+		if node.IsCustomerDedicated() {
+			continue
+		}
+
 		// CRITICAL FIX: Only count NON-SYSTEM dedicated nodes for capacity planning
 		// System nodes (local-node, proxy-node) don't host Minecraft containers
 		if node.Type == "dedicated" && !node.IsSystemNode {
@@ -324,6 +364,12 @@ func (e *ScalingEngine) buildScalingContext() ScalingContext {
 		queuedRAMMB = e.startQueue.GetTotalRequiredRAM()
 	}
 
+	// Headroom for stopped reserved-plan servers: their start must never queue
+	reservedStandbyRAMMB := 0
+	if e.conductor != nil {
+		reservedStandbyRAMMB = e.conductor.ReservedStandbyRAMMB()
+	}
+
 	return ScalingContext{
 		FleetStats:        stats,
 		DedicatedNodes:    dedicatedNodes,
@@ -331,6 +377,7 @@ func (e *ScalingEngine) buildScalingContext() ScalingContext {
 		WorkerNodes:       workerNodes,
 		QueuedServerCount: queueSize,
 		QueuedRAMMB:       queuedRAMMB,
+		ReservedStandbyRAMMB: reservedStandbyRAMMB,
 		ContainerRegistry: containerRegistry,
 		CurrentTime:       now,
 		IsWeekend:         now.Weekday() == time.Saturday || now.Weekday() == time.Sunday,
@@ -348,7 +395,8 @@ func (e *ScalingEngine) buildScalingContext() ScalingContext {
 }
 
 // executeScaling performs the actual scaling operation
-func (e *ScalingEngine) executeScaling(rec ScaleRecommendation) error {
+// Returns the nodes it provisioned or removed (also on failure, the ones done so far).
+func (e *ScalingEngine) executeScaling(rec ScaleRecommendation) ([]string, error) {
 	switch rec.Action {
 	case ScaleActionScaleUp:
 		return e.scaleUp(rec)
@@ -360,22 +408,24 @@ func (e *ScalingEngine) executeScaling(rec ScaleRecommendation) error {
 		return e.provisionSpare(rec)
 
 	default:
-		return nil
+		return nil, nil
 	}
 }
 
 // scaleUp provisions new cloud nodes
-func (e *ScalingEngine) scaleUp(rec ScaleRecommendation) error {
+func (e *ScalingEngine) scaleUp(rec ScaleRecommendation) ([]string, error) {
 	logger.Info("Scaling UP", map[string]interface{}{
 		"server_type": rec.ServerType,
 		"count":       rec.Count,
 		"reason":      rec.Reason,
 		"urgency":     rec.Urgency,
+		"interruptible": rec.Interruptible,
 	})
 
+	var provisioned []string
 	for i := 0; i < rec.Count; i++ {
 		// Provision new VM
-		node, err := e.vmProvisioner.ProvisionNode(rec.ServerType)
+		node, err := e.vmProvisioner.ProvisionNode(rec.ServerType, rec.Interruptible)
 		if err != nil {
 			logger.Error("Failed to provision node", err, map[string]interface{}{
 				"server_type": rec.ServerType,
@@ -385,8 +435,9 @@ func (e *ScalingEngine) scaleUp(rec ScaleRecommendation) error {
 			// Publish scaling event (failed)
 			events.PublishScalingEvent("scale_up", "failed", err.Error())
 
-			return fmt.Errorf("failed to provision node: %w", err)
+			return provisioned, fmt.Errorf("failed to provision node: %w", err)
 		}
+		provisioned = append(provisioned, node.ID)
 
 		logger.Info("Node provisioned successfully", map[string]interface{}{
 			"node_id":     node.ID,
@@ -404,22 +455,27 @@ func (e *ScalingEngine) scaleUp(rec ScaleRecommendation) error {
 	// This will attempt to start any queued servers now that we have capacity
 	e.processStartQueueAfterScaleUp()
 
-	return nil
+	return provisioned, nil
 }
 
 // scaleDown removes idle cloud nodes
-func (e *ScalingEngine) scaleDown(rec ScaleRecommendation) error {
+func (e *ScalingEngine) scaleDown(rec ScaleRecommendation) ([]string, error) {
 	logger.Info("Scaling DOWN", map[string]interface{}{
 		"count":  rec.Count,
 		"reason": rec.Reason,
 	})
 
-	// Get all cloud nodes
-	cloudNodes := e.nodeRegistry.GetNodesByType("cloud")
+	// Get all cloud nodes (except those dedicated to a customer)
+	cloudNodes := make([]*Node, 0)
+	for _, node := range e.nodeRegistry.GetNodesByType("cloud") {
+		if !node.IsCustomerDedicated() {
+			cloudNodes = append(cloudNodes, node)
+		}
+	}
 
 	if len(cloudNodes) == 0 {
 		logger.Warn("No cloud nodes to scale down", nil)
-		return nil
+		return nil, nil
 	}
 
 	// Find the least utilized node
@@ -427,7 +483,7 @@ func (e *ScalingEngine) scaleDown(rec ScaleRecommendation) error {
 
 	if nodeToRemove == nil {
 		logger.Warn("No suitable node found for scale down", nil)
-		return nil
+		return nil, nil
 	}
 
 	// Check if node has containers
@@ -437,7 +493,7 @@ func (e *ScalingEngine) scaleDown(rec ScaleRecommendation) error {
 			"container_count": nodeToRemove.ContainerCount,
 		})
 		// TODO: Implement container draining/migration
-		return fmt.Errorf("node has active containers: %s", nodeToRemove.ID)
+		return nil, fmt.Errorf("node has active containers: %s", nodeToRemove.ID)
 	}
 
 	// Decommission the node
@@ -447,7 +503,7 @@ func (e *ScalingEngine) scaleDown(rec ScaleRecommendation) error {
 		})
 
 		events.PublishScalingEvent("scale_down", "failed", err.Error())
-		return fmt.Errorf("failed to decommission node: %w", err)
+		return nil, fmt.Errorf("failed to decommission node: %w", err)
 	}
 
 	logger.Info("Node scaled down successfully", map[string]interface{}{
@@ -456,11 +512,11 @@ func (e *ScalingEngine) scaleDown(rec ScaleRecommendation) error {
 
 	events.PublishScalingEvent("scale_down", "success", nodeToRemove.ID)
 
-	return nil
+	return []string{nodeToRemove.ID}, nil
 }
 
 // provisionSpare provisions a spare node for hot-spare pool (B6)
-func (e *ScalingEngine) provisionSpare(rec ScaleRecommendation) error {
+func (e *ScalingEngine) provisionSpare(rec ScaleRecommendation) ([]string, error) {
 	logger.Info("Provisioning spare node", map[string]interface{}{
 		"server_type": rec.ServerType,
 		"reason":      rec.Reason,
@@ -470,7 +526,7 @@ func (e *ScalingEngine) provisionSpare(rec ScaleRecommendation) error {
 	node, err := e.vmProvisioner.ProvisionSpareNode()
 	if err != nil {
 		logger.Error("Failed to provision spare node", err, nil)
-		return fmt.Errorf("failed to provision spare: %w", err)
+		return nil, fmt.Errorf("failed to provision spare: %w", err)
 	}
 
 	logger.Info("Spare node provisioned", map[string]interface{}{
@@ -479,7 +535,7 @@ func (e *ScalingEngine) provisionSpare(rec ScaleRecommendation) error {
 
 	events.PublishScalingEvent("provision_spare", "success", node.ID)
 
-	return nil
+	return []string{node.ID}, nil
 }
 
 // findLeastUtilizedNode finds the cloud node with lowest utilization
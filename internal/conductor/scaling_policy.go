@@ -36,6 +36,9 @@ type ScalingContext struct {
 	QueuedServerCount int // Number of servers waiting for capacity
 	QueuedRAMMB       int // Total RAM demand from queued servers
 
+	// Headroom kept free for stopped reserved-plan servers (guaranteed start)
+	ReservedStandbyRAMMB int
+
 	// Container Registry (for B8 - Consolidation Policy)
 	ContainerRegistry *ContainerRegistry
 
@@ -61,6 +64,8 @@ type ScaleRecommendation struct {
 	Count      int     // How many VMs
 	Reason     string  // Human-readable reason for logging
 	Urgency    Urgency // How fast to act
+
+	Interruptible bool // Provision spot instances (decided by the engine, see interruptibleScaleUp)
 }
 
 // ScaleAction defines the type of scaling operation
@@ -0,0 +1,117 @@
+package conductor
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// ScalingSettings are the tunable settings of the scaling engine and its policies
+// Set by the scaling policy config (service.ScalingPolicyService); DefaultScalingSettings are the built-in values.
+type ScalingSettings struct {
+	CheckInterval time.Duration
+	Reactive      ReactiveSettings
+	Consolidation ConsolidationSettings
+}
+
+// ReactiveSettings are the settings of the ReactivePolicy
+type ReactiveSettings struct {
+	Enabled              bool
+	ScaleUpThreshold     float64
+	ScaleDownThreshold   float64
+	Cooldown             time.Duration
+	MinCloudNodes        int
+	MaxCloudNodes        int
+	PreferredServerTypes []string
+}
+
+// ConsolidationSettings are the settings of the ConsolidationPolicy
+type ConsolidationSettings struct {
+	Enabled                   bool
+	Cooldown                  time.Duration
+	MaxCapacityPercent        float64
+	MinNodeSavings            int
+	AllowMigrationWithPlayers bool
+}
+
+// DefaultScalingSettings returns the settings of a new scaling engine and its policies
+func DefaultScalingSettings() ScalingSettings {
+	reactive := NewReactivePolicy(nil, nil)
+	consolidation := NewConsolidationPolicy(nil)
+	return ScalingSettings{
+		CheckInterval: 2 * time.Minute,
+		Reactive: ReactiveSettings{
+			Enabled:            reactive.Enabled,
+			ScaleUpThreshold:   reactive.ScaleUpThreshold,
+			ScaleDownThreshold: reactive.ScaleDownThreshold,
+			Cooldown:           reactive.CooldownPeriod,
+			MinCloudNodes:      reactive.MinCloudNodes,
+			MaxCloudNodes:      reactive.MaxCloudNodes,
+		},
+		Consolidation: ConsolidationSettings{
+			Enabled:                   consolidation.Enabled,
+			Cooldown:                  consolidation.CooldownPeriod,
+			MaxCapacityPercent:        consolidation.MaxCapacityPercent,
+			MinNodeSavings:            consolidation.ThresholdNodeSavings,
+			AllowMigrationWithPlayers: consolidation.AllowMigrationWithPlayers,
+		},
+	}
+}
+
+// ApplySettings hands new settings to the engine
+// The evaluation loop takes them over between two evaluations (never during one) and restarts
+// its interval; before the engine starts they are applied when it does.
+func (e *ScalingEngine) ApplySettings(settings ScalingSettings) {
+	e.settingsMu.Lock()
+	e.pendingSettings = &settings
+	e.settingsMu.Unlock()
+
+	select {
+	case e.settingsChanged <- struct{}{}:
+	default: // Already signalled, the loop picks up the latest settings
+	}
+}
+
+// applyPendingSettings takes over the settings handed to ApplySettings, if any
+// Returns whether settings were applied. Called by the evaluation loop only.
+func (e *ScalingEngine) applyPendingSettings() bool {
+	e.settingsMu.Lock()
+	settings := e.pendingSettings
+	e.pendingSettings = nil
+	e.settingsMu.Unlock()
+	if settings == nil {
+		return false
+	}
+
+	if settings.CheckInterval > 0 {
+		e.checkInterval = settings.CheckInterval
+	}
+	for _, policy := range e.policies {
+		switch p := policy.(type) {
+		case *ReactivePolicy:
+			p.Enabled = settings.Reactive.Enabled
+			p.ScaleUpThreshold = settings.Reactive.ScaleUpThreshold
+			p.ScaleDownThreshold = settings.Reactive.ScaleDownThreshold
+			p.CooldownPeriod = settings.Reactive.Cooldown
+			p.MinCloudNodes = settings.Reactive.MinCloudNodes
+			p.MaxCloudNodes = settings.Reactive.MaxCloudNodes
+			p.PreferredServerTypes = settings.Reactive.PreferredServerTypes
+		case *ConsolidationPolicy:
+			p.Enabled = settings.Consolidation.Enabled
+			p.CooldownPeriod = settings.Consolidation.Cooldown
+			p.MaxCapacityPercent = settings.Consolidation.MaxCapacityPercent
+			p.ThresholdNodeSavings = settings.Consolidation.MinNodeSavings
+			p.AllowMigrationWithPlayers = settings.Consolidation.AllowMigrationWithPlayers
+		}
+	}
+
+	logger.Info("ScalingEngine settings applied", map[string]interface{}{
+		"check_interval":        e.checkInterval.String(),
+		"reactive_enabled":      settings.Reactive.Enabled,
+		"scale_up_threshold":    settings.Reactive.ScaleUpThreshold,
+		"scale_down_threshold":  settings.Reactive.ScaleDownThreshold,
+		"max_cloud_nodes":       settings.Reactive.MaxCloudNodes,
+		"consolidation_enabled": settings.Consolidation.Enabled,
+	})
+	return true
+}
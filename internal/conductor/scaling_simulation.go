@@ -0,0 +1,261 @@
+package conductor
+
+import (
+	"time"
+)
+
+// hoursPerMonth converts hourly node costs into monthly ones
+const hoursPerMonth = 730
+
+// ScalingSimulationOptions override policy thresholds for a simulation (nil = the policy's own value)
+type ScalingSimulationOptions struct {
+	ScaleUpThreshold             *float64 // ReactivePolicy: scale up above this capacity percent
+	ScaleDownThreshold           *float64 // ReactivePolicy: scale down below this capacity percent
+	ConsolidationMaxCapacity     *float64 // ConsolidationPolicy: don't consolidate above this capacity percent
+	ConsolidationThresholdSaving *int     // ConsolidationPolicy: minimum number of nodes to save
+}
+
+// ScalingSimulation is what the scaling engine would do right now, computed without executing anything
+type ScalingSimulation struct {
+	SimulatedAt          time.Time           `json:"simulated_at"`
+	EngineEnabled        bool                `json:"engine_enabled"`
+	CapacityPercent      float64             `json:"capacity_percent"`
+	AllocatedRAMMB       int                 `json:"allocated_ram_mb"`
+	TotalRAMMB           int                 `json:"total_ram_mb"`
+	QueuedRAMMB          int                 `json:"queued_ram_mb"`
+	ReservedStandbyRAMMB int                 `json:"reserved_standby_ram_mb"`
+	CloudNodes           int                 `json:"cloud_nodes"`
+	Action               ScaleAction         `json:"action"` // The decision the engine would execute (one per cycle)
+	Decisions            []SimulatedDecision `json:"decisions"`
+}
+
+// SimulatedDecision is the answer of one policy to one scaling question
+// Cooldowns are ignored (CooldownRemaining tells how long the real policy would still wait).
+type SimulatedDecision struct {
+	Policy             string               `json:"policy"`
+	Action             ScaleAction          `json:"action"`
+	Approved           bool                 `json:"approved"`
+	Selected           bool                 `json:"selected"` // Executed by the engine if this were a real cycle
+	Reason             string               `json:"reason,omitempty"`
+	ServerType         string               `json:"server_type,omitempty"`
+	Urgency            Urgency              `json:"urgency,omitempty"`
+	CooldownRemaining  string               `json:"cooldown_remaining,omitempty"`
+	Migrations         []SimulatedMigration `json:"migrations,omitempty"`
+	NodesRemoved       []SimulatedNode      `json:"nodes_removed,omitempty"`
+	Blocked            string               `json:"blocked,omitempty"` // Why the engine would fail to execute it
+	SavingsEURPerHour  float64              `json:"savings_eur_per_hour,omitempty"`
+	SavingsEURPerMonth float64              `json:"savings_eur_per_month,omitempty"`
+}
+
+// SimulatedMigration is a server move of a simulated consolidation
+type SimulatedMigration struct {
+	ServerID    string `json:"server_id"`
+	ServerName  string `json:"server_name"`
+	FromNode    string `json:"from_node"`
+	ToNode      string `json:"to_node"`
+	RAMMb       int    `json:"ram_mb"`
+	PlayerCount int    `json:"player_count"`
+}
+
+// SimulatedNode is a node a simulated decision would decommission
+type SimulatedNode struct {
+	NodeID        string  `json:"node_id"`
+	Hostname      string  `json:"hostname"`
+	Containers    int     `json:"containers"`
+	HourlyCostEUR float64 `json:"hourly_cost_eur"`
+}
+
+// simulatedPolicy is implemented by policies that can decide on a copy of themselves
+// The copy has the thresholds of the policy (or the overrides) but no cooldown and no dashboard logging.
+type simulatedPolicy interface {
+	simulationCopy(opts ScalingSimulationOptions) ScalingPolicy
+	cooldownRemaining(now time.Time) time.Duration
+}
+
+// simulationCopy returns a cooldown-free copy of the reactive policy
+func (p *ReactivePolicy) simulationCopy(opts ScalingSimulationOptions) ScalingPolicy {
+	p.cacheMutex.RLock()
+	serverTypes, cacheExpiry := p.serverTypeCache, p.cacheExpiry
+	p.cacheMutex.RUnlock()
+
+	copy := &ReactivePolicy{
+		Enabled:              p.Enabled,
+		ScaleUpThreshold:     p.ScaleUpThreshold,
+		ScaleDownThreshold:   p.ScaleDownThreshold,
+		CooldownPeriod:       p.CooldownPeriod,
+		MinCloudNodes:        p.MinCloudNodes,
+		MaxCloudNodes:        p.MaxCloudNodes,
+		PreferredServerTypes: p.PreferredServerTypes,
+		lastScaleType:        ScaleActionNone,
+		cloudProvider:        p.cloudProvider,
+		serverTypeCache:      serverTypes,
+		cacheExpiry:          cacheExpiry,
+	}
+	if opts.ScaleUpThreshold != nil {
+		copy.ScaleUpThreshold = *opts.ScaleUpThreshold
+	}
+	if opts.ScaleDownThreshold != nil {
+		copy.ScaleDownThreshold = *opts.ScaleDownThreshold
+	}
+	return copy
+}
+
+// cooldownRemaining returns how long the reactive policy still waits before its next action
+func (p *ReactivePolicy) cooldownRemaining(now time.Time) time.Duration {
+	return remaining(p.lastScaleAction.Add(p.CooldownPeriod), now)
+}
+
+// simulationCopy returns an enabled, cooldown-free copy of the consolidation policy
+func (p *ConsolidationPolicy) simulationCopy(opts ScalingSimulationOptions) ScalingPolicy {
+	copy := &ConsolidationPolicy{
+		Enabled:                   true,
+		CooldownPeriod:            p.CooldownPeriod,
+		ThresholdNodeSavings:      p.ThresholdNodeSavings,
+		MaxCapacityPercent:        p.MaxCapacityPercent,
+		AllowMigrationWithPlayers: p.AllowMigrationWithPlayers,
+		velocityClient:            p.velocityClient,
+	}
+	if opts.ConsolidationMaxCapacity != nil {
+		copy.MaxCapacityPercent = *opts.ConsolidationMaxCapacity
+	}
+	if opts.ConsolidationThresholdSaving != nil {
+		copy.ThresholdNodeSavings = *opts.ConsolidationThresholdSaving
+	}
+	return copy
+}
+
+// cooldownRemaining returns how long the consolidation policy still waits before its next consolidation
+func (p *ConsolidationPolicy) cooldownRemaining(now time.Time) time.Duration {
+	return remaining(p.lastConsolidation.Add(p.CooldownPeriod), now)
+}
+
+// Simulate asks every policy what it would do with the current fleet, without executing anything
+// The policies decide on copies of themselves, so their cooldowns and the dashboard console stay
+// untouched. The decision the engine would execute follows the engine's order: scale up, scale
+// down, consolidate, one action per cycle.
+func (e *ScalingEngine) Simulate(opts ScalingSimulationOptions) ScalingSimulation {
+	ctx := e.buildScalingContext()
+	now := time.Now()
+
+	simulation := ScalingSimulation{
+		SimulatedAt:          now,
+		EngineEnabled:        e.enabled,
+		AllocatedRAMMB:       ctx.FleetStats.AllocatedRAMMB,
+		TotalRAMMB:           ctx.FleetStats.TotalRAMMB,
+		QueuedRAMMB:          ctx.QueuedRAMMB,
+		ReservedStandbyRAMMB: ctx.ReservedStandbyRAMMB,
+		CloudNodes:           len(ctx.CloudNodes),
+		Action:               ScaleActionNone,
+		Decisions:            []SimulatedDecision{},
+	}
+	if ctx.FleetStats.TotalRAMMB > 0 {
+		simulation.CapacityPercent = float64(ctx.FleetStats.AllocatedRAMMB) / float64(ctx.FleetStats.TotalRAMMB) * 100
+	}
+
+	questions := []ScaleAction{ScaleActionScaleUp, ScaleActionScaleDown, ScaleActionConsolidate}
+	for _, question := range questions {
+		for _, policy := range e.policies {
+			simulated, ok := policy.(simulatedPolicy)
+			if !ok {
+				continue
+			}
+
+			decision := e.simulateDecision(simulated.simulationCopy(opts), question, ctx)
+			if cooldown := simulated.cooldownRemaining(now); cooldown > 0 {
+				decision.CooldownRemaining = cooldown.Round(time.Second).String()
+			}
+			if consolidation, ok := policy.(*ConsolidationPolicy); ok && question == ScaleActionConsolidate && !consolidation.Enabled {
+				// Simulated anyway, so the plan can be judged before enabling consolidation
+				decision.Blocked = "consolidation is disabled"
+				decision.Approved = false
+			}
+			if decision.Approved && simulation.Action == ScaleActionNone {
+				decision.Selected = true
+				simulation.Action = decision.Action
+			}
+			simulation.Decisions = append(simulation.Decisions, decision)
+		}
+	}
+	return simulation
+}
+
+// simulateDecision asks a (copied) policy one scaling question
+func (e *ScalingEngine) simulateDecision(policy ScalingPolicy, question ScaleAction, ctx ScalingContext) SimulatedDecision {
+	decision := SimulatedDecision{Policy: policy.Name(), Action: question}
+
+	switch question {
+	case ScaleActionScaleUp:
+		approved, rec := policy.ShouldScaleUp(ctx)
+		decision.Approved, decision.Reason = approved, rec.Reason
+		decision.ServerType, decision.Urgency = rec.ServerType, rec.Urgency
+
+	case ScaleActionScaleDown:
+		approved, rec := policy.ShouldScaleDown(ctx)
+		decision.Approved, decision.Reason = approved, rec.Reason
+		if !approved {
+			break
+		}
+		// The engine removes the least utilized cloud node, and only if it is empty
+		if node := e.findLeastUtilizedNode(scaleDownCandidates(ctx.CloudNodes)); node != nil {
+			decision.NodesRemoved = []SimulatedNode{simulatedNode(node)}
+			decision.SavingsEURPerHour = node.HourlyCostEUR
+			if node.ContainerCount > 0 {
+				decision.Blocked = "least utilized node still runs containers"
+			}
+		}
+
+	case ScaleActionConsolidate:
+		consolidation, ok := policy.(*ConsolidationPolicy)
+		if !ok {
+			approved, plan := policy.ShouldConsolidate(ctx)
+			decision.Approved, decision.Reason = approved, plan.Reason
+			break
+		}
+		approved, plan := consolidation.ShouldConsolidate(ctx)
+		if !approved {
+			// Show the layout the policy rejected, so thresholds can be judged against it
+			plan = consolidation.calculateOptimalLayout(ctx)
+		}
+		decision.Approved, decision.Reason = approved, plan.Reason
+		decision.SavingsEURPerHour = plan.EstimatedCostSavings
+		for _, migration := range plan.Migrations {
+			decision.Migrations = append(decision.Migrations, SimulatedMigration(migration))
+		}
+		for _, nodeID := range plan.NodesToRemove {
+			if node, exists := e.nodeRegistry.GetNode(nodeID); exists {
+				decision.NodesRemoved = append(decision.NodesRemoved, simulatedNode(node))
+			}
+		}
+	}
+
+	decision.SavingsEURPerMonth = decision.SavingsEURPerHour * hoursPerMonth
+	return decision
+}
+
+// scaleDownCandidates returns the cloud nodes a scale-down may remove (not dedicated to a customer)
+func scaleDownCandidates(nodes []*Node) []*Node {
+	candidates := make([]*Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !node.IsCustomerDedicated() {
+			candidates = append(candidates, node)
+		}
+	}
+	return candidates
+}
+
+func simulatedNode(node *Node) SimulatedNode {
+	return SimulatedNode{
+		NodeID:        node.ID,
+		Hostname:      node.Hostname,
+		Containers:    node.ContainerCount,
+		HourlyCostEUR: node.HourlyCostEUR,
+	}
+}
+
+// remaining returns the time from now until t (0 if t has passed)
+func remaining(t, now time.Time) time.Duration {
+	if t.Before(now) {
+		return 0
+	}
+	return t.Sub(now)
+}
@@ -0,0 +1,318 @@
+package conductor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+// FleetSnapshot is a point-in-time copy of the NodeRegistry, ContainerRegistry and StartQueue
+type FleetSnapshot struct {
+	TakenAt    time.Time
+	Nodes      []Node
+	Containers []ContainerInfo
+	Queue      []QueuedServer
+}
+
+// Snapshot copies the registries and the start queue while holding all three locks,
+// so every container in the copy references the node state of the same instant.
+// Lock order matches RegisterContainer (containers before nodes).
+func (c *Conductor) Snapshot() *FleetSnapshot {
+	c.ContainerRegistry.mu.RLock()
+	defer c.ContainerRegistry.mu.RUnlock()
+	c.NodeRegistry.mu.RLock()
+	defer c.NodeRegistry.mu.RUnlock()
+	c.StartQueue.mu.RLock()
+	defer c.StartQueue.mu.RUnlock()
+
+	snapshot := &FleetSnapshot{
+		TakenAt:    time.Now(),
+		Nodes:      make([]Node, 0, len(c.NodeRegistry.nodes)),
+		Containers: make([]ContainerInfo, 0, len(c.ContainerRegistry.containers)),
+		Queue:      make([]QueuedServer, 0, len(c.StartQueue.queue)),
+	}
+	for _, node := range c.NodeRegistry.nodes {
+		n := *node
+		n.Labels = make(map[string]string, len(node.Labels))
+		for k, v := range node.Labels {
+			n.Labels[k] = v
+		}
+		n.Taints = append([]models.NodeTaint(nil), node.Taints...)
+		snapshot.Nodes = append(snapshot.Nodes, n)
+	}
+	for _, container := range c.ContainerRegistry.containers {
+		snapshot.Containers = append(snapshot.Containers, *container)
+	}
+	for _, queued := range c.StartQueue.queue {
+		snapshot.Queue = append(snapshot.Queue, *queued)
+	}
+
+	sort.Slice(snapshot.Nodes, func(i, j int) bool { return snapshot.Nodes[i].ID < snapshot.Nodes[j].ID })
+	sort.Slice(snapshot.Containers, func(i, j int) bool { return snapshot.Containers[i].ServerID < snapshot.Containers[j].ServerID })
+	return snapshot
+}
+
+// Topology vertex kinds
+const (
+	TopologyNode      = "node"
+	TopologyContainer = "container"
+	TopologyProxy     = "proxy"
+	TopologyQueue     = "queue"
+	TopologyQueued    = "queued_server"
+)
+
+// Topology edge kinds
+const (
+	TopologyEdgeHosts     = "hosts"       // node -> container
+	TopologyEdgeProxies   = "proxies"     // proxy -> running container
+	TopologyEdgeMigration = "migrates_to" // container -> target node (pending migration)
+	TopologyEdgeWaiting   = "waiting"     // queue -> queued server
+)
+
+// TopologyVertex is a vertex of the fleet graph
+type TopologyVertex struct {
+	ID         string                 `json:"id"`
+	Kind       string                 `json:"kind"`
+	Label      string                 `json:"label"`
+	Parent     string                 `json:"parent,omitempty"` // Hosting node of a container
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// TopologyEdge is a directed edge of the fleet graph
+type TopologyEdge struct {
+	From       string                 `json:"from"`
+	To         string                 `json:"to"`
+	Kind       string                 `json:"kind"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// TopologyGraph is the fleet as a graph document for external visualization and capacity reviews
+type TopologyGraph struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Summary     TopologySummary  `json:"summary"`
+	Vertices    []TopologyVertex `json:"vertices"`
+	Edges       []TopologyEdge   `json:"edges"`
+}
+
+// TopologySummary holds fleet totals of the snapshot
+type TopologySummary struct {
+	Nodes             int `json:"nodes"`
+	Containers        int `json:"containers"`
+	PendingMigrations int `json:"pending_migrations"`
+	QueuedServers     int `json:"queued_servers"`
+	TotalRAMMB        int `json:"total_ram_mb"`
+	AllocatedRAMMB    int `json:"allocated_ram_mb"`
+	QueuedRAMMB       int `json:"queued_ram_mb"`
+}
+
+// proxyNodeID is the node running the Velocity proxy
+const proxyNodeID = "proxy-node"
+
+// BuildTopologyGraph turns a fleet snapshot and the unfinished migrations into a graph
+func BuildTopologyGraph(snapshot *FleetSnapshot, migrations []models.Migration) *TopologyGraph {
+	graph := &TopologyGraph{
+		GeneratedAt: snapshot.TakenAt,
+		Vertices:    []TopologyVertex{},
+		Edges:       []TopologyEdge{},
+	}
+
+	nodeIDs := make(map[string]bool, len(snapshot.Nodes))
+	for _, node := range snapshot.Nodes {
+		nodeIDs[node.ID] = true
+		kind := TopologyNode
+		if node.ID == proxyNodeID {
+			kind = TopologyProxy
+		}
+		graph.Vertices = append(graph.Vertices, TopologyVertex{
+			ID:    node.ID,
+			Kind:  kind,
+			Label: node.Hostname,
+			Attributes: map[string]interface{}{
+				"ip_address":       node.IPAddress,
+				"type":             node.Type,
+				"system_node":      node.IsSystemNode,
+				"health":           node.HealthStatus,
+				"lifecycle":        node.LifecycleState,
+				"total_ram_mb":     node.TotalRAMMB,
+				"allocated_ram_mb": node.AllocatedRAMMB,
+				"available_ram_mb": node.AvailableRAMMB(),
+				"cpu_percent":      node.CPUUsagePercent,
+				"hourly_cost_eur":  node.HourlyCostEUR,
+				"dedicated_owner":  node.DedicatedOwnerID,
+				"labels":           node.Labels,
+			},
+		})
+		if !node.IsSystemNode {
+			graph.Summary.Nodes++
+			graph.Summary.TotalRAMMB += node.TotalRAMMB
+			graph.Summary.AllocatedRAMMB += node.AllocatedRAMMB
+		}
+	}
+
+	// Velocity runs outside the registry on single-node setups
+	proxyID := proxyNodeID
+	if !nodeIDs[proxyNodeID] {
+		proxyID = "velocity"
+		graph.Vertices = append(graph.Vertices, TopologyVertex{ID: proxyID, Kind: TopologyProxy, Label: "Velocity"})
+	}
+
+	containerByServer := make(map[string]bool, len(snapshot.Containers))
+	for _, container := range snapshot.Containers {
+		containerByServer[container.ServerID] = true
+		graph.Summary.Containers++
+		graph.Vertices = append(graph.Vertices, TopologyVertex{
+			ID:     container.ServerID,
+			Kind:   TopologyContainer,
+			Label:  container.ServerName,
+			Parent: container.NodeID,
+			Attributes: map[string]interface{}{
+				"status":            container.Status,
+				"ram_mb":            container.RAMMb,
+				"port":              container.MinecraftPort,
+				"minecraft_version": container.MinecraftVersion,
+				"server_type":       container.ServerType,
+				"plan":              container.PlanType,
+				"last_seen_at":      container.LastSeenAt,
+			},
+		})
+		if nodeIDs[container.NodeID] {
+			graph.Edges = append(graph.Edges, TopologyEdge{From: container.NodeID, To: container.ServerID, Kind: TopologyEdgeHosts})
+		}
+		if container.Status == "running" {
+			graph.Edges = append(graph.Edges, TopologyEdge{From: proxyID, To: container.ServerID, Kind: TopologyEdgeProxies})
+		}
+	}
+
+	for _, migration := range migrations {
+		graph.Summary.PendingMigrations++
+		from := migration.ServerID
+		if !containerByServer[from] {
+			from = migration.FromNodeID // Stopped server: show the move between nodes
+		}
+		graph.Edges = append(graph.Edges, TopologyEdge{
+			From: from,
+			To:   migration.ToNodeID,
+			Kind: TopologyEdgeMigration,
+			Attributes: map[string]interface{}{
+				"migration_id":      migration.ID,
+				"server_id":         migration.ServerID,
+				"status":            migration.Status,
+				"reason":            migration.Reason,
+				"savings_eur_month": migration.SavingsEURMonth,
+			},
+		})
+	}
+
+	if len(snapshot.Queue) > 0 {
+		graph.Vertices = append(graph.Vertices, TopologyVertex{ID: "start-queue", Kind: TopologyQueue, Label: "Start queue"})
+		for i, queued := range snapshot.Queue {
+			graph.Summary.QueuedServers++
+			graph.Summary.QueuedRAMMB += queued.RequiredRAMMB
+			id := "queued-" + queued.ServerID
+			graph.Vertices = append(graph.Vertices, TopologyVertex{
+				ID:    id,
+				Kind:  TopologyQueued,
+				Label: queued.ServerName,
+				Attributes: map[string]interface{}{
+					"server_id":       queued.ServerID,
+					"required_ram_mb": queued.RequiredRAMMB,
+					"queued_at":       queued.FirstQueuedAt,
+					"retry_count":     queued.RetryCount,
+				},
+			})
+			graph.Edges = append(graph.Edges, TopologyEdge{
+				From:       "start-queue",
+				To:         id,
+				Kind:       TopologyEdgeWaiting,
+				Attributes: map[string]interface{}{"position": i + 1},
+			})
+		}
+	}
+
+	return graph
+}
+
+// DOT renders the graph in Graphviz DOT format (containers are clustered by their node)
+func (g *TopologyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph fleet {\n")
+	b.WriteString("  rankdir=LR;\n  node [fontname=\"Helvetica\", fontsize=10];\n")
+	fmt.Fprintf(&b, "  label=%s;\n", dotQuote(fmt.Sprintf("PayPerPlay fleet %s", g.GeneratedAt.UTC().Format(time.RFC3339))))
+
+	children := make(map[string][]TopologyVertex)
+	for _, v := range g.Vertices {
+		if v.Kind == TopologyContainer && v.Parent != "" {
+			children[v.Parent] = append(children[v.Parent], v)
+		}
+	}
+
+	rendered := make(map[string]bool)
+	for _, v := range g.Vertices {
+		if v.Kind != TopologyNode && v.Kind != TopologyProxy {
+			continue
+		}
+		fmt.Fprintf(&b, "  subgraph %s {\n", dotQuote("cluster_"+v.ID))
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(dotVertexLabel(v)))
+		fmt.Fprintf(&b, "    %s [label=%s, shape=box3d];\n", dotQuote(v.ID), dotQuote(v.ID))
+		for _, child := range children[v.ID] {
+			fmt.Fprintf(&b, "    %s [label=%s, shape=box];\n", dotQuote(child.ID), dotQuote(dotVertexLabel(child)))
+			rendered[child.ID] = true
+		}
+		b.WriteString("  }\n")
+		rendered[v.ID] = true
+	}
+	for _, v := range g.Vertices {
+		if rendered[v.ID] {
+			continue
+		}
+		shape := "box"
+		switch v.Kind {
+		case TopologyQueue:
+			shape = "cds"
+		case TopologyQueued:
+			shape = "note"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", dotQuote(v.ID), dotQuote(dotVertexLabel(v)), shape)
+	}
+
+	for _, e := range g.Edges {
+		style := "solid"
+		switch e.Kind {
+		case TopologyEdgeProxies:
+			style = "dotted"
+		case TopologyEdgeMigration:
+			style = "dashed, color=orange"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=%s, style=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Kind), style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotVertexLabel(v TopologyVertex) string {
+	label := v.Label
+	if label == "" {
+		label = v.ID
+	}
+	switch v.Kind {
+	case TopologyNode, TopologyProxy:
+		if total, ok := v.Attributes["total_ram_mb"].(int); ok && total > 0 {
+			label += fmt.Sprintf(" (%d/%d MB)", v.Attributes["allocated_ram_mb"], total)
+		}
+	case TopologyContainer:
+		label += fmt.Sprintf("\n%v, %v MB", v.Attributes["status"], v.Attributes["ram_mb"])
+	case TopologyQueued:
+		label += fmt.Sprintf("\n%v MB", v.Attributes["required_ram_mb"])
+	}
+	return label
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -2,10 +2,12 @@ package conductor
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/payperplay/hosting/internal/cloud"
 	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/pkg/config"
 	"github.com/payperplay/hosting/pkg/logger"
 )
@@ -32,9 +34,12 @@ func NewVMProvisioner(cloudProvider cloud.CloudProvider, nodeRegistry *NodeRegis
 }
 
 // ProvisionNode creates a new cloud node with Docker and PayPerPlay agent installed
-func (p *VMProvisioner) ProvisionNode(serverType string) (*Node, error) {
+// With interruptible, a spot instance is requested if the provider offers them; such nodes are
+// labelled NodeLabelInterruptible, so reserved-plan servers are kept off them.
+func (p *VMProvisioner) ProvisionNode(serverType string, interruptible bool) (*Node, error) {
 	logger.Info("Starting VM provisioning", map[string]interface{}{
-		"server_type": serverType,
+		"server_type":   serverType,
+		"interruptible": interruptible,
 	})
 
 	// CRITICAL FIX: Create placeholder node IMMEDIATELY to prevent duplicate provisioning
@@ -123,7 +128,8 @@ func (p *VMProvisioner) ProvisionNode(serverType string) (*Node, error) {
 			"type":       "cloud", // vs "dedicated"
 			"created_at": fmt.Sprintf("%d", time.Now().Unix()), // Unix timestamp - Hetzner-compliant
 		},
-		SSHKeys: []string{p.sshKeyName},
+		SSHKeys:       []string{p.sshKeyName},
+		Interruptible: interruptible,
 	}
 
 	// Create server via cloud provider (THIS TAKES ~20 SECONDS!)
@@ -210,6 +216,14 @@ func (p *VMProvisioner) ProvisionNode(serverType string) (*Node, error) {
 		},
 		HourlyCostEUR: server.HourlyCostEUR,
 	}
+	if server.Interruptible {
+		// Watched for termination notices by the health checker, avoided by reserved-plan servers
+		node.Labels[models.NodeLabelInterruptible] = "true"
+	} else if interruptible {
+		logger.Warn("Cloud provider has no interruptible instances, provisioned a regular node", map[string]interface{}{
+			"server_id": server.ID,
+		})
+	}
 
 	// Calculate intelligent system reserve for cloud node (3-tier strategy)
 	node.UpdateSystemReserve(cfg.SystemReservedRAMMB, cfg.SystemReservedRAMPercent)
@@ -455,8 +469,15 @@ func (p *VMProvisioner) DecommissionNode(nodeID string, decisionBy string) error
 // generateCloudInit generates the Cloud-Init script for VM setup
 func (p *VMProvisioner) generateCloudInit() string {
 	// CRITICAL: Add conductor's public SSH key to allow health checks
-	// This is read from /root/.ssh/id_rsa.pub on the conductor node
+	// Managed by SSH key rotation; the built-in key (/root/.ssh/id_rsa.pub on the conductor node) is the fallback
 	conductorPubKey := "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDfaN2p3gNtatuhvad5b6JVkr05UVmELZl9KzI84Q/8xQQxOmmSI4N7Vy48n03t9xJlbbztyXa2aE1loxZ3GxKdh9kokyavvDxSB7UebeZTOH/A/UkOiruh9Nq47rACtvTgFS/QNRe4IfeswSHsRcAWVALz5rkZ53FfLd9JwgHwazeBf6avT5fcRxJ5NdQ8iDTtvuKZ81mwRoDVq4Q61uy5NGdeILDfWxUqX3N0WXOSmbEO0LqPsp4fb6I1GyT/9C/rC3JNrb2iD51AtAlAoMKg8y1dzyvJHh1TSBL6xPn0EavyzqFLW0ignvX8aLwKB0NIwrPsbEgOgqKknbBlsudAJxic/wS1mSjDjJl8SDY1VaDJo9n0uW4T2KyvPEovsCOyXFXd5Vnl/VQ4YdmdInuM+27+CnD1RGOJhuOA1TXvG2DIGzZe81adTCZS+kZwE7d6E2JCnYBpurUTZfsQVNJVy0+SjnoDlT0qnS1I+Mx361e6+YSFvJAPGDOF7jdUlK4Jwi0sz4zIWgOKGjpA8uITaXN/Qkv8M2v3FJ3EHeijxKPo/5W0nrJXyfMcn+qewuywuLSSjsphr1oy3+nVKIBJghmjvaeE4GAaXdbgHQEQ9E/+Azdk49ipiSsGfBytLXTIOlh4QjXzeQNxSn8i4FfjFJ9xHAquKNUBGsrv9nAcfQ== payperplay-conductor"
+	var authorizedKeys []string
+	if p.conductor != nil {
+		authorizedKeys = p.conductor.NodeAuthorizedKeys()
+	}
+	if len(authorizedKeys) == 0 {
+		authorizedKeys = []string{conductorPubKey}
+	}
 
 	return `#cloud-config
 package_update: true
@@ -470,7 +491,7 @@ packages:
 
 # CRITICAL: Add conductor's SSH public key for health checks
 ssh_authorized_keys:
-  - ` + conductorPubKey + `
+  - ` + strings.Join(authorizedKeys, "\n  - ") + `
 
 runcmd:
   # Enable and start Docker
@@ -522,7 +543,7 @@ final_message: "PayPerPlay node is ready after $UPTIME seconds"
 // ProvisionSpareNode creates a pre-configured spare node (for B6 - Hot-Spare Pool)
 func (p *VMProvisioner) ProvisionSpareNode() (*Node, error) {
 	// Use smallest server type for spares
-	return p.ProvisionNode("cx11") // 1 vCPU, 2GB RAM, cheapest option
+	return p.ProvisionNode("cx11", false) // 1 vCPU, 2GB RAM, cheapest option
 }
 
 // CreateNodeSnapshot creates a snapshot of a node (for B6 - Hot-Spare Pool)
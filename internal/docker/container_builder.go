@@ -2,8 +2,10 @@ package docker
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/config"
 )
 
 // ContainerBuilder provides methods to build Docker container configuration from Server model
@@ -68,16 +70,72 @@ func BuildContainerEnv(server *models.MinecraftServer) []string {
 		env = append(env, fmt.Sprintf("SEED=%s", server.LevelSeed))
 	}
 
+	// COLD-START: Reuse JVM class-data across restarts of the same server
+	// DIAGNOSTICS: Capture heap dumps on OOM for opted-in modded servers
+	if jvmOpts := BuildJVMOptsEnv(config.AppConfig, server.HeapDumpsActive()); jvmOpts != "" {
+		env = append(env, jvmOpts)
+	}
+
 	return env
 }
 
+// HeapDumpPrefix and HeapDumpSuffix frame the heap dump files the JVM writes to the server volume root
+// (java_pid<pid>.hprof). The JVM only writes into existing directories, so /data itself is used.
+const (
+	HeapDumpPrefix = "java_pid"
+	HeapDumpSuffix = ".hprof"
+)
+
+// BuildJVMOptsEnv builds the single JVM_XX_OPTS entry for a server container
+// itzg/minecraft-server only reads one JVM_XX_OPTS variable, so all -XX flags are combined here.
+// Returns an empty string if no extra JVM options are needed.
+func BuildJVMOptsEnv(cfg *config.Config, heapDumps bool) string {
+	var opts []string
+	if cfg != nil && cfg.AppCDSEnabled {
+		opts = append(opts, BuildAppCDSOpts(cfg.AppCDSArchiveName))
+	}
+	if heapDumps {
+		opts = append(opts, BuildHeapDumpOpts())
+	}
+	if len(opts) == 0 {
+		return ""
+	}
+	return "JVM_XX_OPTS=" + strings.Join(opts, " ")
+}
+
+// BuildAppCDSOpts builds the JVM flags that enable a dynamic AppCDS archive
+// The archive lives in the server volume (/data), so it survives container re-creation:
+// the first boot dumps loaded classes on JVM exit, every later boot maps them directly.
+// AutoCreateSharedArchive needs Java 19+, so APPCDS_ENABLED is off by default; on older images
+// IgnoreUnrecognizedVMOptions only keeps a misconfigured server bootable (without any speedup).
+func BuildAppCDSOpts(archiveName string) string {
+	return fmt.Sprintf(
+		"-XX:+IgnoreUnrecognizedVMOptions -XX:+AutoCreateSharedArchive -XX:SharedArchiveFile=/data/%s -Xshare:auto",
+		archiveName,
+	)
+}
+
+// BuildHeapDumpOpts builds the JVM flags that write a heap dump when the heap is exhausted
+// Dumps land in the server volume so they survive the crash and can be collected afterwards.
+func BuildHeapDumpOpts() string {
+	return fmt.Sprintf("-XX:+HeapDumpOnOutOfMemoryError -XX:HeapDumpPath=/data/%s%%p%s", HeapDumpPrefix, HeapDumpSuffix)
+}
+
+// VotifierContainerPort is the port NuVotifier listens on inside the container
+const VotifierContainerPort = "8192/tcp"
+
 // BuildPortBindings builds port mapping for Docker container
 // Returns map of internal port -> host port (e.g., "25565/tcp" -> 25577)
-func BuildPortBindings(hostPort int) map[string]int {
-	return map[string]int{
+// votifierPort maps the NuVotifier listener for vote sites (0 = not mapped).
+func BuildPortBindings(hostPort int, votifierPort int) map[string]int {
+	bindings := map[string]int{
 		"25565/tcp": hostPort, // Minecraft server port
 		// Note: RCON port (25575) is NOT mapped - we use docker exec for commands
 	}
+	if votifierPort > 0 {
+		bindings[VotifierContainerPort] = votifierPort
+	}
+	return bindings
 }
 
 // BuildVolumeBinds builds volume bindings for Docker container
@@ -88,11 +146,72 @@ func BuildVolumeBinds(serverID string, hostServersBasePath string) []string {
 	}
 }
 
-// GetDockerImageName returns the Docker image name for a Minecraft server
-func GetDockerImageName(serverType string) string {
-	// Currently we use itzg/minecraft-server for all server types
-	// In the future, we could have different images for different types
-	return "itzg/minecraft-server:latest"
+// CPULimits are the CPU weight and cap of a server container
+type CPULimits struct {
+	Shares int64   // Relative weight when servers compete for CPU (0 = docker default 1024)
+	Cores  float64 // Hard cap in cores (0 = unlimited)
+}
+
+// BuildCPULimits builds the CPU limits of a server container
+// Servers without their own shares get CONTAINER_CPU_SHARES_PER_GB per GB of booked RAM,
+// so under contention each server gets CPU in proportion to its plan and a busy one can't starve the rest.
+func BuildCPULimits(cfg *config.Config, server *models.MinecraftServer) CPULimits {
+	limits := CPULimits{
+		Shares: int64(server.CPUShares),
+		Cores:  server.CPULimitCores,
+	}
+	if cfg == nil {
+		return limits
+	}
+	if limits.Shares == 0 && cfg.ContainerCPUSharesPerGB > 0 {
+		limits.Shares = int64(server.RAMMb) * int64(cfg.ContainerCPUSharesPerGB) / 1024
+		if limits.Shares < models.MinCPUShares {
+			limits.Shares = models.MinCPUShares
+		}
+	}
+	if limits.Cores == 0 && cfg.ContainerCPULimitCores > 0 {
+		limits.Cores = cfg.ContainerCPULimitCores
+	}
+	return limits
+}
+
+// NanoCPUs returns the CPU cap in units of 10^-9 cores (docker HostConfig.NanoCPUs)
+func (l CPULimits) NanoCPUs() int64 {
+	return int64(l.Cores * 1e9)
+}
+
+// Args returns the docker run/update flags for the limits (empty if docker defaults apply)
+func (l CPULimits) Args() string {
+	var args strings.Builder
+	if l.Shares > 0 {
+		args.WriteString(fmt.Sprintf(" --cpu-shares=%d", l.Shares))
+	}
+	if l.Cores > 0 {
+		args.WriteString(fmt.Sprintf(" --cpus=%.2f", l.Cores))
+	}
+	return args.String()
+}
+
+// GetDockerImageName returns the Docker image for servers on a release channel
+// All server types use itzg/minecraft-server; the channel decides which tag (beta gets image updates first).
+func GetDockerImageName(cfg *config.Config, channel models.ReleaseChannel) string {
+	if channel == models.ReleaseChannelBeta && cfg.MinecraftImageBeta != "" {
+		return cfg.MinecraftImageBeta
+	}
+	if cfg.MinecraftImageStable != "" {
+		return cfg.MinecraftImageStable
+	}
+	return "itzg/minecraft-server:stable"
+}
+
+// ImageName returns the Docker image for servers on a release channel
+func (d *DockerService) ImageName(channel models.ReleaseChannel) string {
+	return GetDockerImageName(d.cfg, channel)
+}
+
+// CPULimits returns the CPU limits of a server container
+func (d *DockerService) CPULimits(server *models.MinecraftServer) CPULimits {
+	return BuildCPULimits(d.cfg, server)
 }
 
 // getServerTypeEnv converts our internal server type to itzg/minecraft-server TYPE env var
@@ -0,0 +1,155 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// ContainerStats is one resource usage sample of a server container
+// Network and block IO are counters since the container started.
+type ContainerStats struct {
+	CPUPercent       float64 // 100 = one core fully used
+	MemoryBytes      int64   // Without page cache, like docker stats
+	MemoryLimitBytes int64
+	NetRxBytes       int64
+	NetTxBytes       int64
+	BlockReadBytes   int64
+	BlockWriteBytes  int64
+}
+
+// LocalContainerStats returns a resource usage sample of all running mc-* containers of a Docker daemon
+// Returns map of serverID -> sample
+func LocalContainerStats(ctx context.Context, cli *client.Client) (map[string]ContainerStats, error) {
+	containers, err := cli.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	stats := make(map[string]ContainerStats)
+	for _, c := range containers {
+		if len(c.Names) == 0 || !strings.HasPrefix(c.Names[0], "/mc-") {
+			continue
+		}
+		// Non-streaming stats include the previous sample, so one call yields a CPU delta
+		response, err := cli.ContainerStats(ctx, c.ID, false)
+		if err != nil {
+			continue // Container stopped in between
+		}
+		var sample container.StatsResponse
+		err = json.NewDecoder(response.Body).Decode(&sample)
+		response.Body.Close()
+		if err != nil {
+			continue
+		}
+		stats[strings.TrimPrefix(c.Names[0], "/mc-")] = StatsFromResponse(&sample)
+	}
+	return stats, nil
+}
+
+// StatsFromResponse converts a Docker API stats sample
+func StatsFromResponse(sample *container.StatsResponse) ContainerStats {
+	stats := ContainerStats{
+		CPUPercent:       CPUPercent(sample),
+		MemoryBytes:      int64(sample.MemoryStats.Usage),
+		MemoryLimitBytes: int64(sample.MemoryStats.Limit),
+	}
+
+	// Page cache is reclaimable, docker stats leaves it out as well (cgroup v2 / v1 key)
+	cache, ok := sample.MemoryStats.Stats["inactive_file"]
+	if !ok {
+		cache = sample.MemoryStats.Stats["total_inactive_file"]
+	}
+	if cache < sample.MemoryStats.Usage {
+		stats.MemoryBytes -= int64(cache)
+	}
+
+	for _, network := range sample.Networks {
+		stats.NetRxBytes += int64(network.RxBytes)
+		stats.NetTxBytes += int64(network.TxBytes)
+	}
+	for _, entry := range sample.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			stats.BlockReadBytes += int64(entry.Value)
+		case "write":
+			stats.BlockWriteBytes += int64(entry.Value)
+		}
+	}
+	return stats
+}
+
+// CPUPercent computes the CPU usage of a stats sample (100 = one core fully used)
+func CPUPercent(sample *container.StatsResponse) float64 {
+	cpuDelta := float64(sample.CPUStats.CPUUsage.TotalUsage) - float64(sample.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(sample.CPUStats.SystemUsage) - float64(sample.PreCPUStats.SystemUsage)
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+	cpus := float64(sample.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(sample.CPUStats.CPUUsage.PercpuUsage))
+	}
+	return cpuDelta / systemDelta * cpus * 100
+}
+
+// ParseContainerStats parses docker stats lines "name|CPUPerc|MemUsage|NetIO|BlockIO" into serverID -> sample
+// e.g. "mc-abc|12.34%|1.2GiB / 4GiB|1.5MB / 800kB|12MB / 3.4GB". Containers other than mc-* are skipped.
+func ParseContainerStats(output string) map[string]ContainerStats {
+	stats := make(map[string]ContainerStats)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "|")
+		if len(fields) != 5 || !strings.HasPrefix(fields[0], "mc-") {
+			continue
+		}
+		cpu, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(fields[1]), "%"), 64)
+		if err != nil {
+			continue
+		}
+		sample := ContainerStats{CPUPercent: cpu}
+		sample.MemoryBytes, sample.MemoryLimitBytes = parseDockerSizePair(fields[2])
+		sample.NetRxBytes, sample.NetTxBytes = parseDockerSizePair(fields[3])
+		sample.BlockReadBytes, sample.BlockWriteBytes = parseDockerSizePair(fields[4])
+		stats[strings.TrimPrefix(fields[0], "mc-")] = sample
+	}
+	return stats
+}
+
+// dockerSizeUnits are the units docker stats prints (binary for memory, decimal for IO)
+var dockerSizeUnits = map[string]float64{
+	"B":   1,
+	"kB":  1e3,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+}
+
+// parseDockerSizePair parses "used / total" sizes of docker stats
+func parseDockerSizePair(value string) (int64, int64) {
+	first, second, _ := strings.Cut(value, "/")
+	return parseDockerSize(first), parseDockerSize(second)
+}
+
+// parseDockerSize parses a docker stats size such as "1.5GiB" or "800kB" (0 if unparsable, e.g. "--")
+func parseDockerSize(value string) int64 {
+	value = strings.TrimSpace(value)
+	split := strings.IndexFunc(value, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if split <= 0 {
+		return 0
+	}
+	number, err := strconv.ParseFloat(value[:split], 64)
+	if err != nil {
+		return 0
+	}
+	return int64(number * dockerSizeUnits[strings.TrimSpace(value[split:])])
+}
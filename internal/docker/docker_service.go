@@ -15,9 +15,17 @@ import (
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
+	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/pkg/config"
 )
 
+const (
+	// dockerAPITimeout bounds a single Docker API call (inspect, start, remove, exec, logs)
+	dockerAPITimeout = 30 * time.Second
+	// dockerCreateTimeout bounds a container creation, including the image pull
+	dockerCreateTimeout = 5 * time.Minute
+)
+
 type DockerService struct {
 	client     *client.Client
 	cfg        *config.Config
@@ -47,7 +55,9 @@ func NewDockerService(cfg *config.Config) (*DockerService, error) {
 }
 
 // CreateContainer creates a Docker container for a Minecraft server
+// (bounded by dockerCreateTimeout, the image is pulled first if missing)
 func (d *DockerService) CreateContainer(
+	ctx context.Context,
 	serverID string,
 	serverType string,
 	minecraftVersion string,
@@ -80,8 +90,17 @@ func (d *DockerService) CreateContainer(
 	networkCompressionThreshold int,
 	// Phase 4 Parameters - Server Description
 	motd string,
+	// Runtime Diagnostics
+	heapDumpsEnabled bool,
+	// Vote Site Integration (0 = Votifier disabled)
+	votifierPort int,
+	// Managed Components (image of the server's release channel, see ImageName)
+	imageName string,
+	// Fair CPU share between servers on the node (see CPULimits)
+	cpu CPULimits,
 ) (string, error) {
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(ctx, dockerCreateTimeout)
+	defer cancel()
 
 	// Create server directory (inside container or on host)
 	serverDir := filepath.Join(d.serversDir, serverID)
@@ -98,7 +117,9 @@ func (d *DockerService) CreateContainer(
 	}
 
 	// Determine Docker image (using itzg/minecraft-server)
-	imageName := "itzg/minecraft-server:latest"
+	if imageName == "" {
+		imageName = d.ImageName(models.ReleaseChannelStable)
+	}
 
 	// Pull image if not exists
 	if err := d.ensureImage(ctx, imageName); err != nil {
@@ -162,22 +183,44 @@ func (d *DockerService) CreateContainer(
 		env = append(env, fmt.Sprintf("SEED=%s", levelSeed))
 	}
 
+	// COLD-START: Reuse JVM class-data across restarts of the same server
+	// DIAGNOSTICS: Capture heap dumps on OOM for opted-in modded servers
+	if jvmOpts := BuildJVMOptsEnv(d.cfg, heapDumpsEnabled); jvmOpts != "" {
+		env = append(env, jvmOpts)
+	}
+
 	// Note: Allow End is set via server.properties, not ENV
 	// We'll need to handle this after container creation
 
 	// Note: RCON port is NOT mapped to host for security
 	// Commands are executed via docker exec instead
 
+	exposedPorts := nat.PortSet{
+		"25565/tcp": struct{}{},
+		"25575/tcp": struct{}{}, // RCON port
+	}
+	portBindings := nat.PortMap{
+		"25565/tcp": []nat.PortBinding{portBinding},
+		// RCON port (25575) is NOT mapped - we use docker exec for commands
+	}
+
+	// Votifier listener for vote sites (NuVotifier plugin inside the container)
+	if votifierPort > 0 {
+		votifierContainerPort := nat.Port(VotifierContainerPort)
+		exposedPorts[votifierContainerPort] = struct{}{}
+		portBindings[votifierContainerPort] = []nat.PortBinding{{
+			HostIP:   "0.0.0.0",
+			HostPort: strconv.Itoa(votifierPort),
+		}}
+	}
+
 	// Create container
 	resp, err := d.client.ContainerCreate(
 		ctx,
 		&container.Config{
-			Image: imageName,
-			Env:   env,
-			ExposedPorts: nat.PortSet{
-				"25565/tcp": struct{}{},
-				"25575/tcp": struct{}{}, // RCON port
-			},
+			Image:        imageName,
+			Env:          env,
+			ExposedPorts: exposedPorts,
 			Labels: map[string]string{
 				"payperplay.server_id": serverID,
 				"payperplay.type":      serverType,
@@ -185,10 +228,7 @@ func (d *DockerService) CreateContainer(
 			},
 		},
 		&container.HostConfig{
-			PortBindings: nat.PortMap{
-				"25565/tcp": []nat.PortBinding{portBinding},
-				// RCON port (25575) is NOT mapped - we use docker exec for commands
-			},
+			PortBindings: portBindings,
 			Binds: []string{
 				fmt.Sprintf("%s:/data", hostPath),
 			},
@@ -199,6 +239,9 @@ func (d *DockerService) CreateContainer(
 				// Add 25% overhead for JVM native memory, threads, GC, etc.
 				// This prevents OOM kills when Java heap is set to ramMB
 				Memory: int64(float64(ramMB)*1.25) * 1024 * 1024, // MB to bytes
+				// CPU weight under contention and optional hard cap
+				CPUShares: cpu.Shares,
+				NanoCPUs:  cpu.NanoCPUs(),
 			},
 		},
 		nil,
@@ -215,8 +258,9 @@ func (d *DockerService) CreateContainer(
 }
 
 // StartContainer starts a Docker container
-func (d *DockerService) StartContainer(containerID string) error {
-	ctx := context.Background()
+func (d *DockerService) StartContainer(ctx context.Context, containerID string) error {
+	ctx, cancel := context.WithTimeout(ctx, dockerAPITimeout)
+	defer cancel()
 	err := d.client.ContainerStart(ctx, containerID, container.StartOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to start container: %w", err)
@@ -226,8 +270,9 @@ func (d *DockerService) StartContainer(containerID string) error {
 }
 
 // WaitForServerReady waits for the Minecraft server to be ready by monitoring logs
-func (d *DockerService) WaitForServerReady(containerID string, timeoutSeconds int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutSeconds)*time.Second)
+// (at most timeoutSeconds, less if ctx ends first)
+func (d *DockerService) WaitForServerReady(ctx context.Context, containerID string, timeoutSeconds int) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
 	defer cancel()
 
 	// Stream container logs
@@ -251,7 +296,7 @@ func (d *DockerService) WaitForServerReady(containerID string, timeoutSeconds in
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("timeout waiting for server to be ready")
+			return fmt.Errorf("timeout waiting for server to be ready: %w", ctx.Err())
 		default:
 			n, err := reader.Read(buf)
 			if err != nil {
@@ -284,8 +329,10 @@ func containsReadyMarker(logText string) bool {
 }
 
 // StopContainer stops a Docker container gracefully
-func (d *DockerService) StopContainer(containerID string, timeoutSeconds int) error {
-	ctx := context.Background()
+func (d *DockerService) StopContainer(ctx context.Context, containerID string, timeoutSeconds int) error {
+	// The container gets timeoutSeconds to shut down before it is killed
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second+dockerAPITimeout)
+	defer cancel()
 	timeout := timeoutSeconds
 	err := d.client.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout})
 	if err != nil {
@@ -297,8 +344,9 @@ func (d *DockerService) StopContainer(containerID string, timeoutSeconds int) er
 
 // PauseContainer pauses a running Docker container
 // FIX #2: Used to pause container before backup to prevent data corruption
-func (d *DockerService) PauseContainer(containerID string) error {
-	ctx := context.Background()
+func (d *DockerService) PauseContainer(ctx context.Context, containerID string) error {
+	ctx, cancel := context.WithTimeout(ctx, dockerAPITimeout)
+	defer cancel()
 	err := d.client.ContainerPause(ctx, containerID)
 	if err != nil {
 		return fmt.Errorf("failed to pause container: %w", err)
@@ -309,8 +357,9 @@ func (d *DockerService) PauseContainer(containerID string) error {
 
 // UnpauseContainer unpauses a paused Docker container
 // FIX #2: Used to unpause container after backup completes
-func (d *DockerService) UnpauseContainer(containerID string) error {
-	ctx := context.Background()
+func (d *DockerService) UnpauseContainer(ctx context.Context, containerID string) error {
+	ctx, cancel := context.WithTimeout(ctx, dockerAPITimeout)
+	defer cancel()
 	err := d.client.ContainerUnpause(ctx, containerID)
 	if err != nil {
 		return fmt.Errorf("failed to unpause container: %w", err)
@@ -320,8 +369,9 @@ func (d *DockerService) UnpauseContainer(containerID string) error {
 }
 
 // RemoveContainer removes a Docker container
-func (d *DockerService) RemoveContainer(containerID string, force bool) error {
-	ctx := context.Background()
+func (d *DockerService) RemoveContainer(ctx context.Context, containerID string, force bool) error {
+	ctx, cancel := context.WithTimeout(ctx, dockerAPITimeout)
+	defer cancel()
 	err := d.client.ContainerRemove(ctx, containerID, container.RemoveOptions{
 		Force: force,
 	})
@@ -333,8 +383,9 @@ func (d *DockerService) RemoveContainer(containerID string, force bool) error {
 }
 
 // RemoveContainerByName removes a Docker container by name
-func (d *DockerService) RemoveContainerByName(containerName string) error {
-	ctx := context.Background()
+func (d *DockerService) RemoveContainerByName(ctx context.Context, containerName string) error {
+	ctx, cancel := context.WithTimeout(ctx, dockerAPITimeout)
+	defer cancel()
 
 	// Try to remove the container (force=true to handle any state)
 	err := d.client.ContainerRemove(ctx, containerName, container.RemoveOptions{
@@ -355,9 +406,9 @@ func (d *DockerService) RemoveContainerByName(containerName string) error {
 }
 
 // StreamContainerLogs streams container logs to a channel
-// Returns a channel that receives log lines and a cancel function
-func (d *DockerService) StreamContainerLogs(containerID string) (<-chan string, context.CancelFunc, error) {
-	ctx, cancel := context.WithCancel(context.Background())
+// Returns a channel that receives log lines and a cancel function; the stream also ends with ctx
+func (d *DockerService) StreamContainerLogs(ctx context.Context, containerID string) (<-chan string, context.CancelFunc, error) {
+	ctx, cancel := context.WithCancel(ctx)
 
 	options := container.LogsOptions{
 		ShowStdout: true,
@@ -419,8 +470,9 @@ func (d *DockerService) StreamContainerLogs(containerID string) (<-chan string,
 }
 
 // ExecuteCommand executes a Minecraft command in a container
-func (d *DockerService) ExecuteCommand(containerID, command string) (string, error) {
-	ctx := context.Background()
+func (d *DockerService) ExecuteCommand(ctx context.Context, containerID, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, dockerAPITimeout)
+	defer cancel()
 
 	// Create exec instance with RCON command
 	rconCommand := []string{"rcon-cli", command}
@@ -457,8 +509,9 @@ func (d *DockerService) ExecuteCommand(containerID, command string) (string, err
 }
 
 // GetContainerStatus gets the status of a container
-func (d *DockerService) GetContainerStatus(containerID string) (string, error) {
-	ctx := context.Background()
+func (d *DockerService) GetContainerStatus(ctx context.Context, containerID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, dockerAPITimeout)
+	defer cancel()
 	inspect, err := d.client.ContainerInspect(ctx, containerID)
 	if err != nil {
 		return "", err
@@ -467,8 +520,9 @@ func (d *DockerService) GetContainerStatus(containerID string) (string, error) {
 }
 
 // GetContainerLogs retrieves logs from a container
-func (d *DockerService) GetContainerLogs(containerID string, tail string) (string, error) {
-	ctx := context.Background()
+func (d *DockerService) GetContainerLogs(ctx context.Context, containerID string, tail string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, dockerAPITimeout)
+	defer cancel()
 	options := container.LogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
@@ -491,11 +545,12 @@ func (d *DockerService) GetContainerLogs(containerID string, tail string) (strin
 
 // ListRunningMinecraftContainers returns all currently running mc-* containers
 // Used by Conductor to sync state after restarts
-func (d *DockerService) ListRunningMinecraftContainers() ([]struct {
+func (d *DockerService) ListRunningMinecraftContainers(ctx context.Context) ([]struct {
 	ContainerID string
 	ServerID    string
 }, error) {
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(ctx, dockerAPITimeout)
+	defer cancel()
 
 	// List all containers with name prefix "mc-"
 	containers, err := d.client.ContainerList(ctx, container.ListOptions{
@@ -614,6 +669,33 @@ func (d *DockerService) UpdateContainerMemory(ctx context.Context, containerID s
 	return nil
 }
 
+// UpdateContainerCPU applies new CPU limits to an existing container
+// A removed cap (Cores 0) is left in place until the container is re-created on the next start.
+func (d *DockerService) UpdateContainerCPU(ctx context.Context, containerID string, cpu CPULimits) error {
+	updateConfig := container.UpdateConfig{
+		Resources: container.Resources{
+			CPUShares: cpu.Shares,
+			NanoCPUs:  cpu.NanoCPUs(),
+		},
+	}
+
+	if _, err := d.client.ContainerUpdate(ctx, containerID, updateConfig); err != nil {
+		return fmt.Errorf("failed to update container CPU limits: %w", err)
+	}
+
+	log.Printf("[Docker] Updated container %s CPU limits (shares: %d, cores: %.2f)",
+		containerID[:12], cpu.Shares, cpu.Cores)
+	return nil
+}
+
+// Ping checks that the Docker daemon responds
+func (d *DockerService) Ping(ctx context.Context) error {
+	if _, err := d.client.Ping(ctx); err != nil {
+		return fmt.Errorf("docker daemon unreachable: %w", err)
+	}
+	return nil
+}
+
 // Close closes the Docker client
 func (d *DockerService) Close() error {
 	return d.client.Close()
@@ -0,0 +1,98 @@
+package docker
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxCommandOutput is the default cap on captured output of a remote/shell command
+const DefaultMaxCommandOutput = 1 << 20 // 1 MiB
+
+// LimitedBuffer is a concurrency-safe io.Writer that keeps at most maxBytes of output
+// The first half of the budget keeps the beginning of the output, the second half is a
+// ring of the most recent bytes (where errors usually are). Everything in between is
+// dropped and replaced by an overflow marker in String().
+type LimitedBuffer struct {
+	mu       sync.Mutex
+	headMax  int
+	tailMax  int
+	head     []byte
+	tail     []byte // Ring buffer once full
+	tailPos  int
+	dropped  int64
+	overflow bool
+}
+
+// NewLimitedBuffer creates a buffer that retains at most maxBytes (< 2 uses DefaultMaxCommandOutput)
+func NewLimitedBuffer(maxBytes int) *LimitedBuffer {
+	if maxBytes < 2 {
+		maxBytes = DefaultMaxCommandOutput
+	}
+	headMax := maxBytes / 2
+	return &LimitedBuffer{
+		headMax: headMax,
+		tailMax: maxBytes - headMax,
+	}
+}
+
+// Write implements io.Writer, never fails
+func (b *LimitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := len(p)
+
+	// Fill head first
+	if room := b.headMax - len(b.head); room > 0 {
+		if room > len(p) {
+			room = len(p)
+		}
+		b.head = append(b.head, p[:room]...)
+		p = p[room:]
+	}
+
+	for len(p) > 0 {
+		// Grow tail until full
+		if len(b.tail) < b.tailMax {
+			room := b.tailMax - len(b.tail)
+			if room > len(p) {
+				room = len(p)
+			}
+			b.tail = append(b.tail, p[:room]...)
+			p = p[room:]
+			continue
+		}
+
+		// Tail full: overwrite oldest bytes
+		b.overflow = true
+		copied := copy(b.tail[b.tailPos:], p)
+		b.dropped += int64(copied)
+		b.tailPos = (b.tailPos + copied) % b.tailMax
+		p = p[copied:]
+	}
+
+	return n, nil
+}
+
+// Truncated returns true if output was dropped
+func (b *LimitedBuffer) Truncated() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.overflow
+}
+
+// String returns the retained output, with an overflow marker where bytes were dropped
+func (b *LimitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.overflow {
+		return string(b.head) + string(b.tail)
+	}
+
+	tail := make([]byte, 0, len(b.tail))
+	tail = append(tail, b.tail[b.tailPos:]...)
+	tail = append(tail, b.tail[:b.tailPos]...)
+
+	return fmt.Sprintf("%s\n...[output truncated: %d bytes omitted]...\n%s", b.head, b.dropped, tail)
+}
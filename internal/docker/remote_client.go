@@ -1,33 +1,62 @@
 package docker
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"path/filepath"
 	"strings"
 	"time"
 
-	"golang.org/x/crypto/ssh"
+	"github.com/payperplay/hosting/pkg/config"
 )
 
 // RemoteDockerClient manages Docker containers on remote nodes via SSH
 type RemoteDockerClient struct {
 	sshKeyPath string
+	pool       *SSHPool // One multiplexed connection per node
 }
 
 // NewRemoteDockerClient creates a new remote Docker client
 func NewRemoteDockerClient(sshKeyPath string) (*RemoteDockerClient, error) {
-	// Verify SSH key exists
-	// Note: We don't load it here, we load it per-connection
-	// This allows for different keys per node in the future
+	// Note: The SSH key is loaded lazily on the first connection
+	poolCfg := DefaultSSHPoolConfig()
+	if cfg := config.AppConfig; cfg != nil {
+		poolCfg.MaxSessionsPerNode = cfg.SSHPoolMaxSessions
+		if idle, err := time.ParseDuration(cfg.SSHPoolIdleTimeout); err == nil {
+			poolCfg.IdleTimeout = idle
+		}
+		if keepalive, err := time.ParseDuration(cfg.SSHPoolKeepalive); err == nil {
+			poolCfg.KeepaliveInterval = keepalive
+		}
+		if timeout, err := time.ParseDuration(cfg.RemoteCommandTimeout); err == nil {
+			poolCfg.CommandTimeout = timeout
+		}
+		if cfg.RemoteCommandMaxOutput > 0 {
+			poolCfg.MaxOutputBytes = cfg.RemoteCommandMaxOutput
+		}
+	}
+
 	return &RemoteDockerClient{
 		sshKeyPath: sshKeyPath,
+		pool:       NewSSHPool(sshKeyPath, poolCfg),
 	}, nil
 }
 
+// Pool returns the SSH connection pool (shared with transfer paths)
+func (r *RemoteDockerClient) Pool() *SSHPool {
+	return r.pool
+}
+
+// SetPrivateKey replaces the SSH key used for new node connections
+func (r *RemoteDockerClient) SetPrivateKey(keyData []byte) error {
+	return r.pool.SetPrivateKey(keyData)
+}
+
+// Close closes all pooled SSH connections
+func (r *RemoteDockerClient) Close() {
+	r.pool.Close()
+}
+
 // RemoteNode represents the minimal node information needed for remote operations
 type RemoteNode struct {
 	ID        string
@@ -51,6 +80,7 @@ func (r *RemoteDockerClient) StartContainer(
 	portBindings map[string]int, // internal port -> host port
 	binds []string,               // volume binds
 	ramMB int,
+	cpu CPULimits,
 ) (string, error) {
 	// LIFECYCLE FIX: Check if container already exists (sleeping/stopped state)
 	checkCmd := fmt.Sprintf("docker ps -a --filter name=^/%s$ --format '{{.ID}}|{{.State}}'", containerName)
@@ -69,7 +99,11 @@ func (r *RemoteDockerClient) StartContainer(
 				// Container is stopped - WARM RESTART using docker start
 				log.Printf("[RemoteDocker] WARM RESTART: Starting existing container %s on node %s", containerName, node.ID)
 
+				// Apply current CPU limits first (they may have changed while the server slept)
 				startCmd := fmt.Sprintf("docker start %s && docker ps --filter id=%s --format '{{.ID}}'", existingContainerID, existingContainerID)
+				if cpuArgs := cpu.Args(); cpuArgs != "" {
+					startCmd = fmt.Sprintf("docker update%s %s >/dev/null && %s", cpuArgs, existingContainerID, startCmd)
+				}
 				startCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 				defer cancel()
 
@@ -98,7 +132,7 @@ func (r *RemoteDockerClient) StartContainer(
 	log.Printf("[RemoteDocker] COLD START: Creating new container %s on node %s", containerName, node.ID)
 
 	// Build docker run command
-	cmd := r.buildDockerRunCommand(containerName, imageName, env, portBindings, binds, ramMB)
+	cmd := r.buildDockerRunCommand(containerName, imageName, env, portBindings, binds, ramMB, cpu)
 
 	// Execute command via SSH with timeout context
 	// Add 120-second timeout for container creation (allows time for image pull)
@@ -190,7 +224,7 @@ func (r *RemoteDockerClient) GetContainerLogs(ctx context.Context, node *RemoteN
 }
 
 // StreamContainerLogs streams container logs from a remote node
-// NOTE: This is a simplified implementation. For production, consider using SSH multiplexing
+// NOTE: Polls over the pooled SSH connection, so each poll only opens a new session (no handshake)
 func (r *RemoteDockerClient) StreamContainerLogs(ctx context.Context, node *RemoteNode, containerID string) (<-chan string, context.CancelFunc, error) {
 	logChan := make(chan string, 100)
 	streamCtx, cancel := context.WithCancel(ctx)
@@ -309,6 +343,34 @@ func (r *RemoteDockerClient) ListRunningContainers(ctx context.Context, node *Re
 	return result, nil
 }
 
+// UpdateContainerCPU applies new CPU limits to a container on a remote node
+func (r *RemoteDockerClient) UpdateContainerCPU(ctx context.Context, node *RemoteNode, containerID string, cpu CPULimits) error {
+	cpuArgs := cpu.Args()
+	if cpuArgs == "" {
+		return nil // Docker defaults, a removed cap is dropped when the container is re-created
+	}
+
+	output, err := r.executeSSHCommand(ctx, node, fmt.Sprintf("docker update%s %s", cpuArgs, containerID))
+	if err != nil {
+		return fmt.Errorf("failed to update container CPU limits on node %s: %w (output: %s)", node.ID, err, output)
+	}
+
+	log.Printf("[RemoteDocker] Updated CPU limits of container %s on node %s (%s)", containerID[:12], node.ID, strings.TrimSpace(cpuArgs))
+	return nil
+}
+
+// ContainerStats returns a resource usage sample of all running mc-* containers on a remote node
+// Returns map of serverID -> sample (see ParseContainerStats)
+func (r *RemoteDockerClient) ContainerStats(ctx context.Context, node *RemoteNode) (map[string]ContainerStats, error) {
+	cmd := `docker stats --no-stream --format "{{.Name}}|{{.CPUPerc}}|{{.MemUsage}}|{{.NetIO}}|{{.BlockIO}}"`
+
+	output, err := r.executeSSHCommand(ctx, node, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container stats on node %s: %w", node.ID, err)
+	}
+	return ParseContainerStats(output), nil
+}
+
 // WaitForServerReady waits for a Minecraft server to be ready by monitoring logs
 func (r *RemoteDockerClient) WaitForServerReady(ctx context.Context, node *RemoteNode, containerID string, timeoutSeconds int) error {
 	deadline := time.Now().Add(time.Duration(timeoutSeconds) * time.Second)
@@ -422,6 +484,7 @@ func (r *RemoteDockerClient) buildDockerRunCommand(
 	portBindings map[string]int,
 	binds []string,
 	ramMB int,
+	cpu CPULimits,
 ) string {
 	var cmd strings.Builder
 	cmd.WriteString("docker run -d")
@@ -448,6 +511,9 @@ func (r *RemoteDockerClient) buildDockerRunCommand(
 	memoryBytes := int64(float64(ramMB)*1.25) * 1024 * 1024
 	cmd.WriteString(fmt.Sprintf(" --memory=%d", memoryBytes))
 
+	// CPU weight and cap (fair share between servers on the node)
+	cmd.WriteString(cpu.Args())
+
 	// Restart policy
 	cmd.WriteString(" --restart=no")
 
@@ -457,89 +523,7 @@ func (r *RemoteDockerClient) buildDockerRunCommand(
 	return cmd.String()
 }
 
-// executeSSHCommand executes a command on a remote node via SSH
+// executeSSHCommand executes a command on a remote node via a pooled SSH session
 func (r *RemoteDockerClient) executeSSHCommand(ctx context.Context, node *RemoteNode, command string) (string, error) {
-	// Load SSH key
-	key, err := r.loadSSHKey()
-	if err != nil {
-		return "", fmt.Errorf("failed to load SSH key: %w", err)
-	}
-
-	// SSH client config
-	config := &ssh.ClientConfig{
-		User: node.SSHUser,
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(key),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // FIXME: Use proper host key verification in production
-		Timeout:         10 * time.Second,
-	}
-
-	// Connect to remote node
-	addr := fmt.Sprintf("%s:22", node.IPAddress)
-	client, err := ssh.Dial("tcp", addr, config)
-	if err != nil {
-		return "", fmt.Errorf("failed to connect to %s: %w", addr, err)
-	}
-	defer client.Close()
-
-	// Create session
-	session, err := client.NewSession()
-	if err != nil {
-		return "", fmt.Errorf("failed to create SSH session: %w", err)
-	}
-	defer session.Close()
-
-	// Capture output
-	var stdout, stderr bytes.Buffer
-	session.Stdout = &stdout
-	session.Stderr = &stderr
-
-	// Execute command with context timeout
-	// Use a channel to handle command execution with context cancellation
-	done := make(chan error, 1)
-	go func() {
-		done <- session.Run(command)
-	}()
-
-	// Wait for command to complete or context to be cancelled
-	select {
-	case <-ctx.Done():
-		// Context cancelled (timeout or manual cancellation)
-		session.Signal(ssh.SIGKILL) // Try to kill the remote process
-		session.Close()              // Close the session
-		output := stdout.String() + stderr.String()
-		return output, fmt.Errorf("command timeout/cancelled: %w (partial output: %s)", ctx.Err(), output)
-	case err := <-done:
-		// Command completed
-		output := stdout.String() + stderr.String()
-		if err != nil {
-			return output, fmt.Errorf("command failed: %w (output: %s)", err, output)
-		}
-		return output, nil
-	}
-}
-
-// loadSSHKey loads the SSH private key from disk
-func (r *RemoteDockerClient) loadSSHKey() (ssh.Signer, error) {
-	// If no path specified, try default location
-	keyPath := r.sshKeyPath
-	if keyPath == "" {
-		home := "/root" // Default on Linux
-		keyPath = filepath.Join(home, ".ssh", "id_rsa")
-	}
-
-	// Read key file
-	keyData, err := ioutil.ReadFile(keyPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read SSH key file %s: %w", keyPath, err)
-	}
-
-	// Parse private key
-	key, err := ssh.ParsePrivateKey(keyData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse SSH private key: %w", err)
-	}
-
-	return key, nil
+	return r.pool.Run(ctx, node, command)
 }
@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// SSH only transports a single command string, which the remote login shell parses.
+// Remote commands must therefore be built from an argv with ShellJoin (every argument
+// single-quoted) and all interpolated values validated first - never with Sprintf.
+
+var (
+	resourceIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+	hostnamePattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*$`)
+	safePathPattern   = regexp.MustCompile(`^/[A-Za-z0-9._/-]*$`)
+)
+
+// ShellJoin builds a remote command line from argv, quoting every argument
+func ShellJoin(argv ...string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ValidateResourceID checks that a server/backup ID is safe to use in paths and commands
+func ValidateResourceID(id string) error {
+	if !resourceIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid resource ID %q", id)
+	}
+	return nil
+}
+
+// ValidateNodeAddress checks that a node address is an IP or a plain hostname
+func ValidateNodeAddress(address string) error {
+	if net.ParseIP(address) != nil {
+		return nil
+	}
+	if len(address) <= 253 && hostnamePattern.MatchString(address) {
+		return nil
+	}
+	return fmt.Errorf("invalid node address %q", address)
+}
+
+// ValidateRemotePath checks that a path is absolute, normalized, free of shell/rsync
+// metacharacters and located below one of the allowed prefixes
+func ValidateRemotePath(p string, allowedPrefixes ...string) error {
+	if !safePathPattern.MatchString(p) {
+		return fmt.Errorf("invalid path %q", p)
+	}
+
+	// Reject "..", "." and duplicate slashes (a single trailing slash is allowed for rsync)
+	cleaned := path.Clean(p)
+	if cleaned != p && cleaned != strings.TrimSuffix(p, "/") {
+		return fmt.Errorf("path %q is not normalized", p)
+	}
+
+	if len(allowedPrefixes) == 0 {
+		return nil
+	}
+	for _, prefix := range allowedPrefixes {
+		if cleaned == path.Clean(prefix) || strings.HasPrefix(cleaned, path.Clean(prefix)+"/") {
+			return nil
+		}
+	}
+	return fmt.Errorf("path %q is outside of allowed directories", p)
+}
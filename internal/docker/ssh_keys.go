@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// authorizedKeysFile is the authorized_keys file of the node's SSH user
+const authorizedKeysFile = "~/.ssh/authorized_keys"
+
+// PublicKey returns the authorized_keys line of the key the pool authenticates with
+func (p *SSHPool) PublicKey() (string, error) {
+	signer, err := p.loadSigner()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))), nil
+}
+
+// VerifyKey opens a separate connection to node authenticated with keyData (not pooled)
+func (p *SSHPool) VerifyKey(ctx context.Context, node *RemoteNode, keyData []byte) error {
+	signer, err := ssh.ParsePrivateKey(keyData)
+	if err != nil {
+		return fmt.Errorf("failed to parse SSH private key: %w", err)
+	}
+
+	config := &ssh.ClientConfig{
+		User:            node.SSHUser,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // Same as the pooled connections
+		Timeout:         p.cfg.DialTimeout,
+	}
+	addr := fmt.Sprintf("%s:22", node.IPAddress)
+
+	result := make(chan error, 1)
+	go func() {
+		client, err := ssh.Dial("tcp", addr, config)
+		if err == nil {
+			client.Close()
+		}
+		result <- err
+	}()
+	select {
+	case err := <-result:
+		if err != nil {
+			return fmt.Errorf("new key rejected by %s: %w", addr, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AddAuthorizedKey appends an authorized_keys line on node unless it is already there
+func (r *RemoteDockerClient) AddAuthorizedKey(ctx context.Context, node *RemoteNode, authorizedKey string) error {
+	key := shellQuote(strings.TrimSpace(authorizedKey))
+	command := fmt.Sprintf("mkdir -p ~/.ssh && chmod 700 ~/.ssh && touch %[1]s && chmod 600 %[1]s && "+
+		"(grep -qxF %[2]s %[1]s || echo %[2]s >> %[1]s)", authorizedKeysFile, key)
+	if _, err := r.executeSSHCommand(ctx, node, command); err != nil {
+		return fmt.Errorf("failed to add SSH key on node %s: %w", node.ID, err)
+	}
+	return nil
+}
+
+// RemoveAuthorizedKey deletes an authorized_keys line on node
+func (r *RemoteDockerClient) RemoveAuthorizedKey(ctx context.Context, node *RemoteNode, authorizedKey string) error {
+	key := shellQuote(strings.TrimSpace(authorizedKey))
+	command := fmt.Sprintf("touch %[1]s && (grep -vxF %[2]s %[1]s > %[1]s.tmp || true) && "+
+		"chmod 600 %[1]s.tmp && mv %[1]s.tmp %[1]s", authorizedKeysFile, key)
+	if _, err := r.executeSSHCommand(ctx, node, command); err != nil {
+		return fmt.Errorf("failed to remove SSH key on node %s: %w", node.ID, err)
+	}
+	return nil
+}
+
+// PublicKey returns the authorized_keys line of the key new connections authenticate with
+func (r *RemoteDockerClient) PublicKey() (string, error) {
+	return r.pool.PublicKey()
+}
+
+// VerifyKey checks that node accepts keyData
+func (r *RemoteDockerClient) VerifyKey(ctx context.Context, node *RemoteNode, keyData []byte) error {
+	return r.pool.VerifyKey(ctx, node, keyData)
+}
@@ -0,0 +1,552 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHPoolConfig controls per-node connection pooling
+type SSHPoolConfig struct {
+	MaxSessionsPerNode int           // Concurrent sessions multiplexed over one connection (sshd MaxSessions defaults to 10)
+	KeepaliveInterval  time.Duration // How often idle connections are probed
+	IdleTimeout        time.Duration // Close connections unused for this long
+	DialTimeout        time.Duration // TCP + handshake timeout (also used for keepalive replies)
+	CommandTimeout     time.Duration // Applied to commands whose context has no deadline (0 = unbounded)
+	MaxOutputBytes     int           // Captured output per command, excess is truncated with a marker
+}
+
+// DefaultSSHPoolConfig returns sane pool defaults
+func DefaultSSHPoolConfig() SSHPoolConfig {
+	return SSHPoolConfig{
+		MaxSessionsPerNode: 8,
+		KeepaliveInterval:  30 * time.Second,
+		IdleTimeout:        5 * time.Minute,
+		DialTimeout:        10 * time.Second,
+		CommandTimeout:     5 * time.Minute,
+		MaxOutputBytes:     DefaultMaxCommandOutput,
+	}
+}
+
+// SSHPool keeps one multiplexed SSH connection per node and hands out sessions on it
+// Instead of dialing + handshaking for every command, sessions are opened as channels
+// on the cached connection. Dead connections are detected via keepalive and redialed.
+type SSHPool struct {
+	keyPath string
+	cfg     SSHPoolConfig
+
+	signerMu sync.Mutex
+	signer   ssh.Signer
+
+	mu    sync.Mutex
+	conns map[string]*pooledSSHConn
+
+	stopChan  chan struct{}
+	closeOnce sync.Once
+}
+
+// pooledSSHConn is the shared connection to a single node
+type pooledSSHConn struct {
+	key      string
+	sessions chan struct{} // Semaphore limiting concurrent sessions
+
+	mu             sync.Mutex
+	client         *ssh.Client
+	connectedSince time.Time
+	lastUsed       time.Time
+	totalSessions  int64
+	dials          int64
+	lastError      string
+}
+
+// SSHPoolStats is a snapshot of one node connection
+type SSHPoolStats struct {
+	Node           string    `json:"node"`
+	Connected      bool      `json:"connected"`
+	ActiveSessions int       `json:"active_sessions"`
+	MaxSessions    int       `json:"max_sessions"`
+	TotalSessions  int64     `json:"total_sessions"`
+	Dials          int64     `json:"dials"`
+	ConnectedSince time.Time `json:"connected_since,omitempty"`
+	LastUsed       time.Time `json:"last_used,omitempty"`
+	LastError      string    `json:"last_error,omitempty"`
+}
+
+// NewSSHPool creates a connection pool and starts its keepalive loop
+func NewSSHPool(keyPath string, cfg SSHPoolConfig) *SSHPool {
+	defaults := DefaultSSHPoolConfig()
+	if cfg.MaxSessionsPerNode <= 0 {
+		cfg.MaxSessionsPerNode = defaults.MaxSessionsPerNode
+	}
+	if cfg.KeepaliveInterval <= 0 {
+		cfg.KeepaliveInterval = defaults.KeepaliveInterval
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaults.IdleTimeout
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaults.DialTimeout
+	}
+
+	p := &SSHPool{
+		keyPath:  keyPath,
+		cfg:      cfg,
+		conns:    make(map[string]*pooledSSHConn),
+		stopChan: make(chan struct{}),
+	}
+
+	go p.keepaliveLoop()
+
+	return p
+}
+
+// Run executes a command on a node over a pooled session
+func (p *SSHPool) Run(ctx context.Context, node *RemoteNode, command string) (string, error) {
+	return p.RunWithStdin(ctx, node, command, nil)
+}
+
+// RunWithStdin executes a command on a node, streaming stdin to it (used for file transfers)
+// Commands are bounded by the context (or CommandTimeout if it has no deadline) and their
+// combined output by MaxOutputBytes.
+func (p *SSHPool) RunWithStdin(ctx context.Context, node *RemoteNode, command string, stdin io.Reader) (string, error) {
+	ctx, span := tracing.Start(ctx, "ssh "+sshCommandName(command), sshSpanAttributes(node)...)
+	output, err := p.runWithStdin(ctx, node, command, stdin)
+	tracing.End(span, err)
+	return output, err
+}
+
+// runWithStdin executes a command on a node (see RunWithStdin)
+func (p *SSHPool) runWithStdin(ctx context.Context, node *RemoteNode, command string, stdin io.Reader) (string, error) {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline && p.cfg.CommandTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.cfg.CommandTimeout)
+		defer cancel()
+	}
+
+	session, release, err := p.NewSession(ctx, node)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	output := NewLimitedBuffer(p.cfg.MaxOutputBytes)
+	session.Stdout = output
+	session.Stderr = output
+	if stdin != nil {
+		session.Stdin = stdin
+	}
+
+	// Run in goroutine so context cancellation can interrupt the command
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Run(command)
+	}()
+
+	select {
+	case <-ctx.Done():
+		session.Signal(ssh.SIGKILL) // Try to kill the remote process
+		session.Close()
+		partial := output.String()
+		return partial, fmt.Errorf("command timeout/cancelled: %w (partial output: %s)", ctx.Err(), partial)
+	case err := <-done:
+		result := output.String()
+		if output.Truncated() {
+			log.Printf("[SSHPool] Output of command on %s truncated to %d bytes", node.IPAddress, p.cfg.MaxOutputBytes)
+		}
+		if err != nil {
+			return result, fmt.Errorf("command failed: %w (output: %s)", err, result)
+		}
+		return result, nil
+	}
+}
+
+// Upload copies a local file to a path on the node over a pooled session
+func (p *SSHPool) Upload(ctx context.Context, node *RemoteNode, localPath, remotePath string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", localPath, err)
+	}
+	defer file.Close()
+
+	if err := p.UploadFrom(ctx, node, file, remotePath); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", localPath, err)
+	}
+	return nil
+}
+
+// UploadFrom streams r to a path on the node over a pooled session
+// (wrap r to observe progress)
+func (p *SSHPool) UploadFrom(ctx context.Context, node *RemoteNode, r io.Reader, remotePath string) error {
+	command := fmt.Sprintf("cat > %s", shellQuote(remotePath))
+	if _, err := p.RunWithStdin(ctx, node, command, r); err != nil {
+		return fmt.Errorf("failed to upload to %s:%s: %w", node.IPAddress, remotePath, err)
+	}
+	return nil
+}
+
+// Download streams a file on the node to w over a pooled session
+// Unlike Run the output is not buffered or size-limited; bound large transfers with the context.
+func (p *SSHPool) Download(ctx context.Context, node *RemoteNode, remotePath string, w io.Writer) error {
+	ctx, span := tracing.Start(ctx, "ssh download", sshSpanAttributes(node)...)
+	err := p.download(ctx, node, remotePath, w)
+	tracing.End(span, err)
+	return err
+}
+
+// download streams a file on the node to w (see Download)
+func (p *SSHPool) download(ctx context.Context, node *RemoteNode, remotePath string, w io.Writer) error {
+	session, release, err := p.NewSession(ctx, node)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	stderr := NewLimitedBuffer(4096)
+	session.Stdout = w
+	session.Stderr = stderr
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Run(ShellJoin("cat", "--", remotePath))
+	}()
+
+	select {
+	case <-ctx.Done():
+		session.Signal(ssh.SIGKILL)
+		session.Close()
+		return fmt.Errorf("download of %s:%s cancelled: %w", node.IPAddress, remotePath, ctx.Err())
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to download %s:%s: %w (output: %s)", node.IPAddress, remotePath, err, stderr.String())
+		}
+		return nil
+	}
+}
+
+// sshSpanAttributes describe the node of an SSH span
+func sshSpanAttributes(node *RemoteNode) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("node.id", node.ID),
+		attribute.String("node.ip", node.IPAddress),
+	}
+}
+
+// sshCommandName names the span of a command by its program ("docker run", "cat") without
+// arguments, which may contain secrets like RCON passwords
+func sshCommandName(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "session"
+	}
+	if fields[0] == "docker" && len(fields) > 1 {
+		return "docker " + fields[1]
+	}
+	return filepath.Base(fields[0])
+}
+
+// NewSession opens a session on the node's shared connection
+// The returned release func must be called when the session is no longer needed.
+func (p *SSHPool) NewSession(ctx context.Context, node *RemoteNode) (*ssh.Session, func(), error) {
+	conn := p.entry(node)
+
+	// Wait for a free session slot on this node
+	select {
+	case conn.sessions <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, fmt.Errorf("waiting for SSH session slot on %s: %w", conn.key, ctx.Err())
+	}
+	releaseSlot := func() {
+		conn.mu.Lock()
+		conn.lastUsed = time.Now()
+		conn.mu.Unlock()
+		<-conn.sessions
+	}
+
+	client, err := p.clientFor(conn, node)
+	if err != nil {
+		releaseSlot()
+		return nil, nil, err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		// Cached connection went stale - drop it and redial once
+		p.dropClient(conn, client, err)
+
+		client, err = p.clientFor(conn, node)
+		if err != nil {
+			releaseSlot()
+			return nil, nil, err
+		}
+		session, err = client.NewSession()
+		if err != nil {
+			p.dropClient(conn, client, err)
+			releaseSlot()
+			return nil, nil, fmt.Errorf("failed to create SSH session: %w", err)
+		}
+	}
+
+	conn.mu.Lock()
+	conn.totalSessions++
+	conn.mu.Unlock()
+
+	release := func() {
+		session.Close()
+		releaseSlot()
+	}
+
+	return session, release, nil
+}
+
+// Stats returns a snapshot of all node connections
+func (p *SSHPool) Stats() []SSHPoolStats {
+	p.mu.Lock()
+	conns := make([]*pooledSSHConn, 0, len(p.conns))
+	for _, conn := range p.conns {
+		conns = append(conns, conn)
+	}
+	p.mu.Unlock()
+
+	stats := make([]SSHPoolStats, 0, len(conns))
+	for _, conn := range conns {
+		conn.mu.Lock()
+		stats = append(stats, SSHPoolStats{
+			Node:           conn.key,
+			Connected:      conn.client != nil,
+			ActiveSessions: len(conn.sessions),
+			MaxSessions:    cap(conn.sessions),
+			TotalSessions:  conn.totalSessions,
+			Dials:          conn.dials,
+			ConnectedSince: conn.connectedSince,
+			LastUsed:       conn.lastUsed,
+			LastError:      conn.lastError,
+		})
+		conn.mu.Unlock()
+	}
+
+	return stats
+}
+
+// Close stops the keepalive loop and closes all connections
+func (p *SSHPool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.stopChan)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for _, conn := range p.conns {
+			conn.mu.Lock()
+			if conn.client != nil {
+				conn.client.Close()
+				conn.client = nil
+			}
+			conn.mu.Unlock()
+		}
+	})
+}
+
+// entry returns (or creates) the pool entry for a node
+func (p *SSHPool) entry(node *RemoteNode) *pooledSSHConn {
+	key := fmt.Sprintf("%s@%s:22", node.SSHUser, node.IPAddress)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn, exists := p.conns[key]
+	if !exists {
+		conn = &pooledSSHConn{
+			key:      key,
+			sessions: make(chan struct{}, p.cfg.MaxSessionsPerNode),
+		}
+		p.conns[key] = conn
+	}
+	return conn
+}
+
+// clientFor returns the node's cached connection, dialing if necessary
+func (p *SSHPool) clientFor(conn *pooledSSHConn, node *RemoteNode) (*ssh.Client, error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.client != nil {
+		return conn.client, nil
+	}
+
+	signer, err := p.loadSigner()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SSH key: %w", err)
+	}
+
+	config := &ssh.ClientConfig{
+		User: node.SSHUser,
+		Auth: []ssh.AuthMethod{
+			ssh.PublicKeys(signer),
+		},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // FIXME: Use proper host key verification in production
+		Timeout:         p.cfg.DialTimeout,
+	}
+
+	addr := fmt.Sprintf("%s:22", node.IPAddress)
+	client, err := ssh.Dial("tcp", addr, config)
+	if err != nil {
+		conn.lastError = err.Error()
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	conn.client = client
+	conn.connectedSince = time.Now()
+	conn.lastUsed = time.Now()
+	conn.dials++
+	conn.lastError = ""
+
+	// Forget the connection as soon as the transport dies
+	go func() {
+		err := client.Wait()
+		conn.mu.Lock()
+		if conn.client == client {
+			conn.client = nil
+			if err != nil {
+				conn.lastError = err.Error()
+			}
+		}
+		conn.mu.Unlock()
+	}()
+
+	return client, nil
+}
+
+// dropClient closes a connection if it is still the cached one
+func (p *SSHPool) dropClient(conn *pooledSSHConn, client *ssh.Client, reason error) {
+	conn.mu.Lock()
+	if conn.client == client {
+		conn.client = nil
+		if reason != nil {
+			conn.lastError = reason.Error()
+		}
+	}
+	conn.mu.Unlock()
+
+	client.Close()
+	log.Printf("[SSHPool] Dropped connection to %s: %v", conn.key, reason)
+}
+
+// keepaliveLoop probes connections and closes idle ones
+func (p *SSHPool) keepaliveLoop() {
+	ticker := time.NewTicker(p.cfg.KeepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopChan:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			conns := make([]*pooledSSHConn, 0, len(p.conns))
+			for _, conn := range p.conns {
+				conns = append(conns, conn)
+			}
+			p.mu.Unlock()
+
+			for _, conn := range conns {
+				p.checkConn(conn)
+			}
+		}
+	}
+}
+
+// checkConn closes an idle connection or verifies a busy one is still alive
+func (p *SSHPool) checkConn(conn *pooledSSHConn) {
+	conn.mu.Lock()
+	client := conn.client
+	idle := len(conn.sessions) == 0 && time.Since(conn.lastUsed) > p.cfg.IdleTimeout
+	conn.mu.Unlock()
+
+	if client == nil {
+		return
+	}
+
+	if idle {
+		conn.mu.Lock()
+		if conn.client == client {
+			conn.client = nil
+		}
+		conn.mu.Unlock()
+		client.Close()
+		return
+	}
+
+	// SendRequest blocks on a dead transport, so bound it with the dial timeout
+	result := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			p.dropClient(conn, client, fmt.Errorf("keepalive failed: %w", err))
+		}
+	case <-time.After(p.cfg.DialTimeout):
+		p.dropClient(conn, client, fmt.Errorf("keepalive timed out after %s", p.cfg.DialTimeout))
+	}
+}
+
+// loadSigner loads and caches the SSH private key
+func (p *SSHPool) loadSigner() (ssh.Signer, error) {
+	p.signerMu.Lock()
+	defer p.signerMu.Unlock()
+
+	if p.signer != nil {
+		return p.signer, nil
+	}
+
+	// If no path specified, try default location
+	keyPath := p.keyPath
+	if keyPath == "" {
+		home := "/root" // Default on Linux
+		keyPath = filepath.Join(home, ".ssh", "id_rsa")
+	}
+
+	keyData, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSH key file %s: %w", keyPath, err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(keyData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH private key: %w", err)
+	}
+
+	p.signer = signer
+	return signer, nil
+}
+
+// SetPrivateKey replaces the SSH key (rotated in the secret store). New connections use it;
+// established connections stay open, they were authenticated with the previous key.
+func (p *SSHPool) SetPrivateKey(keyData []byte) error {
+	signer, err := ssh.ParsePrivateKey(keyData)
+	if err != nil {
+		return fmt.Errorf("failed to parse SSH private key: %w", err)
+	}
+
+	p.signerMu.Lock()
+	p.signer = signer
+	p.signerMu.Unlock()
+	return nil
+}
+
+// shellQuote wraps a value in single quotes for safe use in a remote shell command
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,61 @@
+package events
+
+// CatalogEntry is an event type customers can subscribe webhook endpoints to
+type CatalogEntry struct {
+	Type        EventType `json:"type"`
+	Category    string    `json:"category"`
+	Description string    `json:"description"`
+}
+
+// Catalog lists the events delivered to webhook endpoints, grouped by category. System events
+// (nodes, scaling) are internal and not part of it.
+var Catalog = []CatalogEntry{
+	{EventServerCreated, "server", "Server was created"},
+	{EventServerStarted, "server", "Server started and is joinable"},
+	{EventServerStartFailed, "server", "Server failed to start"},
+	{EventServerStopped, "server", "Server stopped"},
+	{EventServerDeleted, "server", "Server was deleted"},
+	{EventServerCrashed, "server", "Server crashed"},
+	{EventServerRestarted, "server", "Server was restarted"},
+	{EventServerDiskQuota, "server", "Server directory is near or over its disk quota"},
+	{EventServerLowTPS, "server", "Server ticks stayed below the TPS threshold"},
+	{EventPlayerJoined, "player", "Player joined a server"},
+	{EventPlayerLeft, "player", "Player left a server"},
+	{EventBackupCreated, "backup", "Backup was created"},
+	{EventBackupRestored, "backup", "Backup was restored"},
+	{EventBackupFailed, "backup", "Backup failed"},
+	{EventWorldPregenerated, "world", "Chunk pre-generation finished"},
+	{EventMigrationCompleted, "migration", "Server was moved to another node"},
+	{EventMigrationFailed, "migration", "Moving a server to another node failed"},
+	{EventBillingPhaseChanged, "billing", "Server changed its billing phase (active, sleep, archived)"},
+	{EventBillingBudgetAlert, "billing", "Budget cap reached 50, 80 or 100 percent"},
+	{EventSecurityPrefix + "login_failure", "security", "Failed login to the account"},
+	{EventSecurityPrefix + "login_new_device", "security", "Login from a new device"},
+	{EventSecurityPrefix + "password_changed", "security", "Password was changed"},
+	{EventSecurityPrefix + "password_reset_success", "security", "Password was reset"},
+	{EventSecurityPrefix + "email_changed", "security", "Email address was changed"},
+	{EventSecurityPrefix + "account_locked", "security", "Account was locked after failed logins"},
+	{EventSecurityPrefix + "two_factor_enabled", "security", "Two-factor authentication was enabled"},
+	{EventSecurityPrefix + "two_factor_disabled", "security", "Two-factor authentication was disabled"},
+	{EventSecurityPrefix + "recovery_code_used", "security", "Two-factor recovery code was used"},
+}
+
+// CatalogCategory returns the category of an event type ("" if it's not in the catalog)
+func CatalogCategory(eventType EventType) string {
+	for _, entry := range Catalog {
+		if entry.Type == eventType {
+			return entry.Category
+		}
+	}
+	return ""
+}
+
+// IsCatalogCategory returns true if category is one of the catalog's categories
+func IsCatalogCategory(category string) bool {
+	for _, entry := range Catalog {
+		if entry.Category == category {
+			return true
+		}
+	}
+	return false
+}
@@ -81,6 +81,20 @@ func PublishNodeStats(nodeID string, allocatedRAMMB, freeRAMMB, containerCount i
 	DashboardEventPublisher.PublishEvent("node.stats", data)
 }
 
+// PublishDashboardNodeHealth publishes a node health transition with its reasons to the dashboard
+func PublishDashboardNodeHealth(nodeID, oldStatus, newStatus string, reasons []string) {
+	if DashboardEventPublisher == nil {
+		return
+	}
+
+	DashboardEventPublisher.PublishEvent("node.health", map[string]interface{}{
+		"node_id":    nodeID,
+		"old_status": oldStatus,
+		"new_status": newStatus,
+		"reasons":    reasons,
+	})
+}
+
 // PublishDashboardNodeRemoved publishes a node removal event to dashboard
 func PublishDashboardNodeRemoved(nodeID string, reason string) {
 	if DashboardEventPublisher == nil {
@@ -403,3 +417,109 @@ func PublishServerDequeued(serverID, serverName string) {
 		"server_id": serverID,
 	})
 }
+
+// TransferProgressEventType is the event type of byte-level transfer progress
+const TransferProgressEventType = "operation.transfer.progress"
+
+// PublishTransferProgress publishes byte-level progress of a backup, restore, migration or archive transfer
+// data carries operation, operation_id, server_id, phase, bytes_done, bytes_total, throughput_bps and eta_s
+func PublishTransferProgress(data map[string]interface{}) {
+	if DashboardEventPublisher == nil {
+		return
+	}
+
+	DashboardEventPublisher.PublishEvent(TransferProgressEventType, data)
+}
+
+// OperationStatusEventType is the event type of per-owner operation queue changes
+const OperationStatusEventType = "operation.status"
+
+// PublishOperationStatus publishes a status change of a limited operation (queued, running, completed, failed, cancelled)
+// data carries operation_id, owner_id, kind, server_id, status and position (1-based, queued only)
+func PublishOperationStatus(data map[string]interface{}) {
+	if DashboardEventPublisher == nil {
+		return
+	}
+
+	DashboardEventPublisher.PublishEvent(OperationStatusEventType, data)
+}
+
+// PublishControlPlaneStats publishes a resource sample of the control plane host
+// data carries level, sampled_at and resources (name, percent, level per resource)
+func PublishControlPlaneStats(data map[string]interface{}) {
+	if DashboardEventPublisher == nil {
+		return
+	}
+
+	DashboardEventPublisher.PublishEvent("control_plane.stats", data)
+}
+
+// PublishControlPlaneAlert publishes a resource alert (or its resolution) of the control plane host
+func PublishControlPlaneAlert(resource, level string, percent float64, threshold int, message string) {
+	if DashboardEventPublisher == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"resource":  resource,
+		"level":     level,
+		"percent":   percent,
+		"threshold": threshold,
+		"message":   message,
+	}
+
+	DashboardEventPublisher.PublishEvent("control_plane.alert", data)
+	logger.Info("Dashboard event published: control_plane.alert", map[string]interface{}{
+		"resource": resource,
+		"level":    level,
+	})
+}
+
+// PublishPrometheusAlert publishes a firing or resolved Prometheus alert (Alertmanager webhook)
+func PublishPrometheusAlert(alertName, status, severity, component, summary, description string, labels map[string]string) {
+	if DashboardEventPublisher == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"alert":       alertName,
+		"status":      status,
+		"severity":    severity,
+		"component":   component,
+		"summary":     summary,
+		"description": description,
+		"labels":      labels,
+	}
+
+	DashboardEventPublisher.PublishEvent("prometheus.alert", data)
+	logger.Info("Dashboard event published: prometheus.alert", map[string]interface{}{
+		"alert":  alertName,
+		"status": status,
+	})
+}
+
+// BulkJobProgressEventType is the event type of bulk job progress (rolling restarts, plugin and config rollouts)
+const BulkJobProgressEventType = "bulk_job.progress"
+
+// PublishBulkJobProgress publishes the progress of a bulk job after every server
+// data carries job_id, user_id, action, status, total, done, succeeded, failed, progress and item (the server that changed)
+func PublishBulkJobProgress(data map[string]interface{}) {
+	if DashboardEventPublisher == nil {
+		return
+	}
+
+	DashboardEventPublisher.PublishEvent(BulkJobProgressEventType, data)
+}
+
+// PregenerationProgressEventType is the event type of chunk pre-generation progress
+const PregenerationProgressEventType = "pregeneration.progress"
+
+// PublishPregenerationProgress publishes the status and progress of a chunk pre-generation job
+// data carries job_id, server_id, owner_id, status, progress, tps and error
+func PublishPregenerationProgress(data map[string]interface{}) {
+	if DashboardEventPublisher == nil {
+		return
+	}
+
+	DashboardEventPublisher.PublishEvent(PregenerationProgressEventType, data)
+}
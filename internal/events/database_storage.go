@@ -68,8 +68,12 @@ func (s *DatabaseEventStorage) Query(filters EventFilters) ([]Event, error) {
 		query = query.Where("timestamp <= ?", filters.EndTime)
 	}
 
-	// Order by timestamp descending
-	query = query.Order("timestamp DESC")
+	// Newest first unless ascending is requested (the limit then keeps the oldest events)
+	if filters.Ascending {
+		query = query.Order("timestamp ASC")
+	} else {
+		query = query.Order("timestamp DESC")
+	}
 
 	// Apply limit
 	if filters.Limit > 0 {
@@ -1,9 +1,11 @@
 package events
 
 import (
+	"encoding/json"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
@@ -20,6 +22,8 @@ const (
 	EventServerCrashed       EventType = "server.crashed"
 	EventServerRestarted     EventType = "server.restarted"
 	EventServerStateChanged  EventType = "server.state_changed"
+	EventServerDiskQuota     EventType = "server.disk_quota"
+	EventServerLowTPS        EventType = "server.low_tps"
 
 	// Player events
 	EventPlayerJoined        EventType = "player.joined"
@@ -30,6 +34,7 @@ const (
 	EventBillingStarted      EventType = "billing.started"
 	EventBillingStopped      EventType = "billing.stopped"
 	EventBillingPhaseChanged EventType = "billing.phase_changed"
+	EventBillingBudgetAlert  EventType = "billing.budget_alert"
 
 	// Backup events
 	EventBackupCreated       EventType = "backup.created"
@@ -37,6 +42,16 @@ const (
 	EventBackupDeleted       EventType = "backup.deleted"
 	EventBackupFailed        EventType = "backup.failed"
 
+	// World events
+	EventWorldPregenerated   EventType = "world.pregenerated"
+
+	// Migration events
+	EventMigrationCompleted  EventType = "migration.completed"
+	EventMigrationFailed     EventType = "migration.failed"
+
+	// Security events are "security.<models.SecurityEventType>", e.g. "security.login_failure"
+	EventSecurityPrefix      = "security."
+
 	// System events
 	EventNodeAdded           EventType = "node.added"
 	EventNodeRemoved         EventType = "node.removed"
@@ -59,10 +74,15 @@ type Event struct {
 type EventHandler func(event Event)
 
 // EventBus manages event publishing and subscription
+// With a transport (multi-instance API), Subscribe handlers still run exactly once cluster-wide,
+// on the instance that published the event (billing relies on this), while SubscribeAll handlers
+// also receive events published by other instances.
 type EventBus struct {
-	subscribers map[EventType][]EventHandler
-	mu          sync.RWMutex
-	storage     EventStorage
+	subscribers    map[EventType][]EventHandler
+	allSubscribers map[EventType][]EventHandler
+	mu             sync.RWMutex
+	storage        EventStorage
+	relay          *Relay // Optional: fans events out to other API instances
 }
 
 // EventStorage defines the interface for storing events
@@ -79,6 +99,7 @@ type EventFilters struct {
 	StartTime time.Time
 	EndTime   time.Time
 	Limit     int
+	Ascending bool // Oldest events first (the limit keeps the oldest); default is newest first
 }
 
 var (
@@ -102,11 +123,26 @@ func SetEventStorage(storage EventStorage) {
 	bus.storage = storage
 }
 
+// SetEventTransport shares events with other API instances over transport
+func SetEventTransport(transport Transport) error {
+	bus := GetEventBus()
+	relay, err := NewRelay(transport, SubjectEvents, bus.deliverRemote)
+	if err != nil {
+		return err
+	}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.relay = relay
+	return nil
+}
+
 // NewEventBus creates a new event bus
 func NewEventBus(storage EventStorage) *EventBus {
 	return &EventBus{
-		subscribers: make(map[EventType][]EventHandler),
-		storage:     storage,
+		subscribers:    make(map[EventType][]EventHandler),
+		allSubscribers: make(map[EventType][]EventHandler),
+		storage:        storage,
 	}
 }
 
@@ -121,6 +157,19 @@ func (eb *EventBus) Subscribe(eventType EventType, handler EventHandler) {
 	})
 }
 
+// SubscribeAll registers a handler that also receives events published by other API instances
+// Use it for per-instance state (caches, local connections); use Subscribe for side effects
+// that must happen once, such as billing.
+func (eb *EventBus) SubscribeAll(eventType EventType, handler EventHandler) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.allSubscribers[eventType] = append(eb.allSubscribers[eventType], handler)
+	logger.Info("Event handler subscribed (all instances)", map[string]interface{}{
+		"event_type": eventType,
+	})
+}
+
 // Publish publishes an event to all subscribers
 func (eb *EventBus) Publish(event Event) {
 	// Set timestamp if not set
@@ -133,7 +182,7 @@ func (eb *EventBus) Publish(event Event) {
 		event.ID = generateEventID()
 	}
 
-	// Store event if storage is configured
+	// Store event if storage is configured (only the publishing instance stores it)
 	if eb.storage != nil {
 		if err := eb.storage.Store(event); err != nil {
 			logger.Error("Failed to store event", err, map[string]interface{}{
@@ -145,9 +194,45 @@ func (eb *EventBus) Publish(event Event) {
 
 	// Notify subscribers
 	eb.mu.RLock()
-	handlers := eb.subscribers[event.Type]
+	handlers := append(append([]EventHandler{}, eb.subscribers[event.Type]...), eb.allSubscribers[event.Type]...)
+	relay := eb.relay
+	eb.mu.RUnlock()
+
+	eb.dispatch(event, handlers)
+
+	// Share with other API instances
+	if relay != nil {
+		if payload, err := json.Marshal(event); err == nil {
+			relay.Publish(payload)
+		}
+	}
+
+	logger.Info("Event published", map[string]interface{}{
+		"event_id":   event.ID,
+		"event_type": event.Type,
+		"source":     event.Source,
+	})
+}
+
+// deliverRemote hands an event published by another instance to SubscribeAll handlers
+func (eb *EventBus) deliverRemote(payload []byte) {
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		logger.Warn("Failed to decode remote event", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	eb.mu.RLock()
+	handlers := eb.allSubscribers[event.Type]
 	eb.mu.RUnlock()
 
+	eb.dispatch(event, handlers)
+}
+
+// dispatch runs handlers for an event
+func (eb *EventBus) dispatch(event Event, handlers []EventHandler) {
 	for _, handler := range handlers {
 		// Run handlers in goroutines to avoid blocking
 		go func(h EventHandler) {
@@ -162,12 +247,6 @@ func (eb *EventBus) Publish(event Event) {
 			h(event)
 		}(handler)
 	}
-
-	logger.Info("Event published", map[string]interface{}{
-		"event_id":   event.ID,
-		"event_type": event.Type,
-		"source":     event.Source,
-	})
 }
 
 // Query retrieves events based on filters
@@ -179,15 +258,7 @@ func (eb *EventBus) Query(filters EventFilters) ([]Event, error) {
 }
 
 // generateEventID generates a unique event ID
+// The random part must be unique across API instances publishing at the same second.
 func generateEventID() string {
-	return time.Now().Format("20060102150405") + "-" + randomString(8)
-}
-
-func randomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = charset[time.Now().UnixNano()%int64(len(charset))]
-	}
-	return string(b)
+	return time.Now().Format("20060102150405") + "-" + uuid.NewString()[:8]
 }
@@ -41,6 +41,7 @@ func (s *InfluxDBEventStorage) Query(filters EventFilters) ([]Event, error) {
 		StartTime: filters.StartTime,
 		EndTime:   filters.EndTime,
 		Limit:     filters.Limit,
+		Ascending: filters.Ascending,
 	}
 
 	// Convert event types
@@ -0,0 +1,26 @@
+package events
+
+import "github.com/payperplay/hosting/internal/models"
+
+// Outbox records of billing-relevant events
+// Written in the same transaction as the status change; the matching Publish* call follows the commit.
+
+// ServerStartedOutboxEvent is the outbox record of PublishServerStarted
+func ServerStartedOutboxEvent(serverID, userID string) *models.OutboxEvent {
+	return models.NewOutboxEvent(string(EventServerStarted), serverID, userID, map[string]interface{}{})
+}
+
+// ServerStoppedOutboxEvent is the outbox record of PublishServerStopped
+func ServerStoppedOutboxEvent(serverID, reason string) *models.OutboxEvent {
+	return models.NewOutboxEvent(string(EventServerStopped), serverID, "", map[string]interface{}{
+		"reason": reason,
+	})
+}
+
+// BillingPhaseChangedOutboxEvent is the outbox record of PublishBillingPhaseChanged
+func BillingPhaseChangedOutboxEvent(serverID, oldPhase, newPhase string) *models.OutboxEvent {
+	return models.NewOutboxEvent(string(EventBillingPhaseChanged), serverID, "", map[string]interface{}{
+		"old_phase": oldPhase,
+		"new_phase": newPhase,
+	})
+}
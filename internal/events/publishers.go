@@ -148,6 +148,50 @@ func PublishBackupFailed(serverID, errorMessage string) {
 	})
 }
 
+// PublishWorldPregenerated publishes the completion of a chunk pre-generation
+func PublishWorldPregenerated(serverID, userID, jobID, dimension string, radius int) {
+	GetEventBus().Publish(Event{
+		Type:     EventWorldPregenerated,
+		Source:   "pregeneration_service",
+		ServerID: serverID,
+		UserID:   userID,
+		Data: map[string]interface{}{
+			"job_id":    jobID,
+			"dimension": dimension,
+			"radius":    radius,
+		},
+	})
+}
+
+// PublishServerDiskQuota publishes that a server directory crossed a warning threshold of its disk quota
+func PublishServerDiskQuota(serverID, userID string, percent int, usageBytes, quotaBytes int64) {
+	GetEventBus().Publish(Event{
+		Type:     EventServerDiskQuota,
+		Source:   "disk_quota_service",
+		ServerID: serverID,
+		UserID:   userID,
+		Data: map[string]interface{}{
+			"percent":     percent,
+			"usage_bytes": usageBytes,
+			"quota_bytes": quotaBytes,
+		},
+	})
+}
+
+// PublishServerLowTPS publishes that a server's TPS stayed below the alert threshold
+func PublishServerLowTPS(serverID, userID string, tps, mspt float64) {
+	GetEventBus().Publish(Event{
+		Type:     EventServerLowTPS,
+		Source:   "performance_service",
+		ServerID: serverID,
+		UserID:   userID,
+		Data: map[string]interface{}{
+			"tps":  tps,
+			"mspt": mspt,
+		},
+	})
+}
+
 // PublishBillingPhaseChanged publishes a billing phase change event
 func PublishBillingPhaseChanged(serverID, oldPhase, newPhase string) {
 	GetEventBus().Publish(Event{
@@ -161,6 +205,68 @@ func PublishBillingPhaseChanged(serverID, oldPhase, newPhase string) {
 	})
 }
 
+// PublishBudgetAlert publishes a budget cap threshold crossing (50/80/100%)
+func PublishBudgetAlert(serverID, userID, scope string, percent int, accruedEUR, limitEUR float64, action string) {
+	GetEventBus().Publish(Event{
+		Type:     EventBillingBudgetAlert,
+		Source:   "billing_service",
+		ServerID: serverID,
+		UserID:   userID,
+		Data: map[string]interface{}{
+			"scope":       scope,
+			"percent":     percent,
+			"accrued_eur": accruedEUR,
+			"limit_eur":   limitEUR,
+			"action":      action,
+		},
+	})
+}
+
+// PublishServerMigrated publishes a completed migration of a server to another node
+func PublishServerMigrated(serverID, migrationID, fromNode, toNode, reason string, durationSeconds int) {
+	GetEventBus().Publish(Event{
+		Type:     EventMigrationCompleted,
+		Source:   "migration_service",
+		ServerID: serverID,
+		Data: map[string]interface{}{
+			"operation_id":     migrationID,
+			"from_node":        fromNode,
+			"to_node":          toNode,
+			"reason":           reason,
+			"duration_seconds": durationSeconds,
+		},
+	})
+}
+
+// PublishServerMigrationFailed publishes a failed migration (the server stays on its node)
+func PublishServerMigrationFailed(serverID, migrationID, fromNode, toNode, errorMessage string) {
+	GetEventBus().Publish(Event{
+		Type:     EventMigrationFailed,
+		Source:   "migration_service",
+		ServerID: serverID,
+		Data: map[string]interface{}{
+			"operation_id": migrationID,
+			"from_node":    fromNode,
+			"to_node":      toNode,
+			"error":        errorMessage,
+		},
+	})
+}
+
+// PublishSecurityEvent publishes a security event of a user account (login, password, 2FA, ...)
+func PublishSecurityEvent(userID, eventType, ipAddress string, success bool, reason string) {
+	GetEventBus().Publish(Event{
+		Type:   EventType(EventSecurityPrefix + eventType),
+		Source: "security_service",
+		UserID: userID,
+		Data: map[string]interface{}{
+			"ip_address": ipAddress,
+			"success":    success,
+			"reason":     reason,
+		},
+	})
+}
+
 // PublishScalingTriggered publishes a scaling triggered event
 func PublishScalingTriggered(reason string, nodeCount int, action string) {
 	GetEventBus().Publish(Event{
@@ -211,8 +317,8 @@ func PublishNodeRemoved(nodeID, reason string) {
 	})
 }
 
-// PublishNodeHealthChanged publishes a node health change event
-func PublishNodeHealthChanged(nodeID, oldStatus, newStatus string) {
+// PublishNodeHealthChanged publishes a node health change event with the failed health signals
+func PublishNodeHealthChanged(nodeID, oldStatus, newStatus string, reasons []string) {
 	GetEventBus().Publish(Event{
 		Type:   EventNodeHealthChanged,
 		Source: "health_checker",
@@ -220,6 +326,7 @@ func PublishNodeHealthChanged(nodeID, oldStatus, newStatus string) {
 			"node_id":    nodeID,
 			"old_status": oldStatus,
 			"new_status": newStatus,
+			"reasons":    reasons,
 		},
 	})
 }
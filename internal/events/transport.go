@@ -0,0 +1,107 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// Transport fans messages out to every API instance (multi-replica deployments)
+// Implementations deliver each published payload to the subscribers of all instances,
+// including the publishing one; Relay filters out an instance's own messages.
+type Transport interface {
+	Publish(subject string, payload []byte) error
+	Subscribe(subject string, handler func(payload []byte)) error
+	Close() error
+}
+
+// Subjects shared between API instances
+const (
+	SubjectEvents    = "events"       // EventBus events
+	SubjectWebSocket = "ws.broadcast" // Server WebSocket hub broadcasts
+	SubjectDashboard = "ws.dashboard" // Admin dashboard WebSocket events
+)
+
+// Supported transport backends
+const (
+	TransportNATS  = "nats"
+	TransportRedis = "redis"
+)
+
+// InstanceID identifies this API process on the transport
+var InstanceID = uuid.NewString()
+
+// NewTransport creates a transport for the given backend ("nats" or "redis")
+func NewTransport(backend, url string) (Transport, error) {
+	switch backend {
+	case TransportNATS:
+		return NewNATSTransport(url)
+	case TransportRedis:
+		return NewRedisStreamTransport(url)
+	default:
+		return nil, fmt.Errorf("unknown event transport %q (supported: %s, %s)", backend, TransportNATS, TransportRedis)
+	}
+}
+
+// relayEnvelope wraps relayed payloads with the publishing instance
+type relayEnvelope struct {
+	Origin  string          `json:"origin"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Relay publishes local payloads to the other instances and delivers theirs locally
+// A nil Relay is a no-op, so callers don't need to check whether a transport is configured.
+type Relay struct {
+	transport Transport
+	subject   string
+}
+
+// NewRelay subscribes deliver to payloads published on subject by other instances
+func NewRelay(transport Transport, subject string, deliver func(payload []byte)) (*Relay, error) {
+	err := transport.Subscribe(subject, func(data []byte) {
+		var envelope relayEnvelope
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			logger.Warn("EVENT-TRANSPORT: Dropping malformed message", map[string]interface{}{
+				"subject": subject,
+				"error":   err.Error(),
+			})
+			return
+		}
+
+		// Our own messages were already delivered locally
+		if envelope.Origin == InstanceID {
+			return
+		}
+
+		deliver(envelope.Payload)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+
+	return &Relay{transport: transport, subject: subject}, nil
+}
+
+// Publish sends a JSON payload to the other instances
+func (r *Relay) Publish(payload []byte) {
+	if r == nil {
+		return
+	}
+
+	data, err := json.Marshal(relayEnvelope{Origin: InstanceID, Payload: payload})
+	if err != nil {
+		logger.Error("EVENT-TRANSPORT: Failed to encode message", err, map[string]interface{}{
+			"subject": r.subject,
+		})
+		return
+	}
+
+	if err := r.transport.Publish(r.subject, data); err != nil {
+		logger.Warn("EVENT-TRANSPORT: Failed to publish message", map[string]interface{}{
+			"subject": r.subject,
+			"error":   err.Error(),
+		})
+	}
+}
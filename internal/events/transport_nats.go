@@ -0,0 +1,313 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	natsSubjectPrefix   = "payperplay."
+	natsDialTimeout     = 5 * time.Second
+	natsWriteTimeout    = 5 * time.Second // A stalled server must not block publishers holding the lock
+	natsReconnectDelay  = 2 * time.Second
+	natsMaxReconnectGap = 30 * time.Second
+)
+
+// NATSTransport relays messages over NATS core pub/sub (text protocol, no client library)
+// It only needs CONNECT, PUB, SUB and PING/PONG, which keeps nats.go and its dependencies out of the API.
+// Delivery is at-most-once: messages published while an instance is disconnected are lost,
+// which is fine for live events and WebSocket broadcasts.
+type NATSTransport struct {
+	addr     string
+	user     string
+	pass     string
+	token    string
+	mu       sync.Mutex // Guards conn, writer and subs; never held while dialing
+	conn     net.Conn
+	writer   *bufio.Writer
+	subs     map[string]func(payload []byte) // sid -> handler
+	subjects map[string]string               // sid -> subject
+	nextSID  int
+	closed   bool
+}
+
+// NewNATSTransport connects to a NATS server (nats://[user:pass@|token@]host:4222)
+func NewNATSTransport(rawURL string) (*NATSTransport, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS URL: %w", err)
+	}
+	if u.Scheme != "nats" {
+		return nil, fmt.Errorf("unsupported NATS URL scheme %q (expected nats://)", u.Scheme)
+	}
+
+	t := &NATSTransport{
+		addr:     u.Host,
+		subs:     make(map[string]func(payload []byte)),
+		subjects: make(map[string]string),
+	}
+	if u.Port() == "" {
+		t.addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			t.user, t.pass = u.User.Username(), pass
+		} else {
+			t.token = u.User.Username()
+		}
+	}
+
+	if err := t.connect(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("EVENT-TRANSPORT: Connected to NATS", map[string]interface{}{
+		"addr":        t.addr,
+		"instance_id": InstanceID,
+	})
+
+	return t, nil
+}
+
+// Publish sends a payload to all subscribers of subject
+func (t *NATSTransport) Publish(subject string, payload []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.writeLocked(func(w *bufio.Writer) {
+		fmt.Fprintf(w, "PUB %s%s %d\r\n", natsSubjectPrefix, subject, len(payload))
+		w.Write(payload)
+		w.WriteString("\r\n")
+	})
+}
+
+// Subscribe registers a handler for subject (re-subscribed automatically after reconnects)
+func (t *NATSTransport) Subscribe(subject string, handler func(payload []byte)) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.nextSID++
+	sid := strconv.Itoa(t.nextSID)
+	t.subs[sid] = handler
+	t.subjects[sid] = subject
+
+	if t.writer == nil {
+		return nil // Sent on reconnect
+	}
+	return t.writeLocked(func(w *bufio.Writer) {
+		fmt.Fprintf(w, "SUB %s%s %s\r\n", natsSubjectPrefix, subject, sid)
+	})
+}
+
+// Close disconnects from NATS
+func (t *NATSTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = true
+	if t.conn == nil {
+		return nil
+	}
+	return t.conn.Close()
+}
+
+// connect dials and authenticates without holding the lock, then swaps the connection in,
+// re-subscribes and starts the read loop
+func (t *NATSTransport) connect() error {
+	conn, reader, writer, err := t.dial()
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		conn.Close()
+		return errors.New("NATS transport closed")
+	}
+	t.conn = conn
+	t.writer = writer
+
+	// Subscribing under the lock covers handlers registered while dialing
+	err = t.writeLocked(func(w *bufio.Writer) {
+		for sid, subject := range t.subjects {
+			fmt.Fprintf(w, "SUB %s%s %s\r\n", natsSubjectPrefix, subject, sid)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("NATS subscribe failed: %w", err)
+	}
+	go t.readLoop(conn, reader)
+
+	return nil
+}
+
+// dial opens a connection and performs the INFO/CONNECT handshake
+func (t *NATSTransport) dial() (net.Conn, *bufio.Reader, *bufio.Writer, error) {
+	conn, err := net.DialTimeout("tcp", t.addr, natsDialTimeout)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to connect to NATS at %s: %w", t.addr, err)
+	}
+
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(natsDialTimeout))
+	info, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(info, "INFO ") {
+		conn.Close()
+		return nil, nil, nil, fmt.Errorf("unexpected NATS greeting: %q", strings.TrimSpace(info))
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	options := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "payperplay-api-" + InstanceID,
+		"lang":     "go",
+	}
+	if t.user != "" {
+		options["user"] = t.user
+		options["pass"] = t.pass
+	}
+	if t.token != "" {
+		options["auth_token"] = t.token
+	}
+	connectJSON, _ := json.Marshal(options)
+
+	writer := bufio.NewWriter(conn)
+	conn.SetWriteDeadline(time.Now().Add(natsWriteTimeout))
+	fmt.Fprintf(writer, "CONNECT %s\r\n", connectJSON)
+	if err := writer.Flush(); err != nil {
+		conn.Close()
+		return nil, nil, nil, fmt.Errorf("NATS handshake failed: %w", err)
+	}
+
+	return conn, reader, writer, nil
+}
+
+// writeLocked writes frames under a write deadline and flushes them
+// A failed write closes the connection, so the read loop notices and reconnects.
+func (t *NATSTransport) writeLocked(write func(w *bufio.Writer)) error {
+	if t.writer == nil {
+		return errors.New("NATS not connected")
+	}
+
+	t.conn.SetWriteDeadline(time.Now().Add(natsWriteTimeout))
+	write(t.writer)
+	if err := t.writer.Flush(); err != nil {
+		t.conn.Close()
+		t.conn = nil
+		t.writer = nil
+		return err
+	}
+	return nil
+}
+
+// readLoop dispatches MSG frames and answers PINGs until the connection drops
+func (t *NATSTransport) readLoop(conn net.Conn, reader *bufio.Reader) {
+	err := t.read(reader)
+
+	t.mu.Lock()
+	closed := t.closed
+	replaced := t.conn != nil && t.conn != conn
+	if t.conn == conn {
+		t.conn = nil
+		t.writer = nil
+	}
+	t.mu.Unlock()
+	conn.Close()
+
+	if closed || replaced {
+		return
+	}
+
+	logger.Warn("EVENT-TRANSPORT: NATS connection lost, reconnecting", map[string]interface{}{
+		"error": err.Error(),
+	})
+	t.reconnect()
+}
+
+// read parses server frames
+func (t *NATSTransport) read(reader *bufio.Reader) error {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "MSG "):
+			// MSG <subject> <sid> [reply-to] <#bytes>
+			fields := strings.Fields(line)
+			if len(fields) < 4 {
+				return fmt.Errorf("malformed MSG frame: %q", line)
+			}
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return fmt.Errorf("malformed MSG size: %q", line)
+			}
+			payload := make([]byte, size+2) // Payload + CRLF
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return err
+			}
+
+			t.mu.Lock()
+			handler := t.subs[fields[2]]
+			t.mu.Unlock()
+			if handler != nil {
+				handler(payload[:size])
+			}
+
+		case line == "PING":
+			t.mu.Lock()
+			t.writeLocked(func(w *bufio.Writer) { w.WriteString("PONG\r\n") })
+			t.mu.Unlock()
+
+		case strings.HasPrefix(line, "-ERR"):
+			logger.Warn("EVENT-TRANSPORT: NATS error", map[string]interface{}{
+				"error": line,
+			})
+
+		case strings.HasPrefix(line, "INFO "), line == "PONG", line == "+OK", line == "":
+			// Nothing to do
+		}
+	}
+}
+
+// reconnect retries with backoff until connected or closed
+func (t *NATSTransport) reconnect() {
+	delay := natsReconnectDelay
+	for {
+		time.Sleep(delay)
+
+		t.mu.Lock()
+		closed := t.closed
+		t.mu.Unlock()
+		if closed {
+			return
+		}
+
+		if err := t.connect(); err == nil {
+			logger.Info("EVENT-TRANSPORT: Reconnected to NATS", map[string]interface{}{
+				"addr": t.addr,
+			})
+			return
+		}
+
+		if delay *= 2; delay > natsMaxReconnectGap {
+			delay = natsMaxReconnectGap
+		}
+	}
+}
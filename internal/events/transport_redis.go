@@ -0,0 +1,197 @@
+package events
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/storage"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	redisStreamPrefix    = "payperplay:"
+	redisStreamMaxLen    = 10000 // Approximate per-stream cap (XADD MAXLEN ~)
+	redisBlockTimeout    = 5 * time.Second
+	redisReadTimeout     = 10 * time.Second // XREAD's BLOCK time plus the reply
+	redisReconnectDelay  = 2 * time.Second
+	redisMaxReconnectGap = 30 * time.Second
+)
+
+// RedisStreamTransport relays messages over Redis Streams (XADD/XREAD on storage.RedisConn)
+// Every instance reads each stream independently from its last seen ID, so short
+// disconnects don't lose messages as long as the stream hasn't been trimmed past them.
+type RedisStreamTransport struct {
+	pub *storage.RedisClient // Publishing shares one connection
+
+	mu     sync.Mutex
+	closed bool
+	conns  []*storage.RedisConn // Blocking readers, closed on Close
+}
+
+// NewRedisStreamTransport connects to Redis (redis://[user:password@]host:6379[/db])
+func NewRedisStreamTransport(rawURL string) (*RedisStreamTransport, error) {
+	pub, err := storage.NewRedisClient(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	t := &RedisStreamTransport{pub: pub}
+
+	logger.Info("EVENT-TRANSPORT: Connected to Redis", map[string]interface{}{
+		"addr":        pub.Options().Addr,
+		"db":          pub.Options().DB,
+		"instance_id": InstanceID,
+	})
+
+	return t, nil
+}
+
+// Publish appends a payload to the subject's stream
+func (t *RedisStreamTransport) Publish(subject string, payload []byte) error {
+	_, err := t.pub.Do("XADD", redisStreamPrefix+subject, "MAXLEN", "~", strconv.Itoa(redisStreamMaxLen), "*", "payload", string(payload))
+	return err
+}
+
+// Subscribe starts a reader for the subject's stream, beginning with new messages
+func (t *RedisStreamTransport) Subscribe(subject string, handler func(payload []byte)) error {
+	conn, err := storage.DialRedis(t.pub.Options())
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		conn.Close()
+		return errors.New("transport closed")
+	}
+	t.conns = append(t.conns, conn)
+	t.mu.Unlock()
+
+	go t.readStream(conn, redisStreamPrefix+subject, handler)
+	return nil
+}
+
+// Close stops all readers and the publish connection
+func (t *RedisStreamTransport) Close() error {
+	t.mu.Lock()
+	t.closed = true
+	conns := t.conns
+	t.conns = nil
+	t.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+
+	return t.pub.Close()
+}
+
+// readStream blocks on XREAD and dispatches entries, reconnecting on errors
+func (t *RedisStreamTransport) readStream(conn *storage.RedisConn, stream string, handler func(payload []byte)) {
+	lastID := "$" // Only messages published after subscribing
+	delay := redisReconnectDelay
+
+	for {
+		reply, err := conn.DoTimeout(redisReadTimeout, "XREAD", "BLOCK", strconv.Itoa(int(redisBlockTimeout/time.Millisecond)), "STREAMS", stream, lastID)
+		if err == nil {
+			delay = redisReconnectDelay
+			lastID = dispatchStreamEntries(reply, lastID, handler)
+			continue
+		}
+
+		conn.Close()
+		if t.isClosed() {
+			return
+		}
+
+		logger.Warn("EVENT-TRANSPORT: Redis stream read failed, reconnecting", map[string]interface{}{
+			"stream": stream,
+			"error":  err.Error(),
+		})
+
+		// Reconnect with backoff; lastID makes sure we resume where we stopped
+		for {
+			time.Sleep(delay)
+			if delay *= 2; delay > redisMaxReconnectGap {
+				delay = redisMaxReconnectGap
+			}
+			if t.isClosed() {
+				return
+			}
+
+			newConn, dialErr := storage.DialRedis(t.pub.Options())
+			if dialErr != nil {
+				continue
+			}
+			if !t.replaceConn(conn, newConn) {
+				newConn.Close()
+				return
+			}
+			conn = newConn
+			break
+		}
+	}
+}
+
+// dispatchStreamEntries hands XREAD entries to handler and returns the last processed ID
+// Reply shape: [[stream, [[id, [field, value, ...]], ...]]] or nil on timeout
+func dispatchStreamEntries(reply interface{}, lastID string, handler func(payload []byte)) string {
+	streams, ok := reply.([]interface{})
+	if !ok {
+		return lastID
+	}
+
+	for _, s := range streams {
+		stream, ok := s.([]interface{})
+		if !ok || len(stream) != 2 {
+			continue
+		}
+		entries, _ := stream[1].([]interface{})
+		for _, e := range entries {
+			entry, ok := e.([]interface{})
+			if !ok || len(entry) != 2 {
+				continue
+			}
+			if id, ok := entry[0].(string); ok {
+				lastID = id
+			}
+			fields, _ := entry[1].([]interface{})
+			for i := 0; i+1 < len(fields); i += 2 {
+				if name, _ := fields[i].(string); name == "payload" {
+					if value, ok := fields[i+1].(string); ok {
+						handler([]byte(value))
+					}
+				}
+			}
+		}
+	}
+
+	return lastID
+}
+
+// isClosed reports whether Close was called
+func (t *RedisStreamTransport) isClosed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+// replaceConn swaps a reader connection; returns false if the transport was closed meanwhile
+func (t *RedisStreamTransport) replaceConn(old, replacement *storage.RedisConn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return false
+	}
+	for i, conn := range t.conns {
+		if conn == old {
+			t.conns[i] = replacement
+			return true
+		}
+	}
+	t.conns = append(t.conns, replacement)
+	return true
+}
@@ -0,0 +1,107 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	MojangAPIBase     = "https://api.mojang.com"
+	MojangSessionBase = "https://sessionserver.mojang.com"
+)
+
+// Mojang API errors
+var (
+	// ErrMojangProfileNotFound is returned for names and UUIDs without a Minecraft account
+	ErrMojangProfileNotFound = errors.New("minecraft profile not found")
+	// ErrMojangRateLimited is returned when Mojang answers 429
+	ErrMojangRateLimited = errors.New("mojang API rate limit reached")
+)
+
+// MojangProfile is the UUID and current name of a Minecraft account
+type MojangProfile struct {
+	UUID string `json:"uuid"` // Dashed
+	Name string `json:"name"`
+}
+
+// MojangClient resolves player names and UUIDs through the public Mojang API
+type MojangClient struct {
+	httpClient  *http.Client
+	apiBase     string
+	sessionBase string
+}
+
+// NewMojangClient creates a new Mojang API client
+func NewMojangClient() *MojangClient {
+	return &MojangClient{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		apiBase:     MojangAPIBase,
+		sessionBase: MojangSessionBase,
+	}
+}
+
+// ProfileByName returns the account currently using name (case-insensitive)
+func (c *MojangClient) ProfileByName(ctx context.Context, name string) (*MojangProfile, error) {
+	return c.getProfile(ctx, fmt.Sprintf("%s/users/profiles/minecraft/%s", c.apiBase, url.PathEscape(name)))
+}
+
+// ProfileByUUID returns the current name of the account with uuid (dashed or not)
+func (c *MojangClient) ProfileByUUID(ctx context.Context, uuid string) (*MojangProfile, error) {
+	return c.getProfile(ctx, fmt.Sprintf("%s/session/minecraft/profile/%s", c.sessionBase, strings.ReplaceAll(uuid, "-", "")))
+}
+
+func (c *MojangClient) getProfile(ctx context.Context, profileURL string) (*MojangProfile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, profileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mojang API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotFound:
+		return nil, ErrMojangProfileNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, ErrMojangRateLimited
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("mojang API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var raw struct {
+		ID   string `json:"id"` // Undashed
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode mojang profile: %w", err)
+	}
+	uuid, err := DashUUID(raw.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &MojangProfile{UUID: uuid, Name: raw.Name}, nil
+}
+
+// DashUUID formats a 32-digit hex UUID as 8-4-4-4-12 (already dashed UUIDs are lowercased)
+func DashUUID(id string) (string, error) {
+	hex := strings.ToLower(strings.ReplaceAll(id, "-", ""))
+	if len(hex) != 32 || strings.Trim(hex, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("invalid UUID %q", id)
+	}
+	return hex[0:8] + "-" + hex[8:12] + "-" + hex[12:16] + "-" + hex[16:20] + "-" + hex[20:32], nil
+}
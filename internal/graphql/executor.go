@@ -0,0 +1,552 @@
+// Package graphql is a small GraphQL executor for composite read views over the REST services.
+// Object types declare resolvers for computed fields (relations such as a server's backups);
+// every other field resolves to the JSON field of the same name of the resolved Go value, so
+// GraphQL results have the same shape and field names as the REST responses.
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// maxDepth limits the nesting of selection sets
+const maxDepth = 12
+
+// ResolveParams are passed to field resolvers
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{} // Resolved value of the parent object (nil for root fields)
+	Args    map[string]interface{}
+}
+
+// String returns a string argument ("" if missing)
+func (p ResolveParams) String(name string) string {
+	s, _ := p.Args[name].(string)
+	return s
+}
+
+// Int returns an integer argument (def if missing)
+func (p ResolveParams) Int(name string, def int) int {
+	switch n := p.Args[name].(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	}
+	return def
+}
+
+// ResolveFunc resolves the value of a field
+type ResolveFunc func(p ResolveParams) (interface{}, error)
+
+// SubscribeFunc starts the event stream of a subscription field
+// The stream ends when the channel is closed; it must be closed once p.Context is done.
+type SubscribeFunc func(p ResolveParams) (<-chan interface{}, error)
+
+// Object is a GraphQL object type
+type Object struct {
+	Name   string
+	Fields map[string]*Field // Computed fields; other fields resolve from the JSON of the value
+}
+
+// Field is a computed field of an object type
+type Field struct {
+	Type      *Object // Object type of the value (nil for scalars and JSON values)
+	Resolve   ResolveFunc
+	Subscribe SubscribeFunc // Subscription root fields
+}
+
+// Schema holds the root types
+type Schema struct {
+	Query        *Object
+	Subscription *Object
+}
+
+// Request is a GraphQL request (POST body or subscribe payload)
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is a GraphQL response
+type Response struct {
+	Data   interface{} `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error is a GraphQL error
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Execute runs a query operation
+func (s *Schema) Execute(ctx context.Context, req *Request) *Response {
+	op, exec, err := s.prepare(req)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	if op.Type != "query" {
+		return &Response{Errors: []*Error{{Message: "Use the WebSocket endpoint for subscriptions; mutations are not supported"}}}
+	}
+
+	exec.ctx = ctx
+	data := exec.selectionSet(s.Query, nil, op.Selections, nil, 0)
+	return &Response{Data: data, Errors: exec.errors}
+}
+
+// Subscribe starts a subscription operation; every event of the stream yields one response
+// The returned channel is closed when the subscription ends.
+func (s *Schema) Subscribe(ctx context.Context, req *Request) (<-chan *Response, error) {
+	op, exec, err := s.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	if op.Type != "subscription" || s.Subscription == nil {
+		return nil, fmt.Errorf("operation is not a subscription")
+	}
+	exec.ctx = ctx
+
+	fields := exec.collectFields(s.Subscription, op.Selections, nil)
+	if len(fields.keys) != 1 {
+		return nil, fmt.Errorf("a subscription must select exactly one top level field")
+	}
+	key := fields.keys[0]
+	selected := fields.values[key]
+	field, ok := s.Subscription.Fields[selected[0].Name]
+	if !ok || field.Subscribe == nil {
+		return nil, fmt.Errorf("cannot query field %q on type %q", selected[0].Name, s.Subscription.Name)
+	}
+
+	args, err := exec.arguments(selected[0].Arguments)
+	if err != nil {
+		return nil, err
+	}
+	source, err := field.Subscribe(ResolveParams{Context: ctx, Args: args})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make(chan *Response)
+	go func() {
+		defer close(responses)
+		for event := range source {
+			eventExec := &executor{ctx: ctx, doc: exec.doc, variables: exec.variables}
+			data := &orderedMap{}
+			data.set(key, eventExec.complete(field.Type, event, mergeSelections(selected), []interface{}{key}, 1))
+			select {
+			case responses <- &Response{Data: data, Errors: eventExec.errors}:
+			case <-ctx.Done():
+				// Drain the source until its producer closes it
+				for range source {
+				}
+				return
+			}
+		}
+	}()
+	return responses, nil
+}
+
+// OperationType returns the type (query, subscription, mutation) of the operation the request runs
+func (s *Schema) OperationType(req *Request) (string, error) {
+	op, _, err := s.prepare(req)
+	if err != nil {
+		return "", err
+	}
+	return op.Type, nil
+}
+
+// prepare parses the request and selects the operation to run
+func (s *Schema) prepare(req *Request) (*Operation, *executor, error) {
+	if len(req.Query) > maxQuerySize {
+		return nil, nil, fmt.Errorf("query exceeds the maximum size of %d bytes", maxQuerySize)
+	}
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var op *Operation
+	for _, candidate := range doc.Operations {
+		if req.OperationName == "" || candidate.Name == req.OperationName {
+			if op != nil {
+				return nil, nil, fmt.Errorf("operationName is required for documents with multiple operations")
+			}
+			op = candidate
+		}
+	}
+	if op == nil {
+		if req.OperationName != "" {
+			return nil, nil, fmt.Errorf("unknown operation %q", req.OperationName)
+		}
+		return nil, nil, fmt.Errorf("document contains no operation")
+	}
+	if err := validate(doc, op); err != nil {
+		return nil, nil, err
+	}
+
+	variables := map[string]interface{}{}
+	for _, def := range op.Variables {
+		value, ok := req.Variables[def.Name]
+		if !ok && def.HasValue {
+			value, ok = valueOf(def.Default, nil), true
+		}
+		if (!ok || value == nil) && def.NonNull {
+			return nil, nil, fmt.Errorf("variable \"$%s\" of required type %q was not provided", def.Name, def.Type)
+		}
+		variables[def.Name] = value
+	}
+
+	return op, &executor{doc: doc, variables: variables}, nil
+}
+
+// executor holds the state of one execution
+type executor struct {
+	ctx       context.Context
+	doc       *Document
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+func (e *executor) fail(path []interface{}, err error) {
+	e.errors = append(e.errors, &Error{Message: err.Error(), Path: append([]interface{}{}, path...)})
+}
+
+// selectionSet resolves the selected fields of an object value
+func (e *executor) selectionSet(obj *Object, source interface{}, selections []Selection, path []interface{}, depth int) *orderedMap {
+	result := &orderedMap{}
+	if depth > maxDepth {
+		e.fail(path, fmt.Errorf("query exceeds the maximum depth of %d", maxDepth))
+		return result
+	}
+
+	fields := e.collectFields(obj, selections, nil)
+	for _, key := range fields.keys {
+		selected := fields.values[key]
+		fieldPath := append(append([]interface{}{}, path...), key)
+		result.set(key, e.field(obj, source, selected, fieldPath, depth))
+	}
+	return result
+}
+
+// field resolves and completes one response key
+func (e *executor) field(obj *Object, source interface{}, selected []*FieldNode, path []interface{}, depth int) interface{} {
+	name := selected[0].Name
+	if name == "__typename" {
+		if obj != nil {
+			return obj.Name
+		}
+		return "Object"
+	}
+
+	var fieldType *Object
+	var value interface{}
+	var err error
+
+	if def, ok := lookupField(obj, name); ok && def.Resolve != nil {
+		fieldType = def.Type
+		var args map[string]interface{}
+		if args, err = e.arguments(selected[0].Arguments); err == nil {
+			value, err = def.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+		}
+	} else {
+		value, err = jsonField(source, name, obj)
+	}
+	if err != nil {
+		e.fail(path, err)
+		return nil
+	}
+
+	return e.complete(fieldType, value, mergeSelections(selected), path, depth+1)
+}
+
+// complete applies the sub-selection to a resolved value
+func (e *executor) complete(fieldType *Object, value interface{}, selections []Selection, path []interface{}, depth int) interface{} {
+	if isNil(value) {
+		return nil
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	composite := rv.Kind() == reflect.Struct || rv.Kind() == reflect.Map ||
+		((rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8)
+
+	if len(selections) == 0 {
+		if fieldType != nil {
+			e.fail(path, fmt.Errorf("field of type %q must have a selection of subfields", fieldType.Name))
+			return nil
+		}
+		return value
+	}
+	if !composite {
+		e.fail(path, fmt.Errorf("field of a scalar type can't have a selection of subfields"))
+		return nil
+	}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = e.complete(fieldType, rv.Index(i).Interface(), selections, append(path, i), depth)
+		}
+		return list
+	}
+	return e.selectionSet(fieldType, value, selections, path, depth)
+}
+
+// fieldSet groups the selected fields by response key, in selection order
+type fieldSet struct {
+	keys   []string
+	values map[string][]*FieldNode
+}
+
+// collectFields flattens fragments and applies @skip/@include
+func (e *executor) collectFields(obj *Object, selections []Selection, set *fieldSet) *fieldSet {
+	if set == nil {
+		set = &fieldSet{values: map[string][]*FieldNode{}}
+	}
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *FieldNode:
+			if !e.included(sel.Directives) {
+				continue
+			}
+			key := sel.ResponseKey()
+			if _, exists := set.values[key]; !exists {
+				set.keys = append(set.keys, key)
+			}
+			set.values[key] = append(set.values[key], sel)
+		case *FragmentSpread:
+			fragment, ok := e.doc.Fragments[sel.Name]
+			if !ok || !e.included(sel.Directives) || !typeMatches(obj, fragment.TypeCondition) {
+				continue
+			}
+			e.collectFields(obj, fragment.Selections, set)
+		case *InlineFragment:
+			if !e.included(sel.Directives) || !typeMatches(obj, sel.TypeCondition) {
+				continue
+			}
+			e.collectFields(obj, sel.Selections, set)
+		}
+	}
+	return set
+}
+
+// included evaluates @skip(if:) and @include(if:)
+func (e *executor) included(directives []*Directive) bool {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			continue
+		}
+		args, _ := e.arguments(directive.Arguments)
+		condition, _ := args["if"].(bool)
+		if (directive.Name == "skip") == condition {
+			return false
+		}
+	}
+	return true
+}
+
+// arguments resolves argument values (variables, literals)
+func (e *executor) arguments(arguments []*Argument) (map[string]interface{}, error) {
+	args := make(map[string]interface{}, len(arguments))
+	for _, arg := range arguments {
+		if v, ok := arg.Value.(Variable); ok {
+			if _, declared := e.variables[string(v)]; !declared {
+				return nil, fmt.Errorf("variable \"$%s\" is not defined", v)
+			}
+		}
+		args[arg.Name] = valueOf(arg.Value, e.variables)
+	}
+	return args, nil
+}
+
+// valueOf converts an AST value to a Go value
+func valueOf(value Value, variables map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case Variable:
+		return variables[string(v)]
+	case EnumValue:
+		return string(v)
+	case ListValue:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = valueOf(item, variables)
+		}
+		return list
+	case ObjectValue:
+		object := make(map[string]interface{}, len(v))
+		for name, item := range v {
+			object[name] = valueOf(item, variables)
+		}
+		return object
+	}
+	return value
+}
+
+// mergeSelections combines the sub-selections of fields selected under the same response key
+func mergeSelections(fields []*FieldNode) []Selection {
+	if len(fields) == 1 {
+		return fields[0].Selections
+	}
+	var selections []Selection
+	for _, field := range fields {
+		selections = append(selections, field.Selections...)
+	}
+	return selections
+}
+
+func lookupField(obj *Object, name string) (*Field, bool) {
+	if obj == nil {
+		return nil, false
+	}
+	field, ok := obj.Fields[name]
+	return field, ok
+}
+
+func typeMatches(obj *Object, typeCondition string) bool {
+	return typeCondition == "" || obj == nil || obj.Name == typeCondition
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// jsonField returns the field of a value by its JSON name
+// Struct fields follow encoding/json naming (tags, promoted fields of embedded structs);
+// missing map keys resolve to null.
+func jsonField(source interface{}, name string, obj *Object) (interface{}, error) {
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		value := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !value.IsValid() {
+			return nil, nil
+		}
+		return value.Interface(), nil
+	case reflect.Struct:
+		index, ok := jsonFieldIndex(rv.Type())[name]
+		if !ok {
+			break
+		}
+		value, err := rv.FieldByIndexErr(index)
+		if err != nil {
+			return nil, nil // Nil embedded pointer
+		}
+		return value.Interface(), nil
+	}
+
+	typeName := "Object"
+	if obj != nil {
+		typeName = obj.Name
+	}
+	return nil, fmt.Errorf("cannot query field %q on type %q", name, typeName)
+}
+
+var jsonFieldCache sync.Map // reflect.Type -> map[string][]int
+
+// jsonFieldIndex maps the JSON field names of a struct type to their field index
+func jsonFieldIndex(t reflect.Type) map[string][]int {
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+	fields := map[string][]int{}
+	collectJSONFields(t, nil, fields)
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
+
+func collectJSONFields(t reflect.Type, parent []int, fields map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		index := append(append([]int{}, parent...), i)
+		name := strings.Split(tag, ",")[0]
+
+		// Untagged embedded structs are flattened (gorm.Model)
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectJSONFields(ft, index, fields)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, exists := fields[name]; !exists || len(index) < len(fields[name]) {
+			fields[name] = index
+		}
+	}
+}
+
+// orderedMap is a JSON object that keeps the selection order of its keys
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if m.values == nil {
+		m.values = map[string]interface{}{}
+	}
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON encodes the keys in selection order
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
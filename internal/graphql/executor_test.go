@@ -0,0 +1,130 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testServer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func testSchema() *Schema {
+	server := &Object{Name: "Server", Fields: map[string]*Field{}}
+	return &Schema{Query: &Object{Name: "Query", Fields: map[string]*Field{
+		"servers": {Type: server, Resolve: func(p ResolveParams) (interface{}, error) {
+			return []testServer{{ID: "1", Name: "lobby"}, {ID: "2", Name: "survival"}}, nil
+		}},
+	}}}
+}
+
+func TestCollectFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string // Response keys in order
+		count map[string]int
+	}{
+		{name: "fields", query: `{ id name }`, want: []string{"id", "name"}},
+		{name: "aliases", query: `{ a: id b: id }`, want: []string{"a", "b"}},
+		{name: "merged duplicates", query: `{ id name id }`, want: []string{"id", "name"}, count: map[string]int{"id": 2}},
+		{name: "fragment spread", query: `{ id ...f } fragment f on Query { name }`, want: []string{"id", "name"}},
+		{name: "nested spreads", query: `{ ...a } fragment a on Query { id ...b } fragment b on Query { name }`, want: []string{"id", "name"}},
+		{name: "inline fragment", query: `{ ... on Query { id } ... { name } }`, want: []string{"id", "name"}},
+		{name: "type condition mismatch", query: `{ id ... on Server { name } }`, want: []string{"id"}},
+		{name: "skip", query: `{ id @skip(if: true) name }`, want: []string{"name"}},
+		{name: "include", query: `{ id @include(if: false) ...f @include(if: true) } fragment f on Query { name }`, want: []string{"name"}},
+		{name: "unknown fragment", query: `{ id ...missing }`, want: []string{"id"}},
+	}
+
+	obj := &Object{Name: "Query"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			e := &executor{doc: doc, variables: map[string]interface{}{}}
+			set := e.collectFields(obj, doc.Operations[0].Selections, nil)
+			if strings.Join(set.keys, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("collectFields() keys = %v, want %v", set.keys, tt.want)
+			}
+			for key, n := range tt.count {
+				if len(set.values[key]) != n {
+					t.Errorf("collectFields() %q has %d fields, want %d", key, len(set.values[key]), n)
+				}
+			}
+		})
+	}
+}
+
+func TestExecute(t *testing.T) {
+	resp := testSchema().Execute(context.Background(), &Request{Query: `{ servers { id ...f } } fragment f on Server { name }`})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Execute() errors = %v", resp.Errors[0].Message)
+	}
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatalf("failed to marshal data: %v", err)
+	}
+	want := `{"servers":[{"id":"1","name":"lobby"},{"id":"2","name":"survival"}]}`
+	if string(data) != want {
+		t.Errorf("Execute() data = %s, want %s", data, want)
+	}
+}
+
+func TestExecuteValidation(t *testing.T) {
+	// f10 is spread 2^10 times; the count must not expand the document
+	exponential := `{ ...f0 }`
+	for i := 0; i < 10; i++ {
+		exponential += fmt.Sprintf(" fragment f%d on Query { ...f%d ...f%d }", i, i+1, i+1)
+	}
+	exponential += " fragment f10 on Query { servers { id } }"
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{name: "self spread", query: `fragment A on Query { ...A } query { ...A }`, wantErr: `cannot spread fragment "A" within itself`},
+		{name: "indirect cycle", query: `fragment A on Query { servers { ...B } } fragment B on Server { ...A } { ...A }`, wantErr: "within itself"},
+		{name: "cycle in unused fragment", query: `fragment A on Query { ...A } { servers { id } }`, wantErr: "within itself"},
+		{name: "cycle through inline fragment", query: `fragment A on Query { ... on Query { ...A } } { ...A }`, wantErr: "within itself"},
+		{name: "query size", query: "{ servers { id " + strings.Repeat(" ", maxQuerySize) + "} }", wantErr: "maximum size"},
+		{name: "aliases", query: "{ " + repeatFields("a%d: servers { id }", maxAliases+1) + " }", wantErr: "aliases"},
+		{name: "fields", query: "{ servers { " + repeatFields("f%d: id", 20) + " } " + strings.Repeat("servers { id } ", maxFields) + "}", wantErr: "fields"},
+		{name: "exponential fragments", query: exponential, wantErr: "fields"},
+		{name: "shared fragment", query: `{ servers { ...f ...f } } fragment f on Server { id }`},
+	}
+
+	schema := testSchema()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := schema.Execute(context.Background(), &Request{Query: tt.query})
+			if tt.wantErr == "" {
+				if len(resp.Errors) > 0 {
+					t.Fatalf("Execute() errors = %v", resp.Errors[0].Message)
+				}
+				return
+			}
+			if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, tt.wantErr) {
+				t.Fatalf("Execute() errors = %+v, want %q", resp.Errors, tt.wantErr)
+			}
+			if resp.Data != nil {
+				t.Errorf("Execute() data = %v, want nil for a rejected document", resp.Data)
+			}
+		})
+	}
+}
+
+func repeatFields(format string, n int) string {
+	fields := make([]string, n)
+	for i := range fields {
+		fields[i] = fmt.Sprintf(format, i)
+	}
+	return strings.Join(fields, " ")
+}
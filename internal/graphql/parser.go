@@ -0,0 +1,557 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document is a parsed GraphQL request document
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is a query or subscription of a document
+type Operation struct {
+	Type       string // query, subscription, mutation
+	Name       string
+	Variables  []*VariableDefinition
+	Selections []Selection
+}
+
+// VariableDefinition declares an operation variable ($id: ID!)
+type VariableDefinition struct {
+	Name     string
+	Type     string
+	Default  Value
+	NonNull  bool
+	HasValue bool // Default is set
+}
+
+// Fragment is a named fragment (fragment serverFields on Server { ... })
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Selections    []Selection
+}
+
+// Selection is a *FieldNode, *FragmentSpread or *InlineFragment
+type Selection interface{}
+
+// FieldNode is a field selection
+type FieldNode struct {
+	Alias      string
+	Name       string
+	Arguments  []*Argument
+	Directives []*Directive
+	Selections []Selection
+}
+
+// ResponseKey returns the key of the field in the result (alias or name)
+func (f *FieldNode) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// FragmentSpread references a named fragment (...serverFields)
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+}
+
+// InlineFragment is an inline fragment (... on Server { ... })
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	Selections    []Selection
+}
+
+// Argument is a field or directive argument
+type Argument struct {
+	Name  string
+	Value Value
+}
+
+// Directive is a selection directive (@include, @skip)
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+}
+
+// Value is an argument value: a literal (string, int, float64, bool, nil),
+// Variable, EnumValue, ListValue or ObjectValue
+type Value interface{}
+
+// Variable references an operation variable
+type Variable string
+
+// EnumValue is an unquoted enum literal
+type EnumValue string
+
+// ListValue is a list literal
+type ListValue []Value
+
+// ObjectValue is an input object literal
+type ObjectValue map[string]Value
+
+// SyntaxError is returned for documents that can't be parsed
+type SyntaxError struct {
+	Message string
+	Line    int
+	Column  int
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("Syntax Error: %s (line %d, column %d)", e.Message, e.Line, e.Column)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// Parse parses a GraphQL document
+func Parse(source string) (doc *Document, err error) {
+	p := &parser{source: source}
+	defer func() {
+		if r := recover(); r != nil {
+			syntaxErr, ok := r.(*SyntaxError)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, syntaxErr
+		}
+	}()
+
+	p.next()
+	doc = &Document{Fragments: map[string]*Fragment{}}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunct, "{"):
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: p.parseSelectionSet()})
+		case p.peek(tokenName, "fragment"):
+			fragment := p.parseFragment()
+			if _, exists := doc.Fragments[fragment.Name]; exists {
+				p.fail("There can be only one fragment named \"" + fragment.Name + "\"")
+			}
+			doc.Fragments[fragment.Name] = fragment
+		case p.peek(tokenName, "query"), p.peek(tokenName, "subscription"), p.peek(tokenName, "mutation"):
+			doc.Operations = append(doc.Operations, p.parseOperation())
+		default:
+			p.fail("Unexpected " + p.describe())
+		}
+	}
+	return doc, nil
+}
+
+type parser struct {
+	source string
+	pos    int
+	tok    token
+}
+
+func (p *parser) parseOperation() *Operation {
+	op := &Operation{Type: p.expect(tokenName).value}
+	if p.tok.kind == tokenName {
+		op.Name = p.expect(tokenName).value
+	}
+	if p.skip("(") {
+		for !p.skip(")") {
+			op.Variables = append(op.Variables, p.parseVariableDefinition())
+		}
+	}
+	p.parseDirectives()
+	op.Selections = p.parseSelectionSet()
+	return op
+}
+
+func (p *parser) parseVariableDefinition() *VariableDefinition {
+	p.expectPunct("$")
+	def := &VariableDefinition{Name: p.expect(tokenName).value}
+	p.expectPunct(":")
+	def.Type = p.parseType()
+	def.NonNull = strings.HasSuffix(def.Type, "!")
+	if p.skip("=") {
+		def.Default = p.parseValue(true)
+		def.HasValue = true
+	}
+	return def
+}
+
+func (p *parser) parseType() string {
+	if p.skip("[") {
+		inner := p.parseType()
+		p.expectPunct("]")
+		if p.skip("!") {
+			return "[" + inner + "]!"
+		}
+		return "[" + inner + "]"
+	}
+	name := p.expect(tokenName).value
+	if p.skip("!") {
+		return name + "!"
+	}
+	return name
+}
+
+func (p *parser) parseFragment() *Fragment {
+	p.expect(tokenName) // fragment
+	fragment := &Fragment{Name: p.expect(tokenName).value}
+	if !p.peek(tokenName, "on") {
+		p.fail("Expected \"on\", found " + p.describe())
+	}
+	p.next()
+	fragment.TypeCondition = p.expect(tokenName).value
+	p.parseDirectives()
+	fragment.Selections = p.parseSelectionSet()
+	return fragment
+}
+
+func (p *parser) parseSelectionSet() []Selection {
+	p.expectPunct("{")
+	var selections []Selection
+	for !p.skip("}") {
+		if p.skip("...") {
+			selections = append(selections, p.parseFragmentSelection())
+			continue
+		}
+		selections = append(selections, p.parseField())
+	}
+	if len(selections) == 0 {
+		p.fail("Expected a selection, found \"}\"")
+	}
+	return selections
+}
+
+func (p *parser) parseFragmentSelection() Selection {
+	if p.peek(tokenName, "on") {
+		p.next()
+		inline := &InlineFragment{TypeCondition: p.expect(tokenName).value}
+		inline.Directives = p.parseDirectives()
+		inline.Selections = p.parseSelectionSet()
+		return inline
+	}
+	if p.tok.kind == tokenName {
+		return &FragmentSpread{Name: p.expect(tokenName).value, Directives: p.parseDirectives()}
+	}
+	inline := &InlineFragment{Directives: p.parseDirectives()}
+	inline.Selections = p.parseSelectionSet()
+	return inline
+}
+
+func (p *parser) parseField() *FieldNode {
+	field := &FieldNode{Name: p.expect(tokenName).value}
+	if p.skip(":") {
+		field.Alias = field.Name
+		field.Name = p.expect(tokenName).value
+	}
+	field.Arguments = p.parseArguments(false)
+	field.Directives = p.parseDirectives()
+	if p.peek(tokenPunct, "{") {
+		field.Selections = p.parseSelectionSet()
+	}
+	return field
+}
+
+func (p *parser) parseArguments(constant bool) []*Argument {
+	if !p.skip("(") {
+		return nil
+	}
+	var args []*Argument
+	for !p.skip(")") {
+		arg := &Argument{Name: p.expect(tokenName).value}
+		p.expectPunct(":")
+		arg.Value = p.parseValue(constant)
+		args = append(args, arg)
+	}
+	return args
+}
+
+func (p *parser) parseDirectives() []*Directive {
+	var directives []*Directive
+	for p.skip("@") {
+		directives = append(directives, &Directive{
+			Name:      p.expect(tokenName).value,
+			Arguments: p.parseArguments(false),
+		})
+	}
+	return directives
+}
+
+func (p *parser) parseValue(constant bool) Value {
+	tok := p.tok
+	switch tok.kind {
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if constant {
+				p.fail("Unexpected variable in constant value")
+			}
+			p.next()
+			return Variable(p.expect(tokenName).value)
+		case "[":
+			p.next()
+			list := ListValue{}
+			for !p.skip("]") {
+				list = append(list, p.parseValue(constant))
+			}
+			return list
+		case "{":
+			p.next()
+			object := ObjectValue{}
+			for !p.skip("}") {
+				name := p.expect(tokenName).value
+				p.expectPunct(":")
+				object[name] = p.parseValue(constant)
+			}
+			return object
+		}
+	case tokenInt:
+		p.next()
+		n, err := strconv.Atoi(tok.value)
+		if err != nil {
+			p.fail("Invalid integer " + tok.value)
+		}
+		return n
+	case tokenFloat:
+		p.next()
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			p.fail("Invalid float " + tok.value)
+		}
+		return f
+	case tokenString:
+		p.next()
+		return tok.value
+	case tokenName:
+		p.next()
+		switch tok.value {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return EnumValue(tok.value)
+	}
+	p.fail("Unexpected " + p.describe())
+	return nil
+}
+
+// skip consumes the punctuator if it is next
+func (p *parser) skip(punct string) bool {
+	if p.peek(tokenPunct, punct) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+func (p *parser) expect(kind tokenKind) token {
+	if p.tok.kind != kind {
+		p.fail("Unexpected " + p.describe())
+	}
+	tok := p.tok
+	p.next()
+	return tok
+}
+
+func (p *parser) expectPunct(punct string) {
+	if !p.skip(punct) {
+		p.fail("Expected \"" + punct + "\", found " + p.describe())
+	}
+}
+
+func (p *parser) describe() string {
+	switch p.tok.kind {
+	case tokenEOF:
+		return "<EOF>"
+	case tokenString:
+		return "string"
+	}
+	return "\"" + p.tok.value + "\""
+}
+
+func (p *parser) fail(message string) {
+	line, column := 1, 1
+	for _, r := range p.source[:p.tok.pos] {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	panic(&SyntaxError{Message: message, Line: line, Column: column})
+}
+
+// next reads the next token (whitespace, commas and comments are ignored)
+func (p *parser) next() {
+	src := p.source
+	for p.pos < len(src) {
+		c := src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+			continue
+		}
+		if c == '#' {
+			for p.pos < len(src) && src[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		}
+		break
+	}
+
+	start := p.pos
+	if p.pos >= len(src) {
+		p.tok = token{kind: tokenEOF, pos: start}
+		return
+	}
+
+	c := src[p.pos]
+	switch {
+	case strings.HasPrefix(src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokenPunct, value: "...", pos: start}
+	case strings.IndexByte("!$()&:=@[]{}|", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokenPunct, value: string(c), pos: start}
+	case c == '_' || isLetter(c):
+		for p.pos < len(src) && (src[p.pos] == '_' || isLetter(src[p.pos]) || isDigit(src[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenName, value: src[start:p.pos], pos: start}
+	case c == '-' || isDigit(c):
+		p.tok = p.readNumber(start)
+	case c == '"':
+		p.tok = token{kind: tokenString, value: p.readString(start), pos: start}
+	default:
+		p.tok = token{pos: start}
+		p.fail(fmt.Sprintf("Unexpected character %q", c))
+	}
+}
+
+func (p *parser) readNumber(start int) token {
+	src := p.source
+	kind := tokenInt
+	if src[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(src) && isDigit(src[p.pos]) {
+		p.pos++
+	}
+	if p.pos < len(src) && src[p.pos] == '.' {
+		kind = tokenFloat
+		p.pos++
+		for p.pos < len(src) && isDigit(src[p.pos]) {
+			p.pos++
+		}
+	}
+	if p.pos < len(src) && (src[p.pos] == 'e' || src[p.pos] == 'E') {
+		kind = tokenFloat
+		p.pos++
+		if p.pos < len(src) && (src[p.pos] == '+' || src[p.pos] == '-') {
+			p.pos++
+		}
+		for p.pos < len(src) && isDigit(src[p.pos]) {
+			p.pos++
+		}
+	}
+	return token{kind: kind, value: src[start:p.pos], pos: start}
+}
+
+func (p *parser) readString(start int) string {
+	src := p.source
+	if strings.HasPrefix(src[p.pos:], `"""`) {
+		end := strings.Index(src[p.pos+3:], `"""`)
+		if end < 0 {
+			p.tok = token{pos: start}
+			p.fail("Unterminated string")
+		}
+		value := src[p.pos+3 : p.pos+3+end]
+		p.pos += end + 6
+		return strings.TrimSpace(value)
+	}
+
+	var b strings.Builder
+	p.pos++
+	for {
+		if p.pos >= len(src) || src[p.pos] == '\n' {
+			p.tok = token{pos: start}
+			p.fail("Unterminated string")
+		}
+		c := src[p.pos]
+		if c == '"' {
+			p.pos++
+			return b.String()
+		}
+		if c != '\\' {
+			r, size := utf8.DecodeRuneInString(src[p.pos:])
+			b.WriteRune(r)
+			p.pos += size
+			continue
+		}
+
+		p.pos++
+		if p.pos >= len(src) {
+			continue
+		}
+		switch esc := src[p.pos]; esc {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if p.pos+5 > len(src) {
+				p.tok = token{pos: start}
+				p.fail("Invalid unicode escape")
+			}
+			code, err := strconv.ParseUint(src[p.pos+1:p.pos+5], 16, 32)
+			if err != nil {
+				p.tok = token{pos: start}
+				p.fail("Invalid unicode escape")
+			}
+			b.WriteRune(rune(code))
+			p.pos += 4
+		default:
+			b.WriteByte(esc) // \" \\ \/
+		}
+		p.pos++
+	}
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantErr    string
+		operations int
+		fragments  int
+	}{
+		{name: "shorthand query", query: `{ servers { id name } }`, operations: 1},
+		{name: "named operation with variables", query: `query Get($id: ID!, $limit: Int = 10) { server(id: $id) { id } }`, operations: 1},
+		{name: "fragment", query: `query { ...f } fragment f on Query { servers { id } }`, operations: 1, fragments: 1},
+		{name: "aliases and directives", query: `{ a: server(id: "1") @include(if: true) { id } }`, operations: 1},
+		{name: "duplicate fragment", query: `fragment f on Query { a } fragment f on Query { b } { ...f }`, wantErr: `only one fragment named "f"`},
+		{name: "empty selection", query: `{ }`, wantErr: "Expected a selection"},
+		{name: "unterminated selection", query: `{ servers { id }`, wantErr: "Syntax Error"},
+		{name: "unexpected token", query: `servers { id }`, wantErr: "Unexpected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(tt.query)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(doc.Operations) != tt.operations || len(doc.Fragments) != tt.fragments {
+				t.Errorf("Parse() = %d operations, %d fragments; want %d, %d",
+					len(doc.Operations), len(doc.Fragments), tt.operations, tt.fragments)
+			}
+		})
+	}
+}
+
+func TestParseField(t *testing.T) {
+	doc, err := Parse(`{ first: server(id: $id, tags: ["a", B], opts: {x: 1.5}) { id } }`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	field := doc.Operations[0].Selections[0].(*FieldNode)
+	if field.Alias != "first" || field.Name != "server" || field.ResponseKey() != "first" {
+		t.Errorf("field = %q: %q, want first: server", field.Alias, field.Name)
+	}
+	if len(field.Arguments) != 3 {
+		t.Fatalf("got %d arguments, want 3", len(field.Arguments))
+	}
+	if v, ok := field.Arguments[0].Value.(Variable); !ok || v != "id" {
+		t.Errorf("id argument = %#v, want Variable(id)", field.Arguments[0].Value)
+	}
+	if list, ok := field.Arguments[1].Value.(ListValue); !ok || len(list) != 2 || list[1] != EnumValue("B") {
+		t.Errorf("tags argument = %#v, want [\"a\", B]", field.Arguments[1].Value)
+	}
+	if obj, ok := field.Arguments[2].Value.(ObjectValue); !ok || obj["x"] != 1.5 {
+		t.Errorf("opts argument = %#v, want {x: 1.5}", field.Arguments[2].Value)
+	}
+}
@@ -0,0 +1,113 @@
+package graphql
+
+import "fmt"
+
+// Request limits, checked before execution
+const (
+	maxQuerySize = 16 << 10 // Bytes of the query document
+	maxAliases   = 30       // Aliased fields of the executed operation
+	maxFields    = 500      // Fields of the executed operation, counting every fragment spread
+)
+
+// validate rejects documents that can't be executed safely: fragment cycles
+// (the NoFragmentCycles rule of the spec) and operations above the alias and field limits
+func validate(doc *Document, op *Operation) error {
+	visited := map[string]bool{}
+	for name := range doc.Fragments {
+		if err := checkFragmentCycles(doc, name, visited, map[string]bool{}); err != nil {
+			return err
+		}
+	}
+
+	counted := map[string]selectionCount{}
+	count := countSelections(doc, op.Selections, counted)
+	if count.aliases > maxAliases {
+		return fmt.Errorf("query exceeds the maximum of %d aliases", maxAliases)
+	}
+	if count.fields > maxFields {
+		return fmt.Errorf("query exceeds the maximum of %d fields", maxFields)
+	}
+	return nil
+}
+
+// checkFragmentCycles walks the spreads of a fragment depth first; visited holds fragments
+// already proven acyclic, path the fragments on the current walk
+func checkFragmentCycles(doc *Document, name string, visited, path map[string]bool) error {
+	if path[name] {
+		return fmt.Errorf("cannot spread fragment %q within itself", name)
+	}
+	if visited[name] {
+		return nil
+	}
+	fragment, ok := doc.Fragments[name]
+	if !ok {
+		return nil
+	}
+
+	path[name] = true
+	for _, spread := range fragmentSpreads(fragment.Selections, nil) {
+		if err := checkFragmentCycles(doc, spread, visited, path); err != nil {
+			return err
+		}
+	}
+	delete(path, name)
+	visited[name] = true
+	return nil
+}
+
+// fragmentSpreads returns the names of the fragments spread in a selection set
+func fragmentSpreads(selections []Selection, names []string) []string {
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *FieldNode:
+			names = fragmentSpreads(sel.Selections, names)
+		case *FragmentSpread:
+			names = append(names, sel.Name)
+		case *InlineFragment:
+			names = fragmentSpreads(sel.Selections, names)
+		}
+	}
+	return names
+}
+
+// selectionCount counts the fields and aliases of a selection set
+type selectionCount struct {
+	fields  int
+	aliases int
+}
+
+func (c *selectionCount) add(other selectionCount) {
+	// Saturate so that fragments spread exponentially often can't overflow the count
+	c.fields = min(c.fields+other.fields, maxFields+1)
+	c.aliases = min(c.aliases+other.aliases, maxAliases+1)
+}
+
+// countSelections counts fields with every fragment spread expanded; fragment counts are
+// memoized in counted, so the document must be free of fragment cycles
+func countSelections(doc *Document, selections []Selection, counted map[string]selectionCount) selectionCount {
+	var count selectionCount
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *FieldNode:
+			count.add(selectionCount{fields: 1})
+			if sel.Alias != "" {
+				count.add(selectionCount{aliases: 1})
+			}
+			count.add(countSelections(doc, sel.Selections, counted))
+		case *FragmentSpread:
+			fragment, ok := doc.Fragments[sel.Name]
+			if !ok {
+				continue
+			}
+			fragmentCount, ok := counted[sel.Name]
+			if !ok {
+				fragmentCount = countSelections(doc, fragment.Selections, counted)
+				counted[sel.Name] = fragmentCount
+			}
+			count.add(fragmentCount)
+		case *InlineFragment:
+			count.add(countSelections(doc, sel.Selections, counted))
+		}
+	}
+	return count
+}
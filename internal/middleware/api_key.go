@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/ratelimit"
+)
+
+// APIKeyAuthInterface authenticates API keys (personal and organization keys)
+type APIKeyAuthInterface interface {
+	AuthenticateAPIKey(rawKey, ip string) (*models.APIKey, error)
+}
+
+var apiKeyAuth APIKeyAuthInterface
+
+// SetAPIKeyService sets the service used to authenticate API keys in AuthMiddleware
+func SetAPIKeyService(svc APIKeyAuthInterface) {
+	apiKeyAuth = svc
+}
+
+// apiKeyContextKey holds the authenticated *models.APIKey in the gin context
+const apiKeyContextKey = "api_key"
+
+// apiKeyRouteScopes lists the routes API keys may call outside of /servers/:id (which are checked
+// per server permission by RequireServerPermission). API keys are rejected on every other route,
+// e.g. account, billing, organizations, shares and API key management.
+var apiKeyRouteScopes = map[string]models.APIKeyScope{
+	"GET /api/servers":                                    models.ScopeServersRead,
+	"POST /api/servers":                                   models.ScopeServersWrite,
+	"GET /api/operations":                                 models.ScopeServersRead,
+	"GET /api/operations/:operation_id":                   models.ScopeServersRead,
+	"POST /api/operations/:operation_id/cancel":           models.ScopeServersWrite,
+	"GET /api/backups/:id":                                models.ScopeBackupsRead,
+	"DELETE /api/backups/:id":                             models.ScopeBackupsWrite,
+	"GET /api/users/:id/backups":                          models.ScopeBackupsRead,
+	"GET /api/users/:id/backups/quota":                    models.ScopeBackupsRead,
+	"POST /api/users/:user_id/backups/:backup_id/restore": models.ScopeServersWrite,
+	"POST /api/apply":                                     models.ScopeServersWrite,
+	"GET /api/apply/servers":                              models.ScopeServersRead,
+}
+
+// apiKeyOrgRoutes are the routes of apiKeyRouteScopes organization keys may call
+// (everything else there acts on the creator's personal account)
+var apiKeyOrgRoutes = map[string]bool{
+	"GET /api/servers": true,
+}
+
+// extractAPIKey returns the raw API key of a request ("X-API-Key" header or "Bearer ppk_...")
+func extractAPIKey(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "); strings.HasPrefix(token, models.APIKeyPrefix) {
+		return token
+	}
+	return ""
+}
+
+// authenticateAPIKey authenticates rawKey, checks that the key may call the route and sets the user context
+// Returns false (after aborting with an error response) if the request must not continue.
+func authenticateAPIKey(c *gin.Context, rawKey string) bool {
+	if apiKeyAuth == nil {
+		abortAPIKey(c, http.StatusUnauthorized, "API keys are not enabled", "INVALID_API_KEY")
+		return false
+	}
+
+	key, err := apiKeyAuth.AuthenticateAPIKey(rawKey, c.ClientIP())
+	if err != nil {
+		var limited *models.APIKeyRateLimitError
+		if errors.As(err, &limited) {
+			abortRateLimited(c, err.Error(), ratelimit.Result{RetryAfter: limited.RetryAfter})
+		} else {
+			abortAPIKey(c, http.StatusUnauthorized, "Invalid, expired or revoked API key", "INVALID_API_KEY")
+		}
+		return false
+	}
+
+	// Server routes are checked per permission by RequireServerPermission
+	if !isServerRoute(c) {
+		route := c.Request.Method + " " + c.FullPath()
+		scope, allowed := apiKeyRouteScopes[route]
+		if !allowed || (key.OrganizationID != "" && !apiKeyOrgRoutes[route]) {
+			abortAPIKey(c, http.StatusForbidden, "This endpoint can't be used with an API key", "API_KEY_NOT_ALLOWED")
+			return false
+		}
+		if !key.HasScope(scope) {
+			abortAPIKey(c, http.StatusForbidden, "This API key is missing the '"+string(scope)+"' scope", "INSUFFICIENT_SCOPE")
+			return false
+		}
+	}
+
+	// API keys never carry admin rights
+	c.Set("user_id", key.UserID)
+	c.Set("is_admin", false)
+	c.Set("auth_method", "api_key")
+	c.Set(apiKeyContextKey, key)
+	return true
+}
+
+// isServerRoute returns true for routes that act on the server in the :id parameter
+func isServerRoute(c *gin.Context) bool {
+	path := c.FullPath()
+	return strings.HasPrefix(path, "/api/servers/:id") || strings.HasPrefix(path, "/api/billing/servers/:id") ||
+		strings.HasPrefix(path, "/dav/:id")
+}
+
+// checkAPIKeyServerAccess applies the scope and organization restrictions of an API key to a server route
+// Returns false (after aborting) if the key may not use perm on the server.
+func checkAPIKeyServerAccess(c *gin.Context, perm models.ServerPermission) bool {
+	key := CurrentAPIKey(c)
+	if key == nil {
+		return true
+	}
+
+	scope, ok := models.ServerPermissionScope[perm]
+	if !ok {
+		abortAPIKey(c, http.StatusForbidden, "This endpoint can't be used with an API key", "API_KEY_NOT_ALLOWED")
+		return false
+	}
+	if !key.HasScope(scope) {
+		abortAPIKey(c, http.StatusForbidden, "This API key is missing the '"+string(scope)+"' scope", "INSUFFICIENT_SCOPE")
+		return false
+	}
+	if key.OrganizationID != "" && (serverAccess == nil || !serverAccess.ServerInOrganization(c.Param("id"), key.OrganizationID)) {
+		abortAPIKey(c, http.StatusNotFound, "server not found", "NOT_FOUND")
+		return false
+	}
+	return true
+}
+
+// CurrentAPIKey returns the API key of the request (nil for JWT sessions)
+func CurrentAPIKey(c *gin.Context) *models.APIKey {
+	value, exists := c.Get(apiKeyContextKey)
+	if !exists {
+		return nil
+	}
+	key, _ := value.(*models.APIKey)
+	return key
+}
+
+func abortAPIKey(c *gin.Context, status int, message, code string) {
+	c.JSON(status, gin.H{
+		"error": message,
+		"code":  code,
+	})
+	c.Abort()
+}
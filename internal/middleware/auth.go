@@ -20,9 +20,17 @@ func SetAuthService(svc AuthServiceInterface) {
 	authService = svc
 }
 
-// AuthMiddleware validates JWT authentication tokens
+// AuthMiddleware validates JWT authentication tokens and API keys
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// API keys (X-API-Key or "Bearer ppk_...") for programmatic access
+		if rawKey := extractAPIKey(c); rawKey != "" {
+			if authenticateAPIKey(c, rawKey) {
+				c.Next()
+			}
+			return
+		}
+
 		var token string
 
 		// Try to get token from Authorization header first
@@ -71,6 +79,11 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Organization SSO requirements and session policies
+		if !checkSSOSession(c, claims) {
+			return
+		}
+
 		// Set user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
@@ -104,7 +117,7 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 		// Validate token if auth service is available
 		if authService != nil {
 			claims, err := authService.ValidateToken(token)
-			if err == nil {
+			if err == nil && (ssoSessionPolicy == nil || ssoSessionPolicy.CheckSession(claims) == nil) {
 				// Valid token, set context
 				c.Set("user_id", claims.UserID)
 				c.Set("email", claims.Email)
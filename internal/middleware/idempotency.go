@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// idempotencyMaxBody is the largest request body and stored response of an idempotent request
+const idempotencyMaxBody = 1 << 20
+
+// IdempotencyStoreInterface persists Idempotency-Key records (see service.IdempotencyService)
+type IdempotencyStoreInterface interface {
+	Begin(userID, key, method, path, fingerprint string) (*models.IdempotencyRecord, error)
+	Complete(record *models.IdempotencyRecord, statusCode int, contentType string, body []byte) error
+	Release(record *models.IdempotencyRecord) error
+}
+
+var idempotencyStore IdempotencyStoreInterface
+
+// SetIdempotencyService sets the store used by the Idempotency middleware
+func SetIdempotencyService(store IdempotencyStoreInterface) {
+	idempotencyStore = store
+}
+
+// Idempotency makes mutating requests with an Idempotency-Key header safe to retry
+// The first request with a key runs normally and its response is stored; retries with the same key
+// and request get that response again (marked with "Idempotent-Replayed: true") instead of e.g.
+// creating a second server. Reusing a key for a different request is rejected with 422, and a retry
+// while the first request still runs gets 409. Server errors (5xx) are not stored, so they can be retried.
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" || idempotencyStore == nil || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			abortIdempotency(c, http.StatusBadRequest, models.ErrIdempotencyInvalidKey, "INVALID_IDEMPOTENCY_KEY")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, idempotencyMaxBody+1))
+		if err != nil {
+			abortIdempotency(c, http.StatusBadRequest, err, "INVALID_REQUEST")
+			return
+		}
+		if len(body) > idempotencyMaxBody {
+			abortIdempotency(c, http.StatusRequestEntityTooLarge, models.ErrIdempotencyBodyTooLarge, "IDEMPOTENCY_BODY_TOO_LARGE")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		path := c.Request.URL.Path
+		record, err := idempotencyStore.Begin(GetUserID(c), key, c.Request.Method, path, requestFingerprint(c.Request.Method, path, body))
+		switch {
+		case errors.Is(err, models.ErrIdempotencyKeyReused):
+			abortIdempotency(c, http.StatusUnprocessableEntity, err, "IDEMPOTENCY_KEY_REUSED")
+			return
+		case errors.Is(err, models.ErrIdempotencyInProgress):
+			abortIdempotency(c, http.StatusConflict, err, "IDEMPOTENCY_IN_PROGRESS")
+			return
+		case err != nil:
+			logger.Error("IDEMPOTENCY: Failed to check idempotency key", err, map[string]interface{}{
+				"path": path,
+			})
+			abortIdempotency(c, http.StatusInternalServerError, errors.New("failed to check Idempotency-Key"), "INTERNAL_ERROR")
+			return
+		}
+
+		if record.Completed() {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, record.ContentType, record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError || writer.overflow {
+			if err := idempotencyStore.Release(record); err != nil {
+				logger.Warn("IDEMPOTENCY: Failed to release idempotency key", map[string]interface{}{
+					"path":  path,
+					"error": err.Error(),
+				})
+			}
+			return
+		}
+		if err := idempotencyStore.Complete(record, status, writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			logger.Warn("IDEMPOTENCY: Failed to store response", map[string]interface{}{
+				"path":  path,
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// isMutatingMethod reports whether requests with method change state
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// requestFingerprint identifies a request by method, path and body (hex SHA-256)
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func abortIdempotency(c *gin.Context, status int, err error, code string) {
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+		"code":  code,
+	})
+	c.Abort()
+}
+
+// idempotencyWriter keeps a copy of the response body (up to idempotencyMaxBody) for replays
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool // Response too large to store
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *idempotencyWriter) capture(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(data) > idempotencyMaxBody {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+)
+
+// fakeIdempotencyStore mimics IdempotencyService in memory
+type fakeIdempotencyStore struct {
+	records map[string]*models.IdempotencyRecord
+	err     error
+}
+
+func (s *fakeIdempotencyStore) Begin(userID, key, method, path, fingerprint string) (*models.IdempotencyRecord, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if existing, ok := s.records[userID+"/"+key]; ok {
+		switch {
+		case existing.Fingerprint != fingerprint:
+			return nil, models.ErrIdempotencyKeyReused
+		case !existing.Completed():
+			return nil, models.ErrIdempotencyInProgress
+		}
+		return existing, nil
+	}
+	record := &models.IdempotencyRecord{UserID: userID, Key: key, Method: method, Path: path, Fingerprint: fingerprint}
+	s.records[userID+"/"+key] = record
+	return record, nil
+}
+
+func (s *fakeIdempotencyStore) Complete(record *models.IdempotencyRecord, statusCode int, contentType string, body []byte) error {
+	record.StatusCode, record.ContentType, record.ResponseBody = statusCode, contentType, body
+	return nil
+}
+
+func (s *fakeIdempotencyStore) Release(record *models.IdempotencyRecord) error {
+	delete(s.records, record.UserID+"/"+record.Key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &fakeIdempotencyStore{records: make(map[string]*models.IdempotencyRecord)}
+	SetIdempotencyService(store)
+	defer SetIdempotencyService(nil)
+
+	created := 0
+	failNext := false
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", "user-1") }, Idempotency())
+	router.POST("/api/servers", func(c *gin.Context) {
+		if failNext {
+			failNext = false
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "no capacity"})
+			return
+		}
+		created++
+		c.JSON(http.StatusCreated, gin.H{"id": created})
+	})
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/servers", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		body       string
+		failFirst  bool
+		wantStatus int
+		wantBody   string
+		wantReplay bool
+	}{
+		{"first request runs", "key-1", `{"name":"a"}`, false, http.StatusCreated, `{"id":1}`, false},
+		{"retry replays the response", "key-1", `{"name":"a"}`, false, http.StatusCreated, `{"id":1}`, true},
+		{"key reused for another request", "key-1", `{"name":"b"}`, false, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED", false},
+		{"without key every request runs", "", `{"name":"a"}`, false, http.StatusCreated, `{"id":2}`, false},
+		{"server error is not stored", "key-2", `{"name":"c"}`, true, http.StatusServiceUnavailable, "no capacity", false},
+		{"retry after server error runs again", "key-2", `{"name":"c"}`, false, http.StatusCreated, `{"id":3}`, false},
+		{"too long key", strings.Repeat("k", 256), `{}`, false, http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY", false},
+	}
+
+	for _, tt := range tests {
+		failNext = tt.failFirst
+		rec := send(tt.key, tt.body)
+		if rec.Code != tt.wantStatus || !strings.Contains(rec.Body.String(), tt.wantBody) {
+			t.Errorf("%s: got %d %s, want %d containing %s", tt.name, rec.Code, rec.Body.String(), tt.wantStatus, tt.wantBody)
+		}
+		if replayed := rec.Header().Get("Idempotent-Replayed") == "true"; replayed != tt.wantReplay {
+			t.Errorf("%s: Idempotent-Replayed = %v, want %v", tt.name, replayed, tt.wantReplay)
+		}
+	}
+
+	// A retry while the first request still runs is rejected
+	store.records["user-1/key-3"] = &models.IdempotencyRecord{UserID: "user-1", Key: "key-3", Fingerprint: requestFingerprint(http.MethodPost, "/api/servers", []byte(`{}`))}
+	if rec := send("key-3", `{}`); rec.Code != http.StatusConflict {
+		t.Errorf("in progress: got %d, want 409", rec.Code)
+	}
+
+	store.err = errors.New("database down")
+	if rec := send("key-4", `{}`); rec.Code != http.StatusInternalServerError {
+		t.Errorf("store error: got %d, want 500", rec.Code)
+	}
+	if created != 3 {
+		t.Errorf("handler ran %d times, want 3", created)
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceGate reports maintenance mode (implemented by service.MaintenanceService)
+type MaintenanceGate interface {
+	Check() error // Non-nil with the message for users while maintenance mode is on
+}
+
+// Maintenance rejects a request with 503 and the maintenance message while maintenance mode is on.
+// It guards server starts and destructive operations; reads, stops and everything else keep working.
+func Maintenance(gate MaintenanceGate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := gate.Check(); err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":       err.Error(),
+				"maintenance": true,
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -2,109 +2,71 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/ratelimit"
 )
 
-// RateLimiter implements a simple token bucket rate limiter
-type RateLimiter struct {
-	visitors map[string]*Visitor
-	mu       sync.RWMutex
-	rate     time.Duration
-	burst    int
+// rateLimitStore keeps the request counters of all limiters (in memory unless Redis is configured)
+var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+
+// SetRateLimitStore sets where request counters are kept (Redis shares them between API instances)
+func SetRateLimitStore(store ratelimit.Store) {
+	rateLimitStore = store
 }
 
-type Visitor struct {
-	tokens     int
-	lastSeen   time.Time
-	mu         sync.Mutex
+// RateLimiter applies one named limit, counted separately per client (API key, user or IP)
+type RateLimiter struct {
+	name  string
+	mu    sync.RWMutex
+	limit ratelimit.Limit
 }
 
 // NewRateLimiter creates a new rate limiter
-// rate: time between token refills
-// burst: maximum number of tokens
-func NewRateLimiter(rate time.Duration, burst int) *RateLimiter {
-	rl := &RateLimiter{
-		visitors: make(map[string]*Visitor),
-		rate:     rate,
-		burst:    burst,
+// perMinute: sustained requests per minute
+// burst: requests allowed at once before the sustained rate applies
+func NewRateLimiter(name string, perMinute, burst int) *RateLimiter {
+	return &RateLimiter{
+		name:  name,
+		limit: ratelimit.Limit{PerMinute: perMinute, Burst: burst},
 	}
-
-	// Cleanup old visitors every 5 minutes
-	go rl.cleanup()
-
-	return rl
 }
 
-// Allow checks if a request should be allowed
-func (rl *RateLimiter) Allow(ip string) bool {
+// SetLimit changes the limit (from configuration); perMinute 0 disables the limiter
+func (rl *RateLimiter) SetLimit(perMinute, burst int) {
 	rl.mu.Lock()
-	visitor, exists := rl.visitors[ip]
-	if !exists {
-		visitor = &Visitor{
-			tokens:   rl.burst,
-			lastSeen: time.Now(),
-		}
-		rl.visitors[ip] = visitor
-	}
-	rl.mu.Unlock()
-
-	visitor.mu.Lock()
-	defer visitor.mu.Unlock()
-
-	// Refill tokens based on time elapsed
-	now := time.Now()
-	elapsed := now.Sub(visitor.lastSeen)
-	tokensToAdd := int(elapsed / rl.rate)
-
-	if tokensToAdd > 0 {
-		visitor.tokens += tokensToAdd
-		if visitor.tokens > rl.burst {
-			visitor.tokens = rl.burst
-		}
-		visitor.lastSeen = now
-	}
-
-	// Check if we have tokens
-	if visitor.tokens > 0 {
-		visitor.tokens--
-		return true
-	}
+	defer rl.mu.Unlock()
+	rl.limit = ratelimit.Limit{PerMinute: perMinute, Burst: burst}
+}
 
-	return false
+// Limit returns the current limit
+func (rl *RateLimiter) Limit() ratelimit.Limit {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	return rl.limit
 }
 
-// cleanup removes old visitors
-func (rl *RateLimiter) cleanup() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		rl.mu.Lock()
-		for ip, visitor := range rl.visitors {
-			visitor.mu.Lock()
-			if time.Since(visitor.lastSeen) > 10*time.Minute {
-				delete(rl.visitors, ip)
-			}
-			visitor.mu.Unlock()
-		}
-		rl.mu.Unlock()
+// Take counts a request of client
+func (rl *RateLimiter) Take(client string) ratelimit.Result {
+	result, err := rateLimitStore.Take(rl.name+":"+client, rl.Limit(), time.Now())
+	if err != nil {
+		return ratelimit.Result{Allowed: true} // Don't reject requests because counting failed
 	}
+	return result
 }
 
 // RateLimitMiddleware creates a Gin middleware for rate limiting
+// Clients are identified by API key, then user, then IP, so limiters behind AuthMiddleware
+// count per account and limiters before it per IP.
 func RateLimitMiddleware(rl *RateLimiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-
-		if !rl.Allow(ip) {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "Rate limit exceeded",
-				"code":  "RATE_LIMIT_EXCEEDED",
-			})
-			c.Abort()
+		result := rl.Take(rateLimitClient(c))
+
+		if !result.Allowed {
+			abortRateLimited(c, "Rate limit exceeded", result)
 			return
 		}
 
@@ -112,20 +74,57 @@ func RateLimitMiddleware(rl *RateLimiter) gin.HandlerFunc {
 	}
 }
 
-// Different rate limiters for different endpoints
+// AllowRequest counts a request of the caller against rl, for limits that depend on the request
+// (e.g. its parameters) rather than the route. Returns false after aborting with 429 if it's over the limit.
+func AllowRequest(c *gin.Context, rl *RateLimiter) bool {
+	result := rl.Take(rateLimitClient(c))
+	if !result.Allowed {
+		abortRateLimited(c, "Rate limit exceeded", result)
+		return false
+	}
+	return true
+}
+
+// rateLimitClient identifies the caller of a request for rate limiting
+func rateLimitClient(c *gin.Context) string {
+	if key := CurrentAPIKey(c); key != nil {
+		return "key:" + key.ID
+	}
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// abortRateLimited responds 429 with a Retry-After header (whole seconds, at least 1)
+func abortRateLimited(c *gin.Context, message string, result ratelimit.Result) {
+	retryAfter := max(result.RetryAfterSeconds(), 1)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       message,
+		"code":        "RATE_LIMIT_EXCEEDED",
+		"retry_after": retryAfter,
+	})
+	c.Abort()
+}
+
+// Different rate limiters for different endpoints (defaults; main applies the RATE_LIMIT_* settings)
 var (
-	// Global rate limiter: 300 requests per minute (very lenient for GET requests)
-	GlobalRateLimiter = NewRateLimiter(200*time.Millisecond, 300)
+	// Global rate limiter: 300 requests per minute per IP (very lenient for GET requests)
+	GlobalRateLimiter = NewRateLimiter("global", 300, 300)
 
 	// API rate limiter: 120 requests per minute (2 per second for normal API operations)
-	APIRateLimiter = NewRateLimiter(500*time.Millisecond, 120)
+	APIRateLimiter = NewRateLimiter("api", 120, 120)
 
-	// Auth rate limiter: 5 requests per 15 seconds (strict for login/register to prevent brute force)
-	AuthRateLimiter = NewRateLimiter(3*time.Second, 5)
+	// Auth rate limiter: 20 requests per minute, 5 at once (strict for login/register to prevent brute force)
+	AuthRateLimiter = NewRateLimiter("auth", 20, 5)
 
 	// File upload operations: 30 requests per minute (icons, resource packs, etc.)
-	FileUploadRateLimiter = NewRateLimiter(2*time.Second, 30)
+	FileUploadRateLimiter = NewRateLimiter("upload", 30, 30)
+
+	// Expensive operations: 15 requests per minute (backups, restores, server starts, etc.)
+	ExpensiveRateLimiter = NewRateLimiter("expensive", 15, 15)
 
-	// Expensive operations: 15 requests per minute (backups, restores, etc.)
-	ExpensiveRateLimiter = NewRateLimiter(4*time.Second, 15)
+	// Remote actions: 30 per minute, 10 at once (bots and CI triggering server actions)
+	RemoteActionRateLimiter = NewRateLimiter("action", 30, 10)
 )
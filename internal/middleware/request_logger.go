@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/tracing"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
@@ -36,6 +37,11 @@ func RequestLogger() gin.HandlerFunc {
 			fields["user_id"] = userID
 		}
 
+		// Trace ID links the log line to the request's spans
+		if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+			fields["trace_id"] = traceID
+		}
+
 		// Log based on status code
 		status := c.Writer.Status()
 		message := "HTTP request"
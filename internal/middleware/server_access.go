@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+)
+
+// ServerAccessInterface checks a permission of a user on a server (owner, organization role or share)
+type ServerAccessInterface interface {
+	AuthorizeServer(userID, serverID string, perm models.ServerPermission) error
+	ServerInOrganization(serverID, orgID string) bool // Organization API keys only reach their organization's servers
+}
+
+var serverAccess ServerAccessInterface
+
+// SetServerAccessService sets the service used by RequireServerPermission
+func SetServerAccessService(svc ServerAccessInterface) {
+	serverAccess = svc
+}
+
+// RequireServerPermission checks that the user has perm on the server in the :id route parameter
+// The server owner has every permission; platform admins bypass the check.
+// API keys additionally need the scope of perm (see models.ServerPermissionScope).
+func RequireServerPermission(perm models.ServerPermission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if AuthorizeServerRequest(c, perm) {
+			c.Next()
+		}
+	}
+}
+
+// AuthorizeServerRequest applies the check of RequireServerPermission inside a handler, for routes
+// whose permission depends on the request (e.g. the action of POST /api/servers/:id/actions/:action).
+// Returns false after aborting with the error response.
+func AuthorizeServerRequest(c *gin.Context, perm models.ServerPermission) bool {
+	if !checkAPIKeyServerAccess(c, perm) {
+		return false
+	}
+	if serverAccess == nil || c.GetBool("is_admin") {
+		return true
+	}
+
+	err := serverAccess.AuthorizeServer(c.GetString("user_id"), c.Param("id"), perm)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, models.ErrServerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "server not found",
+			"code":  "NOT_FOUND",
+		})
+	case errors.Is(err, models.ErrServerForbidden):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "This action requires the '" + string(perm) + "' permission on this server",
+			"code":  "FORBIDDEN",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to check server permissions",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+	c.Abort()
+	return false
+}
+
+// AuthorizeServerAccess checks perm on a server outside of :id routes (e.g. GraphQL resolvers)
+// It applies the same rules as RequireServerPermission and returns models.ErrServerNotFound
+// or models.ErrServerForbidden instead of aborting the request.
+func AuthorizeServerAccess(c *gin.Context, serverID string, perm models.ServerPermission) error {
+	if key := CurrentAPIKey(c); key != nil {
+		scope, ok := models.ServerPermissionScope[perm]
+		if !ok || !key.HasScope(scope) {
+			return models.ErrServerForbidden
+		}
+		if key.OrganizationID != "" && (serverAccess == nil || !serverAccess.ServerInOrganization(serverID, key.OrganizationID)) {
+			return models.ErrServerNotFound
+		}
+	}
+	if serverAccess == nil || c.GetBool("is_admin") {
+		return nil
+	}
+	return serverAccess.AuthorizeServer(c.GetString("user_id"), serverID, perm)
+}
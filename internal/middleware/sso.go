@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/service"
+)
+
+// SSOSessionPolicy enforces organization SSO requirements on session tokens (implemented by SSOService)
+type SSOSessionPolicy interface {
+	CheckSession(claims *service.Claims) error
+}
+
+var ssoSessionPolicy SSOSessionPolicy
+
+// SetSSOSessionPolicy sets the policy AuthMiddleware applies to session tokens
+func SetSSOSessionPolicy(policy SSOSessionPolicy) {
+	ssoSessionPolicy = policy
+}
+
+// checkSSOSession applies the SSO session policy to validated claims
+// Writes the error response and returns false if the session isn't allowed.
+func checkSSOSession(c *gin.Context, claims *service.Claims) bool {
+	if ssoSessionPolicy == nil {
+		return true
+	}
+
+	err := ssoSessionPolicy.CheckSession(claims)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, models.ErrSSORequired):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Your organization requires single sign-on, sign in via SSO",
+			"code":  "SSO_REQUIRED",
+		})
+	case errors.Is(err, models.ErrSSOSessionExpired):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": err.Error(),
+			"code":  "SSO_SESSION_EXPIRED",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to check session policy",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+	c.Abort()
+	return false
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestTracingContinuesCallerTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var handlerTraceID string
+	router := gin.New()
+	router.Use(Tracing())
+	router.GET("/api/servers/:id", func(c *gin.Context) {
+		handlerTraceID = tracing.TraceID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/servers/server-1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	const want = "4bf92f3577b34da6a3ce929d0e0e4736"
+	if got := w.Header().Get("X-Trace-ID"); got != want {
+		t.Errorf("X-Trace-ID = %q, want %q", got, want)
+	}
+	if handlerTraceID != want {
+		t.Errorf("handler trace ID = %q, want %q", handlerTraceID, want)
+	}
+
+	// Without a caller trace and without an exporter nothing is recorded
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/servers/server-1", nil))
+	if got := w.Header().Get("X-Trace-ID"); got != "" {
+		t.Errorf("X-Trace-ID without trace = %q, want none", got)
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/payperplay/hosting/internal/models"
+)
+
+// TwoFactorCodeHeader carries the TOTP or recovery code for sensitive operations
+const TwoFactorCodeHeader = "X-2FA-Code"
+
+// SensitiveOperationGuard checks the second factor for sensitive operations (implemented by SecurityService)
+type SensitiveOperationGuard interface {
+	CheckSensitiveOperation(userID string, op models.SensitiveOperation, code, ipAddress, userAgent string) error
+}
+
+var sensitiveOperationGuard SensitiveOperationGuard
+
+// SetSensitiveOperationGuard sets the guard used by RequireTwoFactor
+func SetSensitiveOperationGuard(guard SensitiveOperationGuard) {
+	sensitiveOperationGuard = guard
+}
+
+// RequireTwoFactor requires a valid code in the X-2FA-Code header if the user has 2FA enabled
+// API key requests skip the check: creating the key already required 2FA.
+func RequireTwoFactor(op models.SensitiveOperation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if CheckTwoFactor(c, op) {
+			c.Next()
+		}
+	}
+}
+
+// CheckTwoFactor applies the RequireTwoFactor check inside a handler, for requests that are only
+// sensitive depending on their body. Returns false after aborting with 403 if the code is missing or wrong.
+func CheckTwoFactor(c *gin.Context, op models.SensitiveOperation) bool {
+	if sensitiveOperationGuard == nil || CurrentAPIKey(c) != nil {
+		return true
+	}
+
+	err := sensitiveOperationGuard.CheckSensitiveOperation(c.GetString("user_id"), op,
+		c.GetHeader(TwoFactorCodeHeader), c.ClientIP(), c.Request.UserAgent())
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, models.ErrTwoFactorRequired):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "This action requires a two-factor authentication code (" + TwoFactorCodeHeader + " header)",
+			"code":  "TWO_FACTOR_REQUIRED",
+		})
+	case errors.Is(err, models.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Invalid two-factor authentication code",
+			"code":  "INVALID_TWO_FACTOR_CODE",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to verify two-factor authentication",
+			"code":  "INTERNAL_ERROR",
+		})
+	}
+	c.Abort()
+	return false
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webdavRealm is the Basic auth realm WebDAV clients show when asking for credentials
+const webdavRealm = `Basic realm="PayPerPlay WebDAV", charset="UTF-8"`
+
+// WebDAVAuthMiddleware authenticates WebDAV clients, which only speak HTTP Basic auth
+// The password is an API key (or a JWT) and the user name is ignored. The API key needs the
+// files:read scope, plus files:write for changes. Requests without credentials get a Basic challenge
+// so that clients ask for them.
+func WebDAVAuthMiddleware() gin.HandlerFunc {
+	auth := AuthMiddleware()
+	return func(c *gin.Context) {
+		if _, password, ok := c.Request.BasicAuth(); ok {
+			c.Request.Header.Set("Authorization", "Bearer "+password)
+		} else if c.GetHeader("Authorization") == "" && c.GetHeader("X-API-Key") == "" {
+			c.Header("WWW-Authenticate", webdavRealm)
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Log in with an API key as password",
+				"code":  "UNAUTHORIZED",
+			})
+			c.Abort()
+			return
+		}
+
+		// Challenge again if the credentials are rejected; the WebDAV handler drops the header on success
+		c.Header("WWW-Authenticate", webdavRealm)
+		auth(c)
+	}
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Announcement states
+const (
+	AnnouncementScheduled = "scheduled"
+	AnnouncementSending   = "sending"
+	AnnouncementSent      = "sent"
+	AnnouncementCancelled = "cancelled"
+)
+
+// Announcement is a chat message broadcast to the running servers, e.g. before platform maintenance
+// Message is a text/template rendered per server (see service.AnnouncementData).
+type Announcement struct {
+	ID            uint               `gorm:"primaryKey" json:"id"`
+	Message       string             `gorm:"type:text;not null" json:"message"`
+	Filter        AnnouncementFilter `gorm:"serializer:json;type:text" json:"filter"`
+	MaintenanceAt *time.Time         `json:"maintenance_at,omitempty"` // Start of the announced maintenance, for the template
+	SendAt        time.Time          `gorm:"not null;index" json:"send_at"`
+	Status        string             `gorm:"size:16;not null;index" json:"status"`
+	CreatedBy     string             `gorm:"size:36" json:"created_by,omitempty"`
+
+	SentAt         *time.Time             `json:"sent_at,omitempty"`
+	Targeted       int                    `json:"targeted"`
+	Delivered      int                    `json:"delivered"`
+	Failed         int                    `json:"failed"`
+	PlayersReached int                    `json:"players_reached"` // Players online on the servers it was delivered to
+	Deliveries     []AnnouncementDelivery `gorm:"serializer:json;type:text" json:"deliveries,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (Announcement) TableName() string {
+	return "announcements"
+}
+
+// AnnouncementFilter limits the servers an announcement is sent to; empty fields match all running servers
+type AnnouncementFilter struct {
+	ServerIDs []string `json:"server_ids,omitempty"`
+	NodeIDs   []string `json:"node_ids,omitempty"`
+	Plans     []string `json:"plans,omitempty"`
+}
+
+// AnnouncementDelivery is the result of an announcement on one server
+type AnnouncementDelivery struct {
+	ServerID   string `json:"server_id"`
+	ServerName string `json:"server_name"`
+	NodeID     string `json:"node_id,omitempty"`
+	Players    int    `json:"players"`
+	Delivered  bool   `json:"delivered"`
+	Error      string `json:"error,omitempty"`
+}
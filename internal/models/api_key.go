@@ -0,0 +1,138 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyPrefix marks API keys so they can be told apart from JWTs in the Authorization header
+const APIKeyPrefix = "ppk_"
+
+// APIKeyScope limits what an API key may do (on top of the permissions of its user)
+type APIKeyScope string
+
+const (
+	ScopeServersRead    APIKeyScope = "servers:read"    // List servers, status, players, usage
+	ScopeServersWrite   APIKeyScope = "servers:write"   // Create, delete and manage servers (RAM, worlds, budgets, webhooks, restores)
+	ScopeServersStart   APIKeyScope = "servers:start"   // Start/stop, auto-shutdown, idle policies
+	ScopeServersConsole APIKeyScope = "servers:console" // Console, logs, commands, player lists
+	ScopeFilesRead      APIKeyScope = "files:read"      // Read configs, files and world downloads
+	ScopeFilesWrite     APIKeyScope = "files:write"     // Change configs, files, plugins, MOTD
+	ScopeBackupsRead    APIKeyScope = "backups:read"    // Backup details and quotas
+	ScopeBackupsWrite   APIKeyScope = "backups:write"   // Create/delete backups, backup schedules
+)
+
+// AllAPIKeyScopes lists every scope an API key can be granted
+var AllAPIKeyScopes = []APIKeyScope{
+	ScopeServersRead, ScopeServersWrite, ScopeServersStart, ScopeServersConsole,
+	ScopeFilesRead, ScopeFilesWrite, ScopeBackupsRead, ScopeBackupsWrite,
+}
+
+// ServerPermissionScope is the scope an API key needs for a server permission
+// Permissions without a scope (sharing) can't be used with API keys.
+var ServerPermissionScope = map[ServerPermission]APIKeyScope{
+	PermServerView:       ScopeServersRead,
+	PermServerConsole:    ScopeServersConsole,
+	PermServerFilesRead:  ScopeFilesRead,
+	PermServerFilesWrite: ScopeFilesWrite,
+	PermServerPower:      ScopeServersStart,
+	PermServerBackup:     ScopeBackupsWrite,
+	PermServerManage:     ScopeServersWrite,
+}
+
+// Valid returns true if s is a known scope
+func (s APIKeyScope) Valid() bool {
+	for _, scope := range AllAPIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey authenticates scripts and bots (CI, Discord) instead of a user session
+// Personal keys act as their user; organization keys act as their creator but only on the organization's servers.
+// Only the SHA-256 hash of the key is stored; the key itself is returned once on creation.
+type APIKey struct {
+	ID             string     `gorm:"primaryKey;size:36" json:"id"`
+	UserID         string     `gorm:"size:36;not null;index" json:"user_id"`           // Owner (personal) or creator (organization key)
+	OrganizationID string     `gorm:"size:36;default:'';index" json:"organization_id"` // Empty = personal key
+	Name           string     `gorm:"size:100;not null" json:"name"`
+	Prefix         string     `gorm:"size:16;not null" json:"prefix"` // First characters of the key, to recognize it in lists
+	KeyHash        string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	Scopes         string     `gorm:"size:255;not null" json:"-"`   // Comma-separated APIKeyScope values
+	RateLimit      int        `gorm:"default:60" json:"rate_limit"` // Requests per minute
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`         // nil = never expires
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP     string     `gorm:"size:45" json:"last_used_ip,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	ScopeList []APIKeyScope `gorm:"-" json:"scopes"`
+}
+
+// BeforeCreate hook to generate UUID
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == "" {
+		k.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// SetScopes stores a scope set
+func (k *APIKey) SetScopes(scopes []APIKeyScope) {
+	values := make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = string(scope)
+	}
+	k.Scopes = strings.Join(values, ",")
+	k.ScopeList = scopes
+}
+
+// LoadScopes fills ScopeList from the stored scope set
+func (k *APIKey) LoadScopes() {
+	k.ScopeList = nil
+	for _, value := range strings.Split(k.Scopes, ",") {
+		if value != "" {
+			k.ScopeList = append(k.ScopeList, APIKeyScope(value))
+		}
+	}
+}
+
+// HasScope returns true if the key was granted scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, value := range strings.Split(k.Scopes, ",") {
+		if APIKeyScope(value) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive returns true if the key is neither revoked nor expired at t
+func (k *APIKey) IsActive(t time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || t.Before(*k.ExpiresAt))
+}
+
+// API key errors
+var (
+	ErrAPIKeyInvalid          = errors.New("invalid, expired or revoked API key")
+	ErrAPIKeyNotFound         = errors.New("API key not found")
+	ErrAPIKeyInvalidScope     = errors.New("invalid scope (valid: servers:read, servers:write, servers:start, servers:console, files:read, files:write, backups:read, backups:write)")
+	ErrAPIKeyLimit            = errors.New("API key limit reached, revoke unused keys first")
+	ErrAPIKeyInvalidRateLimit = errors.New("rate limit exceeds the maximum requests per minute for API keys")
+	ErrAPIKeyRateLimited      = errors.New("API key rate limit exceeded")
+)
+
+// APIKeyRateLimitError is returned when a key exceeded its rate limit (errors.Is ErrAPIKeyRateLimited)
+type APIKeyRateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *APIKeyRateLimitError) Error() string { return ErrAPIKeyRateLimited.Error() }
+func (e *APIKeyRateLimitError) Unwrap() error { return ErrAPIKeyRateLimited }
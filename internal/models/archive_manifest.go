@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Parts of a server directory that can be restored from an archive on their own
+const (
+	ArchivePartWorld   = "world"   // World folders (every folder holding a level.dat)
+	ArchivePartPlugins = "plugins" // plugins/ and mods/ including their configs
+	ArchivePartConfig  = "config"  // Config files at the top level and config/
+	ArchivePartOther   = "other"   // Everything else: server jar, libraries, logs, caches
+)
+
+// ArchivePartsRestorable are the parts that can be requested in a selective restore
+var ArchivePartsRestorable = []string{ArchivePartWorld, ArchivePartPlugins, ArchivePartConfig}
+
+// ArchiveManifestFile is one file of an archive
+type ArchiveManifestFile struct {
+	Path   string `json:"path"` // Relative to the server directory, slash-separated
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ArchiveManifest lists the files of the current archive of a server
+// Written while archiving; unarchiving checks the extracted files against it.
+type ArchiveManifest struct {
+	ServerID    string                `gorm:"primaryKey;size:64" json:"server_id"`
+	ArchiveName string                `gorm:"size:255" json:"archive_name"` // File name of the archive the manifest describes
+	FileCount   int                   `json:"file_count"`
+	TotalBytes  int64                 `json:"total_bytes"` // Uncompressed
+	Files       []ArchiveManifestFile `gorm:"serializer:json;type:text" json:"files"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (ArchiveManifest) TableName() string {
+	return "archive_manifests"
+}
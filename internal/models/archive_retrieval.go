@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Archive tiers of an archived server (MinecraftServer.ArchiveTier)
+const (
+	ArchiveTierHot  = ""     // Storage Box, or local storage without one
+	ArchiveTierCold = "cold" // Object storage (S3 Glacier-style), restored before it can be read
+)
+
+// ArchiveRetrieval records an archive read back from cold storage, for the retrieval costs on invoices
+type ArchiveRetrieval struct {
+	ID           string     `gorm:"primaryKey;size:36" json:"id"`
+	ServerID     string     `gorm:"size:64;not null;index" json:"server_id"`
+	OwnerID      string     `gorm:"size:64;not null;index" json:"owner_id"`
+	SizeBytes    int64      `json:"size_bytes"`
+	StorageClass string     `gorm:"size:32" json:"storage_class"`
+	RequestedAt  *time.Time `json:"requested_at,omitempty"` // Restore request of an archive class object
+	CompletedAt  time.Time  `gorm:"index" json:"completed_at"`
+}
+
+// TableName specifies the table name
+func (ArchiveRetrieval) TableName() string {
+	return "archive_retrievals"
+}
+
+// BeforeCreate generates the retrieval ID
+func (r *ArchiveRetrieval) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// AuditEvent is a persisted audit log entry: who did what to which resource, and the
+// resource before and after the change. Node decommissions and provisioning by the
+// scaling policies are recorded with DecisionBy instead of an actor.
+type AuditEvent struct {
+	ID           string         `gorm:"primaryKey;size:36" json:"id"`
+	Action       string         `gorm:"size:64;not null;index" json:"action"`
+	ActorID      string         `gorm:"size:36;index" json:"actor_id,omitempty"`
+	ActorIP      string         `gorm:"size:64" json:"actor_ip,omitempty"`
+	DecisionBy   string         `gorm:"size:64" json:"decision_by,omitempty"`
+	ResourceType string         `gorm:"size:32;index:idx_audit_resource" json:"resource_type,omitempty"`
+	ResourceID   string         `gorm:"size:64;index:idx_audit_resource" json:"resource_id,omitempty"`
+	ContainerID  string         `gorm:"size:128" json:"container_id,omitempty"`
+	Reason       string         `gorm:"type:text" json:"reason,omitempty"`
+	Result       string         `gorm:"size:16" json:"result"` // "success", "rejected", "failed"
+	Error        string         `gorm:"type:text" json:"error,omitempty"`
+	Before       datatypes.JSON `gorm:"type:jsonb" json:"before,omitempty"`
+	After        datatypes.JSON `gorm:"type:jsonb" json:"after,omitempty"`
+	Snapshot     datatypes.JSON `gorm:"type:jsonb" json:"state_snapshot,omitempty"` // State the decision was based on
+	CreatedAt    time.Time      `gorm:"index" json:"created_at"`
+}
+
+// TableName specifies the table name
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// BeforeCreate generates the event ID
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -14,6 +14,10 @@ const (
 	BackupTypePreDeletion    BackupType = "pre-deletion"    // Backup before server deletion
 	BackupTypePreRestore     BackupType = "pre-restore"     // Backup before restoring from another backup
 	BackupTypePreUpdate      BackupType = "pre-update"      // Backup before major server update
+	BackupTypeClone          BackupType = "clone"           // Snapshot copied into a cloned server
+	BackupTypeTemplate       BackupType = "template"        // World snapshot of an owner-defined template (kept forever)
+	BackupTypePreReset       BackupType = "pre-reset"       // Backup before a world reset
+	BackupTypePreImport      BackupType = "pre-import"      // Backup before importing a world archive
 )
 
 // BackupStatus represents the status of a backup
@@ -25,6 +29,7 @@ const (
 	BackupStatusUploading  BackupStatus = "uploading"  // Upload to Storage Box in progress
 	BackupStatusCompleted  BackupStatus = "completed"  // Backup successful
 	BackupStatusFailed     BackupStatus = "failed"     // Backup failed
+	BackupStatusCancelled  BackupStatus = "cancelled"  // Backup cancelled by the user (partial archive removed)
 	BackupStatusDeleted    BackupStatus = "deleted"    // Backup deleted (retention policy)
 )
 
@@ -49,6 +54,8 @@ type Backup struct {
 	OriginalSize    int64  `gorm:"not null"`          // Size before compression in bytes
 	CompressionTime int    `gorm:"not null"`          // Time taken to compress (seconds)
 	UploadTime      int    `gorm:"not null"`          // Time taken to upload (seconds)
+	Compression      string `gorm:"size:16;default:'gzip'"` // Codec of the archive (gzip, zstd)
+	CompressionLevel int    `gorm:"default:0"`           // Codec level used (0 = codec default)
 
 	// Retention Policy
 	RetentionDays int        `gorm:"not null;default:7"` // Days to keep backup (0 = keep forever)
@@ -39,6 +39,10 @@ type BillingEvent struct {
 	// Cost metadata
 	HourlyRateEUR float64 // Rate at time of event (for historical accuracy)
 	DailyRateEUR  float64 // For storage billing (sleep phase)
+
+	// Outbox event this billing event was recorded for (nil for direct calls)
+	// Unique, so redelivered outbox events are only billed once.
+	IdempotencyKey *string `gorm:"size:64;uniqueIndex"`
 }
 
 // UsageSession represents a continuous period of server activity
@@ -64,6 +68,19 @@ type UsageSession struct {
 	DurationSeconds int     // Total session duration
 	CostEUR         float64 // Total cost for this session
 	HourlyRateEUR   float64 // Rate used for calculation
+
+	// Prepaid wallet deduction progress
+	WalletChargedEUR   float64    // Amount already deducted from the owner's wallet
+	WalletChargedUntil *time.Time // Session time covered by WalletChargedEUR
+
+	// Set for customer-dedicated node rental (ServerID = node ID, RAMMb = node RAM)
+	DedicatedNodeID string `gorm:"size:100;index"`
+}
+
+// UsageCostEUR calculates the cost of running ramMb for a duration (same formula as session billing)
+func UsageCostEUR(ramMb int, hourlyRateEUR float64, duration time.Duration) float64 {
+	ramGB := float64(ramMb) / 1024.0
+	return ramGB * duration.Hours() * hourlyRateEUR
 }
 
 // CostSummary provides aggregated cost information for a server
@@ -103,6 +120,14 @@ type PricingConfig struct {
 
 	// Phase 3: Archive (Stopped > 48h)
 	ArchiveRateEURPerGBDay float64 `json:"archive_rate_eur_per_gb_day"` // Default: 0.00 (free)
+
+	// Cold archive tier (idle > 30 days, object storage)
+	ColdArchiveRateEURPerGBDay   float64 `json:"cold_archive_rate_eur_per_gb_day"`  // Default: 0.00 (free)
+	ArchiveRetrievalRateEURPerGB float64 `json:"archive_retrieval_rate_eur_per_gb"` // Default: 0.01 per GB restored
+
+	// Backups & Migrations
+	BackupRateEURPerGBDay float64 `json:"backup_rate_eur_per_gb_day"` // Default: 0.00333 (same as sleep storage)
+	MigrationRateEURPerGB float64 `json:"migration_rate_eur_per_gb"`  // Default: 0.01 per GB of world data moved
 }
 
 // DefaultPricingConfig returns the default pricing configuration
@@ -111,6 +136,11 @@ func DefaultPricingConfig() PricingConfig {
 		ActiveRateEURPerGBHour: 0.02,    // 2 cents per GB-hour
 		SleepRateEURPerGBDay:   0.00333, // ~3.3 millicents per GB-day (~0.10/month)
 		ArchiveRateEURPerGBDay: 0.00,    // Free
+		BackupRateEURPerGBDay:  0.00333, // Compressed backup size on Storage Box
+		MigrationRateEURPerGB:  0.01,    // 1 cent per GB transferred
+
+		ColdArchiveRateEURPerGBDay:   0.00, // Free
+		ArchiveRetrievalRateEURPerGB: 0.01, // 1 cent per GB restored from cold storage
 	}
 }
 
@@ -118,3 +148,56 @@ func DefaultPricingConfig() PricingConfig {
 func (p PricingConfig) CalculateActiveMinuteRate(ramGB float64) float64 {
 	return (p.ActiveRateEURPerGBHour * ramGB) / 60.0
 }
+
+// DailyCostBreakdown is the cost of a server on one day, split by category
+type DailyCostBreakdown struct {
+	Date string `json:"date"` // YYYY-MM-DD
+
+	ComputeEUR   float64 `json:"compute_eur"`   // Node runtime share (RAM GB-hours of usage sessions)
+	BackupEUR    float64 `json:"backup_eur"`    // Backup storage (compressed GB-days)
+	ArchiveEUR   float64 `json:"archive_eur"`   // Archive storage (GB-days)
+	MigrationEUR float64 `json:"migration_eur"` // Migration transfer (GB moved)
+	TotalEUR     float64 `json:"total_eur"`
+
+	ColdArchiveEUR float64 `json:"cold_archive_eur"` // Cold archive storage (GB-days)
+	RetrievalEUR   float64 `json:"retrieval_eur"`    // Restores from cold storage (GB retrieved)
+
+	// Billed quantities
+	ComputeGBHours    float64 `json:"compute_gb_hours"`
+	BackupGBDays      float64 `json:"backup_gb_days"`
+	ArchiveGBDays     float64 `json:"archive_gb_days"`
+	MigrationGB       float64 `json:"migration_gb"`
+	ColdArchiveGBDays float64 `json:"cold_archive_gb_days"`
+	RetrievalGB       float64 `json:"retrieval_gb"`
+}
+
+// Add accumulates another breakdown into d (Date is kept)
+func (d *DailyCostBreakdown) Add(other DailyCostBreakdown) {
+	d.ComputeEUR += other.ComputeEUR
+	d.BackupEUR += other.BackupEUR
+	d.ArchiveEUR += other.ArchiveEUR
+	d.MigrationEUR += other.MigrationEUR
+	d.ColdArchiveEUR += other.ColdArchiveEUR
+	d.RetrievalEUR += other.RetrievalEUR
+	d.TotalEUR += other.TotalEUR
+	d.ComputeGBHours += other.ComputeGBHours
+	d.BackupGBDays += other.BackupGBDays
+	d.ArchiveGBDays += other.ArchiveGBDays
+	d.MigrationGB += other.MigrationGB
+	d.ColdArchiveGBDays += other.ColdArchiveGBDays
+	d.RetrievalGB += other.RetrievalGB
+}
+
+// CostBreakdown is the per-category cost of a server over a date range with daily resolution
+type CostBreakdown struct {
+	ServerID   string    `json:"server_id"`
+	ServerName string    `json:"server_name"`
+	OwnerID    string    `json:"owner_id"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"` // Exclusive
+
+	Days   []DailyCostBreakdown `json:"days"`
+	Totals DailyCostBreakdown   `json:"totals"`
+
+	Pricing PricingConfig `json:"pricing"`
+}
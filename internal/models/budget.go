@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// BudgetScope defines what a budget cap applies to
+type BudgetScope string
+
+const (
+	BudgetScopeUser   BudgetScope = "user"   // All servers of the owner
+	BudgetScopeServer BudgetScope = "server" // A single server
+)
+
+// BudgetAlertThresholds are the spend percentages that emit a warning event (once per month each)
+var BudgetAlertThresholds = []int{50, 80, 100}
+
+// BudgetCap is a monthly spend limit for a user or a single server
+// Accrued cost is the calendar-month cost from BillingService (completed sessions + running session).
+type BudgetCap struct {
+	ID       uint        `gorm:"primaryKey" json:"id"`
+	Scope    BudgetScope `gorm:"size:10;not null;uniqueIndex:idx_budget_scope_target" json:"scope"`
+	UserID   string      `gorm:"size:36;not null;index" json:"user_id"`
+	ServerID string      `gorm:"size:64;default:'';index" json:"server_id,omitempty"`           // Empty for user scope
+	TargetID string      `gorm:"size:64;not null;uniqueIndex:idx_budget_scope_target" json:"-"` // UserID or ServerID, keeps one cap per target
+
+	MonthlyLimitEUR float64 `gorm:"not null" json:"monthly_limit_eur"`
+	AutoStop        bool    `gorm:"default:false" json:"auto_stop"`    // Stop running servers when the cap is reached
+	BlockStarts     bool    `gorm:"default:false" json:"block_starts"` // Reject starts while the cap is reached
+
+	// Alert state for the current period (reset when Period changes)
+	Period           string     `gorm:"size:7" json:"period"` // YYYY-MM
+	LastAlertPercent int        `gorm:"default:0" json:"last_alert_percent"`
+	LastAccruedEUR   float64    `json:"last_accrued_eur"`
+	LastCheckedAt    *time.Time `json:"last_checked_at,omitempty"`
+
+	// Admin override: enforcement (auto-stop / block) is suspended until OverrideUntil
+	OverrideUntil  *time.Time `json:"override_until,omitempty"`
+	OverrideBy     string     `gorm:"size:36" json:"override_by,omitempty"`
+	OverrideReason string     `gorm:"size:255" json:"override_reason,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsOverridden returns true if an admin override is active at t
+func (b *BudgetCap) IsOverridden(t time.Time) bool {
+	return b.OverrideUntil != nil && t.Before(*b.OverrideUntil)
+}
+
+// UsagePercent returns accrued cost as a percentage of the limit
+func (b *BudgetCap) UsagePercent(accruedEUR float64) float64 {
+	if b.MonthlyLimitEUR <= 0 {
+		return 0
+	}
+	return accruedEUR / b.MonthlyLimitEUR * 100
+}
+
+// BudgetPeriod returns the budget period key (YYYY-MM) for t
+func BudgetPeriod(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+// BudgetAlert records a threshold crossing of a budget cap
+type BudgetAlert struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	BudgetCapID uint        `gorm:"not null;index" json:"budget_cap_id"`
+	Scope       BudgetScope `gorm:"size:10" json:"scope"`
+	UserID      string      `gorm:"size:36;not null;index" json:"user_id"`
+	ServerID    string      `gorm:"size:64;index" json:"server_id,omitempty"`
+
+	Period     string  `gorm:"size:7;index" json:"period"`
+	Percent    int     `json:"percent"` // Threshold crossed (50, 80, 100)
+	AccruedEUR float64 `json:"accrued_eur"`
+	LimitEUR   float64 `json:"limit_eur"`
+	Action     string  `gorm:"size:32" json:"action"` // "warning", "auto_stop", "override_active"
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "errors"
+
+// CPU limit bounds (docker accepts --cpu-shares 2-262144)
+const (
+	MinCPUShares     = 2
+	MaxCPUShares     = 262144
+	MaxCPULimitCores = 64
+)
+
+// CPU limit errors
+var (
+	ErrInvalidCPUShares = errors.New("cpu_shares must be 0 (default) or between 2 and 262144")
+	ErrInvalidCPULimit  = errors.New("cpu_limit_cores must be between 0 (default) and 64")
+)
+
+// ValidateCPULimits checks per-server CPU settings set by an admin (0 = configured default)
+func ValidateCPULimits(shares int, limitCores float64) error {
+	if shares != 0 && (shares < MinCPUShares || shares > MaxCPUShares) {
+		return ErrInvalidCPUShares
+	}
+	if limitCores < 0 || limitCores > MaxCPULimitCores {
+		return ErrInvalidCPULimit
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// NodeLabelCountry overrides the country derived from a node's "location" label
+const NodeLabelCountry = "country"
+
+// locationCountries maps cloud locations (node "location" label) to ISO 3166-1 alpha-2 countries
+var locationCountries = map[string]string{
+	"nbg1": "DE", // Nuremberg
+	"fsn1": "DE", // Falkenstein
+	"hel1": "FI", // Helsinki
+	"ash":  "US", // Ashburn, VA
+	"hil":  "US", // Hillsboro, OR
+	"sin":  "SG", // Singapore
+}
+
+// residencyCountryPattern is an ISO 3166-1 alpha-2 country code
+var residencyCountryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// NormalizeResidencyCountry validates a country code for data residency ("" = no restriction)
+func NormalizeResidencyCountry(country string) (string, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country != "" && !residencyCountryPattern.MatchString(country) {
+		return "", ErrInvalidResidencyCountry
+	}
+	return country, nil
+}
+
+// NodeCountry returns the country a node is located in ("" if unknown)
+// An explicit "country" label wins over the country of the "location" label.
+func NodeCountry(labels map[string]string) string {
+	if country := labels[NodeLabelCountry]; country != "" {
+		return strings.ToUpper(country)
+	}
+	return locationCountries[labels["location"]]
+}
+
+// Data residency errors
+var (
+	ErrInvalidResidencyCountry = errors.New("invalid data residency country (ISO 3166-1 alpha-2 code, e.g. DE)")
+	ErrResidencyViolation      = errors.New("operation blocked by the organization's data residency")
+)
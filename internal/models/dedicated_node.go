@@ -0,0 +1,42 @@
+package models
+
+import "errors"
+
+// DedicatedNodeKey is the label and taint key of nodes reserved for one customer (value = owner ID)
+// Not to be confused with Node.Type "dedicated" (bare-metal hardware).
+const DedicatedNodeKey = "dedicated-customer"
+
+// DedicatedNodeTaint keeps servers of other customers off a node reserved for ownerID
+func DedicatedNodeTaint(ownerID string) NodeTaint {
+	return NodeTaint{Key: DedicatedNodeKey, Value: ownerID, Effect: TaintNoSchedule}
+}
+
+// DedicatedNodePlacement pins servers of ownerID to the nodes reserved for them
+func DedicatedNodePlacement(ownerID string) NodePlacement {
+	return NodePlacement{
+		Selector:    map[string]string{DedicatedNodeKey: ownerID},
+		Tolerations: []NodeToleration{{Key: DedicatedNodeKey, Value: ownerID}},
+	}
+}
+
+// DedicatedNodeInfo is the public view of a customer-dedicated node
+type DedicatedNodeInfo struct {
+	NodeID         string  `json:"node_id"`
+	Hostname       string  `json:"hostname"`
+	OwnerID        string  `json:"owner_id"`
+	HourlyPriceEUR float64 `json:"hourly_price_eur"`
+	TotalRAMMB     int     `json:"total_ram_mb"`
+	AllocatedRAMMB int     `json:"allocated_ram_mb"`
+	ContainerCount int     `json:"container_count"`
+	Healthy        bool    `json:"healthy"`
+}
+
+// Dedicated node errors
+var (
+	ErrNodeNotFound              = errors.New("node not found")
+	ErrNodeAlreadyDedicated      = errors.New("node is already dedicated to a customer")
+	ErrNodeNotDedicated          = errors.New("node is not dedicated to a customer")
+	ErrNodeNotDedicatable        = errors.New("system nodes can't be dedicated to a customer")
+	ErrNodeHostsOtherCustomers   = errors.New("node runs servers of other customers, move them first")
+	ErrInvalidDedicatedNodePrice = errors.New("dedicated node price must be positive (node has no hourly cost, set hourly_price_eur)")
+)
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// IdempotencyRecord stores the outcome of a mutating request sent with an Idempotency-Key header
+// Retries with the same key get the stored response instead of running the request again.
+type IdempotencyRecord struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID string `gorm:"size:36;not null;uniqueIndex:idx_idempotency_user_key,priority:1" json:"user_id"`
+	Key    string `gorm:"size:255;not null;uniqueIndex:idx_idempotency_user_key,priority:2" json:"key"`
+
+	Method      string `gorm:"size:10;not null" json:"method"`
+	Path        string `gorm:"size:512;not null" json:"path"`
+	Fingerprint string `gorm:"size:64;not null" json:"-"` // SHA-256 of method, path and body
+
+	// Stored response (StatusCode 0 while the first request is still running)
+	StatusCode   int    `gorm:"default:0" json:"status_code"`
+	ContentType  string `gorm:"size:128" json:"-"`
+	ResponseBody []byte `json:"-"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time  `gorm:"index" json:"expires_at"`
+}
+
+// Completed reports whether the response of the original request is stored
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Idempotency errors
+var (
+	ErrIdempotencyKeyReused    = errors.New("Idempotency-Key was already used for a different request")
+	ErrIdempotencyInProgress   = errors.New("a request with this Idempotency-Key is still in progress")
+	ErrIdempotencyInvalidKey   = errors.New("Idempotency-Key must be 1-255 characters")
+	ErrIdempotencyBodyTooLarge = errors.New("Idempotency-Key is not supported for request bodies over 1 MiB")
+)
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// IdleShutdownPolicy extends the single IdleTimeoutSeconds value with time-of-day,
+// restart grace and uptime rules. One policy per server; servers without a policy keep the
+// plain IdleTimeoutSeconds behaviour.
+type IdleShutdownPolicy struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	ServerID string `gorm:"size:64;not null;uniqueIndex" json:"server_id"`
+
+	// Day/Night timeouts (day timeout = MinecraftServer.IdleTimeoutSeconds)
+	Timezone            string `gorm:"size:64;default:'UTC'" json:"timezone"`  // IANA zone used for night window and daily uptime
+	NightTimeoutSeconds int    `gorm:"default:0" json:"night_timeout_seconds"` // 0 = same as day timeout
+	NightStartHour      int    `gorm:"default:22" json:"night_start_hour"`     // 0-23, inclusive
+	NightEndHour        int    `gorm:"default:8" json:"night_end_hour"`        // 0-23, exclusive
+
+	// Grace period after a (re)start before idle shutdown may trigger
+	RestartGraceSeconds int `gorm:"default:0" json:"restart_grace_seconds"`
+
+	// Minimum uptime per calendar day (in Timezone) before idle shutdown may trigger
+	MinDailyUptimeMinutes int `gorm:"default:0" json:"min_daily_uptime_minutes"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsNight reports whether t falls into the policy's night window (handles windows crossing midnight)
+func (p *IdleShutdownPolicy) IsNight(t time.Time) bool {
+	if p.NightStartHour == p.NightEndHour {
+		return false
+	}
+	hour := t.Hour()
+	if p.NightStartHour < p.NightEndHour {
+		return hour >= p.NightStartHour && hour < p.NightEndHour
+	}
+	return hour >= p.NightStartHour || hour < p.NightEndHour
+}
+
+// ShutdownDecision is the outcome of an idle policy evaluation
+type ShutdownDecision string
+
+const (
+	ShutdownDecisionShutdown ShutdownDecision = "shutdown"
+	ShutdownDecisionDeferred ShutdownDecision = "deferred"
+)
+
+// ShutdownAuditEntry records why MonitoringService stopped (or refused to stop) an idle server
+type ShutdownAuditEntry struct {
+	ID       uint             `gorm:"primaryKey" json:"id"`
+	ServerID string           `gorm:"size:64;not null;index" json:"server_id"`
+	Decision ShutdownDecision `gorm:"size:16;not null" json:"decision"`
+	Rule     string           `gorm:"size:64;not null" json:"rule"` // idle_timeout_day, idle_timeout_night, restart_grace, min_daily_uptime
+	Reason   string           `gorm:"size:512" json:"reason"`
+
+	IdleSeconds    int `json:"idle_seconds"`
+	TimeoutSeconds int `json:"timeout_seconds"`
+	PlayerCount    int `json:"player_count"`
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName overrides the table name
+func (ShutdownAuditEntry) TableName() string {
+	return "shutdown_audit_entries"
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IncidentStatus represents the state of a status page incident
+type IncidentStatus string
+
+const (
+	IncidentStatusInvestigating IncidentStatus = "investigating"
+	IncidentStatusResolved      IncidentStatus = "resolved"
+)
+
+// Incident is a platform incident shown on the public status page
+// Incidents are opened and resolved by firing/resolved alerts (Alertmanager webhook).
+type Incident struct {
+	ID          string         `gorm:"primaryKey;size:36" json:"id"`
+	Fingerprint string         `gorm:"size:64;index" json:"-"` // Alertmanager alert fingerprint (dedupes repeated notifications)
+	AlertName   string         `gorm:"size:128" json:"-"`
+	Title       string         `gorm:"size:255;not null" json:"title"`
+	Description string         `gorm:"type:text" json:"description"`
+	Severity    string         `gorm:"size:20" json:"severity"`  // warning, critical
+	Component   string         `gorm:"size:64" json:"component"` // nodes, servers, backups, billing, platform
+	Status      IncidentStatus `gorm:"size:20;not null;index" json:"status"`
+	StartedAt   time.Time      `gorm:"not null;index" json:"started_at"`
+	ResolvedAt  *time.Time     `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time      `json:"-"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (Incident) TableName() string {
+	return "incidents"
+}
+
+// BeforeCreate generates the ID
+func (i *Incident) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvoiceLineCategory identifies what an invoice line charges for
+type InvoiceLineCategory string
+
+const (
+	InvoiceLineCompute   InvoiceLineCategory = "compute"   // Server runtime (RAM GB-hours)
+	InvoiceLineBackup    InvoiceLineCategory = "backup"    // Backup storage (GB-days)
+	InvoiceLineArchive   InvoiceLineCategory = "archive"   // Archive storage (GB-days)
+	InvoiceLineMigration InvoiceLineCategory = "migration" // Migration transfer (GB)
+
+	InvoiceLineColdArchive      InvoiceLineCategory = "cold_archive"      // Cold archive storage (GB-days)
+	InvoiceLineArchiveRetrieval InvoiceLineCategory = "archive_retrieval" // Restores from cold storage (GB)
+)
+
+// Invoice is a generated invoice document for one billing period (calendar month)
+// Amounts are stored so re-rendering the PDF/CSV always yields the issued document.
+type Invoice struct {
+	ID     string `gorm:"primaryKey;size:36" json:"id"`
+	Number string `gorm:"size:32;uniqueIndex;not null" json:"number"` // e.g. PPH-2025-000042
+	UserID string `gorm:"size:36;not null;uniqueIndex:idx_invoice_user_period" json:"user_id"`
+	Period string `gorm:"size:7;not null;uniqueIndex:idx_invoice_user_period" json:"period"` // YYYY-MM
+
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"` // Exclusive
+	IssuedAt    time.Time `json:"issued_at"`
+
+	// Customer snapshot at issue time
+	CustomerName    string `gorm:"size:255" json:"customer_name"`
+	CustomerEmail   string `gorm:"size:255" json:"customer_email"`
+	CustomerAddress string `gorm:"size:512" json:"customer_address"`
+	CustomerCountry string `gorm:"size:2" json:"customer_country"`
+	CustomerVATID   string `gorm:"size:32" json:"customer_vat_id,omitempty"`
+
+	// VAT
+	VATRate          float64 `json:"vat_rate"` // Percent, e.g. 19
+	VATReverseCharge bool    `json:"vat_reverse_charge"`
+	VATNote          string  `gorm:"size:255" json:"vat_note,omitempty"`
+
+	NetEUR   float64 `json:"net_eur"`
+	VATEUR   float64 `json:"vat_eur"`
+	GrossEUR float64 `json:"gross_eur"`
+
+	Lines []InvoiceLine `gorm:"foreignKey:InvoiceID;constraint:OnDelete:CASCADE" json:"lines,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (i *Invoice) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// InvoiceLine is a single charge on an invoice (one category of one server)
+type InvoiceLine struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	InvoiceID string `gorm:"size:36;not null;index" json:"invoice_id"`
+	Position  int    `json:"position"`
+
+	ServerID    string              `gorm:"size:64" json:"server_id"`
+	ServerName  string              `gorm:"size:256" json:"server_name"`
+	Category    InvoiceLineCategory `gorm:"size:20" json:"category"`
+	Description string              `gorm:"size:255" json:"description"`
+
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `gorm:"size:16" json:"unit"` // GB-h, GB-d, GB
+	UnitPriceEUR float64 `json:"unit_price_eur"`
+	AmountEUR    float64 `json:"amount_eur"` // Net, rounded to cents
+}
+
+// EUVATRates are the standard VAT rates (percent) of EU member states
+var EUVATRates = map[string]float64{
+	"AT": 20, "BE": 21, "BG": 20, "HR": 25, "CY": 19, "CZ": 21, "DK": 25,
+	"EE": 24, "FI": 25.5, "FR": 20, "DE": 19, "GR": 24, "HU": 27, "IE": 23,
+	"IT": 22, "LV": 21, "LT": 21, "LU": 17, "MT": 18, "NL": 21, "PL": 23,
+	"PT": 23, "RO": 21, "SK": 23, "SI": 22, "ES": 21, "SE": 25,
+}
+
+// VATTreatment determines the VAT rate for a customer (electronically supplied services, B2C place of supply = customer country)
+// Returns the rate in percent, whether the reverse charge mechanism applies, and a note printed on the invoice.
+func VATTreatment(sellerCountry, customerCountry, customerVATID string) (float64, bool, string) {
+	seller := strings.ToUpper(sellerCountry)
+	customer := strings.ToUpper(customerCountry)
+	if customer == "" {
+		customer = seller // Unknown country: treat as domestic
+	}
+
+	rate, customerInEU := EUVATRates[customer]
+	if !customerInEU {
+		return 0, false, "Not subject to EU VAT (place of supply outside the EU)"
+	}
+
+	if customer != seller && customerVATID != "" {
+		return 0, true, "Reverse charge: VAT to be accounted for by the recipient (Art. 196 Directive 2006/112/EC)"
+	}
+
+	return rate, false, ""
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Job is the persisted record of a background operation (backup, restore, migration, archive, bulk job)
+// Operations run in memory (see service.OperationLimiter and service.BulkJobService); every status
+// and progress change is written here so the history survives API restarts and can be polled.
+type Job struct {
+	ID          string `gorm:"primaryKey;size:36" json:"id"` // Operation or bulk job ID
+	OwnerID     string `gorm:"size:36;index" json:"owner_id"`
+	RequestedBy string `gorm:"size:36;index" json:"requested_by,omitempty"`
+	Kind        string `gorm:"size:32;index" json:"kind"` // start, backup, restore, migration, archive, clone, provision, world_reset, world_export, world_import, pregeneration, bulk_*
+	ServerID    string `gorm:"size:36;index" json:"server_id,omitempty"`
+	ResourceID  string `gorm:"size:64" json:"resource_id,omitempty"` // Backup, migration or other resource the job works on
+
+	Status      string `gorm:"size:16;index" json:"status"` // queued, running, cancelling, completed, failed, cancelled (pregeneration also throttled, paused)
+	Phase       string `gorm:"size:32" json:"phase,omitempty"`
+	Progress    int    `json:"progress"` // Percent of the current phase
+	Error       string `gorm:"type:text" json:"error,omitempty"`
+	Cancellable bool   `json:"cancellable"`
+
+	CreatedAt  time.Time  `gorm:"index" json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `gorm:"index" json:"finished_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Finished reports whether the job reached a final status
+func (j *Job) Finished() bool {
+	return j.FinishedAt != nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BackupLegalHold protects backups from deletion (retention cleanup, schedule rotation and the
+// delete API) until an admin releases it. A hold without BackupID covers every backup of the
+// server, including backups created while the hold is active.
+// Released holds are kept as hold history.
+type BackupLegalHold struct {
+	ID       string `gorm:"primaryKey;size:36" json:"id"`
+	ServerID string `gorm:"size:64;not null;index" json:"server_id"`
+	BackupID string `gorm:"size:36;default:'';index" json:"backup_id,omitempty"` // Empty = whole server
+	Reason   string `gorm:"size:500;not null" json:"reason"`
+
+	PlacedBy   string     `gorm:"size:36;not null" json:"placed_by"` // Admin user ID
+	PlacedAt   time.Time  `gorm:"not null" json:"placed_at"`
+	ReleasedBy string     `gorm:"size:36;default:''" json:"released_by,omitempty"`
+	ReleasedAt *time.Time `gorm:"index" json:"released_at,omitempty"` // nil = active
+}
+
+// TableName specifies the table name
+func (BackupLegalHold) TableName() string {
+	return "backup_legal_holds"
+}
+
+// BeforeCreate generates the ID and placement time
+func (h *BackupLegalHold) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == "" {
+		h.ID = uuid.New().String()
+	}
+	if h.PlacedAt.IsZero() {
+		h.PlacedAt = time.Now()
+	}
+	return nil
+}
+
+// IsActive reports whether the hold has not been released
+func (h *BackupLegalHold) IsActive() bool {
+	return h.ReleasedAt == nil
+}
+
+// CoversServer reports whether the hold applies to all backups of its server
+func (h *BackupLegalHold) CoversServer() bool {
+	return h.BackupID == ""
+}
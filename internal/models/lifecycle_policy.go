@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Lifecycle transitions of a stopped server (see LifecyclePolicy)
+const (
+	LifecycleTransitionSleep   = "sleep"   // stopped -> sleeping
+	LifecycleTransitionArchive = "archive" // sleeping -> archived (Storage Box)
+	LifecycleTransitionCold    = "cold"    // archived -> cold storage
+)
+
+// LifecyclePolicy overrides the lifecycle thresholds of a plan (Plan set, admin) or of a server (ServerID set, owner)
+// nil fields inherit: server -> plan -> built-in default. 0 disables the transition (sleep: immediately).
+type LifecyclePolicy struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Plan     string `gorm:"size:20;uniqueIndex:idx_lifecycle_policy_scope" json:"plan,omitempty"`
+	ServerID string `gorm:"size:64;uniqueIndex:idx_lifecycle_policy_scope" json:"server_id,omitempty"`
+
+	SleepAfterMinutes *int `json:"sleep_after_minutes"` // Stopped -> sleeping
+	ArchiveAfterHours *int `json:"archive_after_hours"` // Stopped -> archived, 0 = never
+	ColdAfterDays     *int `json:"cold_after_days"`     // Stopped -> archive in cold storage, 0 = never
+
+	UpdatedBy string    `gorm:"size:36" json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (LifecyclePolicy) TableName() string {
+	return "lifecycle_policies"
+}
+
+// LifecycleNotice records an email announcing a lifecycle transition, once per transition and stop
+type LifecycleNotice struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ServerID   string    `gorm:"size:64;not null;uniqueIndex:idx_lifecycle_notice" json:"server_id"`
+	Transition string    `gorm:"size:16;not null;uniqueIndex:idx_lifecycle_notice" json:"transition"`
+	IdleSince  time.Time `gorm:"not null;uniqueIndex:idx_lifecycle_notice" json:"idle_since"` // LastStoppedAt the notice belongs to
+	DueAt      time.Time `json:"due_at"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+// TableName specifies the table name
+func (LifecycleNotice) TableName() string {
+	return "lifecycle_notices"
+}
@@ -29,6 +29,9 @@ const (
 	MigrationReasonManual           MigrationReason = "manual"            // Manual admin request
 	MigrationReasonRebalancing      MigrationReason = "rebalancing"       // Load rebalancing
 	MigrationReasonMaintenance      MigrationReason = "maintenance"       // Node maintenance
+	MigrationReasonDiskPressure     MigrationReason = "disk-pressure"     // Node low on free disk
+	MigrationReasonFailover         MigrationReason = "failover"          // Node failed, restored from the latest backup
+	MigrationReasonEviction         MigrationReason = "eviction"          // Interruptible node about to be terminated by the provider
 )
 
 // Migration represents a server migration between nodes
@@ -59,8 +62,11 @@ type Migration struct {
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 
 	// Progress tracking
-	PlayerCountAtStart int `gorm:"default:0" json:"player_count_at_start"`
-	DataSyncProgress   int `gorm:"default:0" json:"data_sync_progress"` // 0-100%
+	PlayerCountAtStart int   `gorm:"default:0" json:"player_count_at_start"`
+	DataSyncProgress   int   `gorm:"default:0" json:"data_sync_progress"` // 0-100%
+	TransferBytes      int64 `gorm:"default:0" json:"transfer_bytes"`     // World data moved between nodes (billed as migration transfer)
+	WorldSizeBytes     int64 `gorm:"default:0" json:"world_size_bytes"`   // World data size at migration time (analytics)
+	DowntimeMs         int64 `gorm:"default:0" json:"downtime_ms"`        // Player-facing interruption while the proxy switched nodes
 
 	// Error handling
 	ErrorMessage string `gorm:"type:text" json:"error_message,omitempty"`
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// MinecraftLink links a platform user to a Minecraft account (one account per user and vice versa)
+// A link starts pending: the code is whispered to the player in-game and only its SHA-256 hash is
+// stored. Confirming the code proves the user controls the account and makes the link verified.
+type MinecraftLink struct {
+	UserID        string     `gorm:"primaryKey;size:36" json:"user_id"`
+	MinecraftUUID string     `gorm:"size:36;index" json:"minecraft_uuid,omitempty"` // Empty until verified
+	MinecraftName string     `gorm:"size:16" json:"minecraft_name,omitempty"`
+	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
+	AutoOp        bool       `gorm:"not null;default:false" json:"auto_op"` // Op the player when the user's servers start
+
+	PendingUUID   string     `gorm:"size:36" json:"pending_uuid,omitempty"`
+	PendingName   string     `gorm:"size:16" json:"pending_name,omitempty"`
+	CodeHash      string     `gorm:"size:64" json:"-"`
+	CodeExpiresAt *time.Time `json:"code_expires_at,omitempty"`
+	CodeAttempts  int        `gorm:"not null;default:0" json:"-"`
+
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (MinecraftLink) TableName() string {
+	return "minecraft_links"
+}
+
+// Verified reports whether the link has a confirmed Minecraft account
+func (l *MinecraftLink) Verified() bool {
+	return l != nil && l.VerifiedAt != nil && l.MinecraftUUID != ""
+}
+
+// Minecraft account linking errors
+var (
+	ErrMinecraftLinkNotStarted = errors.New("start linking a Minecraft account first")
+	ErrMinecraftLinkCode       = errors.New("invalid or expired link code")
+	ErrMinecraftLinkNotLinked  = errors.New("no Minecraft account linked")
+	ErrMinecraftPlayerOffline  = errors.New("the player is not online on any of your running servers, join one and try again")
+	ErrMinecraftLinkTooSoon    = errors.New("a link code was sent recently, wait a minute before requesting another")
+)
@@ -29,6 +29,10 @@ type Node struct {
 	LastContainerRemoved time.Time `json:"last_container_removed"`
 	HourlyCostEUR        float64   `gorm:"type:decimal(10,4);default:0" json:"hourly_cost_eur"`
 	CloudProviderID      string    `gorm:"size:100;index" json:"cloud_provider_id"` // External provider ID (e.g., Hetzner server ID)
+	Labels               string    `gorm:"size:1024;default:''" json:"labels"` // Scheduling labels "key=value,..."
+	Taints               string    `gorm:"size:1024;default:''" json:"taints"` // Scheduling taints "key[=value]:Effect,..."
+	DedicatedOwnerID     string    `gorm:"size:64;default:'';index" json:"dedicated_owner_id"` // Customer the node is reserved for (empty = shared)
+	DedicatedPriceEUR    float64   `gorm:"type:decimal(10,4);default:0" json:"dedicated_price_eur"` // Hourly price billed to that customer
 
 	// Additional metadata stored as JSON
 	CPUUsagePercent float64 `gorm:"-" json:"cpu_usage_percent"` // Runtime metric, not persisted
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NodeCostSample is the cost of one cloud node at one run of the fleet cost reconciliation: the
+// hourly cost the fleet estimates for it and the hourly price the provider bills. The samples of a
+// node make up its cost timeline; nodes only known to one side are sampled too.
+type NodeCostSample struct {
+	ID                 string    `gorm:"primaryKey;size:36" json:"id"`
+	NodeID             string    `gorm:"size:100;not null;index:idx_node_cost_samples_node" json:"node_id"` // Provider server ID
+	SampledAt          time.Time `gorm:"not null;index;index:idx_node_cost_samples_node" json:"sampled_at"`
+	Hostname           string    `gorm:"size:255" json:"hostname"`
+	ServerType         string    `gorm:"size:32" json:"server_type,omitempty"`
+	Location           string    `gorm:"size:32" json:"location,omitempty"`
+	InFleet            bool      `gorm:"not null;default:false" json:"in_fleet"`    // Registered as a node
+	AtProvider         bool      `gorm:"not null;default:false" json:"at_provider"` // Billed by the provider
+	EstimatedHourlyEUR float64   `gorm:"type:decimal(10,4);default:0" json:"estimated_hourly_eur"`
+	ProviderHourlyEUR  float64   `gorm:"type:decimal(10,4);default:0" json:"provider_hourly_eur"`
+}
+
+// TableName specifies the table name
+func (NodeCostSample) TableName() string {
+	return "node_cost_samples"
+}
+
+// BeforeCreate generates the sample ID
+func (s *NodeCostSample) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NodeTaintEffect defines how a taint repels servers that don't tolerate it
+type NodeTaintEffect string
+
+const (
+	TaintNoSchedule       NodeTaintEffect = "NoSchedule"       // Only servers tolerating the taint are placed on the node
+	TaintPreferNoSchedule NodeTaintEffect = "PreferNoSchedule" // Other servers are only placed there if no other node fits
+)
+
+// NodeLabelInterruptible marks nodes on interruptible (spot) instances the cloud provider can reclaim
+// at short notice ("interruptible=true"); reserved-plan servers are never placed on them.
+const NodeLabelInterruptible = "interruptible"
+
+// nodeLabelPattern restricts label/taint keys and values (e.g. "high-memory", "dedicated-customer", "acme")
+var nodeLabelPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._/-]{0,62}$`)
+
+// NodeTaint marks a node for specialized workloads, e.g. "modded-only:NoSchedule" or "dedicated-customer=acme:NoSchedule"
+type NodeTaint struct {
+	Key    string          `json:"key"`
+	Value  string          `json:"value,omitempty"`
+	Effect NodeTaintEffect `json:"effect"`
+}
+
+// String formats the taint as "key[=value]:Effect"
+func (t NodeTaint) String() string {
+	s := t.Key
+	if t.Value != "" {
+		s += "=" + t.Value
+	}
+	return s + ":" + string(t.Effect)
+}
+
+// NodeToleration allows a server onto nodes with a matching taint
+// An empty Value tolerates the key with any value.
+type NodeToleration struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// String formats the toleration as "key[=value]"
+func (t NodeToleration) String() string {
+	if t.Value == "" {
+		return t.Key
+	}
+	return t.Key + "=" + t.Value
+}
+
+// Tolerates returns true if the toleration matches taint
+func (t NodeToleration) Tolerates(taint NodeTaint) bool {
+	return t.Key == taint.Key && (t.Value == "" || t.Value == taint.Value)
+}
+
+// NodePlacement are the placement constraints of a server
+// Selector labels must all be present on a node; taints on a node must be tolerated.
+// Country restricts the server to nodes in that country (organization data residency).
+// PinnedNodeID restricts the server to that one node. AntiAffinityGroup and Spread keep the server
+// off nodes that already run one of its peers (servers of the owner in the same group, or any of
+// the owner's servers) unless every fitting node does; Peers counts them per node ID.
+// AvoidInterruptible keeps the server off interruptible nodes (reserved plan).
+type NodePlacement struct {
+	Selector           map[string]string `json:"node_selector,omitempty"`
+	Tolerations        []NodeToleration  `json:"tolerations,omitempty"`
+	Country            string            `json:"country,omitempty"`
+	PinnedNodeID       string            `json:"pinned_node_id,omitempty"`
+	AntiAffinityGroup  string            `json:"anti_affinity_group,omitempty"`
+	Spread             bool              `json:"spread,omitempty"`
+	AvoidInterruptible bool              `json:"avoid_interruptible,omitempty"`
+	Peers              map[string]int    `json:"-"` // Node ID -> peers running there (filled at placement time)
+}
+
+// IsEmpty returns true if the placement has no constraints
+func (p NodePlacement) IsEmpty() bool {
+	return len(p.Selector) == 0 && len(p.Tolerations) == 0 && p.Country == "" && p.PinnedNodeID == "" && !p.SpreadsPeers() && !p.AvoidInterruptible
+}
+
+// SpreadsPeers returns true if the server avoids nodes running its peers (anti-affinity group or spread)
+func (p NodePlacement) SpreadsPeers() bool {
+	return p.AntiAffinityGroup != "" || p.Spread
+}
+
+// PeersOn returns the number of the server's peers running on a node (0 if it doesn't spread)
+func (p NodePlacement) PeersOn(nodeID string) int {
+	if !p.SpreadsPeers() {
+		return 0
+	}
+	return p.Peers[nodeID]
+}
+
+// Merge returns the placement with the selector labels and tolerations of other added
+// Country, pinned node, anti-affinity group and peers of other override those of p if set;
+// Spread and AvoidInterruptible are kept if either sets them.
+func (p NodePlacement) Merge(other NodePlacement) NodePlacement {
+	merged := NodePlacement{
+		Selector:    make(map[string]string, len(p.Selector)+len(other.Selector)),
+		Tolerations: append(append([]NodeToleration{}, p.Tolerations...), other.Tolerations...),
+		Country:     p.Country,
+
+		PinnedNodeID:       p.PinnedNodeID,
+		AntiAffinityGroup:  p.AntiAffinityGroup,
+		Spread:             p.Spread || other.Spread,
+		AvoidInterruptible: p.AvoidInterruptible || other.AvoidInterruptible,
+		Peers:              p.Peers,
+	}
+	if other.Country != "" {
+		merged.Country = other.Country
+	}
+	if other.PinnedNodeID != "" {
+		merged.PinnedNodeID = other.PinnedNodeID
+	}
+	if other.AntiAffinityGroup != "" {
+		merged.AntiAffinityGroup = other.AntiAffinityGroup
+	}
+	if other.Peers != nil {
+		merged.Peers = other.Peers
+	}
+	for key, value := range p.Selector {
+		merged.Selector[key] = value
+	}
+	for key, value := range other.Selector {
+		merged.Selector[key] = value
+	}
+	return merged
+}
+
+// MatchesLabels returns true if labels contain every selector label (and place the node in Country, if set)
+// Interruptible nodes never match a placement that avoids them.
+func (p NodePlacement) MatchesLabels(labels map[string]string) bool {
+	if p.Country != "" && NodeCountry(labels) != p.Country {
+		return false
+	}
+	if p.AvoidInterruptible && labels[NodeLabelInterruptible] == "true" {
+		return false
+	}
+	for key, value := range p.Selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Tolerates returns true if one of the tolerations matches taint
+func (p NodePlacement) Tolerates(taint NodeTaint) bool {
+	for _, toleration := range p.Tolerations {
+		if toleration.Tolerates(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseNodeLabels parses "key=value,key2=value2" (a key without value is stored as "true", e.g. "high-memory")
+func ParseNodeLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, part := range splitPlacementList(s) {
+		key, value := part, "true"
+		if i := strings.Index(part, "="); i >= 0 {
+			key, value = part[:i], part[i+1:]
+		}
+		if !nodeLabelPattern.MatchString(key) || !nodeLabelPattern.MatchString(value) {
+			return nil, ErrInvalidNodeLabel
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// FormatNodeLabels formats labels as "key=value,..." (sorted for stable storage)
+func FormatNodeLabels(labels map[string]string) string {
+	parts := make([]string, 0, len(labels))
+	for key, value := range labels {
+		parts = append(parts, key+"="+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// ParseNodeTaints parses "key[=value]:Effect,..." (the effect defaults to NoSchedule)
+func ParseNodeTaints(s string) ([]NodeTaint, error) {
+	var taints []NodeTaint
+	for _, part := range splitPlacementList(s) {
+		taint := NodeTaint{Effect: TaintNoSchedule}
+		if i := strings.LastIndex(part, ":"); i >= 0 {
+			taint.Effect = NodeTaintEffect(part[i+1:])
+			part = part[:i]
+		}
+		if taint.Effect != TaintNoSchedule && taint.Effect != TaintPreferNoSchedule {
+			return nil, ErrInvalidNodeTaint
+		}
+		taint.Key = part
+		if i := strings.Index(part, "="); i >= 0 {
+			taint.Key, taint.Value = part[:i], part[i+1:]
+			if !nodeLabelPattern.MatchString(taint.Value) {
+				return nil, ErrInvalidNodeTaint
+			}
+		}
+		if !nodeLabelPattern.MatchString(taint.Key) {
+			return nil, ErrInvalidNodeTaint
+		}
+		taints = append(taints, taint)
+	}
+	return taints, nil
+}
+
+// FormatNodeTaints formats taints as "key[=value]:Effect,..."
+func FormatNodeTaints(taints []NodeTaint) string {
+	parts := make([]string, len(taints))
+	for i, taint := range taints {
+		parts[i] = taint.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseNodeTolerations parses "key[=value],..."
+func ParseNodeTolerations(s string) ([]NodeToleration, error) {
+	var tolerations []NodeToleration
+	for _, part := range splitPlacementList(s) {
+		toleration := NodeToleration{Key: part}
+		if i := strings.Index(part, "="); i >= 0 {
+			toleration.Key, toleration.Value = part[:i], part[i+1:]
+			if !nodeLabelPattern.MatchString(toleration.Value) {
+				return nil, ErrInvalidNodeToleration
+			}
+		}
+		if !nodeLabelPattern.MatchString(toleration.Key) {
+			return nil, ErrInvalidNodeToleration
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations, nil
+}
+
+// FormatNodeTolerations formats tolerations as "key[=value],..."
+func FormatNodeTolerations(tolerations []NodeToleration) string {
+	parts := make([]string, len(tolerations))
+	for i, toleration := range tolerations {
+		parts[i] = toleration.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// ValidatePlacementName checks a pinned node ID or anti-affinity group name (empty = none)
+func ValidatePlacementName(name string) bool {
+	return name == "" || nodeLabelPattern.MatchString(name)
+}
+
+// splitPlacementList splits a comma-separated list, dropping empty entries
+func splitPlacementList(s string) []string {
+	var parts []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// Node placement errors
+var (
+	ErrInvalidNodeLabel      = errors.New("invalid node label (format: key=value, letters, digits, '.', '_', '/', '-')")
+	ErrInvalidNodeTaint      = errors.New("invalid node taint (format: key[=value]:NoSchedule|PreferNoSchedule)")
+	ErrInvalidNodeToleration = errors.New("invalid toleration (format: key[=value])")
+	ErrNoMatchingNode        = errors.New("no node matches the server's placement constraints")
+	ErrInvalidPinnedNode     = errors.New("invalid pinned node ID")
+	ErrInvalidAffinityGroup  = errors.New("invalid anti-affinity group (letters, digits, '.', '_', '/', '-', up to 63 characters)")
+)
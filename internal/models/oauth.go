@@ -32,6 +32,11 @@ type OAuthAccount struct {
 	Scopes       string            `gorm:"size:500"`                // Granted OAuth scopes
 	LastUsedAt   time.Time         `gorm:"not null"`
 
+	// Token refresh tracking
+	LastRefreshedAt  *time.Time `json:"last_refreshed_at,omitempty"`
+	RefreshFailures  int        `gorm:"default:0" json:"refresh_failures"`
+	LastRefreshError string     `gorm:"size:500" json:"-"`
+
 	// Relationship
 	User User `gorm:"foreignKey:UserID"`
 }
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrgRole is the role of a member within an organization
+type OrgRole string
+
+const (
+	OrgRoleOwner    OrgRole = "owner"    // Everything, including deleting the organization and managing owners
+	OrgRoleAdmin    OrgRole = "admin"    // Manage members and servers, destructive actions (delete, restore, RAM upgrade)
+	OrgRoleOperator OrgRole = "operator" // Day-to-day operation: start/stop, console, configs, backups
+	OrgRoleViewer   OrgRole = "viewer"   // Read-only
+)
+
+// orgRoleRank orders roles so checks can ask for "at least" a role
+var orgRoleRank = map[OrgRole]int{
+	OrgRoleViewer:   1,
+	OrgRoleOperator: 2,
+	OrgRoleAdmin:    3,
+	OrgRoleOwner:    4,
+}
+
+// Valid returns true for a known role
+func (r OrgRole) Valid() bool {
+	_, ok := orgRoleRank[r]
+	return ok
+}
+
+// AtLeast returns true if r grants everything min grants
+func (r OrgRole) AtLeast(min OrgRole) bool {
+	return orgRoleRank[r] >= orgRoleRank[min] && r.Valid()
+}
+
+// Organization groups users so they can share servers
+// Servers are shared by setting MinecraftServer.OrganizationID; the server's OwnerID stays the billed user.
+type Organization struct {
+	ID        string    `gorm:"primaryKey;size:36" json:"id"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	CreatedBy string    `gorm:"size:36;not null;index" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Data residency: worlds, backups and archives of the organization's servers stay in this
+	// country (ISO 3166-1 alpha-2, "" = no restriction)
+	DataResidency string `gorm:"size:2" json:"data_residency,omitempty"`
+
+	Members []OrganizationMember `gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE" json:"members,omitempty"`
+}
+
+// BeforeCreate hook to generate UUID
+func (o *Organization) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == "" {
+		o.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// OrganizationMember is the membership of a user in an organization
+type OrganizationMember struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganizationID string    `gorm:"size:36;not null;uniqueIndex:idx_org_member" json:"organization_id"`
+	UserID         string    `gorm:"size:36;not null;uniqueIndex:idx_org_member;index" json:"user_id"`
+	Role           OrgRole   `gorm:"size:20;not null" json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Filled for member listings
+	Email    string `gorm:"-" json:"email,omitempty"`
+	Username string `gorm:"-" json:"username,omitempty"`
+}
+
+// OrganizationInvitation is a pending invitation of an email address into an organization
+type OrganizationInvitation struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	OrganizationID string     `gorm:"size:36;not null;index" json:"organization_id"`
+	Email          string     `gorm:"size:255;not null;index" json:"email"`
+	Role           OrgRole    `gorm:"size:20;not null" json:"role"`
+	Token          string     `gorm:"size:64;uniqueIndex;not null" json:"-"` // Only sent by email
+	InvitedBy      string     `gorm:"size:36;not null" json:"invited_by"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// IsPending returns true if the invitation can still be accepted at t
+func (i *OrganizationInvitation) IsPending(t time.Time) bool {
+	return i.AcceptedAt == nil && t.Before(i.ExpiresAt)
+}
+
+// Organization errors
+var (
+	ErrOrgNotFound          = errors.New("organization not found")
+	ErrOrgForbidden         = errors.New("insufficient organization role")
+	ErrOrgInvalidRole       = errors.New("invalid organization role (owner, admin, operator, viewer)")
+	ErrOrgLastOwner         = errors.New("an organization needs at least one owner")
+	ErrOrgAlreadyMember     = errors.New("user is already a member of the organization")
+	ErrOrgInvalidInvitation = errors.New("invalid or expired invitation")
+	ErrServerNotFound       = errors.New("server not found")
+)
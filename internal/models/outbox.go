@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the state change it describes
+// (transactional outbox). Consumers process it at least once and use ID as their idempotency key,
+// so a crash between the state change and its handling can't lose or double-count it.
+type OutboxEvent struct {
+	ID            string     `gorm:"primaryKey;size:64" json:"id"`
+	Type          string     `gorm:"size:64;not null;index" json:"type"` // Event-Bus event type, e.g. "server.stopped"
+	ServerID      string     `gorm:"size:36;index" json:"server_id"`
+	UserID        string     `gorm:"size:36" json:"user_id,omitempty"`
+	Payload       string     `gorm:"type:text" json:"payload"` // JSON-encoded event data
+	CreatedAt     time.Time  `gorm:"index" json:"created_at"`  // When the state change happened
+	ProcessedAt   *time.Time `gorm:"index" json:"processed_at,omitempty"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+}
+
+// NewOutboxEvent creates an unsaved outbox event that is due immediately
+func NewOutboxEvent(eventType, serverID, userID string, data map[string]interface{}) *OutboxEvent {
+	payload, _ := json.Marshal(data)
+	now := time.Now()
+	return &OutboxEvent{
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		ServerID:      serverID,
+		UserID:        userID,
+		Payload:       string(payload),
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+}
+
+// Data decodes the event payload (empty map if there is none)
+func (e *OutboxEvent) Data() map[string]interface{} {
+	data := map[string]interface{}{}
+	if e.Payload != "" {
+		json.Unmarshal([]byte(e.Payload), &data)
+	}
+	return data
+}
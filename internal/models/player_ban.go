@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// PlayerBan bans a Minecraft account at the Velocity proxy
+// A global ban (set by admins, e.g. for charge-back abusers) keeps the player off the whole network.
+// An organization ban keeps the player off all servers shared with that organization.
+type PlayerBan struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	MinecraftUUID  string     `gorm:"size:36;not null;index" json:"minecraft_uuid"`
+	MinecraftName  string     `gorm:"size:16;not null" json:"minecraft_name"`                             // Name when banned, for listings
+	OrganizationID string     `gorm:"size:36;not null;default:'';index" json:"organization_id,omitempty"` // Empty = global
+	Reason         string     `gorm:"size:255;not null" json:"reason"`
+	ExpiresAt      *time.Time `gorm:"index" json:"expires_at,omitempty"` // Nil = permanent
+	CreatedBy      string     `gorm:"size:36;not null" json:"created_by"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy      string     `gorm:"size:36" json:"revoked_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (PlayerBan) TableName() string {
+	return "player_bans"
+}
+
+// Global reports whether the ban applies to the whole network
+func (b *PlayerBan) Global() bool {
+	return b.OrganizationID == ""
+}
+
+// ActiveAt reports whether the ban is in force at t
+func (b *PlayerBan) ActiveAt(t time.Time) bool {
+	return b.RevokedAt == nil && (b.ExpiresAt == nil || t.Before(*b.ExpiresAt))
+}
+
+// Player ban errors
+var (
+	ErrPlayerBanNotFound = errors.New("ban not found")
+	ErrPlayerBanInvalid  = errors.New("invalid ban")
+	ErrPlayerBanRevoked  = errors.New("ban is already revoked or expired")
+)
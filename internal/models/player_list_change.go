@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Actions of a queued player list change
+const (
+	PlayerListActionAdd    = "add"
+	PlayerListActionRemove = "remove"
+)
+
+// PlayerListChange is a whitelist/ops/ban edit made while the server was offline that could not be
+// written to the list file (e.g. unknown UUID, archived server). It is applied via RCON on the next start.
+type PlayerListChange struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	ServerID  string     `gorm:"size:64;not null;index" json:"server_id"`
+	ListType  string     `gorm:"size:20;not null" json:"list_type"` // whitelist, ops, banned-players
+	Action    string     `gorm:"size:10;not null" json:"action"`
+	Name      string     `gorm:"size:16;not null" json:"name"`
+	Reason    string     `gorm:"size:255" json:"reason,omitempty"` // Bans only
+	CreatedBy string     `gorm:"size:36" json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	AppliedAt *time.Time `gorm:"index" json:"applied_at,omitempty"`
+	Error     string     `gorm:"type:text" json:"error,omitempty"` // Last failed attempt
+}
+
+// TableName specifies the table name
+func (PlayerListChange) TableName() string {
+	return "player_list_changes"
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// ReleaseChannel controls how early a server receives managed platform changes:
+// container image updates, plugin auto-updates and new readiness probes
+type ReleaseChannel string
+
+const (
+	ReleaseChannelStable ReleaseChannel = "stable" // Default: changes arrive after they have soaked on beta
+	ReleaseChannelBeta   ReleaseChannel = "beta"   // Opt-in: newest image, pre-release plugin updates, new probes
+)
+
+// ErrInvalidReleaseChannel is returned for unknown channel names
+var ErrInvalidReleaseChannel = errors.New("invalid release channel (stable or beta)")
+
+// ParseReleaseChannel validates a channel name
+func ParseReleaseChannel(value string) (ReleaseChannel, error) {
+	switch channel := ReleaseChannel(strings.ToLower(strings.TrimSpace(value))); channel {
+	case ReleaseChannelStable, ReleaseChannelBeta:
+		return channel, nil
+	}
+	return "", ErrInvalidReleaseChannel
+}
+
+// Channel returns the server's release channel (servers created before channels existed are stable)
+func (s *MinecraftServer) Channel() ReleaseChannel {
+	if s.ReleaseChannel == ReleaseChannelBeta {
+		return ReleaseChannelBeta
+	}
+	return ReleaseChannelStable
+}
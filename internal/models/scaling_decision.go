@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ScalingDecision is a persisted evaluation of the scaling engine: the fleet it saw, the verdicts
+// of the policies it asked, the action it chose and the outcome. Evaluations without an action
+// are recorded too, so gaps in the history mean the engine didn't run.
+type ScalingDecision struct {
+	ID              string         `gorm:"primaryKey;size:36" json:"id"`
+	EvaluatedAt     time.Time      `gorm:"not null;index" json:"evaluated_at"`
+	Action          string         `gorm:"size:32;not null;index" json:"action"` // "none", "scale_up", "scale_down", "consolidate", ...
+	Policy          string         `gorm:"size:64" json:"policy,omitempty"`      // Policy whose recommendation was chosen
+	Reason          string         `gorm:"type:text" json:"reason,omitempty"`
+	ServerType      string         `gorm:"size:32" json:"server_type,omitempty"`
+	Count           int            `json:"count,omitempty"`
+	Urgency         string         `gorm:"size:16" json:"urgency,omitempty"`
+	Outcome         string         `gorm:"size:16;not null;index" json:"outcome"` // "none", "executed", "failed"
+	Error           string         `gorm:"type:text" json:"error,omitempty"`
+	CapacityPercent float64        `json:"capacity_percent"`
+	NodeIDs         datatypes.JSON `gorm:"type:jsonb" json:"node_ids,omitempty"`   // Nodes provisioned or removed
+	Migrations      datatypes.JSON `gorm:"type:jsonb" json:"migrations,omitempty"` // Server moves of a consolidation
+	Verdicts        datatypes.JSON `gorm:"type:jsonb" json:"verdicts"`             // Answer of every policy asked
+	Context         datatypes.JSON `gorm:"type:jsonb" json:"context"`              // Fleet, queue and nodes at the evaluation
+}
+
+// TableName specifies the table name
+func (ScalingDecision) TableName() string {
+	return "scaling_decisions"
+}
+
+// BeforeCreate generates the decision ID
+func (d *ScalingDecision) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
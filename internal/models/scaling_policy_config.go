@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Scaling policy config scopes: the engine itself and its policies
+const (
+	ScalingScopeEngine        = "engine"
+	ScalingScopeReactive      = "reactive"
+	ScalingScopeConsolidation = "consolidation"
+)
+
+// ScalingPolicyConfig overrides the settings of the scaling engine or one of its policies (admin)
+// nil fields keep the default (environment or built-in). Fields of other scopes stay nil.
+type ScalingPolicyConfig struct {
+	Policy string `gorm:"primaryKey;size:32" json:"policy"` // engine, reactive or consolidation
+
+	CheckIntervalSeconds *int `json:"check_interval_seconds,omitempty"` // engine: time between evaluations
+
+	Enabled         *bool `json:"enabled,omitempty"`          // reactive, consolidation
+	CooldownSeconds *int  `json:"cooldown_seconds,omitempty"` // reactive, consolidation: wait between actions
+
+	ScaleUpThreshold     *float64 `json:"scale_up_threshold,omitempty"`                     // reactive: capacity percent
+	ScaleDownThreshold   *float64 `json:"scale_down_threshold,omitempty"`                   // reactive: capacity percent
+	MinCloudNodes        *int     `json:"min_cloud_nodes,omitempty"`                        // reactive
+	MaxCloudNodes        *int     `json:"max_cloud_nodes,omitempty"`                        // reactive
+	PreferredServerTypes *string  `gorm:"size:255" json:"preferred_server_types,omitempty"` // reactive: comma-separated, "" = all
+
+	MaxCapacityPercent        *float64 `json:"max_capacity_percent,omitempty"`         // consolidation: don't consolidate above
+	MinNodeSavings            *int     `json:"min_node_savings,omitempty"`             // consolidation
+	AllowMigrationWithPlayers *bool    `json:"allow_migration_with_players,omitempty"` // consolidation
+
+	UpdatedBy string    `gorm:"size:36" json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (ScalingPolicyConfig) TableName() string {
+	return "scaling_policy_configs"
+}
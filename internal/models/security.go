@@ -24,6 +24,19 @@ const (
 	EventEmailVerified       SecurityEventType = "email_verified"
 	EventPasswordResetRequest SecurityEventType = "password_reset_request"
 	EventPasswordResetSuccess SecurityEventType = "password_reset_success"
+	EventOAuthUnlinked        SecurityEventType = "oauth_unlinked"
+	EventOAuthRefreshFailed   SecurityEventType = "oauth_refresh_failed"
+	EventTwoFactorEnabled     SecurityEventType = "two_factor_enabled"
+	EventTwoFactorDisabled    SecurityEventType = "two_factor_disabled"
+	EventTwoFactorFailure     SecurityEventType = "two_factor_failure"
+	EventRecoveryCodeUsed     SecurityEventType = "recovery_code_used"
+	EventRecoveryCodesReset   SecurityEventType = "recovery_codes_regenerated"
+	EventSSOConfigured        SecurityEventType = "sso_configured"
+	EventSSORemoved           SecurityEventType = "sso_removed"
+	EventSSODomainVerified    SecurityEventType = "sso_domain_verified"
+	EventSSOLinked            SecurityEventType = "sso_linked"
+	EventLegalHoldPlaced      SecurityEventType = "legal_hold_placed"
+	EventLegalHoldReleased    SecurityEventType = "legal_hold_released"
 )
 
 // TrustedDevice represents a device that the user trusts for 30 days
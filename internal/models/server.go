@@ -32,6 +32,7 @@ const (
 	StatusSleeping  ServerStatus = "sleeping"  // Phase 2: Container stopped, volume persists
 	StatusArchiving ServerStatus = "archiving" // Transitional: Being archived
 	StatusArchived  ServerStatus = "archived"  // Phase 3: Compressed and stored remotely
+	StatusSuspended ServerStatus = "suspended" // Abuse or billing hold: stopped, starts blocked, files kept
 )
 
 // LifecyclePhase represents the server's lifecycle state for billing
@@ -49,8 +50,9 @@ type MinecraftServer struct {
 	ID string `gorm:"primaryKey;size:64"`
 
 	// Basic Info
-	Name    string `gorm:"not null"`
-	OwnerID string `gorm:"not null;default:default"` // Future: user system
+	Name           string `gorm:"not null"`
+	OwnerID        string `gorm:"not null;default:default"`  // Future: user system
+	OrganizationID string `gorm:"size:36;default:'';index"` // Shared with this organization's members (empty = owner only)
 
 	// Server Configuration
 	ServerType       ServerType `gorm:"not null"`
@@ -102,15 +104,50 @@ type MinecraftServer struct {
 	ContainerID string       `gorm:"size:128"`
 	NodeID      string       `gorm:"size:64"` // Multi-Node: Which node hosts this container (assigned by Conductor)
 
+	// Node Placement (dedicated nodes / workload isolation, set by admins)
+	NodeSelector    string `gorm:"size:512;default:''"` // Required node labels "key=value,..." (empty = any node)
+	NodeTolerations string `gorm:"size:512;default:''"` // Tolerated node taints "key[=value],..."
+	PinnedNodeID    string `gorm:"size:64;default:''"`  // Only ever placed on this node (empty = any node)
+
+	// Spread Placement (set by owners, e.g. a network's lobby and game servers on different nodes)
+	AntiAffinityGroup string `gorm:"size:64;default:''"` // Servers of the owner in the same group avoid sharing a node
+	SpreadAcrossNodes bool   `gorm:"default:false"`      // Avoid nodes that run any other server of the owner
+
+	// CPU Limits (fair share between servers on a node, set by admins)
+	CPUShares     int     `gorm:"default:0"` // CPU weight under contention (0 = CONTAINER_CPU_SHARES_PER_GB per GB of RAM)
+	CPULimitCores float64 `gorm:"default:0"` // Hard CPU cap in cores (0 = CONTAINER_CPU_LIMIT_CORES)
+
+	// Managed Components (image updates, plugin auto-updates, readiness probes)
+	ReleaseChannel ReleaseChannel `gorm:"size:16;default:stable"` // How early managed changes are applied (stable, beta)
+
 	// Timestamps
 	LastStartedAt *time.Time
 	LastStoppedAt *time.Time
 
+	// Cold-Start Tracking
+	LastStartupDurationMs int64 `gorm:"default:0"` // Container start → "Done (...)!" on the most recent boot
+
 	// Lifecycle Management (3-Phase System)
 	LifecyclePhase  LifecyclePhase `gorm:"default:active"`      // Current lifecycle phase for billing
 	ArchivedAt      *time.Time                                  // When server was archived
 	ArchiveLocation string         `gorm:"size:512;default:''"` // Path to archive file (Storage Box)
 	ArchiveSize     int64          `gorm:"default:0"`           // Size of archive in bytes
+	ArchiveTier     string         `gorm:"size:16;default:''"`  // "" = Storage Box, cold = object storage (ArchiveTierCold)
+	ArchiveColdAt   *time.Time                                  // When the archive moved to cold storage
+	ArchiveRestoreRequestedAt *time.Time                        // Pending restore of a cold archive (nil = none)
+
+	// Suspension (abuse or billing hold, see StatusSuspended)
+	SuspendedAt            *time.Time   // When the server was suspended
+	SuspensionReason       string       `gorm:"size:512;default:''"` // Shown to the owner when a start is rejected
+	SuspensionSource       string       `gorm:"size:16;default:''"`  // admin, billing
+	SuspendedBy            string       `gorm:"size:64;default:''"`  // Admin user ID (empty for billing automation)
+	StatusBeforeSuspension ServerStatus `gorm:"size:16;default:''"`  // Restored on unsuspend (stopped, sleeping, archived)
+
+	// Disk Quota (size of the server directory, measured by the disk quota worker)
+	DiskQuotaMB        int        `gorm:"default:0"` // Quota set by an admin (0 = DISK_QUOTA_DEFAULT_MB)
+	DiskUsageBytes     int64      `gorm:"default:0"` // Server directory size at the last scan, plus uploads since
+	DiskUsageScannedAt *time.Time // When the server directory was last measured
+	DiskWarnPercent    int        `gorm:"default:0"` // Highest warning threshold already notified (reset below it)
 
 	// Pay-Per-Use Settings
 	IdleTimeoutSeconds   int  `gorm:"default:300"`  // Seconds of inactivity before auto-shutdown (default: 5 minutes)
@@ -123,6 +160,12 @@ type MinecraftServer struct {
 	AllowMigration        bool   `gorm:"default:true"`        // Allow server to be migrated for cost optimization
 	MigrationMode         string `gorm:"default:only_offline"` // Migration modes: "only_offline", "always", "never"
 
+	// Runtime Diagnostics (modded servers only)
+	HeapDumpsEnabled bool `gorm:"default:false"` // Write a heap dump on Java OOM (see HeapDumpsActive)
+
+	// Vote Site Integration (managed NuVotifier)
+	VotifierPort int `gorm:"default:0"` // Host port mapped to the container's Votifier listener (0 = disabled)
+
 	// Velocity Proxy Integration
 	VelocityRegistered  bool   `gorm:"default:false"`
 	VelocityServerName  string `gorm:"size:128"`
@@ -164,6 +207,33 @@ func (UsageLog) TableName() string {
 	return "usage_logs"
 }
 
+// Placement returns the node placement constraints of the server
+// Invalid stored values are ignored (they are validated when set). Reserved-plan servers avoid
+// interruptible nodes.
+func (s *MinecraftServer) Placement() NodePlacement {
+	selector, _ := ParseNodeLabels(s.NodeSelector)
+	tolerations, _ := ParseNodeTolerations(s.NodeTolerations)
+	return NodePlacement{
+		Selector:           selector,
+		Tolerations:        tolerations,
+		PinnedNodeID:       s.PinnedNodeID,
+		AntiAffinityGroup:  s.AntiAffinityGroup,
+		Spread:             s.SpreadAcrossNodes,
+		AvoidInterruptible: s.HasGuaranteedStart(), // Guaranteed starts need nodes the provider can't reclaim
+	}
+}
+
+// IsPlacementPeer reports whether other must not share a node with the server (anti-affinity/spread)
+func (s *MinecraftServer) IsPlacementPeer(other *MinecraftServer) bool {
+	if other.ID == s.ID || other.OwnerID != s.OwnerID {
+		return false
+	}
+	if s.SpreadAcrossNodes {
+		return true
+	}
+	return s.AntiAffinityGroup != "" && other.AntiAffinityGroup == s.AntiAffinityGroup
+}
+
 // GetRAMMb returns the allocated RAM in MB for this server
 // Used by Conductor for state synchronization after restarts
 func (s *MinecraftServer) GetRAMMb() int {
@@ -186,6 +256,23 @@ func (s *MinecraftServer) GetMonthlyRate() float64 {
 	return CalculateMonthlyRate(s.RAMTier, s.Plan, s.RAMMb)
 }
 
+// IsModded returns whether the server runs a mod loader (Forge/Fabric)
+func (s *MinecraftServer) IsModded() bool {
+	return s.ServerType == ServerTypeForge || s.ServerType == ServerTypeFabric
+}
+
+// HeapDumpsActive returns whether the JVM should write heap dumps on OOM
+// Only honored for modded servers: that's where mod authors need them to debug memory leaks.
+func (s *MinecraftServer) HeapDumpsActive() bool {
+	return s.HeapDumpsEnabled && s.IsModded()
+}
+
+// HasGuaranteedStart returns whether the server's start never queues (reserved plan)
+// The fleet keeps RAM for it while it is stopped: on its node, or as scaling headroom.
+func (s *MinecraftServer) HasGuaranteedStart() bool {
+	return s.Plan == PlanReserved
+}
+
 // AllowsConsolidation returns whether this server allows consolidation based on tier and plan
 func (s *MinecraftServer) AllowsConsolidation() bool {
 	// Reserved plan: never consolidate
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ServerDeclaration links a server name of the owner's declarative manifest (POST /api/apply) to the
+// server created or adopted for it. Only declared servers are changed or pruned by later applies;
+// servers created in the dashboard are never touched unless a manifest adopts them by name.
+type ServerDeclaration struct {
+	ID        string    `gorm:"primaryKey;size:36" json:"id"`
+	UserID    string    `gorm:"size:64;not null;uniqueIndex:idx_server_declarations_name" json:"user_id"`
+	Name      string    `gorm:"size:64;not null;uniqueIndex:idx_server_declarations_name" json:"name"`
+	ServerID  string    `gorm:"size:64;not null;uniqueIndex" json:"server_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (ServerDeclaration) TableName() string {
+	return "server_declarations"
+}
+
+// BeforeCreate generates the declaration ID
+func (d *ServerDeclaration) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ServerPermission is a single capability on a server
+type ServerPermission string
+
+const (
+	PermServerView       ServerPermission = "view"        // Server details, status, players, usage
+	PermServerConsole    ServerPermission = "console"     // Console stream, logs and commands, player lists
+	PermServerFilesRead  ServerPermission = "files.read"  // Read configs, files and world downloads
+	PermServerFilesWrite ServerPermission = "files.write" // Change configs, files, plugins, MOTD
+	PermServerPower      ServerPermission = "power"       // Start/stop, auto-shutdown
+	PermServerBackup     ServerPermission = "backup"      // Create backups, backup schedule
+	PermServerManage     ServerPermission = "manage"      // Delete, restore, RAM upgrade, worlds, billing, budget, webhooks
+	PermServerShare      ServerPermission = "share"       // Create and revoke share tokens
+)
+
+// AllServerPermissions lists every permission (least to most privileged)
+var AllServerPermissions = []ServerPermission{
+	PermServerView, PermServerConsole, PermServerFilesRead, PermServerFilesWrite,
+	PermServerPower, PermServerBackup, PermServerManage, PermServerShare,
+}
+
+// ShareablePermissions are the permissions an owner can grant to a single user with a share token
+var ShareablePermissions = []ServerPermission{PermServerConsole, PermServerFilesRead, PermServerPower}
+
+// ServerPermissionMinRole is the minimum organization role (or OrgRoleOwner for the server owner)
+// that includes a permission
+var ServerPermissionMinRole = map[ServerPermission]OrgRole{
+	PermServerView:       OrgRoleViewer,
+	PermServerFilesRead:  OrgRoleViewer,
+	PermServerConsole:    OrgRoleOperator,
+	PermServerFilesWrite: OrgRoleOperator,
+	PermServerPower:      OrgRoleOperator,
+	PermServerBackup:     OrgRoleOperator,
+	PermServerManage:     OrgRoleAdmin,
+	PermServerShare:      OrgRoleOwner,
+}
+
+// IsShareable returns true if p can be granted with a share token
+func (p ServerPermission) IsShareable() bool {
+	for _, shareable := range ShareablePermissions {
+		if p == shareable {
+			return true
+		}
+	}
+	return false
+}
+
+// ServerShare grants one user scoped access to a single server
+// The owner creates a share with a one-time token; the user who redeems it becomes the grantee.
+type ServerShare struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ServerID    string     `gorm:"size:64;not null;index" json:"server_id"`
+	CreatedBy   string     `gorm:"size:36;not null" json:"created_by"`
+	UserID      string     `gorm:"size:36;default:'';index" json:"user_id,omitempty"` // Grantee, empty until redeemed
+	Token       string     `gorm:"size:64;uniqueIndex;not null" json:"-"`             // Returned once on creation
+	Permissions string     `gorm:"size:255;not null" json:"-"`                        // Comma-separated ServerPermission values
+	Note        string     `gorm:"size:255" json:"note,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // Redemption deadline (nil = no deadline)
+	RedeemedAt  *time.Time `json:"redeemed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	PermissionList []ServerPermission `gorm:"-" json:"permissions"`
+}
+
+// SetPermissions stores a permission set
+func (s *ServerShare) SetPermissions(perms []ServerPermission) {
+	values := make([]string, len(perms))
+	for i, perm := range perms {
+		values[i] = string(perm)
+	}
+	s.Permissions = strings.Join(values, ",")
+	s.PermissionList = perms
+}
+
+// LoadPermissions fills PermissionList from the stored permission set
+func (s *ServerShare) LoadPermissions() {
+	s.PermissionList = nil
+	for _, value := range strings.Split(s.Permissions, ",") {
+		if value != "" {
+			s.PermissionList = append(s.PermissionList, ServerPermission(value))
+		}
+	}
+}
+
+// Has returns true if the share grants perm (any share grants view)
+func (s *ServerShare) Has(perm ServerPermission) bool {
+	if perm == PermServerView {
+		return true
+	}
+	for _, value := range strings.Split(s.Permissions, ",") {
+		if ServerPermission(value) == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRedeemable returns true if the share token can still be redeemed at t
+func (s *ServerShare) IsRedeemable(t time.Time) bool {
+	return s.RedeemedAt == nil && (s.ExpiresAt == nil || t.Before(*s.ExpiresAt))
+}
+
+// Server share errors
+var (
+	ErrServerForbidden     = errors.New("insufficient permissions on this server")
+	ErrInvalidShareToken   = errors.New("invalid, expired or already redeemed share token")
+	ErrInvalidPermission   = errors.New("invalid permission (shareable: console, files.read, power)")
+	ErrShareOwnServer      = errors.New("you already own this server")
+	ErrShareNotFound       = errors.New("share not found")
+	ErrShareAlreadyGranted = errors.New("you already have a share for this server")
+)
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SFTPKey is an SSH public key that logs its user into the SFTP gateway
+// The SSH user name is the ID of the server to open; the key's user needs the files:read permission
+// on it (files:write for changes, unless the key is read-only).
+type SFTPKey struct {
+	ID          string     `gorm:"primaryKey;size:36" json:"id"`
+	UserID      string     `gorm:"size:36;not null;index" json:"user_id"`
+	Name        string     `gorm:"size:100;not null" json:"name"`
+	PublicKey   string     `gorm:"type:text;not null" json:"public_key"`            // authorized_keys format
+	Fingerprint string     `gorm:"size:64;uniqueIndex;not null" json:"fingerprint"` // SHA256:...
+	ServerID    string     `gorm:"size:64;default:''" json:"server_id,omitempty"`   // Empty = every server the user can access
+	ReadOnly    bool       `gorm:"not null;default:false" json:"read_only"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP  string     `gorm:"size:45" json:"last_used_ip,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (SFTPKey) TableName() string {
+	return "sftp_keys"
+}
+
+// BeforeCreate generates the key ID
+func (k *SFTPKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == "" {
+		k.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// SFTPAuditAction is what an SFTP session did
+type SFTPAuditAction string
+
+const (
+	SFTPAuditLogin    SFTPAuditAction = "login"
+	SFTPAuditLogout   SFTPAuditAction = "logout"
+	SFTPAuditDownload SFTPAuditAction = "download"
+	SFTPAuditUpload   SFTPAuditAction = "upload"
+	SFTPAuditDelete   SFTPAuditAction = "delete"
+	SFTPAuditRename   SFTPAuditAction = "rename"
+	SFTPAuditMkdir    SFTPAuditAction = "mkdir"
+	SFTPAuditRmdir    SFTPAuditAction = "rmdir"
+	SFTPAuditSetstat  SFTPAuditAction = "setstat" // chmod, truncate, timestamps
+	SFTPAuditDenied   SFTPAuditAction = "denied"  // Refused change (read-only, protected path, quota)
+)
+
+// SFTPAuditEntry records one file operation of an SFTP session
+type SFTPAuditEntry struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	ServerID   string          `gorm:"size:64;not null;index" json:"server_id"`
+	UserID     string          `gorm:"size:36;not null;index" json:"user_id"`
+	KeyID      string          `gorm:"size:36" json:"key_id"`
+	Action     SFTPAuditAction `gorm:"size:16;not null" json:"action"`
+	Path       string          `gorm:"type:text" json:"path,omitempty"`
+	Target     string          `gorm:"type:text" json:"target,omitempty"` // New path of a rename
+	Bytes      int64           `json:"bytes,omitempty"`                   // Bytes written by an upload
+	Detail     string          `gorm:"type:text" json:"detail,omitempty"` // Reason of a denial, new mode, ...
+	RemoteAddr string          `gorm:"size:64" json:"remote_addr"`
+	CreatedAt  time.Time       `gorm:"index" json:"created_at"`
+}
+
+// TableName specifies the table name
+func (SFTPAuditEntry) TableName() string {
+	return "sftp_audit_entries"
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SSH key states: a rotation creates a pending key, which becomes active once every node accepts it
+// (or failed). The previous active key is retiring until the grace period ends and it is removed
+// from the nodes.
+const (
+	SSHKeyPending  = "pending"
+	SSHKeyActive   = "active"
+	SSHKeyRetiring = "retiring"
+	SSHKeyRetired  = "retired"
+	SSHKeyFailed   = "failed"
+)
+
+// SSHKey is a keypair the conductor uses to reach the worker nodes of one environment
+type SSHKey struct {
+	ID           string     `gorm:"primaryKey;size:36" json:"id"`
+	Environment  string     `gorm:"size:64;not null;index" json:"environment"`
+	Fingerprint  string     `gorm:"size:100" json:"fingerprint"` // SHA256 fingerprint, as shown by ssh-keygen -l
+	PublicKey    string     `gorm:"type:text;not null" json:"public_key"`
+	PrivateKey   string     `gorm:"type:text" json:"-"` // AES-GCM encrypted PEM, cleared when retired
+	Status       string     `gorm:"size:16;not null;index" json:"status"`
+	CreatedBy    string     `gorm:"size:36" json:"created_by,omitempty"` // Empty for scheduled rotations
+	NodesUpdated int        `json:"nodes_updated"`
+	NodesFailed  int        `json:"nodes_failed"`
+	LastError    string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ActivatedAt  *time.Time `json:"activated_at,omitempty"`
+	RetireAfter  *time.Time `json:"retire_after,omitempty"`
+	RetiredAt    *time.Time `json:"retired_at,omitempty"`
+}
+
+// TableName specifies the table name
+func (SSHKey) TableName() string {
+	return "ssh_keys"
+}
+
+// BeforeCreate generates the key ID
+func (k *SSHKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == "" {
+		k.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// SSOProtocol is the protocol spoken with an organization's identity provider
+type SSOProtocol string
+
+const (
+	SSOProtocolOIDC SSOProtocol = "oidc" // OpenID Connect authorization code flow
+	SSOProtocolSAML SSOProtocol = "saml" // Not supported yet, see ErrSSOUnsupportedProtocol
+)
+
+// SSOConnection is the identity provider of an organization (one per organization)
+// Users signing in through it are provisioned just in time and get the organization role their IdP
+// claims map to. With Enforced set, members can only use sessions issued by this connection.
+type SSOConnection struct {
+	OrganizationID string      `gorm:"primaryKey;size:36" json:"organization_id"`
+	Protocol       SSOProtocol `gorm:"size:10;not null" json:"protocol"`
+	IssuerURL      string      `gorm:"size:255;not null" json:"issuer_url"` // OIDC issuer, discovery at /.well-known/openid-configuration
+	ClientID       string      `gorm:"size:255;not null" json:"client_id"`
+	ClientSecret   string      `gorm:"size:500" json:"-"`                              // Never expose
+	EmailDomains   string      `gorm:"size:500;default:'';index" json:"email_domains"` // "acme.com,acme.de" - only used once verified (SSODomain)
+
+	// Role mapping: values of RoleClaim (e.g. "groups") -> organization role, DefaultRole for everyone else
+	RoleClaim   string             `gorm:"size:100;default:''" json:"role_claim"`
+	RoleMapping map[string]OrgRole `gorm:"serializer:json;type:text" json:"role_mapping"`
+	DefaultRole OrgRole            `gorm:"size:20;default:''" json:"default_role"` // Empty = users without a mapped role are refused
+
+	// Session policy
+	Enforced          bool `gorm:"not null;default:false" json:"enforced"`        // Members (except owners) must sign in via SSO
+	SessionMaxMinutes int  `gorm:"not null;default:0" json:"session_max_minutes"` // Max age of SSO sessions (0 = default token lifetime)
+	Enabled           bool `gorm:"not null" json:"enabled"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Domains returns the normalized email domains of the connection
+func (c *SSOConnection) Domains() []string {
+	var domains []string
+	for _, domain := range strings.Split(c.EmailDomains, ",") {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// HasDomain returns true if email belongs to one of the connection's domains
+func (c *SSOConnection) HasDomain(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, d := range c.Domains() {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleFor returns the highest role the claim values map to, falling back to DefaultRole
+// Owners are never granted through SSO; ok is false if the user gets no role at all.
+func (c *SSOConnection) RoleFor(claimValues []string) (role OrgRole, ok bool) {
+	for _, value := range claimValues {
+		mapped, exists := c.RoleMapping[value]
+		if !exists || mapped == OrgRoleOwner {
+			continue
+		}
+		if !ok || mapped.AtLeast(role) {
+			role, ok = mapped, true
+		}
+	}
+	if !ok && c.DefaultRole.Valid() && c.DefaultRole != OrgRoleOwner {
+		return c.DefaultRole, true
+	}
+	return role, ok
+}
+
+// SessionMaxAge returns the session lifetime policy (0 = no limit beyond the token lifetime)
+func (c *SSOConnection) SessionMaxAge() time.Duration {
+	return time.Duration(c.SessionMaxMinutes) * time.Minute
+}
+
+// SSOIdentity links an IdP subject to the user it was provisioned as
+type SSOIdentity struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OrganizationID string    `gorm:"size:36;not null;uniqueIndex:idx_sso_subject" json:"organization_id"`
+	Subject        string    `gorm:"size:255;not null;uniqueIndex:idx_sso_subject" json:"subject"` // OIDC "sub"
+	UserID         string    `gorm:"size:36;not null;index" json:"user_id"`
+	Email          string    `gorm:"size:255" json:"email"`
+	LastLoginAt    time.Time `json:"last_login_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SSODomain is an email domain claimed by an organization's SSO connection
+// A claimed domain is ignored for login discovery and provisioning until the organization proves it
+// owns it with a DNS TXT record or a platform admin approves it. Several organizations may claim the
+// same domain, but only one can verify it.
+type SSODomain struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	OrganizationID string     `gorm:"size:36;not null;uniqueIndex:idx_sso_domain" json:"organization_id"`
+	Domain         string     `gorm:"size:255;not null;uniqueIndex:idx_sso_domain;index" json:"domain"`
+	Token          string     `gorm:"size:64;not null" json:"-"` // Expected in the TXT record
+	VerifiedAt     *time.Time `json:"verified_at,omitempty"`
+	VerifiedBy     string     `gorm:"size:36;default:''" json:"verified_by,omitempty"` // "dns" or the approving admin's user ID
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// SSODomainVerifiedByDNS marks domains verified through their TXT record
+const SSODomainVerifiedByDNS = "dns"
+
+// IsVerified returns true once the organization's ownership of the domain is proven
+func (d *SSODomain) IsVerified() bool {
+	return d.VerifiedAt != nil
+}
+
+// TXTRecordName returns the DNS name the verification record must be published at
+func (d *SSODomain) TXTRecordName() string {
+	return "_payperplay-sso." + d.Domain
+}
+
+// TXTRecordValue returns the content of the verification record
+func (d *SSODomain) TXTRecordValue() string {
+	return "payperplay-sso-verification=" + d.Token
+}
+
+// SSOPendingLink is an SSO login that matched an existing, not yet linked local account
+// The IdP identity is only linked after the account holder confirms it, either with the link token
+// returned to the browser plus their password, or with the token emailed to the account's address.
+type SSOPendingLink struct {
+	ID             uint      `gorm:"primaryKey"`
+	TokenHash      string    `gorm:"size:64;not null;uniqueIndex"` // Returned by the callback, redeemed with the password
+	EmailTokenHash string    `gorm:"size:64;not null;uniqueIndex"` // Sent to the account's email address
+	OrganizationID string    `gorm:"size:36;not null"`
+	Subject        string    `gorm:"size:255;not null"`
+	UserID         string    `gorm:"size:36;not null;index"`
+	Email          string    `gorm:"size:255"`
+	RoleClaims     []string  `gorm:"serializer:json;type:text"` // Role claim values of the login, applied on confirmation
+	ExpiresAt      time.Time `gorm:"not null;index"`
+	CreatedAt      time.Time
+}
+
+// IsExpired checks if the pending link is expired
+func (l *SSOPendingLink) IsExpired() bool {
+	return time.Now().After(l.ExpiresAt)
+}
+
+// SSOLoginState is the temporary state of an SSO login for CSRF and replay protection
+type SSOLoginState struct {
+	State          string    `gorm:"primaryKey;size:64"`
+	OrganizationID string    `gorm:"size:36;not null"`
+	Nonce          string    `gorm:"size:64;not null"`
+	ExpiresAt      time.Time `gorm:"not null;index"`
+	CreatedAt      time.Time
+}
+
+// IsExpired checks if the SSO login state is expired
+func (s *SSOLoginState) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// SSO errors
+var (
+	ErrSSONotConfigured       = errors.New("organization has no SSO connection")
+	ErrSSODisabled            = errors.New("SSO connection is disabled")
+	ErrSSOUnsupportedProtocol = errors.New("unsupported SSO protocol (only oidc is supported)")
+	ErrSSOInvalidConfig       = errors.New("invalid SSO configuration")
+	ErrSSOInvalidState        = errors.New("invalid or expired SSO state")
+	ErrSSOInvalidIDToken      = errors.New("identity provider returned an invalid ID token")
+	ErrSSONotAuthorized       = errors.New("your identity provider grants you no role in this organization")
+	ErrSSOEmailNotAllowed     = errors.New("email is not verified or not in the organization's SSO domains")
+	ErrSSORequired            = errors.New("organization requires single sign-on")
+	ErrSSOSessionExpired      = errors.New("SSO session expired, sign in again")
+	ErrSSODomainNotFound      = errors.New("domain is not claimed by the organization's SSO connection")
+	ErrSSODomainTaken         = errors.New("domain is already verified by another organization")
+	ErrSSODomainUnverified    = errors.New("domain ownership could not be verified, publish the TXT record and try again")
+	ErrSSOLinkRequired        = errors.New("an account with this email already exists, confirm linking it to your identity provider")
+	ErrSSOLinkInvalid         = errors.New("invalid or expired SSO link confirmation")
+)
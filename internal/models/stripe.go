@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// StripeCustomer links a user to their Stripe customer and metered subscription
+type StripeCustomer struct {
+	ID                 uint   `gorm:"primaryKey" json:"id"`
+	UserID             string `gorm:"size:36;not null;uniqueIndex" json:"user_id"`
+	CustomerID         string `gorm:"size:64;not null;uniqueIndex" json:"customer_id"`
+	SubscriptionID     string `gorm:"size:64" json:"subscription_id"`
+	SubscriptionItemID string `gorm:"size:64" json:"-"` // Metered item usage records are reported against
+
+	DefaultPaymentMethodID string `gorm:"size:64" json:"default_payment_method_id"`
+
+	// Dunning
+	FailedPaymentCount int        `gorm:"default:0" json:"failed_payment_count"` // Consecutive failed invoice payments
+	Suspended          bool       `gorm:"default:false;index" json:"suspended"`
+	SuspendedAt        *time.Time `json:"suspended_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StripeUsageReport records which usage sessions were reported to Stripe (one report per session)
+type StripeUsageReport struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`
+	UsageSessionID string `gorm:"size:64;not null;uniqueIndex" json:"usage_session_id"`
+	UserID         string `gorm:"size:36;not null;index" json:"user_id"`
+	ServerID       string `gorm:"size:64;index" json:"server_id"`
+
+	QuantityCents int64     `json:"quantity_cents"`
+	UsageRecordID string    `gorm:"size:64" json:"usage_record_id"`
+	ReportedAt    time.Time `json:"reported_at"`
+}
+
+// StripeInvoice mirrors a Stripe invoice (kept in sync via webhooks)
+type StripeInvoice struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	InvoiceID string `gorm:"size:64;not null;uniqueIndex" json:"invoice_id"`
+	UserID    string `gorm:"size:36;not null;index" json:"user_id"`
+
+	Status           string     `gorm:"size:32" json:"status"` // draft, open, paid, uncollectible, void
+	Currency         string     `gorm:"size:8" json:"currency"`
+	AmountDueCents   int64      `json:"amount_due_cents"`
+	AmountPaidCents  int64      `json:"amount_paid_cents"`
+	AttemptCount     int        `json:"attempt_count"`
+	HostedInvoiceURL string     `gorm:"size:512" json:"hosted_invoice_url"`
+	PeriodStart      *time.Time `json:"period_start,omitempty"`
+	PeriodEnd        *time.Time `json:"period_end,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StripeWebhookEvent records a processed webhook event; Stripe retries deliveries, so an event
+// ID is inserted in the same transaction as its state change and processed at most once
+type StripeWebhookEvent struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	EventID     string    `gorm:"size:255;not null;uniqueIndex" json:"event_id"`
+	Type        string    `gorm:"size:64" json:"type"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
@@ -18,6 +18,10 @@ type ServerTemplate struct {
 	WorldPreset string                 `json:"worldPreset,omitempty"` // flat, void, skyblock, etc.
 	Tags        []string               `json:"tags"`       // searchable tags
 	Popular     bool                   `json:"popular"`    // Featured template
+	OwnerID     string                 `json:"ownerId,omitempty"`  // Owner of a user-defined template (empty = built-in)
+	BackupID    string                 `json:"backupId,omitempty"` // World snapshot of a user-defined template
+	Revision    int                    `json:"revision,omitempty"`  // Current revision of a user-defined template
+	Published   bool                   `json:"published,omitempty"` // User-defined template listed in the marketplace
 	CreatedAt   time.Time              `json:"createdAt"`
 	UpdatedAt   time.Time              `json:"updatedAt"`
 }
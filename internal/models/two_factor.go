@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeCount is the number of one-time recovery codes issued per user
+const RecoveryCodeCount = 10
+
+// RecoveryCode is a one-time code that replaces a TOTP code (lost authenticator)
+// Only the SHA-256 hash is stored; the codes are shown once when generated.
+type RecoveryCode struct {
+	ID        string     `gorm:"primaryKey;size:36" json:"id"`
+	UserID    string     `gorm:"size:36;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;not null;index" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// BeforeCreate hook to generate UUID
+func (r *RecoveryCode) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// SensitiveOperation is an action that requires a fresh 2FA code for users with 2FA enabled
+type SensitiveOperation string
+
+const (
+	SensitiveServerDelete    SensitiveOperation = "server.delete"    // Deleting servers (single and bulk)
+	SensitiveAccountDelete   SensitiveOperation = "account.delete"   // Deleting the account
+	SensitivePaymentSettings SensitiveOperation = "payment.settings" // Payment methods and billing details
+	SensitiveAPIKeyCreate    SensitiveOperation = "api_key.create"   // Creating personal or organization API keys
+	SensitiveSSOConfigure    SensitiveOperation = "sso.configure"    // Changing or removing an organization's SSO connection
+	SensitiveWorldReset      SensitiveOperation = "world.reset"      // Regenerating a server's world
+	SensitiveSFTPKeyCreate   SensitiveOperation = "sftp_key.create"  // Adding an SSH key for the SFTP gateway
+)
+
+// Two-factor authentication errors
+var (
+	ErrTwoFactorRequired       = errors.New("two-factor authentication code required")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor authentication code")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("start two-factor enrollment first")
+	ErrInvalidChallengeToken   = errors.New("invalid or expired two-factor login challenge")
+)
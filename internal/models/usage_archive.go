@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UsageArchive is one month of a history table (usage logs, system or billing events) exported
+// to archive storage as gzipped CSV before its rows were deleted from the database. Rows that
+// arrive for an already archived month (e.g. a usage log closed late) go into the next part.
+type UsageArchive struct {
+	ID        string    `gorm:"primaryKey;size:36" json:"id"`
+	Source    string    `gorm:"size:64;not null;uniqueIndex:idx_usage_archive_part" json:"source"` // Table name
+	Month     string    `gorm:"size:7;not null;uniqueIndex:idx_usage_archive_part" json:"month"`   // YYYY-MM
+	Part      int       `gorm:"not null;uniqueIndex:idx_usage_archive_part" json:"part"`
+	Rows      int64     `json:"rows"`
+	SizeBytes int64     `json:"size_bytes"`
+	SHA256    string    `gorm:"size:64" json:"sha256"`
+	Location  string    `gorm:"type:text" json:"location"` // Storage Box path, or local path without Storage Box
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (UsageArchive) TableName() string {
+	return "usage_archives"
+}
+
+// BeforeCreate generates the archive ID
+func (a *UsageArchive) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	return nil
+}
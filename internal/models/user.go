@@ -38,6 +38,13 @@ type User struct {
 	FailedLoginAttempts int        `gorm:"default:0" json:"-"`
 	LockedUntil         *time.Time `json:"-"`
 	LastPasswordChange  *time.Time `json:"-"`
+	OAuthOnly           bool       `gorm:"default:false" json:"-"` // Created via OAuth with a random password the user doesn't know
+
+	// Two-factor authentication (TOTP)
+	TwoFactorEnabled   bool       `gorm:"default:false" json:"two_factor_enabled"`
+	TwoFactorSecret    string     `gorm:"size:255" json:"-"` // AES-GCM encrypted TOTP secret (pending until enrollment is confirmed)
+	TwoFactorLastStep  int64      `gorm:"default:0" json:"-"` // Last accepted TOTP time step (prevents code replay)
+	TwoFactorEnabledAt *time.Time `json:"-"`
 
 	// Backup Plan & Limits
 	BackupPlan         string `gorm:"size:20;default:'basic'" json:"backup_plan"` // basic, premium, enterprise
@@ -45,6 +52,12 @@ type User struct {
 	MaxRestoresPerMonth int   `gorm:"default:5" json:"max_restores_per_month"`   // Max restores/month (0 = unlimited)
 	MaxBackupStorageGB int    `gorm:"default:10" json:"max_backup_storage_gb"`   // Max backup storage quota in GB (0 = unlimited)
 
+	// Invoice details (VAT is determined by BillingCountry)
+	BillingName    string `gorm:"size:255" json:"billing_name"`
+	BillingAddress string `gorm:"size:512" json:"billing_address"`
+	BillingCountry string `gorm:"size:2" json:"billing_country"` // ISO 3166-1 alpha-2
+	VATID          string `gorm:"size:32" json:"vat_id"`         // Business customers (EU reverse charge)
+
 	// Relationships - Temporarily commented out for testing
 	// Servers        []MinecraftServer `gorm:"foreignKey:OwnerID" json:"servers,omitempty"`
 	// TrustedDevices []TrustedDevice   `gorm:"foreignKey:UserID" json:"-"`
@@ -204,6 +217,7 @@ func (u *User) ResetFailedLogins() {
 func (u *User) UpdatePasswordChanged() {
 	now := time.Now()
 	u.LastPasswordChange = &now
+	u.OAuthOnly = false // User now knows their password
 }
 
 // Custom errors
@@ -216,4 +230,5 @@ var (
 	ErrInvalidResetToken        = errors.New("invalid or expired password reset token")
 	ErrAccountLocked            = errors.New("account is locked due to too many failed login attempts")
 	ErrEmailNotVerified         = errors.New("please verify your email before logging in")
+	ErrLastLoginMethod          = errors.New("cannot remove the last login method, set a password first")
 )
@@ -0,0 +1,223 @@
+package models
+
+import "time"
+
+// ServerSettings are the gameplay, world and performance settings of a server
+// Clones copy them from the source server and owner-defined templates store them.
+type ServerSettings struct {
+	MaxPlayers                  int    `json:"max_players"`
+	Gamemode                    string `json:"gamemode"`
+	Difficulty                  string `json:"difficulty"`
+	PVP                         bool   `json:"pvp"`
+	EnableCommandBlock          bool   `json:"enable_command_block"`
+	LevelSeed                   string `json:"level_seed"`
+	ViewDistance                int    `json:"view_distance"`
+	SimulationDistance          int    `json:"simulation_distance"`
+	AllowNether                 bool   `json:"allow_nether"`
+	AllowEnd                    bool   `json:"allow_end"`
+	GenerateStructures          bool   `json:"generate_structures"`
+	WorldType                   string `json:"world_type"`
+	BonusChest                  bool   `json:"bonus_chest"`
+	MaxWorldSize                int    `json:"max_world_size"`
+	SpawnProtection             int    `json:"spawn_protection"`
+	SpawnAnimals                bool   `json:"spawn_animals"`
+	SpawnMonsters               bool   `json:"spawn_monsters"`
+	SpawnNPCs                   bool   `json:"spawn_npcs"`
+	MaxTickTime                 int    `json:"max_tick_time"`
+	NetworkCompressionThreshold int    `json:"network_compression_threshold"`
+	MOTD                        string `json:"motd"`
+}
+
+// DefaultServerSettings returns the settings of a new server
+func DefaultServerSettings() ServerSettings {
+	return ServerSettings{
+		MaxPlayers:                  20,
+		Gamemode:                    "survival",
+		Difficulty:                  "normal",
+		PVP:                         true,
+		ViewDistance:                10,
+		SimulationDistance:          10,
+		AllowNether:                 true,
+		AllowEnd:                    true,
+		GenerateStructures:          true,
+		WorldType:                   "default",
+		MaxWorldSize:                29999984,
+		SpawnProtection:             16,
+		SpawnAnimals:                true,
+		SpawnMonsters:               true,
+		SpawnNPCs:                   true,
+		MaxTickTime:                 60000,
+		NetworkCompressionThreshold: 256,
+		MOTD:                        "A Minecraft Server",
+	}
+}
+
+// SettingsOf returns the current settings of a server
+func SettingsOf(server *MinecraftServer) ServerSettings {
+	return ServerSettings{
+		MaxPlayers:                  server.MaxPlayers,
+		Gamemode:                    server.Gamemode,
+		Difficulty:                  server.Difficulty,
+		PVP:                         server.PVP,
+		EnableCommandBlock:          server.EnableCommandBlock,
+		LevelSeed:                   server.LevelSeed,
+		ViewDistance:                server.ViewDistance,
+		SimulationDistance:          server.SimulationDistance,
+		AllowNether:                 server.AllowNether,
+		AllowEnd:                    server.AllowEnd,
+		GenerateStructures:          server.GenerateStructures,
+		WorldType:                   server.WorldType,
+		BonusChest:                  server.BonusChest,
+		MaxWorldSize:                server.MaxWorldSize,
+		SpawnProtection:             server.SpawnProtection,
+		SpawnAnimals:                server.SpawnAnimals,
+		SpawnMonsters:               server.SpawnMonsters,
+		SpawnNPCs:                   server.SpawnNPCs,
+		MaxTickTime:                 server.MaxTickTime,
+		NetworkCompressionThreshold: server.NetworkCompressionThreshold,
+		MOTD:                        server.MOTD,
+	}
+}
+
+// ApplyTo copies the settings onto a server
+func (s ServerSettings) ApplyTo(server *MinecraftServer) {
+	server.MaxPlayers = s.MaxPlayers
+	server.Gamemode = s.Gamemode
+	server.Difficulty = s.Difficulty
+	server.PVP = s.PVP
+	server.EnableCommandBlock = s.EnableCommandBlock
+	server.LevelSeed = s.LevelSeed
+	server.ViewDistance = s.ViewDistance
+	server.SimulationDistance = s.SimulationDistance
+	server.AllowNether = s.AllowNether
+	server.AllowEnd = s.AllowEnd
+	server.GenerateStructures = s.GenerateStructures
+	server.WorldType = s.WorldType
+	server.BonusChest = s.BonusChest
+	server.MaxWorldSize = s.MaxWorldSize
+	server.SpawnProtection = s.SpawnProtection
+	server.SpawnAnimals = s.SpawnAnimals
+	server.SpawnMonsters = s.SpawnMonsters
+	server.SpawnNPCs = s.SpawnNPCs
+	server.MaxTickTime = s.MaxTickTime
+	server.NetworkCompressionThreshold = s.NetworkCompressionThreshold
+	server.MOTD = s.MOTD
+}
+
+// Validate checks the settings with the rules of MinecraftServer.ValidateConfig
+func (s ServerSettings) Validate() error {
+	probe := &MinecraftServer{IdleTimeoutSeconds: 300} // Not part of the settings
+	s.ApplyTo(probe)
+	return probe.ValidateConfig()
+}
+
+// Properties returns the settings as server.properties entries
+func (s ServerSettings) Properties() map[string]interface{} {
+	props := map[string]interface{}{
+		"max-players":                   s.MaxPlayers,
+		"gamemode":                      s.Gamemode,
+		"difficulty":                    s.Difficulty,
+		"pvp":                           s.PVP,
+		"enable-command-block":          s.EnableCommandBlock,
+		"view-distance":                 s.ViewDistance,
+		"simulation-distance":           s.SimulationDistance,
+		"allow-nether":                  s.AllowNether,
+		"generate-structures":           s.GenerateStructures,
+		"level-type":                    s.WorldType,
+		"max-world-size":                s.MaxWorldSize,
+		"spawn-protection":              s.SpawnProtection,
+		"spawn-animals":                 s.SpawnAnimals,
+		"spawn-monsters":                s.SpawnMonsters,
+		"spawn-npcs":                    s.SpawnNPCs,
+		"max-tick-time":                 s.MaxTickTime,
+		"network-compression-threshold": s.NetworkCompressionThreshold,
+		"motd":                          s.MOTD,
+	}
+	if s.LevelSeed != "" {
+		props["level-seed"] = s.LevelSeed
+	}
+	return props
+}
+
+// TemplateSpec is what a user template creates: server type, version, RAM, settings
+// (including the world seed), plugins and optionally a world snapshot
+type TemplateSpec struct {
+	ServerType       ServerType     `gorm:"size:32;not null" json:"server_type"`
+	MinecraftVersion string         `gorm:"size:50;not null" json:"minecraft_version"`
+	RAMMb            int            `gorm:"not null" json:"ram_mb"`
+	Settings         ServerSettings `gorm:"serializer:json;type:text" json:"settings"` // settings.level_seed is the world seed
+	Plugins          []string       `gorm:"serializer:json;type:text" json:"plugins"`  // Marketplace plugin slugs installed on new servers
+	BackupID         string         `gorm:"size:36" json:"backup_id,omitempty"`        // World snapshot (BackupTypeTemplate), restored into new servers
+}
+
+// UserTemplate is a server template defined by its owner, e.g. saved while cloning a server
+// Every change creates a new revision (see UserTemplateRevision); servers are created from the
+// latest one unless a revision is requested. Published templates are listed in the template
+// marketplace and can be used by everyone. TemplateService lists a user's templates alongside the
+// built-in JSON templates (see ToServerTemplate).
+type UserTemplate struct {
+	ID          string `gorm:"primaryKey;size:36" json:"id"`
+	OwnerID     string `gorm:"size:36;not null;index" json:"owner_id"`
+	Name        string `gorm:"size:255;not null" json:"name"`
+	Description string `gorm:"size:1024" json:"description"`
+
+	TemplateSpec
+	Revision int `gorm:"not null;default:1" json:"revision"` // Current revision, starting at 1
+
+	Published   bool       `gorm:"index;default:false" json:"published"` // Listed in the template marketplace
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	UsageCount  int        `gorm:"default:0" json:"usage_count"` // Servers created from the template
+
+	SourceServerID string `gorm:"size:64" json:"source_server_id,omitempty"` // Server the template was saved from
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (UserTemplate) TableName() string {
+	return "user_templates"
+}
+
+// UserTemplateRevision is one saved version of a user template
+type UserTemplateRevision struct {
+	ID         uint   `gorm:"primaryKey" json:"-"`
+	TemplateID string `gorm:"size:36;not null;uniqueIndex:idx_template_revision" json:"template_id"`
+	Revision   int    `gorm:"not null;uniqueIndex:idx_template_revision" json:"revision"`
+	Changelog  string `gorm:"size:1024" json:"changelog,omitempty"`
+
+	TemplateSpec
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (UserTemplateRevision) TableName() string {
+	return "user_template_revisions"
+}
+
+// ToServerTemplate returns the template in the format of the built-in templates
+func (t *UserTemplate) ToServerTemplate() ServerTemplate {
+	return ServerTemplate{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Category:    UserTemplateCategory,
+		Icon:        "📦",
+		Version:     t.MinecraftVersion,
+		ServerType:  string(t.ServerType),
+		Memory:      t.RAMMb,
+		Properties:  t.Settings.Properties(),
+		Plugins:     t.Plugins,
+		Tags:        []string{UserTemplateCategory},
+		OwnerID:     t.OwnerID,
+		BackupID:    t.BackupID,
+		Revision:    t.Revision,
+		Published:   t.Published,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+// UserTemplateCategory is the category of owner-defined templates
+const UserTemplateCategory = "custom"
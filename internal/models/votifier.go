@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// VotifierConfig represents the managed NuVotifier setup of a server (vote site integrations)
+type VotifierConfig struct {
+	ID       uint             `gorm:"primaryKey" json:"id"`
+	ServerID string           `gorm:"size:64;not null;uniqueIndex" json:"server_id"`
+	Server   *MinecraftServer `gorm:"foreignKey:ServerID" json:"-"`
+	Enabled  bool             `gorm:"default:false;not null" json:"enabled"`
+
+	// Vote site settings (Votifier v1 uses the RSA key, NuVotifier v2 the token)
+	Port       int    `gorm:"default:0;not null" json:"port"`       // Host port vote sites connect to (mirrored in MinecraftServer.VotifierPort)
+	PublicKey  string `gorm:"type:text;not null" json:"public_key"` // Base64 X.509, as pasted into vote sites
+	PrivateKey string `gorm:"type:text;not null" json:"-"`          // Base64 PKCS#8, written to the plugin folder
+	Token      string `gorm:"size:64;not null" json:"token"`        // NuVotifier v2 HMAC token
+
+	// Platform vote relay (POST /votes/:server_id)
+	RelayToken   string     `gorm:"size:64;not null" json:"relay_token"`
+	VotesRelayed int64      `gorm:"default:0;not null" json:"votes_relayed"`
+	LastVoteAt   *time.Time `json:"last_vote_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Vote is a vote received from a server list site
+type Vote struct {
+	ServiceName string `json:"service_name" binding:"required"` // Vote site identifier
+	Username    string `json:"username" binding:"required"`     // Minecraft player name
+	Address     string `json:"address"`                         // Voter IP (as reported by the site)
+	Timestamp   int64  `json:"timestamp"`                       // Unix millis (0 = now)
+}
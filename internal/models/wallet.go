@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// WalletTransactionType represents the reason for a balance change
+type WalletTransactionType string
+
+const (
+	WalletTxPurchase   WalletTransactionType = "purchase"   // Credit bought (e.g. Stripe top-up)
+	WalletTxUsage      WalletTransactionType = "usage"      // Runtime deducted from a usage session
+	WalletTxRefund     WalletTransactionType = "refund"     // Over-deducted usage returned
+	WalletTxAdjustment WalletTransactionType = "adjustment" // Manual correction
+)
+
+// WalletTransaction is a single entry in a user's balance ledger
+// User.Balance is the running total; the ledger explains every change to it.
+type WalletTransaction struct {
+	ID     uint                  `gorm:"primaryKey" json:"id"`
+	UserID string                `gorm:"size:36;not null;index" json:"user_id"`
+	Type   WalletTransactionType `gorm:"size:20;not null;index;uniqueIndex:idx_wallet_tx_reference,priority:2,where:reference <> ''" json:"type"`
+
+	AmountEUR       float64 `gorm:"not null" json:"amount_eur"` // Positive = credit, negative = debit
+	BalanceAfterEUR float64 `json:"balance_after_eur"`
+
+	ServerID       string `gorm:"size:64;index" json:"server_id,omitempty"`
+	UsageSessionID string `gorm:"size:64;index" json:"usage_session_id,omitempty"`
+	Reference      string `gorm:"size:128;uniqueIndex:idx_wallet_tx_reference,priority:1,where:reference <> ''" json:"reference,omitempty"` // External ID (payment intent), unique per type for idempotency
+	Description    string `gorm:"size:255" json:"description"`
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
@@ -1,39 +1,78 @@
 package models
 
 import (
+	"net/url"
+	"strings"
 	"time"
 )
 
-// ServerWebhook represents a Discord webhook configuration for a server
+// WebhookProvider is the chat service a webhook URL delivers to (decides the payload format)
+type WebhookProvider string
+
+const (
+	WebhookProviderDiscord WebhookProvider = "discord" // Embeds
+	WebhookProviderSlack   WebhookProvider = "slack"   // Incoming webhook with attachments
+)
+
+// Valid returns true for supported providers
+func (p WebhookProvider) Valid() bool {
+	return p == WebhookProviderDiscord || p == WebhookProviderSlack
+}
+
+// DetectWebhookProvider guesses the provider from a webhook URL (Discord unless it's a Slack hook)
+func DetectWebhookProvider(webhookURL string) WebhookProvider {
+	parsed, err := url.Parse(webhookURL)
+	if err == nil && strings.HasSuffix(strings.ToLower(parsed.Hostname()), "hooks.slack.com") {
+		return WebhookProviderSlack
+	}
+	return WebhookProviderDiscord
+}
+
+// ServerWebhook represents a Discord or Slack webhook configuration for a server
 type ServerWebhook struct {
-	ID        uint             `gorm:"primaryKey" json:"id"`
-	ServerID  string           `gorm:"size:64;not null;index" json:"server_id"`
-	Server    *MinecraftServer `gorm:"foreignKey:ServerID" json:"-"`
-	WebhookURL string          `gorm:"type:text;not null" json:"webhook_url"`
-	Enabled   bool             `gorm:"default:true;not null" json:"enabled"`
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	ServerID   string           `gorm:"size:64;not null;index" json:"server_id"`
+	Server     *MinecraftServer `gorm:"foreignKey:ServerID" json:"-"`
+	WebhookURL string           `gorm:"type:text;not null" json:"webhook_url"`
+	Provider   WebhookProvider  `gorm:"size:20;default:'discord';not null" json:"provider"`
+	Enabled    bool             `gorm:"default:true;not null" json:"enabled"`
 
 	// Event filters (which events to send)
-	OnServerStart   bool `gorm:"default:true;not null" json:"on_server_start"`
-	OnServerStop    bool `gorm:"default:true;not null" json:"on_server_stop"`
-	OnServerCrash   bool `gorm:"default:true;not null" json:"on_server_crash"`
-	OnPlayerJoin    bool `gorm:"default:true;not null" json:"on_player_join"`
-	OnPlayerLeave   bool `gorm:"default:true;not null" json:"on_player_leave"`
-	OnBackupCreated bool `gorm:"default:false;not null" json:"on_backup_created"`
+	OnServerStart        bool `gorm:"default:true;not null" json:"on_server_start"`
+	OnServerStop         bool `gorm:"default:true;not null" json:"on_server_stop"`
+	OnServerCrash        bool `gorm:"default:true;not null" json:"on_server_crash"`
+	OnServerRecovered    bool `gorm:"default:true;not null" json:"on_server_recovered"` // Crash recovery finished, lists players that were online
+	OnPlayerJoin         bool `gorm:"default:true;not null" json:"on_player_join"`
+	OnPlayerLeave        bool `gorm:"default:true;not null" json:"on_player_leave"`
+	OnBackupCreated      bool `gorm:"default:false;not null" json:"on_backup_created"`
+	OnBackupFailed       bool `gorm:"default:true;not null" json:"on_backup_failed"`
+	OnMigrationCompleted bool `gorm:"default:true;not null" json:"on_migration_completed"`
+	OnBudgetWarning      bool `gorm:"default:true;not null" json:"on_budget_warning"`     // Budget caps of the server or its owner
+	OnPregenCompleted    bool `gorm:"default:true;not null" json:"on_pregen_completed"`   // Chunk pre-generation finished
+	OnDiskQuotaWarning   bool `gorm:"default:true;not null" json:"on_disk_quota_warning"` // Server directory near or over its disk quota
+	OnLowTPS             bool `gorm:"default:true;not null" json:"on_low_tps"`            // TPS stayed below PERFORMANCE_LOW_TPS
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// WebhookEvent represents the type of event being sent to Discord
+// WebhookEvent represents the type of event being sent to a webhook
 type WebhookEvent string
 
 const (
-	WebhookEventServerStart   WebhookEvent = "server_start"
-	WebhookEventServerStop    WebhookEvent = "server_stop"
-	WebhookEventServerCrash   WebhookEvent = "server_crash"
-	WebhookEventPlayerJoin    WebhookEvent = "player_join"
-	WebhookEventPlayerLeave   WebhookEvent = "player_leave"
-	WebhookEventBackupCreated WebhookEvent = "backup_created"
+	WebhookEventServerStart        WebhookEvent = "server_start"
+	WebhookEventServerStop         WebhookEvent = "server_stop"
+	WebhookEventServerCrash        WebhookEvent = "server_crash"
+	WebhookEventServerRecovered    WebhookEvent = "server_recovered"
+	WebhookEventPlayerJoin         WebhookEvent = "player_join"
+	WebhookEventPlayerLeave        WebhookEvent = "player_leave"
+	WebhookEventBackupCreated      WebhookEvent = "backup_created"
+	WebhookEventBackupFailed       WebhookEvent = "backup_failed"
+	WebhookEventMigrationCompleted WebhookEvent = "migration_completed"
+	WebhookEventBudgetWarning      WebhookEvent = "budget_warning"
+	WebhookEventPregenCompleted    WebhookEvent = "pregen_completed"
+	WebhookEventDiskQuotaWarning   WebhookEvent = "disk_quota_warning"
+	WebhookEventLowTPS             WebhookEvent = "low_tps"
 )
 
 // DiscordWebhookPayload represents a Discord webhook message
@@ -67,12 +106,28 @@ type DiscordEmbedFooter struct {
 	IconURL string `json:"icon_url,omitempty"`
 }
 
+// SlackWebhookPayload represents a Slack incoming webhook message
+type SlackWebhookPayload struct {
+	Text        string            `json:"text,omitempty"` // Notification fallback
+	Attachments []SlackAttachment `json:"attachments,omitempty"`
+}
+
+// SlackAttachment represents a Slack message attachment (colored side bar)
+type SlackAttachment struct {
+	Color  string `json:"color,omitempty"` // Hex, e.g. "#2ecc71"
+	Title  string `json:"title,omitempty"`
+	Text   string `json:"text,omitempty"` // Slack mrkdwn
+	Footer string `json:"footer,omitempty"`
+	Ts     int64  `json:"ts,omitempty"`
+}
+
 // WebhookEventData contains event-specific data for webhooks
 type WebhookEventData struct {
 	ServerID   string
 	ServerName string
 	EventType  WebhookEvent
 	PlayerName string // for player events
+	Action     string // for budget events: "warning" or "auto_stop"
 	Message    string // additional context
 	Timestamp  time.Time
 }
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Webhook endpoint errors
+var (
+	ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookEndpointInvalid  = errors.New("invalid webhook endpoint")
+	ErrWebhookEndpointLimit    = errors.New("webhook endpoint limit reached, delete unused endpoints first")
+)
+
+// WebhookEndpoint receives the account's events as signed JSON (unlike ServerWebhook, which posts
+// Discord or Slack messages for one server). Each delivery carries an HMAC-SHA256 signature made
+// with the endpoint's secret, so receivers can verify it came from us.
+type WebhookEndpoint struct {
+	ID          string    `gorm:"primaryKey;size:36" json:"id"`
+	UserID      string    `gorm:"size:64;not null;index" json:"user_id"`
+	URL         string    `gorm:"type:text;not null" json:"url"`
+	Description string    `gorm:"size:255" json:"description,omitempty"`
+	Events      []string  `gorm:"serializer:json;type:text" json:"events"`  // Event types or "<category>.*" / "*" patterns
+	ServerID    string    `gorm:"size:64;index" json:"server_id,omitempty"` // Only events of this server (empty = all)
+	Secret      string    `gorm:"size:128;not null" json:"-"`               // HMAC signing key, only shown on create and rotation
+	Enabled     bool      `gorm:"not null;default:true" json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// BeforeCreate generates the endpoint ID
+func (e *WebhookEndpoint) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// WebhookDeliveryStatus is the state of a delivery to a webhook endpoint
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Being delivered or waiting for a retry
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // Endpoint answered 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // All attempts failed, can be redelivered
+)
+
+// WebhookDelivery is one event sent to an endpoint with all its attempts. A redelivery is a new
+// delivery of the same payload (RedeliveryOf points to the original).
+type WebhookDelivery struct {
+	ID           string                   `gorm:"primaryKey;size:36" json:"id"`
+	EndpointID   string                   `gorm:"size:36;not null;index:idx_webhook_deliveries_endpoint" json:"endpoint_id"`
+	EventID      string                   `gorm:"size:36;index" json:"event_id"`
+	EventType    string                   `gorm:"size:64;not null" json:"event_type"`
+	Payload      string                   `gorm:"type:text;not null" json:"payload"` // The signed JSON body
+	Status       WebhookDeliveryStatus    `gorm:"size:16;not null;index" json:"status"`
+	Attempts     []WebhookDeliveryAttempt `gorm:"serializer:json;type:text" json:"attempts"`
+	RedeliveryOf string                   `gorm:"size:36" json:"redelivery_of,omitempty"`
+	CreatedAt    time.Time                `gorm:"index;index:idx_webhook_deliveries_endpoint" json:"created_at"`
+	DeliveredAt  *time.Time               `json:"delivered_at,omitempty"`
+}
+
+// TableName specifies the table name
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// BeforeCreate generates the delivery ID
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// WebhookDeliveryAttempt is one POST of a delivery
+type WebhookDeliveryAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"` // 0 = no response (network error)
+	DurationMS int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
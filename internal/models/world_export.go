@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WorldExportStatus is the lifecycle state of a world export
+type WorldExportStatus string
+
+const (
+	WorldExportPending WorldExportStatus = "pending" // Archive is being created and uploaded
+	WorldExportReady   WorldExportStatus = "ready"   // Downloadable until ExpiresAt
+	WorldExportFailed  WorldExportStatus = "failed"
+	WorldExportExpired WorldExportStatus = "expired" // Archive was deleted
+)
+
+// WorldExport is a ZIP archive of the worlds of a server, downloadable through a signed URL
+// The archive is kept on the Storage Box (or locally without one) until ExpiresAt and then deleted.
+type WorldExport struct {
+	ID          string            `gorm:"primaryKey;size:36" json:"id"`
+	ServerID    string            `gorm:"size:64;not null;index" json:"server_id"`
+	OwnerID     string            `gorm:"size:36;not null;index" json:"owner_id"`
+	RequestedBy string            `gorm:"size:36" json:"requested_by,omitempty"`
+	Worlds      []string          `gorm:"serializer:json;type:text" json:"worlds"` // World folders in the archive
+	Status      WorldExportStatus `gorm:"size:16;not null;index" json:"status"`
+	SizeBytes   int64             `json:"size_bytes"`
+	StoragePath string            `gorm:"type:text" json:"-"`              // Path on the Storage Box or local disk
+	Remote      bool              `gorm:"not null;default:false" json:"-"` // StoragePath is on the Storage Box
+	Error       string            `gorm:"type:text" json:"error,omitempty"`
+	ExpiresAt   *time.Time        `gorm:"index" json:"expires_at,omitempty"`
+	DownloadURL string            `gorm:"-" json:"download_url,omitempty"` // Signed URL, set while ready
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (WorldExport) TableName() string {
+	return "world_exports"
+}
+
+// BeforeCreate generates the export ID
+func (e *WorldExport) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	return nil
+}
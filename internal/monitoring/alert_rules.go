@@ -0,0 +1,11 @@
+package monitoring
+
+import (
+	_ "embed"
+)
+
+// AlertRules is the Prometheus rule file for the metrics in metrics.go
+// Prometheus loads it from GET /prometheus/rules (e.g. fetched into rule_files by a sidecar or cron).
+//
+//go:embed alert_rules.yml
+var AlertRules []byte
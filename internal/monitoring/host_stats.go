@@ -0,0 +1,133 @@
+package monitoring
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// CPUTimes are the cumulative CPU jiffies of the host (first line of /proc/stat)
+type CPUTimes struct {
+	Idle  uint64 // idle + iowait
+	Total uint64
+}
+
+// ReadCPUTimes reads the cumulative CPU times of the host
+// Usage is the delta between two samples (see CPUPercent).
+func ReadCPUTimes() (CPUTimes, error) {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return CPUTimes{}, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return CPUTimes{}, fmt.Errorf("empty /proc/stat")
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return CPUTimes{}, fmt.Errorf("unexpected /proc/stat format")
+	}
+
+	var times CPUTimes
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return CPUTimes{}, fmt.Errorf("invalid /proc/stat value %q: %w", field, err)
+		}
+		times.Total += value
+		if i == 3 || i == 4 { // idle, iowait
+			times.Idle += value
+		}
+	}
+	return times, nil
+}
+
+// CPUPercent returns the CPU usage between two samples (0-100)
+func CPUPercent(prev, cur CPUTimes) float64 {
+	total := float64(cur.Total - prev.Total)
+	if cur.Total <= prev.Total || total == 0 {
+		return 0
+	}
+	idle := float64(cur.Idle - prev.Idle)
+	return (total - idle) / total * 100
+}
+
+// MemoryStats is the memory usage of the host in bytes
+type MemoryStats struct {
+	TotalBytes     uint64
+	AvailableBytes uint64 // Includes reclaimable page cache
+}
+
+// UsedPercent returns the share of memory that is not available (0-100)
+func (m MemoryStats) UsedPercent() float64 {
+	if m.TotalBytes == 0 {
+		return 0
+	}
+	return float64(m.TotalBytes-m.AvailableBytes) / float64(m.TotalBytes) * 100
+}
+
+// ReadMemoryStats reads MemTotal and MemAvailable from /proc/meminfo
+func ReadMemoryStats() (MemoryStats, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return MemoryStats{}, err
+	}
+	defer file.Close()
+
+	var stats MemoryStats
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			stats.TotalBytes = value * 1024 // kB
+		case "MemAvailable:":
+			stats.AvailableBytes = value * 1024
+		}
+	}
+	if stats.TotalBytes == 0 {
+		return MemoryStats{}, fmt.Errorf("MemTotal not found in /proc/meminfo")
+	}
+	return stats, scanner.Err()
+}
+
+// DiskStats is the usage of the filesystem containing a path in bytes
+type DiskStats struct {
+	Path           string
+	TotalBytes     uint64
+	AvailableBytes uint64 // Available to unprivileged users
+}
+
+// UsedPercent returns the share of the filesystem that is not available (0-100)
+func (d DiskStats) UsedPercent() float64 {
+	if d.TotalBytes == 0 {
+		return 0
+	}
+	return float64(d.TotalBytes-d.AvailableBytes) / float64(d.TotalBytes) * 100
+}
+
+// ReadDiskStats returns the usage of the filesystem containing path
+func ReadDiskStats(path string) (DiskStats, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return DiskStats{}, fmt.Errorf("statfs %s: %w", path, err)
+	}
+	blockSize := uint64(stat.Bsize)
+	return DiskStats{
+		Path:           path,
+		TotalBytes:     uint64(stat.Blocks) * blockSize,
+		AvailableBytes: uint64(stat.Bavail) * blockSize,
+	}, nil
+}
@@ -168,6 +168,16 @@ var (
 		[]string{"server_id", "server_name"},
 	)
 
+	// Cold-start metrics (AppCDS warm-up tracking)
+	ServerStartupDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "payperplay_server_startup_seconds",
+			Help:    "Time from container start until the Minecraft server reports ready (successful boots only)",
+			Buckets: []float64{5, 10, 15, 20, 30, 45, 60, 90, 120},
+		},
+		[]string{"server_type", "appcds"}, // appcds: cold/warm (archive present before the boot), disabled, unknown
+	)
+
 	ServerCrashTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "payperplay_server_crashes_total",
@@ -210,6 +220,98 @@ var (
 		},
 		[]string{"method", "endpoint"},
 	)
+
+	// Control plane host metrics
+	ControlPlaneCPUPercent = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "payperplay_control_plane_cpu_percent",
+			Help: "CPU usage of the control plane host in percent",
+		},
+	)
+
+	ControlPlaneMemoryPercent = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "payperplay_control_plane_memory_percent",
+			Help: "Memory usage of the control plane host in percent (excluding reclaimable cache)",
+		},
+	)
+
+	ControlPlaneDiskPercent = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "payperplay_control_plane_disk_percent",
+			Help: "Disk usage of a watched control plane filesystem in percent",
+		},
+		[]string{"path"},
+	)
+
+	ControlPlanePressure = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "payperplay_control_plane_pressure",
+			Help: "Control plane resource pressure (0=ok, 1=warning, 2=critical)",
+		},
+	)
+
+	// Alerting metrics (used by the bundled alert rules, see alert_rules.yml)
+	NodeUp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "payperplay_node_up",
+			Help: "Whether a node passes its health check (1=healthy, 0=unhealthy)",
+		},
+		[]string{"node_id", "hostname", "type"},
+	)
+
+	NodeDiskFreeMB = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "payperplay_node_disk_free_mb",
+			Help: "Free disk space of the filesystem holding server data on a node in megabytes",
+		},
+		[]string{"node_id", "hostname"},
+	)
+
+	StartQueueLength = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "payperplay_start_queue_length",
+			Help: "Number of servers waiting in the start queue",
+		},
+	)
+
+	StartQueueOldestWaitSeconds = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "payperplay_start_queue_oldest_wait_seconds",
+			Help: "Time the longest-waiting server has been in the start queue",
+		},
+	)
+
+	BackupFailedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "payperplay_backups_failed_total",
+			Help: "Total number of failed backups",
+		},
+		[]string{"server_id", "server_name"},
+	)
+
+	OutboxPendingEvents = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "payperplay_outbox_pending_events",
+			Help: "Outbox events (billing and other consumers) not yet delivered",
+		},
+	)
+
+	OutboxFailuresTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "payperplay_outbox_failures_total",
+			Help: "Total number of failed outbox event deliveries",
+		},
+		[]string{"event_type"},
+	)
+
+	StateDrift = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "payperplay_state_drift",
+			Help: "Confirmed drift between database, Docker and Velocity that is not healed, by kind",
+		},
+		[]string{"kind"},
+	)
 )
 
 // StatusToFloat converts server status string to numeric value for Prometheus
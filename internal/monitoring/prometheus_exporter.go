@@ -224,6 +224,11 @@ func RecordBackupCreated(serverID, serverName string) {
 	BackupCreatedTotal.WithLabelValues(serverID, serverName).Inc()
 }
 
+// RecordBackupFailed increments the backup failed counter
+func RecordBackupFailed(serverID, serverName string) {
+	BackupFailedTotal.WithLabelValues(serverID, serverName).Inc()
+}
+
 // RecordBillingSeconds increments the billing seconds counter
 func RecordBillingSeconds(serverID, serverName, phase string, seconds float64) {
 	ServerBillingSecondsTotal.WithLabelValues(serverID, serverName, phase).Add(seconds)
@@ -45,7 +45,7 @@ func (r *RCONClient) GetTPS() (float64, error) {
 
 	// Parse TPS from response
 	// Example: "§aTPS from last 1m, 5m, 15m: §r§a20.0, §r§a20.0, §r§a20.0"
-	tps := parseTPS(response)
+	tps := ParseTPS(response)
 	if tps > 0 {
 		return tps, nil
 	}
@@ -74,8 +74,13 @@ func (r *RCONClient) GetPlayerCount() (int, int, error) {
 	return current, max, nil
 }
 
-// parseTPS extracts TPS value from command response
-func parseTPS(response string) float64 {
+// ParseTPS extracts the 1m TPS value from a "tps" command response (-1 if there is none)
+func ParseTPS(response string) float64 {
+	// Paper/Spigot "tps" and spark "spark tps": "TPS from last 1m, 5m, 15m: 20.0, 20.0, 20.0"
+	if values := parseWindowStats(response, "TPS from last"); len(values) > 0 {
+		return values[0]
+	}
+
 	// Remove color codes (§x)
 	cleanResponse := regexp.MustCompile(`§.`).ReplaceAllString(response, "")
 
@@ -104,6 +109,67 @@ func parseTPS(response string) float64 {
 	return -1
 }
 
+// msptStats are the tick time headers of Paper "mspt" and spark "spark tps", with the position of the value used as MSPT
+var msptStats = []struct {
+	title string
+	index int
+}{
+	{title: "Tick durations", index: 1},    // spark: min/med/95%ile/max
+	{title: "Server tick times", index: 0}, // Paper: avg/min/max
+}
+
+// ParseMSPT extracts the milliseconds per tick of the last minute from a Paper "mspt" or spark "spark tps"
+// response (-1 if there is none)
+func ParseMSPT(response string) float64 {
+	for _, stat := range msptStats {
+		if values := parseWindowStats(response, stat.title); len(values) > stat.index {
+			return values[stat.index]
+		}
+	}
+	return -1
+}
+
+// windowStatNumber matches one value of a window stat (spark marks capped TPS with "*")
+var windowStatNumber = regexp.MustCompile(`[0-9]+(?:\.[0-9]+)?`)
+
+// parseWindowStats parses a "<title> ... from last 5s, 10s, 1m: <values>" response of Paper or spark and returns
+// the values of the 1m window (the first window if there is none), e.g. [2.1 1.0 5.6] for "2.1/1.0/5.6"
+func parseWindowStats(response, title string) []float64 {
+	clean := regexp.MustCompile(`§.`).ReplaceAllString(response, "")
+	start := strings.Index(strings.ToLower(clean), strings.ToLower(title))
+	if start < 0 {
+		return nil
+	}
+	header, values, ok := strings.Cut(clean[start:], ":")
+	if !ok {
+		return nil
+	}
+	_, windows, _ := strings.Cut(header, "from last")
+
+	// Values follow on the same line (Paper tps) or the next one (spark, Paper mspt)
+	line, _, _ := strings.Cut(strings.TrimSpace(values), "\n")
+	groups := strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ';' })
+	index := 0
+	for i, window := range strings.Split(windows, ",") {
+		if strings.TrimSpace(window) == "1m" {
+			index = i
+		}
+	}
+	if index >= len(groups) {
+		return nil
+	}
+
+	var numbers []float64
+	for _, field := range strings.Split(groups[index], "/") {
+		number, err := strconv.ParseFloat(windowStatNumber.FindString(field), 64)
+		if err != nil {
+			return nil
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
 // parsePlayerCount extracts player count from "list" command response
 func parsePlayerCount(response string) (current int, max int) {
 	// Remove color codes
@@ -0,0 +1,113 @@
+// Package ratelimit provides the request counters behind the API rate limits
+// Counters live in process memory or in Redis, so limits hold across API instances.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit allows Burst requests at once, refilled at PerMinute requests per minute
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+// interval is the time one request "costs" (GCRA emission interval)
+func (l Limit) interval() time.Duration {
+	return time.Minute / time.Duration(l.PerMinute)
+}
+
+// burst returns the burst allowance (at least one request)
+func (l Limit) burst() int {
+	if l.Burst < 1 {
+		return 1
+	}
+	return l.Burst
+}
+
+// Result is the outcome of taking a request from a limit
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration // Until the next request is allowed (when not Allowed)
+}
+
+// RetryAfterSeconds returns RetryAfter rounded up to whole seconds (Retry-After header)
+func (r Result) RetryAfterSeconds() int {
+	return int(math.Ceil(r.RetryAfter.Seconds()))
+}
+
+// Store counts requests per key
+// Both stores implement GCRA: a key holds the "theoretical arrival time" of the next request,
+// which advances by one interval per request and may run up to Burst intervals ahead of now.
+type Store interface {
+	Take(key string, limit Limit, now time.Time) (Result, error)
+	Reset(key string) error
+}
+
+// gcra applies one request to the theoretical arrival time tat
+// Returns the result and the new tat (unchanged if the request is rejected).
+func gcra(tat time.Time, limit Limit, now time.Time) (Result, time.Time) {
+	if limit.PerMinute <= 0 {
+		return Result{Allowed: true}, tat
+	}
+	if tat.Before(now) {
+		tat = now
+	}
+	interval := limit.interval()
+	allowAt := tat.Add(-time.Duration(limit.burst()-1) * interval)
+	if now.Before(allowAt) {
+		return Result{RetryAfter: allowAt.Sub(now)}, tat
+	}
+	return Result{Allowed: true}, tat.Add(interval)
+}
+
+// MemoryStore keeps counters in process memory (single API instance)
+type MemoryStore struct {
+	mu   sync.Mutex
+	tats map[string]time.Time
+}
+
+// NewMemoryStore creates an in-memory store and starts removing expired keys
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{tats: make(map[string]time.Time)}
+	go s.cleanup()
+	return s
+}
+
+// Take counts a request for key
+func (s *MemoryStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, tat := gcra(s.tats[key], limit, now)
+	if result.Allowed && limit.PerMinute > 0 {
+		s.tats[key] = tat
+	}
+	return result, nil
+}
+
+// Reset forgets the requests of key
+func (s *MemoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tats, key)
+	return nil
+}
+
+// cleanup removes keys whose burst is fully refilled
+func (s *MemoryStore) cleanup() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		s.mu.Lock()
+		for key, tat := range s.tats {
+			if tat.Before(now) {
+				delete(s.tats, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
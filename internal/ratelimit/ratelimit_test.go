@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	limit := Limit{PerMinute: 60, Burst: 3} // One request per second, three at once
+
+	tests := []struct {
+		name      string
+		offset    time.Duration
+		allowed   bool
+		retryWant time.Duration
+	}{
+		{"burst 1", 0, true, 0},
+		{"burst 2", 0, true, 0},
+		{"burst 3", 0, true, 0},
+		{"burst exhausted", 0, false, time.Second},
+		{"half refilled", 500 * time.Millisecond, false, 500 * time.Millisecond},
+		{"one refilled", time.Second, true, 0},
+		{"used again", time.Second, false, time.Second},
+		{"fully refilled", time.Minute, true, 0},
+	}
+
+	store := &MemoryStore{tats: make(map[string]time.Time)}
+	for _, tt := range tests {
+		result, err := store.Take("ip:1.2.3.4", limit, start.Add(tt.offset))
+		if err != nil {
+			t.Fatalf("%s: Take() error = %v", tt.name, err)
+		}
+		if result.Allowed != tt.allowed || result.RetryAfter != tt.retryWant {
+			t.Errorf("%s: Take() = %+v, want allowed %v, retry after %v", tt.name, result, tt.allowed, tt.retryWant)
+		}
+	}
+
+	// Keys are counted separately
+	if result, _ := store.Take("ip:5.6.7.8", limit, start.Add(time.Second)); !result.Allowed {
+		t.Errorf("other key rejected: %+v", result)
+	}
+
+	// Reset forgets the requests of a key
+	store.Take("ip:1.2.3.4", limit, start.Add(time.Minute))
+	store.Take("ip:1.2.3.4", limit, start.Add(time.Minute))
+	store.Reset("ip:1.2.3.4")
+	if result, _ := store.Take("ip:1.2.3.4", limit, start.Add(time.Minute)); !result.Allowed {
+		t.Errorf("Take() after Reset = %+v, want allowed", result)
+	}
+}
+
+func TestMemoryStoreUnlimited(t *testing.T) {
+	store := &MemoryStore{tats: make(map[string]time.Time)}
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		if result, _ := store.Take("user:1", Limit{}, now); !result.Allowed {
+			t.Fatalf("request %d rejected without a limit", i)
+		}
+	}
+	if len(store.tats) != 0 {
+		t.Errorf("unlimited requests were counted: %v", store.tats)
+	}
+}
+
+func TestParseGCRAReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   interface{}
+		want    Result
+		wantErr bool
+	}{
+		{"allowed", []interface{}{int64(1), int64(0)}, Result{Allowed: true}, false},
+		{"rejected", []interface{}{int64(0), int64(1500)}, Result{RetryAfter: 1500 * time.Millisecond}, false},
+		{"not an array", "OK", Result{}, true},
+		{"wrong types", []interface{}{"1", "0"}, Result{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGCRAReply(tt.reply)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGCRAReply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseGCRAReply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultRetryAfterSeconds(t *testing.T) {
+	if got := (Result{RetryAfter: 1500 * time.Millisecond}).RetryAfterSeconds(); got != 2 {
+		t.Errorf("RetryAfterSeconds() = %d, want 2 (rounded up)", got)
+	}
+}
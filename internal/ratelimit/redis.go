@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/storage"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	redisKeyPrefix     = "payperplay:ratelimit:"
+	redisErrorLogEvery = time.Minute // Log Redis failures at most this often
+)
+
+// gcraScript is gcra() running atomically in Redis (times in milliseconds)
+// KEYS[1] = counter, ARGV = now, interval, burst. Returns {allowed, retry_after_ms}.
+const gcraScript = `
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then tat = now end
+local allow_at = tat - (burst - 1) * interval
+if now < allow_at then return {0, allow_at - now} end
+tat = tat + interval
+redis.call('SET', KEYS[1], tat, 'PX', tat - now)
+return {1, 0}
+`
+
+// RedisStore keeps counters in Redis, shared by all API instances
+// While Redis is unreachable it falls back to per-instance counters instead of rejecting requests.
+type RedisStore struct {
+	client   *storage.RedisClient
+	fallback *MemoryStore
+
+	mu         sync.Mutex
+	lastLogged time.Time
+}
+
+// NewRedisStore connects to Redis (redis://[user:password@]host:6379[/db])
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	client, err := storage.NewRedisClient(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisStore{client: client, fallback: NewMemoryStore()}, nil
+}
+
+// Take counts a request for key
+func (s *RedisStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	if limit.PerMinute <= 0 {
+		return Result{Allowed: true}, nil
+	}
+
+	reply, err := s.client.Do("EVAL", gcraScript, "1", redisKeyPrefix+key,
+		strconv.FormatInt(now.UnixMilli(), 10),
+		strconv.FormatInt(max(limit.interval().Milliseconds(), 1), 10),
+		strconv.Itoa(limit.burst()))
+	if err == nil {
+		var result Result
+		if result, err = parseGCRAReply(reply); err == nil {
+			return result, nil
+		}
+	}
+
+	s.logFailure(err)
+	return s.fallback.Take(key, limit, now)
+}
+
+// Reset forgets the requests of key
+func (s *RedisStore) Reset(key string) error {
+	s.fallback.Reset(key)
+	_, err := s.client.Do("DEL", redisKeyPrefix+key)
+	return err
+}
+
+// parseGCRAReply decodes the {allowed, retry_after_ms} reply of gcraScript
+func parseGCRAReply(reply interface{}) (Result, error) {
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit reply: %v", reply)
+	}
+	allowed, ok1 := values[0].(int64)
+	retryMs, ok2 := values[1].(int64)
+	if !ok1 || !ok2 {
+		return Result{}, fmt.Errorf("unexpected rate limit reply: %v", reply)
+	}
+	return Result{Allowed: allowed == 1, RetryAfter: time.Duration(retryMs) * time.Millisecond}, nil
+}
+
+// logFailure logs a Redis error, at most once per redisErrorLogEvery
+func (s *RedisStore) logFailure(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.lastLogged) < redisErrorLogEvery {
+		return
+	}
+	s.lastLogged = time.Now()
+
+	logger.Warn("RATE-LIMIT: Redis unavailable, using per-instance counters", map[string]interface{}{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository handles database operations for API keys
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create creates an API key
+func (r *APIKeyRepository) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+// Update updates an API key
+func (r *APIKeyRepository) Update(key *models.APIKey) error {
+	return r.db.Save(key).Error
+}
+
+// FindByID finds an API key by ID
+func (r *APIKeyRepository) FindByID(id string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.First(&key, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByHash finds an API key by the SHA-256 hash of the key
+func (r *APIKeyRepository) FindByHash(hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.db.First(&key, "key_hash = ?", hash).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindPersonal returns the personal (non-organization) keys of a user
+func (r *APIKeyRepository) FindPersonal(userID string) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Where("user_id = ? AND organization_id = ''", userID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// FindByOrganization returns the keys of an organization
+func (r *APIKeyRepository) FindByOrganization(orgID string) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Where("organization_id = ?", orgID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// CountActive counts the unrevoked keys of a user (personal) or organization
+func (r *APIKeyRepository) CountActive(userID, orgID string) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.APIKey{}).Where("revoked_at IS NULL AND organization_id = ?", orgID)
+	if orgID == "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// TouchLastUsed records the last use of a key (without bumping updated_at)
+func (r *APIKeyRepository) TouchLastUsed(id, ip string, t time.Time) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"last_used_at": t, "last_used_ip": ip}).Error
+}
+
+// Revoke marks a key as revoked
+func (r *APIKeyRepository) Revoke(id string) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
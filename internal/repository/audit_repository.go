@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditRepository handles database operations for the audit log
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create stores an audit event
+func (r *AuditRepository) Create(event *models.AuditEvent) error {
+	return r.db.Create(event).Error
+}
+
+// auditPageColumns are the sortable audit columns
+var auditPageColumns = pageColumns[models.AuditEvent]{
+	"created_at": func(e *models.AuditEvent) interface{} { return e.CreatedAt },
+	"action":     func(e *models.AuditEvent) interface{} { return e.Action },
+}
+
+// auditFilterColumns maps list filters to audit columns
+var auditFilterColumns = map[string]string{
+	"user":          "actor_id",
+	"action":        "action",
+	"resource_type": "resource_type",
+	"resource_id":   "resource_id",
+	"result":        "result",
+}
+
+// FindPage returns one page of the audit events in [from, to) (zero times leave the range open).
+// Runs on a read replica if one is configured.
+func (r *AuditRepository) FindPage(from, to time.Time, page PageRequest) (*Page[models.AuditEvent], error) {
+	query := ReadDB(r.db).Model(&models.AuditEvent{})
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at < ?", to)
+	}
+	return paginate(query, page, "created_at", auditPageColumns, auditFilterColumns,
+		func(e *models.AuditEvent) string { return e.ID })
+}
+
+// DeleteBefore deletes audit events older than cutoff
+func (r *AuditRepository) DeleteBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", cutoff).Delete(&models.AuditEvent{})
+	return result.RowsAffected, result.Error
+}
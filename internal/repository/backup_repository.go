@@ -46,6 +46,27 @@ func (r *BackupRepository) FindByServerID(serverID string) ([]models.Backup, err
 	return backups, err
 }
 
+// backupPageColumns are the sortable backup columns
+var backupPageColumns = pageColumns[models.Backup]{
+	"created_at":      func(b *models.Backup) interface{} { return b.CreatedAt },
+	"compressed_size": func(b *models.Backup) interface{} { return b.CompressedSize },
+	"type":            func(b *models.Backup) interface{} { return b.Type },
+	"status":          func(b *models.Backup) interface{} { return b.Status },
+}
+
+// backupFilterColumns maps list filters to backup columns
+var backupFilterColumns = map[string]string{
+	"status": "status",
+	"type":   "type",
+}
+
+// FindByServerIDPage returns one page of a server's backups
+func (r *BackupRepository) FindByServerIDPage(serverID string, page PageRequest) (*Page[models.Backup], error) {
+	query := r.db.Model(&models.Backup{}).Where("server_id = ?", serverID)
+	return paginate(query, page, "created_at", backupPageColumns, backupFilterColumns,
+		func(b *models.Backup) string { return b.ID })
+}
+
 // FindByServerIDAndType finds backups by server and type
 func (r *BackupRepository) FindByServerIDAndType(serverID string, backupType models.BackupType) ([]models.Backup, error) {
 	var backups []models.Backup
@@ -70,6 +91,7 @@ func (r *BackupRepository) FindExpired() ([]models.Backup, error) {
 	now := time.Now()
 	err := r.db.Where("expires_at IS NOT NULL AND expires_at < ? AND status = ?",
 		now, models.BackupStatusCompleted).
+		Where("NOT EXISTS (?)", r.activeLegalHolds()).
 		Find(&backups).Error
 	return backups, err
 }
@@ -147,3 +169,69 @@ func (r *BackupRepository) MarkAsDeleted(id string) error {
 		Where("id = ?", id).
 		Update("status", models.BackupStatusDeleted).Error
 }
+
+// activeLegalHolds selects the active holds covering the backup of the outer query
+func (r *BackupRepository) activeLegalHolds() *gorm.DB {
+	return r.db.Model(&models.BackupLegalHold{}).Select("1").
+		Where("backup_legal_holds.released_at IS NULL").
+		Where("(backup_legal_holds.backup_id = backups.id OR (backup_legal_holds.backup_id = '' AND backup_legal_holds.server_id = backups.server_id))")
+}
+
+// CreateLegalHold creates a legal hold
+func (r *BackupRepository) CreateLegalHold(hold *models.BackupLegalHold) error {
+	return r.db.Create(hold).Error
+}
+
+// FindLegalHoldByID finds a legal hold by ID
+func (r *BackupRepository) FindLegalHoldByID(id string) (*models.BackupLegalHold, error) {
+	var hold models.BackupLegalHold
+	if err := r.db.Where("id = ?", id).First(&hold).Error; err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// FindLegalHolds lists legal holds, newest first (serverID "" = all servers)
+func (r *BackupRepository) FindLegalHolds(serverID string, includeReleased bool) ([]models.BackupLegalHold, error) {
+	var holds []models.BackupLegalHold
+	query := r.db.Order("placed_at DESC")
+	if serverID != "" {
+		query = query.Where("server_id = ?", serverID)
+	}
+	if !includeReleased {
+		query = query.Where("released_at IS NULL")
+	}
+	err := query.Find(&holds).Error
+	return holds, err
+}
+
+// FindActiveLegalHold returns an active hold covering the backup (nil if there is none)
+func (r *BackupRepository) FindActiveLegalHold(backup *models.Backup) (*models.BackupLegalHold, error) {
+	var holds []models.BackupLegalHold
+	err := r.db.Where("released_at IS NULL").
+		Where("backup_id = ? OR (backup_id = '' AND server_id = ?)", backup.ID, backup.ServerID).
+		Order("placed_at ASC").
+		Limit(1).
+		Find(&holds).Error
+	if err != nil || len(holds) == 0 {
+		return nil, err
+	}
+	return &holds[0], nil
+}
+
+// ReleaseLegalHold marks an active hold as released
+func (r *BackupRepository) ReleaseLegalHold(id, releasedBy string) error {
+	result := r.db.Model(&models.BackupLegalHold{}).
+		Where("id = ? AND released_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"released_by": releasedBy,
+			"released_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
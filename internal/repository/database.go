@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/pkg/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -14,11 +13,49 @@ import (
 var DB *gorm.DB
 var dbProvider DatabaseProvider
 
-// InitDB initializes the database connection
+// InitDB initializes the database connection and brings the schema up to date.
+// It refuses to start against a dirty schema, a schema migrated by a newer build
+// (unless DATABASE_ALLOW_NEWER_SCHEMA) and, without DATABASE_AUTO_MIGRATE, pending migrations.
 func InitDB(cfg *config.Config) error {
-	var err error
+	if err := OpenDB(cfg); err != nil {
+		return err
+	}
+
+	migrator, err := NewMigrator(DB)
+	if err != nil {
+		return err
+	}
+	status, err := migrator.Check(cfg.DatabaseAllowNewerSchema, cfg.DatabaseAutoMigrate)
+	if err != nil {
+		return err
+	}
+	if status.Newer {
+		log.Printf("WARNING: database schema version %d is newer than this build (%d), running anyway", status.Current, status.Latest)
+	}
+	for _, version := range status.Modified {
+		log.Printf("WARNING: schema migration %d was changed after it was applied", version)
+	}
+
+	if len(status.Pending) > 0 {
+		applied, err := migrator.Up(cfg.DatabaseAllowNewerSchema)
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied %d schema migration(s), schema is at version %d", applied, status.Latest)
+	}
 
-	// Configure GORM logger
+	if cfg.DatabaseReplicaURLs != "" {
+		if err := openReplicas(cfg.DatabaseReplicaURLs, cfg.DatabaseReplicaMaxLag, newGormConfig(cfg).Logger); err != nil {
+			return err
+		}
+	}
+
+	log.Println("Database initialized successfully")
+	return nil
+}
+
+// newGormConfig returns the GORM settings of the primary and replica connections
+func newGormConfig(cfg *config.Config) *gorm.Config {
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	}
@@ -26,6 +63,14 @@ func InitDB(cfg *config.Config) error {
 	if cfg.Debug {
 		gormConfig.Logger = logger.Default.LogMode(logger.Info)
 	}
+	return gormConfig
+}
+
+// OpenDB connects to the database without touching the schema (used by the migrate command)
+func OpenDB(cfg *config.Config) error {
+	var err error
+
+	gormConfig := newGormConfig(cfg)
 
 	// Initialize database provider based on config
 	switch cfg.DatabaseType {
@@ -47,35 +92,6 @@ func InitDB(cfg *config.Config) error {
 		return fmt.Errorf("unsupported database type: %s (only 'postgres' is supported)", cfg.DatabaseType)
 	}
 
-	// Auto-migrate models
-	err = dbProvider.Migrate(
-		&models.User{},
-		&models.MinecraftServer{},
-		&models.UsageLog{},
-		&models.ConfigChange{},
-		&models.ServerFile{},
-		&models.ServerWebhook{},
-		&models.ServerBackupSchedule{},
-		&models.BillingEvent{},
-		&models.UsageSession{},
-		&models.TrustedDevice{},
-		&models.SecurityEvent{},
-		&models.OAuthAccount{},
-		&models.OAuthState{},
-		&models.SystemEvent{},
-		&models.Plugin{},
-		&models.PluginVersion{},
-		&models.InstalledPlugin{},
-		&models.Migration{},
-		&models.Backup{},
-		&models.BackupRestoreTracking{},
-		&models.Node{},
-	)
-	if err != nil {
-		return err
-	}
-
-	log.Println("Database initialized successfully")
 	return nil
 }
 
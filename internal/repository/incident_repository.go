@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// IncidentRepository handles status page incidents
+type IncidentRepository struct {
+	db *gorm.DB
+}
+
+// NewIncidentRepository creates a new incident repository
+func NewIncidentRepository(db *gorm.DB) *IncidentRepository {
+	return &IncidentRepository{db: db}
+}
+
+// Create creates an incident
+func (r *IncidentRepository) Create(incident *models.Incident) error {
+	return r.db.Create(incident).Error
+}
+
+// Update saves an incident
+func (r *IncidentRepository) Update(incident *models.Incident) error {
+	return r.db.Save(incident).Error
+}
+
+// FindOpenByFingerprint returns the unresolved incident of an alert (nil if there is none)
+func (r *IncidentRepository) FindOpenByFingerprint(fingerprint string) (*models.Incident, error) {
+	var incidents []models.Incident
+	err := r.db.Where("fingerprint = ? AND status <> ?", fingerprint, models.IncidentStatusResolved).
+		Limit(1).
+		Find(&incidents).Error
+	if err != nil || len(incidents) == 0 {
+		return nil, err
+	}
+	return &incidents[0], nil
+}
+
+// FindOpen returns all unresolved incidents, newest first
+func (r *IncidentRepository) FindOpen() ([]models.Incident, error) {
+	var incidents []models.Incident
+	err := r.db.Where("status <> ?", models.IncidentStatusResolved).
+		Order("started_at DESC").
+		Find(&incidents).Error
+	return incidents, err
+}
+
+// FindResolvedSince returns incidents resolved after since, newest first
+func (r *IncidentRepository) FindResolvedSince(since time.Time) ([]models.Incident, error) {
+	var incidents []models.Incident
+	err := r.db.Where("status = ? AND resolved_at >= ?", models.IncidentStatusResolved, since).
+		Order("started_at DESC").
+		Find(&incidents).Error
+	return incidents, err
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobRepository handles database operations for background jobs
+type JobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *gorm.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// Save creates or replaces a job record
+func (r *JobRepository) Save(job *models.Job) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(job).Error
+}
+
+// FindForUser finds a job owned or requested by userID
+func (r *JobRepository) FindForUser(id, userID string) (*models.Job, error) {
+	var job models.Job
+	err := r.db.Where("id = ? AND (owner_id = ? OR requested_by = ?)", id, userID, userID).First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// jobPageColumns are the sortable job columns
+var jobPageColumns = pageColumns[models.Job]{
+	"created_at": func(j *models.Job) interface{} { return j.CreatedAt },
+	"kind":       func(j *models.Job) interface{} { return j.Kind },
+	"status":     func(j *models.Job) interface{} { return j.Status },
+}
+
+// jobFilterColumns maps list filters to job columns
+var jobFilterColumns = map[string]string{
+	"kind":      "kind",
+	"status":    "status",
+	"server_id": "server_id",
+}
+
+// FindForUserPage returns one page of the jobs owned or requested by userID
+func (r *JobRepository) FindForUserPage(userID string, page PageRequest) (*Page[models.Job], error) {
+	query := r.db.Model(&models.Job{}).Where("owner_id = ? OR requested_by = ?", userID, userID)
+	return paginate(query, page, "created_at", jobPageColumns, jobFilterColumns,
+		func(j *models.Job) string { return j.ID })
+}
+
+// FailUnfinished marks jobs that were still queued or running as failed (after an API restart)
+func (r *JobRepository) FailUnfinished(reason string) (int64, error) {
+	now := time.Now()
+	result := r.db.Model(&models.Job{}).
+		Where("finished_at IS NULL").
+		Updates(map[string]interface{}{
+			"status":      "failed",
+			"error":       reason,
+			"cancellable": false,
+			"finished_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteFinishedBefore deletes jobs that finished before cutoff
+func (r *JobRepository) DeleteFinishedBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("finished_at < ?", cutoff).Delete(&models.Job{})
+	return result.RowsAffected, result.Error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/payperplay/hosting/internal/models"
 	"gorm.io/gorm"
@@ -32,38 +33,30 @@ func (r *MigrationRepository) FindByID(id string) (*models.Migration, error) {
 	return &migration, nil
 }
 
-// FindAll finds all migrations with optional filters
-func (r *MigrationRepository) FindAll(filters map[string]interface{}, limit, offset int) ([]models.Migration, error) {
-	var migrations []models.Migration
-	query := r.db.Model(&models.Migration{})
+// migrationPageColumns are the sortable migration columns
+var migrationPageColumns = pageColumns[models.Migration]{
+	"created_at":        func(m *models.Migration) interface{} { return m.CreatedAt },
+	"status":            func(m *models.Migration) interface{} { return m.Status },
+	"savings_eur_month": func(m *models.Migration) interface{} { return m.SavingsEURMonth },
+}
 
-	// Apply filters
-	for key, value := range filters {
-		switch key {
-		case "status":
-			query = query.Where("status = ?", value)
-		case "server_id":
-			query = query.Where("server_id = ?", value)
-		case "reason":
-			query = query.Where("reason = ?", value)
-		case "triggered_by":
-			query = query.Where("triggered_by = ?", value)
-		}
-	}
+// migrationFilterColumns maps list filters to migration columns
+var migrationFilterColumns = map[string]string{
+	"status":       "status",
+	"server_id":    "server_id",
+	"reason":       "reason",
+	"triggered_by": "triggered_by",
+}
 
-	// Apply pagination
-	if limit > 0 {
-		query = query.Limit(limit)
-	}
-	if offset > 0 {
-		query = query.Offset(offset)
+// FindPage returns one page of migrations.
+// The "node" filter matches migrations from or to one of the given nodes.
+func (r *MigrationRepository) FindPage(page PageRequest) (*Page[models.Migration], error) {
+	query := r.db.Model(&models.Migration{})
+	if nodes := page.Filters["node"]; len(nodes) > 0 {
+		query = query.Where("(from_node_id IN ? OR to_node_id IN ?)", nodes, nodes)
 	}
-
-	// Order by created_at DESC
-	query = query.Order("created_at DESC")
-
-	err := query.Find(&migrations).Error
-	return migrations, err
+	return paginate(query, page, "created_at", migrationPageColumns, migrationFilterColumns,
+		func(m *models.Migration) string { return m.ID })
 }
 
 // FindByStatus finds all migrations with a specific status
@@ -112,6 +105,17 @@ func (r *MigrationRepository) FindPendingMigrations() ([]models.Migration, error
 	return migrations, err
 }
 
+// FindUnfinished finds migrations that have not completed, failed or been cancelled yet
+func (r *MigrationRepository) FindUnfinished() ([]models.Migration, error) {
+	var migrations []models.Migration
+	err := r.db.Where("status NOT IN (?)", []models.MigrationStatus{
+		models.MigrationStatusCompleted,
+		models.MigrationStatusFailed,
+		models.MigrationStatusCancelled,
+	}).Order("created_at ASC").Find(&migrations).Error
+	return migrations, err
+}
+
 // Update updates a migration
 func (r *MigrationRepository) Update(migration *models.Migration) error {
 	return r.db.Save(migration).Error
@@ -145,27 +149,6 @@ func (r *MigrationRepository) Delete(id string) error {
 	return r.db.Delete(&models.Migration{}, "id = ?", id).Error
 }
 
-// Count returns the total count of migrations
-func (r *MigrationRepository) Count(filters map[string]interface{}) (int64, error) {
-	var count int64
-	query := r.db.Model(&models.Migration{})
-
-	// Apply filters
-	for key, value := range filters {
-		switch key {
-		case "status":
-			query = query.Where("status = ?", value)
-		case "server_id":
-			query = query.Where("server_id = ?", value)
-		case "reason":
-			query = query.Where("reason = ?", value)
-		}
-	}
-
-	err := query.Count(&count).Error
-	return count, err
-}
-
 // GetStats returns migration statistics
 func (r *MigrationRepository) GetStats() (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
@@ -223,6 +206,16 @@ func (r *MigrationRepository) GetStats() (map[string]interface{}, error) {
 	return stats, nil
 }
 
+// FindFinishedSince finds completed and failed migrations created after since (analytics)
+func (r *MigrationRepository) FindFinishedSince(since time.Time) ([]models.Migration, error) {
+	var migrations []models.Migration
+	err := r.db.Where("status IN (?) AND created_at >= ?",
+		[]models.MigrationStatus{models.MigrationStatusCompleted, models.MigrationStatusFailed}, since).
+		Order("created_at ASC").
+		Find(&migrations).Error
+	return migrations, err
+}
+
 // CleanupOldMigrations deletes migrations older than specified days
 func (r *MigrationRepository) CleanupOldMigrations(daysOld int) (int64, error) {
 	result := r.db.Unscoped().Where(
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_usage_logs_server_started;
+DROP INDEX IF EXISTS idx_usage_logs_server_active;
@@ -0,0 +1,8 @@
+-- GetActiveUsageLog looks up the open usage log of a server on every stop
+CREATE INDEX IF NOT EXISTS idx_usage_logs_server_active
+    ON usage_logs (server_id)
+    WHERE stopped_at IS NULL AND deleted_at IS NULL;
+
+-- GetServerUsageLogs lists the usage of a server newest first
+CREATE INDEX IF NOT EXISTS idx_usage_logs_server_started
+    ON usage_logs (server_id, started_at DESC);
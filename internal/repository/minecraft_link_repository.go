@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// MinecraftLinkRepository stores the links between users and their Minecraft accounts
+type MinecraftLinkRepository struct {
+	db *gorm.DB
+}
+
+// NewMinecraftLinkRepository creates a new Minecraft link repository
+func NewMinecraftLinkRepository(db *gorm.DB) *MinecraftLinkRepository {
+	return &MinecraftLinkRepository{db: db}
+}
+
+// FindByUser returns the link of a user, nil if the user never started linking
+func (r *MinecraftLinkRepository) FindByUser(userID string) (*models.MinecraftLink, error) {
+	var link models.MinecraftLink
+	err := r.db.Where("user_id = ?", userID).First(&link).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindByMinecraftUUID returns the verified link of a Minecraft account, nil if it isn't linked
+func (r *MinecraftLinkRepository) FindByMinecraftUUID(uuid string) (*models.MinecraftLink, error) {
+	var link models.MinecraftLink
+	err := r.db.Where("minecraft_uuid = ? AND verified_at IS NOT NULL", uuid).First(&link).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+// Save creates or updates a link
+func (r *MinecraftLinkRepository) Save(link *models.MinecraftLink) error {
+	return r.db.Save(link).Error
+}
+
+// SaveVerified stores a newly verified link and unlinks the account from any other user
+// (confirming the code proved the new user controls it)
+func (r *MinecraftLinkRepository) SaveVerified(link *models.MinecraftLink) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.MinecraftLink{}).
+			Where("minecraft_uuid = ? AND user_id <> ?", link.MinecraftUUID, link.UserID).
+			Updates(map[string]interface{}{"minecraft_uuid": "", "minecraft_name": "", "verified_at": nil, "auto_op": false}).Error; err != nil {
+			return err
+		}
+		return tx.Save(link).Error
+	})
+}
+
+// Delete removes the link of a user
+func (r *MinecraftLinkRepository) Delete(userID string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.MinecraftLink{}).Error
+}
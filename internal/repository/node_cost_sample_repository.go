@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// NodeCostSampleRepository handles database operations for the node cost timelines
+type NodeCostSampleRepository struct {
+	db *gorm.DB
+}
+
+// NewNodeCostSampleRepository creates a new node cost sample repository
+func NewNodeCostSampleRepository(db *gorm.DB) *NodeCostSampleRepository {
+	return &NodeCostSampleRepository{db: db}
+}
+
+// CreateBatch stores the samples of one reconciliation run
+func (r *NodeCostSampleRepository) CreateBatch(samples []models.NodeCostSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	return r.db.Create(&samples).Error
+}
+
+// FindByNode returns the samples of a node taken in [from, to), oldest first.
+// Runs on a read replica if one is configured.
+func (r *NodeCostSampleRepository) FindByNode(nodeID string, from, to time.Time) ([]models.NodeCostSample, error) {
+	var samples []models.NodeCostSample
+	err := ReadDB(r.db).
+		Where("node_id = ? AND sampled_at >= ? AND sampled_at < ?", nodeID, from, to).
+		Order("sampled_at ASC").
+		Find(&samples).Error
+	return samples, err
+}
+
+// DeleteBefore deletes samples taken before cutoff
+func (r *NodeCostSampleRepository) DeleteBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("sampled_at < ?", cutoff).Delete(&models.NodeCostSample{})
+	return result.RowsAffected, result.Error
+}
@@ -110,3 +110,25 @@ func (r *NodeRepository) Exists(id string) (bool, error) {
 	err := r.db.Model(&models.Node{}).Where("id = ?", id).Count(&count).Error
 	return count > 0, err
 }
+
+// UpdateDedication updates the customer reservation of a node together with its scheduling labels and taints
+func (r *NodeRepository) UpdateDedication(id, ownerID string, priceEUR float64, labels, taints string) error {
+	return r.db.Model(&models.Node{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"dedicated_owner_id":  ownerID,
+			"dedicated_price_eur": priceEUR,
+			"labels":              labels,
+			"taints":              taints,
+		}).Error
+}
+
+// UpdatePlacement updates the scheduling labels and taints of a node
+func (r *NodeRepository) UpdatePlacement(id string, labels, taints string) error {
+	return r.db.Model(&models.Node{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"labels": labels,
+			"taints": taints,
+		}).Error
+}
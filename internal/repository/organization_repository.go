@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// OrganizationRepository handles database operations for organizations, members and invitations
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository creates a new organization repository
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// CreateWithOwner creates an organization and its first owner membership atomically
+func (r *OrganizationRepository) CreateWithOwner(org *models.Organization, ownerID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         ownerID,
+			Role:           models.OrgRoleOwner,
+		}).Error
+	})
+}
+
+// FindByID finds an organization by ID
+func (r *OrganizationRepository) FindByID(id string) (*models.Organization, error) {
+	var org models.Organization
+	err := r.db.First(&org, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// FindByUser returns the organizations the user is a member of
+func (r *OrganizationRepository) FindByUser(userID string) ([]models.Organization, error) {
+	var orgs []models.Organization
+	err := r.db.
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organizations.name ASC").
+		Find(&orgs).Error
+	return orgs, err
+}
+
+// Update updates an organization
+func (r *OrganizationRepository) Update(org *models.Organization) error {
+	return r.db.Save(org).Error
+}
+
+// Delete deletes an organization with its members, invitations and SSO connection and unshares its servers
+func (r *OrganizationRepository) Delete(id string) error {
+	var sharedServerIDs []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.MinecraftServer{}).Where("organization_id = ?", id).
+			Pluck("id", &sharedServerIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.MinecraftServer{}).Where("organization_id = ?", id).
+			Update("organization_id", "").Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationInvitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&models.SSOIdentity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&models.SSODomain{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&models.SSOPendingLink{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&models.SSOConnection{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Organization{}, "id = ?", id).Error
+	})
+	if err != nil {
+		return err
+	}
+	invalidateServers(r.db, sharedServerIDs)
+	return nil
+}
+
+// FindMember finds the membership of a user in an organization
+func (r *OrganizationRepository) FindMember(orgID, userID string) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := r.db.First(&member, "organization_id = ? AND user_id = ?", orgID, userID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// FindMembers returns the members of an organization with their email and username
+func (r *OrganizationRepository) FindMembers(orgID string) ([]models.OrganizationMember, error) {
+	var members []models.OrganizationMember
+	err := r.db.
+		Table("organization_members").
+		Select("organization_members.*, users.email, users.username").
+		Joins("LEFT JOIN users ON users.id = organization_members.user_id").
+		Where("organization_members.organization_id = ?", orgID).
+		Order("organization_members.created_at ASC").
+		Scan(&members).Error
+	return members, err
+}
+
+// CountOwners returns the number of owners of an organization
+func (r *OrganizationRepository) CountOwners(orgID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", orgID, models.OrgRoleOwner).
+		Count(&count).Error
+	return count, err
+}
+
+// AddMember adds a membership
+func (r *OrganizationRepository) AddMember(member *models.OrganizationMember) error {
+	return r.db.Create(member).Error
+}
+
+// UpdateMember updates a membership
+func (r *OrganizationRepository) UpdateMember(member *models.OrganizationMember) error {
+	return r.db.Save(member).Error
+}
+
+// RemoveMember removes a membership
+func (r *OrganizationRepository) RemoveMember(orgID, userID string) error {
+	return r.db.Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Delete(&models.OrganizationMember{}).Error
+}
+
+// CreateInvitation creates an invitation
+func (r *OrganizationRepository) CreateInvitation(invitation *models.OrganizationInvitation) error {
+	return r.db.Create(invitation).Error
+}
+
+// FindInvitationByToken finds an invitation by its token
+func (r *OrganizationRepository) FindInvitationByToken(token string) (*models.OrganizationInvitation, error) {
+	var invitation models.OrganizationInvitation
+	err := r.db.First(&invitation, "token = ?", token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindPendingInvitations returns the open (not accepted, not expired) invitations of an organization
+func (r *OrganizationRepository) FindPendingInvitations(orgID string) ([]models.OrganizationInvitation, error) {
+	var invitations []models.OrganizationInvitation
+	err := r.db.
+		Where("organization_id = ? AND accepted_at IS NULL AND expires_at > ?", orgID, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// DeleteInvitation revokes an invitation of an organization
+func (r *OrganizationRepository) DeleteInvitation(orgID string, invitationID uint) error {
+	return r.db.Where("organization_id = ? AND id = ?", orgID, invitationID).
+		Delete(&models.OrganizationInvitation{}).Error
+}
+
+// AcceptInvitation marks the invitation accepted and creates the membership atomically
+func (r *OrganizationRepository) AcceptInvitation(invitation *models.OrganizationInvitation, userID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.OrganizationInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrOrgInvalidInvitation // Accepted concurrently
+		}
+		invitation.AcceptedAt = &now
+
+		return tx.Create(&models.OrganizationMember{
+			OrganizationID: invitation.OrganizationID,
+			UserID:         userID,
+			Role:           invitation.Role,
+		}).Error
+	})
+}
+
+// FindServers returns the servers shared with an organization
+func (r *OrganizationRepository) FindServers(orgID string) ([]models.MinecraftServer, error) {
+	var servers []models.MinecraftServer
+	err := r.db.Where("organization_id = ?", orgID).Find(&servers).Error
+	return servers, err
+}
+
+// SetServerOrganization shares a server with an organization (empty orgID = unshare)
+func (r *OrganizationRepository) SetServerOrganization(serverID, orgID string) error {
+	if err := r.db.Model(&models.MinecraftServer{}).Where("id = ?", serverID).
+		Update("organization_id", orgID).Error; err != nil {
+		return err
+	}
+	invalidateServers(r.db, []string{serverID})
+	return nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// OutboxRepository handles the transactional event outbox
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// FindDue returns unprocessed events whose next attempt is due, oldest first
+func (r *OutboxRepository) FindDue(limit int) ([]models.OutboxEvent, error) {
+	var outboxEvents []models.OutboxEvent
+	err := r.db.Where("processed_at IS NULL AND next_attempt_at <= ?", time.Now()).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&outboxEvents).Error
+	return outboxEvents, err
+}
+
+// MarkProcessed records that an event was handled
+func (r *OutboxRepository) MarkProcessed(id string) error {
+	return r.db.Model(&models.OutboxEvent{}).Where("id = ?", id).
+		Update("processed_at", time.Now()).Error
+}
+
+// MarkFailed records a failed attempt and when to retry
+func (r *OutboxRepository) MarkFailed(id, lastError string, nextAttemptAt time.Time) error {
+	return r.db.Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+	}).Error
+}
+
+// CountPending returns the number of unprocessed events
+func (r *OutboxRepository) CountPending() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.OutboxEvent{}).Where("processed_at IS NULL").Count(&count).Error
+	return count, err
+}
+
+// DeleteProcessedBefore removes events processed before cutoff
+func (r *OutboxRepository) DeleteProcessedBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("processed_at IS NOT NULL AND processed_at < ?", cutoff).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	ErrInvalidSort   = errors.New("invalid sort field")
+)
+
+// MaxPageLimit caps the page size of list queries
+const MaxPageLimit = 200
+
+// PageRequest describes one page of a list query.
+// Pages are keyset-based: Cursor is the opaque NextCursor of the previous page, so rows
+// inserted or deleted between requests do not shift or repeat entries.
+type PageRequest struct {
+	Cursor  string
+	Limit   int                 // 0 = no limit (all remaining rows)
+	Offset  int                 // Only used without Cursor (legacy offset paging)
+	Sort    string              // Column, must be one of the repository's sortable columns (empty = default)
+	Desc    bool                // Sort descending
+	Filters map[string][]string // Column -> accepted values (IN), columns are validated by the repository
+}
+
+// Page is one page of a list query
+type Page[T any] struct {
+	Items      []T
+	Total      int64  // Rows matching the filters (independent of the cursor)
+	NextCursor string // Empty on the last page
+}
+
+// pageCursor is the keyset position after the last row of a page
+type pageCursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// pageColumns are the sortable columns of a table with an accessor for their cursor value
+type pageColumns[T any] map[string]func(*T) interface{}
+
+// paginate applies filters, sorting and the cursor to query and loads one page.
+// The query must already have its Model set and its scope (owner, server, ...) applied.
+func paginate[T any](query *gorm.DB, page PageRequest, defaultSort string, columns pageColumns[T], filterColumns map[string]string, idOf func(*T) string) (*Page[T], error) {
+	sort := page.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+	value, ok := columns[sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSort, sort)
+	}
+
+	for key, values := range page.Filters {
+		if len(values) == 0 {
+			continue
+		}
+		column, ok := filterColumns[key]
+		if !ok {
+			continue
+		}
+		query = query.Where(column+" IN ?", values)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	direction, compare := "ASC", ">"
+	if page.Desc {
+		direction, compare = "DESC", "<"
+	}
+
+	if page.Cursor != "" {
+		cursor, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		// A cursor only continues the ordering it was issued for
+		if cursor.Sort != sort || cursor.Desc != page.Desc {
+			return nil, ErrInvalidCursor
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sort, compare), cursor.Value, cursor.ID)
+	} else if page.Offset > 0 {
+		query = query.Offset(page.Offset)
+	}
+
+	query = query.Order(fmt.Sprintf("%s %s, id %s", sort, direction, direction))
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit + 1) // One extra row tells whether another page exists
+	}
+
+	var items []T
+	if err := query.Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	result := &Page[T]{Items: items, Total: total}
+	if page.Limit > 0 && len(items) > page.Limit {
+		result.Items = items[:page.Limit]
+		last := &result.Items[page.Limit-1]
+		result.NextCursor = encodeCursor(pageCursor{
+			Sort:  sort,
+			Desc:  page.Desc,
+			Value: cursorValue(value(last)),
+			ID:    idOf(last),
+		})
+	}
+	return result, nil
+}
+
+// cursorValue formats a sort value so the database can compare it against the column
+func cursorValue(v interface{}) string {
+	switch value := v.(type) {
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if value == nil {
+			return time.Time{}.Format(time.RFC3339Nano)
+		}
+		return value.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(encoded string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// fakeServerRow is a minecraft_servers row of the fake table
+type fakeServerRow struct {
+	id        string
+	name      string
+	createdAt time.Time
+}
+
+// fakeServerTable answers the list queries of paginate against an in-memory table,
+// applying the keyset condition, ORDER BY and LIMIT the way postgres would
+type fakeServerTable struct {
+	rows []fakeServerRow
+}
+
+var (
+	keysetPattern = regexp.MustCompile(`\((\w+), id\) ([<>]) \(\$(\d+), ?\$(\d+)\)`)
+	orderPattern  = regexp.MustCompile(`ORDER BY (\w+) (ASC|DESC)`)
+	limitPattern  = regexp.MustCompile(`LIMIT \$(\d+)`)
+)
+
+func (f *fakeServerTable) query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	arg := func(n string) driver.Value {
+		for _, a := range args {
+			if fmt.Sprint(a.Ordinal) == n {
+				return a.Value
+			}
+		}
+		return nil
+	}
+	if strings.Contains(query, "count(*)") {
+		return &fakeRows{columns: []string{"count"}, rows: [][]driver.Value{{int64(len(f.rows))}}}, nil
+	}
+
+	column := "created_at"
+	desc := false
+	if m := orderPattern.FindStringSubmatch(query); m != nil {
+		column, desc = m[1], m[2] == "DESC"
+	}
+	key := func(row fakeServerRow) string {
+		if column == "name" {
+			return row.name
+		}
+		return row.createdAt.UTC().Format(time.RFC3339Nano)
+	}
+	less := func(a, b fakeServerRow) bool {
+		if key(a) != key(b) {
+			return key(a) < key(b)
+		}
+		return a.id < b.id
+	}
+
+	rows := append([]fakeServerRow{}, f.rows...)
+	if m := keysetPattern.FindStringSubmatch(query); m != nil {
+		if m[1] != column {
+			return nil, fmt.Errorf("keyset on %s but ordered by %s", m[1], column)
+		}
+		cursor := fakeServerRow{id: arg(m[4]).(string)}
+		value := arg(m[3]).(string)
+		if column == "name" {
+			cursor.name = value
+		} else if cursor.createdAt, _ = time.Parse(time.RFC3339Nano, value); cursor.createdAt.IsZero() {
+			return nil, fmt.Errorf("unparseable cursor value %q", value)
+		}
+		var after []fakeServerRow
+		for _, row := range rows {
+			if (m[2] == ">" && less(cursor, row)) || (m[2] == "<" && less(row, cursor)) {
+				after = append(after, row)
+			}
+		}
+		rows = after
+	} else if strings.Contains(query, ", id) ") {
+		return nil, fmt.Errorf("unrecognized keyset condition in %q", query)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if desc {
+			return less(rows[j], rows[i])
+		}
+		return less(rows[i], rows[j])
+	})
+	if m := limitPattern.FindStringSubmatch(query); m != nil {
+		if limit := int(arg(m[1]).(int64)); len(rows) > limit {
+			rows = rows[:limit]
+		}
+	}
+
+	result := &fakeRows{columns: []string{"id", "name", "created_at"}}
+	for _, row := range rows {
+		result.rows = append(result.rows, []driver.Value{row.id, row.name, row.createdAt})
+	}
+	return result, nil
+}
+
+func (f *fakeServerTable) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{table: f}, nil
+}
+func (f *fakeServerTable) Driver() driver.Driver { return nil }
+
+type fakeConn struct {
+	table *fakeServerTable
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.table.query(query, args)
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func newFakeServerRepository(t *testing.T, rows []fakeServerRow) *ServerRepository {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(&fakeServerTable{rows: rows})}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("failed to open fake DB: %v", err)
+	}
+	return NewServerRepository(db)
+}
+
+func TestPaginateCursorRoundTrip(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var rows []fakeServerRow
+	for i := 0; i < 7; i++ {
+		rows = append(rows, fakeServerRow{
+			id:        fmt.Sprintf("srv-%d", i),
+			name:      []string{"beta", "alpha", "beta", "gamma", "alpha", "delta", "beta"}[i],
+			createdAt: base.Add(time.Duration(i%4) * time.Hour).Add(time.Duration(i) * time.Nanosecond * 1000),
+		})
+	}
+
+	tests := []struct {
+		name  string
+		page  PageRequest
+		pages int
+	}{
+		{name: "created_at descending", page: PageRequest{Limit: 2, Desc: true}, pages: 4},
+		{name: "created_at ascending", page: PageRequest{Limit: 3}, pages: 3},
+		{name: "name with ties", page: PageRequest{Limit: 2, Sort: "name"}, pages: 4},
+		{name: "name descending", page: PageRequest{Limit: 3, Sort: "name", Desc: true}, pages: 3},
+		{name: "single page", page: PageRequest{Limit: 10}, pages: 1},
+		{name: "exact multiple", page: PageRequest{Limit: 7}, pages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeServerRepository(t, rows)
+
+			seen := map[string]bool{}
+			var order []string
+			page := tt.page
+			pages := 0
+			for {
+				result, err := repo.FindAllPage(page)
+				if err != nil {
+					t.Fatalf("FindAllPage() error = %v", err)
+				}
+				pages++
+				if result.Total != int64(len(rows)) {
+					t.Errorf("Total = %d, want %d", result.Total, len(rows))
+				}
+				for _, server := range result.Items {
+					if seen[server.ID] {
+						t.Fatalf("server %s returned twice", server.ID)
+					}
+					seen[server.ID] = true
+					order = append(order, server.ID)
+				}
+				if result.NextCursor == "" {
+					break
+				}
+				if pages > len(rows) {
+					t.Fatal("paging does not terminate")
+				}
+				page.Cursor = result.NextCursor
+			}
+
+			if pages != tt.pages {
+				t.Errorf("got %d pages, want %d", pages, tt.pages)
+			}
+			if len(seen) != len(rows) {
+				t.Errorf("paged through %d servers, want %d (%v)", len(seen), len(rows), order)
+			}
+
+			// Pages concatenate to the unpaged order
+			all, err := repo.FindAllPage(PageRequest{Sort: tt.page.Sort, Desc: tt.page.Desc})
+			if err != nil {
+				t.Fatalf("FindAllPage() error = %v", err)
+			}
+			for i, server := range all.Items {
+				if order[i] != server.ID {
+					t.Fatalf("paged order = %v, differs from the unpaged order at %d (%s)", order, i, server.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginateCursorMismatch(t *testing.T) {
+	repo := newFakeServerRepository(t, []fakeServerRow{
+		{id: "a", name: "a", createdAt: time.Now()},
+		{id: "b", name: "b", createdAt: time.Now()},
+	})
+	first, err := repo.FindAllPage(PageRequest{Limit: 1, Sort: "name"})
+	if err != nil || first.NextCursor == "" {
+		t.Fatalf("FindAllPage() = %+v, %v; want a next cursor", first, err)
+	}
+
+	tests := []struct {
+		name string
+		page PageRequest
+		want error
+	}{
+		{name: "other sort column", page: PageRequest{Limit: 1, Sort: "created_at", Cursor: first.NextCursor}, want: ErrInvalidCursor},
+		{name: "other direction", page: PageRequest{Limit: 1, Sort: "name", Desc: true, Cursor: first.NextCursor}, want: ErrInvalidCursor},
+		{name: "garbage", page: PageRequest{Limit: 1, Sort: "name", Cursor: "not-a-cursor!"}, want: ErrInvalidCursor},
+		{name: "unknown sort", page: PageRequest{Limit: 1, Sort: "password"}, want: ErrInvalidSort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := repo.FindAllPage(tt.page); !errors.Is(err, tt.want) {
+				t.Errorf("FindAllPage() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorEncoding(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 30, 0, 123456789, time.FixedZone("CET", 3600))
+	tests := []struct {
+		name   string
+		value  interface{}
+		want   string
+		cursor pageCursor
+	}{
+		{name: "time is UTC with nanoseconds", value: at, want: "2026-03-01T11:30:00.123456789Z"},
+		{name: "time pointer", value: &at, want: "2026-03-01T11:30:00.123456789Z"},
+		{name: "nil time pointer", value: (*time.Time)(nil), want: "0001-01-01T00:00:00Z"},
+		{name: "string", value: "lobby, \"main\"", want: "lobby, \"main\""},
+		{name: "int", value: 2048, want: "2048"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := cursorValue(tt.value)
+			if value != tt.want {
+				t.Errorf("cursorValue() = %q, want %q", value, tt.want)
+			}
+
+			cursor := pageCursor{Sort: "created_at", Desc: true, Value: value, ID: "srv-1"}
+			decoded, err := decodeCursor(encodeCursor(cursor))
+			if err != nil {
+				t.Fatalf("decodeCursor() error = %v", err)
+			}
+			if *decoded != cursor {
+				t.Errorf("decodeCursor() = %+v, want %+v", *decoded, cursor)
+			}
+		})
+	}
+
+	if _, err := decodeCursor(encodeCursor(pageCursor{Sort: "name"})); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("decodeCursor() of a cursor without id: error = %v, want ErrInvalidCursor", err)
+	}
+}
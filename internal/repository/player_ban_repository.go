@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// PlayerBanRepository stores the proxy-level player bans
+type PlayerBanRepository struct {
+	db *gorm.DB
+}
+
+// NewPlayerBanRepository creates a new player ban repository
+func NewPlayerBanRepository(db *gorm.DB) *PlayerBanRepository {
+	return &PlayerBanRepository{db: db}
+}
+
+// Create stores a new ban
+func (r *PlayerBanRepository) Create(ban *models.PlayerBan) error {
+	return r.db.Create(ban).Error
+}
+
+// FindByID returns a ban, nil if it doesn't exist
+func (r *PlayerBanRepository) FindByID(id uint) (*models.PlayerBan, error) {
+	var ban models.PlayerBan
+	err := r.db.First(&ban, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ban, nil
+}
+
+// FindByOrganization returns the bans of an organization ("" = global bans), newest first,
+// including revoked and expired ones
+func (r *PlayerBanRepository) FindByOrganization(orgID string, limit int) ([]models.PlayerBan, error) {
+	var bans []models.PlayerBan
+	err := r.db.Where("organization_id = ?", orgID).Order("created_at DESC").Limit(limit).Find(&bans).Error
+	return bans, err
+}
+
+// FindActive returns all bans in force at t
+func (r *PlayerBanRepository) FindActive(t time.Time) ([]models.PlayerBan, error) {
+	var bans []models.PlayerBan
+	err := r.db.Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", t).
+		Order("id").Find(&bans).Error
+	return bans, err
+}
+
+// Revoke lifts a ban that is still in force at t, returns false if it wasn't
+func (r *PlayerBanRepository) Revoke(id uint, revokedBy string, t time.Time) (bool, error) {
+	result := r.db.Model(&models.PlayerBan{}).
+		Where("id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", id, t).
+		Updates(map[string]interface{}{"revoked_at": t, "revoked_by": revokedBy})
+	return result.RowsAffected > 0, result.Error
+}
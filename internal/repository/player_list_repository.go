@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// PlayerListRepository stores player list changes waiting for the next server start
+type PlayerListRepository struct {
+	db *gorm.DB
+}
+
+func NewPlayerListRepository(db *gorm.DB) *PlayerListRepository {
+	return &PlayerListRepository{db: db}
+}
+
+// CreateChange queues a change
+func (r *PlayerListRepository) CreateChange(change *models.PlayerListChange) error {
+	return r.db.Create(change).Error
+}
+
+// FindPending returns the changes of a server not applied yet, oldest first (listType "" = all lists)
+func (r *PlayerListRepository) FindPending(serverID, listType string) ([]models.PlayerListChange, error) {
+	var changes []models.PlayerListChange
+	query := r.db.Where("server_id = ? AND applied_at IS NULL", serverID)
+	if listType != "" {
+		query = query.Where("list_type = ?", listType)
+	}
+	err := query.Order("id ASC").Find(&changes).Error
+	return changes, err
+}
+
+// DeletePending drops the pending changes of a player on a list (a newer change supersedes them)
+func (r *PlayerListRepository) DeletePending(serverID, listType, name string) error {
+	return r.db.Where("server_id = ? AND list_type = ? AND LOWER(name) = LOWER(?) AND applied_at IS NULL", serverID, listType, name).
+		Delete(&models.PlayerListChange{}).Error
+}
+
+// MarkApplied records that a change was applied
+func (r *PlayerListRepository) MarkApplied(id uint, appliedAt time.Time) error {
+	return r.db.Model(&models.PlayerListChange{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"applied_at": appliedAt, "error": ""}).Error
+}
+
+// MarkFailed records the error of a failed attempt; the change stays pending
+func (r *PlayerListRepository) MarkFailed(id uint, message string) error {
+	return r.db.Model(&models.PlayerListChange{}).Where("id = ?", id).Update("error", message).Error
+}
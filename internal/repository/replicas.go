@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const (
+	// replicaCheckInterval is how often replica health and lag are checked
+	replicaCheckInterval = 10 * time.Second
+	// replicaDefaultMaxLag applies if DATABASE_REPLICA_MAX_LAG is not a valid duration
+	replicaDefaultMaxLag = 30 * time.Second
+)
+
+// replicaLagQuery returns how far a standby is behind in seconds (0 if it replayed all it received,
+// so an idle primary doesn't make the replica look stale)
+const replicaLagQuery = `SELECT CASE
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+// replicaRouter routes read-only queries when DATABASE_REPLICA_URLS is set (nil = primary only)
+var replicaRouter *ReplicaRouter
+
+// replica is one read replica connection
+type replica struct {
+	name    string // URL with the password masked, for logs
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// ReplicaRouter sends read-only queries (lists, analytics) round-robin to healthy read replicas.
+// Replicas that don't answer or are more than maxLag behind are skipped until they catch up;
+// without a healthy replica the queries go to the primary.
+type ReplicaRouter struct {
+	primary  *gorm.DB
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewReplicaRouter creates a router for primary and the replica connections
+func NewReplicaRouter(primary *gorm.DB, replicaDBs map[string]*gorm.DB, maxLag time.Duration) *ReplicaRouter {
+	r := &ReplicaRouter{primary: primary, maxLag: maxLag}
+	for name, db := range replicaDBs {
+		rep := &replica{name: name, db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+	return r
+}
+
+// ReadDB returns the connection for a read-only query on db: a healthy replica if db is the
+// primary connection (also with a context attached), db itself inside a transaction or without
+// replicas. Call it at the start of the query chain, conditions added to db before are dropped.
+// Use it only where slightly stale data is fine; reads that decide a write stay on the primary.
+func ReadDB(db *gorm.DB) *gorm.DB {
+	if replicaRouter == nil {
+		return db
+	}
+	return replicaRouter.Read(db)
+}
+
+// Read routes one read-only query, see ReadDB
+func (r *ReplicaRouter) Read(db *gorm.DB) *gorm.DB {
+	if db == nil || db.Statement.ConnPool != r.primary.Statement.ConnPool {
+		return db
+	}
+	rep := r.pick()
+	if rep == nil {
+		return db
+	}
+	if ctx := db.Statement.Context; ctx != nil && ctx != rep.db.Statement.Context {
+		return rep.db.WithContext(ctx)
+	}
+	return rep.db
+}
+
+// pick returns the next healthy replica, nil if there is none
+func (r *ReplicaRouter) pick() *replica {
+	count := uint64(len(r.replicas))
+	if count == 0 {
+		return nil
+	}
+	start := r.next.Add(1)
+	for i := uint64(0); i < count; i++ {
+		if rep := r.replicas[(start+i)%count]; rep.healthy.Load() {
+			return rep
+		}
+	}
+	return nil
+}
+
+// Start checks the replicas periodically
+func (r *ReplicaRouter) Start() {
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	r.done = make(chan struct{})
+	r.checkAll()
+
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(replicaCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.checkAll()
+			case <-r.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the health checks and closes the replica connections
+func (r *ReplicaRouter) Stop() {
+	r.once.Do(func() {
+		if r.cancel != nil {
+			r.cancel()
+			<-r.done
+		}
+		for _, rep := range r.replicas {
+			if sqlDB, err := rep.db.DB(); err == nil {
+				sqlDB.Close()
+			}
+		}
+	})
+}
+
+// checkAll updates the health of every replica and logs changes
+func (r *ReplicaRouter) checkAll() {
+	for _, rep := range r.replicas {
+		err := r.check(rep)
+		healthy := err == nil
+		if rep.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Printf("Read replica %s is healthy again, routing reads to it", rep.name)
+			} else {
+				log.Printf("WARNING: read replica %s skipped: %v", rep.name, err)
+			}
+		}
+	}
+}
+
+// check pings a replica and measures its replication lag
+func (r *ReplicaRouter) check(rep *replica) error {
+	ctx, cancel := context.WithTimeout(context.Background(), replicaCheckInterval/2)
+	defer cancel()
+
+	var lagSeconds float64
+	if err := rep.db.WithContext(ctx).Raw(replicaLagQuery).Scan(&lagSeconds).Error; err != nil {
+		return err
+	}
+	if lag := time.Duration(lagSeconds * float64(time.Second)); r.maxLag > 0 && lag > r.maxLag {
+		return fmt.Errorf("replication lag %s exceeds %s", lag.Round(time.Second), r.maxLag)
+	}
+	return nil
+}
+
+// openReplicas connects the read replicas of DATABASE_REPLICA_URLS and starts routing reads to them
+func openReplicas(urls string, maxLagSetting string, logger gormlogger.Interface) error {
+	maxLag, err := time.ParseDuration(maxLagSetting)
+	if err != nil || maxLag < 0 {
+		maxLag = replicaDefaultMaxLag
+	}
+
+	replicaDBs := make(map[string]*gorm.DB)
+	for _, url := range strings.Split(urls, ",") {
+		if url = strings.TrimSpace(url); url == "" {
+			continue
+		}
+		// No ping on open: an unreachable replica is skipped by the health check instead of blocking startup
+		db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger, DisableAutomaticPing: true})
+		if err != nil {
+			return fmt.Errorf("failed to open read replica %s: %w", maskPassword(url), err)
+		}
+		replicaDBs[maskPassword(url)] = db
+	}
+	if len(replicaDBs) == 0 {
+		return nil
+	}
+
+	replicaRouter = NewReplicaRouter(DB, replicaDBs, maxLag)
+	replicaRouter.Start()
+	log.Printf("Routing read-only queries to %d read replica(s), max lag %s", len(replicaDBs), maxLag)
+	return nil
+}
+
+// CloseReplicas stops routing reads to the replicas and closes their connections
+func CloseReplicas() {
+	if replicaRouter != nil {
+		replicaRouter.Stop()
+	}
+}
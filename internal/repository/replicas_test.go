@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 user=test dbname=test sslmode=disable"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry-run DB: %v", err)
+	}
+	return db
+}
+
+func TestReplicaRouterRoutesPrimaryReads(t *testing.T) {
+	primary := newDryRunDB(t)
+	replicaA, replicaB := newDryRunDB(t), newDryRunDB(t)
+	router := NewReplicaRouter(primary, map[string]*gorm.DB{"a": replicaA, "b": replicaB}, 0)
+
+	// Round robin over the healthy replicas
+	seen := map[*gorm.DB]int{}
+	for i := 0; i < 4; i++ {
+		seen[router.Read(primary)]++
+	}
+	if seen[replicaA] != 2 || seen[replicaB] != 2 {
+		t.Errorf("reads per replica = a:%d b:%d, want 2 each", seen[replicaA], seen[replicaB])
+	}
+
+	// A context on the primary is kept on the replica
+	type requestKey struct{}
+	ctx := context.WithValue(context.Background(), requestKey{}, "request")
+	if got := router.Read(primary.WithContext(ctx)); got.Statement.Context != ctx {
+		t.Error("Read() dropped the context of the query")
+	} else if got.Statement.ConnPool == primary.Statement.ConnPool {
+		t.Error("Read() of the primary with a context stayed on the primary")
+	}
+
+	// Other connections (e.g. transactions) stay where they are
+	other := newDryRunDB(t)
+	if got := router.Read(other); got != other {
+		t.Error("Read() rerouted a connection that isn't the primary")
+	}
+
+	// Unhealthy replicas are skipped, without any the primary answers
+	for _, rep := range router.replicas {
+		if rep.name == "a" {
+			rep.healthy.Store(false)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if got := router.Read(primary); got != replicaB {
+			t.Fatalf("Read() with replica a down = %p, want replica b", got)
+		}
+	}
+	for _, rep := range router.replicas {
+		rep.healthy.Store(false)
+	}
+	if got := router.Read(primary); got != primary {
+		t.Error("Read() without a healthy replica didn't fall back to the primary")
+	}
+}
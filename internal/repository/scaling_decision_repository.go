@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// ScalingDecisionRepository handles database operations for the scaling decision history
+type ScalingDecisionRepository struct {
+	db *gorm.DB
+}
+
+// NewScalingDecisionRepository creates a new scaling decision repository
+func NewScalingDecisionRepository(db *gorm.DB) *ScalingDecisionRepository {
+	return &ScalingDecisionRepository{db: db}
+}
+
+// Create stores a scaling decision
+func (r *ScalingDecisionRepository) Create(decision *models.ScalingDecision) error {
+	return r.db.Create(decision).Error
+}
+
+// FindByID finds a scaling decision by ID
+func (r *ScalingDecisionRepository) FindByID(id string) (*models.ScalingDecision, error) {
+	var decision models.ScalingDecision
+	if err := ReadDB(r.db).Where("id = ?", id).First(&decision).Error; err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+// scalingDecisionPageColumns are the sortable scaling decision columns
+var scalingDecisionPageColumns = pageColumns[models.ScalingDecision]{
+	"evaluated_at":     func(d *models.ScalingDecision) interface{} { return d.EvaluatedAt },
+	"capacity_percent": func(d *models.ScalingDecision) interface{} { return d.CapacityPercent },
+}
+
+// scalingDecisionFilterColumns maps list filters to scaling decision columns
+var scalingDecisionFilterColumns = map[string]string{
+	"action":      "action",
+	"outcome":     "outcome",
+	"policy":      "policy",
+	"server_type": "server_type",
+}
+
+// FindPage returns one page of the decisions evaluated in [from, to) (zero times leave the range open).
+// Runs on a read replica if one is configured.
+func (r *ScalingDecisionRepository) FindPage(from, to time.Time, page PageRequest) (*Page[models.ScalingDecision], error) {
+	query := ReadDB(r.db).Model(&models.ScalingDecision{})
+	if !from.IsZero() {
+		query = query.Where("evaluated_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("evaluated_at < ?", to)
+	}
+	return paginate(query, page, "evaluated_at", scalingDecisionPageColumns, scalingDecisionFilterColumns,
+		func(d *models.ScalingDecision) string { return d.ID })
+}
+
+// DeleteBefore deletes decisions evaluated before cutoff
+func (r *ScalingDecisionRepository) DeleteBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("evaluated_at < ?", cutoff).Delete(&models.ScalingDecision{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// ScalingPolicyConfigRepository handles database operations for the scaling policy configs
+type ScalingPolicyConfigRepository struct {
+	db *gorm.DB
+}
+
+// NewScalingPolicyConfigRepository creates a new scaling policy config repository
+func NewScalingPolicyConfigRepository(db *gorm.DB) *ScalingPolicyConfigRepository {
+	return &ScalingPolicyConfigRepository{db: db}
+}
+
+// FindAll returns all scaling policy configs
+func (r *ScalingPolicyConfigRepository) FindAll() ([]models.ScalingPolicyConfig, error) {
+	var configs []models.ScalingPolicyConfig
+	err := r.db.Order("policy").Find(&configs).Error
+	return configs, err
+}
+
+// FindByPolicy returns the config of a policy, nil if it has none
+func (r *ScalingPolicyConfigRepository) FindByPolicy(policy string) (*models.ScalingPolicyConfig, error) {
+	var config models.ScalingPolicyConfig
+	err := r.db.Where("policy = ?", policy).First(&config).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Save creates or replaces the config of a policy
+func (r *ScalingPolicyConfigRepository) Save(config *models.ScalingPolicyConfig) error {
+	return r.db.Save(config).Error
+}
+
+// Delete removes the config of a policy
+func (r *ScalingPolicyConfigRepository) Delete(policy string) error {
+	return r.db.Where("policy = ?", policy).Delete(&models.ScalingPolicyConfig{}).Error
+}
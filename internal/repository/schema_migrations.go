@@ -0,0 +1,404 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSchemaNewer is returned if the database was migrated by a newer build than this one
+	ErrSchemaNewer = errors.New("database schema is newer than this build")
+	// ErrSchemaDirty is returned if a migration failed halfway and needs manual repair (`migrate force`)
+	ErrSchemaDirty = errors.New("database schema is dirty")
+	// ErrSchemaPending is returned if migrations are pending and DATABASE_AUTO_MIGRATE is off
+	ErrSchemaPending = errors.New("database schema has pending migrations")
+)
+
+// schemaLockKey is the postgres advisory lock held while migrating, so that API replicas
+// starting at the same time don't apply the same migration twice
+const schemaLockKey = 7262001
+
+// SchemaMigration is one versioned schema change with its rollback.
+// Migrations are either SQL files in migrations/ (NNNN_name.up.sql + NNNN_name.down.sql,
+// the golang-migrate naming) or Go functions in goMigrations for model changes.
+type SchemaMigration struct {
+	Version  int64
+	Name     string
+	Checksum string // SHA-256 of the up script, empty for Go migrations
+	Up       func(tx *gorm.DB) error
+	Down     func(tx *gorm.DB) error
+}
+
+// SchemaMigrationRecord is an applied migration (table schema_migrations)
+type SchemaMigrationRecord struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"not null" json:"name"`
+	Checksum  string    `json:"checksum,omitempty"`
+	Dirty     bool      `gorm:"not null;default:false" json:"dirty"` // Set while the migration runs
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// TableName overrides the table name
+func (SchemaMigrationRecord) TableName() string {
+	return "schema_migrations"
+}
+
+// PendingMigration is a known migration that isn't applied yet
+type PendingMigration struct {
+	Version int64  `json:"version"`
+	Name    string `json:"name"`
+}
+
+// SchemaStatus describes the schema version of the database compared to this build
+type SchemaStatus struct {
+	Current  int64                   `json:"current"` // Highest applied version, 0 = empty database
+	Latest   int64                   `json:"latest"`  // Highest version this build knows
+	Dirty    bool                    `json:"dirty"`
+	Newer    bool                    `json:"newer"`              // Database was migrated by a newer build
+	Modified []int64                 `json:"modified,omitempty"` // Applied SQL migrations whose file changed since
+	Applied  []SchemaMigrationRecord `json:"applied"`
+	Pending  []PendingMigration      `json:"pending"`
+}
+
+//go:embed migrations/*.sql
+var sqlMigrationFiles embed.FS
+
+// migrationFilePattern matches NNNN_name.up.sql and NNNN_name.down.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// SchemaMigrations returns all migrations of this build, ordered by version
+func SchemaMigrations() ([]SchemaMigration, error) {
+	fromFiles, err := loadSQLMigrations(sqlMigrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	all := append(append([]SchemaMigration{}, goMigrations...), fromFiles...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	for i := 1; i < len(all); i++ {
+		if all[i].Version == all[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d (%s, %s)", all[i].Version, all[i-1].Name, all[i].Name)
+		}
+	}
+	return all, nil
+}
+
+// loadSQLMigrations reads the up/down script pairs in dir
+func loadSQLMigrations(fsys fs.FS, dir string) ([]SchemaMigration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*SchemaMigration)
+	scripts := make(map[int64]map[string]string)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration file %s doesn't match NNNN_name.(up|down).sql", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		if version <= 0 {
+			return nil, fmt.Errorf("migration file %s: version must be positive", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &SchemaMigration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+			scripts[version] = make(map[string]string)
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d used by %s and %s", version, migration.Name, match[2])
+		}
+		scripts[version][match[3]] = string(content)
+	}
+
+	migrations := make([]SchemaMigration, 0, len(byVersion))
+	for version, migration := range byVersion {
+		up, down := scripts[version]["up"], scripts[version]["down"]
+		if up == "" || down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down script", version, migration.Name)
+		}
+		sum := sha256.Sum256([]byte(up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migration.Up = execSQL(up)
+		migration.Down = execSQL(down)
+		migrations = append(migrations, *migration)
+	}
+	return migrations, nil
+}
+
+// execSQL returns a migration step running script
+func execSQL(script string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Exec(script).Error
+	}
+}
+
+// schemaStatus compares the applied migrations with the known ones
+func schemaStatus(known []SchemaMigration, applied []SchemaMigrationRecord) *SchemaStatus {
+	status := &SchemaStatus{Applied: applied, Pending: []PendingMigration{}}
+	if len(known) > 0 {
+		status.Latest = known[len(known)-1].Version
+	}
+
+	done := make(map[int64]SchemaMigrationRecord, len(applied))
+	for _, record := range applied {
+		done[record.Version] = record
+		if record.Version > status.Current {
+			status.Current = record.Version
+		}
+		if record.Dirty {
+			status.Dirty = true
+		}
+	}
+	status.Newer = status.Current > status.Latest
+
+	for _, migration := range known {
+		record, ok := done[migration.Version]
+		if !ok {
+			status.Pending = append(status.Pending, PendingMigration{Version: migration.Version, Name: migration.Name})
+			continue
+		}
+		if migration.Checksum != "" && record.Checksum != "" && record.Checksum != migration.Checksum {
+			status.Modified = append(status.Modified, migration.Version)
+		}
+	}
+	return status
+}
+
+// check returns the reason the schema can't be used by this build, if any
+func (s *SchemaStatus) check(allowNewer bool) error {
+	if s.Dirty {
+		return fmt.Errorf("%w: a migration failed halfway, repair it and run `migrate force <version>`", ErrSchemaDirty)
+	}
+	if s.Newer && !allowNewer {
+		return fmt.Errorf("%w: database is at version %d, this build knows up to %d (set DATABASE_ALLOW_NEWER_SCHEMA=true to start anyway)",
+			ErrSchemaNewer, s.Current, s.Latest)
+	}
+	return nil
+}
+
+// Migrator applies and rolls back the schema migrations of a database
+type Migrator struct {
+	db         *gorm.DB
+	migrations []SchemaMigration
+}
+
+// NewMigrator creates a migrator for db with the migrations of this build
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := SchemaMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Status returns the schema version of the database
+func (m *Migrator) Status() (*SchemaStatus, error) {
+	var applied []SchemaMigrationRecord
+	if m.db.Migrator().HasTable(&SchemaMigrationRecord{}) {
+		if err := m.db.Order("version ASC").Find(&applied).Error; err != nil {
+			return nil, err
+		}
+	}
+	return schemaStatus(m.migrations, applied), nil
+}
+
+// Check refuses a dirty schema, a newer schema (unless allowNewer) and, without autoMigrate, pending migrations
+func (m *Migrator) Check(allowNewer, autoMigrate bool) (*SchemaStatus, error) {
+	status, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+	if err := status.check(allowNewer); err != nil {
+		return status, err
+	}
+	if !autoMigrate && len(status.Pending) > 0 {
+		return status, fmt.Errorf("%w: %d migration(s) up to version %d, run `migrate up` (or set DATABASE_AUTO_MIGRATE=true)",
+			ErrSchemaPending, len(status.Pending), status.Latest)
+	}
+	return status, nil
+}
+
+// Up applies all pending migrations in version order and returns how many were applied
+func (m *Migrator) Up(allowNewer bool) (int, error) {
+	count := 0
+	err := m.locked(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		status := schemaStatus(m.migrations, applied)
+		if err := status.check(allowNewer); err != nil {
+			return err
+		}
+
+		pending := make(map[int64]bool, len(status.Pending))
+		for _, p := range status.Pending {
+			pending[p.Version] = true
+		}
+		for _, migration := range m.migrations {
+			if !pending[migration.Version] {
+				continue
+			}
+			if err := m.apply(conn, migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down rolls back the last steps applied migrations, newest first
+func (m *Migrator) Down(steps int) (int, error) {
+	known := make(map[int64]SchemaMigration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	count := 0
+	err := m.locked(func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+		if err := schemaStatus(m.migrations, applied).check(false); err != nil {
+			return err
+		}
+
+		for i := len(applied) - 1; i >= 0 && count < steps; i-- {
+			migration, ok := known[applied[i].Version]
+			if !ok {
+				return fmt.Errorf("%w: no down script for version %d", ErrSchemaNewer, applied[i].Version)
+			}
+			if err := m.revert(conn, migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Force marks version as the clean current version after a failed migration was repaired by hand:
+// the dirty flag is cleared, later records are removed and missing earlier ones are recorded
+func (m *Migrator) Force(version int64) error {
+	return m.locked(func(conn *gorm.DB) error {
+		return conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("version > ?", version).Delete(&SchemaMigrationRecord{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&SchemaMigrationRecord{}).Where("dirty = ?", true).Update("dirty", false).Error; err != nil {
+				return err
+			}
+			var applied []SchemaMigrationRecord
+			if err := tx.Find(&applied).Error; err != nil {
+				return err
+			}
+			done := make(map[int64]bool, len(applied))
+			for _, record := range applied {
+				done[record.Version] = true
+			}
+			for _, migration := range m.migrations {
+				if migration.Version > version || done[migration.Version] {
+					continue
+				}
+				if err := tx.Create(m.record(migration, false)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// apply runs one up migration. The record is written as dirty before and cleaned after, so a
+// migration that fails outside its transaction (e.g. CREATE INDEX CONCURRENTLY) blocks startup.
+func (m *Migrator) apply(conn *gorm.DB, migration SchemaMigration) error {
+	started := time.Now()
+	if err := conn.Create(m.record(migration, true)).Error; err != nil {
+		return err
+	}
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Up(tx); err != nil {
+			return err
+		}
+		return tx.Model(&SchemaMigrationRecord{}).Where("version = ?", migration.Version).Update("dirty", false).Error
+	})
+	if err != nil {
+		// The transaction rolled back, the schema is unchanged
+		conn.Where("version = ?", migration.Version).Delete(&SchemaMigrationRecord{})
+		return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+
+	log.Printf("Applied schema migration %d_%s in %s", migration.Version, migration.Name, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
+// revert runs one down migration and removes its record
+func (m *Migrator) revert(conn *gorm.DB, migration SchemaMigration) error {
+	err := conn.Transaction(func(tx *gorm.DB) error {
+		if err := migration.Down(tx); err != nil {
+			return err
+		}
+		return tx.Where("version = ?", migration.Version).Delete(&SchemaMigrationRecord{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("rollback of migration %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+
+	log.Printf("Rolled back schema migration %d_%s", migration.Version, migration.Name)
+	return nil
+}
+
+// record returns the schema_migrations row of migration
+func (m *Migrator) record(migration SchemaMigration, dirty bool) *SchemaMigrationRecord {
+	return &SchemaMigrationRecord{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Checksum:  migration.Checksum,
+		Dirty:     dirty,
+		AppliedAt: time.Now(),
+	}
+}
+
+// applied returns the applied migrations ordered by version; the tracking table is created on first use
+func (m *Migrator) applied(conn *gorm.DB) ([]SchemaMigrationRecord, error) {
+	if err := conn.AutoMigrate(&SchemaMigrationRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	var records []SchemaMigrationRecord
+	err := conn.Order("version ASC").Find(&records).Error
+	return records, err
+}
+
+// locked runs fn on a single connection holding the migration advisory lock
+func (m *Migrator) locked(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", schemaLockKey).Error; err != nil {
+			return fmt.Errorf("failed to acquire the migration lock: %w", err)
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", schemaLockKey)
+		return fn(conn)
+	})
+}
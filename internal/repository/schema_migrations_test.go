@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSchemaMigrationsOfThisBuild(t *testing.T) {
+	migrations, err := SchemaMigrations()
+	if err != nil {
+		t.Fatalf("SchemaMigrations() error = %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 1 || migrations[0].Name != "baseline" {
+		t.Fatalf("first migration = %+v, want version 1 baseline", migrations[0])
+	}
+	for i, migration := range migrations {
+		if migration.Up == nil || migration.Down == nil {
+			t.Errorf("migration %d_%s has no up or down step", migration.Version, migration.Name)
+		}
+		if i > 0 && migration.Version <= migrations[i-1].Version {
+			t.Errorf("migration %d is not ordered after %d", migration.Version, migrations[i-1].Version)
+		}
+	}
+}
+
+func TestLoadSQLMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []int64
+		wantErr string
+	}{
+		{
+			name: "pairs",
+			files: fstest.MapFS{
+				"m/0003_b.up.sql":   {Data: []byte("CREATE INDEX b")},
+				"m/0003_b.down.sql": {Data: []byte("DROP INDEX b")},
+				"m/0002_a.up.sql":   {Data: []byte("CREATE INDEX a")},
+				"m/0002_a.down.sql": {Data: []byte("DROP INDEX a")},
+			},
+			want: []int64{2, 3},
+		},
+		{
+			name:    "missing down script",
+			files:   fstest.MapFS{"m/0002_a.up.sql": {Data: []byte("CREATE INDEX a")}},
+			wantErr: "needs both",
+		},
+		{
+			name:    "bad file name",
+			files:   fstest.MapFS{"m/add_index.sql": {Data: []byte("CREATE INDEX a")}},
+			wantErr: "doesn't match",
+		},
+		{
+			name: "version used twice",
+			files: fstest.MapFS{
+				"m/0002_a.up.sql": {Data: []byte("CREATE INDEX a")},
+				"m/0002_b.up.sql": {Data: []byte("CREATE INDEX b")},
+			},
+			wantErr: "used by",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadSQLMigrations(tt.files, "m")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadSQLMigrations() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadSQLMigrations() error = %v", err)
+			}
+			versions := map[int64]bool{}
+			for _, migration := range migrations {
+				versions[migration.Version] = true
+				if migration.Checksum == "" {
+					t.Errorf("migration %d has no checksum", migration.Version)
+				}
+			}
+			for _, version := range tt.want {
+				if !versions[version] {
+					t.Errorf("migration %d not loaded (got %d migrations)", version, len(migrations))
+				}
+			}
+		})
+	}
+}
+
+func TestSchemaStatusSafetyChecks(t *testing.T) {
+	known := []SchemaMigration{
+		{Version: 1, Name: "baseline"},
+		{Version: 2, Name: "index", Checksum: "abc"},
+		{Version: 3, Name: "column"},
+	}
+
+	tests := []struct {
+		name        string
+		applied     []SchemaMigrationRecord
+		allowNewer  bool
+		wantErr     error
+		wantPending int
+		wantCurrent int64
+	}{
+		{name: "empty database", wantPending: 3},
+		{name: "partly migrated", applied: []SchemaMigrationRecord{{Version: 1}}, wantPending: 2, wantCurrent: 1},
+		{name: "up to date", applied: []SchemaMigrationRecord{{Version: 1}, {Version: 2}, {Version: 3}}, wantCurrent: 3},
+		{
+			name:        "newer schema",
+			applied:     []SchemaMigrationRecord{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}},
+			wantErr:     ErrSchemaNewer,
+			wantCurrent: 4,
+		},
+		{
+			name:        "newer schema allowed",
+			applied:     []SchemaMigrationRecord{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}},
+			allowNewer:  true,
+			wantCurrent: 4,
+		},
+		{
+			name:        "dirty",
+			applied:     []SchemaMigrationRecord{{Version: 1}, {Version: 2, Dirty: true}},
+			allowNewer:  true,
+			wantErr:     ErrSchemaDirty,
+			wantPending: 1,
+			wantCurrent: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := schemaStatus(known, tt.applied)
+			if err := status.check(tt.allowNewer); !errors.Is(err, tt.wantErr) {
+				t.Errorf("check() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(status.Pending) != tt.wantPending {
+				t.Errorf("pending = %v, want %d", status.Pending, tt.wantPending)
+			}
+			if status.Current != tt.wantCurrent || status.Latest != 3 {
+				t.Errorf("current/latest = %d/%d, want %d/3", status.Current, status.Latest, tt.wantCurrent)
+			}
+		})
+	}
+
+	modified := schemaStatus(known, []SchemaMigrationRecord{{Version: 1}, {Version: 2, Checksum: "old"}})
+	if len(modified.Modified) != 1 || modified.Modified[0] != 2 {
+		t.Errorf("modified = %v, want [2]", modified.Modified)
+	}
+}
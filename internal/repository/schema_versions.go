@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// goMigrations are the schema migrations written in Go, merged with the SQL files in migrations/.
+// A new or changed model gets its own version here (AutoMigrate of the model), never an edit of
+// an applied migration; plain DDL like indexes goes into a SQL file.
+var goMigrations = []SchemaMigration{
+	{Version: 1, Name: "baseline", Up: baselineUp, Down: baselineDown},
+	{Version: 3, Name: "usage_archives", Up: createTables(&models.UsageArchive{}), Down: dropTables(&models.UsageArchive{})},
+	{Version: 4, Name: "server_suspension", Up: createTables(&models.MinecraftServer{}), Down: dropColumns(&models.MinecraftServer{},
+		"suspended_at", "suspension_reason", "suspension_source", "suspended_by", "status_before_suspension")},
+	{Version: 5, Name: "archive_tiers", Up: createTables(&models.MinecraftServer{}, &models.ArchiveRetrieval{}), Down: archiveTiersDown},
+	{Version: 6, Name: "archive_manifests", Up: createTables(&models.ArchiveManifest{}), Down: dropTables(&models.ArchiveManifest{})},
+	{Version: 7, Name: "lifecycle_policies", Up: createTables(&models.LifecyclePolicy{}, &models.LifecycleNotice{}),
+		Down: dropTables(&models.LifecyclePolicy{}, &models.LifecycleNotice{})},
+	{Version: 8, Name: "player_list_changes", Up: createTables(&models.PlayerListChange{}), Down: dropTables(&models.PlayerListChange{})},
+	{Version: 9, Name: "minecraft_links", Up: createTables(&models.MinecraftLink{}), Down: dropTables(&models.MinecraftLink{})},
+	{Version: 10, Name: "announcements", Up: createTables(&models.Announcement{}), Down: dropTables(&models.Announcement{})},
+	{Version: 11, Name: "player_bans", Up: createTables(&models.PlayerBan{}), Down: dropTables(&models.PlayerBan{})},
+	{Version: 12, Name: "placement_constraints", Up: createTables(&models.MinecraftServer{}), Down: dropColumns(&models.MinecraftServer{},
+		"pinned_node_id", "anti_affinity_group", "spread_across_nodes")},
+	{Version: 13, Name: "scaling_decisions", Up: createTables(&models.ScalingDecision{}), Down: dropTables(&models.ScalingDecision{})},
+	{Version: 14, Name: "scaling_policy_configs", Up: createTables(&models.ScalingPolicyConfig{}), Down: dropTables(&models.ScalingPolicyConfig{})},
+	{Version: 15, Name: "node_cost_samples", Up: createTables(&models.NodeCostSample{}), Down: dropTables(&models.NodeCostSample{})},
+	{Version: 16, Name: "webhook_endpoints", Up: createTables(&models.WebhookEndpoint{}, &models.WebhookDelivery{}), Down: dropTables(&models.WebhookDelivery{}, &models.WebhookEndpoint{})},
+	{Version: 17, Name: "server_declarations", Up: createTables(&models.ServerDeclaration{}), Down: dropTables(&models.ServerDeclaration{})},
+	{Version: 18, Name: "drop_idle_policy_protected_players", Up: dropIdlePolicyProtectedPlayers, Down: restoreIdlePolicyProtectedPlayers},
+}
+
+// baselineModels are the tables of the schema before versioned migrations. Databases created by
+// the previous AutoMigrate startup already have them; the baseline then only adds missing columns.
+var baselineModels = []interface{}{
+	&models.User{},
+	&models.MinecraftServer{},
+	&models.UsageLog{},
+	&models.ConfigChange{},
+	&models.ServerFile{},
+	&models.ServerWebhook{},
+	&models.ServerBackupSchedule{},
+	&models.BillingEvent{},
+	&models.UsageSession{},
+	&models.TrustedDevice{},
+	&models.SecurityEvent{},
+	&models.OAuthAccount{},
+	&models.OAuthState{},
+	&models.SystemEvent{},
+	&models.Plugin{},
+	&models.PluginVersion{},
+	&models.InstalledPlugin{},
+	&models.Migration{},
+	&models.Backup{},
+	&models.BackupRestoreTracking{},
+	&models.Node{},
+	&models.IdleShutdownPolicy{},
+	&models.ShutdownAuditEntry{},
+	&models.StripeCustomer{},
+	&models.StripeUsageReport{},
+	&models.StripeInvoice{},
+	&models.StripeWebhookEvent{},
+	&models.WalletTransaction{},
+	&models.BudgetCap{},
+	&models.BudgetAlert{},
+	&models.Invoice{},
+	&models.InvoiceLine{},
+	&models.Organization{},
+	&models.OrganizationMember{},
+	&models.OrganizationInvitation{},
+	&models.ServerShare{},
+	&models.APIKey{},
+	&models.RecoveryCode{},
+	&models.SSOConnection{},
+	&models.SSOIdentity{},
+	&models.SSOLoginState{},
+	&models.SSODomain{},
+	&models.SSOPendingLink{},
+	&models.VotifierConfig{},
+	&models.OutboxEvent{},
+	&models.BackupLegalHold{},
+	&models.Incident{},
+	&models.IdempotencyRecord{},
+	&models.Job{},
+	&models.UserTemplate{},
+	&models.UserTemplateRevision{},
+	&models.WorldExport{},
+	&models.SFTPKey{},
+	&models.SFTPAuditEntry{},
+	&models.AuditEvent{},
+	&models.SSHKey{},
+}
+
+func baselineUp(tx *gorm.DB) error {
+	return tx.AutoMigrate(baselineModels...)
+}
+
+func baselineDown(tx *gorm.DB) error {
+	// Reverse order: tables referencing others are dropped first
+	for i := len(baselineModels) - 1; i >= 0; i-- {
+		if err := tx.Migrator().DropTable(baselineModels[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func archiveTiersDown(tx *gorm.DB) error {
+	if err := dropTables(&models.ArchiveRetrieval{})(tx); err != nil {
+		return err
+	}
+	return dropColumns(&models.MinecraftServer{}, "archive_tier", "archive_cold_at", "archive_restore_requested_at")(tx)
+}
+
+// idlePolicyProtectedPlayers is the protected_players column removed from idle-shutdown policies,
+// for restoring it in the down migration
+type idlePolicyProtectedPlayers struct {
+	ProtectedPlayers string `gorm:"size:1024;default:''"`
+}
+
+func (idlePolicyProtectedPlayers) TableName() string {
+	return "idle_shutdown_policies"
+}
+
+func dropIdlePolicyProtectedPlayers(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&idlePolicyProtectedPlayers{}, "ProtectedPlayers") {
+		return nil // Table created after the column was removed from the model
+	}
+	return tx.Migrator().DropColumn(&idlePolicyProtectedPlayers{}, "ProtectedPlayers")
+}
+
+func restoreIdlePolicyProtectedPlayers(tx *gorm.DB) error {
+	if tx.Migrator().HasColumn(&idlePolicyProtectedPlayers{}, "ProtectedPlayers") {
+		return nil
+	}
+	return tx.Migrator().AddColumn(&idlePolicyProtectedPlayers{}, "ProtectedPlayers")
+}
+
+// createTables returns a migration step creating (or updating) the tables of the given models
+func createTables(tables ...interface{}) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.AutoMigrate(tables...)
+	}
+}
+
+// dropTables returns a migration step dropping the tables of the given models
+func dropTables(tables ...interface{}) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(tables...)
+	}
+}
+
+// dropColumns returns a migration step dropping columns that a later version added to a model
+func dropColumns(model interface{}, columns ...string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for _, column := range columns {
+			if err := tx.Migrator().DropColumn(model, column); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
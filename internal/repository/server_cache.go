@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/cache"
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// serverCacheRecheck is the delay of the second invalidation after a write. A read that started
+// before the write can cache the old row after the first invalidation; the second one drops it.
+const serverCacheRecheck = time.Second
+
+// serverCache caches FindByID and FindByOwner when CACHE_STORE is set (nil = no caching)
+var serverCache *cache.Cache
+
+// SetServerCache enables caching of server lookups; every ServerRepository write invalidates
+// the affected entries
+func SetServerCache(c *cache.Cache) {
+	serverCache = c
+}
+
+// cachedServer is the cache entry of a server; the RCON password isn't part of the JSON
+// encoding of the model but is needed by the callers
+type cachedServer struct {
+	Server       models.MinecraftServer `json:"server"`
+	RCONPassword string                 `json:"rcon_password"`
+}
+
+func newCachedServer(server *models.MinecraftServer) cachedServer {
+	return cachedServer{Server: *server, RCONPassword: server.RCONPassword}
+}
+
+func (c cachedServer) server() models.MinecraftServer {
+	server := c.Server
+	server.RCONPassword = c.RCONPassword
+	return server
+}
+
+func serverCacheKey(id string) string {
+	return "server:" + id
+}
+
+func ownerServersCacheKey(ownerID string) string {
+	return "servers:owner:" + ownerID
+}
+
+// invalidateServers drops the cached servers of ids and the server lists of their owners, now and
+// again after serverCacheRecheck. owners are known owners (e.g. of the saved model); the current
+// owners are looked up as well, so an ownership change also clears the list of the new owner.
+func invalidateServers(db *gorm.DB, ids []string, owners ...string) {
+	if serverCache == nil || len(ids) == 0 {
+		return
+	}
+	for _, id := range ids {
+		var cached cachedServer
+		if serverCache.Get(serverCacheKey(id), &cached) {
+			owners = append(owners, cached.Server.OwnerID)
+		}
+	}
+	var current []string
+	if err := db.Unscoped().Model(&models.MinecraftServer{}).Where("id IN ?", ids).Pluck("owner_id", &current).Error; err == nil {
+		owners = append(owners, current...)
+	}
+
+	keys := make([]string, 0, len(ids)+len(owners))
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		keys = append(keys, serverCacheKey(id))
+	}
+	for _, owner := range owners {
+		if owner != "" && !seen[owner] {
+			seen[owner] = true
+			keys = append(keys, ownerServersCacheKey(owner))
+		}
+	}
+
+	c := serverCache
+	c.Delete(keys...)
+	time.AfterFunc(serverCacheRecheck, func() { c.Delete(keys...) })
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/cache"
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestServerCacheInvalidatedOnWrite(t *testing.T) {
+	SetServerCache(cache.New("servers-test", cache.NewMemoryStore(), time.Minute))
+	t.Cleanup(func() { SetServerCache(nil) })
+	// Without the default transaction the dry run needs no connection for writes
+	repo := NewServerRepository(newDryRunDB(t).Session(&gorm.Session{SkipDefaultTransaction: true}))
+
+	server := &models.MinecraftServer{ID: "srv-1", OwnerID: "user-1", Name: "cached", RCONPassword: "secret"}
+	serverCache.Set(serverCacheKey(server.ID), newCachedServer(server))
+	serverCache.Set(ownerServersCacheKey("user-1"), []cachedServer{newCachedServer(server)})
+
+	// Lookups are answered from the cache, including the RCON password the JSON model hides
+	got, err := repo.FindByID("srv-1")
+	if err != nil || got.Name != "cached" || got.RCONPassword != "secret" {
+		t.Fatalf("FindByID() = %+v, %v; want the cached server with its RCON password", got, err)
+	}
+	owned, err := repo.FindByOwner("user-1")
+	if err != nil || len(owned) != 1 || owned[0].RCONPassword != "secret" {
+		t.Fatalf("FindByOwner() = %+v, %v; want the cached list", owned, err)
+	}
+
+	// A write drops the server and the list of its owner, the next lookup goes to the database
+	server.Name = "renamed"
+	if err := repo.Update(server); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	var cached cachedServer
+	if serverCache.Get(serverCacheKey("srv-1"), &cached) {
+		t.Errorf("server still cached after Update(): %+v", cached.Server)
+	}
+	var list []cachedServer
+	if serverCache.Get(ownerServersCacheKey("user-1"), &list) {
+		t.Error("owner list still cached after Update()")
+	}
+	if got, _ := repo.FindByID("srv-1"); got.Name == "cached" {
+		t.Error("FindByID() after Update() returned the stale cache entry")
+	}
+
+	// Updates by ID find the owner list through the cached server
+	serverCache.Set(serverCacheKey("srv-1"), newCachedServer(server))
+	serverCache.Set(ownerServersCacheKey("user-1"), []cachedServer{newCachedServer(server)})
+	if err := repo.UpdateDiskQuota("srv-1", 2048); err != nil {
+		t.Fatalf("UpdateDiskQuota() error = %v", err)
+	}
+	if serverCache.Get(ownerServersCacheKey("user-1"), &list) {
+		t.Error("owner list still cached after UpdateDiskQuota()")
+	}
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// ServerDeclarationRepository handles database operations for the servers managed by declarative manifests
+type ServerDeclarationRepository struct {
+	db *gorm.DB
+}
+
+// NewServerDeclarationRepository creates a new server declaration repository
+func NewServerDeclarationRepository(db *gorm.DB) *ServerDeclarationRepository {
+	return &ServerDeclarationRepository{db: db}
+}
+
+// Create stores a new declaration
+func (r *ServerDeclarationRepository) Create(declaration *models.ServerDeclaration) error {
+	return r.db.Create(declaration).Error
+}
+
+// FindByUser returns the declarations of a user, ordered by name
+func (r *ServerDeclarationRepository) FindByUser(userID string) ([]models.ServerDeclaration, error) {
+	var declarations []models.ServerDeclaration
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&declarations).Error
+	return declarations, err
+}
+
+// Delete removes a declaration (the server itself is not deleted)
+func (r *ServerDeclarationRepository) Delete(id string) error {
+	return r.db.Delete(&models.ServerDeclaration{}, "id = ?", id).Error
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/payperplay/hosting/internal/models"
 	"gorm.io/gorm"
 )
@@ -14,16 +16,28 @@ func NewServerRepository(db *gorm.DB) *ServerRepository {
 }
 
 func (r *ServerRepository) Create(server *models.MinecraftServer) error {
-	return r.db.Create(server).Error
+	if err := r.db.Create(server).Error; err != nil {
+		return err
+	}
+	invalidateServers(r.db, []string{server.ID}, server.OwnerID)
+	return nil
 }
 
+// FindByID finds a server by ID, including soft-deleted ones (cached if CACHE_STORE is set)
 func (r *ServerRepository) FindByID(id string) (*models.MinecraftServer, error) {
+	var cached cachedServer
+	if serverCache.Get(serverCacheKey(id), &cached) {
+		server := cached.server()
+		return &server, nil
+	}
+
 	var server models.MinecraftServer
 	// Use Unscoped() to find soft-deleted servers (needed for cleanup)
 	err := r.db.Unscoped().Where("id = ?", id).First(&server).Error
 	if err != nil {
 		return nil, err
 	}
+	serverCache.Set(serverCacheKey(id), newCachedServer(&server))
 	return &server, nil
 }
 
@@ -34,18 +48,88 @@ func (r *ServerRepository) FindAll() ([]models.MinecraftServer, error) {
 	return servers, err
 }
 
+// FindByOwner returns the servers of an owner (cached if CACHE_STORE is set)
 func (r *ServerRepository) FindByOwner(ownerID string) ([]models.MinecraftServer, error) {
+	var cached []cachedServer
+	if serverCache.Get(ownerServersCacheKey(ownerID), &cached) {
+		servers := make([]models.MinecraftServer, len(cached))
+		for i := range cached {
+			servers[i] = cached[i].server()
+		}
+		return servers, nil
+	}
+
 	var servers []models.MinecraftServer
 	err := r.db.Where("owner_id = ?", ownerID).Find(&servers).Error
+	if err != nil {
+		return servers, err
+	}
+	cached = make([]cachedServer, len(servers))
+	for i := range servers {
+		cached[i] = newCachedServer(&servers[i])
+	}
+	serverCache.Set(ownerServersCacheKey(ownerID), cached)
+	return servers, nil
+}
+
+// FindAccessible returns the servers a user owns, that are shared with one of their organizations
+// or that were shared with them individually (redeemed server shares)
+func (r *ServerRepository) FindAccessible(userID string) ([]models.MinecraftServer, error) {
+	var servers []models.MinecraftServer
+	err := r.accessible(userID).Find(&servers).Error
 	return servers, err
 }
 
+func (r *ServerRepository) accessible(userID string) *gorm.DB {
+	return r.db.Model(&models.MinecraftServer{}).
+		Where("(owner_id = ? OR (organization_id <> '' AND organization_id IN (?)) OR id IN (?))", userID,
+			r.db.Model(&models.OrganizationMember{}).Select("organization_id").Where("user_id = ?", userID),
+			r.db.Model(&models.ServerShare{}).Select("server_id").Where("user_id = ?", userID))
+}
+
+// serverPageColumns are the sortable server columns
+var serverPageColumns = pageColumns[models.MinecraftServer]{
+	"created_at": func(s *models.MinecraftServer) interface{} { return s.CreatedAt },
+	"updated_at": func(s *models.MinecraftServer) interface{} { return s.UpdatedAt },
+	"name":       func(s *models.MinecraftServer) interface{} { return s.Name },
+	"status":     func(s *models.MinecraftServer) interface{} { return s.Status },
+	"ram_mb":     func(s *models.MinecraftServer) interface{} { return s.RAMMb },
+}
+
+// serverFilterColumns maps list filters to server columns
+var serverFilterColumns = map[string]string{
+	"status":          "status",
+	"node":            "node_id",
+	"type":            "server_type",
+	"organization_id": "organization_id",
+}
+
+// FindAccessiblePage returns one page of the servers a user can access (see FindAccessible)
+func (r *ServerRepository) FindAccessiblePage(userID string, page PageRequest) (*Page[models.MinecraftServer], error) {
+	return paginate(r.accessible(userID), page, "created_at", serverPageColumns, serverFilterColumns,
+		func(s *models.MinecraftServer) string { return s.ID })
+}
+
+// FindAllPage returns one page of all servers, including soft-deleted ones (admin, read replica)
+func (r *ServerRepository) FindAllPage(page PageRequest) (*Page[models.MinecraftServer], error) {
+	return paginate(ReadDB(r.db).Unscoped().Model(&models.MinecraftServer{}), page, "created_at", serverPageColumns, serverFilterColumns,
+		func(s *models.MinecraftServer) string { return s.ID })
+}
+
 func (r *ServerRepository) FindByStatus(status string) ([]models.MinecraftServer, error) {
 	var servers []models.MinecraftServer
 	err := r.db.Where("status = ?", status).Find(&servers).Error
 	return servers, err
 }
 
+// FindReservedStandby returns the stopped and sleeping servers of the reserved plan (guaranteed start)
+func (r *ServerRepository) FindReservedStandby() ([]models.MinecraftServer, error) {
+	var servers []models.MinecraftServer
+	err := r.db.Where("plan = ? AND status IN ?", models.PlanReserved,
+		[]models.ServerStatus{models.StatusStopped, models.StatusSleeping}).Find(&servers).Error
+	return servers, err
+}
+
 func (r *ServerRepository) FindArchivedServers(ownerID string) ([]models.MinecraftServer, error) {
 	var servers []models.MinecraftServer
 	query := r.db.Where("status = ?", models.StatusArchived)
@@ -70,10 +154,89 @@ func (r *ServerRepository) FindByPort(port int) (*models.MinecraftServer, error)
 }
 
 func (r *ServerRepository) Update(server *models.MinecraftServer) error {
-	return r.db.Save(server).Error
+	if err := r.db.Save(server).Error; err != nil {
+		return err
+	}
+	invalidateServers(r.db, []string{server.ID}, server.OwnerID)
+	return nil
+}
+
+// UpdateFields updates server columns
+func (r *ServerRepository) UpdateFields(serverID string, updates map[string]interface{}) error {
+	if err := r.db.Model(&models.MinecraftServer{}).Where("id = ?", serverID).Updates(updates).Error; err != nil {
+		return err
+	}
+	invalidateServers(r.db, []string{serverID})
+	return nil
+}
+
+// UpdateWithEvent saves the server and records event in the outbox in one transaction
+func (r *ServerRepository) UpdateWithEvent(server *models.MinecraftServer, event *models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(server).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return err
+	}
+	invalidateServers(r.db, []string{server.ID}, server.OwnerID)
+	return nil
+}
+
+// UpdateFieldsWithEvent updates server columns and records event in the outbox in one transaction
+func (r *ServerRepository) UpdateFieldsWithEvent(serverID string, updates map[string]interface{}, event *models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.MinecraftServer{}).Where("id = ?", serverID).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return err
+	}
+	invalidateServers(r.db, []string{serverID})
+	return nil
+}
+
+// UpdateDiskUsage stores a measured server directory size and the notified warning threshold
+func (r *ServerRepository) UpdateDiskUsage(serverID string, usageBytes int64, warnPercent int) error {
+	err := r.db.Model(&models.MinecraftServer{}).Where("id = ?", serverID).Updates(map[string]interface{}{
+		"disk_usage_bytes":      usageBytes,
+		"disk_usage_scanned_at": time.Now(),
+		"disk_warn_percent":     warnPercent,
+	}).Error
+	if err != nil {
+		return err
+	}
+	invalidateServers(r.db, []string{serverID})
+	return nil
+}
+
+// AddDiskUsage adds bytes written since the last scan to the stored server directory size
+func (r *ServerRepository) AddDiskUsage(serverID string, bytes int64) error {
+	err := r.db.Model(&models.MinecraftServer{}).Where("id = ?", serverID).
+		Update("disk_usage_bytes", gorm.Expr("disk_usage_bytes + ?", bytes)).Error
+	if err != nil {
+		return err
+	}
+	invalidateServers(r.db, []string{serverID})
+	return nil
+}
+
+// UpdateDiskQuota sets the disk quota of a server (0 = default quota)
+func (r *ServerRepository) UpdateDiskQuota(serverID string, quotaMB int) error {
+	if err := r.db.Model(&models.MinecraftServer{}).Where("id = ?", serverID).Update("disk_quota_mb", quotaMB).Error; err != nil {
+		return err
+	}
+	invalidateServers(r.db, []string{serverID})
+	return nil
 }
 
 func (r *ServerRepository) Delete(id string) error {
+	// Drop the cache before the row is gone, the owner list is found through the server
+	invalidateServers(r.db, []string{id})
 	// Use Unscoped() to perform a hard delete (not soft delete)
 	return r.db.Unscoped().Where("id = ?", id).Delete(&models.MinecraftServer{}).Error
 }
@@ -84,7 +247,16 @@ func (r *ServerRepository) GetUsedPorts() ([]int, error) {
 	err := r.db.Unscoped().Model(&models.MinecraftServer{}).
 		Where("port IS NOT NULL").
 		Pluck("port", &ports).Error
-	return ports, err
+	if err != nil {
+		return nil, err
+	}
+
+	// Votifier listeners take ports from the same range
+	var votifierPorts []int
+	err = r.db.Unscoped().Model(&models.MinecraftServer{}).
+		Where("votifier_port > 0").
+		Pluck("votifier_port", &votifierPorts).Error
+	return append(ports, votifierPorts...), err
 }
 
 // Usage Log Repository Methods
@@ -109,7 +281,7 @@ func (r *ServerRepository) UpdateUsageLog(log *models.UsageLog) error {
 
 func (r *ServerRepository) GetServerUsageLogs(serverID string) ([]models.UsageLog, error) {
 	var logs []models.UsageLog
-	err := r.db.Where("server_id = ?", serverID).
+	err := ReadDB(r.db).Where("server_id = ?", serverID).
 		Order("started_at DESC").
 		Find(&logs).Error
 	return logs, err
@@ -119,3 +291,30 @@ func (r *ServerRepository) DeleteServerUsageLogs(serverID string) error {
 	// Use Unscoped() to perform a hard delete (not soft delete)
 	return r.db.Unscoped().Where("server_id = ?", serverID).Delete(&models.UsageLog{}).Error
 }
+
+// CreateArchiveRetrieval records an archive read back from cold storage
+func (r *ServerRepository) CreateArchiveRetrieval(retrieval *models.ArchiveRetrieval) error {
+	return r.db.Create(retrieval).Error
+}
+
+// SaveArchiveManifest stores the manifest of a server's archive, replacing the previous one
+func (r *ServerRepository) SaveArchiveManifest(manifest *models.ArchiveManifest) error {
+	return r.db.Save(manifest).Error
+}
+
+// FindArchiveManifest returns the manifest of a server's archive (nil for archives created without one)
+func (r *ServerRepository) FindArchiveManifest(serverID string) (*models.ArchiveManifest, error) {
+	var manifest models.ArchiveManifest
+	if err := r.db.Where("server_id = ?", serverID).First(&manifest).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// DeleteArchiveManifest removes the manifest once the archive is gone
+func (r *ServerRepository) DeleteArchiveManifest(serverID string) error {
+	return r.db.Where("server_id = ?", serverID).Delete(&models.ArchiveManifest{}).Error
+}
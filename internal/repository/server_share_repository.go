@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// ServerShareRepository handles database operations for per-server shares
+type ServerShareRepository struct {
+	db *gorm.DB
+}
+
+// NewServerShareRepository creates a new server share repository
+func NewServerShareRepository(db *gorm.DB) *ServerShareRepository {
+	return &ServerShareRepository{db: db}
+}
+
+// Create creates a share
+func (r *ServerShareRepository) Create(share *models.ServerShare) error {
+	return r.db.Create(share).Error
+}
+
+// Update updates a share
+func (r *ServerShareRepository) Update(share *models.ServerShare) error {
+	return r.db.Save(share).Error
+}
+
+// FindByID finds a share of a server by ID
+func (r *ServerShareRepository) FindByID(serverID string, id uint) (*models.ServerShare, error) {
+	var share models.ServerShare
+	err := r.db.First(&share, "server_id = ? AND id = ?", serverID, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// FindByToken finds a share by its token
+func (r *ServerShareRepository) FindByToken(token string) (*models.ServerShare, error) {
+	var share models.ServerShare
+	err := r.db.First(&share, "token = ?", token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// FindGrant finds the redeemed share of a user on a server
+func (r *ServerShareRepository) FindGrant(serverID, userID string) (*models.ServerShare, error) {
+	var share models.ServerShare
+	err := r.db.First(&share, "server_id = ? AND user_id = ?", serverID, userID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// FindByServer returns all shares of a server (pending and redeemed)
+func (r *ServerShareRepository) FindByServer(serverID string) ([]models.ServerShare, error) {
+	var shares []models.ServerShare
+	err := r.db.Where("server_id = ?", serverID).Order("created_at DESC").Find(&shares).Error
+	return shares, err
+}
+
+// FindByUser returns the redeemed shares granted to a user
+func (r *ServerShareRepository) FindByUser(userID string) ([]models.ServerShare, error) {
+	var shares []models.ServerShare
+	err := r.db.Where("user_id = ?", userID).Order("redeemed_at DESC").Find(&shares).Error
+	return shares, err
+}
+
+// Redeem binds an unredeemed share to a user (fails with ErrInvalidShareToken if redeemed concurrently)
+func (r *ServerShareRepository) Redeem(share *models.ServerShare, userID string) error {
+	now := time.Now()
+	result := r.db.Model(&models.ServerShare{}).
+		Where("id = ? AND redeemed_at IS NULL", share.ID).
+		Updates(map[string]interface{}{"user_id": userID, "redeemed_at": now})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrInvalidShareToken
+	}
+	share.UserID = userID
+	share.RedeemedAt = &now
+	return nil
+}
+
+// Delete revokes a share
+func (r *ServerShareRepository) Delete(serverID string, id uint) error {
+	return r.db.Where("server_id = ? AND id = ?", serverID, id).Delete(&models.ServerShare{}).Error
+}
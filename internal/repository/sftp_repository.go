@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// SFTPRepository handles database operations for SFTP keys and the SFTP audit log
+type SFTPRepository struct {
+	db *gorm.DB
+}
+
+// NewSFTPRepository creates a new SFTP repository
+func NewSFTPRepository(db *gorm.DB) *SFTPRepository {
+	return &SFTPRepository{db: db}
+}
+
+// CreateKey creates an SFTP key
+func (r *SFTPRepository) CreateKey(key *models.SFTPKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindKeysByUser returns the SFTP keys of a user, newest first
+func (r *SFTPRepository) FindKeysByUser(userID string) ([]models.SFTPKey, error) {
+	var keys []models.SFTPKey
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// FindKeyByFingerprint finds an SFTP key by the SHA256 fingerprint of its public key
+func (r *SFTPRepository) FindKeyByFingerprint(fingerprint string) (*models.SFTPKey, error) {
+	var key models.SFTPKey
+	err := r.db.First(&key, "fingerprint = ?", fingerprint).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// DeleteKey deletes an SFTP key of a user; returns gorm.ErrRecordNotFound if there is none
+func (r *SFTPRepository) DeleteKey(userID, keyID string) error {
+	result := r.db.Where("id = ? AND user_id = ?", keyID, userID).Delete(&models.SFTPKey{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TouchKey records the last login with a key
+func (r *SFTPRepository) TouchKey(keyID, ip string) error {
+	return r.db.Model(&models.SFTPKey{}).Where("id = ?", keyID).
+		Updates(map[string]interface{}{"last_used_at": time.Now(), "last_used_ip": ip}).Error
+}
+
+// CreateAuditEntry records an SFTP operation
+func (r *SFTPRepository) CreateAuditEntry(entry *models.SFTPAuditEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// FindAuditByServer returns the latest SFTP operations on a server, newest first
+func (r *SFTPRepository) FindAuditByServer(serverID string, limit int) ([]models.SFTPAuditEntry, error) {
+	var entries []models.SFTPAuditEntry
+	err := r.db.Where("server_id = ?", serverID).Order("created_at DESC, id DESC").Limit(limit).Find(&entries).Error
+	return entries, err
+}
+
+// DeleteAuditBefore deletes audit entries older than cutoff
+func (r *SFTPRepository) DeleteAuditBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", cutoff).Delete(&models.SFTPAuditEntry{})
+	return result.RowsAffected, result.Error
+}
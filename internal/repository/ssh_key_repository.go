@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// SSHKeyRepository handles database operations for node SSH keys
+type SSHKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewSSHKeyRepository creates a new SSH key repository
+func NewSSHKeyRepository(db *gorm.DB) *SSHKeyRepository {
+	return &SSHKeyRepository{db: db}
+}
+
+// Create stores a key
+func (r *SSHKeyRepository) Create(key *models.SSHKey) error {
+	return r.db.Create(key).Error
+}
+
+// Update saves a key
+func (r *SSHKeyRepository) Update(key *models.SSHKey) error {
+	return r.db.Save(key).Error
+}
+
+// FindActive returns the active key of an environment (nil if there is none yet)
+func (r *SSHKeyRepository) FindActive(environment string) (*models.SSHKey, error) {
+	var key models.SSHKey
+	err := r.db.Where("environment = ? AND status = ?", environment, models.SSHKeyActive).
+		Order("activated_at DESC").First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByEnvironment returns all keys of an environment, newest first
+func (r *SSHKeyRepository) FindByEnvironment(environment string) ([]models.SSHKey, error) {
+	var keys []models.SSHKey
+	err := r.db.Where("environment = ?", environment).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// FindRetiringBefore returns the retiring keys whose grace period ended before t
+func (r *SSHKeyRepository) FindRetiringBefore(environment string, t time.Time) ([]models.SSHKey, error) {
+	var keys []models.SSHKey
+	err := r.db.Where("environment = ? AND status = ? AND retire_after < ?", environment, models.SSHKeyRetiring, t).
+		Find(&keys).Error
+	return keys, err
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// SSORepository handles database operations for organization SSO connections, identities and login states
+type SSORepository struct {
+	db *gorm.DB
+}
+
+// NewSSORepository creates a new SSO repository
+func NewSSORepository(db *gorm.DB) *SSORepository {
+	return &SSORepository{db: db}
+}
+
+// FindConnection finds the SSO connection of an organization
+func (r *SSORepository) FindConnection(orgID string) (*models.SSOConnection, error) {
+	var conn models.SSOConnection
+	err := r.db.First(&conn, "organization_id = ?", orgID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conn, nil
+}
+
+// FindEnabledConnections returns all enabled SSO connections (login discovery by email domain)
+func (r *SSORepository) FindEnabledConnections() ([]models.SSOConnection, error) {
+	var conns []models.SSOConnection
+	err := r.db.Where("enabled = ?", true).Find(&conns).Error
+	return conns, err
+}
+
+// SaveConnection creates or updates an SSO connection
+func (r *SSORepository) SaveConnection(conn *models.SSOConnection) error {
+	return r.db.Save(conn).Error
+}
+
+// DeleteConnection deletes the SSO connection of an organization with its identities, domains and pending links
+func (r *SSORepository) DeleteConnection(orgID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ?", orgID).Delete(&models.SSOIdentity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", orgID).Delete(&models.SSODomain{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", orgID).Delete(&models.SSOPendingLink{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("organization_id = ?", orgID).Delete(&models.SSOConnection{}).Error
+	})
+}
+
+// FindEnforcedConnectionsForUser returns the enforced, enabled SSO connections of the organizations
+// userID is a non-owner member of (owners keep password access as break-glass)
+func (r *SSORepository) FindEnforcedConnectionsForUser(userID string) ([]models.SSOConnection, error) {
+	var conns []models.SSOConnection
+	err := r.db.
+		Joins("JOIN organization_members ON organization_members.organization_id = sso_connections.organization_id").
+		Where("organization_members.user_id = ? AND organization_members.role <> ?", userID, models.OrgRoleOwner).
+		Where("sso_connections.enforced = ? AND sso_connections.enabled = ?", true, true).
+		Find(&conns).Error
+	return conns, err
+}
+
+// FindIdentity finds the identity of an IdP subject in an organization
+func (r *SSORepository) FindIdentity(orgID, subject string) (*models.SSOIdentity, error) {
+	var identity models.SSOIdentity
+	err := r.db.Where("organization_id = ? AND subject = ?", orgID, subject).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// SaveIdentity creates or updates an SSO identity
+func (r *SSORepository) SaveIdentity(identity *models.SSOIdentity) error {
+	return r.db.Save(identity).Error
+}
+
+// CreateState stores the state of a started SSO login
+func (r *SSORepository) CreateState(state *models.SSOLoginState) error {
+	return r.db.Create(state).Error
+}
+
+// ConsumeState loads and deletes an SSO login state, so every state works only once
+func (r *SSORepository) ConsumeState(state string) (*models.SSOLoginState, error) {
+	var loginState models.SSOLoginState
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&loginState, "state = ?", state).Error; err != nil {
+			return err
+		}
+		result := tx.Where("state = ?", state).Delete(&models.SSOLoginState{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound // Consumed concurrently
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &loginState, nil
+}
+
+// DeleteExpiredStates removes abandoned SSO login states
+func (r *SSORepository) DeleteExpiredStates(before time.Time) error {
+	return r.db.Where("expires_at < ?", before).Delete(&models.SSOLoginState{}).Error
+}
+
+// FindDomains returns the email domains claimed by an organization
+func (r *SSORepository) FindDomains(orgID string) ([]models.SSODomain, error) {
+	var domains []models.SSODomain
+	err := r.db.Where("organization_id = ?", orgID).Order("domain ASC").Find(&domains).Error
+	return domains, err
+}
+
+// FindDomain finds an organization's claim of a domain
+func (r *SSORepository) FindDomain(orgID, domain string) (*models.SSODomain, error) {
+	var d models.SSODomain
+	err := r.db.Where("organization_id = ? AND domain = ?", orgID, domain).First(&d).Error
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// FindVerifiedDomain finds the verified claim of a domain (at most one organization verifies a domain)
+func (r *SSORepository) FindVerifiedDomain(domain string) (*models.SSODomain, error) {
+	var d models.SSODomain
+	err := r.db.Where("domain = ? AND verified_at IS NOT NULL", domain).First(&d).Error
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// SaveDomain creates or updates a domain claim
+func (r *SSORepository) SaveDomain(domain *models.SSODomain) error {
+	return r.db.Save(domain).Error
+}
+
+// DeleteDomain removes an organization's claim of a domain
+func (r *SSORepository) DeleteDomain(orgID, domain string) error {
+	return r.db.Where("organization_id = ? AND domain = ?", orgID, domain).Delete(&models.SSODomain{}).Error
+}
+
+// CreatePendingLink stores an SSO login waiting for the account holder's confirmation
+// Older pending links of the same identity are replaced.
+func (r *SSORepository) CreatePendingLink(link *models.SSOPendingLink) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ? AND subject = ?", link.OrganizationID, link.Subject).
+			Delete(&models.SSOPendingLink{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(link).Error
+	})
+}
+
+// FindPendingLink finds a pending link by the hash of its browser or email token
+func (r *SSORepository) FindPendingLink(tokenHash string) (*models.SSOPendingLink, error) {
+	var link models.SSOPendingLink
+	err := r.db.Where("token_hash = ? OR email_token_hash = ?", tokenHash, tokenHash).First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// DeletePendingLink removes a pending link once it is confirmed
+func (r *SSORepository) DeletePendingLink(id uint) error {
+	return r.db.Delete(&models.SSOPendingLink{}, id).Error
+}
+
+// DeleteExpiredPendingLinks removes unconfirmed pending links
+func (r *SSORepository) DeleteExpiredPendingLinks(before time.Time) error {
+	return r.db.Where("expires_at < ?", before).Delete(&models.SSOPendingLink{}).Error
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// TemplateRepository handles database operations for owner-defined server templates
+type TemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewTemplateRepository creates a new template repository
+func NewTemplateRepository(db *gorm.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// Create creates a template together with its first revision
+func (r *TemplateRepository) Create(template *models.UserTemplate, changelog string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		template.Revision = 1
+		if err := tx.Create(template).Error; err != nil {
+			return err
+		}
+		return tx.Create(revisionOf(template, changelog)).Error
+	})
+}
+
+// CreateRevision saves the changed template as its next revision
+func (r *TemplateRepository) CreateRevision(template *models.UserTemplate, changelog string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&models.UserTemplateRevision{}).
+			Where("template_id = ?", template.ID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		template.Revision = latest + 1
+		if err := tx.Save(template).Error; err != nil {
+			return err
+		}
+		return tx.Create(revisionOf(template, changelog)).Error
+	})
+}
+
+// revisionOf returns the revision record of the current content of a template
+func revisionOf(template *models.UserTemplate, changelog string) *models.UserTemplateRevision {
+	return &models.UserTemplateRevision{
+		TemplateID:   template.ID,
+		Revision:     template.Revision,
+		Changelog:    changelog,
+		TemplateSpec: template.TemplateSpec,
+	}
+}
+
+// Update updates the metadata of a template (name, description, publication) without a new revision
+func (r *TemplateRepository) Update(template *models.UserTemplate) error {
+	return r.db.Save(template).Error
+}
+
+// IncrementUsage counts a server created from a template
+func (r *TemplateRepository) IncrementUsage(id string) error {
+	return r.db.Model(&models.UserTemplate{}).Where("id = ?", id).
+		UpdateColumn("usage_count", gorm.Expr("usage_count + 1")).Error
+}
+
+// Delete deletes a template and its revisions
+func (r *TemplateRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&models.UserTemplateRevision{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.UserTemplate{}).Error
+	})
+}
+
+// FindByOwner returns the templates of an owner, newest first
+func (r *TemplateRepository) FindByOwner(ownerID string) ([]models.UserTemplate, error) {
+	var templates []models.UserTemplate
+	err := r.db.Where("owner_id = ?", ownerID).Order("created_at DESC").Find(&templates).Error
+	return templates, err
+}
+
+// FindForOwner finds a template of an owner by ID
+func (r *TemplateRepository) FindForOwner(id, ownerID string) (*models.UserTemplate, error) {
+	var template models.UserTemplate
+	err := r.db.First(&template, "id = ? AND owner_id = ?", id, ownerID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindVisible finds a template owned by userID or published in the marketplace
+func (r *TemplateRepository) FindVisible(id, userID string) (*models.UserTemplate, error) {
+	var template models.UserTemplate
+	err := r.db.First(&template, "id = ? AND (owner_id = ? OR published = ?)", id, userID, true).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindRevisions returns all revisions of a template, newest first
+func (r *TemplateRepository) FindRevisions(templateID string) ([]models.UserTemplateRevision, error) {
+	var revisions []models.UserTemplateRevision
+	err := r.db.Where("template_id = ?", templateID).Order("revision DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+// FindRevision finds one revision of a template
+func (r *TemplateRepository) FindRevision(templateID string, revision int) (*models.UserTemplateRevision, error) {
+	var found models.UserTemplateRevision
+	err := r.db.First(&found, "template_id = ? AND revision = ?", templateID, revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+// marketplacePageColumns are the sortable columns of published templates
+var marketplacePageColumns = pageColumns[models.UserTemplate]{
+	"published_at": func(t *models.UserTemplate) interface{} { return t.PublishedAt },
+	"usage_count":  func(t *models.UserTemplate) interface{} { return t.UsageCount },
+	"name":         func(t *models.UserTemplate) interface{} { return t.Name },
+}
+
+// marketplaceFilterColumns maps marketplace filters to template columns
+var marketplaceFilterColumns = map[string]string{
+	"type":    "server_type",
+	"version": "minecraft_version",
+	"owner":   "owner_id",
+}
+
+// FindPublishedPage returns one page of the templates published in the marketplace
+func (r *TemplateRepository) FindPublishedPage(page PageRequest) (*Page[models.UserTemplate], error) {
+	query := r.db.Model(&models.UserTemplate{}).Where("published = ?", true)
+	return paginate(query, page, "published_at", marketplacePageColumns, marketplaceFilterColumns,
+		func(t *models.UserTemplate) string { return t.ID })
+}
@@ -77,6 +77,13 @@ func (r *UserRepository) FindAll() ([]models.User, error) {
 	return users, err
 }
 
+// FindAdmins returns all active platform admins
+func (r *UserRepository) FindAdmins() ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("is_admin = ? AND is_active = ?", true, true).Find(&users).Error
+	return users, err
+}
+
 // UpdateBalance updates user balance
 func (r *UserRepository) UpdateBalance(userID string, newBalance float64) error {
 	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("balance", newBalance).Error
@@ -113,3 +120,15 @@ func (r *UserRepository) FindByResetToken(token string) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+// AdvanceTwoFactorStep records step as the last accepted TOTP time step
+// Returns false if an equal or later step was already used (replayed code).
+func (r *UserRepository) AdvanceTwoFactorStep(userID string, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND two_factor_last_step < ?", userID, step).
+		Update("two_factor_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// WebhookEndpointRepository handles database operations for webhook endpoints and their deliveries
+type WebhookEndpointRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookEndpointRepository creates a new webhook endpoint repository
+func NewWebhookEndpointRepository(db *gorm.DB) *WebhookEndpointRepository {
+	return &WebhookEndpointRepository{db: db}
+}
+
+// Create stores a new endpoint
+func (r *WebhookEndpointRepository) Create(endpoint *models.WebhookEndpoint) error {
+	return r.db.Create(endpoint).Error
+}
+
+// Update saves an endpoint
+func (r *WebhookEndpointRepository) Update(endpoint *models.WebhookEndpoint) error {
+	return r.db.Save(endpoint).Error
+}
+
+// FindForUser returns an endpoint of userID (models.ErrWebhookEndpointNotFound for others' endpoints)
+func (r *WebhookEndpointRepository) FindForUser(endpointID, userID string) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := r.db.Where("id = ? AND user_id = ?", endpointID, userID).First(&endpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, models.ErrWebhookEndpointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// FindByUser returns the endpoints of a user, oldest first
+func (r *WebhookEndpointRepository) FindByUser(userID string) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&endpoints).Error
+	return endpoints, err
+}
+
+// FindEnabledByUser returns the enabled endpoints of a user
+func (r *WebhookEndpointRepository) FindEnabledByUser(userID string) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := r.db.Where("user_id = ? AND enabled = ?", userID, true).Find(&endpoints).Error
+	return endpoints, err
+}
+
+// CountByUser returns the number of endpoints of a user
+func (r *WebhookEndpointRepository) CountByUser(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.WebhookEndpoint{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Delete deletes an endpoint with its deliveries
+func (r *WebhookEndpointRepository) Delete(endpointID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("endpoint_id = ?", endpointID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", endpointID).Delete(&models.WebhookEndpoint{}).Error
+	})
+}
+
+// CreateDelivery stores a new delivery
+func (r *WebhookEndpointRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// UpdateDelivery saves the status and attempts of a delivery (no-op once its endpoint is deleted)
+func (r *WebhookEndpointRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Model(delivery).Select("status", "attempts", "delivered_at").Updates(delivery).Error
+}
+
+// FindDelivery returns a delivery of an endpoint (models.ErrWebhookDeliveryNotFound if there is none)
+func (r *WebhookEndpointRepository) FindDelivery(endpointID, deliveryID string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.Where("id = ? AND endpoint_id = ?", deliveryID, endpointID).First(&delivery).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, models.ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// webhookDeliveryPageColumns are the sortable delivery columns
+var webhookDeliveryPageColumns = pageColumns[models.WebhookDelivery]{
+	"created_at": func(d *models.WebhookDelivery) interface{} { return d.CreatedAt },
+	"event_type": func(d *models.WebhookDelivery) interface{} { return d.EventType },
+}
+
+// webhookDeliveryFilterColumns maps list filters to delivery columns
+var webhookDeliveryFilterColumns = map[string]string{
+	"status":     "status",
+	"event_type": "event_type",
+}
+
+// FindDeliveryPage returns one page of the deliveries of an endpoint
+func (r *WebhookEndpointRepository) FindDeliveryPage(endpointID string, page PageRequest) (*Page[models.WebhookDelivery], error) {
+	query := r.db.Model(&models.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+	return paginate(query, page, "created_at", webhookDeliveryPageColumns, webhookDeliveryFilterColumns,
+		func(d *models.WebhookDelivery) string { return d.ID })
+}
+
+// FailPendingDeliveries marks deliveries still pending (interrupted by an API restart) as failed
+func (r *WebhookEndpointRepository) FailPendingDeliveries() (int64, error) {
+	result := r.db.Model(&models.WebhookDelivery{}).
+		Where("status = ?", models.WebhookDeliveryPending).
+		Update("status", models.WebhookDeliveryFailed)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteDeliveriesBefore deletes deliveries created before cutoff
+func (r *WebhookEndpointRepository) DeleteDeliveriesBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", cutoff).Delete(&models.WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// WorldExportRepository handles database operations for world exports
+type WorldExportRepository struct {
+	db *gorm.DB
+}
+
+// NewWorldExportRepository creates a new world export repository
+func NewWorldExportRepository(db *gorm.DB) *WorldExportRepository {
+	return &WorldExportRepository{db: db}
+}
+
+// Create creates a world export
+func (r *WorldExportRepository) Create(export *models.WorldExport) error {
+	return r.db.Create(export).Error
+}
+
+// Update updates a world export
+func (r *WorldExportRepository) Update(export *models.WorldExport) error {
+	return r.db.Save(export).Error
+}
+
+// FindByID finds a world export by ID
+func (r *WorldExportRepository) FindByID(id string) (*models.WorldExport, error) {
+	var export models.WorldExport
+	err := r.db.First(&export, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindByServer returns the exports of a server, newest first
+func (r *WorldExportRepository) FindByServer(serverID string) ([]models.WorldExport, error) {
+	var exports []models.WorldExport
+	err := r.db.Where("server_id = ?", serverID).Order("created_at DESC").Find(&exports).Error
+	return exports, err
+}
+
+// FindExpired returns ready and failed exports that expired before cutoff
+func (r *WorldExportRepository) FindExpired(cutoff time.Time) ([]models.WorldExport, error) {
+	var exports []models.WorldExport
+	err := r.db.Where("status IN ? AND expires_at < ?",
+		[]models.WorldExportStatus{models.WorldExportReady, models.WorldExportFailed}, cutoff).
+		Find(&exports).Error
+	return exports, err
+}
+
+// FailPending marks exports that were still being created (e.g. when the API stopped) as failed
+func (r *WorldExportRepository) FailPending(reason string, expiresAt time.Time) (int64, error) {
+	result := r.db.Model(&models.WorldExport{}).
+		Where("status = ?", models.WorldExportPending).
+		Updates(map[string]interface{}{
+			"status":     models.WorldExportFailed,
+			"error":      reason,
+			"expires_at": expiresAt,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,47 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+// Keys of the secrets read from the store
+const (
+	SSHPrivateKey     = "SSH_PRIVATE_KEY"     // PEM of the worker node SSH key (replaces SSH_PRIVATE_KEY_PATH)
+	HetznerCloudToken = "HETZNER_CLOUD_TOKEN" // Hetzner Cloud API token
+	InfluxDBToken     = "INFLUXDB_TOKEN"      // InfluxDB API token
+)
+
+// SSHKeyPathFromStore stands in for SSH_PRIVATE_KEY_PATH when the key comes from the store
+// (the remote Docker client is only created with a key path)
+const SSHKeyPathFromStore = "secret:" + SSHPrivateKey
+
+// Provider reads the current secrets from a secret store
+type Provider interface {
+	// Name identifies the store in logs
+	Name() string
+	// Fetch returns all secrets by key
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// New returns the provider configured by SECRETS_PROVIDER, or nil for "env" (plain env vars)
+func New(cfg *config.Config) (Provider, error) {
+	switch cfg.SecretsProvider {
+	case "", "env":
+		return nil, nil
+	case "vault":
+		if cfg.VaultAddr == "" || cfg.VaultToken == "" {
+			return nil, fmt.Errorf("SECRETS_PROVIDER=vault requires VAULT_ADDR and VAULT_TOKEN")
+		}
+		return NewVaultProvider(cfg.VaultAddr, cfg.VaultToken, cfg.VaultSecretPath), nil
+	case "sops":
+		if cfg.SOPSFile == "" {
+			return nil, fmt.Errorf("SECRETS_PROVIDER=sops requires SOPS_FILE")
+		}
+		return NewSOPSProvider(cfg.SOPSFile), nil
+	default:
+		return nil, fmt.Errorf("unknown SECRETS_PROVIDER %q (expected env, vault or sops)", cfg.SecretsProvider)
+	}
+}
@@ -0,0 +1,43 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// SOPSProvider reads the secrets from a SOPS-encrypted dotenv file. The file is decrypted with the
+// sops binary, which gets its keys (age, PGP, cloud KMS) from its usual environment.
+type SOPSProvider struct {
+	file string
+}
+
+// NewSOPSProvider creates a new SOPS provider
+func NewSOPSProvider(file string) *SOPSProvider {
+	return &SOPSProvider{file: file}
+}
+
+// Name identifies the store in logs
+func (p *SOPSProvider) Name() string {
+	return "sops"
+}
+
+// Fetch decrypts the file and parses its key/value pairs
+func (p *SOPSProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sops", "--decrypt", "--input-type", "dotenv", "--output-type", "dotenv", p.file)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("sops failed to decrypt %s: %w: %s", p.file, err, strings.TrimSpace(stderr.String()))
+	}
+
+	values, err := godotenv.Unmarshal(stdout.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted %s: %w", p.file, err)
+	}
+	return values, nil
+}
@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// VaultProvider reads the secrets from one HashiCorp Vault KV secret (version 1 or 2)
+type VaultProvider struct {
+	addr       string
+	token      string
+	path       string // API path below /v1/, e.g. "secret/data/payperplay"
+	httpClient *http.Client
+}
+
+// NewVaultProvider creates a new Vault provider
+func NewVaultProvider(addr, token, path string) *VaultProvider {
+	return &VaultProvider{
+		addr:  addr,
+		token: token,
+		path:  path,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Name identifies the store in logs
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// Fetch reads the secret's key/value pairs
+func (p *VaultProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.addr+"/v1/"+p.path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("vault returned %d for %s: %s", resp.StatusCode, p.path, string(body))
+	}
+
+	var secret struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	// KV v2 nests the values in data.data next to data.metadata
+	var kv2 struct {
+		Data     map[string]interface{} `json:"data"`
+		Metadata map[string]interface{} `json:"metadata"`
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(secret.Data, &kv2); err == nil && kv2.Metadata != nil {
+		values = kv2.Data
+	} else if err := json.Unmarshal(secret.Data, &values); err != nil {
+		return nil, fmt.Errorf("failed to decode vault secret: %w", err)
+	}
+
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		if text, ok := value.(string); ok {
+			result[key] = text
+		}
+	}
+	return result, nil
+}
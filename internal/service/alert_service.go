@@ -0,0 +1,270 @@
+package service
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// incidentHistory is how long resolved incidents stay on the status page
+const incidentHistory = 7 * 24 * time.Hour
+
+// Alertmanager webhook errors
+var (
+	ErrAlertWebhookDisabled     = errors.New("alertmanager receiver is not enabled")
+	ErrAlertWebhookUnauthorized = errors.New("invalid alertmanager webhook token")
+)
+
+// AlertmanagerWebhook is the payload Alertmanager posts to webhook receivers (version 4)
+type AlertmanagerWebhook struct {
+	Version     string              `json:"version"`
+	GroupKey    string              `json:"groupKey"`
+	Status      string              `json:"status"` // firing, resolved
+	Receiver    string              `json:"receiver"`
+	ExternalURL string              `json:"externalURL"`
+	Alerts      []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert is a single alert of an Alertmanager notification
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"` // firing, resolved
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// AlertWebhookResult summarizes a processed Alertmanager notification
+type AlertWebhookResult struct {
+	Firing            int `json:"firing"`
+	Resolved          int `json:"resolved"`
+	IncidentsOpened   int `json:"incidents_opened"`
+	IncidentsResolved int `json:"incidents_resolved"`
+}
+
+// StatusPage is the public platform status
+type StatusPage struct {
+	Status    string            `json:"status"` // operational, degraded, major_outage
+	Incidents []models.Incident `json:"incidents"`
+	Resolved  []models.Incident `json:"resolved"` // Resolved in the last 7 days
+}
+
+// AlertService receives Prometheus alerts from Alertmanager and turns them into platform
+// notifications (admin dashboard, critical alerts by email) and status page incidents
+type AlertService struct {
+	incidentRepo *repository.IncidentRepository
+	userRepo     *repository.UserRepository
+	emailService *EmailService // Optional
+	token        string        // Bearer token Alertmanager sends (empty = receiver disabled)
+}
+
+// NewAlertService creates a new alert service
+func NewAlertService(incidentRepo *repository.IncidentRepository, userRepo *repository.UserRepository, emailService *EmailService, cfg *config.Config) *AlertService {
+	return &AlertService{
+		incidentRepo: incidentRepo,
+		userRepo:     userRepo,
+		emailService: emailService,
+		token:        cfg.AlertmanagerWebhookToken,
+	}
+}
+
+// VerifyToken checks the Authorization header of an Alertmanager request ("Bearer <token>")
+func (s *AlertService) VerifyToken(authorization string) error {
+	if s.token == "" {
+		return ErrAlertWebhookDisabled
+	}
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		return ErrAlertWebhookUnauthorized
+	}
+	return nil
+}
+
+// HandleAlertmanagerWebhook processes the alerts of an Alertmanager notification
+// Alerts labelled incident="true" open a status page incident while firing and resolve it afterwards.
+func (s *AlertService) HandleAlertmanagerWebhook(webhook *AlertmanagerWebhook) (*AlertWebhookResult, error) {
+	result := &AlertWebhookResult{}
+	var critical []AlertmanagerAlert
+
+	for _, alert := range webhook.Alerts {
+		name := alert.Labels["alertname"]
+		severity := alert.Labels["severity"]
+
+		events.PublishPrometheusAlert(name, alert.Status, severity, alert.Labels["component"],
+			alert.Annotations["summary"], alert.Annotations["description"], alert.Labels)
+
+		switch alert.Status {
+		case "firing":
+			result.Firing++
+			logger.Warn("ALERTS: "+name+" firing", map[string]interface{}{
+				"severity":    severity,
+				"summary":     alert.Annotations["summary"],
+				"fingerprint": alert.Fingerprint,
+			})
+			if severity == "critical" {
+				critical = append(critical, alert)
+			}
+			if alert.Labels["incident"] == "true" {
+				opened, err := s.openIncident(alert)
+				if err != nil {
+					return result, err
+				}
+				if opened {
+					result.IncidentsOpened++
+				}
+			}
+
+		case "resolved":
+			result.Resolved++
+			logger.Info("ALERTS: "+name+" resolved", map[string]interface{}{
+				"fingerprint": alert.Fingerprint,
+			})
+			resolved, err := s.resolveIncident(alert)
+			if err != nil {
+				return result, err
+			}
+			if resolved {
+				result.IncidentsResolved++
+			}
+		}
+	}
+
+	if len(critical) > 0 {
+		go s.emailAdmins(critical)
+	}
+
+	return result, nil
+}
+
+// GetStatusPage returns the open incidents and those resolved in the last 7 days
+func (s *AlertService) GetStatusPage() (*StatusPage, error) {
+	open, err := s.incidentRepo.FindOpen()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load incidents: %w", err)
+	}
+	resolved, err := s.incidentRepo.FindResolvedSince(time.Now().Add(-incidentHistory))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load resolved incidents: %w", err)
+	}
+
+	status := "operational"
+	for _, incident := range open {
+		if incident.Severity == "critical" {
+			status = "major_outage"
+			break
+		}
+		status = "degraded"
+	}
+
+	return &StatusPage{Status: status, Incidents: open, Resolved: resolved}, nil
+}
+
+// openIncident creates the incident of a firing alert unless it is already open
+func (s *AlertService) openIncident(alert AlertmanagerAlert) (bool, error) {
+	existing, err := s.incidentRepo.FindOpenByFingerprint(alert.Fingerprint)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up incident: %w", err)
+	}
+	if existing != nil {
+		return false, nil // Repeated notification
+	}
+
+	title := alert.Annotations["summary"]
+	if title == "" {
+		title = alert.Labels["alertname"]
+	}
+	startedAt := alert.StartsAt
+	if startedAt.IsZero() {
+		startedAt = time.Now()
+	}
+
+	incident := &models.Incident{
+		Fingerprint: alert.Fingerprint,
+		AlertName:   alert.Labels["alertname"],
+		Title:       title,
+		Description: alert.Annotations["description"],
+		Severity:    alert.Labels["severity"],
+		Component:   alert.Labels["component"],
+		Status:      models.IncidentStatusInvestigating,
+		StartedAt:   startedAt,
+	}
+	if err := s.incidentRepo.Create(incident); err != nil {
+		return false, fmt.Errorf("failed to create incident: %w", err)
+	}
+
+	logger.Warn("ALERTS: Status page incident opened", map[string]interface{}{
+		"incident_id": incident.ID,
+		"alert":       incident.AlertName,
+		"title":       incident.Title,
+	})
+	return true, nil
+}
+
+// resolveIncident resolves the open incident of a resolved alert
+func (s *AlertService) resolveIncident(alert AlertmanagerAlert) (bool, error) {
+	incident, err := s.incidentRepo.FindOpenByFingerprint(alert.Fingerprint)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up incident: %w", err)
+	}
+	if incident == nil {
+		return false, nil
+	}
+
+	resolvedAt := alert.EndsAt
+	if resolvedAt.IsZero() || resolvedAt.After(time.Now()) {
+		resolvedAt = time.Now()
+	}
+	incident.Status = models.IncidentStatusResolved
+	incident.ResolvedAt = &resolvedAt
+	if err := s.incidentRepo.Update(incident); err != nil {
+		return false, fmt.Errorf("failed to resolve incident: %w", err)
+	}
+
+	logger.Info("ALERTS: Status page incident resolved", map[string]interface{}{
+		"incident_id": incident.ID,
+		"alert":       incident.AlertName,
+		"duration":    resolvedAt.Sub(incident.StartedAt).Round(time.Second).String(),
+	})
+	return true, nil
+}
+
+// emailAdmins sends one email with the firing critical alerts to every active platform admin
+func (s *AlertService) emailAdmins(alerts []AlertmanagerAlert) {
+	if s.emailService == nil || s.userRepo == nil {
+		return
+	}
+
+	admins, err := s.userRepo.FindAdmins()
+	if err != nil {
+		logger.Error("ALERTS: Failed to load admins for alert", err, nil)
+		return
+	}
+
+	subject := "Critical alert: " + alerts[0].Labels["alertname"]
+	if len(alerts) > 1 {
+		subject = fmt.Sprintf("%d critical alerts firing", len(alerts))
+	}
+
+	var message strings.Builder
+	for _, alert := range alerts {
+		fmt.Fprintf(&message, "%s: %s\n%s\n\n", alert.Labels["alertname"], alert.Annotations["summary"], alert.Annotations["description"])
+	}
+
+	for _, admin := range admins {
+		if err := s.emailService.SendAdminAlert(admin.Email, subject, message.String()); err != nil {
+			logger.Error("ALERTS: Failed to send alert email", err, map[string]interface{}{
+				"admin_id": admin.ID,
+			})
+		}
+	}
+}
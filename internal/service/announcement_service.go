@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	// announcementInterval is how often scheduled announcements are checked
+	announcementInterval = 30 * time.Second
+	// announcementParallelism bounds the RCON commands sent at the same time
+	announcementParallelism = 10
+	// maxAnnouncementLength bounds a rendered message (RCON packets are limited to about 1.4 KB)
+	maxAnnouncementLength = 500
+)
+
+// Announcement errors
+var (
+	ErrAnnouncementInvalid      = errors.New("invalid announcement")
+	ErrAnnouncementNotFound     = errors.New("announcement not found")
+	ErrAnnouncementNotScheduled = errors.New("announcement is no longer scheduled (sent or cancelled)")
+)
+
+// AnnouncementData is the data of an announcement template, e.g.
+// "{{.ServerName}} restarts for maintenance in {{.MinutesLeft}} minutes ({{.MaintenanceAt}})"
+type AnnouncementData struct {
+	ServerID      string
+	ServerName    string
+	MaintenanceAt string // "2025-01-31 22:00 UTC", empty without maintenance_at
+	MinutesLeft   int    // Minutes until maintenance_at when sent, 0 without it
+}
+
+// AnnouncementService broadcasts admin announcements to the chat of running servers via RCON
+// Announcements are stored, so scheduled ones survive restarts; claiming one before sending keeps
+// API replicas from sending it twice.
+type AnnouncementService struct {
+	db             *gorm.DB
+	serverRepo     *repository.ServerRepository
+	consoleService *ConsoleService
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewAnnouncementService creates a new announcement service
+func NewAnnouncementService(db *gorm.DB, serverRepo *repository.ServerRepository, consoleService *ConsoleService) *AnnouncementService {
+	return &AnnouncementService{
+		db:             db,
+		serverRepo:     serverRepo,
+		consoleService: consoleService,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start sends scheduled announcements when they are due
+func (s *AnnouncementService) Start() {
+	go func() {
+		ticker := time.NewTicker(announcementInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.sendDue()
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	logger.Info("ANNOUNCE: Announcement scheduler started", nil)
+}
+
+// Stop stops the scheduler
+func (s *AnnouncementService) Stop() {
+	s.stopOnce.Do(func() { close(s.stopChan) })
+}
+
+// Create validates and stores an announcement; without SendAt (or a past one) it is sent right away
+func (s *AnnouncementService) Create(announcement *models.Announcement) error {
+	if err := validateAnnouncement(announcement); err != nil {
+		return err
+	}
+	now := time.Now()
+	sendNow := announcement.SendAt.IsZero() || !announcement.SendAt.After(now)
+	if sendNow {
+		announcement.SendAt = now
+	}
+	announcement.Status = models.AnnouncementScheduled
+	if err := s.db.Create(announcement).Error; err != nil {
+		return fmt.Errorf("failed to save announcement: %w", err)
+	}
+
+	logger.Info("ANNOUNCE: Announcement created", map[string]interface{}{
+		"announcement_id": announcement.ID,
+		"send_at":         announcement.SendAt,
+		"created_by":      announcement.CreatedBy,
+	})
+	if sendNow {
+		go s.send(announcement.ID)
+	}
+	return nil
+}
+
+// List returns the most recent announcements, newest first (without delivery details)
+func (s *AnnouncementService) List(limit int) ([]models.Announcement, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	var announcements []models.Announcement
+	err := s.db.Omit("deliveries").Order("send_at DESC").Limit(limit).Find(&announcements).Error
+	return announcements, err
+}
+
+// Get returns an announcement with its delivery report
+func (s *AnnouncementService) Get(id uint) (*models.Announcement, error) {
+	var announcement models.Announcement
+	if err := s.db.First(&announcement, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAnnouncementNotFound
+		}
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// Cancel cancels a scheduled announcement
+func (s *AnnouncementService) Cancel(id uint) (*models.Announcement, error) {
+	announcement, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	result := s.db.Model(&models.Announcement{}).
+		Where("id = ? AND status = ?", id, models.AnnouncementScheduled).
+		Update("status", models.AnnouncementCancelled)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrAnnouncementNotScheduled
+	}
+	announcement.Status = models.AnnouncementCancelled
+	return announcement, nil
+}
+
+// Targets returns the running servers matching a filter
+func (s *AnnouncementService) Targets(filter models.AnnouncementFilter) ([]models.MinecraftServer, error) {
+	running, err := s.serverRepo.FindByStatus(string(models.StatusRunning))
+	if err != nil {
+		return nil, err
+	}
+	var targets []models.MinecraftServer
+	for _, server := range running {
+		if announcementMatches(filter, &server) {
+			targets = append(targets, server)
+		}
+	}
+	return targets, nil
+}
+
+// Preview renders an announcement for a server without sending it
+func (s *AnnouncementService) Preview(announcement *models.Announcement, server *models.MinecraftServer) (string, error) {
+	if err := validateAnnouncement(announcement); err != nil {
+		return "", err
+	}
+	sendAt := announcement.SendAt
+	if sendAt.IsZero() {
+		sendAt = time.Now()
+	}
+	return renderAnnouncement(announcement, server, sendAt)
+}
+
+// sendDue sends the scheduled announcements whose time has come
+func (s *AnnouncementService) sendDue() {
+	var due []models.Announcement
+	if err := s.db.Select("id").Where("status = ? AND send_at <= ?", models.AnnouncementScheduled, time.Now()).
+		Find(&due).Error; err != nil {
+		logger.Error("ANNOUNCE: Failed to load due announcements", err, nil)
+		return
+	}
+	for _, announcement := range due {
+		s.send(announcement.ID)
+	}
+}
+
+// send claims an announcement and delivers it to all matching running servers
+func (s *AnnouncementService) send(id uint) {
+	claim := s.db.Model(&models.Announcement{}).
+		Where("id = ? AND status = ?", id, models.AnnouncementScheduled).
+		Update("status", models.AnnouncementSending)
+	if claim.Error != nil || claim.RowsAffected == 0 {
+		return // Cancelled or claimed by another replica
+	}
+	announcement, err := s.Get(id)
+	if err != nil {
+		return
+	}
+
+	targets, err := s.Targets(announcement.Filter)
+	if err != nil {
+		logger.Error("ANNOUNCE: Failed to load running servers", err, map[string]interface{}{
+			"announcement_id": id,
+		})
+	}
+
+	now := time.Now()
+	deliveries := make([]models.AnnouncementDelivery, len(targets))
+	sem := make(chan struct{}, announcementParallelism)
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			deliveries[i] = s.deliver(announcement, &targets[i], now)
+		}(i)
+	}
+	wg.Wait()
+
+	announcement.Deliveries = deliveries
+	announcement.Targeted = len(deliveries)
+	announcement.Delivered, announcement.Failed, announcement.PlayersReached = 0, 0, 0
+	for _, delivery := range deliveries {
+		if delivery.Delivered {
+			announcement.Delivered++
+			announcement.PlayersReached += delivery.Players
+		} else {
+			announcement.Failed++
+		}
+	}
+	sentAt := time.Now()
+	announcement.SentAt = &sentAt
+	announcement.Status = models.AnnouncementSent
+	if err := s.db.Save(announcement).Error; err != nil {
+		logger.Error("ANNOUNCE: Failed to save delivery report", err, map[string]interface{}{
+			"announcement_id": id,
+		})
+	}
+
+	logger.Info("ANNOUNCE: Announcement sent", map[string]interface{}{
+		"announcement_id": id,
+		"targeted":        announcement.Targeted,
+		"delivered":       announcement.Delivered,
+		"failed":          announcement.Failed,
+		"players_reached": announcement.PlayersReached,
+	})
+}
+
+// deliver sends an announcement to the chat of one server
+func (s *AnnouncementService) deliver(announcement *models.Announcement, server *models.MinecraftServer, now time.Time) models.AnnouncementDelivery {
+	delivery := models.AnnouncementDelivery{
+		ServerID:   server.ID,
+		ServerName: server.Name,
+		NodeID:     server.NodeID,
+		Players:    server.CurrentPlayerCount,
+	}
+	message, err := renderAnnouncement(announcement, server, now)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		_, err = s.consoleService.ExecuteCommand(ctx, server.ID, announcementCommand(message))
+		cancel()
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	delivery.Delivered = true
+	return delivery
+}
+
+// validateAnnouncement checks the message template and the filter of an announcement
+func validateAnnouncement(announcement *models.Announcement) error {
+	if strings.TrimSpace(announcement.Message) == "" {
+		return fmt.Errorf("%w: message is required", ErrAnnouncementInvalid)
+	}
+	for _, plan := range announcement.Filter.Plans {
+		if !isKnownPlan(plan) {
+			return fmt.Errorf("%w: unknown plan %q", ErrAnnouncementInvalid, plan)
+		}
+	}
+	// Render against a sample server so template errors surface on creation, not per server
+	sample := &models.MinecraftServer{ID: "00000000", Name: strings.Repeat("x", 32)}
+	if _, err := renderAnnouncement(announcement, sample, time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// renderAnnouncement renders the message of an announcement for a server
+func renderAnnouncement(announcement *models.Announcement, server *models.MinecraftServer, now time.Time) (string, error) {
+	tmpl, err := template.New("announcement").Option("missingkey=error").Parse(announcement.Message)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrAnnouncementInvalid, err)
+	}
+	data := AnnouncementData{ServerID: server.ID, ServerName: server.Name}
+	if announcement.MaintenanceAt != nil {
+		data.MaintenanceAt = announcement.MaintenanceAt.UTC().Format("2006-01-02 15:04 UTC")
+		if left := announcement.MaintenanceAt.Sub(now); left > 0 {
+			data.MinutesLeft = int((left + time.Minute - 1) / time.Minute)
+		}
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrAnnouncementInvalid, err)
+	}
+	message := strings.TrimSpace(out.String())
+	if len(message) > maxAnnouncementLength {
+		return "", fmt.Errorf("%w: message is longer than %d characters", ErrAnnouncementInvalid, maxAnnouncementLength)
+	}
+	return message, nil
+}
+
+// announcementMatches reports whether a server is targeted by a filter
+func announcementMatches(filter models.AnnouncementFilter, server *models.MinecraftServer) bool {
+	contains := func(values []string, value string) bool {
+		if len(values) == 0 {
+			return true
+		}
+		for _, v := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+	return contains(filter.ServerIDs, server.ID) && contains(filter.NodeIDs, server.NodeID) && contains(filter.Plans, planOf(server))
+}
+
+// announcementCommand is the tellraw command showing a message to all players of a server
+func announcementCommand(message string) string {
+	component, _ := json.Marshal([]interface{}{
+		map[string]interface{}{"text": "[PayPerPlay] ", "color": "gold", "bold": true},
+		map[string]interface{}{"text": message, "color": "yellow", "bold": false},
+	})
+	return "tellraw @a " + string(component)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestRenderAnnouncement(t *testing.T) {
+	maintenanceAt := time.Date(2025, 1, 31, 22, 0, 0, 0, time.UTC)
+	announcement := &models.Announcement{
+		Message:       "{{.ServerName}} goes down for maintenance in {{.MinutesLeft}} minutes ({{.MaintenanceAt}})",
+		MaintenanceAt: &maintenanceAt,
+	}
+	server := &models.MinecraftServer{ID: "srv-1", Name: "Survival"}
+
+	got, err := renderAnnouncement(announcement, server, maintenanceAt.Add(-14*time.Minute-30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Survival goes down for maintenance in 15 minutes (2025-01-31 22:00 UTC)"; got != want {
+		t.Errorf("renderAnnouncement() = %q, want %q", got, want)
+	}
+
+	invalid := []string{"{{.Unknown}}", "{{.ServerName", strings.Repeat("x", maxAnnouncementLength+1)}
+	for _, message := range invalid {
+		if _, err := renderAnnouncement(&models.Announcement{Message: message}, server, maintenanceAt); !errors.Is(err, ErrAnnouncementInvalid) {
+			t.Errorf("renderAnnouncement(%.20q) error = %v, want ErrAnnouncementInvalid", message, err)
+		}
+	}
+}
+
+func TestAnnouncementMatches(t *testing.T) {
+	server := &models.MinecraftServer{ID: "srv-1", NodeID: "node-1", Plan: models.PlanBalanced}
+	tests := []struct {
+		name   string
+		filter models.AnnouncementFilter
+		want   bool
+	}{
+		{"no filter", models.AnnouncementFilter{}, true},
+		{"node", models.AnnouncementFilter{NodeIDs: []string{"node-2", "node-1"}}, true},
+		{"other node", models.AnnouncementFilter{NodeIDs: []string{"node-2"}}, false},
+		{"plan and server", models.AnnouncementFilter{Plans: []string{models.PlanBalanced}, ServerIDs: []string{"srv-1"}}, true},
+		{"other plan", models.AnnouncementFilter{Plans: []string{models.PlanReserved}}, false},
+	}
+	for _, tt := range tests {
+		if got := announcementMatches(tt.filter, server); got != tt.want {
+			t.Errorf("%s: announcementMatches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAnnouncementCommand(t *testing.T) {
+	command := announcementCommand(`Back at 22:15 "UTC"`)
+	if !strings.HasPrefix(command, "tellraw @a ") {
+		t.Fatalf("command = %q", command)
+	}
+	var components []map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(command, "tellraw @a ")), &components); err != nil {
+		t.Fatalf("invalid text component: %v", err)
+	}
+	if len(components) != 2 || components[1]["text"] != `Back at 22:15 "UTC"` {
+		t.Errorf("components = %v", components)
+	}
+}
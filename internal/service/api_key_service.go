@@ -0,0 +1,207 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/ratelimit"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// apiKeyTouchInterval throttles last-used updates (one write per key and interval)
+const apiKeyTouchInterval = time.Minute
+
+// APIKeyService manages personal and organization API keys and authenticates requests made with them
+type APIKeyService struct {
+	keyRepo    *repository.APIKeyRepository
+	orgService *OrganizationService
+	cfg        *config.Config
+
+	// Per-key request counters (RateLimit per minute, burst = RateLimit)
+	limits ratelimit.Store
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(keyRepo *repository.APIKeyRepository, orgService *OrganizationService, cfg *config.Config) *APIKeyService {
+	return &APIKeyService{
+		keyRepo:    keyRepo,
+		orgService: orgService,
+		cfg:        cfg,
+		limits:     ratelimit.NewMemoryStore(),
+	}
+}
+
+// SetRateLimitStore sets where per-key request counters are kept (Redis shares them between API instances)
+func (s *APIKeyService) SetRateLimitStore(store ratelimit.Store) {
+	s.limits = store
+}
+
+// CreateKey creates an API key for userID (orgID empty) or for an organization (requires the admin role)
+// rateLimit 0 uses the default; ttl 0 = the key never expires. The key is only returned here.
+func (s *APIKeyService) CreateKey(userID, orgID, name string, scopes []models.APIKeyScope, rateLimit int, ttl time.Duration) (*models.APIKey, string, error) {
+	if orgID != "" {
+		if _, err := s.orgService.requireRole(orgID, userID, models.OrgRoleAdmin); err != nil {
+			return nil, "", err
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, "", models.ErrAPIKeyInvalidScope
+	}
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, "", models.ErrAPIKeyInvalidScope
+		}
+	}
+
+	if rateLimit <= 0 {
+		rateLimit = s.cfg.APIKeyDefaultRateLimit
+	}
+	if s.cfg.APIKeyMaxRateLimit > 0 && rateLimit > s.cfg.APIKeyMaxRateLimit {
+		return nil, "", models.ErrAPIKeyInvalidRateLimit
+	}
+
+	if s.cfg.APIKeyMaxPerOwner > 0 {
+		count, err := s.keyRepo.CountActive(userID, orgID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to count API keys: %w", err)
+		}
+		if count >= int64(s.cfg.APIKeyMaxPerOwner) {
+			return nil, "", models.ErrAPIKeyLimit
+		}
+	}
+
+	token, err := generateAccessToken()
+	if err != nil {
+		return nil, "", err
+	}
+	rawKey := models.APIKeyPrefix + token
+
+	key := &models.APIKey{
+		UserID:         userID,
+		OrganizationID: orgID,
+		Name:           name,
+		Prefix:         rawKey[:len(models.APIKeyPrefix)+8],
+		KeyHash:        hashAPIKey(rawKey),
+		RateLimit:      rateLimit,
+	}
+	key.SetScopes(scopes)
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.keyRepo.Create(key); err != nil {
+		return nil, "", fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	logger.Info("API-KEYS: API key created", map[string]interface{}{
+		"key_id":          key.ID,
+		"user_id":         userID,
+		"organization_id": orgID,
+		"scopes":          key.Scopes,
+		"rate_limit":      rateLimit,
+	})
+	return key, rawKey, nil
+}
+
+// ListKeys returns the personal keys of userID, or the keys of an organization (requires the admin role)
+func (s *APIKeyService) ListKeys(userID, orgID string) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	var err error
+	if orgID == "" {
+		keys, err = s.keyRepo.FindPersonal(userID)
+	} else {
+		if _, err := s.orgService.requireRole(orgID, userID, models.OrgRoleAdmin); err != nil {
+			return nil, err
+		}
+		keys, err = s.keyRepo.FindByOrganization(orgID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range keys {
+		keys[i].LoadScopes()
+	}
+	return keys, nil
+}
+
+// RevokeKey revokes a key; personal keys by their user, organization keys by organization admins
+func (s *APIKeyService) RevokeKey(userID, keyID string) error {
+	key, err := s.keyRepo.FindByID(keyID)
+	if err != nil {
+		return models.ErrAPIKeyNotFound
+	}
+
+	if key.OrganizationID == "" {
+		if key.UserID != userID {
+			return models.ErrAPIKeyNotFound
+		}
+	} else if _, err := s.orgService.requireRole(key.OrganizationID, userID, models.OrgRoleAdmin); err != nil {
+		return models.ErrAPIKeyNotFound
+	}
+
+	if err := s.keyRepo.Revoke(key.ID); err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	s.limits.Reset(apiKeyLimitKey(key.ID))
+
+	logger.Info("API-KEYS: API key revoked", map[string]interface{}{
+		"key_id":     key.ID,
+		"revoked_by": userID,
+	})
+	return nil
+}
+
+// AuthenticateAPIKey resolves a raw key to an active key and applies its rate limit
+// Organization keys stop working when their creator is no longer a member.
+func (s *APIKeyService) AuthenticateAPIKey(rawKey, ip string) (*models.APIKey, error) {
+	key, err := s.keyRepo.FindByHash(hashAPIKey(rawKey))
+	if err != nil {
+		return nil, models.ErrAPIKeyInvalid
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, models.ErrAPIKeyInvalid
+	}
+	if key.OrganizationID != "" {
+		if _, err := s.orgService.requireRole(key.OrganizationID, key.UserID, models.OrgRoleViewer); err != nil {
+			return nil, models.ErrAPIKeyInvalid
+		}
+	}
+
+	limit := ratelimit.Limit{PerMinute: key.RateLimit, Burst: key.RateLimit}
+	if result, err := s.limits.Take(apiKeyLimitKey(key.ID), limit, now); err == nil && !result.Allowed {
+		return nil, &models.APIKeyRateLimitError{RetryAfter: result.RetryAfter}
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval || key.LastUsedIP != ip {
+		if err := s.keyRepo.TouchLastUsed(key.ID, ip, now); err != nil {
+			logger.Warn("API-KEYS: Failed to record key usage", map[string]interface{}{
+				"key_id": key.ID,
+				"error":  err.Error(),
+			})
+		}
+	}
+
+	key.LoadScopes()
+	return key, nil
+}
+
+// apiKeyLimitKey returns the rate limit counter key of an API key
+func apiKeyLimitKey(keyID string) string {
+	return "apikey:" + keyID
+}
+
+// hashAPIKey returns the hex SHA-256 of a raw key (keys are random, so no salt is needed)
+func hashAPIKey(rawKey string) string {
+	hash := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,537 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// Apply errors
+var (
+	ErrManifestInvalid = errors.New("invalid manifest")
+	ErrApplyInProgress = errors.New("another apply of this account is still running")
+)
+
+// ServerManifest is the desired state of an account's servers (POST /api/apply)
+type ServerManifest struct {
+	Servers []DeclaredServer `json:"servers"`
+	Prune   bool             `json:"prune"` // Delete managed servers that are no longer declared
+}
+
+// DeclaredServer is one server of a manifest, identified by its name
+type DeclaredServer struct {
+	Name             string                 `json:"name"`
+	ServerType       string                 `json:"server_type"`
+	MinecraftVersion string                 `json:"minecraft_version"`
+	RAMMb            int                    `json:"ram_mb"`
+	Config           map[string]interface{} `json:"config"`  // models.ServerSettings fields; unset ones keep their value (defaults on create)
+	Plugins          []string               `json:"plugins"` // Marketplace plugin slugs; omitted = installed plugins are left alone
+}
+
+// ApplyAction is what an apply does to a declared server
+type ApplyAction string
+
+const (
+	ApplyCreate    ApplyAction = "create"
+	ApplyUpdate    ApplyAction = "update"
+	ApplyDelete    ApplyAction = "delete"
+	ApplyUnchanged ApplyAction = "unchanged"
+)
+
+// ApplyChange is the planned (dry run) or applied change of one server
+type ApplyChange struct {
+	Name           string                 `json:"name"`
+	Action         ApplyAction            `json:"action"`
+	ServerID       string                 `json:"server_id,omitempty"`
+	Adopt          bool                   `json:"adopt,omitempty"`           // An existing server of that name becomes managed by the manifest
+	Spec           *models.TemplateSpec   `json:"spec,omitempty"`            // Created servers
+	Config         map[string]interface{} `json:"config,omitempty"`          // Changed settings (keys of POST /api/servers/:id/config)
+	InstallPlugins []string               `json:"install_plugins,omitempty"` // Marketplace plugin slugs
+	RemovePlugins  []string               `json:"remove_plugins,omitempty"`  // Marketplace plugin slugs
+
+	// Outcome (not set for dry runs)
+	Status    string     `json:"status,omitempty"` // "applied" or "failed"
+	Error     string     `json:"error,omitempty"`
+	Operation *Operation `json:"operation,omitempty"` // Setup of created servers with plugins
+
+	declarationID   string   // Deleted servers
+	removePluginIDs []string // Plugin IDs of RemovePlugins
+}
+
+// ApplyResult lists the changes of an apply in the order they are made: declared servers in manifest
+// order, then pruned ones
+type ApplyResult struct {
+	DryRun  bool          `json:"dry_run"`
+	Changes []ApplyChange `json:"changes"`
+	Failed  int           `json:"failed"`
+}
+
+// HasDeletes reports whether the apply deletes servers
+func (r *ApplyResult) HasDeletes() bool {
+	for _, change := range r.Changes {
+		if change.Action == ApplyDelete {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredServerState is the current state of a declared server
+type declaredServerState struct {
+	server  *models.MinecraftServer // nil = not created yet
+	managed bool                    // Linked by a declaration (false = adopted by name)
+	plugins map[string]string       // Installed marketplace plugins: slug -> plugin ID
+}
+
+// ApplyService reconciles an account's servers with a declarative manifest, so larger customers can
+// manage them from Git (CI pipelines, a Terraform provider). Changes go through the regular
+// services: servers are created like template servers, settings change via ConfigService (with its
+// audit trail and pre-change backups) and plugins via the plugin marketplace.
+type ApplyService struct {
+	declarations *repository.ServerDeclarationRepository
+	serverRepo   *repository.ServerRepository
+	minecraft    *MinecraftService
+	clone        *CloneService
+	config       *ConfigService
+	plugins      *PluginManagerService
+
+	mu      sync.Mutex
+	running map[string]bool // User IDs with an apply in progress
+}
+
+// NewApplyService creates a new apply service
+func NewApplyService(
+	declarations *repository.ServerDeclarationRepository,
+	serverRepo *repository.ServerRepository,
+	minecraft *MinecraftService,
+	clone *CloneService,
+	config *ConfigService,
+	plugins *PluginManagerService,
+) *ApplyService {
+	return &ApplyService{
+		declarations: declarations,
+		serverRepo:   serverRepo,
+		minecraft:    minecraft,
+		clone:        clone,
+		config:       config,
+		plugins:      plugins,
+		running:      make(map[string]bool),
+	}
+}
+
+// ListDeclarations returns the servers managed by the manifest of userID
+func (s *ApplyService) ListDeclarations(userID string) ([]models.ServerDeclaration, error) {
+	return s.declarations.FindByUser(userID)
+}
+
+// Plan returns the changes Apply would make, without making them
+func (s *ApplyService) Plan(userID string, manifest *ServerManifest) (*ApplyResult, error) {
+	changes, _, err := s.plan(userID, manifest)
+	if err != nil {
+		return nil, err
+	}
+	return &ApplyResult{DryRun: true, Changes: changes}, nil
+}
+
+// Apply reconciles the servers of userID with the manifest
+// A failed change doesn't stop the others; it is reported with status "failed".
+func (s *ApplyService) Apply(userID string, manifest *ServerManifest) (*ApplyResult, error) {
+	s.mu.Lock()
+	if s.running[userID] {
+		s.mu.Unlock()
+		return nil, ErrApplyInProgress
+	}
+	s.running[userID] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, userID)
+		s.mu.Unlock()
+	}()
+
+	changes, stale, err := s.plan(userID, manifest)
+	if err != nil {
+		return nil, err
+	}
+	// Declarations of servers deleted outside the manifest manage nothing anymore
+	for _, id := range stale {
+		if err := s.declarations.Delete(id); err != nil {
+			return nil, fmt.Errorf("failed to remove stale declaration: %w", err)
+		}
+	}
+
+	result := &ApplyResult{Changes: changes}
+	for i := range result.Changes {
+		change := &result.Changes[i]
+		if change.Action == ApplyUnchanged {
+			continue
+		}
+		change.Status = "applied"
+		if err := s.execute(userID, change); err != nil {
+			change.Status, change.Error = "failed", err.Error()
+			result.Failed++
+			logger.Warn("APPLY: Change failed", map[string]interface{}{
+				"user_id":   userID,
+				"server":    change.Name,
+				"server_id": change.ServerID,
+				"action":    string(change.Action),
+				"error":     err.Error(),
+			})
+		}
+	}
+
+	logger.Info("APPLY: Manifest applied", map[string]interface{}{
+		"user_id": userID,
+		"servers": len(manifest.Servers),
+		"changes": len(result.Changes),
+		"failed":  result.Failed,
+	})
+	return result, nil
+}
+
+// plan compares the manifest with the servers of userID
+// Also returns the IDs of declarations whose server no longer exists.
+func (s *ApplyService) plan(userID string, manifest *ServerManifest) ([]ApplyChange, []string, error) {
+	if err := validateManifest(manifest); err != nil {
+		return nil, nil, err
+	}
+
+	declarations, err := s.declarations.FindByUser(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load declarations: %w", err)
+	}
+	servers, err := s.serverRepo.FindByOwner(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load servers: %w", err)
+	}
+
+	byID := make(map[string]*models.MinecraftServer, len(servers))
+	for i := range servers {
+		byID[servers[i].ID] = &servers[i]
+	}
+	declared := make(map[string]models.ServerDeclaration, len(declarations))
+	var stale []string
+	for _, declaration := range declarations {
+		if byID[declaration.ServerID] == nil {
+			stale = append(stale, declaration.ID)
+			continue
+		}
+		declared[declaration.Name] = declaration
+	}
+	managedIDs := make(map[string]bool, len(declared))
+	for _, declaration := range declared {
+		managedIDs[declaration.ServerID] = true
+	}
+
+	changes := make([]ApplyChange, 0, len(manifest.Servers))
+	for _, server := range manifest.Servers {
+		state := declaredServerState{}
+		if declaration, ok := declared[server.Name]; ok {
+			state.server, state.managed = byID[declaration.ServerID], true
+		} else {
+			// Adopt an existing server of the same name, unless the name is ambiguous
+			for i := range servers {
+				if servers[i].Name != server.Name || managedIDs[servers[i].ID] {
+					continue
+				}
+				if state.server != nil {
+					return nil, nil, fmt.Errorf("%w: several servers are named %q, rename all but one first", ErrManifestInvalid, server.Name)
+				}
+				state.server = &servers[i]
+			}
+		}
+		if state.server != nil {
+			if state.plugins, err = s.installedPlugins(state.server.ID); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		change, err := planDeclaredServer(server, state)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	if manifest.Prune {
+		inManifest := make(map[string]bool, len(manifest.Servers))
+		for _, server := range manifest.Servers {
+			inManifest[server.Name] = true
+		}
+		for _, declaration := range declarations {
+			if _, ok := declared[declaration.Name]; !ok || inManifest[declaration.Name] {
+				continue
+			}
+			changes = append(changes, ApplyChange{
+				Name:          declaration.Name,
+				Action:        ApplyDelete,
+				ServerID:      declaration.ServerID,
+				declarationID: declaration.ID,
+			})
+		}
+	}
+	return changes, stale, nil
+}
+
+// installedPlugins returns the marketplace plugins of a server by slug
+func (s *ApplyService) installedPlugins(serverID string) (map[string]string, error) {
+	installed, err := s.plugins.ListInstalledPlugins(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plugins of %s: %w", serverID, err)
+	}
+	plugins := make(map[string]string, len(installed))
+	for _, plugin := range installed {
+		if plugin.Plugin != nil {
+			plugins[plugin.Plugin.Slug] = plugin.PluginID
+		}
+	}
+	return plugins, nil
+}
+
+// execute makes a planned change
+func (s *ApplyService) execute(userID string, change *ApplyChange) error {
+	switch change.Action {
+	case ApplyCreate:
+		server, op, err := s.clone.CreateFromSpec(userID, change.Name, change.Spec)
+		if err != nil {
+			return err
+		}
+		change.ServerID, change.Operation = server.ID, op
+		return s.declare(userID, change)
+
+	case ApplyUpdate:
+		if change.Adopt {
+			if err := s.declare(userID, change); err != nil {
+				return err
+			}
+		}
+		// Settings first: a new version or server type changes which plugin versions fit
+		if len(change.Config) > 0 {
+			if _, err := s.config.ApplyConfigChanges(ConfigChangeRequest{
+				ServerID: change.ServerID,
+				UserID:   userID,
+				Changes:  change.Config,
+			}); err != nil {
+				return err
+			}
+		}
+		for _, slug := range change.InstallPlugins {
+			if err := s.plugins.InstallPlugin(change.ServerID, slug, "", false); err != nil {
+				return fmt.Errorf("failed to install plugin %s: %w", slug, err)
+			}
+		}
+		for i, pluginID := range change.removePluginIDs {
+			if err := s.plugins.UninstallPlugin(change.ServerID, pluginID); err != nil {
+				return fmt.Errorf("failed to remove plugin %s: %w", change.RemovePlugins[i], err)
+			}
+		}
+		return nil
+
+	case ApplyDelete:
+		if err := s.minecraft.DeleteServer(change.ServerID); err != nil {
+			return err
+		}
+		return s.declarations.Delete(change.declarationID)
+	}
+	return nil
+}
+
+// declare makes the server of a change managed by the manifest
+func (s *ApplyService) declare(userID string, change *ApplyChange) error {
+	if err := s.declarations.Create(&models.ServerDeclaration{
+		UserID:   userID,
+		Name:     change.Name,
+		ServerID: change.ServerID,
+	}); err != nil {
+		return fmt.Errorf("failed to save declaration: %w", err)
+	}
+	return nil
+}
+
+// validateManifest checks that every declared server has a unique name, a type, version and RAM
+func validateManifest(manifest *ServerManifest) error {
+	names := make(map[string]bool, len(manifest.Servers))
+	for _, server := range manifest.Servers {
+		if server.Name == "" {
+			return fmt.Errorf("%w: every server needs a name", ErrManifestInvalid)
+		}
+		if names[server.Name] {
+			return fmt.Errorf("%w: server %q is declared twice", ErrManifestInvalid, server.Name)
+		}
+		names[server.Name] = true
+
+		switch models.ServerType(server.ServerType) {
+		case models.ServerTypePaper, models.ServerTypeSpigot, models.ServerTypeForge,
+			models.ServerTypeFabric, models.ServerTypeVanilla, models.ServerTypePurpur:
+		default:
+			return fmt.Errorf("%w: %s: invalid server type %q", ErrManifestInvalid, server.Name, server.ServerType)
+		}
+		if !templateVersionRegex.MatchString(server.MinecraftVersion) {
+			return fmt.Errorf("%w: %s: invalid Minecraft version %q", ErrManifestInvalid, server.Name, server.MinecraftVersion)
+		}
+		if server.RAMMb <= 0 {
+			return fmt.Errorf("%w: %s: ram_mb is required", ErrManifestInvalid, server.Name)
+		}
+		for _, slug := range server.Plugins {
+			if strings.TrimSpace(slug) == "" {
+				return fmt.Errorf("%w: %s: empty plugin slug", ErrManifestInvalid, server.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// planDeclaredServer returns the change that brings a server from its current state to the declared one
+func planDeclaredServer(declared DeclaredServer, state declaredServerState) (ApplyChange, error) {
+	change := ApplyChange{Name: declared.Name, Action: ApplyUnchanged}
+
+	base := models.DefaultServerSettings()
+	if state.server != nil {
+		base = models.SettingsOf(state.server)
+	}
+	settings, err := mergeServerSettings(base, declared.Config)
+	if err != nil {
+		return change, fmt.Errorf("%w: %s: %v", ErrManifestInvalid, declared.Name, err)
+	}
+	if err := settings.Validate(); err != nil {
+		return change, fmt.Errorf("%w: %s: %v", ErrManifestInvalid, declared.Name, err)
+	}
+	plugins := normalizePluginSlugs(declared.Plugins)
+
+	if state.server == nil {
+		change.Action = ApplyCreate
+		change.Spec = &models.TemplateSpec{
+			ServerType:       models.ServerType(declared.ServerType),
+			MinecraftVersion: declared.MinecraftVersion,
+			RAMMb:            declared.RAMMb,
+			Settings:         settings,
+			Plugins:          plugins,
+		}
+		return change, nil
+	}
+
+	server := state.server
+	change.ServerID, change.Adopt = server.ID, !state.managed
+	config := settingsChanges(base, settings)
+	if string(server.ServerType) != declared.ServerType {
+		config["server_type"] = declared.ServerType
+	}
+	if server.MinecraftVersion != declared.MinecraftVersion {
+		config["minecraft_version"] = declared.MinecraftVersion
+	}
+	if server.RAMMb != declared.RAMMb {
+		config["ram_mb"] = float64(declared.RAMMb) // ConfigService expects JSON numbers
+	}
+	if len(config) > 0 {
+		change.Config = config
+	}
+
+	if declared.Plugins != nil {
+		wanted := make(map[string]bool, len(plugins))
+		for _, slug := range plugins {
+			wanted[slug] = true
+			if _, ok := state.plugins[slug]; !ok {
+				change.InstallPlugins = append(change.InstallPlugins, slug)
+			}
+		}
+		for slug := range state.plugins {
+			if !wanted[slug] {
+				change.RemovePlugins = append(change.RemovePlugins, slug)
+			}
+		}
+		sort.Strings(change.RemovePlugins)
+		for _, slug := range change.RemovePlugins {
+			change.removePluginIDs = append(change.removePluginIDs, state.plugins[slug])
+		}
+	}
+
+	if change.Adopt || change.Config != nil || change.InstallPlugins != nil || change.RemovePlugins != nil {
+		change.Action = ApplyUpdate
+	}
+	return change, nil
+}
+
+// mergeServerSettings returns base with the fields set in config (keys are the JSON names of
+// models.ServerSettings; unknown keys and wrong types are rejected)
+func mergeServerSettings(base models.ServerSettings, config map[string]interface{}) (models.ServerSettings, error) {
+	if len(config) == 0 {
+		return base, nil
+	}
+	fields, err := settingsFields(base)
+	if err != nil {
+		return base, err
+	}
+	for key, value := range config {
+		if _, ok := fields[key]; !ok {
+			return base, fmt.Errorf("unknown config key %q", key)
+		}
+		fields[key] = value
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return base, err
+	}
+	var merged models.ServerSettings
+	if err := json.Unmarshal(data, &merged); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return base, fmt.Errorf("config key %q must be a %s", typeErr.Field, typeErr.Type)
+		}
+		return base, err
+	}
+	return merged, nil
+}
+
+// settingsChanges returns the fields of desired that differ from current, with their JSON values
+func settingsChanges(current, desired models.ServerSettings) map[string]interface{} {
+	changes := map[string]interface{}{}
+	from, err := settingsFields(current)
+	if err != nil {
+		return changes
+	}
+	to, err := settingsFields(desired)
+	if err != nil {
+		return changes
+	}
+	for key, value := range to {
+		if from[key] != value {
+			changes[key] = value
+		}
+	}
+	return changes
+}
+
+// settingsFields returns settings as a JSON object (numbers are float64)
+func settingsFields(settings models.ServerSettings) (map[string]interface{}, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// normalizePluginSlugs trims and de-duplicates plugin slugs, keeping their order
+func normalizePluginSlugs(slugs []string) []string {
+	seen := make(map[string]bool, len(slugs))
+	var normalized []string
+	for _, slug := range slugs {
+		slug = strings.TrimSpace(slug)
+		if !seen[slug] {
+			seen[slug] = true
+			normalized = append(normalized, slug)
+		}
+	}
+	return normalized
+}
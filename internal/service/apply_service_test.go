@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestValidateManifest(t *testing.T) {
+	lobby := DeclaredServer{Name: "lobby", ServerType: "paper", MinecraftVersion: "1.21", RAMMb: 2048}
+
+	tests := []struct {
+		name    string
+		servers []DeclaredServer
+		valid   bool
+	}{
+		{"valid", []DeclaredServer{lobby}, true},
+		{"empty", nil, true},
+		{"duplicate name", []DeclaredServer{lobby, lobby}, false},
+		{"missing name", []DeclaredServer{{ServerType: "paper", MinecraftVersion: "1.21", RAMMb: 2048}}, false},
+		{"invalid type", []DeclaredServer{{Name: "a", ServerType: "bedrock", MinecraftVersion: "1.21", RAMMb: 2048}}, false},
+		{"invalid version", []DeclaredServer{{Name: "a", ServerType: "paper", MinecraftVersion: "latest", RAMMb: 2048}}, false},
+		{"missing RAM", []DeclaredServer{{Name: "a", ServerType: "paper", MinecraftVersion: "1.21"}}, false},
+		{"blank plugin", []DeclaredServer{{Name: "a", ServerType: "paper", MinecraftVersion: "1.21", RAMMb: 2048, Plugins: []string{" "}}}, false},
+	}
+	for _, tt := range tests {
+		err := validateManifest(&ServerManifest{Servers: tt.servers})
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: validateManifest() = %v, want valid=%v", tt.name, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrManifestInvalid) {
+			t.Errorf("%s: error %v does not wrap ErrManifestInvalid", tt.name, err)
+		}
+	}
+}
+
+func TestMergeServerSettings(t *testing.T) {
+	base := models.DefaultServerSettings()
+
+	merged, err := mergeServerSettings(base, map[string]interface{}{"max_players": float64(50), "pvp": false, "motd": "Lobby"})
+	if err != nil {
+		t.Fatalf("mergeServerSettings() error = %v", err)
+	}
+	if merged.MaxPlayers != 50 || merged.PVP || merged.MOTD != "Lobby" || merged.Difficulty != base.Difficulty {
+		t.Errorf("mergeServerSettings() = %+v", merged)
+	}
+
+	if _, err := mergeServerSettings(base, map[string]interface{}{"max_player": float64(50)}); err == nil {
+		t.Error("mergeServerSettings() accepted an unknown key")
+	}
+	if _, err := mergeServerSettings(base, map[string]interface{}{"max_players": "fifty"}); err == nil {
+		t.Error("mergeServerSettings() accepted a string for max_players")
+	}
+}
+
+func TestPlanDeclaredServer(t *testing.T) {
+	declared := DeclaredServer{
+		Name:             "lobby",
+		ServerType:       "paper",
+		MinecraftVersion: "1.21",
+		RAMMb:            4096,
+		Config:           map[string]interface{}{"max_players": float64(50)},
+		Plugins:          []string{"luckperms", "essentialsx", "luckperms"},
+	}
+
+	// Not created yet
+	change, err := planDeclaredServer(declared, declaredServerState{})
+	if err != nil {
+		t.Fatalf("planDeclaredServer() error = %v", err)
+	}
+	if change.Action != ApplyCreate || change.Spec == nil || change.Spec.RAMMb != 4096 || change.Spec.Settings.MaxPlayers != 50 {
+		t.Fatalf("create: got %+v", change)
+	}
+	if !reflect.DeepEqual(change.Spec.Plugins, []string{"luckperms", "essentialsx"}) {
+		t.Errorf("create: plugins = %v", change.Spec.Plugins)
+	}
+
+	// Managed server that matches the manifest
+	server := &models.MinecraftServer{ID: "srv-1", Name: "lobby", ServerType: "paper", MinecraftVersion: "1.21", RAMMb: 4096}
+	settings := models.DefaultServerSettings()
+	settings.MaxPlayers = 50
+	settings.ApplyTo(server)
+	state := declaredServerState{server: server, managed: true, plugins: map[string]string{"luckperms": "p1", "essentialsx": "p2"}}
+	if change, err = planDeclaredServer(declared, state); err != nil || change.Action != ApplyUnchanged {
+		t.Fatalf("unchanged: got %+v, %v", change, err)
+	}
+
+	// Drifted server: other RAM, version and settings, one plugin missing and one extra
+	server.RAMMb, server.MinecraftVersion, server.MaxPlayers, server.Difficulty = 2048, "1.20.4", 20, "hard"
+	state.plugins = map[string]string{"luckperms": "p1", "worldedit": "p3"}
+	change, err = planDeclaredServer(declared, state)
+	if err != nil {
+		t.Fatalf("planDeclaredServer() error = %v", err)
+	}
+	wantConfig := map[string]interface{}{"ram_mb": float64(4096), "minecraft_version": "1.21", "max_players": float64(50)}
+	if change.Action != ApplyUpdate || change.Adopt || !reflect.DeepEqual(change.Config, wantConfig) {
+		t.Errorf("update: action %s, adopt %v, config %v, want config %v", change.Action, change.Adopt, change.Config, wantConfig)
+	}
+	if !reflect.DeepEqual(change.InstallPlugins, []string{"essentialsx"}) || !reflect.DeepEqual(change.RemovePlugins, []string{"worldedit"}) ||
+		!reflect.DeepEqual(change.removePluginIDs, []string{"p3"}) {
+		t.Errorf("update: install %v, remove %v (%v)", change.InstallPlugins, change.RemovePlugins, change.removePluginIDs)
+	}
+
+	// Settings not in the manifest keep their value; omitted plugins are left alone
+	declared.Plugins = nil
+	server.RAMMb, server.MinecraftVersion, server.MaxPlayers = 4096, "1.21", 50
+	if change, err = planDeclaredServer(declared, state); err != nil || change.Action != ApplyUnchanged {
+		t.Errorf("partial: got %+v, %v", change, err)
+	}
+
+	// An unmanaged server of the same name is adopted even without other changes
+	state.managed = false
+	if change, err = planDeclaredServer(declared, state); err != nil || change.Action != ApplyUpdate || !change.Adopt {
+		t.Errorf("adopt: got %+v, %v", change, err)
+	}
+
+	// Settings are validated like templates
+	declared.Config = map[string]interface{}{"gamemode": "hardcore"}
+	if _, err := planDeclaredServer(declared, state); !errors.Is(err, ErrManifestInvalid) {
+		t.Errorf("invalid gamemode: error = %v, want ErrManifestInvalid", err)
+	}
+}
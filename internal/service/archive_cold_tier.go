@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/storage"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// coldArchivePrefix is the key prefix of cold archives in the bucket
+	coldArchivePrefix = "archives/"
+	// coldRestoreDays is how long a restored copy stays readable; the unarchive reads it right away
+	coldRestoreDays = 2
+	// coldRestorePollInterval is how often a waiting start checks a pending restore
+	coldRestorePollInterval = 10 * time.Second
+)
+
+// ErrArchiveRestoring is wrapped by the error returned while a cold archive is being restored
+var ErrArchiveRestoring = errors.New("archive is being restored from cold storage")
+
+// ArchiveRestoringError rejects a start whose archive isn't readable from cold storage yet
+// The restore continues; starting the server again after it finished unarchives it.
+type ArchiveRestoringError struct {
+	RequestedAt time.Time
+}
+
+func (e *ArchiveRestoringError) Error() string {
+	return fmt.Sprintf("archive is being restored from cold storage (requested %s ago), start the server again later",
+		time.Since(e.RequestedAt).Round(time.Minute))
+}
+
+// Unwrap makes errors.Is(err, ErrArchiveRestoring) match
+func (e *ArchiveRestoringError) Unwrap() error { return ErrArchiveRestoring }
+
+// ColdStorageEnabled reports whether archives can be moved to cold storage
+func (s *ArchiveService) ColdStorageEnabled() bool {
+	return s.objectStorage != nil
+}
+
+// MoveToColdStorage moves the archive of an archived server from the Storage Box to the cold
+// object storage. The server stays archived; the next start restores the archive transparently.
+func (s *ArchiveService) MoveToColdStorage(serverID string) error {
+	if s.objectStorage == nil {
+		return fmt.Errorf("cold storage not configured")
+	}
+
+	defer s.lockTier(serverID)()
+
+	server, err := s.getServer(serverID)
+	if err != nil {
+		return fmt.Errorf("failed to get server: %w", err)
+	}
+	if server.Status != models.StatusArchived || server.ArchiveLocation == "" {
+		return fmt.Errorf("server is not archived (status: %s)", server.Status)
+	}
+	if server.ArchiveTier == models.ArchiveTierCold {
+		return nil
+	}
+
+	// Data residency: the bucket must be in the organization's country, otherwise the archive stays
+	if err := s.residency.CheckStorage(server, s.coldStorageCountry, "cold archive"); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if err := s.controlPlane.WaitForHeadroom(ctx, "cold archive "+serverID); err != nil {
+		return fmt.Errorf("cold archive deferred: %w", err)
+	}
+
+	// Stage the archive on the control plane (it already is without a Storage Box)
+	localPath := filepath.Join(s.storagePath, filepath.Base(server.ArchiveLocation))
+	staged := false
+	if _, err := os.Stat(localPath); os.IsNotExist(err) {
+		if err := s.downloadFromStorageBox(server.ArchiveLocation, localPath, nil); err != nil {
+			return fmt.Errorf("failed to stage archive: %w", err)
+		}
+		staged = true
+	}
+
+	key := coldArchivePrefix + filepath.Base(localPath)
+	if err := s.objectStorage.PutFile(ctx, key, localPath, s.coldStorageClass, nil); err != nil {
+		if staged {
+			os.Remove(localPath)
+		}
+		return fmt.Errorf("failed to upload to cold storage: %w", err)
+	}
+
+	// Only drop the Storage Box copy once the bucket confirms the full archive
+	info, err := s.objectStorage.Stat(ctx, key)
+	if err == nil && info.Size != server.ArchiveSize {
+		err = fmt.Errorf("size mismatch: %d bytes stored, %d archived", info.Size, server.ArchiveSize)
+	}
+	if err != nil {
+		if staged {
+			os.Remove(localPath)
+		}
+		return fmt.Errorf("failed to verify cold archive: %w", err)
+	}
+
+	now := time.Now()
+	err = s.serverRepo.UpdateFields(serverID, map[string]interface{}{
+		"archive_location": key,
+		"archive_tier":     models.ArchiveTierCold,
+		"archive_cold_at":  now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update archive metadata: %w", err)
+	}
+
+	if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+		logger.Warn("ARCHIVE: Failed to delete staged archive", map[string]interface{}{
+			"server_id": serverID,
+			"path":      localPath,
+			"error":     err.Error(),
+		})
+	}
+	if s.sftpClient != nil && server.ArchiveLocation != localPath {
+		if err := s.sftpClient.Delete(server.ArchiveLocation); err != nil {
+			logger.Warn("ARCHIVE: Failed to delete Storage Box copy of cold archive", map[string]interface{}{
+				"server_id":   serverID,
+				"remote_path": server.ArchiveLocation,
+				"error":       err.Error(),
+			})
+		}
+	}
+
+	logger.Info("ARCHIVE: Archive moved to cold storage", map[string]interface{}{
+		"server_id":     serverID,
+		"key":           key,
+		"storage_class": s.coldStorageClass,
+		"size_mb":       server.ArchiveSize / 1024 / 1024,
+	})
+	return nil
+}
+
+// fetchColdArchive downloads a cold archive to localPath. Objects in an archive class are restored
+// first; if the restore doesn't finish within coldRestoreWait an ArchiveRestoringError is returned.
+func (s *ArchiveService) fetchColdArchive(server *models.MinecraftServer, localPath string, progress *TransferProgress) error {
+	if s.objectStorage == nil {
+		return fmt.Errorf("archive is in cold storage but cold storage is not configured")
+	}
+	ctx := context.Background()
+
+	info, err := s.objectStorage.Stat(ctx, server.ArchiveLocation)
+	if err != nil {
+		return fmt.Errorf("failed to look up cold archive: %w", err)
+	}
+
+	requestedAt := server.ArchiveRestoreRequestedAt
+	if info.NeedsRestore() {
+		if requestedAt == nil || !info.RestoreOngoing {
+			if err := s.objectStorage.Restore(ctx, server.ArchiveLocation, coldRestoreDays, s.coldRestoreTier); err != nil {
+				return err
+			}
+			if requestedAt == nil {
+				now := time.Now()
+				requestedAt = &now
+				if err := s.serverRepo.UpdateFields(server.ID, map[string]interface{}{"archive_restore_requested_at": now}); err != nil {
+					return fmt.Errorf("failed to record restore request: %w", err)
+				}
+			}
+			logger.Info("ARCHIVE: Restore of cold archive requested", map[string]interface{}{
+				"server_id": server.ID,
+				"key":       server.ArchiveLocation,
+				"tier":      s.coldRestoreTier,
+			})
+		}
+
+		deadline := time.Now().Add(s.coldRestoreWait)
+		for info.NeedsRestore() {
+			if !time.Now().Before(deadline) {
+				return &ArchiveRestoringError{RequestedAt: *requestedAt}
+			}
+			time.Sleep(min(coldRestorePollInterval, time.Until(deadline)))
+			if info, err = s.objectStorage.Stat(ctx, server.ArchiveLocation); err != nil {
+				return fmt.Errorf("failed to check restore of cold archive: %w", err)
+			}
+		}
+	}
+
+	progress.StartPhase("downloading", info.Size)
+	if err := s.objectStorage.GetFile(ctx, server.ArchiveLocation, localPath, progress.Func()); err != nil {
+		return err
+	}
+
+	// Retrievals are billed per GB (see ArchiveRetrievalRateEURPerGB)
+	err = s.serverRepo.CreateArchiveRetrieval(&models.ArchiveRetrieval{
+		ServerID:     server.ID,
+		OwnerID:      server.OwnerID,
+		SizeBytes:    info.Size,
+		StorageClass: info.StorageClass,
+		RequestedAt:  requestedAt,
+		CompletedAt:  time.Now(),
+	})
+	if err != nil {
+		logger.Error("ARCHIVE: Failed to record cold archive retrieval", err, map[string]interface{}{
+			"server_id": server.ID,
+		})
+	}
+	return nil
+}
+
+// deleteColdArchive removes a cold archive after the server was unarchived
+func (s *ArchiveService) deleteColdArchive(serverID, key string) {
+	if err := s.objectStorage.Delete(context.Background(), key); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		logger.Warn("ARCHIVE: Failed to delete cold archive after unarchive", map[string]interface{}{
+			"server_id": serverID,
+			"key":       key,
+			"error":     err.Error(),
+		})
+	}
+}
+
+// lockTier locks the archive of a server against concurrent tier moves and unarchives
+// Returns the unlock function.
+func (s *ArchiveService) lockTier(serverID string) func() {
+	lock, _ := s.tierLocks.LoadOrStore(serverID, &sync.Mutex{})
+	mu := lock.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// dueForColdStorage reports whether an archived server has been idle long enough for cold storage
+// Idle time counts from the last stop (the archive date for servers without one).
+func dueForColdStorage(server *models.MinecraftServer, coldAfter time.Duration, now time.Time) bool {
+	if server.Status != models.StatusArchived || server.ArchiveTier == models.ArchiveTierCold || server.ArchiveLocation == "" {
+		return false
+	}
+	idleSince := server.LastStoppedAt
+	if idleSince == nil {
+		idleSince = server.ArchivedAt
+	}
+	return idleSince != nil && now.Sub(*idleSince) >= coldAfter
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestDueForColdStorage(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) *time.Time {
+		at := now.AddDate(0, 0, -days)
+		return &at
+	}
+	coldAfter := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		server models.MinecraftServer
+		want   bool
+	}{
+		{"idle long enough", models.MinecraftServer{Status: models.StatusArchived, ArchiveLocation: "a.tar.gz", LastStoppedAt: daysAgo(31)}, true},
+		{"recently stopped", models.MinecraftServer{Status: models.StatusArchived, ArchiveLocation: "a.tar.gz", LastStoppedAt: daysAgo(10), ArchivedAt: daysAgo(40)}, false},
+		{"archive date without stop", models.MinecraftServer{Status: models.StatusArchived, ArchiveLocation: "a.tar.gz", ArchivedAt: daysAgo(30)}, true},
+		{"already cold", models.MinecraftServer{Status: models.StatusArchived, ArchiveLocation: "archives/a.tar.gz", ArchiveTier: models.ArchiveTierCold, LastStoppedAt: daysAgo(90)}, false},
+		{"not archived", models.MinecraftServer{Status: models.StatusSleeping, LastStoppedAt: daysAgo(90)}, false},
+		{"no idle date", models.MinecraftServer{Status: models.StatusArchived, ArchiveLocation: "a.tar.gz"}, false},
+	}
+	for _, tt := range tests {
+		if got := dueForColdStorage(&tt.server, coldAfter, now); got != tt.want {
+			t.Errorf("%s: dueForColdStorage() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAttributeArchiveCostsSplitsColdTier(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	breakdown := &models.CostBreakdown{From: from, To: from.AddDate(0, 0, 3), Days: make([]models.DailyCostBreakdown, 3)}
+	s := &BillingService{pricing: models.PricingConfig{ArchiveRateEURPerGBDay: 0.01, ColdArchiveRateEURPerGBDay: 0.001}}
+
+	archivedAt := from.Add(-24 * time.Hour)
+	coldAt := from.Add(36 * time.Hour) // Moved to cold storage at noon of the second day
+	server := &models.MinecraftServer{
+		Status:        models.StatusArchived,
+		ArchivedAt:    &archivedAt,
+		ArchiveColdAt: &coldAt,
+		ArchiveTier:   models.ArchiveTierCold,
+		ArchiveSize:   2 * 1024 * 1024 * 1024,
+	}
+	s.attributeArchiveCosts(breakdown, server, from.AddDate(0, 0, 3))
+
+	want := []struct{ hot, cold float64 }{{2, 0}, {1, 1}, {0, 2}} // GB-days
+	for i, w := range want {
+		day := breakdown.Days[i]
+		if math.Abs(day.ArchiveGBDays-w.hot) > 1e-9 || math.Abs(day.ColdArchiveGBDays-w.cold) > 1e-9 {
+			t.Errorf("day %d = %.2f hot / %.2f cold GB-days, want %.2f / %.2f", i, day.ArchiveGBDays, day.ColdArchiveGBDays, w.hot, w.cold)
+		}
+	}
+	if got := breakdown.Days[1].ColdArchiveEUR; math.Abs(got-0.001) > 1e-9 {
+		t.Errorf("cold archive cost of day 1 = %f, want 0.001", got)
+	}
+}
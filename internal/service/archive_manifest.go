@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// Archive manifest and selective restore errors
+var (
+	// ErrArchiveIncomplete is wrapped by the error returned when extracted files don't match the manifest
+	ErrArchiveIncomplete = errors.New("archive does not match its manifest")
+
+	ErrServerNotArchived       = errors.New("server is not archived")
+	ErrArchiveManifestNotFound = errors.New("the archive of this server has no manifest")
+	ErrArchivePartsMissing     = errors.New("select at least one part to restore")
+	ErrArchivePartInvalid      = errors.New("unknown archive part (must be world, plugins or config)")
+)
+
+// defaultWorldDirs are the world folders assumed for archives without a manifest
+var defaultWorldDirs = []string{"world", "world_nether", "world_the_end"}
+
+// configExtensions are the extensions of top-level files restored as config
+var configExtensions = []string{".properties", ".yml", ".yaml", ".json", ".toml", ".txt", ".conf", ".cfg"}
+
+// maxReportedPaths limits the example paths in an ArchiveIncompleteError
+const maxReportedPaths = 5
+
+// ArchiveIncompleteError lists the files that differ between an extracted archive and its manifest
+type ArchiveIncompleteError struct {
+	Missing    []string // In the manifest, not in the archive
+	Changed    []string // Size or hash differs
+	Unexpected []string // In the archive, not in the manifest
+}
+
+func (e *ArchiveIncompleteError) Error() string {
+	examples := append(append(append([]string{}, e.Missing...), e.Changed...), e.Unexpected...)
+	if len(examples) > maxReportedPaths {
+		examples = examples[:maxReportedPaths]
+	}
+	return fmt.Sprintf("%s: %d missing, %d changed, %d unexpected (%s)",
+		ErrArchiveIncomplete, len(e.Missing), len(e.Changed), len(e.Unexpected), strings.Join(examples, ", "))
+}
+
+// Unwrap makes errors.Is(err, ErrArchiveIncomplete) match
+func (e *ArchiveIncompleteError) Unwrap() error { return ErrArchiveIncomplete }
+
+// ArchivePartSummary is the size of one part of an archive
+type ArchivePartSummary struct {
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+}
+
+// ArchiveManifestSummary describes the archive of a server, split by restorable part
+type ArchiveManifestSummary struct {
+	ServerID    string                        `json:"server_id"`
+	ArchiveName string                        `json:"archive_name"`
+	ArchiveTier string                        `json:"archive_tier"`
+	ArchiveSize int64                         `json:"archive_size"` // Compressed
+	FileCount   int                           `json:"file_count"`
+	TotalBytes  int64                         `json:"total_bytes"` // Uncompressed
+	CreatedAt   time.Time                     `json:"created_at"`
+	Parts       map[string]ArchivePartSummary `json:"parts"`
+	Files       []models.ArchiveManifestFile  `json:"files,omitempty"`
+}
+
+// newArchiveManifest builds the manifest of a freshly written archive
+func newArchiveManifest(serverID, archiveName string, files []models.ArchiveManifestFile) *models.ArchiveManifest {
+	manifest := &models.ArchiveManifest{
+		ServerID:    serverID,
+		ArchiveName: archiveName,
+		FileCount:   len(files),
+		Files:       files,
+		CreatedAt:   time.Now(),
+	}
+	for _, file := range files {
+		manifest.TotalBytes += file.Size
+	}
+	return manifest
+}
+
+// findArchiveManifest returns the manifest of the current archive of a server
+// Manifests of an older archive (other file name) are ignored; archives from before manifests have none.
+func (s *ArchiveService) findArchiveManifest(server *models.MinecraftServer) (*models.ArchiveManifest, error) {
+	manifest, err := s.serverRepo.FindArchiveManifest(server.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archive manifest: %w", err)
+	}
+	if manifest == nil || manifest.ArchiveName != filepath.Base(server.ArchiveLocation) {
+		return nil, nil
+	}
+	return manifest, nil
+}
+
+// GetArchiveManifest returns the manifest of an archived server, with the file list if withFiles is set
+func (s *ArchiveService) GetArchiveManifest(serverID string, withFiles bool) (*ArchiveManifestSummary, error) {
+	server, err := s.getServer(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+	if server.Status != models.StatusArchived {
+		return nil, ErrServerNotArchived
+	}
+	manifest, err := s.findArchiveManifest(server)
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, ErrArchiveManifestNotFound
+	}
+
+	summary := &ArchiveManifestSummary{
+		ServerID:    server.ID,
+		ArchiveName: manifest.ArchiveName,
+		ArchiveTier: server.ArchiveTier,
+		ArchiveSize: server.ArchiveSize,
+		FileCount:   manifest.FileCount,
+		TotalBytes:  manifest.TotalBytes,
+		CreatedAt:   manifest.CreatedAt,
+		Parts:       make(map[string]ArchivePartSummary),
+	}
+	worldDirs := archiveWorldDirs(manifest.Files)
+	for _, file := range manifest.Files {
+		part := archivePartOf(file.Path, worldDirs)
+		sum := summary.Parts[part]
+		sum.Files++
+		sum.Bytes += file.Size
+		summary.Parts[part] = sum
+	}
+	if withFiles {
+		summary.Files = manifest.Files
+	}
+	return summary, nil
+}
+
+// RestoreArchiveParts restores only the given parts (world, plugins, config) of an archived server,
+// which skips writing the rest and is much faster for large servers. Everything else in the archive
+// is not restored; the server is stopped afterwards like after a full unarchive.
+// Runs in the background as an operation of the owner.
+func (s *ArchiveService) RestoreArchiveParts(serverID, userID string, parts []string) (*Operation, error) {
+	selected, err := parseArchiveParts(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := s.getServer(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+	if server.Status != models.StatusArchived {
+		return nil, ErrServerNotArchived
+	}
+
+	logger.Info("ARCHIVE: Selective restore requested", map[string]interface{}{
+		"server_id": serverID,
+		"user_id":   userID,
+		"parts":     parts,
+	})
+
+	return s.opLimiter.Go(server.OwnerID, userID, OperationUnarchive, serverID, serverID, func(ctx context.Context) error {
+		progress := NewTransferProgress("unarchive", serverID, serverID, nil).ReportTo(s.opLimiter, OperationUnarchive)
+		err := s.unarchiveServer(serverID, selected, progress)
+		if err != nil {
+			logger.Warn("ARCHIVE: Selective restore failed", map[string]interface{}{
+				"server_id": serverID,
+				"error":     err.Error(),
+			})
+		}
+		return err
+	})
+}
+
+// finishPartialUnarchive moves the restored parts into the server directory, replacing folders and files
+// of the same name, and resets the archive state
+func (s *ArchiveService) finishPartialUnarchive(server *models.MinecraftServer, parts map[string]bool, tempDataPath, serverDataPath, localArchivePath string) error {
+	defer os.RemoveAll(tempDataPath)
+
+	entries, err := os.ReadDir(tempDataPath)
+	if err != nil {
+		return fmt.Errorf("failed to read extracted parts: %w", err)
+	}
+	if err := os.MkdirAll(serverDataPath, 0755); err != nil {
+		return fmt.Errorf("failed to create server directory: %w", err)
+	}
+	for _, entry := range entries {
+		target := filepath.Join(serverDataPath, entry.Name())
+		if err := os.RemoveAll(target); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", entry.Name(), err)
+		}
+		if err := os.Rename(filepath.Join(tempDataPath, entry.Name()), target); err != nil {
+			return fmt.Errorf("failed to move %s into place: %w", entry.Name(), err)
+		}
+	}
+
+	if err := s.completeUnarchive(server, localArchivePath); err != nil {
+		return err
+	}
+
+	logger.Info("ARCHIVE: Server partially unarchived (ready to start)", map[string]interface{}{
+		"server_id": server.ID,
+		"parts":     sortedParts(parts),
+	})
+	return nil
+}
+
+// parseArchiveParts validates the parts of a selective restore
+func parseArchiveParts(parts []string) (map[string]bool, error) {
+	if len(parts) == 0 {
+		return nil, ErrArchivePartsMissing
+	}
+	selected := make(map[string]bool, len(parts))
+	for _, part := range parts {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if !slices.Contains(models.ArchivePartsRestorable, part) {
+			return nil, fmt.Errorf("%w: %q", ErrArchivePartInvalid, part)
+		}
+		selected[part] = true
+	}
+	return selected, nil
+}
+
+// archivePartFilter returns the filter of extracted paths for the selected parts (nil = everything)
+func archivePartFilter(parts map[string]bool, manifest *models.ArchiveManifest) func(path string) bool {
+	if parts == nil {
+		return nil
+	}
+	var worldDirs map[string]bool
+	if manifest != nil {
+		worldDirs = archiveWorldDirs(manifest.Files)
+	} else {
+		worldDirs = make(map[string]bool)
+		for _, dir := range defaultWorldDirs {
+			worldDirs[dir] = true
+		}
+	}
+	return func(path string) bool {
+		return parts[archivePartOf(path, worldDirs)]
+	}
+}
+
+// archiveWorldDirs returns the top-level folders holding a level.dat (any level-name)
+func archiveWorldDirs(files []models.ArchiveManifestFile) map[string]bool {
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		if path.Base(file.Path) != "level.dat" {
+			continue
+		}
+		if top, _, nested := strings.Cut(file.Path, "/"); nested {
+			dirs[top] = true
+		}
+	}
+	return dirs
+}
+
+// archivePartOf classifies a slash-separated path of an archive
+func archivePartOf(filePath string, worldDirs map[string]bool) string {
+	top, _, nested := strings.Cut(filePath, "/")
+	if !nested {
+		if slices.Contains(configExtensions, strings.ToLower(path.Ext(top))) {
+			return models.ArchivePartConfig
+		}
+		return models.ArchivePartOther
+	}
+
+	switch {
+	case worldDirs[top]:
+		return models.ArchivePartWorld
+	case top == "plugins" || top == "mods":
+		return models.ArchivePartPlugins
+	case top == "config" || top == "defaultconfigs":
+		return models.ArchivePartConfig
+	default:
+		return models.ArchivePartOther
+	}
+}
+
+// verifyArchiveManifest compares the extracted files with the manifest entries include accepts (nil = all)
+func verifyArchiveManifest(manifest *models.ArchiveManifest, extracted []models.ArchiveManifestFile, include func(path string) bool) error {
+	got := make(map[string]models.ArchiveManifestFile, len(extracted))
+	for _, file := range extracted {
+		got[file.Path] = file
+	}
+
+	result := &ArchiveIncompleteError{}
+	want := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		if include != nil && !include(file.Path) {
+			continue
+		}
+		want[file.Path] = true
+		actual, ok := got[file.Path]
+		switch {
+		case !ok:
+			result.Missing = append(result.Missing, file.Path)
+		case actual.Size != file.Size || actual.SHA256 != file.SHA256:
+			result.Changed = append(result.Changed, file.Path)
+		}
+	}
+	for _, file := range extracted {
+		if !want[file.Path] {
+			result.Unexpected = append(result.Unexpected, file.Path)
+		}
+	}
+
+	if len(result.Missing) == 0 && len(result.Changed) == 0 && len(result.Unexpected) == 0 {
+		return nil
+	}
+	sort.Strings(result.Missing)
+	sort.Strings(result.Changed)
+	sort.Strings(result.Unexpected)
+	return result
+}
+
+// sortedParts lists the selected parts for logs
+func sortedParts(parts map[string]bool) []string {
+	list := make([]string, 0, len(parts))
+	for part := range parts {
+		list = append(list, part)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestArchivePartOf(t *testing.T) {
+	files := []models.ArchiveManifestFile{
+		{Path: "survival/level.dat"}, // Custom level-name
+		{Path: "survival_nether/level.dat"},
+	}
+	worldDirs := archiveWorldDirs(files)
+
+	tests := map[string]string{
+		"survival/region/r.0.0.mca":       models.ArchivePartWorld,
+		"survival_nether/DIM-1/r.0.0.mca": models.ArchivePartWorld,
+		"world/region/r.0.0.mca":          models.ArchivePartOther, // Not a world of this server
+		"plugins/EssentialsX.jar":         models.ArchivePartPlugins,
+		"plugins/Essentials/config.yml":   models.ArchivePartPlugins,
+		"mods/create.jar":                 models.ArchivePartPlugins,
+		"server.properties":               models.ArchivePartConfig,
+		"ops.json":                        models.ArchivePartConfig,
+		"config/paper-global.yml":         models.ArchivePartConfig,
+		"paper.jar":                       models.ArchivePartOther,
+		"libraries/com/google/guava.jar":  models.ArchivePartOther,
+		"cache/mojang_1.21.jar":           models.ArchivePartOther,
+	}
+	for path, want := range tests {
+		if got := archivePartOf(path, worldDirs); got != want {
+			t.Errorf("archivePartOf(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestVerifyArchiveManifest(t *testing.T) {
+	manifest := newArchiveManifest("srv", "srv.tar.zst", []models.ArchiveManifestFile{
+		{Path: "world/level.dat", Size: 10, SHA256: "a"},
+		{Path: "world/region/r.0.0.mca", Size: 4096, SHA256: "b"},
+		{Path: "plugins/x.jar", Size: 20, SHA256: "c"},
+		{Path: "server.properties", Size: 5, SHA256: "d"},
+	})
+	if manifest.TotalBytes != 4131 || manifest.FileCount != 4 {
+		t.Fatalf("manifest totals = %d files / %d bytes, want 4 / 4131", manifest.FileCount, manifest.TotalBytes)
+	}
+
+	complete := append([]models.ArchiveManifestFile{}, manifest.Files...)
+	if err := verifyArchiveManifest(manifest, complete, nil); err != nil {
+		t.Errorf("verifyArchiveManifest(complete) = %v, want nil", err)
+	}
+
+	damaged := []models.ArchiveManifestFile{
+		{Path: "world/level.dat", Size: 10, SHA256: "a"},
+		{Path: "world/region/r.0.0.mca", Size: 4096, SHA256: "x"},
+		{Path: "server.properties", Size: 5, SHA256: "d"},
+		{Path: "stray.txt", Size: 1, SHA256: "e"},
+	}
+	err := verifyArchiveManifest(manifest, damaged, nil)
+	var incomplete *ArchiveIncompleteError
+	if !errors.As(err, &incomplete) || !errors.Is(err, ErrArchiveIncomplete) {
+		t.Fatalf("verifyArchiveManifest(damaged) = %v, want an ArchiveIncompleteError", err)
+	}
+	if len(incomplete.Missing) != 1 || incomplete.Missing[0] != "plugins/x.jar" ||
+		len(incomplete.Changed) != 1 || incomplete.Changed[0] != "world/region/r.0.0.mca" ||
+		len(incomplete.Unexpected) != 1 || incomplete.Unexpected[0] != "stray.txt" {
+		t.Errorf("differences = %+v, want plugins/x.jar missing, the region changed and stray.txt unexpected", incomplete)
+	}
+
+	// A selective restore only checks the selected parts
+	parts, err := parseArchiveParts([]string{"World"})
+	if err != nil {
+		t.Fatalf("parseArchiveParts() = %v", err)
+	}
+	worldOnly := archivePartFilter(parts, manifest)
+	if err := verifyArchiveManifest(manifest, complete[:2], worldOnly); err != nil {
+		t.Errorf("verifyArchiveManifest(world only) = %v, want nil", err)
+	}
+}
+
+func TestParseArchiveParts(t *testing.T) {
+	if _, err := parseArchiveParts(nil); !errors.Is(err, ErrArchivePartsMissing) {
+		t.Errorf("parseArchiveParts(nil) = %v, want ErrArchivePartsMissing", err)
+	}
+	if _, err := parseArchiveParts([]string{"world", "logs"}); !errors.Is(err, ErrArchivePartInvalid) {
+		t.Errorf("parseArchiveParts(logs) = %v, want ErrArchivePartInvalid", err)
+	}
+	parts, err := parseArchiveParts([]string{"plugins", " config "})
+	if err != nil || !parts[models.ArchivePartPlugins] || !parts[models.ArchivePartConfig] || parts[models.ArchivePartWorld] {
+		t.Errorf("parseArchiveParts(plugins, config) = %v, %v", parts, err)
+	}
+}
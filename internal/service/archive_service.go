@@ -2,15 +2,17 @@ package service
 
 import (
 	"archive/tar"
-	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
+	"sync"
 	"time"
 
+	"github.com/payperplay/hosting/internal/compression"
 	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/repository"
 	"github.com/payperplay/hosting/internal/storage"
@@ -21,11 +23,24 @@ import (
 // ArchiveService handles server archiving to Hetzner Storage Box
 // Phase 3 Lifecycle: Sleeping > 48h → Compress → Upload → FREE for users
 type ArchiveService struct {
-	serverRepo  *repository.ServerRepository // Repository for server operations
-	storagePath string                       // Local path for temporary archive files
-	remotePath  string                       // Remote Storage Box path (SFTP/WebDAV)
-	conductor   interface{}                  // Conductor for container operations
-	sftpClient  *storage.SFTPClient          // SFTP client for Storage Box (Phase 3b)
+	serverRepo     *repository.ServerRepository // Repository for server operations
+	storagePath    string                       // Local path for temporary archive files
+	remotePath     string                       // Remote Storage Box path (SFTP/WebDAV)
+	conductor      interface{}                  // Conductor for container operations
+	sftpClient     *storage.SFTPClient          // SFTP client for Storage Box (Phase 3b)
+	compression    compression.Options          // Codec for new archives (restores auto-detect)
+	controlPlane   *ControlPlaneMonitor         // Optional: defers archiving while the control plane is under pressure
+	opLimiter      *OperationLimiter            // Optional: lists running archives as operations and cancels them
+	residency      *ResidencyService            // Optional: blocks archives of residency-bound servers to storage in other countries
+	storageCountry string                       // Country archives are stored in (Storage Box or local), "" = unknown
+
+	// Cold tier (optional): archives of long-idle servers move to object storage
+	objectStorage      *storage.ObjectStorageClient
+	coldStorageClass   string
+	coldStorageCountry string
+	coldRestoreTier    string
+	coldRestoreWait    time.Duration
+	tierLocks          sync.Map // Server ID -> *sync.Mutex, moves between tiers and unarchives of a server don't overlap
 }
 
 // NewArchiveService creates a new archive service
@@ -34,6 +49,7 @@ func NewArchiveService(serverRepo *repository.ServerRepository, conductor interf
 
 	// Initialize SFTP client if Storage Box is enabled
 	var sftpClient *storage.SFTPClient
+	storageCountry := cfg.LocalStorageCountry
 	if cfg.StorageBoxEnabled {
 		client, err := storage.NewSFTPClient(cfg)
 		if err != nil {
@@ -42,6 +58,7 @@ func NewArchiveService(serverRepo *repository.ServerRepository, conductor interf
 			})
 		} else {
 			sftpClient = client
+			storageCountry = cfg.StorageBoxCountry
 			logger.Info("ARCHIVE: SFTP client initialized successfully", map[string]interface{}{
 				"host": cfg.StorageBoxHost,
 				"path": cfg.StorageBoxPath,
@@ -51,17 +68,77 @@ func NewArchiveService(serverRepo *repository.ServerRepository, conductor interf
 		logger.Info("ARCHIVE: Storage Box disabled, using local storage fallback", nil)
 	}
 
-	return &ArchiveService{
-		serverRepo:  serverRepo,
-		storagePath: filepath.Join(cfg.ServersBasePath, ".archives"),
-		remotePath:  cfg.StorageBoxPath,
-		conductor:   conductor,
-		sftpClient:  sftpClient,
+	opts := compression.Options{
+		Codec:   compression.Codec(cfg.ArchiveCompression),
+		Level:   cfg.ArchiveCompressionLevel,
+		Workers: cfg.CompressionWorkers,
 	}
+	if err := opts.Validate(); err != nil {
+		logger.Warn("ARCHIVE: Invalid compression settings, using gzip defaults", map[string]interface{}{
+			"error": err.Error(),
+		})
+		opts = compression.Options{Codec: compression.CodecGzip, Workers: cfg.CompressionWorkers}
+		opts.Validate()
+	}
+
+	s := &ArchiveService{
+		serverRepo:         serverRepo,
+		storagePath:        filepath.Join(cfg.ServersBasePath, ".archives"),
+		remotePath:         cfg.StorageBoxPath,
+		conductor:          conductor,
+		sftpClient:         sftpClient,
+		compression:        opts,
+		storageCountry:     storageCountry,
+		coldStorageClass:   cfg.ColdStorageClass,
+		coldStorageCountry: cfg.ColdStorageCountry,
+		coldRestoreTier:    cfg.ColdRestoreTier,
+	}
+
+	if cfg.ColdStorageEnabled {
+		client, err := storage.NewObjectStorageClient(storage.ObjectStorageOptions{
+			Endpoint:  cfg.ColdStorageEndpoint,
+			Region:    cfg.ColdStorageRegion,
+			Bucket:    cfg.ColdStorageBucket,
+			AccessKey: cfg.ColdStorageAccessKey,
+			SecretKey: cfg.ColdStorageSecretKey,
+		})
+		if err != nil {
+			logger.Warn("ARCHIVE: Failed to initialize cold storage, archives stay on the Storage Box", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			s.objectStorage = client
+			logger.Info("ARCHIVE: Cold storage tier enabled", map[string]interface{}{
+				"bucket":        cfg.ColdStorageBucket,
+				"storage_class": cfg.ColdStorageClass,
+			})
+		}
+	}
+	if wait, err := time.ParseDuration(cfg.ColdRestoreWait); err == nil && wait >= 0 {
+		s.coldRestoreWait = wait
+	}
+
+	return s
+}
+
+// SetControlPlaneMonitor sets the monitor that defers and throttles archiving under resource pressure
+func (s *ArchiveService) SetControlPlaneMonitor(controlPlane *ControlPlaneMonitor) {
+	s.controlPlane = controlPlane
+}
+
+// SetOperationLimiter sets the limiter that tracks running archives as cancellable operations
+func (s *ArchiveService) SetOperationLimiter(opLimiter *OperationLimiter) {
+	s.opLimiter = opLimiter
+}
+
+// SetResidencyService sets the service that enforces organization data residency on archives
+func (s *ArchiveService) SetResidencyService(residency *ResidencyService) {
+	s.residency = residency
 }
 
 // ArchiveServer archives a sleeping server to Storage Box
 // Steps: 1) Compress volume 2) Upload 3) Delete container/volume 4) Update DB
+// The archive runs as an operation of the server owner and can be cancelled until the upload starts.
 func (s *ArchiveService) ArchiveServer(serverID string) error {
 	logger.Info("ARCHIVE: Starting server archiving", map[string]interface{}{
 		"server_id": serverID,
@@ -78,14 +155,43 @@ func (s *ArchiveService) ArchiveServer(serverID string) error {
 		return fmt.Errorf("server cannot be archived: %w", err)
 	}
 
+	// Data residency: the archive must be stored in the organization's country
+	if err := s.residency.CheckStorage(server, s.storageCountry, "archive"); err != nil {
+		return err
+	}
+
+	return s.opLimiter.Run(server.OwnerID, "", OperationArchive, serverID, serverID, func(ctx context.Context) error {
+		return s.archiveServer(ctx, server)
+	})
+}
+
+// archiveServer compresses, uploads and records the archive of a validated server
+// On cancellation the partial archive is removed and the server keeps its previous status.
+func (s *ArchiveService) archiveServer(ctx context.Context, server *models.MinecraftServer) error {
+	serverID := server.ID
+	previousStatus := server.Status
+
+	// Archives are staged and compressed on the control plane - wait for headroom before starting
+	if err := s.controlPlane.WaitForHeadroom(ctx, "archive "+serverID); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("archiving cancelled: %w", ctx.Err())
+		}
+		return fmt.Errorf("archiving deferred: %w", err)
+	}
+
 	// Update status to 'archiving'
 	if err := s.updateServerStatus(serverID, models.StatusArchiving); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
 
 	// Step 1: Compress server data (world files, configs, etc)
-	archivePath, archiveSize, err := s.compressServerData(server)
+	progress := NewTransferProgress("archive", serverID, serverID, nil).ReportTo(s.opLimiter, OperationArchive)
+	progress.StartPhase("compressing", 0) // Size unknown until the walk completes
+	archivePath, archiveSize, files, err := s.compressServerData(ctx, server, progress)
 	if err != nil {
+		if ctx.Err() != nil {
+			return s.cancelArchiving(ctx, serverID, archivePath, previousStatus)
+		}
 		s.updateServerStatus(serverID, models.StatusError)
 		return fmt.Errorf("failed to compress server data: %w", err)
 	}
@@ -108,12 +214,19 @@ func (s *ArchiveService) ArchiveServer(serverID string) error {
 		"size_mb":      archiveSize / 1024 / 1024,
 	})
 
+	// Last cancellation point: the upload itself can't be interrupted
+	if ctx.Err() != nil {
+		return s.cancelArchiving(ctx, serverID, archivePath, previousStatus)
+	}
+
 	// Step 2: Upload to Hetzner Storage Box (or local fallback for now)
-	remotePath, err := s.uploadToStorageBox(archivePath, serverID)
+	progress.StartPhase("uploading", archiveSize)
+	remotePath, err := s.uploadToStorageBox(archivePath, serverID, progress)
 	if err != nil {
 		s.updateServerStatus(serverID, models.StatusError)
 		return fmt.Errorf("failed to upload to storage box: %w", err)
 	}
+	progress.Finish()
 
 	logger.Info("ARCHIVE: Uploaded to Storage Box", map[string]interface{}{
 		"server_id":   serverID,
@@ -132,6 +245,13 @@ func (s *ArchiveService) ArchiveServer(serverID string) error {
 	if err := s.updateServerArchiveMetadata(serverID, remotePath, archiveSize); err != nil {
 		return fmt.Errorf("failed to update archive metadata: %w", err)
 	}
+	if err := s.serverRepo.SaveArchiveManifest(newArchiveManifest(serverID, filepath.Base(remotePath), files)); err != nil {
+		// The archive is complete; unarchiving falls back to the structural check without a manifest
+		logger.Warn("ARCHIVE: Failed to save archive manifest", map[string]interface{}{
+			"server_id": serverID,
+			"error":     err.Error(),
+		})
+	}
 
 	// Step 5: Update status to 'archived'
 	if err := s.updateServerStatus(serverID, models.StatusArchived); err != nil {
@@ -147,9 +267,18 @@ func (s *ArchiveService) ArchiveServer(serverID string) error {
 	return nil
 }
 
-// UnarchiveServer restores a server from Storage Box
-// Steps: 1) Download 2) Extract 3) Update DB to stopped state 4) Cleanup
+// UnarchiveServer restores a server from Storage Box or cold storage
+// Steps: 1) Download (restoring cold archives first) 2) Extract and verify against the manifest
+// 3) Update DB to stopped state 4) Cleanup
+// Returns an ArchiveRestoringError while a cold archive isn't readable yet.
 func (s *ArchiveService) UnarchiveServer(serverID string) error {
+	return s.unarchiveServer(serverID, nil, NewTransferProgress("unarchive", serverID, serverID, nil))
+}
+
+// unarchiveServer restores the given parts of an archived server (nil = everything)
+func (s *ArchiveService) unarchiveServer(serverID string, parts map[string]bool, progress *TransferProgress) error {
+	defer s.lockTier(serverID)()
+
 	logger.Info("ARCHIVE: Starting server unarchiving", map[string]interface{}{
 		"server_id": serverID,
 	})
@@ -171,18 +300,24 @@ func (s *ArchiveService) UnarchiveServer(serverID string) error {
 	}
 
 	// Step 1: Download from Storage Box (if using SFTP)
-	localArchivePath := filepath.Join(s.storagePath, fmt.Sprintf("%s.tar.gz", serverID))
+	// The file name carries the codec extension the archive was created with
+	localArchivePath := filepath.Join(s.storagePath, filepath.Base(server.ArchiveLocation))
 
 	// Check if local archive exists (for local storage fallback)
 	if _, err := os.Stat(localArchivePath); os.IsNotExist(err) {
-		// Archive not local - try to download from Storage Box
-		if s.sftpClient != nil {
+		// Archive not local - try to download from cold storage or the Storage Box
+		if server.ArchiveTier == models.ArchiveTierCold {
+			if err := s.fetchColdArchive(server, localArchivePath, progress); err != nil {
+				return fmt.Errorf("failed to download from cold storage: %w", err)
+			}
+		} else if s.sftpClient != nil {
 			logger.Info("ARCHIVE: Local archive not found, downloading from Storage Box", map[string]interface{}{
 				"server_id":   serverID,
 				"remote_path": server.ArchiveLocation,
 			})
 
-			if err := s.downloadFromStorageBox(server.ArchiveLocation, localArchivePath); err != nil {
+			progress.StartPhase("downloading", server.ArchiveSize)
+			if err := s.downloadFromStorageBox(server.ArchiveLocation, localArchivePath, progress); err != nil {
 				return fmt.Errorf("failed to download from storage box: %w", err)
 			}
 		} else {
@@ -202,24 +337,33 @@ func (s *ArchiveService) UnarchiveServer(serverID string) error {
 		"archive_size": server.ArchiveSize,
 	})
 
-	// GAP-7: Validate archive integrity BEFORE extraction
-	logger.Info("GAP-7: Validating archive integrity", map[string]interface{}{
-		"server_id": serverID,
-		"archive":   localArchivePath,
-	})
+	// Archives with a manifest are verified file by file while extracting; older ones get a full read first
+	manifest, err := s.findArchiveManifest(server)
+	if err != nil {
+		return err
+	}
 
-	if err := s.validateArchiveIntegrity(localArchivePath); err != nil {
-		logger.Error("GAP-7: Archive validation failed - archive is corrupted", err, map[string]interface{}{
+	if manifest == nil {
+		// GAP-7: Validate archive integrity BEFORE extraction
+		logger.Info("GAP-7: Validating archive integrity", map[string]interface{}{
 			"server_id": serverID,
 			"archive":   localArchivePath,
 		})
-		// TODO: Fallback to backup if available
-		return fmt.Errorf("archive validation failed: %w", err)
-	}
 
-	logger.Info("GAP-7: Archive integrity validated successfully", map[string]interface{}{
-		"server_id": serverID,
-	})
+		if err := s.validateArchiveIntegrity(localArchivePath); err != nil {
+			logger.Error("GAP-7: Archive validation failed - archive is corrupted", err, map[string]interface{}{
+				"server_id": serverID,
+				"archive":   localArchivePath,
+			})
+			// TODO: Fallback to backup if available
+			return fmt.Errorf("archive validation failed: %w", err)
+		}
+
+		logger.Info("GAP-7: Archive integrity validated successfully", map[string]interface{}{
+			"server_id": serverID,
+		})
+	}
+	include := archivePartFilter(parts, manifest)
 
 	// Step 2: Extract archive (FIX #3: Atomic restore)
 	// Extract to temp directory first to prevent data loss on failure
@@ -236,11 +380,28 @@ func (s *ArchiveService) UnarchiveServer(serverID string) error {
 	}
 
 	// Extract to temp directory
-	if err := s.extractArchive(localArchivePath, tempDataPath); err != nil {
+	extracted, err := s.extractArchive(localArchivePath, tempDataPath, include, progress)
+	if err != nil {
 		// Extraction failed - clean up temp and return error
 		os.RemoveAll(tempDataPath)
 		return fmt.Errorf("failed to extract archive: %w", err)
 	}
+	progress.Finish()
+
+	if manifest != nil {
+		if err := verifyArchiveManifest(manifest, extracted, include); err != nil {
+			logger.Error("ARCHIVE: Extracted files don't match the archive manifest", err, map[string]interface{}{
+				"server_id": serverID,
+				"archive":   localArchivePath,
+			})
+			os.RemoveAll(tempDataPath)
+			return err
+		}
+		logger.Info("ARCHIVE: Extracted files verified against the manifest", map[string]interface{}{
+			"server_id": serverID,
+			"files":     len(extracted),
+		})
+	}
 
 	// Validate extraction succeeded by checking if temp directory has content
 	entries, err := os.ReadDir(tempDataPath)
@@ -249,6 +410,11 @@ func (s *ArchiveService) UnarchiveServer(serverID string) error {
 		return fmt.Errorf("extraction validation failed: directory empty or unreadable")
 	}
 
+	// Selective restores are complete once they match the manifest
+	if parts != nil {
+		return s.finishPartialUnarchive(server, parts, tempDataPath, serverDataPath, localArchivePath)
+	}
+
 	// GAP-7: Validate critical Minecraft files exist after extraction
 	logger.Info("GAP-7: Validating extracted Minecraft world data", map[string]interface{}{
 		"server_id": serverID,
@@ -292,58 +458,100 @@ func (s *ArchiveService) UnarchiveServer(serverID string) error {
 		"data_path": serverDataPath,
 	})
 
-	// Step 3: Update server metadata (reset archive state, set to stopped)
-	if err := s.clearArchiveMetadata(serverID); err != nil {
+	// Step 3 and 4: Update server metadata (reset archive state, set to stopped) and clean up
+	if err := s.completeUnarchive(server, localArchivePath); err != nil {
+		return err
+	}
+
+	logger.Info("ARCHIVE: Server successfully unarchived (ready to start)", map[string]interface{}{
+		"server_id": serverID,
+	})
+
+	return nil
+}
+
+// completeUnarchive resets the archive state of an unarchived server and removes the archive copies
+func (s *ArchiveService) completeUnarchive(server *models.MinecraftServer, localArchivePath string) error {
+	if err := s.clearArchiveMetadata(server.ID); err != nil {
 		return fmt.Errorf("failed to clear archive metadata: %w", err)
 	}
+	if server.ArchiveTier == models.ArchiveTierCold && s.objectStorage != nil {
+		s.deleteColdArchive(server.ID, server.ArchiveLocation)
+	}
+	if err := s.serverRepo.DeleteArchiveManifest(server.ID); err != nil {
+		logger.Warn("ARCHIVE: Failed to delete archive manifest", map[string]interface{}{
+			"server_id": server.ID,
+			"error":     err.Error(),
+		})
+	}
 
-	// Step 4: Delete local archive file (cleanup)
+	// Delete local archive file (cleanup)
 	if err := os.Remove(localArchivePath); err != nil {
 		logger.Warn("ARCHIVE: Failed to delete local archive file", map[string]interface{}{
-			"server_id": serverID,
+			"server_id": server.ID,
 			"path":      localArchivePath,
 			"error":     err.Error(),
 		})
 	}
+	return nil
+}
 
-	logger.Info("ARCHIVE: Server successfully unarchived (ready to start)", map[string]interface{}{
+// cancelArchiving removes the partial archive of a cancelled archiving run and restores the server status
+func (s *ArchiveService) cancelArchiving(ctx context.Context, serverID, archivePath string, previousStatus models.ServerStatus) error {
+	if archivePath != "" {
+		os.Remove(archivePath)
+	}
+	s.updateServerStatus(serverID, previousStatus)
+
+	logger.Info("ARCHIVE: Archiving cancelled", map[string]interface{}{
 		"server_id": serverID,
+		"status":    previousStatus,
 	})
-
-	return nil
+	return fmt.Errorf("archiving cancelled: %w", ctx.Err())
 }
 
-// compressServerData compresses server world data to .tar.gz
-// Returns: (archivePath, size in bytes, error)
-func (s *ArchiveService) compressServerData(server *models.MinecraftServer) (string, int64, error) {
+// compressServerData compresses server world data to a tar stream (.tar.gz / .tar.zst)
+// progress (optional) counts uncompressed bytes read from the server files; ctx is checked between files
+// Returns: (archivePath, size in bytes, manifest files, error) - archivePath is set on failure if a partial file was written
+func (s *ArchiveService) compressServerData(ctx context.Context, server *models.MinecraftServer, progress *TransferProgress) (string, int64, []models.ArchiveManifestFile, error) {
 	serverDataPath := filepath.Join(config.AppConfig.ServersBasePath, server.ID)
-	archivePath := filepath.Join(s.storagePath, fmt.Sprintf("%s.tar.gz", server.ID))
+	archivePath := filepath.Join(s.storagePath, server.ID+compression.Extension(s.compression.Codec))
 
 	// Ensure archive directory exists
 	if err := os.MkdirAll(s.storagePath, 0755); err != nil {
-		return "", 0, fmt.Errorf("failed to create archive directory: %w", err)
+		return "", 0, nil, fmt.Errorf("failed to create archive directory: %w", err)
 	}
 
 	// Create archive file
 	archiveFile, err := os.Create(archivePath)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to create archive file: %w", err)
+		return "", 0, nil, fmt.Errorf("failed to create archive file: %w", err)
 	}
 	defer archiveFile.Close()
 
-	// Create gzip writer
-	gzipWriter := gzip.NewWriter(archiveFile)
-	defer gzipWriter.Close()
+	// Create compressor
+	opts := s.compression
+	opts.Workers = s.controlPlane.CompressionWorkers(opts.Workers)
+	compressor, err := compression.NewWriter(archiveFile, opts)
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("failed to create compressor: %w", err)
+	}
+	defer compressor.Close()
 
 	// Create tar writer
-	tarWriter := tar.NewWriter(gzipWriter)
+	tarWriter := tar.NewWriter(compressor)
 	defer tarWriter.Close()
 
+	var files []models.ArchiveManifestFile
+
 	// Walk server data directory and add files to archive
 	err = filepath.Walk(serverDataPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		// Skip directories (tar will create them automatically)
 		if info.IsDir() {
@@ -374,58 +582,87 @@ func (s *ArchiveService) compressServerData(server *models.MinecraftServer) (str
 		}
 		defer file.Close()
 
-		if _, err := io.Copy(tarWriter, file); err != nil {
+		// Hash the content on the way into the archive for the manifest
+		hash := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(tarWriter, hash), progress.Reader(file)); err != nil {
 			return err
 		}
+		files = append(files, models.ArchiveManifestFile{
+			Path:   filepath.ToSlash(relPath),
+			Size:   info.Size(),
+			SHA256: hex.EncodeToString(hash.Sum(nil)),
+		})
 
 		return nil
 	})
 
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to compress data: %w", err)
+		return archivePath, 0, nil, fmt.Errorf("failed to compress data: %w", err)
+	}
+
+	// Flush tar trailer and compressor before measuring the file
+	if err := tarWriter.Close(); err != nil {
+		return "", 0, nil, fmt.Errorf("failed to finalize tar stream: %w", err)
+	}
+	if err := compressor.Close(); err != nil {
+		return "", 0, nil, fmt.Errorf("failed to finalize compression: %w", err)
 	}
 
 	// Get archive file size
 	archiveInfo, err := os.Stat(archivePath)
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to stat archive: %w", err)
+		return "", 0, nil, fmt.Errorf("failed to stat archive: %w", err)
 	}
 
-	return archivePath, archiveInfo.Size(), nil
+	return archivePath, archiveInfo.Size(), files, nil
 }
 
-// extractArchive extracts a .tar.gz archive to a destination path
-func (s *ArchiveService) extractArchive(archivePath, destPath string) error {
+// extractArchive extracts an archive to a destination path (codec is auto-detected)
+// Only files include accepts are written (nil = all). Returns the size and hash of every extracted file.
+// progress (optional) counts compressed bytes read from the archive
+func (s *ArchiveService) extractArchive(archivePath, destPath string, include func(path string) bool, progress *TransferProgress) ([]models.ArchiveManifestFile, error) {
 	// Open archive file
 	archiveFile, err := os.Open(archivePath)
 	if err != nil {
-		return fmt.Errorf("failed to open archive: %w", err)
+		return nil, fmt.Errorf("failed to open archive: %w", err)
 	}
 	defer archiveFile.Close()
 
-	// Create gzip reader
-	gzipReader, err := gzip.NewReader(archiveFile)
+	if progress != nil {
+		if info, err := archiveFile.Stat(); err == nil {
+			progress.StartPhase("extracting", info.Size())
+		}
+	}
+
+	// Create decompressor (gzip or zstd, detected from magic bytes)
+	decompressor, _, err := compression.NewReader(progress.Reader(archiveFile))
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
+		return nil, fmt.Errorf("failed to open archive: %w", err)
 	}
-	defer gzipReader.Close()
+	defer decompressor.Close()
 
 	// Create tar reader
-	tarReader := tar.NewReader(gzipReader)
+	tarReader := tar.NewReader(decompressor)
 
 	// Ensure destination directory exists
 	if err := os.MkdirAll(destPath, 0755); err != nil {
-		return fmt.Errorf("failed to create destination directory: %w", err)
+		return nil, fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
 	// Extract files
+	var extracted []models.ArchiveManifestFile
 	for {
 		header, err := tarReader.Next()
 		if err == io.EOF {
 			break // End of archive
 		}
 		if err != nil {
-			return fmt.Errorf("failed to read tar header: %w", err)
+			return nil, fmt.Errorf("failed to read tar header: %w", err)
+		}
+
+		// Skipped parts are read past without writing them
+		if include != nil && header.Typeflag != tar.TypeDir && !include(filepath.ToSlash(header.Name)) {
+			continue
 		}
 
 		// Construct full path
@@ -434,37 +671,45 @@ func (s *ArchiveService) extractArchive(archivePath, destPath string) error {
 		// Create directories if needed
 		if header.Typeflag == tar.TypeDir {
 			if err := os.MkdirAll(targetPath, os.FileMode(header.Mode)); err != nil {
-				return fmt.Errorf("failed to create directory: %w", err)
+				return nil, fmt.Errorf("failed to create directory: %w", err)
 			}
 			continue
 		}
 
 		// Ensure parent directory exists
 		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-			return fmt.Errorf("failed to create parent directory: %w", err)
+			return nil, fmt.Errorf("failed to create parent directory: %w", err)
 		}
 
 		// Create file
 		outFile, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
 		if err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
+			return nil, fmt.Errorf("failed to create file: %w", err)
 		}
 
 		// Copy content
-		if _, err := io.Copy(outFile, tarReader); err != nil {
+		hash := sha256.New()
+		written, err := io.Copy(io.MultiWriter(outFile, hash), tarReader)
+		if err != nil {
 			outFile.Close()
-			return fmt.Errorf("failed to extract file: %w", err)
+			return nil, fmt.Errorf("failed to extract file: %w", err)
 		}
 		outFile.Close()
+
+		extracted = append(extracted, models.ArchiveManifestFile{
+			Path:   filepath.ToSlash(header.Name),
+			Size:   written,
+			SHA256: hex.EncodeToString(hash.Sum(nil)),
+		})
 	}
 
-	return nil
+	return extracted, nil
 }
 
 // uploadToStorageBox uploads archive to Hetzner Storage Box via SFTP
 // Falls back to local storage if SFTP is disabled or fails
-func (s *ArchiveService) uploadToStorageBox(localPath, serverID string) (string, error) {
-	remoteName := fmt.Sprintf("%s.tar.gz", serverID)
+func (s *ArchiveService) uploadToStorageBox(localPath, serverID string, progress *TransferProgress) (string, error) {
+	remoteName := filepath.Base(localPath) // {server-id}.tar.gz / .tar.zst
 
 	// Phase 3b: SFTP upload to Hetzner Storage Box
 	if s.sftpClient != nil {
@@ -473,7 +718,7 @@ func (s *ArchiveService) uploadToStorageBox(localPath, serverID string) (string,
 			"remote_name": remoteName,
 		})
 
-		remotePath, err := s.sftpClient.Upload(localPath, remoteName)
+		remotePath, err := s.sftpClient.UploadWithProgress(localPath, remoteName, progress.Func())
 		if err != nil {
 			logger.Error("ARCHIVE: SFTP upload failed, keeping local copy", err, map[string]interface{}{
 				"local_path": localPath,
@@ -511,7 +756,7 @@ func (s *ArchiveService) uploadToStorageBox(localPath, serverID string) (string,
 }
 
 // downloadFromStorageBox downloads archive from Hetzner Storage Box via SFTP
-func (s *ArchiveService) downloadFromStorageBox(remotePath, localPath string) error {
+func (s *ArchiveService) downloadFromStorageBox(remotePath, localPath string, progress *TransferProgress) error {
 	if s.sftpClient == nil {
 		return fmt.Errorf("SFTP client not available")
 	}
@@ -521,7 +766,7 @@ func (s *ArchiveService) downloadFromStorageBox(remotePath, localPath string) er
 		"local_path":  localPath,
 	})
 
-	if err := s.sftpClient.Download(remotePath, localPath); err != nil {
+	if err := s.sftpClient.DownloadWithProgress(remotePath, localPath, progress.Func()); err != nil {
 		return fmt.Errorf("SFTP download failed: %w", err)
 	}
 
@@ -612,6 +857,9 @@ func (s *ArchiveService) clearArchiveMetadata(serverID string) error {
 	server.ArchiveLocation = ""
 	server.ArchiveSize = 0
 	server.ArchivedAt = nil
+	server.ArchiveTier = models.ArchiveTierHot
+	server.ArchiveColdAt = nil
+	server.ArchiveRestoreRequestedAt = nil
 	server.LifecyclePhase = models.PhaseSleep
 	server.Status = models.StatusStopped
 
@@ -625,24 +873,45 @@ func (s *ArchiveService) clearArchiveMetadata(serverID string) error {
 }
 
 // GAP-7: validateArchiveIntegrity checks if archive is valid before extraction
-// Uses tar -tzf to list contents without extracting (integrity check)
+// Reads the whole tar stream without extracting (decompression checksums + tar headers)
 func (s *ArchiveService) validateArchiveIntegrity(archivePath string) error {
-	cmd := exec.Command("tar", "-tzf", archivePath)
+	archiveFile, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer archiveFile.Close()
 
-	// Run command and check if it succeeds
-	output, err := cmd.CombinedOutput()
+	decompressor, codec, err := compression.NewReader(archiveFile)
 	if err != nil {
-		return fmt.Errorf("archive integrity check failed: %w (output: %s)", err, string(output))
+		return fmt.Errorf("archive integrity check failed: %w", err)
+	}
+	defer decompressor.Close()
+
+	tarReader := tar.NewReader(decompressor)
+	files := 0
+	for {
+		_, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("archive integrity check failed: %w", err)
+		}
+		if _, err := io.Copy(io.Discard, tarReader); err != nil {
+			return fmt.Errorf("archive integrity check failed: %w", err)
+		}
+		files++
 	}
 
 	// Check if archive contains any files
-	if len(output) == 0 {
+	if files == 0 {
 		return fmt.Errorf("archive is empty")
 	}
 
 	logger.Debug("GAP-7: Archive integrity validated", map[string]interface{}{
 		"archive": archivePath,
-		"files":   strings.Count(string(output), "\n"),
+		"codec":   codec,
+		"files":   files,
 	})
 
 	return nil
@@ -17,6 +17,8 @@ type ArchiveWorker struct {
 	archiveService *ArchiveService
 	scanInterval   time.Duration
 	archiveAfter   time.Duration // Duration after which to archive (default: 48h)
+	coldAfter      time.Duration // Idle time after which archives move to cold storage (default: 30 days)
+	policies       *LifecyclePolicyService // Optional: per plan/server thresholds instead of archiveAfter/coldAfter
 	running        bool
 	ctx            context.Context
 	cancel         context.CancelFunc
@@ -32,6 +34,7 @@ func NewArchiveWorker(serverRepo *repository.ServerRepository, archiveService *A
 		archiveService: archiveService,
 		scanInterval:   1 * time.Hour,  // Scan every hour
 		archiveAfter:   48 * time.Hour, // Archive after 48h of sleeping
+		coldAfter:      30 * 24 * time.Hour,
 		running:        false,
 		archivingSet:   make(map[string]bool), // Track in-progress archives
 	}
@@ -117,13 +120,19 @@ func (w *ArchiveWorker) scanAndArchive() {
 		"total_candidates": len(candidateServers),
 	})
 
+	resolve, err := w.resolver()
+	if err != nil {
+		logger.Error("ARCHIVE-WORKER: Failed to resolve lifecycle policies", err, nil)
+		return
+	}
+
 	eligibleCount := 0
 	archivedCount := 0
 	errorCount := 0
 
 	for _, server := range candidateServers {
 		// Check if server is eligible for archiving
-		if !w.isEligibleForArchiving(&server) {
+		if !w.isEligibleForArchiving(&server, resolve(&server)) {
 			continue
 		}
 
@@ -178,10 +187,75 @@ func (w *ArchiveWorker) scanAndArchive() {
 		"errors":           errorCount,
 		"next_scan":        time.Now().Add(w.scanInterval).Format(time.RFC3339),
 	})
+
+	if w.archiveService.ColdStorageEnabled() {
+		w.moveToColdStorage()
+	}
+}
+
+// moveToColdStorage moves the archives of servers idle longer than their cold storage threshold to cold storage
+func (w *ArchiveWorker) moveToColdStorage() {
+	archivedServers, err := w.serverRepo.FindArchivedServers("")
+	if err != nil {
+		logger.Error("ARCHIVE-WORKER: Failed to fetch archived servers", err, nil)
+		return
+	}
+
+	moved, failed := 0, 0
+	resolve, err := w.resolver()
+	if err != nil {
+		logger.Error("ARCHIVE-WORKER: Failed to resolve lifecycle policies", err, nil)
+		return
+	}
+
+	now := time.Now()
+	for _, server := range archivedServers {
+		if w.ctx.Err() != nil {
+			return
+		}
+		coldAfter := resolve(&server).ColdAfter()
+		if coldAfter == 0 || !dueForColdStorage(&server, coldAfter, now) {
+			continue
+		}
+		if err := w.archiveService.MoveToColdStorage(server.ID); err != nil {
+			logger.Error("ARCHIVE-WORKER: Failed to move archive to cold storage", err, map[string]interface{}{
+				"server_id":   server.ID,
+				"server_name": server.Name,
+			})
+			failed++
+			continue
+		}
+		moved++
+	}
+
+	if moved > 0 || failed > 0 {
+		logger.Info("ARCHIVE-WORKER: Cold storage scan completed", map[string]interface{}{
+			"moved":  moved,
+			"failed": failed,
+		})
+	}
+}
+
+// resolver returns the lifecycle thresholds of servers: the lifecycle policies if set, the worker settings otherwise
+func (w *ArchiveWorker) resolver() (func(server *models.MinecraftServer) LifecycleThresholds, error) {
+	if w.policies != nil {
+		return w.policies.Resolver()
+	}
+	return func(server *models.MinecraftServer) LifecycleThresholds {
+		thresholds := LifecycleThresholds{
+			ArchiveAfterHours: int(w.archiveAfter / time.Hour),
+			ColdAfterDays:     int(w.coldAfter / (24 * time.Hour)),
+		}
+		// Reserved plan servers: never auto-archive (customer pays for 24/7 availability)
+		if server.Plan == models.PlanReserved {
+			thresholds.ArchiveAfterHours, thresholds.ColdAfterDays = 0, 0
+		}
+		return thresholds
+	}, nil
 }
 
 // isEligibleForArchiving checks if a server meets archiving criteria
-func (w *ArchiveWorker) isEligibleForArchiving(server *models.MinecraftServer) bool {
+func (w *ArchiveWorker) isEligibleForArchiving(server *models.MinecraftServer, thresholds LifecycleThresholds) bool {
 	// 1. Server must be sleeping or stopped
 	if server.Status != models.StatusSleeping && server.Status != models.StatusStopped {
 		return false
@@ -201,25 +275,26 @@ func (w *ArchiveWorker) isEligibleForArchiving(server *models.MinecraftServer) b
 		return false
 	}
 
-	// 4. Server must have been stopped for at least 48 hours
+	// 4. Archiving must not be disabled for the server (e.g. reserved plan, see lifecycle policies)
+	archiveAfter := thresholds.ArchiveAfter()
+	if archiveAfter == 0 {
+		logger.Debug("ARCHIVE-WORKER: Auto-archive disabled by lifecycle policy", map[string]interface{}{
+			"server_id":   server.ID,
+			"server_name": server.Name,
+			"plan":        server.Plan,
+		})
+		return false
+	}
+
+	// 5. Server must have been stopped for at least the archive threshold (default 48 hours)
 	timeSinceStopped := time.Since(*server.LastStoppedAt)
-	if timeSinceStopped < w.archiveAfter {
+	if timeSinceStopped < archiveAfter {
 		logger.Debug("ARCHIVE-WORKER: Server not stopped long enough", map[string]interface{}{
 			"server_id":      server.ID,
 			"server_name":    server.Name,
 			"stopped_for":    timeSinceStopped.Round(time.Hour),
-			"required":       w.archiveAfter,
-			"time_remaining": (w.archiveAfter - timeSinceStopped).Round(time.Hour),
-		})
-		return false
-	}
-
-	// 5. Reserved plan servers: never auto-archive (customer pays for 24/7 availability)
-	if server.Plan == models.PlanReserved {
-		logger.Debug("ARCHIVE-WORKER: Reserved plan server - skipping auto-archive", map[string]interface{}{
-			"server_id":   server.ID,
-			"server_name": server.Name,
-			"plan":        server.Plan,
+			"required":       archiveAfter,
+			"time_remaining": (archiveAfter - timeSinceStopped).Round(time.Hour),
 		})
 		return false
 	}
@@ -233,6 +308,9 @@ func (w *ArchiveWorker) GetStats() map[string]interface{} {
 		"running":        w.running,
 		"scan_interval":  w.scanInterval.String(),
 		"archive_after":  w.archiveAfter.String(),
+		"cold_after":     w.coldAfter.String(),
+		"cold_storage":   w.archiveService.ColdStorageEnabled(),
+		"lifecycle_policies": w.policies != nil,
 	}
 }
 
@@ -244,6 +322,16 @@ func (w *ArchiveWorker) SetArchiveAfter(duration time.Duration) {
 	})
 }
 
+// SetColdAfter sets the idle time after which archives move to cold storage
+func (w *ArchiveWorker) SetColdAfter(duration time.Duration) {
+	w.coldAfter = duration
+}
+
+// SetLifecyclePolicies makes the worker use the per plan/server lifecycle thresholds
+func (w *ArchiveWorker) SetLifecyclePolicies(policies *LifecyclePolicyService) {
+	w.policies = policies
+}
+
 // SetScanInterval allows configuring the scan interval (for testing)
 func (w *ArchiveWorker) SetScanInterval(duration time.Duration) {
 	w.scanInterval = duration
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/datatypes"
+)
+
+const (
+	auditQueueSize     = 1024
+	auditPruneInterval = 6 * time.Hour
+	auditStopTimeout   = 5 * time.Second
+)
+
+// AuditService persists the audit log in the audit_events table and serves it to admins.
+// Entries of the in-memory audit.AuditLogger (conductor decisions) and user or admin actions
+// recorded with Record are written in order by one worker, so a slow database never blocks the
+// action. Events older than AUDIT_RETENTION_DAYS are deleted.
+// Record is safe on a nil *AuditService (nothing is recorded).
+type AuditService struct {
+	repo      *repository.AuditRepository
+	log       *audit.AuditLogger
+	retention int // Days, 0 = forever
+	queue     chan models.AuditEvent
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAuditService creates a new audit service persisting the entries of log
+func NewAuditService(repo *repository.AuditRepository, log *audit.AuditLogger, cfg *config.Config) *AuditService {
+	s := &AuditService{
+		repo:      repo,
+		log:       log,
+		retention: cfg.AuditRetentionDays,
+		queue:     make(chan models.AuditEvent, auditQueueSize),
+	}
+	log.SetSink(s)
+	return s
+}
+
+// Record adds a user or admin action to the audit log
+func (s *AuditService) Record(entry audit.AuditEntry) {
+	if s == nil {
+		return
+	}
+	if entry.Result == "" {
+		entry.Result = "success"
+	}
+	s.log.Record(entry) // Comes back through Persist
+}
+
+// Persist queues an entry of the audit logger for writing (audit.Sink)
+func (s *AuditService) Persist(entry audit.AuditEntry) {
+	event := models.AuditEvent{
+		Action:       string(entry.Action),
+		ActorID:      entry.ActorID,
+		ActorIP:      entry.ActorIP,
+		DecisionBy:   entry.DecisionBy,
+		ResourceType: entry.ResourceType,
+		ResourceID:   entry.ResourceID,
+		ContainerID:  entry.ContainerID,
+		Reason:       entry.Reason,
+		Result:       entry.Result,
+		Error:        entry.Error,
+		Before:       auditJSON(entry.Before),
+		After:        auditJSON(entry.After),
+		CreatedAt:    entry.Timestamp,
+	}
+	if len(entry.StateSnapshot) > 0 {
+		event.Snapshot = auditJSON(entry.StateSnapshot)
+	}
+
+	select {
+	case s.queue <- event:
+	default:
+		logger.Warn("AUDIT: Write queue full, dropping audit event", map[string]interface{}{
+			"action":      event.Action,
+			"resource_id": event.ResourceID,
+		})
+	}
+}
+
+// List returns one page of the audit events created in [from, to) (zero times leave the range open)
+func (s *AuditService) List(from, to time.Time, page repository.PageRequest) (*repository.Page[models.AuditEvent], error) {
+	return s.repo.FindPage(from, to, page)
+}
+
+// Start writes queued events and prunes old ones
+func (s *AuditService) Start() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(auditPruneInterval)
+		defer ticker.Stop()
+
+		s.prune()
+		for {
+			select {
+			case event := <-s.queue:
+				s.write(event)
+			case <-ticker.C:
+				s.prune()
+			case <-s.ctx.Done():
+				s.drain()
+				return
+			}
+		}
+	}()
+}
+
+// Stop writes the remaining events and halts the worker
+func (s *AuditService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	select {
+	case <-s.done:
+	case <-time.After(auditStopTimeout):
+		logger.Warn("AUDIT: Timed out writing the remaining audit events", nil)
+	}
+}
+
+// drain writes the events still queued at shutdown
+func (s *AuditService) drain() {
+	for {
+		select {
+		case event := <-s.queue:
+			s.write(event)
+		default:
+			return
+		}
+	}
+}
+
+// write saves an event
+func (s *AuditService) write(event models.AuditEvent) {
+	if err := s.repo.Create(&event); err != nil {
+		logger.Warn("AUDIT: Failed to save audit event", map[string]interface{}{
+			"action":      event.Action,
+			"resource_id": event.ResourceID,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// prune deletes events older than the retention
+func (s *AuditService) prune() {
+	if s.retention <= 0 {
+		return
+	}
+	deleted, err := s.repo.DeleteBefore(time.Now().AddDate(0, 0, -s.retention))
+	if err != nil {
+		logger.Warn("AUDIT: Failed to prune audit log", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if deleted > 0 {
+		logger.Info("AUDIT: Pruned audit log", map[string]interface{}{
+			"deleted": deleted,
+		})
+	}
+}
+
+// auditJSON encodes a snapshot (nil stays empty)
+func auditJSON(value interface{}) datatypes.JSON {
+	if value == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return datatypes.JSON(encoded)
+}
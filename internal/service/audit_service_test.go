@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+func TestAuditServiceQueuesLoggerEntries(t *testing.T) {
+	auditLog := audit.NewAuditLogger(10)
+	s := NewAuditService(nil, auditLog, &config.Config{})
+
+	// Conductor decisions reach the database through the logger
+	auditLog.RecordNodeDecommission("node-1", "idle", "consolidation_policy", map[string]interface{}{"containers": 0}, "success", nil)
+	event := <-s.queue
+	if event.Action != "node_decommission" || event.ResourceType != "node" || event.ResourceID != "node-1" || event.ActorID != "" {
+		t.Errorf("decommission event = %+v", event)
+	}
+	if string(event.Snapshot) != `{"containers":0}` || event.CreatedAt.IsZero() {
+		t.Errorf("decommission snapshot = %s at %v", event.Snapshot, event.CreatedAt)
+	}
+
+	s.Record(audit.AuditEntry{
+		Action:       audit.ActionServerCPULimits,
+		ActorID:      "admin-1",
+		ResourceType: "server",
+		ResourceID:   "server-1",
+		Before:       map[string]int{"cpu_shares": 0},
+		After:        map[string]int{"cpu_shares": 2048},
+	})
+	event = <-s.queue
+	if event.Result != "success" || string(event.Before) != `{"cpu_shares":0}` || string(event.After) != `{"cpu_shares":2048}` || event.Snapshot != nil {
+		t.Errorf("admin event = %+v", event)
+	}
+	if recent := auditLog.GetRecent(1); len(recent) != 1 || recent[0].ActorID != "admin-1" {
+		t.Errorf("in-memory log = %+v", recent)
+	}
+
+	var disabled *AuditService
+	disabled.Record(audit.AuditEntry{Action: audit.ActionServerDelete}) // No audit service configured
+}
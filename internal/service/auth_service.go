@@ -18,6 +18,7 @@ type AuthService struct {
 	cfg             *config.Config
 	emailService    *EmailService
 	securityService *SecurityService
+	twoFactor       *TwoFactorService
 }
 
 // NewAuthService creates a new auth service
@@ -30,11 +31,21 @@ func NewAuthService(userRepo *repository.UserRepository, cfg *config.Config, ema
 	}
 }
 
+// SetTwoFactorService enables the 2FA step during login
+func (s *AuthService) SetTwoFactorService(twoFactor *TwoFactorService) {
+	s.twoFactor = twoFactor
+}
+
+// twoFactorChallengePurpose marks short-lived tokens that only allow completing a 2FA login
+const twoFactorChallengePurpose = "2fa_challenge"
+
 // Claims represents JWT claims
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	IsAdmin bool  `json:"is_admin"`
+	UserID  string `json:"user_id"`
+	Email   string `json:"email"`
+	IsAdmin bool   `json:"is_admin"`
+	Purpose string `json:"purpose,omitempty"` // Empty for session tokens
+	SSOOrg  string `json:"sso_org,omitempty"` // Organization whose SSO connection issued the session
 	jwt.RegisteredClaims
 }
 
@@ -130,6 +141,80 @@ func (s *AuthService) Login(email, password, userAgent, ipAddress string) (strin
 		return "", nil, false, models.ErrInvalidCredentials
 	}
 
+	// Password is correct - a second factor is still required if 2FA is enabled
+	if user.TwoFactorEnabled {
+		challenge, err := s.GenerateTwoFactorChallenge(user)
+		if err != nil {
+			return "", nil, false, err
+		}
+		return challenge, user, false, models.ErrTwoFactorRequired
+	}
+
+	return s.completeLogin(user, userAgent, ipAddress)
+}
+
+// CompleteTwoFactorLogin finishes a login with the challenge token from Login and a 2FA or recovery code
+func (s *AuthService) CompleteTwoFactorLogin(challengeToken, code, userAgent, ipAddress string) (string, *models.User, bool, error) {
+	if s.twoFactor == nil {
+		return "", nil, false, models.ErrTwoFactorNotEnabled
+	}
+
+	claims, err := s.parseToken(challengeToken)
+	if err != nil || claims.Purpose != twoFactorChallengePurpose {
+		return "", nil, false, models.ErrInvalidChallengeToken
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return "", nil, false, models.ErrInvalidChallengeToken
+	}
+	if !user.IsActive {
+		return "", nil, false, errors.New("account is deactivated")
+	}
+	if user.IsLocked() {
+		_ = s.securityService.LogSecurityEvent(user.ID, models.EventLoginFailure, ipAddress, userAgent, false, "Account is locked")
+		return "", nil, false, models.ErrAccountLocked
+	}
+
+	if err := s.twoFactor.VerifyCode(user.ID, code, ipAddress, userAgent); err != nil {
+		if !errors.Is(err, models.ErrInvalidTwoFactorCode) {
+			return "", nil, false, err
+		}
+
+		// Wrong codes count towards the account lockout like wrong passwords
+		lockDuration := user.IncrementFailedLogins()
+		if err := s.userRepo.Update(user); err != nil {
+			return "", nil, false, err
+		}
+		if lockDuration > 0 {
+			_ = s.securityService.LogSecurityEvent(user.ID, models.EventAccountLocked, ipAddress, userAgent, true, "")
+			_ = s.securityService.SendAccountLockedAlert(user, lockDuration)
+		}
+		return "", nil, false, models.ErrInvalidTwoFactorCode
+	}
+
+	return s.completeLogin(user, userAgent, ipAddress)
+}
+
+// GenerateTwoFactorChallenge issues a short-lived token that can only be exchanged via CompleteTwoFactorLogin
+func (s *AuthService) GenerateTwoFactorChallenge(user *models.User) (string, error) {
+	claims := &Claims{
+		UserID:  user.ID,
+		Email:   user.Email,
+		Purpose: twoFactorChallengePurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "payperplay",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.cfg.JWTSecret))
+}
+
+// completeLogin records a successful login and issues the session token
+func (s *AuthService) completeLogin(user *models.User, userAgent, ipAddress string) (string, *models.User, bool, error) {
 	// Check if this is a trusted device
 	_, isTrusted := s.securityService.CheckTrustedDevice(user.ID, userAgent, ipAddress)
 
@@ -182,8 +267,47 @@ func (s *AuthService) GenerateToken(user *models.User) (string, error) {
 	return tokenString, nil
 }
 
+// GenerateSSOToken generates a session token for a login through an organization's SSO connection
+// maxAge shortens the token lifetime to the organization's session policy (0 = default lifetime).
+func (s *AuthService) GenerateSSOToken(user *models.User, orgID string, maxAge time.Duration) (string, error) {
+	lifetime := 24 * time.Hour
+	if maxAge > 0 && maxAge < lifetime {
+		lifetime = maxAge
+	}
+
+	claims := &Claims{
+		UserID:  user.ID,
+		Email:   user.Email,
+		IsAdmin: user.IsAdmin,
+		SSOOrg:  orgID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(lifetime)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "payperplay",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.cfg.JWTSecret))
+}
+
 // ValidateToken validates a JWT token and returns the claims
 func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Challenge tokens are not session tokens
+	if claims.Purpose != "" {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// parseToken verifies a JWT signature and expiry and returns its claims
+func (s *AuthService) parseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		// Verify signing method
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -215,6 +339,11 @@ func (s *AuthService) RefreshToken(tokenString string) (string, error) {
 		return "", err
 	}
 
+	// SSO sessions end with their policy; a new one requires signing in at the identity provider
+	if claims.SSOOrg != "" {
+		return "", models.ErrSSOSessionExpired
+	}
+
 	// Get fresh user data
 	user, err := s.userRepo.FindByID(claims.UserID)
 	if err != nil {
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// Legal hold errors
+var (
+	ErrBackupLegalHold         = errors.New("backup is under legal hold")
+	ErrLegalHoldNotFound       = errors.New("legal hold not found or already released")
+	ErrLegalHoldReasonRequired = errors.New("a reason is required for a legal hold")
+)
+
+// PlaceLegalHold holds a backup, or all backups of a server when backupID is empty, until an admin releases it
+func (s *BackupService) PlaceLegalHold(serverID, backupID, reason, adminID, ipAddress, userAgent string) (*models.BackupLegalHold, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrLegalHoldReasonRequired
+	}
+
+	if backupID != "" {
+		backup, err := s.backupRepo.FindByID(backupID)
+		if err != nil {
+			return nil, fmt.Errorf("backup not found: %w", err)
+		}
+		if serverID != "" && serverID != backup.ServerID {
+			return nil, fmt.Errorf("backup %s does not belong to server %s", backupID, serverID)
+		}
+		serverID = backup.ServerID
+	} else if serverID == "" {
+		return nil, errors.New("server_id or backup_id is required")
+	} else if _, err := s.serverRepo.FindByID(serverID); err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	hold := &models.BackupLegalHold{
+		ServerID: serverID,
+		BackupID: backupID,
+		Reason:   reason,
+		PlacedBy: adminID,
+	}
+	if err := s.backupRepo.CreateLegalHold(hold); err != nil {
+		return nil, fmt.Errorf("failed to create legal hold: %w", err)
+	}
+
+	logger.Info("BACKUP-SERVICE: Legal hold placed", map[string]interface{}{
+		"hold_id":   hold.ID,
+		"server_id": serverID,
+		"backup_id": backupID,
+		"placed_by": adminID,
+	})
+	s.auditLegalHold(adminID, models.EventLegalHoldPlaced, hold, ipAddress, userAgent)
+
+	return hold, nil
+}
+
+// ReleaseLegalHold lifts a legal hold; the held backups become subject to retention again
+func (s *BackupService) ReleaseLegalHold(holdID, adminID, ipAddress, userAgent string) (*models.BackupLegalHold, error) {
+	if err := s.backupRepo.ReleaseLegalHold(holdID, adminID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLegalHoldNotFound
+		}
+		return nil, fmt.Errorf("failed to release legal hold: %w", err)
+	}
+
+	hold, err := s.backupRepo.FindLegalHoldByID(holdID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load legal hold: %w", err)
+	}
+
+	logger.Info("BACKUP-SERVICE: Legal hold released", map[string]interface{}{
+		"hold_id":     hold.ID,
+		"server_id":   hold.ServerID,
+		"backup_id":   hold.BackupID,
+		"released_by": adminID,
+	})
+	s.auditLegalHold(adminID, models.EventLegalHoldReleased, hold, ipAddress, userAgent)
+
+	return hold, nil
+}
+
+// ListLegalHolds lists legal holds, optionally for one server and including released holds (hold history)
+func (s *BackupService) ListLegalHolds(serverID string, includeReleased bool) ([]models.BackupLegalHold, error) {
+	return s.backupRepo.FindLegalHolds(serverID, includeReleased)
+}
+
+// auditLegalHold records a hold change in the security audit log
+func (s *BackupService) auditLegalHold(adminID string, eventType models.SecurityEventType, hold *models.BackupLegalHold, ipAddress, userAgent string) {
+	if s.security == nil {
+		return
+	}
+
+	target := "server " + hold.ServerID
+	if !hold.CoversServer() {
+		target = "backup " + hold.BackupID + " of " + target
+	}
+	_ = s.security.LogSecurityEvent(adminID, eventType, ipAddress, userAgent, true,
+		fmt.Sprintf("Legal hold %s on %s: %s", hold.ID, target, hold.Reason))
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -218,7 +219,12 @@ func (s *BackupScheduler) cleanupOldBackups(serverID string, maxBackups int) err
 				"backup_id": backups[i].ID,
 			})
 
-			if err := s.backupService.DeleteBackup(backups[i].ID); err != nil {
+			if err := s.backupService.DeleteBackup(backups[i].ID); errors.Is(err, ErrBackupLegalHold) {
+				logger.Info("Keeping old backup under legal hold", map[string]interface{}{
+					"server_id": serverID,
+					"backup_id": backups[i].ID,
+				})
+			} else if err != nil {
 				logger.Warn("Failed to delete old backup", map[string]interface{}{
 					"server_id": serverID,
 					"backup_id": backups[i].ID,
@@ -2,7 +2,8 @@ package service
 
 import (
 	"archive/tar"
-	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -11,12 +12,16 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/payperplay/hosting/internal/compression"
 	"github.com/payperplay/hosting/internal/docker"
 	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/monitoring"
 	"github.com/payperplay/hosting/internal/repository"
 	"github.com/payperplay/hosting/internal/storage"
+	"github.com/payperplay/hosting/internal/tracing"
 	"github.com/payperplay/hosting/pkg/config"
 	"github.com/payperplay/hosting/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // BackupService handles server backups with SFTP integration
@@ -27,6 +32,15 @@ type BackupService struct {
 	sftpClient    *storage.SFTPClient
 	storagePath   string
 	quotaService  *BackupQuotaService
+	sshPool       *docker.SSHPool // Pooled SSH connections for remote restores (optional, falls back to ssh/scp)
+	compression   compression.Options // Default codec/level/workers for new backups
+	wsHub         WebSocketHubInterface // Optional: byte-level progress for users watching a backup/restore
+	opLimiter     *OperationLimiter     // Optional: per-owner concurrency limit for manual backups and requested restores
+	controlPlane  *ControlPlaneMonitor  // Optional: defers compression and restores while the control plane is under pressure
+	webhooks      *WebhookService       // Optional: Discord/Slack notification of failed backups
+	residency     *ResidencyService     // Optional: blocks backups of residency-bound servers to storage in other countries
+	storageCountry string               // Country backups are stored in (Storage Box or local), "" = unknown
+	security      *SecurityService      // Optional: records legal hold changes in the security audit log
 }
 
 // NewBackupService creates a new backup service
@@ -43,6 +57,20 @@ func NewBackupService(
 		dockerService: dockerService,
 		storagePath:   filepath.Join(cfg.ServersBasePath, ".backups"),
 		quotaService:  quotaService,
+		storageCountry: cfg.LocalStorageCountry,
+		compression: compression.Options{
+			Codec:   compression.Codec(cfg.BackupCompression),
+			Level:   cfg.BackupCompressionLevel,
+			Workers: cfg.CompressionWorkers,
+		},
+	}
+
+	if err := service.compression.Validate(); err != nil {
+		logger.Warn("BACKUP-SERVICE: Invalid compression settings, using gzip defaults", map[string]interface{}{
+			"error": err.Error(),
+		})
+		service.compression = compression.Options{Codec: compression.CodecGzip, Workers: cfg.CompressionWorkers}
+		service.compression.Validate()
 	}
 
 	// Initialize SFTP client if enabled
@@ -54,6 +82,7 @@ func NewBackupService(
 			})
 		} else {
 			service.sftpClient = sftpClient
+			service.storageCountry = cfg.StorageBoxCountry
 			logger.Info("BACKUP-SERVICE: SFTP client initialized for Storage Box backups", nil)
 		}
 	}
@@ -80,20 +109,55 @@ func (s *BackupService) CreateBackup(
 	userID *string,
 	retentionDays int,
 ) (*models.Backup, error) {
+	backup, _, err := s.CreateBackupWithCompression(context.Background(), serverID, backupType, description, userID, retentionDays, s.compression)
+	return backup, err
+}
+
+// DefaultCompression returns the codec settings used for backups without explicit options
+func (s *BackupService) DefaultCompression() compression.Options {
+	return s.compression
+}
+
+// CreateBackupWithCompression creates a new backup with an explicit codec/level
+// (Workers <= 0 falls back to the configured parallelism)
+// Manual backups count against the owner's concurrency limit: the returned operation
+// is queued (backup stays pending) while the owner is at the limit, nil for other types.
+// ctx only carries the trace of the request, the backup runs on after it.
+func (s *BackupService) CreateBackupWithCompression(
+	ctx context.Context,
+	serverID string,
+	backupType models.BackupType,
+	description string,
+	userID *string,
+	retentionDays int,
+	opts compression.Options,
+) (*models.Backup, *Operation, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = s.compression.Workers
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
+	}
+
 	// Validate server exists
 	server, err := s.serverRepo.FindByID(serverID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find server: %w", err)
+		return nil, nil, fmt.Errorf("failed to find server: %w", err)
+	}
+
+	// Data residency: the backup must be stored in the organization's country
+	if err := s.residency.CheckStorage(server, s.storageCountry, "backup"); err != nil {
+		return nil, nil, err
 	}
 
 	// Check quota limits for manual backups
 	if userID != nil && backupType == models.BackupTypeManual && s.quotaService != nil {
 		canCreate, reason, err := s.quotaService.CanCreateBackup(*userID, backupType)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check backup quota: %w", err)
+			return nil, nil, fmt.Errorf("failed to check backup quota: %w", err)
 		}
 		if !canCreate {
-			return nil, fmt.Errorf("backup quota exceeded: %s", reason)
+			return nil, nil, fmt.Errorf("backup quota exceeded: %s", reason)
 		}
 	}
 
@@ -113,6 +177,8 @@ func (s *BackupService) CreateBackup(
 		MinecraftVersion: server.MinecraftVersion,
 		ServerType:       string(server.ServerType),
 		RAMMb:            server.RAMMb,
+		Compression:      string(opts.Codec),
+		CompressionLevel: opts.Level,
 		UserID:           userID,
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
@@ -120,7 +186,7 @@ func (s *BackupService) CreateBackup(
 
 	// Save to database
 	if err := s.backupRepo.Create(backup); err != nil {
-		return nil, fmt.Errorf("failed to create backup record: %w", err)
+		return nil, nil, fmt.Errorf("failed to create backup record: %w", err)
 	}
 
 	logger.Info("BACKUP-SERVICE: Backup created", map[string]interface{}{
@@ -129,17 +195,47 @@ func (s *BackupService) CreateBackup(
 		"server_name": server.Name,
 		"type":        backupType,
 		"retention":   retentionDays,
+		"compression": opts.Codec,
 	})
 
+	requestedBy := ""
+	if userID != nil {
+		requestedBy = *userID
+	}
+	run := func(opCtx context.Context) error {
+		s.performBackup(tracing.Inherit(opCtx, ctx), backup, server)
+		switch backup.Status {
+		case models.BackupStatusCancelled:
+			return context.Canceled
+		case models.BackupStatusFailed:
+			return fmt.Errorf("backup failed: %s", backup.ErrorMessage)
+		}
+		return nil
+	}
+
 	// Perform backup asynchronously
-	go s.performBackup(backup, server)
+	// Manual backups wait for a free slot of the owner; system backups (scheduled, pre-*) are
+	// not limited because other operations wait for them, but are tracked so they can be cancelled.
+	if backupType != models.BackupTypeManual || s.opLimiter == nil {
+		go s.opLimiter.Run(server.OwnerID, requestedBy, OperationBackup, serverID, backup.ID, run)
+		return backup, nil, nil
+	}
 
-	return backup, nil
+	op, err := s.opLimiter.Go(server.OwnerID, requestedBy, OperationBackup, serverID, backup.ID, run)
+	if err != nil {
+		// Queue full: drop the record so the rejected request doesn't count against the daily quota
+		s.backupRepo.Delete(backup.ID)
+		return nil, nil, err
+	}
+
+	return backup, op, nil
 }
 
 // CreateBackupSync creates a backup and waits for it to complete (synchronous)
-// This is used for migrations where we need to ensure the backup is complete
+// This is used for migrations where we need to ensure the backup is complete.
+// Cancelling ctx aborts the backup (see performBackup) and returns context.Canceled.
 func (s *BackupService) CreateBackupSync(
+	ctx context.Context,
 	serverID string,
 	backupType models.BackupType,
 	description string,
@@ -152,6 +248,11 @@ func (s *BackupService) CreateBackupSync(
 		return nil, fmt.Errorf("failed to find server: %w", err)
 	}
 
+	// Data residency: the backup must be stored in the organization's country
+	if err := s.residency.CheckStorage(server, s.storageCountry, "backup"); err != nil {
+		return nil, err
+	}
+
 	// Set default retention based on type
 	if retentionDays == 0 {
 		retentionDays = s.getDefaultRetentionDays(backupType)
@@ -168,6 +269,8 @@ func (s *BackupService) CreateBackupSync(
 		MinecraftVersion: server.MinecraftVersion,
 		ServerType:       string(server.ServerType),
 		RAMMb:            server.RAMMb,
+		Compression:      string(s.compression.Codec),
+		CompressionLevel: s.compression.Level,
 		UserID:           userID,
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
@@ -186,7 +289,7 @@ func (s *BackupService) CreateBackupSync(
 	})
 
 	// Perform backup synchronously (wait for completion)
-	s.performBackup(backup, server)
+	s.performBackup(ctx, backup, server)
 
 	// Reload backup from database to get updated status
 	backup, err = s.backupRepo.FindByID(backup.ID)
@@ -195,6 +298,9 @@ func (s *BackupService) CreateBackupSync(
 	}
 
 	// Check if backup succeeded
+	if backup.Status == models.BackupStatusCancelled {
+		return nil, fmt.Errorf("backup cancelled: %w", context.Canceled)
+	}
 	if backup.Status != models.BackupStatusCompleted {
 		return nil, fmt.Errorf("backup failed: %s", backup.ErrorMessage)
 	}
@@ -203,7 +309,19 @@ func (s *BackupService) CreateBackupSync(
 }
 
 // performBackup performs the actual backup operation
-func (s *BackupService) performBackup(backup *models.Backup, server *models.MinecraftServer) {
+// Cancelling ctx aborts compression between files or the upload, removes the partial files and marks the backup cancelled.
+func (s *BackupService) performBackup(ctx context.Context, backup *models.Backup, server *models.MinecraftServer) {
+	ctx, span := tracing.Start(ctx, "BackupService.CreateBackup",
+		attribute.String("backup.id", backup.ID), attribute.String("server.id", server.ID), attribute.String("backup.type", string(backup.Type)))
+	defer func() {
+		if backup.Status == models.BackupStatusFailed {
+			tracing.End(span, errors.New(backup.ErrorMessage))
+			return
+		}
+		span.SetAttributes(attribute.String("backup.status", string(backup.Status)))
+		span.End()
+	}()
+
 	// Update status to creating
 	backup.Status = models.BackupStatusCreating
 	backup.UpdatedAt = time.Now()
@@ -222,7 +340,7 @@ func (s *BackupService) performBackup(backup *models.Backup, server *models.Mine
 			"backup_id":    backup.ID,
 			"container_id": server.ContainerID,
 		})
-		if err := s.dockerService.PauseContainer(server.ContainerID); err != nil {
+		if err := s.dockerService.PauseContainer(ctx, server.ContainerID); err != nil {
 			logger.Warn("BACKUP-SERVICE: Failed to pause container, continuing anyway", map[string]interface{}{
 				"backup_id":    backup.ID,
 				"container_id": server.ContainerID,
@@ -240,7 +358,8 @@ func (s *BackupService) performBackup(backup *models.Backup, server *models.Mine
 				"backup_id":    backup.ID,
 				"container_id": server.ContainerID,
 			})
-			if err := s.dockerService.UnpauseContainer(server.ContainerID); err != nil {
+			// Not cancelled with the backup, the server must not stay paused
+			if err := s.dockerService.UnpauseContainer(context.WithoutCancel(ctx), server.ContainerID); err != nil {
 				logger.Error("BACKUP-SERVICE: Failed to unpause container", err, map[string]interface{}{
 					"backup_id":    backup.ID,
 					"container_id": server.ContainerID,
@@ -267,33 +386,75 @@ func (s *BackupService) performBackup(backup *models.Backup, server *models.Mine
 	}
 	backup.OriginalSize = originalSize
 
-	// 3. Create compressed backup locally
-	localPath := filepath.Join(s.storagePath, fmt.Sprintf("%s.tar.gz", backup.ID))
-	compressedSize, err := s.compressServerData(serverPath, localPath)
+	// 3. Create compressed backup locally (codec chosen at creation)
+	// Compression is the heaviest local step - wait while the control plane is out of headroom
+	if err := s.controlPlane.WaitForHeadroom(ctx, "backup "+backup.ID); err != nil {
+		if ctx.Err() != nil {
+			s.markBackupCancelled(backup)
+			return
+		}
+		s.markBackupFailed(backup, fmt.Sprintf("backup deferred too long: %v", err))
+		return
+	}
+	progress := NewTransferProgress("backup", backup.ID, server.ID, s.wsHub).ReportTo(s.opLimiter, OperationBackup)
+	progress.StartPhase("compressing", originalSize)
+
+	opts := s.compressionFor(backup)
+	opts.Workers = s.controlPlane.CompressionWorkers(opts.Workers)
+	localPath := filepath.Join(s.storagePath, backup.ID+compression.Extension(opts.Codec))
+	compressStart := time.Now()
+	_, compressSpan := tracing.Start(ctx, "BackupService.Compress", attribute.String("codec", string(opts.Codec)), attribute.Int64("original_bytes", originalSize))
+	compressedSize, err := s.compressServerData(ctx, serverPath, localPath, opts, progress)
+	tracing.End(compressSpan, err)
 	if err != nil {
+		os.Remove(localPath)
+		if ctx.Err() != nil {
+			s.markBackupCancelled(backup)
+			return
+		}
 		s.markBackupFailed(backup, fmt.Sprintf("failed to compress data: %v", err))
 		return
 	}
 	backup.CompressedSize = compressedSize
+	backup.CompressionTime = int(time.Since(compressStart).Seconds())
 
 	logger.Info("BACKUP-SERVICE: Server data compressed", map[string]interface{}{
 		"backup_id":        backup.ID,
 		"original_mb":      originalSize / 1024 / 1024,
 		"compressed_mb":    compressedSize / 1024 / 1024,
 		"compression_pct":  backup.GetCompressionRatio(),
+		"codec":            opts.Codec,
+		"duration_s":       backup.CompressionTime,
 	})
 
+	if ctx.Err() != nil {
+		os.Remove(localPath)
+		s.markBackupCancelled(backup)
+		return
+	}
+
 	// 4. Upload to Storage Box (or keep locally)
-	remotePath, err := s.uploadBackup(localPath, backup.ID)
+	progress.StartPhase("uploading", compressedSize)
+	uploadCtx, uploadSpan := tracing.Start(ctx, "BackupService.Upload", attribute.Int64("compressed_bytes", compressedSize))
+	remotePath, err := s.uploadBackup(uploadCtx, localPath, backup.ID, progress)
+	tracing.End(uploadSpan, err)
 	if err != nil {
+		os.Remove(localPath)
+		if ctx.Err() != nil {
+			s.markBackupCancelled(backup)
+			return
+		}
 		s.markBackupFailed(backup, fmt.Sprintf("failed to upload backup: %v", err))
 		return
 	}
 	backup.StoragePath = remotePath
+	progress.Finish()
 
-	// 5. Set expiration time
-	expiresAt := backup.CalculateExpiresAt()
-	backup.ExpiresAt = &expiresAt
+	// 5. Set expiration time (none for backups kept forever)
+	if backup.RetentionDays > 0 {
+		expiresAt := backup.CalculateExpiresAt()
+		backup.ExpiresAt = &expiresAt
+	}
 
 	// 6. Mark as completed
 	backup.Status = models.BackupStatusCompleted
@@ -312,12 +473,26 @@ func (s *BackupService) performBackup(backup *models.Backup, server *models.Mine
 		"server_id":      server.ID,
 		"compressed_mb":  compressedSize / 1024 / 1024,
 		"storage_path":   remotePath,
-		"expires_at":     expiresAt.Format(time.RFC3339),
+		"expires_at":     backup.ExpiresAt,
 	})
 }
 
-// compressServerData compresses server directory to tar.gz
-func (s *BackupService) compressServerData(sourcePath, targetPath string) (int64, error) {
+// compressionFor returns the codec settings stored on a backup record
+func (s *BackupService) compressionFor(backup *models.Backup) compression.Options {
+	opts := compression.Options{
+		Codec:   compression.Codec(backup.Compression),
+		Level:   backup.CompressionLevel,
+		Workers: s.compression.Workers,
+	}
+	if err := opts.Validate(); err != nil {
+		return s.compression
+	}
+	return opts
+}
+
+// compressServerData compresses server directory to a tar stream with the given codec
+// progress (optional) counts uncompressed bytes read from the source files; ctx is checked between files
+func (s *BackupService) compressServerData(ctx context.Context, sourcePath, targetPath string, opts compression.Options, progress *TransferProgress) (int64, error) {
 	startTime := time.Now()
 
 	// Create output file
@@ -327,12 +502,15 @@ func (s *BackupService) compressServerData(sourcePath, targetPath string) (int64
 	}
 	defer outFile.Close()
 
-	// Create gzip writer
-	gzWriter := gzip.NewWriter(outFile)
-	defer gzWriter.Close()
+	// Create compressor
+	compressor, err := compression.NewWriter(outFile, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create compressor: %w", err)
+	}
+	defer compressor.Close()
 
 	// Create tar writer
-	tarWriter := tar.NewWriter(gzWriter)
+	tarWriter := tar.NewWriter(compressor)
 	defer tarWriter.Close()
 
 	// Walk directory and add files
@@ -340,6 +518,9 @@ func (s *BackupService) compressServerData(sourcePath, targetPath string) (int64
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		// Create tar header
 		header, err := tar.FileInfoHeader(info, "")
@@ -367,7 +548,7 @@ func (s *BackupService) compressServerData(sourcePath, targetPath string) (int64
 			}
 			defer file.Close()
 
-			if _, err := io.Copy(tarWriter, file); err != nil {
+			if _, err := io.Copy(tarWriter, progress.Reader(file)); err != nil {
 				return fmt.Errorf("failed to write file to tar: %w", err)
 			}
 		}
@@ -379,6 +560,14 @@ func (s *BackupService) compressServerData(sourcePath, targetPath string) (int64
 		return 0, fmt.Errorf("failed to compress directory: %w", err)
 	}
 
+	// Flush tar trailer and compressor before measuring the file
+	if err := tarWriter.Close(); err != nil {
+		return 0, fmt.Errorf("failed to finalize tar stream: %w", err)
+	}
+	if err := compressor.Close(); err != nil {
+		return 0, fmt.Errorf("failed to finalize compression: %w", err)
+	}
+
 	// Get compressed file size
 	fileInfo, err := outFile.Stat()
 	if err != nil {
@@ -391,18 +580,23 @@ func (s *BackupService) compressServerData(sourcePath, targetPath string) (int64
 		"target":      targetPath,
 		"size_mb":     fileInfo.Size() / 1024 / 1024,
 		"duration_s":  duration.Seconds(),
+		"codec":       opts.Codec,
+		"workers":     opts.Workers,
 	})
 
 	return fileInfo.Size(), nil
 }
 
-// uploadBackup uploads backup to Storage Box or keeps locally
-func (s *BackupService) uploadBackup(localPath, backupID string) (string, error) {
-	remoteName := fmt.Sprintf("backup-%s.tar.gz", backupID)
+// uploadBackup uploads backup to Storage Box or keeps locally (cancelling ctx aborts the upload)
+func (s *BackupService) uploadBackup(ctx context.Context, localPath, backupID string, progress *TransferProgress) (string, error) {
+	remoteName := "backup-" + filepath.Base(localPath) // Keeps the codec extension
 
 	// If SFTP enabled, upload to Storage Box
 	if s.sftpClient != nil {
-		remotePath, err := s.sftpClient.Upload(localPath, remoteName)
+		remotePath, err := s.sftpClient.UploadContext(ctx, localPath, remoteName, progress.Func())
+		if ctx.Err() != nil {
+			return "", ctx.Err() // Cancelled: the partial remote file is already removed
+		}
 		if err != nil {
 			logger.Warn("BACKUP-SERVICE: SFTP upload failed, falling back to local storage", map[string]interface{}{
 				"backup_id": backupID,
@@ -430,7 +624,11 @@ func (s *BackupService) uploadBackup(localPath, backupID string) (string, error)
 
 // RestoreBackup restores a backup to a server directory
 // userID is optional - if provided, quota limits will be checked and restore will be tracked
-func (s *BackupService) RestoreBackup(backupID string, targetServerID string, userID *string) error {
+// Cancelling ctx aborts the restore until extraction starts (extraction overwrites files in place and always runs to the end).
+func (s *BackupService) RestoreBackup(ctx context.Context, backupID string, targetServerID string, userID *string) (err error) {
+	ctx, span := tracing.Start(ctx, "BackupService.RestoreBackup", attribute.String("backup.id", backupID), attribute.String("server.id", targetServerID))
+	defer func() { tracing.End(span, err) }()
+
 	// Find backup record
 	backup, err := s.backupRepo.FindByID(backupID)
 	if err != nil {
@@ -441,6 +639,11 @@ func (s *BackupService) RestoreBackup(backupID string, targetServerID string, us
 		return fmt.Errorf("backup is not in completed state: %s", backup.Status)
 	}
 
+	// Restores download and extract on the control plane - reject them under critical pressure
+	if err := s.controlPlane.CheckHeadroom("restore " + backupID); err != nil {
+		return err
+	}
+
 	// Check restore quota if userID provided
 	if userID != nil && s.quotaService != nil {
 		canRestore, reason, err := s.quotaService.CanRestoreBackup(*userID)
@@ -459,14 +662,21 @@ func (s *BackupService) RestoreBackup(backupID string, targetServerID string, us
 		"user_id":          userID,
 	})
 
+	progress := NewTransferProgress("restore", backupID, targetServerID, s.wsHub).ReportTo(s.opLimiter, OperationRestore)
+
 	// Determine if backup is on Storage Box or local
 	isRemote := s.sftpClient != nil && !filepath.IsAbs(backup.StoragePath)
 
 	var localPath string
 	if isRemote {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("restore cancelled: %w", err)
+		}
+
 		// Download from Storage Box
-		localPath = filepath.Join(s.storagePath, fmt.Sprintf("restore-%s.tar.gz", backupID))
-		if err := s.sftpClient.Download(backup.StoragePath, localPath); err != nil {
+		progress.StartPhase("downloading", backup.CompressedSize)
+		localPath = filepath.Join(s.storagePath, "restore-"+backupID+compression.Extension(s.compressionFor(backup).Codec))
+		if err := s.sftpClient.DownloadWithProgress(backup.StoragePath, localPath, progress.Func()); err != nil {
 			return fmt.Errorf("failed to download backup from Storage Box: %w", err)
 		}
 		defer os.Remove(localPath) // Cleanup after restore
@@ -475,11 +685,21 @@ func (s *BackupService) RestoreBackup(backupID string, targetServerID string, us
 		localPath = backup.StoragePath
 	}
 
+	// Last cancellation point: nothing in the server directory has been touched yet
+	if err := ctx.Err(); err != nil {
+		logger.Info("BACKUP-SERVICE: Restore cancelled before extraction", map[string]interface{}{
+			"backup_id":        backupID,
+			"target_server_id": targetServerID,
+		})
+		return fmt.Errorf("restore cancelled: %w", err)
+	}
+
 	// Extract to server directory
 	targetPath := filepath.Join(s.storagePath, "..", targetServerID)
-	if err := s.extractBackup(localPath, targetPath); err != nil {
+	if err := s.extractBackup(localPath, targetPath, progress); err != nil {
 		return fmt.Errorf("failed to extract backup: %w", err)
 	}
+	progress.Finish()
 
 	// Track restore operation for quota management
 	if userID != nil && s.quotaService != nil {
@@ -520,6 +740,65 @@ func (s *BackupService) RestoreBackup(backupID string, targetServerID string, us
 	return nil
 }
 
+// SetWebSocketHub sets the hub that receives transfer progress events
+func (s *BackupService) SetWebSocketHub(wsHub WebSocketHubInterface) {
+	s.wsHub = wsHub
+}
+
+// SetOperationLimiter sets the per-owner limiter for manual backups and RequestRestore
+func (s *BackupService) SetOperationLimiter(opLimiter *OperationLimiter) {
+	s.opLimiter = opLimiter
+}
+
+// SetWebhookService sets the service that notifies server webhooks about failed backups
+func (s *BackupService) SetWebhookService(webhooks *WebhookService) {
+	s.webhooks = webhooks
+}
+
+// SetControlPlaneMonitor sets the monitor that throttles compression and defers restores under resource pressure
+func (s *BackupService) SetControlPlaneMonitor(controlPlane *ControlPlaneMonitor) {
+	s.controlPlane = controlPlane
+}
+
+// SetResidencyService sets the service that enforces organization data residency on backups
+func (s *BackupService) SetResidencyService(residency *ResidencyService) {
+	s.residency = residency
+}
+
+// SetSecurityService sets the service that records legal hold changes in the audit log
+func (s *BackupService) SetSecurityService(security *SecurityService) {
+	s.security = security
+}
+
+// RequestRestore restores a backup on behalf of requestedBy within the target owner's concurrency limit
+// If the owner is at the limit, the restore is queued and the returned operation has status queued.
+func (s *BackupService) RequestRestore(ctx context.Context, backupID, targetServerID string, userID *string, requestedBy string) (*Operation, error) {
+	backup, err := s.backupRepo.FindByID(backupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find backup: %w", err)
+	}
+	if backup.Status != models.BackupStatusCompleted {
+		return nil, fmt.Errorf("backup is not in completed state: %s", backup.Status)
+	}
+
+	server, err := s.serverRepo.FindByID(targetServerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find server: %w", err)
+	}
+	if err := checkNotSuspended(server); err != nil {
+		return nil, err
+	}
+
+	return s.opLimiter.Do(ctx, server.OwnerID, requestedBy, OperationRestore, targetServerID, backupID, func(opCtx context.Context) error {
+		return s.RestoreBackup(tracing.Inherit(opCtx, ctx), backupID, targetServerID, userID)
+	})
+}
+
+// SetSSHPool sets the SSH connection pool used to transfer backups to remote nodes
+func (s *BackupService) SetSSHPool(pool *docker.SSHPool) {
+	s.sshPool = pool
+}
+
 // RestoreBackupToNode restores a backup to a remote node via SSH/SCP
 // This is used during migrations to transfer world data to the target node
 func (s *BackupService) RestoreBackupToNode(backupID string, nodeIPAddress string, targetServerID string) error {
@@ -533,6 +812,17 @@ func (s *BackupService) RestoreBackupToNode(backupID string, nodeIPAddress strin
 		return fmt.Errorf("backup is not in completed state: %s", backup.Status)
 	}
 
+	// Validate everything that ends up in remote paths/commands
+	if err := docker.ValidateResourceID(backupID); err != nil {
+		return err
+	}
+	if err := docker.ValidateResourceID(targetServerID); err != nil {
+		return err
+	}
+	if err := docker.ValidateNodeAddress(nodeIPAddress); err != nil {
+		return err
+	}
+
 	logger.Info("BACKUP-SERVICE: Starting remote backup restore", map[string]interface{}{
 		"backup_id":        backupID,
 		"target_server_id": targetServerID,
@@ -540,14 +830,17 @@ func (s *BackupService) RestoreBackupToNode(backupID string, nodeIPAddress strin
 		"storage_path":     backup.StoragePath,
 	})
 
+	progress := NewTransferProgress("restore", backupID, targetServerID, s.wsHub).ReportTo(s.opLimiter, OperationRestore)
+
 	// 1. Download backup to local temp directory if on Storage Box
 	isRemote := s.sftpClient != nil && !filepath.IsAbs(backup.StoragePath)
 	var localPath string
 
 	if isRemote {
 		// Download from Storage Box
-		localPath = filepath.Join(s.storagePath, fmt.Sprintf("migrate-%s.tar.gz", backupID))
-		if err := s.sftpClient.Download(backup.StoragePath, localPath); err != nil {
+		progress.StartPhase("downloading", backup.CompressedSize)
+		localPath = filepath.Join(s.storagePath, "migrate-"+backupID+compression.Extension(s.compressionFor(backup).Codec))
+		if err := s.sftpClient.DownloadWithProgress(backup.StoragePath, localPath, progress.Func()); err != nil {
 			return fmt.Errorf("failed to download backup from Storage Box: %w", err)
 		}
 		defer os.Remove(localPath) // Cleanup after transfer
@@ -562,45 +855,55 @@ func (s *BackupService) RestoreBackupToNode(backupID string, nodeIPAddress strin
 		"size_mb":    backup.CompressedSize / 1024 / 1024,
 	})
 
+	// Detect the codec from the file itself (the record may predate codec tracking)
+	codec, err := compression.DetectFile(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to detect backup format: %w", err)
+	}
+
 	// 2. Create target directory on remote node
 	targetDir := fmt.Sprintf("/minecraft/servers/%s", targetServerID)
-	createDirCmd := fmt.Sprintf("ssh root@%s 'mkdir -p %s'", nodeIPAddress, targetDir)
-
-	if err := s.executeSSHCommand(createDirCmd); err != nil {
+	if err := s.runOnNode(nodeIPAddress, "mkdir", "-p", targetDir); err != nil {
 		return fmt.Errorf("failed to create remote directory: %w", err)
 	}
 
 	// 3. Transfer backup to remote node
-	remoteTempPath := fmt.Sprintf("/tmp/backup-%s.tar.gz", backupID)
-	scpCmd := fmt.Sprintf("scp %s root@%s:%s", localPath, nodeIPAddress, remoteTempPath)
+	remoteTempPath := "/tmp/backup-" + backupID + compression.Extension(codec)
 
 	logger.Info("BACKUP-SERVICE: Transferring backup to remote node", map[string]interface{}{
 		"backup_id":   backupID,
 		"target_node": nodeIPAddress,
 		"size_mb":     backup.CompressedSize / 1024 / 1024,
+		"pooled":      s.sshPool != nil,
 	})
 
-	if err := s.executeSSHCommand(scpCmd); err != nil {
+	progress.StartPhase("uploading", backup.CompressedSize)
+	if err := s.uploadToNode(nodeIPAddress, localPath, remoteTempPath, progress); err != nil {
 		return fmt.Errorf("failed to transfer backup to remote node: %w", err)
 	}
 
 	// 4. Extract backup on remote node
-	extractCmd := fmt.Sprintf("ssh root@%s 'cd %s && tar -xzf %s && rm %s'",
-		nodeIPAddress,
-		targetDir,
-		remoteTempPath,
-		remoteTempPath,
-	)
-
 	logger.Info("BACKUP-SERVICE: Extracting backup on remote node", map[string]interface{}{
 		"backup_id":   backupID,
 		"target_node": nodeIPAddress,
 		"target_dir":  targetDir,
+		"codec":       codec,
 	})
 
-	if err := s.executeSSHCommand(extractCmd); err != nil {
+	progress.StartPhase("extracting", 0) // Runs remotely: no byte-level progress
+	if err := s.runOnNode(nodeIPAddress, "tar", compression.TarFlag(codec), "-xf", remoteTempPath, "-C", targetDir); err != nil {
+		s.runOnNode(nodeIPAddress, "rm", "-f", remoteTempPath) // Don't leave the archive behind
 		return fmt.Errorf("failed to extract backup on remote node: %w", err)
 	}
+	if err := s.runOnNode(nodeIPAddress, "rm", "-f", remoteTempPath); err != nil {
+		logger.Warn("BACKUP-SERVICE: Failed to remove transferred archive on remote node", map[string]interface{}{
+			"target_node": nodeIPAddress,
+			"path":        remoteTempPath,
+			"error":       err.Error(),
+		})
+	}
+
+	progress.Finish()
 
 	logger.Info("BACKUP-SERVICE: Remote backup restore completed successfully", map[string]interface{}{
 		"backup_id":        backupID,
@@ -618,18 +921,52 @@ func (s *BackupService) RestoreBackupToNode(backupID string, nodeIPAddress strin
 	return nil
 }
 
-// executeSSHCommand executes a shell command (used for SSH/SCP operations)
-func (s *BackupService) executeSSHCommand(command string) error {
-	cmd := exec.Command("bash", "-c", command)
+// runOnNode runs a program with arguments on a remote node (pooled SSH session if available)
+// The remote command line is built with docker.ShellJoin, so arguments are never re-parsed.
+func (s *BackupService) runOnNode(nodeIPAddress string, argv ...string) error {
+	command := docker.ShellJoin(argv...)
+	if s.sshPool != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		_, err := s.sshPool.Run(ctx, &docker.RemoteNode{ID: nodeIPAddress, IPAddress: nodeIPAddress, SSHUser: "root"}, command)
+		return err
+	}
+	return s.executeSSHCommand("ssh", "root@"+nodeIPAddress, command)
+}
+
+// uploadToNode copies a local file to a remote node (pooled SSH session if available)
+// Byte-level progress is only reported over the pool; the scp fallback reports phase boundaries.
+func (s *BackupService) uploadToNode(nodeIPAddress, localPath, remotePath string, progress *TransferProgress) error {
+	if err := docker.ValidateRemotePath(remotePath, "/tmp", "/minecraft"); err != nil {
+		return err
+	}
+	if s.sshPool != nil {
+		file, err := os.Open(localPath)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", localPath, err)
+		}
+		defer file.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+		return s.sshPool.UploadFrom(ctx, &docker.RemoteNode{ID: nodeIPAddress, IPAddress: nodeIPAddress, SSHUser: "root"}, progress.Reader(file), remotePath)
+	}
+	return s.executeSSHCommand("scp", "--", localPath, "root@"+nodeIPAddress+":"+remotePath)
+}
+
+// executeSSHCommand executes ssh/scp with arguments (no local shell)
+func (s *BackupService) executeSSHCommand(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("command failed: %w, output: %s", err, string(output))
+		return fmt.Errorf("%s failed: %w, output: %s", name, err, string(output))
 	}
 	return nil
 }
 
-// extractBackup extracts tar.gz backup to target directory
-func (s *BackupService) extractBackup(archivePath, targetPath string) error {
+// extractBackup extracts a backup to target directory (codec is auto-detected)
+// progress (optional) counts compressed bytes read from the archive
+func (s *BackupService) extractBackup(archivePath, targetPath string, progress *TransferProgress) error {
 	startTime := time.Now()
 
 	// Ensure target directory exists
@@ -644,15 +981,21 @@ func (s *BackupService) extractBackup(archivePath, targetPath string) error {
 	}
 	defer archiveFile.Close()
 
-	// Create gzip reader
-	gzReader, err := gzip.NewReader(archiveFile)
+	if progress != nil {
+		if info, err := archiveFile.Stat(); err == nil {
+			progress.StartPhase("extracting", info.Size())
+		}
+	}
+
+	// Create decompressor (gzip or zstd, detected from magic bytes)
+	decompressor, codec, err := compression.NewReader(progress.Reader(archiveFile))
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
+		return fmt.Errorf("failed to open backup archive: %w", err)
 	}
-	defer gzReader.Close()
+	defer decompressor.Close()
 
 	// Create tar reader
-	tarReader := tar.NewReader(gzReader)
+	tarReader := tar.NewReader(decompressor)
 
 	// Extract files
 	for {
@@ -693,6 +1036,7 @@ func (s *BackupService) extractBackup(archivePath, targetPath string) error {
 	logger.Debug("BACKUP-SERVICE: Extraction completed", map[string]interface{}{
 		"archive":     archivePath,
 		"target":      targetPath,
+		"codec":       codec,
 		"duration_s":  duration.Seconds(),
 	})
 
@@ -706,6 +1050,18 @@ func (s *BackupService) DeleteBackup(backupID string) error {
 		return fmt.Errorf("failed to find backup: %w", err)
 	}
 
+	hold, err := s.backupRepo.FindActiveLegalHold(backup)
+	if err != nil {
+		return fmt.Errorf("failed to check legal hold: %w", err)
+	}
+	if hold != nil {
+		logger.Warn("BACKUP-SERVICE: Deletion blocked by legal hold", map[string]interface{}{
+			"backup_id": backupID,
+			"hold_id":   hold.ID,
+		})
+		return fmt.Errorf("%w (hold %s)", ErrBackupLegalHold, hold.ID)
+	}
+
 	logger.Info("BACKUP-SERVICE: Deleting backup", map[string]interface{}{
 		"backup_id":    backupID,
 		"storage_path": backup.StoragePath,
@@ -805,6 +1161,28 @@ func (s *BackupService) markBackupFailed(backup *models.Backup, errorMsg string)
 		"backup_id": backup.ID,
 		"error":     errorMsg,
 	})
+	monitoring.RecordBackupFailed(backup.ServerID, backup.ServerName)
+
+	if s.webhooks != nil {
+		serverName := backup.ServerID
+		if server, err := s.serverRepo.FindByID(backup.ServerID); err == nil {
+			serverName = server.Name
+		}
+		s.webhooks.NotifyBackupFailed(backup.ServerID, serverName, errorMsg)
+	}
+}
+
+// markBackupCancelled marks a backup as cancelled (the caller removes partial files)
+func (s *BackupService) markBackupCancelled(backup *models.Backup) {
+	backup.Status = models.BackupStatusCancelled
+	backup.ErrorMessage = "cancelled"
+	backup.UpdatedAt = time.Now()
+	s.backupRepo.Update(backup)
+
+	logger.Info("BACKUP-SERVICE: Backup cancelled", map[string]interface{}{
+		"backup_id": backup.ID,
+		"server_id": backup.ServerID,
+	})
 }
 
 func (s *BackupService) getDefaultRetentionDays(backupType models.BackupType) int {
@@ -819,6 +1197,12 @@ func (s *BackupService) getDefaultRetentionDays(backupType models.BackupType) in
 		return 30 // Keep pre-deletion backups for 30 days (safety)
 	case models.BackupTypePreRestore:
 		return 7 // Keep pre-restore backups for 7 days
+	case models.BackupTypePreReset, models.BackupTypePreImport:
+		return 14 // Keep pre-reset/pre-import backups for 14 days (players notice missing builds late)
+	case models.BackupTypeClone:
+		return 1 // Only needed until the clone is restored
+	case models.BackupTypeTemplate:
+		return 0 // Keep template worlds as long as the template exists
 	default:
 		return 7
 	}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+)
+
+// maxBreakdownDays limits the date range of a cost breakdown request
+const maxBreakdownDays = 366
+
+const bytesPerGB = 1024.0 * 1024.0 * 1024.0
+
+// GetCostBreakdown attributes a server's costs to compute, backup storage, archive storage (hot and
+// cold), cold archive retrievals and migration transfer for every day in [from, to). Days are local calendar days (same as GetServerCosts).
+// The history is read from a read replica if one is configured.
+func (s *BillingService) GetCostBreakdown(serverID string, from, to time.Time) (*models.CostBreakdown, error) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	from = startOfDay(from)
+	to = startOfDay(to)
+	if !to.After(from) {
+		return nil, fmt.Errorf("'to' must be after 'from'")
+	}
+	dayCount := int(to.Sub(from).Hours()/24 + 0.5)
+	if dayCount > maxBreakdownDays {
+		return nil, fmt.Errorf("date range too large (max %d days)", maxBreakdownDays)
+	}
+
+	breakdown := &models.CostBreakdown{
+		ServerID:   server.ID,
+		ServerName: server.Name,
+		OwnerID:    server.OwnerID,
+		From:       from,
+		To:         to,
+		Days:       make([]models.DailyCostBreakdown, dayCount),
+		Pricing:    s.pricing,
+	}
+	for i := range breakdown.Days {
+		breakdown.Days[i].Date = from.AddDate(0, 0, i).Format("2006-01-02")
+	}
+
+	now := time.Now()
+	if err := s.attributeComputeCosts(breakdown, now); err != nil {
+		return nil, err
+	}
+	if err := s.attributeBackupCosts(breakdown, now); err != nil {
+		return nil, err
+	}
+	s.attributeArchiveCosts(breakdown, server, now)
+	if err := s.attributeRetrievalCosts(breakdown); err != nil {
+		return nil, err
+	}
+	if err := s.attributeMigrationCosts(breakdown); err != nil {
+		return nil, err
+	}
+
+	for i := range breakdown.Days {
+		day := &breakdown.Days[i]
+		day.TotalEUR = day.ComputeEUR + day.BackupEUR + day.ArchiveEUR + day.ColdArchiveEUR + day.RetrievalEUR + day.MigrationEUR
+		breakdown.Totals.Add(*day)
+	}
+	breakdown.Totals.Date = ""
+
+	return breakdown, nil
+}
+
+// attributeComputeCosts splits usage sessions (including the running one) across days
+func (s *BillingService) attributeComputeCosts(breakdown *models.CostBreakdown, now time.Time) error {
+	var sessions []models.UsageSession
+	err := repository.ReadDB(s.db).Where("server_id = ? AND started_at < ? AND (stopped_at IS NULL OR stopped_at > ?)",
+		breakdown.ServerID, breakdown.To, breakdown.From).
+		Find(&sessions).Error
+	if err != nil {
+		return fmt.Errorf("failed to fetch sessions: %w", err)
+	}
+
+	for _, session := range sessions {
+		end := now
+		if session.StoppedAt != nil {
+			end = *session.StoppedAt
+		}
+		ramGB := float64(session.RAMMb) / 1024.0
+
+		forEachDayOverlap(breakdown, session.StartedAt, end, func(day *models.DailyCostBreakdown, overlap time.Duration) {
+			day.ComputeGBHours += ramGB * overlap.Hours()
+			day.ComputeEUR += models.UsageCostEUR(session.RAMMb, session.HourlyRateEUR, overlap)
+		})
+	}
+
+	return nil
+}
+
+// attributeBackupCosts charges compressed backup size for every day a backup was stored
+func (s *BillingService) attributeBackupCosts(breakdown *models.CostBreakdown, now time.Time) error {
+	var backups []models.Backup
+	err := repository.ReadDB(s.db).Where("server_id = ? AND status IN ? AND created_at < ?",
+		breakdown.ServerID, []models.BackupStatus{models.BackupStatusCompleted, models.BackupStatusDeleted}, breakdown.To).
+		Find(&backups).Error
+	if err != nil {
+		return fmt.Errorf("failed to fetch backups: %w", err)
+	}
+
+	for _, backup := range backups {
+		start := backup.CreatedAt
+		if backup.CompletedAt != nil {
+			start = *backup.CompletedAt
+		}
+		end := now
+		if backup.Status == models.BackupStatusDeleted {
+			end = backup.UpdatedAt // Deletion is the last update
+		}
+		sizeGB := float64(backup.CompressedSize) / bytesPerGB
+
+		forEachDayOverlap(breakdown, start, end, func(day *models.DailyCostBreakdown, overlap time.Duration) {
+			gbDays := sizeGB * overlap.Hours() / 24.0
+			day.BackupGBDays += gbDays
+			day.BackupEUR += gbDays * s.pricing.BackupRateEURPerGBDay
+		})
+	}
+
+	return nil
+}
+
+// attributeArchiveCosts charges the archive size for every day the server has been archived
+// Days after the archive moved to cold storage are charged at the cold archive rate.
+func (s *BillingService) attributeArchiveCosts(breakdown *models.CostBreakdown, server *models.MinecraftServer, now time.Time) {
+	if server.Status != models.StatusArchived || server.ArchivedAt == nil || server.ArchiveSize == 0 {
+		return
+	}
+
+	sizeGB := float64(server.ArchiveSize) / bytesPerGB
+	hotUntil := now
+	if server.ArchiveTier == models.ArchiveTierCold && server.ArchiveColdAt != nil {
+		hotUntil = *server.ArchiveColdAt
+	}
+
+	forEachDayOverlap(breakdown, *server.ArchivedAt, hotUntil, func(day *models.DailyCostBreakdown, overlap time.Duration) {
+		gbDays := sizeGB * overlap.Hours() / 24.0
+		day.ArchiveGBDays += gbDays
+		day.ArchiveEUR += gbDays * s.pricing.ArchiveRateEURPerGBDay
+	})
+	if hotUntil.Before(now) {
+		forEachDayOverlap(breakdown, hotUntil, now, func(day *models.DailyCostBreakdown, overlap time.Duration) {
+			gbDays := sizeGB * overlap.Hours() / 24.0
+			day.ColdArchiveGBDays += gbDays
+			day.ColdArchiveEUR += gbDays * s.pricing.ColdArchiveRateEURPerGBDay
+		})
+	}
+}
+
+// attributeRetrievalCosts charges archives restored from cold storage on the day of the download
+func (s *BillingService) attributeRetrievalCosts(breakdown *models.CostBreakdown) error {
+	var retrievals []models.ArchiveRetrieval
+	err := repository.ReadDB(s.db).Where("server_id = ? AND completed_at >= ? AND completed_at < ?",
+		breakdown.ServerID, breakdown.From, breakdown.To).
+		Find(&retrievals).Error
+	if err != nil {
+		return fmt.Errorf("failed to fetch archive retrievals: %w", err)
+	}
+
+	for _, retrieval := range retrievals {
+		index := dayIndex(breakdown, retrieval.CompletedAt)
+		if index < 0 {
+			continue
+		}
+		gb := float64(retrieval.SizeBytes) / bytesPerGB
+		breakdown.Days[index].RetrievalGB += gb
+		breakdown.Days[index].RetrievalEUR += gb * s.pricing.ArchiveRetrievalRateEURPerGB
+	}
+
+	return nil
+}
+
+// attributeMigrationCosts charges transferred world data on the day a migration completed
+func (s *BillingService) attributeMigrationCosts(breakdown *models.CostBreakdown) error {
+	var migrations []models.Migration
+	err := repository.ReadDB(s.db).Where("server_id = ? AND status = ? AND completed_at >= ? AND completed_at < ?",
+		breakdown.ServerID, models.MigrationStatusCompleted, breakdown.From, breakdown.To).
+		Find(&migrations).Error
+	if err != nil {
+		return fmt.Errorf("failed to fetch migrations: %w", err)
+	}
+
+	for _, migration := range migrations {
+		transferBytes := migration.TransferBytes
+		if transferBytes == 0 && migration.BackupID != nil {
+			// Backup-based migrations transfer the compressed backup
+			var backup models.Backup
+			if err := repository.ReadDB(s.db).Select("compressed_size").Where("id = ?", *migration.BackupID).First(&backup).Error; err == nil {
+				transferBytes = backup.CompressedSize
+			}
+		}
+		if transferBytes == 0 {
+			continue
+		}
+
+		index := dayIndex(breakdown, *migration.CompletedAt)
+		if index < 0 {
+			continue
+		}
+		gb := float64(transferBytes) / bytesPerGB
+		breakdown.Days[index].MigrationGB += gb
+		breakdown.Days[index].MigrationEUR += gb * s.pricing.MigrationRateEURPerGB
+	}
+
+	return nil
+}
+
+// forEachDayOverlap calls fn for every breakdown day that overlaps [start, end)
+func forEachDayOverlap(breakdown *models.CostBreakdown, start, end time.Time, fn func(day *models.DailyCostBreakdown, overlap time.Duration)) {
+	if start.Before(breakdown.From) {
+		start = breakdown.From
+	}
+	if end.After(breakdown.To) {
+		end = breakdown.To
+	}
+
+	for i := range breakdown.Days {
+		dayStart := breakdown.From.AddDate(0, 0, i)
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		if !end.After(dayStart) {
+			break
+		}
+
+		overlapStart := start
+		if dayStart.After(overlapStart) {
+			overlapStart = dayStart
+		}
+		overlapEnd := end
+		if dayEnd.Before(overlapEnd) {
+			overlapEnd = dayEnd
+		}
+		if overlapEnd.After(overlapStart) {
+			fn(&breakdown.Days[i], overlapEnd.Sub(overlapStart))
+		}
+	}
+}
+
+// dayIndex returns the index of the breakdown day containing t, or -1
+func dayIndex(breakdown *models.CostBreakdown, t time.Time) int {
+	for i := range breakdown.Days {
+		dayStart := breakdown.From.AddDate(0, 0, i)
+		if !t.Before(dayStart) && t.Before(dayStart.AddDate(0, 0, 1)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// startOfDay truncates t to local midnight
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var ErrBudgetExceeded = errors.New("monthly budget cap reached")
+
+// ServerStopper stops servers on behalf of billing enforcement (implemented by MinecraftService)
+type ServerStopper interface {
+	StopServer(serverID string, reason string) error
+}
+
+// BudgetStatus is a budget cap together with its current accrued spend
+type BudgetStatus struct {
+	Cap        *models.BudgetCap `json:"cap"`
+	AccruedEUR float64           `json:"accrued_eur"`
+	Percent    float64           `json:"percent"`
+	Exceeded   bool              `json:"exceeded"`
+	Overridden bool              `json:"overridden"`
+}
+
+// SetServerStopper sets the stopper used to auto-stop servers over budget
+func (s *BillingService) SetServerStopper(stopper ServerStopper) {
+	s.serverStopper = stopper
+}
+
+// SetWebhookService sets the service that posts budget alerts to server webhooks
+func (s *BillingService) SetWebhookService(webhooks *WebhookService) {
+	s.webhooks = webhooks
+}
+
+// GetBudgetCap returns the budget cap for a target, or nil if none is configured
+func (s *BillingService) GetBudgetCap(scope models.BudgetScope, targetID string) (*models.BudgetCap, error) {
+	var budget models.BudgetCap
+	err := s.db.Where("scope = ? AND target_id = ?", scope, targetID).First(&budget).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
+
+// ListBudgetCaps returns all budget caps (user and server scope) of a user
+func (s *BillingService) ListBudgetCaps(userID string) ([]models.BudgetCap, error) {
+	var caps []models.BudgetCap
+	err := s.db.Where("user_id = ?", userID).Order("scope, server_id").Find(&caps).Error
+	return caps, err
+}
+
+// SetBudgetCap creates or updates the budget cap for a target (alert and override state are kept)
+func (s *BillingService) SetBudgetCap(budget *models.BudgetCap) (*models.BudgetCap, error) {
+	if budget.MonthlyLimitEUR <= 0 {
+		return nil, fmt.Errorf("monthly_limit_eur must be greater than 0")
+	}
+
+	switch budget.Scope {
+	case models.BudgetScopeUser:
+		budget.ServerID = ""
+		budget.TargetID = budget.UserID
+	case models.BudgetScopeServer:
+		if budget.ServerID == "" {
+			return nil, fmt.Errorf("server_id is required for server budgets")
+		}
+		budget.TargetID = budget.ServerID
+	default:
+		return nil, fmt.Errorf("invalid budget scope: %s", budget.Scope)
+	}
+
+	existing, err := s.GetBudgetCap(budget.Scope, budget.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		if err := s.db.Create(budget).Error; err != nil {
+			return nil, err
+		}
+		return budget, nil
+	}
+
+	// Raising the limit may drop below already-alerted thresholds; re-arm alerts
+	if budget.MonthlyLimitEUR > existing.MonthlyLimitEUR {
+		existing.LastAlertPercent = 0
+	}
+	existing.MonthlyLimitEUR = budget.MonthlyLimitEUR
+	existing.AutoStop = budget.AutoStop
+	existing.BlockStarts = budget.BlockStarts
+	if err := s.db.Save(existing).Error; err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// DeleteBudgetCap removes the budget cap for a target
+func (s *BillingService) DeleteBudgetCap(scope models.BudgetScope, targetID string) error {
+	return s.db.Where("scope = ? AND target_id = ?", scope, targetID).Delete(&models.BudgetCap{}).Error
+}
+
+// SetBudgetOverride suspends enforcement (auto-stop / start blocking) of a cap until the given time (admin only)
+func (s *BillingService) SetBudgetOverride(capID uint, adminID string, until time.Time, reason string) (*models.BudgetCap, error) {
+	var budget models.BudgetCap
+	if err := s.db.First(&budget, capID).Error; err != nil {
+		return nil, fmt.Errorf("budget cap not found: %w", err)
+	}
+
+	budget.OverrideUntil = &until
+	budget.OverrideBy = adminID
+	budget.OverrideReason = reason
+	if err := s.db.Save(&budget).Error; err != nil {
+		return nil, err
+	}
+
+	logger.Info("BUDGET: Admin override set", map[string]interface{}{
+		"budget_cap_id":  budget.ID,
+		"admin_id":       adminID,
+		"override_until": until,
+		"reason":         reason,
+	})
+
+	return &budget, nil
+}
+
+// ClearBudgetOverride re-enables enforcement of a cap (admin only)
+func (s *BillingService) ClearBudgetOverride(capID uint) error {
+	return s.db.Model(&models.BudgetCap{}).Where("id = ?", capID).Updates(map[string]interface{}{
+		"override_until":  nil,
+		"override_by":     "",
+		"override_reason": "",
+	}).Error
+}
+
+// GetBudgetAlerts returns the most recent budget alerts of a user
+func (s *BillingService) GetBudgetAlerts(userID string, limit int) ([]models.BudgetAlert, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var alerts []models.BudgetAlert
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&alerts).Error
+	return alerts, err
+}
+
+// GetBudgetStatus returns a cap together with its current month spend
+func (s *BillingService) GetBudgetStatus(budget *models.BudgetCap) (*BudgetStatus, error) {
+	accrued, err := s.accruedCost(budget)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BudgetStatus{
+		Cap:        budget,
+		AccruedEUR: accrued,
+		Percent:    budget.UsagePercent(accrued),
+		Exceeded:   accrued >= budget.MonthlyLimitEUR,
+		Overridden: budget.IsOverridden(time.Now()),
+	}, nil
+}
+
+// accruedCost returns the current month cost covered by a cap
+func (s *BillingService) accruedCost(budget *models.BudgetCap) (float64, error) {
+	if budget.Scope == models.BudgetScopeServer {
+		summary, err := s.GetServerCosts(budget.ServerID)
+		if err != nil {
+			return 0, err
+		}
+		return summary.TotalCostEUR, nil
+	}
+	return s.GetOwnerCosts(budget.UserID)
+}
+
+// CheckBudgets evaluates all caps: emits 50/80/100% alerts and auto-stops servers over budget
+func (s *BillingService) CheckBudgets() {
+	var caps []models.BudgetCap
+	if err := s.db.Find(&caps).Error; err != nil {
+		logger.Error("BUDGET: Failed to load budget caps", err, nil)
+		return
+	}
+
+	now := time.Now()
+	period := models.BudgetPeriod(now)
+
+	for i := range caps {
+		budget := &caps[i]
+
+		accrued, err := s.accruedCost(budget)
+		if err != nil {
+			logger.Warn("BUDGET: Failed to calculate accrued cost", map[string]interface{}{
+				"budget_cap_id": budget.ID,
+				"error":         err.Error(),
+			})
+			continue
+		}
+
+		// New month: re-arm alerts
+		if budget.Period != period {
+			budget.Period = period
+			budget.LastAlertPercent = 0
+		}
+
+		percent := budget.UsagePercent(accrued)
+		for _, threshold := range models.BudgetAlertThresholds {
+			if percent >= float64(threshold) && threshold > budget.LastAlertPercent {
+				s.recordBudgetAlert(budget, threshold, accrued, "warning")
+				budget.LastAlertPercent = threshold
+			}
+		}
+
+		if percent >= 100 && budget.AutoStop {
+			if budget.IsOverridden(now) {
+				logger.Debug("BUDGET: Cap exceeded but admin override is active", map[string]interface{}{
+					"budget_cap_id":  budget.ID,
+					"override_until": budget.OverrideUntil,
+				})
+			} else {
+				s.enforceBudget(budget, accrued)
+			}
+		}
+
+		budget.LastAccruedEUR = accrued
+		budget.LastCheckedAt = &now
+		if err := s.db.Save(budget).Error; err != nil {
+			logger.Error("BUDGET: Failed to update budget cap state", err, map[string]interface{}{
+				"budget_cap_id": budget.ID,
+			})
+		}
+	}
+}
+
+// recordBudgetAlert stores and publishes a threshold crossing
+func (s *BillingService) recordBudgetAlert(budget *models.BudgetCap, percent int, accrued float64, action string) {
+	alert := &models.BudgetAlert{
+		BudgetCapID: budget.ID,
+		Scope:       budget.Scope,
+		UserID:      budget.UserID,
+		ServerID:    budget.ServerID,
+		Period:      budget.Period,
+		Percent:     percent,
+		AccruedEUR:  accrued,
+		LimitEUR:    budget.MonthlyLimitEUR,
+		Action:      action,
+	}
+	if err := s.db.Create(alert).Error; err != nil {
+		logger.Error("BUDGET: Failed to store budget alert", err, map[string]interface{}{
+			"budget_cap_id": budget.ID,
+		})
+	}
+
+	events.PublishBudgetAlert(budget.ServerID, budget.UserID, string(budget.Scope), percent, accrued, budget.MonthlyLimitEUR, action)
+	if s.webhooks != nil {
+		s.webhooks.NotifyBudgetWarning(budget.UserID, budget.ServerID, percent, accrued, budget.MonthlyLimitEUR, action)
+	}
+
+	logger.Warn("BUDGET: Spend threshold reached", map[string]interface{}{
+		"budget_cap_id": budget.ID,
+		"scope":         budget.Scope,
+		"user_id":       budget.UserID,
+		"server_id":     budget.ServerID,
+		"percent":       percent,
+		"accrued_eur":   accrued,
+		"limit_eur":     budget.MonthlyLimitEUR,
+		"action":        action,
+	})
+}
+
+// enforceBudget stops the running servers covered by an exceeded cap
+func (s *BillingService) enforceBudget(budget *models.BudgetCap, accrued float64) {
+	if s.serverStopper == nil {
+		return
+	}
+
+	var serverIDs []string
+	query := s.db.Model(&models.MinecraftServer{}).Where("status = ?", models.StatusRunning)
+	if budget.Scope == models.BudgetScopeServer {
+		query = query.Where("id = ?", budget.ServerID)
+	} else {
+		query = query.Where("owner_id = ?", budget.UserID)
+	}
+	if err := query.Pluck("id", &serverIDs).Error; err != nil {
+		logger.Error("BUDGET: Failed to find running servers", err, map[string]interface{}{
+			"budget_cap_id": budget.ID,
+		})
+		return
+	}
+	if len(serverIDs) == 0 {
+		return
+	}
+
+	for _, serverID := range serverIDs {
+		if err := s.serverStopper.StopServer(serverID, "budget_exceeded"); err != nil {
+			logger.Error("BUDGET: Failed to stop server over budget", err, map[string]interface{}{
+				"server_id":     serverID,
+				"budget_cap_id": budget.ID,
+			})
+		}
+	}
+
+	s.recordBudgetAlert(budget, 100, accrued, "auto_stop")
+}
+
+// CheckCanStart implements BillingGuardInterface (blocks starts while a blocking cap is exceeded)
+func (s *BillingService) CheckCanStart(server *models.MinecraftServer) error {
+	var caps []models.BudgetCap
+	err := s.db.Where("block_starts = ? AND ((scope = ? AND target_id = ?) OR (scope = ? AND target_id = ?))",
+		true, models.BudgetScopeServer, server.ID, models.BudgetScopeUser, server.OwnerID).
+		Find(&caps).Error
+	if err != nil {
+		logger.Warn("BUDGET: Could not check budget caps, allowing start", map[string]interface{}{
+			"server_id": server.ID,
+			"error":     err.Error(),
+		})
+		return nil
+	}
+
+	now := time.Now()
+	for i := range caps {
+		budget := &caps[i]
+		if budget.IsOverridden(now) {
+			continue
+		}
+
+		accrued, err := s.accruedCost(budget)
+		if err != nil {
+			continue
+		}
+		if accrued >= budget.MonthlyLimitEUR {
+			return fmt.Errorf("%w (%s budget: %.2f of %.2f EUR spent this month)",
+				ErrBudgetExceeded, budget.Scope, accrued, budget.MonthlyLimitEUR)
+		}
+	}
+
+	return nil
+}
+
+// StartBudgetWorker starts a background worker that tracks accrued cost against budget caps
+func (s *BillingService) StartBudgetWorker(interval time.Duration) {
+	if interval == 0 {
+		interval = time.Minute // Default: 1 minute (near-real-time)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logger.Info("BUDGET: Budget tracking worker started", map[string]interface{}{
+			"interval": interval.String(),
+		})
+
+		// Run immediately on startup
+		s.CheckBudgets()
+
+		// Then run on schedule
+		for range ticker.C {
+			s.CheckBudgets()
+		}
+	}()
+}
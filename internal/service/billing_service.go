@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -17,6 +18,9 @@ type BillingService struct {
 	db         *gorm.DB
 	serverRepo *repository.ServerRepository
 	pricing    models.PricingConfig
+
+	serverStopper ServerStopper   // Optional: auto-stops servers over budget
+	webhooks      *WebhookService // Optional: Discord/Slack budget alerts
 }
 
 // NewBillingService creates a new billing service
@@ -28,16 +32,15 @@ func NewBillingService(db *gorm.DB, serverRepo *repository.ServerRepository) *Bi
 	}
 }
 
-// Start subscribes to Event-Bus for automatic billing tracking
-func (s *BillingService) Start() {
-	bus := events.GetEventBus()
-
-	// Subscribe to server lifecycle events
-	bus.Subscribe(events.EventServerStarted, s.handleServerStarted)
-	bus.Subscribe(events.EventServerStopped, s.handleServerStopped)
-	bus.Subscribe(events.EventBillingPhaseChanged, s.handlePhaseChanged)
+// Start registers the billing handlers on the event outbox
+// Server start/stop and phase changes are written to the outbox in the same transaction as the
+// status change, so billing sees every one of them even if the API crashes right after it.
+func (s *BillingService) Start(outbox *OutboxWorker) {
+	outbox.Handle(events.EventServerStarted, s.handleServerStarted)
+	outbox.Handle(events.EventServerStopped, s.handleServerStopped)
+	outbox.Handle(events.EventBillingPhaseChanged, s.handlePhaseChanged)
 
-	logger.Info("BillingService subscribed to Event-Bus", nil)
+	logger.Info("BillingService registered on event outbox", nil)
 }
 
 // Stop unsubscribes from Event-Bus (cleanup)
@@ -46,87 +49,107 @@ func (s *BillingService) Stop() {
 	logger.Info("BillingService stopped", nil)
 }
 
-// handleServerStarted handles server.started events from Event-Bus
-func (s *BillingService) handleServerStarted(event events.Event) {
-	// Fetch server details
+// handleServerStarted handles server.started outbox events
+func (s *BillingService) handleServerStarted(event *models.OutboxEvent) error {
 	server, err := s.serverRepo.FindByID(event.ServerID)
 	if err != nil {
-		logger.Error("Failed to fetch server for billing", err, map[string]interface{}{
-			"server_id": event.ServerID,
-		})
-		return
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("Billing: Server of start event no longer exists, skipping", map[string]interface{}{
+				"server_id": event.ServerID,
+				"event_id":  event.ID,
+			})
+			return nil
+		}
+		return fmt.Errorf("failed to fetch server for billing: %w", err)
 	}
 
 	// Record billing event and create usage session
-	if err := s.recordServerStartedInternal(server); err != nil {
-		logger.Error("Failed to record server start for billing", err, map[string]interface{}{
-			"server_id": server.ID,
-		})
-	}
+	return s.recordOnce(event.ID, func(tx *gorm.DB) error {
+		return s.recordServerStartedInternal(tx, server, event.CreatedAt, &event.ID)
+	})
 }
 
-// handleServerStopped handles server.stopped events from Event-Bus
-func (s *BillingService) handleServerStopped(event events.Event) {
-	// Fetch server details
+// handleServerStopped handles server.stopped outbox events
+func (s *BillingService) handleServerStopped(event *models.OutboxEvent) error {
 	server, err := s.serverRepo.FindByID(event.ServerID)
 	if err != nil {
-		logger.Error("Failed to fetch server for billing", err, map[string]interface{}{
-			"server_id": event.ServerID,
-		})
-		return
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Open sessions of deleted servers are closed by the zombie cleanup
+			logger.Warn("Billing: Server of stop event no longer exists, skipping", map[string]interface{}{
+				"server_id": event.ServerID,
+				"event_id":  event.ID,
+			})
+			return nil
+		}
+		return fmt.Errorf("failed to fetch server for billing: %w", err)
 	}
 
 	// Record billing event and close usage session
-	if err := s.recordServerStoppedInternal(server); err != nil {
-		logger.Error("Failed to record server stop for billing", err, map[string]interface{}{
-			"server_id": server.ID,
-		})
-	}
+	return s.recordOnce(event.ID, func(tx *gorm.DB) error {
+		return s.recordServerStoppedInternal(tx, server, event.CreatedAt, &event.ID)
+	})
 }
 
-// handlePhaseChanged handles billing.phase_changed events from Event-Bus
-func (s *BillingService) handlePhaseChanged(event events.Event) {
-	// Fetch server details
+// handlePhaseChanged handles billing.phase_changed outbox events
+func (s *BillingService) handlePhaseChanged(event *models.OutboxEvent) error {
 	server, err := s.serverRepo.FindByID(event.ServerID)
 	if err != nil {
-		logger.Error("Failed to fetch server for billing", err, map[string]interface{}{
-			"server_id": event.ServerID,
-		})
-		return
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch server for billing: %w", err)
 	}
 
 	// Extract phase change data
-	oldPhaseStr, ok1 := event.Data["old_phase"].(string)
-	newPhaseStr, ok2 := event.Data["new_phase"].(string)
+	data := event.Data()
+	oldPhaseStr, ok1 := data["old_phase"].(string)
+	newPhaseStr, ok2 := data["new_phase"].(string)
 
 	if !ok1 || !ok2 {
 		logger.Warn("Invalid phase change event data", map[string]interface{}{
-			"event": event,
+			"event_id": event.ID,
+			"payload":  event.Payload,
 		})
-		return
+		return nil // Retrying won't fix the payload
 	}
 
 	oldPhase := models.LifecyclePhase(oldPhaseStr)
 	newPhase := models.LifecyclePhase(newPhaseStr)
 
-	if err := s.RecordPhaseChange(server, oldPhase, newPhase); err != nil {
-		logger.Error("Failed to record phase change", err, map[string]interface{}{
-			"server_id": server.ID,
-		})
-	}
+	return s.recordOnce(event.ID, func(tx *gorm.DB) error {
+		return s.recordPhaseChangeInternal(tx, server, oldPhase, newPhase, event.CreatedAt, &event.ID)
+	})
+}
+
+// recordOnce runs record in a transaction unless a billing event with idempotencyKey already exists
+// (the outbox event was handled before, but not marked processed)
+func (s *BillingService) recordOnce(idempotencyKey string, record func(tx *gorm.DB) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.BillingEvent{}).Where("idempotency_key = ?", idempotencyKey).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check idempotency key: %w", err)
+		}
+		if count > 0 {
+			logger.Debug("Billing: Outbox event already recorded, skipping", map[string]interface{}{
+				"event_id": idempotencyKey,
+			})
+			return nil
+		}
+		return record(tx)
+	})
 }
 
 // RecordServerStarted records a server start event and begins a new usage session
 // DEPRECATED: This method is kept for backwards compatibility
-// Billing events are now automatically created via Event-Bus subscription
+// Billing events are now automatically created via the event outbox
 func (s *BillingService) RecordServerStarted(server *models.MinecraftServer) error {
-	return s.recordServerStartedInternal(server)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.recordServerStartedInternal(tx, server, time.Now(), nil)
+	})
 }
 
-// recordServerStartedInternal is the internal implementation called by Event-Bus
-func (s *BillingService) recordServerStartedInternal(server *models.MinecraftServer) error {
-	now := time.Now()
-
+// recordServerStartedInternal records a start at the time it happened (now = event time)
+func (s *BillingService) recordServerStartedInternal(tx *gorm.DB, server *models.MinecraftServer, now time.Time, idempotencyKey *string) error {
 	// Calculate tier-based hourly rate
 	hourlyRate := s.getHourlyRateForServer(server)
 
@@ -144,9 +167,10 @@ func (s *BillingService) recordServerStartedInternal(server *models.MinecraftSer
 		PreviousPhase:    server.LifecyclePhase,
 		MinecraftVersion: server.MinecraftVersion,
 		HourlyRateEUR:    hourlyRate,
+		IdempotencyKey:   idempotencyKey,
 	}
 
-	if err := s.db.Create(event).Error; err != nil {
+	if err := tx.Create(event).Error; err != nil {
 		return fmt.Errorf("failed to create billing event: %w", err)
 	}
 
@@ -163,7 +187,7 @@ func (s *BillingService) recordServerStartedInternal(server *models.MinecraftSer
 		HourlyRateEUR:    hourlyRate,
 	}
 
-	if err := s.db.Create(session).Error; err != nil {
+	if err := tx.Create(session).Error; err != nil {
 		return fmt.Errorf("failed to create usage session: %w", err)
 	}
 
@@ -181,15 +205,15 @@ func (s *BillingService) recordServerStartedInternal(server *models.MinecraftSer
 
 // RecordServerStopped records a server stop event and closes the usage session
 // DEPRECATED: This method is kept for backwards compatibility
-// Billing events are now automatically created via Event-Bus subscription
+// Billing events are now automatically created via the event outbox
 func (s *BillingService) RecordServerStopped(server *models.MinecraftServer) error {
-	return s.recordServerStoppedInternal(server)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.recordServerStoppedInternal(tx, server, time.Now(), nil)
+	})
 }
 
-// recordServerStoppedInternal is the internal implementation called by Event-Bus
-func (s *BillingService) recordServerStoppedInternal(server *models.MinecraftServer) error {
-	now := time.Now()
-
+// recordServerStoppedInternal records a stop at the time it happened (now = event time)
+func (s *BillingService) recordServerStoppedInternal(tx *gorm.DB, server *models.MinecraftServer, now time.Time, idempotencyKey *string) error {
 	// Create billing event
 	event := &models.BillingEvent{
 		ID:               uuid.New().String(),
@@ -204,15 +228,16 @@ func (s *BillingService) recordServerStoppedInternal(server *models.MinecraftSer
 		PreviousPhase:    models.PhaseActive,
 		MinecraftVersion: server.MinecraftVersion,
 		HourlyRateEUR:    s.pricing.ActiveRateEURPerGBHour,
+		IdempotencyKey:   idempotencyKey,
 	}
 
-	if err := s.db.Create(event).Error; err != nil {
+	if err := tx.Create(event).Error; err != nil {
 		return fmt.Errorf("failed to create billing event: %w", err)
 	}
 
 	// Find and close the open usage session
 	var session models.UsageSession
-	err := s.db.Where("server_id = ? AND stopped_at IS NULL", server.ID).
+	err := tx.Where("server_id = ? AND stopped_at IS NULL", server.ID).
 		Order("started_at DESC").
 		First(&session).Error
 
@@ -229,14 +254,15 @@ func (s *BillingService) recordServerStoppedInternal(server *models.MinecraftSer
 	// Calculate session duration and cost
 	session.StoppedAt = &now
 	durationSeconds := int(now.Sub(session.StartedAt).Seconds())
+	if durationSeconds < 0 {
+		durationSeconds = 0
+	}
 	session.DurationSeconds = durationSeconds
 
 	// Cost = (RAM in GB) * (hours) * (hourly rate)
-	ramGB := float64(session.RAMMb) / 1024.0
-	hours := float64(durationSeconds) / 3600.0
-	session.CostEUR = ramGB * hours * session.HourlyRateEUR
+	session.CostEUR = models.UsageCostEUR(session.RAMMb, session.HourlyRateEUR, time.Duration(durationSeconds)*time.Second)
 
-	if err := s.db.Save(&session).Error; err != nil {
+	if err := tx.Save(&session).Error; err != nil {
 		return fmt.Errorf("failed to update session: %w", err)
 	}
 
@@ -252,13 +278,18 @@ func (s *BillingService) recordServerStoppedInternal(server *models.MinecraftSer
 
 // RecordPhaseChange records a lifecycle phase transition
 func (s *BillingService) RecordPhaseChange(server *models.MinecraftServer, oldPhase, newPhase models.LifecyclePhase) error {
+	return s.recordPhaseChangeInternal(s.db, server, oldPhase, newPhase, time.Now(), nil)
+}
+
+// recordPhaseChangeInternal records a phase transition at the time it happened
+func (s *BillingService) recordPhaseChangeInternal(tx *gorm.DB, server *models.MinecraftServer, oldPhase, newPhase models.LifecyclePhase, at time.Time, idempotencyKey *string) error {
 	event := &models.BillingEvent{
 		ID:               uuid.New().String(),
 		ServerID:         server.ID,
 		ServerName:       server.Name,
 		OwnerID:          server.OwnerID,
 		EventType:        models.EventPhaseChanged,
-		Timestamp:        time.Now(),
+		Timestamp:        at,
 		RAMMb:            server.RAMMb,
 		StorageGB:        0,
 		LifecyclePhase:   newPhase,
@@ -266,9 +297,10 @@ func (s *BillingService) RecordPhaseChange(server *models.MinecraftServer, oldPh
 		MinecraftVersion: server.MinecraftVersion,
 		HourlyRateEUR:    s.pricing.ActiveRateEURPerGBHour,
 		DailyRateEUR:     s.pricing.SleepRateEURPerGBDay,
+		IdempotencyKey:   idempotencyKey,
 	}
 
-	if err := s.db.Create(event).Error; err != nil {
+	if err := tx.Create(event).Error; err != nil {
 		return fmt.Errorf("failed to create phase change event: %w", err)
 	}
 
@@ -281,6 +313,73 @@ func (s *BillingService) RecordPhaseChange(server *models.MinecraftServer, oldPh
 	return nil
 }
 
+// replayMatchWindow is how close a billing event must be to a stored event to count as its live record
+// (the outbox record and the published event of one status change are a few seconds apart at most)
+const replayMatchWindow = 30 * time.Second
+
+// replayBillingEventTypes maps replayable Event-Bus events to the billing events they produce
+var replayBillingEventTypes = map[events.EventType]models.BillingEventType{
+	events.EventServerStarted:       models.EventServerStarted,
+	events.EventServerStopped:       models.EventServerStopped,
+	events.EventBillingPhaseChanged: models.EventPhaseChanged,
+}
+
+// ReplayEvent re-records a stored server.started/stopped or billing.phase_changed event at its original time
+// Events that were already billed (live, or by an earlier replay) are skipped and return false.
+// To rebuild a range after a billing bug, delete its wrong billing events and sessions first.
+func (s *BillingService) ReplayEvent(event events.Event, dryRun bool) (bool, error) {
+	billingType, ok := replayBillingEventTypes[event.Type]
+	if !ok {
+		return false, fmt.Errorf("not a billing event: %s", event.Type)
+	}
+
+	server, err := s.serverRepo.FindByID(event.ServerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil // Deleted servers can't be re-billed
+		}
+		return false, fmt.Errorf("failed to fetch server: %w", err)
+	}
+
+	key := "replay:" + event.ID
+	applied := false
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.BillingEvent{}).
+			Where("idempotency_key = ? OR (server_id = ? AND event_type = ? AND timestamp BETWEEN ? AND ?)",
+				key, server.ID, billingType, event.Timestamp.Add(-replayMatchWindow), event.Timestamp.Add(replayMatchWindow)).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check for existing billing event: %w", err)
+		}
+		if count > 0 {
+			return nil
+		}
+
+		applied = true
+		if dryRun {
+			return nil
+		}
+
+		switch event.Type {
+		case events.EventServerStarted:
+			return s.recordServerStartedInternal(tx, server, event.Timestamp, &key)
+		case events.EventServerStopped:
+			return s.recordServerStoppedInternal(tx, server, event.Timestamp, &key)
+		default:
+			oldPhase, ok1 := event.Data["old_phase"].(string)
+			newPhase, ok2 := event.Data["new_phase"].(string)
+			if !ok1 || !ok2 {
+				return fmt.Errorf("invalid phase change event data")
+			}
+			return s.recordPhaseChangeInternal(tx, server, models.LifecyclePhase(oldPhase), models.LifecyclePhase(newPhase), event.Timestamp, &key)
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
 // GetServerCosts calculates the cost summary for a server for the current month
 func (s *BillingService) GetServerCosts(serverID string) (*models.CostSummary, error) {
 	server, err := s.serverRepo.FindByID(serverID)
@@ -302,7 +401,7 @@ func (s *BillingService) GetServerCosts(serverID string) (*models.CostSummary, e
 
 	// Calculate active phase costs (completed sessions this month)
 	var sessions []models.UsageSession
-	err = s.db.Where("server_id = ? AND started_at >= ? AND stopped_at IS NOT NULL", serverID, monthStart).
+	err = repository.ReadDB(s.db).Where("server_id = ? AND started_at >= ? AND stopped_at IS NOT NULL", serverID, monthStart).
 		Find(&sessions).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch sessions: %w", err)
@@ -361,7 +460,7 @@ func (s *BillingService) GetOwnerCosts(ownerID string) (float64, error) {
 	var totalCost float64
 	var serverIDs []string
 
-	err := s.db.Model(&models.MinecraftServer{}).Where("owner_id = ?", ownerID).Pluck("id", &serverIDs).Error
+	err := repository.ReadDB(s.db).Model(&models.MinecraftServer{}).Where("owner_id = ?", ownerID).Pluck("id", &serverIDs).Error
 	if err != nil {
 		return 0, fmt.Errorf("failed to fetch servers: %w", err)
 	}
@@ -377,13 +476,30 @@ func (s *BillingService) GetOwnerCosts(ownerID string) (float64, error) {
 		totalCost += summary.TotalCostEUR
 	}
 
+	// Customer-dedicated nodes (this month, running rentals up to now)
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	var nodeSessions []models.UsageSession
+	err = repository.ReadDB(s.db).Where("owner_id = ? AND dedicated_node_id <> '' AND started_at >= ?", ownerID, monthStart).
+		Find(&nodeSessions).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch dedicated node sessions: %w", err)
+	}
+	for _, session := range nodeSessions {
+		if session.StoppedAt != nil {
+			totalCost += session.CostEUR
+		} else {
+			totalCost += models.UsageCostEUR(session.RAMMb, session.HourlyRateEUR, now.Sub(session.StartedAt))
+		}
+	}
+
 	return totalCost, nil
 }
 
 // GetBillingEvents returns all billing events for a server
 func (s *BillingService) GetBillingEvents(serverID string) ([]models.BillingEvent, error) {
 	var events []models.BillingEvent
-	err := s.db.Where("server_id = ?", serverID).
+	err := repository.ReadDB(s.db).Where("server_id = ?", serverID).
 		Order("timestamp DESC").
 		Find(&events).Error
 
@@ -397,7 +513,7 @@ func (s *BillingService) GetBillingEvents(serverID string) ([]models.BillingEven
 // GetUsageSessions returns all usage sessions for a server
 func (s *BillingService) GetUsageSessions(serverID string) ([]models.UsageSession, error) {
 	var sessions []models.UsageSession
-	err := s.db.Where("server_id = ?", serverID).
+	err := repository.ReadDB(s.db).Where("server_id = ?", serverID).
 		Order("started_at DESC").
 		Find(&sessions).Error
 
@@ -410,7 +526,16 @@ func (s *BillingService) GetUsageSessions(serverID string) ([]models.UsageSessio
 
 // getHourlyRateForServer returns the tier-based hourly rate for a server
 // This replaces the legacy flat-rate pricing with tier+plan based pricing
+// Servers on a node dedicated to their owner are free (the node rental is billed instead).
 func (s *BillingService) getHourlyRateForServer(server *models.MinecraftServer) float64 {
+	if server.NodeID != "" {
+		var dedicated int64
+		s.db.Model(&models.Node{}).Where("id = ? AND dedicated_owner_id = ?", server.NodeID, server.OwnerID).Count(&dedicated)
+		if dedicated > 0 {
+			return 0
+		}
+	}
+
 	// Auto-calculate tier if not set
 	if server.RAMTier == "" {
 		server.CalculateTier()
@@ -440,6 +565,7 @@ func (s *BillingService) CleanupZombieSessions() (int, error) {
 		FROM usage_sessions
 		LEFT JOIN minecraft_servers ON usage_sessions.server_id = minecraft_servers.id
 		WHERE usage_sessions.stopped_at IS NULL
+		  AND (usage_sessions.dedicated_node_id IS NULL OR usage_sessions.dedicated_node_id = '')
 		  AND (minecraft_servers.status IS NULL OR minecraft_servers.status != ?)
 	`, models.StatusRunning).Scan(&zombieSessions).Error
 
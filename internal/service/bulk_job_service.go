@@ -0,0 +1,540 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	bulkMaxBatchSize     = 10              // Servers handled at once within a job
+	bulkReadyTimeout     = 5 * time.Minute // How long a restarted server may take to be running again
+	bulkReadyPollEvery   = 2 * time.Second
+	bulkDefaultBatchSize = 10
+)
+
+// BulkAction is the kind of change a bulk job applies to its servers
+type BulkAction string
+
+const (
+	BulkActionRestart       BulkAction = "restart"
+	BulkActionPluginInstall BulkAction = "plugin_install"
+	BulkActionPluginRemove  BulkAction = "plugin_remove"
+	BulkActionConfig        BulkAction = "config"
+)
+
+// BulkJobStatus is the lifecycle state of a bulk job
+type BulkJobStatus string
+
+const (
+	BulkJobRunning    BulkJobStatus = "running"
+	BulkJobCancelling BulkJobStatus = "cancelling" // Cancellation requested, waiting for the current batch
+	BulkJobCompleted  BulkJobStatus = "completed"  // Every server succeeded
+	BulkJobFailed     BulkJobStatus = "failed"     // At least one server failed or was skipped
+	BulkJobCancelled  BulkJobStatus = "cancelled"  // Stopped before all servers were handled
+)
+
+// BulkItemStatus is the state of one server within a bulk job
+type BulkItemStatus string
+
+const (
+	BulkItemPending   BulkItemStatus = "pending"
+	BulkItemRunning   BulkItemStatus = "running"
+	BulkItemSucceeded BulkItemStatus = "succeeded"
+	BulkItemFailed    BulkItemStatus = "failed"
+	BulkItemSkipped   BulkItemStatus = "skipped"   // Not attempted because an earlier batch failed (stop_on_failure)
+	BulkItemCancelled BulkItemStatus = "cancelled" // Not attempted because the job was cancelled
+)
+
+// Bulk job errors
+var (
+	ErrBulkJobNotFound       = errors.New("bulk job not found")
+	ErrBulkJobNotCancellable = errors.New("bulk job has already finished")
+)
+
+// BulkJobOptions controls how a bulk job rolls out over its servers
+type BulkJobOptions struct {
+	BatchSize     int  `json:"batch_size"`      // Servers changed at once; the next batch starts when all are done (and running again)
+	StopOnFailure bool `json:"stop_on_failure"` // Skip the remaining batches once a server failed
+}
+
+// BulkJobItem is the result of one server of a bulk job
+type BulkJobItem struct {
+	ServerID string         `json:"server_id"`
+	Batch    int            `json:"batch"` // 1-based batch the server belongs to
+	Status   BulkItemStatus `json:"status"`
+	Message  string         `json:"message,omitempty"`
+}
+
+// BulkJob is a change applied to many servers in the background
+type BulkJob struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"user_id"`
+	Action     BulkAction     `json:"action"`
+	Options    BulkJobOptions `json:"options"`
+	Status     BulkJobStatus  `json:"status"`
+	Total      int            `json:"total"`
+	Done       int            `json:"done"`
+	Succeeded  int            `json:"succeeded"`
+	Failed     int            `json:"failed"`
+	Progress   int            `json:"progress"` // Percent of servers done
+	Items      []BulkJobItem  `json:"items"`
+	CreatedAt  time.Time      `json:"created_at"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+}
+
+// BulkJobService runs rolling restarts, plugin installs/removals and config changes over many servers
+// Servers are handled in batches: a batch runs in parallel and the next one starts only when the
+// whole batch is done, so a rolling restart with batch size 1 never has more than one server down.
+// Progress is published on the WebSocket hub after every server. Jobs are kept in memory and
+// forgotten an hour after they finish.
+type BulkJobService struct {
+	mcService     *MinecraftService
+	pluginManager *PluginManagerService
+	configService *ConfigService
+	wsHub         WebSocketHubInterface // Optional
+	jobService    *JobService           // Optional, persists every progress change
+
+	mu   sync.Mutex
+	jobs map[string]*BulkJob
+}
+
+// NewBulkJobService creates a new bulk job service
+func NewBulkJobService(mcService *MinecraftService, pluginManager *PluginManagerService, configService *ConfigService) *BulkJobService {
+	return &BulkJobService{
+		mcService:     mcService,
+		pluginManager: pluginManager,
+		configService: configService,
+		jobs:          make(map[string]*BulkJob),
+	}
+}
+
+// SetWebSocketHub sets the WebSocket hub for job progress broadcasts
+func (s *BulkJobService) SetWebSocketHub(wsHub WebSocketHubInterface) {
+	s.wsHub = wsHub
+}
+
+// SetJobService sets where progress changes are persisted (GET /api/jobs)
+func (s *BulkJobService) SetJobService(jobService *JobService) {
+	s.jobService = jobService
+}
+
+// RollingRestart restarts the running servers batch by batch, waiting until a batch is running again
+// Servers that are not running are left alone. rejected holds servers the user may not restart
+// (server ID -> reason); they are reported as failed without being touched.
+func (s *BulkJobService) RollingRestart(userID string, serverIDs []string, opts BulkJobOptions, rejected map[string]string) *BulkJob {
+	return s.start(userID, BulkActionRestart, serverIDs, opts, 1, rejected, func(serverID string) (string, error) {
+		return s.restart(serverID, userID)
+	})
+}
+
+// InstallPlugin installs a marketplace plugin on the servers
+// With restart set, running servers are restarted after the install so a staged rollout
+// (small batches, stop_on_failure) catches a broken plugin before it reaches every server.
+func (s *BulkJobService) InstallPlugin(userID string, serverIDs []string, pluginSlug, versionID string, autoUpdate, restart bool, opts BulkJobOptions, rejected map[string]string) *BulkJob {
+	return s.start(userID, BulkActionPluginInstall, serverIDs, opts, defaultBatchSize(restart), rejected, func(serverID string) (string, error) {
+		if err := s.pluginManager.InstallPlugin(serverID, pluginSlug, versionID, autoUpdate); err != nil {
+			return "", err
+		}
+		return s.restartIf(restart, serverID, userID, "plugin installed")
+	})
+}
+
+// RemovePlugin uninstalls a marketplace plugin from the servers (see InstallPlugin for restart)
+func (s *BulkJobService) RemovePlugin(userID string, serverIDs []string, pluginID string, restart bool, opts BulkJobOptions, rejected map[string]string) *BulkJob {
+	return s.start(userID, BulkActionPluginRemove, serverIDs, opts, defaultBatchSize(restart), rejected, func(serverID string) (string, error) {
+		if err := s.pluginManager.UninstallPlugin(serverID, pluginID); err != nil {
+			return "", err
+		}
+		return s.restartIf(restart, serverID, userID, "plugin removed")
+	})
+}
+
+// ApplyConfig applies the same configuration changes to the servers
+// Most changes recreate running servers, so the default batch size is 1.
+func (s *BulkJobService) ApplyConfig(userID string, serverIDs []string, changes map[string]interface{}, opts BulkJobOptions, rejected map[string]string) *BulkJob {
+	return s.start(userID, BulkActionConfig, serverIDs, opts, 1, rejected, func(serverID string) (string, error) {
+		change, err := s.configService.ApplyConfigChanges(ConfigChangeRequest{
+			ServerID: serverID,
+			UserID:   userID,
+			Changes:  changes,
+		})
+		if err != nil {
+			return "", err
+		}
+		return "config change " + change.ID + " applied", nil
+	})
+}
+
+// List returns the bulk jobs of userID, newest first
+func (s *BulkJobService) List(userID string) []BulkJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+
+	jobs := []BulkJob{}
+	for _, job := range s.jobs {
+		if job.UserID == userID {
+			jobs = append(jobs, s.snapshotLocked(job))
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// Get returns a bulk job of userID
+func (s *BulkJobService) Get(userID, jobID string) (*BulkJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok || job.UserID != userID {
+		return nil, ErrBulkJobNotFound
+	}
+	snapshot := s.snapshotLocked(job)
+	return &snapshot, nil
+}
+
+// Cancel stops a running job of userID before its next batch
+// Servers of the current batch are finished (a half-done restart is worse than a finished one);
+// the remaining servers are reported as cancelled.
+func (s *BulkJobService) Cancel(userID, jobID string) (*BulkJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok || job.UserID != userID {
+		return nil, ErrBulkJobNotFound
+	}
+	if job.Status != BulkJobRunning {
+		return nil, ErrBulkJobNotCancellable
+	}
+	job.Status = BulkJobCancelling
+
+	logger.Info("BULK: Job cancellation requested", map[string]interface{}{
+		"job_id":  job.ID,
+		"user_id": userID,
+		"action":  job.Action,
+	})
+	s.publishLocked(job, nil)
+	snapshot := s.snapshotLocked(job)
+	return &snapshot, nil
+}
+
+// start creates a job and runs fn for every server that wasn't rejected in the background
+func (s *BulkJobService) start(userID string, action BulkAction, serverIDs []string, opts BulkJobOptions, batchSize int, rejected map[string]string, fn func(serverID string) (string, error)) *BulkJob {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = batchSize
+	}
+	opts.BatchSize = min(opts.BatchSize, bulkMaxBatchSize)
+
+	job := &BulkJob{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Action:    action,
+		Options:   opts,
+		Status:    BulkJobRunning,
+		CreatedAt: time.Now(),
+	}
+
+	var pending []int // Indexes into job.Items of the servers to run
+	seen := make(map[string]bool, len(serverIDs))
+	for _, serverID := range serverIDs {
+		if seen[serverID] {
+			continue
+		}
+		seen[serverID] = true
+
+		item := BulkJobItem{ServerID: serverID, Status: BulkItemPending}
+		if reason, ok := rejected[serverID]; ok {
+			item.Status = BulkItemFailed
+			item.Message = reason
+		} else {
+			item.Batch = len(pending)/opts.BatchSize + 1
+			pending = append(pending, len(job.Items))
+		}
+		job.Items = append(job.Items, item)
+	}
+	job.Total = len(job.Items)
+
+	s.mu.Lock()
+	s.prune()
+	for _, item := range job.Items {
+		if item.Status == BulkItemFailed {
+			job.Done++
+			job.Failed++
+		}
+	}
+	s.jobs[job.ID] = job
+	s.updateProgressLocked(job)
+	snapshot := s.snapshotLocked(job)
+	s.publishLocked(job, nil)
+	s.mu.Unlock()
+
+	logger.Info("BULK: Job started", map[string]interface{}{
+		"job_id":     job.ID,
+		"user_id":    userID,
+		"action":     action,
+		"servers":    job.Total,
+		"batch_size": opts.BatchSize,
+	})
+
+	go s.run(job, pending, fn)
+	return &snapshot
+}
+
+// run works through the pending items batch by batch
+func (s *BulkJobService) run(job *BulkJob, pending []int, fn func(serverID string) (string, error)) {
+	for offset := 0; offset < len(pending); offset += job.Options.BatchSize {
+		if s.cancelRequested(job) {
+			s.markRemaining(job, pending[offset:], BulkItemCancelled, "cancelled before this server was reached")
+			break
+		}
+		batch := pending[offset:min(offset+job.Options.BatchSize, len(pending))]
+
+		var wg sync.WaitGroup
+		for _, index := range batch {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				s.runItem(job, index, fn)
+			}(index)
+		}
+		wg.Wait()
+
+		if job.Options.StopOnFailure && s.batchFailed(job, batch) {
+			s.markRemaining(job, pending[offset+len(batch):], BulkItemSkipped, "skipped after an earlier server failed")
+			break
+		}
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	job.FinishedAt = &now
+	switch {
+	case job.Status == BulkJobCancelling:
+		job.Status = BulkJobCancelled
+	case job.Failed > 0 || job.Done < job.Total:
+		job.Status = BulkJobFailed
+	default:
+		job.Status = BulkJobCompleted
+	}
+	s.publishLocked(job, nil)
+	s.mu.Unlock()
+
+	logger.Info("BULK: Job finished", map[string]interface{}{
+		"job_id":    job.ID,
+		"action":    job.Action,
+		"status":    job.Status,
+		"succeeded": job.Succeeded,
+		"failed":    job.Failed,
+	})
+}
+
+// runItem runs fn for one server and records the result
+func (s *BulkJobService) runItem(job *BulkJob, index int, fn func(serverID string) (string, error)) {
+	s.mu.Lock()
+	job.Items[index].Status = BulkItemRunning
+	s.publishLocked(job, &job.Items[index])
+	serverID := job.Items[index].ServerID
+	s.mu.Unlock()
+
+	message, err := fn(serverID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := &job.Items[index]
+	job.Done++
+	if err != nil {
+		item.Status = BulkItemFailed
+		item.Message = err.Error()
+		job.Failed++
+	} else {
+		item.Status = BulkItemSucceeded
+		item.Message = message
+		job.Succeeded++
+	}
+	s.updateProgressLocked(job)
+	s.publishLocked(job, item)
+}
+
+// batchFailed reports whether a server of the batch failed (rejected servers don't stop the rollout)
+func (s *BulkJobService) batchFailed(job *BulkJob, batch []int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, index := range batch {
+		if job.Items[index].Status == BulkItemFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// cancelRequested reports whether the job was asked to stop
+func (s *BulkJobService) cancelRequested(job *BulkJob) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return job.Status == BulkJobCancelling
+}
+
+// markRemaining marks the items that will no longer run as skipped or cancelled
+func (s *BulkJobService) markRemaining(job *BulkJob, remaining []int, status BulkItemStatus, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, index := range remaining {
+		job.Items[index].Status = status
+		job.Items[index].Message = message
+	}
+}
+
+// restartIf restarts a running server after a change when restart is set
+func (s *BulkJobService) restartIf(restart bool, serverID, userID, done string) (string, error) {
+	if !restart {
+		return done, nil
+	}
+	message, err := s.restart(serverID, userID)
+	if err != nil {
+		return "", fmt.Errorf("%s, but restart failed: %w", done, err)
+	}
+	return done + ", " + message, nil
+}
+
+// restart stops a running server, starts it again and waits until it is running
+func (s *BulkJobService) restart(serverID, userID string) (string, error) {
+	server, err := s.mcService.GetServer(serverID)
+	if err != nil {
+		return "", fmt.Errorf("server not found: %w", err)
+	}
+	if server.Status != models.StatusRunning {
+		return fmt.Sprintf("not restarted (server is %s)", server.Status), nil
+	}
+
+	if err := s.mcService.StopServer(serverID, "Rolling restart"); err != nil {
+		return "", fmt.Errorf("failed to stop server: %w", err)
+	}
+	if _, err := s.mcService.RequestStart(context.Background(), serverID, userID); err != nil {
+		return "", fmt.Errorf("failed to start server: %w", err)
+	}
+	if err := s.waitForRunning(serverID); err != nil {
+		return "", err
+	}
+	return "restarted", nil
+}
+
+// waitForRunning polls the server status until it is running again (queued starts included)
+func (s *BulkJobService) waitForRunning(serverID string) error {
+	deadline := time.Now().Add(bulkReadyTimeout)
+	for {
+		server, err := s.mcService.GetServer(serverID)
+		if err != nil {
+			return fmt.Errorf("server not found: %w", err)
+		}
+		switch server.Status {
+		case models.StatusRunning:
+			return nil
+		case models.StatusQueued, models.StatusStarting, models.StatusStopped:
+			// Stopped: a queued start that hasn't been picked up yet
+		default:
+			return fmt.Errorf("server did not come back up (status %s)", server.Status)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("server not running after %s (status %s)", bulkReadyTimeout, server.Status)
+		}
+		time.Sleep(bulkReadyPollEvery)
+	}
+}
+
+// updateProgressLocked recomputes the progress percentage (s.mu must be held)
+func (s *BulkJobService) updateProgressLocked(job *BulkJob) {
+	if job.Total == 0 {
+		job.Progress = 100
+		return
+	}
+	job.Progress = job.Done * 100 / job.Total
+}
+
+// prune forgets jobs that finished more than finishedOperationRetention ago (s.mu must be held)
+func (s *BulkJobService) prune() {
+	cutoff := time.Now().Add(-finishedOperationRetention)
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// snapshotLocked returns a copy of job that is safe to use without the lock (s.mu must be held)
+func (s *BulkJobService) snapshotLocked(job *BulkJob) BulkJob {
+	snapshot := *job
+	snapshot.Items = append([]BulkJobItem(nil), job.Items...)
+	return snapshot
+}
+
+// publishLocked publishes the progress of job (and the item that changed, if any) (s.mu must be held)
+func (s *BulkJobService) publishLocked(job *BulkJob, item *BulkJobItem) {
+	data := map[string]interface{}{
+		"job_id":    job.ID,
+		"user_id":   job.UserID,
+		"action":    job.Action,
+		"status":    job.Status,
+		"total":     job.Total,
+		"done":      job.Done,
+		"succeeded": job.Succeeded,
+		"failed":    job.Failed,
+		"progress":  job.Progress,
+	}
+	if item != nil {
+		data["item"] = *item
+	}
+	events.PublishBulkJobProgress(data)
+	if s.wsHub != nil {
+		s.wsHub.Broadcast(events.BulkJobProgressEventType, data)
+	}
+	s.jobService.Record(job.record())
+}
+
+// record converts a bulk job to its persisted record (per-server results stay in GET /api/servers/bulk/jobs/:job_id)
+func (j *BulkJob) record() models.Job {
+	startedAt := j.CreatedAt
+	return models.Job{
+		ID:          j.ID,
+		OwnerID:     j.UserID,
+		RequestedBy: j.UserID,
+		Kind:        "bulk_" + string(j.Action),
+		Status:      string(j.Status),
+		Progress:    j.Progress,
+		Error:       bulkJobError(j),
+		Cancellable: j.Status == BulkJobRunning,
+		CreatedAt:   j.CreatedAt,
+		StartedAt:   &startedAt,
+		FinishedAt:  j.FinishedAt,
+	}
+}
+
+// bulkJobError summarizes the failed servers of a job
+func bulkJobError(j *BulkJob) string {
+	if j.Failed == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d of %d servers failed", j.Failed, j.Total)
+}
+
+// defaultBatchSize is 1 for jobs that restart servers and bulkDefaultBatchSize otherwise
+func defaultBatchSize(restart bool) int {
+	if restart {
+		return 1
+	}
+	return bulkDefaultBatchSize
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitForBulkJob polls until the job has finished
+func waitForBulkJob(t *testing.T, s *BulkJobService, userID, jobID string) *BulkJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := s.Get(userID, jobID)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if job.FinishedAt != nil {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("bulk job did not finish")
+	return nil
+}
+
+func TestBulkJobBatches(t *testing.T) {
+	s := NewBulkJobService(nil, nil, nil)
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	fn := func(serverID string) (string, error) {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return "done", nil
+	}
+
+	started := s.start("user-1", BulkActionRestart, []string{"a", "b", "c", "b", "d", "e"}, BulkJobOptions{BatchSize: 2}, 1, map[string]string{"d": "server not found"}, fn)
+	if started.Total != 5 {
+		t.Fatalf("Total = %d, want 5 (duplicates dropped)", started.Total)
+	}
+
+	job := waitForBulkJob(t, s, "user-1", started.ID)
+	if maxRunning > 2 {
+		t.Errorf("%d servers ran at once, want at most the batch size 2", maxRunning)
+	}
+	if job.Status != BulkJobFailed || job.Succeeded != 4 || job.Failed != 1 || job.Progress != 100 {
+		t.Errorf("job = %+v, want failed with 4 succeeded and the rejected server failed", job)
+	}
+
+	wantBatches := map[string]int{"a": 1, "b": 1, "c": 2, "d": 0, "e": 2}
+	for _, item := range job.Items {
+		if item.Batch != wantBatches[item.ServerID] {
+			t.Errorf("server %s in batch %d, want %d", item.ServerID, item.Batch, wantBatches[item.ServerID])
+		}
+	}
+
+	if _, err := s.Get("user-2", started.ID); !errors.Is(err, ErrBulkJobNotFound) {
+		t.Errorf("Get() of another user error = %v, want ErrBulkJobNotFound", err)
+	}
+}
+
+func TestBulkJobStopOnFailure(t *testing.T) {
+	s := NewBulkJobService(nil, nil, nil)
+	fn := func(serverID string) (string, error) {
+		if serverID == "b" {
+			return "", errors.New("plugin broke the server")
+		}
+		return "done", nil
+	}
+
+	// The rejected server must not stop the rollout, the failing one must
+	started := s.start("user-1", BulkActionPluginInstall, []string{"x", "a", "b", "c", "d"}, BulkJobOptions{BatchSize: 1, StopOnFailure: true}, 1, map[string]string{"x": "forbidden"}, fn)
+	job := waitForBulkJob(t, s, "user-1", started.ID)
+
+	want := map[string]BulkItemStatus{
+		"x": BulkItemFailed,
+		"a": BulkItemSucceeded,
+		"b": BulkItemFailed,
+		"c": BulkItemSkipped,
+		"d": BulkItemSkipped,
+	}
+	for _, item := range job.Items {
+		if item.Status != want[item.ServerID] {
+			t.Errorf("server %s status = %s, want %s", item.ServerID, item.Status, want[item.ServerID])
+		}
+	}
+	if job.Status != BulkJobFailed || job.Done != 3 {
+		t.Errorf("job status %s with %d done, want failed with 3 done", job.Status, job.Done)
+	}
+}
+
+func TestBulkJobCancel(t *testing.T) {
+	s := NewBulkJobService(nil, nil, nil)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fn := func(serverID string) (string, error) {
+		if serverID == "a" {
+			close(started)
+			<-release
+		}
+		return "done", nil
+	}
+
+	job := s.start("user-1", BulkActionRestart, []string{"a", "b", "c"}, BulkJobOptions{}, 1, nil, fn)
+	<-started
+
+	if _, err := s.Cancel("user-2", job.ID); !errors.Is(err, ErrBulkJobNotFound) {
+		t.Errorf("Cancel() by another user error = %v, want ErrBulkJobNotFound", err)
+	}
+	cancelling, err := s.Cancel("user-1", job.ID)
+	if err != nil || cancelling.Status != BulkJobCancelling {
+		t.Fatalf("Cancel() = %+v, %v, want status cancelling", cancelling, err)
+	}
+	close(release)
+
+	// The running server finishes, the rest is never started
+	finished := waitForBulkJob(t, s, "user-1", job.ID)
+	want := map[string]BulkItemStatus{"a": BulkItemSucceeded, "b": BulkItemCancelled, "c": BulkItemCancelled}
+	for _, item := range finished.Items {
+		if item.Status != want[item.ServerID] {
+			t.Errorf("server %s status = %s, want %s", item.ServerID, item.Status, want[item.ServerID])
+		}
+	}
+	if finished.Status != BulkJobCancelled {
+		t.Errorf("job status = %s, want cancelled", finished.Status)
+	}
+	if _, err := s.Cancel("user-1", job.ID); !errors.Is(err, ErrBulkJobNotCancellable) {
+		t.Errorf("Cancel() of a finished job error = %v, want ErrBulkJobNotCancellable", err)
+	}
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// Clone errors
+var (
+	ErrCloneSourceArchived      = errors.New("archived servers cannot be cloned, unarchive the server first")
+	ErrCloneTemplateNotSet      = errors.New("saving clones as templates is not enabled")
+	ErrCloneTemplateNameMissing = errors.New("template_name is required when save_as_template is set")
+)
+
+// CloneRequest describes the server created by Clone
+type CloneRequest struct {
+	Name                string `json:"name" binding:"required"`
+	RAMMb               int    `json:"ram_mb"`               // 0 = RAM of the source server
+	SaveAsTemplate      bool   `json:"save_as_template"`     // Also save the snapshot as a reusable template of the user
+	TemplateName        string `json:"template_name"`        // Required with save_as_template
+	TemplateDescription string `json:"template_description"` // Optional
+}
+
+// CloneService copies servers and templates into new servers
+// Clone copies world, config and plugins of a source server; CreateFromTemplate creates a server
+// from a built-in or user template. The new server is created held (not queued) so nothing boots
+// before the world and plugins are in place; the copy runs as an OperationClone (OperationProvision
+// for templates) in the background, after which the server is queued.
+type CloneService struct {
+	serverRepo      *repository.ServerRepository
+	pluginRepo      *repository.PluginRepository
+	mcService       *MinecraftService
+	backupService   *BackupService
+	pluginManager   *PluginManagerService
+	templateService *TemplateService  // Optional, needed for save_as_template and templates
+	opLimiter       *OperationLimiter // Optional, tracks the copy in GET /api/operations and /api/jobs
+}
+
+// NewCloneService creates a new clone service
+func NewCloneService(
+	serverRepo *repository.ServerRepository,
+	pluginRepo *repository.PluginRepository,
+	mcService *MinecraftService,
+	backupService *BackupService,
+	pluginManager *PluginManagerService,
+	templateService *TemplateService,
+) *CloneService {
+	return &CloneService{
+		serverRepo:      serverRepo,
+		pluginRepo:      pluginRepo,
+		mcService:       mcService,
+		backupService:   backupService,
+		pluginManager:   pluginManager,
+		templateService: templateService,
+	}
+}
+
+// SetOperationLimiter sets the per-owner limiter that runs and tracks clone operations
+func (s *CloneService) SetOperationLimiter(opLimiter *OperationLimiter) {
+	s.opLimiter = opLimiter
+}
+
+// Clone creates a copy of sourceID owned by userID
+// The returned server is stopped until the copy finishes; the returned operation (nil without a
+// limiter) reports the progress and can be cancelled.
+func (s *CloneService) Clone(sourceID, userID string, req CloneRequest) (*models.MinecraftServer, *Operation, error) {
+	if req.SaveAsTemplate {
+		if s.templateService == nil {
+			return nil, nil, ErrCloneTemplateNotSet
+		}
+		if req.TemplateName == "" {
+			return nil, nil, ErrCloneTemplateNameMissing
+		}
+	}
+
+	source, err := s.serverRepo.FindByID(sourceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("server not found: %w", err)
+	}
+	if source.Status == models.StatusArchived || source.Status == models.StatusArchiving {
+		return nil, nil, ErrCloneSourceArchived
+	}
+
+	ramMB := req.RAMMb
+	if ramMB == 0 {
+		ramMB = source.RAMMb
+	}
+	settings := models.SettingsOf(source)
+
+	clone, err := s.mcService.CreateServerWithOptions(req.Name, source.ServerType, source.MinecraftVersion, ramMB, userID, NewServerOptions{
+		Configure: settings.ApplyTo,
+		Hold:      true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Info("CLONE: Cloning server", map[string]interface{}{
+		"source_server_id": source.ID,
+		"clone_server_id":  clone.ID,
+		"user_id":          userID,
+		"save_as_template": req.SaveAsTemplate,
+	})
+
+	op, err := s.opLimiter.Go(userID, userID, OperationClone, source.ID, clone.ID, func(ctx context.Context) error {
+		err := s.copyServer(ctx, source, clone, userID, req)
+		if err != nil {
+			s.markFailed(clone, err)
+		}
+		return err
+	})
+	if err != nil {
+		// Nothing was copied yet: drop the empty clone instead of leaving a broken server behind
+		if delErr := s.serverRepo.Delete(clone.ID); delErr != nil {
+			s.markFailed(clone, err)
+		}
+		return nil, nil, err
+	}
+	return clone, op, nil
+}
+
+// copyServer snapshots the source, restores the snapshot into the clone and queues the clone
+func (s *CloneService) copyServer(ctx context.Context, source, clone *models.MinecraftServer, userID string, req CloneRequest) error {
+	backupType := models.BackupTypeClone
+	if req.SaveAsTemplate {
+		backupType = models.BackupTypeTemplate
+	}
+
+	s.opLimiter.SetProgress(OperationClone, clone.ID, "snapshotting", 0)
+	backup, err := s.backupService.CreateBackupSync(ctx, source.ID, backupType, fmt.Sprintf("Snapshot for clone %s", clone.Name), &userID, 0)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot source server: %w", err)
+	}
+	if backup.Status != models.BackupStatusCompleted {
+		if backup.Status == models.BackupStatusCancelled {
+			return context.Canceled
+		}
+		return fmt.Errorf("failed to snapshot source server: %s", backup.ErrorMessage)
+	}
+
+	s.opLimiter.SetProgress(OperationClone, clone.ID, "restoring", 0)
+	if err := s.backupService.RestoreBackup(ctx, backup.ID, clone.ID, nil); err != nil {
+		return fmt.Errorf("failed to restore snapshot into clone: %w", err)
+	}
+
+	// Plugin files came with the snapshot, the records make them manageable on the clone
+	installed, err := s.pluginRepo.ListInstalledPlugins(source.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list plugins of source server: %w", err)
+	}
+	var pluginSlugs []string
+	for _, plugin := range installed {
+		if plugin.Plugin != nil {
+			pluginSlugs = append(pluginSlugs, plugin.Plugin.Slug)
+		}
+		copied := &models.InstalledPlugin{
+			ServerID:   clone.ID,
+			PluginID:   plugin.PluginID,
+			VersionID:  plugin.VersionID,
+			Enabled:    plugin.Enabled,
+			AutoUpdate: plugin.AutoUpdate,
+		}
+		if err := s.pluginRepo.InstallPlugin(copied); err != nil {
+			return fmt.Errorf("failed to copy plugin %s: %w", plugin.PluginID, err)
+		}
+	}
+
+	if req.SaveAsTemplate {
+		template := &models.UserTemplate{
+			OwnerID:     userID,
+			Name:        req.TemplateName,
+			Description: req.TemplateDescription,
+			TemplateSpec: models.TemplateSpec{
+				ServerType:       source.ServerType,
+				MinecraftVersion: source.MinecraftVersion,
+				RAMMb:            clone.RAMMb,
+				Settings:         models.SettingsOf(source),
+				Plugins:          pluginSlugs,
+				BackupID:         backup.ID,
+			},
+			SourceServerID: source.ID,
+		}
+		if err := s.templateService.SaveUserTemplate(template, "Saved from server "+source.Name); err != nil {
+			// The clone itself is fine, only the template is missing
+			logger.Warn("CLONE: Failed to save template", map[string]interface{}{
+				"source_server_id": source.ID,
+				"backup_id":        backup.ID,
+				"error":            err.Error(),
+			})
+		}
+	}
+
+	s.opLimiter.SetProgress(OperationClone, clone.ID, "queueing", 100)
+	if err := s.mcService.QueueServer(clone.ID); err != nil {
+		return err
+	}
+
+	logger.Info("CLONE: Server cloned", map[string]interface{}{
+		"source_server_id": source.ID,
+		"clone_server_id":  clone.ID,
+		"backup_id":        backup.ID,
+	})
+	return nil
+}
+
+// CreateFromTemplate creates a server owned by userID from a template (see TemplateService.ResolveTemplate)
+// ramMB 0 uses the RAM of the template. Templates without world snapshot and plugins are queued
+// right away (nil operation); otherwise the server stays stopped until the returned operation
+// has restored the world and installed the plugins.
+func (s *CloneService) CreateFromTemplate(userID, name, templateID string, revision, ramMB int) (*models.MinecraftServer, *Operation, error) {
+	if s.templateService == nil {
+		return nil, nil, ErrUserTemplatesDisabled
+	}
+	spec, err := s.templateService.ResolveTemplate(userID, templateID, revision)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ramMB != 0 {
+		spec.RAMMb = ramMB
+	}
+
+	server, op, err := s.CreateFromSpec(userID, name, spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.templateService.RecordUsage(templateID)
+
+	logger.Info("CLONE: Created server from template", map[string]interface{}{
+		"server_id":   server.ID,
+		"template_id": templateID,
+		"revision":    revision,
+		"user_id":     userID,
+	})
+	return server, op, nil
+}
+
+// CreateFromSpec creates a server owned by userID with the type, version, RAM, settings, plugins
+// and world snapshot of spec (see CreateFromTemplate for the returned operation)
+func (s *CloneService) CreateFromSpec(userID, name string, spec *models.TemplateSpec) (*models.MinecraftServer, *Operation, error) {
+	needsSetup := spec.BackupID != "" || len(spec.Plugins) > 0
+	server, err := s.mcService.CreateServerWithOptions(name, spec.ServerType, spec.MinecraftVersion, spec.RAMMb, userID, NewServerOptions{
+		Configure: spec.Settings.ApplyTo,
+		Hold:      needsSetup,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !needsSetup {
+		return server, nil, nil
+	}
+
+	op, err := s.opLimiter.Go(userID, userID, OperationProvision, server.ID, server.ID, func(ctx context.Context) error {
+		err := s.setupFromTemplate(ctx, server, spec)
+		if err != nil {
+			s.markFailed(server, err)
+		}
+		return err
+	})
+	if err != nil {
+		if delErr := s.serverRepo.Delete(server.ID); delErr != nil {
+			s.markFailed(server, err)
+		}
+		return nil, nil, err
+	}
+	return server, op, nil
+}
+
+// setupFromTemplate restores the template world, installs the template plugins and queues the server
+func (s *CloneService) setupFromTemplate(ctx context.Context, server *models.MinecraftServer, spec *models.TemplateSpec) error {
+	if spec.BackupID != "" {
+		s.opLimiter.SetProgress(OperationProvision, server.ID, "restoring", 0)
+		if err := s.backupService.RestoreBackup(ctx, spec.BackupID, server.ID, nil); err != nil {
+			return fmt.Errorf("failed to restore template world: %w", err)
+		}
+	}
+
+	for i, slug := range spec.Plugins {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.opLimiter.SetProgress(OperationProvision, server.ID, "installing_plugins", i*100/len(spec.Plugins))
+		// A missing or incompatible plugin should not cost the user the whole server
+		if err := s.pluginManager.InstallPlugin(server.ID, slug, "", false); err != nil {
+			logger.Warn("CLONE: Failed to install template plugin", map[string]interface{}{
+				"server_id": server.ID,
+				"plugin":    slug,
+				"error":     err.Error(),
+			})
+		}
+	}
+
+	s.opLimiter.SetProgress(OperationProvision, server.ID, "queueing", 100)
+	return s.mcService.QueueServer(server.ID)
+}
+
+// markFailed leaves a server that could not be set up in the error state (the user may delete it)
+func (s *CloneService) markFailed(clone *models.MinecraftServer, cause error) {
+	logger.Warn("CLONE: Failed to clone server", map[string]interface{}{
+		"clone_server_id": clone.ID,
+		"error":           cause.Error(),
+	})
+
+	server, err := s.serverRepo.FindByID(clone.ID)
+	if err != nil {
+		return
+	}
+	server.Status = models.StatusError
+	if err := s.serverRepo.Update(server); err != nil {
+		logger.Warn("CLONE: Failed to mark clone as failed", map[string]interface{}{
+			"clone_server_id": clone.ID,
+			"error":           err.Error(),
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -518,10 +519,12 @@ func (s *ConfigService) applyChanges(server *models.MinecraftServer, changes map
 		logger.Info("Recreating container with new configuration", map[string]interface{}{
 			"server_id": server.ID,
 		})
+		// Not tied to the request: a recreation that stops halfway leaves the server without a container
+		ctx := context.Background()
 
 		// Stop old container
 		if server.ContainerID != "" {
-			err = s.dockerService.StopContainer(server.ContainerID, 30)
+			err = s.dockerService.StopContainer(ctx, server.ContainerID, 30)
 			if err != nil {
 				logger.Warn("Failed to stop old container", map[string]interface{}{
 					"server_id":    server.ID,
@@ -531,7 +534,7 @@ func (s *ConfigService) applyChanges(server *models.MinecraftServer, changes map
 			}
 
 			// Remove old container
-			err = s.dockerService.RemoveContainer(server.ContainerID, true)
+			err = s.dockerService.RemoveContainer(ctx, server.ContainerID, true)
 			if err != nil {
 				logger.Warn("Failed to remove old container", map[string]interface{}{
 					"server_id":    server.ID,
@@ -543,6 +546,7 @@ func (s *ConfigService) applyChanges(server *models.MinecraftServer, changes map
 
 		// Create new container with updated config
 		containerID, err := s.dockerService.CreateContainer(
+			ctx,
 			server.ID,
 			string(server.ServerType),
 			server.MinecraftVersion,
@@ -575,6 +579,12 @@ func (s *ConfigService) applyChanges(server *models.MinecraftServer, changes map
 			server.NetworkCompressionThreshold,
 			// Phase 4 Parameters - Server Description
 			server.MOTD,
+			// Runtime Diagnostics
+			server.HeapDumpsActive(),
+			// Vote Site Integration
+			server.VotifierPort,
+			s.dockerService.ImageName(server.Channel()),
+			s.dockerService.CPULimits(server),
 		)
 		if err != nil {
 			return fmt.Errorf("failed to create new container: %w", err)
@@ -590,7 +600,7 @@ func (s *ConfigService) applyChanges(server *models.MinecraftServer, changes map
 		}
 
 		// Start the new container
-		err = s.dockerService.StartContainer(containerID)
+		err = s.dockerService.StartContainer(ctx, containerID)
 		if err != nil {
 			server.Status = models.StatusError
 			s.serverRepo.Update(server)
@@ -598,7 +608,7 @@ func (s *ConfigService) applyChanges(server *models.MinecraftServer, changes map
 		}
 
 		// Wait for server to be ready
-		err = s.dockerService.WaitForServerReady(containerID, 60)
+		err = s.dockerService.WaitForServerReady(ctx, containerID, 60)
 		if err != nil {
 			logger.Warn("Server may not be fully ready", map[string]interface{}{
 				"server_id": server.ID,
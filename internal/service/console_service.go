@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/payperplay/hosting/internal/docker"
@@ -19,8 +20,8 @@ func NewConsoleService(repo *repository.ServerRepository, dockerService *docker.
 	}
 }
 
-// StreamLogs streams container logs for a server
-func (s *ConsoleService) StreamLogs(serverID string) (<-chan string, func(), error) {
+// StreamLogs streams container logs for a server until the returned cancel function is called or ctx ends
+func (s *ConsoleService) StreamLogs(ctx context.Context, serverID string) (<-chan string, func(), error) {
 	// Get server from database
 	server, err := s.repo.FindByID(serverID)
 	if err != nil {
@@ -32,7 +33,7 @@ func (s *ConsoleService) StreamLogs(serverID string) (<-chan string, func(), err
 	}
 
 	// Stream logs from Docker
-	logChan, cancel, err := s.dockerService.StreamContainerLogs(server.ContainerID)
+	logChan, cancel, err := s.dockerService.StreamContainerLogs(ctx, server.ContainerID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to stream logs: %w", err)
 	}
@@ -41,7 +42,7 @@ func (s *ConsoleService) StreamLogs(serverID string) (<-chan string, func(), err
 }
 
 // ExecuteCommand executes a command on the server via docker exec
-func (s *ConsoleService) ExecuteCommand(serverID, command string) (string, error) {
+func (s *ConsoleService) ExecuteCommand(ctx context.Context, serverID, command string) (string, error) {
 	// Get server from database
 	server, err := s.repo.FindByID(serverID)
 	if err != nil {
@@ -53,7 +54,7 @@ func (s *ConsoleService) ExecuteCommand(serverID, command string) (string, error
 	}
 
 	// Execute command via docker exec (uses rcon-cli inside container)
-	response, err := s.dockerService.ExecuteCommand(server.ContainerID, command)
+	response, err := s.dockerService.ExecuteCommand(ctx, server.ContainerID, command)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute command: %w", err)
 	}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/storage"
+)
+
+// Container metrics errors
+var (
+	ErrContainerMetricsDisabled = errors.New("performance metrics need InfluxDB (INFLUXDB_URL, INFLUXDB_TOKEN)")
+	ErrContainerMetricsRange    = errors.New("invalid range (valid: 1h, 6h, 24h, 7d)")
+)
+
+// ContainerMetricsStore stores container samples as time series (implemented by storage.InfluxDBClient)
+type ContainerMetricsStore interface {
+	WriteContainerMetrics(points []storage.ContainerMetricsPoint)
+	QueryContainerMetrics(ctx context.Context, serverID string, start time.Time, window time.Duration) ([]storage.ContainerMetricsPoint, error)
+}
+
+// containerMetricsRange is a graph time range and the window its samples are averaged over
+type containerMetricsRange struct {
+	span   time.Duration
+	window time.Duration
+}
+
+// containerMetricsRanges keep every graph at 60-170 points
+var containerMetricsRanges = map[string]containerMetricsRange{
+	"1h":  {span: time.Hour, window: time.Minute},
+	"6h":  {span: 6 * time.Hour, window: 5 * time.Minute},
+	"24h": {span: 24 * time.Hour, window: 15 * time.Minute},
+	"7d":  {span: 7 * 24 * time.Hour, window: time.Hour},
+}
+
+// ContainerMetricsHistory is the performance graph data of a server
+type ContainerMetricsHistory struct {
+	ServerID      string                          `json:"server_id"`
+	Range         string                          `json:"range"`
+	WindowSeconds int                             `json:"window_seconds"` // Samples are averaged per window
+	Points        []storage.ContainerMetricsPoint `json:"points"`
+}
+
+// containerCounters are the IO counters of a container's previous sample
+type containerCounters struct {
+	nodeID    string
+	sampledAt time.Time
+	stats     docker.ContainerStats
+}
+
+// ContainerMetricsService stores the per-container samples of the conductor's metrics worker
+// (CPU, memory, network and block IO of local and remote nodes) and serves them as performance graphs.
+// IO counters are turned into rates against the previous sample of the same container.
+type ContainerMetricsService struct {
+	store ContainerMetricsStore // Nil if InfluxDB isn't configured
+
+	mu       sync.Mutex
+	previous map[string]containerCounters // key: serverID
+}
+
+// NewContainerMetricsService creates a new container metrics service (store may be nil)
+func NewContainerMetricsService(store ContainerMetricsStore) *ContainerMetricsService {
+	return &ContainerMetricsService{
+		store:    store,
+		previous: make(map[string]containerCounters),
+	}
+}
+
+// Enabled returns whether samples are stored
+func (s *ContainerMetricsService) Enabled() bool {
+	return s.store != nil
+}
+
+// RecordContainerStats stores one sample of every container on a node (conductor.ContainerMetricsSink)
+func (s *ContainerMetricsService) RecordContainerStats(nodeID string, sampledAt time.Time, stats map[string]docker.ContainerStats) {
+	if s.store == nil {
+		return
+	}
+
+	s.mu.Lock()
+	points := make([]storage.ContainerMetricsPoint, 0, len(stats))
+	for serverID, sample := range stats {
+		points = append(points, containerMetricsPoint(serverID, nodeID, sampledAt, sample, s.previous[serverID]))
+		s.previous[serverID] = containerCounters{nodeID: nodeID, sampledAt: sampledAt, stats: sample}
+	}
+	// Forget containers that stopped on this node
+	for serverID, previous := range s.previous {
+		if _, running := stats[serverID]; !running && previous.nodeID == nodeID {
+			delete(s.previous, serverID)
+		}
+	}
+	s.mu.Unlock()
+
+	s.store.WriteContainerMetrics(points)
+}
+
+// History returns the performance graph data of a server for a range (1h, 6h, 24h, 7d)
+func (s *ContainerMetricsService) History(ctx context.Context, serverID, rangeName string) (*ContainerMetricsHistory, error) {
+	if s.store == nil {
+		return nil, ErrContainerMetricsDisabled
+	}
+	metricsRange, ok := containerMetricsRanges[rangeName]
+	if !ok {
+		return nil, ErrContainerMetricsRange
+	}
+
+	points, err := s.store.QueryContainerMetrics(ctx, serverID, time.Now().Add(-metricsRange.span), metricsRange.window)
+	if err != nil {
+		return nil, err
+	}
+	if points == nil {
+		points = []storage.ContainerMetricsPoint{}
+	}
+	return &ContainerMetricsHistory{
+		ServerID:      serverID,
+		Range:         rangeName,
+		WindowSeconds: int(metricsRange.window.Seconds()),
+		Points:        points,
+	}, nil
+}
+
+// containerMetricsPoint builds the stored point of a sample, with IO rates if the previous
+// sample is from the same container (same node, counters didn't restart)
+func containerMetricsPoint(serverID, nodeID string, sampledAt time.Time, sample docker.ContainerStats, previous containerCounters) storage.ContainerMetricsPoint {
+	point := storage.ContainerMetricsPoint{
+		ServerID:         serverID,
+		NodeID:           nodeID,
+		Time:             sampledAt,
+		CPUPercent:       sample.CPUPercent,
+		MemoryBytes:      float64(sample.MemoryBytes),
+		MemoryLimitBytes: float64(sample.MemoryLimitBytes),
+	}
+
+	seconds := sampledAt.Sub(previous.sampledAt).Seconds()
+	last := previous.stats
+	if previous.nodeID != nodeID || seconds <= 0 ||
+		sample.NetRxBytes < last.NetRxBytes || sample.NetTxBytes < last.NetTxBytes ||
+		sample.BlockReadBytes < last.BlockReadBytes || sample.BlockWriteBytes < last.BlockWriteBytes {
+		return point // First sample, moved or restarted container
+	}
+
+	point.HasRates = true
+	point.NetRxBytesPerSec = float64(sample.NetRxBytes-last.NetRxBytes) / seconds
+	point.NetTxBytesPerSec = float64(sample.NetTxBytes-last.NetTxBytes) / seconds
+	point.BlockReadBytesPerSec = float64(sample.BlockReadBytes-last.BlockReadBytes) / seconds
+	point.BlockWriteBytesPerSec = float64(sample.BlockWriteBytes-last.BlockWriteBytes) / seconds
+	return point
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/storage"
+)
+
+type memoryMetricsStore struct {
+	points []storage.ContainerMetricsPoint
+}
+
+func (m *memoryMetricsStore) WriteContainerMetrics(points []storage.ContainerMetricsPoint) {
+	m.points = append(m.points, points...)
+}
+
+func (m *memoryMetricsStore) QueryContainerMetrics(ctx context.Context, serverID string, start time.Time, window time.Duration) ([]storage.ContainerMetricsPoint, error) {
+	return nil, nil
+}
+
+func TestContainerMetricsRates(t *testing.T) {
+	store := &memoryMetricsStore{}
+	metrics := NewContainerMetricsService(store)
+	start := time.Now()
+
+	metrics.RecordContainerStats("node-1", start, map[string]docker.ContainerStats{
+		"server-1": {CPUPercent: 50, NetRxBytes: 1000, BlockWriteBytes: 5000},
+	})
+	metrics.RecordContainerStats("node-1", start.Add(10*time.Second), map[string]docker.ContainerStats{
+		"server-1": {CPUPercent: 70, NetRxBytes: 3000, BlockWriteBytes: 15000},
+	})
+	// Restarted container: counters start over, no rates
+	metrics.RecordContainerStats("node-1", start.Add(20*time.Second), map[string]docker.ContainerStats{
+		"server-1": {CPUPercent: 10, NetRxBytes: 100},
+	})
+
+	if len(store.points) != 3 {
+		t.Fatalf("stored %d points, want 3", len(store.points))
+	}
+	if store.points[0].HasRates {
+		t.Error("first sample has rates")
+	}
+	second := store.points[1]
+	if !second.HasRates || second.NetRxBytesPerSec != 200 || second.BlockWriteBytesPerSec != 1000 {
+		t.Errorf("second sample rates = %v rx %v/s write %v/s, want rx 200/s write 1000/s",
+			second.HasRates, second.NetRxBytesPerSec, second.BlockWriteBytesPerSec)
+	}
+	if store.points[2].HasRates {
+		t.Error("sample after counter reset has rates")
+	}
+
+	// Stopped containers are forgotten, a later start on another node begins without rates
+	metrics.RecordContainerStats("node-1", start.Add(30*time.Second), map[string]docker.ContainerStats{})
+	if _, ok := metrics.previous["server-1"]; ok {
+		t.Error("stopped container still tracked")
+	}
+}
+
+func TestContainerMetricsHistory(t *testing.T) {
+	if _, err := NewContainerMetricsService(nil).History(context.Background(), "server-1", "1h"); err != ErrContainerMetricsDisabled {
+		t.Errorf("History without store = %v, want ErrContainerMetricsDisabled", err)
+	}
+	history, err := NewContainerMetricsService(&memoryMetricsStore{}).History(context.Background(), "server-1", "6h")
+	if err != nil || history.WindowSeconds != 300 || history.Points == nil {
+		t.Errorf("History(6h) = %+v, %v", history, err)
+	}
+	if _, err := NewContainerMetricsService(&memoryMetricsStore{}).History(context.Background(), "server-1", "2h"); err != ErrContainerMetricsRange {
+		t.Errorf("History(2h) = %v, want ErrContainerMetricsRange", err)
+	}
+}
@@ -0,0 +1,484 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/monitoring"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// diskForecastHorizon raises a disk warning below the threshold if the disk is projected to fill up this soon
+const diskForecastHorizon = 6 * time.Hour
+
+// diskRateSmoothing is the EWMA weight of the newest disk consumption sample
+const diskRateSmoothing = 0.3
+
+// ErrControlPlaneBusy is returned for heavy local work while the control plane is under critical pressure
+var ErrControlPlaneBusy = errors.New("the platform is under heavy load, please try again in a few minutes")
+
+// ResourcePressure is the pressure level of a control plane resource
+type ResourcePressure string
+
+const (
+	PressureOK       ResourcePressure = "ok"
+	PressureWarning  ResourcePressure = "warning"  // Admins are alerted
+	PressureCritical ResourcePressure = "critical" // Heavy local work (backups, restores, archives) is deferred
+)
+
+// severity orders pressure levels for comparisons
+func (p ResourcePressure) severity() int {
+	switch p {
+	case PressureCritical:
+		return 2
+	case PressureWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ControlPlaneResource is the last sample of one resource (cpu, memory or disk:<path>)
+type ControlPlaneResource struct {
+	Name           string           `json:"name"`
+	Percent        float64          `json:"percent"`
+	Level          ResourcePressure `json:"level"`
+	WarnAt         int              `json:"warn_at"`
+	CriticalAt     int              `json:"critical_at"`
+	TotalBytes     uint64           `json:"total_bytes,omitempty"`
+	AvailableBytes uint64           `json:"available_bytes,omitempty"`
+	HoursUntilFull float64          `json:"hours_until_full,omitempty"` // Disks only, 0 = not filling up
+}
+
+// ControlPlaneStatus is the last sample of the control plane host
+type ControlPlaneStatus struct {
+	Level              ResourcePressure       `json:"level"` // Worst resource level
+	Resources          []ControlPlaneResource `json:"resources"`
+	DeferredOperations int                    `json:"deferred_operations"` // Heavy operations currently waiting for headroom
+	SampledAt          time.Time              `json:"sampled_at"`
+}
+
+// diskTrend tracks how fast a disk fills up
+type diskTrend struct {
+	available uint64
+	sampledAt time.Time
+	rate      float64 // Smoothed bytes/s consumed (negative = freeing up)
+}
+
+// ControlPlaneMonitor watches CPU, memory and disk of the control plane host, which also runs
+// Postgres, backup compression and archive staging. Warnings alert admins (dashboard + email);
+// critical pressure defers heavy local work until there is headroom again.
+// All guard methods are safe on a nil *ControlPlaneMonitor.
+type ControlPlaneMonitor struct {
+	cfg          *config.Config
+	userRepo     *repository.UserRepository
+	emailService *EmailService         // Optional
+	wsHub        WebSocketHubInterface // Optional
+
+	interval  time.Duration
+	cooldown  time.Duration
+	maxDefer  time.Duration
+	diskPaths []string
+
+	mu         sync.RWMutex
+	status     ControlPlaneStatus
+	prevCPU    monitoring.CPUTimes
+	haveCPU    bool
+	diskTrends map[string]*diskTrend
+	alerted    map[string]ResourcePressure // Last alerted level per resource
+	lastAlert  map[string]time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewControlPlaneMonitor creates a new control plane monitor
+func NewControlPlaneMonitor(cfg *config.Config, userRepo *repository.UserRepository, emailService *EmailService) *ControlPlaneMonitor {
+	m := &ControlPlaneMonitor{
+		cfg:          cfg,
+		userRepo:     userRepo,
+		emailService: emailService,
+		interval:     parseDurationOr(cfg.ControlPlaneMonitorInterval, 30*time.Second),
+		cooldown:     parseDurationOr(cfg.ControlPlaneAlertCooldown, time.Hour),
+		maxDefer:     parseDurationOr(cfg.ControlPlaneMaxDefer, 30*time.Minute),
+		diskTrends:   make(map[string]*diskTrend),
+		alerted:      make(map[string]ResourcePressure),
+		lastAlert:    make(map[string]time.Time),
+		status:       ControlPlaneStatus{Level: PressureOK},
+	}
+
+	if cfg.ControlPlaneDiskPaths != "" {
+		for _, path := range strings.Split(cfg.ControlPlaneDiskPaths, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				m.diskPaths = append(m.diskPaths, path)
+			}
+		}
+	} else {
+		m.diskPaths = []string{cfg.ServersBasePath, "/"}
+	}
+	return m
+}
+
+// SetWebSocketHub sets the WebSocket hub for control plane alerts
+func (m *ControlPlaneMonitor) SetWebSocketHub(wsHub WebSocketHubInterface) {
+	m.wsHub = wsHub
+}
+
+// Start begins sampling in the background
+func (m *ControlPlaneMonitor) Start() {
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+
+	logger.Info("CONTROL-PLANE: Starting resource monitor", map[string]interface{}{
+		"interval":   m.interval.String(),
+		"disk_paths": m.diskPaths,
+		"max_defer":  m.maxDefer.String(),
+	})
+
+	go func() {
+		m.sample()
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.sample()
+			case <-m.ctx.Done():
+				logger.Info("CONTROL-PLANE: Resource monitor stopped", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops sampling
+func (m *ControlPlaneMonitor) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+}
+
+// Status returns the last sample
+func (m *ControlPlaneMonitor) Status() ControlPlaneStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	status := m.status
+	status.Resources = append([]ControlPlaneResource(nil), m.status.Resources...)
+	return status
+}
+
+// Level returns the worst resource level of the last sample
+func (m *ControlPlaneMonitor) Level() ResourcePressure {
+	if m == nil {
+		return PressureOK
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status.Level
+}
+
+// CheckHeadroom returns ErrControlPlaneBusy while the control plane is under critical pressure
+// (for user-facing operations that shouldn't block, e.g. restores)
+func (m *ControlPlaneMonitor) CheckHeadroom(operation string) error {
+	if m.Level() != PressureCritical {
+		return nil
+	}
+	logger.Warn("CONTROL-PLANE: Rejecting heavy operation under critical pressure", map[string]interface{}{
+		"operation": operation,
+	})
+	return ErrControlPlaneBusy
+}
+
+// WaitForHeadroom blocks background work while the control plane is under critical pressure
+// Returns ErrControlPlaneBusy if there is still no headroom after the configured max defer time.
+func (m *ControlPlaneMonitor) WaitForHeadroom(ctx context.Context, operation string) error {
+	if m.Level() != PressureCritical {
+		return nil
+	}
+
+	m.mu.Lock()
+	m.status.DeferredOperations++
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.status.DeferredOperations--
+		m.mu.Unlock()
+	}()
+
+	logger.Warn("CONTROL-PLANE: Deferring heavy operation until resources recover", map[string]interface{}{
+		"operation": operation,
+		"max_defer": m.maxDefer.String(),
+	})
+
+	start := time.Now()
+	deadline := time.NewTimer(m.maxDefer)
+	defer deadline.Stop()
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for m.Level() == PressureCritical {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return ErrControlPlaneBusy
+		case <-ticker.C:
+		}
+	}
+
+	logger.Info("CONTROL-PLANE: Resuming deferred operation", map[string]interface{}{
+		"operation": operation,
+		"waited_s":  int(time.Since(start).Seconds()),
+	})
+	return nil
+}
+
+// CompressionWorkers returns the compression parallelism to use right now
+// Under CPU or memory pressure, compression runs with half the workers (0 = all CPUs).
+func (m *ControlPlaneMonitor) CompressionWorkers(configured int) int {
+	if m == nil {
+		return configured
+	}
+
+	m.mu.RLock()
+	throttle := false
+	for _, resource := range m.status.Resources {
+		if (resource.Name == "cpu" || resource.Name == "memory") && resource.Level != PressureOK {
+			throttle = true
+		}
+	}
+	m.mu.RUnlock()
+
+	if !throttle {
+		return configured
+	}
+	if configured <= 0 {
+		configured = runtime.NumCPU()
+	}
+	if configured/2 < 1 {
+		return 1
+	}
+	return configured / 2
+}
+
+// sample reads all resources, updates metrics and raises alerts
+func (m *ControlPlaneMonitor) sample() {
+	now := time.Now()
+	var resources []ControlPlaneResource
+
+	if cpu, err := monitoring.ReadCPUTimes(); err == nil {
+		m.mu.Lock()
+		prev, havePrev := m.prevCPU, m.haveCPU
+		m.prevCPU, m.haveCPU = cpu, true
+		m.mu.Unlock()
+
+		if havePrev {
+			percent := monitoring.CPUPercent(prev, cpu)
+			resources = append(resources, m.resource("cpu", percent, m.cfg.ControlPlaneCPUWarn, m.cfg.ControlPlaneCPUCritical))
+			monitoring.ControlPlaneCPUPercent.Set(percent)
+		}
+	} else {
+		logger.Debug("CONTROL-PLANE: Failed to read CPU stats", map[string]interface{}{"error": err.Error()})
+	}
+
+	if memory, err := monitoring.ReadMemoryStats(); err == nil {
+		resource := m.resource("memory", memory.UsedPercent(), m.cfg.ControlPlaneMemoryWarn, m.cfg.ControlPlaneMemoryCritical)
+		resource.TotalBytes = memory.TotalBytes
+		resource.AvailableBytes = memory.AvailableBytes
+		resources = append(resources, resource)
+		monitoring.ControlPlaneMemoryPercent.Set(resource.Percent)
+	} else {
+		logger.Debug("CONTROL-PLANE: Failed to read memory stats", map[string]interface{}{"error": err.Error()})
+	}
+
+	seen := make(map[string]bool)
+	for _, path := range m.diskPaths {
+		disk, err := monitoring.ReadDiskStats(path)
+		if err != nil {
+			logger.Warn("CONTROL-PLANE: Failed to read disk stats", map[string]interface{}{
+				"path":  path,
+				"error": err.Error(),
+			})
+			continue
+		}
+		key := fmt.Sprintf("%d/%d", disk.TotalBytes, disk.AvailableBytes) // Same filesystem mounted under several paths
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		resource := m.resource("disk:"+filepath.Clean(path), disk.UsedPercent(), m.cfg.ControlPlaneDiskWarn, m.cfg.ControlPlaneDiskCritical)
+		resource.TotalBytes = disk.TotalBytes
+		resource.AvailableBytes = disk.AvailableBytes
+		resource.HoursUntilFull = m.forecastDisk(resource.Name, disk.AvailableBytes, now)
+		if resource.Level == PressureOK && resource.HoursUntilFull > 0 && resource.HoursUntilFull < diskForecastHorizon.Hours() {
+			resource.Level = PressureWarning
+		}
+		resources = append(resources, resource)
+		monitoring.ControlPlaneDiskPercent.WithLabelValues(path).Set(resource.Percent)
+	}
+
+	level := PressureOK
+	for _, resource := range resources {
+		if resource.Level.severity() > level.severity() {
+			level = resource.Level
+		}
+	}
+	monitoring.ControlPlanePressure.Set(float64(level.severity()))
+
+	m.mu.Lock()
+	m.status.Level = level
+	m.status.Resources = resources
+	m.status.SampledAt = now
+	m.mu.Unlock()
+
+	events.PublishControlPlaneStats(map[string]interface{}{
+		"level":      level,
+		"resources":  resources,
+		"sampled_at": now,
+	})
+
+	for _, resource := range resources {
+		m.checkAlert(resource, now)
+	}
+}
+
+// resource classifies a percentage against its thresholds (0 = threshold disabled)
+func (m *ControlPlaneMonitor) resource(name string, percent float64, warnAt, criticalAt int) ControlPlaneResource {
+	level := PressureOK
+	if criticalAt > 0 && percent >= float64(criticalAt) {
+		level = PressureCritical
+	} else if warnAt > 0 && percent >= float64(warnAt) {
+		level = PressureWarning
+	}
+	return ControlPlaneResource{
+		Name:       name,
+		Percent:    percent,
+		Level:      level,
+		WarnAt:     warnAt,
+		CriticalAt: criticalAt,
+	}
+}
+
+// forecastDisk returns the projected hours until a disk is full (0 = not filling up)
+func (m *ControlPlaneMonitor) forecastDisk(name string, available uint64, now time.Time) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	trend, ok := m.diskTrends[name]
+	if !ok {
+		m.diskTrends[name] = &diskTrend{available: available, sampledAt: now}
+		return 0
+	}
+
+	elapsed := now.Sub(trend.sampledAt).Seconds()
+	if elapsed > 0 {
+		consumed := (float64(trend.available) - float64(available)) / elapsed
+		trend.rate = diskRateSmoothing*consumed + (1-diskRateSmoothing)*trend.rate
+	}
+	trend.available = available
+	trend.sampledAt = now
+
+	if trend.rate <= 0 {
+		return 0
+	}
+	return float64(available) / trend.rate / 3600
+}
+
+// checkAlert alerts admins when a resource escalates, repeats after the cooldown and when it recovers
+func (m *ControlPlaneMonitor) checkAlert(resource ControlPlaneResource, now time.Time) {
+	m.mu.Lock()
+	previous, wasAlerted := m.alerted[resource.Name]
+	if !wasAlerted {
+		previous = PressureOK
+	}
+
+	var message string
+	threshold := resource.WarnAt
+	switch {
+	case resource.Level == PressureOK && previous != PressureOK:
+		message = fmt.Sprintf("Control plane %s recovered: %.1f%% used", resource.Name, resource.Percent)
+		delete(m.alerted, resource.Name)
+	case resource.Level != PressureOK &&
+		(resource.Level.severity() > previous.severity() || now.Sub(m.lastAlert[resource.Name]) >= m.cooldown):
+		if resource.Level == PressureCritical {
+			threshold = resource.CriticalAt
+		}
+		message = fmt.Sprintf("Control plane %s is at %.1f%% (%s threshold %d%%)", resource.Name, resource.Percent, resource.Level, threshold)
+		if resource.HoursUntilFull > 0 && resource.HoursUntilFull < diskForecastHorizon.Hours() {
+			message += fmt.Sprintf(", projected to be full in %.1f hours", resource.HoursUntilFull)
+		}
+		if resource.Level == PressureCritical {
+			message += ". Backups, restores and archives are deferred until it recovers."
+		}
+		m.alerted[resource.Name] = resource.Level
+		m.lastAlert[resource.Name] = now
+	default:
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+
+	fields := map[string]interface{}{
+		"resource": resource.Name,
+		"level":    resource.Level,
+		"percent":  resource.Percent,
+	}
+	if resource.Level == PressureOK {
+		logger.Info("CONTROL-PLANE: "+message, fields)
+	} else {
+		logger.Warn("CONTROL-PLANE: "+message, fields)
+	}
+
+	events.PublishControlPlaneAlert(resource.Name, string(resource.Level), resource.Percent, threshold, message)
+	if m.wsHub != nil {
+		m.wsHub.Broadcast("control_plane_alert", map[string]interface{}{
+			"resource": resource.Name,
+			"level":    resource.Level,
+			"percent":  resource.Percent,
+			"message":  message,
+		})
+	}
+
+	go m.emailAdmins(resource, message)
+}
+
+// emailAdmins sends an alert email to every active platform admin
+func (m *ControlPlaneMonitor) emailAdmins(resource ControlPlaneResource, message string) {
+	if m.emailService == nil || m.userRepo == nil {
+		return
+	}
+
+	admins, err := m.userRepo.FindAdmins()
+	if err != nil {
+		logger.Error("CONTROL-PLANE: Failed to load admins for alert", err, nil)
+		return
+	}
+
+	subject := fmt.Sprintf("Control plane %s %s", resource.Name, resource.Level)
+	for _, admin := range admins {
+		if err := m.emailService.SendAdminAlert(admin.Email, subject, message); err != nil {
+			logger.Error("CONTROL-PLANE: Failed to send alert email", err, map[string]interface{}{
+				"admin_id": admin.ID,
+			})
+		}
+	}
+}
+
+// parseDurationOr parses a duration setting, falling back to def if it's empty or invalid
+func parseDurationOr(value string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	return def
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -33,21 +34,26 @@ type CostOptimizationService struct {
 	currentSuggestions []OptimizationSuggestion
 	suggestionsMu      sync.RWMutex
 	lastAnalysis       time.Time
+
+	residency *ResidencyService          // Optional: keeps migration targets within the organization's residency country
+	analytics *MigrationAnalyticsService // Optional: skips migrations whose historical downtime exceeds the player-impact budget
 }
 
 // OptimizationSuggestion represents a cost-saving opportunity
 type OptimizationSuggestion struct {
-	ServerID         string    `json:"server_id"`
-	ServerName       string    `json:"server_name"`
-	CurrentNodeID    string    `json:"current_node_id"`
-	CurrentCost      float64   `json:"current_cost_eur_hour"`
-	TargetNodeID     string    `json:"target_node_id"`
-	TargetCost       float64   `json:"target_cost_eur_hour"`
-	SavingsPerHour   float64   `json:"savings_eur_hour"`
-	SavingsPerMonth  float64   `json:"savings_eur_month"`
-	Reason           string    `json:"reason"`
-	CreatedAt        time.Time `json:"created_at"`
-	Applied          bool      `json:"applied"`
+	ServerID         string                 `json:"server_id"`
+	ServerName       string                 `json:"server_name"`
+	CurrentNodeID    string                 `json:"current_node_id"`
+	CurrentCost      float64                `json:"current_cost_eur_hour"`
+	TargetNodeID     string                 `json:"target_node_id"`
+	TargetCost       float64                `json:"target_cost_eur_hour"`
+	SavingsPerHour   float64                `json:"savings_eur_hour"`
+	SavingsPerMonth  float64                `json:"savings_eur_month"`
+	Reason           string                 `json:"reason"`
+	CreatedAt        time.Time              `json:"created_at"`
+	Applied          bool                   `json:"applied"`
+	ExpectedDowntime float64                `json:"expected_downtime_player_seconds,omitempty"` // Historical p90 downtime x players online
+	Trigger          models.MigrationReason `json:"trigger"`                                    // cost-optimization or disk-pressure
 }
 
 // NewCostOptimizationService creates a new cost optimization service
@@ -65,6 +71,16 @@ func NewCostOptimizationService(
 	}
 }
 
+// SetResidencyService sets the service that restricts migration targets to the organization's residency country
+func (s *CostOptimizationService) SetResidencyService(residency *ResidencyService) {
+	s.residency = residency
+}
+
+// SetMigrationAnalytics sets the service that tunes suggestions from historical migration downtime
+func (s *CostOptimizationService) SetMigrationAnalytics(analytics *MigrationAnalyticsService) {
+	s.analytics = analytics
+}
+
 // SetConductor sets the conductor instance
 func (s *CostOptimizationService) SetConductor(cond *conductor.Conductor) {
 	s.conductor = cond
@@ -168,7 +184,8 @@ func (s *CostOptimizationService) analyzeAndOptimize() {
 		nodeMap[node.ID] = node
 	}
 
-	suggestions := s.analyzeCostOpportunities(servers, nodeMap)
+	suggestions := s.analyzeDiskPressure(servers, nodeMap)
+	suggestions = append(suggestions, s.analyzeCostOpportunities(servers, nodeMap, suggestions)...)
 
 	// Store suggestions for API access
 	s.suggestionsMu.Lock()
@@ -193,16 +210,102 @@ func (s *CostOptimizationService) analyzeAndOptimize() {
 	s.processSuggestions(suggestions, servers)
 }
 
+// analyzeDiskPressure moves one server off every node with less free disk than NODE_MIN_FREE_DISK_MB
+// The server with the most data goes first, to the cheapest node that keeps enough free disk after taking it.
+func (s *CostOptimizationService) analyzeDiskPressure(
+	servers []models.MinecraftServer,
+	nodeMap map[string]conductor.CostNodeInfo,
+) []OptimizationSuggestion {
+	suggestions := []OptimizationSuggestion{}
+
+	sorted := make([]models.MinecraftServer, len(servers))
+	copy(sorted, servers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DiskUsageBytes > sorted[j].DiskUsageBytes
+	})
+
+	relieved := make(map[string]bool)
+	for _, server := range sorted {
+		currentNode, exists := nodeMap[server.NodeID]
+		if !exists || !currentNode.DiskPressure || relieved[currentNode.ID] {
+			continue
+		}
+		// Skip if cost optimization disabled or placement is guaranteed
+		if server.CostOptimizationLevel == 0 || server.Plan == "reserved" {
+			continue
+		}
+
+		diskMB := int(server.DiskUsageBytes >> 20)
+		var target *conductor.CostNodeInfo
+		var expectedDowntime float64
+		for _, candidate := range nodeMap {
+			if candidate.ID == server.NodeID || !candidate.IsHealthy || candidate.DiskPressure {
+				continue
+			}
+			if target != nil && candidate.CostPerHour >= target.CostPerHour {
+				continue
+			}
+			if !s.conductor.CanFitServerOnNode(candidate.ID, server.RAMMb) || !s.conductor.NodeHasDiskRoom(candidate.ID, diskMB) {
+				continue
+			}
+			if !s.conductor.NodeAcceptsPlacement(candidate.ID, withPlacementPeers(s.serverRepo, &server, s.residency.Placement(&server, s.conductor.PlacementFor(server.OwnerID, server.Placement())))) {
+				continue
+			}
+			downtime, withinBudget := s.analytics.AllowsMigration(&server, server.NodeID, candidate.ID)
+			if !withinBudget {
+				continue
+			}
+			target = &candidate
+			expectedDowntime = downtime
+		}
+		if target == nil {
+			logger.Warn("Disk pressure: no node has room for the server", map[string]interface{}{
+				"server_id":    server.ID,
+				"node_id":      server.NodeID,
+				"disk_free_mb": currentNode.DiskFreeMB,
+				"server_mb":    diskMB,
+			})
+			continue
+		}
+
+		savings := currentNode.CostPerHour - target.CostPerHour
+		suggestions = append(suggestions, OptimizationSuggestion{
+			ServerID:         server.ID,
+			ServerName:       server.Name,
+			CurrentNodeID:    server.NodeID,
+			CurrentCost:      currentNode.CostPerHour,
+			TargetNodeID:     target.ID,
+			TargetCost:       target.CostPerHour,
+			SavingsPerHour:   savings,
+			SavingsPerMonth:  savings * 730,
+			Reason:           fmt.Sprintf("Disk pressure on %s (%d MB free), move %d MB of server data to %s", currentNode.ID, currentNode.DiskFreeMB, diskMB, target.ID),
+			CreatedAt:        time.Now(),
+			ExpectedDowntime: expectedDowntime,
+			Trigger:          models.MigrationReasonDiskPressure,
+		})
+		relieved[currentNode.ID] = true
+	}
+
+	return suggestions
+}
+
 // analyzeCostOpportunities finds servers that could be moved to cheaper nodes
+// Servers that already have a suggestion (disk pressure) are skipped.
 func (s *CostOptimizationService) analyzeCostOpportunities(
 	servers []models.MinecraftServer,
 	nodeMap map[string]conductor.CostNodeInfo,
+	existing []OptimizationSuggestion,
 ) []OptimizationSuggestion {
 	suggestions := []OptimizationSuggestion{}
 
+	suggested := make(map[string]bool, len(existing))
+	for _, suggestion := range existing {
+		suggested[suggestion.ServerID] = true
+	}
+
 	for _, server := range servers {
 		// Skip if cost optimization disabled
-		if server.CostOptimizationLevel == 0 {
+		if server.CostOptimizationLevel == 0 || suggested[server.ID] {
 			continue
 		}
 
@@ -241,6 +344,11 @@ func (s *CostOptimizationService) analyzeCostOpportunities(
 				continue
 			}
 
+			// Skip nodes the server's labels/taints constraints exclude (dedicated nodes, specialized workloads)
+			if !s.conductor.NodeAcceptsPlacement(targetNode.ID, withPlacementPeers(s.serverRepo, &server, s.residency.Placement(&server, s.conductor.PlacementFor(server.OwnerID, server.Placement())))) {
+				continue
+			}
+
 			targetCost := targetNode.CostPerHour
 			savings := currentCost - targetCost
 
@@ -249,6 +357,17 @@ func (s *CostOptimizationService) analyzeCostOpportunities(
 				continue
 			}
 
+			// Skip node pairs whose historical downtime would exceed the player-impact budget
+			expectedDowntime, withinBudget := s.analytics.AllowsMigration(&server, server.NodeID, targetNode.ID)
+			if !withinBudget {
+				logger.Debug("Cost optimization skipped target: historical downtime exceeds player-impact budget", map[string]interface{}{
+					"server_id":         server.ID,
+					"target_node":       targetNode.ID,
+					"expected_downtime": expectedDowntime,
+				})
+				continue
+			}
+
 			// Found a cost-saving opportunity
 			suggestion := OptimizationSuggestion{
 				ServerID:        server.ID,
@@ -262,6 +381,8 @@ func (s *CostOptimizationService) analyzeCostOpportunities(
 				Reason:          fmt.Sprintf("Move from %s (€%.4f/h) to %s (€%.4f/h)", currentNode.Type, currentCost, targetNode.Type, targetCost),
 				CreatedAt:       time.Now(),
 				Applied:         false,
+				ExpectedDowntime: expectedDowntime,
+				Trigger:         models.MigrationReasonCostOptimization,
 			}
 
 			suggestions = append(suggestions, suggestion)
@@ -376,7 +497,7 @@ func (s *CostOptimizationService) logSuggestion(suggestion OptimizationSuggestio
 		ToNodeID:        suggestion.TargetNodeID,
 		ToNodeName:      toNodeName,
 		Status:          models.MigrationStatusSuggested,
-		Reason:          models.MigrationReasonCostOptimization,
+		Reason:          suggestion.Trigger,
 		SavingsEURHour:  suggestion.SavingsPerHour,
 		SavingsEURMonth: suggestion.SavingsPerMonth,
 		CreatedAt:       time.Now(),
@@ -437,7 +558,7 @@ func (s *CostOptimizationService) performAutoMigration(
 		ToNodeID:        suggestion.TargetNodeID,
 		ToNodeName:      toNodeName,
 		Status:          models.MigrationStatusScheduled,
-		Reason:          models.MigrationReasonCostOptimization,
+		Reason:          suggestion.Trigger,
 		SavingsEURHour:  suggestion.SavingsPerHour,
 		SavingsEURMonth: suggestion.SavingsPerMonth,
 		CreatedAt:       now,
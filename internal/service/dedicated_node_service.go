@@ -0,0 +1,278 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// dedicatedSessionLength is how long a dedicated node billing session runs before it is rolled over
+// Closed sessions are what Stripe reporting and wallet settlement pick up, so rentals are split daily.
+const dedicatedSessionLength = 24 * time.Hour
+
+// DedicatedNodeService reserves whole worker nodes for single customers
+// The customer pays the node's hourly price as a usage session; their servers are pinned to the node
+// (Conductor.PlacementFor) and run there without per-server charges. Scaling, consolidation and cost
+// optimization leave dedicated nodes alone until they are released.
+type DedicatedNodeService struct {
+	db         *gorm.DB
+	conductor  *conductor.Conductor
+	userRepo   *repository.UserRepository
+	serverRepo *repository.ServerRepository
+
+	mu       sync.Mutex // Serializes assignment, release and session rollover
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewDedicatedNodeService creates a new dedicated node service
+func NewDedicatedNodeService(db *gorm.DB, cond *conductor.Conductor, userRepo *repository.UserRepository, serverRepo *repository.ServerRepository) *DedicatedNodeService {
+	return &DedicatedNodeService{
+		db:         db,
+		conductor:  cond,
+		userRepo:   userRepo,
+		serverRepo: serverRepo,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start begins the hourly billing session maintenance
+func (s *DedicatedNodeService) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		s.maintainSessions()
+		for {
+			select {
+			case <-ticker.C:
+				s.maintainSessions()
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	logger.Info("Dedicated node service started", nil)
+}
+
+// Stop stops the session maintenance
+func (s *DedicatedNodeService) Stop() {
+	close(s.stopChan)
+	s.wg.Wait()
+	logger.Info("Dedicated node service stopped", nil)
+}
+
+// AssignNode reserves a node for ownerID and starts billing it
+// hourlyPriceEUR 0 bills the node's hourly cost. The node must not run servers of other customers.
+func (s *DedicatedNodeService) AssignNode(nodeID, ownerID string, hourlyPriceEUR float64) (*models.DedicatedNodeInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, exists := s.conductor.NodeRegistry.GetNode(nodeID)
+	if !exists {
+		return nil, models.ErrNodeNotFound
+	}
+	if node.IsSystemNode {
+		return nil, models.ErrNodeNotDedicatable
+	}
+	if node.IsCustomerDedicated() {
+		return nil, models.ErrNodeAlreadyDedicated
+	}
+	if _, err := s.userRepo.FindByID(ownerID); err != nil {
+		return nil, fmt.Errorf("customer not found: %w", err)
+	}
+
+	if hourlyPriceEUR <= 0 {
+		hourlyPriceEUR = node.HourlyCostEUR
+	}
+	if hourlyPriceEUR <= 0 || node.TotalRAMMB <= 0 {
+		return nil, models.ErrInvalidDedicatedNodePrice
+	}
+
+	for _, container := range s.conductor.ContainerRegistry.GetContainersByNode(nodeID) {
+		server, err := s.serverRepo.FindByID(container.ServerID)
+		if err != nil || server.OwnerID != ownerID {
+			return nil, models.ErrNodeHostsOtherCustomers
+		}
+	}
+
+	if err := s.conductor.NodeRegistry.SetNodeDedication(nodeID, ownerID, hourlyPriceEUR); err != nil {
+		return nil, err
+	}
+	if err := s.openSession(node, time.Now()); err != nil {
+		// Don't leave an unbilled reservation behind
+		_ = s.conductor.NodeRegistry.SetNodeDedication(nodeID, "", 0)
+		return nil, err
+	}
+
+	logger.Info("DEDICATED-NODE: Node assigned to customer", map[string]interface{}{
+		"node_id":          nodeID,
+		"owner_id":         ownerID,
+		"hourly_price_eur": hourlyPriceEUR,
+	})
+	return dedicatedNodeInfo(node), nil
+}
+
+// ReleaseNode ends a customer's reservation and billing of a node
+// Servers still running there keep running; new starts are placed on shared nodes again.
+func (s *DedicatedNodeService) ReleaseNode(nodeID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, exists := s.conductor.NodeRegistry.GetNode(nodeID)
+	if !exists {
+		return models.ErrNodeNotFound
+	}
+	if !node.IsCustomerDedicated() {
+		return models.ErrNodeNotDedicated
+	}
+	ownerID := node.DedicatedOwnerID
+
+	if err := s.closeOpenSessions(nodeID, time.Now()); err != nil {
+		return err
+	}
+	if err := s.conductor.NodeRegistry.SetNodeDedication(nodeID, "", 0); err != nil {
+		return err
+	}
+
+	logger.Info("DEDICATED-NODE: Node released", map[string]interface{}{
+		"node_id":  nodeID,
+		"owner_id": ownerID,
+	})
+	return nil
+}
+
+// ListNodes returns the dedicated nodes of ownerID ("" = all customers)
+func (s *DedicatedNodeService) ListNodes(ownerID string) []models.DedicatedNodeInfo {
+	nodes := s.conductor.NodeRegistry.GetDedicatedNodes(ownerID)
+	infos := make([]models.DedicatedNodeInfo, 0, len(nodes))
+	for _, node := range nodes {
+		infos = append(infos, *dedicatedNodeInfo(node))
+	}
+	return infos
+}
+
+// maintainSessions rolls over day-old billing sessions and reopens missing ones (e.g. after a crash)
+func (s *DedicatedNodeService) maintainSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, node := range s.conductor.NodeRegistry.GetDedicatedNodes("") {
+		var session models.UsageSession
+		err := s.db.Where("dedicated_node_id = ? AND stopped_at IS NULL", node.ID).
+			Order("started_at DESC").
+			First(&session).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := s.openSession(node, now); err != nil {
+				logger.Error("DEDICATED-NODE: Failed to open billing session", err, map[string]interface{}{
+					"node_id": node.ID,
+				})
+			}
+			continue
+		}
+		if err != nil {
+			logger.Error("DEDICATED-NODE: Failed to load billing session", err, map[string]interface{}{
+				"node_id": node.ID,
+			})
+			continue
+		}
+
+		// Roll over in whole session lengths so no time is lost between sessions
+		for now.Sub(session.StartedAt) >= dedicatedSessionLength {
+			end := session.StartedAt.Add(dedicatedSessionLength)
+			if err := s.closeSession(&session, end); err != nil {
+				logger.Error("DEDICATED-NODE: Failed to close billing session", err, map[string]interface{}{
+					"node_id":    node.ID,
+					"session_id": session.ID,
+				})
+				break
+			}
+			next, err := s.newSession(node, end)
+			if err != nil {
+				logger.Error("DEDICATED-NODE: Failed to open billing session", err, map[string]interface{}{
+					"node_id": node.ID,
+				})
+				break
+			}
+			session = *next
+		}
+	}
+}
+
+// openSession starts billing a dedicated node at startedAt
+func (s *DedicatedNodeService) openSession(node *conductor.Node, startedAt time.Time) error {
+	_, err := s.newSession(node, startedAt)
+	return err
+}
+
+// newSession creates the usage session of a dedicated node
+// The per-GB rate is chosen so that the node's RAM costs exactly its hourly price.
+func (s *DedicatedNodeService) newSession(node *conductor.Node, startedAt time.Time) (*models.UsageSession, error) {
+	session := &models.UsageSession{
+		ID:              uuid.New().String(),
+		ServerID:        node.ID,
+		ServerName:      "Dedicated node " + node.Hostname,
+		OwnerID:         node.DedicatedOwnerID,
+		StartedAt:       startedAt,
+		RAMMb:           node.TotalRAMMB,
+		HourlyRateEUR:   node.DedicatedPriceEUR / (float64(node.TotalRAMMB) / 1024.0),
+		DedicatedNodeID: node.ID,
+	}
+	if err := s.db.Create(session).Error; err != nil {
+		return nil, fmt.Errorf("failed to create dedicated node session: %w", err)
+	}
+	return session, nil
+}
+
+// closeOpenSessions closes all running sessions of a node at stoppedAt
+func (s *DedicatedNodeService) closeOpenSessions(nodeID string, stoppedAt time.Time) error {
+	var sessions []models.UsageSession
+	if err := s.db.Where("dedicated_node_id = ? AND stopped_at IS NULL", nodeID).Find(&sessions).Error; err != nil {
+		return fmt.Errorf("failed to load dedicated node sessions: %w", err)
+	}
+	for i := range sessions {
+		if err := s.closeSession(&sessions[i], stoppedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeSession ends a session and calculates its cost
+func (s *DedicatedNodeService) closeSession(session *models.UsageSession, stoppedAt time.Time) error {
+	duration := stoppedAt.Sub(session.StartedAt)
+	session.StoppedAt = &stoppedAt
+	session.DurationSeconds = int(duration.Seconds())
+	session.CostEUR = models.UsageCostEUR(session.RAMMb, session.HourlyRateEUR, duration)
+
+	if err := s.db.Save(session).Error; err != nil {
+		return fmt.Errorf("failed to close dedicated node session: %w", err)
+	}
+	return nil
+}
+
+// dedicatedNodeInfo converts a conductor node to its public view
+func dedicatedNodeInfo(node *conductor.Node) *models.DedicatedNodeInfo {
+	return &models.DedicatedNodeInfo{
+		NodeID:         node.ID,
+		Hostname:       node.Hostname,
+		OwnerID:        node.DedicatedOwnerID,
+		HourlyPriceEUR: node.DedicatedPriceEUR,
+		TotalRAMMB:     node.TotalRAMMB,
+		AllocatedRAMMB: node.AllocatedRAMMB,
+		ContainerCount: node.ContainerCount,
+		Healthy:        node.IsHealthy(),
+	}
+}
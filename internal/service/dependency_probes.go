@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/cloud"
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/velocity"
+)
+
+// DependencyStatus is the outcome of one dependency check
+type DependencyStatus string
+
+const (
+	DependencyUp       DependencyStatus = "up"
+	DependencyDown     DependencyStatus = "down"
+	DependencyDegraded DependencyStatus = "degraded" // Reachable but not fully working, doesn't make the instance unready
+	DependencyDisabled DependencyStatus = "disabled" // Not configured in this setup
+)
+
+// DependencyCheck is the result of checking one dependency
+type DependencyCheck struct {
+	Name      string           `json:"name"`
+	Status    DependencyStatus `json:"status"`
+	Required  bool             `json:"required"` // The instance is unready while a required dependency is down
+	LatencyMs int64            `json:"latency_ms"`
+	Error     string           `json:"error,omitempty"`  // Why the dependency is down or degraded
+	Reason    string           `json:"reason,omitempty"` // Why the check is disabled
+	Details   interface{}      `json:"details,omitempty"`
+}
+
+// degradedError marks a check result as degraded instead of down
+type degradedError struct {
+	reason string
+}
+
+func (e *degradedError) Error() string {
+	return e.reason
+}
+
+// dependencyProbe checks one dependency (readiness probe and self-diagnostics)
+type dependencyProbe struct {
+	name     string
+	required bool
+	disabled string // Reason the dependency isn't configured, "" = enabled
+	run      func(ctx context.Context) (interface{}, error)
+}
+
+// runProbes runs the probes concurrently; results are in the order of the probes
+func runProbes(ctx context.Context, probes []dependencyProbe, timeout time.Duration) []DependencyCheck {
+	results := make([]DependencyCheck, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runProbe(ctx, probe, timeout)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// runProbe runs one probe with the timeout; probes without a context keep running in the
+// background after a timeout and are bounded by their client's own timeout
+func runProbe(ctx context.Context, check dependencyProbe, timeout time.Duration) DependencyCheck {
+	result := DependencyCheck{Name: check.name, Required: check.required}
+	if check.disabled != "" {
+		result.Status, result.Reason = DependencyDisabled, check.disabled
+		return result
+	}
+
+	// A caller that hangs up mustn't turn a shared (cached) result into failures
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	type outcome struct {
+		details interface{}
+		err     error
+	}
+	done := make(chan outcome, 1)
+	start := time.Now()
+	go func() {
+		details, err := check.run(ctx)
+		done <- outcome{details, err}
+	}()
+
+	var out outcome
+	select {
+	case out = <-done:
+	case <-ctx.Done():
+		out.err = fmt.Errorf("no response within %s", timeout)
+	}
+	result.LatencyMs = time.Since(start).Milliseconds()
+	result.Details = out.details
+
+	var degraded *degradedError
+	switch {
+	case out.err == nil:
+		result.Status = DependencyUp
+	case errors.As(out.err, &degraded):
+		result.Status, result.Error = DependencyDegraded, degraded.reason
+	default:
+		result.Status, result.Error = DependencyDown, out.err.Error()
+	}
+	return result
+}
+
+// databaseProbe pings the database and reports the connection pool
+func databaseProbe(dbProvider repository.DatabaseProvider) dependencyProbe {
+	return dependencyProbe{
+		name: "database",
+		run: func(ctx context.Context) (interface{}, error) {
+			if err := dbProvider.Ping(); err != nil {
+				return nil, err
+			}
+			db := dbProvider.GetDB()
+			details := map[string]interface{}{"driver": db.Dialector.Name()}
+			if sqlDB, err := db.DB(); err == nil {
+				stats := sqlDB.Stats()
+				details["open_connections"] = stats.OpenConnections
+				details["in_use"] = stats.InUse
+				details["wait_count"] = stats.WaitCount
+			}
+			return details, nil
+		},
+	}
+}
+
+// dockerProbe pings the local Docker daemon through its socket
+func dockerProbe(dockerService *docker.DockerService) dependencyProbe {
+	return dependencyProbe{
+		name: "docker",
+		run: func(ctx context.Context) (interface{}, error) {
+			return nil, dockerService.Ping(ctx)
+		},
+	}
+}
+
+// velocityProbe calls the health endpoint of the Velocity Remote API; velocityClient may be nil
+func velocityProbe(velocityClient *velocity.RemoteVelocityClient) dependencyProbe {
+	probe := dependencyProbe{
+		name: "velocity",
+		run: func(ctx context.Context) (interface{}, error) {
+			health, err := velocityClient.HealthCheck()
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"version":        health.Version,
+				"servers_count":  health.ServersCount,
+				"players_online": health.PlayersOnline,
+			}, nil
+		},
+	}
+	if velocityClient == nil {
+		probe.disabled = "no Velocity API configured"
+	}
+	return probe
+}
+
+// cloudProviderProbe lists the server types of the cloud provider API (read-only, no costs);
+// cloudProvider may be nil
+func cloudProviderProbe(cloudProvider cloud.CloudProvider) dependencyProbe {
+	probe := dependencyProbe{
+		name: "cloud_provider",
+		run: func(ctx context.Context) (interface{}, error) {
+			types, err := cloudProvider.GetServerTypes()
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"server_types": len(types)}, nil
+		},
+	}
+	if cloudProvider == nil {
+		probe.disabled = "no cloud provider configured"
+	}
+	return probe
+}
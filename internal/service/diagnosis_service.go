@@ -0,0 +1,621 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/monitoring"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/velocity"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	diagnosisTimeout      = 45 * time.Second
+	diagnosisLogTail      = "300"
+	diagnosisProbeTimeout = 5 * time.Second
+	proxyNodeID           = "proxy-node"
+	remoteServersPath     = "/minecraft/servers" // Server data on worker nodes (see docker.BuildVolumeBinds)
+)
+
+// DiagnosisSeverity ranks the findings of a diagnosis
+type DiagnosisSeverity string
+
+const (
+	DiagnosisCritical DiagnosisSeverity = "critical" // Prevents the server from starting or being joined
+	DiagnosisWarning  DiagnosisSeverity = "warning"  // Likely causes problems soon
+	DiagnosisInfo     DiagnosisSeverity = "info"     // Check could not run or is not applicable
+	DiagnosisOK       DiagnosisSeverity = "ok"       // Check passed
+)
+
+var diagnosisSeverityRank = map[DiagnosisSeverity]int{
+	DiagnosisCritical: 0,
+	DiagnosisWarning:  1,
+	DiagnosisInfo:     2,
+	DiagnosisOK:       3,
+}
+
+// DiagnosisFinding is the result of one check
+type DiagnosisFinding struct {
+	Check        string            `json:"check"` // container, oom, disk, config, slp, velocity, proxy_port
+	Severity     DiagnosisSeverity `json:"severity"`
+	Title        string            `json:"title"`
+	Detail       string            `json:"detail,omitempty"`
+	SuggestedFix string            `json:"suggested_fix,omitempty"`
+}
+
+// DiagnosisReport is the ranked result of a server diagnosis (most severe first)
+type DiagnosisReport struct {
+	ServerID   string             `json:"server_id"`
+	Status     string             `json:"status"`
+	Healthy    bool               `json:"healthy"` // No critical or warning findings
+	Findings   []DiagnosisFinding `json:"findings"`
+	CheckedAt  time.Time          `json:"checked_at"`
+	DurationMs int64              `json:"duration_ms"`
+}
+
+// Log lines that point at a specific root cause
+var (
+	oomLogPatterns    = []string{"java.lang.OutOfMemoryError", "GC overhead limit exceeded"}
+	diskLogPatterns   = []string{"No space left on device"}
+	configLogPatterns = []string{
+		"InvalidConfigurationException", "Failed to load properties", "Could not load 'plugins/",
+		"YAMLException", "while parsing a block mapping", "Failed to parse server.properties",
+		"Error loading config", "You need to agree to the EULA",
+	}
+)
+
+// server.properties keys that must hold a number or a boolean
+var (
+	numericProperties = []string{"server-port", "max-players", "view-distance", "simulation-distance", "spawn-protection", "rcon.port", "query.port", "max-world-size"}
+	booleanProperties = []string{"online-mode", "pvp", "white-list", "enable-rcon", "enable-query", "allow-flight", "hardcore", "enforce-whitelist", "enable-command-block"}
+)
+
+// DiagnosisService runs self-service checks for servers that won't start or can't be joined
+type DiagnosisService struct {
+	serverRepo     *repository.ServerRepository
+	dockerService  *docker.DockerService
+	conductor      ConductorInterface
+	velocityClient *velocity.RemoteVelocityClient // Optional
+	cfg            *config.Config
+}
+
+// NewDiagnosisService creates a new diagnosis service
+func NewDiagnosisService(serverRepo *repository.ServerRepository, dockerService *docker.DockerService, cfg *config.Config) *DiagnosisService {
+	return &DiagnosisService{
+		serverRepo:    serverRepo,
+		dockerService: dockerService,
+		cfg:           cfg,
+	}
+}
+
+// SetConductor sets the conductor used to reach the server's node and the proxy node
+func (s *DiagnosisService) SetConductor(conductor ConductorInterface) {
+	s.conductor = conductor
+}
+
+// SetVelocityClient sets the Velocity API client for the registration check
+func (s *DiagnosisService) SetVelocityClient(client *velocity.RemoteVelocityClient) {
+	s.velocityClient = client
+}
+
+// diagnosisTarget is what the checks need to know about the server's placement
+type diagnosisTarget struct {
+	server     *models.MinecraftServer
+	remoteNode *docker.RemoteNode // nil for the local node
+	host       string             // Address the proxy connects to
+	logs       string             // Recent container logs
+}
+
+// Diagnose runs all checks for a server and returns the ranked findings (within diagnosisTimeout, less if ctx ends first)
+func (s *DiagnosisService) Diagnose(ctx context.Context, serverID string) (*DiagnosisReport, error) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, models.ErrServerNotFound
+	}
+
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, diagnosisTimeout)
+	defer cancel()
+
+	report := &DiagnosisReport{
+		ServerID:  server.ID,
+		Status:    string(server.Status),
+		CheckedAt: started,
+	}
+	add := func(finding DiagnosisFinding) {
+		report.Findings = append(report.Findings, finding)
+	}
+
+	target, err := s.resolveTarget(server)
+	if err != nil {
+		add(DiagnosisFinding{
+			Check:        "container",
+			Severity:     DiagnosisCritical,
+			Title:        "Server's node is not reachable",
+			Detail:       err.Error(),
+			SuggestedFix: "Stop and start the server to place it on a healthy node.",
+		})
+	} else {
+		running := s.checkContainer(ctx, target, add)
+		s.checkOOM(ctx, target, add)
+		s.checkDisk(ctx, target, add)
+		s.checkConfig(ctx, target, add)
+		if running {
+			s.checkServerListPing(target, add)
+			s.checkVelocity(target, add)
+			s.checkProxyPort(ctx, target, add)
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return diagnosisSeverityRank[report.Findings[i].Severity] < diagnosisSeverityRank[report.Findings[j].Severity]
+	})
+	report.Healthy = true
+	for _, finding := range report.Findings {
+		if finding.Severity == DiagnosisCritical || finding.Severity == DiagnosisWarning {
+			report.Healthy = false
+		}
+	}
+	report.DurationMs = time.Since(started).Milliseconds()
+
+	logger.Info("DIAGNOSIS: Server diagnosed", map[string]interface{}{
+		"server_id":   server.ID,
+		"healthy":     report.Healthy,
+		"findings":    len(report.Findings),
+		"duration_ms": report.DurationMs,
+	})
+	return report, nil
+}
+
+// resolveTarget looks up the node the server is placed on
+func (s *DiagnosisService) resolveTarget(server *models.MinecraftServer) (*diagnosisTarget, error) {
+	target := &diagnosisTarget{server: server, host: s.cfg.ControlPlaneIP}
+	if server.NodeID == "" || server.NodeID == "local-node" {
+		return target, nil
+	}
+	if s.conductor == nil {
+		return nil, fmt.Errorf("conductor not available for remote node %s", server.NodeID)
+	}
+	remoteNode, err := s.conductor.GetRemoteNode(server.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("node %s: %w", server.NodeID, err)
+	}
+	target.remoteNode = remoteNode
+	target.host = remoteNode.IPAddress
+	return target, nil
+}
+
+// checkContainer compares the container state with the server status and loads the recent logs
+// Returns true if the container is running.
+func (s *DiagnosisService) checkContainer(ctx context.Context, target *diagnosisTarget, add func(DiagnosisFinding)) bool {
+	server := target.server
+	shouldRun := server.Status == models.StatusRunning || server.Status == models.StatusStarting
+
+	if server.ContainerID == "" {
+		if shouldRun {
+			add(DiagnosisFinding{
+				Check:        "container",
+				Severity:     DiagnosisCritical,
+				Title:        "Server is marked " + string(server.Status) + " but has no container",
+				SuggestedFix: "Stop and start the server to recreate its container.",
+			})
+		} else {
+			add(DiagnosisFinding{
+				Check:        "container",
+				Severity:     DiagnosisInfo,
+				Title:        "Server has no container",
+				Detail:       "The container is created on the next start (status: " + string(server.Status) + ").",
+				SuggestedFix: "Start the server.",
+			})
+		}
+		return false
+	}
+
+	status, err := s.containerStatus(ctx, target)
+	if err != nil {
+		severity := DiagnosisInfo
+		if shouldRun {
+			severity = DiagnosisCritical
+		}
+		add(DiagnosisFinding{
+			Check:        "container",
+			Severity:     severity,
+			Title:        "Container not found",
+			Detail:       err.Error(),
+			SuggestedFix: "Stop and start the server to recreate its container.",
+		})
+		return false
+	}
+
+	if logs, err := s.containerLogs(ctx, target); err == nil {
+		target.logs = logs
+	}
+
+	switch {
+	case status == "running":
+		add(DiagnosisFinding{Check: "container", Severity: DiagnosisOK, Title: "Container is running"})
+		return true
+	case shouldRun:
+		add(DiagnosisFinding{
+			Check:        "container",
+			Severity:     DiagnosisCritical,
+			Title:        "Container is " + status + " although the server is " + string(server.Status),
+			Detail:       lastLogLines(target.logs, 5),
+			SuggestedFix: "Check the other findings for the cause, then restart the server.",
+		})
+	default:
+		add(DiagnosisFinding{
+			Check:    "container",
+			Severity: DiagnosisOK,
+			Title:    "Container is " + status + " (server is " + string(server.Status) + ")",
+		})
+	}
+	return false
+}
+
+// checkOOM looks for out-of-memory kills of the container and JVM heap exhaustion in the logs
+func (s *DiagnosisService) checkOOM(ctx context.Context, target *diagnosisTarget, add func(DiagnosisFinding)) {
+	fix := fmt.Sprintf("Upgrade the server's RAM (currently %d MB), remove memory-hungry plugins or lower view-distance.", target.server.RAMMb)
+
+	if target.server.ContainerID != "" {
+		oomKilled, exitCode, err := s.containerExitState(ctx, target)
+		if err == nil && oomKilled {
+			add(DiagnosisFinding{
+				Check:        "oom",
+				Severity:     DiagnosisCritical,
+				Title:        "Container was killed for running out of memory",
+				Detail:       fmt.Sprintf("The kernel OOM killer stopped the container (exit code %d).", exitCode),
+				SuggestedFix: fix,
+			})
+			return
+		}
+		if err == nil && exitCode == 137 {
+			add(DiagnosisFinding{
+				Check:        "oom",
+				Severity:     DiagnosisWarning,
+				Title:        "Container was killed (exit code 137)",
+				Detail:       "The process received SIGKILL, usually because it exceeded its memory limit or did not stop in time.",
+				SuggestedFix: fix,
+			})
+			return
+		}
+	}
+
+	if line := findLogLine(target.logs, oomLogPatterns); line != "" {
+		add(DiagnosisFinding{
+			Check:        "oom",
+			Severity:     DiagnosisCritical,
+			Title:        "Java ran out of heap memory",
+			Detail:       line,
+			SuggestedFix: fix,
+		})
+		return
+	}
+
+	add(DiagnosisFinding{Check: "oom", Severity: DiagnosisOK, Title: "No recent out-of-memory kills"})
+}
+
+// checkDisk checks the free space of the filesystem holding the server data
+func (s *DiagnosisService) checkDisk(ctx context.Context, target *diagnosisTarget, add func(DiagnosisFinding)) {
+	fix := "Delete old worlds, backups or logs in the file manager; contact support if the node is full."
+
+	if line := findLogLine(target.logs, diskLogPatterns); line != "" {
+		add(DiagnosisFinding{Check: "disk", Severity: DiagnosisCritical, Title: "Server could not write to disk", Detail: line, SuggestedFix: fix})
+		return
+	}
+
+	usedPercent, availableBytes, err := s.diskUsage(ctx, target)
+	if err != nil {
+		add(DiagnosisFinding{Check: "disk", Severity: DiagnosisInfo, Title: "Disk usage could not be checked", Detail: err.Error()})
+		return
+	}
+
+	detail := fmt.Sprintf("%.0f%% used, %d MB available", usedPercent, availableBytes/(1024*1024))
+	switch {
+	case usedPercent >= 98 || availableBytes < 512*1024*1024:
+		add(DiagnosisFinding{Check: "disk", Severity: DiagnosisCritical, Title: "Disk is full", Detail: detail, SuggestedFix: fix})
+	case usedPercent >= 90:
+		add(DiagnosisFinding{Check: "disk", Severity: DiagnosisWarning, Title: "Disk is almost full", Detail: detail, SuggestedFix: fix})
+	default:
+		add(DiagnosisFinding{Check: "disk", Severity: DiagnosisOK, Title: "Enough disk space", Detail: detail})
+	}
+}
+
+// checkConfig validates server.properties and looks for config load errors in the logs
+func (s *DiagnosisService) checkConfig(ctx context.Context, target *diagnosisTarget, add func(DiagnosisFinding)) {
+	found := false
+
+	if properties, err := s.readServerFile(ctx, target, "server.properties"); err == nil {
+		for _, problem := range validateServerProperties(properties) {
+			found = true
+			add(DiagnosisFinding{
+				Check:        "config",
+				Severity:     DiagnosisCritical,
+				Title:        "Invalid server.properties",
+				Detail:       problem,
+				SuggestedFix: "Fix the value in server.properties with the file manager or reset it in the server settings.",
+			})
+		}
+	}
+
+	if line := findLogLine(target.logs, configLogPatterns); line != "" {
+		found = true
+		fix := "Fix or remove the broken config file named in the log line (plugin configs are in plugins/<name>/config.yml)."
+		if strings.Contains(line, "EULA") {
+			fix = "Restart the server; the platform accepts the EULA on start."
+		}
+		add(DiagnosisFinding{
+			Check:        "config",
+			Severity:     DiagnosisCritical,
+			Title:        "Server failed to load its configuration",
+			Detail:       line,
+			SuggestedFix: fix,
+		})
+	}
+
+	if !found {
+		add(DiagnosisFinding{Check: "config", Severity: DiagnosisOK, Title: "No config errors found"})
+	}
+}
+
+// checkServerListPing checks that the Minecraft server answers status requests on its port
+func (s *DiagnosisService) checkServerListPing(target *diagnosisTarget, add func(DiagnosisFinding)) {
+	address := net.JoinHostPort(target.host, strconv.Itoa(target.server.Port))
+	status, err := pingServerList(address, diagnosisProbeTimeout)
+	if err != nil {
+		severity := DiagnosisCritical
+		fix := "Check the console for errors; if the server is still starting, wait and run the diagnosis again."
+		if target.server.Status == models.StatusStarting {
+			severity = DiagnosisWarning
+		}
+		add(DiagnosisFinding{
+			Check:        "slp",
+			Severity:     severity,
+			Title:        "Server does not answer status pings",
+			Detail:       fmt.Sprintf("%s: %v", address, err),
+			SuggestedFix: fix,
+		})
+		return
+	}
+
+	add(DiagnosisFinding{
+		Check:    "slp",
+		Severity: DiagnosisOK,
+		Title:    "Server answers status pings",
+		Detail: fmt.Sprintf("%s, %d/%d players, %d ms", status.Version.Name, status.Players.Online, status.Players.Max,
+			status.Latency.Milliseconds()),
+	})
+}
+
+// checkVelocity checks that the proxy routes the server's name to its current address
+func (s *DiagnosisService) checkVelocity(target *diagnosisTarget, add func(DiagnosisFinding)) {
+	if s.velocityClient == nil {
+		add(DiagnosisFinding{Check: "velocity", Severity: DiagnosisInfo, Title: "Velocity proxy not configured"})
+		return
+	}
+
+	servers, err := s.velocityClient.ListServers()
+	if err != nil {
+		add(DiagnosisFinding{
+			Check:        "velocity",
+			Severity:     DiagnosisWarning,
+			Title:        "Velocity proxy is not reachable",
+			Detail:       err.Error(),
+			SuggestedFix: "Players can't join through the proxy right now; this is a platform problem, check the status page.",
+		})
+		return
+	}
+
+	name := fmt.Sprintf("mc-%s", target.server.ID)
+	expected := fmt.Sprintf("%s:%d", target.host, target.server.Port)
+	for _, registered := range servers {
+		if registered.Name != name {
+			continue
+		}
+		if registered.Address != expected {
+			add(DiagnosisFinding{
+				Check:        "velocity",
+				Severity:     DiagnosisCritical,
+				Title:        "Velocity routes the server to an old address",
+				Detail:       fmt.Sprintf("registered %s, server runs at %s", registered.Address, expected),
+				SuggestedFix: "Restart the server to re-register it with the proxy.",
+			})
+			return
+		}
+		add(DiagnosisFinding{Check: "velocity", Severity: DiagnosisOK, Title: "Registered with the Velocity proxy", Detail: expected})
+		return
+	}
+
+	add(DiagnosisFinding{
+		Check:        "velocity",
+		Severity:     DiagnosisCritical,
+		Title:        "Server is not registered with the Velocity proxy",
+		Detail:       "Players joining through the proxy can't reach " + name + ".",
+		SuggestedFix: "The proxy re-syncs registrations periodically; if this persists, restart the server.",
+	})
+}
+
+// checkProxyPort checks that the proxy node can open a TCP connection to the server's port
+func (s *DiagnosisService) checkProxyPort(ctx context.Context, target *diagnosisTarget, add func(DiagnosisFinding)) {
+	fix := "The node's firewall blocks the server port; this is a platform problem, contact support."
+
+	var proxyNode *docker.RemoteNode
+	if s.conductor != nil {
+		proxyNode, _ = s.conductor.GetRemoteNode(proxyNodeID)
+	}
+
+	// Without a proxy node (single-host setups) the control plane checks the port instead
+	if proxyNode == nil || s.conductor.GetRemoteDockerClient() == nil {
+		address := net.JoinHostPort(target.host, strconv.Itoa(target.server.Port))
+		conn, err := net.DialTimeout("tcp", address, diagnosisProbeTimeout)
+		if err != nil {
+			add(DiagnosisFinding{Check: "proxy_port", Severity: DiagnosisCritical, Title: "Server port is not reachable", Detail: err.Error(), SuggestedFix: fix})
+			return
+		}
+		conn.Close()
+		add(DiagnosisFinding{Check: "proxy_port", Severity: DiagnosisOK, Title: "Server port is reachable", Detail: address + " (checked from the control plane)"})
+		return
+	}
+
+	cmd := fmt.Sprintf("timeout %d bash -c 'cat < /dev/null > /dev/tcp/%s/%d' && echo open",
+		int(diagnosisProbeTimeout.Seconds()), target.host, target.server.Port)
+	output, err := s.conductor.GetRemoteDockerClient().ExecuteSSHCommand(ctx, proxyNode, cmd)
+	if err != nil || !strings.Contains(output, "open") {
+		detail := fmt.Sprintf("proxy %s can't connect to %s:%d", proxyNode.IPAddress, target.host, target.server.Port)
+		if err != nil {
+			detail += ": " + err.Error()
+		}
+		add(DiagnosisFinding{Check: "proxy_port", Severity: DiagnosisCritical, Title: "Proxy can't reach the server port", Detail: detail, SuggestedFix: fix})
+		return
+	}
+	add(DiagnosisFinding{Check: "proxy_port", Severity: DiagnosisOK, Title: "Proxy can reach the server port"})
+}
+
+// containerStatus returns the Docker state of the server's container
+func (s *DiagnosisService) containerStatus(ctx context.Context, target *diagnosisTarget) (string, error) {
+	if target.remoteNode == nil {
+		return s.dockerService.GetContainerStatus(ctx, target.server.ContainerID)
+	}
+	return s.conductor.GetRemoteDockerClient().GetContainerStatus(ctx, target.remoteNode, target.server.ContainerID)
+}
+
+// containerLogs returns the recent container logs
+func (s *DiagnosisService) containerLogs(ctx context.Context, target *diagnosisTarget) (string, error) {
+	if target.remoteNode == nil {
+		return s.dockerService.GetContainerLogs(ctx, target.server.ContainerID, diagnosisLogTail)
+	}
+	return s.conductor.GetRemoteDockerClient().GetContainerLogs(ctx, target.remoteNode, target.server.ContainerID, diagnosisLogTail)
+}
+
+// containerExitState returns whether the container was OOM-killed and its last exit code
+func (s *DiagnosisService) containerExitState(ctx context.Context, target *diagnosisTarget) (bool, int, error) {
+	if target.remoteNode == nil {
+		inspect, err := s.dockerService.GetClient().ContainerInspect(ctx, target.server.ContainerID)
+		if err != nil {
+			return false, 0, err
+		}
+		return inspect.State.OOMKilled, inspect.State.ExitCode, nil
+	}
+
+	cmd := fmt.Sprintf("docker inspect --format='{{.State.OOMKilled}} {{.State.ExitCode}}' %s", target.server.ContainerID)
+	output, err := s.conductor.GetRemoteDockerClient().ExecuteSSHCommand(ctx, target.remoteNode, cmd)
+	if err != nil {
+		return false, 0, err
+	}
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return false, 0, fmt.Errorf("unexpected inspect output %q", output)
+	}
+	exitCode, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return false, 0, err
+	}
+	return fields[0] == "true", exitCode, nil
+}
+
+// diskUsage returns the used share and the available bytes of the filesystem holding the server data
+func (s *DiagnosisService) diskUsage(ctx context.Context, target *diagnosisTarget) (float64, uint64, error) {
+	if target.remoteNode == nil {
+		stats, err := monitoring.ReadDiskStats(s.cfg.ServersBasePath)
+		if err != nil {
+			return 0, 0, err
+		}
+		return stats.UsedPercent(), stats.AvailableBytes, nil
+	}
+
+	cmd := fmt.Sprintf("df -P -k %s | awk 'NR==2{print $2, $4}'", remoteServersPath)
+	output, err := s.conductor.GetRemoteDockerClient().ExecuteSSHCommand(ctx, target.remoteNode, cmd)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected df output %q", output)
+	}
+	totalKB, err1 := strconv.ParseUint(fields[0], 10, 64)
+	availableKB, err2 := strconv.ParseUint(fields[1], 10, 64)
+	if err1 != nil || err2 != nil || totalKB == 0 {
+		return 0, 0, fmt.Errorf("unexpected df output %q", output)
+	}
+	return float64(totalKB-availableKB) / float64(totalKB) * 100, availableKB * 1024, nil
+}
+
+// readServerFile reads a file from the server's data directory
+func (s *DiagnosisService) readServerFile(ctx context.Context, target *diagnosisTarget, name string) (string, error) {
+	if target.remoteNode == nil {
+		data, err := os.ReadFile(filepath.Join(s.cfg.ServersBasePath, target.server.ID, name))
+		return string(data), err
+	}
+	cmd := fmt.Sprintf("cat %s/%s/%s", remoteServersPath, target.server.ID, name)
+	return s.conductor.GetRemoteDockerClient().ExecuteSSHCommand(ctx, target.remoteNode, cmd)
+}
+
+// validateServerProperties returns the problems of a server.properties file
+func validateServerProperties(content string) []string {
+	var problems []string
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			problems = append(problems, fmt.Sprintf("line %d: %q is not a key=value pair", lineNumber, line))
+			continue
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	for _, key := range numericProperties {
+		if value, ok := values[key]; ok && value != "" {
+			if _, err := strconv.Atoi(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s=%s is not a number", key, value))
+			}
+		}
+	}
+	for _, key := range booleanProperties {
+		if value, ok := values[key]; ok && value != "true" && value != "false" {
+			problems = append(problems, fmt.Sprintf("%s=%s must be true or false", key, value))
+		}
+	}
+	return problems
+}
+
+// findLogLine returns the last log line containing one of the patterns
+func findLogLine(logs string, patterns []string) string {
+	lines := strings.Split(logs, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		for _, pattern := range patterns {
+			if strings.Contains(lines[i], pattern) {
+				return strings.TrimSpace(lines[i])
+			}
+		}
+	}
+	return ""
+}
+
+// lastLogLines returns the last n non-empty log lines
+func lastLogLines(logs string, n int) string {
+	lines := strings.Split(strings.TrimSpace(logs), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/storage"
+	"github.com/payperplay/hosting/internal/velocity"
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+var (
+	// ErrDiagnosticsRunning is returned if a diagnostics run is already in progress
+	ErrDiagnosticsRunning = errors.New("diagnostics are already running")
+	// ErrDiagnosticsNode is returned if the requested worker node can't be probed over SSH
+	ErrDiagnosticsNode = errors.New("worker node can't be probed")
+)
+
+// DiagnosticResult is the result of probing one dependency, with the last failure diagnostics saw
+type DiagnosticResult struct {
+	Name        string           `json:"name"`
+	Status      DependencyStatus `json:"status"`
+	LatencyMs   int64            `json:"latency_ms"`
+	Error       string           `json:"error,omitempty"`
+	Reason      string           `json:"reason,omitempty"` // Why the dependency isn't probed
+	Details     interface{}      `json:"details,omitempty"`
+	LastError   string           `json:"last_error,omitempty"` // Also kept once the dependency is up again
+	LastErrorAt *time.Time       `json:"last_error_at,omitempty"`
+}
+
+// DiagnosticsReport is the result of one diagnostics run
+type DiagnosticsReport struct {
+	StartedAt  time.Time          `json:"started_at"`
+	DurationMs int64              `json:"duration_ms"`
+	Results    []DiagnosticResult `json:"results"`
+	Failed     []string           `json:"failed"` // Dependencies that are down or degraded
+}
+
+// dependencyFailure is the last failure of a dependency
+type dependencyFailure struct {
+	err string
+	at  time.Time
+}
+
+// DiagnosticsService actively probes the external dependencies of the control plane for incident
+// triage: the database, InfluxDB, the Docker socket, the Storage Box (SFTP), the Velocity API, the
+// cloud provider API and the SSH path to one worker node. Unlike the readiness probe, every run
+// probes everything (nothing is cached) and the last failure of each dependency is remembered.
+type DiagnosticsService struct {
+	probes      []dependencyProbe
+	workerProbe func(nodeID string) (dependencyProbe, error)
+	timeout     time.Duration
+
+	running  sync.Mutex
+	mu       sync.Mutex
+	failures map[string]dependencyFailure
+}
+
+// NewDiagnosticsService creates the self-diagnostics; velocityClient may be nil
+func NewDiagnosticsService(dbProvider repository.DatabaseProvider, dockerService *docker.DockerService, velocityClient *velocity.RemoteVelocityClient, cond *conductor.Conductor, cfg *config.Config) *DiagnosticsService {
+	probes := []dependencyProbe{
+		databaseProbe(dbProvider),
+		influxDBProbe(cfg),
+		dockerProbe(dockerService),
+		storageBoxProbe(cfg),
+		velocityProbe(velocityClient),
+		cloudProviderProbe(cond.CloudProvider),
+	}
+	workerProbe := func(nodeID string) (dependencyProbe, error) {
+		return workerSSHProbe(cond, nodeID)
+	}
+
+	s := newDiagnosticsService(probes, workerProbe)
+	s.timeout = parseDurationOr(cfg.DiagnosticsTimeout, s.timeout)
+	return s
+}
+
+func newDiagnosticsService(probes []dependencyProbe, workerProbe func(nodeID string) (dependencyProbe, error)) *DiagnosticsService {
+	return &DiagnosticsService{
+		probes:      probes,
+		workerProbe: workerProbe,
+		timeout:     10 * time.Second,
+		failures:    make(map[string]dependencyFailure),
+	}
+}
+
+// Run probes every dependency concurrently; the SSH path is checked on the worker node with the
+// ID, or on a healthy worker node if nodeID is empty
+func (s *DiagnosticsService) Run(ctx context.Context, nodeID string) (*DiagnosticsReport, error) {
+	if !s.running.TryLock() {
+		return nil, ErrDiagnosticsRunning
+	}
+	defer s.running.Unlock()
+
+	worker, err := s.workerProbe(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	probes := append(append(make([]dependencyProbe, 0, len(s.probes)+1), s.probes...), worker)
+
+	report := &DiagnosticsReport{StartedAt: time.Now(), Failed: []string{}}
+	checks := runProbes(ctx, probes, s.timeout)
+	report.DurationMs = time.Since(report.StartedAt).Milliseconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	report.Results = make([]DiagnosticResult, 0, len(checks))
+	for _, check := range checks {
+		if check.Status == DependencyDown || check.Status == DependencyDegraded {
+			s.failures[check.Name] = dependencyFailure{err: check.Error, at: report.StartedAt}
+			report.Failed = append(report.Failed, check.Name)
+		}
+		result := DiagnosticResult{
+			Name:      check.Name,
+			Status:    check.Status,
+			LatencyMs: check.LatencyMs,
+			Error:     check.Error,
+			Reason:    check.Reason,
+			Details:   check.Details,
+		}
+		if failure, ok := s.failures[check.Name]; ok {
+			at := failure.at
+			result.LastError, result.LastErrorAt = failure.err, &at
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// influxDBProbe calls the health endpoint of InfluxDB
+func influxDBProbe(cfg *config.Config) dependencyProbe {
+	probe := dependencyProbe{
+		name: "influxdb",
+		run: func(ctx context.Context) (interface{}, error) {
+			details := map[string]interface{}{"url": cfg.InfluxDBURL}
+			version, err := storage.PingInfluxDB(ctx, cfg.InfluxDBURL)
+			if err != nil {
+				return details, err
+			}
+			details["version"] = version
+			return details, nil
+		},
+	}
+	if cfg.InfluxDBURL == "" {
+		probe.disabled = "InfluxDB not configured"
+	}
+	return probe
+}
+
+// storageBoxProbe opens a new SFTP connection to the Storage Box and checks the backup path
+func storageBoxProbe(cfg *config.Config) dependencyProbe {
+	probe := dependencyProbe{
+		name: "storage_box",
+		run: func(ctx context.Context) (interface{}, error) {
+			details := map[string]interface{}{"host": cfg.StorageBoxHost, "path": cfg.StorageBoxPath}
+			client, err := storage.NewSFTPClient(cfg)
+			if err != nil {
+				return details, err
+			}
+			defer client.Close()
+			return details, client.Ping()
+		},
+	}
+	if !cfg.StorageBoxEnabled {
+		probe.disabled = "Storage Box not enabled"
+	}
+	return probe
+}
+
+// workerSSHProbe checks SSH and Docker on a worker node ("docker info" over SSH)
+func workerSSHProbe(cond *conductor.Conductor, nodeID string) (dependencyProbe, error) {
+	probe := dependencyProbe{name: "worker_ssh"}
+	if cond.RemoteClient == nil {
+		probe.disabled = "no SSH access to worker nodes configured"
+		return probe, nil
+	}
+	if nodeID == "" {
+		if nodeID = pickWorkerNode(cond.NodeRegistry.GetAllNodes()); nodeID == "" {
+			probe.disabled = "no worker nodes registered"
+			return probe, nil
+		}
+	}
+	node, err := cond.GetRemoteNode(nodeID)
+	if err != nil {
+		return probe, fmt.Errorf("%w: %v", ErrDiagnosticsNode, err)
+	}
+
+	probe.run = func(ctx context.Context) (interface{}, error) {
+		details := map[string]interface{}{"node_id": node.ID, "ip_address": node.IPAddress}
+		return details, cond.RemoteClient.HealthCheck(ctx, node)
+	}
+	return probe, nil
+}
+
+// pickWorkerNode returns the worker node whose SSH path diagnostics checks: a healthy one if
+// there is one, by ID for stable results; "" if there are no worker nodes
+func pickWorkerNode(nodes []*conductor.Node) string {
+	var workers []*conductor.Node
+	for _, node := range nodes {
+		if node.IsSystemNode || node.Type == "local" || node.Labels["status"] == "provisioning" {
+			continue
+		}
+		workers = append(workers, node)
+	}
+	sort.Slice(workers, func(i, j int) bool {
+		iHealthy := workers[i].HealthStatus == conductor.HealthStatusHealthy
+		jHealthy := workers[j].HealthStatus == conductor.HealthStatusHealthy
+		if iHealthy != jHealthy {
+			return iHealthy
+		}
+		return workers[i].ID < workers[j].ID
+	})
+	if len(workers) == 0 {
+		return ""
+	}
+	return workers[0].ID
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/payperplay/hosting/internal/conductor"
+)
+
+func TestDiagnosticsServiceRun(t *testing.T) {
+	var influxErr error = errors.New("connection refused")
+	probes := []dependencyProbe{
+		{name: "database", run: func(ctx context.Context) (interface{}, error) { return nil, nil }},
+		{name: "influxdb", run: func(ctx context.Context) (interface{}, error) { return nil, influxErr }},
+		{name: "storage_box", disabled: "Storage Box not enabled"},
+	}
+	workerProbe := func(nodeID string) (dependencyProbe, error) {
+		if nodeID == "unknown" {
+			return dependencyProbe{}, ErrDiagnosticsNode
+		}
+		return dependencyProbe{name: "worker_ssh", run: func(ctx context.Context) (interface{}, error) {
+			return map[string]interface{}{"node_id": nodeID}, nil
+		}}, nil
+	}
+	s := newDiagnosticsService(probes, workerProbe)
+
+	report, err := s.Run(context.Background(), "node-1")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(report.Results) != 4 || report.Results[3].Name != "worker_ssh" {
+		t.Fatalf("results = %+v, want the probes and worker_ssh last", report.Results)
+	}
+	if len(report.Failed) != 1 || report.Failed[0] != "influxdb" {
+		t.Errorf("Failed = %v, want [influxdb]", report.Failed)
+	}
+	influx := report.Results[1]
+	if influx.Status != DependencyDown || influx.LastError != "connection refused" || influx.LastErrorAt == nil {
+		t.Errorf("influxdb = %+v, want down with the last error", influx)
+	}
+	if report.Results[2].Status != DependencyDisabled {
+		t.Errorf("storage_box status = %s, want disabled", report.Results[2].Status)
+	}
+
+	// The last error is kept once the dependency is up again
+	influxErr = nil
+	report, err = s.Run(context.Background(), "")
+	if err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	influx = report.Results[1]
+	if influx.Status != DependencyUp || influx.Error != "" || influx.LastError != "connection refused" {
+		t.Errorf("recovered influxdb = %+v, want up with the previous error as last error", influx)
+	}
+	if report.Results[0].LastError != "" {
+		t.Errorf("database has last error %q without ever failing", report.Results[0].LastError)
+	}
+
+	if _, err := s.Run(context.Background(), "unknown"); !errors.Is(err, ErrDiagnosticsNode) {
+		t.Errorf("unknown node: error = %v, want ErrDiagnosticsNode", err)
+	}
+}
+
+func TestPickWorkerNode(t *testing.T) {
+	node := func(id string, health conductor.HealthStatus) *conductor.Node {
+		return &conductor.Node{ID: id, Type: "cloud", HealthStatus: health}
+	}
+	system := node("a-proxy", conductor.HealthStatusHealthy)
+	system.IsSystemNode = true
+	local := node("local-node", conductor.HealthStatusHealthy)
+	local.Type = "local"
+	provisioning := node("a-new", conductor.HealthStatusUnknown)
+	provisioning.Labels = map[string]string{"status": "provisioning"}
+
+	tests := []struct {
+		name  string
+		nodes []*conductor.Node
+		want  string
+	}{
+		{"none", nil, ""},
+		{"only system and local nodes", []*conductor.Node{system, local, provisioning}, ""},
+		{"healthy first", []*conductor.Node{node("b", conductor.HealthStatusUnhealthy), node("c", conductor.HealthStatusHealthy), system}, "c"},
+		{"by id among healthy", []*conductor.Node{node("d", conductor.HealthStatusHealthy), node("c", conductor.HealthStatusHealthy)}, "c"},
+		{"unhealthy if nothing else", []*conductor.Node{node("b", conductor.HealthStatusUnhealthy), local}, "b"},
+	}
+	for _, tt := range tests {
+		if got := pickWorkerNode(tt.nodes); got != tt.want {
+			t.Errorf("%s: pickWorkerNode() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/monitoring"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// Disk quota errors
+var (
+	ErrDiskQuotaExceeded = errors.New("disk quota of the server exceeded, delete files or ask for a larger quota")
+	ErrDiskQuotaInvalid  = errors.New("disk quota must be between 0 (default) and 10485760 MB")
+)
+
+// diskQuotaMaxMB is the largest quota an admin can set (10 TB)
+const diskQuotaMaxMB = 10 << 20
+
+// DiskQuotaChecker refuses actions that would grow a server directory past its disk quota
+// Implemented by DiskQuotaService; services that write server files take it as an optional collaborator.
+type DiskQuotaChecker interface {
+	CheckDiskQuota(serverID string, additionalBytes int64) error
+	AddDiskUsage(serverID string, bytes int64)
+}
+
+// checkDiskQuota applies quota unless it is nil (quota enforcement not configured)
+func checkDiskQuota(quota DiskQuotaChecker, serverID string, additionalBytes int64) error {
+	if quota == nil {
+		return nil
+	}
+	return quota.CheckDiskQuota(serverID, additionalBytes)
+}
+
+// DiskUsage is the size of a server directory compared to its disk quota
+type DiskUsage struct {
+	ServerID   string     `json:"server_id"`
+	UsageBytes int64      `json:"usage_bytes"`
+	QuotaBytes int64      `json:"quota_bytes"` // 0 = unlimited
+	Percent    float64    `json:"percent"`
+	OverQuota  bool       `json:"over_quota"`
+	ScannedAt  *time.Time `json:"scanned_at,omitempty"` // Nil until the first scan
+}
+
+// DiskQuotaService tracks the size of server directories and enforces their disk quota
+// Sizes are measured by the DiskQuotaWorker (running servers every scan interval, stopped servers
+// once after they stop) and grown by uploads in between. Owners are notified once per threshold.
+type DiskQuotaService struct {
+	serverRepo *repository.ServerRepository
+	files      *ServerFileAccess
+	cfg        *config.Config
+	webhooks   *WebhookService // Optional, notifies threshold crossings
+
+	scanMu   sync.Mutex
+	scanning map[string]bool
+}
+
+// NewDiskQuotaService creates a new disk quota service
+func NewDiskQuotaService(serverRepo *repository.ServerRepository, files *ServerFileAccess, cfg *config.Config) *DiskQuotaService {
+	return &DiskQuotaService{
+		serverRepo: serverRepo,
+		files:      files,
+		cfg:        cfg,
+		scanning:   make(map[string]bool),
+	}
+}
+
+// SetWebhookService sets the service that notifies server webhooks about quota warnings
+func (s *DiskQuotaService) SetWebhookService(webhooks *WebhookService) {
+	s.webhooks = webhooks
+}
+
+// QuotaBytes returns the disk quota of a server in bytes (0 = unlimited)
+func (s *DiskQuotaService) QuotaBytes(server *models.MinecraftServer) int64 {
+	if server.DiskQuotaMB > 0 {
+		return int64(server.DiskQuotaMB) << 20
+	}
+	if s.cfg.DiskQuotaDefaultMB > 0 {
+		return int64(s.cfg.DiskQuotaDefaultMB) << 20
+	}
+	return 0
+}
+
+// GetUsage returns the disk usage of a server, measured now if refresh is set or it never was
+func (s *DiskQuotaService) GetUsage(serverID string, refresh bool) (*DiskUsage, error) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, models.ErrServerNotFound
+	}
+	if (refresh || server.DiskUsageScannedAt == nil) && server.Status != models.StatusArchived {
+		return s.Scan(server)
+	}
+	return s.usage(server, server.DiskUsageBytes), nil
+}
+
+// Scan measures the directory of a server, stores the size and notifies newly crossed thresholds
+func (s *DiskQuotaService) Scan(server *models.MinecraftServer) (*DiskUsage, error) {
+	if !s.lockScan(server.ID) {
+		return s.usage(server, server.DiskUsageBytes), nil // Another scan is measuring it right now
+	}
+	defer s.unlockScan(server.ID)
+
+	usageBytes, err := s.files.usage(server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure server directory: %w", err)
+	}
+	now := time.Now()
+	server.DiskUsageScannedAt = &now
+	usage := s.usage(server, usageBytes)
+
+	warnPercent := diskWarnLevel(usage, parseDiskWarnPercents(s.cfg.DiskQuotaWarnPercents))
+	if warnPercent > server.DiskWarnPercent {
+		s.notify(server, usage, warnPercent)
+	}
+	if err := s.serverRepo.UpdateDiskUsage(server.ID, usageBytes, warnPercent); err != nil {
+		return nil, fmt.Errorf("failed to save disk usage: %w", err)
+	}
+	server.DiskUsageBytes = usageBytes
+	server.DiskWarnPercent = warnPercent
+
+	monitoring.ServerDiskUsageMB.WithLabelValues(server.ID, server.Name, server.MinecraftVersion).Set(float64(usageBytes) / (1 << 20))
+	return usage, nil
+}
+
+// ScanAll measures running servers and servers that stopped since their last scan
+func (s *DiskQuotaService) ScanAll() {
+	servers, err := s.serverRepo.FindAll()
+	if err != nil {
+		logger.Warn("DISK-QUOTA: Failed to list servers", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	scanned := 0
+	for i := range servers {
+		server := &servers[i]
+		if !needsDiskScan(server) {
+			continue
+		}
+		if _, err := s.Scan(server); err != nil {
+			logger.Warn("DISK-QUOTA: Failed to scan server", map[string]interface{}{
+				"server_id": server.ID,
+				"error":     err.Error(),
+			})
+			continue
+		}
+		scanned++
+	}
+	if scanned > 0 {
+		logger.Info("DISK-QUOTA: Scanned server directories", map[string]interface{}{
+			"scanned": scanned,
+		})
+	}
+}
+
+// needsDiskScan reports whether a server directory may have changed since its last scan
+func needsDiskScan(server *models.MinecraftServer) bool {
+	switch server.Status {
+	case models.StatusArchived, models.StatusArchiving, models.StatusQueued:
+		return false
+	case models.StatusRunning, models.StatusStarting, models.StatusStopping:
+		return true
+	}
+	// Stopped servers don't grow; measure them once after they stopped
+	return server.DiskUsageScannedAt == nil ||
+		(server.LastStoppedAt != nil && server.LastStoppedAt.After(*server.DiskUsageScannedAt))
+}
+
+// CheckDiskQuota returns ErrDiskQuotaExceeded if the server directory can't grow by additionalBytes
+func (s *DiskQuotaService) CheckDiskQuota(serverID string, additionalBytes int64) error {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return models.ErrServerNotFound
+	}
+	quota := s.QuotaBytes(server)
+	if quota == 0 {
+		return nil
+	}
+	if server.DiskUsageBytes+additionalBytes > quota {
+		return fmt.Errorf("%w (%d of %d MB used)", ErrDiskQuotaExceeded, server.DiskUsageBytes>>20, quota>>20)
+	}
+	return nil
+}
+
+// AddDiskUsage records bytes written to a server directory until the next scan measures it
+func (s *DiskQuotaService) AddDiskUsage(serverID string, bytes int64) {
+	if bytes <= 0 {
+		return
+	}
+	if err := s.serverRepo.AddDiskUsage(serverID, bytes); err != nil {
+		logger.Warn("DISK-QUOTA: Failed to record disk usage", map[string]interface{}{
+			"server_id": serverID,
+			"error":     err.Error(),
+		})
+	}
+}
+
+// QuotaMB returns the disk quota set for a server (0 = default quota)
+func (s *DiskQuotaService) QuotaMB(serverID string) (int, error) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return 0, models.ErrServerNotFound
+	}
+	return server.DiskQuotaMB, nil
+}
+
+// SetQuota sets the disk quota of a server (admins only, 0 = default quota)
+func (s *DiskQuotaService) SetQuota(serverID string, quotaMB int) (*DiskUsage, error) {
+	if quotaMB < 0 || quotaMB > diskQuotaMaxMB {
+		return nil, ErrDiskQuotaInvalid
+	}
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, models.ErrServerNotFound
+	}
+	if err := s.serverRepo.UpdateDiskQuota(serverID, quotaMB); err != nil {
+		return nil, fmt.Errorf("failed to save disk quota: %w", err)
+	}
+	server.DiskQuotaMB = quotaMB
+
+	logger.Info("DISK-QUOTA: Quota changed", map[string]interface{}{
+		"server_id": serverID,
+		"quota_mb":  quotaMB,
+	})
+	return s.usage(server, server.DiskUsageBytes), nil
+}
+
+func (s *DiskQuotaService) usage(server *models.MinecraftServer, usageBytes int64) *DiskUsage {
+	usage := &DiskUsage{
+		ServerID:   server.ID,
+		UsageBytes: usageBytes,
+		QuotaBytes: s.QuotaBytes(server),
+		ScannedAt:  server.DiskUsageScannedAt,
+	}
+	if usage.QuotaBytes > 0 {
+		usage.Percent = float64(usageBytes) * 100 / float64(usage.QuotaBytes)
+		usage.OverQuota = usageBytes >= usage.QuotaBytes
+	}
+	return usage
+}
+
+// notify tells the owner that the server directory crossed the warnPercent threshold
+func (s *DiskQuotaService) notify(server *models.MinecraftServer, usage *DiskUsage, warnPercent int) {
+	logger.Warn("DISK-QUOTA: Server directory crossed a quota threshold", map[string]interface{}{
+		"server_id":   server.ID,
+		"threshold":   warnPercent,
+		"usage_bytes": usage.UsageBytes,
+		"quota_bytes": usage.QuotaBytes,
+	})
+	events.PublishServerDiskQuota(server.ID, server.OwnerID, warnPercent, usage.UsageBytes, usage.QuotaBytes)
+	if s.webhooks != nil {
+		s.webhooks.NotifyDiskQuotaWarning(server.ID, server.Name,
+			fmt.Sprintf("%.0f%% (%d of %d MB)", usage.Percent, usage.UsageBytes>>20, usage.QuotaBytes>>20))
+	}
+}
+
+func (s *DiskQuotaService) lockScan(serverID string) bool {
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+	if s.scanning[serverID] {
+		return false
+	}
+	s.scanning[serverID] = true
+	return true
+}
+
+func (s *DiskQuotaService) unlockScan(serverID string) {
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+	delete(s.scanning, serverID)
+}
+
+// diskWarnLevel returns the highest threshold (percent, 100 = over quota) usage has reached, 0 for none
+func diskWarnLevel(usage *DiskUsage, thresholds []int) int {
+	if usage.QuotaBytes == 0 {
+		return 0
+	}
+	level := 0
+	for _, threshold := range append(thresholds, 100) {
+		if usage.Percent >= float64(threshold) && threshold > level {
+			level = threshold
+		}
+	}
+	return level
+}
+
+// parseDiskWarnPercents parses DISK_QUOTA_WARN_PERCENTS ("80,95"), ignoring invalid entries
+func parseDiskWarnPercents(value string) []int {
+	var thresholds []int
+	for _, part := range strings.Split(value, ",") {
+		percent, err := strconv.Atoi(strings.TrimSpace(part))
+		if err == nil && percent > 0 && percent < 100 {
+			thresholds = append(thresholds, percent)
+		}
+	}
+	sort.Ints(thresholds)
+	return thresholds
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestParseDiskWarnPercents(t *testing.T) {
+	for value, want := range map[string][]int{
+		"80,95":          {80, 95},
+		" 95, 80 ":       {80, 95},
+		"0,100,abc,50":   {50},
+		"":               nil,
+		"90,,120,-5, 70": {70, 90},
+	} {
+		if got := parseDiskWarnPercents(value); !reflect.DeepEqual(got, want) {
+			t.Errorf("parseDiskWarnPercents(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestDiskWarnLevel(t *testing.T) {
+	thresholds := []int{80, 95}
+	for percent, want := range map[float64]int{
+		10:  0,
+		80:  80,
+		94:  80,
+		96:  95,
+		100: 100,
+		130: 100,
+	} {
+		usage := &DiskUsage{QuotaBytes: 1000, Percent: percent}
+		if got := diskWarnLevel(usage, thresholds); got != want {
+			t.Errorf("diskWarnLevel(%v%%) = %d, want %d", percent, got, want)
+		}
+	}
+	if got := diskWarnLevel(&DiskUsage{Percent: 500}, thresholds); got != 0 {
+		t.Errorf("diskWarnLevel(unlimited) = %d, want 0", got)
+	}
+}
+
+func TestNeedsDiskScan(t *testing.T) {
+	scanned := time.Now()
+	before, after := scanned.Add(-time.Hour), scanned.Add(time.Hour)
+	for _, tc := range []struct {
+		name   string
+		server models.MinecraftServer
+		want   bool
+	}{
+		{"running", models.MinecraftServer{Status: models.StatusRunning, DiskUsageScannedAt: &scanned}, true},
+		{"archived", models.MinecraftServer{Status: models.StatusArchived}, false},
+		{"stopped, never scanned", models.MinecraftServer{Status: models.StatusStopped}, true},
+		{"stopped before scan", models.MinecraftServer{Status: models.StatusStopped, DiskUsageScannedAt: &scanned, LastStoppedAt: &before}, false},
+		{"stopped after scan", models.MinecraftServer{Status: models.StatusStopped, DiskUsageScannedAt: &scanned, LastStoppedAt: &after}, true},
+	} {
+		if got := needsDiskScan(&tc.server); got != tc.want {
+			t.Errorf("needsDiskScan(%s) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// diskQuotaDefaultScanInterval applies if DISK_QUOTA_SCAN_INTERVAL is not a valid duration
+const diskQuotaDefaultScanInterval = 15 * time.Minute
+
+// DiskQuotaWorker periodically measures server directories for the disk quota
+type DiskQuotaWorker struct {
+	quotaService *DiskQuotaService
+	scanInterval time.Duration // How often to scan (default: 15m)
+	running      bool
+	ctx          context.Context
+	cancel       context.CancelFunc
+	scanMutex    sync.Mutex // Prevents concurrent scan runs
+}
+
+// NewDiskQuotaWorker creates a new disk quota worker
+func NewDiskQuotaWorker(quotaService *DiskQuotaService, scanInterval string) *DiskQuotaWorker {
+	interval, err := time.ParseDuration(scanInterval)
+	if err != nil || interval < time.Minute {
+		interval = diskQuotaDefaultScanInterval
+	}
+	return &DiskQuotaWorker{
+		quotaService: quotaService,
+		scanInterval: interval,
+		running:      false,
+	}
+}
+
+// Start begins the scan worker
+func (w *DiskQuotaWorker) Start() {
+	if w.running {
+		logger.Warn("DISK-QUOTA: Worker already running", nil)
+		return
+	}
+
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.running = true
+
+	logger.Info("DISK-QUOTA: Starting scan worker", map[string]interface{}{
+		"scan_interval": w.scanInterval,
+	})
+
+	go func() {
+		ticker := time.NewTicker(w.scanInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.runScan()
+			case <-w.ctx.Done():
+				logger.Info("DISK-QUOTA: Worker stopped", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the scan worker
+func (w *DiskQuotaWorker) Stop() {
+	if !w.running {
+		return
+	}
+
+	logger.Info("DISK-QUOTA: Stopping scan worker", nil)
+	w.cancel()
+	w.running = false
+}
+
+// runScan performs one scan pass
+func (w *DiskQuotaWorker) runScan() {
+	if !w.scanMutex.TryLock() {
+		logger.Warn("DISK-QUOTA: Scan already in progress, skipping this cycle", nil)
+		return
+	}
+	defer w.scanMutex.Unlock()
+
+	w.quotaService.ScanAll()
+}
@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/monitoring"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/velocity"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// driftDefaultInterval applies if DRIFT_CHECK_INTERVAL is not a valid duration
+	driftDefaultInterval = 5 * time.Minute
+	// driftCheckTimeout bounds one check (container listings on all nodes, Velocity)
+	driftCheckTimeout = 2 * time.Minute
+)
+
+// ErrDriftCheckRunning is returned if a drift check is already in progress
+var ErrDriftCheckRunning = errors.New("drift check is already running")
+
+// DriftKind is the kind of disagreement between the database, Docker and Velocity
+type DriftKind string
+
+const (
+	DriftContainerMissing    DriftKind = "container_missing"    // Database says running, no container runs on the node
+	DriftContainerUnexpected DriftKind = "container_unexpected" // Container runs, the database says the server doesn't (there)
+	DriftVelocityMissing     DriftKind = "velocity_missing"     // Running server not (or wrongly) registered with Velocity
+	DriftVelocityOrphaned    DriftKind = "velocity_orphaned"    // Velocity routes to a server that is stopped or gone
+	DriftRAMLeak             DriftKind = "ram_leak"             // RAM accounted for a container that doesn't run
+)
+
+// driftKinds are all kinds, for resetting the metric
+var driftKinds = []DriftKind{DriftContainerMissing, DriftContainerUnexpected, DriftVelocityMissing, DriftVelocityOrphaned, DriftRAMLeak}
+
+// StateDrift is one disagreement found by a drift check
+type StateDrift struct {
+	Kind        DriftKind `json:"kind"`
+	ServerID    string    `json:"server_id"`
+	ServerName  string    `json:"server_name,omitempty"`
+	NodeID      string    `json:"node_id,omitempty"`
+	Detail      string    `json:"detail"`
+	Action      string    `json:"action"`    // What healing does, or what an admin should do
+	Confirmed   bool      `json:"confirmed"` // Also found by the previous check (only confirmed drift is healed and alerted)
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	Healed      bool      `json:"healed"`
+	HealError   string    `json:"heal_error,omitempty"`
+
+	containerID string // Container the database refers to (container_missing)
+	address     string // Expected Velocity address (velocity_missing)
+	registered  bool   // Registered with another address (velocity_missing)
+}
+
+// key identifies the same drift across checks
+func (d StateDrift) key() string {
+	return string(d.Kind) + "/" + d.ServerID
+}
+
+// DriftReport is the result of one drift check
+type DriftReport struct {
+	CheckedAt  time.Time    `json:"checked_at"`
+	DurationMs int64        `json:"duration_ms"`
+	AutoHeal   bool         `json:"auto_heal"`
+	Drifts     []StateDrift `json:"drifts"`
+	Unchecked  []string     `json:"unchecked"` // Nodes or systems that couldn't be compared (listing failed)
+}
+
+// driftSnapshot is the state one check compares
+type driftSnapshot struct {
+	servers    []models.MinecraftServer
+	containers map[string]map[string]string // Node ID -> server ID -> running container ID, only for listed nodes
+	registry   []*conductor.ContainerInfo
+	velocity   map[string]string // Registered name -> address, nil if Velocity couldn't be listed
+	addresses  map[string]string // Server ID -> address Velocity should use, for running servers
+}
+
+// DriftService periodically compares the database with the containers on the nodes, the container
+// registry and the Velocity registrations. Drift found by two checks in a row is healed (with
+// DRIFT_AUTO_HEAL) and exported as payperplay_state_drift for the StateDrift alert; containers that
+// run unexpectedly are never stopped automatically.
+type DriftService struct {
+	cond           *conductor.Conductor
+	dockerService  *docker.DockerService
+	serverRepo     *repository.ServerRepository
+	mcService      *MinecraftService
+	velocityClient *velocity.RemoteVelocityClient // Optional
+	cfg            *config.Config
+	interval       time.Duration // 0 = no periodic checks
+	autoHeal       bool
+
+	running   sync.Mutex
+	mu        sync.RWMutex
+	firstSeen map[string]time.Time // Drift keys of the last check
+	last      *DriftReport
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDriftService creates a new drift service; velocityClient may be nil
+func NewDriftService(cond *conductor.Conductor, dockerService *docker.DockerService, serverRepo *repository.ServerRepository, mcService *MinecraftService, velocityClient *velocity.RemoteVelocityClient, cfg *config.Config) *DriftService {
+	interval, err := time.ParseDuration(cfg.DriftCheckInterval)
+	if err != nil || interval < 0 {
+		interval = driftDefaultInterval
+	}
+	return &DriftService{
+		cond:           cond,
+		dockerService:  dockerService,
+		serverRepo:     serverRepo,
+		mcService:      mcService,
+		velocityClient: velocityClient,
+		cfg:            cfg,
+		interval:       interval,
+		autoHeal:       cfg.DriftAutoHeal,
+		firstSeen:      make(map[string]time.Time),
+	}
+}
+
+// LastReport returns the report of the last check, nil before the first one
+func (s *DriftService) LastReport() *DriftReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+// Check compares the state once and heals confirmed drift if DRIFT_AUTO_HEAL is on
+func (s *DriftService) Check(ctx context.Context) (*DriftReport, error) {
+	if !s.running.TryLock() {
+		return nil, ErrDriftCheckRunning
+	}
+	defer s.running.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, driftCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	snapshot, unchecked, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{
+		CheckedAt: start,
+		AutoHeal:  s.autoHeal,
+		Drifts:    detectDrift(snapshot),
+		Unchecked: unchecked,
+	}
+
+	s.mu.Lock()
+	firstSeen := make(map[string]time.Time, len(report.Drifts))
+	for i := range report.Drifts {
+		drift := &report.Drifts[i]
+		drift.FirstSeenAt = start
+		if seen, ok := s.firstSeen[drift.key()]; ok {
+			drift.FirstSeenAt, drift.Confirmed = seen, true
+		}
+		firstSeen[drift.key()] = drift.FirstSeenAt
+	}
+	s.firstSeen = firstSeen
+	s.mu.Unlock()
+
+	unhealed := make(map[DriftKind]int)
+	for i := range report.Drifts {
+		drift := &report.Drifts[i]
+		if !drift.Confirmed {
+			continue
+		}
+		if s.autoHeal && drift.Kind != DriftContainerUnexpected {
+			if err := s.heal(drift); err != nil {
+				drift.HealError = err.Error()
+			} else {
+				drift.Healed = true
+			}
+		}
+		if !drift.Healed {
+			unhealed[drift.Kind]++
+		}
+		logger.Warn("DRIFT: State drift detected", map[string]interface{}{
+			"kind":       drift.Kind,
+			"server_id":  drift.ServerID,
+			"node_id":    drift.NodeID,
+			"detail":     drift.Detail,
+			"healed":     drift.Healed,
+			"heal_error": drift.HealError,
+		})
+	}
+	for _, kind := range driftKinds {
+		monitoring.StateDrift.WithLabelValues(string(kind)).Set(float64(unhealed[kind]))
+	}
+
+	report.DurationMs = time.Since(start).Milliseconds()
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+	return report, nil
+}
+
+// snapshot collects the state to compare; nodes and systems that can't be listed are returned
+// as unchecked and their servers are left out of the comparison
+func (s *DriftService) snapshot(ctx context.Context) (driftSnapshot, []string, error) {
+	snapshot := driftSnapshot{
+		containers: make(map[string]map[string]string),
+		addresses:  make(map[string]string),
+	}
+	unchecked := []string{}
+
+	servers, err := s.serverRepo.FindAll()
+	if err != nil {
+		return snapshot, nil, fmt.Errorf("failed to load servers: %w", err)
+	}
+	snapshot.servers = servers
+
+	if local, err := s.dockerService.ListRunningMinecraftContainers(ctx); err != nil {
+		unchecked = append(unchecked, "local-node: "+err.Error())
+	} else {
+		snapshot.containers["local-node"] = make(map[string]string, len(local))
+		for _, container := range local {
+			snapshot.containers["local-node"][container.ServerID] = container.ContainerID
+		}
+	}
+
+	for _, node := range s.cond.NodeRegistry.GetAllNodes() {
+		if node.IsSystemNode || node.ID == "local-node" {
+			continue
+		}
+		if node.Status != conductor.NodeStatusHealthy || s.cond.RemoteClient == nil {
+			unchecked = append(unchecked, node.ID+": not healthy")
+			continue
+		}
+		remote, err := s.cond.RemoteClient.ListRunningContainers(ctx, &docker.RemoteNode{ID: node.ID, IPAddress: node.IPAddress, SSHUser: node.SSHUser})
+		if err != nil {
+			unchecked = append(unchecked, node.ID+": "+err.Error())
+			continue
+		}
+		snapshot.containers[node.ID] = make(map[string]string, len(remote))
+		for _, container := range remote {
+			snapshot.containers[node.ID][container.ServerID] = container.ContainerID
+		}
+	}
+
+	snapshot.registry = s.cond.ContainerRegistry.GetAllContainers()
+
+	if s.velocityClient != nil {
+		registered, err := s.velocityClient.ListServers()
+		if err != nil {
+			unchecked = append(unchecked, "velocity: "+err.Error())
+		} else {
+			snapshot.velocity = make(map[string]string, len(registered))
+			for _, info := range registered {
+				snapshot.velocity[info.Name] = info.Address
+			}
+		}
+		for i := range servers {
+			if servers[i].Status == models.StatusRunning && servers[i].NodeID != "" {
+				if address, err := velocityAddress(s.cond, &servers[i], s.cfg); err == nil {
+					snapshot.addresses[servers[i].ID] = address
+				}
+			}
+		}
+	}
+	return snapshot, unchecked, nil
+}
+
+// detectDrift compares a snapshot, sorted by kind and server
+func detectDrift(snapshot driftSnapshot) []StateDrift {
+	drifts := []StateDrift{}
+	servers := make(map[string]*models.MinecraftServer, len(snapshot.servers))
+	for i := range snapshot.servers {
+		servers[snapshot.servers[i].ID] = &snapshot.servers[i]
+	}
+	// runningOn returns whether the node of a server was listed and its container there
+	runningOn := func(nodeID, serverID string) (bool, string) {
+		listed, ok := snapshot.containers[nodeID]
+		if !ok {
+			return false, ""
+		}
+		return true, listed[serverID]
+	}
+
+	for _, server := range servers {
+		if server.Status != models.StatusRunning || server.NodeID == "" {
+			continue
+		}
+		listed, containerID := runningOn(server.NodeID, server.ID)
+		if !listed {
+			continue
+		}
+		if containerID == "" {
+			drifts = append(drifts, StateDrift{
+				Kind: DriftContainerMissing, ServerID: server.ID, ServerName: server.Name, NodeID: server.NodeID,
+				Detail:      "the database says running, but no container of the server runs on its node",
+				Action:      "mark the server as stopped and close its billing session",
+				containerID: server.ContainerID,
+			})
+			continue
+		}
+
+		if snapshot.velocity == nil {
+			continue
+		}
+		address, known := snapshot.addresses[server.ID]
+		if !known {
+			continue
+		}
+		registeredAddress, registered := snapshot.velocity["mc-"+server.ID]
+		if !registered || registeredAddress != address {
+			detail := "running server is not registered with Velocity"
+			if registered {
+				detail = fmt.Sprintf("Velocity routes to %s instead of %s", registeredAddress, address)
+			}
+			drifts = append(drifts, StateDrift{
+				Kind: DriftVelocityMissing, ServerID: server.ID, ServerName: server.Name, NodeID: server.NodeID,
+				Detail:     detail,
+				Action:     "register the server with Velocity at " + address,
+				address:    address,
+				registered: registered,
+			})
+		}
+	}
+
+	for nodeID, listed := range snapshot.containers {
+		for serverID, containerID := range listed {
+			server, ok := servers[serverID]
+			active := ok && (server.Status == models.StatusRunning || server.Status == models.StatusStarting || server.Status == models.StatusStopping)
+			// A server failed over while its node was down runs elsewhere now; the old container is stale
+			elsewhere := active && server.NodeID != "" && server.NodeID != nodeID
+			if active && !elsewhere {
+				continue
+			}
+			status := "deleted"
+			name := ""
+			if ok {
+				status, name = string(server.Status), server.Name
+			}
+			if elsewhere {
+				status = "running on node " + server.NodeID
+			}
+			drifts = append(drifts, StateDrift{
+				Kind: DriftContainerUnexpected, ServerID: serverID, ServerName: name, NodeID: nodeID,
+				Detail: fmt.Sprintf("container %s runs, but the server is %s", shortID(containerID), status),
+				Action: "stop the container, or start the server again so it is billed",
+			})
+		}
+	}
+
+	for name := range snapshot.velocity {
+		serverID, ok := strings.CutPrefix(name, "mc-")
+		if !ok {
+			continue // Lobby and other proxy servers
+		}
+		server, found := servers[serverID]
+		if found && server.Status != models.StatusStopped && server.Status != models.StatusArchived {
+			continue
+		}
+		status := "deleted"
+		serverName := ""
+		if found {
+			status, serverName = string(server.Status), server.Name
+		}
+		drifts = append(drifts, StateDrift{
+			Kind: DriftVelocityOrphaned, ServerID: serverID, ServerName: serverName,
+			Detail: "Velocity routes players to the server, but it is " + status,
+			Action: "unregister " + name + " from Velocity",
+		})
+	}
+
+	for _, container := range snapshot.registry {
+		if container.Status != "running" {
+			continue
+		}
+		if server, ok := servers[container.ServerID]; ok && server.Status == models.StatusRunning {
+			continue // Counted as container_missing if the container is gone
+		}
+		listed, containerID := runningOn(container.NodeID, container.ServerID)
+		if !listed || containerID != "" {
+			continue
+		}
+		drifts = append(drifts, StateDrift{
+			Kind: DriftRAMLeak, ServerID: container.ServerID, ServerName: container.ServerName, NodeID: container.NodeID,
+			Detail: fmt.Sprintf("%d MB are accounted on the node for a container that doesn't run", container.RAMMb),
+			Action: "remove the container from the registry and recalculate the node allocation",
+		})
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Kind != drifts[j].Kind {
+			return drifts[i].Kind < drifts[j].Kind
+		}
+		return drifts[i].ServerID < drifts[j].ServerID
+	})
+	return drifts
+}
+
+// heal resolves one confirmed drift
+func (s *DriftService) heal(drift *StateDrift) error {
+	switch drift.Kind {
+	case DriftContainerMissing:
+		changed, err := s.mcService.HandleContainerLost(drift.ServerID, drift.containerID)
+		if err == nil && !changed {
+			drift.Detail += " (resolved in the meantime)"
+		}
+		return err
+	case DriftVelocityMissing:
+		name := "mc-" + drift.ServerID
+		if drift.registered {
+			if err := s.velocityClient.UnregisterServer(name); err != nil {
+				return err
+			}
+		}
+		return s.velocityClient.RegisterServer(name, drift.address)
+	case DriftVelocityOrphaned:
+		return s.velocityClient.UnregisterServer("mc-" + drift.ServerID)
+	case DriftRAMLeak:
+		s.cond.RemoveContainer(drift.ServerID)
+		containerCount, allocatedRAMMB := s.cond.ContainerRegistry.GetNodeAllocation(drift.NodeID)
+		s.cond.NodeRegistry.UpdateNodeResources(drift.NodeID, containerCount, allocatedRAMMB)
+		return nil
+	}
+	return fmt.Errorf("drift %s can't be healed automatically", drift.Kind)
+}
+
+// Start checks periodically (DRIFT_CHECK_INTERVAL, 0 = only via the API)
+func (s *DriftService) Start() {
+	if s.interval == 0 {
+		logger.Info("DRIFT: Periodic drift checks disabled (DRIFT_CHECK_INTERVAL=0)", nil)
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.Check(s.ctx); err != nil && !errors.Is(err, ErrDriftCheckRunning) && s.ctx.Err() == nil {
+					logger.Error("DRIFT: Drift check failed", err, nil)
+				}
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the periodic checks
+func (s *DriftService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// shortID shortens a container ID for messages
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
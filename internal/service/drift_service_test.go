@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestDetectDrift(t *testing.T) {
+	snapshot := driftSnapshot{
+		servers: []models.MinecraftServer{
+			{ID: "ok", Status: models.StatusRunning, NodeID: "node-a", ContainerID: "c-ok"},
+			{ID: "lost", Status: models.StatusRunning, NodeID: "node-a", ContainerID: "c-lost"},
+			{ID: "unregistered", Status: models.StatusRunning, NodeID: "node-a"},
+			{ID: "moved", Status: models.StatusRunning, NodeID: "node-a"},
+			{ID: "unlisted", Status: models.StatusRunning, NodeID: "node-b"}, // Node couldn't be listed
+			{ID: "stopped", Status: models.StatusStopped, NodeID: "node-a"},
+			{ID: "leaked", Status: models.StatusStopped, NodeID: "node-a"},
+			{ID: "sleeping", Status: models.StatusSleeping, NodeID: "node-a"},
+			{ID: "failedover", Status: models.StatusRunning, NodeID: "node-b"}, // Old container left on node-a
+		},
+		containers: map[string]map[string]string{
+			"node-a": {"ok": "c-ok", "unregistered": "c-2", "moved": "c-3", "stopped": "c-stopped", "failedover": "c-old"},
+		},
+		registry: []*conductor.ContainerInfo{
+			{ServerID: "leaked", NodeID: "node-a", Status: "running", RAMMb: 2048},
+			{ServerID: "sleeping", NodeID: "node-a", Status: "sleeping", RAMMb: 2048},
+			{ServerID: "lost", NodeID: "node-a", Status: "running", RAMMb: 1024},
+		},
+		velocity: map[string]string{
+			"mc-ok":      "10.0.0.1:25565",
+			"mc-moved":   "10.0.0.9:25566",
+			"mc-stopped": "10.0.0.1:25567",
+			"mc-gone":    "10.0.0.1:25568",
+			"lobby":      "10.0.0.2:25565",
+		},
+		addresses: map[string]string{
+			"ok":           "10.0.0.1:25565",
+			"unregistered": "10.0.0.1:25569",
+			"moved":        "10.0.0.1:25566",
+		},
+	}
+
+	drifts := detectDrift(snapshot)
+	want := []struct {
+		kind     DriftKind
+		serverID string
+	}{
+		{DriftContainerMissing, "lost"},
+		{DriftContainerUnexpected, "failedover"},
+		{DriftContainerUnexpected, "stopped"},
+		{DriftRAMLeak, "leaked"},
+		{DriftVelocityMissing, "moved"},
+		{DriftVelocityMissing, "unregistered"},
+		{DriftVelocityOrphaned, "gone"},
+		{DriftVelocityOrphaned, "stopped"},
+	}
+	if len(drifts) != len(want) {
+		t.Fatalf("detectDrift() = %+v, want %d drifts", drifts, len(want))
+	}
+	for i, w := range want {
+		if drifts[i].Kind != w.kind || drifts[i].ServerID != w.serverID {
+			t.Errorf("drift %d = %s/%s, want %s/%s", i, drifts[i].Kind, drifts[i].ServerID, w.kind, w.serverID)
+		}
+	}
+	if drifts[0].containerID != "c-lost" {
+		t.Errorf("container_missing refers to %q, want the container of the database", drifts[0].containerID)
+	}
+	if moved := drifts[4]; !moved.registered || moved.address != "10.0.0.1:25566" {
+		t.Errorf("velocity_missing of a moved server = %+v, want re-registration at the new address", moved)
+	}
+
+	// Without a Velocity listing, registrations aren't compared
+	snapshot.velocity = nil
+	for _, drift := range detectDrift(snapshot) {
+		if drift.Kind == DriftVelocityMissing || drift.Kind == DriftVelocityOrphaned {
+			t.Errorf("drift %s/%s reported without a Velocity listing", drift.Kind, drift.ServerID)
+		}
+	}
+}
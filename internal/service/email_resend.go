@@ -0,0 +1,258 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const resendAPIURL = "https://api.resend.com/emails"
+
+// ResendEmailSender sends emails through the Resend HTTP API (https://resend.com)
+// Used in production when RESEND_API_KEY is set; links point at the configured frontend URL.
+type ResendEmailSender struct {
+	apiKey      string
+	fromEmail   string // e.g., "PayPerPlay <noreply@payperplay.host>"
+	frontendURL string // e.g., "https://payperplay.host"
+	httpClient  *http.Client
+}
+
+// NewResendEmailSender creates a production email sender using the Resend API
+func NewResendEmailSender(apiKey, fromEmail, frontendURL string) *ResendEmailSender {
+	return &ResendEmailSender{
+		apiKey:      apiKey,
+		fromEmail:   fromEmail,
+		frontendURL: strings.TrimRight(frontendURL, "/"),
+		httpClient:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// SendVerificationEmail sends an email verification link
+func (r *ResendEmailSender) SendVerificationEmail(email, username, token string) error {
+	link := fmt.Sprintf("%s/verify-email?token=%s", r.frontendURL, token)
+	return r.send(email, "Verify your PayPerPlay account", "verification", resendLayout(
+		"Welcome to PayPerPlay! 🎮",
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("Thanks for signing up! Please verify your email address to get started:")+
+			resendButton(link, "Verify Email Address")+
+			resendParagraph("This link will expire in 24 hours. If you didn't create an account, you can safely ignore this email."),
+	))
+}
+
+// SendPasswordResetEmail sends a password reset link
+func (r *ResendEmailSender) SendPasswordResetEmail(email, username, token string) error {
+	link := fmt.Sprintf("%s/reset-password?token=%s", r.frontendURL, token)
+	return r.send(email, "Reset your PayPerPlay password", "password_reset", resendLayout(
+		"Reset Your Password 🔑",
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("We received a request to reset your password. Click the button below to set a new password:")+
+			resendButton(link, "Reset Password")+
+			resendParagraph("This link will expire in 1 hour. If you didn't request a password reset, please ignore this email. Your password will remain unchanged."),
+	))
+}
+
+// SendWelcomeEmail sends a welcome email after registration
+func (r *ResendEmailSender) SendWelcomeEmail(email, username string) error {
+	return r.send(email, "Welcome to PayPerPlay! 🎉", "welcome", resendLayout(
+		"Welcome to PayPerPlay! 🎉",
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("Your account has been successfully verified! You're now ready to create your first Minecraft server.")+
+			resendParagraph("Remember: You only pay when your server is running. Stopped servers cost nothing!")+
+			resendButton(r.frontendURL+"/dashboard", "Go to Dashboard"),
+	))
+}
+
+// SendAccountDeletedEmail sends a confirmation email after account deletion
+func (r *ResendEmailSender) SendAccountDeletedEmail(email, username string) error {
+	return r.send(email, "Your PayPerPlay account has been deleted", "account_deleted", resendLayout(
+		"Account Deleted",
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("Your PayPerPlay account has been successfully deleted. All your servers and data have been permanently removed as per your request.")+
+			resendParagraph("⚠️ If you didn't request this deletion, please contact our support team immediately."),
+	))
+}
+
+// SendNewDeviceAlert sends an alert for a new device login
+func (r *ResendEmailSender) SendNewDeviceAlert(email, username, deviceName, ipAddress string, loginTime time.Time) error {
+	return r.send(email, "🔒 New device login detected", "security_alert_new_device", resendLayout(
+		"New Device Login 🔒",
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("We detected a login to your PayPerPlay account from a new device:")+
+			resendParagraph("Device: %s<br>IP Address: %s<br>Time: %s", deviceName, ipAddress, loginTime.Format("2006-01-02 15:04:05 MST"))+
+			resendParagraph("If this was you, you can safely ignore this email. If you don't recognize this activity, change your password immediately and review your security settings."),
+	))
+}
+
+// SendAccountLockedAlert sends an alert when account is locked
+func (r *ResendEmailSender) SendAccountLockedAlert(email, username string, lockDuration time.Duration) error {
+	return r.send(email, "🔒 Your account has been temporarily locked", "security_alert_account_locked", resendLayout(
+		"Account Temporarily Locked 🔒",
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("Your PayPerPlay account has been temporarily locked due to multiple failed login attempts.")+
+			resendParagraph("Lock Duration: %s", lockDuration.String())+
+			resendParagraph("If you didn't attempt to log in, change your password after the lock expires and enable two-factor authentication."),
+	))
+}
+
+// SendPasswordChangedAlert sends an alert when password is changed
+func (r *ResendEmailSender) SendPasswordChangedAlert(email, username string) error {
+	return r.send(email, "🔒 Your password was changed", "security_alert_password_changed", resendLayout(
+		"Password Changed 🔒",
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("Your PayPerPlay account password was successfully changed just now.")+
+			resendParagraph("If you DID NOT change your password, your account may have been compromised. Reset your password and contact support immediately."),
+	))
+}
+
+// SendOAuthRefreshFailedAlert sends an alert when a linked provider's token can no longer be refreshed
+func (r *ResendEmailSender) SendOAuthRefreshFailedAlert(email, username, provider string) error {
+	return r.send(email, fmt.Sprintf("🔒 Your %s connection needs attention", provider), "security_alert_oauth_refresh_failed", resendLayout(
+		html.EscapeString(provider)+" Connection Expired 🔒",
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("We could not renew the access to your linked %s account. The provider may have revoked our access, or the connection was removed from your %s settings.", provider, provider)+
+			resendParagraph("Your PayPerPlay account is not affected, but logging in with %s may fail until you sign in with it again.", provider)+
+			resendButton(r.frontendURL+"/settings/security", "Review Connected Providers"),
+	))
+}
+
+// SendOrganizationInvitation sends an invitation to join an organization
+func (r *ResendEmailSender) SendOrganizationInvitation(email, inviterName, orgName, role, token string) error {
+	link := fmt.Sprintf("%s/invitations/accept?token=%s", r.frontendURL, token)
+	return r.send(email, fmt.Sprintf("You've been invited to %s on PayPerPlay", orgName), "organization_invitation", resendLayout(
+		"Organization Invitation",
+		resendParagraph("%s invited you to join the organization \"%s\" on PayPerPlay as %s.", inviterName, orgName, role)+
+			resendParagraph("Members of an organization share its Minecraft servers. Accept the invitation here:")+
+			resendButton(link, "Accept Invitation")+
+			resendParagraph("This link will expire in 7 days. If you don't have a PayPerPlay account yet, register with this email address first."),
+	))
+}
+
+// SendAdminAlert sends an operational alert to a platform admin
+func (r *ResendEmailSender) SendAdminAlert(email, subject, message string) error {
+	return r.send(email, "[PayPerPlay Alert] "+subject, "admin_alert", resendLayout(
+		html.EscapeString(subject),
+		"<pre style=\"white-space: pre-wrap;\">"+html.EscapeString(message)+"</pre>"+
+			resendParagraph("This is an automated alert from the PayPerPlay control plane."),
+	))
+}
+
+// SendSSOLinkConfirmation asks the holder of an existing account to confirm linking it to an organization's IdP
+func (r *ResendEmailSender) SendSSOLinkConfirmation(email, username, orgName, token string) error {
+	link := fmt.Sprintf("%s/sso/link?token=%s", r.frontendURL, token)
+	return r.send(email, "🔒 Confirm single sign-on for your PayPerPlay account", "sso_link_confirmation", resendLayout(
+		"Confirm Single Sign-On 🔒",
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("Someone signed in through the identity provider of the organization \"%s\" with your email address. To use single sign-on with your existing PayPerPlay account, confirm the link:", orgName)+
+			resendButton(link, "Link My Account")+
+			resendParagraph("This link will expire in 1 hour. If you didn't sign in through your organization, ignore this email; your account stays unchanged."),
+	))
+}
+
+// SendLifecycleNotice tells a server owner that their server will be archived or moved to cold storage
+func (r *ResendEmailSender) SendLifecycleNotice(email, username, serverID, serverName, transition string, at time.Time) error {
+	title, effect := lifecycleNoticeText(transition)
+	link := fmt.Sprintf("%s/servers/%s/settings", r.frontendURL, serverID)
+	return r.send(email, fmt.Sprintf("%s: %s", title, serverName), "lifecycle_notice", resendLayout(
+		html.EscapeString(title),
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("Your server \"%s\" has not been started for a while. On %s %s.", serverName, at.UTC().Format("Mon, 02 Jan 2006 15:04 MST"), effect)+
+			resendParagraph("Start the server before then to keep it as it is, or change when this happens in the server settings.")+
+			resendButton(link, "Lifecycle Settings"),
+	))
+}
+
+// SendFailoverNotice tells a server owner that their server's node failed and whether it was restarted elsewhere
+func (r *ResendEmailSender) SendFailoverNotice(email, username, serverID, serverName string, backupAt *time.Time) error {
+	title, text := failoverNoticeText(serverName, backupAt)
+	link := fmt.Sprintf("%s/servers/%s", r.frontendURL, serverID)
+	return r.send(email, fmt.Sprintf("%s: %s", title, serverName), "failover_notice", resendLayout(
+		html.EscapeString(title),
+		resendParagraph("Hi %s,", username)+
+			resendParagraph("%s", text)+
+			resendButton(link, "Open Server"),
+	))
+}
+
+// send delivers one HTML email through the Resend API
+func (r *ResendEmailSender) send(to, subject, emailType, htmlBody string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"from":    r.fromEmail,
+		"to":      []string{to},
+		"subject": subject,
+		"html":    htmlBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, resendAPIURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		logger.Error("Failed to send email via Resend", err, map[string]interface{}{
+			"to":   to,
+			"type": emailType,
+		})
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("resend API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		logger.Error("Failed to send email via Resend", err, map[string]interface{}{
+			"to":   to,
+			"type": emailType,
+		})
+		return err
+	}
+
+	logger.Info("✅ Email sent via Resend", map[string]interface{}{
+		"to":   to,
+		"type": emailType,
+	})
+	return nil
+}
+
+// resendLayout wraps email content in the common HTML frame (title is already escaped)
+func resendLayout(title, content string) string {
+	return `<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+    <div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+        <h2>` + title + `</h2>
+        ` + content + `
+        <p style="margin-top: 30px; font-size: 12px; color: #666;">© PayPerPlay - Pay only when you play</p>
+    </div>
+</body>
+</html>`
+}
+
+// resendParagraph formats a paragraph, escaping the arguments (format may contain markup)
+func resendParagraph(format string, args ...interface{}) string {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		escaped[i] = html.EscapeString(fmt.Sprint(arg))
+	}
+	return "<p>" + fmt.Sprintf(format, escaped...) + "</p>\n"
+}
+
+// resendButton renders a link button followed by the plain link for clients without HTML buttons
+func resendButton(link, label string) string {
+	link = html.EscapeString(link)
+	return fmt.Sprintf(`<a href="%s" style="display: inline-block; padding: 12px 24px; background-color: #4CAF50; color: white; text-decoration: none; border-radius: 4px; margin: 20px 0;">%s</a>
+<p>Or copy and paste this link into your browser:<br><code>%s</code></p>
+`, link, html.EscapeString(label), link)
+}
@@ -6,8 +6,6 @@ import (
 
 	"github.com/payperplay/hosting/pkg/logger"
 	"gorm.io/gorm"
-	// Uncomment when ready to use Resend:
-	// "github.com/resendlabs/resend-go"
 )
 
 // EmailSender defines the interface for sending emails
@@ -19,6 +17,12 @@ type EmailSender interface {
 	SendNewDeviceAlert(email, username, deviceName, ipAddress string, loginTime time.Time) error
 	SendAccountLockedAlert(email, username string, lockDuration time.Duration) error
 	SendPasswordChangedAlert(email, username string) error
+	SendOAuthRefreshFailedAlert(email, username, provider string) error
+	SendOrganizationInvitation(email, inviterName, orgName, role, token string) error
+	SendAdminAlert(email, subject, message string) error
+	SendSSOLinkConfirmation(email, username, orgName, token string) error
+	SendLifecycleNotice(email, username, serverID, serverName, transition string, at time.Time) error
+	SendFailoverNotice(email, username, serverID, serverName string, backupAt *time.Time) error
 }
 
 // EmailService manages email sending
@@ -70,13 +74,46 @@ func (s *EmailService) SendPasswordChangedAlert(email, username string) error {
 	return s.sender.SendPasswordChangedAlert(email, username)
 }
 
+// SendOAuthRefreshFailedAlert sends an alert when a linked provider's token can no longer be refreshed
+func (s *EmailService) SendOAuthRefreshFailedAlert(email, username, provider string) error {
+	return s.sender.SendOAuthRefreshFailedAlert(email, username, provider)
+}
+
+// SendOrganizationInvitation sends an invitation to join an organization
+func (s *EmailService) SendOrganizationInvitation(email, inviterName, orgName, role, token string) error {
+	return s.sender.SendOrganizationInvitation(email, inviterName, orgName, role, token)
+}
+
+// SendAdminAlert sends an operational alert to a platform admin
+func (s *EmailService) SendAdminAlert(email, subject, message string) error {
+	return s.sender.SendAdminAlert(email, subject, message)
+}
+
+// SendSSOLinkConfirmation asks the holder of an existing account to confirm linking it to an organization's IdP
+func (s *EmailService) SendSSOLinkConfirmation(email, username, orgName, token string) error {
+	return s.sender.SendSSOLinkConfirmation(email, username, orgName, token)
+}
+
+// SendLifecycleNotice tells a server owner that their server will be archived or moved to cold storage
+func (s *EmailService) SendLifecycleNotice(email, username, serverID, serverName, transition string, at time.Time) error {
+	return s.sender.SendLifecycleNotice(email, username, serverID, serverName, transition, at)
+}
+
+// SendFailoverNotice tells a server owner that their server's node failed and whether the server was
+// restarted elsewhere from the backup taken at backupAt (nil = it could not be restarted)
+func (s *EmailService) SendFailoverNotice(email, username, serverID, serverName string, backupAt *time.Time) error {
+	return s.sender.SendFailoverNotice(email, username, serverID, serverName, backupAt)
+}
+
 // ========================================
-// 🚧 MOCK EMAIL SENDER - REPLACE WITH REAL SMTP LATER
+// MOCK EMAIL SENDER - development without an email provider
 // ========================================
 
 // MockEmailSender simulates email sending by logging to console and database
+// Used when no RESEND_API_KEY is configured; see ResendEmailSender for production.
 type MockEmailSender struct {
-	db *gorm.DB
+	db          *gorm.DB
+	frontendURL string // Base of the links in emails (e.g., https://payperplay.host)
 }
 
 // MockEmail stores simulated emails in database for testing
@@ -90,15 +127,15 @@ type MockEmail struct {
 }
 
 // NewMockEmailSender creates a mock email sender
-func NewMockEmailSender(db *gorm.DB) *MockEmailSender {
+func NewMockEmailSender(db *gorm.DB, frontendURL string) *MockEmailSender {
 	// Auto-migrate mock emails table
 	db.AutoMigrate(&MockEmail{})
-	return &MockEmailSender{db: db}
+	return &MockEmailSender{db: db, frontendURL: frontendURL}
 }
 
 // SendVerificationEmail simulates sending verification email
 func (m *MockEmailSender) SendVerificationEmail(email, username, token string) error {
-	verificationLink := fmt.Sprintf("http://localhost:3000/verify-email?token=%s", token)
+	verificationLink := fmt.Sprintf("%s/verify-email?token=%s", m.frontendURL, token)
 
 	body := fmt.Sprintf(`
 Hi %s,
@@ -126,12 +163,10 @@ PayPerPlay Team
 		return err
 	}
 
-	// 🚧 TODO: Replace with real email service
 	logger.Info("📧 MOCK EMAIL SENT (Verification)", map[string]interface{}{
 		"to":      email,
 		"subject": mockEmail.Subject,
 		"link":    verificationLink,
-		"note":    "🚧 This is a simulated email. Replace MockEmailSender with SMTP implementation.",
 	})
 
 	return nil
@@ -139,7 +174,7 @@ PayPerPlay Team
 
 // SendPasswordResetEmail simulates sending password reset email
 func (m *MockEmailSender) SendPasswordResetEmail(email, username, token string) error {
-	resetLink := fmt.Sprintf("http://localhost:3000/reset-password?token=%s", token)
+	resetLink := fmt.Sprintf("%s/reset-password?token=%s", m.frontendURL, token)
 
 	body := fmt.Sprintf(`
 Hi %s,
@@ -167,12 +202,10 @@ PayPerPlay Team
 		return err
 	}
 
-	// 🚧 TODO: Replace with real email service
 	logger.Info("📧 MOCK EMAIL SENT (Password Reset)", map[string]interface{}{
 		"to":      email,
 		"subject": mockEmail.Subject,
 		"link":    resetLink,
-		"note":    "🚧 This is a simulated email. Replace MockEmailSender with SMTP implementation.",
 	})
 
 	return nil
@@ -209,11 +242,9 @@ PayPerPlay Team
 		return err
 	}
 
-	// 🚧 TODO: Replace with real email service
 	logger.Info("📧 MOCK EMAIL SENT (Welcome)", map[string]interface{}{
 		"to":      email,
 		"subject": mockEmail.Subject,
-		"note":    "🚧 This is a simulated email. Replace MockEmailSender with SMTP implementation.",
 	})
 
 	return nil
@@ -247,11 +278,9 @@ PayPerPlay Team
 		return err
 	}
 
-	// 🚧 TODO: Replace with real email service
 	logger.Info("📧 MOCK EMAIL SENT (Account Deleted)", map[string]interface{}{
 		"to":      email,
 		"subject": mockEmail.Subject,
-		"note":    "🚧 This is a simulated email. Replace MockEmailSender with SMTP implementation.",
 	})
 
 	return nil
@@ -292,12 +321,10 @@ PayPerPlay Security Team
 		return err
 	}
 
-	// 🚧 TODO: Replace with real email service
 	logger.Info("🔒 MOCK SECURITY ALERT (New Device)", map[string]interface{}{
-		"to":      email,
-		"device":  deviceName,
-		"ip":      ipAddress,
-		"note":    "🚧 This is a simulated security alert.",
+		"to":     email,
+		"device": deviceName,
+		"ip":     ipAddress,
 	})
 
 	return nil
@@ -336,11 +363,9 @@ PayPerPlay Security Team
 		return err
 	}
 
-	// 🚧 TODO: Replace with real email service
 	logger.Info("🔒 MOCK SECURITY ALERT (Account Locked)", map[string]interface{}{
 		"to":       email,
 		"duration": lockDuration.String(),
-		"note":     "🚧 This is a simulated security alert.",
 	})
 
 	return nil
@@ -377,225 +402,233 @@ PayPerPlay Security Team
 		return err
 	}
 
-	// 🚧 TODO: Replace with real email service
 	logger.Info("🔒 MOCK SECURITY ALERT (Password Changed)", map[string]interface{}{
-		"to":   email,
-		"note": "🚧 This is a simulated security alert.",
+		"to": email,
 	})
 
 	return nil
 }
 
-// ========================================
-// 🚀 RESEND EMAIL SENDER - PRODUCTION READY
-// ========================================
-// Uncomment this when you're ready to use Resend API
-// Steps:
-// 1. Sign up at https://resend.com (free tier: 3,000 emails/month)
-// 2. Get your API key from dashboard
-// 3. Add to .env: RESEND_API_KEY=re_xxxxx
-// 4. Run: go get github.com/resendlabs/resend-go
-// 5. Uncomment the code below
-// 6. In main.go, replace NewMockEmailSender with NewResendEmailSender
-
-/*
-type ResendEmailSender struct {
-	client *resend.Client
-	fromEmail string
-	frontendURL string
-}
-
-// NewResendEmailSender creates a production email sender using Resend API
-func NewResendEmailSender(apiKey, fromEmail, frontendURL string) *ResendEmailSender {
-	client := resend.NewClient(apiKey)
-	return &ResendEmailSender{
-		client: client,
-		fromEmail: fromEmail, // e.g., "PayPerPlay <noreply@payperplay.host>"
-		frontendURL: frontendURL, // e.g., "https://payperplay.host"
+// SendOAuthRefreshFailedAlert simulates sending an OAuth refresh failure alert
+func (m *MockEmailSender) SendOAuthRefreshFailedAlert(email, username, provider string) error {
+	body := fmt.Sprintf(`
+🔒 SECURITY ALERT: %s Connection Expired
+
+Hi %s,
+
+We could not renew the access to your linked %s account. The provider may have revoked
+our access, or the connection was removed from your %s settings.
+
+Your PayPerPlay account is not affected, but logging in with %s may fail until you
+sign in with it again. You can review your connected providers in your account settings.
+
+Best regards,
+PayPerPlay Security Team
+	`, provider, username, provider, provider, provider)
+
+	mockEmail := &MockEmail{
+		To:      email,
+		Subject: fmt.Sprintf("🔒 Your %s connection needs attention", provider),
+		Body:    body,
+		Type:    "security_alert_oauth_refresh_failed",
 	}
+
+	if err := m.db.Create(mockEmail).Error; err != nil {
+		return err
+	}
+
+	logger.Info("🔒 MOCK SECURITY ALERT (OAuth Refresh Failed)", map[string]interface{}{
+		"to":       email,
+		"provider": provider,
+	})
+
+	return nil
 }
 
-// SendVerificationEmail sends verification email via Resend
-func (r *ResendEmailSender) SendVerificationEmail(email, username, token string) error {
-	verificationLink := fmt.Sprintf("%s/verify-email?token=%s", r.frontendURL, token)
-
-	htmlBody := fmt.Sprintf(`
-<!DOCTYPE html>
-<html>
-<head>
-    <style>
-        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
-        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
-        .button {
-            display: inline-block;
-            padding: 12px 24px;
-            background-color: #4CAF50;
-            color: white;
-            text-decoration: none;
-            border-radius: 4px;
-            margin: 20px 0;
-        }
-        .footer { margin-top: 30px; font-size: 12px; color: #666; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h2>Welcome to PayPerPlay! 🎮</h2>
-        <p>Hi %s,</p>
-        <p>Thanks for signing up! Please verify your email address to get started:</p>
-        <a href="%s" class="button">Verify Email Address</a>
-        <p>Or copy and paste this link into your browser:<br>
-        <code>%s</code></p>
-        <p>This link will expire in 24 hours.</p>
-        <div class="footer">
-            <p>If you didn't create an account, you can safely ignore this email.</p>
-            <p>© 2025 PayPerPlay - Pay only when you play</p>
-        </div>
-    </div>
-</body>
-</html>
-	`, username, verificationLink, verificationLink)
-
-	params := &resend.SendEmailRequest{
-		From:    r.fromEmail,
-		To:      []string{email},
-		Subject: "Verify your PayPerPlay account",
-		Html:    htmlBody,
+// SendOrganizationInvitation simulates sending an organization invitation
+func (m *MockEmailSender) SendOrganizationInvitation(email, inviterName, orgName, role, token string) error {
+	invitationLink := fmt.Sprintf("%s/invitations/accept?token=%s", m.frontendURL, token)
+
+	body := fmt.Sprintf(`
+Hi,
+
+%s invited you to join the organization "%s" on PayPerPlay as %s.
+
+Members of an organization share its Minecraft servers. Accept the invitation here:
+
+%s
+
+This link will expire in 7 days. If you don't have a PayPerPlay account yet, register
+with this email address first.
+
+Best regards,
+PayPerPlay Team
+	`, inviterName, orgName, role, invitationLink)
+
+	mockEmail := &MockEmail{
+		To:      email,
+		Subject: fmt.Sprintf("You've been invited to %s on PayPerPlay", orgName),
+		Body:    body,
+		Type:    "organization_invitation",
 	}
 
-	_, err := r.client.Emails.Send(params)
-	if err != nil {
-		logger.Error("Failed to send verification email via Resend", err, map[string]interface{}{
-			"to": email,
-		})
+	if err := m.db.Create(mockEmail).Error; err != nil {
 		return err
 	}
 
-	logger.Info("✅ Verification email sent via Resend", map[string]interface{}{
-		"to": email,
+	logger.Info("📧 MOCK EMAIL SENT (Organization Invitation)", map[string]interface{}{
+		"to":           email,
+		"organization": orgName,
+		"role":         role,
+		"link":         invitationLink,
 	})
+
 	return nil
 }
 
-// SendPasswordResetEmail sends password reset email via Resend
-func (r *ResendEmailSender) SendPasswordResetEmail(email, username, token string) error {
-	resetLink := fmt.Sprintf("%s/reset-password?token=%s", r.frontendURL, token)
-
-	htmlBody := fmt.Sprintf(`
-<!DOCTYPE html>
-<html>
-<head>
-    <style>
-        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
-        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
-        .button {
-            display: inline-block;
-            padding: 12px 24px;
-            background-color: #FF9800;
-            color: white;
-            text-decoration: none;
-            border-radius: 4px;
-            margin: 20px 0;
-        }
-        .warning { background-color: #fff3cd; padding: 12px; border-left: 4px solid #ff9800; margin: 20px 0; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h2>Reset Your Password 🔑</h2>
-        <p>Hi %s,</p>
-        <p>We received a request to reset your password. Click the button below to set a new password:</p>
-        <a href="%s" class="button">Reset Password</a>
-        <p>Or copy and paste this link into your browser:<br>
-        <code>%s</code></p>
-        <div class="warning">
-            ⚠️ This link will expire in 1 hour.
-        </div>
-        <p>If you didn't request a password reset, please ignore this email. Your password will remain unchanged.</p>
-        <p>© 2025 PayPerPlay</p>
-    </div>
-</body>
-</html>
-	`, username, resetLink, resetLink)
-
-	params := &resend.SendEmailRequest{
-		From:    r.fromEmail,
-		To:      []string{email},
-		Subject: "Reset your PayPerPlay password",
-		Html:    htmlBody,
+// SendAdminAlert simulates sending an operational alert to an admin
+func (m *MockEmailSender) SendAdminAlert(email, subject, message string) error {
+	body := fmt.Sprintf(`
+Hi,
+
+%s
+
+This is an automated alert from the PayPerPlay control plane.
+
+PayPerPlay Monitoring
+	`, message)
+
+	mockEmail := &MockEmail{
+		To:      email,
+		Subject: "[PayPerPlay Alert] " + subject,
+		Body:    body,
+		Type:    "admin_alert",
 	}
 
-	_, err := r.client.Emails.Send(params)
-	return err
-}
-
-// SendWelcomeEmail sends welcome email via Resend
-func (r *ResendEmailSender) SendWelcomeEmail(email, username string) error {
-	htmlBody := fmt.Sprintf(`
-<!DOCTYPE html>
-<html>
-<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
-    <div style="max-width: 600px; margin: 0 auto; padding: 20px;">
-        <h2>Welcome to PayPerPlay! 🎉</h2>
-        <p>Hi %s,</p>
-        <p>Your account has been successfully verified! You're now ready to create your first Minecraft server.</p>
-        <h3>Getting Started:</h3>
-        <ol>
-            <li>Create a new server from our templates</li>
-            <li>Configure your server settings</li>
-            <li>Invite your friends and start playing!</li>
-        </ol>
-        <p>Remember: You only pay when your server is running. Stopped servers cost nothing!</p>
-        <a href="%s/dashboard" style="display: inline-block; padding: 12px 24px; background-color: #4CAF50; color: white; text-decoration: none; border-radius: 4px; margin: 20px 0;">
-            Go to Dashboard
-        </a>
-        <p>Need help? Join our <a href="https://discord.gg/payperplay">Discord community</a> or check the <a href="%s/docs">documentation</a>.</p>
-        <p>Happy gaming! 🎮<br>The PayPerPlay Team</p>
-    </div>
-</body>
-</html>
-	`, username, r.frontendURL, r.frontendURL)
-
-	params := &resend.SendEmailRequest{
-		From:    r.fromEmail,
-		To:      []string{email},
-		Subject: "Welcome to PayPerPlay! 🎉",
-		Html:    htmlBody,
+	if err := m.db.Create(mockEmail).Error; err != nil {
+		return err
 	}
 
-	_, err := r.client.Emails.Send(params)
-	return err
-}
-
-// SendAccountDeletedEmail sends account deletion confirmation via Resend
-func (r *ResendEmailSender) SendAccountDeletedEmail(email, username string) error {
-	htmlBody := fmt.Sprintf(`
-<!DOCTYPE html>
-<html>
-<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
-    <div style="max-width: 600px; margin: 0 auto; padding: 20px;">
-        <h2>Account Deleted</h2>
-        <p>Hi %s,</p>
-        <p>Your PayPerPlay account has been successfully deleted.</p>
-        <p>All your servers and data have been permanently removed as per your request.</p>
-        <div style="background-color: #f8d7da; padding: 12px; border-left: 4px solid #dc3545; margin: 20px 0;">
-            ⚠️ If you didn't request this deletion, please contact our support team immediately at support@payperplay.host
-        </div>
-        <p>We're sorry to see you go. If you change your mind, you're always welcome to create a new account.</p>
-        <p>Best regards,<br>The PayPerPlay Team</p>
-    </div>
-</body>
-</html>
-	`, username)
+	logger.Info("📧 MOCK EMAIL SENT (Admin Alert)", map[string]interface{}{
+		"to":      email,
+		"subject": subject,
+	})
 
-	params := &resend.SendEmailRequest{
-		From:    r.fromEmail,
-		To:      []string{email},
-		Subject: "Your PayPerPlay account has been deleted",
-		Html:    htmlBody,
+	return nil
+}
+
+// SendSSOLinkConfirmation simulates sending an SSO account link confirmation
+func (m *MockEmailSender) SendSSOLinkConfirmation(email, username, orgName, token string) error {
+	confirmLink := fmt.Sprintf("%s/sso/link?token=%s", m.frontendURL, token)
+
+	body := fmt.Sprintf(`
+🔒 Confirm single sign-on for your account
+
+Hi %s,
+
+Someone signed in through the identity provider of the organization "%s" with your email address.
+To use single sign-on with your existing PayPerPlay account, confirm the link here:
+
+%s
+
+This link will expire in 1 hour. If you didn't sign in through your organization, ignore this email;
+your account stays unchanged.
+
+Best regards,
+PayPerPlay Security Team
+	`, username, orgName, confirmLink)
+
+	mockEmail := &MockEmail{
+		To:      email,
+		Subject: "🔒 Confirm single sign-on for your PayPerPlay account",
+		Body:    body,
+		Type:    "sso_link_confirmation",
 	}
 
-	_, err := r.client.Emails.Send(params)
-	return err
+	if err := m.db.Create(mockEmail).Error; err != nil {
+		return err
+	}
+
+	logger.Info("🔒 MOCK SECURITY ALERT (SSO Link Confirmation)", map[string]interface{}{
+		"to":           email,
+		"organization": orgName,
+		"link":         confirmLink,
+	})
+
+	return nil
+}
+
+// SendLifecycleNotice simulates sending a lifecycle transition notice
+func (m *MockEmailSender) SendLifecycleNotice(email, username, serverID, serverName, transition string, at time.Time) error {
+	title, effect := lifecycleNoticeText(transition)
+	settingsLink := fmt.Sprintf("%s/servers/%s/settings", m.frontendURL, serverID)
+
+	body := fmt.Sprintf(`
+Hi %s,
+
+Your server "%s" has not been started for a while. On %s %s.
+
+Start the server before then to keep it as it is, or change when this happens in the server settings:
+
+%s
+
+Best regards,
+PayPerPlay Team
+	`, username, serverName, at.UTC().Format("Mon, 02 Jan 2006 15:04 MST"), effect, settingsLink)
+
+	mockEmail := &MockEmail{
+		To:      email,
+		Subject: fmt.Sprintf("%s: %s", title, serverName),
+		Body:    body,
+		Type:    "lifecycle_notice",
+	}
+
+	if err := m.db.Create(mockEmail).Error; err != nil {
+		return err
+	}
+
+	logger.Info("📧 MOCK EMAIL SENT (Lifecycle Notice)", map[string]interface{}{
+		"to":         email,
+		"server_id":  serverID,
+		"transition": transition,
+		"at":         at,
+	})
+
+	return nil
+}
+
+// SendFailoverNotice simulates sending a failover notice
+func (m *MockEmailSender) SendFailoverNotice(email, username, serverID, serverName string, backupAt *time.Time) error {
+	title, text := failoverNoticeText(serverName, backupAt)
+	serverLink := fmt.Sprintf("%s/servers/%s", m.frontendURL, serverID)
+
+	body := fmt.Sprintf(`
+Hi %s,
+
+%s
+
+%s
+
+Best regards,
+PayPerPlay Team
+	`, username, text, serverLink)
+
+	mockEmail := &MockEmail{
+		To:      email,
+		Subject: fmt.Sprintf("%s: %s", title, serverName),
+		Body:    body,
+		Type:    "failover_notice",
+	}
+
+	if err := m.db.Create(mockEmail).Error; err != nil {
+		return err
+	}
+
+	logger.Info("📧 MOCK EMAIL SENT (Failover Notice)", map[string]interface{}{
+		"to":        email,
+		"server_id": serverID,
+		"restored":  backupAt != nil,
+	})
+
+	return nil
 }
-*/
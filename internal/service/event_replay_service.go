@@ -0,0 +1,280 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	replayDefaultLimit = 10000
+	replayMaxLimit     = 100000
+	replayMaxErrors    = 20 // Error messages kept in a replay result
+)
+
+// Event replay errors
+var (
+	ErrReplayUnknownSource = errors.New("unknown event source")
+	ErrReplayUnknownTarget = errors.New("unknown replay target")
+	ErrReplayInvalidRange  = errors.New("invalid time range (start is required and must be before end)")
+	ErrReplayInvalidType   = errors.New("event type not supported by the replay target")
+	ErrReplayInProgress    = errors.New("another replay is in progress")
+)
+
+// ReplayTarget receives replayed events
+// Handle returns whether the event was (or, in a dry run, would be) applied; events the target
+// already reflects return false so replaying a range twice doesn't apply it twice.
+type ReplayTarget struct {
+	Name        string                                              `json:"name"`
+	Description string                                              `json:"description"`
+	Types       []events.EventType                                  `json:"types"`
+	Handle      func(event events.Event, dryRun bool) (bool, error) `json:"-"`
+}
+
+// ReplayRequest selects stored events and the target to replay them into
+type ReplayRequest struct {
+	Source    string             `json:"source"` // "postgres" or "influxdb"
+	Target    string             `json:"target"`
+	Types     []events.EventType `json:"types"` // Empty = all types of the target
+	ServerID  string             `json:"server_id"`
+	StartTime time.Time          `json:"start"`
+	EndTime   time.Time          `json:"end"` // Zero = now
+	Limit     int                `json:"limit"`
+	DryRun    bool               `json:"dry_run"`
+}
+
+// ReplayResult summarizes a replay
+type ReplayResult struct {
+	Source      string         `json:"source"`
+	Target      string         `json:"target"`
+	DryRun      bool           `json:"dry_run"`
+	StartTime   time.Time      `json:"start"`
+	EndTime     time.Time      `json:"end"`
+	Matched     int            `json:"matched"`                // Stored events matching the filters
+	Applied     int            `json:"applied"`                // Applied (or would be applied in a dry run)
+	Skipped     int            `json:"skipped"`                // Already reflected by the target
+	Failed      int            `json:"failed"`                 // Handler errors
+	ByType      map[string]int `json:"by_type"`                // Matched events per type
+	Truncated   bool           `json:"truncated"`              // Limit reached, more events match the range
+	TruncatedAt *time.Time     `json:"truncated_at,omitempty"` // Last replayed event if truncated: replay from here for the rest
+	Errors      []string       `json:"errors,omitempty"`
+	DurationMs  int64          `json:"duration_ms"`
+}
+
+// EventReplayService replays stored events (PostgreSQL/InfluxDB) into handlers, e.g. to rebuild
+// billing after a bug or re-send node events to the dashboard. One replay runs at a time.
+type EventReplayService struct {
+	sources map[string]events.EventStorage
+	targets map[string]ReplayTarget
+	running sync.Mutex
+}
+
+// NewEventReplayService creates a new event replay service
+func NewEventReplayService() *EventReplayService {
+	return &EventReplayService{
+		sources: make(map[string]events.EventStorage),
+		targets: make(map[string]ReplayTarget),
+	}
+}
+
+// AddSource registers an event storage to replay from
+func (s *EventReplayService) AddSource(name string, storage events.EventStorage) {
+	s.sources[name] = storage
+}
+
+// AddTarget registers a replay target
+func (s *EventReplayService) AddTarget(target ReplayTarget) {
+	s.targets[target.Name] = target
+}
+
+// Sources returns the names of the registered sources
+func (s *EventReplayService) Sources() []string {
+	names := make([]string, 0, len(s.sources))
+	for name := range s.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Targets returns the registered targets
+func (s *EventReplayService) Targets() []ReplayTarget {
+	targets := make([]ReplayTarget, 0, len(s.targets))
+	for _, target := range s.targets {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+	return targets
+}
+
+// Replay queries the source and hands matching events to the target, oldest first
+// A truncated replay covers the oldest events of the range; replaying again from TruncatedAt
+// passes the events of that instant a second time, which targets skip as already reflected.
+func (s *EventReplayService) Replay(req ReplayRequest) (*ReplayResult, error) {
+	source, ok := s.sources[req.Source]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrReplayUnknownSource, req.Source)
+	}
+	target, ok := s.targets[req.Target]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrReplayUnknownTarget, req.Target)
+	}
+
+	if req.EndTime.IsZero() {
+		req.EndTime = time.Now()
+	}
+	if req.StartTime.IsZero() || !req.StartTime.Before(req.EndTime) {
+		return nil, ErrReplayInvalidRange
+	}
+	if req.Limit <= 0 {
+		req.Limit = replayDefaultLimit
+	}
+	if req.Limit > replayMaxLimit {
+		req.Limit = replayMaxLimit
+	}
+
+	types := target.Types
+	if len(req.Types) > 0 {
+		for _, eventType := range req.Types {
+			if !containsEventType(target.Types, eventType) {
+				return nil, fmt.Errorf("%w: %s", ErrReplayInvalidType, eventType)
+			}
+		}
+		types = req.Types
+	}
+
+	if !s.running.TryLock() {
+		return nil, ErrReplayInProgress
+	}
+	defer s.running.Unlock()
+
+	started := time.Now()
+	// Oldest first, so a truncated replay covers the start of the range without gaps and
+	// handlers see the original order (start before stop); one extra event tells whether more exist
+	stored, err := source.Query(events.EventFilters{
+		Types:     types,
+		ServerID:  req.ServerID,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Limit:     req.Limit + 1,
+		Ascending: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", req.Source, err)
+	}
+	sort.SliceStable(stored, func(i, j int) bool { return stored[i].Timestamp.Before(stored[j].Timestamp) })
+
+	truncated := len(stored) > req.Limit
+	if truncated {
+		stored = stored[:req.Limit]
+	}
+
+	result := &ReplayResult{
+		Source:    req.Source,
+		Target:    req.Target,
+		DryRun:    req.DryRun,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		Matched:   len(stored),
+		ByType:    make(map[string]int),
+		Truncated: truncated,
+	}
+	if truncated {
+		last := stored[len(stored)-1].Timestamp
+		result.TruncatedAt = &last
+	}
+
+	logger.Info("EVENT-REPLAY: Starting replay", map[string]interface{}{
+		"source":    req.Source,
+		"target":    req.Target,
+		"types":     types,
+		"server_id": req.ServerID,
+		"start":     req.StartTime,
+		"end":       req.EndTime,
+		"matched":   len(stored),
+		"dry_run":   req.DryRun,
+	})
+
+	for _, event := range stored {
+		result.ByType[string(event.Type)]++
+
+		applied, err := target.Handle(event, req.DryRun)
+		switch {
+		case err != nil:
+			result.Failed++
+			if len(result.Errors) < replayMaxErrors {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s (%s): %v", event.ID, event.Type, err))
+			}
+		case applied:
+			result.Applied++
+		default:
+			result.Skipped++
+		}
+	}
+	result.DurationMs = time.Since(started).Milliseconds()
+
+	logger.Info("EVENT-REPLAY: Replay finished", map[string]interface{}{
+		"source":       req.Source,
+		"target":       req.Target,
+		"dry_run":      req.DryRun,
+		"applied":      result.Applied,
+		"skipped":      result.Skipped,
+		"failed":       result.Failed,
+		"truncated":    result.Truncated,
+		"truncated_at": result.TruncatedAt,
+		"duration_ms":  result.DurationMs,
+	})
+
+	return result, nil
+}
+
+// BillingReplayTarget rebuilds usage sessions and billing events from server start/stop and phase events
+func BillingReplayTarget(billing *BillingService) ReplayTarget {
+	return ReplayTarget{
+		Name:        "billing",
+		Description: "Re-record billing events and usage sessions (events already billed are skipped)",
+		Types:       []events.EventType{events.EventServerStarted, events.EventServerStopped, events.EventBillingPhaseChanged},
+		Handle:      billing.ReplayEvent,
+	}
+}
+
+// DashboardReplayTarget re-sends node and scaling events to connected admin dashboards
+func DashboardReplayTarget() ReplayTarget {
+	return ReplayTarget{
+		Name:        "dashboard",
+		Description: "Re-emit node and scaling events to the admin dashboard WebSocket",
+		Types:       []events.EventType{events.EventNodeAdded, events.EventNodeRemoved, events.EventNodeHealthChanged, events.EventScalingTriggered},
+		Handle: func(event events.Event, dryRun bool) (bool, error) {
+			if events.DashboardEventPublisher == nil {
+				return false, errors.New("dashboard WebSocket not initialized")
+			}
+			if dryRun {
+				return true, nil
+			}
+
+			data := make(map[string]interface{}, len(event.Data)+2)
+			for key, value := range event.Data {
+				data[key] = value
+			}
+			data["original_timestamp"] = event.Timestamp
+			data["replayed"] = true
+			events.DashboardEventPublisher.PublishEvent(string(event.Type), data)
+			return true, nil
+		},
+	}
+}
+
+// containsEventType reports whether eventType is in types
+func containsEventType(types []events.EventType, eventType events.EventType) bool {
+	for _, t := range types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
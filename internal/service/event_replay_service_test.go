@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+)
+
+// fakeEventStorage answers queries like the real storages: time range, order and limit
+type fakeEventStorage struct {
+	events  []events.Event
+	queries []events.EventFilters
+}
+
+func (f *fakeEventStorage) Store(event events.Event) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeEventStorage) Query(filters events.EventFilters) ([]events.Event, error) {
+	f.queries = append(f.queries, filters)
+	var matched []events.Event
+	for _, event := range f.events {
+		if event.Timestamp.Before(filters.StartTime) || event.Timestamp.After(filters.EndTime) {
+			continue
+		}
+		matched = append(matched, event)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if filters.Ascending {
+			return matched[i].Timestamp.Before(matched[j].Timestamp)
+		}
+		return matched[i].Timestamp.After(matched[j].Timestamp)
+	})
+	if filters.Limit > 0 && len(matched) > filters.Limit {
+		matched = matched[:filters.Limit]
+	}
+	return matched, nil
+}
+
+func TestEventReplay(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	storage := &fakeEventStorage{}
+	for i := 0; i < 5; i++ {
+		storage.Store(events.Event{
+			ID:        fmt.Sprintf("evt-%d", i),
+			Type:      events.EventServerStarted,
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	tests := []struct {
+		name          string
+		limit         int
+		wantIDs       []string
+		wantTruncated bool
+	}{
+		{"all events", 0, []string{"evt-0", "evt-1", "evt-2", "evt-3", "evt-4"}, false},
+		{"limit equals matches", 5, []string{"evt-0", "evt-1", "evt-2", "evt-3", "evt-4"}, false},
+		{"truncated keeps the oldest", 3, []string{"evt-0", "evt-1", "evt-2"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var replayed []string
+			s := NewEventReplayService()
+			s.AddSource("postgres", storage)
+			s.AddTarget(ReplayTarget{
+				Name:  "record",
+				Types: []events.EventType{events.EventServerStarted},
+				Handle: func(event events.Event, dryRun bool) (bool, error) {
+					replayed = append(replayed, event.ID)
+					return true, nil
+				},
+			})
+
+			result, err := s.Replay(ReplayRequest{
+				Source:    "postgres",
+				Target:    "record",
+				StartTime: start.Add(-time.Hour),
+				EndTime:   start.Add(time.Hour),
+				Limit:     tt.limit,
+			})
+			if err != nil {
+				t.Fatalf("Replay() error = %v", err)
+			}
+
+			if fmt.Sprint(replayed) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("replayed %v, want %v", replayed, tt.wantIDs)
+			}
+			if result.Matched != len(tt.wantIDs) || result.Applied != len(tt.wantIDs) {
+				t.Errorf("matched %d, applied %d, want %d", result.Matched, result.Applied, len(tt.wantIDs))
+			}
+			if result.Truncated != tt.wantTruncated {
+				t.Errorf("Truncated = %v, want %v", result.Truncated, tt.wantTruncated)
+			}
+			switch {
+			case !tt.wantTruncated && result.TruncatedAt != nil:
+				t.Errorf("TruncatedAt = %v, want nil", result.TruncatedAt)
+			case tt.wantTruncated && (result.TruncatedAt == nil || !result.TruncatedAt.Equal(start.Add(2*time.Minute))):
+				t.Errorf("TruncatedAt = %v, want the last replayed event %v", result.TruncatedAt, start.Add(2*time.Minute))
+			}
+		})
+	}
+
+	last := storage.queries[len(storage.queries)-1]
+	if !last.Ascending || last.Limit != 4 {
+		t.Errorf("query = ascending %v, limit %d; want oldest first with one extra event", last.Ascending, last.Limit)
+	}
+}
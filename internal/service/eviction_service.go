@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// minLiveEvacuationTime is the time before the termination a live migration needs at least
+// With less time left the world is not transferred, the server is restarted from its latest backup.
+const minLiveEvacuationTime = 2 * time.Minute
+
+// EvictionService moves the servers off interruptible nodes the provider is about to terminate
+// The health checker hands over the servers of a node with a termination notice. Each running server is
+// migrated live to another node; if there is too little time left or the migration fails, the server is
+// stopped and restarted on another node from its latest backup (owners are emailed, like on a failover).
+type EvictionService struct {
+	cond       *conductor.Conductor
+	serverRepo *repository.ServerRepository
+	backupRepo *repository.BackupRepository
+	userRepo   *repository.UserRepository
+	minecraft  *MinecraftService
+	migrations *MigrationService
+	email      *EmailService
+	audit      *AuditService
+
+	mu      sync.Mutex
+	pending map[string]bool // Node ID -> eviction in progress
+}
+
+// NewEvictionService creates a new eviction service
+func NewEvictionService(
+	cond *conductor.Conductor,
+	serverRepo *repository.ServerRepository,
+	backupRepo *repository.BackupRepository,
+	userRepo *repository.UserRepository,
+	minecraft *MinecraftService,
+	migrations *MigrationService,
+	email *EmailService,
+	auditService *AuditService,
+) *EvictionService {
+	return &EvictionService{
+		cond:       cond,
+		serverRepo: serverRepo,
+		backupRepo: backupRepo,
+		userRepo:   userRepo,
+		minecraft:  minecraft,
+		migrations: migrations,
+		email:      email,
+		audit:      auditService,
+		pending:    make(map[string]bool),
+	}
+}
+
+// HandleNodeEviction moves the servers off a node with a termination notice (conductor.EvictionHandler)
+// Returns right away; the servers are moved concurrently, a node already being evacuated is not handled twice.
+func (s *EvictionService) HandleNodeEviction(nodeID string, serverIDs []string, terminatesAt *time.Time) {
+	s.mu.Lock()
+	if s.pending[nodeID] {
+		s.mu.Unlock()
+		return
+	}
+	s.pending[nodeID] = true
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.pending, nodeID)
+			s.mu.Unlock()
+		}()
+
+		var wg sync.WaitGroup
+		for _, serverID := range serverIDs {
+			wg.Add(1)
+			go func(serverID string) {
+				defer wg.Done()
+				s.evictServer(nodeID, serverID, terminatesAt)
+			}(serverID)
+		}
+		wg.Wait()
+
+		logger.Info("EVICTION: Node evacuated", map[string]interface{}{
+			"node_id": nodeID,
+			"servers": len(serverIDs),
+		})
+	}()
+}
+
+// evictServer migrates a server off the node, or restarts it elsewhere from its latest backup
+func (s *EvictionService) evictServer(nodeID, serverID string, terminatesAt *time.Time) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil || !failoverCandidate(server, nodeID) {
+		// Stopped servers keep nothing on the node that a backup doesn't have
+		return
+	}
+
+	entry := audit.AuditEntry{
+		Action:       audit.ActionNodeEviction,
+		NodeID:       nodeID,
+		Reason:       "interruptible node is terminated by the provider",
+		DecisionBy:   "eviction",
+		ResourceType: "server",
+		ResourceID:   server.ID,
+		Before:       map[string]interface{}{"node_id": nodeID, "status": server.Status},
+	}
+
+	if evictLive(terminatesAt, time.Now()) {
+		migration, err := s.migrations.Evacuate(server, nodeID)
+		if err == nil {
+			logger.Info("EVICTION: Server migrated off the node", map[string]interface{}{
+				"server_id":    server.ID,
+				"from_node":    nodeID,
+				"to_node":      migration.ToNodeID,
+				"operation_id": migration.ID,
+			})
+			entry.Result = "success"
+			entry.After = map[string]interface{}{"node_id": migration.ToNodeID, "operation_id": migration.ID, "method": "migration"}
+			s.audit.Record(entry)
+			return
+		}
+		logger.Warn("EVICTION: Live migration failed, restarting the server from its latest backup", map[string]interface{}{
+			"server_id": server.ID,
+			"node_id":   nodeID,
+			"error":     err.Error(),
+		})
+	}
+
+	migration, backup, err := s.restart(nodeID, server)
+	if err != nil {
+		logger.Error("EVICTION: Server could not be moved off the node", err, map[string]interface{}{
+			"server_id": server.ID,
+			"node_id":   nodeID,
+		})
+		entry.Result, entry.Error = "failed", err.Error()
+		s.audit.Record(entry)
+		s.notifyOwner(server, nil)
+		return
+	}
+
+	logger.Info("EVICTION: Server restarted on another node from its latest backup", map[string]interface{}{
+		"server_id":    server.ID,
+		"from_node":    nodeID,
+		"to_node":      migration.ToNodeID,
+		"backup_id":    backup.ID,
+		"operation_id": migration.ID,
+	})
+	entry.Result = "success"
+	entry.After = map[string]interface{}{
+		"node_id":      migration.ToNodeID,
+		"backup_id":    backup.ID,
+		"backup_at":    backup.CreatedAt,
+		"operation_id": migration.ID,
+		"method":       "backup",
+	}
+	s.audit.Record(entry)
+	s.notifyOwner(server, &backup.CreatedAt)
+}
+
+// restart stops a server on the node and restores its latest completed backup onto another node
+func (s *EvictionService) restart(nodeID string, server *models.MinecraftServer) (*models.Migration, *models.Backup, error) {
+	backup, err := s.backupRepo.FindLatestBackupForServer(server.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, fmt.Errorf("server has no completed backup")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find latest backup: %w", err)
+	}
+
+	if err := s.minecraft.StopServer(server.ID, "eviction"); err != nil {
+		// Already stopped (e.g. with the node) is fine, the backup is restored either way
+		logger.Warn("EVICTION: Failed to stop server before restoring it", map[string]interface{}{
+			"server_id": server.ID,
+			"error":     err.Error(),
+		})
+	}
+
+	migration, err := s.migrations.Failover(server, nodeID, backup)
+	if err != nil {
+		return nil, nil, err
+	}
+	return migration, backup, nil
+}
+
+// notifyOwner emails the owner whether the server was restored from the backup of backupAt (nil = not restored)
+func (s *EvictionService) notifyOwner(server *models.MinecraftServer, backupAt *time.Time) {
+	owner, err := s.userRepo.FindByID(server.OwnerID)
+	if err != nil {
+		logger.Warn("EVICTION: Owner of server not found, skipping notice", map[string]interface{}{
+			"server_id": server.ID,
+			"owner_id":  server.OwnerID,
+		})
+		return
+	}
+	if err := s.email.SendFailoverNotice(owner.Email, owner.Username, server.ID, server.Name, backupAt); err != nil {
+		logger.Error("EVICTION: Failed to send failover notice", err, map[string]interface{}{
+			"server_id": server.ID,
+		})
+	}
+}
+
+// evictLive reports whether there is enough time left before the termination for a live migration
+// Without a termination time (the provider didn't say) a live migration is tried.
+func evictLive(terminatesAt *time.Time, now time.Time) bool {
+	return terminatesAt == nil || terminatesAt.Sub(now) >= minLiveEvacuationTime
+}
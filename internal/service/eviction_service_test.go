@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvictLive(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		terminatesAt := now.Add(d)
+		return &terminatesAt
+	}
+
+	tests := map[string]struct {
+		terminatesAt *time.Time
+		want         bool
+	}{
+		"no termination time":    {nil, true},
+		"plenty of time":         {at(30 * time.Minute), true},
+		"just enough time":       {at(minLiveEvacuationTime), true},
+		"too little time":        {at(minLiveEvacuationTime - time.Second), false},
+		"termination has passed": {at(-time.Minute), false},
+	}
+	for name, tt := range tests {
+		if got := evictLive(tt.terminatesAt, now); got != tt.want {
+			t.Errorf("%s: evictLive() = %v, want %v", name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// defaultFailoverConfirmationWindow is how long a node must stay unhealthy before its servers are failed over
+const defaultFailoverConfirmationWindow = 3 * time.Minute
+
+// FailoverService restores the servers of a failed worker node onto healthy nodes
+// The health checker hands over the servers of a node that turned unhealthy. If the node is still
+// unhealthy after the confirmation window, each server is marked as crashed (billing ends), its latest
+// backup is restored onto a healthy node by a failover migration and it is started there. Owners get an
+// email either way; players that were online get the recovery service's "server is back" message.
+type FailoverService struct {
+	cond       *conductor.Conductor
+	serverRepo *repository.ServerRepository
+	backupRepo *repository.BackupRepository
+	userRepo   *repository.UserRepository
+	minecraft  *MinecraftService
+	migrations *MigrationService
+	recovery   *RecoveryService
+	email      *EmailService
+	audit      *AuditService
+	webhooks   *WebhookService // Optional: posts the crash to the server's Discord/Slack webhook
+	window     time.Duration
+
+	mu       sync.Mutex
+	pending  map[string]bool // Node ID -> failover waiting for its confirmation window
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewFailoverService creates a new failover service
+func NewFailoverService(
+	cond *conductor.Conductor,
+	serverRepo *repository.ServerRepository,
+	backupRepo *repository.BackupRepository,
+	userRepo *repository.UserRepository,
+	minecraft *MinecraftService,
+	migrations *MigrationService,
+	recovery *RecoveryService,
+	email *EmailService,
+	auditService *AuditService,
+	cfg *config.Config,
+) *FailoverService {
+	return &FailoverService{
+		cond:       cond,
+		serverRepo: serverRepo,
+		backupRepo: backupRepo,
+		userRepo:   userRepo,
+		minecraft:  minecraft,
+		migrations: migrations,
+		recovery:   recovery,
+		email:      email,
+		audit:      auditService,
+		window:     failoverConfirmationWindow(cfg.FailoverConfirmationWindow),
+		pending:    make(map[string]bool),
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// SetWebhookService enables Discord/Slack crash notifications of failed-over servers
+func (s *FailoverService) SetWebhookService(webhooks *WebhookService) {
+	s.webhooks = webhooks
+}
+
+// Stop abandons failovers still waiting for their confirmation window
+func (s *FailoverService) Stop() {
+	s.stopOnce.Do(func() { close(s.stopChan) })
+}
+
+// HandleNodeFailover takes over the servers of a node that turned unhealthy (conductor.FailoverHandler)
+// Returns right away; a node already waiting for its confirmation window is not handled twice.
+func (s *FailoverService) HandleNodeFailover(nodeID string, serverIDs []string) {
+	s.mu.Lock()
+	if s.pending[nodeID] {
+		s.mu.Unlock()
+		return
+	}
+	s.pending[nodeID] = true
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.pending, nodeID)
+			s.mu.Unlock()
+		}()
+		s.failoverNode(nodeID, serverIDs)
+	}()
+}
+
+// failoverNode waits for the confirmation window and fails the servers over one by one
+func (s *FailoverService) failoverNode(nodeID string, serverIDs []string) {
+	servers := make([]*models.MinecraftServer, 0, len(serverIDs))
+	for _, serverID := range serverIDs {
+		server, err := s.serverRepo.FindByID(serverID)
+		if err != nil {
+			logger.Warn("FAILOVER: Server of failed node not found", map[string]interface{}{
+				"server_id": serverID,
+				"node_id":   nodeID,
+			})
+			continue
+		}
+		// Players online now are the ones to tell once the server is back (the window may outlast recent players)
+		s.recovery.snapshotCrashedPlayers(server)
+		servers = append(servers, server)
+	}
+
+	logger.Warn("FAILOVER: Node failed, waiting for the confirmation window", map[string]interface{}{
+		"node_id": nodeID,
+		"servers": len(servers),
+		"window":  s.window.String(),
+	})
+
+	select {
+	case <-time.After(s.window):
+	case <-s.stopChan:
+		return
+	}
+
+	if node, exists := s.cond.NodeRegistry.GetNode(nodeID); exists && node.Status == conductor.NodeStatusHealthy {
+		logger.Info("FAILOVER: Node recovered within the confirmation window, no failover", map[string]interface{}{
+			"node_id": nodeID,
+		})
+		for _, server := range servers {
+			s.recovery.takeCrashedPlayers(server.ID)
+		}
+		s.audit.Record(audit.AuditEntry{
+			Action:     audit.ActionServerFailover,
+			NodeID:     nodeID,
+			Reason:     "node recovered within the confirmation window",
+			DecisionBy: "failover",
+			Result:     "rejected",
+		})
+		return
+	}
+
+	for _, server := range servers {
+		s.failoverServer(nodeID, server)
+	}
+}
+
+// failoverServer marks a server of a failed node as crashed and restores it onto a healthy node
+func (s *FailoverService) failoverServer(nodeID string, server *models.MinecraftServer) {
+	current, err := s.serverRepo.FindByID(server.ID)
+	if err != nil || !failoverCandidate(current, nodeID) {
+		// Stopped, deleted or moved by someone else in the meantime
+		s.recovery.takeCrashedPlayers(server.ID)
+		return
+	}
+
+	s.cond.RemoveContainer(server.ID)
+	if err := s.minecraft.HandleNodeFailure(server.ID); err != nil {
+		logger.Error("FAILOVER: Failed to mark server as crashed", err, map[string]interface{}{
+			"server_id": server.ID,
+			"node_id":   nodeID,
+		})
+		return
+	}
+	if s.webhooks != nil {
+		s.webhooks.NotifyServerCrash(server.ID, server.Name, "The node of the server failed, restoring it on another node")
+	}
+
+	entry := audit.AuditEntry{
+		Action:       audit.ActionServerFailover,
+		NodeID:       nodeID,
+		Reason:       "node failed",
+		DecisionBy:   "failover",
+		ResourceType: "server",
+		ResourceID:   server.ID,
+		Before:       map[string]interface{}{"node_id": nodeID, "status": current.Status},
+	}
+
+	migration, backup, err := s.restore(nodeID, current)
+	if err != nil {
+		logger.Error("FAILOVER: Server could not be restored on another node", err, map[string]interface{}{
+			"server_id": server.ID,
+			"node_id":   nodeID,
+		})
+		s.recovery.takeCrashedPlayers(server.ID)
+		entry.Result, entry.Error = "failed", err.Error()
+		entry.After = map[string]interface{}{"status": models.StatusError}
+		s.audit.Record(entry)
+		s.notifyOwner(current, nil)
+		return
+	}
+
+	logger.Info("FAILOVER: Server restored on another node", map[string]interface{}{
+		"server_id":    server.ID,
+		"from_node":    nodeID,
+		"to_node":      migration.ToNodeID,
+		"backup_id":    backup.ID,
+		"operation_id": migration.ID,
+	})
+	entry.Result = "success"
+	entry.After = map[string]interface{}{
+		"node_id":      migration.ToNodeID,
+		"status":       models.StatusRunning,
+		"backup_id":    backup.ID,
+		"backup_at":    backup.CreatedAt,
+		"operation_id": migration.ID,
+	}
+	s.audit.Record(entry)
+	s.recovery.notifyPlayersRecovered(current)
+	s.notifyOwner(current, &backup.CreatedAt)
+}
+
+// restore fails a server over from its latest completed backup
+func (s *FailoverService) restore(nodeID string, server *models.MinecraftServer) (*models.Migration, *models.Backup, error) {
+	backup, err := s.backupRepo.FindLatestBackupForServer(server.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, fmt.Errorf("server has no completed backup")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find latest backup: %w", err)
+	}
+
+	migration, err := s.migrations.Failover(server, nodeID, backup)
+	if err != nil {
+		return nil, nil, err
+	}
+	return migration, backup, nil
+}
+
+// notifyOwner emails the owner whether the server was restored from the backup of backupAt (nil = not restored)
+func (s *FailoverService) notifyOwner(server *models.MinecraftServer, backupAt *time.Time) {
+	owner, err := s.userRepo.FindByID(server.OwnerID)
+	if err != nil {
+		logger.Warn("FAILOVER: Owner of server not found, skipping notice", map[string]interface{}{
+			"server_id": server.ID,
+			"owner_id":  server.OwnerID,
+		})
+		return
+	}
+	if err := s.email.SendFailoverNotice(owner.Email, owner.Username, server.ID, server.Name, backupAt); err != nil {
+		logger.Error("FAILOVER: Failed to send failover notice", err, map[string]interface{}{
+			"server_id": server.ID,
+		})
+	}
+}
+
+// failoverCandidate reports whether a server still runs on the failed node and should be failed over
+func failoverCandidate(server *models.MinecraftServer, nodeID string) bool {
+	if server == nil || server.NodeID != nodeID {
+		return false
+	}
+	return server.Status == models.StatusRunning || server.Status == models.StatusStarting
+}
+
+// failoverConfirmationWindow parses FAILOVER_CONFIRMATION_WINDOW, falling back to the default
+func failoverConfirmationWindow(value string) time.Duration {
+	if window, err := time.ParseDuration(value); err == nil && window >= 0 {
+		return window
+	}
+	return defaultFailoverConfirmationWindow
+}
+
+// failoverNoticeText returns the title and the text of the owner's failover email
+func failoverNoticeText(serverName string, backupAt *time.Time) (title, text string) {
+	if backupAt == nil {
+		return "Your server is down after a machine failure",
+			fmt.Sprintf("The machine your server \"%s\" was running on failed, and the server could not be restarted on another machine automatically. You are not billed while it is down. Start it again from the dashboard; if its world is missing, restore one of its backups first.", serverName)
+	}
+	return "Your server was restarted on another machine",
+		fmt.Sprintf("The machine your server \"%s\" was running on failed. We restarted the server on another machine from its latest backup of %s; changes made after that backup are lost.", serverName, backupAt.UTC().Format("Mon, 02 Jan 2006 15:04 MST"))
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestFailoverCandidate(t *testing.T) {
+	tests := map[string]struct {
+		server *models.MinecraftServer
+		want   bool
+	}{
+		"running on failed node":  {&models.MinecraftServer{NodeID: "node-a", Status: models.StatusRunning}, true},
+		"starting on failed node": {&models.MinecraftServer{NodeID: "node-a", Status: models.StatusStarting}, true},
+		"stopped meanwhile":       {&models.MinecraftServer{NodeID: "node-a", Status: models.StatusStopped}, false},
+		"already crashed":         {&models.MinecraftServer{NodeID: "node-a", Status: models.StatusError}, false},
+		"moved to another node":   {&models.MinecraftServer{NodeID: "node-b", Status: models.StatusRunning}, false},
+		"deleted":                 {nil, false},
+	}
+	for name, tt := range tests {
+		if got := failoverCandidate(tt.server, "node-a"); got != tt.want {
+			t.Errorf("%s: failoverCandidate() = %v, want %v", name, got, tt.want)
+		}
+	}
+}
+
+func TestFailoverConfirmationWindow(t *testing.T) {
+	tests := map[string]time.Duration{
+		"5m":   5 * time.Minute,
+		"0s":   0,
+		"":     defaultFailoverConfirmationWindow,
+		"soon": defaultFailoverConfirmationWindow,
+		"-1m":  defaultFailoverConfirmationWindow,
+		"90s":  90 * time.Second,
+	}
+	for value, want := range tests {
+		if got := failoverConfirmationWindow(value); got != want {
+			t.Errorf("failoverConfirmationWindow(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestFailoverNoticeText(t *testing.T) {
+	backupAt := time.Date(2025, 2, 1, 12, 30, 0, 0, time.UTC)
+
+	title, text := failoverNoticeText("Survival", &backupAt)
+	if !strings.Contains(title, "restarted") || !strings.Contains(text, "Sat, 01 Feb 2025 12:30 UTC") {
+		t.Errorf("restored notice = %q / %q, want the restart and the backup time", title, text)
+	}
+
+	title, text = failoverNoticeText("Survival", nil)
+	if !strings.Contains(title, "down") || !strings.Contains(text, "not billed") {
+		t.Errorf("failed notice = %q / %q, want the outage and that billing stopped", title, text)
+	}
+}
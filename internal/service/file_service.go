@@ -19,7 +19,8 @@ import (
 type FileService struct {
 	fileRepo   *repository.FileRepository
 	serverRepo *repository.ServerRepository
-	baseDir    string // Base directory for all server files
+	baseDir    string           // Base directory for all server files
+	quota      DiskQuotaChecker // Optional, refuses uploads over the disk quota
 }
 
 // NewFileService creates a new file service
@@ -35,6 +36,11 @@ func NewFileService(
 	}
 }
 
+// SetDiskQuota sets the disk quota checked before uploads are saved
+func (s *FileService) SetDiskQuota(quota DiskQuotaChecker) {
+	s.quota = quota
+}
+
 // UploadFileRequest represents a file upload request
 type UploadFileRequest struct {
 	ServerID   string
@@ -72,6 +78,10 @@ func (s *FileService) UploadFile(req UploadFileRequest) (*models.ServerFile, err
 		metrics.RecordUploadFailure(req.ServerID, req.UserID, req.FileType, err)
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	if err := checkDiskQuota(s.quota, req.ServerID, req.Header.Size); err != nil {
+		metrics.RecordUploadFailure(req.ServerID, req.UserID, req.FileType, err)
+		return nil, err
+	}
 
 	// 4. Calculate SHA1 hash
 	sha1Hash, err := CalculateSHA1(req.File)
@@ -111,6 +121,9 @@ func (s *FileService) UploadFile(req UploadFileRequest) (*models.ServerFile, err
 	}
 
 	serverFile.FilePath = filePath
+	if s.quota != nil {
+		s.quota.AddDiskUsage(req.ServerID, req.Header.Size)
+	}
 
 	// 8. Update file record with path
 	if err := s.fileRepo.Update(serverFile); err != nil {
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// File manager errors
+var (
+	ErrFilePathInvalid   = errors.New("invalid path: must stay inside the server directory")
+	ErrFileProtected     = errors.New("path is managed by the platform and cannot be changed")
+	ErrFileNotFound      = errors.New("file not found")
+	ErrFileExists        = errors.New("a file with this name already exists")
+	ErrFileModeInvalid   = errors.New("mode must be an octal permission between 000 and 777")
+	ErrFileArchiveTooBig = errors.New("archive contents exceed the extraction limit")
+	ErrFileNotArchive    = errors.New("file is not a ZIP archive")
+)
+
+// protectedPaths are paths (relative to the server directory, lower case) the file manager never
+// changes: the server jar and the EULA are managed by the platform, libraries, versions and cache
+// are managed by the Paper launcher, .world-import is the staging area of world imports.
+// Changing a folder that contains one of them (e.g. deleting the server directory) is refused too.
+var protectedPaths = []string{"server.jar", "eula.txt", "libraries", "versions", "cache", ".world-import"}
+
+// FileOpResult is the outcome of a bulk operation for one path
+type FileOpResult struct {
+	Path  string `json:"path"`
+	Error string `json:"error,omitempty"`
+}
+
+// resolvePath turns a path relative to the server directory into an absolute path
+// Symlinks are followed for the part of the path that exists, so a link cannot point outside the
+// server directory.
+func (fm *FileManagerService) resolvePath(serverID, relPath string) (string, error) {
+	serverPath, err := filepath.Abs(filepath.Join(fm.cfg.ServersBasePath, serverID))
+	if err != nil {
+		return "", err
+	}
+	if filepath.IsAbs(relPath) || strings.Contains(relPath, "\x00") {
+		return "", ErrFilePathInvalid
+	}
+	fullPath := filepath.Join(serverPath, filepath.FromSlash(relPath))
+	if !isInside(serverPath, fullPath) {
+		return "", ErrFilePathInvalid
+	}
+
+	// Resolve the deepest existing ancestor and check it is still inside
+	realServer, err := filepath.EvalSymlinks(serverPath)
+	if err != nil {
+		return "", fmt.Errorf("server directory not found: %w", err)
+	}
+	existing := fullPath
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+	realExisting, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", err
+	}
+	if !isInside(realServer, realExisting) {
+		return "", ErrFilePathInvalid
+	}
+	return fullPath, nil
+}
+
+// isInside reports whether path is dir or below it
+func isInside(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// isProtectedPath reports whether changing relPath would touch a protected path
+// Compared case-insensitively, so EULA.TXT on a case-insensitive filesystem is protected too.
+func isProtectedPath(relPath string) bool {
+	rel := strings.ToLower(filepath.ToSlash(filepath.Clean("/" + filepath.FromSlash(relPath))))
+	rel = strings.TrimPrefix(rel, "/")
+	if rel == "" {
+		return true // The server directory itself
+	}
+	for _, protected := range protectedPaths {
+		if rel == protected || strings.HasPrefix(rel, protected+"/") || strings.HasPrefix(protected, rel+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveWritable resolves a path that is about to be changed, refusing protected paths
+func (fm *FileManagerService) resolveWritable(serverID, relPath string) (string, error) {
+	if isProtectedPath(relPath) {
+		return "", ErrFileProtected
+	}
+	return fm.resolvePath(serverID, relPath)
+}
+
+// DeletePaths deletes files and folders of a server; each path succeeds or fails on its own
+func (fm *FileManagerService) DeletePaths(serverID string, paths []string) ([]FileOpResult, error) {
+	if _, err := fm.repo.FindByID(serverID); err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	results := make([]FileOpResult, 0, len(paths))
+	for _, relPath := range paths {
+		err := fm.deletePath(serverID, relPath)
+		results = append(results, fileOpResult(relPath, err))
+	}
+
+	logger.Info("Files deleted", map[string]interface{}{
+		"server_id": serverID,
+		"paths":     paths,
+	})
+	return results, nil
+}
+
+func (fm *FileManagerService) deletePath(serverID, relPath string) error {
+	fullPath, err := fm.resolveWritable(serverID, relPath)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(fullPath); err != nil {
+		return ErrFileNotFound
+	}
+	return os.RemoveAll(fullPath)
+}
+
+// MovePaths moves files and folders of a server into the folder destination
+// Existing files are not overwritten; each path succeeds or fails on its own.
+func (fm *FileManagerService) MovePaths(serverID string, paths []string, destination string) ([]FileOpResult, error) {
+	if _, err := fm.repo.FindByID(serverID); err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+	destDir, err := fm.resolvePath(serverID, destination)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create destination: %w", err)
+	}
+
+	results := make([]FileOpResult, 0, len(paths))
+	for _, relPath := range paths {
+		err := fm.movePath(serverID, relPath, destination, destDir)
+		results = append(results, fileOpResult(relPath, err))
+	}
+
+	logger.Info("Files moved", map[string]interface{}{
+		"server_id":   serverID,
+		"paths":       paths,
+		"destination": destination,
+	})
+	return results, nil
+}
+
+func (fm *FileManagerService) movePath(serverID, relPath, destination, destDir string) error {
+	source, err := fm.resolveWritable(serverID, relPath)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(source); err != nil {
+		return ErrFileNotFound
+	}
+	name := filepath.Base(source)
+	if isProtectedPath(filepath.Join(destination, name)) {
+		return ErrFileProtected
+	}
+	target := filepath.Join(destDir, name)
+	if isInside(source, target) {
+		return fmt.Errorf("%w: cannot move a folder into itself", ErrFilePathInvalid)
+	}
+	if _, err := os.Lstat(target); err == nil {
+		return ErrFileExists
+	}
+	return os.Rename(source, target)
+}
+
+// ChangeMode sets the permission bits of a file or folder (e.g. "755")
+// Only the rwx bits can be set; setuid, setgid and sticky bits are refused.
+func (fm *FileManagerService) ChangeMode(serverID, relPath, mode string) error {
+	if _, err := fm.repo.FindByID(serverID); err != nil {
+		return fmt.Errorf("server not found: %w", err)
+	}
+	perm, err := parseFileMode(mode)
+	if err != nil {
+		return err
+	}
+	fullPath, err := fm.resolveWritable(serverID, relPath)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(fullPath); err != nil {
+		return ErrFileNotFound
+	}
+	if err := os.Chmod(fullPath, perm); err != nil {
+		return fmt.Errorf("failed to change mode: %w", err)
+	}
+
+	logger.Info("File mode changed", map[string]interface{}{
+		"server_id": serverID,
+		"path":      relPath,
+		"mode":      mode,
+	})
+	return nil
+}
+
+// parseFileMode parses an octal permission like "644" or "0755"
+func parseFileMode(mode string) (os.FileMode, error) {
+	value, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || value > 0o777 {
+		return 0, ErrFileModeInvalid
+	}
+	return os.FileMode(value), nil
+}
+
+// Compress writes files and folders of a server into a new ZIP archive at target
+// Entries are named relative to the folder of the first path.
+func (fm *FileManagerService) Compress(serverID string, paths []string, target string) (*FileInfo, error) {
+	if _, err := fm.repo.FindByID(serverID); err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+	if !strings.EqualFold(filepath.Ext(target), ".zip") {
+		return nil, fmt.Errorf("%w: target must end in .zip", ErrFilePathInvalid)
+	}
+	targetPath, err := fm.resolveWritable(serverID, target)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Lstat(targetPath); err == nil {
+		return nil, ErrFileExists
+	}
+
+	sources := make([]string, 0, len(paths))
+	for _, relPath := range paths {
+		source, err := fm.resolvePath(serverID, relPath)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(source); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrFileNotFound, relPath)
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("%w: no paths to compress", ErrFilePathInvalid)
+	}
+	// The archive size isn't known up front; refuse it once the directory is full
+	if err := checkDiskQuota(fm.quota, serverID, 0); err != nil {
+		return nil, err
+	}
+
+	if err := zipPaths(filepath.Dir(sources[0]), sources, targetPath); err != nil {
+		os.Remove(targetPath)
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	info, err := os.Stat(targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Files compressed", map[string]interface{}{
+		"server_id": serverID,
+		"paths":     paths,
+		"target":    target,
+		"size":      info.Size(),
+	})
+	if fm.quota != nil {
+		fm.quota.AddDiskUsage(serverID, info.Size())
+	}
+	return &FileInfo{Name: info.Name(), Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// zipPaths writes files and folders into a ZIP archive, naming entries relative to base
+// Symlinks are skipped so an archive never contains files from outside the server directory.
+func zipPaths(base string, sources []string, target string) error {
+	zipFile, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer zipFile.Close()
+
+	archive := zip.NewWriter(zipFile)
+	for _, source := range sources {
+		err := filepath.WalkDir(source, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if filePath == target || !entry.Type().IsRegular() {
+				return nil
+			}
+			return addZipFile(archive, base, filePath)
+		})
+		if err != nil {
+			archive.Close()
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// Extract unpacks a ZIP archive of a server into the folder destination
+// The archive is checked completely before anything is written: entries must stay inside the
+// destination, must not touch protected paths or existing files (unless overwrite is set), and
+// their total size must stay below FILE_EXTRACT_MAX_SIZE_MB. Symlink entries are skipped.
+func (fm *FileManagerService) Extract(serverID, archive, destination string, overwrite bool) (int, error) {
+	if _, err := fm.repo.FindByID(serverID); err != nil {
+		return 0, fmt.Errorf("server not found: %w", err)
+	}
+	archivePath, err := fm.resolvePath(serverID, archive)
+	if err != nil {
+		return 0, err
+	}
+	destDir, err := fm.resolvePath(serverID, destination)
+	if err != nil {
+		return 0, err
+	}
+
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, ErrFileNotFound
+		}
+		return 0, ErrFileNotArchive
+	}
+	defer reader.Close()
+
+	maxBytes := uint64(fm.cfg.FileExtractMaxSizeMB) << 20
+	var total uint64
+	var files []*zip.File
+	for _, file := range reader.File {
+		if file.Mode()&fs.ModeSymlink != 0 {
+			continue
+		}
+		relPath := filepath.Join(destination, filepath.FromSlash(file.Name))
+		if isProtectedPath(relPath) {
+			return 0, fmt.Errorf("%w: %s", ErrFileProtected, file.Name)
+		}
+		targetPath, err := fm.resolvePath(serverID, relPath)
+		if err != nil || !isInside(destDir, targetPath) {
+			return 0, fmt.Errorf("%w: %s", ErrFilePathInvalid, file.Name)
+		}
+		if !overwrite && !file.FileInfo().IsDir() {
+			if _, err := os.Lstat(targetPath); err == nil {
+				return 0, fmt.Errorf("%w: %s", ErrFileExists, file.Name)
+			}
+		}
+		total += file.UncompressedSize64
+		if total > maxBytes {
+			return 0, ErrFileArchiveTooBig
+		}
+		files = append(files, file)
+	}
+	if err := checkDiskQuota(fm.quota, serverID, int64(total)); err != nil {
+		return 0, err
+	}
+
+	remaining := int64(maxBytes)
+	extracted := 0
+	for _, file := range files {
+		targetPath := filepath.Join(destDir, filepath.FromSlash(file.Name))
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(targetPath, 0755); err != nil {
+				return extracted, err
+			}
+			continue
+		}
+		written, err := extractFile(file, targetPath, remaining)
+		if err != nil {
+			if errors.Is(err, errExtractLimit) {
+				return extracted, ErrFileArchiveTooBig
+			}
+			return extracted, fmt.Errorf("failed to extract %s: %w", file.Name, err)
+		}
+		remaining -= written
+		extracted++
+	}
+	if fm.quota != nil {
+		fm.quota.AddDiskUsage(serverID, int64(maxBytes)-remaining)
+	}
+
+	logger.Info("Archive extracted", map[string]interface{}{
+		"server_id":   serverID,
+		"archive":     archive,
+		"destination": destination,
+		"files":       extracted,
+	})
+	return extracted, nil
+}
+
+// fileOpResult turns the error of one path of a bulk operation into its result
+func fileOpResult(relPath string, err error) FileOpResult {
+	if err != nil {
+		return FileOpResult{Path: relPath, Error: err.Error()}
+	}
+	return FileOpResult{Path: relPath}
+}
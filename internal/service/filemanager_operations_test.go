@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsProtectedPath(t *testing.T) {
+	tests := []struct {
+		path      string
+		protected bool
+	}{
+		{"server.jar", true},
+		{"EULA.TXT", true},
+		{"./plugins/../eula.txt", true},
+		{"libraries/com/example/lib.jar", true},
+		{"", true},  // The server directory contains server.jar
+		{".", true}, // Same
+		{"plugins/server.jar", false},
+		{"plugins", false},
+		{"world/level.dat", false},
+		{"eula.txt.bak", false},
+	}
+	for _, tt := range tests {
+		if got := isProtectedPath(tt.path); got != tt.protected {
+			t.Errorf("isProtectedPath(%q) = %v, want %v", tt.path, got, tt.protected)
+		}
+	}
+}
+
+func TestParseFileMode(t *testing.T) {
+	if mode, err := parseFileMode("0755"); err != nil || mode != 0o755 {
+		t.Errorf("parseFileMode(0755) = %o, %v, want 755", mode, err)
+	}
+	for _, invalid := range []string{"4755", "888", "rwx", ""} {
+		if _, err := parseFileMode(invalid); !errors.Is(err, ErrFileModeInvalid) {
+			t.Errorf("parseFileMode(%q) error = %v, want ErrFileModeInvalid", invalid, err)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/payperplay/hosting/internal/repository"
 	"github.com/payperplay/hosting/pkg/config"
@@ -12,17 +13,27 @@ import (
 )
 
 type FileManagerService struct {
-	repo *repository.ServerRepository
-	cfg  *config.Config
+	repo  *repository.ServerRepository
+	cfg   *config.Config
+	quota DiskQuotaChecker // Optional, refuses uploads and extractions over the disk quota
+
+	uploadMu  sync.Mutex
+	uploading map[string]bool // Resumable uploads with a chunk being written
 }
 
 func NewFileManagerService(repo *repository.ServerRepository, cfg *config.Config) *FileManagerService {
 	return &FileManagerService{
-		repo: repo,
-		cfg:  cfg,
+		repo:      repo,
+		cfg:       cfg,
+		uploading: make(map[string]bool),
 	}
 }
 
+// SetDiskQuota sets the disk quota checked before writes that grow the server directory
+func (fm *FileManagerService) SetDiskQuota(quota DiskQuotaChecker) {
+	fm.quota = quota
+}
+
 // AllowedFile represents a file that can be edited
 type AllowedFile struct {
 	Name        string `json:"name"`
@@ -151,6 +162,13 @@ func (fm *FileManagerService) WriteFile(serverID string, filePath string, conten
 	if err := fm.validateFilePath(serverID, filePath); err != nil {
 		return err
 	}
+	if isProtectedPath(filePath) {
+		return ErrFileProtected
+	}
+	// Player lists (whitelist.json, ops.json, banned-players.json) must stay loadable by Minecraft
+	if err := validatePlayerListFile(filePath, content); err != nil {
+		return err
+	}
 
 	serverPath := filepath.Join(fm.cfg.ServersBasePath, serverID)
 	fullPath := filepath.Join(serverPath, filePath)
@@ -240,16 +258,12 @@ func (fm *FileManagerService) ListFiles(serverID string, subPath string) ([]File
 		return nil, fmt.Errorf("server not found: %w", err)
 	}
 
-	// Security check
-	if subPath != "" {
-		if err := fm.validateFilePath(serverID, subPath); err != nil {
-			return nil, err
-		}
+	// Security check: folders have no extension, so only the path itself is validated
+	fullPath, err := fm.resolvePath(serverID, subPath)
+	if err != nil {
+		return nil, err
 	}
 
-	serverPath := filepath.Join(fm.cfg.ServersBasePath, serverID)
-	fullPath := filepath.Join(serverPath, subPath)
-
 	entries, err := os.ReadDir(fullPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read directory: %w", err)
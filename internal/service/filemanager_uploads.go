@@ -0,0 +1,290 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// Resumable upload errors
+var (
+	ErrUploadNotFound       = errors.New("upload not found or expired")
+	ErrUploadOffsetMismatch = errors.New("offset does not match the bytes received so far")
+	ErrUploadTooLarge       = errors.New("upload is larger than its declared size or the size limit")
+	ErrUploadBusy           = errors.New("another chunk of this upload is still being written")
+)
+
+// fileUploadDefaultExpiry applies if FILE_UPLOAD_EXPIRY is not a valid duration
+const fileUploadDefaultExpiry = 24 * time.Hour
+
+// FileUpload is a resumable upload of a large file (mods, worlds)
+// Works like tus: create the upload with its size, then send chunks with PATCH at the current
+// offset. After a broken connection, ask for the offset and continue from there. The file is moved
+// to its path once all bytes have arrived.
+type FileUpload struct {
+	ID        string    `json:"id"`
+	ServerID  string    `json:"server_id"`
+	Path      string    `json:"path"` // Target path relative to the server directory
+	Size      int64     `json:"size"`
+	Offset    int64     `json:"offset"` // Bytes received so far
+	Overwrite bool      `json:"overwrite"`
+	Completed bool      `json:"completed"`
+	ExpiresAt time.Time `json:"expires_at"` // Extended by every chunk
+}
+
+// CreateUpload starts a resumable upload of size bytes to path
+func (fm *FileManagerService) CreateUpload(serverID, path string, size int64, overwrite bool) (*FileUpload, error) {
+	if _, err := fm.repo.FindByID(serverID); err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+	if size <= 0 || size > int64(fm.cfg.FileUploadMaxSizeMB)<<20 {
+		return nil, fmt.Errorf("%w (limit %d MB)", ErrUploadTooLarge, fm.cfg.FileUploadMaxSizeMB)
+	}
+	targetPath, err := fm.resolveWritable(serverID, path)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(targetPath); err == nil && (info.IsDir() || !overwrite) {
+		return nil, ErrFileExists
+	}
+	if err := checkDiskQuota(fm.quota, serverID, size); err != nil {
+		return nil, err
+	}
+
+	fm.pruneUploads()
+
+	upload := &FileUpload{
+		ID:        uuid.New().String(),
+		ServerID:  serverID,
+		Path:      path,
+		Size:      size,
+		Overwrite: overwrite,
+		ExpiresAt: time.Now().Add(fm.uploadExpiry()),
+	}
+	if err := os.MkdirAll(fm.uploadDir(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	part, err := os.Create(fm.uploadPartPath(upload.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+	part.Close()
+	if err := fm.saveUpload(upload); err != nil {
+		os.Remove(fm.uploadPartPath(upload.ID))
+		return nil, err
+	}
+
+	logger.Info("Resumable upload created", map[string]interface{}{
+		"server_id": serverID,
+		"upload_id": upload.ID,
+		"path":      path,
+		"size":      size,
+	})
+	return upload, nil
+}
+
+// GetUpload returns an unfinished upload with the number of bytes received so far
+func (fm *FileManagerService) GetUpload(serverID, uploadID string) (*FileUpload, error) {
+	upload, err := fm.loadUpload(uploadID)
+	if err != nil || upload.ServerID != serverID || time.Now().After(upload.ExpiresAt) {
+		return nil, ErrUploadNotFound
+	}
+	info, err := os.Stat(fm.uploadPartPath(upload.ID))
+	if err != nil {
+		return nil, ErrUploadNotFound
+	}
+	// The received bytes are what is on disk, even if the last chunk broke off halfway
+	upload.Offset = info.Size()
+	return upload, nil
+}
+
+// AppendUpload writes a chunk at offset, which must be the number of bytes received so far
+// The returned upload is Completed once the last byte has arrived and the file is in place.
+func (fm *FileManagerService) AppendUpload(serverID, uploadID string, offset int64, chunk io.Reader) (*FileUpload, error) {
+	if !fm.lockUpload(uploadID) {
+		return nil, ErrUploadBusy
+	}
+	defer fm.unlockUpload(uploadID)
+
+	upload, err := fm.GetUpload(serverID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if offset != upload.Offset {
+		return upload, ErrUploadOffsetMismatch
+	}
+
+	part, err := os.OpenFile(fm.uploadPartPath(upload.ID), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open upload: %w", err)
+	}
+	remaining := upload.Size - upload.Offset
+	written, copyErr := io.Copy(part, io.LimitReader(chunk, remaining+1))
+	if written > remaining {
+		// Keep the bytes up to the declared size out of an oversized chunk
+		part.Truncate(upload.Size)
+		written = remaining
+		copyErr = ErrUploadTooLarge
+	}
+	part.Close()
+	upload.Offset += written
+
+	upload.ExpiresAt = time.Now().Add(fm.uploadExpiry())
+	if err := fm.saveUpload(upload); err != nil {
+		return nil, err
+	}
+	if copyErr != nil {
+		return upload, copyErr
+	}
+	if upload.Offset < upload.Size {
+		return upload, nil
+	}
+	if err := fm.finishUpload(upload); err != nil {
+		return upload, err
+	}
+	return upload, nil
+}
+
+// finishUpload moves a complete upload to its target path
+func (fm *FileManagerService) finishUpload(upload *FileUpload) error {
+	// Checked again: the path may have been created or protected since the upload started
+	targetPath, err := fm.resolveWritable(upload.ServerID, upload.Path)
+	if err != nil {
+		return err
+	}
+	var replaced int64
+	if info, err := os.Stat(targetPath); err == nil {
+		if info.IsDir() || !upload.Overwrite {
+			return ErrFileExists
+		}
+		replaced = info.Size()
+	}
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return fmt.Errorf("failed to create folder: %w", err)
+	}
+	if err := os.Rename(fm.uploadPartPath(upload.ID), targetPath); err != nil {
+		return fmt.Errorf("failed to move upload into place: %w", err)
+	}
+	os.Remove(fm.uploadMetaPath(upload.ID))
+	upload.Completed = true
+	if fm.quota != nil {
+		fm.quota.AddDiskUsage(upload.ServerID, upload.Size-replaced)
+	}
+
+	logger.Info("Resumable upload completed", map[string]interface{}{
+		"server_id": upload.ServerID,
+		"upload_id": upload.ID,
+		"path":      upload.Path,
+		"size":      upload.Size,
+	})
+	return nil
+}
+
+// CancelUpload discards an unfinished upload
+func (fm *FileManagerService) CancelUpload(serverID, uploadID string) error {
+	if !fm.lockUpload(uploadID) {
+		return ErrUploadBusy
+	}
+	defer fm.unlockUpload(uploadID)
+
+	if _, err := fm.GetUpload(serverID, uploadID); err != nil {
+		return err
+	}
+	os.Remove(fm.uploadPartPath(uploadID))
+	os.Remove(fm.uploadMetaPath(uploadID))
+	return nil
+}
+
+// pruneUploads deletes expired unfinished uploads
+func (fm *FileManagerService) pruneUploads() {
+	entries, err := os.ReadDir(fm.uploadDir())
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		uploadID, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		upload, err := fm.loadUpload(uploadID)
+		if err != nil || time.Now().After(upload.ExpiresAt) {
+			os.Remove(fm.uploadPartPath(uploadID))
+			os.Remove(fm.uploadMetaPath(uploadID))
+		}
+	}
+}
+
+// lockUpload marks an upload as being written; false if it already is
+func (fm *FileManagerService) lockUpload(uploadID string) bool {
+	fm.uploadMu.Lock()
+	defer fm.uploadMu.Unlock()
+	if fm.uploading[uploadID] {
+		return false
+	}
+	fm.uploading[uploadID] = true
+	return true
+}
+
+func (fm *FileManagerService) unlockUpload(uploadID string) {
+	fm.uploadMu.Lock()
+	defer fm.uploadMu.Unlock()
+	delete(fm.uploading, uploadID)
+}
+
+// saveUpload writes the metadata of an upload next to its data
+func (fm *FileManagerService) saveUpload(upload *FileUpload) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(fm.uploadMetaPath(upload.ID), data, 0644); err != nil {
+		return fmt.Errorf("failed to save upload: %w", err)
+	}
+	return nil
+}
+
+// loadUpload reads the metadata of an upload
+func (fm *FileManagerService) loadUpload(uploadID string) (*FileUpload, error) {
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return nil, ErrUploadNotFound
+	}
+	data, err := os.ReadFile(fm.uploadMetaPath(uploadID))
+	if err != nil {
+		return nil, ErrUploadNotFound
+	}
+	var upload FileUpload
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return nil, ErrUploadNotFound
+	}
+	return &upload, nil
+}
+
+// uploadDir holds unfinished uploads; metadata is kept on disk so uploads survive API restarts
+func (fm *FileManagerService) uploadDir() string {
+	return filepath.Join(fm.cfg.ServersBasePath, "temp", "file-uploads")
+}
+
+func (fm *FileManagerService) uploadPartPath(uploadID string) string {
+	return filepath.Join(fm.uploadDir(), uploadID+".part")
+}
+
+func (fm *FileManagerService) uploadMetaPath(uploadID string) string {
+	return filepath.Join(fm.uploadDir(), uploadID+".json")
+}
+
+// uploadExpiry is how long an unfinished upload can be resumed
+func (fm *FileManagerService) uploadExpiry() time.Duration {
+	expiry, err := time.ParseDuration(fm.cfg.FileUploadExpiry)
+	if err != nil || expiry <= 0 {
+		return fileUploadDefaultExpiry
+	}
+	return expiry
+}
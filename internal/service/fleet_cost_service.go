@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/cloud"
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// fleetCostDefaultInterval applies if FLEET_COST_RECONCILE_INTERVAL is not a valid duration
+	fleetCostDefaultInterval = time.Hour
+	// fleetCostTolerance is the relative difference between estimate and billing that is still no discrepancy
+	fleetCostTolerance = 0.05
+	// fleetCostMinDifferenceEUR is the smallest month-to-date difference reported as a discrepancy
+	fleetCostMinDifferenceEUR = 0.05
+)
+
+var (
+	// ErrFleetCostUnavailable is returned if the cloud provider can't report its billing
+	ErrFleetCostUnavailable = errors.New("cloud provider does not report billed usage")
+	// ErrFleetCostRunning is returned if a reconciliation is already in progress
+	ErrFleetCostRunning = errors.New("fleet cost reconciliation is already running")
+)
+
+// FleetCostDiscrepancy is the kind of disagreement between the fleet's estimate and the provider's billing
+type FleetCostDiscrepancy string
+
+const (
+	FleetCostOrphaned          FleetCostDiscrepancy = "orphaned"            // Billed PayPerPlay server that is no node of the fleet
+	FleetCostMissingAtProvider FleetCostDiscrepancy = "missing_at_provider" // Cloud node the provider doesn't know (any more)
+	FleetCostPrice             FleetCostDiscrepancy = "price"               // Estimated hourly cost differs from the provider's price
+	FleetCostUsage             FleetCostDiscrepancy = "usage"               // Same price, but a different month-to-date amount (runtime, monthly cap)
+)
+
+// FleetCostReport compares the month-to-date cost of the cloud nodes estimated by the fleet with what
+// the provider bills for them
+type FleetCostReport struct {
+	ReconciledAt       time.Time       `json:"reconciled_at"`
+	PeriodStart        time.Time       `json:"period_start"` // Start of the billing month (UTC)
+	EstimatedEUR       float64         `json:"estimated_eur"`
+	ProviderEUR        float64         `json:"provider_eur"`
+	DifferenceEUR      float64         `json:"difference_eur"` // Provider minus estimate
+	EstimatedHourlyEUR float64         `json:"estimated_hourly_eur"`
+	ProviderHourlyEUR  float64         `json:"provider_hourly_eur"`
+	Discrepancies      int             `json:"discrepancies"`
+	Nodes              []FleetNodeCost `json:"nodes"`
+}
+
+// FleetNodeCost is the cost of one cloud node (or provider server) in the report
+type FleetNodeCost struct {
+	NodeID             string               `json:"node_id"` // Provider server ID
+	Hostname           string               `json:"hostname"`
+	ServerType         string               `json:"server_type,omitempty"`
+	Location           string               `json:"location,omitempty"`
+	InFleet            bool                 `json:"in_fleet"`
+	AtProvider         bool                 `json:"at_provider"`
+	Managed            bool                 `json:"managed"` // Created by PayPerPlay (managed_by label)
+	EstimatedHourlyEUR float64              `json:"estimated_hourly_eur"`
+	ProviderHourlyEUR  float64              `json:"provider_hourly_eur"`
+	ProviderMonthlyEUR float64              `json:"provider_monthly_eur"` // Monthly cap of the provider
+	EstimatedEUR       float64              `json:"estimated_eur"`
+	ProviderEUR        float64              `json:"provider_eur"`
+	DifferenceEUR      float64              `json:"difference_eur"`
+	BilledHours        int                  `json:"billed_hours"`
+	Discrepancy        FleetCostDiscrepancy `json:"discrepancy,omitempty"`
+	Detail             string               `json:"detail,omitempty"`
+}
+
+// FleetCostService periodically reconciles the estimated cost of the cloud nodes (the hourly cost the
+// scaling and cost optimization work with) with the usage the provider bills, and samples every node's
+// hourly cost for its cost timeline. Samples older than FLEET_COST_RETENTION_DAYS are deleted.
+type FleetCostService struct {
+	cond      *conductor.Conductor
+	repo      *repository.NodeCostSampleRepository
+	interval  time.Duration
+	retention int // Days, 0 = forever
+
+	running sync.Mutex
+	mu      sync.RWMutex
+	last    *FleetCostReport
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewFleetCostService creates a new fleet cost service
+func NewFleetCostService(cond *conductor.Conductor, repo *repository.NodeCostSampleRepository, cfg *config.Config) *FleetCostService {
+	return &FleetCostService{
+		cond:      cond,
+		repo:      repo,
+		interval:  parseDurationOr(cfg.FleetCostReconcileInterval, fleetCostDefaultInterval),
+		retention: cfg.FleetCostRetentionDays,
+	}
+}
+
+// LastReport returns the report of the last reconciliation, nil before the first one
+func (s *FleetCostService) LastReport() *FleetCostReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+// Reconcile compares the fleet's estimate with the provider's billing of the current month and
+// samples the node cost timelines
+func (s *FleetCostService) Reconcile() (*FleetCostReport, error) {
+	provider, ok := s.cond.CloudProvider.(cloud.UsageProvider)
+	if !ok {
+		return nil, ErrFleetCostUnavailable
+	}
+	if !s.running.TryLock() {
+		return nil, ErrFleetCostRunning
+	}
+	defer s.running.Unlock()
+
+	now := time.Now().UTC()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	usage, err := provider.GetServerUsage(periodStart, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get billed usage: %w", err)
+	}
+
+	report := reconcileFleetCosts(s.cond.NodeRegistry.GetAllNodes(), usage, periodStart, now)
+	if err := s.repo.CreateBatch(nodeCostSamples(report)); err != nil {
+		logger.Warn("FLEET-COST: Failed to save node cost samples", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	if report.Discrepancies > 0 {
+		logger.Warn("FLEET-COST: Estimated fleet cost differs from the provider's billing", map[string]interface{}{
+			"discrepancies":  report.Discrepancies,
+			"estimated_eur":  report.EstimatedEUR,
+			"provider_eur":   report.ProviderEUR,
+			"difference_eur": report.DifferenceEUR,
+		})
+	}
+
+	s.mu.Lock()
+	s.last = report
+	s.mu.Unlock()
+	return report, nil
+}
+
+// Timeline returns the cost samples of a node taken in [from, to), oldest first
+func (s *FleetCostService) Timeline(nodeID string, from, to time.Time) ([]models.NodeCostSample, error) {
+	return s.repo.FindByNode(nodeID, from, to)
+}
+
+// Start reconciles periodically and prunes old samples
+func (s *FleetCostService) Start() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.Reconcile(); err != nil && !errors.Is(err, ErrFleetCostRunning) && !errors.Is(err, ErrFleetCostUnavailable) {
+					logger.Error("FLEET-COST: Reconciliation failed", err, nil)
+				}
+				s.prune()
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the periodic reconciliation
+func (s *FleetCostService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// prune deletes samples older than the retention
+func (s *FleetCostService) prune() {
+	if s.retention <= 0 {
+		return
+	}
+	if _, err := s.repo.DeleteBefore(time.Now().AddDate(0, 0, -s.retention)); err != nil {
+		logger.Warn("FLEET-COST: Failed to prune node cost samples", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// reconcileFleetCosts matches the cloud nodes of the fleet with the provider's servers, sorted by node ID
+func reconcileFleetCosts(nodes []*conductor.Node, usage []*cloud.ServerUsage, periodStart, now time.Time) *FleetCostReport {
+	costs := make(map[string]*FleetNodeCost)
+	for _, node := range nodes {
+		if node.Type != "cloud" || strings.HasPrefix(node.ID, "provisioning-") {
+			continue // Dedicated nodes aren't billed by the cloud provider, placeholders aren't servers yet
+		}
+		id := node.ID
+		if node.CloudProviderID != "" {
+			id = node.CloudProviderID
+		}
+		since := node.CreatedAt
+		if since.Before(periodStart) {
+			since = periodStart
+		}
+		costs[id] = &FleetNodeCost{
+			NodeID:             id,
+			Hostname:           node.Hostname,
+			InFleet:            true,
+			Managed:            true,
+			EstimatedHourlyEUR: node.HourlyCostEUR,
+			EstimatedEUR:       node.HourlyCostEUR * math.Max(now.Sub(since).Hours(), 0),
+		}
+	}
+	for _, server := range usage {
+		cost, exists := costs[server.ServerID]
+		if !exists {
+			cost = &FleetNodeCost{NodeID: server.ServerID, Hostname: server.Name, Managed: server.Labels["managed_by"] == "payperplay"}
+			costs[server.ServerID] = cost
+		}
+		cost.AtProvider = true
+		cost.ServerType, cost.Location = server.Type, server.Location
+		cost.ProviderHourlyEUR, cost.ProviderMonthlyEUR = server.HourlyPriceEUR, server.MonthlyPriceEUR
+		cost.ProviderEUR, cost.BilledHours = server.BilledEUR, server.BilledHours
+	}
+
+	report := &FleetCostReport{ReconciledAt: now, PeriodStart: periodStart, Nodes: make([]FleetNodeCost, 0, len(costs))}
+	for _, cost := range costs {
+		cost.Discrepancy, cost.Detail = fleetCostDiscrepancy(*cost)
+		cost.EstimatedEUR, cost.ProviderEUR = roundCents(cost.EstimatedEUR), roundCents(cost.ProviderEUR)
+		cost.DifferenceEUR = roundCents(cost.ProviderEUR - cost.EstimatedEUR)
+
+		report.EstimatedEUR += cost.EstimatedEUR
+		report.ProviderEUR += cost.ProviderEUR
+		report.EstimatedHourlyEUR += cost.EstimatedHourlyEUR
+		report.ProviderHourlyEUR += cost.ProviderHourlyEUR
+		if cost.Discrepancy != "" {
+			report.Discrepancies++
+		}
+		report.Nodes = append(report.Nodes, *cost)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].NodeID < report.Nodes[j].NodeID })
+
+	report.EstimatedEUR, report.ProviderEUR = roundCents(report.EstimatedEUR), roundCents(report.ProviderEUR)
+	report.DifferenceEUR = roundCents(report.ProviderEUR - report.EstimatedEUR)
+	return report
+}
+
+// fleetCostDiscrepancy classifies the disagreement of a node's estimate and billing ("" = none)
+// Servers not created by PayPerPlay (e.g. the control plane) are billed but not reported.
+func fleetCostDiscrepancy(cost FleetNodeCost) (FleetCostDiscrepancy, string) {
+	switch {
+	case !cost.InFleet:
+		if !cost.Managed {
+			return "", ""
+		}
+		return FleetCostOrphaned, fmt.Sprintf("billed server %s is no node of the fleet, delete it if it isn't needed", cost.Hostname)
+	case !cost.AtProvider:
+		return FleetCostMissingAtProvider, "the provider has no server for this node, remove it from the fleet"
+	case !withinTolerance(cost.EstimatedHourlyEUR, cost.ProviderHourlyEUR, 0):
+		return FleetCostPrice, fmt.Sprintf("estimated %.4f EUR/h, the provider bills %.4f EUR/h", cost.EstimatedHourlyEUR, cost.ProviderHourlyEUR)
+	case !withinTolerance(cost.EstimatedEUR, cost.ProviderEUR, fleetCostMinDifferenceEUR):
+		return FleetCostUsage, fmt.Sprintf("estimated %.2f EUR this month, the provider bills %.2f EUR for %d hours", cost.EstimatedEUR, cost.ProviderEUR, cost.BilledHours)
+	}
+	return "", ""
+}
+
+// withinTolerance reports whether estimate is within fleetCostTolerance of billed, or at most minDifference off
+func withinTolerance(estimate, billed, minDifference float64) bool {
+	difference := math.Abs(billed - estimate)
+	return difference <= minDifference || difference <= fleetCostTolerance*billed
+}
+
+// nodeCostSamples converts a report into the samples of the node cost timelines
+func nodeCostSamples(report *FleetCostReport) []models.NodeCostSample {
+	samples := make([]models.NodeCostSample, 0, len(report.Nodes))
+	for _, cost := range report.Nodes {
+		samples = append(samples, models.NodeCostSample{
+			NodeID:             cost.NodeID,
+			SampledAt:          report.ReconciledAt,
+			Hostname:           cost.Hostname,
+			ServerType:         cost.ServerType,
+			Location:           cost.Location,
+			InFleet:            cost.InFleet,
+			AtProvider:         cost.AtProvider,
+			EstimatedHourlyEUR: cost.EstimatedHourlyEUR,
+			ProviderHourlyEUR:  cost.ProviderHourlyEUR,
+		})
+	}
+	return samples
+}
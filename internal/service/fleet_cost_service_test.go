@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/cloud"
+	"github.com/payperplay/hosting/internal/conductor"
+)
+
+func TestReconcileFleetCosts(t *testing.T) {
+	periodStart := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := periodStart.Add(100 * time.Hour)
+	managed := map[string]string{"managed_by": "payperplay"}
+
+	nodes := []*conductor.Node{
+		{ID: "101", Hostname: "node-ok", Type: "cloud", HourlyCostEUR: 0.01, CreatedAt: periodStart.AddDate(0, -1, 0)},
+		{ID: "102", Hostname: "node-price", Type: "cloud", HourlyCostEUR: 0.01, CreatedAt: periodStart},
+		{ID: "103", Hostname: "node-recovered", Type: "cloud", HourlyCostEUR: 0.01, CreatedAt: now.Add(-10 * time.Hour)},
+		{ID: "104", Hostname: "node-gone", Type: "cloud", HourlyCostEUR: 0.02, CreatedAt: periodStart},
+		{ID: "provisioning-1", Hostname: "placeholder", Type: "cloud", CreatedAt: periodStart},
+		{ID: "dedicated-1", Hostname: "dedicated", Type: "dedicated", HourlyCostEUR: 0.1, CreatedAt: periodStart},
+	}
+	usage := []*cloud.ServerUsage{
+		{ServerID: "101", Name: "node-ok", Labels: managed, HourlyPriceEUR: 0.01, MonthlyPriceEUR: 5, BilledHours: 100, BilledEUR: 1},
+		{ServerID: "102", Name: "node-price", Labels: managed, HourlyPriceEUR: 0.02, MonthlyPriceEUR: 10, BilledHours: 100, BilledEUR: 2},
+		{ServerID: "103", Name: "node-recovered", Labels: managed, HourlyPriceEUR: 0.01, MonthlyPriceEUR: 5, BilledHours: 100, BilledEUR: 1},
+		{ServerID: "201", Name: "payperplay-node-old", Labels: managed, HourlyPriceEUR: 0.01, MonthlyPriceEUR: 5, BilledHours: 100, BilledEUR: 1},
+		{ServerID: "301", Name: "control-plane", HourlyPriceEUR: 0.03, MonthlyPriceEUR: 15, BilledHours: 100, BilledEUR: 3},
+	}
+
+	report := reconcileFleetCosts(nodes, usage, periodStart, now)
+
+	want := map[string]FleetCostDiscrepancy{
+		"101": "",
+		"102": FleetCostPrice,
+		"103": FleetCostUsage,
+		"104": FleetCostMissingAtProvider,
+		"201": FleetCostOrphaned,
+		"301": "",
+	}
+	if len(report.Nodes) != len(want) {
+		t.Fatalf("report has %d nodes, want %d: %+v", len(report.Nodes), len(want), report.Nodes)
+	}
+	for _, node := range report.Nodes {
+		discrepancy, ok := want[node.NodeID]
+		if !ok {
+			t.Errorf("unexpected node %s in report", node.NodeID)
+			continue
+		}
+		if node.Discrepancy != discrepancy {
+			t.Errorf("node %s: discrepancy = %q (%s), want %q", node.NodeID, node.Discrepancy, node.Detail, discrepancy)
+		}
+	}
+	if report.Discrepancies != 4 {
+		t.Errorf("discrepancies = %d, want 4", report.Discrepancies)
+	}
+	// Estimates: 1 + 1 + 0.10 + 2 (node-gone), billed: 1 + 2 + 1 + 1 + 3
+	if report.EstimatedEUR != 4.1 || report.ProviderEUR != 8 || report.DifferenceEUR != 3.9 {
+		t.Errorf("totals = %.2f estimated, %.2f billed, %.2f difference, want 4.10, 8.00, 3.90",
+			report.EstimatedEUR, report.ProviderEUR, report.DifferenceEUR)
+	}
+	if samples := nodeCostSamples(report); len(samples) != len(want) || !samples[0].SampledAt.Equal(now) {
+		t.Errorf("samples = %+v, want one per node sampled at the reconciliation", samples)
+	}
+}
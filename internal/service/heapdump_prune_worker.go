@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// HeapDumpPruneWorker periodically collects fresh heap dumps and prunes stored ones
+type HeapDumpPruneWorker struct {
+	heapDumpService *HeapDumpService
+	pruneInterval   time.Duration // How often to run pruning (default: 1h)
+	running         bool
+	ctx             context.Context
+	cancel          context.CancelFunc
+	pruneMutex      sync.Mutex // Prevents concurrent prune runs
+}
+
+// NewHeapDumpPruneWorker creates a new heap dump prune worker
+func NewHeapDumpPruneWorker(heapDumpService *HeapDumpService) *HeapDumpPruneWorker {
+	return &HeapDumpPruneWorker{
+		heapDumpService: heapDumpService,
+		pruneInterval:   time.Hour,
+		running:         false,
+	}
+}
+
+// Start begins the prune worker
+func (w *HeapDumpPruneWorker) Start() {
+	if w.running {
+		logger.Warn("HEAPDUMP-PRUNE: Worker already running", nil)
+		return
+	}
+
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.running = true
+
+	logger.Info("HEAPDUMP-PRUNE: Starting prune worker", map[string]interface{}{
+		"prune_interval": w.pruneInterval,
+	})
+
+	go func() {
+		ticker := time.NewTicker(w.pruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.runPrune()
+			case <-w.ctx.Done():
+				logger.Info("HEAPDUMP-PRUNE: Worker stopped", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the prune worker
+func (w *HeapDumpPruneWorker) Stop() {
+	if !w.running {
+		return
+	}
+
+	logger.Info("HEAPDUMP-PRUNE: Stopping prune worker", nil)
+	w.cancel()
+	w.running = false
+}
+
+// runPrune performs one collect/prune pass
+func (w *HeapDumpPruneWorker) runPrune() {
+	if !w.pruneMutex.TryLock() {
+		logger.Warn("HEAPDUMP-PRUNE: Prune already in progress, skipping this cycle", nil)
+		return
+	}
+	defer w.pruneMutex.Unlock()
+
+	w.heapDumpService.PruneAll()
+}
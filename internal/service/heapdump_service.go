@@ -0,0 +1,539 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+var (
+	// ErrHeapDumpsNotModded is returned when heap dumps are enabled for a non-modded server
+	ErrHeapDumpsNotModded = errors.New("heap dumps are only available for modded servers (forge, fabric)")
+	// ErrHeapDumpNotFound is returned for unknown or invalid dump names
+	ErrHeapDumpNotFound = errors.New("heap dump not found")
+)
+
+const (
+	// heapDumpFilePrefix names collected dumps: heapdump-<UTC timestamp>-<pid>.hprof
+	heapDumpFilePrefix = "heapdump-"
+	// heapDumpSettleTime is how long a dump must be untouched before it is collected
+	heapDumpSettleTime = 30 * time.Second
+	// heapDumpDownloadTimeout bounds the download of one dump from a worker node
+	heapDumpDownloadTimeout = 30 * time.Minute
+)
+
+// HeapDumpInfo describes a stored heap dump
+type HeapDumpInfo struct {
+	Name          string    `json:"name"`
+	Size          int64     `json:"size"`
+	SizeFormatted string    `json:"size_formatted"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// HeapDumpService captures JVM heap dumps of crashed modded servers
+// The JVM writes java_pid<pid>.hprof into the server volume on OOM. Collecting moves those files
+// into a per-server area outside the volume (so they don't bloat backups or block the next dump,
+// since the JVM never overwrites an existing file) and enforces the per-server quota.
+// Dumps in the volumes of remote worker nodes are downloaded over the node's pooled SSH connection.
+type HeapDumpService struct {
+	serverRepo *repository.ServerRepository
+	cfg        *config.Config
+	mu         sync.Mutex // Serializes collect/prune per process
+
+	nodes  NodeAddressResolver // Optional: resolves the worker node of remote servers
+	remote RemoteFileExecutor  // Optional: reaches remote server volumes
+}
+
+// RemoteFileExecutor runs commands and reads files on worker nodes (implemented by docker.SSHPool)
+type RemoteFileExecutor interface {
+	Run(ctx context.Context, node *docker.RemoteNode, command string) (string, error)
+	Download(ctx context.Context, node *docker.RemoteNode, remotePath string, w io.Writer) error
+}
+
+// NewHeapDumpService creates a new heap dump service
+func NewHeapDumpService(serverRepo *repository.ServerRepository, cfg *config.Config) *HeapDumpService {
+	return &HeapDumpService{
+		serverRepo: serverRepo,
+		cfg:        cfg,
+	}
+}
+
+// SetRemoteAccess enables collecting dumps of servers on remote worker nodes
+func (s *HeapDumpService) SetRemoteAccess(nodes NodeAddressResolver, remote RemoteFileExecutor) {
+	s.nodes = nodes
+	s.remote = remote
+}
+
+// SetHeapDumpsEnabled toggles heap dump capture for a server
+// Takes effect on the next start, since the flag is part of the container's JVM options.
+func (s *HeapDumpService) SetHeapDumpsEnabled(serverID string, enabled bool) (*models.MinecraftServer, error) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	if enabled && !server.IsModded() {
+		return nil, ErrHeapDumpsNotModded
+	}
+
+	server.HeapDumpsEnabled = enabled
+	if err := s.serverRepo.Update(server); err != nil {
+		return nil, fmt.Errorf("failed to update server: %w", err)
+	}
+
+	logger.Info("HEAPDUMP: Capture setting changed", map[string]interface{}{
+		"server_id": serverID,
+		"enabled":   enabled,
+	})
+
+	return server, nil
+}
+
+// CollectHeapDumps moves fresh dumps out of the server volume and enforces the quota
+// Returns the number of dumps collected.
+func (s *HeapDumpService) CollectHeapDumps(serverID string) (int, error) {
+	return s.collect(serverID, false)
+}
+
+// CollectHeapDumpsAfterExit collects dumps of a server whose JVM is known to have exited
+// The JVM finishes the dump before exiting, so no settle time is needed.
+func (s *HeapDumpService) CollectHeapDumpsAfterExit(serverID string) (int, error) {
+	return s.collect(serverID, true)
+}
+
+// collect moves dumps out of the volume and prunes the server's dump area
+func (s *HeapDumpService) collect(serverID string, exited bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var collected int
+	node, err := s.remoteNode(serverID)
+	if err == nil {
+		if node != nil {
+			collected, err = s.collectRemote(node, serverID, exited)
+		} else {
+			collected, err = s.collectLocal(serverID, exited)
+		}
+	}
+	if err != nil {
+		return collected, err
+	}
+
+	if err := s.pruneLocked(serverID); err != nil {
+		return collected, err
+	}
+
+	return collected, nil
+}
+
+// ListHeapDumps returns the stored dumps of a server, newest first
+func (s *HeapDumpService) ListHeapDumps(serverID string) ([]HeapDumpInfo, error) {
+	if _, err := s.CollectHeapDumps(serverID); err != nil {
+		logger.Warn("HEAPDUMP: Failed to collect dumps before listing", map[string]interface{}{
+			"server_id": serverID,
+			"error":     err.Error(),
+		})
+	}
+
+	return s.listStored(serverID)
+}
+
+// GetHeapDumpPath returns the local path of a stored dump for download
+func (s *HeapDumpService) GetHeapDumpPath(serverID, name string) (string, error) {
+	path, err := s.storedPath(serverID, name)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return "", ErrHeapDumpNotFound
+	}
+
+	return path, nil
+}
+
+// DeleteHeapDump removes a stored dump
+func (s *HeapDumpService) DeleteHeapDump(serverID, name string) error {
+	path, err := s.storedPath(serverID, name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return ErrHeapDumpNotFound
+		}
+		return fmt.Errorf("failed to delete heap dump: %w", err)
+	}
+
+	logger.Info("HEAPDUMP: Dump deleted", map[string]interface{}{
+		"server_id": serverID,
+		"name":      name,
+	})
+
+	return nil
+}
+
+// PruneAll collects and prunes dumps of every server that has any
+func (s *HeapDumpService) PruneAll() {
+	serverIDs := map[string]bool{}
+
+	// Servers with stored dumps (also covers deleted servers)
+	if entries, err := os.ReadDir(s.cfg.HeapDumpStoragePath); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				serverIDs[entry.Name()] = true
+			}
+		}
+	}
+
+	// Servers with capture enabled that may have fresh dumps in their volume
+	servers, err := s.serverRepo.FindAll()
+	if err != nil {
+		logger.Error("HEAPDUMP: Failed to list servers for pruning", err, nil)
+	}
+	for _, server := range servers {
+		if server.HeapDumpsActive() {
+			serverIDs[server.ID] = true
+		}
+	}
+
+	for serverID := range serverIDs {
+		if _, err := s.serverRepo.FindByID(serverID); err != nil {
+			// Server is gone: its dumps have no owner to download them anymore
+			if err := os.RemoveAll(s.serverDir(serverID)); err != nil {
+				logger.Warn("HEAPDUMP: Failed to remove dumps of deleted server", map[string]interface{}{
+					"server_id": serverID,
+					"error":     err.Error(),
+				})
+			}
+			continue
+		}
+
+		if _, err := s.CollectHeapDumps(serverID); err != nil {
+			logger.Warn("HEAPDUMP: Failed to prune dumps", map[string]interface{}{
+				"server_id": serverID,
+				"error":     err.Error(),
+			})
+		}
+	}
+}
+
+// remoteNode returns the worker node of a server, nil if its volume is on this node
+func (s *HeapDumpService) remoteNode(serverID string) (*docker.RemoteNode, error) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil || server.NodeID == "" || server.NodeID == "local-node" {
+		return nil, nil // Deleted servers only have stored dumps left
+	}
+	if s.nodes == nil || s.remote == nil {
+		return nil, fmt.Errorf("remote node access not configured for node %s", server.NodeID)
+	}
+	node, err := s.nodes.GetRemoteNode(server.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve node %s: %w", server.NodeID, err)
+	}
+	return node, nil
+}
+
+// collectLocal moves java_pid*.hprof files from a local server volume to the dump area
+func (s *HeapDumpService) collectLocal(serverID string, exited bool) (int, error) {
+	volumeDir := filepath.Join(s.cfg.ServersBasePath, serverID)
+	entries, err := os.ReadDir(volumeDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil // Never started
+		}
+		return 0, fmt.Errorf("failed to read server directory: %w", err)
+	}
+
+	collected := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !isRawHeapDump(name) {
+			continue
+		}
+
+		src := filepath.Join(volumeDir, name)
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		// The JVM may still be writing the dump; pick it up on the next pass
+		if !exited && time.Since(info.ModTime()) < heapDumpSettleTime {
+			continue
+		}
+
+		dst, err := s.storeTarget(serverID, name, info.ModTime())
+		if err != nil {
+			return collected, err
+		}
+		if err := moveFile(src, dst); err != nil {
+			return collected, fmt.Errorf("failed to collect %s: %w", name, err)
+		}
+		// Keep the crash time as creation time (a cross-filesystem copy would reset it)
+		os.Chtimes(dst, info.ModTime(), info.ModTime())
+
+		collected++
+		s.logCollected(serverID, dst, info.Size())
+	}
+
+	return collected, nil
+}
+
+// collectRemote downloads java_pid*.hprof files from a server volume on a worker node
+// and removes them there once stored
+func (s *HeapDumpService) collectRemote(node *docker.RemoteNode, serverID string, exited bool) (int, error) {
+	if err := docker.ValidateResourceID(serverID); err != nil {
+		return 0, err
+	}
+	volumeDir := remoteServersPath + "/" + serverID
+
+	// One line per dump: name, size and modification time (Unix seconds with fraction)
+	listCmd := docker.ShellJoin("test", "!", "-d", volumeDir) + " || " +
+		docker.ShellJoin("find", volumeDir, "-maxdepth", "1", "-type", "f",
+			"-name", docker.HeapDumpPrefix+"*"+docker.HeapDumpSuffix, "-printf", `%f %s %T@\n`)
+	listCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	output, err := s.remote.Run(listCtx, node, listCmd)
+	cancel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list dumps on node %s: %w", node.ID, err)
+	}
+
+	collected := 0
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || !isRawHeapDump(fields[0]) {
+			continue
+		}
+		name := fields[0]
+		size, err1 := strconv.ParseInt(fields[1], 10, 64)
+		mtime, err2 := strconv.ParseFloat(fields[2], 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		modTime := time.Unix(0, int64(mtime*float64(time.Second)))
+
+		if !exited && time.Since(modTime) < heapDumpSettleTime {
+			continue
+		}
+
+		dst, err := s.storeTarget(serverID, name, modTime)
+		if err != nil {
+			return collected, err
+		}
+		src := volumeDir + "/" + name
+		if err := s.download(node, src, dst); err != nil {
+			return collected, fmt.Errorf("failed to collect %s from node %s: %w", name, node.ID, err)
+		}
+		os.Chtimes(dst, modTime, modTime)
+
+		// The dump is stored: free the node's volume so the JVM can write the next one
+		rmCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		_, err = s.remote.Run(rmCtx, node, docker.ShellJoin("rm", "-f", "--", src))
+		cancel()
+		if err != nil {
+			logger.Warn("HEAPDUMP: Failed to remove collected dump on node", map[string]interface{}{
+				"server_id": serverID,
+				"node_id":   node.ID,
+				"name":      name,
+				"error":     err.Error(),
+			})
+		}
+
+		collected++
+		s.logCollected(serverID, dst, size)
+	}
+
+	return collected, nil
+}
+
+// download copies a remote file to dst, removing the partial file on failure
+func (s *HeapDumpService) download(node *docker.RemoteNode, src, dst string) error {
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), heapDumpDownloadTimeout)
+	defer cancel()
+	if err := s.remote.Download(ctx, node, src, out); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+// storeTarget returns an unused path in the dump area for a raw dump
+// Names carry the crash time and the JVM pid (heapdump-20240101-120000-<pid>.hprof), with a
+// counter if a dump of the same pid was written in the same second.
+func (s *HeapDumpService) storeTarget(serverID, rawName string, modTime time.Time) (string, error) {
+	if err := os.MkdirAll(s.serverDir(serverID), 0755); err != nil {
+		return "", fmt.Errorf("failed to create heap dump directory: %w", err)
+	}
+
+	pid := strings.TrimSuffix(strings.TrimPrefix(rawName, docker.HeapDumpPrefix), docker.HeapDumpSuffix)
+	base := heapDumpFilePrefix + modTime.UTC().Format("20060102-150405") + "-" + pid
+	dst := filepath.Join(s.serverDir(serverID), base+docker.HeapDumpSuffix)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(dst); os.IsNotExist(err) {
+			return dst, nil
+		}
+		dst = filepath.Join(s.serverDir(serverID), fmt.Sprintf("%s-%d%s", base, i, docker.HeapDumpSuffix))
+	}
+}
+
+func (s *HeapDumpService) logCollected(serverID, dst string, size int64) {
+	logger.Info("HEAPDUMP: Dump collected", map[string]interface{}{
+		"server_id": serverID,
+		"name":      filepath.Base(dst),
+		"size_mb":   size / 1024 / 1024,
+	})
+}
+
+// isRawHeapDump reports whether a file name is a dump written by the JVM (java_pid<pid>.hprof)
+func isRawHeapDump(name string) bool {
+	if len(name) <= len(docker.HeapDumpPrefix)+len(docker.HeapDumpSuffix) ||
+		!strings.HasPrefix(name, docker.HeapDumpPrefix) || !strings.HasSuffix(name, docker.HeapDumpSuffix) {
+		return false
+	}
+	pid := name[len(docker.HeapDumpPrefix) : len(name)-len(docker.HeapDumpSuffix)]
+	_, err := strconv.ParseUint(pid, 10, 32)
+	return err == nil
+}
+
+// pruneLocked removes dumps past retention, then the oldest ones until the server fits its quota
+func (s *HeapDumpService) pruneLocked(serverID string) error {
+	dumps, err := s.listStored(serverID)
+	if err != nil {
+		return err
+	}
+
+	quota := int64(s.cfg.HeapDumpQuotaMB) * 1024 * 1024
+	var cutoff time.Time
+	if s.cfg.HeapDumpRetentionDays > 0 {
+		cutoff = time.Now().AddDate(0, 0, -s.cfg.HeapDumpRetentionDays)
+	}
+
+	// Newest first: keep dumps while they fit, so the freshest crash is always downloadable
+	var used int64
+	for _, dump := range dumps {
+		expired := !cutoff.IsZero() && dump.CreatedAt.Before(cutoff)
+		if !expired && used+dump.Size <= quota {
+			used += dump.Size
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(s.serverDir(serverID), dump.Name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to prune %s: %w", dump.Name, err)
+		}
+
+		reason := "quota"
+		if expired {
+			reason = "retention"
+		}
+		logger.Info("HEAPDUMP: Dump pruned", map[string]interface{}{
+			"server_id": serverID,
+			"name":      dump.Name,
+			"reason":    reason,
+		})
+	}
+
+	return nil
+}
+
+// listStored lists the dump area of a server, newest first
+func (s *HeapDumpService) listStored(serverID string) ([]HeapDumpInfo, error) {
+	entries, err := os.ReadDir(s.serverDir(serverID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []HeapDumpInfo{}, nil
+		}
+		return nil, fmt.Errorf("failed to read heap dump directory: %w", err)
+	}
+
+	dumps := make([]HeapDumpInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), docker.HeapDumpSuffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		dumps = append(dumps, HeapDumpInfo{
+			Name:          entry.Name(),
+			Size:          info.Size(),
+			SizeFormatted: formatBytes(info.Size()),
+			CreatedAt:     info.ModTime(),
+		})
+	}
+
+	sort.Slice(dumps, func(i, j int) bool {
+		return dumps[i].CreatedAt.After(dumps[j].CreatedAt)
+	})
+
+	return dumps, nil
+}
+
+// storedPath resolves a dump name inside the server's dump area (rejects path traversal)
+func (s *HeapDumpService) storedPath(serverID, name string) (string, error) {
+	if name != filepath.Base(name) || !strings.HasPrefix(name, heapDumpFilePrefix) || !strings.HasSuffix(name, docker.HeapDumpSuffix) {
+		return "", ErrHeapDumpNotFound
+	}
+	return filepath.Join(s.serverDir(serverID), name), nil
+}
+
+// serverDir returns the dump area of a server
+func (s *HeapDumpService) serverDir(serverID string) string {
+	return filepath.Join(s.cfg.HeapDumpStoragePath, serverID)
+}
+
+// moveFile renames src to dst, falling back to copy+remove across filesystems
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	return os.Remove(src)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+// fakeRemoteFiles serves the files of one worker node volume
+type fakeRemoteFiles struct {
+	files    map[string]string // remote path -> content
+	modTimes map[string]time.Time
+	commands []string
+}
+
+func (f *fakeRemoteFiles) Run(ctx context.Context, node *docker.RemoteNode, command string) (string, error) {
+	f.commands = append(f.commands, command)
+	switch {
+	case strings.Contains(command, "find"):
+		var lines []string
+		for path, content := range f.files {
+			lines = append(lines, fmt.Sprintf("%s %d %.6f", filepath.Base(path), len(content), float64(f.modTimes[path].UnixNano())/1e9))
+		}
+		return strings.Join(lines, "\n") + "\n", nil
+	case strings.HasPrefix(command, "'rm'"):
+		for path := range f.files {
+			if strings.HasSuffix(command, "'"+path+"'") {
+				delete(f.files, path)
+			}
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("unexpected command %q", command)
+}
+
+func (f *fakeRemoteFiles) Download(ctx context.Context, node *docker.RemoteNode, remotePath string, w io.Writer) error {
+	content, ok := f.files[remotePath]
+	if !ok {
+		return fmt.Errorf("no such file %s", remotePath)
+	}
+	_, err := io.WriteString(w, content)
+	return err
+}
+
+func newTestHeapDumpService(t *testing.T) *HeapDumpService {
+	t.Helper()
+	dir := t.TempDir()
+	return NewHeapDumpService(nil, &config.Config{
+		ServersBasePath:     filepath.Join(dir, "servers"),
+		HeapDumpStoragePath: filepath.Join(dir, "heapdumps"),
+		HeapDumpQuotaMB:     100,
+	})
+}
+
+func TestHeapDumpCollectRemote(t *testing.T) {
+	crash := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	recent := time.Now()
+
+	tests := []struct {
+		name      string
+		exited    bool
+		files     map[string]time.Time // raw dump name -> mtime
+		wantNames []string
+		wantLeft  int // Dumps still on the node
+	}{
+		{
+			name:      "settled dump",
+			files:     map[string]time.Time{"java_pid7.hprof": crash},
+			wantNames: []string{"heapdump-20260101-120000-7.hprof"},
+		},
+		{
+			name:      "same second, different pids",
+			files:     map[string]time.Time{"java_pid7.hprof": crash, "java_pid8.hprof": crash.Add(500 * time.Millisecond)},
+			wantNames: []string{"heapdump-20260101-120000-7.hprof", "heapdump-20260101-120000-8.hprof"},
+		},
+		{
+			name:     "dump still being written",
+			files:    map[string]time.Time{"java_pid7.hprof": recent},
+			wantLeft: 1,
+		},
+		{
+			name:      "dump of an exited JVM",
+			exited:    true,
+			files:     map[string]time.Time{"java_pid7.hprof": recent},
+			wantNames: []string{"heapdump-" + recent.UTC().Format("20060102-150405") + "-7.hprof"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestHeapDumpService(t)
+			remote := &fakeRemoteFiles{files: map[string]string{}, modTimes: map[string]time.Time{}}
+			for name, modTime := range tt.files {
+				path := remoteServersPath + "/srv-1/" + name
+				remote.files[path] = "dump of " + name
+				remote.modTimes[path] = modTime
+			}
+			s.SetRemoteAccess(nil, remote)
+
+			collected, err := s.collectRemote(&docker.RemoteNode{ID: "node-1"}, "srv-1", tt.exited)
+			if err != nil {
+				t.Fatalf("collectRemote() error = %v", err)
+			}
+			if collected != len(tt.wantNames) {
+				t.Errorf("collectRemote() = %d, want %d", collected, len(tt.wantNames))
+			}
+			for _, name := range tt.wantNames {
+				data, err := os.ReadFile(filepath.Join(s.serverDir("srv-1"), name))
+				if err != nil || !strings.HasPrefix(string(data), "dump of java_pid") {
+					t.Errorf("stored dump %s = %q, %v", name, data, err)
+				}
+			}
+			if len(remote.files) != tt.wantLeft {
+				t.Errorf("%d dumps left on the node, want %d", len(remote.files), tt.wantLeft)
+			}
+		})
+	}
+}
+
+func TestHeapDumpCollectLocalUniqueNames(t *testing.T) {
+	s := newTestHeapDumpService(t)
+	crash := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	volume := filepath.Join(s.cfg.ServersBasePath, "srv-1")
+	if err := os.MkdirAll(volume, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The containerized JVM usually has the same pid after every restart
+	for i := 0; i < 3; i++ {
+		raw := filepath.Join(volume, "java_pid7.hprof")
+		if err := os.WriteFile(raw, []byte(fmt.Sprintf("dump %d", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(raw, crash, crash)
+		if n, err := s.collectLocal("srv-1", false); err != nil || n != 1 {
+			t.Fatalf("collectLocal() = %d, %v; want 1", n, err)
+		}
+	}
+
+	dumps, err := s.listStored("srv-1")
+	if err != nil {
+		t.Fatalf("listStored() error = %v", err)
+	}
+	if len(dumps) != 3 {
+		t.Fatalf("stored %d dumps, want 3 (none overwritten)", len(dumps))
+	}
+	for _, dump := range dumps {
+		if _, err := s.storedPath("srv-1", dump.Name); err != nil {
+			t.Errorf("stored name %q is not downloadable: %v", dump.Name, err)
+		}
+	}
+}
+
+func TestIsRawHeapDump(t *testing.T) {
+	tests := map[string]bool{
+		"java_pid7.hprof":        true,
+		"java_pid12345.hprof":    true,
+		"java_pid.hprof":         false,
+		"java_pid7.hprof.tmp":    false,
+		"java_pid7;rm -rf.hprof": false,
+		"heapdump-7.hprof":       false,
+	}
+	for name, want := range tests {
+		if got := isRawHeapDump(name); got != want {
+			t.Errorf("isRawHeapDump(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
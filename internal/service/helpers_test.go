@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newDryRunDB returns a gorm DB that builds statements without a database connection
+// Writes succeed without effect; use it for services whose side writes (audit, security events) tests ignore.
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 user=test dbname=test sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 gormlogger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry-run DB: %v", err)
+	}
+	return db
+}
+
+// fakeSQLResult is the answer of a fakeSQL handler to one statement
+type fakeSQLResult struct {
+	Columns      []string
+	Rows         [][]driver.Value
+	RowsAffected int64 // Exec defaults to 1 unless Rows is set (use an empty Rows for 0 affected rows)
+	Err          error
+}
+
+// fakeSQL is a scripted database/sql driver: every statement is recorded and answered by handle
+// Unlike newDryRunDB it supports transactions, so tests can check what runs inside them.
+type fakeSQL struct {
+	mu         sync.Mutex
+	statements []string
+	handle     func(query string, args []driver.NamedValue) fakeSQLResult
+}
+
+// newFakeSQLDB returns a gorm postgres DB backed by a fakeSQL driver
+func newFakeSQLDB(t *testing.T, handle func(query string, args []driver.NamedValue) fakeSQLResult) (*gorm.DB, *fakeSQL) {
+	t.Helper()
+	fake := &fakeSQL{handle: handle}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(fake)}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("failed to open fake DB: %v", err)
+	}
+	return db, fake
+}
+
+// Statements returns the recorded statements, including BEGIN, COMMIT and ROLLBACK
+func (f *fakeSQL) Statements() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.statements...)
+}
+
+// Count returns the number of recorded statements containing substr
+func (f *fakeSQL) Count(substr string) int {
+	n := 0
+	for _, statement := range f.Statements() {
+		if strings.Contains(statement, substr) {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeSQL) run(query string, args []driver.NamedValue) fakeSQLResult {
+	f.mu.Lock()
+	f.statements = append(f.statements, query)
+	f.mu.Unlock()
+	if f.handle == nil {
+		return fakeSQLResult{}
+	}
+	return f.handle(query, args)
+}
+
+func (f *fakeSQL) Connect(context.Context) (driver.Conn, error) { return &fakeSQLConn{fake: f}, nil }
+func (f *fakeSQL) Driver() driver.Driver                        { return nil }
+
+type fakeSQLConn struct {
+	fake *fakeSQL
+}
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fakeSQL: prepared statements are not supported")
+}
+
+func (c *fakeSQLConn) Close() error { return nil }
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeSQLConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.fake.run("BEGIN", nil)
+	return &fakeSQLTx{fake: c.fake}, nil
+}
+
+func (c *fakeSQLConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result := c.fake.run(query, args)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	if result.RowsAffected == 0 && result.Rows == nil {
+		result.RowsAffected = 1
+	}
+	return driver.RowsAffected(result.RowsAffected), nil
+}
+
+func (c *fakeSQLConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result := c.fake.run(query, args)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return &fakeSQLRows{columns: result.Columns, rows: result.Rows}, nil
+}
+
+type fakeSQLTx struct {
+	fake *fakeSQL
+}
+
+func (tx *fakeSQLTx) Commit() error   { tx.fake.run("COMMIT", nil); return nil }
+func (tx *fakeSQLTx) Rollback() error { tx.fake.run("ROLLBACK", nil); return nil }
+
+type fakeSQLRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeSQLRows) Columns() []string { return r.columns }
+func (r *fakeSQLRows) Close() error      { return nil }
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// fakePgError mimics a postgres error carrying an SQLSTATE code
+type fakePgError string
+
+func (e fakePgError) Error() string    { return "ERROR: fake postgres error (SQLSTATE " + string(e) + ")" }
+func (e fakePgError) SQLState() string { return string(e) }
+
+// namedArg returns the argument bound to the nth placeholder ($n)
+func namedArg(args []driver.NamedValue, n int) driver.Value {
+	for _, arg := range args {
+		if arg.Ordinal == n {
+			return arg.Value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	idempotencyDefaultTTL    = 24 * time.Hour
+	idempotencyStaleAfter    = 10 * time.Minute // An unfinished request older than this was interrupted (e.g. restart)
+	idempotencyPruneInterval = time.Hour
+)
+
+// IdempotencyService persists Idempotency-Key records so retried requests return the original result
+type IdempotencyService struct {
+	db     *gorm.DB
+	ttl    time.Duration
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewIdempotencyService creates a new idempotency service
+func NewIdempotencyService(db *gorm.DB, cfg *config.Config) *IdempotencyService {
+	ttl, err := time.ParseDuration(cfg.IdempotencyKeyTTL)
+	if err != nil || ttl <= 0 {
+		ttl = idempotencyDefaultTTL
+	}
+	return &IdempotencyService{db: db, ttl: ttl}
+}
+
+// Begin claims key for a request, or returns the record of an earlier request with that key
+// A returned record that is Completed() holds the response to replay; otherwise the caller runs
+// the request and reports its outcome with Complete or Release.
+func (s *IdempotencyService) Begin(userID, key, method, path, fingerprint string) (*models.IdempotencyRecord, error) {
+	now := time.Now()
+	record := &models.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		Method:      method,
+		Path:        path,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+
+	err := s.db.Create(record).Error
+	if err == nil {
+		return record, nil
+	}
+	if !isDuplicateKey(err) {
+		return nil, err
+	}
+
+	var existing models.IdempotencyRecord
+	if err := s.db.Where("user_id = ? AND key = ?", userID, key).First(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	switch {
+	case existing.ExpiresAt.Before(now):
+		// Expired but not pruned yet: start over with this request
+		return s.takeOver(&existing, record, "expires_at < ?", now)
+	case existing.Fingerprint != fingerprint:
+		return nil, models.ErrIdempotencyKeyReused
+	case existing.Completed():
+		return &existing, nil
+	case existing.CreatedAt.Before(now.Add(-idempotencyStaleAfter)):
+		// The first request never finished (API restarted mid-request): let the retry run it
+		return s.takeOver(&existing, record, "status_code = 0 AND created_at < ?", now.Add(-idempotencyStaleAfter))
+	default:
+		return nil, models.ErrIdempotencyInProgress
+	}
+}
+
+// takeOver resets an existing record for a new run of the request
+// The condition guards against two retries taking over the same record at once.
+func (s *IdempotencyService) takeOver(existing, record *models.IdempotencyRecord, condition string, arg interface{}) (*models.IdempotencyRecord, error) {
+	result := s.db.Model(&models.IdempotencyRecord{}).
+		Where("id = ?", existing.ID).
+		Where(condition, arg).
+		Updates(map[string]interface{}{
+			"method":        record.Method,
+			"path":          record.Path,
+			"fingerprint":   record.Fingerprint,
+			"status_code":   0,
+			"content_type":  "",
+			"response_body": nil,
+			"completed_at":  nil,
+			"created_at":    time.Now(),
+			"expires_at":    record.ExpiresAt,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, models.ErrIdempotencyInProgress
+	}
+
+	record.ID = existing.ID
+	return record, nil
+}
+
+// Complete stores the response of a claimed request
+func (s *IdempotencyService) Complete(record *models.IdempotencyRecord, statusCode int, contentType string, body []byte) error {
+	now := time.Now()
+	return s.db.Model(&models.IdempotencyRecord{}).Where("id = ?", record.ID).Updates(map[string]interface{}{
+		"status_code":   statusCode,
+		"content_type":  contentType,
+		"response_body": body,
+		"completed_at":  now,
+	}).Error
+}
+
+// Release deletes a claimed record so a retry runs the request again (e.g. after a server error)
+func (s *IdempotencyService) Release(record *models.IdempotencyRecord) error {
+	return s.db.Where("id = ? AND status_code = 0", record.ID).Delete(&models.IdempotencyRecord{}).Error
+}
+
+// Start begins pruning expired records
+func (s *IdempotencyService) Start() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	go func() {
+		ticker := time.NewTicker(idempotencyPruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.prune()
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts pruning
+func (s *IdempotencyService) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// prune deletes expired records
+func (s *IdempotencyService) prune() {
+	result := s.db.Where("expires_at < ?", time.Now()).Delete(&models.IdempotencyRecord{})
+	if result.Error != nil {
+		logger.Warn("IDEMPOTENCY: Failed to prune expired keys", map[string]interface{}{
+			"error": result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected > 0 {
+		logger.Info("IDEMPOTENCY: Pruned expired keys", map[string]interface{}{
+			"deleted": result.RowsAffected,
+		})
+	}
+}
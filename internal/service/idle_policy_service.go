@@ -0,0 +1,225 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// IdlePolicyEvaluation is the result of evaluating an idle server against its policy
+type IdlePolicyEvaluation struct {
+	Decision       models.ShutdownDecision
+	Rule           string
+	Reason         string
+	TimeoutSeconds int  // Effective idle timeout (day or night)
+	TimeoutReached bool // False while the server is simply not idle long enough
+}
+
+// IdlePolicyService manages configurable idle-shutdown policies and the shutdown audit trail
+type IdlePolicyService struct {
+	db *gorm.DB
+}
+
+// NewIdlePolicyService creates a new idle policy service
+func NewIdlePolicyService(db *gorm.DB) *IdlePolicyService {
+	return &IdlePolicyService{
+		db: db,
+	}
+}
+
+// GetPolicy returns the policy for a server, or nil if none is configured
+func (s *IdlePolicyService) GetPolicy(serverID string) (*models.IdleShutdownPolicy, error) {
+	var policy models.IdleShutdownPolicy
+	err := s.db.Where("server_id = ?", serverID).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SavePolicy validates and creates or replaces the policy for a server
+func (s *IdlePolicyService) SavePolicy(policy *models.IdleShutdownPolicy) error {
+	if policy.Timezone == "" {
+		policy.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(policy.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", policy.Timezone)
+	}
+	if policy.NightStartHour < 0 || policy.NightStartHour > 23 || policy.NightEndHour < 0 || policy.NightEndHour > 23 {
+		return fmt.Errorf("night hours must be between 0 and 23")
+	}
+	if policy.NightTimeoutSeconds < 0 || policy.RestartGraceSeconds < 0 || policy.MinDailyUptimeMinutes < 0 {
+		return fmt.Errorf("timeouts and durations must not be negative")
+	}
+	if policy.MinDailyUptimeMinutes > 24*60 {
+		return fmt.Errorf("min_daily_uptime_minutes cannot exceed 1440")
+	}
+
+	existing, err := s.GetPolicy(policy.ServerID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+	}
+
+	return s.db.Save(policy).Error
+}
+
+// DeletePolicy removes the policy for a server (falls back to the plain idle timeout)
+func (s *IdlePolicyService) DeletePolicy(serverID string) error {
+	return s.db.Where("server_id = ?", serverID).Delete(&models.IdleShutdownPolicy{}).Error
+}
+
+// Evaluate decides whether an idle server may be shut down
+// Rules are checked in order: effective timeout, restart grace, min daily uptime
+// Only called for empty servers: any online player resets the idle timer in MonitoringService.
+func (s *IdlePolicyService) Evaluate(server *models.MinecraftServer, idleDuration time.Duration) IdlePolicyEvaluation {
+	eval := IdlePolicyEvaluation{
+		Decision:       models.ShutdownDecisionShutdown,
+		Rule:           "idle_timeout_day",
+		TimeoutSeconds: server.IdleTimeoutSeconds,
+	}
+
+	policy, err := s.GetPolicy(server.ID)
+	if err != nil {
+		logger.Warn("IDLE-POLICY: Failed to load policy, using plain idle timeout", map[string]interface{}{
+			"server_id": server.ID,
+			"error":     err.Error(),
+		})
+	}
+
+	now := time.Now()
+	if policy != nil {
+		loc, err := time.LoadLocation(policy.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		now = now.In(loc)
+
+		if policy.IsNight(now) && policy.NightTimeoutSeconds > 0 {
+			eval.Rule = "idle_timeout_night"
+			eval.TimeoutSeconds = policy.NightTimeoutSeconds
+		}
+	}
+
+	if idleDuration < time.Duration(eval.TimeoutSeconds)*time.Second {
+		eval.Decision = models.ShutdownDecisionDeferred
+		eval.Reason = fmt.Sprintf("idle for %s, timeout %ds", idleDuration.Round(time.Second), eval.TimeoutSeconds)
+		return eval
+	}
+	eval.TimeoutReached = true
+	eval.Reason = fmt.Sprintf("idle for %s, timeout %ds reached", idleDuration.Round(time.Second), eval.TimeoutSeconds)
+
+	if policy == nil {
+		return eval
+	}
+
+	// Grace period after restart
+	if policy.RestartGraceSeconds > 0 && server.LastStartedAt != nil {
+		graceEnds := server.LastStartedAt.Add(time.Duration(policy.RestartGraceSeconds) * time.Second)
+		if now.Before(graceEnds) {
+			eval.Decision = models.ShutdownDecisionDeferred
+			eval.Rule = "restart_grace"
+			eval.Reason = fmt.Sprintf("restart grace period active until %s", graceEnds.In(now.Location()).Format(time.RFC3339))
+			return eval
+		}
+	}
+
+	// Minimum daily uptime guarantee
+	if policy.MinDailyUptimeMinutes > 0 {
+		uptime, err := s.dailyUptime(server.ID, now)
+		if err != nil {
+			logger.Warn("IDLE-POLICY: Could not calculate daily uptime", map[string]interface{}{
+				"server_id": server.ID,
+				"error":     err.Error(),
+			})
+		} else if required := time.Duration(policy.MinDailyUptimeMinutes) * time.Minute; uptime < required {
+			eval.Decision = models.ShutdownDecisionDeferred
+			eval.Rule = "min_daily_uptime"
+			eval.Reason = fmt.Sprintf("uptime today %s, guaranteed %s", uptime.Round(time.Minute), required)
+			return eval
+		}
+	}
+
+	return eval
+}
+
+// dailyUptime sums billing usage session time since local midnight (including the running session)
+func (s *IdlePolicyService) dailyUptime(serverID string, now time.Time) (time.Duration, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var sessions []models.UsageSession
+	err := s.db.Where("server_id = ? AND (stopped_at IS NULL OR stopped_at >= ?)", serverID, midnight).
+		Find(&sessions).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var total time.Duration
+	for _, session := range sessions {
+		start := session.StartedAt
+		if start.Before(midnight) {
+			start = midnight
+		}
+		end := now
+		if session.StoppedAt != nil {
+			end = *session.StoppedAt
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+
+	return total, nil
+}
+
+// RecordDecision persists an audit entry for a shutdown decision
+func (s *IdlePolicyService) RecordDecision(serverID string, eval IdlePolicyEvaluation, idleDuration time.Duration, playerCount int) {
+	entry := &models.ShutdownAuditEntry{
+		ServerID:       serverID,
+		Decision:       eval.Decision,
+		Rule:           eval.Rule,
+		Reason:         eval.Reason,
+		IdleSeconds:    int(idleDuration.Seconds()),
+		TimeoutSeconds: eval.TimeoutSeconds,
+		PlayerCount:    playerCount,
+	}
+
+	if err := s.db.Create(entry).Error; err != nil {
+		logger.Error("IDLE-POLICY: Failed to record shutdown audit entry", err, map[string]interface{}{
+			"server_id": serverID,
+			"rule":      eval.Rule,
+		})
+		return
+	}
+
+	logger.Info("IDLE-POLICY: Shutdown decision recorded", map[string]interface{}{
+		"server_id": serverID,
+		"decision":  eval.Decision,
+		"rule":      eval.Rule,
+		"reason":    eval.Reason,
+	})
+}
+
+// GetAuditLog returns the most recent shutdown audit entries for a server
+func (s *IdlePolicyService) GetAuditLog(serverID string, limit int) ([]models.ShutdownAuditEntry, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var entries []models.ShutdownAuditEntry
+	err := s.db.Where("server_id = ?", serverID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+// Minimal PDF 1.4 writer for invoices: A4 pages, the standard Helvetica fonts (WinAnsiEncoding,
+// no embedding), text and rules only. Enough for a tabular invoice without pulling in a PDF library.
+
+const (
+	pdfPageWidth    = 595.0 // A4 in points
+	pdfPageHeight   = 842.0
+	pdfMarginLeft   = 50.0
+	pdfMarginRight  = 545.0
+	pdfMarginTop    = 792.0
+	pdfMarginBottom = 90.0
+	pdfLineHeight   = 14.0
+)
+
+// invoiceSeller is the issuing party printed on invoices
+type invoiceSeller struct {
+	Name    string
+	Address string
+	Country string
+	VATID   string
+}
+
+// pdfDocument collects page content streams
+type pdfDocument struct {
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+	y       float64
+}
+
+func newPDFDocument() *pdfDocument {
+	doc := &pdfDocument{}
+	doc.newPage()
+	return doc
+}
+
+func (d *pdfDocument) newPage() {
+	d.current = &bytes.Buffer{}
+	d.pages = append(d.pages, d.current)
+	d.y = pdfMarginTop
+}
+
+// text draws a string at (x, y); bold selects Helvetica-Bold
+func (d *pdfDocument) text(x, y float64, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.current, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(s))
+}
+
+// textRight draws a string right-aligned at x (approximate Helvetica width)
+func (d *pdfDocument) textRight(x, y float64, size float64, bold bool, s string) {
+	d.text(x-pdfTextWidth(s, size), y, size, bold, s)
+}
+
+// rule draws a horizontal line at y
+func (d *pdfDocument) rule(y float64) {
+	fmt.Fprintf(d.current, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMarginLeft, y, pdfMarginRight, y)
+}
+
+// bytes serializes the document
+func (d *pdfDocument) bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	writeObject := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Object layout: 1 catalog, 2 page tree, 3-4 fonts, then (page, content) pairs
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+i*2))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xrefOffset := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefOffset)
+
+	return out.Bytes()
+}
+
+// pdfEscape converts s to WinAnsi bytes and escapes PDF string delimiters
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r == '€':
+			b.WriteByte(0x80)
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r)) // Latin-1 range matches WinAnsi
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// pdfTextWidth approximates the Helvetica width of s (digits are 0.556 em)
+func pdfTextWidth(s string, size float64) float64 {
+	return float64(len([]rune(s))) * 0.556 * size
+}
+
+// pdfTruncate shortens s to at most n runes
+func pdfTruncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+// renderInvoicePDF lays out an invoice: parties, metadata, line table (paginated) and totals
+func renderInvoicePDF(invoice *models.Invoice, seller invoiceSeller) []byte {
+	doc := newPDFDocument()
+
+	// Seller block
+	doc.text(pdfMarginLeft, doc.y, 16, true, seller.Name)
+	doc.y -= 18
+	for _, line := range strings.Split(seller.Address, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		doc.text(pdfMarginLeft, doc.y, 9, false, line)
+		doc.y -= 11
+	}
+	if seller.VATID != "" {
+		doc.text(pdfMarginLeft, doc.y, 9, false, "VAT ID: "+seller.VATID)
+		doc.y -= 11
+	}
+
+	// Invoice metadata (right column)
+	metaY := pdfMarginTop
+	doc.textRight(pdfMarginRight, metaY, 16, true, "INVOICE")
+	metaY -= 18
+	for _, meta := range []string{
+		"Number: " + invoice.Number,
+		"Date: " + invoice.IssuedAt.Format("2006-01-02"),
+		fmt.Sprintf("Period: %s - %s", invoice.PeriodStart.Format("2006-01-02"), invoice.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02")),
+	} {
+		doc.textRight(pdfMarginRight, metaY, 9, false, meta)
+		metaY -= 11
+	}
+
+	// Customer block
+	doc.y -= 24
+	doc.text(pdfMarginLeft, doc.y, 10, true, "Bill to")
+	doc.y -= pdfLineHeight
+	customerLines := []string{invoice.CustomerName}
+	customerLines = append(customerLines, strings.Split(invoice.CustomerAddress, "\n")...)
+	customerLines = append(customerLines, invoice.CustomerCountry, invoice.CustomerEmail)
+	if invoice.CustomerVATID != "" {
+		customerLines = append(customerLines, "VAT ID: "+invoice.CustomerVATID)
+	}
+	for _, line := range customerLines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		doc.text(pdfMarginLeft, doc.y, 10, false, line)
+		doc.y -= 12
+	}
+
+	// Line table
+	columns := func(y float64, bold bool, description, quantity, unitPrice, amount string) {
+		doc.text(pdfMarginLeft, y, 9, bold, description)
+		doc.textRight(380, y, 9, bold, quantity)
+		doc.textRight(460, y, 9, bold, unitPrice)
+		doc.textRight(pdfMarginRight, y, 9, bold, amount)
+	}
+	header := func() {
+		columns(doc.y, true, "Description", "Quantity", "Unit price", "Amount (EUR)")
+		doc.rule(doc.y - 4)
+		doc.y -= pdfLineHeight + 4
+	}
+
+	doc.y -= 24
+	header()
+	for _, line := range invoice.Lines {
+		if doc.y < pdfMarginBottom {
+			doc.text(pdfMarginLeft, 40, 8, false, fmt.Sprintf("%s - continued on next page", invoice.Number))
+			doc.newPage()
+			header()
+		}
+		columns(doc.y, false,
+			pdfTruncate(line.Description, 55),
+			fmt.Sprintf("%.3f %s", line.Quantity, line.Unit),
+			fmt.Sprintf("%.4f", line.UnitPriceEUR),
+			fmt.Sprintf("%.2f", line.AmountEUR))
+		doc.y -= pdfLineHeight
+	}
+
+	// Totals (keep together with the last rule)
+	if doc.y < pdfMarginBottom+60 {
+		doc.newPage()
+	}
+	doc.rule(doc.y + 4)
+	doc.y -= 6
+	for _, total := range []struct {
+		label  string
+		amount float64
+		bold   bool
+	}{
+		{"Net", invoice.NetEUR, false},
+		{fmt.Sprintf("VAT %.1f%%", invoice.VATRate), invoice.VATEUR, false},
+		{"Total", invoice.GrossEUR, true},
+	} {
+		doc.textRight(460, doc.y, 10, total.bold, total.label)
+		doc.textRight(pdfMarginRight, doc.y, 10, total.bold, fmt.Sprintf("%.2f EUR", total.amount))
+		doc.y -= pdfLineHeight
+	}
+
+	if invoice.VATNote != "" {
+		doc.y -= 10
+		doc.text(pdfMarginLeft, doc.y, 9, false, invoice.VATNote)
+	}
+
+	return doc.bytes()
+}
@@ -0,0 +1,590 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvoicePeriodOpen is returned when an invoice is requested for a month that hasn't ended yet
+	ErrInvoicePeriodOpen = errors.New("billing period has not ended yet")
+	// ErrInvoicedByStripe is returned for users whose usage is invoiced by their Stripe subscription
+	ErrInvoicedByStripe = errors.New("usage of this account is invoiced by Stripe")
+	// ErrInvoiceNotFound is returned when an invoice doesn't exist or belongs to another user
+	ErrInvoiceNotFound = errors.New("invoice not found")
+)
+
+// invoicePeriodLayout is the format of billing periods (calendar months)
+const invoicePeriodLayout = "2006-01"
+
+// maxInvoiceNumberRetries bounds retries when two invoices race for the same number
+const maxInvoiceNumberRetries = 3
+
+// InvoiceService generates invoice documents per billing period (calendar month)
+// Compute lines come from the owner's usage sessions (so deleted servers are still invoiced),
+// storage and migration lines from BillingService.GetCostBreakdown of the owner's servers.
+// Invoices are immutable once issued; PDF/CSV are rendered from the stored lines.
+type InvoiceService struct {
+	db             *gorm.DB
+	cfg            *config.Config
+	billingService *BillingService
+	userRepo       *repository.UserRepository
+	serverRepo     *repository.ServerRepository
+
+	ticker   *time.Ticker
+	stopChan chan bool
+}
+
+// NewInvoiceService creates a new invoice service
+func NewInvoiceService(db *gorm.DB, cfg *config.Config, billingService *BillingService, userRepo *repository.UserRepository, serverRepo *repository.ServerRepository) *InvoiceService {
+	return &InvoiceService{
+		db:             db,
+		cfg:            cfg,
+		billingService: billingService,
+		userRepo:       userRepo,
+		serverRepo:     serverRepo,
+		stopChan:       make(chan bool),
+	}
+}
+
+// ParseInvoicePeriod parses a YYYY-MM period into its [start, end) range (local time)
+func ParseInvoicePeriod(period string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(invoicePeriodLayout, period, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period %q (expected YYYY-MM)", period)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// ========================================
+// Generation
+// ========================================
+
+// GenerateInvoice issues the invoice of a user for a closed billing period
+// Idempotent: returns the existing invoice if the period was already invoiced.
+// Returns nil (and no error) if there was nothing to charge in the period.
+func (s *InvoiceService) GenerateInvoice(userID, period string) (*models.Invoice, error) {
+	periodStart, periodEnd, err := ParseInvoicePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	if periodEnd.After(time.Now()) {
+		return nil, ErrInvoicePeriodOpen
+	}
+
+	existing, err := s.findInvoice(userID, period)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	stripeBilled, err := s.isStripeBilled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if stripeBilled {
+		return nil, ErrInvoicedByStripe
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	lines, err := s.collectLines(userID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	invoice := &models.Invoice{
+		UserID:          userID,
+		Period:          period,
+		PeriodStart:     periodStart,
+		PeriodEnd:       periodEnd,
+		IssuedAt:        time.Now(),
+		CustomerName:    user.BillingName,
+		CustomerEmail:   user.Email,
+		CustomerAddress: user.BillingAddress,
+		CustomerCountry: strings.ToUpper(user.BillingCountry),
+		CustomerVATID:   user.VATID,
+		Lines:           lines,
+	}
+	if invoice.CustomerName == "" {
+		invoice.CustomerName = user.Username
+	}
+
+	invoice.VATRate, invoice.VATReverseCharge, invoice.VATNote = models.VATTreatment(s.cfg.InvoiceSellerCountry, invoice.CustomerCountry, invoice.CustomerVATID)
+	for _, line := range lines {
+		invoice.NetEUR += line.AmountEUR
+	}
+	invoice.NetEUR = roundCents(invoice.NetEUR)
+	invoice.VATEUR = roundCents(invoice.NetEUR * invoice.VATRate / 100)
+	invoice.GrossEUR = roundCents(invoice.NetEUR + invoice.VATEUR)
+
+	if err := s.createWithNumber(invoice); err != nil {
+		// A concurrent request may have issued the same period in the meantime
+		if existing, findErr := s.findInvoice(userID, period); findErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	logger.Info("INVOICE: Issued invoice", map[string]interface{}{
+		"invoice_id": invoice.ID,
+		"number":     invoice.Number,
+		"user_id":    userID,
+		"period":     period,
+		"gross_eur":  invoice.GrossEUR,
+		"vat_rate":   invoice.VATRate,
+	})
+
+	return invoice, nil
+}
+
+// collectLines builds the invoice lines of a user for [from, to)
+func (s *InvoiceService) collectLines(userID string, from, to time.Time) ([]models.InvoiceLine, error) {
+	var lines []models.InvoiceLine
+
+	computeLines, err := s.computeLines(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	lines = append(lines, computeLines...)
+
+	servers, err := s.serverRepo.FindByOwner(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch servers: %w", err)
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+
+	for _, server := range servers {
+		breakdown, err := s.billingService.GetCostBreakdown(server.ID, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate costs of server %s: %w", server.ID, err)
+		}
+		totals := breakdown.Totals
+		pricing := breakdown.Pricing
+
+		lines = appendLine(lines, models.InvoiceLine{
+			ServerID: server.ID, ServerName: server.Name, Category: models.InvoiceLineBackup,
+			Description: fmt.Sprintf("Backup storage - %s", server.Name),
+			Quantity:    totals.BackupGBDays, Unit: "GB-d", UnitPriceEUR: pricing.BackupRateEURPerGBDay,
+			AmountEUR: totals.BackupEUR,
+		})
+		lines = appendLine(lines, models.InvoiceLine{
+			ServerID: server.ID, ServerName: server.Name, Category: models.InvoiceLineArchive,
+			Description: fmt.Sprintf("Archive storage - %s", server.Name),
+			Quantity:    totals.ArchiveGBDays, Unit: "GB-d", UnitPriceEUR: pricing.ArchiveRateEURPerGBDay,
+			AmountEUR: totals.ArchiveEUR,
+		})
+		lines = appendLine(lines, models.InvoiceLine{
+			ServerID: server.ID, ServerName: server.Name, Category: models.InvoiceLineColdArchive,
+			Description: fmt.Sprintf("Cold archive storage - %s", server.Name),
+			Quantity:    totals.ColdArchiveGBDays, Unit: "GB-d", UnitPriceEUR: pricing.ColdArchiveRateEURPerGBDay,
+			AmountEUR: totals.ColdArchiveEUR,
+		})
+		lines = appendLine(lines, models.InvoiceLine{
+			ServerID: server.ID, ServerName: server.Name, Category: models.InvoiceLineArchiveRetrieval,
+			Description: fmt.Sprintf("Archive retrieval - %s", server.Name),
+			Quantity:    totals.RetrievalGB, Unit: "GB", UnitPriceEUR: pricing.ArchiveRetrievalRateEURPerGB,
+			AmountEUR: totals.RetrievalEUR,
+		})
+		lines = appendLine(lines, models.InvoiceLine{
+			ServerID: server.ID, ServerName: server.Name, Category: models.InvoiceLineMigration,
+			Description: fmt.Sprintf("Migration transfer - %s", server.Name),
+			Quantity:    totals.MigrationGB, Unit: "GB", UnitPriceEUR: pricing.MigrationRateEURPerGB,
+			AmountEUR: totals.MigrationEUR,
+		})
+	}
+
+	for i := range lines {
+		lines[i].Position = i + 1
+	}
+	return lines, nil
+}
+
+// computeLines aggregates the user's usage sessions overlapping [from, to) per server
+func (s *InvoiceService) computeLines(userID string, from, to time.Time) ([]models.InvoiceLine, error) {
+	var sessions []models.UsageSession
+	err := s.db.Where("owner_id = ? AND started_at < ? AND (stopped_at IS NULL OR stopped_at > ?)", userID, to, from).
+		Order("server_name ASC, started_at ASC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch usage sessions: %w", err)
+	}
+
+	now := time.Now()
+	byServer := make(map[string]*models.InvoiceLine)
+	var order []string
+
+	for _, session := range sessions {
+		start := session.StartedAt
+		if start.Before(from) {
+			start = from
+		}
+		end := now
+		if session.StoppedAt != nil {
+			end = *session.StoppedAt
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		line, ok := byServer[session.ServerID]
+		if !ok {
+			line = &models.InvoiceLine{
+				ServerID:    session.ServerID,
+				ServerName:  session.ServerName,
+				Category:    models.InvoiceLineCompute,
+				Description: fmt.Sprintf("Server runtime - %s", session.ServerName),
+				Unit:        "GB-h",
+			}
+			if session.DedicatedNodeID != "" {
+				line.Description = session.ServerName // "Dedicated node <hostname>"
+			}
+			byServer[session.ServerID] = line
+			order = append(order, session.ServerID)
+		}
+		overlap := end.Sub(start)
+		line.Quantity += float64(session.RAMMb) / 1024.0 * overlap.Hours()
+		line.AmountEUR += models.UsageCostEUR(session.RAMMb, session.HourlyRateEUR, overlap)
+	}
+
+	var lines []models.InvoiceLine
+	for _, serverID := range order {
+		line := byServer[serverID]
+		if line.Quantity > 0 {
+			line.UnitPriceEUR = math.Round(line.AmountEUR/line.Quantity*10000) / 10000
+		}
+		lines = appendLine(lines, *line)
+	}
+	return lines, nil
+}
+
+// appendLine rounds a line and appends it unless it charges nothing
+func appendLine(lines []models.InvoiceLine, line models.InvoiceLine) []models.InvoiceLine {
+	line.AmountEUR = roundCents(line.AmountEUR)
+	line.Quantity = math.Round(line.Quantity*1000) / 1000
+	if line.AmountEUR <= 0 {
+		return lines
+	}
+	return append(lines, line)
+}
+
+// createWithNumber assigns the next sequential number of the year and stores the invoice
+func (s *InvoiceService) createWithNumber(invoice *models.Invoice) error {
+	prefix := fmt.Sprintf("%s-%d-", s.cfg.InvoiceNumberPrefix, invoice.IssuedAt.Year())
+
+	var err error
+	for attempt := 0; attempt < maxInvoiceNumberRetries; attempt++ {
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			var count int64
+			if err := tx.Model(&models.Invoice{}).Where("number LIKE ?", prefix+"%").Count(&count).Error; err != nil {
+				return err
+			}
+			invoice.ID = ""
+			invoice.Number = fmt.Sprintf("%s%06d", prefix, count+1)
+			return tx.Create(invoice).Error
+		})
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to store invoice: %w", err)
+}
+
+// isStripeBilled returns true if the user has an active Stripe metered subscription
+func (s *InvoiceService) isStripeBilled(userID string) (bool, error) {
+	var count int64
+	err := s.db.Model(&models.StripeCustomer{}).
+		Where("user_id = ? AND subscription_id <> ''", userID).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check Stripe subscription: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ========================================
+// Billing Profile
+// ========================================
+
+// UpdateBillingProfile sets the invoice details of a user (applies to invoices issued afterwards)
+func (s *InvoiceService) UpdateBillingProfile(userID, name, address, country, vatID string) (*models.User, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country != "" && !isCountryCode(country) {
+		return nil, fmt.Errorf("invalid country code %q (expected ISO 3166-1 alpha-2)", country)
+	}
+	vatID = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(vatID), " ", ""))
+	if len(vatID) > 32 {
+		return nil, fmt.Errorf("VAT ID too long")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	user.BillingName = strings.TrimSpace(name)
+	user.BillingAddress = strings.TrimSpace(address)
+	user.BillingCountry = country
+	user.VATID = vatID
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update billing profile: %w", err)
+	}
+	return user, nil
+}
+
+// VATTreatment returns the VAT rate, reverse charge flag and note that would apply to the user's invoices
+func (s *InvoiceService) VATTreatment(user *models.User) (float64, bool, string) {
+	return models.VATTreatment(s.cfg.InvoiceSellerCountry, user.BillingCountry, user.VATID)
+}
+
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ========================================
+// Queries
+// ========================================
+
+// ListInvoices returns the invoices of a user, newest first (without lines)
+func (s *InvoiceService) ListInvoices(userID string) ([]models.Invoice, error) {
+	var invoices []models.Invoice
+	err := s.db.Where("user_id = ?", userID).Order("period_start DESC").Find(&invoices).Error
+	return invoices, err
+}
+
+// GetInvoice returns an invoice with its lines; admins may pass an empty userID
+func (s *InvoiceService) GetInvoice(invoiceID, userID string) (*models.Invoice, error) {
+	query := s.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("id = ?", invoiceID)
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var invoice models.Invoice
+	err := query.First(&invoice).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvoiceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (s *InvoiceService) findInvoice(userID, period string) (*models.Invoice, error) {
+	var invoice models.Invoice
+	err := s.db.Preload("Lines").Where("user_id = ? AND period = ?", userID, period).First(&invoice).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// ========================================
+// Export
+// ========================================
+
+// invoiceCSVHeader is shared by single-invoice CSVs and the period export
+var invoiceCSVHeader = []string{
+	"invoice_number", "period", "issued_at", "position", "server_id", "server_name", "category",
+	"description", "quantity", "unit", "unit_price_eur", "net_eur", "vat_rate", "vat_eur", "gross_eur",
+}
+
+// RenderCSV renders an invoice (with lines) as CSV, one row per line
+func (s *InvoiceService) RenderCSV(invoice *models.Invoice) ([]byte, error) {
+	return renderInvoicesCSV([]models.Invoice{*invoice})
+}
+
+// ExportCSV renders all invoice lines of a user for the periods fromPeriod..toPeriod (inclusive)
+func (s *InvoiceService) ExportCSV(userID, fromPeriod, toPeriod string) ([]byte, error) {
+	from, _, err := ParseInvoicePeriod(fromPeriod)
+	if err != nil {
+		return nil, err
+	}
+	_, to, err := ParseInvoicePeriod(toPeriod)
+	if err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("'to' must not be before 'from'")
+	}
+
+	var invoices []models.Invoice
+	err = s.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("user_id = ? AND period_start >= ? AND period_start < ?", userID, from, to).
+		Order("period_start ASC").
+		Find(&invoices).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invoices: %w", err)
+	}
+
+	return renderInvoicesCSV(invoices)
+}
+
+func renderInvoicesCSV(invoices []models.Invoice) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write(invoiceCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, invoice := range invoices {
+		for _, line := range invoice.Lines {
+			vat := roundCents(line.AmountEUR * invoice.VATRate / 100)
+			record := []string{
+				invoice.Number,
+				invoice.Period,
+				invoice.IssuedAt.Format(time.RFC3339),
+				fmt.Sprintf("%d", line.Position),
+				line.ServerID,
+				line.ServerName,
+				string(line.Category),
+				line.Description,
+				fmt.Sprintf("%.3f", line.Quantity),
+				line.Unit,
+				fmt.Sprintf("%.4f", line.UnitPriceEUR),
+				fmt.Sprintf("%.2f", line.AmountEUR),
+				fmt.Sprintf("%.2f", invoice.VATRate),
+				fmt.Sprintf("%.2f", vat),
+				fmt.Sprintf("%.2f", line.AmountEUR+vat),
+			}
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// RenderPDF renders an invoice (with lines) as a PDF document
+func (s *InvoiceService) RenderPDF(invoice *models.Invoice) []byte {
+	return renderInvoicePDF(invoice, invoiceSeller{
+		Name:    s.cfg.InvoiceSellerName,
+		Address: s.cfg.InvoiceSellerAddress,
+		Country: s.cfg.InvoiceSellerCountry,
+		VATID:   s.cfg.InvoiceSellerVATID,
+	})
+}
+
+// ========================================
+// Monthly Worker
+// ========================================
+
+// Start starts the worker that issues last month's invoices (checked daily)
+func (s *InvoiceService) Start() {
+	logger.Info("INVOICE: Starting monthly invoice worker", nil)
+
+	go s.GenerateMonthlyInvoices()
+
+	s.ticker = time.NewTicker(24 * time.Hour)
+
+	go func() {
+		for {
+			select {
+			case <-s.ticker.C:
+				s.GenerateMonthlyInvoices()
+			case <-s.stopChan:
+				logger.Info("INVOICE: Stopping monthly invoice worker", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the monthly invoice worker
+func (s *InvoiceService) Stop() {
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+	s.stopChan <- true
+}
+
+// GenerateMonthlyInvoices issues last month's invoice for every user with usage in that month
+func (s *InvoiceService) GenerateMonthlyInvoices() {
+	period := time.Now().AddDate(0, -1, 0).Format(invoicePeriodLayout)
+	periodStart, periodEnd, _ := ParseInvoicePeriod(period)
+
+	var ownerIDs []string
+	err := s.db.Model(&models.UsageSession{}).
+		Where("started_at < ? AND (stopped_at IS NULL OR stopped_at > ?)", periodEnd, periodStart).
+		Distinct("owner_id").
+		Pluck("owner_id", &ownerIDs).Error
+	if err != nil {
+		logger.Error("INVOICE: Failed to fetch billable users", err, map[string]interface{}{
+			"period": period,
+		})
+		return
+	}
+
+	issued := 0
+	for _, ownerID := range ownerIDs {
+		if existing, err := s.findInvoice(ownerID, period); err == nil && existing != nil {
+			continue // Already issued on an earlier run
+		}
+
+		invoice, err := s.GenerateInvoice(ownerID, period)
+		if errors.Is(err, ErrInvoicedByStripe) {
+			continue
+		}
+		if err != nil {
+			logger.Error("INVOICE: Failed to generate invoice", err, map[string]interface{}{
+				"user_id": ownerID,
+				"period":  period,
+			})
+			continue
+		}
+		if invoice != nil {
+			issued++
+		}
+	}
+
+	if issued > 0 {
+		logger.Info("INVOICE: Monthly invoice run completed", map[string]interface{}{
+			"period":   period,
+			"invoices": issued,
+		})
+	}
+}
+
+// roundCents rounds an amount to euro cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	jobQueueSize        = 1024
+	jobPruneInterval    = time.Hour
+	jobDefaultRetention = 30 * 24 * time.Hour
+	jobStopTimeout      = 5 * time.Second
+)
+
+// Job errors
+var ErrJobNotFound = errors.New("job not found")
+
+// JobService persists the status and progress of background operations in the jobs table
+// Operations report every change with Record; the records are written in order by one worker so
+// a slow database never blocks an operation. Jobs that were unfinished when the API stopped are
+// marked failed on Start, and finished jobs are deleted after JOB_HISTORY_RETENTION.
+// Record is safe on a nil *JobService (nothing is persisted).
+type JobService struct {
+	repo      *repository.JobRepository
+	retention time.Duration
+	queue     chan models.Job
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewJobService creates a new job service
+func NewJobService(repo *repository.JobRepository, cfg *config.Config) *JobService {
+	retention, err := time.ParseDuration(cfg.JobHistoryRetention)
+	if err != nil || retention <= 0 {
+		retention = jobDefaultRetention
+	}
+	return &JobService{
+		repo:      repo,
+		retention: retention,
+		queue:     make(chan models.Job, jobQueueSize),
+	}
+}
+
+// Record queues a status or progress change of a job for writing
+func (s *JobService) Record(job models.Job) {
+	if s == nil {
+		return
+	}
+	select {
+	case s.queue <- job:
+	default:
+		logger.Warn("JOBS: Write queue full, dropping job update", map[string]interface{}{
+			"job_id": job.ID,
+			"status": job.Status,
+		})
+	}
+}
+
+// List returns one page of the jobs owned or requested by userID
+func (s *JobService) List(userID string, page repository.PageRequest) (*repository.Page[models.Job], error) {
+	return s.repo.FindForUserPage(userID, page)
+}
+
+// Get returns a job owned or requested by userID
+func (s *JobService) Get(userID, jobID string) (*models.Job, error) {
+	job, err := s.repo.FindForUser(jobID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	return job, err
+}
+
+// Start marks jobs interrupted by the last shutdown as failed, then writes queued records and prunes old jobs
+func (s *JobService) Start() {
+	if interrupted, err := s.repo.FailUnfinished("interrupted by an API restart"); err != nil {
+		logger.Warn("JOBS: Failed to mark interrupted jobs", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if interrupted > 0 {
+		logger.Info("JOBS: Marked jobs interrupted by the last shutdown as failed", map[string]interface{}{
+			"jobs": interrupted,
+		})
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(jobPruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case job := <-s.queue:
+				s.write(job)
+			case <-ticker.C:
+				s.prune()
+			case <-s.ctx.Done():
+				s.drain()
+				return
+			}
+		}
+	}()
+}
+
+// Stop writes the remaining records and halts the worker
+func (s *JobService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	select {
+	case <-s.done:
+	case <-time.After(jobStopTimeout):
+		logger.Warn("JOBS: Timed out writing the remaining job updates", nil)
+	}
+}
+
+// drain writes the records still queued at shutdown
+func (s *JobService) drain() {
+	for {
+		select {
+		case job := <-s.queue:
+			s.write(job)
+		default:
+			return
+		}
+	}
+}
+
+// write saves a record (later records of the same job simply overwrite it)
+func (s *JobService) write(job models.Job) {
+	if err := s.repo.Save(&job); err != nil {
+		logger.Warn("JOBS: Failed to save job", map[string]interface{}{
+			"job_id": job.ID,
+			"status": job.Status,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// prune deletes jobs that finished longer than the retention ago
+func (s *JobService) prune() {
+	deleted, err := s.repo.DeleteFinishedBefore(time.Now().Add(-s.retention))
+	if err != nil {
+		logger.Warn("JOBS: Failed to prune finished jobs", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if deleted > 0 {
+		logger.Info("JOBS: Pruned finished jobs", map[string]interface{}{
+			"deleted": deleted,
+		})
+	}
+}
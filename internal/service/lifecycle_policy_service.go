@@ -0,0 +1,525 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Limits of the lifecycle thresholds owners may set for their servers
+const (
+	maxSleepAfterMinutes = 7 * 24 * 60 // 1 week
+	maxArchiveAfterHours = 90 * 24     // 90 days
+	maxColdAfterDays     = 365
+
+	// lifecycleNoticeInterval is how often upcoming transitions are checked for notices
+	lifecycleNoticeInterval = 15 * time.Minute
+)
+
+// Sources of an effective lifecycle threshold
+const (
+	LifecycleSourceServer  = "server"
+	LifecycleSourcePlan    = "plan"
+	LifecycleSourceDefault = "default"
+)
+
+// ErrLifecyclePolicyInvalid is wrapped by the errors of invalid lifecycle thresholds
+var ErrLifecyclePolicyInvalid = errors.New("invalid lifecycle policy")
+
+// builtinPlanPolicies are the plan defaults used until an admin configures the plan
+// Reserved servers are paid for 24/7 availability and are never archived automatically.
+var builtinPlanPolicies = map[string]models.LifecyclePolicy{
+	models.PlanReserved: {Plan: models.PlanReserved, ArchiveAfterHours: intPtr(0), ColdAfterDays: intPtr(0)},
+}
+
+// LifecycleThresholds are the effective thresholds of a server, all counted from the last stop
+type LifecycleThresholds struct {
+	SleepAfterMinutes int               `json:"sleep_after_minutes"`
+	ArchiveAfterHours int               `json:"archive_after_hours"` // 0 = never
+	ColdAfterDays     int               `json:"cold_after_days"`     // 0 = never
+	Source            map[string]string `json:"source"`              // Transition -> server, plan or default
+}
+
+// SleepAfter returns the idle time before a stopped server goes to sleep
+func (t LifecycleThresholds) SleepAfter() time.Duration {
+	return time.Duration(t.SleepAfterMinutes) * time.Minute
+}
+
+// ArchiveAfter returns the idle time before a server is archived, 0 = never
+func (t LifecycleThresholds) ArchiveAfter() time.Duration {
+	return time.Duration(t.ArchiveAfterHours) * time.Hour
+}
+
+// ColdAfter returns the idle time before an archive moves to cold storage, 0 = never
+func (t LifecycleThresholds) ColdAfter() time.Duration {
+	return time.Duration(t.ColdAfterDays) * 24 * time.Hour
+}
+
+// LifecycleStep is an upcoming lifecycle transition of a server
+type LifecycleStep struct {
+	Transition string    `json:"transition"`
+	At         time.Time `json:"at"` // In the past if the transition is due with the next worker run
+}
+
+// LifecyclePreview is the lifecycle of a server as shown to its owner
+type LifecyclePreview struct {
+	ServerID     string                  `json:"server_id"`
+	Plan         string                  `json:"plan"`
+	IdleSince    *time.Time              `json:"idle_since"`
+	Thresholds   LifecycleThresholds     `json:"thresholds"`
+	Override     *models.LifecyclePolicy `json:"override"`
+	Schedule     []LifecycleStep         `json:"schedule"`
+	NotifyBefore string                  `json:"notify_before"`
+}
+
+// LifecyclePolicyService resolves the lifecycle thresholds of servers (server override -> plan policy
+// -> default) and emails owners before their server is archived or moved to cold storage
+type LifecyclePolicyService struct {
+	db           *gorm.DB
+	userRepo     *repository.UserRepository
+	emailService *EmailService
+	defaults     LifecycleThresholds
+	notifyBefore time.Duration
+	coldStorage  bool
+	stopChan     chan struct{}
+	stopOnce     sync.Once
+}
+
+// NewLifecyclePolicyService creates a new lifecycle policy service with the built-in defaults
+func NewLifecyclePolicyService(db *gorm.DB, userRepo *repository.UserRepository, emailService *EmailService) *LifecyclePolicyService {
+	return &LifecyclePolicyService{
+		db:           db,
+		userRepo:     userRepo,
+		emailService: emailService,
+		defaults: LifecycleThresholds{
+			SleepAfterMinutes: 5,
+			ArchiveAfterHours: 48,
+			ColdAfterDays:     30,
+		},
+		notifyBefore: 24 * time.Hour,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// SetDefaults sets the thresholds of plans and servers without a policy (from the configuration)
+func (s *LifecyclePolicyService) SetDefaults(sleepAfter, archiveAfter, coldAfter time.Duration) {
+	s.defaults.SleepAfterMinutes = int(sleepAfter / time.Minute)
+	s.defaults.ArchiveAfterHours = int(archiveAfter / time.Hour)
+	s.defaults.ColdAfterDays = int(coldAfter / (24 * time.Hour))
+}
+
+// SetNotifyBefore sets the lead time of the transition emails (0 disables them)
+func (s *LifecyclePolicyService) SetNotifyBefore(d time.Duration) {
+	s.notifyBefore = d
+}
+
+// SetColdStorage tells the service whether archives move to cold storage at all
+func (s *LifecyclePolicyService) SetColdStorage(enabled bool) {
+	s.coldStorage = enabled
+}
+
+// Start begins sending transition notices
+func (s *LifecyclePolicyService) Start() {
+	if s.notifyBefore <= 0 || s.emailService == nil {
+		logger.Info("LIFECYCLE: Transition notices disabled", nil)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(lifecycleNoticeInterval)
+		defer ticker.Stop()
+
+		s.sendNotices()
+		for {
+			select {
+			case <-ticker.C:
+				s.sendNotices()
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	logger.Info("LIFECYCLE: Transition notices started", map[string]interface{}{
+		"notify_before": s.notifyBefore.String(),
+	})
+}
+
+// Stop stops sending transition notices
+func (s *LifecyclePolicyService) Stop() {
+	s.stopOnce.Do(func() { close(s.stopChan) })
+}
+
+// Resolver loads all policies once and returns the threshold lookup for a scan over many servers
+func (s *LifecyclePolicyService) Resolver() (func(server *models.MinecraftServer) LifecycleThresholds, error) {
+	var policies []models.LifecyclePolicy
+	if err := s.db.Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load lifecycle policies: %w", err)
+	}
+	plans := make(map[string]*models.LifecyclePolicy)
+	servers := make(map[string]*models.LifecyclePolicy)
+	for i := range policies {
+		if policies[i].ServerID != "" {
+			servers[policies[i].ServerID] = &policies[i]
+		} else {
+			plans[policies[i].Plan] = &policies[i]
+		}
+	}
+
+	return func(server *models.MinecraftServer) LifecycleThresholds {
+		plan := plans[planOf(server)]
+		if plan == nil {
+			if builtin, ok := builtinPlanPolicies[planOf(server)]; ok {
+				plan = &builtin
+			}
+		}
+		return resolveLifecycleThresholds(s.defaults, plan, servers[server.ID])
+	}, nil
+}
+
+// ThresholdsFor returns the effective thresholds of one server
+func (s *LifecyclePolicyService) ThresholdsFor(server *models.MinecraftServer) (LifecycleThresholds, error) {
+	resolve, err := s.Resolver()
+	if err != nil {
+		return LifecycleThresholds{}, err
+	}
+	return resolve(server), nil
+}
+
+// Preview returns the thresholds of a server and when it will be put to sleep, archived and moved to cold storage
+func (s *LifecyclePolicyService) Preview(server *models.MinecraftServer) (*LifecyclePreview, error) {
+	thresholds, err := s.ThresholdsFor(server)
+	if err != nil {
+		return nil, err
+	}
+	override, err := s.findPolicy("server_id = ?", server.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &LifecyclePreview{
+		ServerID:     server.ID,
+		Plan:         planOf(server),
+		IdleSince:    lifecycleIdleSince(server),
+		Thresholds:   thresholds,
+		Override:     override,
+		Schedule:     lifecycleSchedule(server, thresholds, s.coldStorage),
+		NotifyBefore: s.notifyBefore.String(),
+	}, nil
+}
+
+// SaveServerPolicy validates and creates or replaces the override of a server
+func (s *LifecyclePolicyService) SaveServerPolicy(serverID, userID string, policy *models.LifecyclePolicy) error {
+	if err := validateLifecyclePolicy(policy, false); err != nil {
+		return err
+	}
+	policy.Plan = ""
+	policy.ServerID = serverID
+	policy.UpdatedBy = userID
+	return s.savePolicy(policy, "server_id = ?", serverID)
+}
+
+// DeleteServerPolicy removes the override of a server (the plan policy applies again)
+func (s *LifecyclePolicyService) DeleteServerPolicy(serverID string) error {
+	return s.db.Where("server_id = ?", serverID).Delete(&models.LifecyclePolicy{}).Error
+}
+
+// ListPlanPolicies returns the effective policy of every plan
+func (s *LifecyclePolicyService) ListPlanPolicies() (map[string]LifecycleThresholds, error) {
+	var policies []models.LifecyclePolicy
+	if err := s.db.Where("server_id = ?", "").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	configured := make(map[string]*models.LifecyclePolicy, len(policies))
+	for i := range policies {
+		configured[policies[i].Plan] = &policies[i]
+	}
+
+	result := make(map[string]LifecycleThresholds)
+	for _, plan := range []string{models.PlanPayPerPlay, models.PlanBalanced, models.PlanReserved} {
+		policy := configured[plan]
+		if policy == nil {
+			if builtin, ok := builtinPlanPolicies[plan]; ok {
+				policy = &builtin
+			}
+		}
+		result[plan] = resolveLifecycleThresholds(s.defaults, policy, nil)
+	}
+	return result, nil
+}
+
+// GetPlanPolicy returns the configured policy of a plan, nil if it uses the defaults
+func (s *LifecyclePolicyService) GetPlanPolicy(plan string) (*models.LifecyclePolicy, error) {
+	return s.findPolicy("plan = ? AND server_id = ?", plan, "")
+}
+
+// SavePlanPolicy validates and creates or replaces the policy of a plan
+func (s *LifecyclePolicyService) SavePlanPolicy(plan, userID string, policy *models.LifecyclePolicy) error {
+	if !isKnownPlan(plan) {
+		return fmt.Errorf("%w: unknown plan %q", ErrLifecyclePolicyInvalid, plan)
+	}
+	if err := validateLifecyclePolicy(policy, true); err != nil {
+		return err
+	}
+	policy.Plan = plan
+	policy.ServerID = ""
+	policy.UpdatedBy = userID
+	return s.savePolicy(policy, "plan = ? AND server_id = ?", plan, "")
+}
+
+// DeletePlanPolicy removes the policy of a plan (the built-in defaults apply again)
+func (s *LifecyclePolicyService) DeletePlanPolicy(plan string) error {
+	return s.db.Where("plan = ? AND server_id = ?", plan, "").Delete(&models.LifecyclePolicy{}).Error
+}
+
+func (s *LifecyclePolicyService) findPolicy(query string, args ...interface{}) (*models.LifecyclePolicy, error) {
+	var policy models.LifecyclePolicy
+	err := s.db.Where(query, args...).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (s *LifecyclePolicyService) savePolicy(policy *models.LifecyclePolicy, query string, args ...interface{}) error {
+	existing, err := s.findPolicy(query, args...)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		policy.ID = existing.ID
+		policy.CreatedAt = existing.CreatedAt
+	}
+	return s.db.Save(policy).Error
+}
+
+// sendNotices emails the owners of servers with a transition within notifyBefore, once per transition and stop
+func (s *LifecyclePolicyService) sendNotices() {
+	resolve, err := s.Resolver()
+	if err != nil {
+		logger.Error("LIFECYCLE: Failed to resolve lifecycle policies", err, nil)
+		return
+	}
+
+	var servers []models.MinecraftServer
+	err = s.db.Where("status IN ?", []models.ServerStatus{models.StatusStopped, models.StatusSleeping, models.StatusArchived}).
+		Find(&servers).Error
+	if err != nil {
+		logger.Error("LIFECYCLE: Failed to fetch idle servers", err, nil)
+		return
+	}
+
+	now := time.Now()
+	sent := 0
+	for i := range servers {
+		server := &servers[i]
+		idleSince := lifecycleIdleSince(server)
+		if idleSince == nil {
+			continue
+		}
+		thresholds := resolve(server)
+		for _, step := range lifecycleSchedule(server, thresholds, s.coldStorage) {
+			if !noticeDue(step, *idleSince, s.notifyBefore, now) {
+				continue
+			}
+			if s.sendNotice(server, step, *idleSince, now) {
+				sent++
+			}
+		}
+	}
+
+	if sent > 0 {
+		logger.Info("LIFECYCLE: Transition notices sent", map[string]interface{}{
+			"sent": sent,
+		})
+	}
+}
+
+// sendNotice records and sends one notice; the unique index makes sure an owner gets it only once
+func (s *LifecyclePolicyService) sendNotice(server *models.MinecraftServer, step LifecycleStep, idleSince, now time.Time) bool {
+	notice := &models.LifecycleNotice{
+		ServerID:   server.ID,
+		Transition: step.Transition,
+		IdleSince:  idleSince,
+		DueAt:      step.At,
+		SentAt:     now,
+	}
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(notice)
+	if result.Error != nil {
+		logger.Error("LIFECYCLE: Failed to record transition notice", result.Error, map[string]interface{}{
+			"server_id": server.ID,
+		})
+		return false
+	}
+	if result.RowsAffected == 0 {
+		return false // Already sent
+	}
+
+	owner, err := s.userRepo.FindByID(server.OwnerID)
+	if err != nil {
+		logger.Warn("LIFECYCLE: Owner of server not found, skipping notice", map[string]interface{}{
+			"server_id": server.ID,
+			"owner_id":  server.OwnerID,
+		})
+		return false
+	}
+	if err := s.emailService.SendLifecycleNotice(owner.Email, owner.Username, server.ID, server.Name, step.Transition, step.At); err != nil {
+		logger.Error("LIFECYCLE: Failed to send transition notice", err, map[string]interface{}{
+			"server_id":  server.ID,
+			"transition": step.Transition,
+		})
+		return false
+	}
+	return true
+}
+
+// resolveLifecycleThresholds applies the plan policy and then the server override to the defaults
+func resolveLifecycleThresholds(defaults LifecycleThresholds, plan, server *models.LifecyclePolicy) LifecycleThresholds {
+	result := LifecycleThresholds{
+		SleepAfterMinutes: defaults.SleepAfterMinutes,
+		ArchiveAfterHours: defaults.ArchiveAfterHours,
+		ColdAfterDays:     defaults.ColdAfterDays,
+		Source: map[string]string{
+			models.LifecycleTransitionSleep:   LifecycleSourceDefault,
+			models.LifecycleTransitionArchive: LifecycleSourceDefault,
+			models.LifecycleTransitionCold:    LifecycleSourceDefault,
+		},
+	}
+	for _, layer := range []struct {
+		policy *models.LifecyclePolicy
+		source string
+	}{{plan, LifecycleSourcePlan}, {server, LifecycleSourceServer}} {
+		if layer.policy == nil {
+			continue
+		}
+		if layer.policy.SleepAfterMinutes != nil {
+			result.SleepAfterMinutes = *layer.policy.SleepAfterMinutes
+			result.Source[models.LifecycleTransitionSleep] = layer.source
+		}
+		if layer.policy.ArchiveAfterHours != nil {
+			result.ArchiveAfterHours = *layer.policy.ArchiveAfterHours
+			result.Source[models.LifecycleTransitionArchive] = layer.source
+		}
+		if layer.policy.ColdAfterDays != nil {
+			result.ColdAfterDays = *layer.policy.ColdAfterDays
+			result.Source[models.LifecycleTransitionCold] = layer.source
+		}
+	}
+	return result
+}
+
+// validateLifecyclePolicy checks the thresholds of a policy
+// Only plan policies may disable archiving; owners can postpone it up to maxArchiveAfterHours.
+func validateLifecyclePolicy(policy *models.LifecyclePolicy, plan bool) error {
+	if policy.SleepAfterMinutes == nil && policy.ArchiveAfterHours == nil && policy.ColdAfterDays == nil {
+		return fmt.Errorf("%w: set at least one threshold", ErrLifecyclePolicyInvalid)
+	}
+	if v := policy.SleepAfterMinutes; v != nil && (*v < 0 || *v > maxSleepAfterMinutes) {
+		return fmt.Errorf("%w: sleep_after_minutes must be between 0 and %d", ErrLifecyclePolicyInvalid, maxSleepAfterMinutes)
+	}
+	if v := policy.ArchiveAfterHours; v != nil {
+		minHours := 1
+		if plan {
+			minHours = 0
+		}
+		if *v < minHours || *v > maxArchiveAfterHours {
+			return fmt.Errorf("%w: archive_after_hours must be between %d and %d", ErrLifecyclePolicyInvalid, minHours, maxArchiveAfterHours)
+		}
+	}
+	if v := policy.ColdAfterDays; v != nil && (*v < 0 || *v > maxColdAfterDays) {
+		return fmt.Errorf("%w: cold_after_days must be between 0 and %d", ErrLifecyclePolicyInvalid, maxColdAfterDays)
+	}
+	return nil
+}
+
+// lifecycleIdleSince returns the time the idle thresholds count from (the archive date for servers without a stop)
+func lifecycleIdleSince(server *models.MinecraftServer) *time.Time {
+	if server.LastStoppedAt != nil {
+		return server.LastStoppedAt
+	}
+	return server.ArchivedAt
+}
+
+// lifecycleSchedule returns the transitions still ahead of a server in its current state
+// Running servers have none: the thresholds count from the next stop.
+func lifecycleSchedule(server *models.MinecraftServer, thresholds LifecycleThresholds, coldStorage bool) []LifecycleStep {
+	idleSince := lifecycleIdleSince(server)
+	if idleSince == nil {
+		return nil
+	}
+
+	var steps []LifecycleStep
+	switch server.Status {
+	case models.StatusStopped, models.StatusSleeping:
+		if server.Status == models.StatusStopped && server.LifecyclePhase != models.PhaseSleep {
+			steps = append(steps, LifecycleStep{models.LifecycleTransitionSleep, idleSince.Add(thresholds.SleepAfter())})
+		}
+		if thresholds.ArchiveAfterHours == 0 {
+			return steps
+		}
+		archiveAt := idleSince.Add(thresholds.ArchiveAfter())
+		steps = append(steps, LifecycleStep{models.LifecycleTransitionArchive, archiveAt})
+		if coldStorage && thresholds.ColdAfterDays > 0 {
+			steps = append(steps, LifecycleStep{models.LifecycleTransitionCold, maxTime(archiveAt, idleSince.Add(thresholds.ColdAfter()))})
+		}
+	case models.StatusArchived:
+		if coldStorage && thresholds.ColdAfterDays > 0 && server.ArchiveTier != models.ArchiveTierCold {
+			steps = append(steps, LifecycleStep{models.LifecycleTransitionCold, idleSince.Add(thresholds.ColdAfter())})
+		}
+	}
+	return steps
+}
+
+// noticeDue reports whether the owner should be told about step now
+// Transitions shorter than the lead time (like sleep) are not announced.
+func noticeDue(step LifecycleStep, idleSince time.Time, notifyBefore time.Duration, now time.Time) bool {
+	if notifyBefore <= 0 || step.Transition == models.LifecycleTransitionSleep {
+		return false
+	}
+	if step.At.Sub(idleSince) <= notifyBefore {
+		return false
+	}
+	return !now.Before(step.At.Add(-notifyBefore)) && now.Before(step.At)
+}
+
+// lifecycleNoticeText describes a transition in notice emails
+func lifecycleNoticeText(transition string) (title, effect string) {
+	switch transition {
+	case models.LifecycleTransitionCold:
+		return "Your server archive moves to cold storage",
+			"its archive will be moved to cold storage. Starting it afterwards takes longer because the archive has to be restored first"
+	default:
+		return "Your server will be archived",
+			"it will be archived. Your files are kept; the next start takes a little longer because the server is unpacked first"
+	}
+}
+
+func planOf(server *models.MinecraftServer) string {
+	if server.Plan == "" {
+		return models.PlanPayPerPlay
+	}
+	return server.Plan
+}
+
+func isKnownPlan(plan string) bool {
+	return plan == models.PlanPayPerPlay || plan == models.PlanBalanced || plan == models.PlanReserved
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+func intPtr(v int) *int {
+	return &v
+}
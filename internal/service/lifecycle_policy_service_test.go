@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestResolveLifecycleThresholds(t *testing.T) {
+	defaults := LifecycleThresholds{SleepAfterMinutes: 5, ArchiveAfterHours: 48, ColdAfterDays: 30}
+	plan := &models.LifecyclePolicy{Plan: models.PlanBalanced, ArchiveAfterHours: intPtr(72), ColdAfterDays: intPtr(60)}
+	server := &models.LifecyclePolicy{ServerID: "srv", ArchiveAfterHours: intPtr(168)}
+
+	got := resolveLifecycleThresholds(defaults, plan, server)
+	if got.SleepAfterMinutes != 5 || got.ArchiveAfterHours != 168 || got.ColdAfterDays != 60 {
+		t.Errorf("thresholds = %+v, want 5 min / 168 h / 60 days", got)
+	}
+	wantSources := map[string]string{
+		models.LifecycleTransitionSleep:   LifecycleSourceDefault,
+		models.LifecycleTransitionArchive: LifecycleSourceServer,
+		models.LifecycleTransitionCold:    LifecycleSourcePlan,
+	}
+	for transition, want := range wantSources {
+		if got.Source[transition] != want {
+			t.Errorf("source of %s = %q, want %q", transition, got.Source[transition], want)
+		}
+	}
+
+	// The built-in reserved plan never archives, an owner override still can
+	reserved := builtinPlanPolicies[models.PlanReserved]
+	if got := resolveLifecycleThresholds(defaults, &reserved, nil); got.ArchiveAfter() != 0 || got.ColdAfter() != 0 {
+		t.Errorf("reserved thresholds = %+v, want archiving disabled", got)
+	}
+	if got := resolveLifecycleThresholds(defaults, &reserved, server); got.ArchiveAfter() != 168*time.Hour {
+		t.Errorf("reserved with override archives after %s, want 168h", got.ArchiveAfter())
+	}
+}
+
+func TestValidateLifecyclePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy models.LifecyclePolicy
+		plan   bool
+		valid  bool
+	}{
+		{"empty", models.LifecyclePolicy{}, false, false},
+		{"owner postpones archiving", models.LifecyclePolicy{ArchiveAfterHours: intPtr(24 * 14)}, false, true},
+		{"owner disables archiving", models.LifecyclePolicy{ArchiveAfterHours: intPtr(0)}, false, false},
+		{"plan disables archiving", models.LifecyclePolicy{ArchiveAfterHours: intPtr(0)}, true, true},
+		{"archive too late", models.LifecyclePolicy{ArchiveAfterHours: intPtr(maxArchiveAfterHours + 1)}, true, false},
+		{"negative sleep", models.LifecyclePolicy{SleepAfterMinutes: intPtr(-1)}, false, false},
+		{"cold disabled", models.LifecyclePolicy{ColdAfterDays: intPtr(0)}, false, true},
+	}
+	for _, tt := range tests {
+		err := validateLifecyclePolicy(&tt.policy, tt.plan)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: validateLifecyclePolicy() = %v, want valid=%v", tt.name, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrLifecyclePolicyInvalid) {
+			t.Errorf("%s: error %v does not wrap ErrLifecyclePolicyInvalid", tt.name, err)
+		}
+	}
+}
+
+func TestLifecycleSchedule(t *testing.T) {
+	stoppedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	thresholds := LifecycleThresholds{SleepAfterMinutes: 5, ArchiveAfterHours: 48, ColdAfterDays: 1}
+
+	server := &models.MinecraftServer{Status: models.StatusStopped, LastStoppedAt: &stoppedAt}
+	steps := lifecycleSchedule(server, thresholds, true)
+	want := []LifecycleStep{
+		{models.LifecycleTransitionSleep, stoppedAt.Add(5 * time.Minute)},
+		{models.LifecycleTransitionArchive, stoppedAt.Add(48 * time.Hour)},
+		{models.LifecycleTransitionCold, stoppedAt.Add(48 * time.Hour)}, // Not before the archive exists
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("schedule = %+v, want %+v", steps, want)
+	}
+	for i := range want {
+		if steps[i].Transition != want[i].Transition || !steps[i].At.Equal(want[i].At) {
+			t.Errorf("step %d = %+v, want %+v", i, steps[i], want[i])
+		}
+	}
+
+	if steps := lifecycleSchedule(server, thresholds, false); len(steps) != 2 {
+		t.Errorf("schedule without cold storage = %+v, want sleep and archive", steps)
+	}
+	thresholds.ArchiveAfterHours = 0
+	if steps := lifecycleSchedule(server, thresholds, true); len(steps) != 1 {
+		t.Errorf("schedule with archiving disabled = %+v, want sleep only", steps)
+	}
+
+	running := &models.MinecraftServer{Status: models.StatusRunning, LastStoppedAt: &stoppedAt}
+	if steps := lifecycleSchedule(running, thresholds, true); len(steps) != 0 {
+		t.Errorf("schedule of a running server = %+v, want none", steps)
+	}
+}
+
+func TestNoticeDue(t *testing.T) {
+	idleSince := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	archive := LifecycleStep{models.LifecycleTransitionArchive, idleSince.Add(48 * time.Hour)}
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name string
+		step LifecycleStep
+		now  time.Time
+		want bool
+	}{
+		{"too early", archive, idleSince.Add(23 * time.Hour), false},
+		{"within lead time", archive, idleSince.Add(30 * time.Hour), true},
+		{"already due", archive, idleSince.Add(49 * time.Hour), false},
+		{"sleep is never announced", LifecycleStep{models.LifecycleTransitionSleep, idleSince.Add(5 * time.Minute)}, idleSince, false},
+		{"threshold shorter than lead time", LifecycleStep{models.LifecycleTransitionArchive, idleSince.Add(12 * time.Hour)}, idleSince.Add(time.Hour), false},
+	}
+	for _, tt := range tests {
+		if got := noticeDue(tt.step, idleSince, day, tt.now); got != tt.want {
+			t.Errorf("%s: noticeDue() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 type LifecycleService struct {
 	db         *gorm.DB
 	serverRepo *repository.ServerRepository
+	policies   *LifecyclePolicyService // Optional: per plan/server sleep thresholds (default: 5 minutes)
 	stopChan   chan struct{}
 }
 
@@ -26,6 +27,11 @@ func NewLifecycleService(db *gorm.DB, serverRepo *repository.ServerRepository) *
 	}
 }
 
+// SetLifecyclePolicies makes the sleep transition use the configured lifecycle thresholds
+func (s *LifecycleService) SetLifecyclePolicies(policies *LifecyclePolicyService) {
+	s.policies = policies
+}
+
 // Start begins the lifecycle management workers
 func (s *LifecycleService) Start() {
 	logger.Info("Starting lifecycle service", nil)
@@ -67,16 +73,20 @@ func (s *LifecycleService) sleepWorker(interval time.Duration) {
 func (s *LifecycleService) processSleepTransitions() {
 	// Find servers that are:
 	// 1. Status = stopped
-	// 2. LastStoppedAt > 5 minutes ago
+	// 2. LastStoppedAt > sleep threshold ago (5 minutes unless a lifecycle policy says otherwise)
 	// 3. LifecyclePhase != sleep (not already sleeping)
 
-	fiveMinutesAgo := time.Now().Add(-5 * time.Minute)
+	now := time.Now()
+	cutoff := now.Add(-5 * time.Minute)
+	if s.policies != nil {
+		cutoff = now // Thresholds differ per server, filtered below
+	}
 
 	var servers []models.MinecraftServer
 	err := s.db.Where("status = ? AND lifecycle_phase != ? AND last_stopped_at IS NOT NULL AND last_stopped_at < ?",
 		models.StatusStopped,
 		models.PhaseSleep,
-		fiveMinutesAgo,
+		cutoff,
 	).Find(&servers).Error
 
 	if err != nil {
@@ -84,6 +94,21 @@ func (s *LifecycleService) processSleepTransitions() {
 		return
 	}
 
+	if s.policies != nil {
+		resolve, err := s.policies.Resolver()
+		if err != nil {
+			logger.Error("Failed to resolve lifecycle policies", err, nil)
+			return
+		}
+		due := servers[:0]
+		for _, server := range servers {
+			if now.Sub(*server.LastStoppedAt) >= resolve(&server).SleepAfter() {
+				due = append(due, server)
+			}
+		}
+		servers = due
+	}
+
 	if len(servers) == 0 {
 		logger.Debug("No servers to transition to sleep", nil)
 		return
@@ -99,7 +124,8 @@ func (s *LifecycleService) processSleepTransitions() {
 			"lifecycle_phase": models.PhaseSleep,
 		}
 
-		err := s.db.Model(&server).Updates(updates).Error
+		outboxEvent := events.BillingPhaseChangedOutboxEvent(server.ID, string(oldPhase), string(models.PhaseSleep))
+		err := s.serverRepo.UpdateFieldsWithEvent(server.ID, updates, outboxEvent)
 		if err != nil {
 			logger.Error("Failed to transition server to sleep", err, map[string]interface{}{
 				"server_id": server.ID,
@@ -148,7 +174,7 @@ func (s *LifecycleService) WakeFromSleep(serverID string) error {
 		"lifecycle_phase": models.PhaseActive,
 	}
 
-	err = s.db.Model(&server).Updates(updates).Error
+	err = s.serverRepo.UpdateFields(server.ID, updates)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// ErrMaintenanceMode is wrapped by the error returned while maintenance mode is on
+var ErrMaintenanceMode = errors.New("maintenance mode")
+
+// defaultMaintenanceMessage is shown to users if the admin gave no message
+const defaultMaintenanceMessage = "PayPerPlay is undergoing maintenance. Running servers are not affected; starting, creating and deleting servers is paused for a few minutes."
+
+// MaintenanceError rejects an action during maintenance with the admin's message
+type MaintenanceError struct {
+	Message string
+}
+
+func (e *MaintenanceError) Error() string { return e.Message }
+
+// Unwrap makes errors.Is(err, ErrMaintenanceMode) match
+func (e *MaintenanceError) Unwrap() error { return ErrMaintenanceMode }
+
+// MaintenanceStatus is the maintenance mode state reported to admins and /health
+type MaintenanceStatus struct {
+	Enabled          bool       `json:"enabled"`
+	Message          string     `json:"message,omitempty"`
+	Since            *time.Time `json:"since,omitempty"`
+	EnabledBy        string     `json:"enabled_by,omitempty"`
+	ActiveOperations int        `json:"active_operations"` // Queued and running starts, backups, restores, migrations, ...
+	Drained          bool       `json:"drained"`           // Enabled and no operation is left, safe to deploy
+}
+
+// MaintenanceService is the global maintenance mode switch. While it is on, new server starts and
+// destructive operations are rejected with the admin's message, the scaling engine and the migration
+// worker are paused, and operations that were already running finish normally. Deploy automation
+// polls /health until the API reports drained. The state is in-memory and ends with a restart.
+// All methods are safe on a nil *MaintenanceService (maintenance mode is never on).
+type MaintenanceService struct {
+	opLimiter  *OperationLimiter
+	scaling    *conductor.ScalingEngine // Nil if scaling is not configured
+	migrations *MigrationService
+
+	mu             sync.Mutex
+	enabled        bool
+	message        string
+	since          time.Time
+	enabledBy      string
+	scalingResumes bool // The scaling engine was enabled before maintenance
+}
+
+// NewMaintenanceService creates a new maintenance switch (scaling may be nil)
+func NewMaintenanceService(opLimiter *OperationLimiter, scaling *conductor.ScalingEngine, migrations *MigrationService) *MaintenanceService {
+	return &MaintenanceService{
+		opLimiter:  opLimiter,
+		scaling:    scaling,
+		migrations: migrations,
+	}
+}
+
+// Enable turns maintenance mode on (or changes the message if it is already on)
+func (s *MaintenanceService) Enable(message, userID string) MaintenanceStatus {
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+
+	s.mu.Lock()
+	wasEnabled := s.enabled
+	s.message = message
+	if !wasEnabled {
+		s.enabled = true
+		s.since = time.Now()
+		s.enabledBy = userID
+		if s.scaling != nil {
+			s.scalingResumes = s.scaling.IsEnabled()
+			s.scaling.Disable()
+		}
+		if s.migrations != nil {
+			s.migrations.Pause()
+		}
+	}
+	s.mu.Unlock()
+
+	if !wasEnabled {
+		logger.Warn("MAINTENANCE: Maintenance mode enabled", map[string]interface{}{
+			"enabled_by":        userID,
+			"message":           message,
+			"active_operations": s.opLimiter.ActiveCount(),
+		})
+	}
+	return s.Status()
+}
+
+// Disable turns maintenance mode off and resumes the paused workers
+func (s *MaintenanceService) Disable(userID string) MaintenanceStatus {
+	s.mu.Lock()
+	wasEnabled := s.enabled
+	if wasEnabled {
+		s.enabled = false
+		if s.scaling != nil && s.scalingResumes {
+			s.scaling.Enable()
+		}
+		if s.migrations != nil {
+			s.migrations.Resume()
+		}
+		logger.Info("MAINTENANCE: Maintenance mode disabled", map[string]interface{}{
+			"disabled_by": userID,
+			"duration":    time.Since(s.since).Round(time.Second).String(),
+		})
+	}
+	s.message, s.enabledBy, s.since, s.scalingResumes = "", "", time.Time{}, false
+	s.mu.Unlock()
+
+	return s.Status()
+}
+
+// Status returns whether maintenance mode is on and how many operations are still running
+func (s *MaintenanceService) Status() MaintenanceStatus {
+	if s == nil {
+		return MaintenanceStatus{}
+	}
+	active := s.opLimiter.ActiveCount()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := MaintenanceStatus{
+		Enabled:          s.enabled,
+		ActiveOperations: active,
+		Drained:          s.enabled && active == 0,
+	}
+	if s.enabled {
+		since := s.since
+		status.Message, status.Since, status.EnabledBy = s.message, &since, s.enabledBy
+	}
+	return status
+}
+
+// Check returns a *MaintenanceError while maintenance mode is on (middleware.MaintenanceGate)
+func (s *MaintenanceService) Check() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return nil
+	}
+	return &MaintenanceError{Message: s.message}
+}
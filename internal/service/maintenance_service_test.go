@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	limiter := NewOperationLimiter(nil, &config.Config{})
+	migrations := &MigrationService{}
+	s := NewMaintenanceService(limiter, nil, migrations)
+
+	if err := s.Check(); err != nil {
+		t.Fatalf("Check before maintenance = %v", err)
+	}
+
+	// A migration that is already running when maintenance begins
+	op := limiter.Track("owner-1", "owner-1", OperationMigration, "server-1", "migration-1", nil)
+
+	status := s.Enable("", "admin-1")
+	if !status.Enabled || status.Message != defaultMaintenanceMessage || status.EnabledBy != "admin-1" || status.Since == nil {
+		t.Errorf("status after Enable = %+v", status)
+	}
+	if status.ActiveOperations != 1 || status.Drained {
+		t.Errorf("running operation not reported: %+v", status)
+	}
+	if !migrations.paused.Load() {
+		t.Error("migration worker not paused")
+	}
+
+	err := s.Check()
+	var maintenanceErr *MaintenanceError
+	if !errors.Is(err, ErrMaintenanceMode) || !errors.As(err, &maintenanceErr) || err.Error() != defaultMaintenanceMessage {
+		t.Errorf("Check during maintenance = %v", err)
+	}
+	if s.Enable("Back at 22:15 UTC", "admin-2").EnabledBy != "admin-1" || s.Check().Error() != "Back at 22:15 UTC" {
+		t.Error("changing the message replaced the maintenance window")
+	}
+
+	limiter.Finish(op, nil)
+	if status := s.Status(); !status.Drained {
+		t.Errorf("status after the operation finished = %+v, want drained", status)
+	}
+
+	if status := s.Disable("admin-1"); status.Enabled || status.Drained || status.Message != "" {
+		t.Errorf("status after Disable = %+v", status)
+	}
+	if s.Check() != nil || migrations.paused.Load() {
+		t.Error("maintenance still active after Disable")
+	}
+
+	var disabled *MaintenanceService
+	if disabled.Check() != nil || disabled.Status().Enabled {
+		t.Error("nil maintenance service reports maintenance")
+	}
+}
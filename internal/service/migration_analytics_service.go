@@ -0,0 +1,275 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// migrationHistoryWindow is the history the cost-optimization policy is tuned from
+	migrationHistoryWindow = 30 * 24 * time.Hour
+	// migrationStatsTTL is how long the cached policy statistics are reused
+	migrationStatsTTL = 10 * time.Minute
+	// minMigrationSamples is the number of finished migrations a group needs before it is trusted
+	minMigrationSamples = 3
+)
+
+// MigrationStats aggregates finished migrations of one node pair and/or world size bucket
+type MigrationStats struct {
+	FromNodeID     string  `json:"from_node_id,omitempty"`
+	ToNodeID       string  `json:"to_node_id,omitempty"`
+	WorldSize      string  `json:"world_size,omitempty"` // unknown, <1GB, 1-5GB, 5-20GB, >20GB
+	Total          int     `json:"total"`
+	Completed      int     `json:"completed"`
+	Failed         int     `json:"failed"`
+	SuccessRate    float64 `json:"success_rate"` // 0-100%
+	AvgDurationSec float64 `json:"avg_duration_sec"`
+	AvgDowntimeSec float64 `json:"avg_downtime_sec"`
+	P90DowntimeSec float64 `json:"p90_downtime_sec"`
+	MaxDowntimeSec float64 `json:"max_downtime_sec"`
+	durations      []float64
+	downtimes      []float64
+}
+
+// MigrationAnalytics is the historical migration report
+type MigrationAnalytics struct {
+	Since         time.Time        `json:"since"`
+	Overall       MigrationStats   `json:"overall"`
+	ByNodePair    []MigrationStats `json:"by_node_pair"`
+	ByWorldSize   []MigrationStats `json:"by_world_size"`
+	ByPairAndSize []MigrationStats `json:"by_node_pair_and_world_size"`
+	ImpactBudget  float64          `json:"impact_budget_player_seconds"` // 0 = no limit
+}
+
+// MigrationAnalyticsService analyzes finished migrations (duration, failures, downtime)
+// and tells cost optimization whether a migration fits the player-impact budget
+type MigrationAnalyticsService struct {
+	migrationRepo *repository.MigrationRepository
+	backupRepo    *repository.BackupRepository
+	impactBudget  float64 // Max expected player-seconds of downtime (0 = no limit)
+
+	// Cached statistics for AllowsMigration
+	statsMu      sync.Mutex
+	stats        *MigrationAnalytics
+	statsUpdated time.Time
+}
+
+// NewMigrationAnalyticsService creates a new migration analytics service
+func NewMigrationAnalyticsService(migrationRepo *repository.MigrationRepository, backupRepo *repository.BackupRepository, cfg *config.Config) *MigrationAnalyticsService {
+	return &MigrationAnalyticsService{
+		migrationRepo: migrationRepo,
+		backupRepo:    backupRepo,
+		impactBudget:  cfg.MigrationImpactBudget,
+	}
+}
+
+// GetAnalytics aggregates the migrations finished since the given time
+func (s *MigrationAnalyticsService) GetAnalytics(since time.Time) (*MigrationAnalytics, error) {
+	migrations, err := s.migrationRepo.FindFinishedSince(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	pairs := map[string]*MigrationStats{}
+	sizes := map[string]*MigrationStats{}
+	pairSizes := map[string]*MigrationStats{}
+	overall := &MigrationStats{}
+
+	for i := range migrations {
+		m := &migrations[i]
+		bucket := worldSizeBucket(m.WorldSizeBytes)
+
+		pair := statsGroup(pairs, m.FromNodeID+"|"+m.ToNodeID, MigrationStats{FromNodeID: m.FromNodeID, ToNodeID: m.ToNodeID})
+		size := statsGroup(sizes, bucket, MigrationStats{WorldSize: bucket})
+		pairSize := statsGroup(pairSizes, m.FromNodeID+"|"+m.ToNodeID+"|"+bucket, MigrationStats{FromNodeID: m.FromNodeID, ToNodeID: m.ToNodeID, WorldSize: bucket})
+
+		for _, stats := range []*MigrationStats{overall, pair, size, pairSize} {
+			stats.add(m)
+		}
+	}
+
+	analytics := &MigrationAnalytics{
+		Since:         since,
+		ImpactBudget:  s.impactBudget,
+		ByNodePair:    finishStats(pairs),
+		ByWorldSize:   finishStats(sizes),
+		ByPairAndSize: finishStats(pairSizes),
+	}
+	overall.finish()
+	analytics.Overall = *overall
+
+	return analytics, nil
+}
+
+// AllowsMigration reports whether moving the server between the nodes stays within the player-impact budget
+// The expected impact is the historical p90 downtime (most specific group with enough samples:
+// node pair + world size, node pair, world size) times the players online (at least one).
+// Without history or without a budget every migration is allowed.
+func (s *MigrationAnalyticsService) AllowsMigration(server *models.MinecraftServer, fromNodeID, toNodeID string) (float64, bool) {
+	if s == nil || s.impactBudget <= 0 {
+		return 0, true
+	}
+
+	analytics := s.cachedAnalytics()
+	if analytics == nil {
+		return 0, true
+	}
+
+	bucket := worldSizeBucket(s.worldSize(server.ID))
+	stats := findStats(analytics.ByPairAndSize, fromNodeID, toNodeID, bucket)
+	if stats == nil {
+		stats = findStats(analytics.ByNodePair, fromNodeID, toNodeID, "")
+	}
+	if stats == nil {
+		stats = findStats(analytics.ByWorldSize, "", "", bucket)
+	}
+	if stats == nil {
+		return 0, true
+	}
+
+	players := server.CurrentPlayerCount
+	if players < 1 {
+		players = 1
+	}
+	expected := stats.P90DowntimeSec * float64(players)
+
+	return expected, expected <= s.impactBudget
+}
+
+// cachedAnalytics returns the policy statistics, refreshing them every migrationStatsTTL
+func (s *MigrationAnalyticsService) cachedAnalytics() *MigrationAnalytics {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+
+	if s.stats != nil && time.Since(s.statsUpdated) < migrationStatsTTL {
+		return s.stats
+	}
+
+	analytics, err := s.GetAnalytics(time.Now().Add(-migrationHistoryWindow))
+	if err != nil {
+		logger.Warn("MIGRATION-ANALYTICS: Failed to refresh statistics", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return s.stats // Keep using the previous statistics
+	}
+
+	s.stats = analytics
+	s.statsUpdated = time.Now()
+	return s.stats
+}
+
+// worldSize estimates a server's world size from its latest completed backup
+func (s *MigrationAnalyticsService) worldSize(serverID string) int64 {
+	if s.backupRepo == nil {
+		return 0
+	}
+	backup, err := s.backupRepo.FindLatestBackupForServer(serverID)
+	if err != nil {
+		return 0
+	}
+	return backup.OriginalSize
+}
+
+// worldSizeBucket groups world sizes for the analytics
+func worldSizeBucket(bytes int64) string {
+	const gb = 1024 * 1024 * 1024
+	switch {
+	case bytes <= 0:
+		return "unknown"
+	case bytes < 1*gb:
+		return "<1GB"
+	case bytes < 5*gb:
+		return "1-5GB"
+	case bytes < 20*gb:
+		return "5-20GB"
+	default:
+		return ">20GB"
+	}
+}
+
+// statsGroup returns the group for key, creating it from template
+func statsGroup(groups map[string]*MigrationStats, key string, template MigrationStats) *MigrationStats {
+	stats, ok := groups[key]
+	if !ok {
+		stats = &template
+		groups[key] = stats
+	}
+	return stats
+}
+
+// findStats returns the group with enough samples for the node pair and world size ("" = any)
+func findStats(groups []MigrationStats, fromNodeID, toNodeID, worldSize string) *MigrationStats {
+	for i := range groups {
+		g := &groups[i]
+		if g.FromNodeID == fromNodeID && g.ToNodeID == toNodeID && g.WorldSize == worldSize && g.Total >= minMigrationSamples {
+			return g
+		}
+	}
+	return nil
+}
+
+// finishStats computes the aggregates of the groups, ordered by number of migrations
+func finishStats(groups map[string]*MigrationStats) []MigrationStats {
+	result := make([]MigrationStats, 0, len(groups))
+	for _, stats := range groups {
+		stats.finish()
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Total > result[j].Total
+	})
+	return result
+}
+
+// add records a finished migration
+func (m *MigrationStats) add(migration *models.Migration) {
+	m.Total++
+	if migration.Status == models.MigrationStatusCompleted {
+		m.Completed++
+	} else {
+		m.Failed++
+	}
+
+	if migration.StartedAt != nil && migration.CompletedAt != nil {
+		m.durations = append(m.durations, migration.CompletedAt.Sub(*migration.StartedAt).Seconds())
+	}
+	// Migrations that failed before the proxy switch caused no downtime
+	if migration.DowntimeMs > 0 || migration.Status == models.MigrationStatusCompleted {
+		m.downtimes = append(m.downtimes, float64(migration.DowntimeMs)/1000)
+	}
+}
+
+// finish computes the rates, averages and percentiles
+func (m *MigrationStats) finish() {
+	if m.Total > 0 {
+		m.SuccessRate = float64(m.Completed) / float64(m.Total) * 100
+	}
+	m.AvgDurationSec = average(m.durations)
+	m.AvgDowntimeSec = average(m.downtimes)
+
+	if len(m.downtimes) > 0 {
+		sort.Float64s(m.downtimes)
+		m.P90DowntimeSec = m.downtimes[int(math.Ceil(0.9*float64(len(m.downtimes))))-1]
+		m.MaxDowntimeSec = m.downtimes[len(m.downtimes)-1]
+	}
+}
+
+// average returns the mean of values (0 if empty)
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -2,18 +2,37 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/payperplay/hosting/internal/docker"
 	"github.com/payperplay/hosting/internal/events"
 	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
+const (
+	defaultMigrationTransferTimeout = 2 * time.Hour
+	localCommandTimeout             = 5 * time.Minute // Default for commands without a deadline
+	localCommandKillGrace           = 5 * time.Second // Wait for output pipes after the process group is killed
+)
+
+// ErrMigrationNotRunning is returned when cancelling a migration that has no in-flight transfer
+var ErrMigrationNotRunning = errors.New("migration has no in-flight transfer")
+
 // MigrationService handles server migrations between nodes
 type MigrationService struct {
 	migrationRepo       *repository.MigrationRepository
@@ -24,6 +43,15 @@ type MigrationService struct {
 	wsHub               WebSocketHubInterface
 	dashboardWs         DashboardWebSocketInterface
 	remoteVelocityClient RemoteVelocityClientInterface
+	opLimiter           *OperationLimiter // Optional: lists running migrations as cancellable operations of the server owner
+	webhooks            *WebhookService   // Optional: Discord/Slack notification of completed migrations
+	residency           *ResidencyService // Optional: blocks migrations out of the organization's residency country
+
+	// In-flight migrations: cancel funcs for their transfer contexts (keyed by migration ID)
+	transfers  map[string]context.CancelFunc
+	transferMu sync.Mutex
+
+	paused atomic.Bool // Maintenance mode: scheduled migrations wait, running ones finish
 }
 
 // NewMigrationService creates a new migration service
@@ -38,6 +66,7 @@ func NewMigrationService(
 		serverRepo:    serverRepo,
 		dockerService: dockerService,
 		backupService: backupService,
+		transfers:     make(map[string]context.CancelFunc),
 	}
 }
 
@@ -61,6 +90,33 @@ func (s *MigrationService) SetRemoteVelocityClient(client RemoteVelocityClientIn
 	s.remoteVelocityClient = client
 }
 
+// SetWebhookService sets the service that notifies server webhooks about completed migrations
+func (s *MigrationService) SetWebhookService(webhooks *WebhookService) {
+	s.webhooks = webhooks
+}
+
+// SetResidencyService sets the service that blocks migrations to nodes outside the organization's residency country
+func (s *MigrationService) SetResidencyService(residency *ResidencyService) {
+	s.residency = residency
+}
+
+// SetOperationLimiter sets the limiter that lists running migrations in the owner's operations
+func (s *MigrationService) SetOperationLimiter(opLimiter *OperationLimiter) {
+	s.opLimiter = opLimiter
+}
+
+// Pause stops the worker from starting scheduled migrations (running migrations continue)
+func (s *MigrationService) Pause() {
+	s.paused.Store(true)
+	logger.Info("Migration worker paused", nil)
+}
+
+// Resume lets the worker start scheduled migrations again
+func (s *MigrationService) Resume() {
+	s.paused.Store(false)
+	logger.Info("Migration worker resumed", nil)
+}
+
 // StartMigrationWorker starts the background worker that processes scheduled migrations
 func (s *MigrationService) StartMigrationWorker() {
 	go func() {
@@ -77,6 +133,10 @@ func (s *MigrationService) StartMigrationWorker() {
 
 // processPendingMigrations finds and executes scheduled migrations
 func (s *MigrationService) processPendingMigrations() {
+	if s.paused.Load() {
+		return
+	}
+
 	migrations, err := s.migrationRepo.FindPendingMigrations()
 	if err != nil {
 		logger.Error("Failed to fetch pending migrations", err, map[string]interface{}{})
@@ -107,6 +167,12 @@ func (s *MigrationService) processPendingMigrations() {
 	}
 }
 
+// isSystemMigration returns true for migrations the cost optimization triggered (savings or disk pressure)
+// They wait for a running, idle server; manual migrations don't.
+func isSystemMigration(migration *models.Migration) bool {
+	return migration.Reason == models.MigrationReasonCostOptimization || migration.Reason == models.MigrationReasonDiskPressure
+}
+
 // canExecuteMigration checks if a migration can be executed now
 func (s *MigrationService) canExecuteMigration(migration *models.Migration) bool {
 	// Check if server has active migration
@@ -126,8 +192,8 @@ func (s *MigrationService) canExecuteMigration(migration *models.Migration) bool
 	}
 
 	// Server must be running or starting (for manual migrations)
-	// For cost-optimization, server must be fully running
-	if migration.Reason == models.MigrationReasonCostOptimization {
+	// For cost-optimization (and disk pressure), server must be fully running
+	if isSystemMigration(migration) {
 		if server.Status != models.StatusRunning {
 			logger.Debug("Server not running, skipping cost-optimization migration", map[string]interface{}{
 				"operation_id": migration.ID,
@@ -153,7 +219,7 @@ func (s *MigrationService) canExecuteMigration(migration *models.Migration) bool
 	}
 
 	// For cost-optimization migrations: wait for idle state
-	if migration.Reason == models.MigrationReasonCostOptimization {
+	if isSystemMigration(migration) {
 		// Only migrate if server is idle (0 players) OR has been idle for 5+ minutes
 		if server.CurrentPlayerCount > 0 {
 			logger.Debug("Server has players, waiting for idle state", map[string]interface{}{
@@ -192,10 +258,20 @@ func (s *MigrationService) executeMigration(migration *models.Migration) {
 	// Get server name for events
 	server, err := s.serverRepo.FindByID(migration.ServerID)
 	serverName := "Unknown"
+	ownerID := ""
 	if err == nil {
 		serverName = server.Name
+		ownerID = server.OwnerID
 	}
 
+	// Visible in the owner's operations; cancellable while the world data is transferred
+	op := s.opLimiter.Track(ownerID, "", OperationMigration, migration.ServerID, migration.ID, func() error {
+		return s.CancelTransfer(migration.ID)
+	})
+	defer func() {
+		s.opLimiter.Finish(op, migrationOutcome(migration))
+	}()
+
 	// Broadcast migration started event
 	s.broadcastMigrationEvent("operation.migration.started", map[string]interface{}{
 		"operation_id": migration.ID,
@@ -207,6 +283,14 @@ func (s *MigrationService) executeMigration(migration *models.Migration) {
 		"status":       "started",
 	})
 
+	// Data residency: the world may not leave the organization's country
+	if server != nil {
+		if err := s.residency.CheckNode(server, migration.ToNodeID, "migration"); err != nil {
+			s.failMigration(migration, err.Error())
+			return
+		}
+	}
+
 	// Pre-Migration Backup: OPTIONAL for worker-to-worker migrations
 	// For worker-to-worker: we'll use direct rsync instead of backup+restore
 	// For system-to-worker: backup is needed since local access is available
@@ -219,7 +303,19 @@ func (s *MigrationService) executeMigration(migration *models.Migration) {
 	// Skip backup for worker-to-worker migrations (use direct rsync instead)
 	isWorkerToWorker := !fromNodeIsSystem
 
-	if !isWorkerToWorker {
+	// Transfer context: bounded by MIGRATION_TRANSFER_TIMEOUT, cancellable via CancelTransfer
+	// from the pre-migration backup until the world data has been transferred
+	ctx, cancel := s.registerTransfer(migration.ID)
+	defer s.unregisterTransfer(migration.ID, cancel)
+
+	if migration.BackupID != nil {
+		// Failover: the source node is gone, the world comes from the backup the migration was created with
+		logger.Info("MIGRATION: Using the migration's backup, source node is not transferred from", map[string]interface{}{
+			"operation_id": migration.ID,
+			"server_id":    migration.ServerID,
+			"backup_id":    *migration.BackupID,
+		})
+	} else if !isWorkerToWorker {
 		// Only create backup if migrating FROM system node (where we have local access)
 		logger.Info("MIGRATION: Creating pre-migration backup (synchronous)", map[string]interface{}{
 			"operation_id": migration.ID,
@@ -229,6 +325,7 @@ func (s *MigrationService) executeMigration(migration *models.Migration) {
 		})
 
 		backup, err := s.backupService.CreateBackupSync(
+			ctx,
 			migration.ServerID,
 			models.BackupTypePreMigration,
 			fmt.Sprintf("Pre-migration backup for operation %s", migration.ID),
@@ -236,12 +333,17 @@ func (s *MigrationService) executeMigration(migration *models.Migration) {
 			0,   // Use default retention (7 days)
 		)
 		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				s.cancelMigration(migration, serverName, "Migration cancelled during the pre-migration backup", false)
+				return
+			}
 			s.failMigration(migration, fmt.Sprintf("Pre-migration backup failed: %v", err))
 			return
 		}
 
 		// Store backup ID in migration record for rollback purposes
 		migration.BackupID = &backup.ID
+		migration.WorldSizeBytes = backup.OriginalSize
 		if err := s.migrationRepo.Update(migration); err != nil {
 			logger.Warn("Failed to store backup ID in migration record", map[string]interface{}{
 				"operation_id": migration.ID,
@@ -267,10 +369,15 @@ func (s *MigrationService) executeMigration(migration *models.Migration) {
 	}
 
 	// Phase 1: Preparing
-	if err := s.phasePreparing(migration); err != nil {
+	if err := s.phasePreparing(ctx, migration); err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			s.cancelMigration(migration, serverName, "Migration cancelled during transfer", true)
+			return
+		}
 		s.failMigration(migration, fmt.Sprintf("Preparing phase failed: %v", err))
 		return
 	}
+	s.unregisterTransfer(migration.ID, cancel) // Data transfer done, nothing left to cancel
 
 	// Phase 2: Transferring
 	if err := s.phaseTransferring(migration); err != nil {
@@ -294,7 +401,7 @@ func (s *MigrationService) executeMigration(migration *models.Migration) {
 }
 
 // phasePreparing implements Phase 1: Preparation
-func (s *MigrationService) phasePreparing(migration *models.Migration) error {
+func (s *MigrationService) phasePreparing(ctx context.Context, migration *models.Migration) error {
 	// Update status to preparing
 	now := time.Now()
 	migration.Status = models.MigrationStatusPreparing
@@ -402,11 +509,17 @@ func (s *MigrationService) phasePreparing(migration *models.Migration) error {
 			"message":      "Syncing world data between worker nodes...",
 		})
 
-		if err := s.syncWorldDataBetweenNodes(sourceNode.IPAddress, targetNode.IPAddress, server.ID); err != nil {
+		transferBytes, err := s.syncWorldDataBetweenNodes(ctx, migration.ID, sourceNode.IPAddress, targetNode.IPAddress, server.ID)
+		if err != nil {
 			s.conductor.ReleaseRAMOnNode(migration.ToNodeID, server.RAMMb)
 			return fmt.Errorf("failed to sync world data between nodes: %w", err)
 		}
 
+		// Record transferred volume for migration cost attribution
+		migration.TransferBytes = transferBytes
+		migration.WorldSizeBytes = transferBytes
+		s.migrationRepo.Update(migration)
+
 		logger.Info("MIGRATION: World data synced successfully", map[string]interface{}{
 			"operation_id": migration.ID,
 			"source_node":  sourceNode.IPAddress,
@@ -430,22 +543,22 @@ func (s *MigrationService) phasePreparing(migration *models.Migration) error {
 
 	// CRITICAL: Remove any existing container with this name on target node
 	// This can happen if a previous migration attempt failed
-	ctx := context.Background()
+	dockerCtx := context.Background() // Container operations are not interrupted by transfer cancellation
 	logger.Info("MIGRATION: Cleaning up any existing container on target node", map[string]interface{}{
 		"container_name": containerName,
 		"target_node":    targetNode.IPAddress,
 	})
 
 	// Try to remove old container (ignore errors if it doesn't exist)
-	s.conductor.GetRemoteDockerClient().RemoveContainer(ctx, targetNode, containerName, true)
+	s.conductor.GetRemoteDockerClient().RemoveContainer(dockerCtx, targetNode, containerName, true)
 
-	imageName := docker.GetDockerImageName(string(server.ServerType))
+	imageName := s.dockerService.ImageName(server.Channel())
 	env := docker.BuildContainerEnv(server)
-	portBindings := docker.BuildPortBindings(server.Port)
+	portBindings := docker.BuildPortBindings(server.Port, server.VotifierPort)
 	binds := docker.BuildVolumeBinds(server.ID, "/minecraft/servers")
 
 	newContainerID, err := s.conductor.GetRemoteDockerClient().StartContainer(
-		ctx,
+		dockerCtx,
 		targetNode,
 		containerName,
 		imageName,
@@ -453,6 +566,7 @@ func (s *MigrationService) phasePreparing(migration *models.Migration) error {
 		portBindings,
 		binds,
 		server.RAMMb,
+		s.dockerService.CPULimits(server),
 	)
 
 	if err != nil {
@@ -478,10 +592,10 @@ func (s *MigrationService) phasePreparing(migration *models.Migration) error {
 		"message":      "New container started, waiting for server to be ready...",
 	})
 
-	if err := s.conductor.GetRemoteDockerClient().WaitForServerReady(ctx, targetNode, newContainerID, 120); err != nil {
+	if err := s.conductor.GetRemoteDockerClient().WaitForServerReady(dockerCtx, targetNode, newContainerID, 120); err != nil {
 		// Rollback: stop new container
-		s.conductor.GetRemoteDockerClient().StopContainer(ctx, targetNode, newContainerID, 30)
-		s.conductor.GetRemoteDockerClient().RemoveContainer(ctx, targetNode, newContainerID, true)
+		s.conductor.GetRemoteDockerClient().StopContainer(dockerCtx, targetNode, newContainerID, 30)
+		s.conductor.GetRemoteDockerClient().RemoveContainer(dockerCtx, targetNode, newContainerID, true)
 		s.conductor.ReleaseRAMOnNode(migration.ToNodeID, server.RAMMb)
 		return fmt.Errorf("new container failed to start: %w", err)
 	}
@@ -550,6 +664,12 @@ func (s *MigrationService) phaseTransferring(migration *models.Migration) error
 		velocityServerName := fmt.Sprintf("mc-%s", server.ID)
 		newServerAddress := fmt.Sprintf("%s:%d", targetNode.IPAddress, server.Port)
 
+		// Players can't join between unregistering and registering (recorded for migration analytics)
+		switchStarted := time.Now()
+		defer func() {
+			migration.DowntimeMs = time.Since(switchStarted).Milliseconds()
+		}()
+
 		// Unregister old server
 		if err := s.remoteVelocityClient.UnregisterServer(velocityServerName); err != nil {
 			logger.Warn("Failed to unregister old server from Velocity", map[string]interface{}{
@@ -759,12 +879,38 @@ func (s *MigrationService) completeMigration(migration *models.Migration) {
 		"status":              "completed",
 		"success":             true,
 	})
+	events.PublishServerMigrated(migration.ServerID, migration.ID, migration.FromNodeID, migration.ToNodeID, string(migration.Reason), duration)
+
+	if s.webhooks != nil && migration.Reason != models.MigrationReasonFailover { // FailoverService notifies itself
+		fromNode, toNode := migration.FromNodeName, migration.ToNodeName
+		if fromNode == "" {
+			fromNode = migration.FromNodeID
+		}
+		if toNode == "" {
+			toNode = migration.ToNodeID
+		}
+		s.webhooks.NotifyMigrationCompleted(migration.ServerID, serverName, fromNode, toNode)
+	}
 }
 
 // syncWorldDataBetweenNodes synchronizes world data directly between worker nodes using rsync
-func (s *MigrationService) syncWorldDataBetweenNodes(sourceIP, targetIP, serverID string) error {
+// Returns the size of the transferred world data in bytes. All commands are executed from an
+// argv (no local shell); the only remote command line is built with docker.ShellJoin.
+// Byte-level progress of both rsync steps is published for migrationID (rsync --info=progress2).
+func (s *MigrationService) syncWorldDataBetweenNodes(ctx context.Context, migrationID, sourceIP, targetIP, serverID string) (int64, error) {
+	if err := docker.ValidateResourceID(serverID); err != nil {
+		return 0, err
+	}
+	if err := docker.ValidateNodeAddress(sourceIP); err != nil {
+		return 0, fmt.Errorf("source node: %w", err)
+	}
+	if err := docker.ValidateNodeAddress(targetIP); err != nil {
+		return 0, fmt.Errorf("target node: %w", err)
+	}
+
 	sourceDir := fmt.Sprintf("/minecraft/servers/%s/", serverID)
 	targetDir := fmt.Sprintf("/minecraft/servers/%s", serverID)
+	tempDir := filepath.Join(os.TempDir(), "migration-"+serverID)
 
 	logger.Info("MIGRATION: Starting rsync between worker nodes", map[string]interface{}{
 		"source_ip":   sourceIP,
@@ -775,42 +921,67 @@ func (s *MigrationService) syncWorldDataBetweenNodes(sourceIP, targetIP, serverI
 	})
 
 	// SSH identity file (keys are copied to /app/.ssh by entrypoint.sh)
-	sshIdentity := "/app/.ssh/id_rsa"
-
-	// 1. Create target directory on destination node
-	mkdirCmd := fmt.Sprintf("ssh -i %s -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null root@%s 'mkdir -p %s'", sshIdentity, targetIP, targetDir)
-	if err := s.executeCommand(mkdirCmd); err != nil {
-		return fmt.Errorf("failed to create target directory: %w", err)
+	// rsync runs the ssh binary, so multiplex its connections via OpenSSH ControlMaster
+	// (pull and push reuse one master connection per node for the duration of the migration)
+	sshArgs := []string{
+		"-i", "/app/.ssh/id_rsa",
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "UserKnownHostsFile=/dev/null",
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPath=/tmp/ssh-mux-%r@%h:%p",
+		"-o", "ControlPersist=60s",
+	}
+	// rsync splits -e on whitespace; none of the constant arguments above contain any
+	rsyncShell := "ssh " + strings.Join(sshArgs, " ")
+
+	// 1. Create target directory on destination node (pooled SSH connection if available)
+	mkdirCmd := docker.ShellJoin("mkdir", "-p", targetDir)
+	if remoteClient := s.conductor.GetRemoteDockerClient(); remoteClient != nil {
+		mkdirCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		_, err := remoteClient.ExecuteSSHCommand(mkdirCtx, &docker.RemoteNode{ID: targetIP, IPAddress: targetIP, SSHUser: "root"}, mkdirCmd)
+		cancel()
+		if err != nil {
+			return 0, fmt.Errorf("failed to create target directory: %w", err)
+		}
+	} else {
+		args := append(append([]string{}, sshArgs...), "root@"+targetIP, mkdirCmd)
+		if err := s.executeCommand(ctx, "ssh", args...); err != nil {
+			return 0, fmt.Errorf("failed to create target directory: %w", err)
+		}
 	}
 
 	// 2. Rsync in two steps (rsync can't have both source and dest as remote)
 	// Step 2a: Pull from source to conductor temp directory
 	// Step 2b: Push from conductor temp to target
-	tempDir := fmt.Sprintf("/tmp/migration-%s", serverID)
-
 	logger.Info("MIGRATION: Step 2a - Pulling data from source to conductor", map[string]interface{}{
 		"source_ip": sourceIP,
 		"temp_dir":  tempDir,
 	})
 
-	// Create temp directory
-	mkdirTempCmd := fmt.Sprintf("mkdir -p %s", tempDir)
-	if err := s.executeCommand(mkdirTempCmd); err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create temp directory: %w", err)
 	}
+	defer os.RemoveAll(tempDir) // Cleanup on success and error
+
+	progress := NewTransferProgress("migration", migrationID, serverID, s.wsHub)
 
-	// Pull from source to temp
-	rsyncPullCmd := fmt.Sprintf(
-		"rsync -avz --delete -e \"ssh -i %s -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null\" root@%s:%s %s/",
-		sshIdentity, // Identity file
-		sourceIP,    // Source node IP
-		sourceDir,   // Source directory
-		tempDir,     // Local temp directory
+	// Pull from source to temp (total is estimated from rsync's overall percentage)
+	progress.StartPhase("pulling", 0)
+	err := s.executeCommandWithOutput(ctx, &rsyncProgressWriter{progress: progress},
+		"rsync", "-avz", "--delete", "--info=progress2", "--no-inc-recursive", "-e", rsyncShell,
+		"root@"+sourceIP+":"+sourceDir, // Source node directory (trailing slash: copy contents)
+		tempDir+"/",                     // Local temp directory
 	)
+	if err != nil {
+		return 0, fmt.Errorf("rsync pull failed: %w", err)
+	}
 
-	if err := s.executeCommand(rsyncPullCmd); err != nil {
-		s.executeCommand(fmt.Sprintf("rm -rf %s", tempDir)) // Cleanup on error
-		return fmt.Errorf("rsync pull failed: %w", err)
+	transferBytes, err := dirSizeBytes(tempDir)
+	if err != nil {
+		logger.Warn("MIGRATION: Failed to measure transferred data size", map[string]interface{}{
+			"temp_dir": tempDir,
+			"error":    err.Error(),
+		})
 	}
 
 	logger.Info("MIGRATION: Step 2b - Pushing data from conductor to target", map[string]interface{}{
@@ -819,21 +990,16 @@ func (s *MigrationService) syncWorldDataBetweenNodes(sourceIP, targetIP, serverI
 	})
 
 	// Push from temp to target
-	rsyncPushCmd := fmt.Sprintf(
-		"rsync -avz --delete -e \"ssh -i %s -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null\" %s/ root@%s:%s/",
-		sshIdentity, // Identity file
-		tempDir,     // Local temp directory
-		targetIP,    // Target node IP
-		targetDir,   // Target directory
+	progress.StartPhase("pushing", transferBytes)
+	err = s.executeCommandWithOutput(ctx, &rsyncProgressWriter{progress: progress},
+		"rsync", "-avz", "--delete", "--info=progress2", "--no-inc-recursive", "-e", rsyncShell,
+		tempDir+"/",                        // Local temp directory
+		"root@"+targetIP+":"+targetDir+"/", // Target node directory
 	)
-
-	if err := s.executeCommand(rsyncPushCmd); err != nil {
-		s.executeCommand(fmt.Sprintf("rm -rf %s", tempDir)) // Cleanup on error
-		return fmt.Errorf("rsync push failed: %w", err)
+	if err != nil {
+		return 0, fmt.Errorf("rsync push failed: %w", err)
 	}
-
-	// Cleanup temp directory
-	s.executeCommand(fmt.Sprintf("rm -rf %s", tempDir))
+	progress.Finish()
 
 	logger.Info("MIGRATION: Rsync completed successfully", map[string]interface{}{
 		"source_ip": sourceIP,
@@ -841,23 +1007,123 @@ func (s *MigrationService) syncWorldDataBetweenNodes(sourceIP, targetIP, serverI
 		"server_id": serverID,
 	})
 
-	return nil
+	return transferBytes, nil
 }
 
-// executeCommand executes a shell command via sh (Alpine-compatible)
-func (s *MigrationService) executeCommand(command string) error {
-	cmd := exec.Command("sh", "-c", command)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("command failed: %w, output: %s", err, string(output))
+// executeCommand executes a program with arguments (no shell, so arguments are never re-parsed)
+// The command runs in its own process group so cancellation also kills children (rsync, ssh);
+// it is bounded by ctx (or localCommandTimeout if ctx has no deadline) and its captured output
+// by REMOTE_COMMAND_MAX_OUTPUT.
+func (s *MigrationService) executeCommand(ctx context.Context, name string, args ...string) error {
+	return s.executeCommandWithOutput(ctx, nil, name, args...)
+}
+
+// executeCommandWithOutput is executeCommand with stdout additionally streamed to progressOut
+// (e.g. an rsyncProgressWriter); the captured output stays bounded either way.
+func (s *MigrationService) executeCommandWithOutput(ctx context.Context, progressOut io.Writer, name string, args ...string) error {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, localCommandTimeout)
+		defer cancel()
+	}
+
+	maxOutput := 0
+	if config.AppConfig != nil {
+		maxOutput = config.AppConfig.RemoteCommandMaxOutput
+	}
+	output := docker.NewLimitedBuffer(maxOutput)
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = output
+	if progressOut != nil {
+		cmd.Stdout = io.MultiWriter(output, progressOut)
+	}
+	cmd.Stderr = output
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = localCommandKillGrace
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s aborted: %w, output: %s", name, ctx.Err(), output.String())
+		}
+		return fmt.Errorf("%s failed: %w, output: %s", name, err, output.String())
 	}
 	logger.Debug("MIGRATION: Command executed", map[string]interface{}{
-		"command": command,
-		"output":  string(output),
+		"command":   name,
+		"args":      args,
+		"output":    output.String(),
+		"truncated": output.Truncated(),
 	})
 	return nil
 }
 
+// dirSizeBytes returns the total size of regular files below path
+func dirSizeBytes(path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// registerTransfer creates the transfer context of a migration and remembers its cancel func
+func (s *MigrationService) registerTransfer(migrationID string) (context.Context, context.CancelFunc) {
+	timeout := defaultMigrationTransferTimeout
+	if config.AppConfig != nil {
+		if parsed, err := time.ParseDuration(config.AppConfig.MigrationTransferTimeout); err == nil && parsed > 0 {
+			timeout = parsed
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	s.transferMu.Lock()
+	s.transfers[migrationID] = cancel
+	s.transferMu.Unlock()
+
+	return ctx, cancel
+}
+
+// unregisterTransfer releases the transfer context of a finished migration
+func (s *MigrationService) unregisterTransfer(migrationID string, cancel context.CancelFunc) {
+	s.transferMu.Lock()
+	delete(s.transfers, migrationID)
+	s.transferMu.Unlock()
+	cancel()
+}
+
+// CancelTransfer aborts the in-flight pre-migration backup or transfer of a running migration
+// The partial backup is removed and running commands (rsync/ssh) are killed; the migration is then
+// cancelled and rolled back (never retried).
+func (s *MigrationService) CancelTransfer(migrationID string) error {
+	s.transferMu.Lock()
+	cancel, exists := s.transfers[migrationID]
+	s.transferMu.Unlock()
+
+	if !exists {
+		return ErrMigrationNotRunning
+	}
+
+	logger.Warn("MIGRATION: Cancelling in-flight transfer", map[string]interface{}{
+		"operation_id": migrationID,
+	})
+	cancel()
+	return nil
+}
+
 // failMigration marks migration as failed
 func (s *MigrationService) failMigration(migration *models.Migration, errorMessage string) {
 	migration.Status = models.MigrationStatusFailed
@@ -918,9 +1184,11 @@ func (s *MigrationService) failMigration(migration *models.Migration, errorMessa
 		"status":           "failed",
 		"rollback_success": true,
 	})
+	events.PublishServerMigrationFailed(migration.ServerID, migration.ID, migration.FromNodeID, migration.ToNodeID, errorMessage)
 
 	// TODO: Retry logic if retry_count < max_retries
-	if migration.RetryCount < migration.MaxRetries {
+	// A failover is not retried by the worker: its backup is outdated once the server runs again
+	if migration.RetryCount < migration.MaxRetries && migration.Reason != models.MigrationReasonFailover {
 		logger.Info("Migration will be retried", map[string]interface{}{
 			"operation_id": migration.ID,
 			"retry_count":  migration.RetryCount,
@@ -932,6 +1200,51 @@ func (s *MigrationService) failMigration(migration *models.Migration, errorMessa
 	}
 }
 
+// cancelMigration marks a migration as cancelled and, once preparing started (prepared), rolls back the target node
+// Unlike failMigration, a cancelled migration is not retried.
+func (s *MigrationService) cancelMigration(migration *models.Migration, serverName, reason string, prepared bool) {
+	migration.Status = models.MigrationStatusCancelled
+	migration.ErrorMessage = reason
+
+	if err := s.migrationRepo.Update(migration); err != nil {
+		logger.Error("Failed to mark migration as cancelled", err, map[string]interface{}{
+			"operation_id": migration.ID,
+		})
+	}
+
+	if prepared {
+		s.rollbackPreparing(migration) // The target container and its RAM exist only once preparing started
+	}
+
+	logger.Warn("MIGRATION: Migration cancelled", map[string]interface{}{
+		"operation_id": migration.ID,
+		"server_id":    migration.ServerID,
+	})
+
+	s.broadcastMigrationEvent("operation.migration.cancelled", map[string]interface{}{
+		"operation_id": migration.ID,
+		"server_id":    migration.ServerID,
+		"server_name":  serverName,
+		"from_node":    migration.FromNodeID,
+		"to_node":      migration.ToNodeID,
+		"status":       "cancelled",
+	})
+}
+
+// migrationOutcome converts the final state of an executed migration into an operation result
+func migrationOutcome(migration *models.Migration) error {
+	switch migration.Status {
+	case models.MigrationStatusCompleted:
+		return nil
+	case models.MigrationStatusCancelled:
+		return context.Canceled
+	}
+	if migration.ErrorMessage != "" {
+		return errors.New(migration.ErrorMessage)
+	}
+	return errors.New("migration did not complete")
+}
+
 // rollbackPreparing rolls back Phase 1 (stop and remove new container)
 func (s *MigrationService) rollbackPreparing(migration *models.Migration) {
 	logger.Info("Rolling back preparing phase", map[string]interface{}{
@@ -981,6 +1294,112 @@ func (s *MigrationService) broadcastMigrationEvent(eventType string, data map[st
 	if s.dashboardWs != nil {
 		s.dashboardWs.PublishEvent(eventType, data)
 	}
+	// Phase progress of the migration job (GET /api/jobs)
+	if progress, ok := data["progress"].(int); ok {
+		migrationID, _ := data["operation_id"].(string)
+		phase, _ := data["status"].(string)
+		s.opLimiter.SetProgress(OperationMigration, migrationID, phase, progress)
+	}
+}
+
+// Failover moves a server off a failed node: backup is restored onto a healthy node and the
+// server is started there. The migration runs right away instead of through the migration worker;
+// if it succeeds, the server is running (and billed) again on the new node.
+func (s *MigrationService) Failover(server *models.MinecraftServer, fromNodeID string, backup *models.Backup) (*models.Migration, error) {
+	if s.conductor == nil {
+		return nil, fmt.Errorf("conductor not available")
+	}
+
+	toNodeID, err := s.replacementNode(server, fromNodeID)
+	if err != nil {
+		return nil, fmt.Errorf("no node to fail over to: %w", err)
+	}
+
+	now := time.Now()
+	migration := &models.Migration{
+		ID:             uuid.New().String(),
+		ServerID:       server.ID,
+		FromNodeID:     fromNodeID,
+		ToNodeID:       toNodeID,
+		Status:         models.MigrationStatusPreparing, // Active right away, the worker doesn't pick it up
+		Reason:         models.MigrationReasonFailover,
+		CreatedAt:      now,
+		ScheduledAt:    &now,
+		WorldSizeBytes: backup.OriginalSize,
+		BackupID:       &backup.ID,
+		TriggeredBy:    "system",
+		Notes:          fmt.Sprintf("Failover from failed node %s, restored from backup %s (%s)", fromNodeID, backup.ID, backup.CreatedAt.UTC().Format(time.RFC3339)),
+	}
+	if err := s.migrationRepo.Create(migration); err != nil {
+		return nil, fmt.Errorf("failed to create failover migration: %w", err)
+	}
+
+	s.executeMigration(migration)
+	if err := migrationOutcome(migration); err != nil {
+		return migration, err
+	}
+
+	// The migration moved the container; the server itself is still marked as crashed
+	server, err = s.serverRepo.FindByID(server.ID)
+	if err != nil {
+		return migration, fmt.Errorf("failed to reload server: %w", err)
+	}
+	startedAt := time.Now()
+	server.Status = models.StatusRunning
+	server.LastStartedAt = &startedAt
+	server.LifecyclePhase = models.PhaseActive
+	if err := s.serverRepo.UpdateWithEvent(server, events.ServerStartedOutboxEvent(server.ID, server.OwnerID)); err != nil {
+		return migration, fmt.Errorf("failed to mark server as running: %w", err)
+	}
+	events.PublishServerStarted(server.ID, server.OwnerID)
+
+	return migration, nil
+}
+
+// Evacuate moves a running server off an interruptible node the provider is about to terminate
+// Like Failover the migration runs right away, but the world is transferred live from the node,
+// which is still up. Returns an error if no other node fits or the transfer fails.
+func (s *MigrationService) Evacuate(server *models.MinecraftServer, fromNodeID string) (*models.Migration, error) {
+	if s.conductor == nil {
+		return nil, fmt.Errorf("conductor not available")
+	}
+
+	toNodeID, err := s.replacementNode(server, fromNodeID)
+	if err != nil {
+		return nil, fmt.Errorf("no node to evacuate to: %w", err)
+	}
+
+	now := time.Now()
+	migration := &models.Migration{
+		ID:          uuid.New().String(),
+		ServerID:    server.ID,
+		FromNodeID:  fromNodeID,
+		ToNodeID:    toNodeID,
+		Status:      models.MigrationStatusPreparing, // Active right away, the worker doesn't pick it up
+		Reason:      models.MigrationReasonEviction,
+		CreatedAt:   now,
+		ScheduledAt: &now,
+		TriggeredBy: "system",
+		Notes:       fmt.Sprintf("Evacuated from interruptible node %s before its termination", fromNodeID),
+	}
+	if err := s.migrationRepo.Create(migration); err != nil {
+		return nil, fmt.Errorf("failed to create eviction migration: %w", err)
+	}
+
+	s.executeMigration(migration)
+	return migration, migrationOutcome(migration)
+}
+
+// replacementNode selects the node a server moves to when it has to leave fromNodeID
+func (s *MigrationService) replacementNode(server *models.MinecraftServer, fromNodeID string) (string, error) {
+	toNodeID, err := s.conductor.SelectNodeForContainerAuto(server.RAMMb, withPlacementPeers(s.serverRepo, server, s.residency.Placement(server, s.conductor.PlacementFor(server.OwnerID, server.Placement()))))
+	if err != nil {
+		return "", err
+	}
+	if toNodeID == fromNodeID {
+		return "", fmt.Errorf("only node %s has capacity", fromNodeID)
+	}
+	return toNodeID, nil
 }
 
 // ScheduleMigration creates a manual migration and schedules it
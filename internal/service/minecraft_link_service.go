@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// minecraftLinkCodeTTL is how long a whispered link code can be confirmed
+	minecraftLinkCodeTTL = 10 * time.Minute
+	// minecraftLinkResendDelay is the minimum time between two codes of a user
+	minecraftLinkResendDelay = time.Minute
+	// minecraftLinkMaxAttempts is the number of wrong codes after which a code is void
+	minecraftLinkMaxAttempts = 5
+	minecraftLinkCodeDigits  = 6
+)
+
+// MinecraftLinkService links platform users to their Minecraft accounts
+// The user names the account, the code is whispered to that player on one of the user's running
+// servers, and entering it proves the user controls the account. Verified links enable auto-op and
+// let other services attribute players to users (LinkedUser).
+type MinecraftLinkService struct {
+	linkRepo    *repository.MinecraftLinkRepository
+	serverRepo  *repository.ServerRepository
+	playerLists *PlayerListService
+	console     *ConsoleService
+	resolver    *PlayerUUIDService
+}
+
+// NewMinecraftLinkService creates a new Minecraft account linking service
+func NewMinecraftLinkService(
+	linkRepo *repository.MinecraftLinkRepository,
+	serverRepo *repository.ServerRepository,
+	playerLists *PlayerListService,
+	console *ConsoleService,
+	resolver *PlayerUUIDService,
+) *MinecraftLinkService {
+	return &MinecraftLinkService{
+		linkRepo:    linkRepo,
+		serverRepo:  serverRepo,
+		playerLists: playerLists,
+		console:     console,
+		resolver:    resolver,
+	}
+}
+
+// Get returns the link of a user (nil if the user never started linking)
+func (s *MinecraftLinkService) Get(userID string) (*models.MinecraftLink, error) {
+	return s.linkRepo.FindByUser(userID)
+}
+
+// LinkedUser returns the verified link of a Minecraft account, nil if no user linked it
+func (s *MinecraftLinkService) LinkedUser(minecraftUUID string) (*models.MinecraftLink, error) {
+	return s.linkRepo.FindByMinecraftUUID(minecraftUUID)
+}
+
+// StartLink whispers a one-time code to a player online on one of the user's running servers
+// Returns the pending link and the server the code was sent on. An existing verified link stays
+// in place until the new code is confirmed.
+func (s *MinecraftLinkService) StartLink(ctx context.Context, userID, name string) (*models.MinecraftLink, string, error) {
+	if err := validatePlayerName(name); err != nil {
+		return nil, "", err
+	}
+	link, err := s.linkRepo.FindByUser(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	if link != nil && link.CodeExpiresAt != nil && now.Before(link.CodeExpiresAt.Add(minecraftLinkResendDelay-minecraftLinkCodeTTL)) {
+		return nil, "", models.ErrMinecraftLinkTooSoon
+	}
+
+	profile, err := s.resolver.ResolveName(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	serverID, err := s.findOnlineServer(userID, profile.Name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	code, err := generateLinkCode()
+	if err != nil {
+		return nil, "", err
+	}
+	message := fmt.Sprintf("tell %s Your PayPerPlay link code is %s (valid for %d minutes). Don't share it with anyone.",
+		profile.Name, code, int(minecraftLinkCodeTTL.Minutes()))
+	if _, err := s.console.ExecuteCommand(ctx, serverID, message); err != nil {
+		return nil, "", fmt.Errorf("failed to send link code: %w", err)
+	}
+
+	if link == nil {
+		link = &models.MinecraftLink{UserID: userID}
+	}
+	expiresAt := now.Add(minecraftLinkCodeTTL)
+	link.PendingUUID = profile.UUID
+	link.PendingName = profile.Name
+	link.CodeHash = hashLinkCode(code)
+	link.CodeExpiresAt = &expiresAt
+	link.CodeAttempts = 0
+	if err := s.linkRepo.Save(link); err != nil {
+		return nil, "", err
+	}
+
+	logger.Info("MCLINK: Link code sent", map[string]interface{}{
+		"user_id":   userID,
+		"player":    profile.Name,
+		"server_id": serverID,
+	})
+	return link, serverID, nil
+}
+
+// ConfirmLink verifies the whispered code and links the account
+// An account linked by another user before moves to this user.
+func (s *MinecraftLinkService) ConfirmLink(userID, code string) (*models.MinecraftLink, error) {
+	link, err := s.linkRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || link.PendingUUID == "" {
+		return nil, models.ErrMinecraftLinkNotStarted
+	}
+
+	if err := checkLinkCode(link, code, time.Now()); err != nil {
+		link.CodeAttempts++
+		if saveErr := s.linkRepo.Save(link); saveErr != nil {
+			return nil, saveErr
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if link.MinecraftUUID != link.PendingUUID {
+		link.AutoOp = false // Opt in again for a different account
+	}
+	link.MinecraftUUID = link.PendingUUID
+	link.MinecraftName = link.PendingName
+	link.VerifiedAt = &now
+	link.PendingUUID = ""
+	link.PendingName = ""
+	link.CodeHash = ""
+	link.CodeExpiresAt = nil
+	link.CodeAttempts = 0
+	if err := s.linkRepo.SaveVerified(link); err != nil {
+		return nil, err
+	}
+
+	logger.Info("MCLINK: Minecraft account linked", map[string]interface{}{
+		"user_id": userID,
+		"uuid":    link.MinecraftUUID,
+		"player":  link.MinecraftName,
+	})
+	return link, nil
+}
+
+// SetAutoOp turns opping the linked player on the user's servers at every start on or off
+func (s *MinecraftLinkService) SetAutoOp(userID string, enabled bool) (*models.MinecraftLink, error) {
+	link, err := s.linkRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !link.Verified() {
+		return nil, models.ErrMinecraftLinkNotLinked
+	}
+	link.AutoOp = enabled
+	if err := s.linkRepo.Save(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// Unlink removes the link of a user, including a pending code
+func (s *MinecraftLinkService) Unlink(userID string) error {
+	link, err := s.linkRepo.FindByUser(userID)
+	if err != nil {
+		return err
+	}
+	if link == nil {
+		return models.ErrMinecraftLinkNotLinked
+	}
+	return s.linkRepo.Delete(userID)
+}
+
+// StartAutoOp ops the linked player of the owner when a server has started (if enabled)
+func (s *MinecraftLinkService) StartAutoOp() {
+	events.GetEventBus().Subscribe(events.EventServerStarted, func(event events.Event) {
+		go s.applyAutoOp(event.ServerID)
+	})
+}
+
+// applyAutoOp ops the owner's linked player on a server that just started, waiting for RCON
+func (s *MinecraftLinkService) applyAutoOp(serverID string) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return
+	}
+	link, err := s.linkRepo.FindByUser(server.OwnerID)
+	if err != nil || !link.Verified() || !link.AutoOp {
+		return
+	}
+
+	// The player may have been renamed since linking
+	if profile, err := s.resolver.ResolveUUID(context.Background(), link.MinecraftUUID); err == nil && profile.Name != link.MinecraftName {
+		link.MinecraftName = profile.Name
+		if err := s.linkRepo.Save(link); err != nil {
+			logger.Warn("MCLINK: Failed to update renamed player", map[string]interface{}{
+				"user_id": link.UserID,
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	for attempt := 1; attempt <= pendingSyncAttempts; attempt++ {
+		if err = s.playerLists.addViaRCON(serverID, link.MinecraftName, ListTypeOps); err == nil {
+			logger.Info("MCLINK: Linked owner opped", map[string]interface{}{
+				"server_id": serverID,
+				"player":    link.MinecraftName,
+			})
+			return
+		}
+		time.Sleep(pendingSyncDelay)
+	}
+	logger.Warn("MCLINK: Failed to op linked owner", map[string]interface{}{
+		"server_id": serverID,
+		"player":    link.MinecraftName,
+		"error":     err.Error(),
+	})
+}
+
+// findOnlineServer returns a running server of the user the player is online on
+// Codes are only whispered on the user's own servers, so no other owner sees them in a console.
+func (s *MinecraftLinkService) findOnlineServer(userID, name string) (string, error) {
+	servers, err := s.serverRepo.FindByOwner(userID)
+	if err != nil {
+		return "", err
+	}
+	for _, server := range servers {
+		if server.Status != models.StatusRunning {
+			continue
+		}
+		players, err := s.playerLists.GetOnlinePlayers(server.ID)
+		if err != nil {
+			continue
+		}
+		for _, player := range players {
+			if strings.EqualFold(player, name) {
+				return server.ID, nil
+			}
+		}
+	}
+	return "", models.ErrMinecraftPlayerOffline
+}
+
+// checkLinkCode checks a code against the pending code of a link
+func checkLinkCode(link *models.MinecraftLink, code string, now time.Time) error {
+	if link.CodeHash == "" || link.CodeExpiresAt == nil || now.After(*link.CodeExpiresAt) || link.CodeAttempts >= minecraftLinkMaxAttempts {
+		return models.ErrMinecraftLinkCode
+	}
+	if subtle.ConstantTimeCompare([]byte(hashLinkCode(code)), []byte(link.CodeHash)) != 1 {
+		return models.ErrMinecraftLinkCode
+	}
+	return nil
+}
+
+// generateLinkCode returns a random numeric code that is easy to type from the chat
+func generateLinkCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate link code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", minecraftLinkCodeDigits, n.Int64()), nil
+}
+
+// hashLinkCode hashes a link code, ignoring surrounding spaces
+func hashLinkCode(code string) string {
+	hash := sha256.Sum256([]byte(strings.TrimSpace(code)))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestCheckLinkCode(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(minecraftLinkCodeTTL)
+	link := func(attempts int) *models.MinecraftLink {
+		return &models.MinecraftLink{CodeHash: hashLinkCode("042133"), CodeExpiresAt: &expiresAt, CodeAttempts: attempts}
+	}
+
+	tests := []struct {
+		name  string
+		link  *models.MinecraftLink
+		code  string
+		now   time.Time
+		valid bool
+	}{
+		{"correct", link(0), "042133", now, true},
+		{"surrounding spaces", link(0), " 042133 ", now, true},
+		{"wrong", link(0), "042134", now, false},
+		{"expired", link(0), "042133", expiresAt.Add(time.Second), false},
+		{"too many attempts", link(minecraftLinkMaxAttempts), "042133", now, false},
+		{"no code", &models.MinecraftLink{}, "", now, false},
+	}
+	for _, tt := range tests {
+		err := checkLinkCode(tt.link, tt.code, tt.now)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: checkLinkCode() = %v, want valid=%v", tt.name, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, models.ErrMinecraftLinkCode) {
+			t.Errorf("%s: error %v is not ErrMinecraftLinkCode", tt.name, err)
+		}
+	}
+}
+
+func TestGenerateLinkCode(t *testing.T) {
+	code, err := generateLinkCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != minecraftLinkCodeDigits || strings.Trim(code, "0123456789") != "" {
+		t.Errorf("generateLinkCode() = %q, want %d digits", code, minecraftLinkCodeDigits)
+	}
+}
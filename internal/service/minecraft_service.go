@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -13,10 +16,13 @@ import (
 	"github.com/payperplay/hosting/internal/docker"
 	"github.com/payperplay/hosting/internal/events"
 	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/monitoring"
 	"github.com/payperplay/hosting/internal/rcon"
 	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/tracing"
 	"github.com/payperplay/hosting/pkg/config"
 	"github.com/payperplay/hosting/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type MinecraftService struct {
@@ -29,6 +35,10 @@ type MinecraftService struct {
 	conductor             ConductorInterface        // Interface for capacity management
 	archiveService        ArchiveServiceInterface   // Interface for archive management (Phase 3 lifecycle)
 	backupService         *BackupService            // Backup service for pre-operation backups
+	billingGuards         []BillingGuardInterface   // Block starts for owners with billing problems (suspension, low credit)
+	opLimiter             *OperationLimiter         // Per-owner concurrency limit for user-triggered starts
+	residency             *ResidencyService         // Optional: pins organization servers to nodes in their residency country
+	maintenance           *MaintenanceService       // Optional: rejects starts during maintenance
 	// GAP-4: Operation locks to prevent concurrent operations on same server
 	operationLocks        map[string]*sync.Mutex
 	operationLocksMu      sync.Mutex
@@ -57,6 +67,11 @@ type ArchiveServiceInterface interface {
 	ArchiveServer(serverID string) error
 }
 
+// BillingGuardInterface decides whether a server may be started (e.g. owner suspended, insufficient credit)
+type BillingGuardInterface interface {
+	CheckCanStart(server *models.MinecraftServer) error
+}
+
 // RemoteVelocityClientInterface defines the methods needed from RemoteVelocityClient
 // This is the NEW way of communicating with Velocity via HTTP API
 type RemoteVelocityClientInterface interface {
@@ -79,6 +94,10 @@ type ConductorInterface interface {
 	// CRITICAL: This must be called BEFORE Docker starts to prevent race conditions
 	AtomicReserveStartSlot(serverID, serverName string, ramMB int) bool
 
+	// ReserveGuaranteedStartSlot reserves the start slot of a reserved-plan server without waiting for other starts
+	// Returns the node still holding the server's RAM (claimed for this start), or "" to select a node
+	ReserveGuaranteedStartSlot(serverID, serverName string, ramMB int, placement models.NodePlacement) string
+
 	// ReleaseStartSlot removes a "starting" reservation if start fails
 	ReleaseStartSlot(serverID string)
 
@@ -104,8 +123,12 @@ type ConductorInterface interface {
 
 	// SelectNodeForContainerAuto selects the best node for a new container
 	// Uses the recommended node selection strategy based on fleet composition
+	// Only nodes matching the placement (node labels/taints) are considered
 	// Returns (nodeID, error)
-	SelectNodeForContainerAuto(requiredRAMMB int) (string, error)
+	SelectNodeForContainerAuto(requiredRAMMB int, placement models.NodePlacement) (string, error)
+
+	// PlacementFor pins the placement of an owner's server to their customer-dedicated nodes (if any)
+	PlacementFor(ownerID string, placement models.NodePlacement) models.NodePlacement
 
 	// AtomicAllocateRAMOnNode atomically reserves RAM on a specific node
 	// Returns true if allocation succeeded, false if insufficient capacity
@@ -196,6 +219,42 @@ func (s *MinecraftService) SetBackupService(backupService *BackupService) {
 	s.backupService = backupService
 }
 
+// SetOperationLimiter sets the per-owner limiter used by RequestStart
+func (s *MinecraftService) SetOperationLimiter(opLimiter *OperationLimiter) {
+	s.opLimiter = opLimiter
+}
+
+// SetResidencyService sets the service that restricts node placement to the organization's residency country
+func (s *MinecraftService) SetResidencyService(residency *ResidencyService) {
+	s.residency = residency
+}
+
+// SetMaintenanceService sets the maintenance switch that rejects starts while it is on
+func (s *MinecraftService) SetMaintenanceService(maintenance *MaintenanceService) {
+	s.maintenance = maintenance
+}
+
+// AddBillingGuard registers a billing guard that is consulted before every server start
+func (s *MinecraftService) AddBillingGuard(guard BillingGuardInterface) {
+	s.billingGuards = append(s.billingGuards, guard)
+}
+
+// checkBillingGuards returns the first billing guard rejection for a server start
+func (s *MinecraftService) checkBillingGuards(server *models.MinecraftServer) error {
+	for _, guard := range s.billingGuards {
+		if err := guard.CheckCanStart(server); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewServerOptions customise a server created with CreateServerWithOptions
+type NewServerOptions struct {
+	Configure func(server *models.MinecraftServer) // Adjusts the default settings before they are validated and saved
+	Hold      bool                                 // Create the server stopped instead of queueing it (see QueueServer)
+}
+
 // CreateServer creates a new Minecraft server
 func (s *MinecraftService) CreateServer(
 	name string,
@@ -203,6 +262,20 @@ func (s *MinecraftService) CreateServer(
 	minecraftVersion string,
 	ramMB int,
 	ownerID string,
+) (*models.MinecraftServer, error) {
+	return s.CreateServerWithOptions(name, serverType, minecraftVersion, ramMB, ownerID, NewServerOptions{})
+}
+
+// CreateServerWithOptions creates a new Minecraft server with customised settings
+// A held server is not queued, so its data can be prepared (e.g. a cloned world restored) before
+// QueueServer hands it to the Conductor.
+func (s *MinecraftService) CreateServerWithOptions(
+	name string,
+	serverType models.ServerType,
+	minecraftVersion string,
+	ramMB int,
+	ownerID string,
+	opts NewServerOptions,
 ) (*models.MinecraftServer, error) {
 	// Generate server ID
 	serverID := uuid.New().String()[:8]
@@ -230,25 +303,14 @@ func (s *MinecraftService) CreateServer(
 		Status:               models.StatusQueued, // Start in queue - Conductor will assign node
 		IdleTimeoutSeconds:   s.cfg.DefaultIdleTimeout,
 		AutoShutdownEnabled:  true,
-		MaxPlayers:           20,
-		// Set defaults explicitly for validation (GORM defaults only apply on DB insert)
-		ViewDistance:                10,
-		SimulationDistance:          10,
-		SpawnProtection:             16,
-		MaxTickTime:                 60000,
-		NetworkCompressionThreshold: 256,
-		Gamemode:                    "survival",
-		Difficulty:                  "normal",
-		PVP:                         true,
-		AllowNether:                 true,
-		AllowEnd:                    true,
-		GenerateStructures:          true,
-		WorldType:                   "default",
-		SpawnAnimals:                true,
-		SpawnMonsters:               true,
-		SpawnNPCs:                   true,
-		MaxWorldSize:                29999984,
-		MOTD:                        "A Minecraft Server",
+	}
+	// Set defaults explicitly for validation (GORM defaults only apply on DB insert)
+	models.DefaultServerSettings().ApplyTo(server)
+	if opts.Configure != nil {
+		opts.Configure(server)
+	}
+	if opts.Hold {
+		server.Status = models.StatusStopped
 	}
 
 	// FIX CONFIG-2: Validate configuration values before creating server
@@ -274,9 +336,9 @@ func (s *MinecraftService) CreateServer(
 					log.Printf("Blocking server %s is a ghost server (no container ID)", blockingServer.ID)
 					shouldDelete = true
 				} else {
-					// Check if container actually exists
-					_, statusErr := s.dockerService.GetContainerStatus(blockingServer.ContainerID)
-					if statusErr != nil {
+					// Check if container actually exists (a timed out lookup says nothing about it)
+					_, statusErr := s.dockerService.GetContainerStatus(context.Background(), blockingServer.ContainerID)
+					if statusErr != nil && !errors.Is(statusErr, context.DeadlineExceeded) {
 						log.Printf("Blocking server %s has missing container", blockingServer.ID)
 						shouldDelete = true
 					}
@@ -321,6 +383,35 @@ func (s *MinecraftService) CreateServer(
 	// Publish event
 	events.PublishServerCreated(server.ID, server.OwnerID, string(server.ServerType))
 
+	if !opts.Hold {
+		s.enqueueNewServer(server)
+	}
+
+	log.Printf("Created server %s (%s) on port %d", serverID, name, port)
+	return server, nil
+}
+
+// QueueServer hands a server created with NewServerOptions.Hold to the Conductor
+func (s *MinecraftService) QueueServer(serverID string) error {
+	server, err := s.repo.FindByID(serverID)
+	if err != nil {
+		return fmt.Errorf("server not found: %w", err)
+	}
+	if err := checkNotSuspended(server); err != nil {
+		return err
+	}
+
+	server.Status = models.StatusQueued
+	if err := s.repo.Update(server); err != nil {
+		return fmt.Errorf("failed to queue server: %w", err)
+	}
+
+	s.enqueueNewServer(server)
+	return nil
+}
+
+// enqueueNewServer adds a new server to the start queue
+func (s *MinecraftService) enqueueNewServer(server *models.MinecraftServer) {
 	// Add server to queue and trigger immediate scaling check
 	if s.conductor != nil {
 		// Enqueue the server - Conductor will assign it to a node when capacity is available
@@ -329,16 +420,57 @@ func (s *MinecraftService) CreateServer(
 		// Trigger immediate scaling check to provision capacity if needed
 		s.conductor.TriggerScalingCheck()
 	}
+}
 
-	log.Printf("Created server %s (%s) on port %d", serverID, name, port)
-	return server, nil
+// RequestStart starts a server on behalf of requestedBy within the owner's concurrency limit
+// If the owner is at the limit, the start is queued and the returned operation has status queued.
+func (s *MinecraftService) RequestStart(ctx context.Context, serverID, requestedBy string) (*Operation, error) {
+	server, err := s.repo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	// Reject obviously doomed starts up front instead of queueing them
+	if server.Status == models.StatusRunning {
+		return nil, fmt.Errorf("server already running")
+	}
+	if server.Status == models.StatusStarting {
+		return nil, fmt.Errorf("server is already starting, please wait")
+	}
+	if err := checkNotSuspended(server); err != nil {
+		return nil, err
+	}
+	if err := s.checkBillingGuards(server); err != nil {
+		return nil, err
+	}
+	if err := s.maintenance.Check(); err != nil {
+		return nil, err
+	}
+
+	return s.opLimiter.Do(ctx, server.OwnerID, requestedBy, OperationStart, serverID, "", func(opCtx context.Context) error {
+		return s.StartServerContext(tracing.Inherit(opCtx, ctx), serverID)
+	})
 }
 
 // StartServer starts a Minecraft server
 func (s *MinecraftService) StartServer(serverID string) error {
+	return s.StartServerContext(context.Background(), serverID)
+}
+
+// StartServerContext starts a Minecraft server as part of the trace in ctx
+// (node selection, container start and readiness are child spans). Cancelling ctx, e.g. because
+// the client of the request went away, aborts the start until the container is created;
+// from then on the start is completed, a half-started server would need a cleanup.
+func (s *MinecraftService) StartServerContext(ctx context.Context, serverID string) (err error) {
+	ctx, span := tracing.Start(ctx, "MinecraftService.StartServer", attribute.String("server.id", serverID))
+	defer func() { tracing.End(span, err) }()
+
 	// GAP-4: Acquire operation lock to prevent concurrent operations
 	mu := s.acquireOperationLock(serverID)
 	defer s.releaseOperationLock(serverID, mu)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	server, err := s.repo.FindByID(serverID)
 	if err != nil {
@@ -354,6 +486,16 @@ func (s *MinecraftService) StartServer(serverID string) error {
 		return fmt.Errorf("server is already starting, please wait")
 	}
 
+	// SUSPENSION: Held servers (abuse, billing) stay stopped until an unsuspend
+	if err := checkNotSuspended(server); err != nil {
+		return err
+	}
+
+	// BILLING: Owner must be in good standing (not suspended, enough prepaid credit)
+	if err := s.checkBillingGuards(server); err != nil {
+		return err
+	}
+
 	// PHASE 3 LIFECYCLE: Auto-unarchive if server is archived
 	// This restores the server from Storage Box before starting
 	if server.Status == models.StatusArchived {
@@ -396,62 +538,83 @@ func (s *MinecraftService) StartServer(serverID string) error {
 			return fmt.Errorf("server is already queued for start (waiting for capacity)")
 		}
 
-		// CPU-GUARD: Check if we can start a server now (CPU + RAM checks)
-		canStart, reason := s.conductor.CanStartServer(server.RAMMb)
-		if !canStart {
-			// Cannot start now - add to queue
-			s.conductor.EnqueueServer(server.ID, server.Name, server.RAMMb, server.OwnerID)
+		placement := withPlacementPeers(s.repo, server, s.residency.Placement(server, s.conductor.PlacementFor(server.OwnerID, server.Placement())))
+		reservedNodeID := ""
+		if server.HasGuaranteedStart() {
+			// RESERVED PLAN: Guaranteed start - no CPU guard and no queue; the scaling engine keeps
+			// headroom for stopped reserved servers, and RAM still held on a node is claimed right away
+			reservedNodeID = s.conductor.ReserveGuaranteedStartSlot(server.ID, server.Name, server.RAMMb, placement)
+		} else {
+			// CPU-GUARD: Check if we can start a server now (CPU + RAM checks)
+			canStart, reason := s.conductor.CanStartServer(server.RAMMb)
+			if !canStart {
+				// Cannot start now - add to queue
+				s.conductor.EnqueueServer(server.ID, server.Name, server.RAMMb, server.OwnerID)
 
-			log.Printf("CPU_GUARD: Cannot start server %s (%s) - Added to queue", server.ID, reason)
+				log.Printf("CPU_GUARD: Cannot start server %s (%s) - Added to queue", server.ID, reason)
 
-			return fmt.Errorf("cannot start server (%s) - server queued for start, will auto-start when capacity available", reason)
-		}
+				return fmt.Errorf("cannot start server (%s) - server queued for start, will auto-start when capacity available", reason)
+			}
 
-		// ATOMIC START SLOT RESERVATION: Immediately reserve the "starting" slot
-		// This MUST happen BEFORE Docker starts to prevent race conditions!
-		if !s.conductor.AtomicReserveStartSlot(server.ID, server.Name, server.RAMMb) {
-			// Another server is already starting (race condition detected)
-			s.conductor.EnqueueServer(server.ID, server.Name, server.RAMMb, server.OwnerID)
+			// ATOMIC START SLOT RESERVATION: Immediately reserve the "starting" slot
+			// This MUST happen BEFORE Docker starts to prevent race conditions!
+			if !s.conductor.AtomicReserveStartSlot(server.ID, server.Name, server.RAMMb) {
+				// Another server is already starting (race condition detected)
+				s.conductor.EnqueueServer(server.ID, server.Name, server.RAMMb, server.OwnerID)
 
-			log.Printf("CPU_GUARD: Start slot already taken for server %s - Added to queue", server.ID)
+				log.Printf("CPU_GUARD: Start slot already taken for server %s - Added to queue", server.ID)
 
-			return fmt.Errorf("another server is currently starting (CPU protection) - server queued for start, will auto-start when capacity available")
+				return fmt.Errorf("another server is currently starting (CPU protection) - server queued for start, will auto-start when capacity available")
+			}
 		}
 		startSlotReserved = true
 
-		// MULTI-NODE: Intelligent Node Selection
-		// Select the best node for this container using automatic strategy selection
-		nodeID, err := s.conductor.SelectNodeForContainerAuto(server.RAMMb)
-		if err != nil {
-			// No nodes available with sufficient capacity
-			s.conductor.ReleaseStartSlot(server.ID)
-			startSlotReserved = false
+		if reservedNodeID != "" {
+			// The server's RAM is still allocated on the node it was stopped on
+			selectedNodeID = reservedNodeID
+			log.Printf("RESERVED: Server %s starts on its reserved node %s", server.ID, selectedNodeID)
+		} else {
+			// MULTI-NODE: Intelligent Node Selection
+			// Select the best node for this container using automatic strategy selection
+			_, selectSpan := tracing.Start(ctx, "Conductor.SelectNode", attribute.Int("server.ram_mb", server.RAMMb))
+			nodeID, err := s.conductor.SelectNodeForContainerAuto(server.RAMMb, placement)
+			selectSpan.SetAttributes(attribute.String("node.id", nodeID))
+			tracing.End(selectSpan, err)
+			if err != nil {
+				// No nodes available with sufficient capacity
+				s.conductor.ReleaseStartSlot(server.ID)
+				startSlotReserved = false
 
-			// Add to queue - will auto-start when nodes become available
-			s.conductor.EnqueueServer(server.ID, server.Name, server.RAMMb, server.OwnerID)
+				// Add to queue - will auto-start when nodes become available
+				// (reserved servers only get here while the scaling engine is still adding their headroom)
+				s.conductor.EnqueueServer(server.ID, server.Name, server.RAMMb, server.OwnerID)
 
-			log.Printf("NODE_SELECTION: No nodes available for server %s (%d MB required) - Added to queue: %v",
-				server.ID, server.RAMMb, err)
+				log.Printf("NODE_SELECTION: No nodes available for server %s (%d MB required) - Added to queue: %v",
+					server.ID, server.RAMMb, err)
 
-			return fmt.Errorf("no healthy nodes available with sufficient capacity (%d MB required) - server queued for start", server.RAMMb)
-		}
-		selectedNodeID = nodeID
+				if errors.Is(err, models.ErrNoMatchingNode) {
+					return fmt.Errorf("no node matching the server's placement has capacity (%d MB required) - server queued for start", server.RAMMb)
+				}
+				return fmt.Errorf("no healthy nodes available with sufficient capacity (%d MB required) - server queued for start", server.RAMMb)
+			}
+			selectedNodeID = nodeID
 
-		// ATOMIC RAM ALLOCATION: Lock, check, and allocate in ONE operation on selected node
-		// This prevents race conditions where multiple threads check capacity simultaneously
-		if !s.conductor.AtomicAllocateRAMOnNode(selectedNodeID, server.RAMMb) {
-			// Allocation failed - insufficient capacity
-			// ROLLBACK: Release start slot
-			s.conductor.ReleaseStartSlot(server.ID)
-			startSlotReserved = false
+			// ATOMIC RAM ALLOCATION: Lock, check, and allocate in ONE operation on selected node
+			// This prevents race conditions where multiple threads check capacity simultaneously
+			if !s.conductor.AtomicAllocateRAMOnNode(selectedNodeID, server.RAMMb) {
+				// Allocation failed - insufficient capacity
+				// ROLLBACK: Release start slot
+				s.conductor.ReleaseStartSlot(server.ID)
+				startSlotReserved = false
 
-			// Add to queue instead of starting
-			s.conductor.EnqueueServer(server.ID, server.Name, server.RAMMb, server.OwnerID)
+				// Add to queue instead of starting
+				s.conductor.EnqueueServer(server.ID, server.Name, server.RAMMb, server.OwnerID)
 
-			log.Printf("RESOURCE_GUARD: Insufficient capacity on node %s for server %s (%d MB required) - Added to queue",
-				selectedNodeID, server.ID, server.RAMMb)
+				log.Printf("RESOURCE_GUARD: Insufficient capacity on node %s for server %s (%d MB required) - Added to queue",
+					selectedNodeID, server.ID, server.RAMMb)
 
-			return fmt.Errorf("insufficient capacity to start server (%d MB required) - server queued for start, will auto-start when capacity available", server.RAMMb)
+				return fmt.Errorf("insufficient capacity to start server (%d MB required) - server queued for start, will auto-start when capacity available", server.RAMMb)
+			}
 		}
 
 		// RAM successfully allocated!
@@ -525,10 +688,14 @@ func (s *MinecraftService) StartServer(serverID string) error {
 	// This prevents "port already allocated" errors from zombie containers
 	containerName := fmt.Sprintf("mc-%s", server.ID)
 	log.Printf("Checking for existing container %s before start", containerName)
-	if err := s.dockerService.RemoveContainerByName(containerName); err != nil {
+	if err := s.dockerService.RemoveContainerByName(ctx, containerName); err != nil {
 		log.Printf("Warning: failed to remove old container %s: %v", containerName, err)
 	}
 
+	// COLD-START: Boot timer covers container create/start through the ready marker
+	appCDS := s.appCDSState(ctx, server, selectedNodeID)
+	bootStartedAt := time.Now()
+
 	// MULTI-NODE: Create container on selected node (local or remote)
 	if server.ContainerID == "" || server.ContainerID != "" {
 		// Always create a fresh container to avoid state issues
@@ -538,7 +705,9 @@ func (s *MinecraftService) StartServer(serverID string) error {
 		if s.isLocalNode(selectedNodeID) {
 			// LOCAL NODE: Use existing dockerService.CreateContainer()
 			log.Printf("Creating container for server %s on local node", server.ID)
+			_, createSpan := tracing.Start(ctx, "DockerService.CreateContainer", attribute.String("server.id", server.ID))
 			containerID, err = s.dockerService.CreateContainer(
+				ctx,
 				server.ID,
 				string(server.ServerType),
 				server.MinecraftVersion,
@@ -571,7 +740,14 @@ func (s *MinecraftService) StartServer(serverID string) error {
 				server.NetworkCompressionThreshold,
 				// Phase 4 Parameters - Server Description
 				server.MOTD,
+				// Runtime Diagnostics
+				server.HeapDumpsActive(),
+				// Vote Site Integration
+				server.VotifierPort,
+				s.dockerService.ImageName(server.Channel()),
+				s.dockerService.CPULimits(server),
 			)
+			tracing.End(createSpan, err)
 		} else {
 			// REMOTE NODE: Use RemoteDockerClient with environment builder
 			log.Printf("Creating container for server %s on remote node %s", server.ID, selectedNodeID)
@@ -595,13 +771,12 @@ func (s *MinecraftService) StartServer(serverID string) error {
 
 			// Build container configuration using helper methods
 			containerName := fmt.Sprintf("mc-%s", server.ID)
-			imageName := docker.GetDockerImageName(string(server.ServerType))
+			imageName := s.dockerService.ImageName(server.Channel())
 			env := docker.BuildContainerEnv(server)
-			portBindings := docker.BuildPortBindings(server.Port)
+			portBindings := docker.BuildPortBindings(server.Port, server.VotifierPort)
 			binds := docker.BuildVolumeBinds(server.ID, "/minecraft/servers")
 
 			// Create and start container on remote node
-			ctx := context.Background()
 			containerID, err = s.conductor.GetRemoteDockerClient().StartContainer(
 				ctx,
 				remoteNode,
@@ -611,6 +786,7 @@ func (s *MinecraftService) StartServer(serverID string) error {
 				portBindings,
 				binds,
 				server.RAMMb,
+				s.dockerService.CPULimits(server),
 			)
 		}
 
@@ -633,21 +809,22 @@ func (s *MinecraftService) StartServer(serverID string) error {
 					// Retry container creation after unarchive
 					if s.isLocalNode(selectedNodeID) {
 						containerID, err = s.dockerService.CreateContainer(
-							server.ID, string(server.ServerType), server.MinecraftVersion, server.RAMMb, server.Port,
+							ctx, server.ID, string(server.ServerType), server.MinecraftVersion, server.RAMMb, server.Port,
 							server.MaxPlayers, server.Gamemode, server.Difficulty, server.PVP, server.EnableCommandBlock, server.LevelSeed,
 							server.ViewDistance, server.SimulationDistance, server.AllowNether, server.AllowEnd, server.GenerateStructures,
 							server.WorldType, server.BonusChest, server.MaxWorldSize, server.SpawnProtection, server.SpawnAnimals,
-							server.SpawnMonsters, server.SpawnNPCs, server.MaxTickTime, server.NetworkCompressionThreshold, server.MOTD,
+							server.SpawnMonsters, server.SpawnNPCs, server.MaxTickTime, server.NetworkCompressionThreshold, server.MOTD, server.HeapDumpsActive(), server.VotifierPort,
+							s.dockerService.ImageName(server.Channel()),
+							s.dockerService.CPULimits(server),
 						)
 					} else {
 						remoteNode, _ := s.conductor.GetRemoteNode(selectedNodeID)
 						containerName := fmt.Sprintf("mc-%s", server.ID)
-						imageName := docker.GetDockerImageName(string(server.ServerType))
+						imageName := s.dockerService.ImageName(server.Channel())
 						env := docker.BuildContainerEnv(server)
-						portBindings := docker.BuildPortBindings(server.Port)
+						portBindings := docker.BuildPortBindings(server.Port, server.VotifierPort)
 						binds := docker.BuildVolumeBinds(server.ID, "/minecraft/servers")
-						ctx := context.Background()
-						containerID, err = s.conductor.GetRemoteDockerClient().StartContainer(ctx, remoteNode, containerName, imageName, env, portBindings, binds, server.RAMMb)
+						containerID, err = s.conductor.GetRemoteDockerClient().StartContainer(ctx, remoteNode, containerName, imageName, env, portBindings, binds, server.RAMMb, s.dockerService.CPULimits(server))
 					}
 				}
 			}
@@ -685,6 +862,9 @@ func (s *MinecraftService) StartServer(serverID string) error {
 		}
 	}
 
+	// The container exists: the rest of the start is not cancelled anymore
+	ctx = context.WithoutCancel(ctx)
+
 	// Start container
 	server.Status = models.StatusStarting
 	if err := s.repo.Update(server); err != nil {
@@ -722,7 +902,10 @@ func (s *MinecraftService) StartServer(serverID string) error {
 
 	// Only call StartContainer for LOCAL nodes (remote containers are already started by RemoteDockerClient.StartContainer)
 	if s.isLocalNode(selectedNodeID) {
-		if err := s.dockerService.StartContainer(server.ContainerID); err != nil {
+		_, startSpan := tracing.Start(ctx, "DockerService.StartContainer", attribute.String("server.id", server.ID))
+		err := s.dockerService.StartContainer(ctx, server.ContainerID)
+		tracing.End(startSpan, err)
+		if err != nil {
 			server.Status = models.StatusError
 			s.repo.Update(server)
 			// ROLLBACK: Release RAM and start slot if container start failed
@@ -747,11 +930,15 @@ func (s *MinecraftService) StartServer(serverID string) error {
 	log.Printf("Waiting for Minecraft server %s to be ready...", server.ID)
 
 	// MULTI-NODE FIX: Route readiness check based on node type (local vs remote)
+	readyCtx, readySpan := tracing.Start(ctx, "MinecraftService.WaitForServerReady", attribute.String("node.id", selectedNodeID))
+	ready := false
 	if s.isLocalNode(selectedNodeID) {
 		// LOCAL NODE: Use local Docker client
-		if err := s.dockerService.WaitForServerReady(server.ContainerID, 60); err != nil {
+		if err := s.dockerService.WaitForServerReady(readyCtx, server.ContainerID, 60); err != nil {
 			log.Printf("Warning: Minecraft server %s may not be fully ready: %v", server.ID, err)
 			// Continue anyway - server might still work
+		} else {
+			ready = true
 		}
 	} else {
 		// REMOTE NODE: Use RemoteDockerClient with SSH
@@ -760,21 +947,29 @@ func (s *MinecraftService) StartServer(serverID string) error {
 			if err != nil {
 				log.Printf("Warning: Failed to get remote node for readiness check: %v", err)
 			} else {
-				ctx := context.Background()
-				if err := s.conductor.GetRemoteDockerClient().WaitForServerReady(ctx, remoteNode, server.ContainerID, 60); err != nil {
+				if err := s.conductor.GetRemoteDockerClient().WaitForServerReady(readyCtx, remoteNode, server.ContainerID, 60); err != nil {
 					log.Printf("Warning: Remote Minecraft server %s may not be fully ready: %v", server.ID, err)
 					// Continue anyway - server might still work
+				} else {
+					ready = true
 				}
 			}
 		}
 	}
+	readySpan.End()
+
+	// Failed or timed-out boots would skew the startup metrics
+	if ready {
+		s.recordStartupDuration(server, bootStartedAt, appCDS)
+	}
+	s.waitForStatusPing(server, selectedNodeID) // Beta channel readiness probe
 
 	// Update status
 	now := time.Now()
 	server.Status = models.StatusRunning
 	server.LastStartedAt = &now
 	server.LifecyclePhase = models.PhaseActive // Mark as active when running
-	if err := s.repo.Update(server); err != nil {
+	if err := s.repo.UpdateWithEvent(server, events.ServerStartedOutboxEvent(server.ID, server.OwnerID)); err != nil {
 		return err
 	}
 
@@ -839,7 +1034,10 @@ func (s *MinecraftService) StartServer(serverID string) error {
 // StartServerFromQueue starts a server that was dequeued from the start queue
 // This method BYPASSES queue checks since capacity was already verified during dequeue
 // However, it STILL maintains CPU-Guard and atomic RAM allocation for race condition protection
-func (s *MinecraftService) StartServerFromQueue(serverID string) error {
+func (s *MinecraftService) StartServerFromQueue(serverID string) (err error) {
+	ctx, span := tracing.Start(context.Background(), "MinecraftService.StartServerFromQueue", attribute.String("server.id", serverID))
+	defer func() { tracing.End(span, err) }()
+
 	server, err := s.repo.FindByID(serverID)
 	if err != nil {
 		return fmt.Errorf("server not found: %w", err)
@@ -849,6 +1047,16 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 		return fmt.Errorf("server already running")
 	}
 
+	// SUSPENSION: Server may have been suspended while it was queued
+	if err := checkNotSuspended(server); err != nil {
+		return err
+	}
+
+	// BILLING: Owner may have been suspended or run out of credit while the server was queued
+	if err := s.checkBillingGuards(server); err != nil {
+		return err
+	}
+
 	// QUEUE-BYPASS: Skip capacity and queue checks - we know capacity was available when dequeued
 	// However, we STILL need CPU-Guard slot reservation and RAM allocation for thread safety!
 
@@ -866,7 +1074,10 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 		startSlotReserved = true
 
 		// MULTI-NODE: Intelligent Node Selection for queued server
-		nodeID, err := s.conductor.SelectNodeForContainerAuto(server.RAMMb)
+		_, selectSpan := tracing.Start(ctx, "Conductor.SelectNode", attribute.Int("server.ram_mb", server.RAMMb))
+		nodeID, err := s.conductor.SelectNodeForContainerAuto(server.RAMMb, withPlacementPeers(s.repo, server, s.residency.Placement(server, s.conductor.PlacementFor(server.OwnerID, server.Placement()))))
+		selectSpan.SetAttributes(attribute.String("node.id", nodeID))
+		tracing.End(selectSpan, err)
 		if err != nil {
 			// No nodes available - re-queue
 			s.conductor.ReleaseStartSlot(server.ID)
@@ -975,7 +1186,7 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 	// CRITICAL: Remove any existing container with the same name before creating a new one
 	containerName := fmt.Sprintf("mc-%s", server.ID)
 	log.Printf("Checking for existing container %s before start", containerName)
-	if err := s.dockerService.RemoveContainerByName(containerName); err != nil {
+	if err := s.dockerService.RemoveContainerByName(ctx, containerName); err != nil {
 		log.Printf("Warning: failed to remove old container %s: %v", containerName, err)
 	}
 
@@ -988,13 +1199,19 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 		log.Printf("Warning: ActualRAMMB not set for server %s, using booked RAM %d MB", server.ID, actualRAM)
 	}
 
+	// COLD-START: Boot timer covers container create/start through the ready marker
+	appCDS := s.appCDSState(ctx, server, selectedNodeID)
+	bootStartedAt := time.Now()
+
 	var containerID string
 	if server.ContainerID == "" || server.ContainerID != "" {
 		// Route container creation based on node type
 		if s.isLocalNode(selectedNodeID) {
 			// LOCAL NODE: Use local dockerService
 			log.Printf("Creating container for queued server %s on LOCAL node with %d MB actual RAM", server.ID, actualRAM)
+			_, createSpan := tracing.Start(ctx, "DockerService.CreateContainer", attribute.String("server.id", server.ID))
 			containerID, err = s.dockerService.CreateContainer(
+				ctx,
 				server.ID,
 				string(server.ServerType),
 				server.MinecraftVersion,
@@ -1021,7 +1238,12 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 				server.MaxTickTime,
 				server.NetworkCompressionThreshold,
 				server.MOTD,
+				server.HeapDumpsActive(),
+				server.VotifierPort,
+				s.dockerService.ImageName(server.Channel()),
+				s.dockerService.CPULimits(server),
 			)
+			tracing.End(createSpan, err)
 		} else {
 			// REMOTE NODE: Use RemoteDockerClient with environment builder
 			log.Printf("Creating container for queued server %s on remote node %s", server.ID, selectedNodeID)
@@ -1037,13 +1259,12 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 
 			// Build container configuration using helper methods
 			containerName := fmt.Sprintf("mc-%s", server.ID)
-			imageName := docker.GetDockerImageName(string(server.ServerType))
+			imageName := s.dockerService.ImageName(server.Channel())
 			env := docker.BuildContainerEnv(server)
-			portBindings := docker.BuildPortBindings(server.Port)
+			portBindings := docker.BuildPortBindings(server.Port, server.VotifierPort)
 			binds := docker.BuildVolumeBinds(server.ID, "/minecraft/servers")
 
 			// Create and start container on remote node
-			ctx := context.Background()
 			containerID, err = s.conductor.GetRemoteDockerClient().StartContainer(
 				ctx,
 				remoteNode,
@@ -1053,6 +1274,7 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 				portBindings,
 				binds,
 				server.RAMMb,
+				s.dockerService.CPULimits(server),
 			)
 		}
 
@@ -1125,7 +1347,10 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 
 	// Only call StartContainer for LOCAL nodes (remote containers are already started by RemoteDockerClient.StartContainer)
 	if s.isLocalNode(selectedNodeID) {
-		if err := s.dockerService.StartContainer(server.ContainerID); err != nil {
+		_, startSpan := tracing.Start(ctx, "DockerService.StartContainer", attribute.String("server.id", server.ID))
+		err := s.dockerService.StartContainer(ctx, server.ContainerID)
+		tracing.End(startSpan, err)
+		if err != nil {
 			server.Status = models.StatusError
 			s.repo.Update(server)
 			// ROLLBACK
@@ -1149,10 +1374,14 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 	log.Printf("Waiting for Minecraft server %s to be ready...", server.ID)
 
 	// MULTI-NODE FIX: Route readiness check based on node type (local vs remote)
+	readyCtx, readySpan := tracing.Start(ctx, "MinecraftService.WaitForServerReady", attribute.String("node.id", selectedNodeID))
+	ready := false
 	if s.isLocalNode(selectedNodeID) {
 		// LOCAL NODE: Use local Docker client
-		if err := s.dockerService.WaitForServerReady(server.ContainerID, 60); err != nil {
+		if err := s.dockerService.WaitForServerReady(readyCtx, server.ContainerID, 60); err != nil {
 			log.Printf("Warning: Minecraft server %s may not be fully ready: %v", server.ID, err)
+		} else {
+			ready = true
 		}
 	} else {
 		// REMOTE NODE: Use RemoteDockerClient with SSH
@@ -1161,20 +1390,28 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 			if err != nil {
 				log.Printf("Warning: Failed to get remote node for readiness check: %v", err)
 			} else {
-				ctx := context.Background()
-				if err := s.conductor.GetRemoteDockerClient().WaitForServerReady(ctx, remoteNode, server.ContainerID, 60); err != nil {
+				if err := s.conductor.GetRemoteDockerClient().WaitForServerReady(readyCtx, remoteNode, server.ContainerID, 60); err != nil {
 					log.Printf("Warning: Remote Minecraft server %s may not be fully ready: %v", server.ID, err)
+				} else {
+					ready = true
 				}
 			}
 		}
 	}
+	readySpan.End()
+
+	// Failed or timed-out boots would skew the startup metrics
+	if ready {
+		s.recordStartupDuration(server, bootStartedAt, appCDS)
+	}
+	s.waitForStatusPing(server, selectedNodeID) // Beta channel readiness probe
 
 	// Update status
 	now := time.Now()
 	server.Status = models.StatusRunning
 	server.LastStartedAt = &now
 	server.LifecyclePhase = models.PhaseActive
-	if err := s.repo.Update(server); err != nil {
+	if err := s.repo.UpdateWithEvent(server, events.ServerStartedOutboxEvent(server.ID, server.OwnerID)); err != nil {
 		return err
 	}
 
@@ -1235,6 +1472,15 @@ func (s *MinecraftService) StartServerFromQueue(serverID string) error {
 
 // StopServer stops a Minecraft server
 func (s *MinecraftService) StopServer(serverID string, reason string) error {
+	return s.StopServerContext(context.Background(), serverID, reason)
+}
+
+// StopServerContext stops a Minecraft server as part of the trace in ctx (the stop is not cancelled with ctx)
+func (s *MinecraftService) StopServerContext(ctx context.Context, serverID string, reason string) (err error) {
+	ctx, span := tracing.Start(tracing.Inherit(context.Background(), ctx), "MinecraftService.StopServer",
+		attribute.String("server.id", serverID), attribute.String("stop.reason", reason))
+	defer func() { tracing.End(span, err) }()
+
 	server, err := s.repo.FindByID(serverID)
 	if err != nil {
 		return fmt.Errorf("server not found: %w", err)
@@ -1275,7 +1521,6 @@ func (s *MinecraftService) StopServer(serverID string, reason string) error {
 			stopErr = fmt.Errorf("failed to get remote node: %w", err)
 		} else {
 			// Stop container via remote client
-			ctx := context.Background()
 			stopErr = s.conductor.GetRemoteDockerClient().StopContainer(ctx, remoteNode, server.ContainerID, 30)
 		}
 		if stopErr != nil {
@@ -1286,7 +1531,7 @@ func (s *MinecraftService) StopServer(serverID string, reason string) error {
 	} else {
 		// LOCAL: Stop container via local Docker daemon
 		log.Printf("Stopping local container %s", server.ContainerID)
-		stopErr = s.dockerService.StopContainer(server.ContainerID, 30)
+		stopErr = s.dockerService.StopContainer(ctx, server.ContainerID, 30)
 		if stopErr != nil {
 			log.Printf("ERROR: Failed to stop local container %s: %v", server.ContainerID, stopErr)
 		}
@@ -1302,7 +1547,7 @@ func (s *MinecraftService) StopServer(serverID string, reason string) error {
 	now := time.Now()
 	server.Status = models.StatusStopped
 	server.LastStoppedAt = &now
-	if err := s.repo.Update(server); err != nil {
+	if err := s.repo.UpdateWithEvent(server, events.ServerStoppedOutboxEvent(server.ID, reason)); err != nil {
 		return err
 	}
 
@@ -1334,16 +1579,16 @@ func (s *MinecraftService) StopServer(serverID string, reason string) error {
 			nodeID = "local-node" // Fallback for legacy servers
 		}
 
-		s.conductor.ReleaseRAMOnNode(nodeID, server.RAMMb)
-		log.Printf("RESOURCE_RELEASE: Released %d MB RAM on node %s for server %s", server.RAMMb, nodeID, server.ID)
-
 		// Plan-based container registry handling
-		if server.Plan == "reserved" {
-			// RESERVED PLAN: Keep container in registry with "stopped" status
-			// This reserves the RAM even when stopped for guaranteed fast wake
+		if server.HasGuaranteedStart() {
+			// RESERVED PLAN: Keep container in registry with "stopped" status and its RAM allocated
+			// This reserves the RAM even when stopped for a guaranteed start on the same node
 			s.conductor.UpdateContainerStatus(server.ID, "stopped")
-			log.Printf("REGISTRY_UPDATE: Updated container status to stopped (reserved plan keeps RAM) for server %s", server.ID)
+			log.Printf("REGISTRY_UPDATE: Updated container status to stopped (reserved plan keeps %d MB RAM on node %s) for server %s", server.RAMMb, nodeID, server.ID)
 		} else {
+			s.conductor.ReleaseRAMOnNode(nodeID, server.RAMMb)
+			log.Printf("RESOURCE_RELEASE: Released %d MB RAM on node %s for server %s", server.RAMMb, nodeID, server.ID)
+
 			// PAYPERPLAY/BALANCED PLAN: Remove container from registry
 			// This frees the RAM for other servers (best-effort wake)
 			s.conductor.RemoveContainer(server.ID)
@@ -1426,7 +1671,13 @@ func (s *MinecraftService) DeleteServer(serverID string) error {
 							strings.Contains(errorMsg, "quota limit") ||
 							strings.Contains(errorMsg, "insufficient quota")
 
-			if isQuotaError {
+			if errors.Is(err, models.ErrResidencyViolation) {
+				logger.Warn("DELETE: Pre-deletion backup skipped due to data residency (deletion allowed)", map[string]interface{}{
+					"server_id": serverID,
+					"error":     errorMsg,
+				})
+				// Allow deletion to proceed - the backup may not leave the organization's country
+			} else if isQuotaError {
 				logger.Warn("DELETE: Pre-deletion backup skipped due to quota (deletion allowed)", map[string]interface{}{
 					"server_id": serverID,
 					"error":     errorMsg,
@@ -1492,7 +1743,7 @@ func (s *MinecraftService) DeleteServer(serverID string) error {
 			}
 		} else {
 			// LOCAL: Remove container via local Docker daemon
-			removeErr = s.dockerService.RemoveContainer(server.ContainerID, true)
+			removeErr = s.dockerService.RemoveContainer(context.Background(), server.ContainerID, true)
 			if removeErr != nil {
 				log.Printf("Warning: failed to remove local container %s: %v", server.ContainerID, removeErr)
 			} else {
@@ -1531,17 +1782,140 @@ func (s *MinecraftService) GetServer(serverID string) (*models.MinecraftServer,
 	return s.repo.FindByID(serverID)
 }
 
-// ListServers lists all servers for an owner
+// SetServerPlacement sets the node selector ("key=value,..."), tolerations ("key[=value],...") and
+// pinned node (empty = any node) of a server
+// Applies from the next start; a running server stays on its current node.
+func (s *MinecraftService) SetServerPlacement(serverID, nodeSelector, tolerations, pinnedNodeID string) (*models.MinecraftServer, error) {
+	selector, err := models.ParseNodeLabels(nodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	tolerationList, err := models.ParseNodeTolerations(tolerations)
+	if err != nil {
+		return nil, err
+	}
+	pinnedNodeID = strings.TrimSpace(pinnedNodeID)
+	if !models.ValidatePlacementName(pinnedNodeID) {
+		return nil, models.ErrInvalidPinnedNode
+	}
+
+	server, err := s.repo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	// Store normalized values
+	server.NodeSelector = models.FormatNodeLabels(selector)
+	server.NodeTolerations = models.FormatNodeTolerations(tolerationList)
+	server.PinnedNodeID = pinnedNodeID
+	if err := s.repo.Update(server); err != nil {
+		return nil, fmt.Errorf("failed to update server placement: %w", err)
+	}
+
+	logger.Info("Server placement updated", map[string]interface{}{
+		"server_id":      serverID,
+		"node_selector":  server.NodeSelector,
+		"tolerations":    server.NodeTolerations,
+		"pinned_node_id": server.PinnedNodeID,
+	})
+	return server, nil
+}
+
+// SetServerAffinity sets the anti-affinity group of a server and whether it spreads across nodes
+// Servers of the owner in the same group (with spread: all of the owner's servers) are started on
+// different nodes where capacity allows. Applies from the next start.
+func (s *MinecraftService) SetServerAffinity(serverID, group string, spread bool) (*models.MinecraftServer, error) {
+	group = strings.TrimSpace(group)
+	if !models.ValidatePlacementName(group) {
+		return nil, models.ErrInvalidAffinityGroup
+	}
+
+	server, err := s.repo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	server.AntiAffinityGroup = group
+	server.SpreadAcrossNodes = spread
+	if err := s.repo.Update(server); err != nil {
+		return nil, fmt.Errorf("failed to update server affinity: %w", err)
+	}
+
+	logger.Info("Server affinity updated", map[string]interface{}{
+		"server_id":           serverID,
+		"anti_affinity_group": group,
+		"spread":              spread,
+	})
+	return server, nil
+}
+
+// SetServerCPULimits sets the CPU weight and cap of a server (admin only, 0 = configured default)
+// A running container gets the new limits right away, otherwise they apply on the next start.
+func (s *MinecraftService) SetServerCPULimits(serverID string, shares int, limitCores float64) (*models.MinecraftServer, error) {
+	if err := models.ValidateCPULimits(shares, limitCores); err != nil {
+		return nil, err
+	}
+
+	server, err := s.repo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	server.CPUShares = shares
+	server.CPULimitCores = limitCores
+	if err := s.repo.Update(server); err != nil {
+		return nil, fmt.Errorf("failed to update server CPU limits: %w", err)
+	}
+
+	cpu := s.dockerService.CPULimits(server)
+	if server.Status == models.StatusRunning && server.ContainerID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var updateErr error
+		if s.isLocalNode(server.NodeID) {
+			updateErr = s.dockerService.UpdateContainerCPU(ctx, server.ContainerID, cpu)
+		} else if remoteNode, err := s.conductor.GetRemoteNode(server.NodeID); err != nil {
+			updateErr = err
+		} else {
+			updateErr = s.conductor.GetRemoteDockerClient().UpdateContainerCPU(ctx, remoteNode, server.ContainerID, cpu)
+		}
+		if updateErr != nil {
+			// Don't fail - the container gets the new limits on its next start
+			logger.Warn("Failed to apply CPU limits to running container", map[string]interface{}{
+				"server_id": serverID,
+				"error":     updateErr.Error(),
+			})
+		}
+	}
+
+	logger.Info("Server CPU limits updated", map[string]interface{}{
+		"server_id":  serverID,
+		"cpu_shares": cpu.Shares,
+		"cpu_cores":  cpu.Cores,
+	})
+	return server, nil
+}
+
+// ListServers lists all servers of an owner, including servers shared with them (organizations, shares)
 func (s *MinecraftService) ListServers(ownerID string) ([]models.MinecraftServer, error) {
 	if ownerID == "" {
 		ownerID = "default"
 	}
-	return s.repo.FindByOwner(ownerID)
+	return s.repo.FindAccessible(ownerID)
 }
 
-// ListAllServers lists ALL servers (admin function)
-func (s *MinecraftService) ListAllServers() ([]models.MinecraftServer, error) {
-	return s.repo.FindAll()
+// ListServersPage lists one page of the servers a user can access
+func (s *MinecraftService) ListServersPage(ownerID string, page repository.PageRequest) (*repository.Page[models.MinecraftServer], error) {
+	if ownerID == "" {
+		ownerID = "default"
+	}
+	return s.repo.FindAccessiblePage(ownerID, page)
+}
+
+// ListAllServersPage lists one page of ALL servers (admin function)
+func (s *MinecraftService) ListAllServersPage(page repository.PageRequest) (*repository.Page[models.MinecraftServer], error) {
+	return s.repo.FindAllPage(page)
 }
 
 // ListArchivedServers lists archived servers (optionally filtered by owner)
@@ -1567,7 +1941,10 @@ func (s *MinecraftService) CleanOrphanedServers() (int, error) {
 			reason = "no container ID"
 		} else {
 			// Case 2: Server has container ID but container doesn't exist
-			status, err := s.dockerService.GetContainerStatus(server.ContainerID)
+			status, err := s.dockerService.GetContainerStatus(context.Background(), server.ContainerID)
+			if errors.Is(err, context.DeadlineExceeded) {
+				continue // Docker didn't answer in time, the container may well exist
+			}
 			if err != nil || status == "" {
 				shouldDelete = true
 				reason = fmt.Sprintf("container %s not found", server.ContainerID[:12])
@@ -1594,7 +1971,7 @@ func (s *MinecraftService) GetServerUsage(serverID string) ([]models.UsageLog, e
 }
 
 // GetServerLogs retrieves Docker logs for a server with application events
-func (s *MinecraftService) GetServerLogs(serverID string, tail int) (string, error) {
+func (s *MinecraftService) GetServerLogs(ctx context.Context, serverID string, tail int) (string, error) {
 	server, err := s.repo.FindByID(serverID)
 	if err != nil {
 		return "", err
@@ -1643,7 +2020,7 @@ func (s *MinecraftService) GetServerLogs(serverID string, tail int) (string, err
 		return logOutput.String(), nil
 	}
 
-	containerLogs, err := s.dockerService.GetContainerLogs(server.ContainerID, fmt.Sprintf("%d", tail))
+	containerLogs, err := s.dockerService.GetContainerLogs(ctx, server.ContainerID, fmt.Sprintf("%d", tail))
 	if err != nil {
 		logOutput.WriteString(fmt.Sprintf("Error fetching container logs: %v\n", err))
 		logOutput.WriteString("Container might have been removed. Try starting the server to create a new container.\n")
@@ -1852,26 +2229,101 @@ func (s *MinecraftService) isLocalNode(nodeID string) bool {
 	return nodeID == "" || nodeID == "local-node"
 }
 
+// recordStartupDuration records how long a successful boot took (Prometheus + server record)
+// appCDS is the archive state before the boot (see appCDSState), so cold and warm boots can be compared
+func (s *MinecraftService) recordStartupDuration(server *models.MinecraftServer, bootStartedAt time.Time, appCDS string) {
+	duration := time.Since(bootStartedAt)
+
+	monitoring.ServerStartupDuration.WithLabelValues(string(server.ServerType), appCDS).Observe(duration.Seconds())
+	server.LastStartupDurationMs = duration.Milliseconds()
+
+	logger.Info("COLD-START: Server boot completed", map[string]interface{}{
+		"server_id":   server.ID,
+		"duration_ms": server.LastStartupDurationMs,
+		"appcds":      appCDS,
+	})
+}
+
+// appCDSState returns the AppCDS state of the next boot: "warm" if the server volume holds an archive
+// from an earlier boot, "cold" if the JVM still has to dump one, "disabled" or "unknown" if the volume
+// couldn't be checked. It is read from the volume, so restored and migrated volumes are labelled correctly.
+func (s *MinecraftService) appCDSState(ctx context.Context, server *models.MinecraftServer, nodeID string) string {
+	if !s.cfg.AppCDSEnabled {
+		return "disabled"
+	}
+
+	if s.isLocalNode(nodeID) {
+		_, err := os.Stat(filepath.Join(s.cfg.ServersBasePath, server.ID, s.cfg.AppCDSArchiveName))
+		switch {
+		case err == nil:
+			return "warm"
+		case os.IsNotExist(err):
+			return "cold"
+		default:
+			return "unknown"
+		}
+	}
+
+	if s.conductor == nil {
+		return "unknown"
+	}
+	remoteNode, err := s.conductor.GetRemoteNode(nodeID)
+	if err != nil {
+		return "unknown"
+	}
+	archivePath := fmt.Sprintf("%s/%s/%s", remoteServersPath, server.ID, s.cfg.AppCDSArchiveName)
+	cmd := docker.ShellJoin("test", "-f", archivePath) + " && echo warm || echo cold"
+	output, err := s.conductor.GetRemoteDockerClient().ExecuteSSHCommand(ctx, remoteNode, cmd)
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(output)
+}
+
+// rconHostForServer returns the address RCON is reachable at for the server's node
+func (s *MinecraftService) rconHostForServer(server *models.MinecraftServer) (string, error) {
+	nodeID := server.NodeID
+	if nodeID == "" || nodeID == "local-node" {
+		return "localhost", nil
+	}
+	if s.conductor == nil {
+		return "", fmt.Errorf("conductor not available for remote node %s", nodeID)
+	}
+	remoteNode, err := s.conductor.GetRemoteNode(nodeID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get node info: %w", err)
+	}
+	return remoteNode.IPAddress, nil
+}
+
+// SendServerMessage broadcasts a chat message to all players on a running server via RCON
+func (s *MinecraftService) SendServerMessage(server *models.MinecraftServer, message string) error {
+	rconHost, err := s.rconHostForServer(server)
+	if err != nil {
+		return err
+	}
+
+	client, err := rcon.NewClient(rconHost, server.RCONPort, server.RCONPassword)
+	if err != nil {
+		return fmt.Errorf("RCON connection failed: %w", err)
+	}
+	defer client.Close()
+
+	_, err = client.SendCommand(fmt.Sprintf("say %s", message))
+	return err
+}
+
 // sendShutdownWarning sends a graceful shutdown warning to players via RCON
 // FIX SERVER-8: Give players time to save and disconnect before server stops
 func (s *MinecraftService) sendShutdownWarning(server *models.MinecraftServer) {
 	// Get node info to determine RCON address
-	var rconHost string
-	nodeID := server.NodeID
-	if nodeID == "" || nodeID == "local-node" {
-		rconHost = "localhost"
-	} else if s.conductor != nil {
-		remoteNode, err := s.conductor.GetRemoteNode(nodeID)
-		if err != nil {
-			logger.Warn("SHUTDOWN: Cannot send warning - failed to get node info", map[string]interface{}{
-				"server_id": server.ID,
-				"node_id":   nodeID,
-				"error":     err.Error(),
-			})
-			return
-		}
-		rconHost = remoteNode.IPAddress
-	} else {
+	rconHost, err := s.rconHostForServer(server)
+	if err != nil {
+		logger.Warn("SHUTDOWN: Cannot send warning - failed to get node info", map[string]interface{}{
+			"server_id": server.ID,
+			"node_id":   server.NodeID,
+			"error":     err.Error(),
+		})
 		return
 	}
 
@@ -1927,8 +2379,8 @@ func (s *MinecraftService) sendShutdownWarning(server *models.MinecraftServer) {
 // ===================================
 
 // HandleNodeFailure handles a server whose node has failed
-// This is called by the Health Checker when a node becomes unhealthy
-// It closes billing sessions and updates server status to prevent incorrect billing
+// This is called by the Health Checker when a node becomes unhealthy, or by the FailoverService
+// once the failure is confirmed. It closes billing sessions and marks the server as crashed.
 func (s *MinecraftService) HandleNodeFailure(serverID string) error {
 	server, err := s.repo.FindByID(serverID)
 	if err != nil {
@@ -1944,20 +2396,19 @@ func (s *MinecraftService) HandleNodeFailure(serverID string) error {
 
 	oldNodeID := server.NodeID
 
-	// Update server status to stopped (container is gone)
-	server.Status = models.StatusStopped
+	// Mark server as crashed (container is gone with its node)
+	server.Status = models.StatusError
 	server.NodeID = "" // Clear node assignment since node failed
 	server.ContainerID = "" // Clear container ID
 
-	if err := s.repo.Update(server); err != nil {
+	if err := s.repo.UpdateWithEvent(server, events.ServerStoppedOutboxEvent(server.ID, "node_failure")); err != nil {
 		logger.Error("NODE-FAILURE: Failed to update server status", err, map[string]interface{}{
 			"server_id": serverID,
 		})
 		// Continue anyway to publish event
 	}
 
-	// Publish stopped event to close billing session
-	// The BillingService is subscribed to this event and will close the session
+	// Publish stopped event; the BillingService closes the session from the outbox record
 	events.PublishServerStopped(server.ID, "node_failure")
 
 	logger.Info("NODE-FAILURE: Server handled successfully", map[string]interface{}{
@@ -1970,6 +2421,56 @@ func (s *MinecraftService) HandleNodeFailure(serverID string) error {
 	return nil
 }
 
+// HandleContainerLost marks a server as stopped whose container is gone while the database still
+// says running (found by the drift detection). Billing is closed like on a node failure. Returns
+// false if the server changed in the meantime (stopped, restarted with another container).
+func (s *MinecraftService) HandleContainerLost(serverID, containerID string) (bool, error) {
+	mu := s.acquireOperationLock(serverID)
+	defer s.releaseOperationLock(serverID, mu)
+
+	server, err := s.repo.FindByID(serverID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find server: %w", err)
+	}
+	if server.Status != models.StatusRunning || server.ContainerID != containerID {
+		return false, nil
+	}
+
+	server.Status = models.StatusStopped
+	server.ContainerID = ""
+	if err := s.repo.UpdateWithEvent(server, events.ServerStoppedOutboxEvent(server.ID, "container_lost")); err != nil {
+		return false, fmt.Errorf("failed to update server status: %w", err)
+	}
+	events.PublishServerStopped(server.ID, "container_lost")
+
+	if s.conductor != nil {
+		s.conductor.RemoveContainer(server.ID)
+	}
+	if s.remoteVelocityClient != nil {
+		if err := s.remoteVelocityClient.UnregisterServer(fmt.Sprintf("mc-%s", server.ID)); err != nil {
+			logger.Warn("DRIFT: Failed to unregister lost server from Velocity", map[string]interface{}{
+				"server_id": server.ID,
+				"error":     err.Error(),
+			})
+		}
+	}
+	if s.wsHub != nil {
+		s.wsHub.Broadcast("server_stopped", map[string]interface{}{
+			"server_id": server.ID,
+			"name":      server.Name,
+			"status":    server.Status,
+		})
+	}
+
+	logger.Warn("DRIFT: Server with lost container marked as stopped", map[string]interface{}{
+		"server_id":    server.ID,
+		"server_name":  server.Name,
+		"node_id":      server.NodeID,
+		"container_id": containerID,
+	})
+	return true, nil
+}
+
 // GAP-4: acquireOperationLock gets or creates a mutex for a server operation
 // This prevents concurrent operations on the same server (e.g., Start+Delete, Restore+Start)
 func (s *MinecraftService) acquireOperationLock(serverID string) *sync.Mutex {
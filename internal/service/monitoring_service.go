@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -18,6 +19,11 @@ type MonitoringService struct {
 	repo           *repository.ServerRepository
 	cfg            *config.Config
 	recoveryService *RecoveryService
+	idlePolicyService *IdlePolicyService
+	walletService  *WalletService
+
+	// Owners already warned about a low wallet balance (reset after top-up)
+	lowBalanceWarned map[string]bool
 
 	// Track idle timers per server
 	idleTimers map[string]*IdleTimer
@@ -35,6 +41,7 @@ type IdleTimer struct {
 	LastPlayerCount int
 	CheckInterval  time.Duration
 	TimeoutSeconds int
+	DeferredBy     string // Policy rule currently deferring shutdown (audited once per idle period)
 }
 
 func NewMonitoringService(
@@ -49,6 +56,7 @@ func NewMonitoringService(
 		repo:       repo,
 		cfg:        cfg,
 		idleTimers: make(map[string]*IdleTimer),
+		lowBalanceWarned: make(map[string]bool),
 		ctx:        ctx,
 		cancel:     cancel,
 	}
@@ -77,6 +85,18 @@ func (m *MonitoringService) SetRecoveryService(recoveryService *RecoveryService)
 	log.Println("Recovery service linked to monitoring")
 }
 
+// SetIdlePolicyService sets the idle policy service for configurable shutdown rules
+func (m *MonitoringService) SetIdlePolicyService(idlePolicyService *IdlePolicyService) {
+	m.idlePolicyService = idlePolicyService
+	log.Println("Idle policy service linked to monitoring")
+}
+
+// SetWalletService sets the wallet service for low-balance enforcement
+func (m *MonitoringService) SetWalletService(walletService *WalletService) {
+	m.walletService = walletService
+	log.Println("Wallet service linked to monitoring")
+}
+
 // monitorLoop runs the main monitoring loop
 func (m *MonitoringService) monitorLoop() {
 	ticker := time.NewTicker(60 * time.Second) // Check every 60 seconds
@@ -89,6 +109,11 @@ func (m *MonitoringService) monitorLoop() {
 		case <-ticker.C:
 			m.checkAllServers()
 
+			// Warn or stop servers of prepaid owners running out of credit
+			if m.walletService != nil && m.walletService.IsEnabled() {
+				m.enforceWalletBalances()
+			}
+
 			// Also check for crashed servers if recovery service is available
 			if m.recoveryService != nil {
 				if err := m.recoveryService.CheckAndRecoverCrashedServers(); err != nil {
@@ -223,6 +248,7 @@ func (m *MonitoringService) checkServer(serverID string) {
 	if playerCount > 0 {
 		// Server has players, reset idle timer
 		timer.IdleSince = time.Now()
+		timer.DeferredBy = ""
 		log.Printf("Server %s has %d players online", serverID, playerCount)
 
 		// Update usage log with peak player count
@@ -230,15 +256,32 @@ func (m *MonitoringService) checkServer(serverID string) {
 
 		m.mu.Unlock()
 	} else {
-		// Server is empty, check if timeout reached
+		// Server is empty, evaluate idle-shutdown policy (DB/RCON lookups happen outside the lock)
 		idleDuration := time.Since(timer.IdleSince)
-		timeoutDuration := time.Duration(timer.TimeoutSeconds) * time.Second
+		m.mu.Unlock()
 
-		log.Printf("Server %s idle for %v (timeout: %v)", serverID, idleDuration.Round(time.Second), timeoutDuration)
+		eval := m.evaluateIdlePolicy(server, idleDuration)
 
-		if idleDuration >= timeoutDuration {
-			m.mu.Unlock()
+		m.mu.Lock()
+		timer.TimeoutSeconds = eval.TimeoutSeconds
+		auditDeferral := eval.TimeoutReached && eval.Decision == models.ShutdownDecisionDeferred && timer.DeferredBy != eval.Rule
+		if eval.Decision == models.ShutdownDecisionDeferred && eval.TimeoutReached {
+			timer.DeferredBy = eval.Rule
+		}
+		m.mu.Unlock()
+
+		log.Printf("Server %s idle for %v (timeout: %ds, rule: %s)", serverID, idleDuration.Round(time.Second), eval.TimeoutSeconds, eval.Rule)
+
+		if auditDeferral && m.idlePolicyService != nil {
+			log.Printf("Server %s idle timeout reached but shutdown deferred: %s", serverID, eval.Reason)
+			m.idlePolicyService.RecordDecision(serverID, eval, idleDuration, playerCount)
+		}
+
+		if eval.Decision == models.ShutdownDecisionShutdown {
 			log.Printf("Server %s reached idle timeout, shutting down...", serverID)
+			if m.idlePolicyService != nil {
+				m.idlePolicyService.RecordDecision(serverID, eval, idleDuration, playerCount)
+			}
 
 			// Auto-shutdown
 			if err := m.mcService.StopServer(serverID, "idle"); err != nil {
@@ -247,12 +290,28 @@ func (m *MonitoringService) checkServer(serverID string) {
 				log.Printf("Successfully stopped idle server %s", serverID)
 				m.StopMonitoring(serverID)
 			}
-		} else {
-			m.mu.Unlock()
 		}
 	}
 }
 
+// evaluateIdlePolicy applies the server's idle-shutdown policy, falling back to the plain timeout
+func (m *MonitoringService) evaluateIdlePolicy(server *models.MinecraftServer, idleDuration time.Duration) IdlePolicyEvaluation {
+	if m.idlePolicyService != nil {
+		return m.idlePolicyService.Evaluate(server, idleDuration)
+	}
+
+	eval := IdlePolicyEvaluation{
+		Decision:       models.ShutdownDecisionDeferred,
+		Rule:           "idle_timeout_day",
+		TimeoutSeconds: server.IdleTimeoutSeconds,
+	}
+	if idleDuration >= time.Duration(server.IdleTimeoutSeconds)*time.Second {
+		eval.Decision = models.ShutdownDecisionShutdown
+		eval.TimeoutReached = true
+	}
+	return eval
+}
+
 // getPlayerCount attempts to get the current player count via RCON
 func (m *MonitoringService) getPlayerCount(_ *models.MinecraftServer) (int, error) {
 	// RCON is on port 25575 by default for itzg/minecraft-server
@@ -364,3 +423,66 @@ func (m *MonitoringService) DisableAutoShutdown(serverID string) error {
 
 	return nil
 }
+
+// enforceWalletBalances warns owners at low balance and gracefully stops their servers at zero
+func (m *MonitoringService) enforceWalletBalances() {
+	servers, err := m.repo.FindAll()
+	if err != nil {
+		log.Printf("Error loading servers for wallet check: %v", err)
+		return
+	}
+
+	// Group running servers by owner
+	byOwner := make(map[string][]models.MinecraftServer)
+	for _, server := range servers {
+		if server.Status == models.StatusRunning {
+			byOwner[server.OwnerID] = append(byOwner[server.OwnerID], server)
+		}
+	}
+
+	threshold := m.walletService.LowBalanceThreshold()
+
+	for ownerID, ownerServers := range byOwner {
+		if !m.walletService.IsPrepaid(ownerID) {
+			continue
+		}
+
+		balance, err := m.walletService.GetBalance(ownerID)
+		if err != nil {
+			log.Printf("Error getting wallet balance for owner %s: %v", ownerID, err)
+			continue
+		}
+
+		if balance <= 0 {
+			for _, server := range ownerServers {
+				log.Printf("Wallet of owner %s is empty (%.2f EUR), stopping server %s", ownerID, balance, server.ID)
+				if err := m.mcService.StopServer(server.ID, "insufficient_balance"); err != nil {
+					log.Printf("Error stopping server %s for insufficient balance: %v", server.ID, err)
+				}
+			}
+			continue
+		}
+
+		m.mu.Lock()
+		if balance >= threshold {
+			delete(m.lowBalanceWarned, ownerID)
+			m.mu.Unlock()
+			continue
+		}
+		alreadyWarned := m.lowBalanceWarned[ownerID]
+		m.lowBalanceWarned[ownerID] = true
+		m.mu.Unlock()
+
+		if alreadyWarned {
+			continue
+		}
+
+		log.Printf("Wallet of owner %s is low (%.2f EUR), warning players on %d server(s)", ownerID, balance, len(ownerServers))
+		message := fmt.Sprintf("Low credit: %.2f EUR left. The server will stop when the balance reaches zero.", balance)
+		for i := range ownerServers {
+			if err := m.mcService.SendServerMessage(&ownerServers[i], message); err != nil {
+				log.Printf("Failed to send low-balance warning to server %s: %v", ownerServers[i].ID, err)
+			}
+		}
+	}
+}
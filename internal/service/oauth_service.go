@@ -10,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/payperplay/hosting/internal/models"
@@ -26,8 +28,20 @@ type OAuthService struct {
 	cfg             *config.Config
 	securityService *SecurityService
 	emailService    *EmailService
+
+	// Background token refresh
+	refreshTicker   *time.Ticker
+	refreshStopChan chan bool
+	refreshMu       sync.Mutex
 }
 
+const (
+	oauthRefreshInterval    = 15 * time.Minute   // How often the refresh worker runs
+	oauthRefreshAhead       = 30 * time.Minute   // Refresh access tokens expiring within this window
+	oauthRefreshTokenMaxAge = 7 * 24 * time.Hour // Rotate refresh tokens at least this often
+	oauthRefreshAlertAfter  = 3                  // Consecutive failures before the user is alerted
+)
+
 // NewOAuthService creates a new OAuth service
 func NewOAuthService(db *gorm.DB, userRepo *repository.UserRepository, cfg *config.Config, securityService *SecurityService, emailService *EmailService) *OAuthService {
 	return &OAuthService{
@@ -36,6 +50,7 @@ func NewOAuthService(db *gorm.DB, userRepo *repository.UserRepository, cfg *conf
 		cfg:             cfg,
 		securityService: securityService,
 		emailService:    emailService,
+		refreshStopChan: make(chan bool),
 	}
 }
 
@@ -178,13 +193,23 @@ func (s *OAuthService) HandleCallback(provider models.OAuthProviderType, code, s
 		return "", nil, false, err
 	}
 
-	// Generate JWT token
 	authService := &AuthService{
 		userRepo:        s.userRepo,
 		cfg:             s.cfg,
 		emailService:    s.emailService,
 		securityService: s.securityService,
 	}
+
+	// Accounts with 2FA get a challenge token; the login is completed via /api/auth/2fa/login
+	if user.TwoFactorEnabled {
+		challenge, err := authService.GenerateTwoFactorChallenge(user)
+		if err != nil {
+			return "", nil, false, err
+		}
+		return challenge, user, isNewDevice, models.ErrTwoFactorRequired
+	}
+
+	// Generate JWT token
 	token, err := authService.GenerateToken(user)
 	if err != nil {
 		return "", nil, false, err
@@ -371,6 +396,8 @@ func (s *OAuthService) findOrCreateUser(provider models.OAuthProviderType, userI
 			oauthAccount.ExpiresAt = &expiresAt
 		}
 		oauthAccount.LastUsedAt = time.Now()
+		oauthAccount.RefreshFailures = 0
+		oauthAccount.LastRefreshError = ""
 		s.db.Save(&oauthAccount)
 
 		// Check for new device
@@ -396,7 +423,8 @@ func (s *OAuthService) findOrCreateUser(provider models.OAuthProviderType, userI
 			Email:         userInfo.Email,
 			Username:      userInfo.Username,
 			Password:      generateRandomPassword(), // Random password for OAuth-only users
-			EmailVerified: userInfo.Verified,        // OAuth providers verify emails
+			OAuthOnly:     true,
+			EmailVerified: userInfo.Verified, // OAuth providers verify emails
 			IsActive:      true,
 			IsAdmin:       false,
 			Balance:       0.0,
@@ -437,6 +465,248 @@ func (s *OAuthService) findOrCreateUser(provider models.OAuthProviderType, userI
 	return user, isNewUser, !isNewUser, nil
 }
 
+// ========================================
+// Linked Provider Management
+// ========================================
+
+// LinkedProvider is the public view of a linked OAuth account
+type LinkedProvider struct {
+	Provider        models.OAuthProviderType `json:"provider"`
+	Email           string                   `json:"email"`
+	Username        string                   `json:"username"`
+	AvatarURL       string                   `json:"avatar_url"`
+	LinkedAt        time.Time                `json:"linked_at"`
+	LastUsedAt      time.Time                `json:"last_used_at"`
+	LastRefreshedAt *time.Time               `json:"last_refreshed_at,omitempty"`
+	TokenExpired    bool                     `json:"token_expired"`
+	RefreshFailures int                      `json:"refresh_failures"`
+}
+
+// ListLinkedProviders returns all OAuth providers linked to a user and whether the user has a usable password
+func (s *OAuthService) ListLinkedProviders(userID string) ([]LinkedProvider, bool, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var accounts []models.OAuthAccount
+	if err := s.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&accounts).Error; err != nil {
+		return nil, false, err
+	}
+
+	providers := make([]LinkedProvider, 0, len(accounts))
+	for _, account := range accounts {
+		providers = append(providers, LinkedProvider{
+			Provider:        account.Provider,
+			Email:           account.Email,
+			Username:        account.Username,
+			AvatarURL:       account.AvatarURL,
+			LinkedAt:        account.CreatedAt,
+			LastUsedAt:      account.LastUsedAt,
+			LastRefreshedAt: account.LastRefreshedAt,
+			TokenExpired:    account.IsExpired(),
+			RefreshFailures: account.RefreshFailures,
+		})
+	}
+
+	return providers, !user.OAuthOnly, nil
+}
+
+// UnlinkProvider removes a linked OAuth provider
+// At least one login method (password or another provider) must remain
+func (s *OAuthService) UnlinkProvider(userID string, provider models.OAuthProviderType, ipAddress, userAgent string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	var account models.OAuthAccount
+	if err := s.db.Where("user_id = ? AND provider = ?", userID, provider).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("provider %s is not linked", provider)
+		}
+		return err
+	}
+
+	if user.OAuthOnly {
+		var linkedCount int64
+		if err := s.db.Model(&models.OAuthAccount{}).Where("user_id = ?", userID).Count(&linkedCount).Error; err != nil {
+			return err
+		}
+		if linkedCount <= 1 {
+			return models.ErrLastLoginMethod
+		}
+	}
+
+	// Hard delete so stored tokens don't linger in soft-deleted rows
+	if err := s.db.Unscoped().Where("user_id = ? AND provider = ?", userID, provider).Delete(&models.OAuthAccount{}).Error; err != nil {
+		return err
+	}
+
+	_ = s.securityService.LogSecurityEvent(userID, models.EventOAuthUnlinked, ipAddress, userAgent, true, fmt.Sprintf("Unlinked %s account", provider))
+
+	logger.Info("OAuth provider unlinked", map[string]interface{}{
+		"user_id":  userID,
+		"provider": provider,
+	})
+
+	return nil
+}
+
+// ========================================
+// Background Token Refresh
+// ========================================
+
+// StartTokenRefresh starts the background worker that refreshes stale OAuth tokens
+func (s *OAuthService) StartTokenRefresh() {
+	logger.Info("Starting OAuth token refresh worker", map[string]interface{}{
+		"interval": oauthRefreshInterval.String(),
+	})
+
+	go s.refreshStaleTokens()
+
+	s.refreshTicker = time.NewTicker(oauthRefreshInterval)
+
+	go func() {
+		for {
+			select {
+			case <-s.refreshTicker.C:
+				s.refreshStaleTokens()
+			case <-s.refreshStopChan:
+				logger.Info("Stopping OAuth token refresh worker", nil)
+				return
+			}
+		}
+	}()
+}
+
+// StopTokenRefresh stops the background token refresh worker
+func (s *OAuthService) StopTokenRefresh() {
+	if s.refreshTicker != nil {
+		s.refreshTicker.Stop()
+	}
+	s.refreshStopChan <- true
+}
+
+// refreshStaleTokens refreshes tokens that expire soon and rotates old refresh tokens
+func (s *OAuthService) refreshStaleTokens() {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	now := time.Now()
+	var accounts []models.OAuthAccount
+	err := s.db.Where("refresh_token <> ''").
+		Where("(expires_at IS NOT NULL AND expires_at < ?) OR COALESCE(last_refreshed_at, created_at) < ?",
+			now.Add(oauthRefreshAhead), now.Add(-oauthRefreshTokenMaxAge)).
+		Find(&accounts).Error
+	if err != nil {
+		logger.Error("Failed to load OAuth accounts for token refresh", err, nil)
+		return
+	}
+
+	for i := range accounts {
+		s.refreshAccount(&accounts[i])
+	}
+}
+
+// refreshAccount runs the refresh_token grant for a single account and records the outcome
+func (s *OAuthService) refreshAccount(account *models.OAuthAccount) {
+	tokenResp, err := s.refreshAccessToken(account.Provider, account.RefreshToken)
+	if err != nil {
+		account.RefreshFailures++
+		account.LastRefreshError = truncateString(err.Error(), 500)
+		s.db.Save(account)
+
+		logger.Warn("OAuth token refresh failed", map[string]interface{}{
+			"user_id":  account.UserID,
+			"provider": account.Provider,
+			"failures": account.RefreshFailures,
+			"error":    err.Error(),
+		})
+
+		// Alert once when the failure threshold is reached
+		if account.RefreshFailures == oauthRefreshAlertAfter {
+			_ = s.securityService.LogSecurityEvent(account.UserID, models.EventOAuthRefreshFailed, "", "", false,
+				fmt.Sprintf("Token refresh for %s failed %d times: %s", account.Provider, account.RefreshFailures, account.LastRefreshError))
+			if user, err := s.userRepo.FindByID(account.UserID); err == nil {
+				_ = s.securityService.SendOAuthRefreshFailedAlert(user, account.Provider)
+			}
+		}
+		return
+	}
+
+	now := time.Now()
+	account.AccessToken = tokenResp.AccessToken
+	if tokenResp.RefreshToken != "" {
+		account.RefreshToken = tokenResp.RefreshToken // Provider rotated the refresh token
+	}
+	if tokenResp.ExpiresIn > 0 {
+		expiresAt := now.Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+		account.ExpiresAt = &expiresAt
+	}
+	account.LastRefreshedAt = &now
+	account.RefreshFailures = 0
+	account.LastRefreshError = ""
+
+	if err := s.db.Save(account).Error; err != nil {
+		logger.Error("Failed to save refreshed OAuth token", err, map[string]interface{}{
+			"user_id":  account.UserID,
+			"provider": account.Provider,
+		})
+		return
+	}
+
+	logger.Info("OAuth token refreshed", map[string]interface{}{
+		"user_id":  account.UserID,
+		"provider": account.Provider,
+		"rotated":  tokenResp.RefreshToken != "",
+	})
+}
+
+// refreshAccessToken exchanges a refresh token for a new access token
+func (s *OAuthService) refreshAccessToken(provider models.OAuthProviderType, refreshToken string) (*TokenResponse, error) {
+	providerCfg, err := s.GetProviderConfig(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	data := url.Values{}
+	data.Set("client_id", providerCfg.ClientID)
+	data.Set("client_secret", providerCfg.ClientSecret)
+	data.Set("refresh_token", refreshToken)
+	data.Set("grant_type", "refresh_token")
+
+	req, err := http.NewRequestWithContext(context.Background(), "POST", providerCfg.TokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("token refresh failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, err
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, errors.New("token refresh returned no access token")
+	}
+
+	return &tokenResp, nil
+}
+
 // Helper functions
 
 func generateRandomState() (string, error) {
@@ -453,6 +723,13 @@ func generateRandomPassword() string {
 	return base64.URLEncoding.EncodeToString(b)
 }
 
+func truncateString(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
+
 func joinScopes(scopes []string) string {
 	result := ""
 	for i, scope := range scopes {
@@ -0,0 +1,654 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// finishedOperationRetention is how long completed/failed/cancelled operations stay visible
+const finishedOperationRetention = time.Hour
+
+// OperationKind is a heavy operation tracked by the OperationLimiter
+// Starts, manual backups, restores, clones, template provisioning, world resets, exports and imports and selective
+// unarchives count against the owner's concurrency limit;
+// migrations, archives and system backups are only tracked (see Run and Track).
+type OperationKind string
+
+const (
+	OperationStart       OperationKind = "start"
+	OperationBackup      OperationKind = "backup"
+	OperationRestore     OperationKind = "restore"
+	OperationMigration   OperationKind = "migration"
+	OperationArchive     OperationKind = "archive"
+	OperationClone       OperationKind = "clone"
+	OperationProvision   OperationKind = "provision" // World and plugins of a server created from a template
+	OperationWorldReset  OperationKind = "world_reset"
+	OperationWorldExport OperationKind = "world_export"
+	OperationWorldImport OperationKind = "world_import"
+	OperationUnarchive   OperationKind = "unarchive" // Selective restore of parts of an archived server
+)
+
+// OperationStatus is the lifecycle state of a limited operation
+type OperationStatus string
+
+const (
+	OperationQueued     OperationStatus = "queued" // Waiting for a free slot of the owner
+	OperationRunning    OperationStatus = "running"
+	OperationCancelling OperationStatus = "cancelling" // Cancellation requested, waiting for the operation to clean up
+	OperationCompleted  OperationStatus = "completed"
+	OperationFailed     OperationStatus = "failed"
+	OperationCancelled  OperationStatus = "cancelled" // Removed from the queue or aborted while running
+)
+
+// Operation limiter errors
+var (
+	ErrOperationQueueFull      = errors.New("too many queued operations, please wait for running operations to finish")
+	ErrOperationNotFound       = errors.New("operation not found")
+	ErrOperationNotCancellable = errors.New("operation has already finished or cannot be cancelled")
+	ErrOperationLimiterUnset   = errors.New("operation limits are not enabled")
+)
+
+// Operation is a start, backup or restore tracked by the OperationLimiter
+type Operation struct {
+	ID          string          `json:"id"`
+	OwnerID     string          `json:"owner_id"`               // Server owner whose slots are used
+	RequestedBy string          `json:"requested_by,omitempty"` // User who triggered it (owner, org member or share grantee)
+	Kind        OperationKind   `json:"kind"`
+	ServerID    string          `json:"server_id"`
+	ResourceID  string          `json:"resource_id,omitempty"` // Backup ID for backups and restores, migration ID for migrations, server ID for archives, new server ID for clones and provisioning
+	Status      OperationStatus `json:"status"`
+	Position    int             `json:"position,omitempty"` // 1-based queue position (queued only)
+	Phase       string          `json:"phase,omitempty"`    // e.g. compressing, uploading (see SetProgress)
+	Progress    int             `json:"progress"`           // Percent of the current phase
+	Cancellable bool            `json:"cancellable"`        // POST /api/operations/:id/cancel is accepted
+	Error       string          `json:"error,omitempty"`
+	QueuedAt    time.Time       `json:"queued_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+
+	run     func(ctx context.Context) error
+	ctx     context.Context
+	cancel  func() error // Asks the running operation to stop (nil = not cancellable while running)
+	limited bool         // Holds one of the owner's slots while running
+}
+
+// IsQueued returns true if the operation is waiting for a free slot
+func (o *Operation) IsQueued() bool {
+	return o != nil && o.Status == OperationQueued
+}
+
+// cancellableWhileRunning returns true if the running operation can be asked to stop
+// Starts only observe their context until the container is created, so cancelling them
+// would mostly hide the result.
+func (o *Operation) cancellableWhileRunning() bool {
+	return o.cancel != nil && o.Kind != OperationStart
+}
+
+// ownerOperations is the slot usage and FIFO queue of one owner
+type ownerOperations struct {
+	running int
+	queue   []*Operation
+}
+
+// OperationLimiter caps concurrent heavy operations per server owner.
+// The limit depends on the owner's plan (User.BackupPlan); operations beyond the limit are
+// queued in FIFO order and started as soon as one of the owner's operations finishes.
+// State is in-memory: queued operations are lost on restart (the API caller can simply retry).
+// All methods are safe on a nil *OperationLimiter (operations run without limits).
+type OperationLimiter struct {
+	userRepo *repository.UserRepository
+	cfg      *config.Config
+	wsHub    WebSocketHubInterface // Optional
+	jobs     *JobService           // Optional, persists every status change
+
+	mu     sync.Mutex
+	owners map[string]*ownerOperations
+	ops    map[string]*Operation
+}
+
+// NewOperationLimiter creates a new per-owner operation limiter
+func NewOperationLimiter(userRepo *repository.UserRepository, cfg *config.Config) *OperationLimiter {
+	return &OperationLimiter{
+		userRepo: userRepo,
+		cfg:      cfg,
+		owners:   make(map[string]*ownerOperations),
+		ops:      make(map[string]*Operation),
+	}
+}
+
+// SetWebSocketHub sets the WebSocket hub for operation status broadcasts
+func (l *OperationLimiter) SetWebSocketHub(wsHub WebSocketHubInterface) {
+	l.wsHub = wsHub
+}
+
+// SetJobService sets where status and progress changes are persisted (GET /api/jobs)
+func (l *OperationLimiter) SetJobService(jobs *JobService) {
+	l.jobs = jobs
+}
+
+// Limit returns the number of concurrent operations allowed for ownerID (0 = unlimited)
+func (l *OperationLimiter) Limit(ownerID string) int {
+	plan := "basic"
+	if user, err := l.userRepo.FindByID(ownerID); err == nil && user.BackupPlan != "" {
+		plan = user.BackupPlan
+	}
+
+	switch plan {
+	case "enterprise":
+		return l.cfg.OperationLimitEnterprise
+	case "premium":
+		return l.cfg.OperationLimitPremium
+	default:
+		return l.cfg.OperationLimitBasic
+	}
+}
+
+// Do runs fn synchronously if ownerID has a free slot and returns its error.
+// Otherwise fn is queued (the returned operation has status queued) and runs in the background later.
+// fn should return ctx.Err() once it notices ctx is done (cancellation via Cancel, or ctx of the
+// caller ending while fn runs synchronously; queued operations don't depend on ctx).
+func (l *OperationLimiter) Do(ctx context.Context, ownerID, requestedBy string, kind OperationKind, serverID, resourceID string, fn func(ctx context.Context) error) (*Operation, error) {
+	if l == nil {
+		return nil, fn(ctx)
+	}
+
+	op, runNow, err := l.enqueue(ownerID, requestedBy, kind, serverID, resourceID, fn)
+	if err != nil {
+		return nil, err
+	}
+	if !runNow {
+		return l.snapshot(op), nil
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		l.mu.Lock()
+		if op.cancel != nil {
+			op.cancel()
+		}
+		l.mu.Unlock()
+	})
+	err = l.execute(op)
+	stop()
+	return l.snapshot(op), err
+}
+
+// Go runs fn in the background, immediately if ownerID has a free slot or once one frees up
+func (l *OperationLimiter) Go(ownerID, requestedBy string, kind OperationKind, serverID, resourceID string, fn func(ctx context.Context) error) (*Operation, error) {
+	if l == nil {
+		go fn(context.Background())
+		return nil, nil
+	}
+
+	op, runNow, err := l.enqueue(ownerID, requestedBy, kind, serverID, resourceID, fn)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := l.snapshot(op)
+	if runNow {
+		go l.execute(op)
+	}
+	return snapshot, nil
+}
+
+// Run runs fn synchronously without using a slot of ownerID (system operations like archiving)
+// The operation is listed and can be cancelled like limited ones; fn should honor ctx.
+func (l *OperationLimiter) Run(ownerID, requestedBy string, kind OperationKind, serverID, resourceID string, fn func(ctx context.Context) error) error {
+	if l == nil {
+		return fn(context.Background())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	op := l.Track(ownerID, requestedBy, kind, serverID, resourceID, func() error {
+		cancel()
+		return nil
+	})
+	err := fn(ctx)
+	l.Finish(op, err)
+	return err
+}
+
+// Track registers a running operation with its own lifecycle (e.g. migrations) without using a slot
+// cancel (optional) is called by Cancel and may return an error if the operation is past the point
+// of no return. The caller must report the outcome with Finish.
+func (l *OperationLimiter) Track(ownerID, requestedBy string, kind OperationKind, serverID, resourceID string, cancel func() error) *Operation {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune()
+	op := &Operation{
+		ID:          uuid.New().String(),
+		OwnerID:     ownerID,
+		RequestedBy: requestedBy,
+		Kind:        kind,
+		ServerID:    serverID,
+		ResourceID:  resourceID,
+		QueuedAt:    time.Now(),
+		cancel:      cancel,
+	}
+	l.ops[op.ID] = op
+	l.startLocked(op)
+	return op
+}
+
+// Finish records the outcome of a tracked operation (context.Canceled = cancelled)
+func (l *OperationLimiter) Finish(op *Operation, err error) {
+	if l == nil || op == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.finishLocked(op, err)
+}
+
+// SetProgress records the phase and percent of the running operation of kind working on resourceID
+// Unknown or finished operations are ignored, so callers can report progress unconditionally.
+func (l *OperationLimiter) SetProgress(kind OperationKind, resourceID, phase string, percent int) {
+	if l == nil || resourceID == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, op := range l.ops {
+		if op.Kind != kind || op.ResourceID != resourceID || op.FinishedAt != nil || op.Status == OperationQueued {
+			continue
+		}
+		if op.Phase == phase && op.Progress == percent {
+			return
+		}
+		op.Phase = phase
+		op.Progress = min(max(percent, 0), 100)
+		l.publishLocked(op)
+		return
+	}
+}
+
+// List returns the operations owned or requested by userID (oldest first)
+func (l *OperationLimiter) List(userID string) []Operation {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune()
+	ops := make([]Operation, 0)
+	for _, op := range l.ops {
+		if op.OwnerID == userID || op.RequestedBy == userID {
+			ops = append(ops, *l.snapshotLocked(op))
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].QueuedAt.Before(ops[j].QueuedAt) })
+	return ops
+}
+
+// ActiveCount returns the number of queued, running and cancelling operations of all owners
+func (l *OperationLimiter) ActiveCount() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	active := 0
+	for _, op := range l.ops {
+		switch op.Status {
+		case OperationQueued, OperationRunning, OperationCancelling:
+			active++
+		}
+	}
+	return active
+}
+
+// Get returns an operation owned or requested by userID
+func (l *OperationLimiter) Get(userID, operationID string) (*Operation, error) {
+	if l == nil {
+		return nil, ErrOperationNotFound
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	op, ok := l.ops[operationID]
+	if !ok || (op.OwnerID != userID && op.RequestedBy != userID) {
+		return nil, ErrOperationNotFound
+	}
+	return l.snapshotLocked(op), nil
+}
+
+// Cancel cancels an operation owned or requested by userID
+// Queued operations are removed from the queue. Running operations are asked to stop and stay
+// "cancelling" until they have cleaned up; they end as cancelled (or completed if they were too far along).
+func (l *OperationLimiter) Cancel(userID, operationID string) (*Operation, error) {
+	if l == nil {
+		return nil, ErrOperationLimiterUnset
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	op, ok := l.ops[operationID]
+	if !ok || (op.OwnerID != userID && op.RequestedBy != userID) {
+		return nil, ErrOperationNotFound
+	}
+	if op.Status == OperationRunning {
+		return l.cancelRunningLocked(op, userID)
+	}
+	if op.Status != OperationQueued {
+		return nil, ErrOperationNotCancellable
+	}
+
+	owner := l.owners[op.OwnerID]
+	for i, queued := range owner.queue {
+		if queued == op {
+			owner.queue = append(owner.queue[:i], owner.queue[i+1:]...)
+			break
+		}
+	}
+	now := time.Now()
+	op.Status = OperationCancelled
+	op.FinishedAt = &now
+	op.cancel() // Releases the context of the operation
+	op.run = nil
+	op.cancel = nil
+
+	logger.Info("OPERATIONS: Queued operation cancelled", map[string]interface{}{
+		"operation_id": op.ID,
+		"owner_id":     op.OwnerID,
+		"kind":         op.Kind,
+		"server_id":    op.ServerID,
+		"cancelled_by": userID,
+	})
+	l.publishLocked(op)
+	l.publishQueueLocked(owner)
+	return l.snapshotLocked(op), nil
+}
+
+// cancelRunningLocked asks a running operation to stop (l.mu must be held)
+func (l *OperationLimiter) cancelRunningLocked(op *Operation, userID string) (*Operation, error) {
+	if !op.cancellableWhileRunning() {
+		return nil, ErrOperationNotCancellable
+	}
+	if err := op.cancel(); err != nil {
+		logger.Info("OPERATIONS: Running operation refused cancellation", map[string]interface{}{
+			"operation_id": op.ID,
+			"kind":         op.Kind,
+			"server_id":    op.ServerID,
+			"reason":       err.Error(),
+		})
+		return nil, ErrOperationNotCancellable
+	}
+	op.Status = OperationCancelling
+
+	logger.Info("OPERATIONS: Cancellation of running operation requested", map[string]interface{}{
+		"operation_id": op.ID,
+		"owner_id":     op.OwnerID,
+		"kind":         op.Kind,
+		"server_id":    op.ServerID,
+		"cancelled_by": userID,
+	})
+	l.publishLocked(op)
+	return l.snapshotLocked(op), nil
+}
+
+// enqueue registers an operation and reserves a slot if one is free (runNow = true)
+// A queued operation of the same kind for the same server and resource is reused instead of queued twice.
+func (l *OperationLimiter) enqueue(ownerID, requestedBy string, kind OperationKind, serverID, resourceID string, fn func(ctx context.Context) error) (*Operation, bool, error) {
+	limit := l.Limit(ownerID) // DB lookup outside the lock
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune()
+	owner := l.ownerLocked(ownerID)
+	for _, queued := range owner.queue {
+		if queued.Kind == kind && queued.ServerID == serverID && queued.ResourceID == resourceID {
+			return queued, false, nil
+		}
+	}
+
+	op := &Operation{
+		ID:          uuid.New().String(),
+		OwnerID:     ownerID,
+		RequestedBy: requestedBy,
+		Kind:        kind,
+		ServerID:    serverID,
+		ResourceID:  resourceID,
+		QueuedAt:    time.Now(),
+		run:         fn,
+		limited:     true,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	op.ctx = ctx
+	op.cancel = func() error {
+		cancel()
+		return nil
+	}
+
+	if limit <= 0 || owner.running < limit {
+		owner.running++
+		l.ops[op.ID] = op
+		l.startLocked(op)
+		return op, true, nil
+	}
+
+	if l.cfg.OperationQueueMax > 0 && len(owner.queue) >= l.cfg.OperationQueueMax {
+		logger.Warn("OPERATIONS: Queue full, rejecting operation", map[string]interface{}{
+			"owner_id":  ownerID,
+			"kind":      kind,
+			"server_id": serverID,
+			"queued":    len(owner.queue),
+		})
+		return nil, false, ErrOperationQueueFull
+	}
+
+	op.Status = OperationQueued
+	owner.queue = append(owner.queue, op)
+	l.ops[op.ID] = op
+
+	logger.Info("OPERATIONS: Operation queued (owner at concurrency limit)", map[string]interface{}{
+		"operation_id": op.ID,
+		"owner_id":     ownerID,
+		"kind":         kind,
+		"server_id":    serverID,
+		"limit":        limit,
+		"position":     len(owner.queue),
+	})
+	l.publishLocked(op)
+	return op, false, nil
+}
+
+// execute runs an operation that holds a slot, then releases the slot and starts queued operations
+func (l *OperationLimiter) execute(op *Operation) error {
+	err := op.run(op.ctx)
+
+	l.mu.Lock()
+	l.finishLocked(op, err)
+	l.mu.Unlock()
+
+	l.dispatch(op.OwnerID)
+	return err
+}
+
+// finishLocked records the outcome of a running operation and releases its slot (l.mu must be held)
+// Operations that stopped because of a cancellation request end as cancelled instead of failed.
+func (l *OperationLimiter) finishLocked(op *Operation, err error) {
+	if op.FinishedAt != nil {
+		return
+	}
+
+	now := time.Now()
+	op.FinishedAt = &now
+	switch {
+	case err == nil:
+		op.Status = OperationCompleted
+		op.Progress = 100
+	case errors.Is(err, context.Canceled) || op.Status == OperationCancelling:
+		op.Status = OperationCancelled
+	default:
+		op.Status = OperationFailed
+		op.Error = err.Error()
+	}
+	op.run = nil
+	if op.limited {
+		op.cancel() // Releases the context of the operation
+		l.owners[op.OwnerID].running--
+	}
+	op.cancel = nil
+	l.publishLocked(op)
+}
+
+// dispatch starts queued operations of ownerID while slots are free
+func (l *OperationLimiter) dispatch(ownerID string) {
+	limit := l.Limit(ownerID)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	owner := l.ownerLocked(ownerID)
+	started := false
+	for len(owner.queue) > 0 && (limit <= 0 || owner.running < limit) {
+		next := owner.queue[0]
+		owner.queue = owner.queue[1:]
+		owner.running++
+		l.startLocked(next)
+		started = true
+
+		logger.Info("OPERATIONS: Starting queued operation", map[string]interface{}{
+			"operation_id": next.ID,
+			"owner_id":     ownerID,
+			"kind":         next.Kind,
+			"server_id":    next.ServerID,
+			"waited_s":     int(time.Since(next.QueuedAt).Seconds()),
+		})
+		go l.execute(next)
+	}
+	if started {
+		l.publishQueueLocked(owner)
+	}
+}
+
+// startLocked marks an operation as running (l.mu must be held)
+func (l *OperationLimiter) startLocked(op *Operation) {
+	now := time.Now()
+	op.Status = OperationRunning
+	op.StartedAt = &now
+	l.publishLocked(op)
+}
+
+// ownerLocked returns the slot state of ownerID, creating it if needed (l.mu must be held)
+func (l *OperationLimiter) ownerLocked(ownerID string) *ownerOperations {
+	owner, ok := l.owners[ownerID]
+	if !ok {
+		owner = &ownerOperations{}
+		l.owners[ownerID] = owner
+	}
+	return owner
+}
+
+// prune forgets finished operations after finishedOperationRetention and idle owners (l.mu must be held)
+func (l *OperationLimiter) prune() {
+	cutoff := time.Now().Add(-finishedOperationRetention)
+	for id, op := range l.ops {
+		if op.FinishedAt != nil && op.FinishedAt.Before(cutoff) {
+			delete(l.ops, id)
+		}
+	}
+	for ownerID, owner := range l.owners {
+		if owner.running == 0 && len(owner.queue) == 0 {
+			delete(l.owners, ownerID)
+		}
+	}
+}
+
+// snapshot returns a copy of op with its current queue position
+func (l *OperationLimiter) snapshot(op *Operation) *Operation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.snapshotLocked(op)
+}
+
+// snapshotLocked returns a copy of op with its current queue position (l.mu must be held)
+func (l *OperationLimiter) snapshotLocked(op *Operation) *Operation {
+	copied := *op
+	copied.run = nil
+	copied.ctx = nil
+	copied.cancel = nil
+	copied.Position = 0
+	copied.Cancellable = op.Status == OperationQueued || (op.Status == OperationRunning && op.cancellableWhileRunning())
+	if op.Status == OperationQueued {
+		if owner, ok := l.owners[op.OwnerID]; ok {
+			for i, queued := range owner.queue {
+				if queued == op {
+					copied.Position = i + 1
+					break
+				}
+			}
+		}
+	}
+	return &copied
+}
+
+// publishQueueLocked re-publishes the queued operations of an owner after their positions changed (l.mu must be held)
+func (l *OperationLimiter) publishQueueLocked(owner *ownerOperations) {
+	for _, queued := range owner.queue {
+		l.publishLocked(queued)
+	}
+}
+
+// publishLocked publishes the status of op to the dashboard and the WebSocket hub (l.mu must be held)
+func (l *OperationLimiter) publishLocked(op *Operation) {
+	snapshot := l.snapshotLocked(op)
+	data := map[string]interface{}{
+		"operation_id": snapshot.ID,
+		"owner_id":     snapshot.OwnerID,
+		"kind":         snapshot.Kind,
+		"server_id":    snapshot.ServerID,
+		"resource_id":  snapshot.ResourceID,
+		"status":       snapshot.Status,
+		"position":     snapshot.Position,
+		"phase":        snapshot.Phase,
+		"progress":     snapshot.Progress,
+		"error":        snapshot.Error,
+	}
+	events.PublishOperationStatus(data)
+	if l.wsHub != nil {
+		l.wsHub.Broadcast(events.OperationStatusEventType, data)
+	}
+	l.jobs.Record(snapshot.job())
+}
+
+// job converts an operation snapshot to its persisted record
+func (o *Operation) job() models.Job {
+	return models.Job{
+		ID:          o.ID,
+		OwnerID:     o.OwnerID,
+		RequestedBy: o.RequestedBy,
+		Kind:        string(o.Kind),
+		ServerID:    o.ServerID,
+		ResourceID:  o.ResourceID,
+		Status:      string(o.Status),
+		Phase:       o.Phase,
+		Progress:    o.Progress,
+		Error:       o.Error,
+		Cancellable: o.Cancellable,
+		CreatedAt:   o.QueuedAt,
+		StartedAt:   o.StartedAt,
+		FinishedAt:  o.FinishedAt,
+	}
+}
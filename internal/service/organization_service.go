@@ -0,0 +1,441 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// organizationInvitationTTL is how long an invitation link stays valid
+const organizationInvitationTTL = 7 * 24 * time.Hour
+
+// OrganizationService manages organizations (teams), their members and roles, invitations
+// and the servers shared with them. A server's OwnerID always has the owner role on it;
+// members of the server's organization get their organization role.
+type OrganizationService struct {
+	orgRepo      *repository.OrganizationRepository
+	userRepo     *repository.UserRepository
+	serverRepo   *repository.ServerRepository
+	emailService *EmailService
+}
+
+// NewOrganizationService creates a new organization service
+func NewOrganizationService(orgRepo *repository.OrganizationRepository, userRepo *repository.UserRepository, serverRepo *repository.ServerRepository, emailService *EmailService) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:      orgRepo,
+		userRepo:     userRepo,
+		serverRepo:   serverRepo,
+		emailService: emailService,
+	}
+}
+
+// CreateOrganization creates an organization with userID as its owner
+func (s *OrganizationService) CreateOrganization(userID, name string) (*models.Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return nil, fmt.Errorf("organization name must be between 1 and 100 characters")
+	}
+
+	org := &models.Organization{Name: name, CreatedBy: userID}
+	if err := s.orgRepo.CreateWithOwner(org, userID); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	logger.Info("ORG: Organization created", map[string]interface{}{
+		"org_id":  org.ID,
+		"name":    org.Name,
+		"user_id": userID,
+	})
+	return org, nil
+}
+
+// ListOrganizations returns the organizations userID is a member of
+func (s *OrganizationService) ListOrganizations(userID string) ([]models.Organization, error) {
+	return s.orgRepo.FindByUser(userID)
+}
+
+// GetOrganization returns an organization with its members (any member may read it)
+func (s *OrganizationService) GetOrganization(orgID, userID string) (*models.Organization, error) {
+	if _, err := s.requireRole(orgID, userID, models.OrgRoleViewer); err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.FindByID(orgID)
+	if err != nil {
+		return nil, models.ErrOrgNotFound
+	}
+	if org.Members, err = s.orgRepo.FindMembers(orgID); err != nil {
+		return nil, fmt.Errorf("failed to load members: %w", err)
+	}
+	return org, nil
+}
+
+// RenameOrganization changes the name of an organization (admin)
+func (s *OrganizationService) RenameOrganization(orgID, userID, name string) (*models.Organization, error) {
+	if _, err := s.requireRole(orgID, userID, models.OrgRoleAdmin); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return nil, fmt.Errorf("organization name must be between 1 and 100 characters")
+	}
+
+	org, err := s.orgRepo.FindByID(orgID)
+	if err != nil {
+		return nil, models.ErrOrgNotFound
+	}
+	org.Name = name
+	if err := s.orgRepo.Update(org); err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+	return org, nil
+}
+
+// SetDataResidency restricts the organization's worlds, backups and archives to a country (owner, "" = no restriction)
+// Applies to placement, backups, archives and migrations from now on; running servers and
+// existing backups are not moved.
+func (s *OrganizationService) SetDataResidency(orgID, userID, country string) (*models.Organization, error) {
+	if _, err := s.requireRole(orgID, userID, models.OrgRoleOwner); err != nil {
+		return nil, err
+	}
+	country, err := models.NormalizeResidencyCountry(country)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.FindByID(orgID)
+	if err != nil {
+		return nil, models.ErrOrgNotFound
+	}
+	org.DataResidency = country
+	if err := s.orgRepo.Update(org); err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	logger.Info("ORG: Data residency changed", map[string]interface{}{
+		"org_id":  orgID,
+		"country": country,
+		"user_id": userID,
+	})
+	return org, nil
+}
+
+// DeleteOrganization deletes an organization (owner); its servers go back to owner-only access
+func (s *OrganizationService) DeleteOrganization(orgID, userID string) error {
+	if _, err := s.requireRole(orgID, userID, models.OrgRoleOwner); err != nil {
+		return err
+	}
+	if err := s.orgRepo.Delete(orgID); err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+
+	logger.Info("ORG: Organization deleted", map[string]interface{}{
+		"org_id":  orgID,
+		"user_id": userID,
+	})
+	return nil
+}
+
+// InviteMember invites an email address into an organization (admin; only owners may invite owners)
+// The invitation token is only delivered by email.
+func (s *OrganizationService) InviteMember(orgID, inviterID, email string, role models.OrgRole) (*models.OrganizationInvitation, error) {
+	inviter, err := s.requireRole(orgID, inviterID, models.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if !role.Valid() {
+		return nil, models.ErrOrgInvalidRole
+	}
+	if role == models.OrgRoleOwner && inviter.Role != models.OrgRoleOwner {
+		return nil, models.ErrOrgForbidden
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if user, err := s.userRepo.FindByEmail(email); err == nil {
+		if _, err := s.orgRepo.FindMember(orgID, user.ID); err == nil {
+			return nil, models.ErrOrgAlreadyMember
+		}
+	}
+
+	org, err := s.orgRepo.FindByID(orgID)
+	if err != nil {
+		return nil, models.ErrOrgNotFound
+	}
+
+	token, err := generateAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &models.OrganizationInvitation{
+		OrganizationID: orgID,
+		Email:          email,
+		Role:           role,
+		Token:          token,
+		InvitedBy:      inviterID,
+		ExpiresAt:      time.Now().Add(organizationInvitationTTL),
+	}
+	if err := s.orgRepo.CreateInvitation(invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	inviterName := inviterID
+	if user, err := s.userRepo.FindByID(inviterID); err == nil {
+		inviterName = user.Username
+		if inviterName == "" {
+			inviterName = user.Email
+		}
+	}
+
+	if s.emailService != nil {
+		if err := s.emailService.SendOrganizationInvitation(email, inviterName, org.Name, string(role), token); err != nil {
+			logger.Error("ORG: Failed to send invitation email", err, map[string]interface{}{
+				"org_id": orgID,
+				"email":  email,
+			})
+		}
+	}
+
+	logger.Info("ORG: Member invited", map[string]interface{}{
+		"org_id":     orgID,
+		"email":      email,
+		"role":       role,
+		"invited_by": inviterID,
+	})
+	return invitation, nil
+}
+
+// ListInvitations returns the pending invitations of an organization (admin)
+func (s *OrganizationService) ListInvitations(orgID, userID string) ([]models.OrganizationInvitation, error) {
+	if _, err := s.requireRole(orgID, userID, models.OrgRoleAdmin); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.FindPendingInvitations(orgID)
+}
+
+// RevokeInvitation deletes a pending invitation (admin)
+func (s *OrganizationService) RevokeInvitation(orgID, userID string, invitationID uint) error {
+	if _, err := s.requireRole(orgID, userID, models.OrgRoleAdmin); err != nil {
+		return err
+	}
+	return s.orgRepo.DeleteInvitation(orgID, invitationID)
+}
+
+// AcceptInvitation adds userID to the invitation's organization
+// The invitation must be addressed to the user's email.
+func (s *OrganizationService) AcceptInvitation(token, userID string) (*models.OrganizationMember, error) {
+	invitation, err := s.orgRepo.FindInvitationByToken(token)
+	if err != nil || !invitation.IsPending(time.Now()) {
+		return nil, models.ErrOrgInvalidInvitation
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, models.ErrOrgInvalidInvitation
+	}
+	if _, err := s.orgRepo.FindMember(invitation.OrganizationID, userID); err == nil {
+		return nil, models.ErrOrgAlreadyMember
+	}
+
+	if err := s.orgRepo.AcceptInvitation(invitation, userID); err != nil {
+		if errors.Is(err, models.ErrOrgInvalidInvitation) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	logger.Info("ORG: Invitation accepted", map[string]interface{}{
+		"org_id":  invitation.OrganizationID,
+		"user_id": userID,
+		"role":    invitation.Role,
+	})
+	return s.orgRepo.FindMember(invitation.OrganizationID, userID)
+}
+
+// UpdateMemberRole changes the role of a member (admin; granting or revoking owner requires owner)
+func (s *OrganizationService) UpdateMemberRole(orgID, actorID, memberID string, role models.OrgRole) (*models.OrganizationMember, error) {
+	actor, err := s.requireRole(orgID, actorID, models.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if !role.Valid() {
+		return nil, models.ErrOrgInvalidRole
+	}
+
+	member, err := s.orgRepo.FindMember(orgID, memberID)
+	if err != nil {
+		return nil, models.ErrOrgNotFound
+	}
+	if (role == models.OrgRoleOwner || member.Role == models.OrgRoleOwner) && actor.Role != models.OrgRoleOwner {
+		return nil, models.ErrOrgForbidden
+	}
+	if member.Role == models.OrgRoleOwner && role != models.OrgRoleOwner {
+		if err := s.ensureAnotherOwner(orgID); err != nil {
+			return nil, err
+		}
+	}
+
+	member.Role = role
+	if err := s.orgRepo.UpdateMember(member); err != nil {
+		return nil, fmt.Errorf("failed to update member: %w", err)
+	}
+
+	logger.Info("ORG: Member role changed", map[string]interface{}{
+		"org_id":     orgID,
+		"member_id":  memberID,
+		"role":       role,
+		"changed_by": actorID,
+	})
+	return member, nil
+}
+
+// RemoveMember removes a member (admin; removing an owner requires owner). Members may always leave.
+func (s *OrganizationService) RemoveMember(orgID, actorID, memberID string) error {
+	member, err := s.orgRepo.FindMember(orgID, memberID)
+	if err != nil {
+		return models.ErrOrgNotFound
+	}
+
+	if actorID != memberID {
+		actor, err := s.requireRole(orgID, actorID, models.OrgRoleAdmin)
+		if err != nil {
+			return err
+		}
+		if member.Role == models.OrgRoleOwner && actor.Role != models.OrgRoleOwner {
+			return models.ErrOrgForbidden
+		}
+	}
+	if member.Role == models.OrgRoleOwner {
+		if err := s.ensureAnotherOwner(orgID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.orgRepo.RemoveMember(orgID, memberID); err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+
+	logger.Info("ORG: Member removed", map[string]interface{}{
+		"org_id":     orgID,
+		"member_id":  memberID,
+		"removed_by": actorID,
+	})
+	return nil
+}
+
+// ListServers returns the servers shared with an organization (any member)
+func (s *OrganizationService) ListServers(orgID, userID string) ([]models.MinecraftServer, error) {
+	if _, err := s.requireRole(orgID, userID, models.OrgRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.FindServers(orgID)
+}
+
+// ShareServer shares a server with an organization
+// Only the server owner may share it, and only with an organization they administrate.
+func (s *OrganizationService) ShareServer(orgID, userID, serverID string) error {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil || server.DeletedAt.Valid {
+		return models.ErrServerNotFound
+	}
+	if server.OwnerID != userID {
+		return models.ErrOrgForbidden
+	}
+	if _, err := s.requireRole(orgID, userID, models.OrgRoleAdmin); err != nil {
+		return err
+	}
+
+	if err := s.orgRepo.SetServerOrganization(serverID, orgID); err != nil {
+		return fmt.Errorf("failed to share server: %w", err)
+	}
+
+	logger.Info("ORG: Server shared with organization", map[string]interface{}{
+		"org_id":    orgID,
+		"server_id": serverID,
+		"user_id":   userID,
+	})
+	return nil
+}
+
+// UnshareServer removes a server from an organization (server owner or organization admin)
+func (s *OrganizationService) UnshareServer(orgID, userID, serverID string) error {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil || server.OrganizationID != orgID {
+		return models.ErrServerNotFound
+	}
+	if server.OwnerID != userID {
+		if _, err := s.requireRole(orgID, userID, models.OrgRoleAdmin); err != nil {
+			return err
+		}
+	}
+
+	if err := s.orgRepo.SetServerOrganization(serverID, ""); err != nil {
+		return fmt.Errorf("failed to unshare server: %w", err)
+	}
+
+	logger.Info("ORG: Server removed from organization", map[string]interface{}{
+		"org_id":    orgID,
+		"server_id": serverID,
+		"user_id":   userID,
+	})
+	return nil
+}
+
+// ServerRole returns the role of userID on a server: owner for the server owner, the organization
+// role for members of the server's organization; ok is false without organization access.
+func (s *OrganizationService) ServerRole(userID string, server *models.MinecraftServer) (role models.OrgRole, ok bool) {
+	if server.OwnerID == userID {
+		return models.OrgRoleOwner, true
+	}
+	if server.OrganizationID == "" {
+		return "", false
+	}
+	member, err := s.orgRepo.FindMember(server.OrganizationID, userID)
+	if err != nil {
+		return "", false
+	}
+	return member.Role, true
+}
+
+// requireRole returns the membership of userID if it has at least role min in the organization
+func (s *OrganizationService) requireRole(orgID, userID string, min models.OrgRole) (*models.OrganizationMember, error) {
+	member, err := s.orgRepo.FindMember(orgID, userID)
+	if err != nil {
+		return nil, models.ErrOrgNotFound // Non-members can't tell whether the organization exists
+	}
+	if !member.Role.AtLeast(min) {
+		return nil, models.ErrOrgForbidden
+	}
+	return member, nil
+}
+
+// ensureAnotherOwner fails if the organization has only one owner left
+func (s *OrganizationService) ensureAnotherOwner(orgID string) error {
+	owners, err := s.orgRepo.CountOwners(orgID)
+	if err != nil {
+		return fmt.Errorf("failed to count owners: %w", err)
+	}
+	if owners <= 1 {
+		return models.ErrOrgLastOwner
+	}
+	return nil
+}
+
+// generateAccessToken returns a random 256-bit hex token (invitations, server shares)
+func generateAccessToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/monitoring"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	outboxBatchSize      = 100
+	outboxRetryBaseDelay = 5 * time.Second
+	outboxRetryMaxDelay  = 10 * time.Minute
+	outboxRetention      = 7 * 24 * time.Hour // Processed events are kept this long for debugging
+	outboxPruneInterval  = time.Hour
+)
+
+// OutboxHandler processes an outbox event; returning an error schedules a retry
+// Handlers must be idempotent (keyed by event.ID): an event is redelivered if the
+// process dies between handling it and marking it processed.
+type OutboxHandler func(event *models.OutboxEvent) error
+
+// OutboxWorker delivers transactional outbox events to their handlers (at-least-once)
+// It polls the outbox and is woken early by the matching Event-Bus events, so handling
+// is usually immediate while events written before a crash are picked up on restart.
+type OutboxWorker struct {
+	outboxRepo   *repository.OutboxRepository
+	pollInterval time.Duration
+	handlers     map[string]OutboxHandler
+	wake         chan struct{}
+	lastPrune    time.Time
+	running      bool
+	ctx          context.Context
+	cancel       context.CancelFunc
+	processMutex sync.Mutex // Prevents concurrent delivery runs
+}
+
+// NewOutboxWorker creates a new outbox worker
+func NewOutboxWorker(outboxRepo *repository.OutboxRepository) *OutboxWorker {
+	return &OutboxWorker{
+		outboxRepo:   outboxRepo,
+		pollInterval: 5 * time.Second,
+		handlers:     make(map[string]OutboxHandler),
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Handle registers the handler for an event type (call before Start)
+func (w *OutboxWorker) Handle(eventType events.EventType, handler OutboxHandler) {
+	w.handlers[string(eventType)] = handler
+
+	// The in-memory event is published right after the outbox row is committed
+	events.GetEventBus().Subscribe(eventType, func(events.Event) {
+		w.Notify()
+	})
+}
+
+// Notify wakes the worker to deliver pending events now
+func (w *OutboxWorker) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default: // Already woken
+	}
+}
+
+// Start begins delivering outbox events
+func (w *OutboxWorker) Start() {
+	if w.running {
+		logger.Warn("OUTBOX: Worker already running", nil)
+		return
+	}
+
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.running = true
+
+	if pending, err := w.outboxRepo.CountPending(); err == nil && pending > 0 {
+		logger.Info("OUTBOX: Delivering events pending since last shutdown", map[string]interface{}{
+			"pending": pending,
+		})
+	}
+
+	logger.Info("OUTBOX: Starting outbox worker", map[string]interface{}{
+		"poll_interval": w.pollInterval,
+		"event_types":   len(w.handlers),
+	})
+
+	go func() {
+		ticker := time.NewTicker(w.pollInterval)
+		defer ticker.Stop()
+
+		w.deliverPending()
+		for {
+			select {
+			case <-ticker.C:
+				w.deliverPending()
+			case <-w.wake:
+				w.deliverPending()
+			case <-w.ctx.Done():
+				logger.Info("OUTBOX: Worker stopped", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the outbox worker (undelivered events stay in the outbox)
+func (w *OutboxWorker) Stop() {
+	if !w.running {
+		return
+	}
+
+	logger.Info("OUTBOX: Stopping outbox worker", nil)
+	w.cancel()
+	w.running = false
+}
+
+// deliverPending hands due events to their handlers until the outbox is drained
+func (w *OutboxWorker) deliverPending() {
+	w.processMutex.Lock()
+	defer w.processMutex.Unlock()
+
+	for {
+		due, err := w.outboxRepo.FindDue(outboxBatchSize)
+		if err != nil {
+			logger.Error("OUTBOX: Failed to load pending events", err, nil)
+			return
+		}
+
+		for i := range due {
+			w.deliver(&due[i])
+		}
+
+		if len(due) < outboxBatchSize {
+			break
+		}
+	}
+
+	if pending, err := w.outboxRepo.CountPending(); err == nil {
+		monitoring.OutboxPendingEvents.Set(float64(pending))
+	}
+
+	if time.Since(w.lastPrune) >= outboxPruneInterval {
+		w.lastPrune = time.Now()
+		if deleted, err := w.outboxRepo.DeleteProcessedBefore(time.Now().Add(-outboxRetention)); err != nil {
+			logger.Warn("OUTBOX: Failed to prune processed events", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else if deleted > 0 {
+			logger.Debug("OUTBOX: Pruned processed events", map[string]interface{}{
+				"deleted": deleted,
+			})
+		}
+	}
+}
+
+// deliver runs the handler of one event and records the outcome
+func (w *OutboxWorker) deliver(event *models.OutboxEvent) {
+	handler, ok := w.handlers[event.Type]
+	if ok {
+		if err := handler(event); err != nil {
+			delay := outboxRetryBaseDelay << uint(min(event.Attempts, 10))
+			if delay > outboxRetryMaxDelay {
+				delay = outboxRetryMaxDelay
+			}
+
+			monitoring.OutboxFailuresTotal.WithLabelValues(event.Type).Inc()
+			logger.Error("OUTBOX: Event handling failed, will retry", err, map[string]interface{}{
+				"event_id":   event.ID,
+				"event_type": event.Type,
+				"server_id":  event.ServerID,
+				"attempts":   event.Attempts + 1,
+				"retry_in":   delay.String(),
+			})
+			if err := w.outboxRepo.MarkFailed(event.ID, err.Error(), time.Now().Add(delay)); err != nil {
+				logger.Error("OUTBOX: Failed to record failed attempt", err, map[string]interface{}{
+					"event_id": event.ID,
+				})
+			}
+			return
+		}
+	}
+
+	// Events without a handler (e.g. after a consumer was removed) are marked processed too
+	if err := w.outboxRepo.MarkProcessed(event.ID); err != nil {
+		logger.Error("OUTBOX: Failed to mark event processed (will be redelivered)", err, map[string]interface{}{
+			"event_id": event.ID,
+		})
+	}
+}
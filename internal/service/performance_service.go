@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/monitoring"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/storage"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// performanceDefaultSampleInterval applies if PERFORMANCE_SAMPLE_INTERVAL is not a valid duration
+	performanceDefaultSampleInterval = time.Minute
+	// performanceSampleConcurrency limits the console commands running at the same time
+	performanceSampleConcurrency = 8
+	// performanceSparkPlugin is the plugin slug of the spark profiler
+	performanceSparkPlugin = "spark"
+)
+
+// TickSource is how a server's TPS is read
+type TickSource string
+
+const (
+	TickSourceSpark TickSource = "spark" // "spark tps" (TPS and median MSPT)
+	TickSourceRCON  TickSource = "rcon"  // "tps" and, on Paper/Purpur, "mspt"
+)
+
+// TickMetricsStore stores TPS samples as time series (implemented by storage.InfluxDBClient)
+type TickMetricsStore interface {
+	WriteTickMetrics(points []storage.TickMetricsPoint)
+	QueryTickMetrics(ctx context.Context, serverID string, start time.Time, window time.Duration) ([]storage.TickMetricsPoint, error)
+}
+
+// TickSample is one TPS/MSPT reading of a server
+type TickSample struct {
+	TPS       float64    `json:"tps"`  // Last minute
+	MSPT      float64    `json:"mspt"` // Milliseconds per tick, -1 if the server doesn't report it
+	Source    TickSource `json:"source"`
+	SampledAt time.Time  `json:"sampled_at"`
+}
+
+// PerformanceHistory is the performance graph data of a server: container resources and tick rate
+type PerformanceHistory struct {
+	ContainerMetricsHistory
+	Ticks       []storage.TickMetricsPoint `json:"ticks"`
+	CurrentTick *TickSample                `json:"current_tick"` // Nil until the running server was sampled
+}
+
+// PerformanceService samples TPS and MSPT of running servers through the console (spark if installed,
+// the tps/mspt commands of Paper, Spigot and Purpur otherwise), stores them next to the container metrics
+// and alerts owners once when TPS stays below PERFORMANCE_LOW_TPS.
+type PerformanceService struct {
+	serverRepo       *repository.ServerRepository
+	pluginRepo       *repository.PluginRepository
+	exec             func(serverID, command string) (string, error)
+	containerMetrics *ContainerMetricsService
+	store            TickMetricsStore // Nil if InfluxDB isn't configured
+	webhooks         *WebhookService  // Optional, notifies low TPS
+	cfg              *config.Config
+
+	mu       sync.Mutex
+	latest   map[string]TickSample // key: serverID
+	lowTicks map[string]int        // Lagging samples in a row, key: serverID
+
+	running bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// NewPerformanceService creates a new performance service (store may be nil)
+func NewPerformanceService(serverRepo *repository.ServerRepository, pluginRepo *repository.PluginRepository, console *ConsoleService, containerMetrics *ContainerMetricsService, store TickMetricsStore, cfg *config.Config) *PerformanceService {
+	return &PerformanceService{
+		serverRepo: serverRepo,
+		pluginRepo: pluginRepo,
+		exec: func(serverID, command string) (string, error) {
+			return console.ExecuteCommand(context.Background(), serverID, command)
+		},
+		containerMetrics: containerMetrics,
+		store:            store,
+		cfg:              cfg,
+		latest:           make(map[string]TickSample),
+		lowTicks:         make(map[string]int),
+	}
+}
+
+// SetWebhookService sets the service that notifies server webhooks about low TPS
+func (s *PerformanceService) SetWebhookService(webhooks *WebhookService) {
+	s.webhooks = webhooks
+}
+
+// Start begins sampling running servers every PERFORMANCE_SAMPLE_INTERVAL
+func (s *PerformanceService) Start() {
+	if s.running {
+		logger.Warn("PERFORMANCE: Sampler already running", nil)
+		return
+	}
+
+	interval, err := time.ParseDuration(s.cfg.PerformanceSampleInterval)
+	if err != nil || interval < 10*time.Second {
+		interval = performanceDefaultSampleInterval
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.running = true
+
+	logger.Info("PERFORMANCE: Starting TPS sampler", map[string]interface{}{
+		"sample_interval": interval,
+		"low_tps":         s.cfg.PerformanceLowTPS,
+	})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.SampleAll()
+			case <-s.ctx.Done():
+				logger.Info("PERFORMANCE: Sampler stopped", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the sampler
+func (s *PerformanceService) Stop() {
+	if !s.running {
+		return
+	}
+	s.cancel()
+	s.running = false
+}
+
+// SampleAll samples every running server and stores the readings
+func (s *PerformanceService) SampleAll() {
+	servers, err := s.serverRepo.FindByStatus(string(models.StatusRunning))
+	if err != nil {
+		logger.Warn("PERFORMANCE: Failed to list running servers", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var (
+		wg      sync.WaitGroup
+		pointMu sync.Mutex
+		points  []storage.TickMetricsPoint
+		running = make(map[string]bool, len(servers))
+		limit   = make(chan struct{}, performanceSampleConcurrency)
+	)
+	for i := range servers {
+		server := &servers[i]
+		running[server.ID] = true
+		source := s.sourceFor(server)
+		if source == "" {
+			continue // Vanilla, Forge and Fabric servers report TPS only with spark
+		}
+
+		wg.Add(1)
+		limit <- struct{}{}
+		go func() {
+			defer func() { <-limit; wg.Done() }()
+			sample, ok := s.readSample(server.ID, source)
+			if !ok {
+				return
+			}
+			s.observe(server, sample)
+			pointMu.Lock()
+			points = append(points, storage.TickMetricsPoint{ServerID: server.ID, Time: sample.SampledAt, TPS: sample.TPS, MSPT: sample.MSPT})
+			pointMu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Forget servers that stopped
+	s.mu.Lock()
+	for serverID := range s.latest {
+		if !running[serverID] {
+			delete(s.latest, serverID)
+			delete(s.lowTicks, serverID)
+		}
+	}
+	s.mu.Unlock()
+
+	if s.store != nil && len(points) > 0 {
+		s.store.WriteTickMetrics(points)
+	}
+}
+
+// Current returns the last TPS sample of a running server
+func (s *PerformanceService) Current(serverID string) (*TickSample, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sample, ok := s.latest[serverID]
+	if !ok {
+		return nil, false
+	}
+	return &sample, true
+}
+
+// History returns the performance graph data of a server for a range (1h, 6h, 24h, 7d)
+func (s *PerformanceService) History(ctx context.Context, serverID, rangeName string) (*PerformanceHistory, error) {
+	containers, err := s.containerMetrics.History(ctx, serverID, rangeName)
+	if err != nil {
+		return nil, err
+	}
+	history := &PerformanceHistory{
+		ContainerMetricsHistory: *containers,
+		Ticks:                   []storage.TickMetricsPoint{},
+	}
+	history.CurrentTick, _ = s.Current(serverID)
+
+	if s.store != nil {
+		metricsRange := containerMetricsRanges[rangeName]
+		ticks, err := s.store.QueryTickMetrics(ctx, serverID, time.Now().Add(-metricsRange.span), metricsRange.window)
+		if err != nil {
+			return nil, err
+		}
+		if ticks != nil {
+			history.Ticks = ticks
+		}
+	}
+	return history, nil
+}
+
+// sourceFor returns how the TPS of a server is read, "" if it cannot report it
+func (s *PerformanceService) sourceFor(server *models.MinecraftServer) TickSource {
+	if s.pluginRepo != nil {
+		if installed, err := s.pluginRepo.ListInstalledPlugins(server.ID); err == nil {
+			for _, plugin := range installed {
+				if plugin.Enabled && plugin.Plugin != nil && plugin.Plugin.Slug == performanceSparkPlugin {
+					return TickSourceSpark
+				}
+			}
+		}
+	}
+	switch server.ServerType {
+	case models.ServerTypePaper, models.ServerTypePurpur, models.ServerTypeSpigot:
+		return TickSourceRCON
+	}
+	return ""
+}
+
+// readSample runs the TPS commands of a source on a server (false if it didn't answer with a TPS)
+func (s *PerformanceService) readSample(serverID string, source TickSource) (TickSample, bool) {
+	sample := TickSample{MSPT: -1, Source: source, SampledAt: time.Now()}
+
+	command := "tps"
+	if source == TickSourceSpark {
+		command = "spark tps"
+	}
+	response, err := s.exec(serverID, command)
+	if err != nil {
+		return sample, false
+	}
+	sample.TPS = monitoring.ParseTPS(response)
+	if sample.TPS < 0 {
+		return sample, false
+	}
+
+	if source == TickSourceSpark {
+		sample.MSPT = monitoring.ParseMSPT(response)
+	} else if response, err := s.exec(serverID, "mspt"); err == nil {
+		sample.MSPT = monitoring.ParseMSPT(response) // Paper/Purpur only, Spigot answers "Unknown command"
+	}
+	return sample, true
+}
+
+// observe keeps a sample as the server's current one and alerts the owner once per lagging period
+func (s *PerformanceService) observe(server *models.MinecraftServer, sample TickSample) {
+	s.mu.Lock()
+	s.latest[server.ID] = sample
+	alert := false
+	if s.cfg.PerformanceLowTPS > 0 && sample.TPS < s.cfg.PerformanceLowTPS {
+		s.lowTicks[server.ID]++
+		alert = s.lowTicks[server.ID] == max(s.cfg.PerformanceLowTPSSamples, 1)
+	} else {
+		delete(s.lowTicks, server.ID)
+	}
+	s.mu.Unlock()
+
+	if !alert {
+		return
+	}
+	logger.Warn("PERFORMANCE: Server TPS stayed low", map[string]interface{}{
+		"server_id": server.ID,
+		"tps":       sample.TPS,
+		"mspt":      sample.MSPT,
+		"threshold": s.cfg.PerformanceLowTPS,
+	})
+	events.PublishServerLowTPS(server.ID, server.OwnerID, sample.TPS, sample.MSPT)
+	if s.webhooks != nil {
+		tickRate := fmt.Sprintf("%.1f TPS", sample.TPS)
+		if sample.MSPT >= 0 {
+			tickRate += fmt.Sprintf(" (%.1f ms per tick)", sample.MSPT)
+		}
+		s.webhooks.NotifyLowTPS(server.ID, server.Name, tickRate)
+	}
+}
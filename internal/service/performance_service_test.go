@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+func TestPerformanceReadSample(t *testing.T) {
+	responses := map[string]string{
+		"spark tps": "§7TPS from last 5s, 10s, 1m, 5m, 15m:\n §a*20.0, §a19.5, §a18.2, §a20.0, §a20.0\n\n" +
+			"§7Tick durations (min/med/95%ile/max ms) from last 10s, 1m:\n §a1.2/3.4/8.9/22.0;  §a1.1/4.5/9.0/30.1\n",
+		"tps":  "§6TPS from last 1m, 5m, 15m: §a17.3, §a*20.0, §a20.0",
+		"mspt": "§6Server tick times (avg/min/max) from last 5s, 10s, 1m:\n§6◴ §a2.1/1.0/5.3, §a2.2/1.0/6.0, §a2.5/0.9/9.9",
+	}
+	s := &PerformanceService{exec: func(serverID, command string) (string, error) {
+		if response, ok := responses[command]; ok {
+			return response, nil
+		}
+		return "Unknown command", nil
+	}}
+
+	if sample, ok := s.readSample("server-1", TickSourceSpark); !ok || sample.TPS != 18.2 || sample.MSPT != 4.5 {
+		t.Errorf("spark sample = %+v, %v; want 18.2 TPS, 4.5 MSPT", sample, ok)
+	}
+	if sample, ok := s.readSample("server-1", TickSourceRCON); !ok || sample.TPS != 17.3 || sample.MSPT != 2.5 {
+		t.Errorf("rcon sample = %+v, %v; want 17.3 TPS, 2.5 MSPT", sample, ok)
+	}
+
+	delete(responses, "mspt") // Spigot
+	if sample, ok := s.readSample("server-1", TickSourceRCON); !ok || sample.MSPT != -1 {
+		t.Errorf("spigot sample = %+v, %v; want MSPT -1", sample, ok)
+	}
+	s.exec = func(serverID, command string) (string, error) { return "", errors.New("container stopped") }
+	if _, ok := s.readSample("server-1", TickSourceRCON); ok {
+		t.Error("sample read from a stopped container")
+	}
+}
+
+func TestPerformanceLowTPSAlert(t *testing.T) {
+	s := &PerformanceService{
+		cfg:      &config.Config{PerformanceLowTPS: 15, PerformanceLowTPSSamples: 3},
+		latest:   make(map[string]TickSample),
+		lowTicks: make(map[string]int),
+	}
+	server := &models.MinecraftServer{ID: "server-1"}
+
+	for i, tps := range []float64{12, 11, 19, 12, 10, 9, 8} {
+		s.observe(server, TickSample{TPS: tps})
+		want := map[int]int{0: 1, 1: 2, 2: 0, 3: 1, 4: 2, 5: 3, 6: 4}[i]
+		if s.lowTicks[server.ID] != want {
+			t.Errorf("after sample %d (%v TPS) low count = %d, want %d", i, tps, s.lowTicks[server.ID], want)
+		}
+	}
+	if current, ok := s.Current(server.ID); !ok || current.TPS != 8 {
+		t.Errorf("Current = %+v, %v; want the last sample", current, ok)
+	}
+}
+
+func TestPerformanceSourceFor(t *testing.T) {
+	s := &PerformanceService{}
+	for serverType, want := range map[models.ServerType]TickSource{
+		models.ServerTypePaper:   TickSourceRCON,
+		models.ServerTypePurpur:  TickSourceRCON,
+		models.ServerTypeSpigot:  TickSourceRCON,
+		models.ServerTypeVanilla: "",
+		models.ServerTypeFabric:  "",
+	} {
+		if got := s.sourceFor(&models.MinecraftServer{ServerType: serverType}); got != want {
+			t.Errorf("sourceFor(%s) = %q, want %q", serverType, got, want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// PermissionService answers "may this user do X on this server" for every handler.
+// Access comes from (in order): owning the server, a role in the server's organization,
+// or a per-server share redeemed by the user. Platform admins are handled by the callers.
+type PermissionService struct {
+	serverRepo *repository.ServerRepository
+	shareRepo  *repository.ServerShareRepository
+	orgService *OrganizationService
+}
+
+// NewPermissionService creates a new permission service
+func NewPermissionService(serverRepo *repository.ServerRepository, shareRepo *repository.ServerShareRepository, orgService *OrganizationService) *PermissionService {
+	return &PermissionService{
+		serverRepo: serverRepo,
+		shareRepo:  shareRepo,
+		orgService: orgService,
+	}
+}
+
+// Can returns true if userID has perm on server
+func (s *PermissionService) Can(userID string, server *models.MinecraftServer, perm models.ServerPermission) bool {
+	minRole, known := models.ServerPermissionMinRole[perm]
+	if !known {
+		return false
+	}
+
+	if server.OwnerID == userID {
+		return true
+	}
+	if s.orgService != nil {
+		if role, ok := s.orgService.ServerRole(userID, server); ok && role.AtLeast(minRole) {
+			return true
+		}
+	}
+
+	share, err := s.shareRepo.FindGrant(server.ID, userID)
+	return err == nil && share.Has(perm)
+}
+
+// Permissions returns every permission userID has on server (empty without access)
+func (s *PermissionService) Permissions(userID string, server *models.MinecraftServer) []models.ServerPermission {
+	var perms []models.ServerPermission
+	for _, perm := range models.AllServerPermissions {
+		if s.Can(userID, server, perm) {
+			perms = append(perms, perm)
+		}
+	}
+	return perms
+}
+
+// Authorize loads a server and checks that userID has perm on it
+// Returns models.ErrServerNotFound (also when the user can't even view it) or models.ErrServerForbidden.
+func (s *PermissionService) Authorize(userID, serverID string, perm models.ServerPermission) (*models.MinecraftServer, error) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrServerNotFound
+		}
+		return nil, fmt.Errorf("failed to load server: %w", err)
+	}
+
+	if s.Can(userID, server, perm) {
+		return server, nil
+	}
+	if perm != models.PermServerView && s.Can(userID, server, models.PermServerView) {
+		return nil, models.ErrServerForbidden
+	}
+	return nil, models.ErrServerNotFound // Don't reveal servers the user can't see
+}
+
+// AuthorizeServer checks that userID has perm on serverID (middleware.ServerAccessInterface)
+func (s *PermissionService) AuthorizeServer(userID, serverID string, perm models.ServerPermission) error {
+	_, err := s.Authorize(userID, serverID, perm)
+	return err
+}
+
+// ServerInOrganization returns true if serverID belongs to the organization orgID
+func (s *PermissionService) ServerInOrganization(serverID, orgID string) bool {
+	server, err := s.serverRepo.FindByID(serverID)
+	return err == nil && orgID != "" && server.OrganizationID == orgID
+}
+
+// CreateShare creates a share token granting perms on a server (requires the share permission)
+// ttl bounds how long the token can be redeemed (0 = no deadline). The token is only returned here.
+func (s *PermissionService) CreateShare(userID, serverID string, perms []models.ServerPermission, note string, ttl time.Duration) (*models.ServerShare, string, error) {
+	if _, err := s.Authorize(userID, serverID, models.PermServerShare); err != nil {
+		return nil, "", err
+	}
+	if err := validateSharePermissions(perms); err != nil {
+		return nil, "", err
+	}
+
+	token, err := generateAccessToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	share := &models.ServerShare{
+		ServerID:  serverID,
+		CreatedBy: userID,
+		Token:     token,
+		Note:      note,
+	}
+	share.SetPermissions(perms)
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		share.ExpiresAt = &expiresAt
+	}
+
+	if err := s.shareRepo.Create(share); err != nil {
+		return nil, "", fmt.Errorf("failed to create share: %w", err)
+	}
+
+	logger.Info("PERMISSIONS: Server share created", map[string]interface{}{
+		"server_id":   serverID,
+		"share_id":    share.ID,
+		"permissions": share.Permissions,
+		"created_by":  userID,
+	})
+	return share, token, nil
+}
+
+// ListShares returns the shares of a server (requires the share permission)
+func (s *PermissionService) ListShares(userID, serverID string) ([]models.ServerShare, error) {
+	if _, err := s.Authorize(userID, serverID, models.PermServerShare); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shareRepo.FindByServer(serverID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		shares[i].LoadPermissions()
+	}
+	return shares, nil
+}
+
+// UpdateShare replaces the permissions of a share (requires the share permission)
+func (s *PermissionService) UpdateShare(userID, serverID string, shareID uint, perms []models.ServerPermission) (*models.ServerShare, error) {
+	if _, err := s.Authorize(userID, serverID, models.PermServerShare); err != nil {
+		return nil, err
+	}
+	if err := validateSharePermissions(perms); err != nil {
+		return nil, err
+	}
+
+	share, err := s.shareRepo.FindByID(serverID, shareID)
+	if err != nil {
+		return nil, models.ErrShareNotFound
+	}
+	share.SetPermissions(perms)
+	if err := s.shareRepo.Update(share); err != nil {
+		return nil, fmt.Errorf("failed to update share: %w", err)
+	}
+	return share, nil
+}
+
+// RevokeShare deletes a share; a redeemed share loses its access immediately (requires the share permission)
+func (s *PermissionService) RevokeShare(userID, serverID string, shareID uint) error {
+	if _, err := s.Authorize(userID, serverID, models.PermServerShare); err != nil {
+		return err
+	}
+	if _, err := s.shareRepo.FindByID(serverID, shareID); err != nil {
+		return models.ErrShareNotFound
+	}
+	if err := s.shareRepo.Delete(serverID, shareID); err != nil {
+		return fmt.Errorf("failed to revoke share: %w", err)
+	}
+
+	logger.Info("PERMISSIONS: Server share revoked", map[string]interface{}{
+		"server_id":  serverID,
+		"share_id":   shareID,
+		"revoked_by": userID,
+	})
+	return nil
+}
+
+// RedeemShare grants the share to userID (one user per token)
+func (s *PermissionService) RedeemShare(userID, token string) (*models.ServerShare, error) {
+	share, err := s.shareRepo.FindByToken(token)
+	if err != nil || !share.IsRedeemable(time.Now()) {
+		return nil, models.ErrInvalidShareToken
+	}
+
+	server, err := s.serverRepo.FindByID(share.ServerID)
+	if err != nil || server.DeletedAt.Valid {
+		return nil, models.ErrInvalidShareToken
+	}
+	if server.OwnerID == userID {
+		return nil, models.ErrShareOwnServer
+	}
+	if _, err := s.shareRepo.FindGrant(share.ServerID, userID); err == nil {
+		return nil, models.ErrShareAlreadyGranted
+	}
+
+	if err := s.shareRepo.Redeem(share, userID); err != nil {
+		return nil, err
+	}
+	share.LoadPermissions()
+
+	logger.Info("PERMISSIONS: Server share redeemed", map[string]interface{}{
+		"server_id": share.ServerID,
+		"share_id":  share.ID,
+		"user_id":   userID,
+	})
+	return share, nil
+}
+
+// ListGrants returns the shares granted to userID
+func (s *PermissionService) ListGrants(userID string) ([]models.ServerShare, error) {
+	shares, err := s.shareRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shares {
+		shares[i].LoadPermissions()
+	}
+	return shares, nil
+}
+
+// validateSharePermissions checks that perms is a non-empty set of shareable permissions
+func validateSharePermissions(perms []models.ServerPermission) error {
+	if len(perms) == 0 {
+		return models.ErrInvalidPermission
+	}
+	for _, perm := range perms {
+		if !perm.IsShareable() {
+			return models.ErrInvalidPermission
+		}
+	}
+	return nil
+}
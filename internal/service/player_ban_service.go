@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/velocity"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// banSyncInterval is how often the bans are pushed to the proxy (expiries, proxy restarts,
+	// servers shared with or removed from an organization)
+	banSyncInterval = time.Minute
+	// maxBanReasonLength bounds the reason shown to the banned player
+	maxBanReasonLength = 255
+)
+
+// BanEnforcer enforces bans at the proxy (the Velocity remote API client)
+type BanEnforcer interface {
+	SyncBans(bans []velocity.ProxyBan) (*velocity.BanSyncResponse, error)
+}
+
+// PlayerBanService manages bans that are enforced at the Velocity proxy instead of per server
+// Admins ban players from the whole network, organization admins from all servers of their
+// organization. The database is the source of truth; the proxy gets the full list of active bans
+// after every change and every minute, so expired bans drop out and a restarted proxy catches up.
+type PlayerBanService struct {
+	banRepo    *repository.PlayerBanRepository
+	orgRepo    *repository.OrganizationRepository
+	orgService *OrganizationService
+	resolver   *PlayerUUIDService
+	enforcer   BanEnforcer // Optional, bans are only stored without a proxy
+
+	syncMu   sync.Mutex
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPlayerBanService creates a new player ban service
+func NewPlayerBanService(
+	banRepo *repository.PlayerBanRepository,
+	orgRepo *repository.OrganizationRepository,
+	orgService *OrganizationService,
+	resolver *PlayerUUIDService,
+) *PlayerBanService {
+	return &PlayerBanService{
+		banRepo:    banRepo,
+		orgRepo:    orgRepo,
+		orgService: orgService,
+		resolver:   resolver,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// SetEnforcer sets the proxy the bans are pushed to
+func (s *PlayerBanService) SetEnforcer(enforcer BanEnforcer) {
+	s.enforcer = enforcer
+}
+
+// Start pushes the bans to the proxy now and periodically
+func (s *PlayerBanService) Start() {
+	go func() {
+		s.syncLogged()
+
+		ticker := time.NewTicker(banSyncInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.syncLogged()
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	logger.Info("BANS: Proxy ban sync started", map[string]interface{}{
+		"interval": banSyncInterval.String(),
+	})
+}
+
+// Stop stops the periodic sync
+func (s *PlayerBanService) Stop() {
+	s.stopOnce.Do(func() { close(s.stopChan) })
+}
+
+// Ban bans a player globally (orgID "", the caller must be a platform admin) or from the servers
+// of an organization (the user must be an admin of it)
+func (s *PlayerBanService) Ban(ctx context.Context, userID, orgID, name, reason string, expiresAt *time.Time) (*models.PlayerBan, error) {
+	if orgID != "" {
+		if _, err := s.orgService.requireRole(orgID, userID, models.OrgRoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateBan(reason, expiresAt, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := validatePlayerName(name); err != nil {
+		return nil, err
+	}
+	profile, err := s.resolver.ResolveName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ban := &models.PlayerBan{
+		MinecraftUUID:  profile.UUID,
+		MinecraftName:  profile.Name,
+		OrganizationID: orgID,
+		Reason:         strings.TrimSpace(reason),
+		ExpiresAt:      expiresAt,
+		CreatedBy:      userID,
+	}
+	if err := s.banRepo.Create(ban); err != nil {
+		return nil, fmt.Errorf("failed to save ban: %w", err)
+	}
+
+	logger.Info("BANS: Player banned", map[string]interface{}{
+		"ban_id":          ban.ID,
+		"player":          ban.MinecraftName,
+		"uuid":            ban.MinecraftUUID,
+		"organization_id": orgID,
+		"expires_at":      expiresAt,
+		"created_by":      userID,
+	})
+	go s.syncLogged()
+	return ban, nil
+}
+
+// List returns the bans of an organization (orgID "" = global bans), including lifted ones
+func (s *PlayerBanService) List(userID, orgID string, limit int) ([]models.PlayerBan, error) {
+	if orgID != "" {
+		if _, err := s.orgService.requireRole(orgID, userID, models.OrgRoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.banRepo.FindByOrganization(orgID, limit)
+}
+
+// Revoke lifts a ban of an organization (orgID "" = a global ban)
+func (s *PlayerBanService) Revoke(userID, orgID string, banID uint) (*models.PlayerBan, error) {
+	if orgID != "" {
+		if _, err := s.orgService.requireRole(orgID, userID, models.OrgRoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+	ban, err := s.banRepo.FindByID(banID)
+	if err != nil {
+		return nil, err
+	}
+	if ban == nil || ban.OrganizationID != orgID {
+		return nil, models.ErrPlayerBanNotFound
+	}
+
+	now := time.Now()
+	revoked, err := s.banRepo.Revoke(banID, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, models.ErrPlayerBanRevoked
+	}
+	ban.RevokedAt = &now
+	ban.RevokedBy = userID
+
+	logger.Info("BANS: Ban revoked", map[string]interface{}{
+		"ban_id":     banID,
+		"player":     ban.MinecraftName,
+		"revoked_by": userID,
+	})
+	go s.syncLogged()
+	return ban, nil
+}
+
+// Sync pushes all active bans to the proxy
+func (s *PlayerBanService) Sync() error {
+	if s.enforcer == nil {
+		return nil
+	}
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	bans, err := s.banRepo.FindActive(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to load bans: %w", err)
+	}
+	orgServers := make(map[string][]string)
+	for _, ban := range bans {
+		if ban.Global() {
+			continue
+		}
+		if _, loaded := orgServers[ban.OrganizationID]; loaded {
+			continue
+		}
+		servers, err := s.orgRepo.FindServers(ban.OrganizationID)
+		if err != nil {
+			return fmt.Errorf("failed to load servers of organization %s: %w", ban.OrganizationID, err)
+		}
+		names := make([]string, 0, len(servers))
+		for _, server := range servers {
+			names = append(names, "mc-"+server.ID)
+		}
+		orgServers[ban.OrganizationID] = names
+	}
+
+	response, err := s.enforcer.SyncBans(proxyBans(bans, orgServers))
+	if err != nil {
+		return err
+	}
+	if response.Kicked > 0 {
+		logger.Info("BANS: Banned players disconnected", map[string]interface{}{
+			"kicked": response.Kicked,
+		})
+	}
+	return nil
+}
+
+// syncLogged runs Sync and logs a failure (the next sync retries)
+func (s *PlayerBanService) syncLogged() {
+	if err := s.Sync(); err != nil {
+		logger.Warn("BANS: Failed to sync bans to Velocity", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// validateBan checks the reason and the expiry of a new ban
+func validateBan(reason string, expiresAt *time.Time, now time.Time) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("%w: reason is required", models.ErrPlayerBanInvalid)
+	}
+	if len(reason) > maxBanReasonLength {
+		return fmt.Errorf("%w: reason is longer than %d characters", models.ErrPlayerBanInvalid, maxBanReasonLength)
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return fmt.Errorf("%w: expires_at must be in the future", models.ErrPlayerBanInvalid)
+	}
+	return nil
+}
+
+// proxyBans converts active bans into the proxy's ban list
+// Organization bans become bans from the organization's servers; they are left out if the player is
+// banned globally anyway, or if the organization has no servers.
+func proxyBans(bans []models.PlayerBan, orgServers map[string][]string) []velocity.ProxyBan {
+	global := make(map[string]bool)
+	for _, ban := range bans {
+		if ban.Global() {
+			global[ban.MinecraftUUID] = true
+		}
+	}
+
+	result := make([]velocity.ProxyBan, 0, len(bans))
+	for _, ban := range bans {
+		entry := velocity.ProxyBan{
+			UUID:    ban.MinecraftUUID,
+			Name:    ban.MinecraftName,
+			Reason:  ban.Reason,
+			Servers: []string{},
+		}
+		if ban.ExpiresAt != nil {
+			entry.ExpiresAt = ban.ExpiresAt.Unix()
+		}
+		if !ban.Global() {
+			if global[ban.MinecraftUUID] || len(orgServers[ban.OrganizationID]) == 0 {
+				continue
+			}
+			entry.Servers = orgServers[ban.OrganizationID]
+		}
+		result = append(result, entry)
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestValidateBan(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	if err := validateBan("Charge-back", nil, now); err != nil {
+		t.Errorf("validateBan(permanent) error = %v", err)
+	}
+	if err := validateBan("Griefing", &future, now); err != nil {
+		t.Errorf("validateBan(temporary) error = %v", err)
+	}
+
+	invalid := map[string]struct {
+		reason    string
+		expiresAt *time.Time
+	}{
+		"empty reason":    {"  ", nil},
+		"long reason":     {strings.Repeat("x", maxBanReasonLength+1), nil},
+		"expired already": {"Griefing", &past},
+	}
+	for name, tt := range invalid {
+		if err := validateBan(tt.reason, tt.expiresAt, now); !errors.Is(err, models.ErrPlayerBanInvalid) {
+			t.Errorf("%s: validateBan() error = %v, want ErrPlayerBanInvalid", name, err)
+		}
+	}
+}
+
+func TestProxyBans(t *testing.T) {
+	expiresAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	bans := []models.PlayerBan{
+		{MinecraftUUID: "uuid-a", MinecraftName: "Alex", Reason: "Charge-back"},
+		{MinecraftUUID: "uuid-a", MinecraftName: "Alex", Reason: "Griefing", OrganizationID: "org-1"},
+		{MinecraftUUID: "uuid-b", MinecraftName: "Steve", Reason: "Griefing", OrganizationID: "org-1", ExpiresAt: &expiresAt},
+		{MinecraftUUID: "uuid-c", MinecraftName: "Notch", Reason: "Spam", OrganizationID: "org-empty"},
+	}
+	orgServers := map[string][]string{"org-1": {"mc-1", "mc-2"}}
+
+	got := proxyBans(bans, orgServers)
+	if len(got) != 2 {
+		t.Fatalf("proxyBans() returned %d bans, want 2 (global ban covers the org ban, org without servers skipped): %+v", len(got), got)
+	}
+	if got[0].UUID != "uuid-a" || len(got[0].Servers) != 0 || got[0].ExpiresAt != 0 {
+		t.Errorf("global ban = %+v, want network-wide and permanent", got[0])
+	}
+	if got[1].UUID != "uuid-b" || len(got[1].Servers) != 2 || got[1].ExpiresAt != expiresAt.Unix() {
+		t.Errorf("organization ban = %+v, want servers mc-1, mc-2 and expiry %d", got[1], expiresAt.Unix())
+	}
+}
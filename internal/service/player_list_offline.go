@@ -0,0 +1,368 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// How a player list edit was applied
+const (
+	PlayerListAppliedRCON    = "rcon"    // Server running, applied immediately
+	PlayerListAppliedFile    = "file"    // Server offline, written to the list file
+	PlayerListAppliedPending = "pending" // Queued, applied via RCON on the next start
+)
+
+const (
+	// banDateFormat is the date format of banned-players.json
+	banDateFormat = "2006-01-02 15:04:05 -0700"
+	// pendingSyncAttempts and pendingSyncDelay bound the wait for RCON after a start
+	pendingSyncAttempts = 12
+	pendingSyncDelay    = 10 * time.Second
+)
+
+// Player list errors
+var (
+	// ErrPlayerListInvalid is wrapped by the errors of list files that don't match the Minecraft format
+	ErrPlayerListInvalid    = errors.New("invalid player list")
+	ErrPlayerNameInvalid    = errors.New("invalid player name (3-16 letters, digits or underscores)")
+	ErrPlayerListType       = errors.New("unknown player list (must be whitelist, ops or banned-players)")
+	ErrPlayerListServerLive = errors.New("the server is running, edit the list in-game or with the add/remove endpoints")
+)
+
+// playerNamePattern matches Java names; Bedrock players joining through Floodgate have a "." prefix
+var playerNamePattern = regexp.MustCompile(`^\.?[A-Za-z0-9_]{3,16}$`)
+
+// uuidPattern matches the dashed UUIDs of the list files
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// playerListFiles maps the list files to their list type
+var playerListFiles = map[string]PlayerListType{
+	"whitelist.json":      ListTypeWhitelist,
+	"ops.json":            ListTypeOps,
+	"banned-players.json": ListTypeBanned,
+}
+
+// StartPendingSync applies queued list changes when a server has started
+func (s *PlayerListService) StartPendingSync() {
+	events.GetEventBus().Subscribe(events.EventServerStarted, func(event events.Event) {
+		go s.ApplyPendingChanges(event.ServerID)
+	})
+}
+
+// GetPendingChanges returns the changes of a list waiting for the next start (listType "" = all lists)
+func (s *PlayerListService) GetPendingChanges(serverID string, listType PlayerListType) ([]models.PlayerListChange, error) {
+	return s.listRepo.FindPending(serverID, string(listType))
+}
+
+// ApplyPendingChanges applies the queued changes of a running server via RCON, oldest first
+// Waits for RCON to come up; changes that still fail stay pending with their error.
+func (s *PlayerListService) ApplyPendingChanges(serverID string) {
+	changes, err := s.listRepo.FindPending(serverID, "")
+	if err != nil {
+		logger.Error("PLAYERS: Failed to load pending list changes", err, map[string]interface{}{
+			"server_id": serverID,
+		})
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	applied := 0
+	for _, change := range changes {
+		var err error
+		for attempt := 1; attempt <= pendingSyncAttempts; attempt++ {
+			if err = s.applyViaRCON(serverID, change.Name, PlayerListType(change.ListType), change.Action); err == nil {
+				break
+			}
+			if applied > 0 || attempt == pendingSyncAttempts {
+				break // RCON is up, the command itself failed
+			}
+			time.Sleep(pendingSyncDelay)
+		}
+		if err != nil {
+			s.listRepo.MarkFailed(change.ID, err.Error())
+			logger.Warn("PLAYERS: Pending list change failed, retrying on next start", map[string]interface{}{
+				"server_id": serverID,
+				"list_type": change.ListType,
+				"action":    change.Action,
+				"name":      change.Name,
+				"error":     err.Error(),
+			})
+			continue
+		}
+		s.listRepo.MarkApplied(change.ID, time.Now())
+		applied++
+	}
+
+	logger.Info("PLAYERS: Pending list changes applied", map[string]interface{}{
+		"server_id": serverID,
+		"applied":   applied,
+		"pending":   len(changes) - applied,
+	})
+}
+
+// ReplaceList replaces a whole list file of an offline server after validating it
+func (s *PlayerListService) ReplaceList(serverID string, listType PlayerListType, data []byte) error {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return fmt.Errorf("server not found: %w", err)
+	}
+	if !offlineEditable(server.Status) {
+		return ErrPlayerListServerLive
+	}
+	if err := validatePlayerList(listType, data); err != nil {
+		return err
+	}
+	return s.fileManager.WriteFile(server.ID, string(listType)+".json", string(data))
+}
+
+// applyViaRCON applies one add or remove to a running server
+func (s *PlayerListService) applyViaRCON(serverID, username string, listType PlayerListType, action string) error {
+	if action == models.PlayerListActionRemove {
+		return s.removeViaRCON(serverID, username, listType)
+	}
+	return s.addViaRCON(serverID, username, listType)
+}
+
+// editOffline applies an add or remove to the list file of an offline server
+// Players are stored by UUID under their current name. If the UUID can't be resolved (Mojang down,
+// Bedrock players the server never saw), the add is queued for the next start like all edits of
+// servers without local files (e.g. archived).
+func (s *PlayerListService) editOffline(server *models.MinecraftServer, username string, listType PlayerListType, action, userID string) (string, error) {
+	// A new edit supersedes queued ones of the same player
+	if err := s.listRepo.DeletePending(server.ID, string(listType), username); err != nil {
+		return "", fmt.Errorf("failed to update pending changes: %w", err)
+	}
+
+	if offlineEditable(server.Status) {
+		profile, err := s.resolvePlayer(server.ID, username)
+		if action == models.PlayerListActionRemove {
+			uuid := ""
+			if profile != nil {
+				uuid = profile.UUID
+			}
+			return PlayerListAppliedFile, s.removeFromFileDirectly(server.ID, username, uuid, listType)
+		}
+		if err != nil {
+			return "", err
+		}
+		if profile != nil {
+			return PlayerListAppliedFile, s.addToFileDirectly(server.ID, profile.Name, profile.UUID, listType)
+		}
+	}
+
+	if err := s.queueChange(server.ID, listType, action, username, userID); err != nil {
+		return "", err
+	}
+	return PlayerListAppliedPending, nil
+}
+
+// queueChange records a change to apply on the next start
+func (s *PlayerListService) queueChange(serverID string, listType PlayerListType, action, username, userID string) error {
+	change := &models.PlayerListChange{
+		ServerID:  serverID,
+		ListType:  string(listType),
+		Action:    action,
+		Name:      username,
+		CreatedBy: userID,
+	}
+	if err := s.listRepo.CreateChange(change); err != nil {
+		return fmt.Errorf("failed to queue list change: %w", err)
+	}
+	logger.Info("Player list change queued for next start", map[string]interface{}{
+		"server_id": serverID,
+		"username":  username,
+		"list_type": listType,
+		"action":    action,
+	})
+	return nil
+}
+
+// resolvePlayer returns the UUID and current name of a player
+// Asks the UUID resolver first and falls back to usercache.json (players that joined the server before).
+// Returns nil without error if the player can't be resolved right now and ErrPlayerNotFound for unknown names.
+func (s *PlayerListService) resolvePlayer(serverID, username string) (*PlayerProfile, error) {
+	// Bedrock names (Floodgate "." prefix) are no Mojang accounts
+	if s.resolver != nil && !strings.HasPrefix(username, ".") {
+		profile, err := s.resolver.ResolveName(context.Background(), username)
+		if err == nil || errors.Is(err, ErrPlayerNotFound) {
+			return profile, err
+		}
+	}
+
+	players, err := s.GetHistoricPlayers(serverID)
+	if err != nil {
+		return nil, nil
+	}
+	for _, player := range players {
+		if strings.EqualFold(player.Name, username) && uuidPattern.MatchString(player.UUID) {
+			return &PlayerProfile{UUID: player.UUID, Name: player.Name}, nil
+		}
+	}
+	return nil, nil
+}
+
+// listEntry is an entry of any player list
+type listEntry interface {
+	playerName() string
+	playerUUID() string
+}
+
+func (e PlayerEntry) playerName() string { return e.Name }
+func (e PlayerEntry) playerUUID() string { return e.UUID }
+func (e OpEntry) playerName() string     { return e.Name }
+func (e OpEntry) playerUUID() string     { return e.UUID }
+func (e BannedEntry) playerName() string { return e.Name }
+func (e BannedEntry) playerUUID() string { return e.UUID }
+
+// withoutUnresolved splits the entries without a valid UUID off a list, returning their names
+func withoutUnresolved[T listEntry](list []T) ([]T, []string) {
+	kept := make([]T, 0, len(list))
+	var unresolved []string
+	for _, entry := range list {
+		if uuidPattern.MatchString(entry.playerUUID()) {
+			kept = append(kept, entry)
+		} else if playerNamePattern.MatchString(entry.playerName()) {
+			unresolved = append(unresolved, entry.playerName())
+		}
+	}
+	return kept, unresolved
+}
+
+// findPlayer returns the index of the entry of the player with uuid, -1 if there is none
+// Legacy entries without a UUID match by name.
+func findPlayer[T listEntry](list []T, name, uuid string) int {
+	for i, entry := range list {
+		if strings.EqualFold(entry.playerUUID(), uuid) || (entry.playerUUID() == "" && strings.EqualFold(entry.playerName(), name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// withoutPlayer removes the entries of a player from a list by name or, if not empty, by uuid
+func withoutPlayer[T listEntry](list []T, name, uuid string) []T {
+	kept := make([]T, 0, len(list))
+	for _, entry := range list {
+		if strings.EqualFold(entry.playerName(), name) || (uuid != "" && strings.EqualFold(entry.playerUUID(), uuid)) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	return kept
+}
+
+// offlineEditable reports whether the list files of a server can be edited directly
+// Running servers keep the lists in memory and overwrite the files; archived servers have none.
+func offlineEditable(status models.ServerStatus) bool {
+	return status == models.StatusStopped || status == models.StatusSleeping
+}
+
+// validatePlayerName checks a name before it is used in a list or an RCON command
+func validatePlayerName(name string) error {
+	if !playerNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrPlayerNameInvalid, name)
+	}
+	return nil
+}
+
+// validListType checks the list type of a request
+func validListType(listType PlayerListType) error {
+	switch listType {
+	case ListTypeWhitelist, ListTypeOps, ListTypeBanned:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrPlayerListType, listType)
+	}
+}
+
+// validatePlayerListFile validates the content of a file if it is a player list (see FileManagerService.WriteFile)
+func validatePlayerListFile(filePath, content string) error {
+	listType, ok := playerListFiles[filepath.ToSlash(filepath.Clean(filePath))]
+	if !ok {
+		return nil
+	}
+	return validatePlayerList(listType, []byte(content))
+}
+
+// validatePlayerList checks a list file against the format Minecraft loads: known fields only,
+// valid names and UUIDs without duplicates, op levels 0-4 and parseable ban dates
+func validatePlayerList(listType PlayerListType, data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var entries []struct {
+		UUID                string  `json:"uuid"`
+		Name                string  `json:"name"`
+		Level               *int    `json:"level,omitempty"`
+		BypassesPlayerLimit *bool   `json:"bypassesPlayerLimit,omitempty"`
+		Created             *string `json:"created,omitempty"`
+		Source              *string `json:"source,omitempty"`
+		Expires             *string `json:"expires,omitempty"`
+		Reason              *string `json:"reason,omitempty"`
+	}
+	if err := decoder.Decode(&entries); err != nil {
+		return fmt.Errorf("%w: %v", ErrPlayerListInvalid, err)
+	}
+
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		invalid := func(format string, args ...interface{}) error {
+			return fmt.Errorf("%w: entry %d (%s): %s", ErrPlayerListInvalid, i+1, entry.Name, fmt.Sprintf(format, args...))
+		}
+		if !playerNamePattern.MatchString(entry.Name) {
+			return invalid("invalid name")
+		}
+		if !uuidPattern.MatchString(entry.UUID) {
+			return invalid("invalid uuid %q", entry.UUID)
+		}
+		if key := strings.ToLower(entry.UUID); seen[key] {
+			return invalid("duplicate uuid")
+		} else {
+			seen[key] = true
+		}
+
+		switch listType {
+		case ListTypeWhitelist:
+			if entry.Level != nil || entry.BypassesPlayerLimit != nil || entry.Created != nil || entry.Expires != nil {
+				return invalid("whitelist entries only have uuid and name")
+			}
+		case ListTypeOps:
+			if entry.Level == nil || *entry.Level < 0 || *entry.Level > 4 {
+				return invalid("level must be between 0 and 4")
+			}
+			if entry.Created != nil || entry.Expires != nil {
+				return invalid("op entries have no ban fields")
+			}
+		case ListTypeBanned:
+			if entry.Level != nil || entry.BypassesPlayerLimit != nil {
+				return invalid("ban entries have no op fields")
+			}
+			if entry.Created != nil {
+				if _, err := time.Parse(banDateFormat, *entry.Created); err != nil {
+					return invalid("created must look like 2025-01-31 12:00:00 +0000")
+				}
+			}
+			if entry.Expires != nil && *entry.Expires != "forever" {
+				if _, err := time.Parse(banDateFormat, *entry.Expires); err != nil {
+					return invalid("expires must be \"forever\" or look like 2025-01-31 12:00:00 +0000")
+				}
+			}
+		default:
+			return fmt.Errorf("%w: %q", ErrPlayerListType, listType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatePlayerList(t *testing.T) {
+	tests := []struct {
+		name     string
+		listType PlayerListType
+		data     string
+		valid    bool
+	}{
+		{"empty", ListTypeWhitelist, `[]`, true},
+		{"whitelist", ListTypeWhitelist, `[{"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "Notch"}]`, true},
+		{"missing uuid", ListTypeWhitelist, `[{"uuid": "", "name": "Notch"}]`, false},
+		{"invalid name", ListTypeWhitelist, `[{"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "No tch"}]`, false},
+		{"unknown field", ListTypeWhitelist, `[{"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "Notch", "admin": true}]`, false},
+		{"not an array", ListTypeWhitelist, `{"name": "Notch"}`, false},
+		{"duplicate", ListTypeWhitelist, `[{"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "Notch"}, {"uuid": "069A79F4-44E9-4726-A5BE-FCA90E38AAF5", "name": "Notch"}]`, false},
+		{"op", ListTypeOps, `[{"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "Notch", "level": 4, "bypassesPlayerLimit": false}]`, true},
+		{"op level too high", ListTypeOps, `[{"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "Notch", "level": 5}]`, false},
+		{"op without level", ListTypeOps, `[{"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "Notch"}]`, false},
+		{"ban", ListTypeBanned, `[{"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "Notch", "created": "2025-06-01 12:00:00 +0000", "source": "Server", "expires": "forever", "reason": "Griefing"}]`, true},
+		{"ban with bad date", ListTypeBanned, `[{"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "Notch", "created": "PayPerPlay", "expires": "forever"}]`, false},
+		{"ban with op level", ListTypeBanned, `[{"uuid": "069a79f4-44e9-4726-a5be-fca90e38aaf5", "name": "Notch", "level": 4}]`, false},
+	}
+	for _, tt := range tests {
+		err := validatePlayerList(tt.listType, []byte(tt.data))
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: validatePlayerList() = %v, want valid=%v", tt.name, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrPlayerListInvalid) {
+			t.Errorf("%s: error %v does not wrap ErrPlayerListInvalid", tt.name, err)
+		}
+	}
+}
+
+func TestValidatePlayerListFile(t *testing.T) {
+	if err := validatePlayerListFile("ops.json", `[{"uuid": "x", "name": "Notch", "level": 4}]`); !errors.Is(err, ErrPlayerListInvalid) {
+		t.Errorf("validatePlayerListFile(ops.json) = %v, want ErrPlayerListInvalid", err)
+	}
+	if err := validatePlayerListFile("plugins/ops.json", `not json`); err != nil {
+		t.Errorf("validatePlayerListFile(plugins/ops.json) = %v, want nil (not a server list)", err)
+	}
+}
+
+func TestWithoutUnresolved(t *testing.T) {
+	list := []OpEntry{
+		{UUID: "069a79f4-44e9-4726-a5be-fca90e38aaf5", Name: "Notch", Level: 4},
+		{UUID: "", Name: "jeb_", Level: 4}, // Written by earlier versions
+	}
+	kept, unresolved := withoutUnresolved(list)
+	if len(kept) != 1 || kept[0].Name != "Notch" || len(unresolved) != 1 || unresolved[0] != "jeb_" {
+		t.Errorf("withoutUnresolved() = %v, %v; want Notch kept and jeb_ unresolved", kept, unresolved)
+	}
+}
@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/repository"
@@ -30,9 +32,10 @@ type PlayerEntry struct {
 
 // OpEntry represents an operator with level
 type OpEntry struct {
-	UUID  string `json:"uuid"`
-	Name  string `json:"name"`
-	Level int    `json:"level"`
+	UUID                string `json:"uuid"`
+	Name                string `json:"name"`
+	Level               int    `json:"level"`
+	BypassesPlayerLimit bool   `json:"bypassesPlayerLimit"`
 }
 
 // BannedEntry represents a banned player with metadata
@@ -46,25 +49,38 @@ type BannedEntry struct {
 }
 
 // PlayerListService handles all player list operations (whitelist, ops, banned)
+// Running servers are edited via RCON, offline servers through their list files (see player_list_offline.go).
 type PlayerListService struct {
 	serverRepo     *repository.ServerRepository
+	listRepo       *repository.PlayerListRepository
 	consoleService *ConsoleService
+	fileManager    *FileManagerService
+	resolver       *PlayerUUIDService
 	config         *config.Config
 }
 
 // NewPlayerListService creates a new player list service
 func NewPlayerListService(
 	serverRepo *repository.ServerRepository,
+	listRepo *repository.PlayerListRepository,
 	consoleService *ConsoleService,
+	fileManager *FileManagerService,
 	config *config.Config,
 ) *PlayerListService {
 	return &PlayerListService{
 		serverRepo:     serverRepo,
+		listRepo:       listRepo,
 		consoleService: consoleService,
+		fileManager:    fileManager,
 		config:         config,
 	}
 }
 
+// SetUUIDResolver sets the resolver storing offline list entries by their Mojang UUID
+func (s *PlayerListService) SetUUIDResolver(resolver *PlayerUUIDService) {
+	s.resolver = resolver
+}
+
 // GetList retrieves a player list for a server
 func (s *PlayerListService) GetList(serverID string, listType PlayerListType) (interface{}, error) {
 	server, err := s.serverRepo.FindByID(serverID)
@@ -125,7 +141,7 @@ func (s *PlayerListService) GetOnlinePlayers(serverID string) ([]string, error)
 	}
 
 	// Execute "list" command via RCON
-	output, err := s.consoleService.ExecuteCommand(serverID, "list")
+	output, err := s.consoleService.ExecuteCommand(context.Background(), serverID, "list")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get online players: %w", err)
 	}
@@ -163,40 +179,36 @@ func (s *PlayerListService) GetHistoricPlayers(serverID string) ([]PlayerEntry,
 }
 
 // AddToList adds a player to a specific list
-func (s *PlayerListService) AddToList(serverID, username string, listType PlayerListType) error {
-	server, err := s.serverRepo.FindByID(serverID)
-	if err != nil {
-		return fmt.Errorf("server not found: %w", err)
-	}
+// Returns how the change was applied (PlayerListAppliedRCON, PlayerListAppliedFile or PlayerListAppliedPending).
+func (s *PlayerListService) AddToList(serverID, username string, listType PlayerListType, userID string) (string, error) {
+	return s.editList(serverID, username, listType, models.PlayerListActionAdd, userID)
+}
 
-	// Validate username
-	if username == "" {
-		return fmt.Errorf("username cannot be empty")
-	}
+// RemoveFromList removes a player from a specific list
+func (s *PlayerListService) RemoveFromList(serverID, username string, listType PlayerListType, userID string) (string, error) {
+	return s.editList(serverID, username, listType, models.PlayerListActionRemove, userID)
+}
 
-	// If server is running, use RCON
-	if server.Status == models.StatusRunning {
-		return s.addViaRCON(serverID, username, listType)
+func (s *PlayerListService) editList(serverID, username string, listType PlayerListType, action, userID string) (string, error) {
+	if err := validListType(listType); err != nil {
+		return "", err
+	}
+	if err := validatePlayerName(username); err != nil {
+		return "", err
 	}
 
-	// If server is stopped, edit JSON file directly
-	return s.addToFileDirectly(server.ID, username, listType)
-}
-
-// RemoveFromList removes a player from a specific list
-func (s *PlayerListService) RemoveFromList(serverID, username string, listType PlayerListType) error {
 	server, err := s.serverRepo.FindByID(serverID)
 	if err != nil {
-		return fmt.Errorf("server not found: %w", err)
+		return "", fmt.Errorf("server not found: %w", err)
 	}
 
 	// If server is running, use RCON
 	if server.Status == models.StatusRunning {
-		return s.removeViaRCON(serverID, username, listType)
+		return PlayerListAppliedRCON, s.applyViaRCON(serverID, username, listType, action)
 	}
 
-	// If server is stopped, edit JSON file directly
-	return s.removeFromFileDirectly(server.ID, username, listType)
+	// Otherwise edit the JSON file directly or queue the change for the next start
+	return s.editOffline(server, username, listType, action, userID)
 }
 
 // addViaRCON adds a player using RCON commands (server is running)
@@ -215,7 +227,7 @@ func (s *PlayerListService) addViaRCON(serverID, username string, listType Playe
 	}
 
 	// Execute via Console Service (docker exec with rcon-cli)
-	_, err := s.consoleService.ExecuteCommand(serverID, command)
+	_, err := s.consoleService.ExecuteCommand(context.Background(), serverID, command)
 	if err != nil {
 		return fmt.Errorf("failed to execute command: %w", err)
 	}
@@ -245,7 +257,7 @@ func (s *PlayerListService) removeViaRCON(serverID, username string, listType Pl
 	}
 
 	// Execute via Console Service (docker exec with rcon-cli)
-	_, err := s.consoleService.ExecuteCommand(serverID, command)
+	_, err := s.consoleService.ExecuteCommand(context.Background(), serverID, command)
 	if err != nil {
 		return fmt.Errorf("failed to execute command: %w", err)
 	}
@@ -259,10 +271,9 @@ func (s *PlayerListService) removeViaRCON(serverID, username string, listType Pl
 	return nil
 }
 
-// addToFileDirectly adds a player to JSON file (server is stopped)
-func (s *PlayerListService) addToFileDirectly(serverID, username string, listType PlayerListType) error {
-	filePath := s.getListFilePath(serverID, listType)
-
+// addToFileDirectly adds a player with a known UUID to JSON file (server is stopped)
+// username is the current name of the account; entries of the same UUID under an old name are updated.
+func (s *PlayerListService) addToFileDirectly(serverID, username, uuid string, listType PlayerListType) error {
 	// Read existing list
 	currentList, err := s.GetList(serverID, listType)
 	if err != nil {
@@ -273,50 +284,56 @@ func (s *PlayerListService) addToFileDirectly(serverID, username string, listTyp
 	switch listType {
 	case ListTypeWhitelist:
 		list := currentList.([]PlayerEntry)
-		// Check if already exists
-		for _, entry := range list {
-			if strings.EqualFold(entry.Name, username) {
+		// Check if already exists (by UUID, the player may have been renamed since)
+		if i := findPlayer(list, username, uuid); i >= 0 {
+			if list[i].Name == username {
 				return nil // Already in list
 			}
+			list[i].Name = username
+			return s.writeList(serverID, listType, list)
 		}
 		list = append(list, PlayerEntry{
-			UUID: "",        // Will be resolved by Minecraft
+			UUID: uuid,
 			Name: username,
 		})
-		return s.writeJSONFile(filePath, list)
+		return s.writeList(serverID, listType, list)
 
 	case ListTypeOps:
 		list := currentList.([]OpEntry)
-		// Check if already exists
-		for _, entry := range list {
-			if strings.EqualFold(entry.Name, username) {
+		// Check if already exists (by UUID, the player may have been renamed since)
+		if i := findPlayer(list, username, uuid); i >= 0 {
+			if list[i].Name == username {
 				return nil // Already in list
 			}
+			list[i].Name = username
+			return s.writeList(serverID, listType, list)
 		}
 		list = append(list, OpEntry{
-			UUID:  "",        // Will be resolved by Minecraft
+			UUID:  uuid,
 			Name:  username,
-			Level: 4,         // Full op permissions
+			Level: 4, // Full op permissions
 		})
-		return s.writeJSONFile(filePath, list)
+		return s.writeList(serverID, listType, list)
 
 	case ListTypeBanned:
 		list := currentList.([]BannedEntry)
-		// Check if already exists
-		for _, entry := range list {
-			if strings.EqualFold(entry.Name, username) {
+		// Check if already exists (by UUID, the player may have been renamed since)
+		if i := findPlayer(list, username, uuid); i >= 0 {
+			if list[i].Name == username {
 				return nil // Already banned
 			}
+			list[i].Name = username
+			return s.writeList(serverID, listType, list)
 		}
 		list = append(list, BannedEntry{
-			UUID:    "",
+			UUID:    uuid,
 			Name:    username,
-			Created: "PayPerPlay",
+			Created: time.Now().Format(banDateFormat),
 			Source:  "PayPerPlay",
 			Expires: "forever",
 			Reason:  "Banned via PayPerPlay",
 		})
-		return s.writeJSONFile(filePath, list)
+		return s.writeList(serverID, listType, list)
 
 	default:
 		return fmt.Errorf("unknown list type: %s", listType)
@@ -324,9 +341,8 @@ func (s *PlayerListService) addToFileDirectly(serverID, username string, listTyp
 }
 
 // removeFromFileDirectly removes a player from JSON file (server is stopped)
-func (s *PlayerListService) removeFromFileDirectly(serverID, username string, listType PlayerListType) error {
-	filePath := s.getListFilePath(serverID, listType)
-
+// Entries match by name or, if known, by UUID (a renamed player is listed under the old name).
+func (s *PlayerListService) removeFromFileDirectly(serverID, username, uuid string, listType PlayerListType) error {
 	// Read existing list
 	currentList, err := s.GetList(serverID, listType)
 	if err != nil {
@@ -336,34 +352,13 @@ func (s *PlayerListService) removeFromFileDirectly(serverID, username string, li
 	// Remove player based on type
 	switch listType {
 	case ListTypeWhitelist:
-		list := currentList.([]PlayerEntry)
-		newList := []PlayerEntry{}
-		for _, entry := range list {
-			if !strings.EqualFold(entry.Name, username) {
-				newList = append(newList, entry)
-			}
-		}
-		return s.writeJSONFile(filePath, newList)
+		return s.writeList(serverID, listType, withoutPlayer(currentList.([]PlayerEntry), username, uuid))
 
 	case ListTypeOps:
-		list := currentList.([]OpEntry)
-		newList := []OpEntry{}
-		for _, entry := range list {
-			if !strings.EqualFold(entry.Name, username) {
-				newList = append(newList, entry)
-			}
-		}
-		return s.writeJSONFile(filePath, newList)
+		return s.writeList(serverID, listType, withoutPlayer(currentList.([]OpEntry), username, uuid))
 
 	case ListTypeBanned:
-		list := currentList.([]BannedEntry)
-		newList := []BannedEntry{}
-		for _, entry := range list {
-			if !strings.EqualFold(entry.Name, username) {
-				newList = append(newList, entry)
-			}
-		}
-		return s.writeJSONFile(filePath, newList)
+		return s.writeList(serverID, listType, withoutPlayer(currentList.([]BannedEntry), username, uuid))
 
 	default:
 		return fmt.Errorf("unknown list type: %s", listType)
@@ -375,14 +370,31 @@ func (s *PlayerListService) getListFilePath(serverID string, listType PlayerList
 	return filepath.Join(s.config.ServersBasePath, serverID, string(listType)+".json")
 }
 
-// writeJSONFile writes data to a JSON file
-func (s *PlayerListService) writeJSONFile(filePath string, data interface{}) error {
+// writeList writes a list file through the file manager (path checks, validation, backup of the previous version)
+// Entries without a UUID, as written by earlier versions, are never loaded by Minecraft; they are moved
+// to the queue of the next start so the file passes validation.
+func (s *PlayerListService) writeList(serverID string, listType PlayerListType, data interface{}) error {
+	var unresolved []string
+	switch list := data.(type) {
+	case []PlayerEntry:
+		data, unresolved = withoutUnresolved(list)
+	case []OpEntry:
+		data, unresolved = withoutUnresolved(list)
+	case []BannedEntry:
+		data, unresolved = withoutUnresolved(list)
+	}
+	for _, name := range unresolved {
+		if err := s.queueChange(serverID, listType, models.PlayerListActionAdd, name, ""); err != nil {
+			return err
+		}
+	}
+
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+	if err := s.fileManager.WriteFile(serverID, string(listType)+".json", string(jsonData)); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/cache"
+	"github.com/payperplay/hosting/internal/external"
+	"github.com/payperplay/hosting/internal/ratelimit"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// mojangRateLimitKey is the shared counter of all Mojang lookups
+	mojangRateLimitKey = "mojang:lookups"
+	// playerProfileMissTTL is how long unknown names are remembered (the name may be registered later)
+	playerProfileMissTTL = 10 * time.Minute
+	// playerLookupTimeout bounds a single lookup
+	playerLookupTimeout = 5 * time.Second
+)
+
+// Player UUID resolution errors
+var (
+	ErrPlayerNotFound = errors.New("no Minecraft account with this name")
+	// ErrPlayerLookupUnavailable is wrapped when the Mojang API can't be asked right now (rate limit, outage)
+	ErrPlayerLookupUnavailable = errors.New("player lookup unavailable, try again later")
+)
+
+// PlayerProfile is the UUID and current name of a Minecraft account
+type PlayerProfile = external.MojangProfile
+
+// PlayerUUIDService resolves player names to UUIDs (and back) through the Mojang API
+// Results are cached, unknown names briefly too, and lookups share one rate limit across instances.
+type PlayerUUIDService struct {
+	client   *external.MojangClient
+	profiles *cache.Cache // playerNameKey and playerUUIDKey -> PlayerProfile
+	misses   *cache.Cache // playerMissKey -> true
+	limiter  ratelimit.Store
+	limit    ratelimit.Limit
+}
+
+// NewPlayerUUIDService creates a resolver caching in store for ttl, allowing perMinute lookups
+func NewPlayerUUIDService(client *external.MojangClient, store cache.Store, ttl time.Duration, limiter ratelimit.Store, perMinute int) *PlayerUUIDService {
+	return &PlayerUUIDService{
+		client:   client,
+		profiles: cache.New("player_profiles", store, ttl),
+		misses:   cache.New("player_profile_misses", store, playerProfileMissTTL),
+		limiter:  limiter,
+		limit:    ratelimit.Limit{PerMinute: perMinute, Burst: max(perMinute/6, 1)},
+	}
+}
+
+// ResolveName returns the account currently using name
+// Returns ErrPlayerNotFound for unknown names and wraps ErrPlayerLookupUnavailable if Mojang can't be asked.
+func (s *PlayerUUIDService) ResolveName(ctx context.Context, name string) (*PlayerProfile, error) {
+	var profile PlayerProfile
+	if s.profiles.Get(playerNameKey(name), &profile) {
+		return &profile, nil
+	}
+	var missing bool
+	if s.misses.Get(playerMissKey(name), &missing) {
+		return nil, fmt.Errorf("%w: %s", ErrPlayerNotFound, name)
+	}
+
+	if err := s.take(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, playerLookupTimeout)
+	defer cancel()
+	result, err := s.client.ProfileByName(ctx, name)
+	if errors.Is(err, external.ErrMojangProfileNotFound) {
+		s.misses.Set(playerMissKey(name), true)
+		return nil, fmt.Errorf("%w: %s", ErrPlayerNotFound, name)
+	}
+	if err != nil {
+		return nil, s.unavailable(err)
+	}
+
+	s.store(result)
+	return result, nil
+}
+
+// ResolveUUID returns the current name of the account with uuid (follows renames)
+func (s *PlayerUUIDService) ResolveUUID(ctx context.Context, uuid string) (*PlayerProfile, error) {
+	dashed, err := external.DashUUID(uuid)
+	if err != nil {
+		return nil, err
+	}
+	var profile PlayerProfile
+	if s.profiles.Get(playerUUIDKey(dashed), &profile) {
+		return &profile, nil
+	}
+
+	if err := s.take(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, playerLookupTimeout)
+	defer cancel()
+	result, err := s.client.ProfileByUUID(ctx, dashed)
+	if errors.Is(err, external.ErrMojangProfileNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrPlayerNotFound, dashed)
+	}
+	if err != nil {
+		return nil, s.unavailable(err)
+	}
+
+	s.store(result)
+	return result, nil
+}
+
+// store caches a profile under its name and UUID; a renamed account drops the old name's entry
+func (s *PlayerUUIDService) store(profile *PlayerProfile) {
+	var previous PlayerProfile
+	if s.profiles.Get(playerUUIDKey(profile.UUID), &previous) && !strings.EqualFold(previous.Name, profile.Name) {
+		s.profiles.Delete(playerNameKey(previous.Name))
+	}
+	s.profiles.Set(playerNameKey(profile.Name), profile)
+	s.profiles.Set(playerUUIDKey(profile.UUID), profile)
+	s.misses.Delete(playerMissKey(profile.Name))
+}
+
+// take counts a lookup against the shared Mojang limit
+func (s *PlayerUUIDService) take() error {
+	result, err := s.limiter.Take(mojangRateLimitKey, s.limit, time.Now())
+	if err != nil {
+		return s.unavailable(err)
+	}
+	if !result.Allowed {
+		return fmt.Errorf("%w (retry in %ds)", ErrPlayerLookupUnavailable, result.RetryAfterSeconds())
+	}
+	return nil
+}
+
+func (s *PlayerUUIDService) unavailable(err error) error {
+	logger.Warn("PLAYERS: Mojang lookup failed", map[string]interface{}{
+		"error": err.Error(),
+	})
+	return fmt.Errorf("%w: %v", ErrPlayerLookupUnavailable, err)
+}
+
+// The caches may share a store with other caches, keys are prefixed accordingly
+func playerNameKey(name string) string { return "player:name:" + strings.ToLower(name) }
+func playerUUIDKey(uuid string) string { return "player:uuid:" + uuid }
+func playerMissKey(name string) string { return "player:missing:" + strings.ToLower(name) }
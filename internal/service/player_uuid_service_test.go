@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/cache"
+	"github.com/payperplay/hosting/internal/ratelimit"
+)
+
+func TestPlayerUUIDServiceCache(t *testing.T) {
+	// No client: every lookup in this test must be answered from the cache
+	s := NewPlayerUUIDService(nil, cache.NewMemoryStore(), time.Hour, ratelimit.NewMemoryStore(), 60)
+	s.store(&PlayerProfile{UUID: "069a79f4-44e9-4726-a5be-fca90e38aaf5", Name: "Notch"})
+
+	profile, err := s.ResolveName(context.Background(), "notch")
+	if err != nil || profile.UUID != "069a79f4-44e9-4726-a5be-fca90e38aaf5" || profile.Name != "Notch" {
+		t.Fatalf("ResolveName(notch) = %+v, %v; want the cached profile", profile, err)
+	}
+	if profile, err := s.ResolveUUID(context.Background(), "069a79f444e94726a5befca90e38aaf5"); err != nil || profile.Name != "Notch" {
+		t.Errorf("ResolveUUID(undashed) = %+v, %v; want the cached profile", profile, err)
+	}
+
+	// A rename replaces the old name
+	s.store(&PlayerProfile{UUID: "069a79f4-44e9-4726-a5be-fca90e38aaf5", Name: "Notch2"})
+	var stale PlayerProfile
+	if s.profiles.Get(playerNameKey("notch"), &stale) {
+		t.Errorf("old name still cached as %+v", stale)
+	}
+
+	s.misses.Set(playerMissKey("nobody"), true)
+	if _, err := s.ResolveName(context.Background(), "Nobody"); !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("ResolveName(Nobody) error = %v, want ErrPlayerNotFound", err)
+	}
+}
+
+func TestPlayerUUIDServiceRateLimit(t *testing.T) {
+	s := NewPlayerUUIDService(nil, cache.NewMemoryStore(), time.Hour, ratelimit.NewMemoryStore(), 6)
+	if err := s.take(); err != nil {
+		t.Fatalf("first lookup limited: %v", err)
+	}
+	if err := s.take(); !errors.Is(err, ErrPlayerLookupUnavailable) {
+		t.Errorf("lookup beyond the burst error = %v, want ErrPlayerLookupUnavailable", err)
+	}
+}
+
+func TestFindPlayer(t *testing.T) {
+	list := []PlayerEntry{
+		{UUID: "069a79f4-44e9-4726-a5be-fca90e38aaf5", Name: "OldName"},
+		{UUID: "", Name: "Legacy"},
+	}
+	tests := []struct {
+		name, uuid string
+		want       int
+	}{
+		{"NewName", "069a79f4-44e9-4726-a5be-fca90e38aaf5", 0},  // Renamed
+		{"oldname", "853c80ef-3c37-49fd-aa49-938b674adae6", -1}, // Name taken over by another account
+		{"legacy", "61699b2e-d327-4a01-9f1e-0ea8c3f06bc6", 1},
+	}
+	for _, tt := range tests {
+		if got := findPlayer(list, tt.name, tt.uuid); got != tt.want {
+			t.Errorf("findPlayer(%s, %s) = %d, want %d", tt.name, tt.uuid, got, tt.want)
+		}
+	}
+
+	if kept := withoutPlayer(list, "NewName", "069a79f4-44e9-4726-a5be-fca90e38aaf5"); len(kept) != 1 || kept[0].Name != "Legacy" {
+		t.Errorf("withoutPlayer by uuid = %+v, want only Legacy", kept)
+	}
+	if kept := withoutPlayer(list, "oldname", ""); len(kept) != 1 || kept[0].Name != "Legacy" {
+		t.Errorf("withoutPlayer by name = %+v, want only Legacy", kept)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -10,13 +11,20 @@ import (
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
+// recentPlayerTTL is how long players are remembered after they were last seen on a server
+const recentPlayerTTL = 30 * time.Minute
+
 // PlayerCountService tracks player counts via Velocity and triggers auto-shutdown
+// It also remembers which players were recently on each server (e.g. to notify them after a crash).
 type PlayerCountService struct {
 	velocityClient *velocity.RemoteVelocityClient
 	serverRepo     *repository.ServerRepository
 	checkInterval  time.Duration
 	stopChan       chan struct{}
 	wg             sync.WaitGroup
+
+	lastSeenMu sync.Mutex
+	lastSeen   map[string]map[string]time.Time // server ID -> player name -> last seen
 }
 
 // NewPlayerCountService creates a new player count tracking service
@@ -29,6 +37,7 @@ func NewPlayerCountService(
 		serverRepo:     serverRepo,
 		checkInterval:  15 * time.Second, // Check every 15 seconds
 		stopChan:       make(chan struct{}),
+		lastSeen:       make(map[string]map[string]time.Time),
 	}
 }
 
@@ -92,8 +101,10 @@ func (s *PlayerCountService) updatePlayerCounts() {
 
 	// Build map of server name -> player count
 	playerCounts := make(map[string]int)
+	playerNames := make(map[string][]string)
 	for _, vs := range velocityServers {
 		playerCounts[vs.Name] = vs.Players
+		playerNames[vs.Name] = vs.PlayerNames
 	}
 
 	// Update each server's player count
@@ -108,6 +119,7 @@ func (s *PlayerCountService) updatePlayerCounts() {
 			// Server not registered with Velocity
 			continue
 		}
+		s.recordPlayers(server.ID, playerNames[velocityServerName], now)
 
 		// Check if player count changed
 		if playerCount != server.CurrentPlayerCount {
@@ -164,4 +176,59 @@ func (s *PlayerCountService) updatePlayerCounts() {
 			"updated":       updated,
 		})
 	}
+
+	s.pruneLastSeen(now)
+}
+
+// RecentPlayers returns the players seen on a server within the given window (sorted by name)
+func (s *PlayerCountService) RecentPlayers(serverID string, within time.Duration) []string {
+	s.lastSeenMu.Lock()
+	defer s.lastSeenMu.Unlock()
+
+	cutoff := time.Now().Add(-within)
+	var players []string
+	for name, seen := range s.lastSeen[serverID] {
+		if seen.After(cutoff) {
+			players = append(players, name)
+		}
+	}
+	sort.Strings(players)
+	return players
+}
+
+// recordPlayers marks players as seen on a server
+func (s *PlayerCountService) recordPlayers(serverID string, names []string, now time.Time) {
+	if len(names) == 0 {
+		return
+	}
+
+	s.lastSeenMu.Lock()
+	defer s.lastSeenMu.Unlock()
+
+	seen, ok := s.lastSeen[serverID]
+	if !ok {
+		seen = make(map[string]time.Time)
+		s.lastSeen[serverID] = seen
+	}
+	for _, name := range names {
+		seen[name] = now
+	}
+}
+
+// pruneLastSeen forgets players not seen for recentPlayerTTL
+func (s *PlayerCountService) pruneLastSeen(now time.Time) {
+	s.lastSeenMu.Lock()
+	defer s.lastSeenMu.Unlock()
+
+	cutoff := now.Add(-recentPlayerTTL)
+	for serverID, seen := range s.lastSeen {
+		for name, at := range seen {
+			if at.Before(cutoff) {
+				delete(seen, name)
+			}
+		}
+		if len(seen) == 0 {
+			delete(s.lastSeen, serverID)
+		}
+	}
 }
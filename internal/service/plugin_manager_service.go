@@ -218,34 +218,82 @@ type UpdateInfo struct {
 }
 
 // AutoUpdatePlugins automatically updates all plugins with AutoUpdate enabled
+// The target version depends on the server's release channel (see autoUpdateVersion).
 func (s *PluginManagerService) AutoUpdatePlugins(serverID string) error {
-	updates, err := s.CheckForUpdates(serverID)
+	server, err := s.serverRepo.FindByID(serverID)
 	if err != nil {
-		return err
+		return fmt.Errorf("server not found: %w", err)
+	}
+
+	installed, err := s.pluginRepo.ListInstalledPlugins(serverID)
+	if err != nil {
+		return fmt.Errorf("failed to list installed plugins: %w", err)
 	}
 
 	updatedCount := 0
-	for _, update := range updates {
-		if update.AutoUpdate {
-			if err := s.UpdatePlugin(serverID, update.PluginID, update.LatestVersionID); err != nil {
-				logger.Error("Auto-update failed", err, map[string]interface{}{
-					"server_id": serverID,
-					"plugin":    update.PluginName,
-				})
-				continue
+	heldBack := 0
+	for _, inst := range installed {
+		if !inst.AutoUpdate || inst.Plugin == nil {
+			continue
+		}
+
+		target, err := s.autoUpdateVersion(inst.PluginID, server)
+		if err != nil || target.ID == inst.VersionID {
+			if latest, latestErr := s.findBestVersion(inst.PluginID, server.MinecraftVersion, string(server.ServerType)); latestErr == nil && latest.ID != inst.VersionID {
+				heldBack++ // Newer version exists but hasn't reached this channel yet
 			}
-			updatedCount++
+			continue
 		}
+		// Never downgrade a plugin the owner updated manually beyond what the channel offers
+		if inst.Version != nil && !target.ReleaseDate.After(inst.Version.ReleaseDate) {
+			continue
+		}
+
+		if err := s.UpdatePlugin(serverID, inst.PluginID, target.ID); err != nil {
+			logger.Error("Auto-update failed", err, map[string]interface{}{
+				"server_id": serverID,
+				"plugin":    inst.Plugin.Name,
+			})
+			continue
+		}
+		updatedCount++
 	}
 
 	logger.Info("Auto-update completed", map[string]interface{}{
-		"server_id": serverID,
-		"updated":   updatedCount,
+		"server_id":       serverID,
+		"release_channel": server.Channel(),
+		"updated":         updatedCount,
+		"held_back":       heldBack,
 	})
 
 	return nil
 }
 
+// autoUpdateVersion picks the version auto-update may install on the server's release channel
+// Beta takes the newest compatible version, pre-releases included; stable only takes stable
+// versions that have been out for PluginUpdateSoakDays.
+func (s *PluginManagerService) autoUpdateVersion(pluginID string, server *models.MinecraftServer) (*models.PluginVersion, error) {
+	versions, err := s.pluginRepo.FindVersionsByPluginID(pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	soakedBefore := time.Now().AddDate(0, 0, -s.cfg.PluginUpdateSoakDays)
+	for i := range versions { // Newest first
+		version := &versions[i]
+		if !s.isVersionCompatible(version, server.MinecraftVersion, string(server.ServerType)) {
+			continue
+		}
+		if server.Channel() == models.ReleaseChannelBeta {
+			return version, nil
+		}
+		if version.IsStable && !version.ReleaseDate.After(soakedBefore) {
+			return version, nil
+		}
+	}
+	return nil, fmt.Errorf("no version of %s available on the %s channel", pluginID, server.Channel())
+}
+
 // === Removal ===
 
 // UninstallPlugin removes a plugin from a server
@@ -0,0 +1,763 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/monitoring"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	pregenChunkyPollEvery  = 10 * time.Second // How often Chunky's progress and the TPS are checked
+	pregenConsoleAreaEvery = 5 * time.Second  // How long the console method keeps an area force-loaded
+	pregenDefaultMinTPS    = 18.0
+	pregenResumeTPSMargin  = 1.0 // A throttled job resumes once TPS is this far above min_tps again
+	pregenAreaChunks       = 16  // Console method: areas of 16x16 chunks (forceload allows at most 256 at once)
+	pregenChunkyPlugin     = "chunky"
+)
+
+// PregenShape is the shape of the pre-generated area around its center
+type PregenShape string
+
+const (
+	PregenShapeSquare PregenShape = "square"
+	PregenShapeCircle PregenShape = "circle"
+)
+
+// PregenMethod is how chunks are generated
+type PregenMethod string
+
+const (
+	PregenMethodChunky  PregenMethod = "chunky"  // The Chunky plugin is installed on the server
+	PregenMethodConsole PregenMethod = "console" // Fallback: force-loading the area piece by piece
+)
+
+// PregenStatus is the lifecycle state of a pre-generation job
+type PregenStatus string
+
+const (
+	PregenRunning    PregenStatus = "running"
+	PregenThrottled  PregenStatus = "throttled" // Paused automatically while TPS is below min_tps
+	PregenPaused     PregenStatus = "paused"    // Paused by the user
+	PregenCancelling PregenStatus = "cancelling"
+	PregenCompleted  PregenStatus = "completed"
+	PregenFailed     PregenStatus = "failed"
+	PregenCancelled  PregenStatus = "cancelled"
+)
+
+// Pre-generation errors
+var (
+	ErrPregenNotFound         = errors.New("pre-generation job not found")
+	ErrPregenAlreadyRunning   = errors.New("a pre-generation job is already running on this server")
+	ErrPregenServerNotRunning = errors.New("the server must be running to pre-generate chunks")
+	ErrPregenInvalid          = errors.New("invalid pre-generation request")
+	ErrPregenNotRunning       = errors.New("pre-generation job is not running")
+	ErrPregenNotPaused        = errors.New("pre-generation job is not paused")
+	ErrPregenFinished         = errors.New("pre-generation job has already finished")
+)
+
+// chunkyWorlds are the world names Chunky knows the dimensions by
+var chunkyWorlds = map[string]string{
+	DimensionOverworld: "world",
+	DimensionNether:    "world_nether",
+	DimensionEnd:       "world_the_end",
+}
+
+// consoleDimensions are the dimension IDs used with /execute in
+var consoleDimensions = map[string]string{
+	DimensionOverworld: "minecraft:overworld",
+	DimensionNether:    "minecraft:the_nether",
+	DimensionEnd:       "minecraft:the_end",
+}
+
+// PregenRequest describes the area to pre-generate
+type PregenRequest struct {
+	Dimension string      `json:"dimension"`                                  // overworld (default), nether, end
+	Radius    int         `json:"radius" binding:"required,min=16,max=50000"` // Blocks from the center
+	Shape     PregenShape `json:"shape"`                                      // square (default) or circle
+	CenterX   int         `json:"center_x"`
+	CenterZ   int         `json:"center_z"`
+	MinTPS    float64     `json:"min_tps"` // Pause while TPS is below; 0 = 18, negative = never throttle
+}
+
+// PregenJob is a chunk pre-generation running on a server
+type PregenJob struct {
+	ID          string `json:"id"`
+	ServerID    string `json:"server_id"`
+	OwnerID     string `json:"owner_id"`
+	RequestedBy string `json:"requested_by"`
+	PregenRequest
+	Method     PregenMethod `json:"method"`
+	Status     PregenStatus `json:"status"`
+	Progress   float64      `json:"progress"`      // Percent of the area generated
+	TPS        float64      `json:"tps,omitempty"` // Last measured TPS (Paper/Spigot only)
+	Error      string       `json:"error,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+
+	userPaused bool          // Desired state set by Pause/Resume, applied by the worker
+	cancel     bool          // Cancel requested, applied by the worker
+	wake       chan struct{} // Wakes the worker after Pause, Resume and Cancel
+}
+
+// pregenerator drives one pre-generation method
+type pregenerator interface {
+	start() error
+	pause() error
+	resume() error
+	cancel() error
+	// step advances the generation (console) or reads its progress (Chunky)
+	step() (progress float64, done bool, err error)
+	interval() time.Duration
+}
+
+// PregenerationService pre-generates the chunks of an area so players don't wait for (and lag on)
+// world generation, e.g. before a launch event. With the Chunky plugin installed, Chunky does the work
+// and is paused and continued through console commands; otherwise the area is force-loaded piece by
+// piece. While the server's TPS is below min_tps the job pauses itself ("throttled") and continues once
+// the server has recovered. One job runs per server; jobs are kept in memory, forgotten an hour after
+// they finish and persisted in the job history (kind "pregeneration"). A completed job publishes
+// world.pregenerated and notifies the server's webhook.
+type PregenerationService struct {
+	serverRepo *repository.ServerRepository
+	pluginRepo *repository.PluginRepository
+	console    *ConsoleService
+	wsHub      WebSocketHubInterface // Optional
+	jobService *JobService           // Optional, persists every status change
+	webhooks   *WebhookService       // Optional, notifies completed jobs
+	quota      DiskQuotaChecker      // Optional, refuses jobs on full server directories
+
+	mu   sync.Mutex
+	jobs map[string]*PregenJob
+}
+
+// NewPregenerationService creates a new pre-generation service
+func NewPregenerationService(serverRepo *repository.ServerRepository, pluginRepo *repository.PluginRepository, console *ConsoleService) *PregenerationService {
+	return &PregenerationService{
+		serverRepo: serverRepo,
+		pluginRepo: pluginRepo,
+		console:    console,
+		jobs:       make(map[string]*PregenJob),
+	}
+}
+
+// SetWebSocketHub sets the WebSocket hub for progress broadcasts
+func (s *PregenerationService) SetWebSocketHub(wsHub WebSocketHubInterface) {
+	s.wsHub = wsHub
+}
+
+// SetJobService sets where status changes are persisted (GET /api/jobs)
+func (s *PregenerationService) SetJobService(jobService *JobService) {
+	s.jobService = jobService
+}
+
+// SetWebhookService sets the service that notifies server webhooks about completed jobs
+func (s *PregenerationService) SetWebhookService(webhooks *WebhookService) {
+	s.webhooks = webhooks
+}
+
+// SetDiskQuota sets the disk quota checked before jobs start
+func (s *PregenerationService) SetDiskQuota(quota DiskQuotaChecker) {
+	s.quota = quota
+}
+
+// Start starts pre-generating an area of a running server
+func (s *PregenerationService) Start(serverID, userID string, req PregenRequest) (*PregenJob, error) {
+	if err := normalizePregenRequest(&req); err != nil {
+		return nil, err
+	}
+
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+	if server.Status != models.StatusRunning {
+		return nil, ErrPregenServerNotRunning
+	}
+	// Generated chunks grow the world; refuse new jobs once the directory is full
+	if err := checkDiskQuota(s.quota, server.ID, 0); err != nil {
+		return nil, err
+	}
+
+	job := &PregenJob{
+		ID:            uuid.New().String(),
+		ServerID:      server.ID,
+		OwnerID:       server.OwnerID,
+		RequestedBy:   userID,
+		PregenRequest: req,
+		Method:        s.methodFor(server.ID),
+		Status:        PregenRunning,
+		CreatedAt:     time.Now(),
+		wake:          make(chan struct{}, 1),
+	}
+
+	s.mu.Lock()
+	s.prune()
+	for _, other := range s.jobs {
+		if other.ServerID == server.ID && other.FinishedAt == nil {
+			s.mu.Unlock()
+			return nil, ErrPregenAlreadyRunning
+		}
+	}
+	s.jobs[job.ID] = job
+	s.publishLocked(job)
+	snapshot := *job
+	s.mu.Unlock()
+
+	logger.Info("PREGEN: Job started", map[string]interface{}{
+		"job_id":    job.ID,
+		"server_id": server.ID,
+		"user_id":   userID,
+		"method":    job.Method,
+		"dimension": req.Dimension,
+		"radius":    req.Radius,
+		"shape":     req.Shape,
+	})
+
+	go s.run(job, s.pregeneratorFor(job))
+	return &snapshot, nil
+}
+
+// List returns the pre-generation jobs of a server, newest first
+func (s *PregenerationService) List(serverID string) []PregenJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+
+	jobs := []PregenJob{}
+	for _, job := range s.jobs {
+		if job.ServerID == serverID {
+			jobs = append(jobs, *job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// Get returns a pre-generation job of a server
+func (s *PregenerationService) Get(serverID, jobID string) (*PregenJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok || job.ServerID != serverID {
+		return nil, ErrPregenNotFound
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// Pause pauses a running or throttled job until Resume
+func (s *PregenerationService) Pause(serverID, jobID string) (*PregenJob, error) {
+	return s.control(serverID, jobID, func(job *PregenJob) error {
+		if job.Status != PregenRunning && job.Status != PregenThrottled {
+			return ErrPregenNotRunning
+		}
+		job.userPaused = true
+		job.Status = PregenPaused
+		return nil
+	})
+}
+
+// Resume continues a paused job (it may be throttled again right away while TPS is low)
+func (s *PregenerationService) Resume(serverID, jobID string) (*PregenJob, error) {
+	return s.control(serverID, jobID, func(job *PregenJob) error {
+		if job.Status != PregenPaused {
+			return ErrPregenNotPaused
+		}
+		job.userPaused = false
+		job.Status = PregenRunning
+		return nil
+	})
+}
+
+// Cancel stops a job; the chunks generated so far are kept
+func (s *PregenerationService) Cancel(serverID, jobID string) (*PregenJob, error) {
+	return s.control(serverID, jobID, cancelPregenJob)
+}
+
+// CancelForUser cancels a job owned or started by userID (DELETE /api/jobs/:job_id)
+func (s *PregenerationService) CancelForUser(userID, jobID string) (*PregenJob, error) {
+	s.mu.Lock()
+	job, ok := s.jobs[jobID]
+	s.mu.Unlock()
+	if !ok || (job.OwnerID != userID && job.RequestedBy != userID) {
+		return nil, ErrPregenNotFound
+	}
+	return s.Cancel(job.ServerID, jobID)
+}
+
+// cancelPregenJob requests the cancellation of an unfinished job (s.mu must be held)
+func cancelPregenJob(job *PregenJob) error {
+	if job.FinishedAt != nil || job.Status == PregenCancelling {
+		return ErrPregenFinished
+	}
+	job.cancel = true
+	job.Status = PregenCancelling
+	return nil
+}
+
+// control changes the desired state of a job and wakes its worker
+func (s *PregenerationService) control(serverID, jobID string, change func(job *PregenJob) error) (*PregenJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok || job.ServerID != serverID {
+		return nil, ErrPregenNotFound
+	}
+	if err := change(job); err != nil {
+		return nil, err
+	}
+	s.publishLocked(job)
+
+	select {
+	case job.wake <- struct{}{}:
+	default:
+	}
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// run drives a job until it completes, fails or is cancelled
+func (s *PregenerationService) run(job *PregenJob, gen pregenerator) {
+	if err := gen.start(); err != nil {
+		s.finish(job, PregenFailed, fmt.Errorf("failed to start pre-generation: %w", err))
+		return
+	}
+
+	paused := false    // Whether the generator is currently paused (by the user or throttling)
+	throttled := false // Whether TPS was too low at the last check
+	ticker := time.NewTicker(gen.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-job.wake:
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		cancel, userPaused := job.cancel, job.userPaused
+		s.mu.Unlock()
+
+		if cancel {
+			if err := gen.cancel(); err != nil {
+				logger.Warn("PREGEN: Failed to cancel generation", map[string]interface{}{
+					"job_id": job.ID,
+					"error":  err.Error(),
+				})
+			}
+			s.finish(job, PregenCancelled, nil)
+			return
+		}
+
+		server, err := s.serverRepo.FindByID(job.ServerID)
+		if err != nil || server.Status != models.StatusRunning {
+			s.finish(job, PregenFailed, errors.New("the server stopped before pre-generation finished"))
+			return
+		}
+
+		tps := -1.0
+		if job.MinTPS > 0 {
+			tps = s.tps(job.ServerID)
+		}
+		// Recovering servers need some headroom before the load returns
+		throttled = tps > 0 && (tps < job.MinTPS || (throttled && tps < job.MinTPS+pregenResumeTPSMargin))
+
+		switch wantPaused := userPaused || throttled; {
+		case wantPaused && !paused:
+			err = gen.pause()
+		case !wantPaused && paused:
+			err = gen.resume()
+		}
+		if err != nil {
+			s.finish(job, PregenFailed, err)
+			return
+		}
+		paused = userPaused || throttled
+
+		var progress float64
+		done := false
+		if !paused {
+			if progress, done, err = gen.step(); err != nil {
+				s.finish(job, PregenFailed, err)
+				return
+			}
+		}
+
+		s.mu.Lock()
+		if tps > 0 {
+			job.TPS = tps
+		}
+		if !paused {
+			job.Progress = progress
+		}
+		if !job.cancel && !job.userPaused {
+			if throttled {
+				job.Status = PregenThrottled
+			} else {
+				job.Status = PregenRunning
+			}
+		}
+		s.publishLocked(job)
+		s.mu.Unlock()
+
+		if done {
+			s.finish(job, PregenCompleted, nil)
+			return
+		}
+	}
+}
+
+// finish ends a job and announces completed ones
+func (s *PregenerationService) finish(job *PregenJob, status PregenStatus, cause error) {
+	s.mu.Lock()
+	now := time.Now()
+	job.Status = status
+	job.FinishedAt = &now
+	if status == PregenCompleted {
+		job.Progress = 100
+	}
+	if cause != nil {
+		job.Error = cause.Error()
+	}
+	s.publishLocked(job)
+	s.mu.Unlock()
+
+	fields := map[string]interface{}{
+		"job_id":    job.ID,
+		"server_id": job.ServerID,
+		"status":    status,
+		"progress":  job.Progress,
+	}
+	if cause != nil {
+		fields["error"] = cause.Error()
+		logger.Warn("PREGEN: Job failed", fields)
+		return
+	}
+	logger.Info("PREGEN: Job finished", fields)
+
+	if status != PregenCompleted {
+		return
+	}
+	events.PublishWorldPregenerated(job.ServerID, job.OwnerID, job.ID, job.Dimension, job.Radius)
+	if s.webhooks != nil {
+		serverName := job.ServerID
+		if server, err := s.serverRepo.FindByID(job.ServerID); err == nil {
+			serverName = server.Name
+		}
+		s.webhooks.NotifyPregenCompleted(job.ServerID, serverName,
+			fmt.Sprintf("%s, %s radius %d around %d, %d", job.Dimension, job.Shape, job.Radius, job.CenterX, job.CenterZ))
+	}
+}
+
+// methodFor returns Chunky if the plugin is installed and enabled on the server, the console method otherwise
+func (s *PregenerationService) methodFor(serverID string) PregenMethod {
+	installed, err := s.pluginRepo.ListInstalledPlugins(serverID)
+	if err != nil {
+		return PregenMethodConsole
+	}
+	for _, plugin := range installed {
+		if plugin.Enabled && plugin.Plugin != nil && plugin.Plugin.Slug == pregenChunkyPlugin {
+			return PregenMethodChunky
+		}
+	}
+	return PregenMethodConsole
+}
+
+// pregeneratorFor returns the generator of the job's method
+func (s *PregenerationService) pregeneratorFor(job *PregenJob) pregenerator {
+	exec := func(command string) (string, error) {
+		return s.console.ExecuteCommand(context.Background(), job.ServerID, command)
+	}
+	if job.Method == PregenMethodChunky {
+		return &chunkyPregenerator{req: job.PregenRequest, exec: exec}
+	}
+	return &consolePregenerator{req: job.PregenRequest, exec: exec, areas: pregenAreas(job.PregenRequest)}
+}
+
+// tps returns the current TPS of a server, -1 if the server cannot report it (vanilla)
+func (s *PregenerationService) tps(serverID string) float64 {
+	response, err := s.console.ExecuteCommand(context.Background(), serverID, "tps")
+	if err != nil {
+		return -1
+	}
+	return monitoring.ParseTPS(response)
+}
+
+// prune forgets jobs that finished more than finishedOperationRetention ago (s.mu must be held)
+func (s *PregenerationService) prune() {
+	cutoff := time.Now().Add(-finishedOperationRetention)
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// publishLocked publishes the status and progress of a job (s.mu must be held)
+func (s *PregenerationService) publishLocked(job *PregenJob) {
+	data := map[string]interface{}{
+		"job_id":    job.ID,
+		"server_id": job.ServerID,
+		"owner_id":  job.OwnerID,
+		"status":    job.Status,
+		"progress":  job.Progress,
+		"tps":       job.TPS,
+		"error":     job.Error,
+	}
+	events.PublishPregenerationProgress(data)
+	if s.wsHub != nil {
+		s.wsHub.Broadcast(events.PregenerationProgressEventType, data)
+	}
+	s.jobService.Record(job.record())
+}
+
+// record converts a job to its persisted record
+func (j *PregenJob) record() models.Job {
+	startedAt := j.CreatedAt
+	return models.Job{
+		ID:          j.ID,
+		OwnerID:     j.OwnerID,
+		RequestedBy: j.RequestedBy,
+		Kind:        "pregeneration",
+		ServerID:    j.ServerID,
+		Status:      string(j.Status),
+		Phase:       string(j.Method),
+		Progress:    int(j.Progress),
+		Error:       j.Error,
+		Cancellable: j.FinishedAt == nil && j.Status != PregenCancelling,
+		CreatedAt:   j.CreatedAt,
+		StartedAt:   &startedAt,
+		FinishedAt:  j.FinishedAt,
+	}
+}
+
+// normalizePregenRequest validates a request and fills in its defaults
+func normalizePregenRequest(req *PregenRequest) error {
+	if req.Dimension == "" {
+		req.Dimension = DimensionOverworld
+	}
+	if _, ok := chunkyWorlds[req.Dimension]; !ok {
+		return fmt.Errorf("%w: dimension must be overworld, nether or end", ErrPregenInvalid)
+	}
+	if req.Shape == "" {
+		req.Shape = PregenShapeSquare
+	}
+	if req.Shape != PregenShapeSquare && req.Shape != PregenShapeCircle {
+		return fmt.Errorf("%w: shape must be square or circle", ErrPregenInvalid)
+	}
+	if req.Radius < 16 {
+		return fmt.Errorf("%w: radius must be at least 16 blocks", ErrPregenInvalid)
+	}
+	if req.MinTPS > 20 {
+		return fmt.Errorf("%w: min_tps must be at most 20", ErrPregenInvalid)
+	}
+	if req.MinTPS == 0 {
+		req.MinTPS = pregenDefaultMinTPS
+	}
+	return nil
+}
+
+// chunkyPregenerator generates chunks with the Chunky plugin
+type chunkyPregenerator struct {
+	req  PregenRequest
+	exec func(command string) (string, error)
+}
+
+func (g *chunkyPregenerator) start() error {
+	for _, command := range []string{
+		"chunky world " + chunkyWorlds[g.req.Dimension],
+		"chunky shape " + string(g.req.Shape),
+		fmt.Sprintf("chunky center %d %d", g.req.CenterX, g.req.CenterZ),
+		fmt.Sprintf("chunky radius %d", g.req.Radius),
+		"chunky start",
+	} {
+		response, err := g.exec(command)
+		if err != nil {
+			return err
+		}
+		// Chunky asks before replacing an unfinished task of the same world
+		if strings.Contains(strings.ToLower(response), "confirm") {
+			if _, err := g.exec("chunky confirm"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *chunkyPregenerator) pause() error {
+	_, err := g.exec("chunky pause")
+	return err
+}
+
+func (g *chunkyPregenerator) resume() error {
+	_, err := g.exec("chunky continue")
+	return err
+}
+
+func (g *chunkyPregenerator) cancel() error {
+	_, err := g.exec("chunky cancel")
+	return err
+}
+
+func (g *chunkyPregenerator) step() (float64, bool, error) {
+	response, err := g.exec("chunky progress")
+	if err != nil {
+		return 0, false, err
+	}
+	progress, done := parseChunkyProgress(response)
+	return progress, done, nil
+}
+
+func (g *chunkyPregenerator) interval() time.Duration {
+	return pregenChunkyPollEvery
+}
+
+// chunkyProgressRegex matches "Processed: 1234 chunks (12.34%)" of "chunky progress"
+var chunkyProgressRegex = regexp.MustCompile(`\(([0-9]+(?:\.[0-9]+)?)%\)`)
+
+// parseChunkyProgress returns the percent done of a "chunky progress" response and whether the task is over
+// Chunky drops finished tasks, so "No tasks running" after a started task means it completed.
+func parseChunkyProgress(response string) (float64, bool) {
+	clean := regexp.MustCompile(`§.`).ReplaceAllString(response, "")
+	if strings.Contains(strings.ToLower(clean), "no tasks") {
+		return 100, true
+	}
+	matches := chunkyProgressRegex.FindStringSubmatch(clean)
+	if len(matches) < 2 {
+		return 0, false
+	}
+	progress, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	return progress, progress >= 100
+}
+
+// pregenArea is a rectangle of chunks, in block coordinates
+type pregenArea struct {
+	fromX, fromZ, toX, toZ int
+}
+
+// pregenAreas splits the requested area into force-loadable pieces of at most 16x16 chunks
+// For circles, pieces entirely outside the radius are left out.
+func pregenAreas(req PregenRequest) []pregenArea {
+	minCX, maxCX := floorDiv(req.CenterX-req.Radius, 16), floorDiv(req.CenterX+req.Radius, 16)
+	minCZ, maxCZ := floorDiv(req.CenterZ-req.Radius, 16), floorDiv(req.CenterZ+req.Radius, 16)
+
+	var areas []pregenArea
+	for cx := minCX; cx <= maxCX; cx += pregenAreaChunks {
+		for cz := minCZ; cz <= maxCZ; cz += pregenAreaChunks {
+			area := pregenArea{
+				fromX: cx * 16,
+				fromZ: cz * 16,
+				toX:   min(cx+pregenAreaChunks-1, maxCX)*16 + 15,
+				toZ:   min(cz+pregenAreaChunks-1, maxCZ)*16 + 15,
+			}
+			if req.Shape == PregenShapeCircle && !area.touchesCircle(req.CenterX, req.CenterZ, req.Radius) {
+				continue
+			}
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
+// touchesCircle reports whether the point of the area closest to the center is within radius
+func (a pregenArea) touchesCircle(centerX, centerZ, radius int) bool {
+	dx := max(a.fromX-centerX, 0, centerX-a.toX)
+	dz := max(a.fromZ-centerZ, 0, centerZ-a.toZ)
+	return dx*dx+dz*dz <= radius*radius
+}
+
+// floorDiv divides rounding towards negative infinity (block to chunk coordinates)
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// consolePregenerator generates chunks by force-loading one area after the other
+// Each area stays loaded for one interval, which gives the server time to generate it.
+type consolePregenerator struct {
+	req     PregenRequest
+	exec    func(command string) (string, error)
+	areas   []pregenArea
+	next    int         // Index of the next area to load
+	current *pregenArea // Area that is force-loaded right now
+}
+
+func (g *consolePregenerator) start() error {
+	return nil
+}
+
+func (g *consolePregenerator) pause() error {
+	return g.unload()
+}
+
+func (g *consolePregenerator) resume() error {
+	return nil
+}
+
+func (g *consolePregenerator) cancel() error {
+	return g.unload()
+}
+
+func (g *consolePregenerator) step() (float64, bool, error) {
+	if err := g.unload(); err != nil {
+		return 0, false, err
+	}
+	if g.next >= len(g.areas) {
+		return 100, true, nil
+	}
+
+	area := g.areas[g.next]
+	if err := g.forceload("add", area); err != nil {
+		return 0, false, err
+	}
+	g.current = &area
+	g.next++
+	return float64(g.next-1) * 100 / float64(len(g.areas)), false, nil
+}
+
+func (g *consolePregenerator) interval() time.Duration {
+	return pregenConsoleAreaEvery
+}
+
+// unload releases the force-loaded area, if any
+func (g *consolePregenerator) unload() error {
+	if g.current == nil {
+		return nil
+	}
+	if err := g.forceload("remove", *g.current); err != nil {
+		return err
+	}
+	g.current = nil
+	return nil
+}
+
+// forceload adds or removes the force-loading of an area in the job's dimension
+func (g *consolePregenerator) forceload(action string, area pregenArea) error {
+	_, err := g.exec(fmt.Sprintf("execute in %s run forceload %s %d %d %d %d",
+		consoleDimensions[g.req.Dimension], action, area.fromX, area.fromZ, area.toX, area.toZ))
+	return err
+}
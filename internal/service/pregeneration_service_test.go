@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseChunkyProgress(t *testing.T) {
+	tests := []struct {
+		response string
+		progress float64
+		done     bool
+	}{
+		{"§a[Chunky] Task running for world. Processed: 1234 chunks (12.34%), ETA: 0:10:00, Rate: 98.7 cps, Current: 10, 20", 12.34, false},
+		{"[Chunky] Task running for world. Processed: 40401 chunks (100%), ETA: 0:00:00", 100, true},
+		{"[Chunky] No tasks running.", 100, true},
+		{"Unknown or incomplete command", 0, false},
+	}
+	for _, tt := range tests {
+		progress, done := parseChunkyProgress(tt.response)
+		if progress != tt.progress || done != tt.done {
+			t.Errorf("parseChunkyProgress(%q) = %v, %v, want %v, %v", tt.response, progress, done, tt.progress, tt.done)
+		}
+	}
+}
+
+func TestPregenAreas(t *testing.T) {
+	// 512 blocks around the origin are chunks -32..32, i.e. 65x65 chunks in 5x5 areas
+	square := pregenAreas(PregenRequest{Radius: 512, Shape: PregenShapeSquare})
+	if len(square) != 25 {
+		t.Fatalf("square areas = %d, want 25", len(square))
+	}
+	first, last := square[0], square[len(square)-1]
+	if first.fromX != -512 || first.fromZ != -512 || last.toX != 527 || last.toZ != 527 {
+		t.Errorf("areas cover %d,%d to %d,%d, want -512,-512 to 527,527", first.fromX, first.fromZ, last.toX, last.toZ)
+	}
+	for _, area := range square {
+		if (area.toX-area.fromX+1)/16 > pregenAreaChunks || (area.toZ-area.fromZ+1)/16 > pregenAreaChunks {
+			t.Errorf("area %+v is larger than %dx%d chunks", area, pregenAreaChunks, pregenAreaChunks)
+		}
+	}
+
+	circle := pregenAreas(PregenRequest{Radius: 512, Shape: PregenShapeCircle})
+	// The one-chunk-wide edge areas at x or z 512 only touch the circle in the middle
+	if len(circle) != 18 {
+		t.Errorf("circle areas = %d, want 18", len(circle))
+	}
+}
+
+func TestNormalizePregenRequest(t *testing.T) {
+	req := PregenRequest{Radius: 1000}
+	if err := normalizePregenRequest(&req); err != nil {
+		t.Fatalf("normalizePregenRequest() error = %v", err)
+	}
+	if req.Dimension != DimensionOverworld || req.Shape != PregenShapeSquare || req.MinTPS != pregenDefaultMinTPS {
+		t.Errorf("defaults = %+v, want overworld, square and min_tps %v", req, pregenDefaultMinTPS)
+	}
+
+	for _, invalid := range []PregenRequest{
+		{Radius: 1000, Dimension: "world_nether"},
+		{Radius: 1000, Shape: "diamond"},
+		{Radius: 1000, MinTPS: 25},
+	} {
+		if err := normalizePregenRequest(&invalid); !errors.Is(err, ErrPregenInvalid) {
+			t.Errorf("normalizePregenRequest(%+v) error = %v, want ErrPregenInvalid", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/cloud"
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/velocity"
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+// ReadinessReport is the result of checking all dependencies
+type ReadinessReport struct {
+	Ready     bool              `json:"ready"`
+	Checks    []DependencyCheck `json:"checks"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// ReadinessService decides whether the instance can take traffic: the database, Docker, Velocity
+// and the cloud provider are reachable and the startup reconciliation has finished.
+// Checks run concurrently with a timeout each; the result is reused for a few seconds so frequent
+// load balancer probes don't hit the dependencies (and the cloud provider's rate limit) every time.
+type ReadinessService struct {
+	checks   []dependencyProbe
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu   sync.Mutex // Held while checking, so concurrent probes share one run
+	last *ReadinessReport
+}
+
+// NewReadinessService creates the readiness probe; velocityClient and cloudProvider may be nil.
+// Checks listed in HEALTH_READY_OPTIONAL are reported but don't make the instance unready.
+func NewReadinessService(dbProvider repository.DatabaseProvider, dockerService *docker.DockerService, velocityClient *velocity.RemoteVelocityClient, cloudProvider cloud.CloudProvider, reconciler *StateReconciler, cfg *config.Config) *ReadinessService {
+	checks := []dependencyProbe{
+		databaseProbe(dbProvider),
+		dockerProbe(dockerService),
+		velocityProbe(velocityClient),
+		cloudProviderProbe(cloudProvider),
+		{
+			// Before the startup run the registries don't know the running containers and nodes
+			name: "reconciliation",
+			run: func(ctx context.Context) (interface{}, error) {
+				return reconciliationStatus(reconciler.LastReport())
+			},
+		},
+	}
+
+	optional := map[string]bool{}
+	for _, name := range strings.Split(cfg.HealthReadyOptional, ",") {
+		optional[strings.TrimSpace(name)] = true
+	}
+	for i := range checks {
+		checks[i].required = !optional[checks[i].name]
+	}
+
+	s := newReadinessService(checks)
+	s.timeout = parseDurationOr(cfg.HealthCheckTimeout, s.timeout)
+	s.cacheTTL = parseDurationOr(cfg.HealthCheckCacheTTL, s.cacheTTL)
+	return s
+}
+
+func newReadinessService(checks []dependencyProbe) *ReadinessService {
+	return &ReadinessService{checks: checks, timeout: 3 * time.Second, cacheTTL: 10 * time.Second}
+}
+
+// Check returns the readiness of the instance, reusing a result younger than the cache TTL
+func (s *ReadinessService) Check(ctx context.Context) *ReadinessReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.last != nil && time.Since(s.last.CheckedAt) < s.cacheTTL {
+		return s.last
+	}
+
+	report := &ReadinessReport{CheckedAt: time.Now()}
+	report.Checks = runProbes(ctx, s.checks, s.timeout)
+	report.Ready = isReady(report.Checks)
+
+	s.last = report
+	return report
+}
+
+// isReady reports whether no required dependency is down
+func isReady(checks []DependencyCheck) bool {
+	for _, check := range checks {
+		if check.Required && check.Status == DependencyDown {
+			return false
+		}
+	}
+	return true
+}
+
+// reconciliationStatus checks the last state reconciliation: down until the first run has
+// finished, degraded if phases failed (the instance works, but parts of the state may be stale
+// until POST /api/admin/reconcile succeeds)
+func reconciliationStatus(report *ReconcileReport) (interface{}, error) {
+	if report == nil {
+		return nil, errors.New("the startup reconciliation hasn't finished yet")
+	}
+	details := map[string]interface{}{
+		"trigger":     report.Trigger,
+		"finished_at": report.FinishedAt,
+		"failed":      report.Failed,
+	}
+	if len(report.Failed) > 0 {
+		return details, &degradedError{reason: "failed phases: " + strings.Join(report.Failed, ", ")}
+	}
+	return details, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadinessServiceCheck(t *testing.T) {
+	var calls atomic.Int32
+	check := func(name string, required bool, err error) dependencyProbe {
+		return dependencyProbe{name: name, required: required, run: func(ctx context.Context) (interface{}, error) {
+			calls.Add(1)
+			return nil, err
+		}}
+	}
+	hanging := dependencyProbe{name: "hanging", run: func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond) // Ignores the deadline like the context-less clients
+		return nil, nil
+	}}
+	disabled := check("disabled", true, nil)
+	disabled.disabled = "not configured"
+
+	s := newReadinessService([]dependencyProbe{
+		check("database", true, nil),
+		check("velocity", false, errors.New("connection refused")),
+		check("reconciliation", true, &degradedError{reason: "failed phases: velocity"}),
+		disabled,
+		hanging,
+	})
+	s.timeout = 20 * time.Millisecond
+
+	report := s.Check(context.Background())
+	if !report.Ready {
+		t.Fatalf("Ready = false, want true (only an optional check is down): %+v", report.Checks)
+	}
+	want := map[string]DependencyStatus{
+		"database":       DependencyUp,
+		"velocity":       DependencyDown,
+		"reconciliation": DependencyDegraded,
+		"disabled":       DependencyDisabled,
+		"hanging":        DependencyDown,
+	}
+	for i, result := range report.Checks {
+		if result.Name != s.checks[i].name {
+			t.Errorf("check %d is %q, want %q (declaration order)", i, result.Name, s.checks[i].name)
+		}
+		if result.Status != want[result.Name] {
+			t.Errorf("%s status = %s, want %s (error %q)", result.Name, result.Status, want[result.Name], result.Error)
+		}
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("ran %d checks, want 3 (disabled checks don't run)", n)
+	}
+
+	// Probes within the TTL reuse the result
+	if again := s.Check(context.Background()); again != report || calls.Load() != 3 {
+		t.Errorf("second probe ran the checks again (calls = %d)", calls.Load())
+	}
+}
+
+func TestIsReady(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []DependencyCheck
+		want   bool
+	}{
+		{"all up", []DependencyCheck{{Required: true, Status: DependencyUp}}, true},
+		{"required down", []DependencyCheck{{Required: true, Status: DependencyUp}, {Required: true, Status: DependencyDown}}, false},
+		{"optional down", []DependencyCheck{{Required: false, Status: DependencyDown}}, true},
+		{"degraded", []DependencyCheck{{Required: true, Status: DependencyDegraded}}, true},
+		{"disabled", []DependencyCheck{{Required: true, Status: DependencyDisabled}}, true},
+	}
+	for _, tt := range tests {
+		if got := isReady(tt.checks); got != tt.want {
+			t.Errorf("%s: isReady() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReconciliationStatus(t *testing.T) {
+	if _, err := reconciliationStatus(nil); err == nil {
+		t.Error("no report yet: want an error")
+	}
+	if _, err := reconciliationStatus(&ReconcileReport{Failed: []string{}}); err != nil {
+		t.Errorf("clean run: error = %v", err)
+	}
+	var degraded *degradedError
+	if _, err := reconciliationStatus(&ReconcileReport{Failed: []string{"velocity"}}); !errors.As(err, &degraded) {
+		t.Errorf("failed phases: error = %v, want degraded", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
@@ -24,8 +25,43 @@ type RecoveryService struct {
 	cfg           *config.Config
 	wsHub         WebSocketHubInterface
 	conductor     ConductorInterface  // For multi-node support
+	velocityLobby VelocityFallbackInterface // Moves players to the fallback lobby while a server recovers (optional)
 	recoveryQueue chan *models.MinecraftServer
 	stopChan      chan struct{}
+
+	// Tell players that were online when a server crashed that it is back (all optional)
+	// webhookService also posts the crash itself
+	playerTracker    RecentPlayersProvider
+	playerMessenger  PlayerMessengerInterface
+	webhookService   *WebhookService
+	crashedPlayers   map[string][]string // server ID -> players online at crash time
+	crashedPlayersMu sync.Mutex
+
+	heapDumps HeapDumpCollector // Secures OOM heap dumps of modded servers before restarting (optional)
+}
+
+// recentCrashPlayersWindow is how recently a player must have been on a server to count as affected by its crash
+const recentCrashPlayersWindow = 5 * time.Minute
+
+// VelocityFallbackInterface moves players between a crashed backend and the Velocity fallback lobby
+type VelocityFallbackInterface interface {
+	EvacuateServer(serverName string) (int, error)
+	ReturnPlayers(serverName string) (int, error)
+}
+
+// RecentPlayersProvider returns the players recently seen on a server (implemented by PlayerCountService)
+type RecentPlayersProvider interface {
+	RecentPlayers(serverID string, within time.Duration) []string
+}
+
+// HeapDumpCollector moves OOM heap dumps out of a server volume (implemented by HeapDumpService)
+type HeapDumpCollector interface {
+	CollectHeapDumpsAfterExit(serverID string) (int, error)
+}
+
+// PlayerMessengerInterface messages players on the Velocity network (implemented by RemoteVelocityClient)
+type PlayerMessengerInterface interface {
+	NotifyPlayers(serverName string, players []string, message string) (int, error)
 }
 
 // NewRecoveryService creates a new recovery service
@@ -38,8 +74,9 @@ func NewRecoveryService(
 		serverRepo:    serverRepo,
 		dockerService: dockerService,
 		cfg:           cfg,
-		recoveryQueue: make(chan *models.MinecraftServer, 10),
-		stopChan:      make(chan struct{}),
+		recoveryQueue:  make(chan *models.MinecraftServer, 10),
+		stopChan:       make(chan struct{}),
+		crashedPlayers: make(map[string][]string),
 	}
 }
 
@@ -53,6 +90,31 @@ func (s *RecoveryService) SetConductor(conductor ConductorInterface) {
 	s.conductor = conductor
 }
 
+// SetVelocityFallback enables moving players to the fallback lobby during crash recovery
+func (s *RecoveryService) SetVelocityFallback(velocityLobby VelocityFallbackInterface) {
+	s.velocityLobby = velocityLobby
+}
+
+// SetPlayerTracker sets the source of players that were online when a server crashed
+func (s *RecoveryService) SetPlayerTracker(playerTracker RecentPlayersProvider) {
+	s.playerTracker = playerTracker
+}
+
+// SetPlayerMessenger enables in-game "server is back" messages for players of recovered servers
+func (s *RecoveryService) SetPlayerMessenger(playerMessenger PlayerMessengerInterface) {
+	s.playerMessenger = playerMessenger
+}
+
+// SetWebhookService enables Discord/Slack crash and "server is back" notifications
+func (s *RecoveryService) SetWebhookService(webhookService *WebhookService) {
+	s.webhookService = webhookService
+}
+
+// SetHeapDumpCollector enables collecting heap dumps of modded servers that crashed with a Java OOM
+func (s *RecoveryService) SetHeapDumpCollector(collector HeapDumpCollector) {
+	s.heapDumps = collector
+}
+
 // Start starts the recovery service
 func (s *RecoveryService) Start() {
 	logger.Info("Starting recovery service", nil)
@@ -99,7 +161,7 @@ func (s *RecoveryService) attemptRecovery(server *models.MinecraftServer) {
 	})
 
 	// Get container logs to diagnose the issue
-	logs, err := s.dockerService.GetContainerLogs(server.ContainerID, "200")
+	logs, err := s.dockerService.GetContainerLogs(context.Background(), server.ContainerID, "200")
 	if err != nil {
 		logger.Warn("Failed to get container logs for diagnosis", map[string]interface{}{
 			"server_id": server.ID,
@@ -140,6 +202,10 @@ func (s *RecoveryService) attemptRecovery(server *models.MinecraftServer) {
 		// Publish event
 		events.PublishServerRestarted(server.ID, fmt.Sprintf("Auto-recovery from %s", crashCause))
 
+		// Tell players elsewhere that the server is back, then bring back the ones waiting in the lobby
+		s.notifyPlayersRecovered(server)
+		s.returnPlayers(server)
+
 		// Broadcast recovery success via WebSocket
 		if s.wsHub != nil {
 			s.wsHub.Broadcast("server_recovered", map[string]interface{}{
@@ -155,6 +221,7 @@ func (s *RecoveryService) attemptRecovery(server *models.MinecraftServer) {
 		})
 		server.Status = models.StatusError
 		s.serverRepo.Update(server)
+		s.takeCrashedPlayers(server.ID)
 
 		// Broadcast recovery failure via WebSocket
 		if s.wsHub != nil {
@@ -371,11 +438,12 @@ func (s *RecoveryService) recoverFromSystemOOM(server *models.MinecraftServer) b
 
 	// DO NOT restart - this will cause an infinite loop
 	// Set server to error state permanently
+	reason := "CRITICAL: System has insufficient memory. Cannot restart."
 	server.Status = models.StatusError
-	s.serverRepo.Update(server)
+	s.serverRepo.UpdateWithEvent(server, events.ServerStoppedOutboxEvent(server.ID, reason))
 
 	// Publish critical event
-	events.PublishServerStopped(server.ID, "CRITICAL: System has insufficient memory. Cannot restart.")
+	events.PublishServerStopped(server.ID, reason)
 
 	// Alert via WebSocket
 	if s.wsHub != nil {
@@ -395,6 +463,21 @@ func (s *RecoveryService) recoverFromOOM(server *models.MinecraftServer) bool {
 		"current_ram": server.RAMMb,
 	})
 
+	// Secure the heap dump before the restart, so the next OOM can write a fresh one
+	if s.heapDumps != nil && server.HeapDumpsActive() {
+		if collected, err := s.heapDumps.CollectHeapDumpsAfterExit(server.ID); err != nil {
+			logger.Warn("Failed to collect heap dump after OOM", map[string]interface{}{
+				"server_id": server.ID,
+				"error":     err.Error(),
+			})
+		} else if collected > 0 {
+			logger.Info("Heap dump collected after OOM", map[string]interface{}{
+				"server_id": server.ID,
+				"dumps":     collected,
+			})
+		}
+	}
+
 	// For now, just try restarting
 	// In the future, could automatically increase RAM or notify admins
 	return s.restartContainer(server)
@@ -457,6 +540,7 @@ func (s *RecoveryService) restartContainer(server *models.MinecraftServer) bool
 
 	// Create new container
 	containerID, err := s.dockerService.CreateContainer(
+		ctx,
 		server.ID,
 		string(server.ServerType),
 		server.MinecraftVersion,
@@ -489,6 +573,12 @@ func (s *RecoveryService) restartContainer(server *models.MinecraftServer) bool
 		server.NetworkCompressionThreshold,
 		// Phase 4 Parameters - Server Description
 		server.MOTD,
+		// Runtime Diagnostics
+		server.HeapDumpsActive(),
+		// Vote Site Integration
+		server.VotifierPort,
+		s.dockerService.ImageName(server.Channel()),
+		s.dockerService.CPULimits(server),
 	)
 	if err != nil {
 		logger.Error("Failed to create container during recovery", err, map[string]interface{}{
@@ -503,7 +593,7 @@ func (s *RecoveryService) restartContainer(server *models.MinecraftServer) bool
 
 	// Start container (only for local nodes - remote containers are handled by RemoteDockerClient)
 	if s.isLocalNode(server.NodeID) {
-		if err := s.dockerService.StartContainer(containerID); err != nil {
+		if err := s.dockerService.StartContainer(ctx, containerID); err != nil {
 		logger.Error("Failed to start container during recovery", err, map[string]interface{}{
 			"server_id": server.ID,
 		})
@@ -513,7 +603,7 @@ func (s *RecoveryService) restartContainer(server *models.MinecraftServer) bool
 	}
 
 		// Wait for server to be ready (with shorter timeout for recovery)
-		err = s.dockerService.WaitForServerReady(containerID, 90)
+		err = s.dockerService.WaitForServerReady(ctx, containerID, 90)
 		if err != nil {
 			logger.Warn("Server may not be fully ready after recovery", map[string]interface{}{
 				"server_id": server.ID,
@@ -541,7 +631,7 @@ func (s *RecoveryService) restartContainer(server *models.MinecraftServer) bool
 // cleanupOrphanedContainers removes orphaned containers that might be blocking the port
 func (s *RecoveryService) cleanupOrphanedContainers(server *models.MinecraftServer) {
 	containerName := fmt.Sprintf("mc-%s", server.ID)
-	err := s.dockerService.RemoveContainerByName(containerName)
+	err := s.dockerService.RemoveContainerByName(context.Background(), containerName)
 	if err != nil {
 		logger.Warn("Failed to cleanup orphaned container", map[string]interface{}{
 			"server_id": server.ID,
@@ -603,6 +693,9 @@ func (s *RecoveryService) CheckAndRecoverCrashedServers() error {
 				errorMessage = "Container exited unexpectedly"
 			}
 			events.PublishServerCrashed(server.ID, inspect.State.ExitCode, errorMessage)
+			if s.webhookService != nil {
+				s.webhookService.NotifyServerCrash(server.ID, server.Name, errorMessage)
+			}
 
 			// Broadcast crash detection via WebSocket
 			if s.wsHub != nil {
@@ -614,6 +707,10 @@ func (s *RecoveryService) CheckAndRecoverCrashedServers() error {
 				})
 			}
 
+			// Remember who was online and keep them on the network while the server recovers
+			s.snapshotCrashedPlayers(&server)
+			s.evacuatePlayers(&server)
+
 			// Queue for recovery
 			s.RecoverServer(&server)
 		}
@@ -622,6 +719,112 @@ func (s *RecoveryService) CheckAndRecoverCrashedServers() error {
 	return nil
 }
 
+// evacuatePlayers moves players still connected to a crashed server to the fallback lobby
+// Players already kicked by the crash are caught by Velocity's fallback and remembered there as well.
+func (s *RecoveryService) evacuatePlayers(server *models.MinecraftServer) {
+	if s.velocityLobby == nil {
+		return
+	}
+
+	moved, err := s.velocityLobby.EvacuateServer("mc-" + server.ID)
+	if err != nil {
+		logger.Warn("Failed to move players to fallback lobby", map[string]interface{}{
+			"server_id": server.ID,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	if moved > 0 {
+		logger.Info("Players moved to fallback lobby", map[string]interface{}{
+			"server_id": server.ID,
+			"players":   moved,
+		})
+	}
+}
+
+// returnPlayers sends players waiting in the fallback lobby back to their recovered server
+func (s *RecoveryService) returnPlayers(server *models.MinecraftServer) {
+	if s.velocityLobby == nil {
+		return
+	}
+
+	moved, err := s.velocityLobby.ReturnPlayers("mc-" + server.ID)
+	if err != nil {
+		logger.Warn("Failed to return players from fallback lobby", map[string]interface{}{
+			"server_id": server.ID,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	if moved > 0 {
+		logger.Info("Players returned from fallback lobby", map[string]interface{}{
+			"server_id": server.ID,
+			"players":   moved,
+		})
+	}
+}
+
+// snapshotCrashedPlayers remembers the players that were on a server when it crashed
+func (s *RecoveryService) snapshotCrashedPlayers(server *models.MinecraftServer) {
+	if s.playerTracker == nil {
+		return
+	}
+
+	players := s.playerTracker.RecentPlayers(server.ID, recentCrashPlayersWindow)
+	if len(players) == 0 {
+		return
+	}
+
+	s.crashedPlayersMu.Lock()
+	s.crashedPlayers[server.ID] = players
+	s.crashedPlayersMu.Unlock()
+}
+
+// takeCrashedPlayers returns and forgets the players remembered for a crashed server
+func (s *RecoveryService) takeCrashedPlayers(serverID string) []string {
+	s.crashedPlayersMu.Lock()
+	defer s.crashedPlayersMu.Unlock()
+
+	players := s.crashedPlayers[serverID]
+	delete(s.crashedPlayers, serverID)
+	return players
+}
+
+// notifyPlayersRecovered tells the players that were online at crash time that their server is back
+// In-game via Velocity (with a rejoin hint) and via the server's Discord webhook.
+func (s *RecoveryService) notifyPlayersRecovered(server *models.MinecraftServer) {
+	players := s.takeCrashedPlayers(server.ID)
+	if len(players) == 0 {
+		return
+	}
+
+	if s.webhookService != nil {
+		s.webhookService.NotifyServerRecovered(server.ID, server.Name, players)
+	}
+
+	if s.playerMessenger == nil {
+		return
+	}
+
+	message := fmt.Sprintf("%s is back online!", server.Name)
+	delivered, err := s.playerMessenger.NotifyPlayers("mc-"+server.ID, players, message)
+	if err != nil {
+		logger.Warn("Failed to notify players about recovered server", map[string]interface{}{
+			"server_id": server.ID,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Notified players about recovered server", map[string]interface{}{
+		"server_id": server.ID,
+		"players":   len(players),
+		"delivered": delivered,
+	})
+}
+
 // isLocalNode checks if a node ID represents the local Docker daemon
 // Returns true if nodeID is "local-node" or empty (backward compatibility)
 func (s *RecoveryService) isLocalNode(nodeID string) bool {
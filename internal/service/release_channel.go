@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// statusPingReadinessTimeout bounds the Server List Ping readiness probe after the "Done" log line
+const statusPingReadinessTimeout = 30 * time.Second
+
+// ReleaseChannelStatus describes which managed changes a server receives on its channel
+type ReleaseChannelStatus struct {
+	ServerID             string                `json:"server_id"`
+	Channel              models.ReleaseChannel `json:"channel"`
+	Image                string                `json:"image"`                   // Used from the next container start
+	PluginUpdateSoakDays int                   `json:"plugin_update_soak_days"` // 0 = auto-updates apply as soon as a compatible version exists
+	PreReleasePlugins    bool                  `json:"pre_release_plugins"`     // Auto-update may install beta/alpha plugin versions
+	StatusPingReadiness  bool                  `json:"status_ping_readiness"`   // Start waits for a Server List Ping after "Done"
+}
+
+// GetReleaseChannel returns the server's release channel and what it means for automation
+func (s *MinecraftService) GetReleaseChannel(server *models.MinecraftServer) *ReleaseChannelStatus {
+	channel := server.Channel()
+	status := &ReleaseChannelStatus{
+		ServerID: server.ID,
+		Channel:  channel,
+		Image:    s.dockerService.ImageName(channel),
+	}
+	if channel == models.ReleaseChannelBeta {
+		status.PreReleasePlugins = true
+		status.StatusPingReadiness = true
+	} else {
+		status.PluginUpdateSoakDays = s.cfg.PluginUpdateSoakDays
+	}
+	return status
+}
+
+// SetReleaseChannel moves a server to a release channel
+// The image changes on the next container start; plugin auto-updates and readiness probes follow immediately.
+func (s *MinecraftService) SetReleaseChannel(serverID string, channel models.ReleaseChannel) (*models.MinecraftServer, error) {
+	server, err := s.repo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	previous := server.Channel()
+	server.ReleaseChannel = channel
+	if err := s.repo.Update(server); err != nil {
+		return nil, fmt.Errorf("failed to update release channel: %w", err)
+	}
+
+	logger.Info("Server release channel updated", map[string]interface{}{
+		"server_id": serverID,
+		"from":      previous,
+		"to":        channel,
+	})
+	return server, nil
+}
+
+// waitForStatusPing is the Server List Ping readiness probe, rolled out to beta servers first
+// The log-based check only sees "Done (...)!"; the ping confirms the server accepts connections on its port.
+func (s *MinecraftService) waitForStatusPing(server *models.MinecraftServer, nodeID string) {
+	if server.Channel() != models.ReleaseChannelBeta {
+		return
+	}
+
+	host := s.cfg.ControlPlaneIP
+	if !s.isLocalNode(nodeID) {
+		if s.conductor == nil {
+			return
+		}
+		remoteNode, err := s.conductor.GetRemoteNode(nodeID)
+		if err != nil {
+			return
+		}
+		host = remoteNode.IPAddress
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(server.Port))
+
+	deadline := time.Now().Add(statusPingReadinessTimeout)
+	for {
+		status, err := pingServerList(address, 3*time.Second)
+		if err == nil {
+			logger.Info("READINESS: Server answers status pings", map[string]interface{}{
+				"server_id":  server.ID,
+				"version":    status.Version.Name,
+				"latency_ms": status.Latency.Milliseconds(),
+			})
+			return
+		}
+		if time.Now().After(deadline) {
+			// Continue anyway, like the log-based check: the server may still come up
+			logger.Warn("READINESS: Server did not answer status pings", map[string]interface{}{
+				"server_id": server.ID,
+				"address":   address,
+				"error":     err.Error(),
+			})
+			return
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+// Remote action errors
+var (
+	ErrRemoteActionUnknown  = errors.New("unknown action")
+	ErrRemoteActionInvalid  = errors.New("invalid action parameters")
+	ErrRemoteActionConflict = errors.New("action not possible in the server's current state")
+)
+
+// remoteActionMaxCommandLength is the longest console command or broadcast message accepted
+const remoteActionMaxCommandLength = 256
+
+// RemoteActionParam is a parameter of a remote action (passed in the "params" object)
+type RemoteActionParam struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// RemoteAction is an action external automation (Discord bots, CI pipelines) can trigger on a server
+// with one call. The caller needs Permission on the server; API keys additionally need Scope.
+type RemoteAction struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Permission  models.ServerPermission `json:"permission"`
+	Scope       models.APIKeyScope      `json:"scope"`
+	Params      []RemoteActionParam     `json:"params,omitempty"`
+	Expensive   bool                    `json:"expensive"` // Also counts against the expensive-operation rate limit
+}
+
+// RemoteActions is the action catalog of POST /api/servers/:id/actions/:action
+var RemoteActions = []RemoteAction{
+	{Name: "start", Description: "Start the server (queued if the plan's concurrency limit is reached)",
+		Permission: models.PermServerPower, Scope: models.ScopeServersStart, Expensive: true},
+	{Name: "stop", Description: "Stop the server",
+		Permission: models.PermServerPower, Scope: models.ScopeServersStart},
+	{Name: "restart", Description: "Stop the running server and start it again",
+		Permission: models.PermServerPower, Scope: models.ScopeServersStart, Expensive: true},
+	{Name: "backup", Description: "Create a manual backup",
+		Permission: models.PermServerBackup, Scope: models.ScopeBackupsWrite, Expensive: true,
+		Params: []RemoteActionParam{{Name: "description", Description: "Shown in the backup list"}}},
+	{Name: "command", Description: "Run a console command and return its output",
+		Permission: models.PermServerConsole, Scope: models.ScopeServersConsole,
+		Params: []RemoteActionParam{{Name: "command", Description: "Command without the leading slash, e.g. \"whitelist add Steve\"", Required: true}}},
+	{Name: "broadcast", Description: "Send a chat message to all players (\"say\")",
+		Permission: models.PermServerConsole, Scope: models.ScopeServersConsole,
+		Params: []RemoteActionParam{{Name: "message", Description: "Chat message", Required: true}}},
+}
+
+// FindRemoteAction returns the catalog entry of an action
+func FindRemoteAction(name string) (*RemoteAction, error) {
+	for i := range RemoteActions {
+		if RemoteActions[i].Name == name {
+			return &RemoteActions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrRemoteActionUnknown, name)
+}
+
+// RemoteActionResult is the outcome of a remote action
+type RemoteActionResult struct {
+	Action    string     `json:"action"`
+	Status    string     `json:"status"` // "done", "started" (runs in the background) or "queued"
+	Message   string     `json:"message"`
+	Output    string     `json:"output,omitempty"`    // Console output of command actions
+	BackupID  string     `json:"backup_id,omitempty"` // Backup actions
+	Operation *Operation `json:"operation,omitempty"` // Poll GET /api/operations/:operation_id
+}
+
+// RemoteActionService runs the actions of the remote action catalog through the regular services,
+// so they follow the same rules (operation slots, quotas, maintenance mode) as the dashboard
+type RemoteActionService struct {
+	minecraft   *MinecraftService
+	backups     *BackupService
+	console     *ConsoleService
+	maintenance *MaintenanceService
+}
+
+// NewRemoteActionService creates a new remote action service
+func NewRemoteActionService(minecraft *MinecraftService, backups *BackupService, console *ConsoleService, maintenance *MaintenanceService) *RemoteActionService {
+	return &RemoteActionService{
+		minecraft:   minecraft,
+		backups:     backups,
+		console:     console,
+		maintenance: maintenance,
+	}
+}
+
+// Run runs an action on a server for userID (permissions are checked by the caller)
+func (s *RemoteActionService) Run(ctx context.Context, serverID, userID string, action *RemoteAction, params map[string]string) (*RemoteActionResult, error) {
+	if err := validateRemoteActionParams(action, params); err != nil {
+		return nil, err
+	}
+	result := &RemoteActionResult{Action: action.Name, Status: "done"}
+
+	switch action.Name {
+	case "start":
+		op, err := s.minecraft.RequestStart(ctx, serverID, userID)
+		if err != nil {
+			return nil, err
+		}
+		result.Status, result.Message, result.Operation = startStatus(op), "server starting", op
+
+	case "stop":
+		if err := s.minecraft.StopServerContext(ctx, serverID, "remote action"); err != nil {
+			return nil, err
+		}
+		result.Message = "server stopped"
+
+	case "restart":
+		server, err := s.minecraft.GetServer(serverID)
+		if err != nil {
+			return nil, fmt.Errorf("server not found: %w", err)
+		}
+		if server.Status != models.StatusRunning {
+			return nil, fmt.Errorf("%w: server is %s", ErrRemoteActionConflict, server.Status)
+		}
+		// Don't stop a server that couldn't be started again
+		if err := s.maintenance.Check(); err != nil {
+			return nil, err
+		}
+		if err := s.minecraft.StopServerContext(ctx, serverID, "remote action: restart"); err != nil {
+			return nil, err
+		}
+		op, err := s.minecraft.RequestStart(ctx, serverID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("server stopped, but the start failed: %w", err)
+		}
+		result.Status, result.Message, result.Operation = startStatus(op), "server restarting", op
+
+	case "backup":
+		backup, op, err := s.backups.CreateBackupWithCompression(ctx, serverID, models.BackupTypeManual,
+			strings.TrimSpace(params["description"]), &userID, 0, s.backups.DefaultCompression())
+		if err != nil {
+			return nil, err
+		}
+		result.Status, result.Message, result.BackupID, result.Operation = "started", "backup started", backup.ID, op
+		if op.IsQueued() {
+			result.Status, result.Message = "queued", "backup queued"
+		}
+
+	case "command", "broadcast":
+		command := strings.TrimSpace(params["command"])
+		if action.Name == "broadcast" {
+			command = "say " + strings.TrimSpace(params["message"])
+		}
+		output, err := s.console.ExecuteCommand(ctx, serverID, command)
+		if err != nil {
+			return nil, err
+		}
+		result.Message, result.Output = "command executed", output
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrRemoteActionUnknown, action.Name)
+	}
+	return result, nil
+}
+
+// startStatus returns the result status of a requested start
+func startStatus(op *Operation) string {
+	if op.IsQueued() {
+		return "queued"
+	}
+	return "started"
+}
+
+// validateRemoteActionParams checks the parameters of an action: required ones are set, there are no
+// unknown ones, and command texts are a single line of at most remoteActionMaxCommandLength characters
+func validateRemoteActionParams(action *RemoteAction, params map[string]string) error {
+	known := map[string]bool{}
+	for _, param := range action.Params {
+		known[param.Name] = true
+		value := strings.TrimSpace(params[param.Name])
+		if param.Required && value == "" {
+			return fmt.Errorf("%w: %s is required", ErrRemoteActionInvalid, param.Name)
+		}
+		if len(value) > remoteActionMaxCommandLength {
+			return fmt.Errorf("%w: %s is longer than %d characters", ErrRemoteActionInvalid, param.Name, remoteActionMaxCommandLength)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("%w: %s must be a single line", ErrRemoteActionInvalid, param.Name)
+		}
+	}
+	for name := range params {
+		if !known[name] {
+			return fmt.Errorf("%w: unknown parameter %s", ErrRemoteActionInvalid, name)
+		}
+	}
+	return nil
+}
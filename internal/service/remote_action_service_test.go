@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestFindRemoteAction(t *testing.T) {
+	action, err := FindRemoteAction("backup")
+	if err != nil || action.Permission != models.PermServerBackup {
+		t.Fatalf("FindRemoteAction(backup) = %+v, %v", action, err)
+	}
+	if _, err := FindRemoteAction("format-disk"); !errors.Is(err, ErrRemoteActionUnknown) {
+		t.Errorf("FindRemoteAction(format-disk) error = %v, want ErrRemoteActionUnknown", err)
+	}
+
+	// Every action's scope is the one API keys need for its permission
+	for _, action := range RemoteActions {
+		if scope := models.ServerPermissionScope[action.Permission]; scope != action.Scope {
+			t.Errorf("%s: scope %s, want %s for permission %s", action.Name, action.Scope, scope, action.Permission)
+		}
+	}
+}
+
+func TestValidateRemoteActionParams(t *testing.T) {
+	command, _ := FindRemoteAction("command")
+	restart, _ := FindRemoteAction("restart")
+
+	tests := []struct {
+		name   string
+		action *RemoteAction
+		params map[string]string
+		valid  bool
+	}{
+		{"command", command, map[string]string{"command": "whitelist add Steve"}, true},
+		{"command missing", command, nil, false},
+		{"command blank", command, map[string]string{"command": "  "}, false},
+		{"command multi-line", command, map[string]string{"command": "say hi\nop Steve"}, false},
+		{"command too long", command, map[string]string{"command": strings.Repeat("a", remoteActionMaxCommandLength+1)}, false},
+		{"unknown param", command, map[string]string{"command": "list", "as": "console"}, false},
+		{"no params", restart, nil, true},
+		{"param of action without params", restart, map[string]string{"delay": "10"}, false},
+	}
+	for _, tt := range tests {
+		err := validateRemoteActionParams(tt.action, tt.params)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: validateRemoteActionParams() = %v, want valid=%v", tt.name, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrRemoteActionInvalid) {
+			t.Errorf("%s: error %v does not wrap ErrRemoteActionInvalid", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// NodeCountryResolver resolves the country a node is located in (implemented by the Conductor)
+type NodeCountryResolver interface {
+	NodeCountry(nodeID string) string
+}
+
+// ResidencyService enforces organization data residency: servers of an organization with a
+// residency country only run on nodes in that country, and their backups, archives and
+// migrations are blocked (and logged) when they would leave it.
+// A nil *ResidencyService allows everything, so callers don't need to check whether it's wired.
+type ResidencyService struct {
+	orgRepo *repository.OrganizationRepository
+	nodes   NodeCountryResolver // Optional: required for migration checks
+}
+
+// NewResidencyService creates a new data residency service
+func NewResidencyService(orgRepo *repository.OrganizationRepository) *ResidencyService {
+	return &ResidencyService{orgRepo: orgRepo}
+}
+
+// SetNodeResolver sets the resolver used to check migration target nodes
+func (s *ResidencyService) SetNodeResolver(nodes NodeCountryResolver) {
+	s.nodes = nodes
+}
+
+// RequiredCountry returns the country the server's data must stay in ("" = no restriction)
+func (s *ResidencyService) RequiredCountry(server *models.MinecraftServer) string {
+	if s == nil || server.OrganizationID == "" {
+		return ""
+	}
+
+	org, err := s.orgRepo.FindByID(server.OrganizationID)
+	if err != nil {
+		return ""
+	}
+	return org.DataResidency
+}
+
+// Placement restricts placement to nodes in the server's residency country
+func (s *ResidencyService) Placement(server *models.MinecraftServer, placement models.NodePlacement) models.NodePlacement {
+	if country := s.RequiredCountry(server); country != "" {
+		placement.Country = country
+	}
+	return placement
+}
+
+// CheckNode blocks moving the server's data to a node outside its residency country
+func (s *ResidencyService) CheckNode(server *models.MinecraftServer, nodeID, operation string) error {
+	country := s.RequiredCountry(server)
+	if country == "" {
+		return nil
+	}
+
+	nodeCountry := ""
+	if s.nodes != nil {
+		nodeCountry = s.nodes.NodeCountry(nodeID)
+	}
+	if nodeCountry == country {
+		return nil
+	}
+
+	return s.violation(server, country, nodeCountry, operation, map[string]interface{}{"node_id": nodeID})
+}
+
+// CheckStorage blocks writing the server's data to storage outside its residency country
+// storageCountry "" means the storage location is unknown, which never satisfies a residency.
+func (s *ResidencyService) CheckStorage(server *models.MinecraftServer, storageCountry, operation string) error {
+	country := s.RequiredCountry(server)
+	if country == "" || storageCountry == country {
+		return nil
+	}
+
+	return s.violation(server, country, storageCountry, operation, nil)
+}
+
+// violation logs a blocked residency violation and returns the error for the caller
+func (s *ResidencyService) violation(server *models.MinecraftServer, required, actual, operation string, extra map[string]interface{}) error {
+	if actual == "" {
+		actual = "unknown"
+	}
+
+	fields := map[string]interface{}{
+		"server_id":        server.ID,
+		"organization_id":  server.OrganizationID,
+		"operation":        operation,
+		"required_country": required,
+		"target_country":   actual,
+	}
+	for key, value := range extra {
+		fields[key] = value
+	}
+	logger.Warn("RESIDENCY: Violation blocked", fields)
+
+	return fmt.Errorf("%w: %s requires %s, target is in %s", models.ErrResidencyViolation, operation, required, actual)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// runtimeConfigDefaultInterval applies if CONFIG_RELOAD_INTERVAL is not a valid duration
+const runtimeConfigDefaultInterval = 10 * time.Second
+
+// RuntimeConfigStatus is the effective configuration shown to admins
+type RuntimeConfigStatus struct {
+	File           string                 `json:"file"`
+	ReloadInterval string                 `json:"reload_interval"` // "0s" = file is not watched
+	LastReload     *time.Time             `json:"last_reload,omitempty"`
+	LastError      string                 `json:"last_error,omitempty"`
+	Reloadable     []string               `json:"reloadable"` // Env keys that apply without a restart
+	Settings       map[string]interface{} `json:"settings"`   // By Config field, secrets redacted
+}
+
+// RuntimeConfigService applies changes of the settings in config.Reloadable while the API runs.
+// CONFIG_FILE is re-read when it changes (checked every CONFIG_RELOAD_INTERVAL), on SIGHUP and when
+// an admin asks for it. Most reloadable settings are read from the shared config on every use;
+// services that copied a setting at startup register a listener with OnChange.
+type RuntimeConfigService struct {
+	cfg      *config.Config
+	interval time.Duration // 0 = don't watch the file
+
+	mu         sync.Mutex
+	listeners  []func(changes []config.Change)
+	modTime    time.Time
+	lastReload time.Time
+	lastError  string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRuntimeConfigService creates a new config reloader for cfg (config.AppConfig)
+func NewRuntimeConfigService(cfg *config.Config) *RuntimeConfigService {
+	interval, err := time.ParseDuration(cfg.ConfigReloadInterval)
+	if err != nil || interval < 0 {
+		interval = runtimeConfigDefaultInterval
+	}
+	if cfg.ConfigReloadInterval == "0" {
+		interval = 0
+	}
+	return &RuntimeConfigService{cfg: cfg, interval: interval}
+}
+
+// OnChange registers fn to be called with the changed settings after every reload that changed something
+func (s *RuntimeConfigService) OnChange(fn func(changes []config.Change)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Reload re-reads the config file, applies the changed reloadable settings and notifies the listeners
+func (s *RuntimeConfigService) Reload() ([]config.Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if info, err := os.Stat(s.cfg.ConfigFile); err == nil {
+		s.modTime = info.ModTime()
+	}
+	changes, err := config.Reload(s.cfg, s.cfg.ConfigFile)
+	if err != nil {
+		s.lastError = err.Error()
+		logger.Warn("CONFIG: Failed to reload configuration", map[string]interface{}{
+			"file":  s.cfg.ConfigFile,
+			"error": err.Error(),
+		})
+		return nil, err
+	}
+	s.lastReload, s.lastError = time.Now(), ""
+
+	for _, change := range changes {
+		logger.Info("CONFIG: Setting reloaded", map[string]interface{}{
+			"key": change.Key,
+			"old": change.Old,
+			"new": change.New,
+		})
+	}
+	if len(changes) > 0 {
+		for _, listener := range s.listeners {
+			listener(changes)
+		}
+	}
+	return changes, nil
+}
+
+// Status returns the effective configuration with secrets redacted
+func (s *RuntimeConfigService) Status() RuntimeConfigStatus {
+	s.mu.Lock()
+	status := RuntimeConfigStatus{
+		File:           s.cfg.ConfigFile,
+		ReloadInterval: s.interval.String(),
+		LastError:      s.lastError,
+		Reloadable:     config.ReloadableKeys(),
+	}
+	if !s.lastReload.IsZero() {
+		lastReload := s.lastReload
+		status.LastReload = &lastReload
+	}
+	s.mu.Unlock()
+
+	status.Settings = config.Redacted(s.cfg)
+	return status
+}
+
+// Start watches the config file and SIGHUP
+func (s *RuntimeConfigService) Start() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+	if info, err := os.Stat(s.cfg.ConfigFile); err == nil {
+		s.modTime = info.ModTime()
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	logger.Info("CONFIG: Watching configuration for reloadable settings", map[string]interface{}{
+		"file":     s.cfg.ConfigFile,
+		"interval": s.interval.String(),
+	})
+
+	go func() {
+		defer close(s.done)
+		defer signal.Stop(hangup)
+		var tick <-chan time.Time // Nil (never fires) if the file isn't watched
+		if s.interval > 0 {
+			ticker := time.NewTicker(s.interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			select {
+			case <-tick:
+				if s.fileChanged() {
+					s.Reload()
+				}
+			case <-hangup:
+				logger.Info("CONFIG: SIGHUP received, reloading configuration", nil)
+				s.Reload()
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the watcher
+func (s *RuntimeConfigService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// fileChanged reports whether the config file was modified since the last reload
+func (s *RuntimeConfigService) fileChanged() bool {
+	info, err := os.Stat(s.cfg.ConfigFile)
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !info.ModTime().Equal(s.modTime)
+}
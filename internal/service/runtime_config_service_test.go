@@ -0,0 +1,56 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+func TestRuntimeConfigReloadsChangedSettings(t *testing.T) {
+	// Reload writes the file values to the environment; t.Setenv restores it afterwards
+	t.Setenv("RATE_LIMIT_API_BURST", "")
+	t.Setenv("DEFAULT_IDLE_TIMEOUT", "")
+	t.Setenv("JWT_SECRET", "from-env")
+
+	file := filepath.Join(t.TempDir(), ".env")
+	t.Setenv("CONFIG_FILE", file)
+	if err := os.WriteFile(file, []byte("RATE_LIMIT_API_BURST=120\nJWT_SECRET=hunter2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Load()
+	if cfg.RateLimitAPIBurst != 120 {
+		t.Fatalf("RateLimitAPIBurst = %d, want 120 from the file", cfg.RateLimitAPIBurst)
+	}
+
+	s := NewRuntimeConfigService(cfg)
+	var notified []config.Change
+	s.OnChange(func(changes []config.Change) { notified = changes })
+
+	if err := os.WriteFile(file, []byte("RATE_LIMIT_API_BURST=7\nDEFAULT_IDLE_TIMEOUT=600\nJWT_SECRET=changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := s.Reload()
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if len(changes) != 2 || len(notified) != 2 {
+		t.Fatalf("changes = %+v, notified = %+v, want the two reloadable settings", changes, notified)
+	}
+	if cfg.RateLimitAPIBurst != 7 || cfg.DefaultIdleTimeout != 600 {
+		t.Errorf("settings not applied: burst %d, idle timeout %d", cfg.RateLimitAPIBurst, cfg.DefaultIdleTimeout)
+	}
+	if cfg.JWTSecret != "from-env" {
+		t.Errorf("JWTSecret = %q, settings that need a restart must not change", cfg.JWTSecret)
+	}
+
+	if changes, _ := s.Reload(); len(changes) != 0 {
+		t.Errorf("second reload changed %+v", changes)
+	}
+
+	status := s.Status()
+	if status.Settings["JWTSecret"] != "[redacted]" || status.Settings["RateLimitAPIBurst"] != 7 || status.LastReload == nil {
+		t.Errorf("status = %+v", status)
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	scalingDecisionQueueSize     = 64
+	scalingDecisionPruneInterval = 6 * time.Hour
+	scalingDecisionStopTimeout   = 5 * time.Second
+)
+
+// ScalingDecisionService keeps the history of the scaling engine's evaluations: the fleet snapshot,
+// the verdict of every policy asked, the chosen action and its outcome. Records are written by one
+// worker, so the engine never waits for the database. Decisions older than
+// SCALING_DECISION_RETENTION_DAYS are deleted.
+type ScalingDecisionService struct {
+	repo      *repository.ScalingDecisionRepository
+	retention int // Days, 0 = forever
+	queue     chan models.ScalingDecision
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewScalingDecisionService creates a new scaling decision service
+func NewScalingDecisionService(repo *repository.ScalingDecisionRepository, cfg *config.Config) *ScalingDecisionService {
+	return &ScalingDecisionService{
+		repo:      repo,
+		retention: cfg.ScalingDecisionRetentionDays,
+		queue:     make(chan models.ScalingDecision, scalingDecisionQueueSize),
+	}
+}
+
+// RecordScalingDecision queues an evaluation of the scaling engine for writing (conductor.ScalingDecisionSink)
+func (s *ScalingDecisionService) RecordScalingDecision(decision conductor.ScalingDecision) {
+	record := scalingDecisionRecord(decision)
+	select {
+	case s.queue <- record:
+	default:
+		logger.Warn("SCALING-HISTORY: Write queue full, dropping scaling decision", map[string]interface{}{
+			"action":  record.Action,
+			"outcome": record.Outcome,
+		})
+	}
+}
+
+// List returns one page of the decisions evaluated in [from, to) (zero times leave the range open)
+func (s *ScalingDecisionService) List(from, to time.Time, page repository.PageRequest) (*repository.Page[models.ScalingDecision], error) {
+	return s.repo.FindPage(from, to, page)
+}
+
+// Get returns one decision
+func (s *ScalingDecisionService) Get(id string) (*models.ScalingDecision, error) {
+	return s.repo.FindByID(id)
+}
+
+// Start writes queued decisions and prunes old ones
+func (s *ScalingDecisionService) Start() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(scalingDecisionPruneInterval)
+		defer ticker.Stop()
+
+		s.prune()
+		for {
+			select {
+			case decision := <-s.queue:
+				s.write(decision)
+			case <-ticker.C:
+				s.prune()
+			case <-s.ctx.Done():
+				s.drain()
+				return
+			}
+		}
+	}()
+}
+
+// Stop writes the remaining decisions and halts the worker
+func (s *ScalingDecisionService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	select {
+	case <-s.done:
+	case <-time.After(scalingDecisionStopTimeout):
+		logger.Warn("SCALING-HISTORY: Timed out writing the remaining scaling decisions", nil)
+	}
+}
+
+// drain writes the decisions still queued at shutdown
+func (s *ScalingDecisionService) drain() {
+	for {
+		select {
+		case decision := <-s.queue:
+			s.write(decision)
+		default:
+			return
+		}
+	}
+}
+
+// write saves a decision
+func (s *ScalingDecisionService) write(decision models.ScalingDecision) {
+	if err := s.repo.Create(&decision); err != nil {
+		logger.Warn("SCALING-HISTORY: Failed to save scaling decision", map[string]interface{}{
+			"action": decision.Action,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// prune deletes decisions older than the retention
+func (s *ScalingDecisionService) prune() {
+	if s.retention <= 0 {
+		return
+	}
+	deleted, err := s.repo.DeleteBefore(time.Now().AddDate(0, 0, -s.retention))
+	if err != nil {
+		logger.Warn("SCALING-HISTORY: Failed to prune scaling decisions", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if deleted > 0 {
+		logger.Info("SCALING-HISTORY: Pruned scaling decisions", map[string]interface{}{
+			"deleted": deleted,
+		})
+	}
+}
+
+// scalingDecisionMigration is the stored form of a consolidation migration
+type scalingDecisionMigration struct {
+	ServerID    string `json:"server_id"`
+	ServerName  string `json:"server_name"`
+	FromNode    string `json:"from_node"`
+	ToNode      string `json:"to_node"`
+	RAMMb       int    `json:"ram_mb"`
+	PlayerCount int    `json:"player_count"`
+}
+
+// scalingDecisionRecord converts an evaluation of the scaling engine into its stored form
+func scalingDecisionRecord(decision conductor.ScalingDecision) models.ScalingDecision {
+	record := models.ScalingDecision{
+		EvaluatedAt:     decision.EvaluatedAt,
+		Action:          string(decision.Action),
+		Policy:          decision.Policy,
+		Reason:          decision.Reason,
+		ServerType:      decision.ServerType,
+		Count:           decision.Count,
+		Urgency:         string(decision.Urgency),
+		Outcome:         decision.Outcome,
+		Error:           decision.Error,
+		CapacityPercent: decision.Context.CapacityPercent,
+		Verdicts:        auditJSON(decision.Verdicts),
+		Context:         auditJSON(decision.Context),
+	}
+	if len(decision.NodeIDs) > 0 {
+		record.NodeIDs = auditJSON(decision.NodeIDs)
+	}
+	if len(decision.Migrations) > 0 {
+		migrations := make([]scalingDecisionMigration, len(decision.Migrations))
+		for i, migration := range decision.Migrations {
+			migrations[i] = scalingDecisionMigration(migration)
+		}
+		record.Migrations = auditJSON(migrations)
+	}
+	return record
+}
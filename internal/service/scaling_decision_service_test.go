@@ -0,0 +1,57 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/conductor"
+)
+
+func TestScalingDecisionRecord(t *testing.T) {
+	evaluatedAt := time.Date(2025, 3, 4, 3, 12, 0, 0, time.UTC)
+	decision := conductor.ScalingDecision{
+		EvaluatedAt: evaluatedAt,
+		Action:      conductor.ScaleActionScaleUp,
+		Policy:      "reactive",
+		Reason:      "Capacity 91.0% > 85.0%",
+		ServerType:  "cx42",
+		Count:       1,
+		Urgency:     conductor.UrgencyHigh,
+		Outcome:     conductor.ScalingOutcomeFailed,
+		Error:       "failed to provision node: quota exceeded",
+		Context:     conductor.ScalingContextSnapshot{CapacityPercent: 91},
+		Verdicts: []conductor.ScalingPolicyVerdict{
+			{Policy: "reactive", Question: conductor.ScaleActionScaleUp, Approved: true, Reason: "Capacity 91.0% > 85.0%"},
+		},
+	}
+
+	record := scalingDecisionRecord(decision)
+	if record.Action != "scale_up" || record.ServerType != "cx42" || record.Outcome != "failed" || record.Urgency != "high" {
+		t.Errorf("record = %+v, want the chosen scale-up and its failure", record)
+	}
+	if !record.EvaluatedAt.Equal(evaluatedAt) || record.CapacityPercent != 91 {
+		t.Errorf("record time/capacity = %v/%v, want %v/91", record.EvaluatedAt, record.CapacityPercent, evaluatedAt)
+	}
+	if record.NodeIDs != nil || record.Migrations != nil {
+		t.Errorf("record nodes/migrations = %s/%s, want none", record.NodeIDs, record.Migrations)
+	}
+
+	var verdicts []conductor.ScalingPolicyVerdict
+	if err := json.Unmarshal(record.Verdicts, &verdicts); err != nil || len(verdicts) != 1 || !verdicts[0].Approved {
+		t.Errorf("verdicts = %s (%v), want the approving reactive verdict", record.Verdicts, err)
+	}
+
+	decision.Action, decision.Outcome, decision.Error = conductor.ScaleActionConsolidate, conductor.ScalingOutcomeExecuted, ""
+	decision.NodeIDs = []string{"cloud-2"}
+	decision.Migrations = []conductor.Migration{{ServerID: "srv-1", FromNode: "cloud-2", ToNode: "cloud-1", RAMMb: 2048}}
+	record = scalingDecisionRecord(decision)
+
+	var migrations []map[string]interface{}
+	if err := json.Unmarshal(record.Migrations, &migrations); err != nil || len(migrations) != 1 || migrations[0]["server_id"] != "srv-1" {
+		t.Errorf("migrations = %s (%v), want srv-1 with snake_case keys", record.Migrations, err)
+	}
+	if string(record.NodeIDs) != `["cloud-2"]` {
+		t.Errorf("node IDs = %s, want [\"cloud-2\"]", record.NodeIDs)
+	}
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// Limits of the scaling policy settings
+const (
+	minScalingCheckInterval = 30 * time.Second
+	maxScalingCheckInterval = time.Hour
+	minScalingCooldown      = time.Minute
+	maxScalingCooldown      = 24 * time.Hour
+	maxScalingCloudNodes    = 100
+	maxPreferredServerTypes = 10
+)
+
+// ErrScalingPolicyInvalid is wrapped by the errors of invalid scaling policy configs
+var ErrScalingPolicyInvalid = errors.New("invalid scaling policy config")
+
+// scalingPolicyScopes are the configurable parts of the scaling engine, in display order
+var scalingPolicyScopes = []string{models.ScalingScopeEngine, models.ScalingScopeReactive, models.ScalingScopeConsolidation}
+
+// serverTypeNamePattern matches cloud server type names like cpx42
+var serverTypeNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// ScalingPolicyView is the configuration of a scaling policy as shown to admins
+type ScalingPolicyView struct {
+	Policy    string                      `json:"policy"`
+	Override  *models.ScalingPolicyConfig `json:"override"`  // nil = defaults
+	Effective models.ScalingPolicyConfig  `json:"effective"` // Override on top of the defaults
+}
+
+// ScalingPolicyService keeps the admin overrides of the scaling settings (thresholds, node limits,
+// cooldowns, interval, preferred server types) and applies them to the running scaling engine.
+// Defaults are the built-in values with the SCALING_* environment settings on top.
+type ScalingPolicyService struct {
+	repo      *repository.ScalingPolicyConfigRepository
+	conductor *conductor.Conductor
+	defaults  map[string]models.ScalingPolicyConfig
+
+	mu sync.Mutex // Serializes changes, so the engine gets the settings of the last one
+}
+
+// NewScalingPolicyService creates a new scaling policy service
+func NewScalingPolicyService(repo *repository.ScalingPolicyConfigRepository, cond *conductor.Conductor, cfg *config.Config) *ScalingPolicyService {
+	defaults := conductor.DefaultScalingSettings()
+	if interval, err := time.ParseDuration(cfg.ScalingCheckInterval); err == nil && interval > 0 {
+		defaults.CheckInterval = interval
+	}
+	if cfg.ScalingScaleUpThreshold > 0 {
+		defaults.Reactive.ScaleUpThreshold = cfg.ScalingScaleUpThreshold
+	}
+	if cfg.ScalingScaleDownThreshold > 0 {
+		defaults.Reactive.ScaleDownThreshold = cfg.ScalingScaleDownThreshold
+	}
+	if cfg.ScalingMaxCloudNodes > 0 {
+		defaults.Reactive.MaxCloudNodes = cfg.ScalingMaxCloudNodes
+	}
+
+	return &ScalingPolicyService{
+		repo:      repo,
+		conductor: cond,
+		defaults:  scalingPolicyDefaults(defaults),
+	}
+}
+
+// List returns the override and the effective settings of every scope
+func (s *ScalingPolicyService) List() ([]ScalingPolicyView, error) {
+	overrides, err := s.overrides()
+	if err != nil {
+		return nil, err
+	}
+	views := make([]ScalingPolicyView, 0, len(scalingPolicyScopes))
+	for _, scope := range scalingPolicyScopes {
+		views = append(views, s.view(scope, overrides[scope]))
+	}
+	return views, nil
+}
+
+// Get returns the override of a scope, nil if it uses the defaults
+func (s *ScalingPolicyService) Get(policy string) (*models.ScalingPolicyConfig, error) {
+	if _, ok := s.defaults[policy]; !ok {
+		return nil, fmt.Errorf("%w: unknown policy %q", ErrScalingPolicyInvalid, policy)
+	}
+	return s.repo.FindByPolicy(policy)
+}
+
+// Save validates and creates or replaces the override of a scope, then applies it to the engine
+func (s *ScalingPolicyService) Save(policy, userID string, override *models.ScalingPolicyConfig) (*ScalingPolicyView, error) {
+	defaults, ok := s.defaults[policy]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown policy %q", ErrScalingPolicyInvalid, policy)
+	}
+	override.Policy = policy
+	if err := validateScalingPolicyConfig(override, mergeScalingPolicyConfig(defaults, override)); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.repo.FindByPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		override.CreatedAt = existing.CreatedAt
+	}
+	override.UpdatedBy = userID
+	if err := s.repo.Save(override); err != nil {
+		return nil, err
+	}
+	if err := s.apply(); err != nil {
+		return nil, err
+	}
+
+	view := s.view(policy, override)
+	return &view, nil
+}
+
+// Delete removes the override of a scope (the defaults apply again)
+func (s *ScalingPolicyService) Delete(policy string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.repo.Delete(policy); err != nil {
+		return err
+	}
+	return s.apply()
+}
+
+// Apply hands the effective settings to the scaling engine (at startup, before the engine starts)
+func (s *ScalingPolicyService) Apply() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.apply()
+}
+
+func (s *ScalingPolicyService) apply() error {
+	overrides, err := s.overrides()
+	if err != nil {
+		return err
+	}
+	effective := make(map[string]models.ScalingPolicyConfig, len(s.defaults))
+	for scope, defaults := range s.defaults {
+		effective[scope] = mergeScalingPolicyConfig(defaults, overrides[scope])
+	}
+
+	if s.conductor == nil || s.conductor.ScalingEngine == nil {
+		logger.Debug("SCALING-POLICY: Scaling engine not initialized, settings are kept for later", nil)
+		return nil
+	}
+	s.conductor.ScalingEngine.ApplySettings(scalingSettings(effective))
+	return nil
+}
+
+// overrides loads the overrides by scope
+func (s *ScalingPolicyService) overrides() (map[string]*models.ScalingPolicyConfig, error) {
+	configs, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scaling policy configs: %w", err)
+	}
+	overrides := make(map[string]*models.ScalingPolicyConfig, len(configs))
+	for i := range configs {
+		overrides[configs[i].Policy] = &configs[i]
+	}
+	return overrides, nil
+}
+
+func (s *ScalingPolicyService) view(scope string, override *models.ScalingPolicyConfig) ScalingPolicyView {
+	return ScalingPolicyView{
+		Policy:    scope,
+		Override:  override,
+		Effective: mergeScalingPolicyConfig(s.defaults[scope], override),
+	}
+}
+
+// scalingPolicyDefaults returns the settings of every scope as fully populated configs
+func scalingPolicyDefaults(settings conductor.ScalingSettings) map[string]models.ScalingPolicyConfig {
+	reactive, consolidation := settings.Reactive, settings.Consolidation
+	preferred := strings.Join(reactive.PreferredServerTypes, ",")
+	return map[string]models.ScalingPolicyConfig{
+		models.ScalingScopeEngine: {
+			Policy:               models.ScalingScopeEngine,
+			CheckIntervalSeconds: intPtr(int(settings.CheckInterval / time.Second)),
+		},
+		models.ScalingScopeReactive: {
+			Policy:               models.ScalingScopeReactive,
+			Enabled:              &reactive.Enabled,
+			CooldownSeconds:      intPtr(int(reactive.Cooldown / time.Second)),
+			ScaleUpThreshold:     &reactive.ScaleUpThreshold,
+			ScaleDownThreshold:   &reactive.ScaleDownThreshold,
+			MinCloudNodes:        &reactive.MinCloudNodes,
+			MaxCloudNodes:        &reactive.MaxCloudNodes,
+			PreferredServerTypes: &preferred,
+		},
+		models.ScalingScopeConsolidation: {
+			Policy:                    models.ScalingScopeConsolidation,
+			Enabled:                   &consolidation.Enabled,
+			CooldownSeconds:           intPtr(int(consolidation.Cooldown / time.Second)),
+			MaxCapacityPercent:        &consolidation.MaxCapacityPercent,
+			MinNodeSavings:            &consolidation.MinNodeSavings,
+			AllowMigrationWithPlayers: &consolidation.AllowMigrationWithPlayers,
+		},
+	}
+}
+
+// mergeScalingPolicyConfig returns base with the set fields of override on top
+func mergeScalingPolicyConfig(base models.ScalingPolicyConfig, override *models.ScalingPolicyConfig) models.ScalingPolicyConfig {
+	if override == nil {
+		return base
+	}
+	merged := base
+	if override.CheckIntervalSeconds != nil {
+		merged.CheckIntervalSeconds = override.CheckIntervalSeconds
+	}
+	if override.Enabled != nil {
+		merged.Enabled = override.Enabled
+	}
+	if override.CooldownSeconds != nil {
+		merged.CooldownSeconds = override.CooldownSeconds
+	}
+	if override.ScaleUpThreshold != nil {
+		merged.ScaleUpThreshold = override.ScaleUpThreshold
+	}
+	if override.ScaleDownThreshold != nil {
+		merged.ScaleDownThreshold = override.ScaleDownThreshold
+	}
+	if override.MinCloudNodes != nil {
+		merged.MinCloudNodes = override.MinCloudNodes
+	}
+	if override.MaxCloudNodes != nil {
+		merged.MaxCloudNodes = override.MaxCloudNodes
+	}
+	if override.PreferredServerTypes != nil {
+		merged.PreferredServerTypes = override.PreferredServerTypes
+	}
+	if override.MaxCapacityPercent != nil {
+		merged.MaxCapacityPercent = override.MaxCapacityPercent
+	}
+	if override.MinNodeSavings != nil {
+		merged.MinNodeSavings = override.MinNodeSavings
+	}
+	if override.AllowMigrationWithPlayers != nil {
+		merged.AllowMigrationWithPlayers = override.AllowMigrationWithPlayers
+	}
+	merged.UpdatedBy, merged.CreatedAt, merged.UpdatedAt = override.UpdatedBy, override.CreatedAt, override.UpdatedAt
+	return merged
+}
+
+// validateScalingPolicyConfig checks an override against its scope and the resulting settings
+// Preferred server types are normalized (trimmed, lowercase, no duplicates).
+func validateScalingPolicyConfig(override *models.ScalingPolicyConfig, effective models.ScalingPolicyConfig) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrScalingPolicyInvalid, fmt.Sprintf(format, args...))
+	}
+
+	// Fields of other scopes
+	engineOnly := override.CheckIntervalSeconds != nil
+	reactiveOnly := override.ScaleUpThreshold != nil || override.ScaleDownThreshold != nil ||
+		override.MinCloudNodes != nil || override.MaxCloudNodes != nil || override.PreferredServerTypes != nil
+	consolidationOnly := override.MaxCapacityPercent != nil || override.MinNodeSavings != nil || override.AllowMigrationWithPlayers != nil
+	policyFields := override.Enabled != nil || override.CooldownSeconds != nil
+	switch override.Policy {
+	case models.ScalingScopeEngine:
+		if reactiveOnly || consolidationOnly || policyFields {
+			return invalid("engine only has check_interval_seconds")
+		}
+	case models.ScalingScopeReactive:
+		if engineOnly || consolidationOnly {
+			return invalid("field does not apply to the reactive policy")
+		}
+	case models.ScalingScopeConsolidation:
+		if engineOnly || reactiveOnly {
+			return invalid("field does not apply to the consolidation policy")
+		}
+	default:
+		return invalid("unknown policy %q", override.Policy)
+	}
+
+	if v := override.CheckIntervalSeconds; v != nil {
+		if interval := time.Duration(*v) * time.Second; interval < minScalingCheckInterval || interval > maxScalingCheckInterval {
+			return invalid("check_interval_seconds must be between %d and %d", int(minScalingCheckInterval.Seconds()), int(maxScalingCheckInterval.Seconds()))
+		}
+	}
+	if v := override.CooldownSeconds; v != nil {
+		if cooldown := time.Duration(*v) * time.Second; cooldown < minScalingCooldown || cooldown > maxScalingCooldown {
+			return invalid("cooldown_seconds must be between %d and %d", int(minScalingCooldown.Seconds()), int(maxScalingCooldown.Seconds()))
+		}
+	}
+	for name, v := range map[string]*float64{
+		"scale_up_threshold":   override.ScaleUpThreshold,
+		"scale_down_threshold": override.ScaleDownThreshold,
+		"max_capacity_percent": override.MaxCapacityPercent,
+	} {
+		if v != nil && (*v <= 0 || *v > 100) {
+			return invalid("%s must be a percentage above 0 and at most 100", name)
+		}
+	}
+	if v := override.MinCloudNodes; v != nil && (*v < 0 || *v > maxScalingCloudNodes) {
+		return invalid("min_cloud_nodes must be between 0 and %d", maxScalingCloudNodes)
+	}
+	if v := override.MaxCloudNodes; v != nil && (*v < 1 || *v > maxScalingCloudNodes) {
+		return invalid("max_cloud_nodes must be between 1 and %d", maxScalingCloudNodes)
+	}
+	if v := override.MinNodeSavings; v != nil && *v < 1 {
+		return invalid("min_node_savings must be at least 1")
+	}
+	if v := override.PreferredServerTypes; v != nil {
+		types, err := parsePreferredServerTypes(*v)
+		if err != nil {
+			return invalid("%s", err.Error())
+		}
+		normalized := strings.Join(types, ",")
+		override.PreferredServerTypes = &normalized
+	}
+
+	// Combined with the defaults
+	if override.Policy == models.ScalingScopeReactive {
+		if *effective.ScaleDownThreshold >= *effective.ScaleUpThreshold {
+			return invalid("scale_down_threshold (%.1f) must be below scale_up_threshold (%.1f)", *effective.ScaleDownThreshold, *effective.ScaleUpThreshold)
+		}
+		if *effective.MinCloudNodes > *effective.MaxCloudNodes {
+			return invalid("min_cloud_nodes (%d) must not exceed max_cloud_nodes (%d)", *effective.MinCloudNodes, *effective.MaxCloudNodes)
+		}
+	}
+	return nil
+}
+
+// parsePreferredServerTypes splits a comma-separated list of server type names
+func parsePreferredServerTypes(value string) ([]string, error) {
+	types := []string{}
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if !serverTypeNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid server type %q", name)
+		}
+		seen[name] = true
+		types = append(types, name)
+	}
+	if len(types) > maxPreferredServerTypes {
+		return nil, fmt.Errorf("at most %d preferred server types", maxPreferredServerTypes)
+	}
+	return types, nil
+}
+
+// scalingSettings turns the effective configs of all scopes into the engine settings
+func scalingSettings(effective map[string]models.ScalingPolicyConfig) conductor.ScalingSettings {
+	engine := effective[models.ScalingScopeEngine]
+	reactive := effective[models.ScalingScopeReactive]
+	consolidation := effective[models.ScalingScopeConsolidation]
+
+	preferred, _ := parsePreferredServerTypes(*reactive.PreferredServerTypes) // Validated on save
+	return conductor.ScalingSettings{
+		CheckInterval: time.Duration(*engine.CheckIntervalSeconds) * time.Second,
+		Reactive: conductor.ReactiveSettings{
+			Enabled:              *reactive.Enabled,
+			ScaleUpThreshold:     *reactive.ScaleUpThreshold,
+			ScaleDownThreshold:   *reactive.ScaleDownThreshold,
+			Cooldown:             time.Duration(*reactive.CooldownSeconds) * time.Second,
+			MinCloudNodes:        *reactive.MinCloudNodes,
+			MaxCloudNodes:        *reactive.MaxCloudNodes,
+			PreferredServerTypes: preferred,
+		},
+		Consolidation: conductor.ConsolidationSettings{
+			Enabled:                   *consolidation.Enabled,
+			Cooldown:                  time.Duration(*consolidation.CooldownSeconds) * time.Second,
+			MaxCapacityPercent:        *consolidation.MaxCapacityPercent,
+			MinNodeSavings:            *consolidation.MinNodeSavings,
+			AllowMigrationWithPlayers: *consolidation.AllowMigrationWithPlayers,
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestValidateScalingPolicyConfig(t *testing.T) {
+	defaults := scalingPolicyDefaults(conductor.DefaultScalingSettings())
+	float := func(v float64) *float64 { return &v }
+	text := func(v string) *string { return &v }
+	enabled := true
+
+	tests := []struct {
+		name   string
+		config models.ScalingPolicyConfig
+		valid  bool
+	}{
+		{"engine interval", models.ScalingPolicyConfig{Policy: models.ScalingScopeEngine, CheckIntervalSeconds: intPtr(60)}, true},
+		{"engine interval too short", models.ScalingPolicyConfig{Policy: models.ScalingScopeEngine, CheckIntervalSeconds: intPtr(5)}, false},
+		{"engine has no cooldown", models.ScalingPolicyConfig{Policy: models.ScalingScopeEngine, CooldownSeconds: intPtr(600)}, false},
+		{"reactive thresholds", models.ScalingPolicyConfig{Policy: models.ScalingScopeReactive, ScaleUpThreshold: float(90), ScaleDownThreshold: float(20)}, true},
+		{"reactive down above default up", models.ScalingPolicyConfig{Policy: models.ScalingScopeReactive, ScaleDownThreshold: float(90)}, false},
+		{"reactive threshold above 100", models.ScalingPolicyConfig{Policy: models.ScalingScopeReactive, ScaleUpThreshold: float(120)}, false},
+		{"reactive min above max", models.ScalingPolicyConfig{Policy: models.ScalingScopeReactive, MinCloudNodes: intPtr(3), MaxCloudNodes: intPtr(2)}, false},
+		{"reactive server types", models.ScalingPolicyConfig{Policy: models.ScalingScopeReactive, PreferredServerTypes: text("cpx42, cx32")}, true},
+		{"reactive bad server type", models.ScalingPolicyConfig{Policy: models.ScalingScopeReactive, PreferredServerTypes: text("cx32;rm")}, false},
+		{"reactive has no max capacity", models.ScalingPolicyConfig{Policy: models.ScalingScopeReactive, MaxCapacityPercent: float(60)}, false},
+		{"consolidation enabled", models.ScalingPolicyConfig{Policy: models.ScalingScopeConsolidation, Enabled: &enabled, MinNodeSavings: intPtr(2)}, true},
+		{"consolidation cooldown too short", models.ScalingPolicyConfig{Policy: models.ScalingScopeConsolidation, CooldownSeconds: intPtr(10)}, false},
+		{"consolidation has no node limits", models.ScalingPolicyConfig{Policy: models.ScalingScopeConsolidation, MaxCloudNodes: intPtr(4)}, false},
+		{"unknown policy", models.ScalingPolicyConfig{Policy: "predictive"}, false},
+	}
+	for _, tt := range tests {
+		config := tt.config
+		err := validateScalingPolicyConfig(&config, mergeScalingPolicyConfig(defaults[config.Policy], &config))
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: validateScalingPolicyConfig() = %v, want valid=%v", tt.name, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrScalingPolicyInvalid) {
+			t.Errorf("%s: error %v does not wrap ErrScalingPolicyInvalid", tt.name, err)
+		}
+	}
+
+	config := models.ScalingPolicyConfig{Policy: models.ScalingScopeReactive, PreferredServerTypes: text(" CPX42,cx32,cpx42 ")}
+	if err := validateScalingPolicyConfig(&config, mergeScalingPolicyConfig(defaults[config.Policy], &config)); err != nil || *config.PreferredServerTypes != "cpx42,cx32" {
+		t.Errorf("preferred server types = %q (%v), want normalized \"cpx42,cx32\"", *config.PreferredServerTypes, err)
+	}
+}
+
+func TestScalingSettings(t *testing.T) {
+	defaults := scalingPolicyDefaults(conductor.DefaultScalingSettings())
+	disabled := false
+	effective := map[string]models.ScalingPolicyConfig{
+		models.ScalingScopeEngine: mergeScalingPolicyConfig(defaults[models.ScalingScopeEngine],
+			&models.ScalingPolicyConfig{CheckIntervalSeconds: intPtr(300)}),
+		models.ScalingScopeReactive: mergeScalingPolicyConfig(defaults[models.ScalingScopeReactive],
+			&models.ScalingPolicyConfig{MaxCloudNodes: intPtr(4), Enabled: &disabled}),
+		models.ScalingScopeConsolidation: mergeScalingPolicyConfig(defaults[models.ScalingScopeConsolidation], nil),
+	}
+
+	settings := scalingSettings(effective)
+	want := conductor.DefaultScalingSettings()
+	if settings.CheckInterval != 5*time.Minute {
+		t.Errorf("check interval = %s, want 5m", settings.CheckInterval)
+	}
+	if settings.Reactive.Enabled || settings.Reactive.MaxCloudNodes != 4 || settings.Reactive.ScaleUpThreshold != want.Reactive.ScaleUpThreshold {
+		t.Errorf("reactive = %+v, want disabled, 4 nodes and the default thresholds", settings.Reactive)
+	}
+	if len(settings.Reactive.PreferredServerTypes) != 0 {
+		t.Errorf("preferred server types = %v, want none", settings.Reactive.PreferredServerTypes)
+	}
+	if settings.Consolidation != want.Consolidation {
+		t.Errorf("consolidation = %+v, want the defaults %+v", settings.Consolidation, want.Consolidation)
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/secrets"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// secretsDefaultRefreshInterval applies if SECRETS_REFRESH_INTERVAL is not a valid duration
+	secretsDefaultRefreshInterval = 5 * time.Minute
+	// secretsFetchTimeout bounds one read of the secret store
+	secretsFetchTimeout = 30 * time.Second
+)
+
+// SecretsService reads credentials from a secret store (Vault or a SOPS-encrypted file) instead of
+// plain env vars and re-reads them every SECRETS_REFRESH_INTERVAL. When a secret is rotated, the
+// listeners registered with OnRotate pass the new value on (SSH pool, Hetzner client, InfluxDB),
+// so rotation needs no restart. If the store is unreachable the last values stay in use.
+// All methods are safe on a nil *SecretsService (no store configured, env vars are used).
+type SecretsService struct {
+	provider secrets.Provider
+	interval time.Duration
+
+	mu        sync.RWMutex
+	values    map[string]string
+	listeners map[string][]func(value string)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSecretsService creates a new secrets service reading from provider
+func NewSecretsService(provider secrets.Provider, cfg *config.Config) *SecretsService {
+	interval, err := time.ParseDuration(cfg.SecretsRefreshInterval)
+	if err != nil || interval <= 0 {
+		interval = secretsDefaultRefreshInterval
+	}
+	return &SecretsService{
+		provider:  provider,
+		interval:  interval,
+		values:    make(map[string]string),
+		listeners: make(map[string][]func(value string)),
+	}
+}
+
+// Load reads the secrets for the first time and copies the tokens into cfg, so services
+// created afterwards use them like env vars
+func (s *SecretsService) Load(cfg *config.Config) error {
+	if s == nil {
+		return nil
+	}
+	if _, err := s.refresh(); err != nil {
+		return err
+	}
+
+	if token, ok := s.Get(secrets.HetznerCloudToken); ok {
+		cfg.HetznerCloudToken = token
+	}
+	if token, ok := s.Get(secrets.InfluxDBToken); ok {
+		cfg.InfluxDBToken = token
+	}
+	s.mu.RLock()
+	logger.Info("SECRETS: Loaded secrets from store", map[string]interface{}{
+		"provider": s.provider.Name(),
+		"keys":     len(s.values),
+	})
+	s.mu.RUnlock()
+	return nil
+}
+
+// Get returns the current value of a secret
+func (s *SecretsService) Get(key string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok && value != ""
+}
+
+// OnRotate registers fn to be called with the new value whenever the secret key changes
+func (s *SecretsService) OnRotate(key string, fn func(value string)) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners[key] = append(s.listeners[key], fn)
+}
+
+// Start re-reads the secrets periodically
+func (s *SecretsService) Start() {
+	if s == nil {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.refresh(); err != nil {
+					logger.Warn("SECRETS: Failed to refresh secrets, keeping the current values", map[string]interface{}{
+						"provider": s.provider.Name(),
+						"error":    err.Error(),
+					})
+				}
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the refresh loop
+func (s *SecretsService) Stop() {
+	if s == nil || s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// refresh reads the store and notifies the listeners of changed secrets; returns the changed keys
+func (s *SecretsService) refresh() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretsFetchTimeout)
+	defer cancel()
+	values, err := s.provider.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type rotation struct {
+		key       string
+		value     string
+		listeners []func(value string)
+	}
+	var rotations []rotation
+	s.mu.Lock()
+	first := len(s.values) == 0
+	for key, value := range values {
+		if old, ok := s.values[key]; ok && old == value {
+			continue
+		}
+		if !first && value != "" {
+			rotations = append(rotations, rotation{key: key, value: value, listeners: s.listeners[key]})
+		}
+	}
+	s.values = values
+	s.mu.Unlock()
+
+	// Listeners run outside the lock, they may call Get
+	changed := make([]string, 0, len(rotations))
+	for _, r := range rotations {
+		logger.Info("SECRETS: Secret rotated", map[string]interface{}{
+			"provider":  s.provider.Name(),
+			"key":       r.key,
+			"listeners": len(r.listeners),
+		})
+		for _, listener := range r.listeners {
+			listener(r.value)
+		}
+		changed = append(changed, r.key)
+	}
+	return changed, nil
+}
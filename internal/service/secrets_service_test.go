@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/payperplay/hosting/internal/secrets"
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+func TestSecretsServiceRotatesFromVault(t *testing.T) {
+	token := "token-1"
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" || r.URL.Path != "/v1/secret/data/payperplay" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"HETZNER_CLOUD_TOKEN":"` + token + `","INFLUXDB_TOKEN":"influx"},"metadata":{"version":1}}}`))
+	}))
+	defer vault.Close()
+
+	cfg := &config.Config{SecretsRefreshInterval: "1m", HetznerCloudToken: "from-env"}
+	s := NewSecretsService(secrets.NewVaultProvider(vault.URL, "root", "secret/data/payperplay"), cfg)
+	if err := s.Load(cfg); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.HetznerCloudToken != "token-1" || cfg.InfluxDBToken != "influx" {
+		t.Errorf("tokens not copied into the config: hetzner %q, influx %q", cfg.HetznerCloudToken, cfg.InfluxDBToken)
+	}
+
+	var rotated []string
+	s.OnRotate(secrets.HetznerCloudToken, func(value string) { rotated = append(rotated, value) })
+
+	if changed, err := s.refresh(); err != nil || len(changed) != 0 || len(rotated) != 0 {
+		t.Errorf("refresh without rotation: changed %v, rotated %v, err %v", changed, rotated, err)
+	}
+
+	token = "token-2"
+	if changed, err := s.refresh(); err != nil || len(changed) != 1 {
+		t.Fatalf("refresh after rotation: changed %v, err %v", changed, err)
+	}
+	if len(rotated) != 1 || rotated[0] != "token-2" {
+		t.Errorf("listener got %v, want [token-2]", rotated)
+	}
+	if value, _ := s.Get(secrets.HetznerCloudToken); value != "token-2" {
+		t.Errorf("Get = %q, want token-2", value)
+	}
+
+	// A denied read is an error, so the refresh loop keeps the current values
+	denied := secrets.NewVaultProvider(vault.URL, "wrong", "secret/data/payperplay")
+	if _, err := denied.Fetch(context.Background()); err == nil {
+		t.Error("Fetch with a wrong token succeeded")
+	}
+}
@@ -3,15 +3,23 @@ package service
 import (
 	"time"
 
+	"github.com/payperplay/hosting/internal/events"
 	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/pkg/logger"
 	"gorm.io/gorm"
 )
 
+// TwoFactorVerifier checks second factors (implemented by TwoFactorService)
+type TwoFactorVerifier interface {
+	IsEnabled(userID string) bool
+	VerifyCode(userID, code, ipAddress, userAgent string) error
+}
+
 // SecurityService manages device trust and security events
 type SecurityService struct {
 	db           *gorm.DB
 	emailService *EmailService
+	twoFactor    TwoFactorVerifier
 }
 
 // NewSecurityService creates a new security service
@@ -22,6 +30,32 @@ func NewSecurityService(db *gorm.DB, emailService *EmailService) *SecurityServic
 	}
 }
 
+// SetTwoFactorVerifier enables 2FA enforcement for sensitive operations
+func (s *SecurityService) SetTwoFactorVerifier(verifier TwoFactorVerifier) {
+	s.twoFactor = verifier
+}
+
+// CheckSensitiveOperation requires a valid 2FA code for op if the user has 2FA enabled
+// Users without 2FA pass; an empty code returns ErrTwoFactorRequired so clients can prompt for one.
+func (s *SecurityService) CheckSensitiveOperation(userID string, op models.SensitiveOperation, code, ipAddress, userAgent string) error {
+	if s.twoFactor == nil || !s.twoFactor.IsEnabled(userID) {
+		return nil
+	}
+	if code == "" {
+		return models.ErrTwoFactorRequired
+	}
+
+	if err := s.twoFactor.VerifyCode(userID, code, ipAddress, userAgent); err != nil {
+		logger.Warn("SECURITY: 2FA check failed for sensitive operation", map[string]interface{}{
+			"user_id":   userID,
+			"operation": op,
+			"error":     err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
 // CheckTrustedDevice checks if a device is trusted for this user
 func (s *SecurityService) CheckTrustedDevice(userID, userAgent, ipAddress string) (*models.TrustedDevice, bool) {
 	deviceID := models.GenerateDeviceID(userAgent, ipAddress)
@@ -114,6 +148,7 @@ func (s *SecurityService) LogSecurityEvent(userID string, eventType models.Secur
 		"event_type": eventType,
 		"success":    success,
 	})
+	events.PublishSecurityEvent(userID, string(eventType), ipAddress, success, reason)
 
 	return nil
 }
@@ -144,6 +179,11 @@ func (s *SecurityService) SendPasswordChangedAlert(user *models.User) error {
 	return s.emailService.SendPasswordChangedAlert(user.Email, user.Username)
 }
 
+// SendOAuthRefreshFailedAlert sends an email alert when a linked provider token can't be refreshed
+func (s *SecurityService) SendOAuthRefreshFailedAlert(user *models.User, provider models.OAuthProviderType) error {
+	return s.emailService.SendOAuthRefreshFailedAlert(user.Email, user.Username, string(provider))
+}
+
 // CleanupExpiredDevices removes expired trusted devices (runs periodically)
 func (s *SecurityService) CleanupExpiredDevices() error {
 	result := s.db.Where("expires_at < ? OR is_active = ?", time.Now(), false).
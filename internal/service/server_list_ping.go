@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// maxStatusResponse bounds the status JSON a server may send (favicons make it a few KB)
+const maxStatusResponse = 256 * 1024
+
+// ServerListStatus is the status response of a Minecraft server list ping
+type ServerListStatus struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int    `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Max    int `json:"max"`
+		Online int `json:"online"`
+	} `json:"players"`
+	Latency time.Duration `json:"-"`
+}
+
+// pingServerList performs a Server List Ping (the status request the multiplayer screen sends)
+// It answers whether the Minecraft server itself accepts connections, independent of the proxy.
+func pingServerList(address string, timeout time.Duration) (*ServerListStatus, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", portStr)
+	}
+
+	started := time.Now()
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// Handshake (protocol version -1 = status query) followed by the status request
+	var handshake bytes.Buffer
+	writeVarInt(&handshake, 0x00)
+	writeVarInt(&handshake, -1)
+	writeVarInt(&handshake, int32(len(host)))
+	handshake.WriteString(host)
+	binary.Write(&handshake, binary.BigEndian, uint16(port))
+	writeVarInt(&handshake, 1)
+
+	var request bytes.Buffer
+	writeVarInt(&request, int32(handshake.Len()))
+	request.Write(handshake.Bytes())
+	request.Write([]byte{0x01, 0x00})
+	if _, err := conn.Write(request.Bytes()); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	if _, err := readVarInt(reader); err != nil { // Packet length
+		return nil, err
+	}
+	packetID, err := readVarInt(reader)
+	if err != nil {
+		return nil, err
+	}
+	if packetID != 0x00 {
+		return nil, fmt.Errorf("unexpected packet id %d", packetID)
+	}
+	length, err := readVarInt(reader)
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 || length > maxStatusResponse {
+		return nil, fmt.Errorf("invalid status length %d", length)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, err
+	}
+
+	status := &ServerListStatus{Latency: time.Since(started)}
+	if err := json.Unmarshal(payload, status); err != nil {
+		return nil, fmt.Errorf("invalid status response: %w", err)
+	}
+	return status, nil
+}
+
+func writeVarInt(buf *bytes.Buffer, value int32) {
+	v := uint32(value)
+	for {
+		if v&^0x7F == 0 {
+			buf.WriteByte(byte(v))
+			return
+		}
+		buf.WriteByte(byte(v&0x7F | 0x80))
+		v >>= 7
+	}
+}
+
+func readVarInt(r io.ByteReader) (int32, error) {
+	var value uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint32(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, errors.New("varint too long")
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// withPlacementPeers adds the nodes running the server's peers (anti-affinity group or spread) to its placement
+// Without the owner's servers the placement is returned as is: the server then starts, just not spread.
+func withPlacementPeers(serverRepo *repository.ServerRepository, server *models.MinecraftServer, placement models.NodePlacement) models.NodePlacement {
+	if !placement.SpreadsPeers() {
+		return placement
+	}
+
+	ownerServers, err := serverRepo.FindByOwner(server.OwnerID)
+	if err != nil {
+		logger.Warn("Placement: failed to load the owner's servers, placing without anti-affinity", map[string]interface{}{
+			"server_id": server.ID,
+			"owner_id":  server.OwnerID,
+			"error":     err.Error(),
+		})
+		return placement
+	}
+	placement.Peers = placementPeers(server, ownerServers)
+	return placement
+}
+
+// placementPeers counts the peers of server per node among the owner's servers
+// Only servers holding a node (starting or running) count; a stopped server gets placed anew on start.
+func placementPeers(server *models.MinecraftServer, ownerServers []models.MinecraftServer) map[string]int {
+	peers := make(map[string]int)
+	for i := range ownerServers {
+		other := &ownerServers[i]
+		if other.NodeID == "" || (other.Status != models.StatusRunning && other.Status != models.StatusStarting) {
+			continue
+		}
+		if server.IsPlacementPeer(other) {
+			peers[other.NodeID]++
+		}
+	}
+	return peers
+}
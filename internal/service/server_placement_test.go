@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestPlacementPeers(t *testing.T) {
+	ownerServers := []models.MinecraftServer{
+		{ID: "lobby", OwnerID: "owner", AntiAffinityGroup: "network", NodeID: "node-a", Status: models.StatusRunning},
+		{ID: "game-1", OwnerID: "owner", AntiAffinityGroup: "network", NodeID: "node-b", Status: models.StatusStarting},
+		{ID: "game-2", OwnerID: "owner", AntiAffinityGroup: "network", NodeID: "node-b", Status: models.StatusRunning},
+		{ID: "game-3", OwnerID: "owner", AntiAffinityGroup: "network", NodeID: "node-c", Status: models.StatusStopped},
+		{ID: "creative", OwnerID: "owner", NodeID: "node-c", Status: models.StatusRunning},
+		{ID: "other", OwnerID: "someone-else", AntiAffinityGroup: "network", NodeID: "node-c", Status: models.StatusRunning},
+	}
+
+	grouped := &models.MinecraftServer{ID: "game-1", OwnerID: "owner", AntiAffinityGroup: "network"}
+	peers := placementPeers(grouped, ownerServers)
+	if len(peers) != 2 || peers["node-a"] != 1 || peers["node-b"] != 1 {
+		t.Errorf("group peers = %v, want node-a: 1, node-b: 1 (not itself, stopped servers, other groups or owners)", peers)
+	}
+
+	spread := &models.MinecraftServer{ID: "new", OwnerID: "owner", SpreadAcrossNodes: true}
+	peers = placementPeers(spread, ownerServers)
+	if len(peers) != 3 || peers["node-a"] != 1 || peers["node-b"] != 2 || peers["node-c"] != 1 {
+		t.Errorf("spread peers = %v, want node-a: 1, node-b: 2, node-c: 1", peers)
+	}
+
+	if peers := placementPeers(&models.MinecraftServer{ID: "new", OwnerID: "owner"}, ownerServers); len(peers) != 0 {
+		t.Errorf("unconstrained peers = %v, want none", peers)
+	}
+}
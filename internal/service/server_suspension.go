@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// Suspension sources
+const (
+	SuspensionSourceAdmin   = "admin"   // Abuse or manual hold by an admin
+	SuspensionSourceBilling = "billing" // Owner suspended after repeated failed payments, lifted on payment
+)
+
+var (
+	// ErrServerSuspended is wrapped by the error returned when a suspended server is started
+	ErrServerSuspended = errors.New("server suspended")
+
+	ErrServerNotSuspended      = errors.New("server is not suspended")
+	ErrServerAlreadySuspended  = errors.New("server is already suspended")
+	ErrSuspensionReasonMissing = errors.New("a suspension reason is required")
+	ErrSuspensionInTransition  = errors.New("server is changing state, try again once it has settled")
+)
+
+// ServerSuspendedError rejects an action on a suspended server with the reason of the suspension
+type ServerSuspendedError struct {
+	Reason string
+}
+
+func (e *ServerSuspendedError) Error() string {
+	return "server is suspended: " + e.Reason
+}
+
+// Unwrap makes errors.Is(err, ErrServerSuspended) match
+func (e *ServerSuspendedError) Unwrap() error { return ErrServerSuspended }
+
+// checkNotSuspended returns a ServerSuspendedError if the server is suspended
+func checkNotSuspended(server *models.MinecraftServer) error {
+	if server.Status != models.StatusSuspended {
+		return nil
+	}
+	return &ServerSuspendedError{Reason: server.SuspensionReason}
+}
+
+// statusAfterSuspension is the status a server returns to when its suspension is lifted
+// Sleeping and archived servers keep their files where they were; everything else is stopped.
+func statusAfterSuspension(status models.ServerStatus) models.ServerStatus {
+	switch status {
+	case models.StatusSleeping, models.StatusArchived:
+		return status
+	default:
+		return models.StatusStopped
+	}
+}
+
+// SuspendServer puts a server on hold (abuse, billing): a running server is stopped, a queued one
+// leaves the start queue, and the server is unregistered from Velocity. Files, backups and archives
+// are kept. Starts and restores are rejected with reason until UnsuspendServer is called.
+// suspendedBy is the admin user ID (empty for billing automation).
+func (s *MinecraftService) SuspendServer(ctx context.Context, serverID, reason, source, suspendedBy string) (*models.MinecraftServer, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrSuspensionReasonMissing
+	}
+
+	// Starts take the same lock, so none can slip in between the stop and the suspension
+	mu := s.acquireOperationLock(serverID)
+	defer s.releaseOperationLock(serverID, mu)
+
+	server, err := s.repo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+
+	switch server.Status {
+	case models.StatusSuspended:
+		return nil, ErrServerAlreadySuspended
+	case models.StatusStarting, models.StatusStopping, models.StatusArchiving:
+		return nil, fmt.Errorf("%w (status: %s)", ErrSuspensionInTransition, server.Status)
+	case models.StatusRunning:
+		if err := s.StopServerContext(ctx, serverID, "suspended"); err != nil {
+			return nil, fmt.Errorf("failed to stop server: %w", err)
+		}
+		if server, err = s.repo.FindByID(serverID); err != nil {
+			return nil, fmt.Errorf("failed to reload server after stop: %w", err)
+		}
+	case models.StatusQueued:
+		if s.conductor != nil {
+			s.conductor.RemoveFromQueue(serverID)
+		}
+	}
+
+	now := time.Now()
+	server.StatusBeforeSuspension = statusAfterSuspension(server.Status)
+	server.Status = models.StatusSuspended
+	server.SuspendedAt = &now
+	server.SuspensionReason = reason
+	server.SuspensionSource = source
+	server.SuspendedBy = suspendedBy
+	if err := s.repo.Update(server); err != nil {
+		return nil, fmt.Errorf("failed to suspend server: %w", err)
+	}
+
+	// A reserved plan keeps stopped containers in the registry; a suspended server holds no RAM
+	if s.conductor != nil {
+		s.conductor.RemoveContainer(server.ID)
+	}
+	// Sleeping servers stay registered for wake-on-join, suspended ones must not be reachable
+	if s.remoteVelocityClient != nil {
+		if err := s.remoteVelocityClient.UnregisterServer(fmt.Sprintf("mc-%s", server.ID)); err != nil {
+			logger.Warn("SUSPENSION: Failed to unregister server from Velocity", map[string]interface{}{
+				"server_id": server.ID,
+				"error":     err.Error(),
+			})
+		}
+	}
+	if s.wsHub != nil {
+		s.wsHub.Broadcast("server_suspended", map[string]interface{}{
+			"server_id": server.ID,
+			"name":      server.Name,
+			"status":    server.Status,
+			"reason":    reason,
+		})
+	}
+
+	logger.Warn("SUSPENSION: Server suspended", map[string]interface{}{
+		"server_id":    server.ID,
+		"server_name":  server.Name,
+		"owner_id":     server.OwnerID,
+		"source":       source,
+		"suspended_by": suspendedBy,
+		"reason":       reason,
+	})
+	return server, nil
+}
+
+// UnsuspendServer lifts a suspension. The server returns to stopped (or sleeping, archived) and is
+// not started; the owner starts it again. A non-empty source only lifts suspensions of that source,
+// so paying an invoice doesn't lift an abuse hold.
+func (s *MinecraftService) UnsuspendServer(serverID, source string) (*models.MinecraftServer, error) {
+	mu := s.acquireOperationLock(serverID)
+	defer s.releaseOperationLock(serverID, mu)
+
+	server, err := s.repo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+	if server.Status != models.StatusSuspended || (source != "" && server.SuspensionSource != source) {
+		return nil, ErrServerNotSuspended
+	}
+
+	previousReason := server.SuspensionReason
+	server.Status = statusAfterSuspension(server.StatusBeforeSuspension)
+	server.StatusBeforeSuspension = ""
+	server.SuspendedAt = nil
+	server.SuspensionReason = ""
+	server.SuspensionSource = ""
+	server.SuspendedBy = ""
+	if err := s.repo.Update(server); err != nil {
+		return nil, fmt.Errorf("failed to unsuspend server: %w", err)
+	}
+
+	if s.wsHub != nil {
+		s.wsHub.Broadcast("server_unsuspended", map[string]interface{}{
+			"server_id": server.ID,
+			"name":      server.Name,
+			"status":    server.Status,
+		})
+	}
+
+	logger.Info("SUSPENSION: Server suspension lifted", map[string]interface{}{
+		"server_id":       server.ID,
+		"server_name":     server.Name,
+		"owner_id":        server.OwnerID,
+		"status":          server.Status,
+		"previous_reason": previousReason,
+	})
+	return server, nil
+}
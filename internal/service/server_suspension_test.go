@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestCheckNotSuspended(t *testing.T) {
+	if err := checkNotSuspended(&models.MinecraftServer{Status: models.StatusStopped}); err != nil {
+		t.Errorf("checkNotSuspended(stopped) = %v, want nil", err)
+	}
+
+	err := checkNotSuspended(&models.MinecraftServer{Status: models.StatusSuspended, SuspensionReason: "abuse report"})
+	if !errors.Is(err, ErrServerSuspended) {
+		t.Fatalf("checkNotSuspended(suspended) = %v, want ErrServerSuspended", err)
+	}
+	if err.Error() != "server is suspended: abuse report" {
+		t.Errorf("error = %q, want the suspension reason", err.Error())
+	}
+}
+
+func TestStatusAfterSuspension(t *testing.T) {
+	tests := map[models.ServerStatus]models.ServerStatus{
+		models.StatusStopped:  models.StatusStopped,
+		models.StatusSleeping: models.StatusSleeping,
+		models.StatusArchived: models.StatusArchived, // Files stay in the archive until the next start
+		models.StatusQueued:   models.StatusStopped,  // Left the start queue
+		models.StatusError:    models.StatusStopped,
+		"":                    models.StatusStopped, // Suspended before the previous status was recorded
+	}
+	for before, want := range tests {
+		if got := statusAfterSuspension(before); got != want {
+			t.Errorf("statusAfterSuspension(%q) = %q, want %q", before, got, want)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpFile is an open file of an SFTP session (*os.File or *sftp.File)
+type sftpFile interface {
+	io.ReaderAt
+	io.WriterAt
+	io.Closer
+	Stat() (os.FileInfo, error)
+}
+
+// sftpFS is the directory of one server, on this node or on a worker node
+// Names are slash paths inside the server directory ("/" is the server directory itself).
+type sftpFS interface {
+	OpenFile(name string, flag int) (sftpFile, error)
+	Stat(name string) (os.FileInfo, error)
+	Lstat(name string) (os.FileInfo, error)
+	ReadDir(name string) ([]os.FileInfo, error)
+	Mkdir(name string) error
+	Remove(name string) error
+	RemoveDirectory(name string) error
+	Rename(oldname, newname string) error
+	Chmod(name string, mode os.FileMode) error
+	Truncate(name string, size int64) error
+	Chtimes(name string, atime, mtime time.Time) error
+	Usage() (int64, error) // Bytes used by the server directory
+	Close() error
+}
+
+// localSFTPFS is a server directory on this node
+type localSFTPFS struct {
+	root string
+}
+
+func (l *localSFTPFS) path(name string) string {
+	return filepath.Join(l.root, filepath.FromSlash(path.Clean("/"+name)))
+}
+
+func (l *localSFTPFS) OpenFile(name string, flag int) (sftpFile, error) {
+	file, err := os.OpenFile(l.path(name), flag, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (l *localSFTPFS) Stat(name string) (os.FileInfo, error)  { return os.Stat(l.path(name)) }
+func (l *localSFTPFS) Lstat(name string) (os.FileInfo, error) { return os.Lstat(l.path(name)) }
+func (l *localSFTPFS) Mkdir(name string) error                { return os.Mkdir(l.path(name), 0755) }
+func (l *localSFTPFS) Remove(name string) error               { return os.Remove(l.path(name)) }
+func (l *localSFTPFS) RemoveDirectory(name string) error      { return os.Remove(l.path(name)) }
+func (l *localSFTPFS) Close() error                           { return nil }
+
+func (l *localSFTPFS) ReadDir(name string) ([]os.FileInfo, error) {
+	entries, err := os.ReadDir(l.path(name))
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]os.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil {
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
+
+func (l *localSFTPFS) Rename(oldname, newname string) error {
+	return os.Rename(l.path(oldname), l.path(newname))
+}
+
+func (l *localSFTPFS) Chmod(name string, mode os.FileMode) error {
+	return os.Chmod(l.path(name), mode)
+}
+
+func (l *localSFTPFS) Truncate(name string, size int64) error {
+	return os.Truncate(l.path(name), size)
+}
+
+func (l *localSFTPFS) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(l.path(name), atime, mtime)
+}
+
+func (l *localSFTPFS) Usage() (int64, error) {
+	var total int64
+	err := filepath.WalkDir(l.root, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total, err
+}
+
+// remoteSFTPFS is a server directory on a worker node, reached through the node's SFTP subsystem
+type remoteSFTPFS struct {
+	client  *sftp.Client
+	root    string
+	usage   func() (int64, error)
+	release func()
+}
+
+func (r *remoteSFTPFS) path(name string) string {
+	return path.Join(r.root, path.Clean("/"+name))
+}
+
+func (r *remoteSFTPFS) OpenFile(name string, flag int) (sftpFile, error) {
+	file, err := r.client.OpenFile(r.path(name), flag)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func (r *remoteSFTPFS) Stat(name string) (os.FileInfo, error)  { return r.client.Stat(r.path(name)) }
+func (r *remoteSFTPFS) Lstat(name string) (os.FileInfo, error) { return r.client.Lstat(r.path(name)) }
+func (r *remoteSFTPFS) ReadDir(name string) ([]os.FileInfo, error) {
+	return r.client.ReadDir(r.path(name))
+}
+func (r *remoteSFTPFS) Mkdir(name string) error  { return r.client.Mkdir(r.path(name)) }
+func (r *remoteSFTPFS) Remove(name string) error { return r.client.Remove(r.path(name)) }
+func (r *remoteSFTPFS) Usage() (int64, error)    { return r.usage() }
+
+func (r *remoteSFTPFS) RemoveDirectory(name string) error {
+	return r.client.RemoveDirectory(r.path(name))
+}
+
+func (r *remoteSFTPFS) Rename(oldname, newname string) error {
+	return r.client.Rename(r.path(oldname), r.path(newname))
+}
+
+func (r *remoteSFTPFS) Chmod(name string, mode os.FileMode) error {
+	return r.client.Chmod(r.path(name), mode)
+}
+
+func (r *remoteSFTPFS) Truncate(name string, size int64) error {
+	return r.client.Truncate(r.path(name), size)
+}
+
+func (r *remoteSFTPFS) Chtimes(name string, atime, mtime time.Time) error {
+	return r.client.Chtimes(r.path(name), atime, mtime)
+}
+
+func (r *remoteSFTPFS) Close() error {
+	r.release()
+	return nil
+}
+
+// ServerFileAccess opens server directories for file protocols (SFTP gateway, WebDAV)
+// Directories of servers on worker nodes are reached through the node's SFTP subsystem.
+type ServerFileAccess struct {
+	cfg         *config.Config
+	nodes       NodeAddressResolver
+	opener      RemoteSessionOpener
+	nodeClients *nodeSFTPClients
+}
+
+// NewServerFileAccess creates a server file access for directories on this node
+func NewServerFileAccess(cfg *config.Config) *ServerFileAccess {
+	return &ServerFileAccess{cfg: cfg}
+}
+
+// SetRemoteAccess enables access to servers on remote worker nodes
+func (a *ServerFileAccess) SetRemoteAccess(nodes NodeAddressResolver, opener RemoteSessionOpener) {
+	a.nodes = nodes
+	a.opener = opener
+	a.nodeClients = &nodeSFTPClients{opener: opener, clients: make(map[string]*nodeSFTPClient)}
+}
+
+// open returns the directory of a server on its node; Close it when done
+func (a *ServerFileAccess) open(server *models.MinecraftServer) (sftpFS, error) {
+	if isLocalNode(server.NodeID) {
+		root := filepath.Join(a.cfg.ServersBasePath, server.ID)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("server directory %s not found", root)
+		}
+		return &localSFTPFS{root: root}, nil
+	}
+
+	node, err := a.remoteNode(server)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client, release, err := a.nodeClients.acquire(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	root := remoteServersPath + "/" + server.ID
+	return &remoteSFTPFS{
+		client:  client,
+		root:    root,
+		usage:   func() (int64, error) { return remoteDirUsage(a.opener, node, root) },
+		release: release,
+	}, nil
+}
+
+// usage measures the server directory in bytes, without opening an SFTP connection to worker nodes
+func (a *ServerFileAccess) usage(server *models.MinecraftServer) (int64, error) {
+	if isLocalNode(server.NodeID) {
+		root := filepath.Join(a.cfg.ServersBasePath, server.ID)
+		if _, err := os.Stat(root); err != nil {
+			return 0, fmt.Errorf("server directory %s not found", root)
+		}
+		return (&localSFTPFS{root: root}).Usage()
+	}
+
+	node, err := a.remoteNode(server)
+	if err != nil {
+		return 0, err
+	}
+	return remoteDirUsage(a.opener, node, remoteServersPath+"/"+server.ID)
+}
+
+func (a *ServerFileAccess) remoteNode(server *models.MinecraftServer) (*docker.RemoteNode, error) {
+	if a.nodes == nil || a.nodeClients == nil {
+		return nil, fmt.Errorf("remote node access not configured for node %s", server.NodeID)
+	}
+	node, err := a.nodes.GetRemoteNode(server.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve node %s: %w", server.NodeID, err)
+	}
+	return node, nil
+}
+
+func isLocalNode(nodeID string) bool {
+	return nodeID == "" || nodeID == "local-node"
+}
+
+// errSymlinkPath is returned for paths that pass through a symlink
+var errSymlinkPath = errors.New("path passes through a symlink")
+
+// checkNoSymlinks refuses paths that pass through a symlink, which could lead out of the server directory
+func checkNoSymlinks(fs sftpFS, name string) error {
+	current := "/"
+	for _, part := range strings.Split(strings.TrimPrefix(path.Clean("/"+name), "/"), "/") {
+		if part == "" {
+			continue
+		}
+		current = path.Join(current, part)
+		info, err := fs.Lstat(current)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil // The rest doesn't exist yet, nothing to follow
+			}
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return errSymlinkPath
+		}
+	}
+	return nil
+}
+
+// RemoteSessionOpener opens SSH sessions on worker nodes (implemented by docker.SSHPool)
+type RemoteSessionOpener interface {
+	NewSession(ctx context.Context, node *docker.RemoteNode) (*ssh.Session, func(), error)
+}
+
+// nodeSFTPClients shares one SFTP connection per worker node between all gateway sessions
+// Every connection holds one of the node's pooled SSH session slots, so sessions must not open their own.
+type nodeSFTPClients struct {
+	opener RemoteSessionOpener
+
+	mu      sync.Mutex
+	clients map[string]*nodeSFTPClient
+}
+
+// nodeSFTPClient is the shared SFTP connection to one node
+type nodeSFTPClient struct {
+	client  *sftp.Client
+	release func()
+	refs    int
+}
+
+// acquire returns the SFTP client of a node and a func to call when the session no longer needs it
+func (n *nodeSFTPClients) acquire(ctx context.Context, node *docker.RemoteNode) (*sftp.Client, func(), error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	shared, ok := n.clients[node.ID]
+	if !ok {
+		client, release, err := openNodeSFTP(ctx, n.opener, node)
+		if err != nil {
+			return nil, nil, err
+		}
+		shared = &nodeSFTPClient{client: client, release: release}
+		n.clients[node.ID] = shared
+
+		// Forget connections that broke, the next session reconnects
+		go func() {
+			client.Wait()
+			n.mu.Lock()
+			if n.clients[node.ID] == shared {
+				delete(n.clients, node.ID)
+			}
+			n.mu.Unlock()
+		}()
+	}
+	shared.refs++
+
+	var once sync.Once
+	return shared.client, func() {
+		once.Do(func() {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+			shared.refs--
+			if shared.refs == 0 {
+				if n.clients[node.ID] == shared {
+					delete(n.clients, node.ID)
+				}
+				shared.client.Close()
+				shared.release()
+			}
+		})
+	}, nil
+}
+
+// openNodeSFTP starts the SFTP subsystem of a node on a pooled SSH session
+func openNodeSFTP(ctx context.Context, opener RemoteSessionOpener, node *docker.RemoteNode) (*sftp.Client, func(), error) {
+	session, release, err := opener.NewSession(ctx, node)
+	if err != nil {
+		return nil, nil, err
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	if err := session.RequestSubsystem("sftp"); err != nil {
+		release()
+		return nil, nil, fmt.Errorf("failed to start SFTP on node %s: %w", node.ID, err)
+	}
+	client, err := sftp.NewClientPipe(stdout, stdin)
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("failed to start SFTP on node %s: %w", node.ID, err)
+	}
+	return client, release, nil
+}
+
+// remoteDirUsage measures a directory on a worker node with du
+func remoteDirUsage(opener RemoteSessionOpener, node *docker.RemoteNode, dir string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	session, release, err := opener.NewSession(ctx, node)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	output, err := session.Output(docker.ShellJoin("du", "-sb", "--", dir))
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure %s on node %s: %w", dir, node.ID, err)
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("unexpected du output: %q", output)
+	}
+	return strconv.ParseInt(fields[0], 10, 64)
+}
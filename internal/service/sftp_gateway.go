@@ -0,0 +1,493 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"golang.org/x/crypto/ssh"
+	"gorm.io/gorm"
+)
+
+// SFTP gateway errors
+var (
+	ErrSFTPGatewayDisabled = errors.New("SFTP gateway is not enabled")
+	ErrSFTPKeyInvalid      = errors.New("invalid public key (expected one authorized_keys line; RSA keys need at least 2048 bits, DSA is not supported)")
+	ErrSFTPKeyExists       = errors.New("this public key is already registered")
+	ErrSFTPKeyNotFound     = errors.New("SFTP key not found")
+)
+
+// sftpHandshakeTimeout bounds key exchange and authentication of new connections
+const sftpHandshakeTimeout = 30 * time.Second
+
+// SFTPGatewayService serves the files of servers over SFTP
+// Users log in with one of their registered SSH keys and the server ID as user name. The session
+// sees the server directory as its root, on this node or on the server's worker node. Every change
+// is written to the SFTP audit log, and uploads stop once the server directory reaches its disk quota.
+type SFTPGatewayService struct {
+	repo        *repository.SFTPRepository
+	serverRepo  *repository.ServerRepository
+	permissions *PermissionService
+	files       *ServerFileAccess
+	quota       *DiskQuotaService // Optional, limits uploads to the server's disk quota
+	cfg         *config.Config
+
+	hostKey  ssh.Signer
+	listener net.Listener
+	ctx      context.Context
+	cancel   context.CancelFunc
+	running  bool
+
+	connMu sync.Mutex
+	conns  map[net.Conn]struct{}
+}
+
+// NewSFTPGatewayService creates a new SFTP gateway
+func NewSFTPGatewayService(repo *repository.SFTPRepository, serverRepo *repository.ServerRepository, permissions *PermissionService, files *ServerFileAccess, cfg *config.Config) *SFTPGatewayService {
+	return &SFTPGatewayService{
+		repo:        repo,
+		serverRepo:  serverRepo,
+		permissions: permissions,
+		files:       files,
+		cfg:         cfg,
+		conns:       make(map[net.Conn]struct{}),
+	}
+}
+
+// SetDiskQuota limits SFTP uploads to the disk quota of each server
+func (s *SFTPGatewayService) SetDiskQuota(quota *DiskQuotaService) {
+	s.quota = quota
+}
+
+// Start listens for SFTP connections and begins the daily audit log pruning
+func (s *SFTPGatewayService) Start() error {
+	if s.running {
+		logger.Warn("SFTP-GATEWAY: Already running", nil)
+		return nil
+	}
+
+	hostKey, err := loadSFTPHostKey(s.cfg.SFTPHostKeyPath)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.cfg.SFTPGatewayPort))
+	if err != nil {
+		return fmt.Errorf("failed to listen for SFTP: %w", err)
+	}
+
+	s.hostKey = hostKey
+	s.listener = listener
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.running = true
+
+	logger.Info("SFTP-GATEWAY: Listening", map[string]interface{}{
+		"port":     s.cfg.SFTPGatewayPort,
+		"host_key": ssh.FingerprintSHA256(hostKey.PublicKey()),
+	})
+
+	go s.acceptLoop(listener)
+	go func() {
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+
+		s.pruneAudit()
+		for {
+			select {
+			case <-ticker.C:
+				s.pruneAudit()
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop closes the listener and all open SFTP sessions
+func (s *SFTPGatewayService) Stop() {
+	if !s.running {
+		return
+	}
+
+	logger.Info("SFTP-GATEWAY: Stopping", nil)
+	s.cancel()
+	s.listener.Close()
+	s.running = false
+
+	s.connMu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.connMu.Unlock()
+}
+
+func (s *SFTPGatewayService) acceptLoop(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logger.Warn("SFTP-GATEWAY: Accept failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+			time.Sleep(time.Second)
+			continue
+		}
+		go s.handleConn(conn)
+	}
+}
+
+// handleConn authenticates a connection and serves its SFTP channels
+func (s *SFTPGatewayService) handleConn(conn net.Conn) {
+	s.connMu.Lock()
+	s.conns[conn] = struct{}{}
+	s.connMu.Unlock()
+	defer func() {
+		s.connMu.Lock()
+		delete(s.conns, conn)
+		s.connMu.Unlock()
+		conn.Close()
+	}()
+
+	conn.SetDeadline(time.Now().Add(sftpHandshakeTimeout))
+	sshConn, channels, requests, err := ssh.NewServerConn(conn, s.sshConfig())
+	if err != nil {
+		return
+	}
+	conn.SetDeadline(time.Time{})
+	defer sshConn.Close()
+	go ssh.DiscardRequests(requests)
+
+	session, err := s.openSession(sshConn)
+	if err != nil {
+		logger.Warn("SFTP-GATEWAY: Failed to open session", map[string]interface{}{
+			"server_id": sshConn.User(),
+			"error":     err.Error(),
+		})
+		return
+	}
+	defer session.close()
+
+	var wg sync.WaitGroup
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only session channels are supported")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer channel.Close()
+			for req := range channelRequests {
+				ok := req.Type == "subsystem" && sftpSubsystemName(req.Payload) == "sftp"
+				req.Reply(ok, nil)
+				if ok {
+					session.serve(channel)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// sshConfig authenticates SSH keys against the registered SFTP keys
+func (s *SFTPGatewayService) sshConfig() *ssh.ServerConfig {
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: s.authenticate,
+		ServerVersion:     "SSH-2.0-PayPerPlay-SFTP",
+	}
+	config.AddHostKey(s.hostKey)
+	return config
+}
+
+// authenticate checks that a key may open the server named by the SSH user name
+func (s *SFTPGatewayService) authenticate(conn ssh.ConnMetadata, publicKey ssh.PublicKey) (*ssh.Permissions, error) {
+	key, err := s.repo.FindKeyByFingerprint(ssh.FingerprintSHA256(publicKey))
+	if err != nil {
+		return nil, fmt.Errorf("unknown key")
+	}
+	serverID := conn.User()
+	if key.ServerID != "" && key.ServerID != serverID {
+		return nil, fmt.Errorf("key is not valid for server %s", serverID)
+	}
+	server, err := s.permissions.Authorize(key.UserID, serverID, models.PermServerFilesRead)
+	if err != nil {
+		return nil, fmt.Errorf("no file access to server %s", serverID)
+	}
+	if server.Status == models.StatusArchived {
+		return nil, fmt.Errorf("server %s is archived", serverID)
+	}
+
+	write := ""
+	if !key.ReadOnly && s.permissions.Can(key.UserID, server, models.PermServerFilesWrite) {
+		write = "true"
+	}
+	return &ssh.Permissions{Extensions: map[string]string{
+		"key_id":  key.ID,
+		"user_id": key.UserID,
+		"write":   write,
+	}}, nil
+}
+
+// openSession opens the server directory of an authenticated connection
+func (s *SFTPGatewayService) openSession(conn *ssh.ServerConn) (*sftpSession, error) {
+	server, err := s.serverRepo.FindByID(conn.User())
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+	fs, err := s.files.open(server)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &sftpSession{
+		gateway:    s,
+		fs:         fs,
+		serverID:   server.ID,
+		userID:     conn.Permissions.Extensions["user_id"],
+		keyID:      conn.Permissions.Extensions["key_id"],
+		remoteAddr: conn.RemoteAddr().String(),
+		write:      conn.Permissions.Extensions["write"] == "true",
+	}
+	if s.quota != nil {
+		session.quota = s.quota.QuotaBytes(server)
+	}
+	if session.write && session.quota > 0 {
+		usage, err := fs.Usage()
+		if err != nil {
+			fs.Close()
+			return nil, fmt.Errorf("failed to measure server directory: %w", err)
+		}
+		session.usage = usage
+		session.loginUsage = usage
+	}
+
+	remoteIP := conn.RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(remoteIP); err == nil {
+		remoteIP = host
+	}
+	if err := s.repo.TouchKey(session.keyID, remoteIP); err != nil {
+		logger.Warn("SFTP-GATEWAY: Failed to record key usage", map[string]interface{}{
+			"key_id": session.keyID,
+			"error":  err.Error(),
+		})
+	}
+	detail := "read-only"
+	if session.write {
+		detail = "read-write"
+	}
+	session.audit(models.SFTPAuditLogin, "", "", 0, detail)
+	return session, nil
+}
+
+// AddKey registers a public key in authorized_keys format for SFTP logins of userID
+// With serverID set the key only opens that server; readOnly keys never change files.
+func (s *SFTPGatewayService) AddKey(userID, name, publicKey, serverID string, readOnly bool) (*models.SFTPKey, error) {
+	parsed, comment, err := parseSFTPPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if serverID != "" {
+		if _, err := s.permissions.Authorize(userID, serverID, models.PermServerFilesRead); err != nil {
+			return nil, err
+		}
+	}
+
+	fingerprint := ssh.FingerprintSHA256(parsed)
+	if _, err := s.repo.FindKeyByFingerprint(fingerprint); err == nil {
+		return nil, ErrSFTPKeyExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check key: %w", err)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = comment
+	}
+	if name == "" {
+		name = "SFTP key"
+	}
+	key := &models.SFTPKey{
+		UserID:      userID,
+		Name:        name,
+		PublicKey:   strings.TrimSpace(string(ssh.MarshalAuthorizedKey(parsed))),
+		Fingerprint: fingerprint,
+		ServerID:    serverID,
+		ReadOnly:    readOnly,
+	}
+	if err := s.repo.CreateKey(key); err != nil {
+		return nil, fmt.Errorf("failed to save key: %w", err)
+	}
+
+	logger.Info("SFTP key added", map[string]interface{}{
+		"user_id":     userID,
+		"key_id":      key.ID,
+		"fingerprint": fingerprint,
+		"server_id":   serverID,
+		"read_only":   readOnly,
+	})
+	return key, nil
+}
+
+// ListKeys returns the SFTP keys of a user
+func (s *SFTPGatewayService) ListKeys(userID string) ([]models.SFTPKey, error) {
+	return s.repo.FindKeysByUser(userID)
+}
+
+// DeleteKey removes an SFTP key of a user; open sessions of the key end with their connection
+func (s *SFTPGatewayService) DeleteKey(userID, keyID string) error {
+	if err := s.repo.DeleteKey(userID, keyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSFTPKeyNotFound
+		}
+		return fmt.Errorf("failed to delete key: %w", err)
+	}
+	logger.Info("SFTP key deleted", map[string]interface{}{
+		"user_id": userID,
+		"key_id":  keyID,
+	})
+	return nil
+}
+
+// SFTPConnectionInfo is what an SFTP client needs to open a server
+type SFTPConnectionInfo struct {
+	Host               string `json:"host"`
+	Port               int    `json:"port"`
+	Username           string `json:"username"`
+	HostKeyFingerprint string `json:"host_key_fingerprint"`
+}
+
+// ConnectionInfo returns the SFTP login of a server
+func (s *SFTPGatewayService) ConnectionInfo(serverID string) (*SFTPConnectionInfo, error) {
+	if !s.running {
+		return nil, ErrSFTPGatewayDisabled
+	}
+	host := s.cfg.SFTPGatewayHost
+	if host == "" {
+		if baseURL, err := url.Parse(s.cfg.BaseURL); err == nil {
+			host = baseURL.Hostname()
+		}
+	}
+	return &SFTPConnectionInfo{
+		Host:               host,
+		Port:               s.cfg.SFTPGatewayPort,
+		Username:           serverID,
+		HostKeyFingerprint: ssh.FingerprintSHA256(s.hostKey.PublicKey()),
+	}, nil
+}
+
+// ListAudit returns the newest SFTP audit entries of a server
+func (s *SFTPGatewayService) ListAudit(serverID string, limit int) ([]models.SFTPAuditEntry, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	return s.repo.FindAuditByServer(serverID, limit)
+}
+
+// pruneAudit deletes audit entries older than the retention
+func (s *SFTPGatewayService) pruneAudit() {
+	if s.cfg.SFTPAuditRetentionDays <= 0 {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -s.cfg.SFTPAuditRetentionDays)
+	deleted, err := s.repo.DeleteAuditBefore(cutoff)
+	if err != nil {
+		logger.Warn("SFTP-GATEWAY: Failed to prune audit log", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if deleted > 0 {
+		logger.Info("SFTP-GATEWAY: Pruned audit log", map[string]interface{}{
+			"deleted": deleted,
+		})
+	}
+}
+
+// parseSFTPPublicKey parses one authorized_keys line and rejects weak key types
+func parseSFTPPublicKey(publicKey string) (ssh.PublicKey, string, error) {
+	parsed, comment, options, rest, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(publicKey)))
+	if err != nil || len(options) > 0 || len(strings.TrimSpace(string(rest))) > 0 {
+		return nil, "", ErrSFTPKeyInvalid
+	}
+	switch parsed.Type() {
+	case ssh.KeyAlgoDSA:
+		return nil, "", ErrSFTPKeyInvalid
+	case ssh.KeyAlgoRSA:
+		cryptoKey, ok := parsed.(ssh.CryptoPublicKey)
+		if !ok {
+			return nil, "", ErrSFTPKeyInvalid
+		}
+		if rsaKey, ok := cryptoKey.CryptoPublicKey().(*rsa.PublicKey); !ok || rsaKey.N.BitLen() < 2048 {
+			return nil, "", ErrSFTPKeyInvalid
+		}
+	}
+	return parsed, comment, nil
+}
+
+// loadSFTPHostKey reads the gateway's host key, generating an ed25519 key on first start
+func loadSFTPHostKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate SFTP host key: %w", err)
+		}
+		block, err := ssh.MarshalPrivateKey(privateKey, "payperplay-sftp")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode SFTP host key: %w", err)
+		}
+		data = pem.EncodeToMemory(block)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create SFTP host key directory: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to save SFTP host key: %w", err)
+		}
+		logger.Info("SFTP-GATEWAY: Generated host key", map[string]interface{}{
+			"path": path,
+		})
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read SFTP host key: %w", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SFTP host key: %w", err)
+	}
+	return signer, nil
+}
+
+// sftpSubsystemName extracts the name of a subsystem request (an SSH string)
+func sftpSubsystemName(payload []byte) string {
+	if len(payload) < 4 {
+		return ""
+	}
+	length := binary.BigEndian.Uint32(payload)
+	if uint32(len(payload)-4) < length {
+		return ""
+	}
+	return string(payload[4 : 4+length])
+}
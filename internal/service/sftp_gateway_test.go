@@ -0,0 +1,98 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestParseSFTPPublicKey(t *testing.T) {
+	edKey, _, _ := ed25519.GenerateKey(rand.Reader)
+	edPublic, _ := ssh.NewPublicKey(edKey)
+	line := string(ssh.MarshalAuthorizedKey(edPublic))
+	line = line[:len(line)-1] + " me@laptop\n"
+
+	parsed, comment, err := parseSFTPPublicKey(line)
+	if err != nil {
+		t.Fatalf("parseSFTPPublicKey(ed25519) error = %v", err)
+	}
+	if comment != "me@laptop" || ssh.FingerprintSHA256(parsed) != ssh.FingerprintSHA256(edPublic) {
+		t.Errorf("parseSFTPPublicKey(ed25519) = %s, %q", ssh.FingerprintSHA256(parsed), comment)
+	}
+
+	weak, _ := rsa.GenerateKey(rand.Reader, 1024)
+	weakPublic, _ := ssh.NewPublicKey(&weak.PublicKey)
+	for _, invalid := range []string{
+		"",
+		"ssh-ed25519 notbase64",
+		string(ssh.MarshalAuthorizedKey(weakPublic)),
+		`command="sh" ` + line,
+		line + line,
+	} {
+		if _, _, err := parseSFTPPublicKey(invalid); !errors.Is(err, ErrSFTPKeyInvalid) {
+			t.Errorf("parseSFTPPublicKey(%q) error = %v, want ErrSFTPKeyInvalid", invalid, err)
+		}
+	}
+}
+
+func TestSFTPSubsystemName(t *testing.T) {
+	if name := sftpSubsystemName(ssh.Marshal(struct{ Name string }{"sftp"})); name != "sftp" {
+		t.Errorf("sftpSubsystemName() = %q, want sftp", name)
+	}
+	if name := sftpSubsystemName([]byte{0, 0, 0, 9, 's'}); name != "" {
+		t.Errorf("sftpSubsystemName(truncated) = %q, want empty", name)
+	}
+}
+
+func TestLocalSFTPFSStaysInRoot(t *testing.T) {
+	root := t.TempDir()
+	fs := &localSFTPFS{root: root}
+	for name, want := range map[string]string{
+		"/":                 root,
+		"server.properties": filepath.Join(root, "server.properties"),
+		"/../../etc/passwd": filepath.Join(root, "etc", "passwd"),
+		"world/../../x":     filepath.Join(root, "x"),
+	} {
+		if got := fs.path(name); got != want {
+			t.Errorf("path(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSFTPQuotaFile(t *testing.T) {
+	root := t.TempDir()
+	fs := &localSFTPFS{root: root}
+	file, err := fs.OpenFile("mod.jar", os.O_WRONLY|os.O_CREATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	session := &sftpSession{quota: 100, usage: 40}
+	quotaFile := &sftpQuotaFile{sftpFile: file, session: session, path: "mod.jar"}
+
+	if _, err := quotaFile.WriteAt(make([]byte, 50), 0); err != nil {
+		t.Fatalf("WriteAt() within quota error = %v", err)
+	}
+	// Rewriting existing bytes doesn't grow the directory
+	if _, err := quotaFile.WriteAt(make([]byte, 50), 0); err != nil {
+		t.Fatalf("WriteAt() overwrite error = %v", err)
+	}
+	if _, err := quotaFile.WriteAt(make([]byte, 20), 50); !errors.Is(err, errSFTPQuotaExceeded) {
+		t.Errorf("WriteAt() past quota error = %v, want errSFTPQuotaExceeded", err)
+	}
+	if session.usage != 90 {
+		t.Errorf("usage = %d, want 90", session.usage)
+	}
+
+	session.release(50)
+	if !session.reserve(60) || session.reserve(1) {
+		t.Errorf("reserve() after release: usage = %d, want exactly 100 reservable", session.usage)
+	}
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+	"github.com/pkg/sftp"
+)
+
+// errSFTPQuotaExceeded is sent to clients whose upload would grow the server directory past the quota
+var errSFTPQuotaExceeded = errors.New("disk quota exceeded")
+
+// sftpSession is one logged-in SFTP connection to a server directory
+type sftpSession struct {
+	gateway    *SFTPGatewayService
+	fs         sftpFS
+	serverID   string
+	userID     string
+	keyID      string
+	remoteAddr string
+	write      bool  // Files may be changed (key not read-only and files.write permission)
+	quota      int64 // Largest size of the server directory in bytes, 0 = unlimited
+
+	mu         sync.Mutex
+	usage      int64 // Size of the server directory, measured at login and tracked since
+	loginUsage int64
+}
+
+// serve runs the SFTP protocol on a channel until the client closes it
+func (s *sftpSession) serve(channel io.ReadWriteCloser) {
+	handlers := sftp.Handlers{FileGet: s, FilePut: s, FileCmd: s, FileList: s}
+	server := sftp.NewRequestServer(channel, handlers, sftp.WithStartDirectory("/"))
+	if err := server.Serve(); err != nil && !errors.Is(err, io.EOF) {
+		logger.Warn("SFTP-GATEWAY: Session ended with error", map[string]interface{}{
+			"server_id": s.serverID,
+			"error":     err.Error(),
+		})
+	}
+	server.Close()
+}
+
+func (s *sftpSession) close() {
+	s.fs.Close()
+	if s.gateway.quota != nil && s.quota > 0 {
+		// Counts against the quota until the next scan measures the directory
+		s.gateway.quota.AddDiskUsage(s.serverID, s.usage-s.loginUsage)
+	}
+	s.audit(models.SFTPAuditLogout, "", "", 0, "")
+}
+
+// Fileread opens a file for download
+func (s *sftpSession) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	if err := s.checkPath(r.Filepath); err != nil {
+		return nil, err
+	}
+	file, err := s.fs.OpenFile(r.Filepath, os.O_RDONLY)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(models.SFTPAuditDownload, r.Filepath, "", 0, "")
+	return file, nil
+}
+
+// Filewrite opens a file for upload
+func (s *sftpSession) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	return s.openWritable(r, os.O_WRONLY)
+}
+
+// OpenFile opens a file for reading and writing (sftp.OpenFileWriter)
+func (s *sftpSession) OpenFile(r *sftp.Request) (sftp.WriterAtReaderAt, error) {
+	return s.openWritable(r, os.O_RDWR)
+}
+
+func (s *sftpSession) openWritable(r *sftp.Request, flag int) (*sftpQuotaFile, error) {
+	if err := s.checkWritable(r.Filepath); err != nil {
+		return nil, err
+	}
+	pflags := r.Pflags()
+	if pflags.Creat {
+		flag |= os.O_CREATE
+	}
+	if pflags.Trunc {
+		flag |= os.O_TRUNC
+	}
+	if pflags.Excl {
+		flag |= os.O_EXCL
+	}
+	// O_APPEND is left out: clients send offsets, and WriteAt refuses files opened for appending
+
+	var size int64
+	info, err := s.fs.Stat(r.Filepath)
+	if err == nil && info.IsDir() {
+		return nil, sftp.ErrSSHFxFailure
+	}
+	changed := err != nil // Created by this open
+	if err == nil {
+		size = info.Size()
+	}
+	file, err := s.fs.OpenFile(r.Filepath, flag)
+	if err != nil {
+		return nil, err
+	}
+	if flag&os.O_TRUNC != 0 {
+		s.release(size)
+		size = 0
+		changed = true
+	}
+	return &sftpQuotaFile{sftpFile: file, session: s, path: r.Filepath, size: size, changed: changed}, nil
+}
+
+// Filecmd handles changes that don't open a file
+func (s *sftpSession) Filecmd(r *sftp.Request) error {
+	switch r.Method {
+	case "Setstat":
+		return s.setstat(r)
+	case "Rename", "PosixRename":
+		if err := s.checkWritable(r.Filepath); err != nil {
+			return err
+		}
+		if err := s.checkWritable(r.Target); err != nil {
+			return err
+		}
+		if target, err := s.fs.Lstat(r.Target); err == nil {
+			if r.Method == "Rename" || target.IsDir() {
+				return os.ErrExist // SFTP rename never replaces; posix-rename replaces files only
+			}
+			s.release(target.Size())
+		}
+		if err := s.fs.Rename(r.Filepath, r.Target); err != nil {
+			return err
+		}
+		s.audit(models.SFTPAuditRename, r.Filepath, r.Target, 0, "")
+		return nil
+	case "Mkdir":
+		if err := s.checkWritable(r.Filepath); err != nil {
+			return err
+		}
+		if err := s.fs.Mkdir(r.Filepath); err != nil {
+			return err
+		}
+		s.audit(models.SFTPAuditMkdir, r.Filepath, "", 0, "")
+		return nil
+	case "Rmdir":
+		if err := s.checkWritable(r.Filepath); err != nil {
+			return err
+		}
+		if err := s.fs.RemoveDirectory(r.Filepath); err != nil {
+			return err
+		}
+		s.audit(models.SFTPAuditRmdir, r.Filepath, "", 0, "")
+		return nil
+	case "Remove":
+		if err := s.checkWritable(r.Filepath); err != nil {
+			return err
+		}
+		info, err := s.fs.Lstat(r.Filepath)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return sftp.ErrSSHFxFailure
+		}
+		if err := s.fs.Remove(r.Filepath); err != nil {
+			return err
+		}
+		s.release(info.Size())
+		s.audit(models.SFTPAuditDelete, r.Filepath, "", info.Size(), "")
+		return nil
+	default:
+		// Link and Symlink: links could point out of the server directory
+		return sftp.ErrSSHFxOpUnsupported
+	}
+}
+
+// setstat changes the size, mode or timestamps of a file
+func (s *sftpSession) setstat(r *sftp.Request) error {
+	if err := s.checkWritable(r.Filepath); err != nil {
+		return err
+	}
+	flags := r.AttrFlags()
+	attrs := r.Attributes()
+	var details []string
+
+	if flags.Size {
+		info, err := s.fs.Stat(r.Filepath)
+		if err != nil {
+			return err
+		}
+		newSize := int64(attrs.Size)
+		if growth := newSize - info.Size(); growth > 0 && !s.reserve(growth) {
+			s.audit(models.SFTPAuditDenied, r.Filepath, "", 0, "quota exceeded")
+			return errSFTPQuotaExceeded
+		}
+		if err := s.fs.Truncate(r.Filepath, newSize); err != nil {
+			return err
+		}
+		if newSize < info.Size() {
+			s.release(info.Size() - newSize)
+		}
+		details = append(details, "size")
+	}
+	if flags.Permissions {
+		mode := attrs.FileMode() & os.ModePerm // No setuid, setgid or sticky bits
+		if err := s.fs.Chmod(r.Filepath, mode); err != nil {
+			return err
+		}
+		details = append(details, "mode "+mode.String())
+	}
+	if flags.Acmodtime {
+		if err := s.fs.Chtimes(r.Filepath, attrs.AccessTime(), attrs.ModTime()); err != nil {
+			return err
+		}
+		details = append(details, "times")
+	}
+	if len(details) > 0 {
+		s.audit(models.SFTPAuditSetstat, r.Filepath, "", 0, strings.Join(details, ", "))
+	}
+	return nil
+}
+
+// Filelist lists directories and stats files
+func (s *sftpSession) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	if err := s.checkPath(r.Filepath); err != nil {
+		return nil, err
+	}
+	switch r.Method {
+	case "List":
+		infos, err := s.fs.ReadDir(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+		return sftpLister(infos), nil
+	case "Stat":
+		info, err := s.fs.Stat(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+		return sftpLister{info}, nil
+	case "Lstat":
+		info, err := s.fs.Lstat(r.Filepath)
+		if err != nil {
+			return nil, err
+		}
+		return sftpLister{info}, nil
+	default:
+		return nil, sftp.ErrSSHFxOpUnsupported
+	}
+}
+
+// checkPath refuses paths that pass through a symlink
+func (s *sftpSession) checkPath(name string) error {
+	err := checkNoSymlinks(s.fs, name)
+	if errors.Is(err, errSymlinkPath) {
+		s.audit(models.SFTPAuditDenied, name, "", 0, "symlink")
+		return sftp.ErrSSHFxPermissionDenied
+	}
+	return err
+}
+
+// checkWritable refuses changes by read-only sessions and to protected paths
+func (s *sftpSession) checkWritable(name string) error {
+	if !s.write {
+		s.audit(models.SFTPAuditDenied, name, "", 0, "read-only")
+		return sftp.ErrSSHFxPermissionDenied
+	}
+	if isProtectedPath(name) {
+		s.audit(models.SFTPAuditDenied, name, "", 0, "protected path")
+		return sftp.ErrSSHFxPermissionDenied
+	}
+	return s.checkPath(name)
+}
+
+// reserve accounts n more bytes of the server directory; false if that exceeds the quota
+func (s *sftpSession) reserve(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.quota > 0 && s.usage+n > s.quota {
+		return false
+	}
+	s.usage += n
+	return true
+}
+
+// release accounts n bytes freed in the server directory
+func (s *sftpSession) release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage -= n
+	if s.usage < 0 {
+		s.usage = 0
+	}
+}
+
+func (s *sftpSession) audit(action models.SFTPAuditAction, filePath, target string, bytes int64, detail string) {
+	entry := &models.SFTPAuditEntry{
+		ServerID:   s.serverID,
+		UserID:     s.userID,
+		KeyID:      s.keyID,
+		Action:     action,
+		Path:       filePath,
+		Target:     target,
+		Bytes:      bytes,
+		Detail:     detail,
+		RemoteAddr: s.remoteAddr,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.gateway.repo.CreateAuditEntry(entry); err != nil {
+		logger.Warn("SFTP-GATEWAY: Failed to write audit entry", map[string]interface{}{
+			"server_id": s.serverID,
+			"action":    string(action),
+			"error":     err.Error(),
+		})
+	}
+}
+
+// sftpQuotaFile is a file opened for writing that stops growing at the quota
+type sftpQuotaFile struct {
+	sftpFile
+	session *sftpSession
+	path    string
+
+	mu      sync.Mutex
+	size    int64 // Current file size
+	written int64
+	changed bool // Created or truncated on open, audited even without writes
+}
+
+func (f *sftpQuotaFile) WriteAt(p []byte, off int64) (int, error) {
+	f.mu.Lock()
+	if end := off + int64(len(p)); end > f.size {
+		if !f.session.reserve(end - f.size) {
+			f.mu.Unlock()
+			return 0, errSFTPQuotaExceeded
+		}
+		f.size = end
+	}
+	f.mu.Unlock()
+
+	n, err := f.sftpFile.WriteAt(p, off)
+	f.mu.Lock()
+	f.written += int64(n)
+	f.mu.Unlock()
+	return n, err
+}
+
+func (f *sftpQuotaFile) Close() error {
+	err := f.sftpFile.Close()
+	f.mu.Lock()
+	written := f.written
+	f.mu.Unlock()
+	if written > 0 || f.changed {
+		f.session.audit(models.SFTPAuditUpload, f.path, "", written, "")
+	}
+	return err
+}
+
+// sftpLister serves file infos to the SFTP server in pages
+type sftpLister []os.FileInfo
+
+func (l sftpLister) ListAt(infos []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(infos, l[offset:])
+	if n < len(infos) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/audit"
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// sshKeyCheckInterval is how often retiring keys and the rotation schedule are checked
+	sshKeyCheckInterval = time.Hour
+	// sshKeyNodeTimeout bounds pushing or removing a key on one node
+	sshKeyNodeTimeout = 30 * time.Second
+	// sshKeyDefaultGracePeriod applies if SSH_KEY_GRACE_PERIOD is not a valid duration
+	sshKeyDefaultGracePeriod = 24 * time.Hour
+)
+
+var (
+	// ErrSSHKeyRotationUnavailable is returned without remote node access (no SSH key configured)
+	ErrSSHKeyRotationUnavailable = errors.New("SSH key rotation needs remote node access (SSH_PRIVATE_KEY_PATH)")
+	// ErrSSHKeyRotationRunning is returned while another rotation is in progress
+	ErrSSHKeyRotationRunning = errors.New("an SSH key rotation is already running")
+	// ErrSSHKeyPushFailed is wrapped when a node didn't accept the new key; the old key stays active
+	ErrSSHKeyPushFailed = errors.New("new SSH key could not be installed on every node")
+)
+
+// NodeKeyInstaller manages the authorized keys of the worker nodes (implemented by docker.RemoteDockerClient)
+type NodeKeyInstaller interface {
+	AddAuthorizedKey(ctx context.Context, node *docker.RemoteNode, authorizedKey string) error
+	RemoveAuthorizedKey(ctx context.Context, node *docker.RemoteNode, authorizedKey string) error
+	VerifyKey(ctx context.Context, node *docker.RemoteNode, keyData []byte) error
+	SetPrivateKey(keyData []byte) error
+	PublicKey() (string, error)
+}
+
+// SSHKeyService rotates the keypair the conductor uses to reach the worker nodes. A rotation
+// generates an ed25519 keypair for the environment (SSH_KEY_ENVIRONMENT), adds the public key on
+// every registered node, checks that each node accepts it and only then switches the SSH pool and
+// cloud-init of new nodes to it. The previous key is removed from the nodes after
+// SSH_KEY_GRACE_PERIOD. Rotations and retirements are recorded in the audit log.
+type SSHKeyService struct {
+	repo        *repository.SSHKeyRepository
+	installer   NodeKeyInstaller              // Nil without remote node access
+	nodes       func() []*docker.RemoteNode   // Registered remote nodes
+	setNodeKeys func(authorizedKeys []string) // Keys cloud-init puts on new nodes
+	audit       *AuditService
+
+	environment string
+	grace       time.Duration
+	interval    time.Duration // Automatic rotation, 0 = only via API
+	key         []byte        // AES-256 key for private keys at rest
+
+	mu      sync.Mutex // Serializes rotations and retirements
+	active  *models.SSHKey
+	rotated time.Time // Activation of the current key (or service start for the built-in key)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSSHKeyService creates a new SSH key rotation service for the conductor's nodes
+func NewSSHKeyService(repo *repository.SSHKeyRepository, cond *conductor.Conductor, auditService *AuditService, cfg *config.Config) *SSHKeyService {
+	var installer NodeKeyInstaller
+	if cond.RemoteClient != nil {
+		installer = cond.RemoteClient
+	}
+	nodes := func() []*docker.RemoteNode {
+		var remote []*docker.RemoteNode
+		for _, node := range cond.NodeRegistry.GetAllNodes() {
+			if node.Labels["status"] == "provisioning" {
+				continue // Placeholder, the VM doesn't exist yet
+			}
+			if remoteNode, err := cond.GetRemoteNode(node.ID); err == nil {
+				remote = append(remote, remoteNode)
+			}
+		}
+		return remote
+	}
+	return newSSHKeyService(repo, installer, nodes, cond.SetNodeAuthorizedKeys, auditService, cfg)
+}
+
+// newSSHKeyService wires the service to its node access
+func newSSHKeyService(repo *repository.SSHKeyRepository, installer NodeKeyInstaller, nodes func() []*docker.RemoteNode, setNodeKeys func([]string), auditService *AuditService, cfg *config.Config) *SSHKeyService {
+	grace, err := time.ParseDuration(cfg.SSHKeyGracePeriod)
+	if err != nil || grace < 0 {
+		grace = sshKeyDefaultGracePeriod
+	}
+	interval, err := time.ParseDuration(cfg.SSHKeyRotationInterval)
+	if err != nil || interval < 0 {
+		interval = 0
+	}
+
+	keySource := cfg.SSHKeyEncryptionKey
+	if keySource == "" {
+		keySource = "ssh-keys:" + cfg.JWTSecret
+	}
+	key := sha256.Sum256([]byte(keySource))
+
+	environment := cfg.SSHKeyEnvironment
+	if environment == "" {
+		environment = "production"
+	}
+
+	return &SSHKeyService{
+		repo:        repo,
+		installer:   installer,
+		nodes:       nodes,
+		setNodeKeys: setNodeKeys,
+		audit:       auditService,
+		environment: environment,
+		grace:       grace,
+		interval:    interval,
+		key:         key[:],
+		rotated:     time.Now(),
+	}
+}
+
+// Init switches the SSH pool and cloud-init to the active key of the environment, if one was generated
+func (s *SSHKeyService) Init() error {
+	if s.installer == nil {
+		return nil
+	}
+	active, err := s.repo.FindActive(s.environment)
+	if err != nil {
+		return fmt.Errorf("failed to load active SSH key: %w", err)
+	}
+	if active == nil {
+		return nil // Keep using SSH_PRIVATE_KEY_PATH and the built-in cloud-init key
+	}
+
+	privateKey, err := s.decrypt(active.PrivateKey)
+	if err != nil {
+		return err
+	}
+	if err := s.installer.SetPrivateKey(privateKey); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active = active
+	if active.ActivatedAt != nil {
+		s.rotated = *active.ActivatedAt
+	}
+	s.setNodeKeys([]string{active.PublicKey})
+
+	logger.Info("SSH-KEYS: Using managed node key", map[string]interface{}{
+		"environment": s.environment,
+		"fingerprint": active.Fingerprint,
+	})
+	return nil
+}
+
+// List returns the keys of the environment, newest first
+func (s *SSHKeyService) List() ([]models.SSHKey, error) {
+	return s.repo.FindByEnvironment(s.environment)
+}
+
+// Rotate generates a new keypair, installs it on all nodes and makes it the active key
+// userID is empty for scheduled rotations
+func (s *SSHKeyService) Rotate(ctx context.Context, userID string) (*models.SSHKey, error) {
+	if s.installer == nil {
+		return nil, ErrSSHKeyRotationUnavailable
+	}
+	if !s.mu.TryLock() {
+		return nil, ErrSSHKeyRotationRunning
+	}
+	defer s.mu.Unlock()
+
+	previousKey, _ := s.installer.PublicKey() // Before the switch; only used on the first rotation
+	publicKey, privateKey, err := generateNodeKey(s.environment)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.encrypt(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return nil, err
+	}
+	key := &models.SSHKey{
+		Environment: s.environment,
+		Fingerprint: ssh.FingerprintSHA256(parsed),
+		PublicKey:   publicKey,
+		PrivateKey:  encrypted,
+		Status:      models.SSHKeyPending,
+		CreatedBy:   userID,
+	}
+	if err := s.repo.Create(key); err != nil {
+		return nil, fmt.Errorf("failed to store SSH key: %w", err)
+	}
+
+	logger.Info("SSH-KEYS: Rotating node key", map[string]interface{}{
+		"environment": s.environment,
+		"fingerprint": key.Fingerprint,
+	})
+
+	// Install and verify on every node before anything switches to the new key
+	nodes := s.nodes()
+	var installed []*docker.RemoteNode
+	var failures []string
+	for _, node := range nodes {
+		nodeCtx, cancel := context.WithTimeout(ctx, sshKeyNodeTimeout)
+		err := s.installer.AddAuthorizedKey(nodeCtx, node, publicKey)
+		if err == nil {
+			installed = append(installed, node)
+			err = s.installer.VerifyKey(nodeCtx, node, privateKey)
+		}
+		cancel()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", node.ID, err))
+		}
+	}
+	key.NodesUpdated, key.NodesFailed = len(nodes)-len(failures), len(failures)
+
+	before := s.keySummary(s.active)
+	if len(failures) > 0 {
+		// Roll back: the new key must not stay authorized anywhere
+		for _, node := range installed {
+			nodeCtx, cancel := context.WithTimeout(context.Background(), sshKeyNodeTimeout)
+			if err := s.installer.RemoveAuthorizedKey(nodeCtx, node, publicKey); err != nil {
+				logger.Warn("SSH-KEYS: Failed to remove the rejected key again", map[string]interface{}{
+					"node_id": node.ID,
+					"error":   err.Error(),
+				})
+			}
+			cancel()
+		}
+		key.Status, key.PrivateKey, key.LastError = models.SSHKeyFailed, "", strings.Join(failures, "; ")
+		s.saveKey(key)
+		s.recordAudit(audit.ActionSSHKeyRotate, userID, key, before, nil, "failed", key.LastError)
+		return key, fmt.Errorf("%w: %s", ErrSSHKeyPushFailed, key.LastError)
+	}
+
+	if err := s.installer.SetPrivateKey(privateKey); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	key.Status, key.ActivatedAt = models.SSHKeyActive, &now
+	s.saveKey(key)
+
+	retireAfter := now.Add(s.grace)
+	if previous := s.active; previous != nil {
+		previous.Status, previous.RetireAfter = models.SSHKeyRetiring, &retireAfter
+		s.saveKey(previous)
+	} else if previousKey != "" {
+		// First rotation: the key from SSH_PRIVATE_KEY_PATH is retired like a managed one
+		s.retireLater(previousKey, retireAfter)
+	}
+	s.active, s.rotated = key, now
+	s.setNodeKeys([]string{publicKey})
+
+	s.recordAudit(audit.ActionSSHKeyRotate, userID, key, before, s.keySummary(key), "success", "")
+	logger.Info("SSH-KEYS: Node key rotated", map[string]interface{}{
+		"environment": s.environment,
+		"fingerprint": key.Fingerprint,
+		"nodes":       len(nodes),
+		"grace":       s.grace.String(),
+	})
+	return key, nil
+}
+
+// Start retires keys after their grace period and rotates on schedule
+func (s *SSHKeyService) Start() {
+	if s.installer == nil {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(sshKeyCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.retireDue()
+				s.rotateIfDue()
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the worker
+func (s *SSHKeyService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// rotateIfDue rotates when SSH_KEY_ROTATION_INTERVAL passed since the last rotation
+func (s *SSHKeyService) rotateIfDue() {
+	s.mu.Lock()
+	due := s.interval > 0 && time.Since(s.rotated) >= s.interval
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+	if _, err := s.Rotate(s.ctx, ""); err != nil {
+		logger.Warn("SSH-KEYS: Scheduled rotation failed, keeping the current key", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// retireDue removes keys whose grace period ended from all nodes
+func (s *SSHKeyService) retireDue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys, err := s.repo.FindRetiringBefore(s.environment, time.Now())
+	if err != nil {
+		logger.Warn("SSH-KEYS: Failed to load retiring keys", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	for i := range keys {
+		s.retire(&keys[i])
+	}
+}
+
+// retire removes a key from all nodes; unreachable nodes are logged and keep the key until
+// they are re-provisioned
+func (s *SSHKeyService) retire(key *models.SSHKey) {
+	var failures []string
+	nodes := s.nodes()
+	for _, node := range nodes {
+		ctx, cancel := context.WithTimeout(context.Background(), sshKeyNodeTimeout)
+		if err := s.installer.RemoveAuthorizedKey(ctx, node, key.PublicKey); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", node.ID, err))
+		}
+		cancel()
+	}
+
+	before := s.keySummary(key)
+	now := time.Now()
+	key.Status, key.RetiredAt, key.PrivateKey = models.SSHKeyRetired, &now, ""
+	key.NodesFailed, key.LastError = len(failures), strings.Join(failures, "; ")
+	s.saveKey(key)
+
+	result := "success"
+	if len(failures) > 0 {
+		result = "failed"
+		logger.Warn("SSH-KEYS: Retired key could not be removed from every node", map[string]interface{}{
+			"fingerprint": key.Fingerprint,
+			"failures":    key.LastError,
+		})
+	}
+	s.recordAudit(audit.ActionSSHKeyRetire, "", key, before, s.keySummary(key), result, key.LastError)
+	logger.Info("SSH-KEYS: Retired node key", map[string]interface{}{
+		"fingerprint": key.Fingerprint,
+		"nodes":       len(nodes),
+	})
+}
+
+// retireLater records an unmanaged key (without private key) for removal from the nodes
+func (s *SSHKeyService) retireLater(authorizedKey string, retireAfter time.Time) {
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+	if err != nil {
+		return
+	}
+	key := &models.SSHKey{
+		Environment: s.environment,
+		Fingerprint: ssh.FingerprintSHA256(parsed),
+		PublicKey:   authorizedKey,
+		Status:      models.SSHKeyRetiring,
+		RetireAfter: &retireAfter,
+	}
+	if err := s.repo.Create(key); err != nil {
+		logger.Warn("SSH-KEYS: Failed to record the previous key for retirement", map[string]interface{}{
+			"fingerprint": key.Fingerprint,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// saveKey persists a key state change
+func (s *SSHKeyService) saveKey(key *models.SSHKey) {
+	if err := s.repo.Update(key); err != nil {
+		logger.Warn("SSH-KEYS: Failed to save key state", map[string]interface{}{
+			"fingerprint": key.Fingerprint,
+			"status":      key.Status,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// recordAudit adds a rotation or retirement to the audit log
+func (s *SSHKeyService) recordAudit(action audit.ActionType, userID string, key *models.SSHKey, before, after interface{}, result, errMsg string) {
+	decisionBy := "manual"
+	if userID == "" {
+		decisionBy = "ssh_key_rotation"
+	}
+	s.audit.Record(audit.AuditEntry{
+		Action:       action,
+		ActorID:      userID,
+		DecisionBy:   decisionBy,
+		ResourceType: "ssh_key",
+		ResourceID:   key.ID,
+		Before:       before,
+		After:        after,
+		Result:       result,
+		Error:        errMsg,
+	})
+}
+
+// keySummary is the audit snapshot of a key (nil stays nil)
+func (s *SSHKeyService) keySummary(key *models.SSHKey) interface{} {
+	if key == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"environment": key.Environment,
+		"fingerprint": key.Fingerprint,
+		"status":      key.Status,
+	}
+}
+
+// encrypt encrypts a private key with AES-GCM (base64 of nonce + ciphertext)
+func (s *SSHKeyService) encrypt(plain []byte) (string, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plain, nil)), nil
+}
+
+// decrypt reverses encrypt
+func (s *SSHKeyService) decrypt(encrypted string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypted SSH key: %w", err)
+	}
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("invalid encrypted SSH key")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt SSH key (encryption key changed?): %w", err)
+	}
+	return plain, nil
+}
+
+// generateNodeKey creates an ed25519 keypair: the authorized_keys line and the OpenSSH PEM
+func generateNodeKey(environment string) (string, []byte, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", nil, err
+	}
+	comment := fmt.Sprintf("payperplay-conductor-%s-%s", environment, time.Now().UTC().Format("20060102"))
+
+	block, err := ssh.MarshalPrivateKey(privateKey, comment)
+	if err != nil {
+		return "", nil, err
+	}
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return "", nil, err
+	}
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublicKey))) + " " + comment
+	return authorizedKey, pem.EncodeToMemory(block), nil
+}
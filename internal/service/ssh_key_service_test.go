@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"golang.org/x/crypto/ssh"
+)
+
+// fakeKeyInstaller records the authorized keys per node
+type fakeKeyInstaller struct {
+	authorized map[string][]string
+	rejects    string // Node that refuses new keys
+	privateKey []byte
+}
+
+func (f *fakeKeyInstaller) AddAuthorizedKey(ctx context.Context, node *docker.RemoteNode, key string) error {
+	f.authorized[node.ID] = append(f.authorized[node.ID], key)
+	return nil
+}
+
+func (f *fakeKeyInstaller) RemoveAuthorizedKey(ctx context.Context, node *docker.RemoteNode, key string) error {
+	var kept []string
+	for _, k := range f.authorized[node.ID] {
+		if k != key {
+			kept = append(kept, k)
+		}
+	}
+	f.authorized[node.ID] = kept
+	return nil
+}
+
+func (f *fakeKeyInstaller) VerifyKey(ctx context.Context, node *docker.RemoteNode, keyData []byte) error {
+	if node.ID == f.rejects {
+		return errors.New("permission denied (publickey)")
+	}
+	return nil
+}
+
+func (f *fakeKeyInstaller) SetPrivateKey(keyData []byte) error {
+	f.privateKey = keyData
+	return nil
+}
+
+func (f *fakeKeyInstaller) PublicKey() (string, error) {
+	return "", errors.New("no key file")
+}
+
+func TestSSHKeyRotation(t *testing.T) {
+	installer := &fakeKeyInstaller{authorized: map[string][]string{}}
+	nodes := []*docker.RemoteNode{{ID: "node-1", IPAddress: "10.0.0.1", SSHUser: "root"}, {ID: "node-2", IPAddress: "10.0.0.2", SSHUser: "root"}}
+	var cloudInitKeys []string
+	s := newSSHKeyService(repository.NewSSHKeyRepository(newDryRunDB(t)), installer,
+		func() []*docker.RemoteNode { return nodes },
+		func(keys []string) { cloudInitKeys = keys },
+		nil, &config.Config{SSHKeyEnvironment: "staging", JWTSecret: "secret"})
+
+	key, err := s.Rotate(context.Background(), "admin-1")
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if key.Status != models.SSHKeyActive || key.NodesUpdated != 2 || !strings.Contains(key.PublicKey, "payperplay-conductor-staging-") {
+		t.Errorf("rotated key = %+v", key)
+	}
+	for _, node := range nodes {
+		if got := installer.authorized[node.ID]; len(got) != 1 || got[0] != key.PublicKey {
+			t.Errorf("%s authorized keys = %v", node.ID, got)
+		}
+	}
+	if len(cloudInitKeys) != 1 || cloudInitKeys[0] != key.PublicKey {
+		t.Errorf("cloud-init keys = %v", cloudInitKeys)
+	}
+
+	// The pool got the key that is stored encrypted
+	stored, err := s.decrypt(key.PrivateKey)
+	if err != nil || string(stored) != string(installer.privateKey) {
+		t.Fatalf("stored private key doesn't match the pool key (err %v)", err)
+	}
+	if _, err := ssh.ParsePrivateKey(stored); err != nil {
+		t.Errorf("generated private key doesn't parse: %v", err)
+	}
+
+	// A node that rejects the next key keeps everything on the current one
+	installer.rejects = "node-2"
+	failed, err := s.Rotate(context.Background(), "admin-1")
+	if !errors.Is(err, ErrSSHKeyPushFailed) || failed.Status != models.SSHKeyFailed || failed.NodesFailed != 1 {
+		t.Fatalf("rotation with a rejecting node = %+v, %v", failed, err)
+	}
+	if got := installer.authorized["node-1"]; len(got) != 1 || got[0] != key.PublicKey {
+		t.Errorf("rejected key not rolled back on node-1: %v", got)
+	}
+	if string(installer.privateKey) != string(stored) || s.active != key || cloudInitKeys[0] != key.PublicKey {
+		t.Error("failed rotation switched away from the active key")
+	}
+}
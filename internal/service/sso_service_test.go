@@ -0,0 +1,430 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/payperplay/hosting/internal/models"
+	"gorm.io/gorm"
+)
+
+// fakeSSOStore keeps SSO data in memory
+type fakeSSOStore struct {
+	connections map[string]*models.SSOConnection
+	identities  map[string]*models.SSOIdentity // orgID + "/" + subject
+	domains     []*models.SSODomain
+	links       []*models.SSOPendingLink
+}
+
+func newFakeSSOStore() *fakeSSOStore {
+	return &fakeSSOStore{
+		connections: make(map[string]*models.SSOConnection),
+		identities:  make(map[string]*models.SSOIdentity),
+	}
+}
+
+func (f *fakeSSOStore) FindConnection(orgID string) (*models.SSOConnection, error) {
+	if conn, ok := f.connections[orgID]; ok {
+		return conn, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeSSOStore) FindEnabledConnections() ([]models.SSOConnection, error) {
+	var conns []models.SSOConnection
+	for _, conn := range f.connections {
+		if conn.Enabled {
+			conns = append(conns, *conn)
+		}
+	}
+	return conns, nil
+}
+
+func (f *fakeSSOStore) SaveConnection(conn *models.SSOConnection) error {
+	f.connections[conn.OrganizationID] = conn
+	return nil
+}
+
+func (f *fakeSSOStore) DeleteConnection(orgID string) error {
+	delete(f.connections, orgID)
+	return nil
+}
+
+func (f *fakeSSOStore) FindEnforcedConnectionsForUser(userID string) ([]models.SSOConnection, error) {
+	return nil, nil
+}
+
+func (f *fakeSSOStore) FindIdentity(orgID, subject string) (*models.SSOIdentity, error) {
+	if identity, ok := f.identities[orgID+"/"+subject]; ok {
+		return identity, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeSSOStore) SaveIdentity(identity *models.SSOIdentity) error {
+	f.identities[identity.OrganizationID+"/"+identity.Subject] = identity
+	return nil
+}
+
+func (f *fakeSSOStore) CreateState(state *models.SSOLoginState) error { return nil }
+func (f *fakeSSOStore) ConsumeState(state string) (*models.SSOLoginState, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+func (f *fakeSSOStore) DeleteExpiredStates(before time.Time) error { return nil }
+
+func (f *fakeSSOStore) FindDomains(orgID string) ([]models.SSODomain, error) {
+	var domains []models.SSODomain
+	for _, d := range f.domains {
+		if d.OrganizationID == orgID {
+			domains = append(domains, *d)
+		}
+	}
+	return domains, nil
+}
+
+func (f *fakeSSOStore) FindDomain(orgID, domain string) (*models.SSODomain, error) {
+	for _, d := range f.domains {
+		if d.OrganizationID == orgID && d.Domain == domain {
+			return d, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeSSOStore) FindVerifiedDomain(domain string) (*models.SSODomain, error) {
+	for _, d := range f.domains {
+		if d.Domain == domain && d.IsVerified() {
+			return d, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeSSOStore) SaveDomain(domain *models.SSODomain) error {
+	for i, d := range f.domains {
+		if d.OrganizationID == domain.OrganizationID && d.Domain == domain.Domain {
+			f.domains[i] = domain
+			return nil
+		}
+	}
+	f.domains = append(f.domains, domain)
+	return nil
+}
+
+func (f *fakeSSOStore) DeleteDomain(orgID, domain string) error {
+	for i, d := range f.domains {
+		if d.OrganizationID == orgID && d.Domain == domain {
+			f.domains = append(f.domains[:i], f.domains[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (f *fakeSSOStore) CreatePendingLink(link *models.SSOPendingLink) error {
+	link.ID = uint(len(f.links) + 1)
+	f.links = append(f.links, link)
+	return nil
+}
+
+func (f *fakeSSOStore) FindPendingLink(tokenHash string) (*models.SSOPendingLink, error) {
+	for _, link := range f.links {
+		if link.TokenHash == tokenHash || link.EmailTokenHash == tokenHash {
+			return link, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeSSOStore) DeletePendingLink(id uint) error                  { return nil }
+func (f *fakeSSOStore) DeleteExpiredPendingLinks(before time.Time) error { return nil }
+
+// fakeUserStore keeps users in memory by email
+type fakeUserStore struct {
+	users map[string]*models.User
+}
+
+func (f *fakeUserStore) FindByID(id string) (*models.User, error) {
+	for _, user := range f.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUserStore) FindByEmail(email string) (*models.User, error) {
+	if user, ok := f.users[email]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUserStore) Create(user *models.User) error {
+	if user.ID == "" {
+		user.ID = "user-" + user.Email
+	}
+	f.users[user.Email] = user
+	return nil
+}
+
+func (f *fakeUserStore) Update(user *models.User) error {
+	f.users[user.Email] = user
+	return nil
+}
+
+func verifiedAt() *time.Time {
+	now := time.Now()
+	return &now
+}
+
+func newTestSSOService(t *testing.T, store *fakeSSOStore, users *fakeUserStore) *SSOService {
+	return &SSOService{
+		ssoRepo:         store,
+		userRepo:        users,
+		securityService: NewSecurityService(newDryRunDB(t), nil),
+	}
+}
+
+func TestSSOValidateDomains(t *testing.T) {
+	store := newFakeSSOStore()
+	store.domains = []*models.SSODomain{
+		{OrganizationID: "org-other", Domain: "taken.com", VerifiedAt: verifiedAt()},
+		{OrganizationID: "org-other", Domain: "pending.com"},
+		{OrganizationID: "org-1", Domain: "mine.com", VerifiedAt: verifiedAt()},
+	}
+	svc := newTestSSOService(t, store, &fakeUserStore{users: map[string]*models.User{}})
+
+	tests := []struct {
+		name    string
+		domains []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "normalizes and dedupes", domains: []string{" Acme.COM ", "acme.com"}, want: []string{"acme.com"}},
+		{name: "verified by other organization", domains: []string{"taken.com"}, wantErr: true},
+		{name: "unverified claim of other organization", domains: []string{"pending.com"}, want: []string{"pending.com"}},
+		{name: "verified by same organization", domains: []string{"mine.com"}, want: []string{"mine.com"}},
+		{name: "email instead of domain", domains: []string{"jane@acme.com"}, wantErr: true},
+		{name: "no dot", domains: []string{"localhost"}, wantErr: true},
+		{name: "leading dot", domains: []string{".acme.com"}, wantErr: true},
+		{name: "empty", domains: []string{" "}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.validateDomains("org-1", tt.domains)
+			if tt.wantErr {
+				if !errors.Is(err, models.ErrSSOInvalidConfig) {
+					t.Fatalf("expected ErrSSOInvalidConfig, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSSOProvisionUser(t *testing.T) {
+	conn := &models.SSOConnection{OrganizationID: "org-1", EmailDomains: "acme.com,unverified.com", Enabled: true}
+
+	tests := []struct {
+		name        string
+		claims      jwt.MapClaims
+		wantErr     error
+		wantNew     bool
+		wantLink    bool
+		wantUserID  string
+		wantLinked  bool // Identity stored after the call
+		wantCreated bool // Local user created
+	}{
+		{
+			name:       "known identity signs in",
+			claims:     jwt.MapClaims{"sub": "linked-sub", "email": "old@acme.com", "email_verified": true},
+			wantUserID: "user-linked",
+		},
+		{
+			name:        "new user on verified domain is provisioned",
+			claims:      jwt.MapClaims{"sub": "new-sub", "email": "new@acme.com", "email_verified": true, "preferred_username": "newbie"},
+			wantNew:     true,
+			wantLinked:  true,
+			wantCreated: true,
+		},
+		{
+			name:    "unverified domain is refused",
+			claims:  jwt.MapClaims{"sub": "sub-2", "email": "jane@unverified.com", "email_verified": true},
+			wantErr: models.ErrSSOEmailNotAllowed,
+		},
+		{
+			name:    "domain not claimed by the connection is refused",
+			claims:  jwt.MapClaims{"sub": "sub-3", "email": "jane@gmail.com", "email_verified": true},
+			wantErr: models.ErrSSOEmailNotAllowed,
+		},
+		{
+			name:    "unverified email is refused",
+			claims:  jwt.MapClaims{"sub": "sub-4", "email": "jane@acme.com", "email_verified": false},
+			wantErr: models.ErrSSOEmailNotAllowed,
+		},
+		{
+			name:     "existing local account is not linked automatically",
+			claims:   jwt.MapClaims{"sub": "attacker-sub", "email": "admin@acme.com", "email_verified": true},
+			wantErr:  models.ErrSSOLinkRequired,
+			wantLink: true,
+		},
+		{
+			name:    "missing subject",
+			claims:  jwt.MapClaims{"email": "jane@acme.com", "email_verified": true},
+			wantErr: models.ErrSSOInvalidIDToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeSSOStore()
+			store.domains = []*models.SSODomain{
+				{OrganizationID: "org-1", Domain: "acme.com", VerifiedAt: verifiedAt()},
+				{OrganizationID: "org-1", Domain: "unverified.com"},
+			}
+			store.identities["org-1/linked-sub"] = &models.SSOIdentity{OrganizationID: "org-1", Subject: "linked-sub", UserID: "user-linked"}
+			users := &fakeUserStore{users: map[string]*models.User{
+				"old@acme.com":   {ID: "user-linked", Email: "old@acme.com", IsActive: true},
+				"admin@acme.com": {ID: "user-admin", Email: "admin@acme.com", IsActive: true, IsAdmin: true},
+			}}
+			svc := newTestSSOService(t, store, users)
+
+			user, isNew, err := svc.provisionUser(conn, tt.claims)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if tt.wantLink {
+					var linkErr *SSOLinkRequiredError
+					if !errors.As(err, &linkErr) || linkErr.LinkToken == "" {
+						t.Fatalf("expected SSOLinkRequiredError with a link token, got %v", err)
+					}
+					if len(store.links) != 1 || store.links[0].UserID != "user-admin" {
+						t.Fatalf("expected one pending link for the existing account, got %+v", store.links)
+					}
+					if _, err := store.FindIdentity("org-1", "attacker-sub"); err == nil {
+						t.Fatal("identity must not be linked before the account holder confirms")
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if isNew != tt.wantNew {
+				t.Errorf("isNew = %v, want %v", isNew, tt.wantNew)
+			}
+			if tt.wantUserID != "" && user.ID != tt.wantUserID {
+				t.Errorf("user = %s, want %s", user.ID, tt.wantUserID)
+			}
+			if tt.wantCreated {
+				if _, err := users.FindByEmail(getStringField(tt.claims, "email")); err != nil {
+					t.Error("expected the user to be created")
+				}
+			}
+			if tt.wantLinked {
+				if _, err := store.FindIdentity("org-1", getStringField(tt.claims, "sub")); err != nil {
+					t.Error("expected the identity to be linked")
+				}
+			}
+		})
+	}
+}
+
+func TestSSORedeemLink(t *testing.T) {
+	holder := &models.User{ID: "user-1", Email: "jane@acme.com", IsActive: true}
+	if err := holder.SetPassword("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		password string
+		expired  bool
+		wantErr  error
+	}{
+		{name: "browser token with password", token: "browser", password: "correct horse"},
+		{name: "browser token with wrong password", token: "browser", password: "guess", wantErr: models.ErrInvalidCredentials},
+		{name: "browser token without password", token: "browser", wantErr: models.ErrInvalidCredentials},
+		{name: "emailed token alone", token: "emailed"},
+		{name: "unknown token", token: "unknown", wantErr: models.ErrSSOLinkInvalid},
+		{name: "expired link", token: "emailed", expired: true, wantErr: models.ErrSSOLinkInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeSSOStore()
+			expiresAt := time.Now().Add(time.Hour)
+			if tt.expired {
+				expiresAt = time.Now().Add(-time.Minute)
+			}
+			store.links = []*models.SSOPendingLink{{
+				ID:             1,
+				TokenHash:      hashSSOLinkToken("browser"),
+				EmailTokenHash: hashSSOLinkToken("emailed"),
+				OrganizationID: "org-1",
+				Subject:        "sub-1",
+				UserID:         holder.ID,
+				ExpiresAt:      expiresAt,
+			}}
+			user := *holder
+			svc := newTestSSOService(t, store, &fakeUserStore{users: map[string]*models.User{user.Email: &user}})
+
+			link, got, err := svc.redeemLink(tt.token, tt.password, "test", "127.0.0.1")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if link.Subject != "sub-1" || got.ID != holder.ID {
+				t.Fatalf("unexpected link %+v for user %s", link, got.ID)
+			}
+		})
+	}
+}
+
+func TestSSOMarkDomainVerified(t *testing.T) {
+	store := newFakeSSOStore()
+	store.domains = []*models.SSODomain{
+		{OrganizationID: "org-owner", Domain: "acme.com", VerifiedAt: verifiedAt()},
+		{OrganizationID: "org-squatter", Domain: "acme.com"},
+		{OrganizationID: "org-1", Domain: "free.com", Token: "abc"},
+	}
+	svc := newTestSSOService(t, store, &fakeUserStore{users: map[string]*models.User{}})
+
+	squatter, _ := store.FindDomain("org-squatter", "acme.com")
+	if err := svc.markDomainVerified(squatter, "admin-1"); !errors.Is(err, models.ErrSSODomainTaken) {
+		t.Fatalf("expected ErrSSODomainTaken, got %v", err)
+	}
+
+	free, _ := store.FindDomain("org-1", "free.com")
+	if err := svc.markDomainVerified(free, models.SSODomainVerifiedByDNS); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !free.IsVerified() || free.VerifiedBy != models.SSODomainVerifiedByDNS {
+		t.Fatalf("domain not verified: %+v", free)
+	}
+	if free.TXTRecordValue() != "payperplay-sso-verification=abc" || free.TXTRecordName() != "_payperplay-sso.free.com" {
+		t.Fatalf("unexpected TXT record %s = %s", free.TXTRecordName(), free.TXTRecordValue())
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/conductor"
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/velocity"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// reconcileAttempts is how often a failing phase is tried before it is reported as failed
+	reconcileAttempts = 3
+	// reconcileRetryDelay is the wait before the first retry, doubled for every further one
+	reconcileRetryDelay = 2 * time.Second
+)
+
+// Reconciliation triggers
+const (
+	ReconcileTriggerStartup = "startup"
+	ReconcileTriggerManual  = "manual"
+)
+
+// ErrReconcileRunning is returned if a reconciliation is already in progress
+var ErrReconcileRunning = errors.New("state reconciliation is already running")
+
+// ReconcilePhaseStatus is the outcome of one reconciliation phase
+type ReconcilePhaseStatus string
+
+const (
+	ReconcilePhaseSucceeded ReconcilePhaseStatus = "succeeded"
+	ReconcilePhaseFailed    ReconcilePhaseStatus = "failed"  // Failed on every attempt
+	ReconcilePhaseSkipped   ReconcilePhaseStatus = "skipped" // Not applicable, or a dependency didn't succeed
+)
+
+// ReconcilePhaseResult is the outcome of one phase of a reconciliation
+type ReconcilePhaseResult struct {
+	Name       string               `json:"name"`
+	Status     ReconcilePhaseStatus `json:"status"`
+	Attempts   int                  `json:"attempts"`
+	Error      string               `json:"error,omitempty"`       // Last error if failed
+	SkipReason string               `json:"skip_reason,omitempty"` // Why the phase didn't run
+	DurationMs int64                `json:"duration_ms"`
+}
+
+// ReconcileReport is the result of one reconciliation run
+type ReconcileReport struct {
+	Trigger    string                 `json:"trigger"` // startup, manual
+	StartedAt  time.Time              `json:"started_at"`
+	FinishedAt time.Time              `json:"finished_at"`
+	Phases     []ReconcilePhaseResult `json:"phases"`
+	Failed     []string               `json:"failed"` // Names of the failed phases
+}
+
+// reconcilePhase is one step of the reconciliation
+type reconcilePhase struct {
+	name        string
+	dependsOn   []string // Phases that must have succeeded before (declared earlier)
+	startupOnly bool     // Restores state files that are only valid right after a restart
+	disabled    string   // Reason the phase doesn't apply in this setup, "" = enabled
+	run         func() error
+}
+
+// StateReconciler brings the conductor state (container and node registries, start queue) and the
+// Velocity proxy in line with the database and the nodes after a restart, and on demand.
+// Phases run in declaration order and are retried with backoff. A phase whose dependency didn't
+// succeed is skipped; the other phases still run, so one unreachable system doesn't block the rest.
+type StateReconciler struct {
+	phases     []reconcilePhase
+	retryDelay time.Duration
+
+	running sync.Mutex
+	mu      sync.RWMutex
+	last    *ReconcileReport
+}
+
+// NewStateReconciler creates the reconciler; velocityClient may be nil and stateDir holds the
+// node and container state files written on shutdown
+func NewStateReconciler(cond *conductor.Conductor, dockerService *docker.DockerService, serverRepo *repository.ServerRepository, velocityClient *velocity.RemoteVelocityClient, stateDir string, cfg *config.Config) *StateReconciler {
+	noCloud := ""
+	if cond.CloudProvider == nil {
+		noCloud = "no cloud provider configured"
+	}
+	noVelocity := ""
+	if velocityClient == nil {
+		noVelocity = "no Velocity API configured"
+	}
+
+	return newStateReconciler([]reconcilePhase{
+		{
+			// Prevents OOM after restarts: RAM of the running local containers is allocated again
+			name: "local_containers",
+			run: func() error {
+				return cond.SyncRunningContainers(dockerService, serverRepo)
+			},
+		},
+		{
+			// Queued servers keep their status in the database (scaling isn't triggered yet)
+			name: "start_queue",
+			run: func() error {
+				return cond.SyncQueuedServers(serverRepo, false)
+			},
+		},
+		{
+			// Nodes from the state file get a recovery grace period against immediate scale-down
+			name:        "node_state",
+			startupOnly: true,
+			disabled:    noCloud,
+			run: func() error {
+				return cond.RestoreNodesFromState(filepath.Join(stateDir, "node_state.json"))
+			},
+		},
+		{
+			// Discovers nodes at Hetzner that aren't registered yet
+			name:     "worker_nodes",
+			disabled: noCloud,
+			run: func() error {
+				return cond.SyncExistingWorkerNodes(false)
+			},
+		},
+		{
+			// Keeps the timing information (sleeping timers) of the containers; without the restored
+			// nodes their containers would count as lost
+			name:        "container_state",
+			dependsOn:   []string{"node_state"},
+			startupOnly: true,
+			disabled:    noCloud,
+			run: func() error {
+				_, err := cond.RestoreContainersFromState(filepath.Join(stateDir, "container_state.json"), serverRepo)
+				return err
+			},
+		},
+		{
+			// Verifies the containers that actually run on the worker nodes
+			name:     "remote_containers",
+			disabled: noCloud,
+			run: func() error {
+				return cond.SyncRemoteNodeContainers(serverRepo)
+			},
+		},
+		{
+			// Prevents "server not found" on the proxy for servers that kept running
+			name:     "velocity",
+			disabled: noVelocity,
+			run: func() error {
+				return registerRunningServers(cond, serverRepo, velocityClient, cfg)
+			},
+		},
+	})
+}
+
+func newStateReconciler(phases []reconcilePhase) *StateReconciler {
+	return &StateReconciler{phases: phases, retryDelay: reconcileRetryDelay}
+}
+
+// LastReport returns the report of the last finished reconciliation, nil before the first one
+func (r *StateReconciler) LastReport() *ReconcileReport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.last
+}
+
+// Run reconciles all phases; startup-only phases run only for ReconcileTriggerStartup.
+// Cancelling ctx skips the remaining retries and phases. Failed phases are listed in the report,
+// the error is only set if the run couldn't start or was cancelled.
+func (r *StateReconciler) Run(ctx context.Context, trigger string) (*ReconcileReport, error) {
+	if !r.running.TryLock() {
+		return nil, ErrReconcileRunning
+	}
+	defer r.running.Unlock()
+
+	report := &ReconcileReport{
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+		Phases:    make([]ReconcilePhaseResult, 0, len(r.phases)),
+		Failed:    []string{},
+	}
+	logger.Info("RECONCILE: Starting state reconciliation", map[string]interface{}{
+		"trigger": trigger,
+	})
+
+	statuses := make(map[string]ReconcilePhaseStatus, len(r.phases))
+	for _, phase := range r.phases {
+		result := r.runPhase(ctx, phase, trigger == ReconcileTriggerStartup, statuses)
+		statuses[phase.name] = result.Status
+		report.Phases = append(report.Phases, result)
+		if result.Status == ReconcilePhaseFailed {
+			report.Failed = append(report.Failed, phase.name)
+		}
+	}
+	report.FinishedAt = time.Now()
+
+	fields := map[string]interface{}{
+		"trigger":     trigger,
+		"failed":      report.Failed,
+		"duration_ms": report.FinishedAt.Sub(report.StartedAt).Milliseconds(),
+	}
+	if len(report.Failed) > 0 {
+		logger.Warn("RECONCILE: State reconciliation finished with failed phases", fields)
+	} else {
+		logger.Info("RECONCILE: State reconciliation completed", fields)
+	}
+
+	r.mu.Lock()
+	r.last = report
+	r.mu.Unlock()
+	return report, ctx.Err()
+}
+
+// runPhase runs one phase with retries, unless it doesn't apply or a dependency didn't succeed
+func (r *StateReconciler) runPhase(ctx context.Context, phase reconcilePhase, startup bool, statuses map[string]ReconcilePhaseStatus) ReconcilePhaseResult {
+	result := ReconcilePhaseResult{Name: phase.name, Status: ReconcilePhaseSkipped}
+	switch {
+	case phase.disabled != "":
+		result.SkipReason = phase.disabled
+		return result
+	case phase.startupOnly && !startup:
+		result.SkipReason = "only runs on startup"
+		return result
+	}
+	for _, dependency := range phase.dependsOn {
+		if statuses[dependency] != ReconcilePhaseSucceeded {
+			result.SkipReason = fmt.Sprintf("dependency %s didn't succeed", dependency)
+			return result
+		}
+	}
+
+	start := time.Now()
+	defer func() { result.DurationMs = time.Since(start).Milliseconds() }()
+
+	delay := r.retryDelay
+	for result.Attempts < reconcileAttempts {
+		if err := ctx.Err(); err != nil {
+			result.Status, result.Error = ReconcilePhaseFailed, err.Error()
+			return result
+		}
+		result.Attempts++
+		err := phase.run()
+		if err == nil {
+			result.Status, result.Error = ReconcilePhaseSucceeded, ""
+			return result
+		}
+		result.Status, result.Error = ReconcilePhaseFailed, err.Error()
+		logger.Warn("RECONCILE: Phase failed", map[string]interface{}{
+			"phase":   phase.name,
+			"attempt": result.Attempts,
+			"error":   err.Error(),
+		})
+
+		if result.Attempts < reconcileAttempts {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+			}
+			delay *= 2
+		}
+	}
+	return result
+}
+
+// registerRunningServers registers every running server with Velocity again
+// Servers without a node are skipped; servers that couldn't be registered are returned as error.
+func registerRunningServers(cond *conductor.Conductor, serverRepo *repository.ServerRepository, velocityClient *velocity.RemoteVelocityClient, cfg *config.Config) error {
+	runningServers, err := serverRepo.FindByStatus(string(models.StatusRunning))
+	if err != nil {
+		return fmt.Errorf("failed to find running servers: %w", err)
+	}
+
+	registered, failed := 0, 0
+	for _, server := range runningServers {
+		if server.NodeID == "" {
+			logger.Warn("Skipping Velocity registration for server without node assignment", map[string]interface{}{
+				"server_id": server.ID,
+				"name":      server.Name,
+			})
+			continue
+		}
+
+		velocityServerName := fmt.Sprintf("mc-%s", server.ID)
+		serverAddress, err := velocityAddress(cond, &server, cfg)
+		if err != nil {
+			logger.Warn("Failed to get node IP for Velocity registration", map[string]interface{}{
+				"server_id": server.ID,
+				"node_id":   server.NodeID,
+				"error":     err.Error(),
+			})
+			failed++
+			continue
+		}
+
+		if err := velocityClient.RegisterServer(velocityServerName, serverAddress); err != nil {
+			logger.Warn("Failed to re-register server with Velocity", map[string]interface{}{
+				"server_id": server.ID,
+				"error":     err.Error(),
+			})
+			failed++
+			continue
+		}
+		registered++
+		logger.Debug("Server re-registered with Velocity", map[string]interface{}{
+			"server_id": server.ID,
+			"name":      velocityServerName,
+			"address":   serverAddress,
+		})
+	}
+
+	logger.Info("Velocity state sync completed", map[string]interface{}{
+		"total_running": len(runningServers),
+		"registered":    registered,
+		"failed":        failed,
+	})
+	if failed > 0 {
+		return fmt.Errorf("%d of %d running servers not registered", failed, len(runningServers))
+	}
+	return nil
+}
+
+// velocityAddress returns the backend address Velocity reaches server under (node IP and port)
+func velocityAddress(cond *conductor.Conductor, server *models.MinecraftServer, cfg *config.Config) (string, error) {
+	if server.NodeID == "local-node" {
+		return fmt.Sprintf("%s:%d", cfg.ControlPlaneIP, server.Port), nil
+	}
+	remoteNode, err := cond.GetRemoteNode(server.NodeID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%d", remoteNode.IPAddress, server.Port), nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStateReconcilerPhases(t *testing.T) {
+	calls := map[string]int{}
+	phase := func(name string, failures int, dependsOn ...string) reconcilePhase {
+		return reconcilePhase{name: name, dependsOn: dependsOn, run: func() error {
+			calls[name]++
+			if calls[name] <= failures {
+				return errors.New(name + " unreachable")
+			}
+			return nil
+		}}
+	}
+
+	flaky := phase("flaky", 1)
+	down := phase("down", reconcileAttempts)
+	stateFile := phase("state_file", 0)
+	stateFile.startupOnly = true
+	disabled := phase("disabled", 0)
+	disabled.disabled = "not configured"
+	r := newStateReconciler([]reconcilePhase{
+		flaky,
+		down,
+		phase("after_down", 0, "down"),
+		stateFile,
+		phase("after_state_file", 0, "state_file"),
+		disabled,
+		phase("independent", 0),
+	})
+	r.retryDelay = 0
+
+	report, err := r.Run(context.Background(), ReconcileTriggerManual)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := map[string]struct {
+		status   ReconcilePhaseStatus
+		attempts int
+	}{
+		"flaky":            {ReconcilePhaseSucceeded, 2},
+		"down":             {ReconcilePhaseFailed, reconcileAttempts},
+		"after_down":       {ReconcilePhaseSkipped, 0},
+		"state_file":       {ReconcilePhaseSkipped, 0}, // Startup only
+		"after_state_file": {ReconcilePhaseSkipped, 0},
+		"disabled":         {ReconcilePhaseSkipped, 0},
+		"independent":      {ReconcilePhaseSucceeded, 1},
+	}
+	if len(report.Phases) != len(want) {
+		t.Fatalf("report has %d phases, want %d", len(report.Phases), len(want))
+	}
+	for _, result := range report.Phases {
+		if w := want[result.Name]; result.Status != w.status || result.Attempts != w.attempts {
+			t.Errorf("phase %s = %s after %d attempt(s), want %s after %d", result.Name, result.Status, result.Attempts, w.status, w.attempts)
+		}
+	}
+	if !reflect.DeepEqual(report.Failed, []string{"down"}) || report.Phases[1].Error != "down unreachable" {
+		t.Errorf("failed = %v (%q), want only down with its error", report.Failed, report.Phases[1].Error)
+	}
+	if r.LastReport() != report {
+		t.Error("LastReport() doesn't return the finished run")
+	}
+
+	// On startup the state file phases run too
+	report, _ = r.Run(context.Background(), ReconcileTriggerStartup)
+	if report.Phases[3].Status != ReconcilePhaseSucceeded || report.Phases[4].Status != ReconcilePhaseSucceeded {
+		t.Errorf("startup phases = %+v, want the state file phases to run", report.Phases[3:5])
+	}
+}
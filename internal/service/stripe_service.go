@@ -0,0 +1,922 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	stripeAPIBase             = "https://api.stripe.com"
+	stripeWebhookTolerance    = 5 * time.Minute
+	stripeUsageBatchSize      = 500
+	stripeDefaultSyncInterval = 15 * time.Minute
+
+	// billingSuspensionReason is shown on servers suspended for failed payments
+	billingSuspensionReason = "payments failed repeatedly, please update your payment method"
+)
+
+var (
+	ErrStripeDisabled         = errors.New("stripe billing is not enabled")
+	ErrBillingSuspended       = errors.New("account suspended due to failed payments, please update your payment method")
+	ErrInvalidStripeSignature = errors.New("invalid stripe webhook signature")
+
+	errStripeEventProcessed = errors.New("stripe event already processed")
+)
+
+// StripeService integrates BillingService usage sessions with Stripe metered subscriptions
+// Closed UsageSessions are reported as metered usage (1 unit = 1 euro cent), Stripe generates
+// the monthly invoice, and invoice webhooks drive dunning (server suspension after repeated failures)
+type StripeService struct {
+	db         *gorm.DB
+	cfg        *config.Config
+	userRepo   *repository.UserRepository
+	serverRepo *repository.ServerRepository
+	mcService  *MinecraftService
+	httpClient *http.Client
+
+	walletService *WalletService // Optional: credits wallet top-ups
+
+	ticker   *time.Ticker
+	stopChan chan bool
+	mu       sync.Mutex
+}
+
+// NewStripeService creates a new Stripe service
+func NewStripeService(db *gorm.DB, cfg *config.Config, userRepo *repository.UserRepository, serverRepo *repository.ServerRepository, mcService *MinecraftService) *StripeService {
+	return &StripeService{
+		db:         db,
+		cfg:        cfg,
+		userRepo:   userRepo,
+		serverRepo: serverRepo,
+		mcService:  mcService,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		stopChan:   make(chan bool),
+	}
+}
+
+// SetWalletService sets the wallet service credited by top-up payments
+func (s *StripeService) SetWalletService(walletService *WalletService) {
+	s.walletService = walletService
+}
+
+// IsEnabled returns true if Stripe billing is configured
+func (s *StripeService) IsEnabled() bool {
+	return s.cfg.StripeEnabled && s.cfg.StripeSecretKey != ""
+}
+
+// Start starts the usage sync worker
+func (s *StripeService) Start() {
+	interval, err := time.ParseDuration(s.cfg.StripeUsageSyncInterval)
+	if err != nil || interval <= 0 {
+		interval = stripeDefaultSyncInterval
+	}
+
+	logger.Info("STRIPE: Starting usage sync worker", map[string]interface{}{
+		"interval": interval.String(),
+	})
+
+	go s.SyncUsage()
+
+	s.ticker = time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-s.ticker.C:
+				s.SyncUsage()
+			case <-s.stopChan:
+				logger.Info("STRIPE: Stopping usage sync worker", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the usage sync worker
+func (s *StripeService) Stop() {
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+	s.stopChan <- true
+}
+
+// ========================================
+// Customers & Subscriptions
+// ========================================
+
+// GetCustomer returns the Stripe customer for a user, or nil if the user isn't enrolled
+func (s *StripeService) GetCustomer(userID string) (*models.StripeCustomer, error) {
+	var customer models.StripeCustomer
+	err := s.db.Where("user_id = ?", userID).First(&customer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// EnsureCustomer creates the Stripe customer and metered subscription for a user if missing
+func (s *StripeService) EnsureCustomer(userID string) (*models.StripeCustomer, error) {
+	if !s.IsEnabled() {
+		return nil, ErrStripeDisabled
+	}
+
+	customer, err := s.ensureCustomerRecord(userID)
+	if err != nil {
+		return nil, err
+	}
+	if customer.SubscriptionItemID != "" {
+		return customer, nil
+	}
+
+	if err := s.createSubscription(customer); err != nil {
+		return nil, err
+	}
+
+	logger.Info("STRIPE: Customer enrolled in metered billing", map[string]interface{}{
+		"user_id":         userID,
+		"customer_id":     customer.CustomerID,
+		"subscription_id": customer.SubscriptionID,
+	})
+
+	return customer, nil
+}
+
+// ensureCustomerRecord creates the Stripe customer (without subscription) for a user if missing
+func (s *StripeService) ensureCustomerRecord(userID string) (*models.StripeCustomer, error) {
+	customer, err := s.GetCustomer(userID)
+	if err != nil {
+		return nil, err
+	}
+	if customer != nil {
+		return customer, nil
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("email", user.Email)
+	params.Set("name", user.Username)
+	params.Set("metadata[user_id]", user.ID)
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := s.stripeRequest("POST", "/v1/customers", params, "customer-"+user.ID, &created); err != nil {
+		return nil, fmt.Errorf("failed to create stripe customer: %w", err)
+	}
+
+	customer = &models.StripeCustomer{
+		UserID:     user.ID,
+		CustomerID: created.ID,
+	}
+	if err := s.db.Create(customer).Error; err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// createSubscription subscribes the customer to the metered price, invoiced on the 1st of every month
+func (s *StripeService) createSubscription(customer *models.StripeCustomer) error {
+	if s.cfg.StripeMeteredPriceID == "" {
+		return errors.New("STRIPE_METERED_PRICE_ID is not configured")
+	}
+
+	now := time.Now().UTC()
+	nextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+
+	params := url.Values{}
+	params.Set("customer", customer.CustomerID)
+	params.Set("items[0][price]", s.cfg.StripeMeteredPriceID)
+	params.Set("billing_cycle_anchor", strconv.FormatInt(nextMonth.Unix(), 10))
+	params.Set("proration_behavior", "none")
+	params.Set("metadata[user_id]", customer.UserID)
+
+	var subscription struct {
+		ID    string `json:"id"`
+		Items struct {
+			Data []struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		} `json:"items"`
+	}
+	if err := s.stripeRequest("POST", "/v1/subscriptions", params, "subscription-"+customer.CustomerID, &subscription); err != nil {
+		return fmt.Errorf("failed to create stripe subscription: %w", err)
+	}
+	if len(subscription.Items.Data) == 0 {
+		return errors.New("stripe subscription has no items")
+	}
+
+	customer.SubscriptionID = subscription.ID
+	customer.SubscriptionItemID = subscription.Items.Data[0].ID
+	return s.db.Save(customer).Error
+}
+
+// requireCustomer returns the enrolled customer or an error
+func (s *StripeService) requireCustomer(userID string) (*models.StripeCustomer, error) {
+	if !s.IsEnabled() {
+		return nil, ErrStripeDisabled
+	}
+	customer, err := s.GetCustomer(userID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, errors.New("billing account not set up")
+	}
+	return customer, nil
+}
+
+// ========================================
+// Payment Methods
+// ========================================
+
+// PaymentMethodSummary is the public view of a stored card
+type PaymentMethodSummary struct {
+	ID        string `json:"id"`
+	Brand     string `json:"brand"`
+	Last4     string `json:"last4"`
+	ExpMonth  int    `json:"exp_month"`
+	ExpYear   int    `json:"exp_year"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// CreateSetupIntent returns a SetupIntent client secret the frontend uses to collect a new card
+func (s *StripeService) CreateSetupIntent(userID string) (string, error) {
+	customer, err := s.EnsureCustomer(userID)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("customer", customer.CustomerID)
+	params.Set("usage", "off_session")
+	params.Set("payment_method_types[]", "card")
+
+	var intent struct {
+		ClientSecret string `json:"client_secret"`
+	}
+	if err := s.stripeRequest("POST", "/v1/setup_intents", params, "", &intent); err != nil {
+		return "", fmt.Errorf("failed to create setup intent: %w", err)
+	}
+
+	return intent.ClientSecret, nil
+}
+
+// ListPaymentMethods returns the cards stored for a user
+func (s *StripeService) ListPaymentMethods(userID string) ([]PaymentMethodSummary, error) {
+	customer, err := s.requireCustomer(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("customer", customer.CustomerID)
+	params.Set("type", "card")
+
+	var list struct {
+		Data []struct {
+			ID   string `json:"id"`
+			Card struct {
+				Brand    string `json:"brand"`
+				Last4    string `json:"last4"`
+				ExpMonth int    `json:"exp_month"`
+				ExpYear  int    `json:"exp_year"`
+			} `json:"card"`
+		} `json:"data"`
+	}
+	if err := s.stripeRequest("GET", "/v1/payment_methods", params, "", &list); err != nil {
+		return nil, fmt.Errorf("failed to list payment methods: %w", err)
+	}
+
+	methods := make([]PaymentMethodSummary, 0, len(list.Data))
+	for _, pm := range list.Data {
+		methods = append(methods, PaymentMethodSummary{
+			ID:        pm.ID,
+			Brand:     pm.Card.Brand,
+			Last4:     pm.Card.Last4,
+			ExpMonth:  pm.Card.ExpMonth,
+			ExpYear:   pm.Card.ExpYear,
+			IsDefault: pm.ID == customer.DefaultPaymentMethodID,
+		})
+	}
+
+	return methods, nil
+}
+
+// SetDefaultPaymentMethod makes a stored card the default for invoice payments
+func (s *StripeService) SetDefaultPaymentMethod(userID, paymentMethodID string) error {
+	customer, err := s.requireCustomer(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.verifyPaymentMethodOwner(customer, paymentMethodID); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("invoice_settings[default_payment_method]", paymentMethodID)
+	if err := s.stripeRequest("POST", "/v1/customers/"+url.PathEscape(customer.CustomerID), params, "", nil); err != nil {
+		return fmt.Errorf("failed to set default payment method: %w", err)
+	}
+
+	customer.DefaultPaymentMethodID = paymentMethodID
+	return s.db.Save(customer).Error
+}
+
+// DetachPaymentMethod removes a stored card
+func (s *StripeService) DetachPaymentMethod(userID, paymentMethodID string) error {
+	customer, err := s.requireCustomer(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.verifyPaymentMethodOwner(customer, paymentMethodID); err != nil {
+		return err
+	}
+
+	if err := s.stripeRequest("POST", "/v1/payment_methods/"+url.PathEscape(paymentMethodID)+"/detach", url.Values{}, "", nil); err != nil {
+		return fmt.Errorf("failed to detach payment method: %w", err)
+	}
+
+	if customer.DefaultPaymentMethodID == paymentMethodID {
+		customer.DefaultPaymentMethodID = ""
+		return s.db.Save(customer).Error
+	}
+	return nil
+}
+
+// verifyPaymentMethodOwner ensures a payment method belongs to the customer
+func (s *StripeService) verifyPaymentMethodOwner(customer *models.StripeCustomer, paymentMethodID string) error {
+	var pm struct {
+		Customer string `json:"customer"`
+	}
+	if err := s.stripeRequest("GET", "/v1/payment_methods/"+url.PathEscape(paymentMethodID), nil, "", &pm); err != nil {
+		return fmt.Errorf("payment method not found: %w", err)
+	}
+	if pm.Customer != customer.CustomerID {
+		return errors.New("payment method not found")
+	}
+	return nil
+}
+
+// ========================================
+// Usage Reporting & Invoices
+// ========================================
+
+// SyncUsage reports closed usage sessions of enrolled customers to Stripe
+// Each session is reported exactly once (idempotency key = session ID)
+func (s *StripeService) SyncUsage() {
+	if !s.IsEnabled() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type pendingSession struct {
+		models.UsageSession
+		SubscriptionItemID string
+	}
+
+	var sessions []pendingSession
+	err := s.db.Table("usage_sessions").
+		Select("usage_sessions.*, stripe_customers.subscription_item_id").
+		Joins("JOIN stripe_customers ON stripe_customers.user_id = usage_sessions.owner_id AND stripe_customers.subscription_item_id <> ''").
+		Where("usage_sessions.deleted_at IS NULL AND usage_sessions.stopped_at IS NOT NULL").
+		Where("usage_sessions.started_at >= stripe_customers.created_at"). // Only usage after enrollment
+		Where("usage_sessions.id NOT IN (?)", s.db.Model(&models.StripeUsageReport{}).Select("usage_session_id")).
+		Order("usage_sessions.stopped_at ASC").
+		Limit(stripeUsageBatchSize).
+		Scan(&sessions).Error
+	if err != nil {
+		logger.Error("STRIPE: Failed to load unreported usage sessions", err, nil)
+		return
+	}
+
+	reported := 0
+	for _, session := range sessions {
+		quantity := int64(math.Round(session.CostEUR * 100))
+		report := &models.StripeUsageReport{
+			UsageSessionID: session.ID,
+			UserID:         session.OwnerID,
+			ServerID:       session.ServerID,
+			QuantityCents:  quantity,
+			ReportedAt:     time.Now(),
+		}
+
+		// Sessions below one cent are recorded without calling Stripe
+		if quantity > 0 {
+			params := url.Values{}
+			params.Set("quantity", strconv.FormatInt(quantity, 10))
+			params.Set("timestamp", strconv.FormatInt(session.StoppedAt.Unix(), 10))
+			params.Set("action", "increment")
+
+			var record struct {
+				ID string `json:"id"`
+			}
+			path := "/v1/subscription_items/" + url.PathEscape(session.SubscriptionItemID) + "/usage_records"
+			if err := s.stripeRequest("POST", path, params, "usage-"+session.ID, &record); err != nil {
+				logger.Warn("STRIPE: Failed to report usage, will retry", map[string]interface{}{
+					"session_id": session.ID,
+					"owner_id":   session.OwnerID,
+					"error":      err.Error(),
+				})
+				continue
+			}
+			report.UsageRecordID = record.ID
+		}
+
+		if err := s.db.Create(report).Error; err != nil {
+			logger.Error("STRIPE: Failed to record usage report", err, map[string]interface{}{
+				"session_id": session.ID,
+			})
+			continue
+		}
+		reported++
+	}
+
+	if reported > 0 {
+		logger.Info("STRIPE: Usage synced", map[string]interface{}{
+			"sessions": reported,
+		})
+	}
+}
+
+// ListInvoices returns the invoices of a user (newest first)
+func (s *StripeService) ListInvoices(userID string) ([]models.StripeInvoice, error) {
+	var invoices []models.StripeInvoice
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&invoices).Error
+	return invoices, err
+}
+
+// ========================================
+// Wallet Top-Ups
+// ========================================
+
+// CreateTopUpIntent creates a one-off PaymentIntent buying prepaid credit, returns its client secret
+// The balance is credited by the payment_intent.succeeded webhook, never by the client.
+func (s *StripeService) CreateTopUpIntent(userID string, amountEUR float64) (string, error) {
+	if !s.IsEnabled() {
+		return "", ErrStripeDisabled
+	}
+	if s.walletService == nil || !s.walletService.IsEnabled() {
+		return "", ErrWalletDisabled
+	}
+	if amountEUR < s.cfg.WalletMinTopUpEUR {
+		return "", fmt.Errorf("minimum top-up is %.2f EUR", s.cfg.WalletMinTopUpEUR)
+	}
+
+	customer, err := s.ensureCustomerRecord(userID)
+	if err != nil {
+		return "", err
+	}
+
+	amountCents := int64(math.Round(amountEUR * 100))
+
+	params := url.Values{}
+	params.Set("customer", customer.CustomerID)
+	params.Set("amount", strconv.FormatInt(amountCents, 10))
+	params.Set("currency", "eur")
+	params.Set("automatic_payment_methods[enabled]", "true")
+	params.Set("metadata[purpose]", "wallet_topup")
+	params.Set("metadata[user_id]", userID)
+
+	var intent struct {
+		ID           string `json:"id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	if err := s.stripeRequest("POST", "/v1/payment_intents", params, "", &intent); err != nil {
+		return "", fmt.Errorf("failed to create payment intent: %w", err)
+	}
+
+	logger.Info("STRIPE: Wallet top-up initiated", map[string]interface{}{
+		"user_id":           userID,
+		"amount_eur":        amountEUR,
+		"payment_intent_id": intent.ID,
+	})
+
+	return intent.ClientSecret, nil
+}
+
+// handleTopUpSucceeded credits the wallet for a successful top-up PaymentIntent
+func (s *StripeService) handleTopUpSucceeded(tx *gorm.DB, raw json.RawMessage) error {
+	var intent struct {
+		ID             string            `json:"id"`
+		AmountReceived int64             `json:"amount_received"`
+		Currency       string            `json:"currency"`
+		Metadata       map[string]string `json:"metadata"`
+	}
+	if err := json.Unmarshal(raw, &intent); err != nil {
+		return fmt.Errorf("invalid payment intent object: %w", err)
+	}
+
+	if intent.Metadata["purpose"] != "wallet_topup" {
+		return nil // Not a wallet top-up
+	}
+	if s.walletService == nil {
+		logger.Warn("STRIPE: Wallet top-up received but wallet is not enabled", map[string]interface{}{
+			"payment_intent_id": intent.ID,
+		})
+		return nil
+	}
+	if !strings.EqualFold(intent.Currency, "eur") {
+		return fmt.Errorf("unexpected top-up currency %q", intent.Currency)
+	}
+
+	amountEUR := float64(intent.AmountReceived) / 100.0
+	_, err := s.walletService.addCredit(tx, intent.Metadata["user_id"], amountEUR, models.WalletTxPurchase, intent.ID,
+		fmt.Sprintf("Credit purchase (%.2f EUR)", amountEUR))
+	return err
+}
+
+// ========================================
+// Webhooks & Dunning
+// ========================================
+
+// stripeInvoiceObject is the subset of the Stripe invoice object we persist
+type stripeInvoiceObject struct {
+	ID               string `json:"id"`
+	Customer         string `json:"customer"`
+	Status           string `json:"status"`
+	Currency         string `json:"currency"`
+	AmountDue        int64  `json:"amount_due"`
+	AmountPaid       int64  `json:"amount_paid"`
+	AttemptCount     int    `json:"attempt_count"`
+	HostedInvoiceURL string `json:"hosted_invoice_url"`
+	PeriodStart      int64  `json:"period_start"`
+	PeriodEnd        int64  `json:"period_end"`
+}
+
+// HandleWebhook verifies and processes a Stripe webhook event
+// Events are processed once per event ID; redeliveries of a processed event succeed without effect.
+func (s *StripeService) HandleWebhook(payload []byte, signatureHeader string) error {
+	if !s.IsEnabled() {
+		return ErrStripeDisabled
+	}
+	if err := s.verifySignature(payload, signatureHeader); err != nil {
+		return err
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if event.ID == "" {
+		return fmt.Errorf("invalid webhook payload: missing event id")
+	}
+
+	var changed *models.StripeCustomer
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		record := &models.StripeWebhookEvent{EventID: event.ID, Type: event.Type, ProcessedAt: time.Now()}
+		if err := tx.Create(record).Error; err != nil {
+			if isDuplicateKey(err) {
+				return errStripeEventProcessed
+			}
+			return err
+		}
+
+		var err error
+		changed, err = s.processEvent(tx, event.ID, event.Type, event.Data.Object)
+		return err
+	})
+	if errors.Is(err, errStripeEventProcessed) {
+		logger.Info("STRIPE: Ignoring already processed webhook event", map[string]interface{}{
+			"event_id": event.ID,
+			"type":     event.Type,
+		})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Suspending servers talks to nodes and Velocity, so it runs after the change is committed
+	if changed != nil {
+		if changed.Suspended {
+			s.suspendOwnerServers(changed.UserID)
+		} else {
+			s.unsuspendOwnerServers(changed.UserID)
+		}
+	}
+	return nil
+}
+
+// processEvent applies a webhook event within the transaction that records its ID
+// Returns the customer if the event suspended them or lifted their suspension.
+func (s *StripeService) processEvent(tx *gorm.DB, eventID, eventType string, object json.RawMessage) (*models.StripeCustomer, error) {
+	if eventType == "payment_intent.succeeded" {
+		return nil, s.handleTopUpSucceeded(tx, object)
+	}
+
+	if !strings.HasPrefix(eventType, "invoice.") {
+		return nil, nil // Not interested
+	}
+
+	var invoice stripeInvoiceObject
+	if err := json.Unmarshal(object, &invoice); err != nil {
+		return nil, fmt.Errorf("invalid invoice object: %w", err)
+	}
+
+	var customer models.StripeCustomer
+	if err := tx.Where("customer_id = ?", invoice.Customer).First(&customer).Error; err != nil {
+		logger.Warn("STRIPE: Webhook for unknown customer", map[string]interface{}{
+			"event_id":    eventID,
+			"customer_id": invoice.Customer,
+		})
+		return nil, nil
+	}
+
+	if err := s.upsertInvoice(tx, customer.UserID, &invoice); err != nil {
+		return nil, err
+	}
+
+	switch eventType {
+	case "invoice.payment_failed":
+		suspended, err := s.recordPaymentFailure(tx, &customer, &invoice)
+		if err != nil || !suspended {
+			return nil, err
+		}
+		return &customer, nil
+	case "invoice.paid":
+		wasSuspended := customer.Suspended
+		if err := s.recordPaymentSuccess(tx, &customer); err != nil || !wasSuspended {
+			return nil, err
+		}
+		return &customer, nil
+	}
+
+	return nil, nil
+}
+
+// upsertInvoice stores the latest state of a Stripe invoice
+func (s *StripeService) upsertInvoice(tx *gorm.DB, userID string, obj *stripeInvoiceObject) error {
+	var invoice models.StripeInvoice
+	err := tx.Where("invoice_id = ?", obj.ID).First(&invoice).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	invoice.InvoiceID = obj.ID
+	invoice.UserID = userID
+	invoice.Status = obj.Status
+	invoice.Currency = obj.Currency
+	invoice.AmountDueCents = obj.AmountDue
+	invoice.AmountPaidCents = obj.AmountPaid
+	invoice.AttemptCount = obj.AttemptCount
+	invoice.HostedInvoiceURL = obj.HostedInvoiceURL
+	if obj.PeriodStart > 0 {
+		periodStart := time.Unix(obj.PeriodStart, 0)
+		invoice.PeriodStart = &periodStart
+	}
+	if obj.PeriodEnd > 0 {
+		periodEnd := time.Unix(obj.PeriodEnd, 0)
+		invoice.PeriodEnd = &periodEnd
+	}
+
+	return tx.Save(&invoice).Error
+}
+
+// recordPaymentFailure counts a failed payment and suspends the owner after too many in a row
+// Returns true if this failure suspended the owner; the caller suspends their servers after commit.
+func (s *StripeService) recordPaymentFailure(tx *gorm.DB, customer *models.StripeCustomer, invoice *stripeInvoiceObject) (bool, error) {
+	customer.FailedPaymentCount++
+
+	logger.Warn("STRIPE: Invoice payment failed", map[string]interface{}{
+		"user_id":      customer.UserID,
+		"invoice_id":   invoice.ID,
+		"failed_count": customer.FailedPaymentCount,
+	})
+
+	maxFailures := s.cfg.StripeMaxFailedPayments
+	if maxFailures <= 0 {
+		maxFailures = 3
+	}
+
+	if customer.FailedPaymentCount >= maxFailures && !customer.Suspended {
+		now := time.Now()
+		customer.Suspended = true
+		customer.SuspendedAt = &now
+		if err := tx.Save(customer).Error; err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	return false, tx.Save(customer).Error
+}
+
+// recordPaymentSuccess resets the failure counter and lifts a suspension
+func (s *StripeService) recordPaymentSuccess(tx *gorm.DB, customer *models.StripeCustomer) error {
+	if customer.Suspended {
+		logger.Info("STRIPE: Payment received, lifting suspension", map[string]interface{}{
+			"user_id": customer.UserID,
+		})
+	}
+
+	customer.FailedPaymentCount = 0
+	customer.Suspended = false
+	customer.SuspendedAt = nil
+	return tx.Save(customer).Error
+}
+
+// suspendOwnerServers suspends all servers of an owner suspended for failed payments
+func (s *StripeService) suspendOwnerServers(ownerID string) {
+	servers, err := s.serverRepo.FindByOwner(ownerID)
+	if err != nil {
+		logger.Error("STRIPE: Failed to load servers for suspension", err, map[string]interface{}{
+			"user_id": ownerID,
+		})
+		return
+	}
+
+	suspended := 0
+	for _, server := range servers {
+		if server.Status == models.StatusSuspended {
+			continue // Already held, e.g. by an admin
+		}
+		_, err := s.mcService.SuspendServer(context.Background(), server.ID, billingSuspensionReason, SuspensionSourceBilling, "")
+		if err != nil {
+			logger.Error("STRIPE: Failed to suspend server of suspended owner", err, map[string]interface{}{
+				"user_id":   ownerID,
+				"server_id": server.ID,
+			})
+			continue
+		}
+		suspended++
+	}
+
+	logger.Warn("STRIPE: Owner suspended after repeated failed payments", map[string]interface{}{
+		"user_id":           ownerID,
+		"servers_suspended": suspended,
+	})
+}
+
+// unsuspendOwnerServers lifts the billing suspension of an owner's servers after a payment
+// Servers suspended by an admin stay suspended.
+func (s *StripeService) unsuspendOwnerServers(ownerID string) {
+	servers, err := s.serverRepo.FindByOwner(ownerID)
+	if err != nil {
+		logger.Error("STRIPE: Failed to load servers for lifting the suspension", err, map[string]interface{}{
+			"user_id": ownerID,
+		})
+		return
+	}
+
+	for _, server := range servers {
+		if server.Status != models.StatusSuspended || server.SuspensionSource != SuspensionSourceBilling {
+			continue
+		}
+		if _, err := s.mcService.UnsuspendServer(server.ID, SuspensionSourceBilling); err != nil {
+			logger.Error("STRIPE: Failed to unsuspend server after payment", err, map[string]interface{}{
+				"user_id":   ownerID,
+				"server_id": server.ID,
+			})
+		}
+	}
+}
+
+// CheckCanStart implements BillingGuardInterface (suspended owners can't start servers)
+func (s *StripeService) CheckCanStart(server *models.MinecraftServer) error {
+	customer, err := s.GetCustomer(server.OwnerID)
+	if err != nil {
+		logger.Warn("STRIPE: Could not check billing status, allowing start", map[string]interface{}{
+			"owner_id": server.OwnerID,
+			"error":    err.Error(),
+		})
+		return nil
+	}
+	if customer != nil && customer.Suspended {
+		return ErrBillingSuspended
+	}
+	return nil
+}
+
+// isDuplicateKey reports whether err is a unique constraint violation (SQLSTATE 23505)
+func isDuplicateKey(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	var pgErr interface{ SQLState() string }
+	return errors.As(err, &pgErr) && pgErr.SQLState() == "23505"
+}
+
+// verifySignature validates the Stripe-Signature header (t=timestamp,v1=hmac)
+func (s *StripeService) verifySignature(payload []byte, header string) error {
+	if s.cfg.StripeWebhookSecret == "" {
+		return errors.New("STRIPE_WEBHOOK_SECRET is not configured")
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidStripeSignature
+	}
+	if age := time.Since(time.Unix(ts, 0)); age > stripeWebhookTolerance || age < -stripeWebhookTolerance {
+		return ErrInvalidStripeSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.cfg.StripeWebhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	for _, signature := range signatures {
+		decoded, err := hex.DecodeString(signature)
+		if err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+
+	return ErrInvalidStripeSignature
+}
+
+// stripeRequest performs a form-encoded Stripe API call and decodes the JSON response into out
+func (s *StripeService) stripeRequest(method, path string, params url.Values, idempotencyKey string, out interface{}) error {
+	endpoint := stripeAPIBase + path
+
+	var body io.Reader
+	if method == "GET" {
+		if len(params) > 0 {
+			endpoint += "?" + params.Encode()
+		}
+	} else if params != nil {
+		body = strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, endpoint, body)
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(s.cfg.StripeSecretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("stripe API error (status %d): %s", resp.StatusCode, apiErr.Error.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+const testStripeWebhookSecret = "whsec_test"
+
+// fakeStripeDB answers the queries of webhook processing; event IDs are unique like the real index
+type fakeStripeDB struct {
+	mu     sync.Mutex
+	events map[string]bool
+}
+
+func (f *fakeStripeDB) handle(query string, args []driver.NamedValue) fakeSQLResult {
+	switch {
+	case strings.HasPrefix(query, `INSERT INTO "stripe_webhook_events"`):
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		eventID := namedArg(args, 1).(string)
+		if f.events[eventID] {
+			return fakeSQLResult{Err: fakePgError("23505")}
+		}
+		f.events[eventID] = true
+		return fakeSQLResult{Columns: []string{"id"}, Rows: [][]driver.Value{{int64(len(f.events))}}}
+	case strings.Contains(query, `FROM "stripe_customers"`):
+		return fakeSQLResult{
+			Columns: []string{"id", "user_id", "customer_id", "failed_payment_count", "suspended"},
+			Rows:    [][]driver.Value{{int64(1), "user-1", "cus_1", int64(0), false}},
+		}
+	}
+	return fakeSQLResult{}
+}
+
+func signStripePayload(payload string) string {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testStripeWebhookSecret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func stripeInvoiceEvent(eventID, eventType string) string {
+	return fmt.Sprintf(`{"id":%q,"type":%q,"data":{"object":{"id":"in_1","customer":"cus_1","status":"open","attempt_count":1}}}`, eventID, eventType)
+}
+
+func TestStripeHandleWebhookDedupe(t *testing.T) {
+	tests := []struct {
+		name            string
+		events          []string
+		wantErr         bool
+		wantCommits     int
+		wantRollbacks   int
+		wantCustomerSet int // Saves of the customer (failure counter updates)
+	}{
+		{
+			name:            "single delivery",
+			events:          []string{stripeInvoiceEvent("evt_1", "invoice.payment_failed")},
+			wantCommits:     1,
+			wantCustomerSet: 1,
+		},
+		{
+			name: "redelivery is ignored",
+			events: []string{
+				stripeInvoiceEvent("evt_1", "invoice.payment_failed"),
+				stripeInvoiceEvent("evt_1", "invoice.payment_failed"),
+				stripeInvoiceEvent("evt_1", "invoice.payment_failed"),
+			},
+			wantCommits:     1,
+			wantRollbacks:   2,
+			wantCustomerSet: 1,
+		},
+		{
+			name: "distinct events are processed",
+			events: []string{
+				stripeInvoiceEvent("evt_1", "invoice.payment_failed"),
+				stripeInvoiceEvent("evt_2", "invoice.payment_failed"),
+			},
+			wantCommits:     2,
+			wantCustomerSet: 2,
+		},
+		{
+			name:    "missing event id",
+			events:  []string{`{"type":"invoice.paid","data":{"object":{}}}`},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDB := &fakeStripeDB{events: map[string]bool{}}
+			db, fake := newFakeSQLDB(t, fakeDB.handle)
+			s := NewStripeService(db, &config.Config{
+				StripeEnabled:           true,
+				StripeSecretKey:         "sk_test",
+				StripeWebhookSecret:     testStripeWebhookSecret,
+				StripeMaxFailedPayments: 3,
+			}, nil, nil, nil)
+
+			for _, payload := range tt.events {
+				err := s.HandleWebhook([]byte(payload), signStripePayload(payload))
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("HandleWebhook() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+
+			if got := fake.Count("COMMIT"); got != tt.wantCommits {
+				t.Errorf("got %d commits, want %d", got, tt.wantCommits)
+			}
+			if got := fake.Count("ROLLBACK"); got != tt.wantRollbacks {
+				t.Errorf("got %d rollbacks, want %d", got, tt.wantRollbacks)
+			}
+			if got := fake.Count(`UPDATE "stripe_customers"`); got != tt.wantCustomerSet {
+				t.Errorf("customer saved %d times, want %d", got, tt.wantCustomerSet)
+			}
+		})
+	}
+}
+
+func TestStripeHandleWebhookEventInTransaction(t *testing.T) {
+	fakeDB := &fakeStripeDB{events: map[string]bool{}}
+	db, fake := newFakeSQLDB(t, fakeDB.handle)
+	s := NewStripeService(db, &config.Config{
+		StripeEnabled:       true,
+		StripeSecretKey:     "sk_test",
+		StripeWebhookSecret: testStripeWebhookSecret,
+	}, nil, nil, nil)
+
+	payload := stripeInvoiceEvent("evt_1", "invoice.paid")
+	if err := s.HandleWebhook([]byte(payload), signStripePayload(payload)); err != nil {
+		t.Fatalf("HandleWebhook() error = %v", err)
+	}
+
+	// The event ID must be claimed first, and the state change committed with it
+	statements := fake.Statements()
+	if len(statements) < 3 || statements[0] != "BEGIN" ||
+		!strings.HasPrefix(statements[1], `INSERT INTO "stripe_webhook_events"`) ||
+		statements[len(statements)-1] != "COMMIT" {
+		t.Fatalf("statements = %q, want BEGIN, event insert, ..., COMMIT", statements)
+	}
+	for _, statement := range statements[2 : len(statements)-1] {
+		if statement == "BEGIN" || statement == "COMMIT" {
+			t.Errorf("state change ran outside the event transaction: %q", statements)
+		}
+	}
+}
+
+func TestIsDuplicateKey(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fakePgError("23505"), true},
+		{fmt.Errorf("wrapped: %w", fakePgError("23505")), true},
+		{fakePgError("23503"), false},
+		{fmt.Errorf("duplicate"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isDuplicateKey(tt.err); got != tt.want {
+			t.Errorf("isDuplicateKey(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
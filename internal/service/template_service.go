@@ -2,21 +2,40 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
+// Template errors
+var (
+	ErrTemplateNotFound      = errors.New("template not found")
+	ErrInvalidTemplate       = errors.New("invalid template")
+	ErrUserTemplatesDisabled = errors.New("user templates are not enabled")
+)
+
+// templateVersionRegex is the accepted Minecraft version format of templates (X.Y or X.Y.Z)
+var templateVersionRegex = regexp.MustCompile(`^1\.\d{1,2}(\.\d{1,2})?$`)
+
 // TemplateService handles server template operations
+// Built-in templates come from a JSON file; user templates (created directly or saved while
+// cloning a server) are versioned in the database, listed alongside them for their owner and,
+// once published, in the template marketplace for everyone.
 type TemplateService struct {
 	templatesPath string
 	templates     []models.ServerTemplate
 	categories    []models.TemplateCategory
+	repo          *repository.TemplateRepository // Optional: user templates
+	backupService *BackupService                 // Optional: deletes world snapshots of deleted templates
 }
 
 // TemplateData holds the entire templates JSON structure
@@ -73,11 +92,40 @@ func (s *TemplateService) LoadTemplates() error {
 	return nil
 }
 
+// SetRepository sets the repository of owner-defined templates
+func (s *TemplateService) SetRepository(repo *repository.TemplateRepository) {
+	s.repo = repo
+}
+
+// SetBackupService sets the service that deletes the world snapshots of deleted templates
+func (s *TemplateService) SetBackupService(backupService *BackupService) {
+	s.backupService = backupService
+}
+
 // GetAllTemplates returns all available templates
 func (s *TemplateService) GetAllTemplates() []models.ServerTemplate {
 	return s.templates
 }
 
+// GetTemplatesForUser returns the built-in templates followed by the templates owned by userID
+func (s *TemplateService) GetTemplatesForUser(userID string) ([]models.ServerTemplate, error) {
+	if s.repo == nil || userID == "" {
+		return s.templates, nil
+	}
+
+	owned, err := s.repo.FindByOwner(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user templates: %w", err)
+	}
+
+	templates := make([]models.ServerTemplate, 0, len(s.templates)+len(owned))
+	templates = append(templates, s.templates...)
+	for i := range owned {
+		templates = append(templates, owned[i].ToServerTemplate())
+	}
+	return templates, nil
+}
+
 // GetTemplateByID returns a specific template by ID
 func (s *TemplateService) GetTemplateByID(id string) (*models.ServerTemplate, error) {
 	for _, template := range s.templates {
@@ -85,7 +133,295 @@ func (s *TemplateService) GetTemplateByID(id string) (*models.ServerTemplate, er
 			return &template, nil
 		}
 	}
-	return nil, fmt.Errorf("template not found: %s", id)
+	return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, id)
+}
+
+// GetTemplateForUser returns a built-in template, a template owned by userID or a published template
+func (s *TemplateService) GetTemplateForUser(id, userID string) (*models.ServerTemplate, error) {
+	if template, err := s.GetTemplateByID(id); err == nil {
+		return template, nil
+	}
+	if s.repo == nil || userID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, id)
+	}
+
+	visible, err := s.repo.FindVisible(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, id)
+	}
+	template := visible.ToServerTemplate()
+	return &template, nil
+}
+
+// UserTemplateInput is the content of a user template (see CreateUserTemplate and UpdateUserTemplate)
+type UserTemplateInput struct {
+	Name             string                 `json:"name" binding:"required"`
+	Description      string                 `json:"description"`
+	ServerType       string                 `json:"server_type" binding:"required"`
+	MinecraftVersion string                 `json:"minecraft_version" binding:"required"`
+	RAMMb            int                    `json:"ram_mb" binding:"required,min=1024"`
+	Settings         *models.ServerSettings `json:"settings"`   // nil = defaults of a new server
+	WorldSeed        string                 `json:"world_seed"` // Overrides settings.level_seed
+	Plugins          []string               `json:"plugins"`    // Marketplace plugin slugs
+	Changelog        string                 `json:"changelog"`  // Describes the revision
+}
+
+// spec validates the input and returns the template content it describes
+func (in UserTemplateInput) spec() (models.TemplateSpec, error) {
+	serverType := models.ServerType(in.ServerType)
+	switch serverType {
+	case models.ServerTypePaper, models.ServerTypeSpigot, models.ServerTypeForge,
+		models.ServerTypeFabric, models.ServerTypeVanilla, models.ServerTypePurpur:
+	default:
+		return models.TemplateSpec{}, fmt.Errorf("%w: invalid server type %q", ErrInvalidTemplate, in.ServerType)
+	}
+	if !templateVersionRegex.MatchString(in.MinecraftVersion) {
+		return models.TemplateSpec{}, fmt.Errorf("%w: invalid Minecraft version %q", ErrInvalidTemplate, in.MinecraftVersion)
+	}
+
+	settings := models.DefaultServerSettings()
+	if in.Settings != nil {
+		settings = *in.Settings
+	}
+	if in.WorldSeed != "" {
+		settings.LevelSeed = in.WorldSeed
+	}
+	if err := settings.Validate(); err != nil {
+		return models.TemplateSpec{}, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	return models.TemplateSpec{
+		ServerType:       serverType,
+		MinecraftVersion: in.MinecraftVersion,
+		RAMMb:            in.RAMMb,
+		Settings:         settings,
+		Plugins:          in.Plugins,
+	}, nil
+}
+
+// ListUserTemplates returns the templates owned by userID
+func (s *TemplateService) ListUserTemplates(userID string) ([]models.UserTemplate, error) {
+	if s.repo == nil {
+		return nil, ErrUserTemplatesDisabled
+	}
+	return s.repo.FindByOwner(userID)
+}
+
+// ListMarketplace returns one page of the templates published by all users
+func (s *TemplateService) ListMarketplace(page repository.PageRequest) (*repository.Page[models.UserTemplate], error) {
+	if s.repo == nil {
+		return nil, ErrUserTemplatesDisabled
+	}
+	return s.repo.FindPublishedPage(page)
+}
+
+// CreateUserTemplate creates a template owned by userID (revision 1)
+func (s *TemplateService) CreateUserTemplate(userID string, in UserTemplateInput, publish bool) (*models.UserTemplate, error) {
+	spec, err := in.spec()
+	if err != nil {
+		return nil, err
+	}
+	template := &models.UserTemplate{
+		OwnerID:      userID,
+		Name:         in.Name,
+		Description:  in.Description,
+		TemplateSpec: spec,
+	}
+	if publish {
+		now := time.Now()
+		template.Published = true
+		template.PublishedAt = &now
+	}
+	if err := s.SaveUserTemplate(template, in.Changelog); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// SaveUserTemplate stores a new owner-defined template as its first revision
+func (s *TemplateService) SaveUserTemplate(template *models.UserTemplate, changelog string) error {
+	if s.repo == nil {
+		return ErrUserTemplatesDisabled
+	}
+	if template.ID == "" {
+		template.ID = uuid.New().String()
+	}
+	if err := s.repo.Create(template, changelog); err != nil {
+		return fmt.Errorf("failed to save template: %w", err)
+	}
+
+	logger.Info("User template saved", map[string]interface{}{
+		"template_id":      template.ID,
+		"owner_id":         template.OwnerID,
+		"source_server_id": template.SourceServerID,
+	})
+	return nil
+}
+
+// UpdateUserTemplate saves new content of a template owned by userID as its next revision
+// The world snapshot of the template is kept; servers created from earlier revisions are unaffected.
+func (s *TemplateService) UpdateUserTemplate(userID, templateID string, in UserTemplateInput) (*models.UserTemplate, error) {
+	template, err := s.ownedTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := in.spec()
+	if err != nil {
+		return nil, err
+	}
+	spec.BackupID = template.BackupID
+
+	template.Name = in.Name
+	template.Description = in.Description
+	template.TemplateSpec = spec
+	if err := s.repo.CreateRevision(template, in.Changelog); err != nil {
+		return nil, fmt.Errorf("failed to save template revision: %w", err)
+	}
+
+	logger.Info("User template revised", map[string]interface{}{
+		"template_id": template.ID,
+		"owner_id":    userID,
+		"revision":    template.Revision,
+	})
+	return template, nil
+}
+
+// SetPublished publishes a template owned by userID in the marketplace or withdraws it
+func (s *TemplateService) SetPublished(userID, templateID string, published bool) (*models.UserTemplate, error) {
+	template, err := s.ownedTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.Published == published {
+		return template, nil
+	}
+
+	template.Published = published
+	template.PublishedAt = nil
+	if published {
+		now := time.Now()
+		template.PublishedAt = &now
+	}
+	if err := s.repo.Update(template); err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	logger.Info("User template publication changed", map[string]interface{}{
+		"template_id": template.ID,
+		"owner_id":    userID,
+		"published":   published,
+	})
+	return template, nil
+}
+
+// ListRevisions returns the revisions of a template owned by userID or published
+func (s *TemplateService) ListRevisions(userID, templateID string) ([]models.UserTemplateRevision, error) {
+	if s.repo == nil {
+		return nil, ErrUserTemplatesDisabled
+	}
+	if _, err := s.repo.FindVisible(templateID, userID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, templateID)
+	}
+	return s.repo.FindRevisions(templateID)
+}
+
+// DeleteUserTemplate deletes a template owned by userID with its revisions and world snapshots
+func (s *TemplateService) DeleteUserTemplate(userID, templateID string) error {
+	template, err := s.ownedTemplate(userID, templateID)
+	if err != nil {
+		return err
+	}
+	revisions, err := s.repo.FindRevisions(templateID)
+	if err != nil {
+		return fmt.Errorf("failed to load template revisions: %w", err)
+	}
+	if err := s.repo.Delete(templateID); err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	// Template worlds are kept forever, so they are only removed together with the template
+	backupIDs := map[string]bool{template.BackupID: true}
+	for _, revision := range revisions {
+		backupIDs[revision.BackupID] = true
+	}
+	for backupID := range backupIDs {
+		if backupID == "" || s.backupService == nil {
+			continue
+		}
+		if err := s.backupService.DeleteBackup(backupID); err != nil {
+			logger.Warn("Failed to delete template world snapshot", map[string]interface{}{
+				"template_id": templateID,
+				"backup_id":   backupID,
+				"error":       err.Error(),
+			})
+		}
+	}
+
+	logger.Info("User template deleted", map[string]interface{}{
+		"template_id": templateID,
+		"owner_id":    userID,
+	})
+	return nil
+}
+
+// ResolveTemplate returns what a server created from a template gets
+// templateID is a built-in template, a template of userID or a published template; revision 0
+// selects the current revision of user templates (built-in templates have no revisions).
+func (s *TemplateService) ResolveTemplate(userID, templateID string, revision int) (*models.TemplateSpec, error) {
+	if builtin, err := s.GetTemplateByID(templateID); err == nil {
+		if revision != 0 {
+			return nil, fmt.Errorf("%w: built-in templates have no revisions", ErrTemplateNotFound)
+		}
+		return &models.TemplateSpec{
+			ServerType:       models.ServerType(builtin.ServerType),
+			MinecraftVersion: builtin.Version,
+			RAMMb:            builtin.Memory,
+			Settings:         models.DefaultServerSettings(),
+			Plugins:          builtin.Plugins,
+		}, nil
+	}
+	if s.repo == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, templateID)
+	}
+
+	template, err := s.repo.FindVisible(templateID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, templateID)
+	}
+	if revision == 0 || revision == template.Revision {
+		return &template.TemplateSpec, nil
+	}
+
+	found, err := s.repo.FindRevision(templateID, revision)
+	if err != nil {
+		return nil, fmt.Errorf("%w: revision %d of %s", ErrTemplateNotFound, revision, templateID)
+	}
+	return &found.TemplateSpec, nil
+}
+
+// RecordUsage counts a server created from a user template (built-in templates are ignored)
+func (s *TemplateService) RecordUsage(templateID string) {
+	if s.repo == nil {
+		return
+	}
+	if err := s.repo.IncrementUsage(templateID); err != nil {
+		logger.Warn("Failed to count template usage", map[string]interface{}{
+			"template_id": templateID,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// ownedTemplate finds a template of userID
+func (s *TemplateService) ownedTemplate(userID, templateID string) (*models.UserTemplate, error) {
+	if s.repo == nil {
+		return nil, ErrUserTemplatesDisabled
+	}
+	template, err := s.repo.FindForOwner(templateID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, templateID)
+	}
+	return template, nil
 }
 
 // GetTemplatesByCategory returns templates filtered by category
@@ -146,8 +482,9 @@ func (s *TemplateService) SearchTemplates(query string) []models.ServerTemplate
 }
 
 // ApplyTemplateToServer applies a template configuration to a server's server.properties
-func (s *TemplateService) ApplyTemplateToServer(serverID string, templateID string) error {
-	template, err := s.GetTemplateByID(templateID)
+// userID may use its own templates besides the built-in ones.
+func (s *TemplateService) ApplyTemplateToServer(serverID string, templateID string, userID string) error {
+	template, err := s.GetTemplateForUser(templateID, userID)
 	if err != nil {
 		return err
 	}
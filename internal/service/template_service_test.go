@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/payperplay/hosting/internal/models"
+)
+
+func TestUserTemplateInputSpec(t *testing.T) {
+	in := UserTemplateInput{
+		Name:             "Skyblock",
+		ServerType:       "paper",
+		MinecraftVersion: "1.21.1",
+		RAMMb:            2048,
+		WorldSeed:        "12345",
+		Plugins:          []string{"essentialsx"},
+	}
+
+	spec, err := in.spec()
+	if err != nil {
+		t.Fatalf("spec() error = %v", err)
+	}
+	want := models.DefaultServerSettings()
+	want.LevelSeed = "12345"
+	if spec.Settings != want {
+		t.Errorf("settings = %+v, want the defaults with the world seed", spec.Settings)
+	}
+
+	invalid := []UserTemplateInput{
+		{ServerType: "bukkit", MinecraftVersion: "1.21", RAMMb: 2048},
+		{ServerType: "paper", MinecraftVersion: "latest", RAMMb: 2048},
+		{ServerType: "paper", MinecraftVersion: "1.21", RAMMb: 2048, Settings: &models.ServerSettings{MaxPlayers: 20}},
+	}
+	for _, in := range invalid {
+		if _, err := in.spec(); !errors.Is(err, ErrInvalidTemplate) {
+			t.Errorf("spec(%+v) error = %v, want ErrInvalidTemplate", in, err)
+		}
+	}
+}
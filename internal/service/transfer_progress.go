@@ -0,0 +1,241 @@
+package service
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+)
+
+// progressEmitInterval throttles byte-level progress events per transfer
+const progressEmitInterval = time.Second
+
+// progressRateSmoothing is the EWMA weight of the newest throughput sample
+const progressRateSmoothing = 0.3
+
+// TransferProgress tracks the bytes of a long-running backup/restore/migration/archive transfer
+// and publishes throttled operation.transfer.progress events (bytes, throughput, ETA) to the
+// dashboard and the WebSocket hub. A transfer runs through phases (e.g. compressing, uploading);
+// each phase has its own byte total. All methods are no-ops on a nil *TransferProgress.
+type TransferProgress struct {
+	operation   string // backup, restore, migration, archive, unarchive
+	operationID string
+	serverID    string
+	wsHub       WebSocketHubInterface // Optional
+	opLimiter   *OperationLimiter     // Optional, receives the percent of each phase (see ReportTo)
+	opKind      OperationKind
+
+	mu         sync.Mutex
+	phase      string
+	total      int64 // 0 = unknown
+	done       int64
+	phaseStart time.Time
+	lastEmit   time.Time
+	lastBytes  int64
+	rate       float64 // Smoothed bytes/s
+}
+
+// NewTransferProgress creates a progress tracker for an operation
+func NewTransferProgress(operation, operationID, serverID string, wsHub WebSocketHubInterface) *TransferProgress {
+	return &TransferProgress{
+		operation:   operation,
+		operationID: operationID,
+		serverID:    serverID,
+		wsHub:       wsHub,
+	}
+}
+
+// ReportTo forwards the phase and percent to the operation of kind working on the operation ID
+// (the backup ID for backups and restores), so GET /api/jobs shows the progress
+func (p *TransferProgress) ReportTo(opLimiter *OperationLimiter, kind OperationKind) *TransferProgress {
+	p.opLimiter = opLimiter
+	p.opKind = kind
+	return p
+}
+
+// StartPhase resets the counters for a new phase (totalBytes 0 = unknown) and emits immediately
+func (p *TransferProgress) StartPhase(phase string, totalBytes int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.phase = phase
+	p.total = totalBytes
+	p.done = 0
+	p.phaseStart = now
+	p.lastEmit = now
+	p.lastBytes = 0
+	p.rate = 0
+	p.emitLocked(now)
+}
+
+// Add records n processed bytes
+func (p *TransferProgress) Add(n int64) {
+	if p == nil || n <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done += n
+	p.maybeEmitLocked()
+}
+
+// Set records absolute progress (total <= 0 keeps the current total)
+func (p *TransferProgress) Set(done, total int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done = done
+	if total > 0 {
+		p.total = total
+	}
+	p.maybeEmitLocked()
+}
+
+// Finish marks the current phase complete and emits a final event
+func (p *TransferProgress) Finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.total > 0 {
+		p.done = p.total
+	} else {
+		p.total = p.done
+	}
+	p.emitLocked(time.Now())
+}
+
+// Reader wraps r so every read counts as progress
+func (p *TransferProgress) Reader(r io.Reader) io.Reader {
+	if p == nil {
+		return r
+	}
+	return &progressReader{r: r, progress: p}
+}
+
+// Func adapts the tracker to (transferred, total) callbacks, e.g. storage.ProgressFunc
+func (p *TransferProgress) Func() func(transferred, total int64) {
+	if p == nil {
+		return nil
+	}
+	return p.Set
+}
+
+func (p *TransferProgress) maybeEmitLocked() {
+	now := time.Now()
+	if now.Sub(p.lastEmit) < progressEmitInterval {
+		return
+	}
+	p.emitLocked(now)
+}
+
+func (p *TransferProgress) emitLocked(now time.Time) {
+	if elapsed := now.Sub(p.lastEmit).Seconds(); elapsed > 0 {
+		sample := float64(p.done-p.lastBytes) / elapsed
+		if p.rate == 0 {
+			p.rate = sample
+		} else {
+			p.rate = progressRateSmoothing*sample + (1-progressRateSmoothing)*p.rate
+		}
+	}
+	p.lastEmit = now
+	p.lastBytes = p.done
+
+	data := map[string]interface{}{
+		"operation":      p.operation,
+		"operation_id":   p.operationID,
+		"server_id":      p.serverID,
+		"phase":          p.phase,
+		"bytes_done":     p.done,
+		"bytes_total":    p.total,
+		"throughput_bps": int64(p.rate),
+		"elapsed_s":      int(now.Sub(p.phaseStart).Seconds()),
+	}
+	if p.total > 0 {
+		percent := float64(p.done) / float64(p.total) * 100
+		if percent > 100 {
+			percent = 100
+		}
+		data["percent"] = int(percent)
+		p.opLimiter.SetProgress(p.opKind, p.operationID, p.phase, int(percent))
+		if p.rate > 0 && p.done < p.total {
+			data["eta_s"] = int(float64(p.total-p.done) / p.rate)
+		}
+	}
+
+	events.PublishTransferProgress(data)
+	if p.wsHub != nil {
+		p.wsHub.Broadcast(events.TransferProgressEventType, data)
+	}
+}
+
+// progressReader counts bytes read through it
+type progressReader struct {
+	r        io.Reader
+	progress *TransferProgress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.progress.Add(int64(n))
+	return n, err
+}
+
+// rsyncProgressWriter parses rsync --info=progress2 output ("  1,234,567  45%  12.34MB/s  0:01:23")
+// into TransferProgress updates; rsync separates updates with \r
+type rsyncProgressWriter struct {
+	progress *TransferProgress
+	partial  []byte
+}
+
+func (w *rsyncProgressWriter) Write(b []byte) (int, error) {
+	w.partial = append(w.partial, b...)
+	for {
+		index := bytes.IndexAny(w.partial, "\r\n")
+		if index < 0 {
+			break
+		}
+		w.parseLine(string(w.partial[:index]))
+		w.partial = w.partial[index+1:]
+	}
+	if len(w.partial) > 4096 {
+		w.partial = w.partial[:0] // Not a progress line; don't buffer unbounded output
+	}
+	return len(b), nil
+}
+
+func (w *rsyncProgressWriter) parseLine(line string) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || !strings.HasSuffix(fields[1], "%") {
+		return
+	}
+	done, err := strconv.ParseInt(strings.ReplaceAll(fields[0], ",", ""), 10, 64)
+	if err != nil {
+		return
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(fields[1], "%"))
+	if err != nil {
+		return
+	}
+
+	// progress2 reports overall percent, from which the (growing) total is estimated
+	var total int64
+	if percent > 0 {
+		total = done * 100 / int64(percent)
+	}
+	w.progress.Set(done, total)
+}
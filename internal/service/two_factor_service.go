@@ -0,0 +1,398 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// TOTP parameters (RFC 6238 defaults, supported by all common authenticator apps)
+const (
+	totpPeriod     = 30 // Seconds per time step
+	totpDigits     = 6
+	totpSkew       = 1  // Accepted steps before/after the current one (clock drift)
+	totpSecretSize = 20 // 160-bit secret
+)
+
+// recoveryCodeAlphabet avoids easily confused characters (0/o, 1/l/i)
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// TwoFactorStatus is the 2FA state of a user
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	EnrollmentPending      bool       `json:"enrollment_pending"` // Secret issued but not confirmed yet
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorService handles TOTP enrollment, verification and recovery codes
+type TwoFactorService struct {
+	userRepo        *repository.UserRepository
+	codeRepo        *repository.RecoveryCodeRepository
+	securityService *SecurityService
+	issuer          string
+	key             []byte // AES-256 key for TOTP secrets at rest
+}
+
+// NewTwoFactorService creates a new two-factor authentication service
+func NewTwoFactorService(userRepo *repository.UserRepository, codeRepo *repository.RecoveryCodeRepository, securityService *SecurityService, cfg *config.Config) *TwoFactorService {
+	keySource := cfg.TwoFactorEncryptionKey
+	if keySource == "" {
+		logger.Warn("2FA: TWO_FACTOR_ENCRYPTION_KEY not set, deriving the secret encryption key from JWT_SECRET", nil)
+		keySource = "two-factor:" + cfg.JWTSecret
+	}
+	key := sha256.Sum256([]byte(keySource))
+
+	return &TwoFactorService{
+		userRepo:        userRepo,
+		codeRepo:        codeRepo,
+		securityService: securityService,
+		issuer:          cfg.TwoFactorIssuer,
+		key:             key[:],
+	}
+}
+
+// Status returns the 2FA state of a user
+func (s *TwoFactorService) Status(userID string) (*TwoFactorStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &TwoFactorStatus{
+		Enabled:           user.TwoFactorEnabled,
+		EnabledAt:         user.TwoFactorEnabledAt,
+		EnrollmentPending: !user.TwoFactorEnabled && user.TwoFactorSecret != "",
+	}
+	if user.TwoFactorEnabled {
+		if status.RecoveryCodesRemaining, err = s.codeRepo.CountUnused(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// IsEnabled returns true if the user has confirmed 2FA
+func (s *TwoFactorService) IsEnabled(userID string) bool {
+	user, err := s.userRepo.FindByID(userID)
+	return err == nil && user.TwoFactorEnabled
+}
+
+// BeginEnrollment issues a new TOTP secret for the user
+// Returns the base32 secret and an otpauth:// URL for QR codes. 2FA is only enabled after ConfirmEnrollment.
+func (s *TwoFactorService) BeginEnrollment(userID string) (string, string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", "", err
+	}
+	if user.TwoFactorEnabled {
+		return "", "", models.ErrTwoFactorAlreadyEnabled
+	}
+
+	raw := make([]byte, totpSecretSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+
+	encrypted, err := s.encryptSecret(secret)
+	if err != nil {
+		return "", "", err
+	}
+	user.TwoFactorSecret = encrypted
+	user.TwoFactorLastStep = 0
+	if err := s.userRepo.Update(user); err != nil {
+		return "", "", err
+	}
+
+	label := url.PathEscape(s.issuer + ":" + user.Email)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", s.issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", totpDigits))
+	params.Set("period", fmt.Sprintf("%d", totpPeriod))
+
+	return secret, "otpauth://totp/" + label + "?" + params.Encode(), nil
+}
+
+// ConfirmEnrollment enables 2FA once the user proves their authenticator works
+// Returns the recovery codes (shown once).
+func (s *TwoFactorService) ConfirmEnrollment(userID, code, ipAddress, userAgent string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, models.ErrTwoFactorAlreadyEnabled
+	}
+	if user.TwoFactorSecret == "" {
+		return nil, models.ErrTwoFactorNotEnrolled
+	}
+
+	if err := s.checkTOTP(user, code); err != nil {
+		_ = s.securityService.LogSecurityEvent(userID, models.EventTwoFactorFailure, ipAddress, userAgent, false, "Invalid code during enrollment")
+		return nil, err
+	}
+
+	// Reload: checkTOTP advanced the last step in the database
+	if user, err = s.userRepo.FindByID(userID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user.TwoFactorEnabled = true
+	user.TwoFactorEnabledAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.issueRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.securityService.LogSecurityEvent(userID, models.EventTwoFactorEnabled, ipAddress, userAgent, true, "")
+	logger.Info("2FA: Enabled", map[string]interface{}{
+		"user_id": userID,
+	})
+	return codes, nil
+}
+
+// Disable turns off 2FA after re-authentication (password unless the account is OAuth-only, plus a 2FA or recovery code)
+func (s *TwoFactorService) Disable(userID, password, code, ipAddress, userAgent string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled {
+		return models.ErrTwoFactorNotEnabled
+	}
+	if !user.OAuthOnly && !user.CheckPassword(password) {
+		return models.ErrInvalidCredentials
+	}
+	if err := s.VerifyCode(userID, code, ipAddress, userAgent); err != nil {
+		return err
+	}
+
+	if user, err = s.userRepo.FindByID(userID); err != nil {
+		return err
+	}
+	user.TwoFactorEnabled = false
+	user.TwoFactorEnabledAt = nil
+	user.TwoFactorSecret = ""
+	user.TwoFactorLastStep = 0
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	if err := s.codeRepo.DeleteForUser(userID); err != nil {
+		return err
+	}
+
+	_ = s.securityService.LogSecurityEvent(userID, models.EventTwoFactorDisabled, ipAddress, userAgent, true, "")
+	logger.Info("2FA: Disabled", map[string]interface{}{
+		"user_id": userID,
+	})
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes (requires a TOTP code, not a recovery code)
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID, code, ipAddress, userAgent string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled {
+		return nil, models.ErrTwoFactorNotEnabled
+	}
+	if err := s.checkTOTP(user, code); err != nil {
+		_ = s.securityService.LogSecurityEvent(userID, models.EventTwoFactorFailure, ipAddress, userAgent, false, "Invalid code for recovery code regeneration")
+		return nil, err
+	}
+
+	codes, err := s.issueRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.securityService.LogSecurityEvent(userID, models.EventRecoveryCodesReset, ipAddress, userAgent, true, "")
+	return codes, nil
+}
+
+// VerifyCode checks a TOTP code or, failing that, consumes a recovery code
+func (s *TwoFactorService) VerifyCode(userID, code, ipAddress, userAgent string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled {
+		return models.ErrTwoFactorNotEnabled
+	}
+	if strings.TrimSpace(code) == "" {
+		return models.ErrTwoFactorRequired
+	}
+
+	if err := s.checkTOTP(user, code); err == nil {
+		return nil
+	}
+
+	used, err := s.codeRepo.Consume(userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if used {
+		remaining, _ := s.codeRepo.CountUnused(userID)
+		_ = s.securityService.LogSecurityEvent(userID, models.EventRecoveryCodeUsed, ipAddress, userAgent, true,
+			fmt.Sprintf("%d recovery codes remaining", remaining))
+		return nil
+	}
+
+	_ = s.securityService.LogSecurityEvent(userID, models.EventTwoFactorFailure, ipAddress, userAgent, false, "Invalid code")
+	return models.ErrInvalidTwoFactorCode
+}
+
+// checkTOTP validates a TOTP code against the user's secret and records its time step (each code works once)
+func (s *TwoFactorService) checkTOTP(user *models.User, code string) error {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != totpDigits {
+		return models.ErrInvalidTwoFactorCode
+	}
+
+	secret, err := s.decryptSecret(user.TwoFactorSecret)
+	if err != nil {
+		return err
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return fmt.Errorf("invalid stored secret: %w", err)
+	}
+
+	current := time.Now().Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) != 1 {
+			continue
+		}
+		fresh, err := s.userRepo.AdvanceTwoFactorStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return models.ErrInvalidTwoFactorCode // Code already used
+		}
+		return nil
+	}
+	return models.ErrInvalidTwoFactorCode
+}
+
+// issueRecoveryCodes replaces the user's recovery codes and returns the new plaintext codes
+func (s *TwoFactorService) issueRecoveryCodes(userID string) ([]string, error) {
+	codes := make([]string, models.RecoveryCodeCount)
+	records := make([]models.RecoveryCode, models.RecoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		records[i] = models.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)}
+	}
+
+	if err := s.codeRepo.ReplaceForUser(userID, records); err != nil {
+		return nil, fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// encryptSecret encrypts a TOTP secret with AES-GCM (base64 of nonce + ciphertext)
+func (s *TwoFactorService) encryptSecret(secret string) (string, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+// decryptSecret reverses encryptSecret
+func (s *TwoFactorService) decryptSecret(encrypted string) (string, error) {
+	if encrypted == "" {
+		return "", models.ErrTwoFactorNotEnrolled
+	}
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted secret: %w", err)
+	}
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted secret")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret (encryption key changed?): %w", err)
+	}
+	return string(plain), nil
+}
+
+// totpCode computes the HOTP value (RFC 4226) for a time step
+func totpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// generateRecoveryCode returns a random code like "abcde-fgh23"
+func generateRecoveryCode() (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+	code := make([]byte, len(raw))
+	for i, b := range raw {
+		code[i] = recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)]
+	}
+	return string(code[:5]) + "-" + string(code[5:]), nil
+}
+
+// hashRecoveryCode hashes a recovery code, ignoring case, spaces and dashes
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	// usageArchiveDefaultInterval applies if USAGE_ARCHIVE_INTERVAL is not a valid duration
+	usageArchiveDefaultInterval = 24 * time.Hour
+	// usageArchiveStartDelay is the wait before the first run, so restarts don't postpone archival forever
+	usageArchiveStartDelay = 10 * time.Minute
+)
+
+var (
+	// ErrUsageArchiveDisabled is returned if USAGE_ARCHIVE_RETENTION_DAYS is 0
+	ErrUsageArchiveDisabled = errors.New("usage archival is disabled")
+	// ErrUsageArchiveRunning is returned if an archival run is already in progress
+	ErrUsageArchiveRunning = errors.New("usage archival is already running")
+	// errUsageArchiveChanged aborts a month whose rows changed between export and delete
+	errUsageArchiveChanged = errors.New("rows changed during the export")
+)
+
+// usageArchiveTable is a history table whose old months are archived
+type usageArchiveTable struct {
+	name       string
+	timeColumn string
+	condition  string // Extra condition for archivable rows, "" = all rows
+}
+
+// usageArchiveTables are archived in this order. Usage logs are only archived once closed;
+// usage sessions stay, they are the source of invoices and wallet charges.
+var usageArchiveTables = []usageArchiveTable{
+	{name: "usage_logs", timeColumn: "started_at", condition: "stopped_at IS NOT NULL"},
+	{name: "system_events", timeColumn: "timestamp"},
+	{name: "billing_events", timeColumn: "timestamp"},
+}
+
+// UsageArchiveStore keeps the exported archive files (the Storage Box SFTP client)
+type UsageArchiveStore interface {
+	Upload(localPath, remoteName string) (string, error)
+}
+
+// UsageArchiveRun is the result of one archival run
+type UsageArchiveRun struct {
+	Cutoff   time.Time             `json:"cutoff"` // Months before this were archived
+	Archives []models.UsageArchive `json:"archives"`
+	Rows     int64                 `json:"rows"`
+}
+
+// UsageArchiveService keeps the usage log and event tables small: every month that is older than
+// USAGE_ARCHIVE_RETENTION_DAYS is exported as gzipped CSV to the Storage Box (or kept in
+// USAGE_ARCHIVE_PATH without it) and then deleted. A month is only deleted if the upload
+// succeeded and exactly the exported rows are deleted, otherwise it is retried on the next run.
+type UsageArchiveService struct {
+	db            *gorm.DB
+	store         UsageArchiveStore // nil = archives stay in dir
+	dir           string
+	retentionDays int
+	interval      time.Duration
+
+	running sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewUsageArchiveService creates a new usage archive service; store may be nil
+func NewUsageArchiveService(db *gorm.DB, store UsageArchiveStore, cfg *config.Config) *UsageArchiveService {
+	interval, err := time.ParseDuration(cfg.UsageArchiveInterval)
+	if err != nil || interval <= 0 {
+		interval = usageArchiveDefaultInterval
+	}
+	return &UsageArchiveService{
+		db:            db,
+		store:         store,
+		dir:           cfg.UsageArchivePath,
+		retentionDays: cfg.UsageArchiveRetentionDays,
+		interval:      interval,
+	}
+}
+
+// List returns the archived months, newest first
+func (s *UsageArchiveService) List(limit int) ([]models.UsageArchive, error) {
+	var archives []models.UsageArchive
+	err := s.db.Order("month DESC, source, part DESC").Limit(limit).Find(&archives).Error
+	return archives, err
+}
+
+// Run archives all months before the retention cutoff
+func (s *UsageArchiveService) Run(ctx context.Context) (*UsageArchiveRun, error) {
+	if s.retentionDays <= 0 {
+		return nil, ErrUsageArchiveDisabled
+	}
+	if !s.running.TryLock() {
+		return nil, ErrUsageArchiveRunning
+	}
+	defer s.running.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	run := &UsageArchiveRun{
+		Cutoff:   startOfMonth(time.Now().AddDate(0, 0, -s.retentionDays)),
+		Archives: []models.UsageArchive{},
+	}
+	for _, table := range usageArchiveTables {
+		for ctx.Err() == nil {
+			month, found, err := s.oldestMonth(ctx, table, run.Cutoff)
+			if err != nil {
+				return run, fmt.Errorf("%s: %w", table.name, err)
+			}
+			if !found {
+				break
+			}
+			archive, err := s.archiveMonth(ctx, table, month)
+			if err != nil {
+				return run, fmt.Errorf("%s %s: %w", table.name, month.Format("2006-01"), err)
+			}
+			if archive == nil {
+				break
+			}
+			run.Archives = append(run.Archives, *archive)
+			run.Rows += archive.Rows
+		}
+	}
+	return run, ctx.Err()
+}
+
+// Start archives periodically, the first time shortly after startup
+func (s *UsageArchiveService) Start() {
+	if s.retentionDays <= 0 {
+		logger.Info("USAGE-ARCHIVE: Disabled (USAGE_ARCHIVE_RETENTION_DAYS=0)", nil)
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		timer := time.NewTimer(usageArchiveStartDelay)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-timer.C:
+				run, err := s.Run(s.ctx)
+				if err != nil && !errors.Is(err, context.Canceled) {
+					logger.Error("USAGE-ARCHIVE: Archival run failed", err, nil)
+				} else if run != nil && len(run.Archives) > 0 {
+					logger.Info("USAGE-ARCHIVE: Archived old history", map[string]interface{}{
+						"months": len(run.Archives),
+						"rows":   run.Rows,
+						"cutoff": run.Cutoff.Format("2006-01"),
+					})
+				}
+				timer.Reset(s.interval)
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the archival loop, a running export is cancelled
+func (s *UsageArchiveService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// scope restricts db to the archivable rows of table in [from, to)
+func (t usageArchiveTable) scope(db *gorm.DB, from, to time.Time) *gorm.DB {
+	query := db.Table(t.name).Where(t.timeColumn+" >= ? AND "+t.timeColumn+" < ?", from, to)
+	if t.condition != "" {
+		query = query.Where(t.condition)
+	}
+	return query
+}
+
+// oldestMonth returns the start of the oldest month of table with archivable rows before cutoff
+func (s *UsageArchiveService) oldestMonth(ctx context.Context, table usageArchiveTable, cutoff time.Time) (time.Time, bool, error) {
+	var oldest sql.NullTime
+	err := table.scope(s.db.WithContext(ctx), time.Time{}, cutoff).
+		Select("MIN(" + table.timeColumn + ")").
+		Scan(&oldest).Error
+	if err != nil || !oldest.Valid {
+		return time.Time{}, false, err
+	}
+	return startOfMonth(oldest.Time.In(cutoff.Location())), true, nil
+}
+
+// archiveMonth exports, stores and deletes one month of table; nil if it had no rows (anymore)
+func (s *UsageArchiveService) archiveMonth(ctx context.Context, table usageArchiveTable, from time.Time) (*models.UsageArchive, error) {
+	to := from.AddDate(0, 1, 0)
+	month := from.Format("2006-01")
+
+	var parts int64
+	if err := s.db.WithContext(ctx).Model(&models.UsageArchive{}).
+		Where("source = ? AND month = ?", table.name, month).Count(&parts).Error; err != nil {
+		return nil, err
+	}
+	archive := &models.UsageArchive{Source: table.name, Month: month, Part: int(parts) + 1}
+	name := fmt.Sprintf("usage-archive-%s-%s-%d.csv.gz", table.name, month, archive.Part)
+	path := filepath.Join(s.dir, name)
+
+	query := table.scope(s.db.WithContext(ctx), from, to).Order(table.timeColumn + ", id")
+	rows, sum, size, err := exportCSV(query, path)
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("export failed: %w", err)
+	}
+	if rows == 0 {
+		os.Remove(path)
+		return nil, nil
+	}
+	archive.Rows, archive.SHA256, archive.SizeBytes, archive.Location = rows, sum, size, path
+
+	if s.store != nil {
+		remotePath, err := s.store.Upload(path, name)
+		if err != nil {
+			os.Remove(path)
+			return nil, fmt.Errorf("upload failed: %w", err)
+		}
+		os.Remove(path)
+		archive.Location = remotePath
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		statement := "DELETE FROM " + table.name + " WHERE " + table.timeColumn + " >= ? AND " + table.timeColumn + " < ?"
+		if table.condition != "" {
+			statement += " AND " + table.condition
+		}
+		deleted := tx.Exec(statement, from, to)
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		if deleted.RowsAffected != rows {
+			return fmt.Errorf("%w (exported %d, would delete %d)", errUsageArchiveChanged, rows, deleted.RowsAffected)
+		}
+		return tx.Create(archive).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rows kept, archive %s stays for the next run: %w", archive.Location, err)
+	}
+
+	logger.Info("USAGE-ARCHIVE: Month archived", map[string]interface{}{
+		"table":    table.name,
+		"month":    month,
+		"rows":     rows,
+		"location": archive.Location,
+	})
+	return archive, nil
+}
+
+// exportCSV writes the rows of query to a gzipped CSV file with a header line
+// and returns the row count, the SHA-256 of the file and its size
+func exportCSV(query *gorm.DB, path string) (int64, string, int64, error) {
+	rows, err := query.Rows()
+	if err != nil {
+		return 0, "", 0, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, "", 0, err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, "", 0, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(file, hash))
+	writer := csv.NewWriter(gz)
+	if err := writer.Write(columns); err != nil {
+		return 0, "", 0, err
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	record := make([]string, len(columns))
+	var count int64
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return count, "", 0, err
+		}
+		for i, value := range values {
+			record[i] = csvValue(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return count, "", 0, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, "", 0, err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return count, "", 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return count, "", 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return count, "", 0, err
+	}
+	return count, hex.EncodeToString(hash.Sum(nil)), info.Size(), file.Close()
+}
+
+// csvValue formats a database value for the CSV export (NULL is empty, times are RFC 3339 UTC)
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// startOfMonth returns the first moment of the month of t
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
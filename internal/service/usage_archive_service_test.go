@@ -0,0 +1,139 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql/driver"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+// fakeUsageArchiveStore records uploads and keeps a copy of each file
+type fakeUsageArchiveStore struct {
+	dir     string
+	uploads []string
+	err     error
+}
+
+func (f *fakeUsageArchiveStore) Upload(localPath, remoteName string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return "", err
+	}
+	f.uploads = append(f.uploads, remoteName)
+	return "/archives/" + remoteName, os.WriteFile(filepath.Join(f.dir, remoteName), data, 0644)
+}
+
+func TestUsageArchiveExportsAndDeletesOldMonths(t *testing.T) {
+	old := time.Now().AddDate(-2, 0, 0)
+	monthStart := time.Date(old.Year(), old.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name        string
+		deleted     int64 // Rows the DELETE affects
+		uploadErr   error
+		wantErr     bool
+		wantDeletes int
+		wantInserts int
+	}{
+		{name: "archived", deleted: 2, wantDeletes: 1, wantInserts: 1},
+		{name: "rows changed during export", deleted: 3, wantErr: true, wantDeletes: 1},
+		{name: "upload failed", deleted: 2, uploadErr: errors.New("storage box unreachable"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archived := false
+			db, fake := newFakeSQLDB(t, func(query string, args []driver.NamedValue) fakeSQLResult {
+				switch {
+				case strings.Contains(query, "MIN(started_at)"):
+					if archived {
+						return fakeSQLResult{Columns: []string{"min"}, Rows: [][]driver.Value{{nil}}}
+					}
+					return fakeSQLResult{Columns: []string{"min"}, Rows: [][]driver.Value{{monthStart.Add(36 * time.Hour)}}}
+				case strings.Contains(query, "MIN("):
+					return fakeSQLResult{Columns: []string{"min"}, Rows: [][]driver.Value{{nil}}}
+				case strings.Contains(query, "count(*)"):
+					return fakeSQLResult{Columns: []string{"count"}, Rows: [][]driver.Value{{int64(0)}}}
+				case strings.HasPrefix(query, "SELECT * FROM"):
+					return fakeSQLResult{
+						Columns: []string{"id", "server_id", "started_at", "stopped_at", "cost_eur"},
+						Rows: [][]driver.Value{
+							{int64(1), "srv-1", monthStart.Add(36 * time.Hour), monthStart.Add(38 * time.Hour), 0.25},
+							{int64(2), "srv-2", monthStart.Add(48 * time.Hour), monthStart.Add(49 * time.Hour), nil},
+						},
+					}
+				case strings.HasPrefix(query, "DELETE FROM usage_logs"):
+					if tt.deleted == 2 {
+						archived = true
+					}
+					return fakeSQLResult{RowsAffected: tt.deleted}
+				}
+				return fakeSQLResult{}
+			})
+
+			store := &fakeUsageArchiveStore{dir: t.TempDir(), err: tt.uploadErr}
+			staging := t.TempDir()
+			svc := NewUsageArchiveService(db, store, &config.Config{UsageArchiveRetentionDays: 400, UsageArchivePath: staging})
+
+			run, err := svc.Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := fake.Count("DELETE FROM usage_logs"); got != tt.wantDeletes {
+				t.Errorf("DELETE statements = %d, want %d", got, tt.wantDeletes)
+			}
+			if got := fake.Count(`INSERT INTO "usage_archives"`); got != tt.wantInserts {
+				t.Errorf("archive records = %d, want %d", got, tt.wantInserts)
+			}
+			if tt.wantDeletes > 0 && fake.Count("ROLLBACK") != tt.wantDeletes-tt.wantInserts {
+				t.Errorf("statements = %v, want the delete rolled back unless recorded", fake.Statements())
+			}
+			if leftovers, _ := os.ReadDir(staging); len(leftovers) != 0 {
+				t.Errorf("staging directory keeps %d file(s)", len(leftovers))
+			}
+			if tt.wantErr {
+				return
+			}
+
+			month := monthStart.Format("2006-01")
+			if len(run.Archives) != 1 || run.Rows != 2 || run.Archives[0].Month != month || run.Archives[0].Part != 1 {
+				t.Fatalf("Run() = %+v, want one archive of %s with 2 rows", run, month)
+			}
+			wantName := "usage-archive-usage_logs-" + month + "-1.csv.gz"
+			if len(store.uploads) != 1 || store.uploads[0] != wantName || run.Archives[0].Location != "/archives/"+wantName {
+				t.Fatalf("uploads = %v, location %q; want %s", store.uploads, run.Archives[0].Location, wantName)
+			}
+
+			file, err := os.Open(filepath.Join(store.dir, wantName))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			gz, err := gzip.NewReader(file)
+			if err != nil {
+				t.Fatalf("archive is not gzipped: %v", err)
+			}
+			records, err := csv.NewReader(gz).ReadAll()
+			if err != nil {
+				t.Fatalf("archive is not CSV: %v", err)
+			}
+			if len(records) != 3 || strings.Join(records[0], ",") != "id,server_id,started_at,stopped_at,cost_eur" {
+				t.Fatalf("archive = %v, want a header and 2 rows", records)
+			}
+			if records[1][4] != "0.25" || records[2][4] != "" || !strings.HasSuffix(records[1][2], "Z") {
+				t.Errorf("archive rows = %v, want costs 0.25/empty and UTC times", records[1:])
+			}
+		})
+	}
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/docker"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrVotifierUnsupported is returned for server types that can't run Bukkit plugins
+	ErrVotifierUnsupported = errors.New("votifier requires a plugin-capable server (paper, spigot, purpur)")
+	// ErrVotifierNotEnabled is returned when votes arrive for a server without Votifier
+	ErrVotifierNotEnabled = errors.New("votifier is not enabled for this server")
+	// ErrVoteUnauthorized is returned for relay requests with a wrong token
+	ErrVoteUnauthorized = errors.New("invalid vote relay token")
+	// ErrVoteServerOffline is returned when the backend isn't running to receive the vote
+	ErrVoteServerOffline = errors.New("server is not running, vote not delivered")
+)
+
+const (
+	votifierPluginSlug  = "nuvotifier"
+	votifierPluginDir   = "Votifier" // NuVotifier keeps the Bukkit plugin folder name
+	votifierDialTimeout = 5 * time.Second
+	votifierV2Magic     = 0x733A // NuVotifier protocol v2 frame magic
+)
+
+// NodeAddressResolver resolves the address of a remote node (implemented by the Conductor)
+type NodeAddressResolver interface {
+	GetRemoteNode(nodeID string) (*docker.RemoteNode, error)
+}
+
+// VotifierService manages NuVotifier setups for vote site integrations
+// Enabling generates the RSA key pair (Votifier v1) and token (NuVotifier v2), takes a host port
+// from the Minecraft port range (already open in the node firewall), writes the plugin config and
+// installs the plugin. Sites that only support HTTP callbacks use the platform relay, which
+// forwards votes to the backend over the v2 protocol.
+type VotifierService struct {
+	db            *gorm.DB
+	serverRepo    *repository.ServerRepository
+	dockerService *docker.DockerService
+	cfg           *config.Config
+
+	// Optional: plugin installation and remote node addresses
+	pluginManager *PluginManagerService
+	nodes         NodeAddressResolver
+}
+
+// NewVotifierService creates a new Votifier service
+func NewVotifierService(db *gorm.DB, serverRepo *repository.ServerRepository, dockerService *docker.DockerService, cfg *config.Config) *VotifierService {
+	return &VotifierService{
+		db:            db,
+		serverRepo:    serverRepo,
+		dockerService: dockerService,
+		cfg:           cfg,
+	}
+}
+
+// SetPluginManager enables automatic NuVotifier installation
+func (s *VotifierService) SetPluginManager(pluginManager *PluginManagerService) {
+	s.pluginManager = pluginManager
+}
+
+// SetNodeResolver enables relaying votes to servers on remote nodes
+func (s *VotifierService) SetNodeResolver(nodes NodeAddressResolver) {
+	s.nodes = nodes
+}
+
+// GetConfig returns the Votifier setup of a server
+func (s *VotifierService) GetConfig(serverID string) (*models.VotifierConfig, error) {
+	var cfg models.VotifierConfig
+	if err := s.db.Where("server_id = ?", serverID).First(&cfg).Error; err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Enable sets up NuVotifier for a server
+// Keys and tokens are kept across disable/enable so vote sites don't need to be reconfigured.
+// Returns whether the plugin was installed; the setup takes effect on the next start.
+func (s *VotifierService) Enable(serverID string) (*models.VotifierConfig, bool, error) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, false, fmt.Errorf("server not found: %w", err)
+	}
+
+	switch server.ServerType {
+	case models.ServerTypePaper, models.ServerTypeSpigot, models.ServerTypePurpur:
+	default:
+		return nil, false, ErrVotifierUnsupported
+	}
+
+	votifierCfg, err := s.GetConfig(serverID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, fmt.Errorf("failed to load votifier config: %w", err)
+		}
+		if votifierCfg, err = newVotifierConfig(serverID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// Allocate the host port (reuse the previous one while it's still free)
+	if server.VotifierPort == 0 {
+		usedPorts, err := s.serverRepo.GetUsedPorts()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get used ports: %w", err)
+		}
+		port := votifierCfg.Port
+		if port == 0 || containsPort(usedPorts, port) {
+			if port, err = s.dockerService.FindAvailablePort(usedPorts); err != nil {
+				return nil, false, err
+			}
+		}
+		server.VotifierPort = port
+	}
+	votifierCfg.Port = server.VotifierPort
+	votifierCfg.Enabled = true
+
+	if err := s.writePluginConfig(server.ID, votifierCfg); err != nil {
+		return nil, false, err
+	}
+
+	if err := s.db.Save(votifierCfg).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to save votifier config: %w", err)
+	}
+	if err := s.serverRepo.Update(server); err != nil {
+		return nil, false, fmt.Errorf("failed to update server: %w", err)
+	}
+
+	pluginInstalled := s.installPlugin(server.ID)
+
+	logger.Info("VOTIFIER: Enabled", map[string]interface{}{
+		"server_id":        serverID,
+		"port":             votifierCfg.Port,
+		"plugin_installed": pluginInstalled,
+	})
+
+	return votifierCfg, pluginInstalled, nil
+}
+
+// Disable releases the Votifier port (the plugin stays installed, without a mapped port)
+func (s *VotifierService) Disable(serverID string) error {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return fmt.Errorf("server not found: %w", err)
+	}
+
+	if err := s.db.Model(&models.VotifierConfig{}).Where("server_id = ?", serverID).
+		Updates(map[string]interface{}{"enabled": false}).Error; err != nil {
+		return fmt.Errorf("failed to update votifier config: %w", err)
+	}
+
+	server.VotifierPort = 0
+	if err := s.serverRepo.Update(server); err != nil {
+		return fmt.Errorf("failed to update server: %w", err)
+	}
+
+	logger.Info("VOTIFIER: Disabled", map[string]interface{}{
+		"server_id": serverID,
+	})
+
+	return nil
+}
+
+// RelayVote forwards a vote posted to the platform endpoint to the server's NuVotifier
+func (s *VotifierService) RelayVote(serverID, relayToken string, vote models.Vote) error {
+	votifierCfg, err := s.GetConfig(serverID)
+	if err != nil || !votifierCfg.Enabled {
+		return ErrVotifierNotEnabled
+	}
+
+	if subtle.ConstantTimeCompare([]byte(relayToken), []byte(votifierCfg.RelayToken)) != 1 {
+		return ErrVoteUnauthorized
+	}
+
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return fmt.Errorf("server not found: %w", err)
+	}
+	if server.Status != models.StatusRunning {
+		return ErrVoteServerOffline
+	}
+
+	host, err := s.nodeHost(server)
+	if err != nil {
+		return err
+	}
+
+	if vote.Timestamp == 0 {
+		vote.Timestamp = time.Now().UnixMilli()
+	}
+
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", server.VotifierPort))
+	if err := sendVoteV2(addr, votifierCfg.Token, vote); err != nil {
+		logger.Warn("VOTIFIER: Vote relay failed", map[string]interface{}{
+			"server_id": serverID,
+			"service":   vote.ServiceName,
+			"error":     err.Error(),
+		})
+		return fmt.Errorf("failed to deliver vote: %w", err)
+	}
+
+	now := time.Now()
+	s.db.Model(&models.VotifierConfig{}).Where("server_id = ?", serverID).
+		Updates(map[string]interface{}{
+			"votes_relayed": gorm.Expr("votes_relayed + 1"),
+			"last_vote_at":  now,
+		})
+
+	logger.Info("VOTIFIER: Vote relayed", map[string]interface{}{
+		"server_id": serverID,
+		"service":   vote.ServiceName,
+		"username":  vote.Username,
+	})
+
+	return nil
+}
+
+// VoteAddress returns the host:port vote sites send Votifier packets to
+func (s *VotifierService) VoteAddress(serverID string) (string, error) {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return "", fmt.Errorf("server not found: %w", err)
+	}
+	if server.VotifierPort == 0 {
+		return "", ErrVotifierNotEnabled
+	}
+
+	host, err := s.nodeHost(server)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, fmt.Sprintf("%d", server.VotifierPort)), nil
+}
+
+// nodeHost returns the address a server's published ports are reachable on
+func (s *VotifierService) nodeHost(server *models.MinecraftServer) (string, error) {
+	if server.NodeID == "" || server.NodeID == "local-node" {
+		return s.cfg.ControlPlaneIP, nil
+	}
+
+	if s.nodes == nil {
+		return "", fmt.Errorf("cannot resolve node %s", server.NodeID)
+	}
+	node, err := s.nodes.GetRemoteNode(server.NodeID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve node %s: %w", server.NodeID, err)
+	}
+	return node.GetIPAddress(), nil
+}
+
+// writePluginConfig writes config.yml and the RSA key pair into the NuVotifier plugin folder
+func (s *VotifierService) writePluginConfig(serverID string, votifierCfg *models.VotifierConfig) error {
+	pluginDir := filepath.Join(s.cfg.ServersBasePath, serverID, "plugins", votifierPluginDir)
+	if err := os.MkdirAll(filepath.Join(pluginDir, "rsa"), 0755); err != nil {
+		return fmt.Errorf("failed to create votifier plugin directory: %w", err)
+	}
+
+	// The listener always binds the container port; the host port is mapped by Docker
+	configYAML := fmt.Sprintf(`# Managed by PayPerPlay - changes are overwritten when Votifier is re-enabled
+host: 0.0.0.0
+port: %s
+debug: false
+disable-v1-protocol: false
+tokens:
+  default: %s
+forwarding:
+  method: none
+`, strings.TrimSuffix(docker.VotifierContainerPort, "/tcp"), votifierCfg.Token)
+
+	files := map[string]string{
+		filepath.Join(pluginDir, "config.yml"):         configYAML,
+		filepath.Join(pluginDir, "rsa", "public.key"):  votifierCfg.PublicKey,
+		filepath.Join(pluginDir, "rsa", "private.key"): votifierCfg.PrivateKey,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	return nil
+}
+
+// installPlugin installs NuVotifier from the plugin catalog; returns false if it isn't available
+func (s *VotifierService) installPlugin(serverID string) bool {
+	if s.pluginManager == nil {
+		return false
+	}
+
+	if err := s.pluginManager.InstallPlugin(serverID, votifierPluginSlug, "", true); err != nil {
+		if strings.Contains(err.Error(), "already installed") {
+			return true
+		}
+		logger.Warn("VOTIFIER: Failed to install NuVotifier", map[string]interface{}{
+			"server_id": serverID,
+			"error":     err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+// newVotifierConfig generates the keys and tokens of a new Votifier setup
+func newVotifierConfig(serverID string) (*models.VotifierConfig, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate RSA key: %w", err)
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+
+	token, err := randomHexToken(16)
+	if err != nil {
+		return nil, err
+	}
+	relayToken, err := randomHexToken(24)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.VotifierConfig{
+		ServerID:   serverID,
+		PublicKey:  base64.StdEncoding.EncodeToString(publicDER),
+		PrivateKey: base64.StdEncoding.EncodeToString(privateDER),
+		Token:      token,
+		RelayToken: relayToken,
+	}, nil
+}
+
+// randomHexToken returns n random bytes, hex encoded
+func randomHexToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// containsPort reports whether port is in ports
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// sendVoteV2 delivers a vote using the NuVotifier v2 protocol:
+// the server greets with "VOTIFIER 2 <challenge>", the client answers with a framed JSON message
+// whose payload (including the challenge) is signed with HMAC-SHA256 using the server token.
+func sendVoteV2(addr, token string, vote models.Vote) error {
+	conn, err := net.DialTimeout("tcp", addr, votifierDialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(votifierDialTimeout))
+
+	reader := bufio.NewReader(conn)
+	greeting, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("no greeting: %w", err)
+	}
+	fields := strings.Fields(greeting)
+	if len(fields) != 3 || fields[0] != "VOTIFIER" || fields[1] != "2" {
+		return fmt.Errorf("unsupported votifier greeting: %q", strings.TrimSpace(greeting))
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"serviceName": vote.ServiceName,
+		"username":    vote.Username,
+		"address":     vote.Address,
+		"timestamp":   vote.Timestamp,
+		"challenge":   fields[2],
+	})
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write(payload)
+	message, err := json.Marshal(map[string]string{
+		"payload":   string(payload),
+		"signature": base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+	})
+	if err != nil {
+		return err
+	}
+
+	frame := make([]byte, 4, 4+len(message))
+	binary.BigEndian.PutUint16(frame[0:2], votifierV2Magic)
+	binary.BigEndian.PutUint16(frame[2:4], uint16(len(message)))
+	if _, err := conn.Write(append(frame, message...)); err != nil {
+		return err
+	}
+
+	var response struct {
+		Status string `json:"status"`
+		Cause  string `json:"cause"`
+		Error  string `json:"error"`
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return fmt.Errorf("no response: %w", err)
+	}
+	if err := json.Unmarshal([]byte(line), &response); err != nil {
+		return fmt.Errorf("invalid response: %q", strings.TrimSpace(line))
+	}
+	if response.Status != "ok" {
+		return fmt.Errorf("votifier rejected vote: %s %s", response.Cause, response.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrWalletDisabled      = errors.New("wallet is not enabled")
+	ErrInsufficientBalance = errors.New("insufficient balance, please top up your credit")
+)
+
+// walletEpsilon ignores sub-cent rounding noise when settling sessions
+const walletEpsilon = 0.0001
+
+// WalletService manages prepaid credit: purchases, the balance ledger and usage deduction
+// Users enrolled in Stripe metered billing (postpaid) are never charged from their wallet.
+type WalletService struct {
+	db        *gorm.DB
+	cfg       *config.Config
+	startedAt time.Time // Closed sessions older than this and never tracked are not charged retroactively
+
+	ticker   *time.Ticker
+	stopChan chan bool
+}
+
+// NewWalletService creates a new wallet service
+func NewWalletService(db *gorm.DB, cfg *config.Config) *WalletService {
+	return &WalletService{
+		db:        db,
+		cfg:       cfg,
+		startedAt: time.Now(),
+		stopChan:  make(chan bool),
+	}
+}
+
+// IsEnabled returns true if prepaid credit is enforced
+func (s *WalletService) IsEnabled() bool {
+	return s.cfg.WalletEnabled
+}
+
+// LowBalanceThreshold returns the balance below which owners are warned
+func (s *WalletService) LowBalanceThreshold() float64 {
+	return s.cfg.WalletLowBalanceEUR
+}
+
+// Start starts the usage deduction worker
+func (s *WalletService) Start() {
+	interval, err := time.ParseDuration(s.cfg.WalletChargeInterval)
+	if err != nil || interval <= 0 {
+		interval = time.Minute
+	}
+
+	logger.Info("WALLET: Starting usage deduction worker", map[string]interface{}{
+		"interval": interval.String(),
+	})
+
+	s.ticker = time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-s.ticker.C:
+				s.ChargeUsage()
+			case <-s.stopChan:
+				logger.Info("WALLET: Stopping usage deduction worker", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the usage deduction worker
+func (s *WalletService) Stop() {
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+	s.stopChan <- true
+}
+
+// GetBalance returns the current credit balance of a user
+func (s *WalletService) GetBalance(userID string) (float64, error) {
+	var user models.User
+	if err := s.db.Select("balance").Where("id = ?", userID).First(&user).Error; err != nil {
+		return 0, err
+	}
+	return user.Balance, nil
+}
+
+// IsPrepaid returns true if the user pays from their wallet (not enrolled in Stripe metered billing)
+func (s *WalletService) IsPrepaid(userID string) bool {
+	var count int64
+	s.db.Model(&models.StripeCustomer{}).Where("user_id = ? AND subscription_id <> ''", userID).Count(&count)
+	return count == 0
+}
+
+// GetTransactions returns the most recent ledger entries of a user
+func (s *WalletService) GetTransactions(userID string, limit int) ([]models.WalletTransaction, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	var transactions []models.WalletTransaction
+	err := s.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// AddCredit adds credit to a user's balance (idempotent per non-empty reference)
+func (s *WalletService) AddCredit(userID string, amountEUR float64, txType models.WalletTransactionType, reference, description string) (*models.WalletTransaction, error) {
+	return s.addCredit(s.db, userID, amountEUR, txType, reference, description)
+}
+
+// addCredit adds credit using db, which may be a transaction of the caller (e.g. a webhook event)
+func (s *WalletService) addCredit(db *gorm.DB, userID string, amountEUR float64, txType models.WalletTransactionType, reference, description string) (*models.WalletTransaction, error) {
+	if amountEUR <= 0 {
+		return nil, fmt.Errorf("credit amount must be positive")
+	}
+
+	if reference != "" {
+		var existing models.WalletTransaction
+		err := db.Where("reference = ? AND type = ?", reference, txType).First(&existing).Error
+		if err == nil {
+			return &existing, nil // Already credited (e.g. webhook retry)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	tx := &models.WalletTransaction{
+		UserID:      userID,
+		Type:        txType,
+		AmountEUR:   amountEUR,
+		Reference:   reference,
+		Description: description,
+	}
+	if err := applyTransaction(db, tx); err != nil {
+		if reference != "" && isDuplicateKey(err) {
+			// A concurrent delivery credited the reference first
+			var existing models.WalletTransaction
+			if findErr := db.Where("reference = ? AND type = ?", reference, txType).First(&existing).Error; findErr != nil {
+				return nil, findErr
+			}
+			return &existing, nil
+		}
+		return nil, err
+	}
+
+	logger.Info("WALLET: Credit added", map[string]interface{}{
+		"user_id":       userID,
+		"amount_eur":    amountEUR,
+		"balance_after": tx.BalanceAfterEUR,
+		"type":          txType,
+	})
+
+	return tx, nil
+}
+
+// applyTransaction atomically updates User.Balance and writes the ledger entry
+// Within a transaction of the caller it runs in a savepoint.
+func applyTransaction(db *gorm.DB, entry *models.WalletTransaction) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ?", entry.UserID).
+			UpdateColumn("balance", gorm.Expr("balance + ?", entry.AmountEUR))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("user %s not found", entry.UserID)
+		}
+
+		var user models.User
+		if err := tx.Select("balance").Where("id = ?", entry.UserID).First(&user).Error; err != nil {
+			return err
+		}
+		entry.BalanceAfterEUR = user.Balance
+
+		return tx.Create(entry).Error
+	})
+}
+
+// ChargeUsage deducts running and just-closed usage sessions of prepaid owners from their balance
+func (s *WalletService) ChargeUsage() {
+	if !s.IsEnabled() {
+		return
+	}
+
+	postpaid := s.db.Model(&models.StripeCustomer{}).Select("user_id").Where("subscription_id <> ''")
+
+	// Running sessions: charge time since the last deduction
+	var openSessions []models.UsageSession
+	if err := s.db.Where("stopped_at IS NULL AND owner_id NOT IN (?)", postpaid).Find(&openSessions).Error; err != nil {
+		logger.Error("WALLET: Failed to load running usage sessions", err, nil)
+		return
+	}
+
+	now := time.Now()
+	for i := range openSessions {
+		s.chargeSession(openSessions[i].ID, now)
+	}
+
+	// Closed sessions: settle the difference to the final session cost
+	var closedSessions []models.UsageSession
+	err := s.db.Where("stopped_at IS NOT NULL AND owner_id NOT IN (?)", postpaid).
+		Where("ABS(cost_eur - wallet_charged_eur) > ?", walletEpsilon).
+		Where("wallet_charged_until IS NOT NULL OR started_at >= ?", s.startedAt).
+		Find(&closedSessions).Error
+	if err != nil {
+		logger.Error("WALLET: Failed to load closed usage sessions", err, nil)
+		return
+	}
+
+	for i := range closedSessions {
+		s.chargeSession(closedSessions[i].ID, now)
+	}
+}
+
+// chargeSession deducts (or refunds, if negative) what a session owes and advances its progress
+// The session row stays locked until the ledger entry and the progress are committed together,
+// so API instances charging concurrently can't deduct the same session time twice.
+func (s *WalletService) chargeSession(sessionID string, now time.Time) {
+	var session models.UsageSession
+	var amount float64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", sessionID).First(&session).Error; err != nil {
+			return err
+		}
+
+		var until time.Time
+		var description string
+		amount, until, description = sessionCharge(&session, now)
+		if math.Abs(amount) < walletEpsilon {
+			return nil // Charged by another instance since it was loaded
+		}
+
+		entry := &models.WalletTransaction{
+			UserID:         session.OwnerID,
+			Type:           models.WalletTxUsage,
+			AmountEUR:      -amount,
+			ServerID:       session.ServerID,
+			UsageSessionID: session.ID,
+			Description:    fmt.Sprintf("%s: %s", description, session.ServerName),
+		}
+		if amount < 0 {
+			entry.Type = models.WalletTxRefund
+		}
+		if err := applyTransaction(tx, entry); err != nil {
+			return err
+		}
+
+		return tx.Model(&models.UsageSession{}).
+			Where("id = ?", session.ID).
+			Updates(map[string]interface{}{
+				"wallet_charged_eur":   session.WalletChargedEUR + amount,
+				"wallet_charged_until": until,
+			}).Error
+	})
+	if err != nil {
+		logger.Error("WALLET: Failed to charge usage", err, map[string]interface{}{
+			"session_id": sessionID,
+			"owner_id":   session.OwnerID,
+			"amount_eur": amount,
+		})
+	}
+}
+
+// sessionCharge returns the amount a session owes since its last deduction and the session time it covers
+// Running sessions owe their runtime up to now, closed sessions the difference to their final cost.
+func sessionCharge(session *models.UsageSession, now time.Time) (float64, time.Time, string) {
+	if session.StoppedAt != nil {
+		return session.CostEUR - session.WalletChargedEUR, *session.StoppedAt, "Server runtime (session closed)"
+	}
+
+	from := session.StartedAt
+	if session.WalletChargedUntil != nil {
+		from = *session.WalletChargedUntil
+	}
+	if !now.After(from) {
+		return 0, from, ""
+	}
+	return models.UsageCostEUR(session.RAMMb, session.HourlyRateEUR, now.Sub(from)), now, "Server runtime"
+}
+
+// MinimumStartBalance returns the credit needed to start a server
+func (s *WalletService) MinimumStartBalance(server *models.MinecraftServer) float64 {
+	if server.RAMTier == "" {
+		server.CalculateTier()
+	}
+	runtime := time.Duration(s.cfg.WalletMinStartMinutes) * time.Minute
+	return models.UsageCostEUR(server.RAMMb, server.GetHourlyRate(), runtime)
+}
+
+// CheckCanStart implements BillingGuardInterface (prepaid owners need enough credit to start)
+func (s *WalletService) CheckCanStart(server *models.MinecraftServer) error {
+	if !s.IsEnabled() || !s.IsPrepaid(server.OwnerID) {
+		return nil
+	}
+
+	balance, err := s.GetBalance(server.OwnerID)
+	if err != nil {
+		logger.Warn("WALLET: Could not check balance, allowing start", map[string]interface{}{
+			"owner_id": server.OwnerID,
+			"error":    err.Error(),
+		})
+		return nil
+	}
+
+	if required := s.MinimumStartBalance(server); balance <= 0 || balance < required {
+		return fmt.Errorf("%w (balance %.2f EUR, %d minutes of runtime require %.2f EUR)",
+			ErrInsufficientBalance, balance, s.cfg.WalletMinStartMinutes, required)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"database/sql/driver"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+// fakeWalletDB answers the queries of usage charging for one session and one user
+type fakeWalletDB struct {
+	mu          sync.Mutex
+	session     []driver.Value // usage_sessions row, see walletSessionColumns
+	userMissing bool
+	balance     float64
+	references  map[string]bool // reference/type of inserted ledger entries
+
+	chargedEUR   float64 // Last session progress update
+	chargedUntil time.Time
+	ledger       []float64 // Amounts of inserted ledger entries
+}
+
+var walletSessionColumns = []string{"id", "server_id", "server_name", "owner_id", "started_at", "stopped_at",
+	"ram_mb", "cost_eur", "hourly_rate_eur", "wallet_charged_eur", "wallet_charged_until"}
+
+func (f *fakeWalletDB) handle(query string, args []driver.NamedValue) fakeSQLResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.Contains(query, `FROM "usage_sessions"`):
+		return fakeSQLResult{Columns: walletSessionColumns, Rows: [][]driver.Value{f.session}}
+	case strings.HasPrefix(query, `UPDATE "users"`):
+		if f.userMissing {
+			return fakeSQLResult{Rows: [][]driver.Value{}}
+		}
+		f.balance += namedArg(args, 1).(float64)
+		return fakeSQLResult{RowsAffected: 1}
+	case strings.Contains(query, `FROM "users"`):
+		return fakeSQLResult{Columns: []string{"balance"}, Rows: [][]driver.Value{{f.balance}}}
+	case strings.HasPrefix(query, `INSERT INTO "wallet_transactions"`):
+		reference, _ := namedArg(args, 7).(string)
+		key := reference + "/" + namedArg(args, 2).(string)
+		if reference != "" && f.references[key] {
+			return fakeSQLResult{Err: fakePgError("23505")}
+		}
+		f.references[key] = true
+		f.ledger = append(f.ledger, namedArg(args, 3).(float64))
+		return fakeSQLResult{Columns: []string{"id"}, Rows: [][]driver.Value{{int64(len(f.ledger))}}}
+	case strings.Contains(query, `FROM "wallet_transactions"`):
+		return fakeSQLResult{Columns: []string{"id", "amount_eur"}, Rows: [][]driver.Value{{int64(1), 10.0}}}
+	case strings.HasPrefix(query, `UPDATE "usage_sessions"`):
+		f.chargedEUR = namedArg(args, 1).(float64)
+		f.chargedUntil = namedArg(args, 2).(time.Time)
+		return fakeSQLResult{RowsAffected: 1}
+	}
+	return fakeSQLResult{}
+}
+
+func TestWalletChargeSession(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	hourAgo := now.Add(-time.Hour)
+	stoppedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		session     []driver.Value
+		userMissing bool
+		wantLedger  []float64 // Ledger amounts (negative = debit)
+		wantCharged float64   // Session progress after the charge (unchanged if nothing is charged)
+		wantUntil   time.Time
+		wantCommit  bool
+	}{
+		{
+			name:        "running session since start",
+			session:     []driver.Value{"s1", "srv", "lobby", "user-1", now.Add(-2 * time.Hour), nil, int64(2048), 0.0, 0.5, 0.0, nil},
+			wantLedger:  []float64{-2.0}, // 2 GB * 2 h * 0.5
+			wantCharged: 2.0,
+			wantUntil:   now,
+			wantCommit:  true,
+		},
+		{
+			name:        "running session since last charge",
+			session:     []driver.Value{"s1", "srv", "lobby", "user-1", now.Add(-2 * time.Hour), nil, int64(1024), 0.0, 0.5, 0.5, hourAgo},
+			wantLedger:  []float64{-0.5},
+			wantCharged: 1.0,
+			wantUntil:   now,
+			wantCommit:  true,
+		},
+		{
+			name:       "already charged by another instance",
+			session:    []driver.Value{"s1", "srv", "lobby", "user-1", hourAgo, nil, int64(1024), 0.0, 0.5, 0.5, now},
+			wantCommit: true,
+		},
+		{
+			name:        "closed session settles the final cost",
+			session:     []driver.Value{"s1", "srv", "lobby", "user-1", hourAgo, stoppedAt, int64(1024), 0.75, 0.5, 0.5, hourAgo},
+			wantLedger:  []float64{-0.25},
+			wantCharged: 0.75,
+			wantUntil:   stoppedAt,
+			wantCommit:  true,
+		},
+		{
+			name:        "closed session refunds over-deduction",
+			session:     []driver.Value{"s1", "srv", "lobby", "user-1", hourAgo, stoppedAt, int64(1024), 0.4, 0.5, 0.5, now},
+			wantLedger:  []float64{0.1},
+			wantCharged: 0.4,
+			wantUntil:   stoppedAt,
+			wantCommit:  true,
+		},
+		{
+			name:        "missing owner rolls back",
+			session:     []driver.Value{"s1", "srv", "lobby", "user-1", hourAgo, nil, int64(1024), 0.0, 0.5, 0.0, nil},
+			userMissing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDB := &fakeWalletDB{session: tt.session, userMissing: tt.userMissing, references: map[string]bool{}}
+			db, fake := newFakeSQLDB(t, fakeDB.handle)
+			s := NewWalletService(db, &config.Config{WalletEnabled: true})
+
+			s.chargeSession("s1", now)
+
+			statements := fake.Statements()
+			if len(statements) < 2 || statements[0] != "BEGIN" || !strings.HasSuffix(statements[1], "FOR UPDATE") {
+				t.Fatalf("statements = %q, want the session row locked first", statements)
+			}
+			if got := fake.Count("COMMIT") == 1; got != tt.wantCommit {
+				t.Errorf("committed = %v, want %v (statements %q)", got, tt.wantCommit, statements)
+			}
+
+			if len(fakeDB.ledger) != len(tt.wantLedger) {
+				t.Fatalf("ledger = %v, want %v", fakeDB.ledger, tt.wantLedger)
+			}
+			for i, amount := range tt.wantLedger {
+				if math.Abs(fakeDB.ledger[i]-amount) > walletEpsilon {
+					t.Errorf("ledger[%d] = %.4f, want %.4f", i, fakeDB.ledger[i], amount)
+				}
+			}
+			if math.Abs(fakeDB.chargedEUR-tt.wantCharged) > walletEpsilon || !fakeDB.chargedUntil.Equal(tt.wantUntil) {
+				t.Errorf("session progress = %.4f until %v, want %.4f until %v",
+					fakeDB.chargedEUR, fakeDB.chargedUntil, tt.wantCharged, tt.wantUntil)
+			}
+			if tt.userMissing && fake.Count(`UPDATE "usage_sessions"`) != 0 {
+				t.Errorf("session progress was updated although the charge failed")
+			}
+		})
+	}
+}
+
+func TestWalletAddCreditDuplicateReference(t *testing.T) {
+	fakeDB := &fakeWalletDB{references: map[string]bool{"pi_1/purchase": true}}
+	lookups := 0
+	db, fake := newFakeSQLDB(t, func(query string, args []driver.NamedValue) fakeSQLResult {
+		// The pre-check misses the entry, as it does when a concurrent delivery inserts it in between
+		if strings.Contains(query, `FROM "wallet_transactions"`) {
+			if lookups++; lookups == 1 {
+				return fakeSQLResult{Columns: []string{"id"}, Rows: [][]driver.Value{}}
+			}
+		}
+		return fakeDB.handle(query, args)
+	})
+	s := NewWalletService(db, &config.Config{WalletEnabled: true})
+
+	entry, err := s.AddCredit("user-1", 10, models.WalletTxPurchase, "pi_1", "Credit purchase")
+	if err != nil {
+		t.Fatalf("AddCredit() error = %v, want the existing entry", err)
+	}
+	if entry.ID != 1 || entry.AmountEUR != 10 {
+		t.Errorf("AddCredit() = %+v, want the existing entry", entry)
+	}
+	if fake.Count("ROLLBACK") != 1 {
+		t.Errorf("statements = %q, want the duplicate credit rolled back", fake.Statements())
+	}
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/logger"
+	"golang.org/x/net/webdav"
+)
+
+// ErrWebDAVArchived is returned for servers whose files are in the archive
+var ErrWebDAVArchived = errors.New("server is archived, start it to restore its files")
+
+// WebDAVService serves server directories over WebDAV so they can be mounted as network drives
+// Permissions are checked per route like the file manager's (files.read for reading, files.write for
+// changes). Symlinks and the file manager's protected paths are off limits.
+type WebDAVService struct {
+	serverRepo *repository.ServerRepository
+	files      *ServerFileAccess
+	quota      DiskQuotaChecker // Optional, refuses uploads into full server directories
+
+	locksMu sync.Mutex
+	locks   map[string]webdav.LockSystem // Per server, lock paths are relative to the server directory
+}
+
+// NewWebDAVService creates a new WebDAV service
+func NewWebDAVService(serverRepo *repository.ServerRepository, files *ServerFileAccess) *WebDAVService {
+	return &WebDAVService{
+		serverRepo: serverRepo,
+		files:      files,
+		locks:      make(map[string]webdav.LockSystem),
+	}
+}
+
+// SetDiskQuota sets the disk quota checked before uploads
+func (s *WebDAVService) SetDiskQuota(quota DiskQuotaChecker) {
+	s.quota = quota
+}
+
+// ServeHTTP serves one WebDAV request for the server directory mounted at prefix
+func (s *WebDAVService) ServeHTTP(w http.ResponseWriter, r *http.Request, serverID, prefix string) error {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return models.ErrServerNotFound
+	}
+	if server.Status == models.StatusArchived {
+		return ErrWebDAVArchived
+	}
+	switch r.Method {
+	case http.MethodPut, "COPY", "MKCOL":
+		if err := checkDiskQuota(s.quota, serverID, max(r.ContentLength, 0)); err != nil {
+			return err
+		}
+	}
+	fs, err := s.files.open(server)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	handler := &webdav.Handler{
+		Prefix:     prefix,
+		FileSystem: &webdavFS{fs: fs, serverID: serverID, quota: s.quota},
+		LockSystem: s.lockSystem(serverID),
+		Logger: func(r *http.Request, err error) {
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				logger.Warn("WEBDAV: Request failed", map[string]interface{}{
+					"server_id": serverID,
+					"method":    r.Method,
+					"path":      r.URL.Path,
+					"error":     err.Error(),
+				})
+			}
+		},
+	}
+	handler.ServeHTTP(w, r)
+	return nil
+}
+
+func (s *WebDAVService) lockSystem(serverID string) webdav.LockSystem {
+	s.locksMu.Lock()
+	defer s.locksMu.Unlock()
+	ls, ok := s.locks[serverID]
+	if !ok {
+		ls = webdav.NewMemLS()
+		s.locks[serverID] = ls
+	}
+	return ls
+}
+
+// webdavFS is a server directory as webdav.FileSystem
+type webdavFS struct {
+	fs       sftpFS
+	serverID string
+	quota    DiskQuotaChecker // Optional, written bytes are added to the disk usage
+}
+
+func (w *webdavFS) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	if err := w.checkWritable(name); err != nil {
+		return err
+	}
+	return w.fs.Mkdir(name)
+}
+
+func (w *webdavFS) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		if err := w.checkWritable(name); err != nil {
+			return nil, err
+		}
+	} else if err := checkNoSymlinks(w.fs, name); err != nil {
+		return nil, os.ErrPermission
+	}
+
+	info, err := w.fs.Stat(name)
+	if err == nil && info.IsDir() {
+		if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+			return nil, os.ErrInvalid
+		}
+		return &webdavDir{fs: w.fs, name: name, info: info}, nil
+	}
+	file, err := w.fs.OpenFile(name, flag&^os.O_APPEND)
+	if err != nil {
+		return nil, err
+	}
+	opened := &webdavFile{file: file}
+	if w.quota != nil && flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		opened.onClose = func(size int64) {
+			if info != nil {
+				size -= info.Size()
+			}
+			w.quota.AddDiskUsage(w.serverID, size)
+		}
+	}
+	return opened, nil
+}
+
+func (w *webdavFS) RemoveAll(ctx context.Context, name string) error {
+	if err := w.checkWritable(name); err != nil {
+		return err
+	}
+	return removeAllSFTP(w.fs, path.Clean("/"+name))
+}
+
+func (w *webdavFS) Rename(ctx context.Context, oldName, newName string) error {
+	if err := w.checkWritable(oldName); err != nil {
+		return err
+	}
+	if err := w.checkWritable(newName); err != nil {
+		return err
+	}
+	return w.fs.Rename(oldName, newName)
+}
+
+func (w *webdavFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	if err := checkNoSymlinks(w.fs, name); err != nil {
+		return nil, os.ErrPermission
+	}
+	return w.fs.Stat(name)
+}
+
+// checkWritable refuses changes to protected paths and through symlinks
+func (w *webdavFS) checkWritable(name string) error {
+	if isProtectedPath(name) {
+		return os.ErrPermission
+	}
+	if err := checkNoSymlinks(w.fs, name); err != nil {
+		return os.ErrPermission
+	}
+	return nil
+}
+
+// removeAllSFTP deletes a file or a directory with its contents; symlinks are deleted, not followed
+func removeAllSFTP(fs sftpFS, name string) error {
+	info, err := fs.Lstat(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return fs.Remove(name)
+	}
+	entries, err := fs.ReadDir(name)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := removeAllSFTP(fs, path.Join(name, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return fs.RemoveDirectory(name)
+}
+
+// webdavFile adds a read/write position to an sftpFile
+type webdavFile struct {
+	file    sftpFile
+	offset  int64
+	onClose func(size int64) // Set for files opened for writing, gets the final size
+}
+
+func (f *webdavFile) Read(p []byte) (int, error) {
+	n, err := f.file.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (f *webdavFile) Write(p []byte) (int, error) {
+	n, err := f.file.WriteAt(p, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *webdavFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		info, err := f.file.Stat()
+		if err != nil {
+			return 0, err
+		}
+		offset += info.Size()
+	default:
+		return 0, os.ErrInvalid
+	}
+	if offset < 0 {
+		return 0, os.ErrInvalid
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *webdavFile) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, fmt.Errorf("not a directory")
+}
+
+func (f *webdavFile) Stat() (os.FileInfo, error) { return f.file.Stat() }
+
+func (f *webdavFile) Close() error {
+	if f.onClose != nil {
+		if info, err := f.file.Stat(); err == nil {
+			f.onClose(info.Size())
+		}
+	}
+	return f.file.Close()
+}
+
+// webdavDir is an opened directory; it is listed on the first Readdir
+type webdavDir struct {
+	fs      sftpFS
+	name    string
+	info    os.FileInfo
+	entries []os.FileInfo
+	listed  bool
+}
+
+func (d *webdavDir) Readdir(count int) ([]os.FileInfo, error) {
+	if !d.listed {
+		entries, err := d.fs.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries = entries
+		d.listed = true
+	}
+	if count <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(d.entries) {
+		count = len(d.entries)
+	}
+	entries := d.entries[:count]
+	d.entries = d.entries[count:]
+	return entries, nil
+}
+
+func (d *webdavDir) Read(p []byte) (int, error)                   { return 0, os.ErrInvalid }
+func (d *webdavDir) Write(p []byte) (int, error)                  { return 0, os.ErrInvalid }
+func (d *webdavDir) Seek(offset int64, whence int) (int64, error) { return 0, nil }
+func (d *webdavDir) Stat() (os.FileInfo, error)                   { return d.info, nil }
+func (d *webdavDir) Close() error                                 { return nil }
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/webdav"
+)
+
+func TestWebDAVFS(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	os.WriteFile(filepath.Join(root, "eula.txt"), []byte("eula=true"), 0644)
+	os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644)
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skip("symlinks not supported")
+	}
+
+	handler := &webdav.Handler{
+		Prefix:     "/dav/server-1",
+		FileSystem: &webdavFS{fs: &localSFTPFS{root: root}},
+		LockSystem: webdav.NewMemLS(),
+	}
+	do := func(method, target, body string) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, "/dav/server-1"+target, strings.NewReader(body)))
+		return recorder.Code
+	}
+
+	if code := do("MKCOL", "/plugins", ""); code != http.StatusCreated {
+		t.Errorf("MKCOL /plugins = %d, want 201", code)
+	}
+	if code := do("PUT", "/plugins/config.yml", "enabled: true"); code != http.StatusCreated {
+		t.Errorf("PUT /plugins/config.yml = %d, want 201", code)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "plugins", "config.yml")); string(data) != "enabled: true" {
+		t.Errorf("uploaded file = %q, want %q", data, "enabled: true")
+	}
+	if code := do("GET", "/plugins/config.yml", ""); code != http.StatusOK {
+		t.Errorf("GET /plugins/config.yml = %d, want 200", code)
+	}
+	if code := do("PROPFIND", "/", ""); code != http.StatusMultiStatus {
+		t.Errorf("PROPFIND / = %d, want 207", code)
+	}
+
+	// Protected paths and symlinks out of the server directory are refused
+	if code := do("PUT", "/eula.txt", "eula=false"); code == http.StatusCreated || code == http.StatusNoContent {
+		t.Errorf("PUT /eula.txt = %d, want a refusal", code)
+	}
+	if code := do("DELETE", "/eula.txt", ""); code == http.StatusNoContent {
+		t.Errorf("DELETE /eula.txt = %d, want a refusal", code)
+	}
+	if code := do("GET", "/escape/secret.txt", ""); code == http.StatusOK {
+		t.Errorf("GET /escape/secret.txt = %d, want a refusal", code)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "eula.txt")); string(data) != "eula=true" {
+		t.Errorf("eula.txt = %q, want it unchanged", data)
+	}
+
+	if code := do("DELETE", "/plugins", ""); code != http.StatusNoContent {
+		t.Errorf("DELETE /plugins = %d, want 204", code)
+	}
+	if _, err := os.Stat(filepath.Join(root, "plugins")); !os.IsNotExist(err) {
+		t.Errorf("plugins still exists after DELETE (err = %v)", err)
+	}
+}
@@ -0,0 +1,491 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/internal/events"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	webhookEndpointLimit         = 10 // Endpoints per account
+	webhookDeliveryPruneInterval = time.Hour
+	webhookPingEvent             = "webhook.ping" // Sent by PingEndpoint, not part of the catalog
+
+	// Headers of endpoint deliveries
+	webhookEventHeader     = "X-PayPerPlay-Event"
+	webhookDeliveryHeader  = "X-PayPerPlay-Delivery"
+	webhookSignatureHeader = "X-PayPerPlay-Signature" // "t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<body>">"
+)
+
+// WebhookEndpointInput is the configuration of a webhook endpoint set by its owner
+type WebhookEndpointInput struct {
+	URL         string
+	Description string
+	Events      []string // Catalog event types, "<category>.*" or "*"
+	ServerID    string   // Only events of this server (empty = all of the account's)
+	Enabled     *bool    // nil = unchanged (enabled on create)
+}
+
+// webhookEndpointPayload is the JSON body delivered to endpoints
+type webhookEndpointPayload struct {
+	ID        string                 `json:"id"` // Event ID, the same for redeliveries
+	Type      string                 `json:"type"`
+	CreatedAt time.Time              `json:"created_at"`
+	ServerID  string                 `json:"server_id,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// Start subscribes the webhook endpoints to the event catalog and prunes old deliveries
+// Deliveries interrupted by the last shutdown are marked failed (they can be redelivered).
+func (s *WebhookService) Start() {
+	if failed, err := s.endpoints.FailPendingDeliveries(); err != nil {
+		logger.Warn("WEBHOOKS: Failed to mark interrupted deliveries", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if failed > 0 {
+		logger.Info("WEBHOOKS: Marked deliveries interrupted by the last shutdown as failed", map[string]interface{}{
+			"deliveries": failed,
+		})
+	}
+
+	// Subscribe: delivered once cluster-wide, by the instance that published the event
+	bus := events.GetEventBus()
+	for _, entry := range events.Catalog {
+		bus.Subscribe(entry.Type, s.dispatchEndpointEvent)
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(webhookDeliveryPruneInterval)
+		defer ticker.Stop()
+
+		s.pruneDeliveries()
+		for {
+			select {
+			case <-ticker.C:
+				s.pruneDeliveries()
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts pruning (deliveries still in flight are failed on the next start)
+func (s *WebhookService) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// pruneDeliveries deletes deliveries older than WEBHOOK_DELIVERY_RETENTION_DAYS
+func (s *WebhookService) pruneDeliveries() {
+	if config.AppConfig == nil || config.AppConfig.WebhookDeliveryRetentionDays <= 0 {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -config.AppConfig.WebhookDeliveryRetentionDays)
+	if deleted, err := s.endpoints.DeleteDeliveriesBefore(cutoff); err != nil {
+		logger.Warn("WEBHOOKS: Failed to prune deliveries", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if deleted > 0 {
+		logger.Info("WEBHOOKS: Pruned old deliveries", map[string]interface{}{
+			"deleted": deleted,
+		})
+	}
+}
+
+// ListEndpoints returns the webhook endpoints of a user
+func (s *WebhookService) ListEndpoints(userID string) ([]models.WebhookEndpoint, error) {
+	return s.endpoints.FindByUser(userID)
+}
+
+// GetEndpoint returns a webhook endpoint of a user
+func (s *WebhookService) GetEndpoint(userID, endpointID string) (*models.WebhookEndpoint, error) {
+	return s.endpoints.FindForUser(endpointID, userID)
+}
+
+// CreateEndpoint creates a webhook endpoint and returns it with its signing secret (only returned here
+// and on rotation)
+func (s *WebhookService) CreateEndpoint(userID string, input WebhookEndpointInput) (*models.WebhookEndpoint, string, error) {
+	count, err := s.endpoints.CountByUser(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= webhookEndpointLimit {
+		return nil, "", models.ErrWebhookEndpointLimit
+	}
+
+	endpoint := &models.WebhookEndpoint{UserID: userID, Enabled: true}
+	if err := s.applyEndpointInput(endpoint, input); err != nil {
+		return nil, "", err
+	}
+	if endpoint.Secret, err = generateWebhookSecret(); err != nil {
+		return nil, "", err
+	}
+	if err := s.endpoints.Create(endpoint); err != nil {
+		return nil, "", err
+	}
+	return endpoint, endpoint.Secret, nil
+}
+
+// UpdateEndpoint replaces the configuration of a webhook endpoint (the secret stays)
+func (s *WebhookService) UpdateEndpoint(userID, endpointID string, input WebhookEndpointInput) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.endpoints.FindForUser(endpointID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyEndpointInput(endpoint, input); err != nil {
+		return nil, err
+	}
+	if err := s.endpoints.Update(endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint deletes a webhook endpoint with its deliveries
+func (s *WebhookService) DeleteEndpoint(userID, endpointID string) error {
+	if _, err := s.endpoints.FindForUser(endpointID, userID); err != nil {
+		return err
+	}
+	return s.endpoints.Delete(endpointID)
+}
+
+// RotateEndpointSecret replaces the signing secret of an endpoint and returns the new one
+// Deliveries are signed with the new secret right away.
+func (s *WebhookService) RotateEndpointSecret(userID, endpointID string) (*models.WebhookEndpoint, string, error) {
+	endpoint, err := s.endpoints.FindForUser(endpointID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if endpoint.Secret, err = generateWebhookSecret(); err != nil {
+		return nil, "", err
+	}
+	if err := s.endpoints.Update(endpoint); err != nil {
+		return nil, "", err
+	}
+	return endpoint, endpoint.Secret, nil
+}
+
+// ListDeliveries returns one page of the deliveries of an endpoint, newest first by default
+func (s *WebhookService) ListDeliveries(userID, endpointID string, page repository.PageRequest) (*repository.Page[models.WebhookDelivery], error) {
+	if _, err := s.endpoints.FindForUser(endpointID, userID); err != nil {
+		return nil, err
+	}
+	return s.endpoints.FindDeliveryPage(endpointID, page)
+}
+
+// GetDelivery returns a delivery of an endpoint with its attempts
+func (s *WebhookService) GetDelivery(userID, endpointID, deliveryID string) (*models.WebhookDelivery, error) {
+	if _, err := s.endpoints.FindForUser(endpointID, userID); err != nil {
+		return nil, err
+	}
+	return s.endpoints.FindDelivery(endpointID, deliveryID)
+}
+
+// Redeliver sends the payload of a delivery again as a new delivery (signed with the current secret)
+// Returns right away with the pending delivery; it is retried like a regular delivery.
+func (s *WebhookService) Redeliver(userID, endpointID, deliveryID string) (*models.WebhookDelivery, error) {
+	endpoint, err := s.endpoints.FindForUser(endpointID, userID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := s.endpoints.FindDelivery(endpointID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &models.WebhookDelivery{
+		EndpointID:   endpoint.ID,
+		EventID:      original.EventID,
+		EventType:    original.EventType,
+		Payload:      original.Payload,
+		Status:       models.WebhookDeliveryPending,
+		RedeliveryOf: original.ID,
+	}
+	if err := s.endpoints.CreateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	pending := *delivery // The returned delivery is not touched by the attempts
+	go s.deliver(*endpoint, &pending, webhookMaxAttempts)
+	return delivery, nil
+}
+
+// PingEndpoint sends a webhook.ping event to an endpoint and waits for the single attempt
+// (also sent to disabled endpoints, to check them before enabling)
+func (s *WebhookService) PingEndpoint(userID, endpointID string) (*models.WebhookDelivery, error) {
+	endpoint, err := s.endpoints.FindForUser(endpointID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.createDelivery(endpoint, events.Event{
+		ID:        "ping-" + strconv.FormatInt(time.Now().UnixNano(), 36),
+		Type:      webhookPingEvent,
+		Timestamp: time.Now(),
+		Data:      map[string]interface{}{"endpoint_id": endpoint.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.deliver(*endpoint, delivery, 1)
+	return delivery, nil
+}
+
+// applyEndpointInput validates input and sets it on endpoint
+func (s *WebhookService) applyEndpointInput(endpoint *models.WebhookEndpoint, input WebhookEndpointInput) error {
+	if err := validateWebhookEndpointURL(input.URL); err != nil {
+		return err
+	}
+	eventPatterns, err := normalizeWebhookEvents(input.Events)
+	if err != nil {
+		return err
+	}
+	if len(input.Description) > 255 {
+		return fmt.Errorf("%w: description is longer than 255 characters", models.ErrWebhookEndpointInvalid)
+	}
+	if input.ServerID != "" {
+		server, err := s.servers.FindByID(input.ServerID)
+		if err != nil || server.OwnerID != endpoint.UserID {
+			return fmt.Errorf("%w: server not found", models.ErrWebhookEndpointInvalid)
+		}
+	}
+
+	endpoint.URL = input.URL
+	endpoint.Description = input.Description
+	endpoint.Events = eventPatterns
+	endpoint.ServerID = input.ServerID
+	if input.Enabled != nil {
+		endpoint.Enabled = *input.Enabled
+	}
+	return nil
+}
+
+// dispatchEndpointEvent delivers a catalog event to the matching endpoints of the account it belongs
+// to: the owner of the event's server, or the user of account events
+func (s *WebhookService) dispatchEndpointEvent(event events.Event) {
+	ownerID := event.UserID
+	if event.ServerID != "" {
+		// The owner, not the member who e.g. started a shared server (deleted servers keep the event's user)
+		if server, err := s.servers.FindByID(event.ServerID); err == nil {
+			ownerID = server.OwnerID
+		}
+	}
+	if ownerID == "" {
+		return
+	}
+
+	endpoints, err := s.endpoints.FindEnabledByUser(ownerID)
+	if err != nil {
+		logger.Error("WEBHOOKS: Failed to load endpoints", err, map[string]interface{}{
+			"user_id":    ownerID,
+			"event_type": event.Type,
+		})
+		return
+	}
+
+	for i := range endpoints {
+		endpoint := endpoints[i]
+		if !webhookEndpointWants(&endpoint, event) {
+			continue
+		}
+		delivery, err := s.createDelivery(&endpoint, event)
+		if err != nil {
+			logger.Error("WEBHOOKS: Failed to create delivery", err, map[string]interface{}{
+				"endpoint_id": endpoint.ID,
+				"event_id":    event.ID,
+			})
+			continue
+		}
+		go s.deliver(endpoint, delivery, webhookMaxAttempts)
+	}
+}
+
+// createDelivery stores a pending delivery of event to endpoint
+func (s *WebhookService) createDelivery(endpoint *models.WebhookEndpoint, event events.Event) (*models.WebhookDelivery, error) {
+	body, err := json.Marshal(webhookEndpointPayload{
+		ID:        event.ID,
+		Type:      string(event.Type),
+		CreatedAt: event.Timestamp,
+		ServerID:  event.ServerID,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	delivery := &models.WebhookDelivery{
+		EndpointID: endpoint.ID,
+		EventID:    event.ID,
+		EventType:  string(event.Type),
+		Payload:    string(body),
+		Status:     models.WebhookDeliveryPending,
+	}
+	if err := s.endpoints.CreateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// deliver posts a delivery to its endpoint, retrying transient failures with exponential backoff
+// Every attempt is recorded on the delivery; each one is signed with a fresh timestamp.
+func (s *WebhookService) deliver(endpoint models.WebhookEndpoint, delivery *models.WebhookDelivery, attempts int) {
+	body := []byte(delivery.Payload)
+	delay := webhookRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		header := http.Header{}
+		header.Set("User-Agent", "PayPerPlay-Webhooks/1.0")
+		header.Set(webhookEventHeader, delivery.EventType)
+		header.Set(webhookDeliveryHeader, delivery.ID)
+		header.Set(webhookSignatureHeader, signWebhookPayload(endpoint.Secret, start.Unix(), body))
+
+		status, retryable, retryAfter, err := s.postWebhook(endpoint.URL, body, header)
+		record := models.WebhookDeliveryAttempt{
+			At:         start,
+			StatusCode: status,
+			DurationMS: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			record.Error = err.Error()
+		}
+		delivery.Attempts = append(delivery.Attempts, record)
+
+		done := err == nil || !retryable || attempt >= attempts
+		if err == nil {
+			now := time.Now()
+			delivery.Status = models.WebhookDeliverySucceeded
+			delivery.DeliveredAt = &now
+		} else if done {
+			delivery.Status = models.WebhookDeliveryFailed
+		}
+		if updateErr := s.endpoints.UpdateDelivery(delivery); updateErr != nil {
+			logger.Error("WEBHOOKS: Failed to record delivery attempt", updateErr, map[string]interface{}{
+				"delivery_id": delivery.ID,
+			})
+		}
+		if done {
+			if err != nil {
+				logger.Warn("WEBHOOKS: Delivery failed", map[string]interface{}{
+					"endpoint_id": endpoint.ID,
+					"delivery_id": delivery.ID,
+					"event_type":  delivery.EventType,
+					"attempts":    attempt,
+					"error":       err.Error(),
+				})
+			}
+			return
+		}
+
+		time.Sleep(webhookRetryWait(delay, retryAfter))
+		delay *= 2
+	}
+}
+
+// signWebhookPayload returns the signature header of a delivery: "t=<timestamp>,v1=<signature>" with
+// the hex HMAC-SHA256 of "<timestamp>.<body>". Receivers recompute it with the endpoint secret and
+// should reject old timestamps (replays).
+func signWebhookPayload(secret string, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateWebhookSecret returns a new endpoint signing secret
+func generateWebhookSecret() (string, error) {
+	token, err := randomHexToken(32)
+	if err != nil {
+		return "", err
+	}
+	return "whsec_" + token, nil
+}
+
+// webhookEndpointWants returns true if endpoint subscribed to the event (and its server, if limited to one)
+func webhookEndpointWants(endpoint *models.WebhookEndpoint, event events.Event) bool {
+	if endpoint.ServerID != "" && endpoint.ServerID != event.ServerID {
+		return false
+	}
+	for _, pattern := range endpoint.Events {
+		if webhookEventMatches(pattern, string(event.Type)) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookEventMatches returns true if an event type matches a subscription: the type itself,
+// "<category>.*" or "*"
+func webhookEventMatches(pattern, eventType string) bool {
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	category, ok := strings.CutSuffix(pattern, ".*")
+	return ok && strings.HasPrefix(eventType, category+".")
+}
+
+// normalizeWebhookEvents validates the subscriptions of an endpoint against the catalog, trimmed and
+// without duplicates
+func normalizeWebhookEvents(patterns []string) ([]string, error) {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if seen[pattern] {
+			continue
+		}
+		category, wildcard := strings.CutSuffix(pattern, ".*")
+		valid := pattern == "*" ||
+			(wildcard && events.IsCatalogCategory(category)) ||
+			events.CatalogCategory(events.EventType(pattern)) != ""
+		if !valid {
+			return nil, fmt.Errorf("%w: unknown event %q (see GET /api/webhooks/events)", models.ErrWebhookEndpointInvalid, pattern)
+		}
+		seen[pattern] = true
+		normalized = append(normalized, pattern)
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("%w: subscribe to at least one event", models.ErrWebhookEndpointInvalid)
+	}
+	return normalized, nil
+}
+
+// validateWebhookEndpointURL accepts https URLs that don't point at local or private addresses
+// (hostnames are not resolved here)
+func validateWebhookEndpointURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" || parsed.User != nil {
+		return fmt.Errorf("%w: url must be an https URL", models.ErrWebhookEndpointInvalid)
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return fmt.Errorf("%w: url must not point at a local address", models.ErrWebhookEndpointInvalid)
+	}
+	if ip := net.ParseIP(host); ip != nil &&
+		(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified()) {
+		return fmt.Errorf("%w: url must not point at a local address", models.ErrWebhookEndpointInvalid)
+	}
+	return nil
+}
@@ -2,9 +2,12 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/payperplay/hosting/internal/models"
@@ -13,10 +16,24 @@ import (
 	"gorm.io/gorm"
 )
 
-// WebhookService handles Discord webhook operations
+// Webhook delivery retries (exponential backoff: 1s, 2s, 4s, ...)
+const (
+	webhookMaxAttempts    = 4
+	webhookRetryBaseDelay = time.Second
+	webhookMaxRetryDelay  = 30 * time.Second
+)
+
+// WebhookService handles Discord and Slack webhook notifications of server events, and the signed
+// JSON deliveries of the event catalog to the accounts' webhook endpoints (webhook_endpoints.go)
 type WebhookService struct {
 	db         *gorm.DB
 	httpClient *http.Client
+	endpoints  *repository.WebhookEndpointRepository
+	servers    *repository.ServerRepository
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
 // NewWebhookService creates a new webhook service
@@ -26,6 +43,8 @@ func NewWebhookService(db *gorm.DB) *WebhookService {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		endpoints: repository.NewWebhookEndpointRepository(db),
+		servers:   repository.NewServerRepository(db),
 	}
 }
 
@@ -41,8 +60,8 @@ func (s *WebhookService) GetWebhook(serverID string) (*models.ServerWebhook, err
 	return &webhook, nil
 }
 
-// CreateWebhook creates a new webhook configuration
-func (s *WebhookService) CreateWebhook(serverID string, webhookURL string) (*models.ServerWebhook, error) {
+// CreateWebhook creates a new webhook configuration (provider "" = detect from the URL)
+func (s *WebhookService) CreateWebhook(serverID string, webhookURL string, provider models.WebhookProvider) (*models.ServerWebhook, error) {
 	// Check if webhook already exists
 	existing, err := s.GetWebhook(serverID)
 	if err != nil {
@@ -52,16 +71,28 @@ func (s *WebhookService) CreateWebhook(serverID string, webhookURL string) (*mod
 		return nil, fmt.Errorf("webhook already exists for this server")
 	}
 
+	if provider == "" {
+		provider = models.DetectWebhookProvider(webhookURL)
+	}
+
 	webhook := &models.ServerWebhook{
-		ServerID:        serverID,
-		WebhookURL:      webhookURL,
-		Enabled:         true,
-		OnServerStart:   true,
-		OnServerStop:    true,
-		OnServerCrash:   true,
-		OnPlayerJoin:    true,
-		OnPlayerLeave:   true,
-		OnBackupCreated: false,
+		ServerID:             serverID,
+		WebhookURL:           webhookURL,
+		Provider:             provider,
+		Enabled:              true,
+		OnServerStart:        true,
+		OnServerStop:         true,
+		OnServerCrash:        true,
+		OnServerRecovered:    true,
+		OnPlayerJoin:         true,
+		OnPlayerLeave:        true,
+		OnBackupCreated:      false,
+		OnBackupFailed:       true,
+		OnMigrationCompleted: true,
+		OnBudgetWarning:      true,
+		OnPregenCompleted:    true,
+		OnDiskQuotaWarning:   true,
+		OnLowTPS:             true,
 	}
 
 	if err := s.db.Create(webhook).Error; err != nil {
@@ -93,24 +124,17 @@ func (s *WebhookService) DeleteWebhook(serverID string) error {
 	return s.db.Where("server_id = ?", serverID).Delete(&models.ServerWebhook{}).Error
 }
 
-// TestWebhook sends a test message to the webhook URL
-func (s *WebhookService) TestWebhook(webhookURL string, serverName string) error {
-	payload := models.DiscordWebhookPayload{
-		Username: "PayPerPlay",
-		Embeds: []models.DiscordEmbed{
-			{
-				Title:       "🔔 Test Webhook",
-				Description: fmt.Sprintf("Webhook test for server **%s**", serverName),
-				Color:       3447003, // Blue
-				Footer: &models.DiscordEmbedFooter{
-					Text: "PayPerPlay Hosting",
-				},
-				Timestamp: time.Now().Format(time.RFC3339),
-			},
-		},
-	}
+// TestWebhook sends a test message to the webhook in its provider's format
+// Delivered once without retries, so the caller sees problems with the URL right away.
+func (s *WebhookService) TestWebhook(webhook *models.ServerWebhook, serverName string) error {
+	payload := s.buildPayload(webhook.Provider, webhookMessage{
+		title:       "🔔 Test Webhook",
+		description: fmt.Sprintf("Webhook test for server **%s**", serverName),
+		color:       3447003, // Blue
+		timestamp:   time.Now(),
+	})
 
-	return s.sendWebhook(webhookURL, payload)
+	return s.sendWebhook(webhook.WebhookURL, payload, 1)
 }
 
 // SendEvent sends a server event to Discord webhook
@@ -133,18 +157,13 @@ func (s *WebhookService) SendEvent(data models.WebhookEventData) error {
 		return nil // Event type disabled
 	}
 
-	// Build Discord embed
-	embed := s.buildEmbed(data)
-	payload := models.DiscordWebhookPayload{
-		Username: "PayPerPlay",
-		Embeds:   []models.DiscordEmbed{embed},
-	}
-
-	// Send webhook
-	if err := s.sendWebhook(webhook.WebhookURL, payload); err != nil {
+	// Format for the webhook's provider and send (transient failures are retried)
+	payload := s.buildPayload(webhook.Provider, s.buildMessage(data))
+	if err := s.sendWebhook(webhook.WebhookURL, payload, webhookMaxAttempts); err != nil {
 		logger.Error("Failed to send webhook", err, map[string]interface{}{
 			"server_id":  data.ServerID,
 			"event_type": data.EventType,
+			"provider":   webhook.Provider,
 		})
 		return err
 	}
@@ -152,6 +171,7 @@ func (s *WebhookService) SendEvent(data models.WebhookEventData) error {
 	logger.Info("Webhook sent", map[string]interface{}{
 		"server_id":  data.ServerID,
 		"event_type": data.EventType,
+		"provider":   webhook.Provider,
 	})
 
 	return nil
@@ -166,19 +186,41 @@ func (s *WebhookService) isEventEnabled(webhook *models.ServerWebhook, eventType
 		return webhook.OnServerStop
 	case models.WebhookEventServerCrash:
 		return webhook.OnServerCrash
+	case models.WebhookEventServerRecovered:
+		return webhook.OnServerRecovered
 	case models.WebhookEventPlayerJoin:
 		return webhook.OnPlayerJoin
 	case models.WebhookEventPlayerLeave:
 		return webhook.OnPlayerLeave
 	case models.WebhookEventBackupCreated:
 		return webhook.OnBackupCreated
+	case models.WebhookEventBackupFailed:
+		return webhook.OnBackupFailed
+	case models.WebhookEventMigrationCompleted:
+		return webhook.OnMigrationCompleted
+	case models.WebhookEventBudgetWarning:
+		return webhook.OnBudgetWarning
+	case models.WebhookEventPregenCompleted:
+		return webhook.OnPregenCompleted
+	case models.WebhookEventDiskQuotaWarning:
+		return webhook.OnDiskQuotaWarning
+	case models.WebhookEventLowTPS:
+		return webhook.OnLowTPS
 	default:
 		return false
 	}
 }
 
-// buildEmbed creates a Discord embed for an event
-func (s *WebhookService) buildEmbed(data models.WebhookEventData) models.DiscordEmbed {
+// webhookMessage is a provider-neutral notification (description uses Discord markdown)
+type webhookMessage struct {
+	title       string
+	description string
+	color       int
+	timestamp   time.Time
+}
+
+// buildMessage creates the notification text for an event
+func (s *WebhookService) buildMessage(data models.WebhookEventData) webhookMessage {
 	var title, description string
 	var color int
 
@@ -198,6 +240,13 @@ func (s *WebhookService) buildEmbed(data models.WebhookEventData) models.Discord
 			description += fmt.Sprintf("\n\n**Error:** %s", data.Message)
 		}
 		color = 15105570 // Dark Red
+	case models.WebhookEventServerRecovered:
+		title = "✅ Server Back Online"
+		description = fmt.Sprintf("Server **%s** recovered from a crash and is back online!", data.ServerName)
+		if data.Message != "" {
+			description += fmt.Sprintf("\n\n**Players online before the crash:** %s", data.Message)
+		}
+		color = 3066993 // Green
 	case models.WebhookEventPlayerJoin:
 		title = "👋 Player Joined"
 		description = fmt.Sprintf("**%s** joined **%s**", data.PlayerName, data.ServerName)
@@ -213,48 +262,172 @@ func (s *WebhookService) buildEmbed(data models.WebhookEventData) models.Discord
 			description += fmt.Sprintf("\n\n**Size:** %s", data.Message)
 		}
 		color = 3447003 // Blue
+	case models.WebhookEventBackupFailed:
+		title = "❌ Backup Failed"
+		description = fmt.Sprintf("Backup of **%s** failed.", data.ServerName)
+		if data.Message != "" {
+			description += fmt.Sprintf("\n\n**Error:** %s", data.Message)
+		}
+		color = 15158332 // Red
+	case models.WebhookEventMigrationCompleted:
+		title = "🚚 Migration Completed"
+		description = fmt.Sprintf("Server **%s** was moved to a new node.", data.ServerName)
+		if data.Message != "" {
+			description += fmt.Sprintf("\n\n**Route:** %s", data.Message)
+		}
+		color = 3066993 // Green
+	case models.WebhookEventBudgetWarning:
+		if data.Action == "auto_stop" {
+			title = "⛔ Budget Cap Reached"
+			description = fmt.Sprintf("The monthly budget cap covering **%s** was reached, running servers were stopped.", data.ServerName)
+			color = 15158332 // Red
+		} else {
+			title = "💸 Budget Warning"
+			description = fmt.Sprintf("The monthly budget covering **%s** is running out.", data.ServerName)
+			color = 15844367 // Gold
+		}
+		if data.Message != "" {
+			description += fmt.Sprintf("\n\n**Spend:** %s", data.Message)
+		}
+	case models.WebhookEventPregenCompleted:
+		title = "🗺️ World Pre-generated"
+		description = fmt.Sprintf("Chunk pre-generation of **%s** finished.", data.ServerName)
+		if data.Message != "" {
+			description += fmt.Sprintf("\n\n**Area:** %s", data.Message)
+		}
+		color = 3066993 // Green
+	case models.WebhookEventDiskQuotaWarning:
+		title = "💾 Disk Quota Warning"
+		description = fmt.Sprintf("The files of **%s** are using %s of the disk quota.", data.ServerName, data.Message)
+		description += "\n\nUploads, world imports and pre-generation are refused once the quota is reached. Delete old files or ask for a larger quota."
+		color = 15844367 // Gold
+	case models.WebhookEventLowTPS:
+		title = "🐢 Low TPS"
+		description = fmt.Sprintf("**%s** is lagging: %s.", data.ServerName, data.Message)
+		description += "\n\nCheck the performance graphs; a spark profile shows which plugins or chunks take the tick time."
+		color = 15105570 // Orange
 	default:
 		title = "📢 Server Event"
 		description = fmt.Sprintf("Event on server **%s**", data.ServerName)
 		color = 3447003 // Blue
 	}
 
-	return models.DiscordEmbed{
-		Title:       title,
-		Description: description,
-		Color:       color,
-		Footer: &models.DiscordEmbedFooter{
-			Text: "PayPerPlay Hosting",
+	return webhookMessage{
+		title:       title,
+		description: description,
+		color:       color,
+		timestamp:   data.Timestamp,
+	}
+}
+
+// buildPayload formats a message for the webhook's provider (Discord embed or Slack attachment)
+func (s *WebhookService) buildPayload(provider models.WebhookProvider, msg webhookMessage) interface{} {
+	if provider == models.WebhookProviderSlack {
+		text := strings.ReplaceAll(msg.description, "**", "*") // Slack mrkdwn bold
+		return models.SlackWebhookPayload{
+			Text: msg.title,
+			Attachments: []models.SlackAttachment{
+				{
+					Color:  fmt.Sprintf("#%06x", msg.color),
+					Title:  msg.title,
+					Text:   text,
+					Footer: "PayPerPlay Hosting",
+					Ts:     msg.timestamp.Unix(),
+				},
+			},
+		}
+	}
+
+	return models.DiscordWebhookPayload{
+		Username: "PayPerPlay",
+		Embeds: []models.DiscordEmbed{
+			{
+				Title:       msg.title,
+				Description: msg.description,
+				Color:       msg.color,
+				Footer: &models.DiscordEmbedFooter{
+					Text: "PayPerPlay Hosting",
+				},
+				Timestamp: msg.timestamp.Format(time.RFC3339),
+			},
 		},
-		Timestamp: data.Timestamp.Format(time.RFC3339),
 	}
 }
 
-// sendWebhook sends a webhook payload to Discord
-func (s *WebhookService) sendWebhook(webhookURL string, payload models.DiscordWebhookPayload) error {
+// sendWebhook posts a payload to a webhook URL, retrying transient failures with exponential backoff
+// Network errors, 429 (honoring Retry-After) and 5xx responses are retried until attempts are used up.
+func (s *WebhookService) sendWebhook(webhookURL string, payload interface{}, attempts int) error {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonData))
+	delay := webhookRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		_, retryable, retryAfter, err := s.postWebhook(webhookURL, jsonData, nil)
+		if err == nil || !retryable || attempt >= attempts {
+			return err
+		}
+
+		wait := webhookRetryWait(delay, retryAfter)
+		logger.Warn("Webhook delivery failed, retrying", map[string]interface{}{
+			"attempt":  attempt,
+			"retry_in": wait.String(),
+			"error":    err.Error(),
+		})
+		time.Sleep(wait)
+		delay *= 2
+	}
+}
+
+// webhookRetryWait returns the wait before the next attempt: the backoff delay or the receiver's
+// Retry-After if longer, at most webhookMaxRetryDelay
+func webhookRetryWait(delay, retryAfter time.Duration) time.Duration {
+	wait := delay
+	if retryAfter > wait {
+		wait = retryAfter
+	}
+	if wait > webhookMaxRetryDelay {
+		wait = webhookMaxRetryDelay
+	}
+	return wait
+}
+
+// postWebhook makes one delivery attempt with extra headers (may be nil); status is 0 without a response
+// retryable is true for failures worth retrying.
+func (s *WebhookService) postWebhook(webhookURL string, body []byte, header http.Header) (status int, retryable bool, retryAfter time.Duration, err error) {
+	req, err := http.NewRequest("POST", webhookURL, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, false, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	for name, values := range header {
+		req.Header[name] = values
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send webhook: %w", err)
+		return 0, true, 0, fmt.Errorf("failed to send webhook: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, 0, nil
 	}
 
-	return nil
+	err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if seconds, parseErr := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); parseErr == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds * float64(time.Second))
+		}
+		return resp.StatusCode, true, retryAfter, err
+	case resp.StatusCode >= 500:
+		return resp.StatusCode, true, 0, err
+	default:
+		return resp.StatusCode, false, 0, err // Bad URL or payload: retrying won't help
+	}
 }
 
 // NotifyServerStart sends a server start notification
@@ -288,6 +461,17 @@ func (s *WebhookService) NotifyServerCrash(serverID string, serverName string, e
 	})
 }
 
+// NotifyServerRecovered sends a notification that a crashed server is back, mentioning the players that were online
+func (s *WebhookService) NotifyServerRecovered(serverID string, serverName string, players []string) {
+	go s.SendEvent(models.WebhookEventData{
+		ServerID:   serverID,
+		ServerName: serverName,
+		EventType:  models.WebhookEventServerRecovered,
+		Message:    strings.Join(players, ", "),
+		Timestamp:  time.Now(),
+	})
+}
+
 // NotifyPlayerJoin sends a player join notification
 func (s *WebhookService) NotifyPlayerJoin(serverID string, serverName string, playerName string) {
 	go s.SendEvent(models.WebhookEventData{
@@ -321,6 +505,99 @@ func (s *WebhookService) NotifyBackupCreated(serverID string, serverName string,
 	})
 }
 
+// NotifyBackupFailed sends a backup failure notification
+func (s *WebhookService) NotifyBackupFailed(serverID string, serverName string, errorMsg string) {
+	go s.SendEvent(models.WebhookEventData{
+		ServerID:   serverID,
+		ServerName: serverName,
+		EventType:  models.WebhookEventBackupFailed,
+		Message:    errorMsg,
+		Timestamp:  time.Now(),
+	})
+}
+
+// NotifyPregenCompleted sends a notification that the chunk pre-generation of a server finished
+func (s *WebhookService) NotifyPregenCompleted(serverID string, serverName string, area string) {
+	go s.SendEvent(models.WebhookEventData{
+		ServerID:   serverID,
+		ServerName: serverName,
+		EventType:  models.WebhookEventPregenCompleted,
+		Message:    area,
+		Timestamp:  time.Now(),
+	})
+}
+
+// NotifyDiskQuotaWarning sends a notification that a server directory crossed a threshold of its disk quota
+func (s *WebhookService) NotifyDiskQuotaWarning(serverID string, serverName string, usage string) {
+	go s.SendEvent(models.WebhookEventData{
+		ServerID:   serverID,
+		ServerName: serverName,
+		EventType:  models.WebhookEventDiskQuotaWarning,
+		Message:    usage,
+		Timestamp:  time.Now(),
+	})
+}
+
+// NotifyLowTPS sends a notification that a server's TPS stayed below the alert threshold
+func (s *WebhookService) NotifyLowTPS(serverID string, serverName string, tickRate string) {
+	go s.SendEvent(models.WebhookEventData{
+		ServerID:   serverID,
+		ServerName: serverName,
+		EventType:  models.WebhookEventLowTPS,
+		Message:    tickRate,
+		Timestamp:  time.Now(),
+	})
+}
+
+// NotifyMigrationCompleted sends a notification that a server was migrated to another node
+func (s *WebhookService) NotifyMigrationCompleted(serverID string, serverName string, fromNode string, toNode string) {
+	go s.SendEvent(models.WebhookEventData{
+		ServerID:   serverID,
+		ServerName: serverName,
+		EventType:  models.WebhookEventMigrationCompleted,
+		Message:    fmt.Sprintf("%s → %s", fromNode, toNode),
+		Timestamp:  time.Now(),
+	})
+}
+
+// NotifyBudgetWarning sends a budget alert to the webhook of the capped server, or for
+// account-wide caps (serverID "") to the webhooks of all servers of the owner
+func (s *WebhookService) NotifyBudgetWarning(ownerID, serverID string, percent int, accruedEUR, limitEUR float64, action string) {
+	go func() {
+		var servers []models.MinecraftServer
+		query := s.db.Model(&models.MinecraftServer{}).
+			Joins("JOIN server_webhooks ON server_webhooks.server_id = minecraft_servers.id").
+			Where("server_webhooks.enabled = ? AND server_webhooks.on_budget_warning = ?", true, true)
+		if serverID != "" {
+			query = query.Where("minecraft_servers.id = ?", serverID)
+		} else {
+			query = query.Where("minecraft_servers.owner_id = ?", ownerID)
+		}
+		if err := query.Find(&servers).Error; err != nil {
+			logger.Error("Failed to find webhooks for budget alert", err, map[string]interface{}{
+				"owner_id":  ownerID,
+				"server_id": serverID,
+			})
+			return
+		}
+
+		message := fmt.Sprintf("%d%% (%.2f € of %.2f €)", percent, accruedEUR, limitEUR)
+		if serverID == "" {
+			message += ", account-wide cap"
+		}
+		for _, server := range servers {
+			s.SendEvent(models.WebhookEventData{
+				ServerID:   server.ID,
+				ServerName: server.Name,
+				EventType:  models.WebhookEventBudgetWarning,
+				Action:     action,
+				Message:    message,
+				Timestamp:  time.Now(),
+			})
+		}
+	}()
+}
+
 // GetWebhookRepository returns a webhook repository
 func GetWebhookRepository() *WebhookRepository {
 	return &WebhookRepository{db: repository.GetDB()}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// WorldExportPruneWorker periodically deletes the archives of expired world exports
+type WorldExportPruneWorker struct {
+	worldService  *WorldService
+	pruneInterval time.Duration // How often to run pruning (default: 1h)
+	running       bool
+	ctx           context.Context
+	cancel        context.CancelFunc
+	pruneMutex    sync.Mutex // Prevents concurrent prune runs
+}
+
+// NewWorldExportPruneWorker creates a new world export prune worker
+func NewWorldExportPruneWorker(worldService *WorldService) *WorldExportPruneWorker {
+	return &WorldExportPruneWorker{
+		worldService:  worldService,
+		pruneInterval: time.Hour,
+		running:       false,
+	}
+}
+
+// Start fails exports interrupted by the last shutdown and begins the prune worker
+func (w *WorldExportPruneWorker) Start() {
+	if w.running {
+		logger.Warn("WORLD-EXPORT-PRUNE: Worker already running", nil)
+		return
+	}
+
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	w.running = true
+
+	logger.Info("WORLD-EXPORT-PRUNE: Starting prune worker", map[string]interface{}{
+		"prune_interval": w.pruneInterval,
+	})
+
+	w.worldService.RecoverExports()
+
+	go func() {
+		ticker := time.NewTicker(w.pruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.runPrune()
+			case <-w.ctx.Done():
+				logger.Info("WORLD-EXPORT-PRUNE: Worker stopped", nil)
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the prune worker
+func (w *WorldExportPruneWorker) Stop() {
+	if !w.running {
+		return
+	}
+
+	logger.Info("WORLD-EXPORT-PRUNE: Stopping prune worker", nil)
+	w.cancel()
+	w.running = false
+}
+
+// runPrune performs one prune pass
+func (w *WorldExportPruneWorker) runPrune() {
+	if !w.pruneMutex.TryLock() {
+		logger.Warn("WORLD-EXPORT-PRUNE: Prune already in progress, skipping this cycle", nil)
+		return
+	}
+	defer w.pruneMutex.Unlock()
+
+	w.worldService.PruneExports()
+}
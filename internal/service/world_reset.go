@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// World reset errors
+var (
+	ErrWorldResetNotConfirmed = errors.New("confirm must be the name of the server")
+	ErrWorldResetUnavailable  = errors.New("world reset is not enabled")
+	ErrWorldResetBusy         = errors.New("the server must be running or stopped to reset its world")
+	ErrWorldResetDimension    = errors.New("unknown dimension (must be overworld, nether or end)")
+	ErrWorldResetSeedConflict = errors.New("seed and random_seed cannot be combined")
+	ErrWorldResetSeedPartial  = errors.New("a new seed regenerates the whole world, reset all dimensions")
+)
+
+// World dimensions that can be reset
+const (
+	DimensionOverworld = "overworld"
+	DimensionNether    = "nether"
+	DimensionEnd       = "end"
+)
+
+// dimensionFolders are the folders holding the chunks of a dimension, relative to the server directory
+// Paper and Spigot keep the nether and end in separate worlds, vanilla keeps them inside "world".
+var dimensionFolders = map[string][]string{
+	DimensionOverworld: {"world"},
+	DimensionNether:    {filepath.Join("world_nether", "DIM-1"), filepath.Join("world", "DIM-1")},
+	DimensionEnd:       {filepath.Join("world_the_end", "DIM1"), filepath.Join("world", "DIM1")},
+}
+
+// chunkFolders are the per-dimension folders regenerated by a reset (terrain, entities, points of interest)
+var chunkFolders = []string{"region", "entities", "poi"}
+
+// WorldResetRequest describes a world reset
+type WorldResetRequest struct {
+	Confirm    string   `json:"confirm" binding:"required"` // Name of the server, guards against resetting the wrong server
+	Dimensions []string `json:"dimensions"`                 // overworld, nether, end; empty = all
+	Seed       string   `json:"seed"`                       // New level seed; empty keeps the current seed
+	RandomSeed bool     `json:"random_seed"`                // Generate a new random seed
+}
+
+// worldReset is a validated WorldResetRequest
+type worldReset struct {
+	dimensions []string
+	newSeed    bool
+	seed       string
+}
+
+// SetMinecraftService sets the service used to stop and restart servers during a world reset
+func (s *WorldService) SetMinecraftService(mcService *MinecraftService) {
+	s.mcService = mcService
+}
+
+// SetConfigService sets the service that records seed changes of a world reset
+func (s *WorldService) SetConfigService(configService *ConfigService) {
+	s.configService = configService
+}
+
+// SetOperationLimiter sets the per-owner limiter that runs and tracks world resets
+func (s *WorldService) SetOperationLimiter(opLimiter *OperationLimiter) {
+	s.opLimiter = opLimiter
+}
+
+// StartWorldReset regenerates the world of a server in the background
+// The returned operation (nil without a limiter) reports the phases stopping, backup, deleting,
+// configuring and starting. A running server is stopped first and started again afterwards.
+// The pre-reset backup must succeed before any file is deleted. Without a new seed only the chunk
+// folders (region, entities, poi) of the selected dimensions are deleted, so level.dat, player data
+// and advancements are kept; a new seed only applies to a new level.dat, so it resets all
+// dimensions completely.
+func (s *WorldService) StartWorldReset(serverID, userID string, req WorldResetRequest) (*models.MinecraftServer, *Operation, error) {
+	if s.mcService == nil || s.configService == nil {
+		return nil, nil, ErrWorldResetUnavailable
+	}
+
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("server not found: %w", err)
+	}
+	if req.Confirm != server.Name {
+		return nil, nil, ErrWorldResetNotConfirmed
+	}
+	switch server.Status {
+	case models.StatusRunning, models.StatusStopped, models.StatusSleeping, models.StatusError:
+	default:
+		return nil, nil, ErrWorldResetBusy
+	}
+
+	reset, err := parseWorldReset(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Info("WORLD: Resetting world", map[string]interface{}{
+		"server_id":  server.ID,
+		"user_id":    userID,
+		"dimensions": reset.dimensions,
+		"new_seed":   reset.newSeed,
+	})
+
+	op, err := s.opLimiter.Go(server.OwnerID, userID, OperationWorldReset, server.ID, server.ID, func(ctx context.Context) error {
+		err := s.resetWorld(ctx, server.ID, userID, reset)
+		if err != nil {
+			logger.Warn("WORLD: World reset failed", map[string]interface{}{
+				"server_id": server.ID,
+				"error":     err.Error(),
+			})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return server, op, nil
+}
+
+// parseWorldReset validates the dimensions and seed of a reset request
+func parseWorldReset(req WorldResetRequest) (*worldReset, error) {
+	if req.RandomSeed && req.Seed != "" {
+		return nil, ErrWorldResetSeedConflict
+	}
+
+	reset := &worldReset{newSeed: req.RandomSeed || req.Seed != "", seed: req.Seed}
+	if req.RandomSeed {
+		reset.seed = strconv.FormatInt(rand.Int64(), 10)
+	}
+
+	selected := map[string]bool{}
+	for _, dimension := range req.Dimensions {
+		if _, ok := dimensionFolders[dimension]; !ok {
+			return nil, ErrWorldResetDimension
+		}
+		selected[dimension] = true
+	}
+	for _, dimension := range []string{DimensionOverworld, DimensionNether, DimensionEnd} {
+		if len(selected) == 0 || selected[dimension] {
+			reset.dimensions = append(reset.dimensions, dimension)
+		}
+	}
+	if reset.newSeed && len(reset.dimensions) != len(dimensionFolders) {
+		return nil, ErrWorldResetSeedPartial
+	}
+	return reset, nil
+}
+
+// resetWorld stops the server, backs it up, deletes the world data, applies the seed and restarts the server
+func (s *WorldService) resetWorld(ctx context.Context, serverID, userID string, reset *worldReset) error {
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return fmt.Errorf("server not found: %w", err)
+	}
+
+	wasRunning := server.Status == models.StatusRunning
+	if wasRunning {
+		s.opLimiter.SetProgress(OperationWorldReset, serverID, "stopping", 0)
+		if err := s.mcService.StopServer(serverID, "world_reset"); err != nil {
+			return fmt.Errorf("failed to stop server: %w", err)
+		}
+	}
+
+	s.opLimiter.SetProgress(OperationWorldReset, serverID, "backup", 10)
+	backup, err := s.backupService.CreateBackupSync(ctx, serverID, models.BackupTypePreReset, "Pre-world-reset backup", &userID, 0)
+	if err != nil {
+		return fmt.Errorf("failed to create pre-reset backup: %w", err)
+	}
+	if backup.Status != models.BackupStatusCompleted {
+		if backup.Status == models.BackupStatusCancelled {
+			return context.Canceled
+		}
+		return fmt.Errorf("failed to create pre-reset backup: %s", backup.ErrorMessage)
+	}
+
+	// Last point to back out: nothing has been deleted yet
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.opLimiter.SetProgress(OperationWorldReset, serverID, "deleting", 60)
+	serverPath := filepath.Join(s.config.ServersBasePath, server.ID)
+	if reset.newSeed {
+		for _, worldName := range []string{"world", "world_nether", "world_the_end"} {
+			if err := os.RemoveAll(filepath.Join(serverPath, worldName)); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", worldName, err)
+			}
+		}
+	} else {
+		for _, dimension := range reset.dimensions {
+			for _, folder := range dimensionFolders[dimension] {
+				for _, chunks := range chunkFolders {
+					if err := os.RemoveAll(filepath.Join(serverPath, folder, chunks)); err != nil {
+						return fmt.Errorf("failed to delete %s chunks: %w", dimension, err)
+					}
+				}
+			}
+		}
+	}
+
+	if reset.newSeed {
+		s.opLimiter.SetProgress(OperationWorldReset, serverID, "configuring", 80)
+		// The server is stopped, so this only records the change; the next start uses the seed
+		if _, err := s.configService.ApplyConfigChanges(ConfigChangeRequest{
+			ServerID: serverID,
+			UserID:   userID,
+			Changes:  map[string]interface{}{"level_seed": reset.seed},
+		}); err != nil {
+			return fmt.Errorf("world was reset, but setting the seed failed: %w", err)
+		}
+	}
+
+	logger.Info("WORLD: World reset", map[string]interface{}{
+		"server_id":  serverID,
+		"dimensions": reset.dimensions,
+		"new_seed":   reset.newSeed,
+		"backup_id":  backup.ID,
+	})
+
+	if wasRunning {
+		s.opLimiter.SetProgress(OperationWorldReset, serverID, "starting", 90)
+		if err := s.mcService.StartServer(serverID); err != nil {
+			return fmt.Errorf("world was reset, but starting the server failed: %w", err)
+		}
+	}
+	s.opLimiter.SetProgress(OperationWorldReset, serverID, "completed", 100)
+	return nil
+}
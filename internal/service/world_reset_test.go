@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseWorldReset(t *testing.T) {
+	reset, err := parseWorldReset(WorldResetRequest{Dimensions: []string{DimensionEnd, DimensionNether, DimensionEnd}})
+	if err != nil {
+		t.Fatalf("parseWorldReset() error = %v", err)
+	}
+	if !slices.Equal(reset.dimensions, []string{DimensionNether, DimensionEnd}) || reset.newSeed {
+		t.Errorf("reset = %+v, want nether and end without a new seed", reset)
+	}
+
+	reset, err = parseWorldReset(WorldResetRequest{RandomSeed: true})
+	if err != nil {
+		t.Fatalf("parseWorldReset() error = %v", err)
+	}
+	if len(reset.dimensions) != 3 || !reset.newSeed || reset.seed == "" {
+		t.Errorf("reset = %+v, want all dimensions with a generated seed", reset)
+	}
+
+	tests := []struct {
+		name string
+		req  WorldResetRequest
+		want error
+	}{
+		{"unknown dimension", WorldResetRequest{Dimensions: []string{"world"}}, ErrWorldResetDimension},
+		{"seed and random seed", WorldResetRequest{Seed: "42", RandomSeed: true}, ErrWorldResetSeedConflict},
+		{"seed for one dimension", WorldResetRequest{Seed: "42", Dimensions: []string{DimensionOverworld}}, ErrWorldResetSeedPartial},
+	}
+	for _, tt := range tests {
+		if _, err := parseWorldReset(tt.req); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
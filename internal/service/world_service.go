@@ -11,6 +11,7 @@ import (
 
 	"github.com/payperplay/hosting/internal/models"
 	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/storage"
 	"github.com/payperplay/hosting/pkg/config"
 	"github.com/payperplay/hosting/pkg/logger"
 )
@@ -31,6 +32,13 @@ type WorldService struct {
 	serverRepo    *repository.ServerRepository
 	backupService *BackupService
 	config        *config.Config
+	mcService     *MinecraftService // Optional, needed for world resets (see StartWorldReset)
+	configService *ConfigService    // Optional, needed for world resets
+	opLimiter     *OperationLimiter // Optional, tracks world resets in GET /api/operations and /api/jobs
+	exportRepo    *repository.WorldExportRepository // Optional, enables world exports and imports (see StartExport)
+	sftpClient    *storage.SFTPClient               // Optional, stores exports on the Storage Box
+	console       *ConsoleService                   // Optional, flushes running servers before an export
+	quota         DiskQuotaChecker                  // Optional, refuses imports into full server directories
 }
 
 // NewWorldService creates a new world service
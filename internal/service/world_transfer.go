@@ -0,0 +1,734 @@
+package service
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/payperplay/hosting/internal/models"
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/internal/storage"
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+// World export and import errors
+var (
+	ErrWorldTransferUnavailable = errors.New("world export and import are not enabled")
+	ErrWorldExportNotFound      = errors.New("world export not found")
+	ErrWorldExportInvalid       = errors.New("worlds must be world, world_nether or world_the_end")
+	ErrWorldExportNotReady      = errors.New("world export is not ready for download")
+	ErrWorldExportNoWorlds      = errors.New("the server has no world to export")
+	ErrWorldExportArchived      = errors.New("the server is archived, start it once before exporting its world")
+	ErrInvalidDownloadLink      = errors.New("invalid or expired download link")
+	ErrWorldImportInvalid       = errors.New("invalid world archive")
+	ErrWorldImportTooLarge      = errors.New("world archive is too large")
+	ErrWorldImportRunning       = errors.New("the server must be stopped to import a world")
+)
+
+// worldFolders are the world folders of a Paper/Spigot server, in export order
+var worldFolders = []string{"world", "world_nether", "world_the_end"}
+
+// worldExportDefaultTTL applies if WORLD_EXPORT_TTL is not a valid duration
+const worldExportDefaultTTL = 24 * time.Hour
+
+// SetExportStorage enables world exports and imports
+// Exports are recorded in repo and uploaded to the Storage Box; with a nil sftpClient they stay on local disk.
+func (s *WorldService) SetExportStorage(repo *repository.WorldExportRepository, sftpClient *storage.SFTPClient) {
+	s.exportRepo = repo
+	s.sftpClient = sftpClient
+}
+
+// SetConsoleService sets the console used to flush the world of a running server before an export
+func (s *WorldService) SetConsoleService(console *ConsoleService) {
+	s.console = console
+}
+
+// SetDiskQuota sets the disk quota checked before world imports
+func (s *WorldService) SetDiskQuota(quota DiskQuotaChecker) {
+	s.quota = quota
+}
+
+// StartExport creates a ZIP archive of the worlds of a server in the background
+// worlds are folders like "world" or "world_nether"; empty exports all existing worlds. The returned
+// operation (nil without a limiter) reports the phases saving, archiving and uploading. A running
+// server keeps running: autosave is paused while the files are read.
+func (s *WorldService) StartExport(serverID, userID string, worlds []string) (*models.WorldExport, *Operation, error) {
+	if s.exportRepo == nil {
+		return nil, nil, ErrWorldTransferUnavailable
+	}
+
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("server not found: %w", err)
+	}
+	if server.Status == models.StatusArchived || server.Status == models.StatusArchiving {
+		return nil, nil, ErrWorldExportArchived
+	}
+
+	serverPath := filepath.Join(s.config.ServersBasePath, server.ID)
+	if len(worlds) == 0 {
+		worlds = worldFolders
+	}
+	var selected []string
+	for _, worldName := range worlds {
+		if !isValidWorldName(worldName) {
+			return nil, nil, ErrWorldExportInvalid
+		}
+		if _, err := os.Stat(filepath.Join(serverPath, worldName)); err == nil && !containsString(selected, worldName) {
+			selected = append(selected, worldName)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, nil, ErrWorldExportNoWorlds
+	}
+
+	export := &models.WorldExport{
+		ServerID:    server.ID,
+		OwnerID:     server.OwnerID,
+		RequestedBy: userID,
+		Worlds:      selected,
+		Status:      models.WorldExportPending,
+	}
+	if err := s.exportRepo.Create(export); err != nil {
+		return nil, nil, fmt.Errorf("failed to create export: %w", err)
+	}
+
+	created := *export // The operation updates export while the caller serializes its copy
+	op, err := s.opLimiter.Go(server.OwnerID, userID, OperationWorldExport, server.ID, export.ID, func(ctx context.Context) error {
+		return s.runExport(ctx, export)
+	})
+	if err != nil {
+		s.failExport(export, err)
+		return nil, nil, err
+	}
+	return &created, op, nil
+}
+
+// runExport archives the worlds of an export and stores the archive until it expires
+func (s *WorldService) runExport(ctx context.Context, export *models.WorldExport) error {
+	localPath := filepath.Join(s.exportDir(), export.ID+".zip")
+	err := s.writeExport(ctx, export, localPath)
+	if err != nil {
+		os.Remove(localPath)
+		s.failExport(export, err)
+		logger.Warn("WORLD: World export failed", map[string]interface{}{
+			"server_id": export.ServerID,
+			"export_id": export.ID,
+			"error":     err.Error(),
+		})
+		return err
+	}
+
+	logger.Info("WORLD: World exported", map[string]interface{}{
+		"server_id":  export.ServerID,
+		"export_id":  export.ID,
+		"worlds":     export.Worlds,
+		"size_bytes": export.SizeBytes,
+		"remote":     export.Remote,
+	})
+	return nil
+}
+
+// writeExport flushes, zips and stores the worlds of an export and marks it ready
+func (s *WorldService) writeExport(ctx context.Context, export *models.WorldExport, localPath string) error {
+	server, err := s.serverRepo.FindByID(export.ServerID)
+	if err != nil {
+		return fmt.Errorf("server not found: %w", err)
+	}
+
+	if server.Status == models.StatusRunning && s.console != nil {
+		s.opLimiter.SetProgress(OperationWorldExport, export.ID, "saving", 0)
+		// Without save-off the server could write region files while they are being zipped
+		if _, err := s.console.ExecuteCommand(ctx, server.ID, "save-off"); err == nil {
+			defer s.console.ExecuteCommand(context.WithoutCancel(ctx), server.ID, "save-on") // Also after a cancelled export
+			if _, err := s.console.ExecuteCommand(ctx, server.ID, "save-all flush"); err != nil {
+				return fmt.Errorf("failed to save world: %w", err)
+			}
+		}
+	}
+
+	s.opLimiter.SetProgress(OperationWorldExport, export.ID, "archiving", 10)
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	serverPath := filepath.Join(s.config.ServersBasePath, server.ID)
+	if err := zipWorlds(ctx, serverPath, export.Worlds, localPath); err != nil {
+		return fmt.Errorf("failed to archive worlds: %w", err)
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+	export.SizeBytes = info.Size()
+	export.StoragePath = localPath
+
+	if s.sftpClient != nil {
+		s.opLimiter.SetProgress(OperationWorldExport, export.ID, "uploading", 60)
+		remotePath, err := s.sftpClient.UploadContext(ctx, localPath, "world-export-"+export.ID+".zip", nil)
+		if err != nil {
+			return fmt.Errorf("failed to upload archive: %w", err)
+		}
+		os.Remove(localPath)
+		export.StoragePath = remotePath
+		export.Remote = true
+	}
+
+	expiresAt := time.Now().Add(s.exportTTL())
+	export.Status = models.WorldExportReady
+	export.ExpiresAt = &expiresAt
+	if err := s.exportRepo.Update(export); err != nil {
+		return fmt.Errorf("failed to record export: %w", err)
+	}
+	s.opLimiter.SetProgress(OperationWorldExport, export.ID, "completed", 100)
+	return nil
+}
+
+// failExport marks an export as failed; the prune worker removes it right away
+func (s *WorldService) failExport(export *models.WorldExport, err error) {
+	now := time.Now()
+	export.Status = models.WorldExportFailed
+	export.Error = err.Error()
+	export.ExpiresAt = &now
+	if updateErr := s.exportRepo.Update(export); updateErr != nil {
+		logger.Warn("WORLD: Failed to record failed export", map[string]interface{}{
+			"export_id": export.ID,
+			"error":     updateErr.Error(),
+		})
+	}
+}
+
+// ListExports returns the exports of a server, with download URLs for the ready ones
+func (s *WorldService) ListExports(serverID string) ([]models.WorldExport, error) {
+	if s.exportRepo == nil {
+		return nil, ErrWorldTransferUnavailable
+	}
+	exports, err := s.exportRepo.FindByServer(serverID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range exports {
+		s.setDownloadURL(&exports[i])
+	}
+	return exports, nil
+}
+
+// GetExport returns an export of a server, with its download URL once it is ready
+func (s *WorldService) GetExport(serverID, exportID string) (*models.WorldExport, error) {
+	if s.exportRepo == nil {
+		return nil, ErrWorldTransferUnavailable
+	}
+	export, err := s.exportRepo.FindByID(exportID)
+	if err != nil || export.ServerID != serverID {
+		return nil, ErrWorldExportNotFound
+	}
+	s.setDownloadURL(export)
+	return export, nil
+}
+
+// setDownloadURL signs the download URL of a ready export, valid until the export expires
+func (s *WorldService) setDownloadURL(export *models.WorldExport) {
+	if export.Status != models.WorldExportReady || export.ExpiresAt == nil || export.ExpiresAt.Before(time.Now()) {
+		return
+	}
+	expires := export.ExpiresAt.Unix()
+	export.DownloadURL = fmt.Sprintf("%s/api/world-exports/%s/download?expires=%d&signature=%s",
+		strings.TrimRight(s.config.BaseURL, "/"), export.ID, expires, s.signExport(export.ID, expires))
+}
+
+// signExport returns the signature of a download link (HMAC-SHA256 with the JWT secret)
+func (s *WorldService) signExport(exportID string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.config.JWTSecret))
+	fmt.Fprintf(mac, "world-export:%s:%d", exportID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// OpenExport verifies a signed download link and opens the archive of the export
+// The caller must close the returned reader.
+func (s *WorldService) OpenExport(exportID, expires, signature string) (io.ReadCloser, int64, error) {
+	if s.exportRepo == nil {
+		return nil, 0, ErrWorldTransferUnavailable
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt ||
+		!hmac.Equal([]byte(signature), []byte(s.signExport(exportID, expiresAt))) {
+		return nil, 0, ErrInvalidDownloadLink
+	}
+
+	export, err := s.exportRepo.FindByID(exportID)
+	if err != nil {
+		return nil, 0, ErrWorldExportNotFound
+	}
+	if export.Status != models.WorldExportReady {
+		return nil, 0, ErrWorldExportNotReady
+	}
+
+	if export.Remote {
+		if s.sftpClient == nil {
+			return nil, 0, ErrWorldTransferUnavailable
+		}
+		return s.sftpClient.Open(export.StoragePath)
+	}
+	file, err := os.Open(export.StoragePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	return file, info.Size(), nil
+}
+
+// RecoverExports fails exports that were interrupted by a restart of the API
+func (s *WorldService) RecoverExports() {
+	if s.exportRepo == nil {
+		return
+	}
+	failed, err := s.exportRepo.FailPending("interrupted by an API restart", time.Now())
+	if err != nil {
+		logger.Warn("WORLD: Failed to recover interrupted exports", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if failed > 0 {
+		logger.Info("WORLD: Marked interrupted exports as failed", map[string]interface{}{
+			"count": failed,
+		})
+	}
+}
+
+// PruneExports deletes the archives of expired and failed exports
+func (s *WorldService) PruneExports() {
+	if s.exportRepo == nil {
+		return
+	}
+	exports, err := s.exportRepo.FindExpired(time.Now())
+	if err != nil {
+		logger.Warn("WORLD: Failed to list expired exports", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	for i := range exports {
+		export := &exports[i]
+		if export.StoragePath != "" {
+			var err error
+			if export.Remote {
+				if s.sftpClient == nil {
+					continue // Storage Box disabled since, keep the record to delete it later
+				}
+				err = s.sftpClient.Delete(export.StoragePath)
+			} else if err = os.Remove(export.StoragePath); errors.Is(err, fs.ErrNotExist) {
+				err = nil
+			}
+			if err != nil {
+				logger.Warn("WORLD: Failed to delete expired export", map[string]interface{}{
+					"export_id": export.ID,
+					"error":     err.Error(),
+				})
+				continue
+			}
+		}
+
+		export.Status = models.WorldExportExpired
+		export.StoragePath = ""
+		if err := s.exportRepo.Update(export); err != nil {
+			logger.Warn("WORLD: Failed to record expired export", map[string]interface{}{
+				"export_id": export.ID,
+				"error":     err.Error(),
+			})
+		}
+	}
+}
+
+// ImportMaxBytes is the largest accepted world archive upload
+func (s *WorldService) ImportMaxBytes() int64 {
+	return int64(s.config.WorldImportMaxSizeMB) << 20
+}
+
+// StartImport replaces the worlds of a stopped server with an uploaded ZIP archive in the background
+// The archive may contain the world folders (world, world_nether, world_the_end), a single world
+// folder of any name, or the files of a single world at its root; a single world becomes the
+// overworld. The archive is validated before the operation starts; the operation (nil without a
+// limiter) backs the server up, extracts the worlds, checks their level.dat and only then replaces
+// the existing worlds.
+func (s *WorldService) StartImport(serverID, userID string, archive io.Reader) (*Operation, error) {
+	if s.exportRepo == nil {
+		return nil, ErrWorldTransferUnavailable
+	}
+
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, fmt.Errorf("server not found: %w", err)
+	}
+	if server.Status != models.StatusStopped && server.Status != models.StatusSleeping {
+		return nil, ErrWorldImportRunning
+	}
+	if err := checkDiskQuota(s.quota, serverID, 0); err != nil {
+		return nil, err
+	}
+
+	archivePath, err := s.saveImport(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.planImport(archivePath)
+	if err != nil {
+		os.Remove(archivePath)
+		return nil, err
+	}
+
+	logger.Info("WORLD: Importing world", map[string]interface{}{
+		"server_id": server.ID,
+		"user_id":   userID,
+		"worlds":    plan,
+	})
+
+	op, err := s.opLimiter.Go(server.OwnerID, userID, OperationWorldImport, server.ID, server.ID, func(ctx context.Context) error {
+		defer os.Remove(archivePath)
+		err := s.runImport(ctx, server.ID, userID, archivePath, plan)
+		if err != nil {
+			logger.Warn("WORLD: World import failed", map[string]interface{}{
+				"server_id": server.ID,
+				"error":     err.Error(),
+			})
+		}
+		return err
+	})
+	if err != nil {
+		os.Remove(archivePath)
+		return nil, err
+	}
+	return op, nil
+}
+
+// saveImport copies an uploaded archive to a temporary file, enforcing the upload size limit
+func (s *WorldService) saveImport(archive io.Reader) (string, error) {
+	dir := filepath.Join(s.config.ServersBasePath, "temp", "imports")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create import directory: %w", err)
+	}
+	archivePath := filepath.Join(dir, uuid.New().String()+".zip")
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return "", err
+	}
+
+	maxBytes := s.ImportMaxBytes()
+	written, err := io.Copy(file, io.LimitReader(archive, maxBytes+1))
+	file.Close()
+	switch {
+	case err != nil:
+		err = fmt.Errorf("failed to save archive: %w", err)
+	case written > maxBytes:
+		err = fmt.Errorf("%w (limit %d MB)", ErrWorldImportTooLarge, s.config.WorldImportMaxSizeMB)
+	}
+	if err != nil {
+		os.Remove(archivePath)
+		return "", err
+	}
+	return archivePath, nil
+}
+
+// planImport opens an archive and decides which archive folder becomes which world
+func (s *WorldService) planImport(archivePath string) (worldImportPlan, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a ZIP archive", ErrWorldImportInvalid)
+	}
+	defer reader.Close()
+	return planWorldImport(reader.File, uint64(s.config.WorldImportMaxExtractedMB)<<20)
+}
+
+// worldImportPlan maps world folders of the server to their folder in the archive ("" = archive root)
+type worldImportPlan map[string]string
+
+// planWorldImport validates the entries of a world archive and locates the worlds in it
+func planWorldImport(files []*zip.File, maxExtracted uint64) (worldImportPlan, error) {
+	var total uint64
+	var rootLevel bool
+	var folders []string // Top-level folders containing a level.dat
+	for _, file := range files {
+		name := file.Name
+		if strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+		if strings.Contains(name, "\\") || path.IsAbs(name) || !fs.ValidPath(strings.TrimSuffix(name, "/")) {
+			return nil, fmt.Errorf("%w: illegal file path %s", ErrWorldImportInvalid, name)
+		}
+		total += file.UncompressedSize64
+		if total > maxExtracted {
+			return nil, fmt.Errorf("%w (more than %d MB extracted)", ErrWorldImportTooLarge, maxExtracted>>20)
+		}
+
+		switch parts := strings.Split(name, "/"); {
+		case len(parts) == 1 && name == "level.dat":
+			rootLevel = true
+		case len(parts) == 2 && parts[1] == "level.dat":
+			folders = append(folders, parts[0])
+		}
+	}
+
+	if rootLevel {
+		return worldImportPlan{"world": ""}, nil
+	}
+	plan := worldImportPlan{}
+	for _, folder := range folders {
+		if containsString(worldFolders, folder) {
+			plan[folder] = folder + "/"
+		}
+	}
+	switch {
+	case len(plan) > 0:
+		return plan, nil
+	case len(folders) == 1:
+		return worldImportPlan{"world": folders[0] + "/"}, nil
+	case len(folders) > 1:
+		return nil, fmt.Errorf("%w: several worlds found, name their folders world, world_nether and world_the_end", ErrWorldImportInvalid)
+	default:
+		return nil, fmt.Errorf("%w: no level.dat found", ErrWorldImportInvalid)
+	}
+}
+
+// runImport backs up the server, extracts and checks the worlds and swaps them in
+func (s *WorldService) runImport(ctx context.Context, serverID, userID, archivePath string, plan worldImportPlan) error {
+	s.opLimiter.SetProgress(OperationWorldImport, serverID, "backup", 0)
+	backup, err := s.backupService.CreateBackupSync(ctx, serverID, models.BackupTypePreImport, "Pre-world-import backup", &userID, 0)
+	if err != nil {
+		return fmt.Errorf("failed to create pre-import backup: %w", err)
+	}
+	if backup.Status != models.BackupStatusCompleted {
+		if backup.Status == models.BackupStatusCancelled {
+			return context.Canceled
+		}
+		return fmt.Errorf("failed to create pre-import backup: %s", backup.ErrorMessage)
+	}
+
+	s.opLimiter.SetProgress(OperationWorldImport, serverID, "extracting", 40)
+	serverPath := filepath.Join(s.config.ServersBasePath, serverID)
+	stagingPath := filepath.Join(serverPath, ".world-import")
+	os.RemoveAll(stagingPath)
+	defer os.RemoveAll(stagingPath)
+	if err := extractWorlds(ctx, archivePath, stagingPath, plan, uint64(s.config.WorldImportMaxExtractedMB)<<20); err != nil {
+		return err
+	}
+	for worldName := range plan {
+		if err := validateLevelDat(filepath.Join(stagingPath, worldName, "level.dat")); err != nil {
+			return fmt.Errorf("%s: %w", worldName, err)
+		}
+	}
+
+	// Last point to back out: the existing worlds are still untouched
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	server, err := s.serverRepo.FindByID(serverID)
+	if err != nil {
+		return fmt.Errorf("server not found: %w", err)
+	}
+	if server.Status != models.StatusStopped && server.Status != models.StatusSleeping {
+		return ErrWorldImportRunning
+	}
+
+	s.opLimiter.SetProgress(OperationWorldImport, serverID, "replacing", 90)
+	for worldName := range plan {
+		worldPath := filepath.Join(serverPath, worldName)
+		if err := os.RemoveAll(worldPath); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", worldName, err)
+		}
+		if err := os.Rename(filepath.Join(stagingPath, worldName), worldPath); err != nil {
+			return fmt.Errorf("failed to move %s into place: %w", worldName, err)
+		}
+	}
+
+	logger.Info("WORLD: World imported", map[string]interface{}{
+		"server_id": serverID,
+		"worlds":    plan,
+		"backup_id": backup.ID,
+	})
+	s.opLimiter.SetProgress(OperationWorldImport, serverID, "completed", 100)
+	return nil
+}
+
+// extractWorlds extracts the planned worlds of an archive into targetDir/<world>
+// maxBytes limits the bytes actually written, in case the archive lies about its sizes.
+func extractWorlds(ctx context.Context, archivePath, targetDir string, plan worldImportPlan, maxBytes uint64) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("%w: not a ZIP archive", ErrWorldImportInvalid)
+	}
+	defer reader.Close()
+
+	remaining := int64(maxBytes)
+	for _, file := range reader.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for worldName, prefix := range plan {
+			rel, ok := strings.CutPrefix(file.Name, prefix)
+			if !ok || rel == "" || strings.HasPrefix(file.Name, "__MACOSX/") {
+				continue
+			}
+			targetPath := filepath.Join(targetDir, worldName, filepath.FromSlash(rel))
+			if file.FileInfo().IsDir() {
+				if err := os.MkdirAll(targetPath, 0755); err != nil {
+					return err
+				}
+				continue
+			}
+			written, err := extractFile(file, targetPath, remaining)
+			if errors.Is(err, errExtractLimit) {
+				return ErrWorldImportTooLarge
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrWorldImportInvalid, err)
+			}
+			remaining -= written
+		}
+	}
+	return nil
+}
+
+// errExtractLimit is returned by extractFile when an entry is larger than the remaining limit
+var errExtractLimit = errors.New("extraction limit exceeded")
+
+// extractFile writes one archive entry to targetPath, writing at most limit bytes
+func extractFile(file *zip.File, targetPath string, limit int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return 0, err
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	out, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	written, err := io.Copy(out, io.LimitReader(rc, limit+1))
+	if err != nil {
+		return written, err
+	}
+	if written > limit {
+		return written, errExtractLimit
+	}
+	return written, nil
+}
+
+// validateLevelDat checks that a level.dat is gzip-compressed NBT starting with a compound tag
+func validateLevelDat(levelPath string) error {
+	file, err := os.Open(levelPath)
+	if err != nil {
+		return fmt.Errorf("%w: missing level.dat", ErrWorldImportInvalid)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("%w: level.dat is not gzip-compressed", ErrWorldImportInvalid)
+	}
+	defer gz.Close()
+
+	tag := make([]byte, 1)
+	if _, err := io.ReadFull(gz, tag); err != nil || tag[0] != 0x0A {
+		return fmt.Errorf("%w: level.dat is not NBT data", ErrWorldImportInvalid)
+	}
+	return nil
+}
+
+// zipWorlds writes the world folders of a server into a ZIP archive, one top-level folder per world
+func zipWorlds(ctx context.Context, serverPath string, worlds []string, target string) error {
+	zipFile, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer zipFile.Close()
+
+	archive := zip.NewWriter(zipFile)
+	for _, worldName := range worlds {
+		err := filepath.WalkDir(filepath.Join(serverPath, worldName), func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			// session.lock is held by a running server and useless in an export
+			if entry.IsDir() || entry.Name() == "session.lock" {
+				return nil
+			}
+			return addZipFile(archive, serverPath, filePath)
+		})
+		if err != nil {
+			archive.Close()
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// addZipFile adds a file to a ZIP archive under its path relative to base
+func addZipFile(archive *zip.Writer, base, filePath string) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(base, filePath)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(rel)
+	header.Method = zip.Deflate
+
+	writer, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(writer, file)
+	return err
+}
+
+// exportDir is where export archives are written (and kept without a Storage Box)
+func (s *WorldService) exportDir() string {
+	return filepath.Join(s.config.ServersBasePath, ".exports")
+}
+
+// exportTTL is how long export archives can be downloaded
+func (s *WorldService) exportTTL() time.Duration {
+	ttl, err := time.ParseDuration(s.config.WorldExportTTL)
+	if err != nil || ttl <= 0 {
+		return worldExportDefaultTTL
+	}
+	return ttl
+}
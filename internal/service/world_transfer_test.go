@@ -0,0 +1,79 @@
+package service
+
+import (
+	"archive/zip"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/payperplay/hosting/internal/repository"
+	"github.com/payperplay/hosting/pkg/config"
+)
+
+func TestPlanWorldImport(t *testing.T) {
+	entries := func(names ...string) []*zip.File {
+		files := make([]*zip.File, len(names))
+		for i, name := range names {
+			files[i] = &zip.File{FileHeader: zip.FileHeader{Name: name, UncompressedSize64: 1 << 20}}
+		}
+		return files
+	}
+
+	tests := []struct {
+		name  string
+		files []*zip.File
+		want  worldImportPlan
+		err   error
+	}{
+		{"world at the root", entries("level.dat", "region/r.0.0.mca"), worldImportPlan{"world": ""}, nil},
+		{"server folders", entries("world/level.dat", "world_nether/level.dat", "__MACOSX/world/level.dat"), worldImportPlan{"world": "world/", "world_nether": "world_nether/"}, nil},
+		{"single named world", entries("My Survival/level.dat", "My Survival/region/r.0.0.mca"), worldImportPlan{"world": "My Survival/"}, nil},
+		{"several named worlds", entries("a/level.dat", "b/level.dat"), nil, ErrWorldImportInvalid},
+		{"no level.dat", entries("world/region/r.0.0.mca"), nil, ErrWorldImportInvalid},
+		{"zip slip", entries("../world/level.dat"), nil, ErrWorldImportInvalid},
+		{"too large", entries("level.dat", "a", "b", "c"), nil, ErrWorldImportTooLarge},
+	}
+	for _, tt := range tests {
+		plan, err := planWorldImport(tt.files, 3<<20)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if len(plan) != len(tt.want) {
+			t.Errorf("%s: plan = %v, want %v", tt.name, plan, tt.want)
+			continue
+		}
+		for world, prefix := range tt.want {
+			if plan[world] != prefix {
+				t.Errorf("%s: plan = %v, want %v", tt.name, plan, tt.want)
+			}
+		}
+	}
+}
+
+func TestExportDownloadSignature(t *testing.T) {
+	s := &WorldService{config: &config.Config{JWTSecret: "secret", BaseURL: "https://api.example.com/"}}
+	signature := s.signExport("export-1", 1700000000)
+	if signature != s.signExport("export-1", 1700000000) {
+		t.Fatal("signExport() is not deterministic")
+	}
+	for _, other := range []string{s.signExport("export-2", 1700000000), s.signExport("export-1", 1700000001)} {
+		if other == signature {
+			t.Error("signature does not depend on the export and expiry")
+		}
+	}
+
+	// Links are checked before the export is looked up
+	s.exportRepo = &repository.WorldExportRepository{}
+	future := time.Now().Add(time.Hour).Unix()
+	for _, link := range []struct{ expires, signature string }{
+		{"1700000000", signature},
+		{strconv.FormatInt(future, 10), signature},
+		{"not-a-time", signature},
+	} {
+		if _, _, err := s.OpenExport("export-1", link.expires, link.signature); !errors.Is(err, ErrInvalidDownloadLink) {
+			t.Errorf("OpenExport(%s, %s) error = %v, want ErrInvalidDownloadLink", link.expires, link.signature, err)
+		}
+	}
+}
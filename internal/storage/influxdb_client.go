@@ -29,6 +29,7 @@ type EventFilters struct {
 	StartTime time.Time
 	EndTime   time.Time
 	Limit     int
+	Ascending bool // Oldest events first; default is newest first
 }
 
 // InfluxDBClient manages connection to InfluxDB for time-series event storage
@@ -38,14 +39,19 @@ type InfluxDBClient struct {
 	queryAPI api.QueryAPI
 	org      string
 	bucket   string
+
+	// Container metrics (see influxdb_metrics.go)
+	metricsWriteAPI api.WriteAPI
+	metricsBucket   string
 }
 
 // InfluxDBConfig holds InfluxDB connection configuration
 type InfluxDBConfig struct {
-	URL    string
-	Token  string
-	Org    string
-	Bucket string
+	URL           string
+	Token         string
+	Org           string
+	Bucket        string
+	MetricsBucket string // Container metrics (empty = Bucket)
 }
 
 // NewInfluxDBClient creates a new InfluxDB client
@@ -77,15 +83,47 @@ func NewInfluxDBClient(config InfluxDBConfig) (*InfluxDBClient, error) {
 	writeAPI := client.WriteAPI(config.Org, config.Bucket)
 	queryAPI := client.QueryAPI(config.Org)
 
+	metricsBucket := config.MetricsBucket
+	if metricsBucket == "" {
+		metricsBucket = config.Bucket
+	}
+
 	return &InfluxDBClient{
-		client:   client,
-		writeAPI: writeAPI,
-		queryAPI: queryAPI,
-		org:      config.Org,
-		bucket:   config.Bucket,
+		client:          client,
+		writeAPI:        writeAPI,
+		queryAPI:        queryAPI,
+		org:             config.Org,
+		bucket:          config.Bucket,
+		metricsWriteAPI: client.WriteAPI(config.Org, metricsBucket),
+		metricsBucket:   metricsBucket,
 	}, nil
 }
 
+// PingInfluxDB checks that the InfluxDB server at url is healthy and returns its version
+// The health endpoint needs no token, so this also works if the client couldn't be created.
+func PingInfluxDB(ctx context.Context, url string) (string, error) {
+	client := influxdb2.NewClient(url, "")
+	defer client.Close()
+
+	health, err := client.Health(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach InfluxDB: %w", err)
+	}
+	if health.Status != "pass" {
+		message := ""
+		if health.Message != nil {
+			message = *health.Message
+		}
+		return "", fmt.Errorf("InfluxDB health check failed: %s", message)
+	}
+
+	version := ""
+	if health.Version != nil {
+		version = *health.Version
+	}
+	return version, nil
+}
+
 // WriteEvent writes an event to InfluxDB as a time-series point
 func (c *InfluxDBClient) WriteEvent(event EventData) error {
 	// Create InfluxDB point
@@ -206,9 +244,9 @@ func (c *InfluxDBClient) buildFluxQuery(filters EventFilters) string {
   |> filter(fn: (r) => r.user_id == "%s")`, filters.UserID)
 	}
 
-	// Sort by time descending
-	query += `
-  |> sort(columns: ["_time"], desc: true)`
+	// Sort by time (newest first unless ascending is requested)
+	query += fmt.Sprintf(`
+  |> sort(columns: ["_time"], desc: %t)`, !filters.Ascending)
 
 	// Limit
 	if filters.Limit > 0 {
@@ -219,9 +257,15 @@ func (c *InfluxDBClient) buildFluxQuery(filters EventFilters) string {
 	return query
 }
 
+// SetToken replaces the API token (rotated in the secret store)
+func (c *InfluxDBClient) SetToken(token string) {
+	c.client.HTTPService().SetAuthorization("Token " + token)
+}
+
 // Close closes the InfluxDB client and flushes pending writes
 func (c *InfluxDBClient) Close() {
 	c.writeAPI.Flush()
+	c.metricsWriteAPI.Flush()
 	c.client.Close()
 	logger.Info("InfluxDB client closed", nil)
 }
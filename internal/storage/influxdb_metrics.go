@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/query"
+)
+
+// containerMetricsMeasurement holds one point per server container and sample
+const containerMetricsMeasurement = "container_metrics"
+
+// ContainerMetricsPoint is the resource usage of a server container at one point in time
+// IO values are rates over the previous sample; HasRates is false for a container's first sample.
+type ContainerMetricsPoint struct {
+	ServerID              string    `json:"-"`
+	NodeID                string    `json:"node_id"`
+	Time                  time.Time `json:"time"`
+	CPUPercent            float64   `json:"cpu_percent"` // 100 = one core
+	MemoryBytes           float64   `json:"memory_bytes"`
+	MemoryLimitBytes      float64   `json:"memory_limit_bytes"`
+	NetRxBytesPerSec      float64   `json:"net_rx_bytes_per_sec"`
+	NetTxBytesPerSec      float64   `json:"net_tx_bytes_per_sec"`
+	BlockReadBytesPerSec  float64   `json:"block_read_bytes_per_sec"`
+	BlockWriteBytesPerSec float64   `json:"block_write_bytes_per_sec"`
+	HasRates              bool      `json:"-"`
+}
+
+// WriteContainerMetrics writes container samples to the metrics bucket (non-blocking)
+func (c *InfluxDBClient) WriteContainerMetrics(points []ContainerMetricsPoint) {
+	for _, point := range points {
+		fields := map[string]interface{}{
+			"cpu_percent":        point.CPUPercent,
+			"memory_bytes":       point.MemoryBytes,
+			"memory_limit_bytes": point.MemoryLimitBytes,
+		}
+		if point.HasRates {
+			fields["net_rx_bytes_per_sec"] = point.NetRxBytesPerSec
+			fields["net_tx_bytes_per_sec"] = point.NetTxBytesPerSec
+			fields["block_read_bytes_per_sec"] = point.BlockReadBytesPerSec
+			fields["block_write_bytes_per_sec"] = point.BlockWriteBytesPerSec
+		}
+		c.metricsWriteAPI.WritePoint(influxdb2.NewPoint(
+			containerMetricsMeasurement,
+			map[string]string{
+				"server_id": point.ServerID,
+				"node_id":   point.NodeID,
+			},
+			fields,
+			point.Time,
+		))
+	}
+}
+
+// QueryContainerMetrics returns the samples of a server since start, averaged per window (oldest first)
+func (c *InfluxDBClient) QueryContainerMetrics(ctx context.Context, serverID string, start time.Time, window time.Duration) ([]ContainerMetricsPoint, error) {
+	flux := fmt.Sprintf(`from(bucket: %q)
+  |> range(start: %s)
+  |> filter(fn: (r) => r._measurement == %q and r.server_id == %q)
+  |> aggregateWindow(every: %s, fn: mean, createEmpty: false)
+  |> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+  |> group()
+  |> sort(columns: ["_time"])`,
+		c.metricsBucket, start.UTC().Format(time.RFC3339), containerMetricsMeasurement, serverID, window)
+
+	result, err := c.queryAPI.Query(ctx, flux)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query InfluxDB: %w", err)
+	}
+
+	var points []ContainerMetricsPoint
+	for result.Next() {
+		record := result.Record()
+		nodeID, _ := record.ValueByKey("node_id").(string)
+		points = append(points, ContainerMetricsPoint{
+			ServerID:              serverID,
+			NodeID:                nodeID,
+			Time:                  record.Time(),
+			CPUPercent:            recordFloat(record, "cpu_percent"),
+			MemoryBytes:           recordFloat(record, "memory_bytes"),
+			MemoryLimitBytes:      recordFloat(record, "memory_limit_bytes"),
+			NetRxBytesPerSec:      recordFloat(record, "net_rx_bytes_per_sec"),
+			NetTxBytesPerSec:      recordFloat(record, "net_tx_bytes_per_sec"),
+			BlockReadBytesPerSec:  recordFloat(record, "block_read_bytes_per_sec"),
+			BlockWriteBytesPerSec: recordFloat(record, "block_write_bytes_per_sec"),
+		})
+	}
+	if result.Err() != nil {
+		return nil, fmt.Errorf("query parsing failed: %w", result.Err())
+	}
+	return points, nil
+}
+
+// recordFloat returns a numeric column of a pivoted record (0 if missing in that window)
+func recordFloat(record *query.FluxRecord, key string) float64 {
+	switch value := record.ValueByKey(key).(type) {
+	case float64:
+		return value
+	case int64:
+		return float64(value)
+	}
+	return 0
+}
+
+// tickMetricsMeasurement holds one point per server and TPS sample
+const tickMetricsMeasurement = "server_ticks"
+
+// TickMetricsPoint is the tick rate of a server at one point in time
+type TickMetricsPoint struct {
+	ServerID string    `json:"-"`
+	Time     time.Time `json:"time"`
+	TPS      float64   `json:"tps"`
+	MSPT     float64   `json:"mspt"` // Milliseconds per tick, -1 if the server doesn't report it
+}
+
+// WriteTickMetrics writes TPS samples to the metrics bucket (non-blocking)
+func (c *InfluxDBClient) WriteTickMetrics(points []TickMetricsPoint) {
+	for _, point := range points {
+		fields := map[string]interface{}{"tps": point.TPS}
+		if point.MSPT >= 0 {
+			fields["mspt"] = point.MSPT
+		}
+		c.metricsWriteAPI.WritePoint(influxdb2.NewPoint(
+			tickMetricsMeasurement,
+			map[string]string{"server_id": point.ServerID},
+			fields,
+			point.Time,
+		))
+	}
+}
+
+// QueryTickMetrics returns the TPS samples of a server since start, averaged per window (oldest first)
+func (c *InfluxDBClient) QueryTickMetrics(ctx context.Context, serverID string, start time.Time, window time.Duration) ([]TickMetricsPoint, error) {
+	flux := fmt.Sprintf(`from(bucket: %q)
+  |> range(start: %s)
+  |> filter(fn: (r) => r._measurement == %q and r.server_id == %q)
+  |> aggregateWindow(every: %s, fn: mean, createEmpty: false)
+  |> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+  |> group()
+  |> sort(columns: ["_time"])`,
+		c.metricsBucket, start.UTC().Format(time.RFC3339), tickMetricsMeasurement, serverID, window)
+
+	result, err := c.queryAPI.Query(ctx, flux)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query InfluxDB: %w", err)
+	}
+
+	var points []TickMetricsPoint
+	for result.Next() {
+		record := result.Record()
+		mspt := -1.0
+		if record.ValueByKey("mspt") != nil {
+			mspt = recordFloat(record, "mspt")
+		}
+		points = append(points, TickMetricsPoint{
+			ServerID: serverID,
+			Time:     record.Time(),
+			TPS:      recordFloat(record, "tps"),
+			MSPT:     mspt,
+		})
+	}
+	if result.Err() != nil {
+		return nil, fmt.Errorf("query parsing failed: %w", result.Err())
+	}
+	return points, nil
+}
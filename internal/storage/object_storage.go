@@ -0,0 +1,451 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/payperplay/hosting/pkg/logger"
+)
+
+const (
+	// objectPartSize is the part size of multipart uploads (single PUTs are limited to 5 GB)
+	objectPartSize = 64 << 20
+	// unsignedPayload skips hashing upload bodies; allowed by S3 for requests over HTTPS
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	emptyPayloadSHA = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// ErrObjectNotFound is returned if an object doesn't exist in the bucket
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStorageOptions are the settings of an S3-compatible bucket
+type ObjectStorageOptions struct {
+	Endpoint  string // e.g. https://s3.eu-central-1.amazonaws.com (path-style requests)
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// ObjectInfo is the metadata of a stored object
+type ObjectInfo struct {
+	Size           int64
+	StorageClass   string     // "" = STANDARD
+	RestoreOngoing bool       // A restore of an archived object is in progress
+	RestoredUntil  *time.Time // Expiry of the temporary copy of a restored object
+}
+
+// NeedsRestore reports whether the object is in an archive class that can't be read before a restore
+// (GLACIER, DEEP_ARCHIVE). Instant-retrieval classes are readable directly.
+func (o *ObjectInfo) NeedsRestore() bool {
+	switch o.StorageClass {
+	case "GLACIER", "DEEP_ARCHIVE":
+		return o.RestoredUntil == nil
+	default:
+		return false
+	}
+}
+
+// ObjectStorageClient talks to an S3-compatible object storage (AWS S3 or compatible providers)
+// Requests are signed with AWS Signature Version 4; only the calls the cold archive tier needs are implemented.
+type ObjectStorageClient struct {
+	opts       ObjectStorageOptions
+	endpoint   *url.URL
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewObjectStorageClient creates a client for the bucket in opts
+func NewObjectStorageClient(opts ObjectStorageOptions) (*ObjectStorageClient, error) {
+	if opts.Endpoint == "" || opts.Bucket == "" || opts.AccessKey == "" || opts.SecretKey == "" {
+		return nil, fmt.Errorf("object storage endpoint, bucket or credentials missing in configuration")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(opts.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid object storage endpoint %q", opts.Endpoint)
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+
+	return &ObjectStorageClient{
+		opts:       opts,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: 0}, // Transfers of large archives; requests are bound by their context
+		now:        time.Now,
+	}, nil
+}
+
+// Bucket returns the name of the bucket
+func (c *ObjectStorageClient) Bucket() string {
+	return c.opts.Bucket
+}
+
+// PutFile uploads a local file as key with the given storage class ("" = bucket default)
+// Files larger than one part are uploaded in parts; a failed multipart upload is aborted.
+func (c *ObjectStorageClient) PutFile(ctx context.Context, key, localPath, storageClass string, onProgress ProgressFunc) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open local file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat local file: %w", err)
+	}
+	size := info.Size()
+	progress := &progressReader{ctx: ctx, r: file, total: size, onProgress: onProgress}
+
+	headers := http.Header{}
+	if storageClass != "" {
+		headers.Set("X-Amz-Storage-Class", storageClass)
+	}
+
+	if size <= objectPartSize {
+		resp, err := c.do(ctx, http.MethodPut, key, nil, headers, progress, size)
+		if err != nil {
+			return fmt.Errorf("failed to upload object: %w", err)
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	return c.putMultipart(ctx, key, headers, progress, size)
+}
+
+// putMultipart uploads body in objectPartSize parts
+func (c *ObjectStorageClient) putMultipart(ctx context.Context, key string, headers http.Header, body io.Reader, size int64) error {
+	resp, err := c.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, headers, nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to start multipart upload: %w", err)
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err = xml.NewDecoder(resp.Body).Decode(&initiated)
+	resp.Body.Close()
+	if err != nil || initiated.UploadID == "" {
+		return fmt.Errorf("invalid multipart upload response: %v", err)
+	}
+
+	type completedPart struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+	var parts []completedPart
+	abort := func(cause error) error {
+		// Aborting frees the stored parts; a background context so a cancelled upload is cleaned up too
+		abortCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if resp, err := c.do(abortCtx, http.MethodDelete, key, url.Values{"uploadId": {initiated.UploadID}}, nil, nil, 0); err != nil {
+			logger.Warn("OBJECT-STORAGE: Failed to abort multipart upload", map[string]interface{}{
+				"key":   key,
+				"error": err.Error(),
+			})
+		} else {
+			resp.Body.Close()
+		}
+		return cause
+	}
+
+	for offset, number := int64(0), 1; offset < size; offset, number = offset+objectPartSize, number+1 {
+		partSize := min(objectPartSize, size-offset)
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {initiated.UploadID}}
+		resp, err := c.do(ctx, http.MethodPut, key, query, nil, io.LimitReader(body, partSize), partSize)
+		if err != nil {
+			return abort(fmt.Errorf("failed to upload part %d: %w", number, err))
+		}
+		resp.Body.Close()
+		parts = append(parts, completedPart{PartNumber: number, ETag: resp.Header.Get("ETag")})
+	}
+
+	completion, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return abort(err)
+	}
+	resp, err = c.do(ctx, http.MethodPost, key, url.Values{"uploadId": {initiated.UploadID}}, nil, bytes.NewReader(completion), int64(len(completion)))
+	if err != nil {
+		return abort(fmt.Errorf("failed to complete multipart upload: %w", err))
+	}
+	defer resp.Body.Close()
+
+	// S3 reports errors of the completion inside a 200 response
+	var result struct {
+		XMLName xml.Name
+		Message string `xml:"Message"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err == nil && result.XMLName.Local == "Error" {
+		return abort(fmt.Errorf("failed to complete multipart upload: %s", result.Message))
+	}
+	return nil
+}
+
+// GetFile downloads key to a local file
+func (c *ObjectStorageClient) GetFile(ctx context.Context, key, localPath string, onProgress ProgressFunc) error {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil, nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to download object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("failed to create local directory: %w", err)
+	}
+	file, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create local file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, &progressReader{ctx: ctx, r: resp.Body, total: resp.ContentLength, onProgress: onProgress}); err != nil {
+		os.Remove(localPath)
+		return fmt.Errorf("failed to download object: %w", err)
+	}
+	return nil
+}
+
+// Stat returns the metadata of key, ErrObjectNotFound if it doesn't exist
+func (c *ObjectStorageClient) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	resp, err := c.do(ctx, http.MethodHead, key, nil, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	info := &ObjectInfo{Size: resp.ContentLength, StorageClass: resp.Header.Get("X-Amz-Storage-Class")}
+	info.RestoreOngoing, info.RestoredUntil = parseRestoreHeader(resp.Header.Get("X-Amz-Restore"))
+	return info, nil
+}
+
+// restoreRequest is the body of a RestoreObject request
+type restoreRequest struct {
+	XMLName              xml.Name `xml:"RestoreRequest"`
+	Days                 int      `xml:"Days"`
+	GlacierJobParameters struct {
+		Tier string `xml:"Tier"`
+	} `xml:"GlacierJobParameters"`
+}
+
+// Restore requests a temporary readable copy of an archived object for days
+// tier is Expedited, Standard or Bulk. A restore that is already in progress is not an error.
+func (c *ObjectStorageClient) Restore(ctx context.Context, key string, days int, tier string) error {
+	request := restoreRequest{Days: days}
+	request.GlacierJobParameters.Tier = tier
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, key, url.Values{"restore": {""}}, nil, bytes.NewReader(body), int64(len(body)))
+	var statusErr *ObjectStorageError
+	if errors.As(err, &statusErr) && statusErr.Code == "RestoreAlreadyInProgress" {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to request restore: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Delete removes key (deleting a missing object succeeds)
+func (c *ObjectStorageClient) Delete(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, nil, nil, nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ObjectStorageError is an error response of the object storage
+type ObjectStorageError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *ObjectStorageError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("object storage returned HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("object storage returned HTTP %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Unwrap makes errors.Is(err, ErrObjectNotFound) match for 404 responses
+func (e *ObjectStorageError) Unwrap() error {
+	if e.StatusCode == http.StatusNotFound {
+		return ErrObjectNotFound
+	}
+	return nil
+}
+
+// do sends a signed request for key; responses other than 2xx are returned as *ObjectStorageError
+func (c *ObjectStorageClient) do(ctx context.Context, method, key string, query url.Values, headers http.Header, body io.Reader, size int64) (*http.Response, error) {
+	u := *c.endpoint
+	u.Path = u.Path + "/" + c.opts.Bucket + "/" + key
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	req.ContentLength = size
+	if body == nil {
+		req.Body = http.NoBody
+	}
+
+	payloadHash := emptyPayloadSHA
+	if size > 0 {
+		payloadHash = unsignedPayload
+	}
+	c.sign(req, payloadHash)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	storageErr := &ObjectStorageError{StatusCode: resp.StatusCode}
+	if method != http.MethodHead {
+		var body struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		if xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body) == nil {
+			storageErr.Code, storageErr.Message = body.Code, body.Message
+		}
+	}
+	return nil, storageErr
+}
+
+// sign adds the AWS Signature Version 4 headers to req
+// host, range and all x-amz-* headers are signed.
+func (c *ObjectStorageClient) sign(req *http.Request, payloadHash string) {
+	now := c.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "range" {
+			signed[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + signed[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.opts.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+c.opts.SecretKey), date)
+	key = hmacSHA256(key, c.opts.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.opts.AccessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query sorted by key with RFC 3986 escaping, as required for signing
+func canonicalQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, uriEscape(key)+"="+uriEscape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func uriEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// parseRestoreHeader parses x-amz-restore: ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"
+func parseRestoreHeader(header string) (bool, *time.Time) {
+	if header == "" {
+		return false, nil
+	}
+	if strings.Contains(header, `ongoing-request="true"`) {
+		return true, nil
+	}
+	_, expiry, found := strings.Cut(header, `expiry-date="`)
+	if !found {
+		return false, nil
+	}
+	expiry, _, _ = strings.Cut(expiry, `"`)
+	until, err := time.Parse(http.TimeFormat, expiry)
+	if err != nil {
+		return false, nil
+	}
+	return false, &until
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
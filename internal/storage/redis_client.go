@@ -0,0 +1,259 @@
+package storage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	redisDialTimeout = 5 * time.Second
+	redisIOTimeout   = 5 * time.Second // Per command (blocking commands add their own wait)
+)
+
+// RedisOptions holds the connection settings of a redis:// URL
+type RedisOptions struct {
+	Addr     string
+	Username string
+	Password string
+	DB       int
+}
+
+// ParseRedisURL parses redis://[user:password@]host[:6379][/db]
+func ParseRedisURL(rawURL string) (RedisOptions, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return RedisOptions{}, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if u.Scheme != "redis" {
+		return RedisOptions{}, fmt.Errorf("unsupported Redis URL scheme %q (expected redis://)", u.Scheme)
+	}
+
+	opts := RedisOptions{Addr: u.Host}
+	if u.Port() == "" {
+		opts.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		opts.Username = u.User.Username()
+		opts.Password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if opts.DB, err = strconv.Atoi(db); err != nil {
+			return RedisOptions{}, fmt.Errorf("invalid Redis database %q", db)
+		}
+	}
+	return opts, nil
+}
+
+// DialRedis opens an authenticated connection to the configured database
+func DialRedis(opts RedisOptions) (*RedisConn, error) {
+	netConn, err := net.DialTimeout("tcp", opts.Addr, redisDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", opts.Addr, err)
+	}
+	conn := &RedisConn{conn: netConn, reader: bufio.NewReader(netConn), writer: bufio.NewWriter(netConn)}
+
+	if opts.Password != "" {
+		args := []string{"AUTH", opts.Password}
+		if opts.Username != "" {
+			args = []string{"AUTH", opts.Username, opts.Password}
+		}
+		if _, err := conn.Do(args...); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis AUTH failed: %w", err)
+		}
+	}
+	if opts.DB != 0 {
+		if _, err := conn.Do("SELECT", strconv.Itoa(opts.DB)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis SELECT failed: %w", err)
+		}
+	}
+
+	return conn, nil
+}
+
+// RedisConn is a minimal RESP2 connection (one command at a time, no client library)
+// Callers only need a handful of commands, which keeps go-redis and its dependencies out of the API.
+type RedisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// Do sends a command and reads its reply
+// Replies decode to string, int64, nil, []interface{} or an error for "-" replies.
+func (c *RedisConn) Do(args ...string) (interface{}, error) {
+	return c.DoTimeout(redisIOTimeout, args...)
+}
+
+// DoTimeout is Do with a deadline for writing the command and reading the reply
+// A timed out connection is left in an undefined state; callers close it on errors.
+func (c *RedisConn) DoTimeout(timeout time.Duration, args ...string) (interface{}, error) {
+	c.conn.SetDeadline(time.Now().Add(timeout))
+	fmt.Fprintf(c.writer, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.writer, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := c.writer.Flush(); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply decodes one RESP value
+func (c *RedisConn) readReply() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty Redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, RedisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2) // Value + CRLF
+		if _, err := io.ReadFull(c.reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, count)
+		for i := range values {
+			if values[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unexpected Redis reply: %q", line)
+	}
+}
+
+// Close closes the underlying connection
+func (c *RedisConn) Close() error {
+	return c.conn.Close()
+}
+
+// RedisError is an error reply ("-ERR ...") sent by the server
+// The connection stays usable after it, unlike after network errors.
+type RedisError string
+
+func (e RedisError) Error() string { return string(e) }
+
+// RedisClient shares one connection between callers: commands are serialized and the
+// connection is re-dialed (outside the lock) after network errors
+type RedisClient struct {
+	opts RedisOptions
+
+	mu     sync.Mutex // Guards conn; never held while dialing
+	conn   *RedisConn
+	closed bool
+}
+
+// NewRedisClient connects to Redis (redis://[user:password@]host:6379[/db])
+func NewRedisClient(rawURL string) (*RedisClient, error) {
+	opts, err := ParseRedisURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := DialRedis(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisClient{opts: opts, conn: conn}, nil
+}
+
+// Options returns the connection settings, e.g. to dial dedicated connections for blocking commands
+func (c *RedisClient) Options() RedisOptions {
+	return c.opts
+}
+
+// Do runs a command on the shared connection, reconnecting first if the last command failed
+func (c *RedisClient) Do(args ...string) (interface{}, error) {
+	if err := c.ensureConn(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil, errors.New("redis not connected")
+	}
+	reply, err := c.conn.Do(args...)
+	var replyErr RedisError
+	if err != nil && !errors.As(err, &replyErr) {
+		// Drop the connection; the next command reconnects
+		c.conn.Close()
+		c.conn = nil
+	}
+	return reply, err
+}
+
+// ensureConn dials a connection if there is none and swaps it in
+func (c *RedisClient) ensureConn() error {
+	c.mu.Lock()
+	connected := c.conn != nil
+	c.mu.Unlock()
+	if connected {
+		return nil
+	}
+
+	conn, err := DialRedis(c.opts)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil || c.closed {
+		conn.Close() // Another caller reconnected first, or the client was closed
+		return nil
+	}
+	c.conn = conn
+	return nil
+}
+
+// Close closes the shared connection
+func (c *RedisClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
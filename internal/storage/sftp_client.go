@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -14,6 +15,32 @@ import (
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
+// ProgressFunc receives the transferred and total bytes of an upload or download
+type ProgressFunc func(transferred, total int64)
+
+// progressReader reports cumulative bytes read to a ProgressFunc
+type progressReader struct {
+	ctx         context.Context // Optional, stops the copy when cancelled
+	r           io.Reader
+	total       int64
+	transferred int64
+	onProgress  ProgressFunc
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	if r.ctx != nil {
+		if err := r.ctx.Err(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.r.Read(b)
+	if n > 0 && r.onProgress != nil {
+		r.transferred += int64(n)
+		r.onProgress(r.transferred, r.total)
+	}
+	return n, err
+}
+
 // SFTPClient handles SFTP operations for Hetzner Storage Box
 type SFTPClient struct {
 	config      *config.Config
@@ -138,6 +165,17 @@ func (c *SFTPClient) Close() error {
 	return nil
 }
 
+// Ping connects to the Storage Box if needed and checks that the base path is accessible
+func (c *SFTPClient) Ping() error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	if _, err := c.sftpClient.Stat(c.config.StorageBoxPath); err != nil {
+		return fmt.Errorf("failed to access %s: %w", c.config.StorageBoxPath, err)
+	}
+	return nil
+}
+
 // ensureBasePath creates the base path if it doesn't exist
 func (c *SFTPClient) ensureBasePath() error {
 	return c.sftpClient.MkdirAll(c.config.StorageBoxPath)
@@ -148,6 +186,17 @@ func (c *SFTPClient) ensureBasePath() error {
 // remoteName: filename on Storage Box (will be placed in StorageBoxPath)
 // Returns: full remote path
 func (c *SFTPClient) Upload(localPath, remoteName string) (string, error) {
+	return c.UploadWithProgress(localPath, remoteName, nil)
+}
+
+// UploadWithProgress uploads a local file and reports transferred bytes to onProgress (may be nil)
+func (c *SFTPClient) UploadWithProgress(localPath, remoteName string, onProgress ProgressFunc) (string, error) {
+	return c.UploadContext(context.Background(), localPath, remoteName, onProgress)
+}
+
+// UploadContext is UploadWithProgress that stops when ctx is cancelled
+// The partially written remote file is removed before the error is returned.
+func (c *SFTPClient) UploadContext(ctx context.Context, localPath, remoteName string, onProgress ProgressFunc) (string, error) {
 	if err := c.ensureConnected(); err != nil {
 		return "", fmt.Errorf("failed to ensure connection: %w", err)
 	}
@@ -184,8 +233,18 @@ func (c *SFTPClient) Upload(localPath, remoteName string) (string, error) {
 
 	// Copy with progress tracking
 	startTime := time.Now()
-	written, err := io.Copy(remoteFile, localFile)
+	written, err := io.Copy(remoteFile, &progressReader{ctx: ctx, r: localFile, total: fileSize, onProgress: onProgress})
 	if err != nil {
+		if ctx.Err() != nil {
+			remoteFile.Close()
+			if removeErr := c.sftpClient.Remove(remotePath); removeErr != nil {
+				logger.Warn("SFTP: Failed to remove partial upload", map[string]interface{}{
+					"remote_path": remotePath,
+					"error":       removeErr.Error(),
+				})
+			}
+			return "", fmt.Errorf("upload cancelled: %w", ctx.Err())
+		}
 		return "", fmt.Errorf("failed to upload file: %w", err)
 	}
 
@@ -206,6 +265,11 @@ func (c *SFTPClient) Upload(localPath, remoteName string) (string, error) {
 // remotePath: full path on Storage Box (e.g., /minecraft-archives/server-id.tar.gz)
 // localPath: absolute path where to save the file
 func (c *SFTPClient) Download(remotePath, localPath string) error {
+	return c.DownloadWithProgress(remotePath, localPath, nil)
+}
+
+// DownloadWithProgress downloads a file and reports transferred bytes to onProgress (may be nil)
+func (c *SFTPClient) DownloadWithProgress(remotePath, localPath string, onProgress ProgressFunc) error {
 	if err := c.ensureConnected(); err != nil {
 		return fmt.Errorf("failed to ensure connection: %w", err)
 	}
@@ -243,7 +307,7 @@ func (c *SFTPClient) Download(remotePath, localPath string) error {
 
 	// Copy with progress tracking
 	startTime := time.Now()
-	written, err := io.Copy(localFile, remoteFile)
+	written, err := io.Copy(localFile, &progressReader{r: remoteFile, total: fileSize, onProgress: onProgress})
 	if err != nil {
 		return fmt.Errorf("failed to download file: %w", err)
 	}
@@ -261,6 +325,25 @@ func (c *SFTPClient) Download(remotePath, localPath string) error {
 	return nil
 }
 
+// Open opens a file on the Storage Box for streaming (e.g. straight into an HTTP response)
+// The caller must close the returned reader; size is the file size in bytes.
+func (c *SFTPClient) Open(remotePath string) (io.ReadCloser, int64, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, 0, fmt.Errorf("failed to ensure connection: %w", err)
+	}
+
+	remoteFile, err := c.sftpClient.Open(remotePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open remote file: %w", err)
+	}
+	info, err := remoteFile.Stat()
+	if err != nil {
+		remoteFile.Close()
+		return nil, 0, fmt.Errorf("failed to stat remote file: %w", err)
+	}
+	return remoteFile, info.Size(), nil
+}
+
 // Delete deletes a file from Storage Box
 func (c *SFTPClient) Delete(remotePath string) error {
 	if err := c.ensureConnected(); err != nil {
@@ -2,6 +2,7 @@ package velocity
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,7 +20,9 @@ type VelocityMonitor struct {
 	conductor    ConductorInterface // Interface to avoid circular dependency
 	checkInterval time.Duration
 	retryInterval time.Duration
+	reconcileInterval time.Duration // Periodic sync of the registrations (drops and restarts between checks)
 	isHealthy    bool
+	proxyStartedAt int64 // Start time reported by the last health check, a change means the proxy restarted
 	healthyMu    sync.RWMutex
 	stopChan     chan struct{}
 	wg           sync.WaitGroup
@@ -47,6 +50,7 @@ func NewVelocityMonitor(
 		cfg:           cfg,
 		checkInterval: 30 * time.Second, // Check every 30 seconds
 		retryInterval: 5 * time.Second,   // Retry failed checks every 5 seconds
+		reconcileInterval: 5 * time.Minute,
 		isHealthy:     false,
 		stopChan:      make(chan struct{}),
 	}
@@ -81,19 +85,23 @@ func (m *VelocityMonitor) IsHealthy() bool {
 }
 
 // setHealthStatus updates health status thread-safely
-func (m *VelocityMonitor) setHealthStatus(healthy bool) {
+// Returns whether Velocity needs its registrations replayed: it just recovered (unhealthy → healthy)
+// or reports a new start time, i.e. it restarted between two checks.
+func (m *VelocityMonitor) setHealthStatus(healthy bool, startedAt int64) bool {
 	m.healthyMu.Lock()
 	defer m.healthyMu.Unlock()
 
-	// Detect state transition (unhealthy → healthy)
 	wasUnhealthy := !m.isHealthy
 	m.isHealthy = healthy
+	if !healthy {
+		return false
+	}
 
-	// If Velocity just recovered, trigger state sync
-	if wasUnhealthy && healthy {
-		logger.Info("Velocity recovery detected - triggering state sync", nil)
-		go m.syncServerState()
+	restarted := startedAt != 0 && m.proxyStartedAt != 0 && startedAt != m.proxyStartedAt
+	if startedAt != 0 {
+		m.proxyStartedAt = startedAt
 	}
+	return wasUnhealthy || restarted
 }
 
 // healthCheckLoop runs periodic health checks
@@ -102,6 +110,8 @@ func (m *VelocityMonitor) healthCheckLoop() {
 
 	ticker := time.NewTicker(m.checkInterval)
 	defer ticker.Stop()
+	reconcileTicker := time.NewTicker(m.reconcileInterval)
+	defer reconcileTicker.Stop()
 
 	// Initial health check
 	m.performHealthCheck()
@@ -112,6 +122,11 @@ func (m *VelocityMonitor) healthCheckLoop() {
 			return
 		case <-ticker.C:
 			m.performHealthCheck()
+		case <-reconcileTicker.C:
+			// Registrations lost to a request that failed even after retries are restored here
+			if m.IsHealthy() {
+				m.syncServerState()
+			}
 		}
 	}
 }
@@ -120,7 +135,7 @@ func (m *VelocityMonitor) healthCheckLoop() {
 func (m *VelocityMonitor) performHealthCheck() {
 	health, err := m.client.HealthCheck()
 	if err != nil {
-		m.setHealthStatus(false)
+		m.setHealthStatus(false, 0)
 		logger.Warn("Velocity health check failed", map[string]interface{}{
 			"error": err.Error(),
 		})
@@ -134,7 +149,14 @@ func (m *VelocityMonitor) performHealthCheck() {
 		return
 	}
 
-	m.setHealthStatus(true)
+	if m.setHealthStatus(true, health.StartedAt) {
+		// Velocity keeps registrations in memory only, so a restarted proxy knows no servers
+		logger.Info("Velocity recovery detected - replaying server registrations", map[string]interface{}{
+			"started_at": health.StartedAt,
+			"servers":    health.ServersCount,
+		})
+		go m.syncServerState()
+	}
 	logger.Debug("Velocity health check passed", map[string]interface{}{
 		"version":        health.Version,
 		"servers":        health.ServersCount,
@@ -142,25 +164,32 @@ func (m *VelocityMonitor) performHealthCheck() {
 	})
 }
 
-// syncServerState re-registers all running servers with Velocity
+// syncServerState pushes the registrations of the database to Velocity: running servers are
+// registered (or re-registered with their current address) and registrations of stopped,
+// archived, suspended or deleted servers are removed
 func (m *VelocityMonitor) syncServerState() {
 	if m.conductor == nil {
 		logger.Warn("Cannot sync Velocity state: Conductor not set", nil)
 		return
 	}
 
-	logger.Info("Syncing server state with Velocity after recovery...", nil)
-
-	runningServers, err := m.serverRepo.FindByStatus(string(models.StatusRunning))
+	servers, err := m.serverRepo.FindAll()
 	if err != nil {
-		logger.Error("Failed to find running servers for Velocity sync", err, nil)
+		logger.Error("Failed to find servers for Velocity sync", err, nil)
 		return
 	}
 
-	registered := 0
-	failed := 0
-
-	for _, server := range runningServers {
+	statuses := make(map[string]models.ServerStatus, len(servers))
+	var expected []ServerRegistration
+	var keep []string
+	for _, server := range servers {
+		statuses[server.ID] = server.Status
+		if server.Status != models.StatusRunning {
+			if !registrationStale(server.Status) {
+				keep = append(keep, "mc-"+server.ID)
+			}
+			continue
+		}
 		if server.NodeID == "" {
 			logger.Warn("Skipping server without node assignment", map[string]interface{}{
 				"server_id": server.ID,
@@ -168,8 +197,6 @@ func (m *VelocityMonitor) syncServerState() {
 			continue
 		}
 
-		velocityServerName := "mc-" + server.ID
-
 		// Get node IP
 		var serverIP string
 		if server.NodeID == "local-node" {
@@ -182,28 +209,91 @@ func (m *VelocityMonitor) syncServerState() {
 					"node_id":   server.NodeID,
 					"error":     err.Error(),
 				})
-				failed++
 				continue
 			}
 			serverIP = remoteNode.GetIPAddress()
 		}
 
-		serverAddress := fmt.Sprintf("%s:%d", serverIP, server.Port)
+		expected = append(expected, ServerRegistration{
+			Name:    "mc-" + server.ID,
+			Address: fmt.Sprintf("%s:%d", serverIP, server.Port),
+		})
+	}
 
-		if err := m.client.RegisterServer(velocityServerName, serverAddress); err != nil {
-			logger.Warn("Failed to register server with Velocity", map[string]interface{}{
-				"server_id": server.ID,
-				"error":     err.Error(),
-			})
-			failed++
-		} else {
-			registered++
+	// Only platform servers are pruned, the lobby and other proxy servers are left alone. Sleeping
+	// servers stay registered (wake on join), starting ones are registered by the start itself.
+	response, err := m.client.SyncServers(ServerSyncRequest{
+		Prefix:   "mc-",
+		Servers:  expected,
+		Keep:     keep,
+		Fallback: m.cfg.VelocityFallbackLobby,
+	})
+	if err == nil {
+		logger.Info("Velocity state sync completed", map[string]interface{}{
+			"expected":     len(expected),
+			"registered":   response.Registered,
+			"updated":      response.Updated,
+			"unregistered": response.Unregistered,
+		})
+		return
+	}
+	if !IsUnsupported(err) {
+		logger.Warn("Velocity state sync failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Plugins without the sync endpoint are reconciled one registration at a time
+	isStale := func(name string) bool {
+		serverID, ok := strings.CutPrefix(name, "mc-")
+		if !ok {
+			return false
 		}
+		status, found := statuses[serverID]
+		return !found || registrationStale(status)
+	}
+
+	result, err := m.client.Reconcile(expected, isStale)
+	if err != nil {
+		logger.Warn("Velocity state sync failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
 	}
 
 	logger.Info("Velocity state sync completed", map[string]interface{}{
-		"total_running": len(runningServers),
-		"registered":    registered,
-		"failed":        failed,
+		"expected":      len(expected),
+		"missing":       len(result.Missing),
+		"wrong_address": len(result.WrongAddress),
+		"stale":         len(result.Stale),
+		"failed":        result.Failed,
+	})
+
+	m.syncFallbackLobby()
+}
+
+// registrationStale reports whether a server in this status must not be registered with Velocity
+func registrationStale(status models.ServerStatus) bool {
+	return status == models.StatusStopped || status == models.StatusArchived || status == models.StatusSuspended
+}
+
+// syncFallbackLobby tells Velocity which lobby catches players of crashed backends
+// Velocity keeps this in memory only, so it's pushed again after every proxy restart.
+func (m *VelocityMonitor) syncFallbackLobby() {
+	if m.cfg.VelocityFallbackLobby == "" {
+		return
+	}
+
+	if err := m.client.SetFallbackServer(m.cfg.VelocityFallbackLobby); err != nil {
+		logger.Warn("Failed to configure Velocity fallback lobby", map[string]interface{}{
+			"lobby": m.cfg.VelocityFallbackLobby,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Velocity fallback lobby configured", map[string]interface{}{
+		"lobby": m.cfg.VelocityFallbackLobby,
 	})
 }
@@ -3,9 +3,11 @@ package velocity
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/payperplay/hosting/pkg/logger"
@@ -18,9 +20,15 @@ import (
 // - Tier 1 (Control Plane): PayPerPlay API + DB (this code)
 // - Tier 2 (Proxy Layer): Velocity Proxy + RemoteAPI Plugin (target of this client)
 // - Tier 3 (Workload Layer): Minecraft servers on Cloud nodes
+//
+// Idempotent requests are retried with jittered backoff on connection errors, 5xx and 429, and a
+// circuit breaker fails fast while the proxy is down. With a secret, requests are HMAC-signed.
 type RemoteVelocityClient struct {
 	apiURL     string
 	httpClient *http.Client
+	secret     string // HMAC key shared with the RemoteAPI plugin, empty = unsigned
+	retry      RetryPolicy
+	breaker    *circuitBreaker
 }
 
 // ServerRegistration represents the payload for registering a server
@@ -38,9 +46,10 @@ type ServerListResponse struct {
 
 // VelocityServerInfo represents a registered server in Velocity
 type VelocityServerInfo struct {
-	Name    string `json:"name"`
-	Address string `json:"address"`
-	Players int    `json:"players"`
+	Name        string   `json:"name"`
+	Address     string   `json:"address"`
+	Players     int      `json:"players"`
+	PlayerNames []string `json:"player_names"` // Usernames of the connected players
 }
 
 // HealthCheckResponse represents the response from GET /health
@@ -49,43 +58,168 @@ type HealthCheckResponse struct {
 	Version      string `json:"version"`
 	ServersCount int    `json:"servers_count"`
 	PlayersOnline int   `json:"players_online"`
+	StartedAt    int64  `json:"started_at"` // Unix seconds the proxy started, changes with every restart (0 = old plugin)
+}
+
+// ServerSyncRequest is the full set of platform registrations pushed to PUT /api/servers
+type ServerSyncRequest struct {
+	Prefix   string               `json:"prefix"`   // Registrations with this prefix that are not listed (or kept) are removed
+	Servers  []ServerRegistration `json:"servers"`  // Registered, or re-registered on an address change
+	Keep     []string             `json:"keep"`     // Left as they are if registered (e.g. sleeping servers, wake on join)
+	Fallback string               `json:"fallback"` // Fallback lobby, empty = unchanged
+}
+
+// ServerSyncResponse represents the response from PUT /api/servers
+type ServerSyncResponse struct {
+	Status       string `json:"status"`
+	Registered   int    `json:"registered"`
+	Updated      int    `json:"updated"`
+	Unregistered int    `json:"unregistered"`
+	Unchanged    int    `json:"unchanged"`
+}
+
+// PlayerMoveResponse represents the response from the evacuate and return endpoints
+type PlayerMoveResponse struct {
+	Status string `json:"status"`
+	Server string `json:"server"`
+	Target string `json:"target"`
+	Moved  int    `json:"moved"`
 }
 
 // NewRemoteVelocityClient creates a new client for the Velocity Remote API
 func NewRemoteVelocityClient(apiURL string) *RemoteVelocityClient {
 	return &RemoteVelocityClient{
-		apiURL:     apiURL,
+		apiURL:     strings.TrimRight(apiURL, "/"),
 		httpClient: &http.Client{Timeout: 10 * time.Second},
+		retry:      DefaultRetryPolicy,
+		breaker:    newCircuitBreaker(5, 30*time.Second),
 	}
 }
 
-// RegisterServer registers a new backend server with Velocity proxy
-//
-// Example: RegisterServer("survival-1", "91.98.202.235:25566")
-func (c *RemoteVelocityClient) RegisterServer(name, address string) error {
-	payload := ServerRegistration{
-		Name:    name,
-		Address: address,
+// SetSigningSecret signs all requests with secret (VELOCITY_API_SECRET)
+func (c *RemoteVelocityClient) SetSigningSecret(secret string) {
+	c.secret = secret
+}
+
+// CircuitState returns the state of the circuit breaker (closed, open or half_open)
+func (c *RemoteVelocityClient) CircuitState() string {
+	return c.breaker.State()
+}
+
+// statusError is a response with an unexpected status code
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d, body: %s", e.status, e.body)
+}
+
+// retryable reports whether a failed attempt may succeed when repeated
+func retryable(err error) bool {
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.status >= 500 || status.status == http.StatusTooManyRequests
+	}
+	return true // Connection errors and timeouts
+}
+
+// do sends a request and decodes the response into out (if not nil)
+// Idempotent requests are retried; only failures that point at the proxy (not at the request) trip the breaker.
+func (c *RemoteVelocityClient) do(method, path string, payload, out interface{}, idempotent bool, okStatus ...int) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+	}
+	if len(okStatus) == 0 {
+		okStatus = []int{http.StatusOK}
+	}
+
+	attempts := 1
+	if idempotent && c.retry.Attempts > 1 {
+		attempts = c.retry.Attempts
+	}
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(c.retry.delay(attempt - 1))
+		}
+		if err = c.breaker.allow(time.Now()); err != nil {
+			return err
+		}
+		err = c.attempt(method, path, body, out, okStatus)
+		c.breaker.record(err == nil || !retryable(err), time.Now())
+		if err == nil || !retryable(err) {
+			return err
+		}
+	}
+	if attempts > 1 {
+		logger.Warn("Velocity API request failed after retries", map[string]interface{}{
+			"method":   method,
+			"path":     path,
+			"attempts": attempts,
+			"error":    err.Error(),
+		})
 	}
+	return err
+}
 
-	jsonData, err := json.Marshal(payload)
+// attempt sends a request once
+func (c *RemoteVelocityClient) attempt(method, path string, body []byte, out interface{}, okStatus []int) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, c.apiURL+path, reader)
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(TimestampHeader, fmt.Sprint(timestamp))
+		req.Header.Set(SignatureHeader, Sign(c.secret, timestamp, method, path, body))
 	}
 
-	resp, err := c.httpClient.Post(
-		c.apiURL+"/api/servers",
-		"application/json",
-		bytes.NewBuffer(jsonData),
-	)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	ok := false
+	for _, status := range okStatus {
+		ok = ok || resp.StatusCode == status
+	}
+	if !ok {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &statusError{status: resp.StatusCode, body: string(respBody)}
+	}
+	if out != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// RegisterServer registers a new backend server with Velocity proxy
+// Registering an existing name replaces its address, so the call is retried safely.
+//
+// Example: RegisterServer("survival-1", "91.98.202.235:25566")
+func (c *RemoteVelocityClient) RegisterServer(name, address string) error {
+	payload := ServerRegistration{
+		Name:    name,
+		Address: address,
+	}
+	if err := c.do(http.MethodPost, "/api/servers", payload, nil, true); err != nil {
+		return err
 	}
 
 	logger.Info("Server registered with Velocity", map[string]interface{}{
@@ -98,24 +232,8 @@ func (c *RemoteVelocityClient) RegisterServer(name, address string) error {
 
 // UnregisterServer removes a backend server from Velocity proxy
 func (c *RemoteVelocityClient) UnregisterServer(name string) error {
-	req, err := http.NewRequest(
-		http.MethodDelete,
-		fmt.Sprintf("%s/api/servers/%s", c.apiURL, name),
-		nil,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	if err := c.do(http.MethodDelete, "/api/servers/"+name, nil, nil, true, http.StatusOK, http.StatusNotFound); err != nil {
+		return err
 	}
 
 	logger.Info("Server unregistered from Velocity", map[string]interface{}{
@@ -127,106 +245,214 @@ func (c *RemoteVelocityClient) UnregisterServer(name string) error {
 
 // ListServers returns all registered servers from Velocity
 func (c *RemoteVelocityClient) ListServers() ([]VelocityServerInfo, error) {
-	resp, err := c.httpClient.Get(c.apiURL + "/api/servers")
-	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
-	}
-
 	var response ServerListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.do(http.MethodGet, "/api/servers", nil, &response, true); err != nil {
+		return nil, err
 	}
-
 	return response.Servers, nil
 }
 
 // GetPlayerCount returns the player count for a specific server
 func (c *RemoteVelocityClient) GetPlayerCount(serverName string) (int, error) {
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s/api/players/%s", c.apiURL, serverName))
-	if err != nil {
-		return 0, fmt.Errorf("HTTP request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
-		return 0, fmt.Errorf("server not found: %s", serverName)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
-	}
-
 	var response struct {
 		Status  string `json:"status"`
 		Server  string `json:"server"`
 		Players int    `json:"players"`
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return 0, fmt.Errorf("failed to decode response: %w", err)
+	err := c.do(http.MethodGet, "/api/players/"+serverName, nil, &response, true)
+	var status *statusError
+	if errors.As(err, &status) && status.status == http.StatusNotFound {
+		return 0, fmt.Errorf("server not found: %s", serverName)
+	}
+	if err != nil {
+		return 0, err
 	}
 
 	return response.Players, nil
 }
 
-// HealthCheck pings the Velocity Remote API to verify connectivity
-func (c *RemoteVelocityClient) HealthCheck() (*HealthCheckResponse, error) {
-	resp, err := c.httpClient.Get(c.apiURL + "/health")
-	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+// SetFallbackServer configures the lobby Velocity sends players to when their backend goes down
+// Players kicked by a crashing backend land there instead of on the login screen. Empty disables the fallback.
+func (c *RemoteVelocityClient) SetFallbackServer(name string) error {
+	return c.do(http.MethodPut, "/api/fallback", map[string]string{"server": name}, nil, true)
+}
+
+// EvacuateServer moves all players of a backend server to the fallback lobby
+// Velocity remembers the moved players so ReturnPlayers can send them back. Returns the number of players moved.
+func (c *RemoteVelocityClient) EvacuateServer(serverName string) (int, error) {
+	return c.movePlayers(fmt.Sprintf("/api/servers/%s/evacuate", serverName))
+}
+
+// ReturnPlayers sends players that were moved off a backend server (evacuated or kicked to the lobby) back to it
+// Returns the number of players moved.
+func (c *RemoteVelocityClient) ReturnPlayers(serverName string) (int, error) {
+	return c.movePlayers(fmt.Sprintf("/api/servers/%s/return", serverName))
+}
+
+// NotifyPlayers sends a chat message with a click-to-rejoin hint for a backend server to the given players
+// Only players online on the network but not on that server are messaged. Returns the number of players reached.
+// Not retried: a request that timed out may have been delivered.
+func (c *RemoteVelocityClient) NotifyPlayers(serverName string, players []string, message string) (int, error) {
+	payload := map[string]interface{}{
+		"players": players,
+		"message": message,
 	}
-	defer resp.Body.Close()
+	var response struct {
+		Delivered int `json:"delivered"`
+	}
+	if err := c.do(http.MethodPost, fmt.Sprintf("/api/servers/%s/notify", serverName), payload, &response, false); err != nil {
+		return 0, err
+	}
+
+	return response.Delivered, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+// movePlayers calls one of the player move endpoints (moving players is safe to repeat)
+func (c *RemoteVelocityClient) movePlayers(path string) (int, error) {
+	var response PlayerMoveResponse
+	if err := c.do(http.MethodPost, path, nil, &response, true); err != nil {
+		return 0, err
 	}
 
+	return response.Moved, nil
+}
+
+// HealthCheck pings the Velocity Remote API to verify connectivity
+// Not retried and not subject to the breaker, so the monitor always sees the current state;
+// a passing check closes the breaker right away.
+func (c *RemoteVelocityClient) HealthCheck() (*HealthCheckResponse, error) {
 	var health HealthCheckResponse
-	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.attempt(http.MethodGet, "/health", nil, &health, []int{http.StatusOK}); err != nil {
+		return nil, err
 	}
+	c.breaker.record(true, time.Now())
 
 	return &health, nil
 }
 
-// SyncServerState syncs the current server state with Velocity
-// This should be called after API restarts to ensure Velocity knows about running servers
-func (c *RemoteVelocityClient) SyncServerState(servers []ServerRegistration) error {
-	// Get current Velocity servers
-	velocityServers, err := c.ListServers()
+// ReconcileResult is the difference between the expected and the actual Velocity registrations
+// and what was done about it
+type ReconcileResult struct {
+	Missing      []string `json:"missing"`       // Expected but not registered
+	WrongAddress []string `json:"wrong_address"` // Registered with another address
+	Stale        []string `json:"stale"`         // Registered but should not be
+	Fixed        int      `json:"fixed"`
+	Failed       int      `json:"failed"`
+}
+
+// Reconcile registers the expected servers that are missing or have a wrong address and
+// unregisters registrations that isStale reports (registrations not managed by the platform,
+// like the lobby, must be left alone by isStale)
+func (c *RemoteVelocityClient) Reconcile(expected []ServerRegistration, isStale func(name string) bool) (*ReconcileResult, error) {
+	actual, err := c.ListServers()
 	if err != nil {
-		return fmt.Errorf("failed to list Velocity servers: %w", err)
+		return nil, fmt.Errorf("failed to list Velocity servers: %w", err)
+	}
+	result := diffRegistrations(expected, actual, isStale)
+
+	fix := func(name string, err error) {
+		if err != nil {
+			result.Failed++
+			logger.Warn("Failed to reconcile Velocity registration", map[string]interface{}{
+				"name":  name,
+				"error": err.Error(),
+			})
+			return
+		}
+		result.Fixed++
+	}
+	addresses := make(map[string]string, len(expected))
+	for _, server := range expected {
+		addresses[server.Name] = server.Address
+	}
+	for _, name := range append(append([]string{}, result.Missing...), result.WrongAddress...) {
+		fix(name, c.RegisterServer(name, addresses[name]))
+	}
+	for _, name := range result.Stale {
+		fix(name, c.UnregisterServer(name))
 	}
 
-	// Build map of existing servers
-	existingServers := make(map[string]bool)
-	for _, vs := range velocityServers {
-		existingServers[vs.Name] = true
+	if len(result.Missing)+len(result.WrongAddress)+len(result.Stale) > 0 {
+		logger.Info("Velocity registrations reconciled", map[string]interface{}{
+			"missing":       len(result.Missing),
+			"wrong_address": len(result.WrongAddress),
+			"stale":         len(result.Stale),
+			"fixed":         result.Fixed,
+			"failed":        result.Failed,
+		})
 	}
+	return result, nil
+}
 
-	// Register missing servers
-	for _, server := range servers {
-		if !existingServers[server.Name] {
-			if err := c.RegisterServer(server.Name, server.Address); err != nil {
-				logger.Warn("Failed to sync server with Velocity", map[string]interface{}{
-					"name":  server.Name,
-					"error": err.Error(),
-				})
-			}
+// diffRegistrations compares expected and actual registrations
+func diffRegistrations(expected []ServerRegistration, actual []VelocityServerInfo, isStale func(name string) bool) *ReconcileResult {
+	registered := make(map[string]string, len(actual))
+	for _, info := range actual {
+		registered[info.Name] = info.Address
+	}
+	wanted := make(map[string]bool, len(expected))
+	result := &ReconcileResult{Missing: []string{}, WrongAddress: []string{}, Stale: []string{}}
+	for _, server := range expected {
+		wanted[server.Name] = true
+		address, ok := registered[server.Name]
+		switch {
+		case !ok:
+			result.Missing = append(result.Missing, server.Name)
+		case address != server.Address:
+			result.WrongAddress = append(result.WrongAddress, server.Name)
 		}
 	}
+	for _, info := range actual {
+		if !wanted[info.Name] && isStale != nil && isStale(info.Name) {
+			result.Stale = append(result.Stale, info.Name)
+		}
+	}
+	return result
+}
 
-	logger.Info("Server state synced with Velocity", map[string]interface{}{
-		"synced_count": len(servers),
-	})
+// SyncServers replaces the platform registrations of the proxy with one request
+// Used to replay the registrations after a proxy restart; the request is idempotent.
+func (c *RemoteVelocityClient) SyncServers(request ServerSyncRequest) (*ServerSyncResponse, error) {
+	if request.Servers == nil {
+		request.Servers = []ServerRegistration{}
+	}
+	var response ServerSyncResponse
+	if err := c.do(http.MethodPut, "/api/servers", request, &response, true); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
 
-	return nil
+// IsUnsupported reports whether an error means the plugin doesn't know the endpoint (older plugin version)
+func IsUnsupported(err error) bool {
+	var status *statusError
+	return errors.As(err, &status) && (status.status == http.StatusNotFound || status.status == http.StatusMethodNotAllowed)
+}
+
+// ProxyBan is a ban enforced by the proxy
+type ProxyBan struct {
+	UUID      string   `json:"uuid"`
+	Name      string   `json:"name"`
+	Reason    string   `json:"reason"`
+	ExpiresAt int64    `json:"expires_at"` // Unix seconds, 0 = permanent
+	Servers   []string `json:"servers"`    // Servers the player may not join, empty = the whole network
+}
+
+// BanSyncResponse represents the response from PUT /api/bans
+type BanSyncResponse struct {
+	Status string `json:"status"`
+	Bans   int    `json:"bans"`
+	Kicked int    `json:"kicked"` // Banned players that were online
+}
+
+// SyncBans replaces the bans of the proxy; banned players that are online are disconnected
+func (c *RemoteVelocityClient) SyncBans(bans []ProxyBan) (*BanSyncResponse, error) {
+	if bans == nil {
+		bans = []ProxyBan{}
+	}
+	var response BanSyncResponse
+	if err := c.do(http.MethodPut, "/api/bans", map[string]interface{}{"bans": bans}, &response, true); err != nil {
+		return nil, err
+	}
+	return &response, nil
 }
@@ -0,0 +1,130 @@
+package velocity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Request signing headers checked by the RemoteAPI plugin when it has a secret configured
+const (
+	SignatureHeader = "X-PayPerPlay-Signature" // "v1=" + hex HMAC-SHA256 of SignaturePayload
+	TimestampHeader = "X-PayPerPlay-Timestamp" // Unix seconds; the plugin rejects old timestamps (replays)
+)
+
+// ErrCircuitOpen is returned without calling Velocity while the circuit breaker is open
+var ErrCircuitOpen = errors.New("velocity API circuit breaker open")
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"    // Requests pass
+	CircuitOpen     = "open"      // Requests fail fast until the cooldown is over
+	CircuitHalfOpen = "half_open" // One trial request decides whether to close or open again
+)
+
+// RetryPolicy is how often and how long apart failed idempotent requests are retried
+type RetryPolicy struct {
+	Attempts  int           // Including the first attempt
+	BaseDelay time.Duration // Delay before the first retry, doubled for each further one
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy rides out short proxy hiccups (restarts, dropped connections) within a few seconds
+var DefaultRetryPolicy = RetryPolicy{Attempts: 4, BaseDelay: 250 * time.Millisecond, MaxDelay: 3 * time.Second}
+
+// delay returns the wait before retry n (1 = first retry) with full jitter, so that many callers
+// retrying at once don't hit the proxy in lockstep
+func (p RetryPolicy) delay(n int) time.Duration {
+	ceiling := p.BaseDelay << (n - 1)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// circuitBreaker stops calling Velocity after consecutive failures and lets a single trial
+// request through once the cooldown is over
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	trial    bool // The half-open trial request is in flight
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, state: CircuitClosed}
+}
+
+// allow reports whether a request may be sent now
+func (b *circuitBreaker) allow(now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		b.trial = true
+		return nil
+	case CircuitHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record counts the outcome of a request that allow let through
+func (b *circuitBreaker) record(success bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if success {
+		b.state = CircuitClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state = CircuitOpen
+		b.openedAt = now
+	}
+}
+
+// State returns the current state of the breaker
+func (b *circuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// SignaturePayload is the string a request signature covers: timestamp, method, path with query and body
+func SignaturePayload(timestamp int64, method, path string, body []byte) []byte {
+	payload := strconv.FormatInt(timestamp, 10) + "." + method + "." + path + "."
+	return append([]byte(payload), body...)
+}
+
+// Sign returns the signature header value of a request
+func Sign(secret string, timestamp int64, method, path string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(SignaturePayload(timestamp, method, path, body))
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/payperplay/hosting/internal/events"
 	"github.com/payperplay/hosting/pkg/logger"
 )
 
@@ -24,6 +25,9 @@ type Hub struct {
 
 	// Mutex for thread-safe operations
 	mu sync.RWMutex
+
+	// Optional: shares broadcasts with the clients of other API instances
+	relay *events.Relay
 }
 
 // NewHub creates a new Hub instance
@@ -36,6 +40,22 @@ func NewHub() *Hub {
 	}
 }
 
+// SetTransport shares broadcasts with other API instances
+// Messages broadcast by other instances are delivered to this hub's clients as-is.
+func (h *Hub) SetTransport(transport events.Transport) error {
+	relay, err := events.NewRelay(transport, events.SubjectWebSocket, func(payload []byte) {
+		h.broadcast <- payload
+	})
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	h.relay = relay
+	h.mu.Unlock()
+	return nil
+}
+
 // Run starts the hub
 func (h *Hub) Run() {
 	for {
@@ -89,6 +109,11 @@ func (h *Hub) Broadcast(messageType string, data interface{}) {
 	}
 
 	h.broadcast <- jsonData
+
+	h.mu.RLock()
+	relay := h.relay
+	h.mu.RUnlock()
+	relay.Publish(jsonData)
 }
 
 // Register adds a client to the hub
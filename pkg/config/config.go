@@ -4,15 +4,29 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	// Application
-	AppName string
-	Debug   bool
-	Port    string
+	AppName         string
+	Debug           bool
+	Port            string
+	ShutdownTimeout string // How long in-flight requests may take to finish on SIGTERM (default: "30s")
+
+	// Live reload of the settings in Reloadable (file watch, SIGHUP or POST /api/admin/config/reload)
+	ConfigFile           string // .env file checked for changes (default: ".env")
+	ConfigReloadInterval string // How often the file is checked (default: "10s", "0" = only SIGHUP and the admin endpoint)
+
+	// Secrets store for SSH_PRIVATE_KEY, HETZNER_CLOUD_TOKEN and INFLUXDB_TOKEN (values override the env vars)
+	SecretsProvider        string // "env" (default, plain env vars), "vault" or "sops"
+	SecretsRefreshInterval string // How often rotated secrets are picked up (default: "5m")
+	VaultAddr              string // e.g. https://vault.internal:8200
+	VaultToken             string
+	VaultSecretPath        string // API path of the secret, e.g. "secret/data/payperplay" (KV v2) or "kv/payperplay" (KV v1)
+	SOPSFile               string // SOPS-encrypted dotenv file, decrypted with the sops binary (e.g. ./secrets.enc.env)
 
 	// Logging
 	LogLevel string
@@ -22,11 +36,35 @@ type Config struct {
 	DatabasePath string
 	DatabaseType string
 	DatabaseURL  string
+	// Schema migrations: apply pending ones at startup (false = refuse to start until `migrate up` ran)
+	DatabaseAutoMigrate bool
+	// Start even if the schema is newer than this build knows (e.g. during a rollback)
+	DatabaseAllowNewerSchema bool
+	// Read replicas for lists and analytics (comma-separated URLs, empty = everything on the primary)
+	DatabaseReplicaURLs   string
+	DatabaseReplicaMaxLag string // Replicas further behind are skipped (default: "30s")
+	// History archival: usage logs, system and billing events older than the retention are exported
+	// to the Storage Box (local UsageArchivePath without it) as monthly CSV files and deleted
+	UsageArchiveRetentionDays int    // 0 = keep everything (default: 400, longer than the billing breakdown range)
+	UsageArchiveInterval      string // How often old months are archived (default: "24h")
+	UsageArchivePath          string // Staging directory, and the archive itself without Storage Box
+	// Cache for hot server lookups (FindByID/FindByOwner on WebSocket reconnects and monitoring ticks)
+	CacheStore    string // "" (off), "memory" (per instance) or "redis" (shared between instances)
+	CacheRedisURL string // e.g. redis://:password@redis:6379/2
+	CacheTTL      string // Upper bound for stale entries if an invalidation is lost (default: "15s")
+	// Drift detection between database, Docker and Velocity
+	DriftCheckInterval string // How often the state is compared, "0" = only via the API (default: "5m")
+	DriftAutoHeal      bool   // Heal drift found twice in a row, otherwise only report and alert (default: true)
 
 	// Authentication
 	JWTSecret string
 	BaseURL   string // Base URL for OAuth callbacks (e.g., https://yourdomain.com)
 
+	// Email (Resend in production, mock sender without an API key)
+	FrontendURL  string // Base URL of links in emails (default: BASE_URL)
+	ResendAPIKey string // Empty = emails are only logged and stored (MockEmailSender)
+	EmailFrom    string // Sender address, e.g. "PayPerPlay <noreply@payperplay.host>"
+
 	// OAuth Providers
 	DiscordClientID     string
 	DiscordClientSecret string
@@ -43,27 +81,159 @@ type Config struct {
 	MCPortEnd           int
 	ControlPlaneIP      string // Public IP address of Control Plane for Velocity to connect to Minecraft servers
 
+	// Cold-Start Optimization (JVM class-data sharing)
+	AppCDSEnabled     bool   // Persist a dynamic AppCDS archive in the server volume so repeat starts boot faster (needs Java 19+ images)
+	AppCDSArchiveName string // Archive file name inside /data (default: .payperplay-appcds.jsa)
+
+	// Heap Dumps (opt-in OOM diagnostics for modded servers)
+	HeapDumpStoragePath   string // Per-server heap dump area, outside the server volume so dumps don't end up in backups
+	HeapDumpQuotaMB       int    // Max total size of stored dumps per server; oldest dumps are pruned first
+	HeapDumpRetentionDays int    // Dumps older than this are pruned (0 = keep until quota)
+
+	// Container CPU Limits (fair share between servers on a node)
+	ContainerCPUSharesPerGB int     // CPU weight per GB of booked RAM for servers without their own (0 = docker default 1024)
+	ContainerCPULimitCores  float64 // CPU cap of servers without their own, in cores (0 = unlimited)
+
+	// Release Channels (how early servers receive managed changes)
+	MinecraftImageStable string // Server image for the stable channel (default)
+	MinecraftImageBeta   string // Server image for the beta channel (gets image updates first)
+	PluginUpdateSoakDays int    // Stable servers auto-update plugins only to stable versions released at least this long ago
+
 	// Billing rates (EUR/hour)
 	Rate2GB  float64
 	Rate4GB  float64
 	Rate8GB  float64
 	Rate16GB float64
 
+	// Stripe (metered usage invoicing)
+	StripeEnabled           bool
+	StripeSecretKey         string
+	StripeWebhookSecret     string
+	StripeMeteredPriceID    string // Metered price, 1 unit = 1 euro cent of usage
+	StripeMaxFailedPayments int    // Suspend servers after this many failed payments in a row
+	StripeUsageSyncInterval string // How often closed usage sessions are reported (e.g., "15m")
+
+	// Prepaid wallet (credit balance)
+	WalletEnabled         bool
+	WalletChargeInterval  string  // How often running usage is deducted from balances (e.g., "1m")
+	WalletLowBalanceEUR   float64 // Warn players/owners below this balance
+	WalletMinStartMinutes int     // Balance must cover this many minutes of runtime to start a server
+	WalletMinTopUpEUR     float64 // Smallest credit purchase
+
+	// Invoice documents (PDF/CSV, issued monthly for non-Stripe customers)
+	InvoiceEnabled       bool
+	InvoiceNumberPrefix  string
+	InvoiceSellerName    string
+	InvoiceSellerAddress string // Multiple lines separated by "\n"
+	InvoiceSellerCountry string // ISO 3166-1 alpha-2, determines domestic VAT
+	InvoiceSellerVATID   string
+
 	// InfluxDB (Time-Series Event Storage)
-	InfluxDBURL    string
-	InfluxDBToken  string
-	InfluxDBOrg    string
-	InfluxDBBucket string
+	InfluxDBURL           string
+	InfluxDBToken         string
+	InfluxDBOrg           string
+	InfluxDBBucket        string
+	InfluxDBMetricsBucket string // Per-container resource metrics (empty = InfluxDBBucket)
+
+	// OpenTelemetry tracing (HTTP requests -> services -> Docker/SSH calls)
+	OTLPEndpoint       string  // OTLP/HTTP collector, e.g. http://otel-collector:4318 ("" = tracing off)
+	OTELServiceName    string  // service.name of the spans (default: "payperplay-api")
+	TracingSampleRatio float64 // Share of new traces that are recorded, 0-1 (default: 1)
+
+	// Event Transport (multi-instance API: events, WebSocket broadcasts)
+	EventTransport    string // "" (in-process only), "nats" or "redis" (Redis Streams)
+	EventTransportURL string // e.g. nats://nats:4222 or redis://:password@redis:6379/0
+
+	// API rate limits (requests per minute and burst per client: API key, user or IP; 0 per minute = off)
+	RateLimitStore              string // "memory" (per instance) or "redis" (shared between instances)
+	RateLimitRedisURL           string // e.g. redis://:password@redis:6379/1
+	RateLimitGlobalPerMinute    int    // All requests, per IP (default: 300)
+	RateLimitGlobalBurst        int
+	RateLimitAPIPerMinute       int // /api requests, per API key or user (default: 120)
+	RateLimitAPIBurst           int
+	RateLimitAuthPerMinute      int // Login, registration, password reset, per IP (default: 20, burst 5)
+	RateLimitAuthBurst          int
+	RateLimitUploadPerMinute    int // File uploads (default: 30)
+	RateLimitUploadBurst        int
+	RateLimitExpensivePerMinute int // Server creation, starts, backups, restores, diagnosis (default: 15)
+	RateLimitExpensiveBurst     int
+	RateLimitActionPerMinute    int // Remote actions (POST /api/servers/:id/actions/:action) (default: 30, burst 10)
+	RateLimitActionBurst        int
+
+	// Player UUID resolution via the Mojang API (whitelist, ops and bans are stored by UUID)
+	PlayerProfileCacheTTL   string // How long resolved names/UUIDs are cached, in CACHE_STORE if set (default: "24h")
+	MojangRequestsPerMinute int    // Lookups sent to Mojang per minute across all instances (default: 60)
+
+	// Idempotency-Key header for mutating requests
+	IdempotencyKeyTTL string // How long responses are kept for retries (default: "24h")
+
+	// Background jobs (backups, restores, migrations, archives, bulk jobs)
+	JobHistoryRetention string // How long finished jobs stay in GET /api/jobs (default: "720h")
+
+	// World export and import
+	WorldExportTTL            string // How long export archives can be downloaded (default: "24h")
+	WorldImportMaxSizeMB      int    // Largest accepted world archive upload (default: 2048)
+	WorldImportMaxExtractedMB int    // Largest accepted world after extraction (default: 8192)
+
+	// File manager (archives and resumable uploads)
+	FileUploadMaxSizeMB  int    // Largest file accepted by a resumable upload (default: 4096)
+	FileUploadExpiry     string // How long an unfinished upload can be resumed (default: "24h")
+	FileExtractMaxSizeMB int    // Largest archive contents the file manager extracts (default: 8192)
+
+	// SFTP gateway (SSH key login to server directories)
+	SFTPGatewayEnabled     bool   // Serve SFTP (default: false)
+	SFTPGatewayPort        int    // Listen port (default: 2022)
+	SFTPGatewayHost        string // Host name shown to users (default: host of BASE_URL)
+	SFTPHostKeyPath        string // ed25519 host key, generated if missing (default: ./data/sftp_host_key)
+	SFTPAuditRetentionDays int    // How long SFTP audit entries are kept (default: 90)
+
+	// Audit log (admin actions, node provisioning and decommissioning)
+	AuditRetentionDays int // How long audit events are kept, 0 = forever (default: 365)
+
+	// Per-server disk quota (size of the server directory)
+	DiskQuotaDefaultMB    int    // Quota of servers without their own, 0 = unlimited (default: 20480)
+	DiskQuotaWarnPercents string // Usage thresholds that notify the owner once (default: "80,95")
+	DiskQuotaScanInterval string // How often running servers are measured (default: "15m")
+
+	// TPS/MSPT monitoring (spark or RCON tps/mspt commands)
+	PerformanceSampleInterval string  // How often running servers are sampled (default: "1m")
+	PerformanceLowTPS         float64 // TPS below this counts as lagging, 0 = no alerts (default: 15)
+	PerformanceLowTPSSamples  int     // Lagging samples in a row before the owner is alerted (default: 3)
 
 	// B5 Auto-Scaling (Hetzner Cloud)
 	HetznerCloudToken         string
 	HetznerSSHKeyName         string
 	SSHPrivateKeyPath         string // Path to SSH private key for remote node access (e.g., /root/.ssh/id_rsa)
+	SSHKeyEnvironment         string // Name of this deployment's keypair (default: "production"; staging gets its own keys)
+	SSHKeyRotationInterval    string // Rotate the node key automatically after this long (e.g. "720h", default: "0" = only via API)
+	SSHKeyGracePeriod         string // How long the previous key stays on the nodes after a rotation (default: "24h")
+	SSHKeyEncryptionKey       string // Key for encrypting generated private keys at rest (default: derived from JWT_SECRET)
 	ScalingEnabled            bool
 	ScalingCheckInterval      string
 	ScalingScaleUpThreshold   float64
 	ScalingScaleDownThreshold float64
 	ScalingMaxCloudNodes      int
+	CloudInterruptibleNodes   bool // Scale up with spot instances if the provider offers them (default: false)
+
+	// Scaling decision history (every evaluation of the scaling engine)
+	ScalingDecisionRetentionDays int // How long decisions are kept, 0 = forever (default: 90)
+
+	// Fleet cost reconciliation (estimated node costs vs. the cloud provider's billing)
+	FleetCostReconcileInterval string // How often costs are reconciled and the node cost timelines sampled (default: "1h")
+	FleetCostRetentionDays     int    // How long node cost samples are kept, 0 = forever (default: 365)
+
+	// Signed JSON webhook endpoints (account-wide event subscriptions)
+	WebhookDeliveryRetentionDays int // How long deliveries with their attempts are kept, 0 = forever (default: 30)
+
+	// SSH connection pool (remote node operations)
+	SSHPoolMaxSessions int    // Concurrent sessions multiplexed over one connection per node
+	SSHPoolIdleTimeout string // Close node connections unused for this long (e.g., "5m")
+	SSHPoolKeepalive   string // Keepalive probe interval (e.g., "30s")
+
+	// Remote/shell command limits
+	RemoteCommandTimeout     string // Default timeout for SSH commands without a deadline (e.g., "5m")
+	RemoteCommandMaxOutput   int    // Captured output per command in bytes, excess is truncated
+	MigrationTransferTimeout string // Upper bound for a migration's world data transfer (e.g., "2h")
 
 	// B8 Container Migration & Cost Optimization
 	CostOptimizationEnabled      bool    // Enable automatic container consolidation
@@ -71,16 +241,26 @@ type Config struct {
 	ConsolidationThreshold       int     // Minimum number of nodes to save for consolidation (default: 2)
 	ConsolidationMaxCapacity     float64 // Don't consolidate if fleet capacity > this % (default: 70.0)
 	AllowMigrationWithPlayers    bool    // Allow migration of servers with active players (default: false - safety first!)
+	MigrationImpactBudget        float64 // Max expected player-seconds of downtime for cost-optimization migrations (0 = no limit, default: 60)
 
 	// System Resource Reservation (prevents OOM for system processes)
 	SystemReservedRAMMB      int     // Base RAM reserved for system (API, Postgres, Docker, OS)
 	SystemReservedCPUCores   float64 // CPU cores reserved for system
 	SystemReservedRAMPercent float64 // For cloud nodes: percentage of RAM to reserve (minimum)
+	NodeMinFreeDiskMB        int     // Nodes with less free disk get no new servers and are relieved by migrations (default: 10240)
+	NodeCPUBusyPercent       float64 // Nodes with a higher sustained CPU load only get new servers if no other node fits (default: 80)
+	NodeHealthFailureThreshold  int     // Consecutive failed health checks before a node is marked unhealthy (default: 3)
+	NodeHealthRecoveryThreshold int     // Consecutive passed health checks before an unhealthy node is healthy again (default: 2)
+	NodeLoadPerCoreLimit        float64 // 1-minute load average per core above which a node is reported as overloaded (default: 4)
+	FailoverEnabled             bool    // Restore the servers of a failed node from their latest backup onto healthy nodes (default: true)
+	FailoverConfirmationWindow  string  // How long a node must stay unhealthy before its servers are failed over (default: "3m")
 
 	// 3-Tier Architecture: Velocity Proxy Layer (Tier 2)
 	VelocityAPIURL string // URL to Velocity Remote API (e.g., http://91.98.232.193:8080)
 	ProxyNodeIP    string // IP address of proxy node for resource monitoring (e.g., 91.98.232.193)
 	ProxyNodeSSHUser string // SSH user for proxy node (default: root)
+	VelocityFallbackLobby string // Velocity server players are moved to while their backend is down (empty = disabled)
+	VelocityAPISecret string // Shared secret for signing Velocity API requests (empty = unsigned, must match the plugin)
 
 	// Tier-Based Scaling & Pricing
 	// Standard RAM Tiers (MB) - Powers of 2 for perfect bin-packing
@@ -118,29 +298,124 @@ type Config struct {
 	StorageBoxPassword string // Storage Box password
 	StorageBoxPath     string // Base path for archives (e.g., /minecraft-archives)
 
+	// Data Residency (countries backups/archives are stored in, ISO 3166-1 alpha-2, "" = unknown)
+	StorageBoxCountry   string // Country of the Storage Box (e.g., DE for fsn1/nbg1, FI for hel1)
+	LocalStorageCountry string // Country of the control plane's local backup/archive storage
+
+	// Cold archive tier (S3-compatible object storage, e.g. S3 Glacier)
+	ColdStorageEnabled     bool   // Move archives of long-idle servers from the Storage Box to object storage
+	ColdStorageEndpoint    string // S3 endpoint, path-style (e.g., https://s3.eu-central-1.amazonaws.com)
+	ColdStorageRegion      string // Signing region (default: us-east-1)
+	ColdStorageBucket      string
+	ColdStorageAccessKey   string
+	ColdStorageSecretKey   string
+	ColdStorageClass       string // Storage class of cold archives (default: GLACIER; GLACIER_IR, DEEP_ARCHIVE)
+	ColdStorageCountry     string // Country of the bucket for data residency ("" = unknown)
+	ArchiveColdAfter       string // Idle time (since the last stop) before an archive moves to cold storage (default: "720h")
+	ColdRestoreTier        string // Retrieval tier of restores: Expedited, Standard, Bulk (default: Standard)
+	ColdRestoreWait        string // How long a start waits for a restore before it is retried later (default: "2m")
+
 	// Lifecycle Configuration
 	ArchiveAfterHours   int    // How long servers stay sleeping before archiving (hours, default: 48)
 	ArchiveScanInterval string // Archive worker scan interval (default: "1h")
+	// Defaults of the per-plan and per-server lifecycle thresholds (see LifecyclePolicy)
+	LifecycleSleepAfter   string // How long a stopped server waits before it goes to sleep (default: "5m")
+	LifecycleNotifyBefore string // Lead time of the owner emails before a transition, "0" = no emails (default: "24h")
+
+	// Compression (backups & archives)
+	BackupCompression       string // Codec for new backups: gzip, zstd (restores auto-detect)
+	BackupCompressionLevel  int    // 0 = codec default
+	ArchiveCompression      string // Codec for new archives: gzip, zstd
+	ArchiveCompressionLevel int    // 0 = codec default
+	CompressionWorkers      int    // Parallel compression goroutines (0 = all CPUs, 1 = single-threaded)
+
+	// Per-owner concurrency limits for heavy operations (starts, manual backups, restores)
+	// Keyed by the owner's plan (User.BackupPlan); excess operations are queued. 0 = unlimited
+	OperationLimitBasic      int // Concurrent operations for the basic plan (default: 1)
+	OperationLimitPremium    int // Concurrent operations for the premium plan (default: 2)
+	OperationLimitEnterprise int // Concurrent operations for the enterprise plan (default: 4)
+	OperationQueueMax        int // Max queued operations per owner before new ones are rejected (default: 10)
+
+	// API keys (programmatic access for CI, bots)
+	APIKeyDefaultRateLimit int // Requests per minute for keys created without a limit (default: 60)
+	APIKeyMaxRateLimit     int // Highest rate limit a key can be created with (default: 600)
+	APIKeyMaxPerOwner      int // Max active keys per user or organization (default: 20)
+
+	// Control plane self-monitoring (host running the API, Postgres, backup compression, archive staging)
+	ControlPlaneMonitorInterval string // Sampling interval (default: "30s")
+	ControlPlaneDiskPaths       string // Comma-separated paths whose filesystems are watched (default: servers base path and "/")
+	ControlPlaneCPUWarn         int    // CPU % that raises a warning (default: 85)
+	ControlPlaneCPUCritical     int    // CPU % that defers heavy local work (default: 95)
+	ControlPlaneMemoryWarn      int    // Memory % that raises a warning (default: 85)
+	ControlPlaneMemoryCritical  int    // Memory % that defers heavy local work (default: 95)
+	ControlPlaneDiskWarn        int    // Disk % that raises a warning (default: 80)
+	ControlPlaneDiskCritical    int    // Disk % that defers heavy local work (default: 90)
+	ControlPlaneAlertCooldown   string // Minimum time between repeated alerts per resource (default: "1h")
+	ControlPlaneMaxDefer        string // How long heavy work waits for headroom before failing (default: "30m")
+
+	// Readiness probe (GET /health/ready)
+	HealthReadyOptional string // Comma-separated checks that are reported but don't make the instance unready (e.g. "velocity,cloud_provider")
+	HealthCheckTimeout  string // Timeout of each dependency check (default: "3s")
+	HealthCheckCacheTTL string // How long a result is reused for further probes (default: "10s")
+
+	// Self-diagnostics (GET /api/admin/diagnostics)
+	DiagnosticsTimeout string // Timeout of each dependency probe (default: "10s")
+
+	// Two-factor authentication (TOTP)
+	TwoFactorIssuer        string // Issuer shown in authenticator apps (default: "PayPerPlay")
+	TwoFactorEncryptionKey string // Key for encrypting TOTP secrets at rest (default: derived from JWT_SECRET)
+
+	// Alertmanager webhook receiver
+	AlertmanagerWebhookToken string // Bearer token Alertmanager sends to /webhooks/alertmanager (empty = receiver disabled)
 }
 
 var AppConfig *Config
 
 // Load loads configuration from environment
 func Load() *Config {
-	// Load .env file if exists
-	_ = godotenv.Load()
+	AppConfig = load()
+	return AppConfig
+}
+
+// load reads the configuration from the environment (and the .env file, without overriding the environment)
+func load() *Config {
+	// Load .env file (CONFIG_FILE) if exists
+	_ = godotenv.Load(getEnv("CONFIG_FILE", ".env"))
 
 	config := &Config{
 		AppName:            getEnv("APP_NAME", "PayPerPlay"),
 		Debug:              getEnvBool("DEBUG", true),
 		Port:               getEnv("PORT", "8000"),
+		ShutdownTimeout:    getEnv("SHUTDOWN_TIMEOUT", "30s"),
+		ConfigFile:         getEnv("CONFIG_FILE", ".env"),
+		ConfigReloadInterval: getEnv("CONFIG_RELOAD_INTERVAL", "10s"),
 		LogLevel:           getEnv("LOG_LEVEL", "INFO"),
 		LogJSON:            getEnvBool("LOG_JSON", false),
+		SecretsProvider:        getEnv("SECRETS_PROVIDER", "env"),
+		SecretsRefreshInterval: getEnv("SECRETS_REFRESH_INTERVAL", "5m"),
+		VaultAddr:              strings.TrimRight(getEnv("VAULT_ADDR", ""), "/"),
+		VaultToken:             getEnv("VAULT_TOKEN", ""),
+		VaultSecretPath:        strings.Trim(getEnv("VAULT_SECRET_PATH", "secret/data/payperplay"), "/"),
+		SOPSFile:               getEnv("SOPS_FILE", ""),
 		DatabasePath:       getEnv("DATABASE_PATH", "./payperplay.db"),
 		DatabaseType:       getEnv("DATABASE_TYPE", "sqlite"),
 		DatabaseURL:        getEnv("DATABASE_URL", ""),
+		DatabaseAutoMigrate:      getEnvBool("DATABASE_AUTO_MIGRATE", true),
+		DatabaseAllowNewerSchema: getEnvBool("DATABASE_ALLOW_NEWER_SCHEMA", false),
+		DatabaseReplicaURLs:      getEnv("DATABASE_REPLICA_URLS", ""),
+		DatabaseReplicaMaxLag:    getEnv("DATABASE_REPLICA_MAX_LAG", "30s"),
+		UsageArchiveRetentionDays: getEnvInt("USAGE_ARCHIVE_RETENTION_DAYS", 400),
+		UsageArchiveInterval:      getEnv("USAGE_ARCHIVE_INTERVAL", "24h"),
+		UsageArchivePath:          getEnv("USAGE_ARCHIVE_PATH", "./data/usage-archives"),
+		CacheStore:                getEnv("CACHE_STORE", ""),
+		CacheRedisURL:             getEnv("CACHE_REDIS_URL", ""),
+		CacheTTL:                  getEnv("CACHE_TTL", "15s"),
+		DriftCheckInterval:        getEnv("DRIFT_CHECK_INTERVAL", "5m"),
+		DriftAutoHeal:             getEnvBool("DRIFT_AUTO_HEAL", true),
 		JWTSecret:           getEnv("JWT_SECRET", "change-me-in-production-please-use-a-random-string"),
 		BaseURL:            getEnv("BASE_URL", "http://localhost:8000"),
+		ResendAPIKey:        getEnv("RESEND_API_KEY", ""),
+		EmailFrom:           getEnv("EMAIL_FROM", "PayPerPlay <noreply@payperplay.host>"),
 		DiscordClientID:     getEnv("DISCORD_CLIENT_ID", ""),
 		DiscordClientSecret: getEnv("DISCORD_CLIENT_SECRET", ""),
 		GoogleClientID:      getEnv("GOOGLE_CLIENT_ID", ""),
@@ -153,24 +428,138 @@ func Load() *Config {
 		MCPortStart:        getEnvInt("MC_PORT_START", 25565),
 		MCPortEnd:          getEnvInt("MC_PORT_END", 25665),
 		ControlPlaneIP:     getEnv("CONTROL_PLANE_IP", "91.98.202.235"),
+		AppCDSEnabled:      getEnvBool("APPCDS_ENABLED", false),
+		AppCDSArchiveName:  getEnv("APPCDS_ARCHIVE_NAME", ".payperplay-appcds.jsa"),
+		HeapDumpStoragePath:   getEnv("HEAP_DUMP_STORAGE_PATH", "./minecraft/heapdumps"),
+		HeapDumpQuotaMB:       getEnvInt("HEAP_DUMP_QUOTA_MB", 8192),
+		HeapDumpRetentionDays: getEnvInt("HEAP_DUMP_RETENTION_DAYS", 7),
+		ContainerCPUSharesPerGB: getEnvInt("CONTAINER_CPU_SHARES_PER_GB", 256),
+		ContainerCPULimitCores:  getEnvFloat("CONTAINER_CPU_LIMIT_CORES", 0),
+		MinecraftImageStable:  getEnv("MC_IMAGE_STABLE", "itzg/minecraft-server:stable"),
+		MinecraftImageBeta:    getEnv("MC_IMAGE_BETA", "itzg/minecraft-server:latest"),
+		PluginUpdateSoakDays:  getEnvInt("PLUGIN_UPDATE_SOAK_DAYS", 7),
 		Rate2GB:            getEnvFloat("RATE_2GB", 0.10),
 		Rate4GB:            getEnvFloat("RATE_4GB", 0.20),
 		Rate8GB:            getEnvFloat("RATE_8GB", 0.40),
 		Rate16GB:           getEnvFloat("RATE_16GB", 0.80),
+		StripeEnabled:           getEnvBool("STRIPE_ENABLED", false),
+		StripeSecretKey:         getEnv("STRIPE_SECRET_KEY", ""),
+		StripeWebhookSecret:     getEnv("STRIPE_WEBHOOK_SECRET", ""),
+		StripeMeteredPriceID:    getEnv("STRIPE_METERED_PRICE_ID", ""),
+		StripeMaxFailedPayments: getEnvInt("STRIPE_MAX_FAILED_PAYMENTS", 3),
+		StripeUsageSyncInterval: getEnv("STRIPE_USAGE_SYNC_INTERVAL", "15m"),
+		WalletEnabled:           getEnvBool("WALLET_ENABLED", false),
+		WalletChargeInterval:    getEnv("WALLET_CHARGE_INTERVAL", "1m"),
+		WalletLowBalanceEUR:     getEnvFloat("WALLET_LOW_BALANCE_EUR", 2.0),
+		WalletMinStartMinutes:   getEnvInt("WALLET_MIN_START_MINUTES", 30),
+		WalletMinTopUpEUR:       getEnvFloat("WALLET_MIN_TOPUP_EUR", 5.0),
+		InvoiceEnabled:          getEnvBool("INVOICE_ENABLED", true),
+		InvoiceNumberPrefix:     getEnv("INVOICE_NUMBER_PREFIX", "PPH"),
+		InvoiceSellerName:       getEnv("INVOICE_SELLER_NAME", "PayPerPlay Hosting"),
+		InvoiceSellerAddress:    strings.ReplaceAll(getEnv("INVOICE_SELLER_ADDRESS", ""), "\\n", "\n"),
+		InvoiceSellerCountry:    strings.ToUpper(getEnv("INVOICE_SELLER_COUNTRY", "DE")),
+		InvoiceSellerVATID:      getEnv("INVOICE_SELLER_VAT_ID", ""),
 		InfluxDBURL:        getEnv("INFLUXDB_URL", ""),
 		InfluxDBToken:      getEnv("INFLUXDB_TOKEN", ""),
 		InfluxDBOrg:        getEnv("INFLUXDB_ORG", "payperplay"),
 		InfluxDBBucket:     getEnv("INFLUXDB_BUCKET", "events"),
+		InfluxDBMetricsBucket: getEnv("INFLUXDB_METRICS_BUCKET", ""),
+		OTLPEndpoint:          getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		OTELServiceName:       getEnv("OTEL_SERVICE_NAME", "payperplay-api"),
+		TracingSampleRatio:    getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+		EventTransport:     getEnv("EVENT_TRANSPORT", ""),
+		EventTransportURL:  getEnv("EVENT_TRANSPORT_URL", ""),
+
+		// API rate limits
+		RateLimitStore:              getEnv("RATE_LIMIT_STORE", "memory"),
+		RateLimitRedisURL:           getEnv("RATE_LIMIT_REDIS_URL", ""),
+		RateLimitGlobalPerMinute:    getEnvInt("RATE_LIMIT_GLOBAL_PER_MINUTE", 300),
+		RateLimitGlobalBurst:        getEnvInt("RATE_LIMIT_GLOBAL_BURST", 300),
+		RateLimitAPIPerMinute:       getEnvInt("RATE_LIMIT_API_PER_MINUTE", 120),
+		RateLimitAPIBurst:           getEnvInt("RATE_LIMIT_API_BURST", 120),
+		RateLimitAuthPerMinute:      getEnvInt("RATE_LIMIT_AUTH_PER_MINUTE", 20),
+		RateLimitAuthBurst:          getEnvInt("RATE_LIMIT_AUTH_BURST", 5),
+		RateLimitUploadPerMinute:    getEnvInt("RATE_LIMIT_UPLOAD_PER_MINUTE", 30),
+		RateLimitUploadBurst:        getEnvInt("RATE_LIMIT_UPLOAD_BURST", 30),
+		RateLimitExpensivePerMinute: getEnvInt("RATE_LIMIT_EXPENSIVE_PER_MINUTE", 15),
+		RateLimitExpensiveBurst:     getEnvInt("RATE_LIMIT_EXPENSIVE_BURST", 15),
+		RateLimitActionPerMinute:    getEnvInt("RATE_LIMIT_ACTION_PER_MINUTE", 30),
+		RateLimitActionBurst:        getEnvInt("RATE_LIMIT_ACTION_BURST", 10),
+
+		// Player UUID resolution
+		PlayerProfileCacheTTL:   getEnv("PLAYER_PROFILE_CACHE_TTL", "24h"),
+		MojangRequestsPerMinute: getEnvInt("MOJANG_REQUESTS_PER_MINUTE", 60),
+
+		// Idempotency keys
+		IdempotencyKeyTTL: getEnv("IDEMPOTENCY_KEY_TTL", "24h"),
+
+		// Background jobs
+		JobHistoryRetention: getEnv("JOB_HISTORY_RETENTION", "720h"),
+
+		// World export and import
+		WorldExportTTL:            getEnv("WORLD_EXPORT_TTL", "24h"),
+		WorldImportMaxSizeMB:      getEnvInt("WORLD_IMPORT_MAX_SIZE_MB", 2048),
+		WorldImportMaxExtractedMB: getEnvInt("WORLD_IMPORT_MAX_EXTRACTED_MB", 8192),
+
+		// File manager
+		FileUploadMaxSizeMB:  getEnvInt("FILE_UPLOAD_MAX_SIZE_MB", 4096),
+		FileUploadExpiry:     getEnv("FILE_UPLOAD_EXPIRY", "24h"),
+		FileExtractMaxSizeMB: getEnvInt("FILE_EXTRACT_MAX_SIZE_MB", 8192),
+
+		// SFTP gateway
+		SFTPGatewayEnabled:     getEnvBool("SFTP_GATEWAY_ENABLED", false),
+		SFTPGatewayPort:        getEnvInt("SFTP_GATEWAY_PORT", 2022),
+		SFTPGatewayHost:        getEnv("SFTP_GATEWAY_HOST", ""),
+		SFTPHostKeyPath:        getEnv("SFTP_HOST_KEY_PATH", "./data/sftp_host_key"),
+		SFTPAuditRetentionDays: getEnvInt("SFTP_AUDIT_RETENTION_DAYS", 90),
+
+		// Audit log
+		AuditRetentionDays: getEnvInt("AUDIT_RETENTION_DAYS", 365),
+
+		// Disk quota
+		DiskQuotaDefaultMB:    getEnvInt("DISK_QUOTA_DEFAULT_MB", 20480),
+		DiskQuotaWarnPercents: getEnv("DISK_QUOTA_WARN_PERCENTS", "80,95"),
+		DiskQuotaScanInterval: getEnv("DISK_QUOTA_SCAN_INTERVAL", "15m"),
+
+		// TPS/MSPT monitoring
+		PerformanceSampleInterval: getEnv("PERFORMANCE_SAMPLE_INTERVAL", "1m"),
+		PerformanceLowTPS:         getEnvFloat("PERFORMANCE_LOW_TPS", 15),
+		PerformanceLowTPSSamples:  getEnvInt("PERFORMANCE_LOW_TPS_SAMPLES", 3),
 
 		// B5 Auto-Scaling
 		HetznerCloudToken:         getEnv("HETZNER_CLOUD_TOKEN", ""),
 		HetznerSSHKeyName:         getEnv("HETZNER_SSH_KEY_NAME", "payperplay-main"),
 		SSHPrivateKeyPath:         getEnv("SSH_PRIVATE_KEY_PATH", "/app/.ssh/id_rsa"),
+		SSHKeyEnvironment:         getEnv("SSH_KEY_ENVIRONMENT", "production"),
+		SSHKeyRotationInterval:    getEnv("SSH_KEY_ROTATION_INTERVAL", "0"),
+		SSHKeyGracePeriod:         getEnv("SSH_KEY_GRACE_PERIOD", "24h"),
+		SSHKeyEncryptionKey:       getEnv("SSH_KEY_ENCRYPTION_KEY", ""),
 		ScalingEnabled:            getEnvBool("SCALING_ENABLED", false),
 		ScalingCheckInterval:      getEnv("SCALING_CHECK_INTERVAL", "2m"),
 		ScalingScaleUpThreshold:   getEnvFloat("SCALING_SCALE_UP_THRESHOLD", 85.0),
 		ScalingScaleDownThreshold: getEnvFloat("SCALING_SCALE_DOWN_THRESHOLD", 30.0),
 		ScalingMaxCloudNodes:      getEnvInt("SCALING_MAX_CLOUD_NODES", 10),
+		CloudInterruptibleNodes:   getEnvBool("CLOUD_INTERRUPTIBLE_NODES", false),
+
+		// Scaling decision history
+		ScalingDecisionRetentionDays: getEnvInt("SCALING_DECISION_RETENTION_DAYS", 90),
+
+		// Fleet cost reconciliation
+		FleetCostReconcileInterval: getEnv("FLEET_COST_RECONCILE_INTERVAL", "1h"),
+		FleetCostRetentionDays:     getEnvInt("FLEET_COST_RETENTION_DAYS", 365),
+
+		// Webhook endpoints
+		WebhookDeliveryRetentionDays: getEnvInt("WEBHOOK_DELIVERY_RETENTION_DAYS", 30),
+
+		// SSH connection pool
+		SSHPoolMaxSessions: getEnvInt("SSH_POOL_MAX_SESSIONS", 8),
+		SSHPoolIdleTimeout: getEnv("SSH_POOL_IDLE_TIMEOUT", "5m"),
+		SSHPoolKeepalive:   getEnv("SSH_POOL_KEEPALIVE", "30s"),
+
+		// Remote/shell command limits
+		RemoteCommandTimeout:     getEnv("REMOTE_COMMAND_TIMEOUT", "5m"),
+		RemoteCommandMaxOutput:   getEnvInt("REMOTE_COMMAND_MAX_OUTPUT", 1048576),
+		MigrationTransferTimeout: getEnv("MIGRATION_TRANSFER_TIMEOUT", "2h"),
 
 		// B8 Container Migration & Cost Optimization
 		CostOptimizationEnabled:   getEnvBool("COST_OPTIMIZATION_ENABLED", true),
@@ -178,6 +567,7 @@ func Load() *Config {
 		ConsolidationThreshold:    getEnvInt("CONSOLIDATION_THRESHOLD", 2),
 		ConsolidationMaxCapacity:  getEnvFloat("CONSOLIDATION_MAX_CAPACITY", 70.0),
 		AllowMigrationWithPlayers: getEnvBool("ALLOW_MIGRATION_WITH_PLAYERS", false),
+		MigrationImpactBudget:     getEnvFloat("MIGRATION_IMPACT_BUDGET", 60),
 
 		// System Resource Reservation (Proportional Overhead Model)
 		// SYSTEM_RESERVED_RAM_PERCENT = System overhead (default 12.5% = 1/8)
@@ -185,11 +575,20 @@ func Load() *Config {
 		SystemReservedRAMMB:      getEnvInt("SYSTEM_RESERVED_RAM_MB", 1000),        // Unused in new model
 		SystemReservedCPUCores:   getEnvFloat("SYSTEM_RESERVED_CPU_CORES", 0.5),    // 0.5 cores for system
 		SystemReservedRAMPercent: getEnvFloat("SYSTEM_RESERVED_RAM_PERCENT", 12.5), // 12.5% system overhead (1/8)
+		NodeMinFreeDiskMB:        getEnvInt("NODE_MIN_FREE_DISK_MB", 10240),
+		NodeCPUBusyPercent:       getEnvFloat("NODE_CPU_BUSY_PERCENT", 80),
+		NodeHealthFailureThreshold:  getEnvInt("NODE_HEALTH_FAILURE_THRESHOLD", 3),
+		NodeHealthRecoveryThreshold: getEnvInt("NODE_HEALTH_RECOVERY_THRESHOLD", 2),
+		NodeLoadPerCoreLimit:        getEnvFloat("NODE_LOAD_PER_CORE_LIMIT", 4),
+		FailoverEnabled:             getEnvBool("FAILOVER_ENABLED", true),
+		FailoverConfirmationWindow:  getEnv("FAILOVER_CONFIRMATION_WINDOW", "3m"),
 
 		// 3-Tier Architecture: Velocity Proxy Layer (Tier 2)
 		VelocityAPIURL: getEnv("VELOCITY_API_URL", ""),
 		ProxyNodeIP:    getEnv("PROXY_NODE_IP", "91.98.232.193"), // Default to known proxy node
 		ProxyNodeSSHUser: getEnv("PROXY_NODE_SSH_USER", "root"),
+		VelocityFallbackLobby: getEnv("VELOCITY_FALLBACK_LOBBY", ""),
+		VelocityAPISecret: getEnv("VELOCITY_API_SECRET", ""),
 
 		// Tier-Based Scaling & Pricing
 		StandardTierMicro:  getEnvInt("STANDARD_TIER_MICRO_MB", 2048),   // 2GB
@@ -223,12 +622,77 @@ func Load() *Config {
 		StorageBoxPassword: getEnv("STORAGE_BOX_PASSWORD", ""),
 		StorageBoxPath:     getEnv("STORAGE_BOX_PATH", "/minecraft-archives"),
 
+		// Data Residency
+		StorageBoxCountry:   getEnv("STORAGE_BOX_COUNTRY", ""),
+		LocalStorageCountry: getEnv("LOCAL_STORAGE_COUNTRY", ""),
+
+		// Cold archive tier
+		ColdStorageEnabled:   getEnvBool("COLD_STORAGE_ENABLED", false),
+		ColdStorageEndpoint:  getEnv("COLD_STORAGE_ENDPOINT", ""),
+		ColdStorageRegion:    getEnv("COLD_STORAGE_REGION", "us-east-1"),
+		ColdStorageBucket:    getEnv("COLD_STORAGE_BUCKET", ""),
+		ColdStorageAccessKey: getEnv("COLD_STORAGE_ACCESS_KEY", ""),
+		ColdStorageSecretKey: getEnv("COLD_STORAGE_SECRET_KEY", ""),
+		ColdStorageClass:     getEnv("COLD_STORAGE_CLASS", "GLACIER"),
+		ColdStorageCountry:   getEnv("COLD_STORAGE_COUNTRY", ""),
+		ArchiveColdAfter:     getEnv("ARCHIVE_COLD_AFTER", "720h"), // 30 days
+		ColdRestoreTier:      getEnv("COLD_RESTORE_TIER", "Standard"),
+		ColdRestoreWait:      getEnv("COLD_RESTORE_WAIT", "2m"),
+
 		// Lifecycle Configuration
 		ArchiveAfterHours:   getEnvInt("ARCHIVE_AFTER_HOURS", 48),      // Default: 48 hours
 		ArchiveScanInterval: getEnv("ARCHIVE_SCAN_INTERVAL", "1h"),     // Default: 1 hour
+		LifecycleSleepAfter:   getEnv("LIFECYCLE_SLEEP_AFTER", "5m"),
+		LifecycleNotifyBefore: getEnv("LIFECYCLE_NOTIFY_BEFORE", "24h"),
+
+		// Compression
+		BackupCompression:       getEnv("BACKUP_COMPRESSION", "zstd"),
+		BackupCompressionLevel:  getEnvInt("BACKUP_COMPRESSION_LEVEL", 0),
+		ArchiveCompression:      getEnv("ARCHIVE_COMPRESSION", "zstd"),
+		ArchiveCompressionLevel: getEnvInt("ARCHIVE_COMPRESSION_LEVEL", 9), // Archives are cold: trade CPU for space
+		CompressionWorkers:      getEnvInt("COMPRESSION_WORKERS", 0),
+
+		// Per-owner operation limits
+		OperationLimitBasic:      getEnvInt("OPERATION_LIMIT_BASIC", 1),
+		OperationLimitPremium:    getEnvInt("OPERATION_LIMIT_PREMIUM", 2),
+		OperationLimitEnterprise: getEnvInt("OPERATION_LIMIT_ENTERPRISE", 4),
+		OperationQueueMax:        getEnvInt("OPERATION_QUEUE_MAX", 10),
+
+		// API keys
+		APIKeyDefaultRateLimit: getEnvInt("API_KEY_DEFAULT_RATE_LIMIT", 60),
+		APIKeyMaxRateLimit:     getEnvInt("API_KEY_MAX_RATE_LIMIT", 600),
+		APIKeyMaxPerOwner:      getEnvInt("API_KEY_MAX_PER_OWNER", 20),
+
+		// Control plane self-monitoring
+		ControlPlaneMonitorInterval: getEnv("CONTROL_PLANE_MONITOR_INTERVAL", "30s"),
+		ControlPlaneDiskPaths:       getEnv("CONTROL_PLANE_DISK_PATHS", ""), // Empty = servers base path and "/"
+		ControlPlaneCPUWarn:         getEnvInt("CONTROL_PLANE_CPU_WARN", 85),
+		ControlPlaneCPUCritical:     getEnvInt("CONTROL_PLANE_CPU_CRITICAL", 95),
+		ControlPlaneMemoryWarn:      getEnvInt("CONTROL_PLANE_MEMORY_WARN", 85),
+		ControlPlaneMemoryCritical:  getEnvInt("CONTROL_PLANE_MEMORY_CRITICAL", 95),
+		ControlPlaneDiskWarn:        getEnvInt("CONTROL_PLANE_DISK_WARN", 80),
+		ControlPlaneDiskCritical:    getEnvInt("CONTROL_PLANE_DISK_CRITICAL", 90),
+		ControlPlaneAlertCooldown:   getEnv("CONTROL_PLANE_ALERT_COOLDOWN", "1h"),
+		ControlPlaneMaxDefer:        getEnv("CONTROL_PLANE_MAX_DEFER", "30m"),
+
+		// Readiness probe
+		HealthReadyOptional: getEnv("HEALTH_READY_OPTIONAL", ""),
+		HealthCheckTimeout:  getEnv("HEALTH_CHECK_TIMEOUT", "3s"),
+		HealthCheckCacheTTL: getEnv("HEALTH_CHECK_CACHE_TTL", "10s"),
+
+		// Self-diagnostics
+		DiagnosticsTimeout: getEnv("DIAGNOSTICS_TIMEOUT", "10s"),
+
+		// Two-factor authentication
+		TwoFactorIssuer:        getEnv("TWO_FACTOR_ISSUER", "PayPerPlay"),
+		TwoFactorEncryptionKey: getEnv("TWO_FACTOR_ENCRYPTION_KEY", ""), // Empty = derived from JWT_SECRET
+
+		// Alertmanager webhook receiver
+		AlertmanagerWebhookToken: getEnv("ALERTMANAGER_WEBHOOK_TOKEN", ""),
 	}
 
-	AppConfig = config
+	config.FrontendURL = strings.TrimRight(getEnv("FRONTEND_URL", config.BaseURL), "/")
+
 	return config
 }
 
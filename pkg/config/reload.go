@@ -0,0 +1,135 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+// Reloadable maps the settings that take effect without a restart to their Config field.
+// They are read on every use (or pushed to their service by a change listener); all other
+// settings are wired into services at startup and still need a restart.
+var Reloadable = map[string]string{
+	"LOG_LEVEL":                       "LogLevel",
+	"DEFAULT_IDLE_TIMEOUT":            "DefaultIdleTimeout",
+	"RATE_LIMIT_GLOBAL_PER_MINUTE":    "RateLimitGlobalPerMinute",
+	"RATE_LIMIT_GLOBAL_BURST":         "RateLimitGlobalBurst",
+	"RATE_LIMIT_API_PER_MINUTE":       "RateLimitAPIPerMinute",
+	"RATE_LIMIT_API_BURST":            "RateLimitAPIBurst",
+	"RATE_LIMIT_AUTH_PER_MINUTE":      "RateLimitAuthPerMinute",
+	"RATE_LIMIT_AUTH_BURST":           "RateLimitAuthBurst",
+	"RATE_LIMIT_UPLOAD_PER_MINUTE":    "RateLimitUploadPerMinute",
+	"RATE_LIMIT_UPLOAD_BURST":         "RateLimitUploadBurst",
+	"RATE_LIMIT_EXPENSIVE_PER_MINUTE": "RateLimitExpensivePerMinute",
+	"RATE_LIMIT_EXPENSIVE_BURST":      "RateLimitExpensiveBurst",
+	"API_KEY_DEFAULT_RATE_LIMIT":      "APIKeyDefaultRateLimit",
+	"API_KEY_MAX_RATE_LIMIT":          "APIKeyMaxRateLimit",
+	"API_KEY_MAX_PER_OWNER":           "APIKeyMaxPerOwner",
+	"WORKER_NODE_MIN_RAM_MB":          "WorkerNodeMinRAMMB",
+	"WORKER_NODE_MAX_RAM_MB":          "WorkerNodeMaxRAMMB",
+	"WORKER_NODE_BUFFER_PERCENT":      "WorkerNodeBufferPercent",
+}
+
+// Change is a reloadable setting that got a new value
+type Change struct {
+	Key   string      `json:"key"`
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// reloadMu serializes reloads and the redacted view
+var reloadMu sync.Mutex
+
+// ReloadableKeys returns the env keys of the reloadable settings, sorted
+func ReloadableKeys() []string {
+	keys := make([]string, 0, len(Reloadable))
+	for key := range Reloadable {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Reload re-reads the reloadable settings from path and applies the changed ones to cfg (AppConfig).
+// Values in the file override the environment; keys removed from the file keep their current value.
+func Reload(cfg *Config, path string) ([]Change, error) {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	for key := range Reloadable {
+		if value, ok := values[key]; ok {
+			os.Setenv(key, value)
+		}
+	}
+	fresh := reflect.ValueOf(load()).Elem()
+	live := reflect.ValueOf(cfg).Elem()
+
+	var changes []Change
+	for _, key := range ReloadableKeys() {
+		field := Reloadable[key]
+		old, next := live.FieldByName(field), fresh.FieldByName(field)
+		if old.Interface() == next.Interface() {
+			continue
+		}
+		changes = append(changes, Change{Key: key, Field: field, Old: old.Interface(), New: next.Interface()})
+		old.Set(next)
+	}
+	return changes, nil
+}
+
+// dsnPassword matches the password of a key=value database DSN
+var dsnPassword = regexp.MustCompile(`(?i)(password=)\S+`)
+
+// Redacted returns the settings of cfg by field name with secrets masked and
+// passwords removed from connection URLs
+func Redacted(cfg *Config) map[string]interface{} {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	value := reflect.ValueOf(cfg).Elem()
+	fields := make(map[string]interface{}, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		name := value.Type().Field(i).Name
+		setting := value.Field(i).Interface()
+		if text, ok := setting.(string); ok && text != "" {
+			switch {
+			case isSecretSetting(name):
+				setting = "[redacted]"
+			case strings.HasSuffix(name, "URL"):
+				setting = redactURL(text)
+			}
+		}
+		fields[name] = setting
+	}
+	return fields
+}
+
+// isSecretSetting reports whether a Config field holds a credential
+func isSecretSetting(name string) bool {
+	for _, marker := range []string{"Secret", "Password", "Token", "APIKey", "EncryptionKey"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL masks the password of a URL or key=value DSN
+func redactURL(value string) string {
+	if parsed, err := url.Parse(value); err == nil && parsed.Scheme != "" {
+		return parsed.Redacted()
+	}
+	return dsnPassword.ReplaceAllString(value, "${1}xxxxx")
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,7 +27,7 @@ func (l LogLevel) String() string {
 
 // Logger is a structured logger
 type Logger struct {
-	level      LogLevel
+	level      atomic.Int32 // LogLevel, changed by SetLevel on config reload
 	writer     io.Writer
 	structured bool // JSON output if true
 }
@@ -47,11 +49,35 @@ func init() {
 
 // NewLogger creates a new logger instance
 func NewLogger(level LogLevel, writer io.Writer, structured bool) *Logger {
-	return &Logger{
-		level:      level,
+	l := &Logger{
 		writer:     writer,
 		structured: structured,
 	}
+	l.level.Store(int32(level))
+	return l
+}
+
+// SetLevel changes the minimum level that is logged
+func (l *Logger) SetLevel(level LogLevel) {
+	l.level.Store(int32(level))
+}
+
+// ParseLevel parses a LOG_LEVEL value (INFO if unknown)
+func ParseLevel(level string) LogLevel {
+	switch strings.ToUpper(level) {
+	case "DEBUG":
+		return DEBUG
+	case "INFO":
+		return INFO
+	case "WARN":
+		return WARN
+	case "ERROR":
+		return ERROR
+	case "FATAL":
+		return FATAL
+	default:
+		return INFO
+	}
 }
 
 // SetDefault sets the default logger
@@ -59,9 +85,14 @@ func SetDefault(logger *Logger) {
 	defaultLogger = logger
 }
 
+// SetLevel changes the minimum level of the default logger
+func SetLevel(level LogLevel) {
+	defaultLogger.SetLevel(level)
+}
+
 // Log logs a message with the given level and fields
 func (l *Logger) Log(level LogLevel, message string, fields map[string]interface{}) {
-	if level < l.level {
+	if level < LogLevel(l.level.Load()) {
 		return
 	}
 
@@ -81,7 +112,7 @@ func (l *Logger) Log(level LogLevel, message string, fields map[string]interface
 
 // LogError logs an error message
 func (l *Logger) LogError(level LogLevel, message string, err error, fields map[string]interface{}) {
-	if level < l.level {
+	if level < LogLevel(l.level.Load()) {
 		return
 	}
 
@@ -0,0 +1,194 @@
+// Package sdk is a Go client for the PayPerPlay Hosting API.
+//
+// The operation methods and request types in operations_gen.go are generated
+// from the router by cmd/openapi-gen (go generate ./internal/api).
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the API with a JWT access token or an API key
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Token      string // JWT access token (Authorization: Bearer)
+	APIKey     string // API key (X-API-Key), alternative to Token
+}
+
+// NewClient creates a client for the API at baseURL (e.g. https://panel.example.com)
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// APIError is a non-2xx response
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("payperplay: %d %s", e.StatusCode, e.Message)
+}
+
+// Form is a multipart/form-data request body (file uploads)
+type Form struct {
+	Fields map[string]string
+	Files  map[string]File
+}
+
+// File is a file part of a Form
+type File struct {
+	Name    string
+	Content io.Reader
+}
+
+// Chunk is a raw request body at an offset (the chunks of resumable uploads)
+type Chunk struct {
+	Offset  int64 // Sent as Upload-Offset
+	Size    int64 // Sent as Content-Length if set
+	Content io.Reader
+}
+
+type twoFactorKey struct{}
+
+// WithTwoFactorCode attaches the second factor (X-2FA-Code) required by sensitive operations
+func WithTwoFactorCode(ctx context.Context, code string) context.Context {
+	return context.WithValue(ctx, twoFactorKey{}, code)
+}
+
+// do sends a request and decodes the JSON response into out.
+// out may be nil (response discarded) or an io.Writer (raw download, e.g. world archives and PDFs).
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, out interface{}) error {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	if v := reflect.ValueOf(body); v.Kind() == reflect.Ptr && v.IsNil() {
+		body = nil // Typed nil request structs mean "no body"
+	}
+
+	var payload io.Reader
+	contentType := ""
+	contentLength := int64(0)
+	header := http.Header{}
+	switch b := body.(type) {
+	case nil:
+	case *Chunk:
+		payload, contentType, contentLength = b.Content, "application/offset+octet-stream", b.Size
+		header.Set("Upload-Offset", strconv.FormatInt(b.Offset, 10))
+	case *Form:
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		for name, value := range b.Fields {
+			if err := writer.WriteField(name, value); err != nil {
+				return err
+			}
+		}
+		for name, file := range b.Files {
+			part, err := writer.CreateFormFile(name, file.Name)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(part, file.Content); err != nil {
+				return err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		payload, contentType = &buf, writer.FormDataContentType()
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		payload, contentType = bytes.NewReader(data), "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, payload)
+	if err != nil {
+		return err
+	}
+	if contentLength > 0 {
+		req.ContentLength = contentLength
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	c.setAuth(req.Header)
+	if code, ok := ctx.Value(twoFactorKey{}).(string); ok && code != "" {
+		req.Header.Set("X-2FA-Code", code)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: resp.Status}
+		var errBody struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&errBody) == nil && errBody.Error != "" {
+			apiErr.Message = errBody.Error
+		}
+		return apiErr
+	}
+
+	switch o := out.(type) {
+	case nil:
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	case io.Writer:
+		_, err = io.Copy(o, resp.Body)
+		return err
+	default:
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
+	}
+}
+
+// setAuth sets the credentials of the client on a request
+func (c *Client) setAuth(header http.Header) {
+	if c.Token != "" {
+		header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.APIKey != "" {
+		header.Set("X-API-Key", c.APIKey)
+	}
+}
+
+// AppendUploadChunk calls PATCH /api/servers/{id}/files/uploads/{upload_id} with the next chunk of
+// a resumable upload (see CreateUpload). A 409 means the offset is not the one the server has;
+// GET the upload (FileManagerGetUploadGet) for the current offset and continue there.
+func (c *Client) AppendUploadChunk(ctx context.Context, id string, uploadID string, chunk *Chunk, out interface{}) error {
+	return c.do(ctx, "PATCH", "/api/servers/"+url.PathEscape(id)+"/files/uploads/"+url.PathEscape(uploadID), nil, chunk, out)
+}
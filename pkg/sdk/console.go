@@ -0,0 +1,68 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// ConsoleMessage is a message of the console stream: "log" lines and command "response"s or
+// "error"s from the server, "command"s from the client
+type ConsoleMessage struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// ConsoleStream is an open console WebSocket (GET /api/servers/{id}/console/stream)
+type ConsoleStream struct {
+	conn *websocket.Conn
+}
+
+// StreamConsole connects to the live console of a server
+// Requires the "console" permission on the server.
+func (c *Client) StreamConsole(ctx context.Context, id string) (*ConsoleStream, error) {
+	target, err := url.Parse(c.BaseURL + "/api/servers/" + url.PathEscape(id) + "/console/stream")
+	if err != nil {
+		return nil, err
+	}
+	switch target.Scheme {
+	case "https":
+		target.Scheme = "wss"
+	case "http":
+		target.Scheme = "ws"
+	}
+
+	header := http.Header{}
+	c.setAuth(header)
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, target.String(), header)
+	if err != nil {
+		if resp != nil {
+			return nil, &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(resp.Status)}
+		}
+		return nil, fmt.Errorf("failed to connect to the console: %w", err)
+	}
+	return &ConsoleStream{conn: conn}, nil
+}
+
+// Receive waits for the next message
+func (s *ConsoleStream) Receive() (*ConsoleMessage, error) {
+	var msg ConsoleMessage
+	if err := s.conn.ReadJSON(&msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// SendCommand runs a console command; its output arrives as a "response" message
+func (s *ConsoleStream) SendCommand(command string) error {
+	return s.conn.WriteJSON(ConsoleMessage{Type: "command", Content: command})
+}
+
+// Close closes the stream
+func (s *ConsoleStream) Close() error {
+	return s.conn.Close()
+}
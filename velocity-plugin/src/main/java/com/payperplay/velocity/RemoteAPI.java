@@ -1,24 +1,45 @@
 package com.payperplay.velocity;
 
 import com.google.inject.Inject;
+import com.velocitypowered.api.event.ResultedEvent;
 import com.velocitypowered.api.event.Subscribe;
+import com.velocitypowered.api.event.connection.DisconnectEvent;
+import com.velocitypowered.api.event.connection.LoginEvent;
+import com.velocitypowered.api.event.player.KickedFromServerEvent;
 import com.velocitypowered.api.event.player.PlayerChooseInitialServerEvent;
+import com.velocitypowered.api.event.player.ServerPreConnectEvent;
 import com.velocitypowered.api.event.proxy.ProxyInitializeEvent;
 import com.velocitypowered.api.event.proxy.ProxyShutdownEvent;
 import com.velocitypowered.api.plugin.Plugin;
+import com.velocitypowered.api.proxy.Player;
 import com.velocitypowered.api.proxy.ProxyServer;
 import com.velocitypowered.api.proxy.server.RegisteredServer;
 import com.velocitypowered.api.proxy.server.ServerInfo;
 import org.slf4j.Logger;
 import io.javalin.Javalin;
 import io.javalin.http.Context;
+import io.javalin.http.UnauthorizedResponse;
 import net.kyori.adventure.text.Component;
+import net.kyori.adventure.text.event.ClickEvent;
+import net.kyori.adventure.text.event.HoverEvent;
 import net.kyori.adventure.text.format.NamedTextColor;
 
+import javax.crypto.Mac;
+import javax.crypto.spec.SecretKeySpec;
 import java.net.InetSocketAddress;
+import java.nio.charset.StandardCharsets;
+import java.security.GeneralSecurityException;
+import java.security.MessageDigest;
+import java.time.Instant;
+import java.util.ArrayList;
 import java.util.HashMap;
+import java.util.HexFormat;
 import java.util.List;
 import java.util.Map;
+import java.util.Optional;
+import java.util.Set;
+import java.util.UUID;
+import java.util.concurrent.ConcurrentHashMap;
 import java.util.stream.Collectors;
 
 /**
@@ -29,10 +50,19 @@ import java.util.stream.Collectors;
  *
  * Endpoints:
  * - POST   /api/servers         - Register a new backend server
+ * - PUT    /api/servers         - Replace all platform registrations (replayed after a proxy restart)
  * - DELETE /api/servers/{name}  - Unregister a backend server
  * - GET    /api/servers         - List all registered servers
  * - GET    /api/players/{server} - Get player count for a specific server
+ * - PUT    /api/fallback         - Configure the fallback lobby for players of crashed servers
+ * - POST   /api/servers/{name}/evacuate - Move all players of a server to the fallback lobby
+ * - POST   /api/servers/{name}/return   - Send players waiting in the lobby back to their server
+ * - POST   /api/servers/{name}/notify   - Tell players elsewhere on the network that a server is back
+ * - PUT    /api/bans             - Replace the player bans enforced at the proxy
  * - GET    /health               - Health check endpoint
+ *
+ * With VELOCITY_API_SECRET set, all requests except /health must carry an HMAC-SHA256 signature
+ * (X-PayPerPlay-Signature) over "timestamp.METHOD.path.body" and a recent X-PayPerPlay-Timestamp.
  */
 @Plugin(
     id = "velocity-remote-api",
@@ -47,6 +77,34 @@ public class RemoteAPI {
     private final Logger logger;
     private Javalin app;
 
+    // Fallback lobby (empty = disabled) and the players waiting there, by the server they came from
+    private volatile String fallbackServer = "";
+    private final Map<UUID, String> displacedPlayers = new ConcurrentHashMap<>();
+
+    // Registrations live in memory only; the health check reports the start time, so the
+    // Control Plane notices a restart and pushes its registrations again (PUT /api/servers)
+    private final long startedAt = System.currentTimeMillis() / 1000;
+
+    // Bans pushed by the Control Plane (PUT /api/bans) by player UUID, replaced as a whole on every sync
+    private volatile Map<UUID, List<Ban>> bans = Map.of();
+
+    /**
+     * A ban from the whole network (no servers) or from the listed servers; expiresAt 0 = permanent
+     */
+    private record Ban(String reason, long expiresAt, Set<String> servers) {
+        boolean active() {
+            return expiresAt == 0 || System.currentTimeMillis() / 1000 < expiresAt;
+        }
+
+        boolean global() {
+            return servers.isEmpty();
+        }
+    }
+
+    // Shared secret of the control plane (empty = requests are not signed) and the accepted clock skew
+    private final String apiSecret = Optional.ofNullable(System.getenv("VELOCITY_API_SECRET")).orElse("");
+    private static final long MAX_SIGNATURE_AGE_SECONDS = 300;
+
     @Inject
     public RemoteAPI(ProxyServer server, Logger logger) {
         this.server = server;
@@ -61,11 +119,23 @@ public class RemoteAPI {
             config.showJavalinBanner = false;
         }).start(8080);
 
+        if (!apiSecret.isEmpty()) {
+            app.before(this::verifySignature);
+        } else {
+            logger.warn("VELOCITY_API_SECRET is not set, accepting unsigned requests");
+        }
+
         // Register endpoints
         app.post("/api/servers", this::registerServer);
+        app.put("/api/servers", this::syncServers);
         app.delete("/api/servers/{name}", this::unregisterServer);
         app.get("/api/servers", this::listServers);
         app.get("/api/players/{server}", this::getPlayerCount);
+        app.put("/api/fallback", this::setFallback);
+        app.post("/api/servers/{name}/evacuate", this::evacuateServer);
+        app.post("/api/servers/{name}/return", this::returnPlayers);
+        app.post("/api/servers/{name}/notify", this::notifyPlayers);
+        app.put("/api/bans", this::syncBans);
         app.get("/health", this::healthCheck);
 
         logger.info("VelocityRemoteAPI initialized successfully on port 8080");
@@ -79,6 +149,50 @@ public class RemoteAPI {
         }
     }
 
+    /**
+     * Rejects requests without a valid signature of the control plane, and old ones (replays)
+     */
+    private void verifySignature(Context ctx) {
+        if (ctx.path().equals("/health")) {
+            return;
+        }
+
+        String timestamp = ctx.header("X-PayPerPlay-Timestamp");
+        String signature = ctx.header("X-PayPerPlay-Signature");
+        if (timestamp == null || signature == null) {
+            throw new UnauthorizedResponse("Missing request signature");
+        }
+        long sentAt;
+        try {
+            sentAt = Long.parseLong(timestamp);
+        } catch (NumberFormatException e) {
+            throw new UnauthorizedResponse("Invalid signature timestamp");
+        }
+        if (Math.abs(System.currentTimeMillis() / 1000 - sentAt) > MAX_SIGNATURE_AGE_SECONDS) {
+            throw new UnauthorizedResponse("Signature expired");
+        }
+
+        String path = ctx.path();
+        if (ctx.queryString() != null) {
+            path += "?" + ctx.queryString();
+        }
+        String expected;
+        try {
+            Mac mac = Mac.getInstance("HmacSHA256");
+            mac.init(new SecretKeySpec(apiSecret.getBytes(StandardCharsets.UTF_8), "HmacSHA256"));
+            mac.update((timestamp + "." + ctx.method().name() + "." + path + ".").getBytes(StandardCharsets.UTF_8));
+            mac.update(ctx.bodyAsBytes());
+            expected = "v1=" + HexFormat.of().formatHex(mac.doFinal());
+        } catch (GeneralSecurityException e) {
+            logger.error("Failed to verify request signature", e);
+            throw new UnauthorizedResponse("Signature verification failed");
+        }
+        if (!MessageDigest.isEqual(expected.getBytes(StandardCharsets.UTF_8), signature.getBytes(StandardCharsets.UTF_8))) {
+            logger.warn("Rejected request with invalid signature: {} {}", ctx.method(), path);
+            throw new UnauthorizedResponse("Invalid request signature");
+        }
+    }
+
     /**
      * POST /api/servers
      * Body: {"name": "server-name", "address": "host:port"}
@@ -131,6 +245,106 @@ public class RemoteAPI {
         }
     }
 
+    /**
+     * PUT /api/servers
+     * Body: {"prefix": "mc-", "servers": [{"name": "mc-abc", "address": "host:port"}], "keep": ["mc-def"], "fallback": "lobby"}
+     *
+     * Registers the listed servers (again on an address change) and unregisters all other servers
+     * whose name starts with the prefix, except the ones in "keep". Servers without the prefix
+     * (e.g. the lobby from velocity.toml) are never touched.
+     */
+    @SuppressWarnings("unchecked")
+    private void syncServers(Context ctx) {
+        try {
+            Map<String, Object> body = ctx.bodyAsClass(Map.class);
+            String prefix = (String) body.getOrDefault("prefix", "");
+            List<Map<String, String>> servers = (List<Map<String, String>>) body.getOrDefault("servers", List.of());
+            List<String> keep = (List<String>) body.getOrDefault("keep", List.of());
+            String fallback = (String) body.getOrDefault("fallback", "");
+
+            if (prefix == null || prefix.isEmpty()) {
+                ctx.status(400).json(Map.of("error", "Missing 'prefix' field"));
+                return;
+            }
+
+            // Validate everything before changing anything
+            Map<String, InetSocketAddress> wanted = new HashMap<>();
+            for (Map<String, String> entry : servers) {
+                String name = entry.get("name");
+                InetSocketAddress address = parseAddress(entry.get("address"));
+                if (name == null || !name.startsWith(prefix) || address == null) {
+                    ctx.status(400).json(Map.of("error", "Invalid server entry: " + entry));
+                    return;
+                }
+                wanted.put(name, address);
+            }
+
+            int registered = 0, updated = 0, unregistered = 0, unchanged = 0;
+            for (RegisteredServer registeredServer : server.getAllServers()) {
+                ServerInfo info = registeredServer.getServerInfo();
+                String name = info.getName();
+                if (!name.startsWith(prefix) || keep.contains(name)) {
+                    continue;
+                }
+                InetSocketAddress address = wanted.get(name);
+                if (address == null) {
+                    server.unregisterServer(info);
+                    unregistered++;
+                } else if (!address.equals(info.getAddress())) {
+                    server.unregisterServer(info);
+                    server.registerServer(new ServerInfo(name, address));
+                    wanted.remove(name);
+                    updated++;
+                } else {
+                    wanted.remove(name);
+                    unchanged++;
+                }
+            }
+            for (Map.Entry<String, InetSocketAddress> entry : wanted.entrySet()) {
+                server.registerServer(new ServerInfo(entry.getKey(), entry.getValue()));
+                registered++;
+            }
+
+            if (fallback != null && !fallback.isEmpty()) {
+                fallbackServer = fallback;
+            }
+
+            logger.info("Synced servers: {} registered, {} updated, {} unregistered, {} unchanged",
+                registered, updated, unregistered, unchanged);
+            ctx.status(200).json(Map.of(
+                "status", "ok",
+                "registered", registered,
+                "updated", updated,
+                "unregistered", unregistered,
+                "unchanged", unchanged
+            ));
+
+        } catch (ClassCastException e) {
+            ctx.status(400).json(Map.of("error", "Invalid request body"));
+        } catch (Exception e) {
+            logger.error("Failed to sync servers", e);
+            ctx.status(500).json(Map.of("error", "Internal server error: " + e.getMessage()));
+        }
+    }
+
+    /**
+     * Parses "host:port", returns null if the address is invalid
+     */
+    private static InetSocketAddress parseAddress(String address) {
+        if (address == null) {
+            return null;
+        }
+        String[] parts = address.split(":");
+        if (parts.length != 2) {
+            return null;
+        }
+        try {
+            return new InetSocketAddress(parts[0], Integer.parseInt(parts[1]));
+        } catch (IllegalArgumentException e) {
+            return null;
+        }
+    }
+
     /**
      * DELETE /api/servers/{name}
      *
@@ -175,6 +389,9 @@ public class RemoteAPI {
                     serverData.put("name", info.getName());
                     serverData.put("address", info.getAddress().getHostString() + ":" + info.getAddress().getPort());
                     serverData.put("players", registeredServer.getPlayersConnected().size());
+                    serverData.put("player_names", registeredServer.getPlayersConnected().stream()
+                        .map(Player::getUsername)
+                        .collect(Collectors.toList()));
                     return serverData;
                 })
                 .collect(Collectors.toList());
@@ -219,6 +436,350 @@ public class RemoteAPI {
         }
     }
 
+    /**
+     * PUT /api/fallback
+     * Body: {"server": "lobby"} (empty disables the fallback)
+     */
+    @SuppressWarnings("unchecked")
+    private void setFallback(Context ctx) {
+        try {
+            Map<String, String> body = ctx.bodyAsClass(Map.class);
+            String name = body.getOrDefault("server", "");
+            fallbackServer = name == null ? "" : name;
+
+            logger.info("Fallback lobby set to '{}'", fallbackServer);
+            ctx.status(200).json(Map.of(
+                "status", "ok",
+                "server", fallbackServer
+            ));
+
+        } catch (Exception e) {
+            logger.error("Failed to set fallback lobby", e);
+            ctx.status(500).json(Map.of("error", "Internal server error: " + e.getMessage()));
+        }
+    }
+
+    /**
+     * POST /api/servers/{name}/evacuate
+     *
+     * Moves all players of a (crashed or hanging) server to the fallback lobby and remembers them
+     */
+    private void evacuateServer(Context ctx) {
+        try {
+            String name = ctx.pathParam("name");
+            RegisteredServer registeredServer = server.getServer(name).orElse(null);
+            if (registeredServer == null) {
+                ctx.status(404).json(Map.of("error", "Server not found"));
+                return;
+            }
+
+            RegisteredServer lobby = fallbackLobbyFor(name).orElse(null);
+            if (lobby == null) {
+                ctx.status(409).json(Map.of("error", "No fallback lobby available"));
+                return;
+            }
+
+            int moved = 0;
+            for (Player player : registeredServer.getPlayersConnected()) {
+                displacedPlayers.put(player.getUniqueId(), name);
+                player.sendMessage(Component.text("Your server is restarting, you'll be sent back once it's up again.", NamedTextColor.YELLOW));
+                player.createConnectionRequest(lobby).fireAndForget();
+                moved++;
+            }
+
+            logger.info("Evacuated {} players from {} to {}", moved, name, lobby.getServerInfo().getName());
+            ctx.status(200).json(Map.of(
+                "status", "ok",
+                "server", name,
+                "target", lobby.getServerInfo().getName(),
+                "moved", moved
+            ));
+
+        } catch (Exception e) {
+            logger.error("Failed to evacuate server", e);
+            ctx.status(500).json(Map.of("error", "Internal server error: " + e.getMessage()));
+        }
+    }
+
+    /**
+     * POST /api/servers/{name}/return
+     *
+     * Sends players that were moved to the lobby because of this server back to it
+     */
+    private void returnPlayers(Context ctx) {
+        try {
+            String name = ctx.pathParam("name");
+            RegisteredServer registeredServer = server.getServer(name).orElse(null);
+            if (registeredServer == null) {
+                ctx.status(404).json(Map.of("error", "Server not found"));
+                return;
+            }
+
+            int moved = 0;
+            for (Map.Entry<UUID, String> entry : displacedPlayers.entrySet()) {
+                if (!entry.getValue().equals(name)) {
+                    continue;
+                }
+                displacedPlayers.remove(entry.getKey());
+
+                // Players that moved on from the lobby by themselves stay where they are
+                Player player = server.getPlayer(entry.getKey()).orElse(null);
+                if (player == null || !isInFallbackLobby(player)) {
+                    continue;
+                }
+                player.sendMessage(Component.text("Your server is back, sending you there now.", NamedTextColor.GREEN));
+                player.createConnectionRequest(registeredServer).fireAndForget();
+                moved++;
+            }
+
+            logger.info("Returned {} players to {}", moved, name);
+            ctx.status(200).json(Map.of(
+                "status", "ok",
+                "server", name,
+                "target", name,
+                "moved", moved
+            ));
+
+        } catch (Exception e) {
+            logger.error("Failed to return players", e);
+            ctx.status(500).json(Map.of("error", "Internal server error: " + e.getMessage()));
+        }
+    }
+
+    /**
+     * POST /api/servers/{name}/notify
+     * Body: {"players": ["Steve", "Alex"], "message": "Survival is back online!"}
+     *
+     * Messages the listed players that are online elsewhere on the network, with a click-to-rejoin hint
+     */
+    @SuppressWarnings("unchecked")
+    private void notifyPlayers(Context ctx) {
+        try {
+            String name = ctx.pathParam("name");
+            RegisteredServer registeredServer = server.getServer(name).orElse(null);
+            if (registeredServer == null) {
+                ctx.status(404).json(Map.of("error", "Server not found"));
+                return;
+            }
+
+            Map<String, Object> body = ctx.bodyAsClass(Map.class);
+            List<String> players = (List<String>) body.getOrDefault("players", List.of());
+            String message = (String) body.getOrDefault("message", "");
+            if (message == null || message.isEmpty()) {
+                message = "Your server is back online!";
+            }
+
+            Component hint = Component.text(" [Click to rejoin]", NamedTextColor.AQUA)
+                .clickEvent(ClickEvent.runCommand("/server " + name))
+                .hoverEvent(HoverEvent.showText(Component.text("/server " + name)));
+
+            int delivered = 0;
+            for (String username : players) {
+                Player player = server.getPlayer(username).orElse(null);
+                if (player == null) {
+                    continue;
+                }
+                // Players already back on the server don't need a hint
+                boolean onServer = player.getCurrentServer()
+                    .map(current -> current.getServerInfo().getName().equals(name))
+                    .orElse(false);
+                // Players waiting in the lobby for this server are sent back by /return instead
+                if (onServer || name.equals(displacedPlayers.get(player.getUniqueId()))) {
+                    continue;
+                }
+                player.sendMessage(Component.text(message, NamedTextColor.GREEN).append(hint));
+                delivered++;
+            }
+
+            logger.info("Notified {} players that {} is back", delivered, name);
+            ctx.status(200).json(Map.of(
+                "status", "ok",
+                "server", name,
+                "delivered", delivered
+            ));
+
+        } catch (Exception e) {
+            logger.error("Failed to notify players", e);
+            ctx.status(500).json(Map.of("error", "Internal server error: " + e.getMessage()));
+        }
+    }
+
+    /**
+     * PUT /api/bans
+     * Body: {"bans": [{"uuid": "...", "name": "Steve", "reason": "Griefing", "expires_at": 0, "servers": ["mc-abc"]}]}
+     *
+     * Replaces all bans. Online players banned from the network are disconnected; players on a server
+     * they are now banned from are moved to the fallback lobby (or disconnected without one).
+     */
+    @SuppressWarnings("unchecked")
+    private void syncBans(Context ctx) {
+        try {
+            Map<String, Object> body = ctx.bodyAsClass(Map.class);
+            List<Map<String, Object>> entries = (List<Map<String, Object>>) body.getOrDefault("bans", List.of());
+
+            Map<UUID, List<Ban>> updated = new HashMap<>();
+            for (Map<String, Object> entry : entries) {
+                UUID uuid = UUID.fromString((String) entry.get("uuid"));
+                String reason = (String) entry.getOrDefault("reason", "");
+                Number expiresAt = (Number) entry.getOrDefault("expires_at", 0);
+                List<String> servers = (List<String>) entry.getOrDefault("servers", List.of());
+                updated.computeIfAbsent(uuid, key -> new ArrayList<>())
+                    .add(new Ban(reason == null ? "" : reason, expiresAt.longValue(), Set.copyOf(servers)));
+            }
+            bans = updated;
+
+            int kicked = 0;
+            for (Player player : server.getAllPlayers()) {
+                Ban networkBan = findBan(player.getUniqueId(), null).orElse(null);
+                if (networkBan != null) {
+                    player.disconnect(banMessage(networkBan));
+                    kicked++;
+                    continue;
+                }
+
+                String current = player.getCurrentServer()
+                    .map(connection -> connection.getServerInfo().getName())
+                    .orElse(null);
+                Ban serverBan = current == null ? null : findBan(player.getUniqueId(), current).orElse(null);
+                if (serverBan == null) {
+                    continue;
+                }
+                RegisteredServer lobby = fallbackLobbyFor(current).orElse(null);
+                if (lobby != null && findBan(player.getUniqueId(), lobby.getServerInfo().getName()).isEmpty()) {
+                    player.sendMessage(banMessage(serverBan));
+                    player.createConnectionRequest(lobby).fireAndForget();
+                } else {
+                    player.disconnect(banMessage(serverBan));
+                }
+                kicked++;
+            }
+
+            logger.info("Synced {} bans for {} players, {} online players removed", entries.size(), updated.size(), kicked);
+            ctx.status(200).json(Map.of(
+                "status", "ok",
+                "bans", entries.size(),
+                "kicked", kicked
+            ));
+
+        } catch (ClassCastException | IllegalArgumentException | NullPointerException e) {
+            ctx.status(400).json(Map.of("error", "Invalid ban list: " + e.getMessage()));
+        } catch (Exception e) {
+            logger.error("Failed to sync bans", e);
+            ctx.status(500).json(Map.of("error", "Internal server error: " + e.getMessage()));
+        }
+    }
+
+    /**
+     * Returns an active ban of the player from the given server, or from the whole network if serverName is null
+     */
+    private Optional<Ban> findBan(UUID player, String serverName) {
+        return bans.getOrDefault(player, List.of()).stream()
+            .filter(Ban::active)
+            .filter(ban -> ban.global() || (serverName != null && ban.servers().contains(serverName)))
+            .findFirst();
+    }
+
+    /**
+     * Message shown to a banned player
+     */
+    private static Component banMessage(Ban ban) {
+        Component message = Component.text(
+            ban.global() ? "You are banned from this network." : "You are banned from this server.",
+            NamedTextColor.RED
+        );
+        if (!ban.reason().isEmpty()) {
+            message = message.append(Component.text("\nReason: " + ban.reason(), NamedTextColor.GRAY));
+        }
+        if (ban.expiresAt() != 0) {
+            message = message.append(Component.text("\nExpires: " + Instant.ofEpochSecond(ban.expiresAt()), NamedTextColor.GRAY));
+        }
+        return message;
+    }
+
+    /**
+     * Keep players banned from the network out at login
+     */
+    @Subscribe
+    public void onLogin(LoginEvent event) {
+        findBan(event.getPlayer().getUniqueId(), null).ifPresent(ban -> {
+            event.setResult(ResultedEvent.ComponentResult.denied(banMessage(ban)));
+            logger.info("Denied login of banned player {}", event.getPlayer().getUsername());
+        });
+    }
+
+    /**
+     * Keep players off the servers they are banned from
+     */
+    @Subscribe
+    public void onServerPreConnect(ServerPreConnectEvent event) {
+        RegisteredServer target = event.getResult().getServer().orElse(null);
+        if (target == null) {
+            return;
+        }
+        String name = target.getServerInfo().getName();
+        findBan(event.getPlayer().getUniqueId(), name).ifPresent(ban -> {
+            event.setResult(ServerPreConnectEvent.ServerResult.denied());
+            event.getPlayer().sendMessage(banMessage(ban));
+            logger.info("Denied connection of banned player {} to {}", event.getPlayer().getUsername(), name);
+        });
+    }
+
+    /**
+     * Returns the fallback lobby for players of the given server (never the server itself)
+     */
+    private Optional<RegisteredServer> fallbackLobbyFor(String serverName) {
+        String lobby = fallbackServer;
+        if (lobby.isEmpty() || lobby.equals(serverName)) {
+            return Optional.empty();
+        }
+        return server.getServer(lobby);
+    }
+
+    /**
+     * Returns whether the player is currently connected to the fallback lobby
+     */
+    private boolean isInFallbackLobby(Player player) {
+        return player.getCurrentServer()
+            .map(connection -> connection.getServerInfo().getName().equals(fallbackServer))
+            .orElse(false);
+    }
+
+    /**
+     * Catch players kicked by a crashing backend in the fallback lobby instead of the login screen
+     */
+    @Subscribe
+    public void onKickedFromServer(KickedFromServerEvent event) {
+        String name = event.getServer().getServerInfo().getName();
+        if (event.kickedDuringServerConnect()) {
+            return;
+        }
+
+        RegisteredServer lobby = fallbackLobbyFor(name).orElse(null);
+        if (lobby == null) {
+            return;
+        }
+
+        displacedPlayers.put(event.getPlayer().getUniqueId(), name);
+        event.setResult(KickedFromServerEvent.RedirectPlayer.create(
+            lobby,
+            Component.text("Your server went down, you'll be sent back once it's up again.", NamedTextColor.YELLOW)
+        ));
+
+        logger.info("Redirected player {} from {} to fallback lobby {}",
+            event.getPlayer().getUsername(),
+            name,
+            lobby.getServerInfo().getName()
+        );
+    }
+
+    /**
+     * Forget displaced players that left the network
+     */
+    @Subscribe
+    public void onDisconnect(DisconnectEvent event) {
+        displacedPlayers.remove(event.getPlayer().getUniqueId());
+    }
+
     /**
      * GET /health
      *
@@ -229,7 +790,8 @@ public class RemoteAPI {
             "status", "ok",
             "version", "1.0.0",
             "servers_count", server.getAllServers().size(),
-            "players_online", server.getPlayerCount()
+            "players_online", server.getPlayerCount(),
+            "started_at", startedAt
         ));
     }
 